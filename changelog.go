@@ -0,0 +1,9 @@
+// Package greg holds files embedded from the repository root
+package greg
+
+import _ "embed"
+
+// Changelog is the contents of CHANGELOG.md
+//
+//go:embed CHANGELOG.md
+var Changelog string
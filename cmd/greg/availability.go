@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// loadProviderAvailability flags the registered providers that fail
+// consistently from the user's region
+func loadProviderAvailability() {
+	list, err := database.ListProviderAvailability(database.DB)
+	if err != nil {
+		logger.Warn("failed to load provider availability", "error", err)
+		return
+	}
+	for _, availability := range list {
+		providers.SetLikelyGeoBlocked(availability.Provider, availability.LikelyGeoBlocked())
+	}
+}
+
+// recordHealthChecks records the latest health check of each provider. When
+// every provider failed the network is probably down, so nothing is recorded.
+func recordHealthChecks() {
+	statuses := providers.GetProviderStatuses()
+	var checked, failed int
+	for _, status := range statuses {
+		if status.LastResult == nil {
+			continue
+		}
+		checked++
+		if !status.Healthy {
+			failed++
+		}
+	}
+	if checked == 0 || failed == checked {
+		return
+	}
+
+	for _, status := range statuses {
+		if status.LastResult == nil {
+			continue
+		}
+		var availability *database.ProviderAvailability
+		var err error
+		if status.Healthy {
+			availability, err = database.RecordProviderSuccess(database.DB, status.ProviderName)
+		} else {
+			geoBlocked := status.LastResult.Kind == providers.ErrorGeoBlocked
+			availability, err = database.RecordProviderFailure(database.DB, status.ProviderName, geoBlocked)
+		}
+		if err != nil {
+			logger.Warn("failed to record provider availability", "provider", status.ProviderName, "error", err)
+			continue
+		}
+		providers.SetLikelyGeoBlocked(status.ProviderName, availability.LikelyGeoBlocked())
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg"
+	"github.com/justchokingaround/greg/internal/changelog"
+)
+
+// changelogCmd prints the release notes embedded in the binary
+var changelogCmd = &cobra.Command{
+	Use:   "changelog [version]",
+	Short: "Show release notes",
+	Long: `Show the release notes of the installed version, or of the given version.
+Development builds and --all print the whole changelog.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		target := version
+		if len(args) == 1 {
+			target = args[0]
+		} else if !changelog.IsRelease(version) {
+			all = true
+		}
+
+		if all {
+			fmt.Println(strings.TrimSpace(greg.Changelog))
+			return nil
+		}
+
+		release, ok := changelog.Find(changelog.Parse(greg.Changelog), target)
+		if !ok {
+			return fmt.Errorf("no release notes for version %s", target)
+		}
+		printRelease(release)
+		return nil
+	},
+}
+
+// printRelease prints one release's heading and notes
+func printRelease(release changelog.Release) {
+	heading := "greg " + release.Version
+	if release.Date != "" {
+		heading += " (" + release.Date + ")"
+	}
+	fmt.Println(heading)
+	fmt.Println()
+	fmt.Println(release.Notes)
+}
+
+func init() {
+	changelogCmd.Flags().Bool("all", false, "print the whole changelog")
+	rootCmd.AddCommand(changelogCmd)
+}
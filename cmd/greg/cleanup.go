@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+)
+
+// cleanupCmd applies the retention policy for watched downloads
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete or archive downloads watched long ago",
+	Long: `Apply downloads.cleanup: completed downloads whose episode was marked watched
+in the history more than downloads.cleanup.days ago are moved to the trash
+(action "delete") or to downloads.cleanup.archive_path (action "archive").
+Shows listed in downloads.cleanup.exclude are kept. greg also applies the
+policy on its own while running.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		days, _ := cmd.Flags().GetInt("days")
+
+		policy := cfg.Downloads
+		if days > 0 {
+			policy.Cleanup.Days = days
+		}
+		if policy.Cleanup.Days <= 0 {
+			fmt.Println("Cleanup is disabled: set downloads.cleanup.days or pass --days")
+			return nil
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		downloadMgr, err := downloader.NewManager(database.DB, &policy, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize download manager: %w", err)
+		}
+
+		report, err := downloadMgr.Cleanup(ctx, dryRun)
+		if report != nil {
+			printCleanupReport(report, policy.Cleanup.Days)
+		}
+		if err != nil {
+			return fmt.Errorf("cleanup failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// printCleanupReport prints the episodes a cleanup run handled
+func printCleanupReport(report *downloader.CleanupReport, days int) {
+	if len(report.Excluded) > 0 {
+		fmt.Printf("Kept (excluded): %s\n", strings.Join(report.Excluded, ", "))
+	}
+	if len(report.Candidates) == 0 {
+		fmt.Printf("Nothing watched more than %d day(s) ago\n", days)
+		return
+	}
+
+	verb := "Deleted"
+	if report.Action == downloader.CleanupArchive {
+		verb = "Archived"
+	}
+	if report.DryRun {
+		verb = "Would " + strings.ToLower(strings.TrimSuffix(verb, "d"))
+	}
+
+	for _, c := range report.Candidates {
+		episode := fmt.Sprintf("E%02d", c.Task.Episode)
+		if c.Task.Season > 0 {
+			episode = fmt.Sprintf("S%02d%s", c.Task.Season, episode)
+		}
+		line := fmt.Sprintf("%-8s %s %s  %s  watched %s",
+			humanize.IBytes(uint64(c.Size)), c.Task.MediaTitle, episode, c.Task.OutputPath, humanize.Time(c.WatchedAt))
+		if c.Target != "" {
+			line += "  → " + c.Target
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("%s %d episode(s), %s\n", verb, len(report.Candidates), humanize.IBytes(uint64(report.Freed)))
+	if report.Trash != nil {
+		fmt.Printf("Undo with 'greg trash restore %d'\n", report.Trash.ID)
+	}
+}
+
+func init() {
+	cleanupCmd.Flags().Bool("dry-run", false, "list what would be cleaned up without changing anything")
+	cleanupCmd.Flags().Int("days", 0, "override downloads.cleanup.days for this run")
+
+	rootCmd.AddCommand(cleanupCmd)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker/anilist"
+)
+
+// cowatchCmd groups the co-watch profile commands
+var cowatchCmd = &cobra.Command{
+	Use:   "cowatch",
+	Short: "Manage co-watch profiles",
+	Long: `Co-watch profiles keep a separate progress track for watching together
+("greg --with alice", or W on the home screen). Their progress doesn't touch
+your history or AniList until you push it with 'greg cowatch sync <name>'.`,
+}
+
+// cowatchListCmd lists co-watch profiles
+var cowatchListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List co-watch profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := database.ListCoWatchProfiles(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to list co-watch profiles: %w", err)
+		}
+		if len(profiles) == 0 {
+			fmt.Println("No co-watch profiles (add one with 'greg cowatch add <name>')")
+			return nil
+		}
+
+		for _, profile := range profiles {
+			fmt.Printf("%-20s since %s\n", profile.Name, profile.CreatedAt.Format("2006-01-02"))
+		}
+		return nil
+	},
+}
+
+// cowatchAddCmd creates a co-watch profile
+var cowatchAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a co-watch profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := database.AddCoWatchProfile(database.DB, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to add co-watch profile: %w", err)
+		}
+		fmt.Printf("Added co-watch profile %s (watch with 'greg --with %s')\n", profile.Name, profile.Name)
+		return nil
+	},
+}
+
+// cowatchRemoveCmd deletes a co-watch profile and its progress
+var cowatchRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a co-watch profile and its progress",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := findCoWatchProfile(args[0])
+		if err != nil {
+			return err
+		}
+		if err := database.RemoveCoWatchProfile(database.DB, profile.ID); err != nil {
+			return fmt.Errorf("failed to remove co-watch profile: %w", err)
+		}
+		fmt.Printf("Removed co-watch profile %s\n", profile.Name)
+		return nil
+	},
+}
+
+// cowatchSyncCmd pushes co-watch progress to AniList where it is ahead
+var cowatchSyncCmd = &cobra.Command{
+	Use:   "sync <name>",
+	Short: "Push a co-watch profile's progress to AniList",
+	Long: `Push the episodes finished with a co-watch profile to AniList. Entries
+are only updated when the profile is ahead of your AniList progress.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		profile, err := findCoWatchProfile(args[0])
+		if err != nil {
+			return err
+		}
+		pending, err := database.PendingCoWatchSync(database.DB, profile.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get co-watch progress: %w", err)
+		}
+		if len(pending) == 0 {
+			fmt.Printf("Nothing to sync for %s\n", profile.Name)
+			return nil
+		}
+
+		if !cfg.Tracker.AniList.Enabled {
+			return fmt.Errorf("anilist tracking is disabled")
+		}
+		tokenStorage := anilist.NewTokenStorage(database.DB)
+		client := anilist.NewClient(anilist.Config{
+			ClientID:    anilist.AuthBrowserClientID,
+			RedirectURI: anilist.AuthBrowserRedirectURI,
+			SaveToken:   tokenStorage.SaveToken,
+			LoadToken:   tokenStorage.LoadToken,
+		})
+		if !client.IsAuthenticated() {
+			return fmt.Errorf("not authenticated with AniList (run 'greg auth anilist')")
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+		defer cancel()
+
+		library, err := client.GetUserLibrary(ctx, providers.MediaTypeAnime)
+		if err != nil {
+			return fmt.Errorf("failed to fetch AniList library: %w", err)
+		}
+		progress := make(map[string]int, len(library))
+		for _, media := range library {
+			progress[media.ServiceID] = media.Progress
+		}
+
+		synced := 0
+		for _, entry := range pending {
+			id := fmt.Sprintf("%d", entry.AniListID)
+			current := progress[id]
+			if entry.Episode <= current {
+				fmt.Printf("skip  %-40s AniList already at ep %d\n", entry.MediaTitle, current)
+			} else {
+				fmt.Printf("push  %-40s ep %d -> %d\n", entry.MediaTitle, current, entry.Episode)
+				if dryRun {
+					continue
+				}
+				if err := client.UpdateProgress(ctx, id, entry.Episode, 1.0); err != nil {
+					fmt.Printf("      failed: %v\n", err)
+					continue
+				}
+				synced++
+			}
+			if !dryRun {
+				if err := database.MarkCoWatchSynced(database.DB, profile.ID, entry.AniListID, entry.Episode); err != nil {
+					return fmt.Errorf("failed to mark co-watch progress as synced: %w", err)
+				}
+			}
+		}
+
+		if dryRun {
+			fmt.Println("Dry run: AniList was not updated")
+		} else {
+			fmt.Printf("Synced %d AniList entries from %s\n", synced, profile.Name)
+		}
+		return nil
+	},
+}
+
+// findCoWatchProfile looks up a co-watch profile by name, failing when
+// there is none
+func findCoWatchProfile(name string) (*database.CoWatchProfile, error) {
+	profile, err := database.FindCoWatchProfile(database.DB, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find co-watch profile: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("no co-watch profile named %q (see 'greg cowatch list')", name)
+	}
+	return profile, nil
+}
+
+func init() {
+	cowatchSyncCmd.Flags().Bool("dry-run", false, "show what would be pushed without updating AniList")
+	cowatchCmd.AddCommand(cowatchListCmd)
+	cowatchCmd.AddCommand(cowatchAddCmd)
+	cowatchCmd.AddCommand(cowatchRemoveCmd)
+	cowatchCmd.AddCommand(cowatchSyncCmd)
+	rootCmd.AddCommand(cowatchCmd)
+}
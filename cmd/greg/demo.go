@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justchokingaround/greg/internal/database"
+	fakeplayer "github.com/justchokingaround/greg/internal/player/fake"
+	"github.com/justchokingaround/greg/internal/providers"
+	fakeprovider "github.com/justchokingaround/greg/internal/providers/fake"
+	"github.com/justchokingaround/greg/internal/tui"
+)
+
+// demoSpeed is how many times faster than real time the demo player plays,
+// so an episode ends within a minute
+const demoSpeed = 30
+
+// demoDir holds the data of a --demo session, removed on exit
+var demoDir string
+
+// setupDemo sets up a --demo session: the TUI browses the fake providers'
+// catalog and plays on the fake player, without network, with AniList off
+// and a database and downloads of its own, leaving the user's data alone
+func setupDemo() error {
+	dir, err := os.MkdirTemp("", "greg-demo-")
+	if err != nil {
+		return fmt.Errorf("failed to create demo directory: %w", err)
+	}
+	demoDir = dir
+
+	cfg.Database.Driver = database.DriverSQLite
+	cfg.Database.Path = filepath.Join(dir, "greg.db")
+	cfg.Downloads.Path = filepath.Join(dir, "downloads")
+	cfg.Tracker.AniList.Enabled = false
+	cfg.Providers.Default.Anime = fakeprovider.AnimeName
+	cfg.Providers.Default.MoviesAndTV = fakeprovider.MoviesTVName
+	if err := database.Init(&cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	providers.Clear()
+	for _, p := range []providers.Provider{fakeprovider.NewAnime(), fakeprovider.NewMoviesTV()} {
+		if err := providers.Register(p); err != nil {
+			return err
+		}
+	}
+	providers.CheckAllProviders(context.Background())
+
+	tui.Player = fakeplayer.New(0, demoSpeed)
+	logger.Info("demo mode", "dir", dir)
+	return nil
+}
+
+// cleanupDemo removes the data of a --demo session
+func cleanupDemo() {
+	if demoDir == "" {
+		return
+	}
+	if err := os.RemoveAll(demoDir); err != nil {
+		logger.Warn("failed to remove demo directory", "dir", demoDir, "error", err)
+	}
+}
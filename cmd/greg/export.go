@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/schedule"
+)
+
+// exportCmd groups the export commands
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export data for calendars and dashboards",
+}
+
+// exportScheduleCmd exports the airing schedule of the followed shows
+var exportScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Export the airing schedule of followed shows",
+	Long: `Export the upcoming episodes of the anime you're watching or planning on
+AniList.
+
+  --ical  an iCal calendar to import or subscribe to (default)
+  --json  a dashboard widget: counters, the next episodes and how far behind
+          you are on each show
+
+With --serve, greg keeps running and serves both instead:
+
+  /schedule.ics   the calendar, to subscribe to from a calendar app
+  /widget.json    the widget, for Homepage/Glance-style dashboards
+  /schedule.json  the whole schedule
+
+Requires AniList to be enabled and authenticated.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		days, _ := cmd.Flags().GetInt("days")
+		output, _ := cmd.Flags().GetString("output")
+		serve, _ := cmd.Flags().GetString("serve")
+		refresh, _ := cmd.Flags().GetDuration("refresh")
+
+		if days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+		client := authenticatedAniList()
+		if client == nil {
+			return fmt.Errorf("AniList is not enabled or not authenticated (run 'greg auth anilist')")
+		}
+		load := func(ctx context.Context) (*schedule.Schedule, error) {
+			ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+			defer cancel()
+			return schedule.Load(ctx, client, time.Now(), days)
+		}
+
+		if serve != "" {
+			return serveSchedule(serve, schedule.NewServer(load, refresh))
+		}
+
+		s, err := load(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" && output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", output, err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if asJSON {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(s.Widget(time.Now()))
+		} else {
+			err = schedule.WriteICal(w, s)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write schedule: %w", err)
+		}
+		if output != "" && output != "-" {
+			fmt.Fprintf(os.Stderr, "Exported %d upcoming episodes to %s\n", len(s.Episodes), output)
+		}
+		return nil
+	},
+}
+
+// serveSchedule serves the schedule until interrupted
+func serveSchedule(addr string, handler http.Handler) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("schedule server stopped", "error", err)
+		}
+	}()
+	defer func() { _ = server.Close() }()
+
+	base := "http://" + listener.Addr().String()
+	logger.Info("schedule server started", "addr", listener.Addr().String())
+	fmt.Printf("Calendar: %s/schedule.ics\n", base)
+	fmt.Printf("Widget:   %s/widget.json\n", base)
+	fmt.Println("Press Ctrl+C to stop.")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+
+	return nil
+}
+
+func init() {
+	exportScheduleCmd.Flags().Bool("ical", false, "write an iCal calendar (default)")
+	exportScheduleCmd.Flags().Bool("json", false, "write the dashboard widget JSON")
+	exportScheduleCmd.MarkFlagsMutuallyExclusive("ical", "json")
+	exportScheduleCmd.Flags().Int("days", 14, "how many days ahead to include")
+	exportScheduleCmd.Flags().StringP("output", "o", "", "file to write (default: stdout)")
+	exportScheduleCmd.Flags().String("serve", "", "serve the calendar and widget on this address (e.g. :8787) instead")
+	exportScheduleCmd.Flags().Duration("refresh", 15*time.Minute, "how often the served schedule is reloaded from AniList")
+
+	exportCmd.AddCommand(exportScheduleCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/extensions"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// extensionsCmd groups the extension commands
+var extensionsCmd = &cobra.Command{
+	Use:     "extensions",
+	Aliases: []string{"ext"},
+	Short:   "Install community scrapers and themes",
+	Long: `Browse and install scrapers and themes from the repository index set in
+extensions.repo. The index is verified against extensions.public_key and every
+file against its checksum. Install name@version to pin a version.`,
+}
+
+// extensionsBrowseCmd lists the repository index
+var extensionsBrowseCmd = &cobra.Command{
+	Use:   "browse",
+	Short: "List the extensions of the repository",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := extensions.NewManager(database.DB, cfg)
+		idx, err := mgr.Index(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if len(idx.Extensions) == 0 {
+			fmt.Println("The repository has no extensions")
+			return nil
+		}
+
+		installed := installedExtensions(mgr)
+		for _, entry := range idx.Extensions {
+			latest, _ := entry.Latest()
+			status := ""
+			if ext, ok := installed[entry.Name]; ok {
+				status = "installed " + ext.Version
+				if ext.Pinned {
+					status += ", pinned"
+				}
+				status = " [" + status + "]"
+			}
+			fmt.Printf("%-20s %-8s %-8s %s%s\n", entry.Name, entry.Kind, latest.Version, entry.Description, status)
+		}
+		return nil
+	},
+}
+
+// extensionsInstallCmd installs extensions
+var extensionsInstallCmd = &cobra.Command{
+	Use:   "install <name>[@version]...",
+	Short: "Install extensions, pinning a version when given",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := extensions.NewManager(database.DB, cfg)
+		for _, arg := range args {
+			name, version, _ := strings.Cut(arg, "@")
+			ext, err := mgr.Install(cmd.Context(), name, version)
+			if err != nil {
+				return fmt.Errorf("failed to install %s: %w", name, err)
+			}
+			fmt.Printf("Installed %s %s to %s\n", ext.Name, ext.Version, ext.Path)
+		}
+		return nil
+	},
+}
+
+// extensionsUpdateCmd updates installed extensions
+var extensionsUpdateCmd = &cobra.Command{
+	Use:   "update [name...]",
+	Short: "Update installed extensions (pinned ones only when named)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		mgr := extensions.NewManager(database.DB, cfg)
+		updates, err := mgr.Update(cmd.Context(), args...)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, u := range updates {
+			switch {
+			case u.Err != nil:
+				failed++
+				fmt.Printf("%-20s %s: %v\n", u.Name, u.From, u.Err)
+			case u.To != "":
+				fmt.Printf("%-20s %s -> %s\n", u.Name, u.From, u.To)
+			default:
+				fmt.Printf("%-20s %s (up to date)\n", u.Name, u.From)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d extension(s) failed to update", failed)
+		}
+		return nil
+	},
+}
+
+// extensionsListCmd lists installed extensions
+var extensionsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List installed extensions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exts, err := extensions.NewManager(database.DB, cfg).Installed()
+		if err != nil {
+			return fmt.Errorf("failed to list extensions: %w", err)
+		}
+		if len(exts) == 0 {
+			fmt.Println("No extensions installed")
+			return nil
+		}
+
+		for _, ext := range exts {
+			pinned := ""
+			if ext.Pinned {
+				pinned = " (pinned)"
+			}
+			fmt.Printf("%-20s %-8s %s%s\n", ext.Name, ext.Kind, ext.Version, pinned)
+		}
+		return nil
+	},
+}
+
+// extensionsRemoveCmd uninstalls extensions
+var extensionsRemoveCmd = &cobra.Command{
+	Use:     "remove <name>...",
+	Aliases: []string{"rm"},
+	Short:   "Uninstall extensions",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := extensions.NewManager(database.DB, cfg)
+		for _, name := range args {
+			if err := mgr.Remove(name); err != nil {
+				return err
+			}
+			fmt.Printf("Removed %s\n", name)
+		}
+		return nil
+	},
+}
+
+// installedExtensions indexes the installed extensions by name
+func installedExtensions(mgr *extensions.Manager) map[string]database.Extension {
+	exts, _ := mgr.Installed()
+	installed := make(map[string]database.Extension, len(exts))
+	for _, ext := range exts {
+		installed[ext.Name] = ext
+	}
+	return installed
+}
+
+// applyTheme loads ui.theme from the themes directory before the TUI starts
+func applyTheme() {
+	name := cfg.UI.Theme
+	if name == "" || name == "default" {
+		return
+	}
+	theme, err := styles.LoadTheme(filepath.Join(extensions.ThemesDir(), name+".yaml"))
+	if err != nil {
+		logger.Warn("failed to load theme", "theme", name, "error", err)
+		return
+	}
+	styles.ApplyTheme(theme)
+}
+
+func init() {
+	extensionsCmd.AddCommand(extensionsBrowseCmd)
+	extensionsCmd.AddCommand(extensionsInstallCmd)
+	extensionsCmd.AddCommand(extensionsUpdateCmd)
+	extensionsCmd.AddCommand(extensionsListCmd)
+	extensionsCmd.AddCommand(extensionsRemoveCmd)
+	rootCmd.AddCommand(extensionsCmd)
+}
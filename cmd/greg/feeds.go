@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/feeds"
+	"github.com/justchokingaround/greg/internal/incoming"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/releases"
+	"github.com/justchokingaround/greg/internal/torrentclient"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/anilist"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+// feedsCmd groups the RSS release feed commands
+var feedsCmd = &cobra.Command{
+	Use:   "feeds",
+	Short: "Track RSS release feeds",
+	Long: `Track RSS release feeds (SubsPlease, nyaa searches, ...).
+New entries matching a feed's --match titles or your AniList watching list are
+queued for download; magnet and .torrent links go to the torrent client in
+downloads.torrent, or to downloads.feeds.torrent_command.`,
+}
+
+// feedsAddCmd starts tracking a feed
+var feedsAddCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Track an RSS feed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		match, _ := cmd.Flags().GetStringSlice("match")
+
+		feed, err := database.AddFeed(database.DB, args[0], name, strings.Join(match, ","))
+		if err != nil {
+			return fmt.Errorf("failed to add feed: %w", err)
+		}
+
+		fmt.Printf("Tracking feed #%d: %s\n", feed.ID, feed.URL)
+		fmt.Println("Existing entries are recorded on the first check; only newer releases are queued.")
+		return nil
+	},
+}
+
+// feedsListCmd lists tracked feeds
+var feedsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tracked feeds",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := database.ListFeeds(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to list feeds: %w", err)
+		}
+		if len(list) == 0 {
+			fmt.Println("No feeds tracked (add one with 'greg feeds add <url>')")
+			return nil
+		}
+
+		for _, feed := range list {
+			label := feed.URL
+			if feed.Name != "" {
+				label = fmt.Sprintf("%s (%s)", feed.Name, feed.URL)
+			}
+			checked := "never"
+			if feed.LastCheckedAt != nil {
+				checked = feed.LastCheckedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("#%-3d %s\n", feed.ID, label)
+			if feed.Match != "" {
+				fmt.Printf("     match: %s\n", feed.Match)
+			}
+			fmt.Printf("     last checked: %s\n", checked)
+		}
+		return nil
+	},
+}
+
+// feedsRemoveCmd stops tracking a feed
+var feedsRemoveCmd = &cobra.Command{
+	Use:     "remove <id | url>",
+	Aliases: []string{"rm"},
+	Short:   "Stop tracking a feed",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := database.RemoveFeed(database.DB, args[0]); err != nil {
+			return err
+		}
+		fmt.Println("Feed removed")
+		return nil
+	},
+}
+
+// feedsCheckCmd checks all feeds once
+var feedsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check feeds once and queue matching releases",
+	Long: `Check all tracked feeds once and queue new releases that match a subscription.
+The first check of a feed only records its entries unless --backfill is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		backfill, _ := cmd.Flags().GetBool("backfill")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		return runFeeds(ctx, feeds.CheckOptions{DryRun: dryRun, Backfill: backfill}, 0)
+	},
+}
+
+// feedsWatchCmd checks feeds periodically
+var feedsWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Check feeds periodically and queue matching releases",
+	Long: `Check all tracked feeds every downloads.feeds.interval (or --interval) and
+download matching releases as they appear. Runs until interrupted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			interval = cfg.Downloads.Feeds.Interval
+		}
+		if interval <= 0 {
+			interval = 15 * time.Minute
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		return runFeeds(ctx, feeds.CheckOptions{}, interval)
+	},
+}
+
+// runFeeds checks all feeds, once or every interval, with a download manager
+// running so queued releases are downloaded
+func runFeeds(ctx context.Context, opts feeds.CheckOptions, interval time.Duration) error {
+	if incognito {
+		return fmt.Errorf("feed subscriptions are disabled in incognito mode")
+	}
+	var queue feeds.Queuer
+	var watcher *incoming.Watcher
+	if !opts.DryRun {
+		downloadMgr, err := downloader.NewManager(database.DB, &cfg.Downloads, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize download manager: %w", err)
+		}
+		if err := downloadMgr.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start download manager: %w", err)
+		}
+		defer func() { _ = downloadMgr.Stop() }()
+		queue = downloadMgr
+		watcher = incoming.New(cfg.Downloads.Incoming.Path, downloadMgr, mapping.NewManager(database.DB, "", logger), logger)
+
+		if interval == 0 {
+			// Wait for this run's downloads before exiting
+			defer waitForDownloads(ctx, downloadMgr)
+		}
+	}
+
+	ingester := feeds.NewIngester(database.DB, queue, cfg.Downloads.Feeds.TorrentCommand)
+	ingester.SetPreferences(releases.FromConfig(&cfg.Downloads.Releases))
+	var client torrentclient.Client
+	if cfg.Downloads.Torrent.Client != "" {
+		c, err := torrentclient.New(cfg.Downloads.Torrent)
+		if err != nil {
+			return err
+		}
+		client = c
+		ingester.SetTorrentClient(client)
+	}
+	for {
+		checkFeeds(ctx, ingester, opts)
+		if client != nil && watcher != nil && interval > 0 {
+			// Import what finished since the last check
+			results, err := watcher.CheckTorrents(ctx, database.DB, client)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to check torrents: %v\n", err)
+			}
+			printIncomingResults(results)
+		}
+		if interval == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// checkFeeds runs one check of every tracked feed and prints the outcome
+func checkFeeds(ctx context.Context, ingester *feeds.Ingester, opts feeds.CheckOptions) {
+	list, err := database.ListFeeds(database.DB)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list feeds: %v\n", err)
+		return
+	}
+	if len(list) == 0 {
+		fmt.Println("No feeds tracked (add one with 'greg feeds add <url>')")
+		return
+	}
+
+	if cfg.Downloads.Feeds.AniListWatching {
+		opts.Watching = anilistWatchingTitles(ctx)
+	}
+
+	for _, feed := range list {
+		result, err := ingester.Check(ctx, feed, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", feed.URL, err)
+			continue
+		}
+
+		fmt.Printf("%s: %d new entries, %d matching\n", feed.URL, result.New, len(result.Releases))
+		for _, release := range result.Releases {
+			switch {
+			case opts.DryRun:
+				fmt.Printf("  would queue %s\n", release.Title)
+			case result.Primed:
+				fmt.Printf("  recorded %s\n", release.Title)
+			case errors.Is(release.Err, feeds.ErrNoTorrentClient):
+				fmt.Printf("  skipped %s: %v\n", release.Title, release.Err)
+			case release.Err != nil:
+				fmt.Printf("  failed %s: %v\n", release.Title, release.Err)
+			default:
+				fmt.Printf("  queued %s\n", release.Title)
+			}
+		}
+	}
+}
+
+// anilistWatchingTitles returns the anime on the AniList watching list, or
+// nothing when AniList is disabled or unauthenticated
+func anilistWatchingTitles(ctx context.Context) []string {
+	if !cfg.Tracker.AniList.Enabled {
+		return nil
+	}
+
+	tokenStorage := anilist.NewTokenStorage(database.DB)
+	client := anilist.NewClient(anilist.Config{
+		ClientID:    anilist.AuthBrowserClientID,
+		RedirectURI: anilist.AuthBrowserRedirectURI,
+		SaveToken:   tokenStorage.SaveToken,
+		LoadToken:   tokenStorage.LoadToken,
+	})
+	if !client.IsAuthenticated() {
+		return nil
+	}
+
+	library, err := client.GetUserLibrary(ctx, providers.MediaTypeAnime)
+	if err != nil {
+		logger.Warn("failed to fetch AniList watching list", "error", err)
+		return nil
+	}
+
+	var titles []string
+	for _, media := range library {
+		if media.Status == tracker.StatusWatching || media.Status == tracker.StatusRewatching {
+			titles = append(titles, media.Title)
+		}
+	}
+	return titles
+}
+
+// waitForDownloads blocks until the queue has no active downloads
+func waitForDownloads(ctx context.Context, downloadMgr *downloader.Manager) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		queue, err := downloadMgr.GetQueue(ctx)
+		if err != nil {
+			return
+		}
+		active := 0
+		for _, task := range queue {
+			if !task.Status.IsComplete() && task.Status != downloader.StatusPaused {
+				active++
+			}
+		}
+		if active == 0 {
+			return
+		}
+		fmt.Printf("\rWaiting for %d download(s)...   ", active)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nInterrupted; queued downloads resume on the next run.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	feedsAddCmd.Flags().String("name", "", "display name for the feed")
+	feedsAddCmd.Flags().StringSlice("match", nil, "show titles to match (in addition to the AniList watching list)")
+
+	feedsCheckCmd.Flags().Bool("dry-run", false, "show matching releases without queueing or recording them")
+	feedsCheckCmd.Flags().Bool("backfill", false, "queue matches on a feed's first check instead of only recording them")
+
+	feedsWatchCmd.Flags().Duration("interval", 0, "time between checks (default: downloads.feeds.interval)")
+
+	feedsCmd.AddCommand(feedsAddCmd)
+	feedsCmd.AddCommand(feedsListCmd)
+	feedsCmd.AddCommand(feedsRemoveCmd)
+	feedsCmd.AddCommand(feedsCheckCmd)
+	feedsCmd.AddCommand(feedsWatchCmd)
+	rootCmd.AddCommand(feedsCmd)
+}
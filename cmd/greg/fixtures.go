@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/justchokingaround/greg/internal/providers/fixtures"
+)
+
+// defaultFixtureDir is where --record saves fixtures when no directory is given
+const defaultFixtureDir = "fixtures"
+
+// setupFixtures routes HTTP traffic through the fixture recorder or replayer
+// requested by --record or --replay
+func setupFixtures() error {
+	switch {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("--record and --replay can't be used together")
+	case recordDir != "":
+		recorder, err := fixtures.NewRecorder(recordDir, http.DefaultTransport)
+		if err != nil {
+			return err
+		}
+		http.DefaultTransport = recorder
+		logger.Info("recording HTTP fixtures", "dir", recordDir)
+	case replayDir != "":
+		http.DefaultTransport = fixtures.NewReplayer(replayDir)
+		logger.Info("replaying HTTP fixtures", "dir", replayDir)
+	}
+	return nil
+}
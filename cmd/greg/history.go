@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/history"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/anilist"
+)
+
+// historyCmd groups the watch history commands
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Manage watch history",
+}
+
+// historyExportCmd exports the watch history for other services
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export watch history for other services",
+	Long: `Export watch history as an import-ready file:
+
+  csv         every title with progress, dates and scores
+  letterboxd  completed movies, for Letterboxd's diary import
+  mal_xml     anime with a MyAnimeList ID, for MyAnimeList's list import
+
+Scores, completion dates and MyAnimeList IDs come from your AniList list
+when AniList is enabled and authenticated.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		var formats []string
+		known := false
+		for _, f := range history.ExportFormats {
+			formats = append(formats, string(f))
+			known = known || string(f) == format
+		}
+		if !known {
+			return fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(formats, ", "))
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+		defer cancel()
+
+		entries, err := history.NewService(database.DB).ExportEntries(anilistLibrary(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to export history: %w", err)
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" && output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", output, err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		skipped := 0
+		switch history.ExportFormat(format) {
+		case history.ExportCSV:
+			err = history.WriteCSV(w, entries)
+		case history.ExportLetterboxd:
+			skipped, err = history.WriteLetterboxd(w, entries)
+		case history.ExportMALXML:
+			skipped, err = history.WriteMALXML(w, entries)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s export: %w", format, err)
+		}
+
+		if output != "" && output != "-" {
+			fmt.Fprintf(os.Stderr, "Exported %d title(s) to %s\n", len(entries)-skipped, output)
+		}
+		if skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Left out %d title(s) the %s format can't import\n", skipped, format)
+		}
+		return nil
+	},
+}
+
+// importCmd groups the commands importing data from other services
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import data from other services",
+}
+
+// importHistoryCmd imports watch history exported by another service
+var importHistoryCmd = &cobra.Command{
+	Use:   "history <file>",
+	Short: "Import watch history from a MyAnimeList/AniList or Trakt export",
+	Long: `Import watch history from another service's export into the local database:
+
+  mal_xml  MyAnimeList list export (AniList can export this format too)
+  trakt    Trakt watched history JSON
+
+Every watched episode is recorded as completed, so resuming picks up after
+the last one. Episodes already in the history are skipped. With --map,
+MyAnimeList entries are linked to their AniList entries, so the history and
+provider mappings of AniList titles apply to them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		mapAniList, _ := cmd.Flags().GetBool("map")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+		if format == "" {
+			detected, err := history.DetectImportFormat(data)
+			if err != nil {
+				return err
+			}
+			format = string(detected)
+		}
+
+		var entries []history.ImportEntry
+		switch history.ImportFormat(format) {
+		case history.ImportMALXML:
+			entries, err = history.ParseMALXML(bytes.NewReader(data), time.Now())
+		case history.ImportTrakt:
+			entries, err = history.ParseTraktJSON(bytes.NewReader(data))
+		default:
+			return fmt.Errorf("unknown import format %q (use mal_xml or trakt)", format)
+		}
+		if err != nil {
+			return err
+		}
+
+		if mapAniList {
+			linkAniListIDs(cmd.Context(), entries)
+		}
+
+		records := 0
+		for _, entry := range entries {
+			records += len(entry.Records)
+		}
+		if dryRun {
+			fmt.Printf("Would import %d title(s), %d episode(s)\n", len(entries), records)
+			return nil
+		}
+
+		added, skipped, err := history.NewService(database.DB).Import(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d episode(s) of %d title(s), %d already in history\n", added, len(entries), skipped)
+		return nil
+	},
+}
+
+// linkAniListIDs looks up the AniList entries of imported MyAnimeList titles
+func linkAniListIDs(ctx context.Context, entries []history.ImportEntry) {
+	tokenStorage := anilist.NewTokenStorage(database.DB)
+	client := anilist.NewClient(anilist.Config{
+		ClientID:    anilist.AuthBrowserClientID,
+		RedirectURI: anilist.AuthBrowserRedirectURI,
+		SaveToken:   tokenStorage.SaveToken,
+		LoadToken:   tokenStorage.LoadToken,
+	})
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.MALID == 0 {
+			continue
+		}
+		mediaType := providers.MediaTypeAnime
+		if entry.MediaType == "manga" {
+			mediaType = providers.MediaTypeManga
+		}
+
+		lookupCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		media, err := client.GetMediaByID(lookupCtx, entry.MALID, true, mediaType)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "No AniList entry for %s (MAL %d): %v\n", entry.Title, entry.MALID, err)
+			continue
+		}
+		id, err := strconv.Atoi(media.ServiceID)
+		if err != nil {
+			continue
+		}
+		entry.SetAniListID(id)
+	}
+}
+
+// authenticatedAniList returns an authenticated AniList client, or nil when
+// AniList is disabled or not authenticated
+func authenticatedAniList() *anilist.Client {
+	if !cfg.Tracker.AniList.Enabled {
+		return nil
+	}
+
+	tokenStorage := anilist.NewTokenStorage(database.DB)
+	client := anilist.NewClient(anilist.Config{
+		ClientID:    anilist.AuthBrowserClientID,
+		RedirectURI: anilist.AuthBrowserRedirectURI,
+		SaveToken:   tokenStorage.SaveToken,
+		LoadToken:   tokenStorage.LoadToken,
+	})
+	if !client.IsAuthenticated() {
+		return nil
+	}
+	return client
+}
+
+// anilistLibrary returns the AniList anime and manga lists, or nothing when
+// AniList is disabled or unauthenticated
+func anilistLibrary(ctx context.Context) []tracker.TrackedMedia {
+	client := authenticatedAniList()
+	if client == nil {
+		return nil
+	}
+
+	var library []tracker.TrackedMedia
+	for _, mediaType := range []providers.MediaType{providers.MediaTypeAnime, providers.MediaTypeManga} {
+		list, err := client.GetUserLibrary(ctx, mediaType)
+		if err != nil {
+			logger.Warn("failed to fetch AniList library", "type", mediaType, "error", err)
+			continue
+		}
+		library = append(library, list...)
+	}
+	return library
+}
+
+func init() {
+	historyExportCmd.Flags().StringP("format", "f", string(history.ExportCSV), "export format: csv, letterboxd or mal_xml")
+	historyExportCmd.Flags().StringP("output", "o", "", "file to write (default: stdout)")
+	historyCmd.AddCommand(historyExportCmd)
+	rootCmd.AddCommand(historyCmd)
+
+	importHistoryCmd.Flags().String("format", "", "export format: mal_xml or trakt (default: detected)")
+	importHistoryCmd.Flags().Bool("map", false, "link MyAnimeList entries to AniList (one lookup per title)")
+	importHistoryCmd.Flags().Bool("dry-run", false, "show what would be imported without writing anything")
+	importCmd.AddCommand(importHistoryCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/incoming"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+// incomingCmd groups the commands for the incoming folder
+var incomingCmd = &cobra.Command{
+	Use:   "incoming",
+	Short: "Import files other programs downloaded",
+	Long: `Import the video files other programs, such as a torrent client, drop into
+downloads.incoming.path: each is matched to a show by its name, renamed with the
+filename templates and moved into the downloads directory. greg also watches the
+folder on its own while running.`,
+}
+
+// incomingScanCmd imports the incoming folder once
+var incomingScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Import the files of the incoming folder once",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		watcher, err := newIncomingWatcher(cmd)
+		if err != nil {
+			return err
+		}
+		results := watcher.Scan(ctx)
+		if len(results) == 0 {
+			fmt.Println("Nothing to import")
+		}
+		printIncomingResults(results)
+		return nil
+	},
+}
+
+// incomingWatchCmd imports the incoming folder periodically
+var incomingWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Import new files of the incoming folder as they appear",
+	Long: `Scan the incoming folder every downloads.incoming.interval (or --interval)
+and import the files that finished downloading. Runs until interrupted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			interval = cfg.Downloads.Incoming.Interval
+		}
+		if interval <= 0 {
+			interval = time.Minute
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		watcher, err := newIncomingWatcher(cmd)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Watching %s, press Ctrl+C to stop.\n", cfg.Downloads.Incoming.Path)
+		for {
+			printIncomingResults(watcher.Scan(ctx))
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+// newIncomingWatcher creates a watcher of the configured folder, or --dir
+func newIncomingWatcher(cmd *cobra.Command) (*incoming.Watcher, error) {
+	if incognito {
+		return nil, fmt.Errorf("importing is disabled in incognito mode")
+	}
+	if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+		cfg.Downloads.Incoming.Path = dir
+	}
+	dir := cfg.Downloads.Incoming.Path
+	if dir == "" {
+		return nil, fmt.Errorf("no incoming folder: set downloads.incoming.path or pass --dir")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("incoming folder %s not found", dir)
+	}
+
+	downloadMgr, err := downloader.NewManager(database.DB, &cfg.Downloads, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize download manager: %w", err)
+	}
+	mappings := mapping.NewManager(database.DB, "", logger)
+	return incoming.New(dir, downloadMgr, mappings, logger), nil
+}
+
+// printIncomingResults prints where imported files went and why others
+// were left in place
+func printIncomingResults(results []incoming.Result) {
+	for _, r := range results {
+		name := filepath.Base(r.Path)
+		var dup *downloader.DuplicateError
+		switch {
+		case r.Err == nil:
+			fmt.Printf("Imported %s -> %s\n", name, r.Task.OutputPath)
+		case errors.As(r.Err, &dup):
+			fmt.Printf("Skipped %s: already downloaded as %s\n", name, dup.Path)
+		case errors.Is(r.Err, incoming.ErrNoMatch):
+			fmt.Printf("Skipped %s: %v\n", name, r.Err)
+		default:
+			fmt.Printf("Failed %s: %v\n", name, r.Err)
+		}
+	}
+}
+
+func init() {
+	incomingScanCmd.Flags().String("dir", "", "folder to import from (default: downloads.incoming.path)")
+	incomingWatchCmd.Flags().String("dir", "", "folder to import from (default: downloads.incoming.path)")
+	incomingWatchCmd.Flags().Duration("interval", 0, "time between scans (default: downloads.incoming.interval)")
+
+	incomingCmd.AddCommand(incomingScanCmd)
+	incomingCmd.AddCommand(incomingWatchCmd)
+	rootCmd.AddCommand(incomingCmd)
+}
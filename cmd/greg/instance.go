@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/instance"
+)
+
+// errAlreadyRunning is returned when another greg owns the TUI and download queue
+var errAlreadyRunning = errors.New("greg is already running in another terminal")
+
+// lockInstance takes the single-instance lock. Returns errAlreadyRunning when
+// another greg holds it. If the lock can't be taken for any other reason greg
+// runs without it.
+func lockInstance() (*instance.Instance, error) {
+	inst, err := instance.Acquire(config.GetStateDir())
+	if errors.Is(err, instance.ErrRunning) {
+		return nil, errAlreadyRunning
+	}
+	if err != nil {
+		logger.Warn("failed to take instance lock", "error", err)
+		return nil, nil
+	}
+	return inst, nil
+}
+
+// forwardLink opens link in the running greg instead of starting a new one
+func forwardLink(link string) error {
+	return forwardRequest(instance.Request{Command: instance.CommandOpen, Args: []string{link}}, "Opened in the running greg instance")
+}
+
+// forwardRequest hands req to the running greg and prints done once it took it
+func forwardRequest(req instance.Request, done string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := instance.Forward(ctx, config.GetStateDir(), req); err != nil {
+		return fmt.Errorf("failed to %s in running greg: %w", req.Command, err)
+	}
+	fmt.Println(done)
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/lanshare"
+)
+
+// lanCmd groups the commands sharing downloads with other greg instances
+var lanCmd = &cobra.Command{
+	Use:   "lan",
+	Short: "Share downloads with other greg instances on the network",
+	Long: `Share downloads with other greg instances on the local network.
+Instances with downloads.lan.share on announce themselves over mDNS and serve
+their completed downloads. With downloads.lan.pull on, a queued episode another
+instance already downloaded is copied from it instead of the provider.`,
+}
+
+// lanPeersCmd lists the instances on the network and what they share
+var lanPeersCmd = &cobra.Command{
+	Use:   "peers",
+	Short: "List greg instances sharing downloads on the network",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wait, _ := cmd.Flags().GetDuration("wait")
+		showFiles, _ := cmd.Flags().GetBool("files")
+
+		peers, err := lanshare.Browse(cmd.Context(), wait)
+		if err != nil {
+			return err
+		}
+		if len(peers) == 0 {
+			fmt.Println("No greg instances found. Is downloads.lan.share on and mDNS allowed through the firewall?")
+			return nil
+		}
+
+		client := lanshare.NewClient(cfg.Downloads.LAN.Token)
+		for _, peer := range peers {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+			files, err := client.Files(ctx, peer, nil)
+			cancel()
+			if err != nil {
+				fmt.Printf("%-20s %-22s %v\n", peer.Name, peer.Addr, err)
+				continue
+			}
+
+			var size int64
+			for _, f := range files {
+				size += f.Size
+			}
+			fmt.Printf("%-20s %-22s %d files, %s\n", peer.Name, peer.Addr, len(files), humanize.IBytes(uint64(size)))
+			if showFiles {
+				for _, f := range files {
+					fmt.Printf("  %-40s S%02dE%03d  %-6s %s\n", f.MediaTitle, f.Season, f.Episode, f.Quality, humanize.IBytes(uint64(f.Size)))
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// lanServeCmd shares the completed downloads without the TUI running
+var lanServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Share completed downloads until stopped",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		if listen == "" {
+			listen = cfg.Downloads.LAN.Listen
+		}
+
+		server := lanshare.NewServer(database.DB, cfg.Downloads.LAN.Token)
+		if err := server.Listen(listen); err != nil {
+			return err
+		}
+		defer func() { _ = server.Close() }()
+		if err := server.Advertise(); err != nil {
+			logger.Warn("share server running but not discoverable", "error", err)
+			fmt.Printf("Warning: %v; peers can't discover this instance\n", err)
+		}
+
+		logger.Info("sharing downloads on the network", "addr", server.Addr())
+		fmt.Printf("Sharing downloads on %s\n", server.Addr())
+		if cfg.Downloads.LAN.Token == "" {
+			fmt.Println("No downloads.lan.token set: anyone on the network can fetch them.")
+		}
+		fmt.Println("Press Ctrl+C to stop.")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		<-ctx.Done()
+
+		return nil
+	},
+}
+
+func init() {
+	lanPeersCmd.Flags().Duration("wait", 2*time.Second, "how long to wait for instances to answer")
+	lanPeersCmd.Flags().Bool("files", false, "list the files each instance shares")
+	lanServeCmd.Flags().String("listen", "", "address to listen on (default: downloads.lan.listen)")
+
+	lanCmd.AddCommand(lanPeersCmd)
+	lanCmd.AddCommand(lanServeCmd)
+	rootCmd.AddCommand(lanCmd)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/downloader/tools"
+	"github.com/justchokingaround/greg/internal/mediaserver"
+)
+
+// libraryCmd groups the commands for the downloaded library
+var libraryCmd = &cobra.Command{
+	Use:   "library",
+	Short: "Downloaded library commands",
+}
+
+// libraryServeCmd serves the downloads to TVs over DLNA and HTTP
+var libraryServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve downloads to smart TVs over DLNA/HTTP",
+	Long: `Serve the downloads directory as a DLNA/UPnP media server until stopped.
+Smart TVs, consoles and apps such as VLC find it on the network and browse the
+same folders as on disk; the folders can also be browsed at http://<host>:<port>/.
+Videos in containers TVs often can't play are transcoded on the fly with ffmpeg
+(downloads.library.transcode).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		if listen == "" {
+			listen = cfg.Downloads.Library.Listen
+		}
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = cfg.Downloads.Library.Name
+		}
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			dir = cfg.Downloads.Path
+		}
+		transcode, _ := cmd.Flags().GetString("transcode")
+		if transcode == "" {
+			transcode = cfg.Downloads.Library.Transcode
+		}
+
+		mode := mediaserver.TranscodeMode(transcode)
+		switch mode {
+		case mediaserver.TranscodeAuto, mediaserver.TranscodeAlways, mediaserver.TranscodeNever:
+		default:
+			return fmt.Errorf("unknown transcode mode %q (use auto, always or never)", transcode)
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("downloads directory %s not found", dir)
+		}
+
+		ffmpeg, err := tools.FindTool("ffmpeg")
+		if err != nil && mode != mediaserver.TranscodeNever {
+			fmt.Println("ffmpeg not found: videos are served without transcoding.")
+		}
+
+		server := mediaserver.NewServer(dir, name, mode, ffmpeg, logger)
+		if err := server.Listen(listen); err != nil {
+			return err
+		}
+		defer func() { _ = server.Close() }()
+		if err := server.Advertise(); err != nil {
+			logger.Warn("media server running but not discoverable", "error", err)
+			fmt.Printf("Warning: %v; TVs won't find the server on their own\n", err)
+		}
+
+		logger.Info("media server started", "addr", server.Addr(), "dir", dir)
+		fmt.Printf("Serving %s as %q on %s\n", dir, name, server.Addr())
+		fmt.Println("Press Ctrl+C to stop.")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		<-ctx.Done()
+
+		return nil
+	},
+}
+
+func init() {
+	libraryServeCmd.Flags().String("listen", "", "address to listen on (default: downloads.library.listen)")
+	libraryServeCmd.Flags().String("name", "", "server name TVs show (default: downloads.library.name)")
+	libraryServeCmd.Flags().String("dir", "", "directory to serve (default: downloads.path)")
+	libraryServeCmd.Flags().String("transcode", "", "auto, always or never (default: downloads.library.transcode)")
+
+	libraryCmd.AddCommand(libraryServeCmd)
+	rootCmd.AddCommand(libraryCmd)
+}
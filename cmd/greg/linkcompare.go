@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// printComparisons prints the provider comparison of `greg debug links --compare`
+func printComparisons(comparisons []providers.Comparison) {
+	fmt.Printf("\n%-14s %-6s %-8s %-7s %-24s %-12s %s\n", "PROVIDER", "BEST", "LATENCY", "SOURCES", "SUBTITLES", "AUDIO", "QUALITIES")
+	for _, c := range comparisons {
+		if c.Error != "" {
+			fmt.Printf("%-14s failed after %s: %s\n", c.Provider, c.Latency.Round(10*time.Millisecond), c.Error)
+			continue
+		}
+
+		qualities := make([]string, 0, len(c.Qualities))
+		for _, q := range c.Qualities {
+			qualities = append(qualities, string(q))
+		}
+		best := string(c.Best())
+		if best == "" {
+			best = "-"
+		}
+		fmt.Printf("%-14s %-6s %-8s %-7d %-24s %-12s %s\n",
+			c.Provider,
+			best,
+			c.Latency.Round(10*time.Millisecond),
+			c.Sources,
+			summarizeList(c.Subtitles, 3),
+			summarizeList(c.Audio, 3),
+			summarizeList(qualities, len(qualities)),
+		)
+	}
+}
+
+// summarizeList joins the first limit values and counts the rest, "-" when empty
+func summarizeList(values []string, limit int) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	if len(values) <= limit {
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("%s +%d", strings.Join(values[:limit], ", "), len(values)-limit)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -15,12 +16,16 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/justchokingaround/greg/internal/audio"
 	"github.com/justchokingaround/greg/internal/clipboard"
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
 	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/instance"
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/registry"
+	"github.com/justchokingaround/greg/internal/resolve"
 	"github.com/justchokingaround/greg/internal/tracker"
 	"github.com/justchokingaround/greg/internal/tracker/anilist"
 	"github.com/justchokingaround/greg/internal/tui"
@@ -40,6 +45,16 @@ var (
 	debugMode  bool
 	dubFlag    bool
 	subFlag    bool
+	audioLang  string
+	recordDir  string
+	replayDir  string
+	incognito  bool
+	demo       bool
+	coWatch    string
+	sleepAt    string
+
+	// Link opened on TUI startup (set by 'greg open')
+	initialLink string
 
 	// Global config and logger
 	cfg    *config.Config
@@ -107,11 +122,33 @@ and concurrent downloads.`,
 			return fmt.Errorf("failed to initialize logger: %w", err)
 		}
 
+		// Demo mode runs offline on data of its own
+		if demo {
+			return setupDemo()
+		}
+
+		// Record or replay provider HTTP traffic
+		if err := setupFixtures(); err != nil {
+			return err
+		}
+
 		// Initialize database
 		if err := database.Init(&cfg.Database); err != nil {
 			return fmt.Errorf("failed to initialize database: %w", err)
 		}
 
+		// Purge expired trash items
+		if cfg.Database.TrashRetention > 0 {
+			if n, err := database.PurgeTrash(database.DB, cfg.Database.TrashRetention); err != nil {
+				logger.Warn("failed to purge trash", "error", err)
+			} else if n > 0 {
+				logger.Debug("purged trash", "items", n)
+			}
+		}
+
+		// Bind provider traffic to the configured interface
+		netbind.Configure(cfg.Network.BindInterface, cfg.Network.ProviderInterfaces)
+
 		// Initialize new registry and load providers
 		reg := registry.New()
 		reg.Load(cfg)
@@ -130,6 +167,7 @@ and concurrent downloads.`,
 				logger.Debug("registered provider", "name", name)
 			}
 		}
+		loadProviderAvailability()
 
 		// Setup hot reload
 		v.WatchConfig()
@@ -140,6 +178,7 @@ and concurrent downloads.`,
 				logger.Error("Failed to reload config", "error", err)
 				return
 			}
+			netbind.Configure(cfg.Network.BindInterface, cfg.Network.ProviderInterfaces)
 			// Reload registry
 			reg.Load(cfg)
 			// Re-register providers
@@ -153,6 +192,7 @@ and concurrent downloads.`,
 					logger.Warn("failed to register provider", "name", name, "error", err)
 				}
 			}
+			loadProviderAvailability()
 			logger.Info("Providers reloaded")
 		})
 
@@ -160,6 +200,7 @@ and concurrent downloads.`,
 		go func() {
 			logger.Info("Running provider health checks...")
 			providers.CheckAllProviders(context.Background())
+			recordHealthChecks()
 			logger.Info("Provider health checks complete.")
 		}()
 
@@ -170,41 +211,43 @@ and concurrent downloads.`,
 		if err := database.Close(); err != nil {
 			logger.Error("failed to close database", "error", err)
 		}
+		cleanupDemo()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Default behavior: launch TUI
+		inst, err := lockInstance()
+		if errors.Is(err, errAlreadyRunning) && initialLink != "" {
+			return forwardLink(initialLink)
+		}
+		if err != nil {
+			return err
+		}
+		if inst != nil {
+			defer func() { _ = inst.Close() }()
+		}
+
 		logger.Info("greg starting...", "version", version)
 
 		providerMap := make(map[providers.MediaType]providers.Provider)
 
-		// Get anime provider - use configured default
-		animeProvider, err := providers.Get(cfg.Providers.Default.Anime)
+		// Get anime provider - use configured default unless it looks geo-blocked
+		animeProvider, err := providers.Preferred(cfg.Providers.Default.Anime, providers.MediaTypeAnime)
 		if err != nil {
-			// Fallback to first available anime provider
-			animeProviders := providers.GetByType(providers.MediaTypeAnime)
-			if len(animeProviders) > 0 {
-				animeProvider = animeProviders[0]
-				logger.Warn("default anime provider not available, using fallback", "default", cfg.Providers.Default.Anime, "fallback", animeProvider.Name())
-			} else {
-				logger.Warn("no anime providers available")
-			}
+			logger.Warn("no anime providers available")
+		} else if animeProvider.Name() != cfg.Providers.Default.Anime {
+			logger.Warn("default anime provider not available or likely geo-blocked, using fallback", "default", cfg.Providers.Default.Anime, "fallback", animeProvider.Name())
 		}
 		if animeProvider != nil {
 			providerMap[providers.MediaTypeAnime] = animeProvider
 			logger.Info("using anime provider", "provider", animeProvider.Name())
 		}
 
-		// Get movie provider - use configured default
-		movieProvider, err := providers.Get(cfg.Providers.Default.MoviesAndTV)
+		// Get movie provider - use configured default unless it looks geo-blocked
+		movieProvider, err := providers.Preferred(cfg.Providers.Default.MoviesAndTV, providers.MediaTypeMovieTV)
 		if err != nil {
-			// Fallback to first available movie/tv provider
-			movieProviders := providers.GetByType(providers.MediaTypeMovieTV)
-			if len(movieProviders) > 0 {
-				movieProvider = movieProviders[0]
-				logger.Warn("default movie provider not available, using fallback", "default", cfg.Providers.Default.MoviesAndTV, "fallback", movieProvider.Name())
-			} else {
-				logger.Warn("no movie/tv providers available")
-			}
+			logger.Warn("no movie/tv providers available")
+		} else if movieProvider.Name() != cfg.Providers.Default.MoviesAndTV {
+			logger.Warn("default movie provider not available or likely geo-blocked, using fallback", "default", cfg.Providers.Default.MoviesAndTV, "fallback", movieProvider.Name())
 		}
 		if movieProvider != nil {
 			providerMap[providers.MediaTypeMovieTV] = movieProvider
@@ -213,11 +256,21 @@ and concurrent downloads.`,
 			logger.Info("using movie provider", "provider", movieProvider.Name())
 		}
 
-		// Get manga provider
-		mangaProviders := providers.GetByType(providers.MediaTypeManga)
-		if len(mangaProviders) > 0 {
-			providerMap[providers.MediaTypeManga] = mangaProviders[0]
-			logger.Info("using manga provider", "provider", mangaProviders[0].Name())
+		// Get manga provider - use configured default unless it looks geo-blocked
+		mangaProvider, err := providers.Preferred(cfg.Providers.Default.Manga, providers.MediaTypeManga)
+		if err == nil && mangaProvider.Type() != providers.MediaTypeManga {
+			// The default isn't a manga provider, fall back to the first one
+			mangaProvider = nil
+			if mangaProviders := providers.GetByType(providers.MediaTypeManga); len(mangaProviders) > 0 {
+				mangaProvider = mangaProviders[0]
+			}
+		}
+		if mangaProvider != nil && mangaProvider.Name() != cfg.Providers.Default.Manga {
+			logger.Warn("default manga provider not available or likely geo-blocked, using fallback", "default", cfg.Providers.Default.Manga, "fallback", mangaProvider.Name())
+		}
+		if mangaProvider != nil {
+			providerMap[providers.MediaTypeManga] = mangaProvider
+			logger.Info("using manga provider", "provider", mangaProvider.Name())
 		}
 
 		if len(providerMap) == 0 {
@@ -245,19 +298,43 @@ and concurrent downloads.`,
 			}
 		}
 
-		// Determine audio preference from CLI flags
-		audioPreference := cfg.Player.AudioPreference // Config default
-		if dubFlag {
+		// Determine audio preference from CLI flags; when unset the TUI falls
+		// back to the per-show memory and then the config default
+		audioPreference := ""
+		switch {
+		case dubFlag:
 			audioPreference = "dub"
-		} else if subFlag {
+		case subFlag:
 			audioPreference = "sub"
+		case audioLang != "":
+			audioPreference = audio.NormalizeLanguage(audioLang)
+			if audioPreference == "" {
+				return fmt.Errorf("unknown audio language: %s", audioLang)
+			}
 		}
 
+		tui.Version = version
+		tui.Incognito = incognito
+		if sleepAt != "" {
+			if err := tui.CheckSleepTimer(sleepAt); err != nil {
+				return err
+			}
+			tui.SleepTimer = sleepAt
+		}
+		if coWatch != "" {
+			profile, err := findCoWatchProfile(coWatch)
+			if err != nil {
+				return err
+			}
+			tui.CoWatch = profile.Name
+		}
+		applyTheme()
+
 		var debugInfo *tui.DebugInfo
 		if debugLinks {
 			debugInfo = tui.StartDebugLinks(providerMap, trackerMgr, database.DB, cfg, logger, audioPreference)
 		} else {
-			debugInfo = tui.Start(providerMap, trackerMgr, database.DB, cfg, logger, audioPreference)
+			debugInfo = tui.Start(providerMap, trackerMgr, database.DB, cfg, logger, audioPreference, initialLink, inst)
 		}
 
 		// Print the debug info after TUI exits (if in debug mode)
@@ -315,9 +392,17 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "enable debug mode (verbose HTTP logging, skip playback, print JSON output)")
 	rootCmd.PersistentFlags().BoolVar(&dubFlag, "dub", false, "use dubbed audio track (overrides config)")
 	rootCmd.PersistentFlags().BoolVar(&subFlag, "sub", false, "use subbed audio track (overrides config)")
+	rootCmd.PersistentFlags().StringVar(&audioLang, "audio-lang", "", "preferred audio language, e.g. ja, en, ru (overrides config)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "record provider HTTP responses as fixtures into this directory (default: ./"+defaultFixtureDir+")")
+	rootCmd.PersistentFlags().Lookup("record").NoOptDefVal = defaultFixtureDir
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "answer provider HTTP requests from the fixtures in this directory, offline")
+	rootCmd.PersistentFlags().BoolVar(&incognito, "incognito", false, "don't record history, sync AniList or match feed subscriptions this session")
+	rootCmd.PersistentFlags().StringVar(&sleepAt, "sleep", "", "sleep timer: stop after this episode (episode), after a duration (45m) or at a time (23:30)")
+	rootCmd.Flags().BoolVar(&demo, "demo", false, "showcase the TUI offline: a fake catalog and player, nothing saved")
+	rootCmd.PersistentFlags().StringVar(&coWatch, "with", "", "watch with a co-watch profile: progress is kept apart from history and AniList")
 
 	// Mark as mutually exclusive
-	rootCmd.MarkFlagsMutuallyExclusive("dub", "sub")
+	rootCmd.MarkFlagsMutuallyExclusive("dub", "sub", "audio-lang")
 
 	// Add subcommands
 	rootCmd.AddCommand(versionCmd)
@@ -384,7 +469,11 @@ var configShowCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Config file: %s\n", cfgFile)
 		fmt.Printf("Log level: %s\n", cfg.Logging.Level)
-		fmt.Printf("Database: %s\n", cfg.Database.Path)
+		if cfg.Database.Driver == "" || cfg.Database.Driver == database.DriverSQLite {
+			fmt.Printf("Database: %s\n", cfg.Database.Path)
+		} else {
+			fmt.Printf("Database: %s (remote)\n", cfg.Database.Driver)
+		}
 		return nil
 	},
 }
@@ -417,9 +506,6 @@ var searchCmd = &cobra.Command{
 		providerName, _ := cmd.Flags().GetString("provider")
 		mediaType, _ := cmd.Flags().GetString("type")
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
 		// Get provider
 		var provider providers.Provider
 		var err error
@@ -433,38 +519,29 @@ var searchCmd = &cobra.Command{
 			// Determine which provider to use based on type
 			switch mediaType {
 			case "anime":
-				provider, err = providers.Get(cfg.Providers.Default.Anime)
+				provider, err = providers.Preferred(cfg.Providers.Default.Anime, providers.MediaTypeAnime)
 				if err != nil {
-					allProviders := providers.GetByType(providers.MediaTypeAnime)
-					if len(allProviders) == 0 {
-						return fmt.Errorf("no anime providers available")
-					}
-					provider = allProviders[0]
+					return fmt.Errorf("no anime providers available")
 				}
 			case "movie", "movies", "tv", "shows":
-				provider, err = providers.Get(cfg.Providers.Default.MoviesAndTV)
+				provider, err = providers.Preferred(cfg.Providers.Default.MoviesAndTV, providers.MediaTypeMovieTV)
 				if err != nil {
-					allProviders := providers.GetByType(providers.MediaTypeMovieTV)
-					if len(allProviders) == 0 {
-						return fmt.Errorf("no movie/TV providers available")
-					}
-					provider = allProviders[0]
+					return fmt.Errorf("no movie/TV providers available")
 				}
 			default:
 				// Default to anime if not specified
-				provider, err = providers.Get(cfg.Providers.Default.Anime)
+				provider, err = providers.Preferred(cfg.Providers.Default.Anime, providers.MediaTypeAnime)
 				if err != nil {
-					allProviders := providers.GetByType(providers.MediaTypeAnime)
-					if len(allProviders) == 0 {
-						return fmt.Errorf("no providers available")
-					}
-					provider = allProviders[0]
+					return fmt.Errorf("no providers available")
 				}
 			}
 		}
 
 		logger.Info("searching", "query", query, "provider", provider.Name())
 
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Network.Timeouts.For(provider.Name(), config.TimeoutSearch))
+		defer cancel()
+
 		// Search
 		results, err := provider.Search(ctx, query)
 		if err != nil {
@@ -534,13 +611,13 @@ var downloadCmd = &cobra.Command{
 		episodeRange, _ := cmd.Flags().GetString("episode")
 		quality, _ := cmd.Flags().GetString("quality")
 		outputDir, _ := cmd.Flags().GetString("output")
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		onDuplicate, err := parseDuplicateFlag(cmd)
+		if err != nil {
+			return err
+		}
 
 		// Get provider
 		var provider providers.Provider
-		var err error
 
 		if providerName != "" {
 			provider, err = providers.Get(providerName)
@@ -581,7 +658,30 @@ var downloadCmd = &cobra.Command{
 			}
 		}
 
+		// A running greg owns the download queue, so it queues the episodes
+		inst, err := lockInstance()
+		if errors.Is(err, errAlreadyRunning) {
+			if outputDir != "" {
+				return fmt.Errorf("%w, --output can't be used while it runs", err)
+			}
+			req := instance.Request{
+				Command: instance.CommandDownload,
+				Args:    []string{provider.Name(), mediaID, episodeRange, quality, string(onDuplicate)},
+			}
+			return forwardRequest(req, "Queuing in the running greg instance, see its download queue")
+		}
+		if err != nil {
+			return err
+		}
+		if inst != nil {
+			defer func() { _ = inst.Close() }()
+		}
+
 		logger.Info("downloading", "media_id", mediaID, "provider", provider.Name())
+		warnIgnoredQuality(cmd, provider)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Network.Timeouts.Budget(provider.Name(), config.TimeoutDetails, config.TimeoutStream))
+		defer cancel()
 
 		// Check if it's a movie (no episodes) or TV/anime (with episodes)
 		mediaDetails, err := provider.GetMediaDetails(ctx, mediaID)
@@ -591,19 +691,9 @@ var downloadCmd = &cobra.Command{
 
 		// If it's a movie, get the movie episode ID directly
 		if len(mediaDetails.Seasons) == 0 && mediaDetails.Type == providers.MediaTypeMovie {
-			// This is a movie, so get the episode ID directly
-			type movieEpisodeIDGetter interface {
-				GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-			}
-
-			episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-			if !ok {
-				return fmt.Errorf("provider does not support direct movie playback/download")
-			}
-
-			episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), mediaID)
+			episodeID, err := providers.MovieEpisodeID(ctx, provider, mediaID)
 			if err != nil {
-				return fmt.Errorf("failed to get movie episode ID: %w", err)
+				return err
 			}
 
 			// Get the stream URL
@@ -647,6 +737,8 @@ var downloadCmd = &cobra.Command{
 				Referer:    stream.Referer,
 				Subtitles:  stream.Subtitles,
 				EmbedSubs:  cfg.Downloads.EmbedSubtitles,
+
+				OnDuplicate: onDuplicate,
 			}
 
 			// Set output directory if specified
@@ -692,14 +784,9 @@ var downloadCmd = &cobra.Command{
 		} else {
 			// This is TV/anime with episodes
 			// For now, just download the first season
-			if len(mediaDetails.Seasons) == 0 {
-				return fmt.Errorf("no seasons found for %s", mediaDetails.Title)
-			}
-
-			// Get episodes for the first season
-			episodes, err := provider.GetEpisodes(ctx, mediaDetails.Seasons[0].ID)
+			episodes, err := resolve.Episodes(ctx, provider, mediaID, 0)
 			if err != nil {
-				return fmt.Errorf("failed to get episodes: %w", err)
+				return err
 			}
 
 			// Determine which episodes to download based on range
@@ -767,6 +854,8 @@ var downloadCmd = &cobra.Command{
 					Referer:    stream.Referer,
 					Subtitles:  stream.Subtitles,
 					EmbedSubs:  cfg.Downloads.EmbedSubtitles,
+
+					OnDuplicate: onDuplicate,
 				}
 
 				// Add to download queue
@@ -847,11 +936,33 @@ var providersListCmd = &cobra.Command{
 		fmt.Printf("Available providers (%d):\n\n", len(providersList))
 		for _, name := range providersList {
 			provider, _ := providers.Get(name)
-			fmt.Printf("- %s (Type: %s)\n", name, provider.Type())
+			supports := capabilityList(provider.Capabilities())
+			if providers.LikelyGeoBlocked(name) {
+				fmt.Printf("- %s (Type: %s, supports: %s) (likely geo-blocked)\n", name, provider.Type(), supports)
+				continue
+			}
+			fmt.Printf("- %s (Type: %s, supports: %s)\n", name, provider.Type(), supports)
 		}
 	},
 }
 
+// warnIgnoredQuality tells that --quality has no effect on a provider
+// offering a single stream per episode
+func warnIgnoredQuality(cmd *cobra.Command, provider providers.Provider) {
+	if cmd.Flags().Changed("quality") && !provider.Capabilities().Qualities {
+		fmt.Fprintf(os.Stderr, "%s offers a single stream per episode, --quality is ignored\n", provider.Name())
+	}
+}
+
+// capabilityList lists the features a provider supports
+func capabilityList(caps providers.Capabilities) string {
+	names := caps.Names()
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
 var providersInfoCmd = &cobra.Command{
 	Use:   "info <provider-name>",
 	Short: "Get information about a provider",
@@ -869,6 +980,7 @@ var providersInfoCmd = &cobra.Command{
 
 		fmt.Printf("Provider: %s\n", provider.Name())
 		fmt.Printf("Type: %s\n", provider.Type())
+		fmt.Printf("Supports: %s\n", capabilityList(provider.Capabilities()))
 
 		// Check availability
 		fmt.Print("Status: ")
@@ -902,9 +1014,7 @@ var debugLinksCmd = &cobra.Command{
 		providerName, _ := cmd.Flags().GetString("provider")
 		mediaTypeStr, _ := cmd.Flags().GetString("type")
 		episodeStr, _ := cmd.Flags().GetString("episode")
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		compare, _ := cmd.Flags().GetBool("compare")
 
 		// Parse media type
 		var mediaType providers.MediaType
@@ -949,18 +1059,22 @@ var debugLinksCmd = &cobra.Command{
 
 		logger.Info("searching for media", "query", query, "provider", provider.Name())
 
-		// Search for the media
-		results, err := provider.Search(ctx, query)
-		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
-		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Network.Timeouts.Budget(provider.Name(), config.TimeoutSearch, config.TimeoutDetails, config.TimeoutStream))
+		defer cancel()
 
-		if len(results) == 0 {
-			return fmt.Errorf("no results found for query: %s", query)
+		episodeNum := 0
+		if episodeStr != "" {
+			episodeNum, err = strconv.Atoi(episodeStr)
+			if err != nil {
+				return fmt.Errorf("invalid episode number: %s", episodeStr)
+			}
 		}
 
-		// Use the first result for now
-		media := results[0]
+		res, err := resolve.EpisodeStream(ctx, provider, resolve.MediaRef{Title: query}, resolve.EpisodeSelector{Episode: episodeNum}, resolve.Prefs{Quality: providers.QualityAuto})
+		if err != nil {
+			return err
+		}
+		media, targetEpisode, stream := res.Media, res.Episode, res.Stream
 		logger.Info("found media", "title", media.Title, "id", media.ID)
 
 		fmt.Printf("Media: %s (ID: %s)\n", media.Title, media.ID)
@@ -969,94 +1083,7 @@ var debugLinksCmd = &cobra.Command{
 			fmt.Printf("Total Episodes: %d\n", media.TotalEpisodes)
 		}
 		fmt.Println()
-
-		// Get seasons from provider
-		seasons, err := provider.GetSeasons(ctx, media.ID)
-		if err != nil {
-			return fmt.Errorf("failed to get seasons: %w", err)
-		}
-
-		var episodes []providers.Episode
-		if len(seasons) > 0 {
-			// For debugging, just use the first season
-			firstSeason := seasons[0]
-			logger.Info("using first season", "season_id", firstSeason.ID, "season_title", firstSeason.Title)
-
-			episodes, err = provider.GetEpisodes(ctx, firstSeason.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get episodes: %w", err)
-			}
-		} else if media.Type == providers.MediaTypeMovie {
-			// It's a movie, so we need to get the movie episode ID
-			type movieEpisodeIDGetter interface {
-				GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-			}
-
-			episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-			if !ok {
-				return fmt.Errorf("provider does not support direct movie playback")
-			}
-
-			episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), media.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get movie episode ID: %w", err)
-			}
-			episodes = []providers.Episode{
-				{
-					ID:     episodeID,
-					Number: 1,
-					Title:  media.Title,
-				},
-			}
-		}
-
-		// If no episodes were returned (fallback), create a single episode
-		if len(episodes) == 0 {
-			episodes = []providers.Episode{
-				{
-					ID:     media.ID,
-					Number: 1,
-					Title:  "Movie",
-				},
-			}
-		}
-
-		// Determine which episode to get stream URL for
-		var targetEpisode *providers.Episode
-		if episodeStr != "" {
-			episodeNum, err := strconv.Atoi(episodeStr)
-			if err != nil {
-				return fmt.Errorf("invalid episode number: %s", episodeStr)
-			}
-
-			for _, ep := range episodes {
-				if ep.Number == episodeNum {
-					targetEpisode = &ep
-					break
-				}
-			}
-
-			if targetEpisode == nil {
-				return fmt.Errorf("episode %d not found", episodeNum)
-			}
-		} else {
-			// Use first episode if no specific episode requested
-			if len(episodes) > 0 {
-				targetEpisode = &episodes[0]
-			} else {
-				return fmt.Errorf("no episodes available")
-			}
-		}
-
-		// Get stream URL for the specific episode
-		logger.Info("getting stream URL", "episode", targetEpisode.Number, "episode_id", targetEpisode.ID)
-
-		fmt.Printf("Attempting to get stream URL for episode ID: %s\n", targetEpisode.ID)
-
-		stream, err := provider.GetStreamURL(ctx, targetEpisode.ID, providers.QualityAuto)
-		if err != nil {
-			return fmt.Errorf("failed to get stream URL: %w", err)
-		}
+		fmt.Printf("Episode ID: %s\n", targetEpisode.ID)
 
 		// Display the stream info
 		fmt.Printf("Episode: %s (Number: %d)\n", targetEpisode.Title, targetEpisode.Number)
@@ -1082,6 +1109,17 @@ var debugLinksCmd = &cobra.Command{
 			}
 		}
 
+		if compare {
+			candidates := providers.GetByType(provider.Type())
+			fmt.Printf("\nComparing %d providers...\n", len(candidates))
+
+			compareCtx, cancel := context.WithTimeout(context.Background(), cfg.Network.Timeouts.Budget(provider.Name(), config.TimeoutSearch, config.TimeoutDetails, config.TimeoutStream))
+			defer cancel()
+			comparisons := providers.CompareProviders(compareCtx, candidates, media.Title, targetEpisode.Season, targetEpisode.Number)
+			providers.RankComparisons(comparisons)
+			printComparisons(comparisons)
+		}
+
 		return nil
 	},
 }
@@ -1091,6 +1129,7 @@ func init() {
 	debugLinksCmd.Flags().StringP("provider", "p", "", "provider to use (default: first available of specified type)")
 	debugLinksCmd.Flags().StringP("type", "t", "anime", "media type (anime, movie, tv, movie_tv)")
 	debugLinksCmd.Flags().StringP("episode", "e", "", "specific episode number to get links for")
+	debugLinksCmd.Flags().BoolP("compare", "c", false, "also resolve the episode on every provider of its type and compare them")
 	rootCmd.AddCommand(debugCmd)
 }
 
@@ -1296,6 +1335,21 @@ var authStatusCmd = &cobra.Command{
 	},
 }
 
+// parseDuplicateFlag reads what --duplicate says to do with episodes that
+// were already downloaded
+func parseDuplicateFlag(cmd *cobra.Command) (downloader.DuplicateAction, error) {
+	value, _ := cmd.Flags().GetString("duplicate")
+	switch value {
+	case "skip", "":
+		return downloader.DuplicateRefuse, nil
+	case "overwrite":
+		return downloader.DuplicateOverwrite, nil
+	case "keep":
+		return downloader.DuplicateKeepBoth, nil
+	}
+	return "", fmt.Errorf("invalid --duplicate %q: use skip, overwrite or keep", value)
+}
+
 func init() {
 	// Add download command flags
 	downloadCmd.Flags().StringP("provider", "p", "", "provider to use (default: auto-detect by type)")
@@ -1303,6 +1357,7 @@ func init() {
 	downloadCmd.Flags().StringP("episode", "e", "", "episode range (e.g., 1-5, 7, 9-12) - TV/anime only")
 	downloadCmd.Flags().StringP("quality", "q", "1080p", "video quality (360p, 480p, 720p, 1080p, etc.)")
 	downloadCmd.Flags().StringP("output", "o", "", "output directory (default: config setting)")
+	downloadCmd.Flags().String("duplicate", "skip", "episodes already downloaded: skip, overwrite or keep (both)")
 
 	authCmd.AddCommand(authAniListCmd)
 	authCmd.AddCommand(authStatusCmd)
@@ -1318,18 +1373,11 @@ This command searches for the specified media, gets the stream URL,
 applies proxy headers if needed, and generates a WatchParty URL.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		query := args[0]
-		providerName, _ := cmd.Flags().GetString("provider")
-		mediaType, _ := cmd.Flags().GetString("type")
-		episodeNum, _ := cmd.Flags().GetInt("episode")
 		qualityStr, _ := cmd.Flags().GetString("quality")
 		proxyURL, _ := cmd.Flags().GetString("proxy")
 		origin, _ := cmd.Flags().GetString("origin")
 		openBrowser, _ := cmd.Flags().GetBool("open")
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
 		// Determine quality
 		quality := providers.Quality1080p
 		if qualityStr != "" {
@@ -1340,131 +1388,20 @@ applies proxy headers if needed, and generates a WatchParty URL.`,
 			quality = parsedQuality
 		}
 
-		// Get provider
-		var provider providers.Provider
-		var err error
-
-		if providerName != "" {
-			provider, err = providers.Get(providerName)
-			if err != nil {
-				return fmt.Errorf("provider %s not found: %w", providerName, err)
-			}
-		} else {
-			// Determine which provider to use based on type
-			switch mediaType {
-			case "anime":
-				provider, err = providers.Get(cfg.Providers.Default.Anime)
-				if err != nil {
-					allProviders := providers.GetByType(providers.MediaTypeAnime)
-					if len(allProviders) == 0 {
-						return fmt.Errorf("no anime providers available")
-					}
-					provider = allProviders[0]
-				}
-			case "movie", "movies", "tv", "shows":
-				provider, err = providers.Get(cfg.Providers.Default.MoviesAndTV)
-				if err != nil {
-					allProviders := providers.GetByType(providers.MediaTypeMovieTV)
-					if len(allProviders) == 0 {
-						return fmt.Errorf("no movie/TV providers available")
-					}
-					provider = allProviders[0]
-				}
-			default:
-				// Default to anime if not specified
-				provider, err = providers.Get(cfg.Providers.Default.Anime)
-				if err != nil {
-					allProviders := providers.GetByType(providers.MediaTypeAnime)
-					if len(allProviders) == 0 {
-						return fmt.Errorf("no providers available")
-					}
-					provider = allProviders[0]
-				}
-			}
-		}
-
-		logger.Info("searching for media", "query", query, "provider", provider.Name())
-
-		// Search for media
-		results, err := provider.Search(ctx, query)
+		provider, err := watchPartyProvider(cmd)
 		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
+			return err
 		}
 
-		if len(results) == 0 {
-			return fmt.Errorf("no results found for query: %s", query)
-		}
-
-		// Use the first result for now
-		media := results[0]
-		logger.Info("found media", "title", media.Title, "id", media.ID)
+		ctx, cancel := context.WithTimeout(context.Background(), watchPartyBudget(provider))
+		defer cancel()
 
-		// Get seasons from provider to determine if it's TV/anime or movie
-		seasons, err := provider.GetSeasons(ctx, media.ID)
+		target, err := resolveWatchPartyTarget(ctx, cmd, provider, args[0])
 		if err != nil {
-			return fmt.Errorf("failed to get seasons: %w", err)
-		}
-
-		var episodeID string
-		var episodeNumber int
-
-		if len(seasons) > 0 {
-			// This is TV/anime with episodes
-			var episodes []providers.Episode
-			if len(seasons) > 0 {
-				// For simplicity, use the first season
-				firstSeason := seasons[0]
-				logger.Info("using first season", "season_id", firstSeason.ID, "season_title", firstSeason.Title)
-
-				episodes, err = provider.GetEpisodes(ctx, firstSeason.ID)
-				if err != nil {
-					return fmt.Errorf("failed to get episodes: %w", err)
-				}
-			}
-
-			// Determine which episode to use
-			if episodeNum > 0 {
-				// Specific episode requested
-				for _, ep := range episodes {
-					if ep.Number == episodeNum {
-						episodeID = ep.ID
-						episodeNumber = ep.Number
-						break
-					}
-				}
-				if episodeID == "" {
-					return fmt.Errorf("episode %d not found", episodeNum)
-				}
-			} else {
-				// Use first episode if no specific episode requested
-				if len(episodes) > 0 {
-					episodeID = episodes[0].ID
-					episodeNumber = episodes[0].Number
-				} else {
-					return fmt.Errorf("no episodes available")
-				}
-			}
-		} else if media.Type == providers.MediaTypeMovie {
-			// It's a movie, so we need to get the movie episode ID
-			type movieEpisodeIDGetter interface {
-				GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-			}
-
-			episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-			if !ok {
-				return fmt.Errorf("provider does not support direct movie playback")
-			}
-
-			episodeID, err = episodeIDGetter.GetMovieEpisodeID(context.Background(), media.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get movie episode ID: %w", err)
-			}
-			episodeNumber = 1
-		} else {
-			// Fallback: treat as single episode
-			episodeID = media.ID
-			episodeNumber = 1
+			return err
 		}
+		provider, media, episodeID, episodeNumber := target.provider, target.media, target.episodeID, target.episodeNumber
+		warnIgnoredQuality(cmd, provider)
 
 		// Create WatchParty manager
 		wpConfig := watchparty.Config{
@@ -1472,6 +1409,8 @@ applies proxy headers if needed, and generates a WatchParty URL.`,
 			DefaultProxy:    cfg.WatchParty.DefaultProxy,
 			AutoOpenBrowser: cfg.WatchParty.AutoOpenBrowser,
 			DefaultOrigin:   cfg.WatchParty.DefaultOrigin,
+			SubtitleLang:    cfg.Player.SubtitleLang,
+			StateDir:        config.GetStateDir(),
 		}
 		wpManager := watchparty.NewManager(wpConfig)
 
@@ -1480,6 +1419,11 @@ applies proxy headers if needed, and generates a WatchParty URL.`,
 		if finalProxyURL == "" {
 			finalProxyURL = cfg.WatchParty.DefaultProxy
 		}
+		finalProxyURL, err = watchparty.ResolveProxyURL(finalProxyURL, cfg.WatchParty.ProxyPublicURL, config.GetStateDir())
+		if err != nil {
+			logger.Warn("no watchparty proxy", "error", err)
+			fmt.Printf("Warning: %v; the stream URL is shared unproxied\n", err)
+		}
 
 		finalOrigin := origin
 		if finalOrigin == "" {
@@ -1501,6 +1445,23 @@ applies proxy headers if needed, and generates a WatchParty URL.`,
 			return fmt.Errorf("failed to create WatchParty: %w", err)
 		}
 
+		// Remember the session so 'greg watchparty next' can continue from here
+		session := &watchparty.Session{
+			Provider:      provider.Name(),
+			MediaID:       media.ID,
+			MediaTitle:    media.Title,
+			SeasonNumber:  target.seasonNumber,
+			EpisodeID:     episodeID,
+			EpisodeNumber: episodeNumber,
+			Quality:       string(quality),
+			ProxyURL:      finalProxyURL,
+			Origin:        finalOrigin,
+			WatchPartyURL: watchPartyURL,
+		}
+		if err := watchparty.SaveSession(database.DB, session); err != nil {
+			logger.Warn("failed to save watchparty session", "error", err)
+		}
+
 		fmt.Printf("WatchParty room created!\n")
 		fmt.Printf("Media: %s\n", media.Title)
 		if episodeNumber > 0 {
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/links"
+)
+
+// openCmd launches the TUI straight into a title from a link
+var openCmd = &cobra.Command{
+	Use:   "open <url>",
+	Short: "Open an AniList, MyAnimeList or provider link",
+	Long: `Open an AniList, MyAnimeList or provider link in the TUI.
+greg resolves the title and jumps straight to its episode list, skipping the
+manual search. If greg is already running, the link is opened there instead
+of starting a second instance. Supported links:
+  https://anilist.co/anime/<id>
+  https://myanimelist.net/anime/<id>
+  https://hianime.to/watch/<id>
+  https://flixhq.to/movie/<slug>, https://flixhq.to/tv/<slug>
+  https://sflix.ps/movie/<slug>, https://sflix.ps/tv/<slug>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := links.Parse(args[0]); !ok {
+			return fmt.Errorf("unsupported link: %s", args[0])
+		}
+
+		initialLink = args[0]
+		return rootCmd.RunE(cmd, nil)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
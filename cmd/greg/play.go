@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/instance"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/player/mpv"
+	"github.com/justchokingaround/greg/internal/providers/manual"
+)
+
+// playCmd plays an arbitrary stream URL in mpv
+var playCmd = &cobra.Command{
+	Use:   "play --url <url>",
+	Short: "Play a stream URL directly",
+	Long: `Play an HLS playlist or video file URL in mpv, without a provider.
+Headers the stream needs can be passed with --referer and --header. The
+playback is recorded in the history under the "manual" provider and resumes
+where it was left off next time. If greg is already running, the stream is
+played there instead, which asks whether to resume.`,
+	Example: `  greg play --url https://example.com/hls/master.m3u8 --referer https://example.com/
+  greg play --url https://example.com/video.mp4 --header "Origin: https://example.com"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rawURL, _ := cmd.Flags().GetString("url")
+		referer, _ := cmd.Flags().GetString("referer")
+		headerFlags, _ := cmd.Flags().GetStringArray("header")
+		title, _ := cmd.Flags().GetString("title")
+		startOver, _ := cmd.Flags().GetBool("start-over")
+
+		if rawURL == "" {
+			return fmt.Errorf("--url is required")
+		}
+		headers, err := manual.ParseHeaders(headerFlags)
+		if err != nil {
+			return err
+		}
+		stream, err := manual.Stream(rawURL, referer, headers)
+		if err != nil {
+			return err
+		}
+
+		// A running greg owns the player and history, so it plays the stream
+		inst, err := lockInstance()
+		if errors.Is(err, errAlreadyRunning) {
+			req := instance.Request{
+				Command: instance.CommandPlay,
+				Args:    append([]string{stream.URL, referer, title}, headerFlags...),
+			}
+			return forwardRequest(req, "Playing in the running greg instance")
+		}
+		if err != nil {
+			return err
+		}
+		if inst != nil {
+			defer func() { _ = inst.Close() }()
+		}
+
+		if title == "" {
+			title = manual.Title(stream.URL)
+		}
+
+		options := player.PlayOptions{
+			Title:   title,
+			Headers: stream.Headers,
+			Referer: stream.Referer,
+		}
+		if cfg.Player.Resume && !startOver {
+			if entry, err := database.UnfinishedProgress(database.DB, stream.URL, 0, 0); err != nil {
+				logger.Warn("failed to look up resume position", "error", err)
+			} else if entry != nil {
+				options.StartTime = time.Duration(entry.ProgressSeconds) * time.Second
+				fmt.Printf("Resuming at %s (use --start-over to play from the start)\n", options.StartTime)
+			}
+		}
+
+		progress, err := playManual(stream.URL, options)
+		if err != nil {
+			return err
+		}
+		if progress == nil || progress.Duration <= 0 || incognito {
+			return nil
+		}
+
+		completion := player.CompletionRule{
+			Rule:      cfg.Player.Completion.Rule,
+			Percent:   cfg.Player.Completion.Percent,
+			Remaining: cfg.Player.Completion.Remaining,
+		}
+		entry := database.History{
+			MediaID:         stream.URL,
+			MediaTitle:      title,
+			MediaType:       "movie",
+			ProgressSeconds: int(progress.CurrentTime.Seconds()),
+			TotalSeconds:    int(progress.Duration.Seconds()),
+			ProgressPercent: progress.Percentage,
+			WatchedAt:       time.Now(),
+			Completed:       completion.Completed(*progress),
+			ProviderName:    manual.ProviderName,
+		}
+		if err := database.SaveProgress(database.DB, entry); err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+		fmt.Printf("Watched %.1f%% of %s\n", progress.Percentage, title)
+		return nil
+	},
+}
+
+// playManual plays url in mpv until playback ends or the user interrupts,
+// returning the last progress mpv reported
+func playManual(url string, options player.PlayOptions) (*player.PlaybackProgress, error) {
+	mpvPlayer, err := mpv.NewMPVPlayerWithConfig(cfg, cfg.Advanced.Debug)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mpvPlayer.OnPlaybackEnd(cancel)
+	mpvPlayer.OnError(func(err error) {
+		logger.Error("player error", "error", err)
+		cancel()
+	})
+
+	options.AudioNormalization = cfg.Player.AudioNormalization
+	options.ReplayGain = cfg.Player.ReplayGain
+	if err := mpvPlayer.Play(ctx, url, options); err != nil {
+		return nil, fmt.Errorf("failed to start playback: %w", err)
+	}
+	defer func() { _ = mpvPlayer.Stop(context.Background()) }()
+	go func() {
+		_ = mpvPlayer.Wait(ctx)
+		cancel()
+	}()
+
+	var last *player.PlaybackProgress
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case <-ticker.C:
+		}
+
+		progressCtx, progressCancel := context.WithTimeout(ctx, 2*time.Second)
+		progress, err := mpvPlayer.GetProgress(progressCtx)
+		progressCancel()
+		if err != nil {
+			continue // IPC not ready yet, or mpv is closing
+		}
+		last = progress
+		if progress.EOF {
+			return last, nil
+		}
+	}
+}
+
+func init() {
+	playCmd.Flags().String("url", "", "stream URL (HLS playlist or video file)")
+	playCmd.Flags().String("referer", "", "Referer header to send")
+	playCmd.Flags().StringArray("header", nil, `extra header as "Name: value" (repeatable)`)
+	playCmd.Flags().String("title", "", "title shown in mpv and the history (default: from the URL)")
+	playCmd.Flags().Bool("start-over", false, "ignore the saved position and play from the start")
+
+	rootCmd.AddCommand(playCmd)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/playlist"
+)
+
+// playlistCmd groups the OP/ED playlist commands
+var playlistCmd = &cobra.Command{
+	Use:   "playlist",
+	Short: "Manage the playlist of saved openings and endings",
+	Long: `Songs are saved to the playlist by pressing m while an episode plays.
+Export it to a file, or to a Spotify or YouTube (Music) playlist.`,
+}
+
+// playlistListCmd lists the saved songs
+var playlistListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List saved songs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tracks, err := database.ListPlaylistTracks(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to list playlist: %w", err)
+		}
+		if len(tracks) == 0 {
+			fmt.Println("The playlist is empty (press m while watching to save a song)")
+			return nil
+		}
+
+		for _, track := range tracks {
+			fmt.Printf("%4d  %-30s %-24s %s %s\n", track.ID, track.Song, track.Artist, track.MediaTitle, track.Theme)
+		}
+		return nil
+	},
+}
+
+// playlistRemoveCmd removes a song from the playlist
+var playlistRemoveCmd = &cobra.Command{
+	Use:     "remove <id>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a song from the playlist",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid playlist track ID %q (see 'greg playlist list')", args[0])
+		}
+		if err := database.RemovePlaylistTrack(database.DB, uint(id)); err != nil {
+			return fmt.Errorf("failed to remove playlist track: %w", err)
+		}
+		fmt.Printf("Removed track %d\n", id)
+		return nil
+	},
+}
+
+// playlistClearCmd empties the playlist
+var playlistClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every song from the playlist",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := database.ClearPlaylist(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to clear playlist: %w", err)
+		}
+		fmt.Printf("Removed %d track(s)\n", removed)
+		return nil
+	},
+}
+
+// playlistExportCmd exports the playlist to a file or music service
+var playlistExportCmd = &cobra.Command{
+	Use:   "export <csv|spotify|youtube>",
+	Short: "Export the playlist to a file, Spotify or YouTube Music",
+	Long: `Export the saved songs:
+
+  csv      a CSV file (or stdout)
+  spotify  a new private Spotify playlist (needs playlist.spotify_client_id)
+  youtube  a new private YouTube playlist, also in YouTube Music
+           (needs playlist.youtube_client_id and youtube_client_secret)
+
+Songs are matched by searching for their title and artist; the ones without
+a match are listed afterwards.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"csv", "spotify", "youtube"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		output, _ := cmd.Flags().GetString("output")
+
+		tracks, err := database.ListPlaylistTracks(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to list playlist: %w", err)
+		}
+		if len(tracks) == 0 {
+			return fmt.Errorf("the playlist is empty (press m while watching to save a song)")
+		}
+
+		if args[0] == "csv" {
+			var w io.Writer = os.Stdout
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", output, err)
+				}
+				defer f.Close()
+				w = f
+			}
+			return playlist.WriteCSV(w, tracks)
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Minute)
+		defer cancel()
+
+		settings := cfg.Playlist
+		var svc playlist.Service
+		switch args[0] {
+		case "spotify":
+			if settings.SpotifyClientID == "" {
+				return fmt.Errorf("set playlist.spotify_client_id to a Spotify app with the redirect URI %s", playlist.RedirectURI(settings.RedirectPort))
+			}
+			client, err := playlist.Authorize(ctx, playlist.SpotifyOAuth(settings.SpotifyClientID), settings.RedirectPort)
+			if err != nil {
+				return err
+			}
+			svc = playlist.NewSpotify(client)
+		case "youtube":
+			if settings.YouTubeClientID == "" || settings.YouTubeClientSecret == "" {
+				return fmt.Errorf("set playlist.youtube_client_id and playlist.youtube_client_secret to a Google desktop app OAuth client")
+			}
+			client, err := playlist.Authorize(ctx, playlist.YouTubeOAuth(settings.YouTubeClientID, settings.YouTubeClientSecret), settings.RedirectPort)
+			if err != nil {
+				return err
+			}
+			svc = playlist.NewYouTube(client)
+		default:
+			return fmt.Errorf("unknown export target %q (use csv, spotify or youtube)", args[0])
+		}
+
+		url, missing, err := playlist.Export(ctx, svc, name, tracks)
+		for _, track := range missing {
+			fmt.Printf("not found  %s - %s (%s %s)\n", track.Song, track.Artist, track.MediaTitle, track.Theme)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Exported %d of %d song(s) to %s\n", len(tracks)-len(missing), len(tracks), url)
+		return nil
+	},
+}
+
+func init() {
+	playlistExportCmd.Flags().String("name", "greg openings & endings", "name of the created playlist")
+	playlistExportCmd.Flags().StringP("output", "o", "", "file to write for csv (default: stdout)")
+	playlistCmd.AddCommand(playlistListCmd)
+	playlistCmd.AddCommand(playlistRemoveCmd)
+	playlistCmd.AddCommand(playlistClearCmd)
+	playlistCmd.AddCommand(playlistExportCmd)
+	rootCmd.AddCommand(playlistCmd)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// livenessTimeout bounds the stream URL check of a provider self-test
+const livenessTimeout = 15 * time.Second
+
+// providersTestCmd runs the self-test scenario against a provider
+var providersTestCmd = &cobra.Command{
+	Use:   "test <provider-name>",
+	Short: "Run a self-test scenario against a provider",
+	Long: `Search a well-known title, fetch its seasons and episodes, resolve a
+stream (or the pages of a manga chapter) and check that the stream URL
+answers. Each step is reported as pass, fail or skip, so site layout changes
+show up as the step that broke. Exits with an error when a step fails.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, _ := cmd.Flags().GetString("query")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		provider, err := providers.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("provider %s not found: %w", args[0], err)
+		}
+		// A failed step is a report, not a usage mistake
+		cmd.SilenceUsage = true
+
+		budget := cfg.Network.Timeouts.Budget(provider.Name(), config.TimeoutSearch, config.TimeoutDetails, config.TimeoutStream) + livenessTimeout
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		ctx, cancel := context.WithTimeout(ctx, budget)
+		defer cancel()
+
+		report := providers.SelfTest(ctx, provider, providers.SelfTestOptions{Query: query})
+
+		if asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return fmt.Errorf("failed to encode report: %w", err)
+			}
+		} else {
+			printSelfTestReport(report)
+		}
+
+		if !report.Passed() {
+			return fmt.Errorf("%s failed its self-test", provider.Name())
+		}
+		return nil
+	},
+}
+
+func printSelfTestReport(report *providers.SelfTestReport) {
+	fmt.Printf("Provider: %s\n", report.Provider)
+	fmt.Printf("Query: %s\n\n", report.Query)
+
+	marks := map[providers.SelfTestResult]string{
+		providers.SelfTestPass: "PASS",
+		providers.SelfTestFail: "FAIL",
+		providers.SelfTestSkip: "SKIP",
+	}
+	for _, step := range report.Steps {
+		line := fmt.Sprintf("%s  %-9s", marks[step.Result], step.Name)
+		if step.Result != providers.SelfTestSkip {
+			line += fmt.Sprintf(" %6s", step.Duration.Round(time.Millisecond))
+		}
+		switch {
+		case step.Kind != "":
+			line += fmt.Sprintf("  [%s] %s", step.Kind, step.Error)
+		case step.Error != "":
+			line += "  " + step.Error
+		case step.Detail != "":
+			line += "  " + step.Detail
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	if report.Passed() {
+		fmt.Println("\nResult: PASS")
+	} else {
+		fmt.Println("\nResult: FAIL")
+	}
+}
+
+func init() {
+	providersTestCmd.Flags().String("query", "", "title to search instead of the built-in one")
+	providersTestCmd.Flags().Bool("json", false, "print the report as JSON")
+
+	providersCmd.AddCommand(providersTestCmd)
+}
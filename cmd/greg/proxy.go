@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/watchparty"
+)
+
+// proxyCmd groups the built-in stream proxy commands
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Built-in m3u8 stream proxy",
+	Long: `Built-in m3u8 stream proxy.
+Streams often require Origin/Referer headers and are blocked by browser CORS
+rules, which breaks WatchParty. greg can run its own proxy instead of a
+separately deployed Cloudflare worker.`,
+}
+
+// proxyServeCmd runs the m3u8 proxy
+var proxyServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the m3u8 proxy",
+	Long: `Run a CORS-enabled m3u8 proxy with header injection.
+Playlists are rewritten so segments and keys are fetched through the proxy too.
+It only listens on loopback by default and only serves URLs carrying its token,
+so it can't be used as an open relay. Expose it through a tunnel and set
+watchparty.proxy_public_url (or --public-url) to the tunnel's URL; WatchParty
+URLs then use it automatically when no other proxy is configured.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		if listen == "" {
+			listen = cfg.WatchParty.ProxyListen
+		}
+		publicURL, _ := cmd.Flags().GetString("public-url")
+		if publicURL == "" {
+			publicURL = cfg.WatchParty.ProxyPublicURL
+		}
+
+		token, err := watchparty.LoadProxyToken(config.GetStateDir())
+		if err != nil {
+			return err
+		}
+
+		server := watchparty.NewProxyServer(publicURL, token)
+		if err := server.Listen(listen); err != nil {
+			return err
+		}
+		defer func() { _ = server.Close() }()
+
+		logger.Info("stream proxy started", "addr", server.Addr(), "public_url", publicURL)
+		fmt.Printf("Stream proxy listening on %s\n", server.Addr())
+		if publicURL != "" {
+			fmt.Printf("Public URL: %s\n", watchparty.ProxyBaseURL(publicURL, token))
+		} else {
+			fmt.Println("No public URL set, so WatchParty rooms won't use this proxy.")
+		}
+		fmt.Println("Press Ctrl+C to stop.")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		<-ctx.Done()
+
+		return nil
+	},
+}
+
+func init() {
+	proxyServeCmd.Flags().String("listen", "", "address to listen on (default: watchparty.proxy_listen)")
+	proxyServeCmd.Flags().String("public-url", "", "public base URL of the proxy (default: watchparty.proxy_public_url)")
+
+	proxyCmd.AddCommand(proxyServeCmd)
+	rootCmd.AddCommand(proxyCmd)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/anilist"
+)
+
+// syncCmd groups the tracker sync queue commands
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Inspect and retry queued tracker updates",
+	Long: `Progress updates that fail to reach AniList are queued and retried with
+backoff while greg runs, and again on the next startup.`,
+}
+
+// syncStatusCmd lists queued updates
+var syncStatusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"ls"},
+	Short:   "List queued tracker updates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := database.PendingSyncs(database.DB, true)
+		if err != nil {
+			return fmt.Errorf("failed to get sync queue: %w", err)
+		}
+		if len(items) == 0 {
+			fmt.Println("No pending syncs")
+			return nil
+		}
+
+		for _, item := range items {
+			next := "now"
+			if item.NextAttemptAt != nil && item.NextAttemptAt.After(time.Now()) {
+				next = item.NextAttemptAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%-20s ep %-4d attempts %-3d next %s  %s\n", item.MediaID, item.Episode, item.Attempts, next, item.LastError)
+		}
+		return nil
+	},
+}
+
+// syncRetryCmd retries all queued updates right away
+var syncRetryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Retry all queued tracker updates now",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cfg.Tracker.AniList.Enabled {
+			return fmt.Errorf("anilist tracking is disabled")
+		}
+
+		tokenStorage := anilist.NewTokenStorage(database.DB)
+		client := anilist.NewClient(anilist.Config{
+			ClientID:    anilist.AuthBrowserClientID,
+			RedirectURI: anilist.AuthBrowserRedirectURI,
+			SaveToken:   tokenStorage.SaveToken,
+			LoadToken:   tokenStorage.LoadToken,
+		})
+
+		mgr := tracker.NewManager(cfg, database.DB)
+		mgr.SetAniListClient(client)
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+		defer cancel()
+
+		synced, failed, err := mgr.ProcessSyncQueue(ctx, true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Synced %d update(s), %d failed\n", synced, failed)
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncStatusCmd)
+	syncCmd.AddCommand(syncRetryCmd)
+	rootCmd.AddCommand(syncCmd)
+}
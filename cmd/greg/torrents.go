@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/feeds"
+	"github.com/justchokingaround/greg/internal/incoming"
+	"github.com/justchokingaround/greg/internal/torrentclient"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+// torrentsCmd groups the commands for torrents handed to a torrent client
+var torrentsCmd = &cobra.Command{
+	Use:   "torrents",
+	Short: "Torrents handed to qBittorrent or Transmission",
+	Long: `Hand torrents to the qBittorrent or Transmission instance configured in
+downloads.torrent and import their files into the library once they finish.
+Torrent releases from feeds go there too; greg imports finished torrents on its
+own while running and during 'greg feeds watch'.`,
+}
+
+// torrentsAddCmd hands a torrent to the client
+var torrentsAddCmd = &cobra.Command{
+	Use:   "add <magnet | .torrent url>",
+	Short: "Hand a torrent to the torrent client",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		link := args[0]
+		if !torrentclient.IsTorrent(link) {
+			return fmt.Errorf("not a magnet link or .torrent URL: %s", link)
+		}
+		client, err := newTorrentClient()
+		if err != nil {
+			return err
+		}
+
+		name := torrentclient.MagnetName(link)
+		show, episode := feeds.ParseReleaseTitle(name)
+		if s, _ := cmd.Flags().GetString("show"); s != "" {
+			show = s
+		}
+
+		ingester := feeds.NewIngester(database.DB, nil, "")
+		ingester.SetTorrentClient(client)
+		release := feeds.Release{Item: feeds.Item{Title: name, Link: link}, Show: show, Episode: episode}
+		if err := ingester.Queue(cmd.Context(), release); err != nil {
+			return err
+		}
+		fmt.Printf("Added to %s; greg imports it once finished\n", client.Name())
+		return nil
+	},
+}
+
+// torrentsListCmd lists tracked torrents
+var torrentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List torrents handed to the torrent client",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobs, err := database.ListTorrentJobs(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to list torrents: %w", err)
+		}
+		if len(jobs) == 0 {
+			fmt.Println("No torrents handed to a torrent client")
+			return nil
+		}
+
+		// Progress of the active ones, when the client answers
+		progress := make(map[string]float64)
+		if client, err := newTorrentClient(); err == nil {
+			var hashes []string
+			for _, job := range jobs {
+				if job.Status == database.TorrentActive {
+					hashes = append(hashes, job.Hash)
+				}
+			}
+			if torrents, err := client.Torrents(cmd.Context(), hashes); err == nil {
+				for _, t := range torrents {
+					progress[t.Hash] = t.Progress
+				}
+			}
+		}
+
+		for _, job := range jobs {
+			name := job.Name
+			if name == "" {
+				name = job.Hash
+			}
+			status := job.Status
+			if p, ok := progress[job.Hash]; ok {
+				status = fmt.Sprintf("%s %.0f%%", status, p*100)
+			}
+			fmt.Printf("%-14s %s\n", status, name)
+			if job.Error != "" {
+				fmt.Printf("               %s\n", job.Error)
+			}
+		}
+		return nil
+	},
+}
+
+// torrentsCheckCmd imports finished torrents
+var torrentsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Import the torrents that finished",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTorrentClient()
+		if err != nil {
+			return err
+		}
+		downloadMgr, err := downloader.NewManager(database.DB, &cfg.Downloads, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize download manager: %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		watcher := incoming.New(cfg.Downloads.Incoming.Path, downloadMgr, mapping.NewManager(database.DB, "", logger), logger)
+		results, err := watcher.CheckTorrents(ctx, database.DB, client)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("No finished torrents to import")
+		}
+		printIncomingResults(results)
+		return nil
+	},
+}
+
+// newTorrentClient creates the client configured in downloads.torrent
+func newTorrentClient() (torrentclient.Client, error) {
+	if incognito {
+		return nil, fmt.Errorf("torrent clients are disabled in incognito mode")
+	}
+	if strings.TrimSpace(cfg.Downloads.Torrent.Client) == "" {
+		return nil, fmt.Errorf("no torrent client configured (set downloads.torrent.client)")
+	}
+	return torrentclient.New(cfg.Downloads.Torrent)
+}
+
+func init() {
+	torrentsAddCmd.Flags().String("show", "", "show the torrent is an episode of (default: from the magnet's name)")
+
+	torrentsCmd.AddCommand(torrentsAddCmd)
+	torrentsCmd.AddCommand(torrentsListCmd)
+	torrentsCmd.AddCommand(torrentsCheckCmd)
+	rootCmd.AddCommand(torrentsCmd)
+}
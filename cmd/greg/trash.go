@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// trashCmd groups the trash commands
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Restore deleted downloads, history and mappings",
+	Long: `Deleted downloads, history entries and AniList mappings are kept in the trash
+for database.trash_retention before being purged for good.`,
+}
+
+// trashListCmd lists trash items
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List deleted items",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := database.ListTrash(database.DB)
+		if err != nil {
+			return fmt.Errorf("failed to list trash: %w", err)
+		}
+		if len(items) == 0 {
+			fmt.Println("Trash is empty")
+			return nil
+		}
+
+		for _, item := range items {
+			fmt.Printf("#%-4d %-9s %s  %s\n", item.ID, item.Kind, item.DeletedAt.Format("2006-01-02 15:04"), item.Label)
+		}
+		return nil
+	},
+}
+
+// trashRestoreCmd restores a trash item
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore a deleted item",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid trash id: %s", args[0])
+		}
+
+		item, err := database.RestoreTrash(database.DB, uint(id))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s\n", item.Label)
+		return nil
+	},
+}
+
+// trashPurgeCmd permanently deletes trash items
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete trashed items",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+
+		n, err := database.PurgeTrash(database.DB, olderThan)
+		if err != nil {
+			return fmt.Errorf("failed to purge trash: %w", err)
+		}
+		fmt.Printf("Purged %d item(s)\n", n)
+		return nil
+	},
+}
+
+func init() {
+	trashPurgeCmd.Flags().Duration("older-than", 0, "only purge items deleted longer ago than this (default: all)")
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+	rootCmd.AddCommand(trashCmd)
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/player/mpv"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/resolve"
+	"github.com/justchokingaround/greg/internal/watchparty"
+)
+
+// watchPartyTarget is the media and episode a watchparty command resolved from a query
+type watchPartyTarget struct {
+	provider      providers.Provider
+	media         providers.Media
+	seasonNumber  int
+	episodes      []providers.Episode
+	episodeID     string
+	episodeNumber int
+}
+
+// watchPartyProvider returns the provider picked by the --provider and --type
+// flags of cmd
+func watchPartyProvider(cmd *cobra.Command) (providers.Provider, error) {
+	providerName, _ := cmd.Flags().GetString("provider")
+	mediaType, _ := cmd.Flags().GetString("type")
+
+	if providerName != "" {
+		provider, err := providers.Get(providerName)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s not found: %w", providerName, err)
+		}
+		return provider, nil
+	}
+
+	// Determine which provider to use based on type
+	switch mediaType {
+	case "movie", "movies", "tv", "shows":
+		provider, err := providers.Get(cfg.Providers.Default.MoviesAndTV)
+		if err != nil {
+			allProviders := providers.GetByType(providers.MediaTypeMovieTV)
+			if len(allProviders) == 0 {
+				return nil, fmt.Errorf("no movie/TV providers available")
+			}
+			provider = allProviders[0]
+		}
+		return provider, nil
+	default:
+		// Default to anime if not specified
+		provider, err := providers.Get(cfg.Providers.Default.Anime)
+		if err != nil {
+			allProviders := providers.GetByType(providers.MediaTypeAnime)
+			if len(allProviders) == 0 {
+				return nil, fmt.Errorf("no anime providers available")
+			}
+			provider = allProviders[0]
+		}
+		return provider, nil
+	}
+}
+
+// watchPartyBudget bounds resolving a watchparty target and its stream
+func watchPartyBudget(provider providers.Provider) time.Duration {
+	return cfg.Network.Timeouts.Budget(provider.Name(), config.TimeoutSearch, config.TimeoutDetails, config.TimeoutStream)
+}
+
+// resolveWatchPartyTarget searches provider for query and picks the episode
+// requested via the --episode flag of cmd
+func resolveWatchPartyTarget(ctx context.Context, cmd *cobra.Command, provider providers.Provider, query string) (*watchPartyTarget, error) {
+	episodeNum, _ := cmd.Flags().GetInt("episode")
+
+	logger.Info("searching for media", "query", query, "provider", provider.Name())
+
+	res, err := resolve.Episode(ctx, provider, resolve.MediaRef{Title: query}, resolve.EpisodeSelector{Episode: episodeNum})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("found media", "title", res.Media.Title, "id", res.Media.ID)
+
+	target := &watchPartyTarget{
+		provider:      provider,
+		media:         res.Media,
+		seasonNumber:  res.Episode.Season,
+		episodes:      res.Episodes,
+		episodeID:     res.Episode.ID,
+		episodeNumber: max(res.Episode.Number, 1),
+	}
+	return target, nil
+}
+
+// watchpartyHostCmd hosts a native sync session
+var watchpartyHostCmd = &cobra.Command{
+	Use:   "host <query>",
+	Short: "Host a native watch-together session",
+	Long: `Host a native watch-together session.
+greg plays the media in mpv and runs a small WebSocket hub. Friends join with
+'greg watchparty join <address> <room>' and play/pause/seek is mirrored across
+everyone's mpv.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		if listen == "" {
+			listen = cfg.WatchParty.SyncListen
+		}
+
+		provider, err := watchPartyProvider(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), watchPartyBudget(provider))
+		target, err := resolveWatchPartyTarget(ctx, cmd, provider, args[0])
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		stream, err := target.provider.GetStreamURL(ctx, target.episodeID, providers.Quality1080p)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to get stream URL: %w", err)
+		}
+
+		host, err := watchparty.NewSyncHost(listen)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = host.Close() }()
+
+		host.SetMedia(watchparty.SyncMedia{
+			Provider:  target.provider.Name(),
+			MediaID:   target.media.ID,
+			EpisodeID: target.episodeID,
+			Title:     target.media.Title,
+			Episode:   target.episodeNumber,
+			StreamURL: stream.URL,
+			Referer:   stream.Referer,
+			Headers:   stream.Headers,
+		})
+
+		fmt.Printf("Watch-together session started\n")
+		fmt.Printf("Media: %s (Episode %d)\n", target.media.Title, target.episodeNumber)
+		fmt.Printf("Room: %s\n", host.Room())
+		fmt.Printf("Join with: greg watchparty join <your-address>%s %s\n", portSuffix(host.Addr()), host.Room())
+
+		peer, err := watchparty.JoinSync(context.Background(), host.Addr(), host.Room(), "host")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = peer.Close() }()
+
+		return playSynced(peer, target.media.Title, target.episodeNumber, stream)
+	},
+}
+
+// watchpartyJoinCmd joins a native sync session
+var watchpartyJoinCmd = &cobra.Command{
+	Use:   "join <address> <room>",
+	Short: "Join a native watch-together session",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		peer, err := watchparty.JoinSync(ctx, args[0], args[1], name)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = peer.Close() }()
+
+		media, err := peer.WaitForMedia(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Joined room %s\n", args[1])
+		fmt.Printf("Media: %s (Episode %d)\n", media.Title, media.Episode)
+
+		// Prefer resolving the stream through our own provider; stream URLs
+		// are often bound to the host's IP or expire quickly
+		stream := &providers.StreamURL{URL: media.StreamURL, Referer: media.Referer, Headers: media.Headers}
+		if provider, err := providers.Get(media.Provider); err == nil {
+			if own, err := provider.GetStreamURL(ctx, media.EpisodeID, providers.Quality1080p); err == nil {
+				stream = own
+			} else {
+				logger.Warn("failed to resolve stream locally, using host stream", "error", err)
+			}
+		}
+
+		if stream.URL == "" {
+			return fmt.Errorf("no stream available for %s", media.Title)
+		}
+
+		return playSynced(peer, media.Title, media.Episode, stream)
+	},
+}
+
+// watchpartyNextCmd shares the next episode of the last WatchParty session
+var watchpartyNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Create a WatchParty URL for the next episode of the last session",
+	Long: `Create a WatchParty URL for the episode after the one last shared.
+The media, season and proxy settings are taken from the last WatchParty
+session, so no search is needed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		openBrowser, _ := cmd.Flags().GetBool("open")
+
+		session, err := watchparty.LoadSession(database.DB)
+		if err != nil {
+			if errors.Is(err, watchparty.ErrNoSession) {
+				return fmt.Errorf("%w (run 'greg watchparty <query>' first)", err)
+			}
+			return err
+		}
+
+		provider, err := providers.Get(session.Provider)
+		if err != nil {
+			return fmt.Errorf("provider %s not found: %w", session.Provider, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Network.Timeouts.Budget(provider.Name(), config.TimeoutDetails, config.TimeoutStream))
+		defer cancel()
+
+		wpManager := watchparty.NewManager(watchparty.Config{
+			Enabled:         cfg.WatchParty.Enabled,
+			DefaultProxy:    cfg.WatchParty.DefaultProxy,
+			AutoOpenBrowser: cfg.WatchParty.AutoOpenBrowser,
+			DefaultOrigin:   cfg.WatchParty.DefaultOrigin,
+			SubtitleLang:    cfg.Player.SubtitleLang,
+			StateDir:        config.GetStateDir(),
+		})
+
+		watchPartyURL, err := wpManager.Next(ctx, provider, session)
+		if err != nil {
+			return fmt.Errorf("failed to create WatchParty for next episode: %w", err)
+		}
+
+		if err := watchparty.SaveSession(database.DB, session); err != nil {
+			logger.Warn("failed to save watchparty session", "error", err)
+		}
+
+		fmt.Printf("WatchParty room created!\n")
+		fmt.Printf("Media: %s\n", session.MediaTitle)
+		fmt.Printf("Episode: %d\n", session.EpisodeNumber)
+		fmt.Printf("WatchParty URL: %s\n", watchPartyURL)
+
+		if openBrowser || cfg.WatchParty.AutoOpenBrowser {
+			if err := watchparty.OpenURL(watchPartyURL); err != nil {
+				logger.Error("failed to open browser", "error", err)
+				fmt.Printf("Failed to open browser: %v\n", err)
+				fmt.Printf("Please open the URL manually in your browser.\n")
+			} else {
+				fmt.Printf("Opening WatchParty room in your browser...\n")
+			}
+		}
+
+		return nil
+	},
+}
+
+// playSynced plays stream in mpv and mirrors the session until playback ends or the user interrupts
+func playSynced(peer *watchparty.SyncPeer, title string, episode int, stream *providers.StreamURL) error {
+	mpvPlayer, err := mpv.NewMPVPlayerWithConfig(cfg, cfg.Advanced.Debug)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mpvPlayer.OnPlaybackEnd(cancel)
+	mpvPlayer.OnError(func(err error) {
+		logger.Error("player error", "error", err)
+		cancel()
+	})
+
+	options := player.PlayOptions{
+		Title:   title,
+		Episode: episode,
+		Headers: stream.Headers,
+		Referer: stream.Referer,
+		YTDL:    stream.Type == providers.StreamTypeYTDLP,
+
+		AudioNormalization: cfg.Player.AudioNormalization,
+		ReplayGain:         cfg.Player.ReplayGain,
+	}
+	if len(stream.Subtitles) > 0 {
+		options.SubtitleURL = stream.Subtitles[0].URL
+	}
+
+	if err := mpvPlayer.Play(ctx, stream.URL, options); err != nil {
+		return fmt.Errorf("failed to start playback: %w", err)
+	}
+	defer func() { _ = mpvPlayer.Stop(context.Background()) }()
+	go func() {
+		// Closing mpv ends the session
+		_ = mpvPlayer.Wait(ctx)
+		cancel()
+	}()
+
+	// Wait for mpv IPC before mirroring commands
+	for !mpvPlayer.IsPlaying() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	fmt.Println("Synced playback running. Press Ctrl+C to leave.")
+	if err := peer.Mirror(ctx, mpvPlayer); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// portSuffix returns ":port" for a listener address
+func portSuffix(addr string) string {
+	for i := len(addr) - 1; i >= 0; i-- {
+		if addr[i] == ':' {
+			return addr[i:]
+		}
+	}
+	return ""
+}
+
+func init() {
+	watchpartyHostCmd.Flags().StringP("provider", "p", "", "provider to use (default: auto-detect by type)")
+	watchpartyHostCmd.Flags().StringP("type", "t", "anime", "media type: anime, movie, movies, tv, shows (default: anime)")
+	watchpartyHostCmd.Flags().IntP("episode", "e", 0, "specific episode number (TV/anime only)")
+	watchpartyHostCmd.Flags().String("listen", "", "address to listen on (default: watchparty.sync_listen)")
+	watchpartyJoinCmd.Flags().String("name", "guest", "name shown to other participants")
+	watchpartyNextCmd.Flags().BoolP("open", "o", false, "Open browser to WatchParty room")
+
+	watchpartyCmd.AddCommand(watchpartyHostCmd)
+	watchpartyCmd.AddCommand(watchpartyJoinCmd)
+	watchpartyCmd.AddCommand(watchpartyNextCmd)
+}
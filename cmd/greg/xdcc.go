@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/downloader/xdcc"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/releases"
+)
+
+// xdccCmd groups the IRC/XDCC download commands
+var xdccCmd = &cobra.Command{
+	Use:   "xdcc",
+	Short: "Download releases from IRC XDCC bots",
+	Long: `Download releases from IRC XDCC bots (e.g. on Rizon).
+Packs are addressed as xdcc://server[:port]/[channel/]bot/pack and go through
+the regular download queue; interrupted transfers resume where they stopped.`,
+}
+
+// xdccSearchCmd searches the configured packlists
+var xdccSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search XDCC packlists",
+	Long: `Search iroffer packlists for packs whose filename contains every word of
+the query. Packlists come from downloads.xdcc.packlists or --packlist.
+Packs from downloads.releases.banned_groups are hidden and preferred groups,
+resolutions and codecs are listed first.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		packlists, _ := cmd.Flags().GetStringSlice("packlist")
+		if len(packlists) == 0 {
+			packlists = cfg.Downloads.XDCC.Packlists
+		}
+		if len(packlists) == 0 {
+			return fmt.Errorf("no packlists configured (set downloads.xdcc.packlists or pass --packlist)")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		query := strings.Join(args, " ")
+		prefs := releases.FromConfig(&cfg.Downloads.Releases)
+		found := 0
+		for _, url := range packlists {
+			entries, err := xdcc.FetchPacklist(ctx, url)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", url, err)
+				continue
+			}
+
+			matches := releases.Select(prefs, xdcc.Search(entries, query), func(e xdcc.Entry) string { return e.Filename })
+			for _, entry := range matches {
+				if entry.Bot == "" {
+					continue
+				}
+				pack := xdccPack(entry.Bot, entry.Number)
+				fmt.Printf("%-6s %-8s %s\n", fmt.Sprintf("[%s]", entry.Size), entry.Bot, entry.Filename)
+				fmt.Printf("       %s\n", pack.URL())
+				found++
+			}
+		}
+
+		if found == 0 {
+			fmt.Printf("No packs found for %q\n", query)
+		}
+		return nil
+	},
+}
+
+// xdccGetCmd queues an XDCC pack and waits for it
+var xdccGetCmd = &cobra.Command{
+	Use:   "get <xdcc-url | bot pack>",
+	Short: "Download an XDCC pack",
+	Long: `Download an XDCC pack through the download queue.
+Pass a pack URL printed by 'greg xdcc search', or a bot name and pack number to
+use downloads.xdcc.server and downloads.xdcc.channel. Running the same command
+again resumes an interrupted transfer.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var pack xdcc.Pack
+		if len(args) == 2 {
+			number, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+			if err != nil || number <= 0 {
+				return fmt.Errorf("invalid pack number: %s", args[1])
+			}
+			pack = xdccPack(args[0], number)
+		} else {
+			var err error
+			if pack, err = xdcc.ParseURL(args[0]); err != nil {
+				return err
+			}
+		}
+
+		title, _ := cmd.Flags().GetString("title")
+		if title == "" {
+			title = pack.Bot
+		}
+		episode, _ := cmd.Flags().GetInt("episode")
+		outputDir, _ := cmd.Flags().GetString("output")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		downloadMgr, err := downloader.NewManager(database.DB, &cfg.Downloads, logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize download manager: %w", err)
+		}
+		if outputDir != "" {
+			downloadMgr.SetOutputDir(outputDir)
+		}
+		if err := downloadMgr.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start download manager: %w", err)
+		}
+		defer func() { _ = downloadMgr.Stop() }()
+
+		task := downloader.DownloadTask{
+			MediaID:    pack.URL(), // Lets the queue resume the pack after a restart
+			MediaTitle: title,
+			MediaType:  providers.MediaTypeAnime,
+			Episode:    episode,
+			Provider:   "xdcc",
+			StreamURL:  pack.URL(),
+			StreamType: providers.StreamTypeXDCC,
+		}
+
+		// Earlier attempts keep their partial file, so requeue them instead of
+		// starting a new download
+		var previous database.Download
+		err = database.DB.Where("media_id = ? AND episode = ? AND season = ?", task.MediaID, episode, 0).First(&previous).Error
+		switch {
+		case err != nil:
+			if err := downloadMgr.AddToQueue(ctx, task); err != nil {
+				return fmt.Errorf("failed to add download to queue: %w", err)
+			}
+		case previous.Status == string(downloader.StatusCompleted):
+			fmt.Printf("Already downloaded: %s\n", previous.FilePath)
+			return nil
+		case previous.Status == string(downloader.StatusFailed), previous.Status == string(downloader.StatusCancelled):
+			if err := downloadMgr.Retry(ctx, previous.ID); err != nil {
+				return fmt.Errorf("failed to resume download: %w", err)
+			}
+		case previous.Status == string(downloader.StatusPaused):
+			if err := downloadMgr.Resume(ctx, previous.ID); err != nil {
+				return fmt.Errorf("failed to resume download: %w", err)
+			}
+		case !cfg.Downloads.AutoResume:
+			return fmt.Errorf("pack is already queued (status: %s); enable downloads.auto_resume to resume it", previous.Status)
+		}
+
+		fmt.Printf("Requesting pack #%d from %s on %s...\n", pack.Number, pack.Bot, pack.Server)
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				fmt.Println("\nInterrupted; run the same command again to resume.")
+				return nil
+			case <-ticker.C:
+			}
+
+			queue, err := downloadMgr.GetQueue(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get download queue: %w", err)
+			}
+
+			for _, queued := range queue {
+				if queued.MediaID != task.MediaID || queued.Episode != episode {
+					continue
+				}
+				if queued.Status.IsComplete() {
+					if queued.Status == downloader.StatusCompleted {
+						fmt.Printf("\nDownload completed: %s\n", queued.OutputPath)
+						return nil
+					}
+					return fmt.Errorf("download failed: %s", queued.Error)
+				}
+				fmt.Printf("\rProgress: %.1f%% (%d KB/s)   ", queued.Progress, queued.Speed/1024)
+			}
+		}
+	},
+}
+
+// xdccPack builds a pack on the configured server and channel
+func xdccPack(bot string, number int) xdcc.Pack {
+	server := cfg.Downloads.XDCC.Server
+	if server == "" {
+		server = xdcc.DefaultServer
+	}
+	return xdcc.Pack{
+		Server:  server,
+		Channel: strings.TrimPrefix(cfg.Downloads.XDCC.Channel, "#"),
+		Bot:     bot,
+		Number:  number,
+	}
+}
+
+func init() {
+	xdccSearchCmd.Flags().StringSlice("packlist", nil, "packlist URL to search (default: downloads.xdcc.packlists)")
+
+	xdccGetCmd.Flags().String("title", "", "title used for the download folder (default: bot name)")
+	xdccGetCmd.Flags().Int("episode", 0, "episode number used in the filename")
+	xdccGetCmd.Flags().StringP("output", "o", "", "output directory (default: downloads.path)")
+
+	xdccCmd.AddCommand(xdccSearchCmd)
+	xdccCmd.AddCommand(xdccGetCmd)
+	rootCmd.AddCommand(xdccCmd)
+}
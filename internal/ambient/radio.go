@@ -0,0 +1,172 @@
+package ambient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+// audioArgs keep the radio's mpv audio-only and out of the way
+var audioArgs = []string{"--no-video", "--force-window=no", "--audio-display=no", "--really-quiet"}
+
+// ErrNoStations is returned when there is nothing to play
+var ErrNoStations = errors.New("no ambient stations configured")
+
+// State is what the radio is doing
+type State int
+
+const (
+	Stopped State = iota
+	Playing
+	Paused
+)
+
+// Radio plays audio-only stations in the background on its own player
+type Radio struct {
+	mu       sync.Mutex
+	player   player.Player
+	stations []config.AmbientStation
+	volume   int
+	current  int
+	state    State
+	run      int // Bumped on every Play, so a stale exit is ignored
+}
+
+// New creates a radio playing stations on p. Stations without a URL are
+// left out.
+func New(p player.Player, stations []config.AmbientStation, volume int) *Radio {
+	r := &Radio{player: p, volume: volume}
+	for _, station := range stations {
+		if station.URL == "" {
+			continue
+		}
+		if station.Name == "" {
+			station.Name = station.URL
+		}
+		r.stations = append(r.stations, station)
+	}
+	return r
+}
+
+// Station returns the current station
+func (r *Radio) Station() (config.AmbientStation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.stations) == 0 {
+		return config.AmbientStation{}, false
+	}
+	return r.stations[r.current], true
+}
+
+// State reports whether the radio is playing, paused or stopped. A stream
+// that ended or failed counts as stopped.
+func (r *Radio) State() State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// Toggle starts the current station when stopped, else pauses or resumes it
+func (r *Radio) Toggle(ctx context.Context) error {
+	switch r.State() {
+	case Playing:
+		return r.setPaused(ctx, true)
+	case Paused:
+		return r.setPaused(ctx, false)
+	default:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.playLocked(ctx)
+	}
+}
+
+// Next switches to the next station and plays it
+func (r *Radio) Next(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.stations) == 0 {
+		return ErrNoStations
+	}
+	r.current = (r.current + 1) % len(r.stations)
+	return r.playLocked(ctx)
+}
+
+// Stop stops the radio
+func (r *Radio) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == Stopped {
+		return nil
+	}
+	r.state = Stopped
+	if err := r.player.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop ambient radio: %w", err)
+	}
+	return nil
+}
+
+// Hold pauses the radio if it is playing, reporting whether it was, so it
+// can be resumed with Release once a video is over
+func (r *Radio) Hold(ctx context.Context) bool {
+	if r.State() != Playing {
+		return false
+	}
+	return r.setPaused(ctx, true) == nil
+}
+
+// Release resumes the radio after Hold, unless it was stopped meanwhile
+func (r *Radio) Release(ctx context.Context) error {
+	if r.State() != Paused {
+		return nil
+	}
+	return r.setPaused(ctx, false)
+}
+
+// playLocked starts the current station; the caller holds r.mu
+func (r *Radio) playLocked(ctx context.Context) error {
+	if len(r.stations) == 0 {
+		return ErrNoStations
+	}
+	station := r.stations[r.current]
+	err := r.player.Play(ctx, station.URL, player.PlayOptions{
+		Volume:  r.volume,
+		Title:   station.Name,
+		MPVArgs: audioArgs,
+	})
+	if err != nil {
+		r.state = Stopped
+		return fmt.Errorf("failed to play %s: %w", station.Name, err)
+	}
+	r.state = Playing
+	r.run++
+	go r.watch(r.run)
+	return nil
+}
+
+// watch marks the radio stopped once the mpv of run exits
+func (r *Radio) watch(run int) {
+	_ = r.player.Wait(context.Background())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.run == run {
+		r.state = Stopped
+	}
+}
+
+// setPaused pauses or resumes the player
+func (r *Radio) setPaused(ctx context.Context, paused bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.player.SetPaused(ctx, paused); err != nil {
+		return fmt.Errorf("failed to pause ambient radio: %w", err)
+	}
+	r.state = Playing
+	if paused {
+		r.state = Paused
+	}
+	return nil
+}
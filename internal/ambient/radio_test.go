@@ -0,0 +1,164 @@
+package ambient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+// fakePlayer records what the radio asks of it; exit ends the playback
+type fakePlayer struct {
+	mu      sync.Mutex
+	played  []string
+	opts    player.PlayOptions
+	paused  bool
+	stopped int
+	playErr error
+	exit    chan struct{}
+}
+
+func (f *fakePlayer) Play(ctx context.Context, url string, opts player.PlayOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.playErr != nil {
+		return f.playErr
+	}
+	f.played = append(f.played, url)
+	f.opts = opts
+	f.exit = make(chan struct{})
+	return nil
+}
+
+func (f *fakePlayer) Stop(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped++
+	return nil
+}
+
+func (f *fakePlayer) Wait(ctx context.Context) error {
+	f.mu.Lock()
+	exit := f.exit
+	f.mu.Unlock()
+	<-exit
+	return nil
+}
+
+func (f *fakePlayer) SetPaused(ctx context.Context, paused bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paused = paused
+	return nil
+}
+
+func (f *fakePlayer) GetProgress(ctx context.Context) (*player.PlaybackProgress, error) {
+	return nil, nil
+}
+func (f *fakePlayer) Seek(ctx context.Context, position time.Duration) error  { return nil }
+func (f *fakePlayer) OnProgressUpdate(callback func(player.PlaybackProgress)) {}
+func (f *fakePlayer) OnPlaybackEnd(callback func())                           {}
+func (f *fakePlayer) OnError(callback func(error))                            {}
+func (f *fakePlayer) IsPlaying() bool                                         { return true }
+func (f *fakePlayer) IsPaused() bool                                          { return false }
+
+var testStations = []config.AmbientStation{
+	{Name: "Jpop", URL: "https://example.com/jpop"},
+	{Name: "Broken"},
+	{URL: "https://example.com/lofi"},
+}
+
+func TestRadioToggle(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakePlayer{}
+	r := New(fake, testStations, 40)
+
+	require.NoError(t, r.Toggle(ctx))
+	assert.Equal(t, Playing, r.State())
+	assert.Equal(t, []string{"https://example.com/jpop"}, fake.played)
+	assert.Equal(t, 40, fake.opts.Volume)
+	assert.Equal(t, "Jpop", fake.opts.Title)
+	assert.Contains(t, fake.opts.MPVArgs, "--no-video")
+
+	require.NoError(t, r.Toggle(ctx))
+	assert.Equal(t, Paused, r.State())
+	assert.True(t, fake.paused)
+
+	require.NoError(t, r.Toggle(ctx))
+	assert.Equal(t, Playing, r.State())
+	assert.False(t, fake.paused)
+}
+
+func TestRadioNextSkipsStationsWithoutURL(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakePlayer{}
+	r := New(fake, testStations, 0)
+
+	require.NoError(t, r.Next(ctx))
+	station, ok := r.Station()
+	require.True(t, ok)
+	assert.Equal(t, "https://example.com/lofi", station.URL)
+	assert.Equal(t, "https://example.com/lofi", station.Name, "unnamed stations are named after their URL")
+
+	require.NoError(t, r.Next(ctx))
+	station, _ = r.Station()
+	assert.Equal(t, "Jpop", station.Name)
+}
+
+func TestRadioNoStations(t *testing.T) {
+	r := New(&fakePlayer{}, nil, 0)
+	assert.ErrorIs(t, r.Toggle(context.Background()), ErrNoStations)
+	_, ok := r.Station()
+	assert.False(t, ok)
+}
+
+func TestRadioPlayError(t *testing.T) {
+	r := New(&fakePlayer{playErr: errors.New("mpv not found")}, testStations, 0)
+	assert.Error(t, r.Toggle(context.Background()))
+	assert.Equal(t, Stopped, r.State())
+}
+
+func TestRadioHoldAndRelease(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakePlayer{}
+	r := New(fake, testStations, 0)
+
+	assert.False(t, r.Hold(ctx), "a stopped radio isn't held")
+
+	require.NoError(t, r.Toggle(ctx))
+	assert.True(t, r.Hold(ctx))
+	assert.Equal(t, Paused, r.State())
+
+	require.NoError(t, r.Release(ctx))
+	assert.Equal(t, Playing, r.State())
+}
+
+func TestRadioStreamEnd(t *testing.T) {
+	fake := &fakePlayer{}
+	r := New(fake, testStations, 0)
+	require.NoError(t, r.Toggle(context.Background()))
+
+	close(fake.exit)
+	assert.Eventually(t, func() bool { return r.State() == Stopped }, time.Second, 10*time.Millisecond)
+}
+
+func TestRadioStop(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakePlayer{}
+	r := New(fake, testStations, 0)
+
+	require.NoError(t, r.Stop(ctx))
+	assert.Zero(t, fake.stopped, "stopping a stopped radio leaves the player alone")
+
+	require.NoError(t, r.Toggle(ctx))
+	require.NoError(t, r.Stop(ctx))
+	assert.Equal(t, Stopped, r.State())
+	assert.Equal(t, 1, fake.stopped)
+}
@@ -32,7 +32,50 @@ func DetectAudioType(label string) string {
 	return "unknown"
 }
 
+// languageAliases maps language names and ISO 639-2 codes to ISO 639-1 codes
+var languageAliases = map[string]string{
+	"ja": "ja", "jp": "ja", "jpn": "ja", "japanese": "ja",
+	"en": "en", "eng": "en", "english": "en",
+	"ru": "ru", "rus": "ru", "russian": "ru",
+	"es": "es", "spa": "es", "spanish": "es",
+	"pt": "pt", "por": "pt", "portuguese": "pt",
+	"fr": "fr", "fre": "fr", "fra": "fr", "french": "fr",
+	"de": "de", "ger": "de", "deu": "de", "german": "de",
+	"it": "it", "ita": "it", "italian": "it",
+	"ko": "ko", "kor": "ko", "korean": "ko",
+	"zh": "zh", "chi": "zh", "zho": "zh", "chinese": "zh",
+}
+
+// NormalizeLanguage maps a language name or code ("Japanese", "jpn", "ja")
+// to its ISO 639-1 code. Returns an empty string for unknown languages.
+func NormalizeLanguage(lang string) string {
+	return languageAliases[strings.ToLower(strings.TrimSpace(lang))]
+}
+
+// IsLanguagePreference reports whether preference names a language rather
+// than "dub" or "sub"
+func IsLanguagePreference(preference string) bool {
+	return preference != "dub" && preference != "sub" && NormalizeLanguage(preference) != ""
+}
+
+// TrackPreference returns the preference to remember for a selected track:
+// its dub/sub type, or its language when the type is unknown
+func TrackPreference(track providers.AudioTrack) string {
+	trackType := track.Type
+	if trackType == "" || trackType == "unknown" || trackType == "original" {
+		if lang := NormalizeLanguage(track.Language); lang != "" {
+			return lang
+		}
+		trackType = DetectAudioType(track.Label)
+	}
+	if trackType == "dub" || trackType == "sub" {
+		return trackType
+	}
+	return ""
+}
+
 // SelectAudioTrack finds best matching audio track for user preference
+// ("dub", "sub" or a language code)
 // Returns nil if no match found (triggers user prompt in TUI)
 func SelectAudioTrack(tracks []providers.AudioTrack, preference string) *providers.AudioTrack {
 	if len(tracks) == 0 {
@@ -44,6 +87,24 @@ func SelectAudioTrack(tracks []providers.AudioTrack, preference string) *provide
 		return &tracks[0]
 	}
 
+	if IsLanguagePreference(preference) {
+		lang := NormalizeLanguage(preference)
+		for i := range tracks {
+			if NormalizeLanguage(tracks[i].Language) == lang {
+				return &tracks[i]
+			}
+		}
+		// Tracks without language tags: Japanese is the original, English a dub
+		switch lang {
+		case "ja":
+			preference = "sub"
+		case "en":
+			preference = "dub"
+		default:
+			return nil
+		}
+	}
+
 	// Exact match on Type field (provider explicitly set dub/sub)
 	for i := range tracks {
 		if tracks[i].Type == preference {
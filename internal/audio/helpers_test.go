@@ -0,0 +1,42 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+func TestSelectAudioTrackByLanguage(t *testing.T) {
+	tracks := []providers.AudioTrack{
+		{Index: 1, Language: "jpn", Label: "Japanese"},
+		{Index: 2, Language: "eng", Label: "English"},
+		{Index: 3, Language: "rus", Label: "Russian"},
+	}
+
+	for pref, index := range map[string]int{"ja": 1, "en": 2, "ru": 3, "dub": 2, "sub": 1} {
+		track := SelectAudioTrack(tracks, pref)
+		require.NotNil(t, track, pref)
+		assert.Equal(t, index, track.Index, pref)
+	}
+
+	assert.Nil(t, SelectAudioTrack(tracks, "de"))
+
+	// Untagged tracks fall back to dub/sub detection
+	untagged := []providers.AudioTrack{
+		{Index: 1, Label: "Japanese (Original)"},
+		{Index: 2, Label: "English (Dub)"},
+	}
+	track := SelectAudioTrack(untagged, "en")
+	require.NotNil(t, track)
+	assert.Equal(t, 2, track.Index)
+}
+
+func TestTrackPreference(t *testing.T) {
+	assert.Equal(t, "dub", TrackPreference(providers.AudioTrack{Type: "dub", Language: "en"}))
+	assert.Equal(t, "ru", TrackPreference(providers.AudioTrack{Type: "unknown", Language: "Russian"}))
+	assert.Equal(t, "sub", TrackPreference(providers.AudioTrack{Label: "Japanese"}))
+	assert.Empty(t, TrackPreference(providers.AudioTrack{Label: "Track 3"}))
+}
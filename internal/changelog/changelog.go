@@ -0,0 +1,73 @@
+// Package changelog parses the embedded CHANGELOG.md into per-release notes
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Release is one version's section of the changelog
+type Release struct {
+	Version string // Version as written in the heading, e.g. "1.4.0" or "Unreleased"
+	Date    string // Release date, empty if not given
+	Notes   string // Markdown body of the section
+}
+
+var (
+	// releaseHeading matches "## [1.4.0] - 2026-10-01", "## v1.4.0" or "## [Unreleased]"
+	releaseHeading = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?(?:\s+-\s+(\S+))?\s*$`)
+	// describeSuffix matches what git describe appends to a tag, e.g. "-3-gabc1234-dirty"
+	describeSuffix = regexp.MustCompile(`(-\d+-g[0-9a-f]+)?(-dirty)?$`)
+	// releaseVersion matches a tagged version such as "1.4.0"
+	releaseVersion = regexp.MustCompile(`^\d+\.\d+`)
+)
+
+// Parse splits a changelog into releases, newest first as written
+func Parse(text string) []Release {
+	var releases []Release
+	var body []string
+
+	flush := func() {
+		if len(releases) > 0 {
+			releases[len(releases)-1].Notes = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if m := releaseHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			releases = append(releases, Release{Version: m[1], Date: m[2]})
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	return releases
+}
+
+// Normalize reduces a version to its release tag: the "v" prefix and any git
+// describe suffix are dropped, so "v1.4.0-3-gabc1234" becomes "1.4.0"
+func Normalize(version string) string {
+	version = strings.TrimSpace(version)
+	version = describeSuffix.ReplaceAllString(version, "")
+	return strings.TrimPrefix(version, "v")
+}
+
+// IsRelease reports whether version names a tagged release rather than a
+// development build
+func IsRelease(version string) bool {
+	return releaseVersion.MatchString(Normalize(version))
+}
+
+// Find returns the notes of the given version
+func Find(releases []Release, version string) (Release, bool) {
+	want := Normalize(version)
+	for _, release := range releases {
+		if strings.EqualFold(Normalize(release.Version), want) {
+			return release, true
+		}
+	}
+	return Release{}, false
+}
@@ -0,0 +1,61 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `# Changelog
+
+Intro text.
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.4.0] - 2026-10-01
+
+### Added
+- Changelog viewer
+
+## v1.3.0
+- Older release
+`
+
+func TestParse(t *testing.T) {
+	releases := Parse(sample)
+	require.Len(t, releases, 3)
+
+	assert.Equal(t, "Unreleased", releases[0].Version)
+	assert.Equal(t, "- Work in progress", releases[0].Notes)
+	assert.Equal(t, "1.4.0", releases[1].Version)
+	assert.Equal(t, "2026-10-01", releases[1].Date)
+	assert.Equal(t, "### Added\n- Changelog viewer", releases[1].Notes)
+	assert.Equal(t, "v1.3.0", releases[2].Version)
+}
+
+func TestFind(t *testing.T) {
+	releases := Parse(sample)
+
+	release, ok := Find(releases, "v1.4.0-3-gabc1234-dirty")
+	require.True(t, ok)
+	assert.Equal(t, "1.4.0", release.Version)
+
+	release, ok = Find(releases, "1.3.0")
+	require.True(t, ok)
+	assert.Equal(t, "- Older release", release.Notes)
+
+	_, ok = Find(releases, "2.0.0")
+	assert.False(t, ok)
+}
+
+func TestIsRelease(t *testing.T) {
+	assert.True(t, IsRelease("v1.4.0"))
+	assert.True(t, IsRelease("1.4.0-2-g1234abc"))
+	assert.False(t, IsRelease("dev"))
+	assert.False(t, IsRelease("abc1234"))
+	assert.False(t, IsRelease("1234abc"))
+	assert.False(t, IsRelease(""))
+}
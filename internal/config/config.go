@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -24,6 +25,11 @@ type Config struct {
 	Database   DatabaseConfig   `mapstructure:"database" yaml:"database"`
 	Logging    LoggingConfig    `mapstructure:"logging" yaml:"logging"`
 	Network    NetworkConfig    `mapstructure:"network" yaml:"network"`
+	Metadata   MetadataConfig   `mapstructure:"metadata" yaml:"metadata"`
+	Extensions ExtensionsConfig `mapstructure:"extensions" yaml:"extensions"`
+	Ambient    AmbientConfig    `mapstructure:"ambient" yaml:"ambient"`
+	Playlist   PlaylistConfig   `mapstructure:"playlist" yaml:"playlist"`
+	Scripts    ScriptsConfig    `mapstructure:"scripts" yaml:"scripts"`
 	Advanced   AdvancedConfig   `mapstructure:"advanced" yaml:"advanced"`
 
 	// Internal fields
@@ -47,6 +53,51 @@ type PlayerConfig struct {
 	AudioPreference string        `mapstructure:"audio_preference"`
 	LoadUserConfig  bool          `mapstructure:"load_user_config"`
 	IPCTimeout      time.Duration `mapstructure:"ipc_timeout"`
+	SourceSelector  bool          `mapstructure:"source_selector"` // Pick the server/mirror before every playback
+	PreloadNext     bool          `mapstructure:"preload_next"`    // Resolve the next episode's stream halfway through the current one
+
+	// SleepAction is what the sleep timer does to mpv: "pause" or "stop"
+	SleepAction string `mapstructure:"sleep_action"`
+	// SleepStopsAutoplay turns off "continue watching" once the sleep timer fires
+	SleepStopsAutoplay bool `mapstructure:"sleep_stops_autoplay"`
+	// DailyLimit is the watch time per day after which greg reminds you, 0 = off
+	DailyLimit time.Duration `mapstructure:"daily_limit"`
+
+	// AudioNormalization evens out loudness: "off", "loudnorm" or "dynaudnorm";
+	// 'n' while playing overrides it per show
+	AudioNormalization string `mapstructure:"audio_normalization"`
+	// ReplayGain applies ReplayGain tags: "off", "track" or "album"
+	ReplayGain string `mapstructure:"replaygain"`
+
+	// HWDec is mpv's hardware decoding API (auto, vaapi, nvdec, videotoolbox,
+	// off), empty to leave it to mpv
+	HWDec string `mapstructure:"hwdec"`
+	// Profile tunes mpv for the machine: "low-end", "desktop" or empty
+	Profile string `mapstructure:"profile"`
+
+	// StreamHealth switches source when the stream keeps stalling
+	StreamHealth StreamHealthConfig `mapstructure:"stream_health"`
+
+	// Completion decides when an episode counts as watched
+	Completion CompletionConfig `mapstructure:"completion"`
+}
+
+// CompletionConfig decides when an episode counts as watched
+type CompletionConfig struct {
+	Rule      string        `mapstructure:"rule"`      // "percent" or "remaining"
+	Percent   float64       `mapstructure:"percent"`   // Watched share for the percent rule
+	Remaining time.Duration `mapstructure:"remaining"` // Time left for the remaining rule
+	AniSkip   bool          `mapstructure:"aniskip"`   // Leave the ending out of the time left when AniSkip knows it
+}
+
+// StreamHealthConfig decides when a stalling stream is swapped for another
+// source mid-playback
+type StreamHealthConfig struct {
+	AutoSwitch     bool          `mapstructure:"auto_switch"`     // Switch sources automatically
+	Stalls         int           `mapstructure:"stalls"`          // Stalls within Window that make a stream unhealthy
+	Window         time.Duration `mapstructure:"window"`          // Period stalls are counted over
+	StallTimeout   time.Duration `mapstructure:"stall_timeout"`   // A single stall this long is unhealthy too
+	OtherProviders bool          `mapstructure:"other_providers"` // Look the episode up on other providers when no server is left
 }
 
 // ProvidersConfig contains provider settings
@@ -61,12 +112,37 @@ type ProvidersConfig struct {
 	FlixHQ              ProviderSettings  `mapstructure:"flixhq" yaml:"flixhq"`
 	HDRezka             ProviderSettings  `mapstructure:"hdrezka" yaml:"hdrezka"`
 	Comix               ProviderSettings  `mapstructure:"comix" yaml:"comix"`
+	Comick              ProviderSettings  `mapstructure:"comick" yaml:"comick"`
+	YTDLP               YTDLPConfig       `mapstructure:"ytdlp" yaml:"ytdlp"`
+	Scrapers            ScrapersConfig    `mapstructure:"scrapers" yaml:"scrapers"`
+}
+
+// ScrapersConfig contains settings for providers defined in YAML descriptors
+type ScrapersConfig struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"` // Load the descriptors in Dir
+	Dir     string `mapstructure:"dir" yaml:"dir"`         // Descriptor directory (empty = "scrapers" in the config directory)
+}
+
+// ScrapersDir returns the directory holding scraper descriptors
+func (s ScrapersConfig) ScrapersDir() string {
+	if s.Dir != "" {
+		return expandPath(s.Dir)
+	}
+	return filepath.Join(getConfigDir(), "scrapers")
+}
+
+// YTDLPConfig contains settings for the yt-dlp fallback extractor
+type YTDLPConfig struct {
+	Enabled bool     `mapstructure:"enabled" yaml:"enabled"` // Extract streams with yt-dlp when a provider fails
+	Binary  string   `mapstructure:"binary" yaml:"binary"`   // yt-dlp executable (empty = "yt-dlp" from PATH)
+	Args    []string `mapstructure:"args" yaml:"args"`       // Extra arguments, e.g. ["--cookies-from-browser", "firefox"]
 }
 
 // DefaultProviders specifies default provider for each media type
 type DefaultProviders struct {
 	Anime       string `mapstructure:"anime" yaml:"anime"`
 	MoviesAndTV string `mapstructure:"movies_and_tv" yaml:"movies_and_tv"` // Combined field for movies and TV
+	Manga       string `mapstructure:"manga" yaml:"manga"`
 }
 
 // PriorityProviders specifies provider priority order
@@ -102,22 +178,149 @@ type AniListConfig struct {
 	SyncInterval  time.Duration `mapstructure:"sync_interval"`
 	RedirectURI   string        `mapstructure:"redirect_uri"`
 	ServerPort    int           `mapstructure:"server_port"`
+	SmartLists    []SmartList   `mapstructure:"smart_lists"`
+
+	// ProgressConflict decides the next episode when local history and
+	// AniList disagree: "ask" prompts, "max" plays the furthest one
+	ProgressConflict string `mapstructure:"progress_conflict"`
+
+	// ChapterThreshold is the share of a chapter's pages that must be viewed
+	// for it to count as read (0-1)
+	ChapterThreshold float64 `mapstructure:"chapter_threshold"`
+}
+
+// SmartList is a named filter over the AniList library, shown as a tab in
+// the library view. Empty criteria match everything.
+type SmartList struct {
+	Name          string        `mapstructure:"name"`
+	Status        []string      `mapstructure:"status"`         // e.g. ["watching", "on_hold"]
+	Airing        bool          `mapstructure:"airing"`         // Only currently airing media
+	Behind        bool          `mapstructure:"behind"`         // Progress is behind the released episodes
+	MinScore      float64       `mapstructure:"min_score"`      // Minimum AniList average score (0-10)
+	MinUserScore  float64       `mapstructure:"min_user_score"` // Minimum personal score
+	WatchedWithin time.Duration `mapstructure:"watched_within"` // Watched in greg within this period
+	UnwatchedFor  time.Duration `mapstructure:"unwatched_for"`  // Not watched in greg for at least this period
 }
 
 // DownloadsConfig contains download settings
 type DownloadsConfig struct {
-	Path                  string   `mapstructure:"path"`
-	Concurrent            int      `mapstructure:"concurrent"`
-	ConcurrentSegments    int      `mapstructure:"concurrent_segments"`
-	EmbedSubtitles        bool     `mapstructure:"embed_subtitles"`
-	SubtitleLanguages     []string `mapstructure:"subtitle_languages"`
-	AutoResume            bool     `mapstructure:"auto_resume"`
-	KeepPartial           bool     `mapstructure:"keep_partial"`
-	FilenameTemplate      string   `mapstructure:"filename_template"`
-	AnimeFilenameTemplate string   `mapstructure:"anime_filename_template"`
-	MovieFilenameTemplate string   `mapstructure:"movie_filename_template"`
-	MaxSpeed              int64    `mapstructure:"max_speed"`
-	MinFreeSpace          int      `mapstructure:"min_free_space"`
+	Path                  string         `mapstructure:"path"`
+	Concurrent            int            `mapstructure:"concurrent"`
+	ConcurrentSegments    int            `mapstructure:"concurrent_segments"`
+	EmbedSubtitles        bool           `mapstructure:"embed_subtitles"`
+	SubtitleLanguages     []string       `mapstructure:"subtitle_languages"`
+	AutoResume            bool           `mapstructure:"auto_resume"`
+	KeepPartial           bool           `mapstructure:"keep_partial"`
+	FilenameTemplate      string         `mapstructure:"filename_template"`
+	AnimeFilenameTemplate string         `mapstructure:"anime_filename_template"`
+	MovieFilenameTemplate string         `mapstructure:"movie_filename_template"`
+	MaxSpeed              int64          `mapstructure:"max_speed"`
+	MinFreeSpace          int            `mapstructure:"min_free_space"`
+	IntroChapters         bool           `mapstructure:"intro_chapters"` // Mark openings found by comparing episodes' audio as chapters
+	Cleanup               CleanupConfig  `mapstructure:"cleanup"`
+	XDCC                  XDCCConfig     `mapstructure:"xdcc"`
+	Clips                 ClipsConfig    `mapstructure:"clips"`
+	Feeds                 FeedsConfig    `mapstructure:"feeds"`
+	Releases              ReleasesConfig `mapstructure:"releases"`
+	LAN                   LANConfig      `mapstructure:"lan"`
+	Library               LibraryConfig  `mapstructure:"library"`
+	Incoming              IncomingConfig `mapstructure:"incoming"`
+	Torrent               TorrentConfig  `mapstructure:"torrent"`
+	Remote                RemoteConfig   `mapstructure:"remote"`
+}
+
+// CleanupConfig contains the retention policy for watched downloads
+type CleanupConfig struct {
+	Days        int      `mapstructure:"days"`         // Days after an episode is watched before it's cleaned up (0 = never)
+	Action      string   `mapstructure:"action"`       // "delete" (moves to the trash) or "archive"
+	ArchivePath string   `mapstructure:"archive_path"` // Where archived files go (empty = <path>/archive)
+	Exclude     []string `mapstructure:"exclude"`      // Show titles or media IDs that are never cleaned up
+}
+
+// ClipsConfig contains the settings of clips exported from playback
+type ClipsConfig struct {
+	Format      string        `mapstructure:"format"`       // "gif" or "webm"
+	Width       int           `mapstructure:"width"`        // Output width in pixels, height keeps the aspect ratio
+	FPS         int           `mapstructure:"fps"`          // GIF frame rate
+	Hardsub     bool          `mapstructure:"hardsub"`      // Burn in the stream's subtitles
+	MaxDuration time.Duration `mapstructure:"max_duration"` // Longest clip accepted
+}
+
+// XDCCConfig contains IRC/XDCC download settings
+type XDCCConfig struct {
+	Nick      string   `mapstructure:"nick"`      // IRC nickname (empty = random)
+	Server    string   `mapstructure:"server"`    // IRC server used for packlist search results
+	Channel   string   `mapstructure:"channel"`   // Channel joined before requesting packs
+	Packlists []string `mapstructure:"packlists"` // Packlist URLs searched by 'greg xdcc search'
+}
+
+// FeedsConfig contains RSS release feed settings
+type FeedsConfig struct {
+	Interval        time.Duration `mapstructure:"interval"`         // How often 'greg feeds watch' checks feeds
+	AniListWatching bool          `mapstructure:"anilist_watching"` // Match entries against the AniList watching list
+	TorrentCommand  string        `mapstructure:"torrent_command"`  // Command run for magnet/.torrent links ({url} = link)
+}
+
+// ReleasesConfig contains release group preferences used when choosing among
+// torrent/XDCC alternatives of the same episode
+type ReleasesConfig struct {
+	PreferredGroups      []string `mapstructure:"preferred_groups"`      // Most preferred first
+	BannedGroups         []string `mapstructure:"banned_groups"`         // Never downloaded
+	PreferredResolutions []string `mapstructure:"preferred_resolutions"` // e.g. ["1080p", "720p"]
+	PreferredCodecs      []string `mapstructure:"preferred_codecs"`      // e.g. ["hevc", "avc"]
+}
+
+// LANConfig contains the settings of sharing downloads with other greg
+// instances on the local network
+type LANConfig struct {
+	Share  bool   `mapstructure:"share"`  // Share completed downloads while greg runs
+	Pull   bool   `mapstructure:"pull"`   // Pull episodes other instances already have before downloading them
+	Listen string `mapstructure:"listen"` // Address the share server listens on
+	Token  string `mapstructure:"token"`  // Shared secret, the same on every instance (empty = none)
+}
+
+// LibraryConfig contains the settings of 'greg library serve'
+type LibraryConfig struct {
+	Name      string `mapstructure:"name"`      // Server name TVs show
+	Listen    string `mapstructure:"listen"`    // Address the media server listens on
+	Transcode string `mapstructure:"transcode"` // "auto" (all but MP4), "always" or "never"
+}
+
+// IncomingConfig contains the settings of the folder other programs drop
+// finished downloads into
+type IncomingConfig struct {
+	Path     string        `mapstructure:"path"`     // Folder watched for new files (empty = disabled)
+	Interval time.Duration `mapstructure:"interval"` // How often the folder is scanned
+}
+
+// TorrentConfig contains the settings of the qBittorrent or Transmission
+// instance torrents are handed to
+type TorrentConfig struct {
+	Client     string        `mapstructure:"client"`   // "qbittorrent" or "transmission" (empty = feeds.torrent_command)
+	URL        string        `mapstructure:"url"`      // Web UI or RPC address
+	Username   string        `mapstructure:"username"` // Empty when the client allows access without login
+	Password   string        `mapstructure:"password"`
+	Category   string        `mapstructure:"category"`    // qBittorrent category or Transmission label of added torrents
+	SavePath   string        `mapstructure:"save_path"`   // Download folder on the client (empty = client default)
+	RemotePath string        `mapstructure:"remote_path"` // Download folder as the client sees it, when greg sees it at local_path
+	LocalPath  string        `mapstructure:"local_path"`  // The same folder as greg sees it (e.g. a container volume)
+	Interval   time.Duration `mapstructure:"interval"`    // How often finished torrents are looked for
+}
+
+// RemoteConfig contains the remote storage finished downloads are uploaded to
+type RemoteConfig struct {
+	Type            string `mapstructure:"type"`          // "rclone", "sftp" or "s3" (empty = downloads stay local)
+	Path            string `mapstructure:"path"`          // rclone: "remote:folder"; sftp: folder on the server; s3: "bucket/prefix"
+	KeepLocal       bool   `mapstructure:"keep_local"`    // Keep the downloaded file after uploading it
+	Host            string `mapstructure:"host"`          // SFTP server
+	Port            int    `mapstructure:"port"`          // SFTP port (0 = 22)
+	User            string `mapstructure:"user"`          // SFTP user
+	KeyFile         string `mapstructure:"key_file"`      // SFTP private key (empty = ssh-agent or password)
+	Password        string `mapstructure:"password"`      // SFTP password
+	Region          string `mapstructure:"region"`        // S3 region
+	Endpoint        string `mapstructure:"endpoint"`      // S3-compatible endpoint, e.g. MinIO or Backblaze (empty = AWS)
+	AccessKeyID     string `mapstructure:"access_key_id"` // S3 credentials (empty = from the AWS environment)
+	SecretAccessKey string `mapstructure:"secret_access_key"`
 }
 
 // UIConfig contains UI settings
@@ -135,6 +338,16 @@ type UIConfig struct {
 	FuzzyFinder      string            `mapstructure:"fuzzy_finder"`
 	ShowLoading      bool              `mapstructure:"show_loading"`
 	DefaultMediaType string            `mapstructure:"default_media_type"` // movie_tv, anime, or manga
+	ClipboardWatch   bool              `mapstructure:"clipboard_watch"`    // Open copied AniList/MAL/provider links
+	LowRefresh       bool              `mapstructure:"low_refresh"`        // Static spinners and fewer redraws for SSH/mosh
+	Locale           string            `mapstructure:"locale"`             // Interface language, e.g. "es" (empty = from environment)
+	Refresh          RefreshConfig     `mapstructure:"refresh"`
+}
+
+// RefreshConfig contains the intervals of periodic TUI updates
+type RefreshConfig struct {
+	Downloads time.Duration `mapstructure:"downloads"` // Downloads view progress refresh
+	Playback  time.Duration `mapstructure:"playback"`  // Playback progress check
 }
 
 // PreviewSize contains preview image dimensions
@@ -149,6 +362,9 @@ type WatchPartyConfig struct {
 	DefaultProxy    string `mapstructure:"default_proxy"`
 	AutoOpenBrowser bool   `mapstructure:"auto_open_browser"`
 	DefaultOrigin   string `mapstructure:"default_origin"`
+	SyncListen      string `mapstructure:"sync_listen"`
+	ProxyListen     string `mapstructure:"proxy_listen"`
+	ProxyPublicURL  string `mapstructure:"proxy_public_url"`
 }
 
 // CacheConfig contains cache settings
@@ -170,11 +386,16 @@ type CacheTTL struct {
 
 // DatabaseConfig contains database settings
 type DatabaseConfig struct {
-	Path           string `mapstructure:"path"`
-	WALMode        bool   `mapstructure:"wal_mode"`
-	MaxConnections int    `mapstructure:"max_connections"`
-	AutoVacuum     bool   `mapstructure:"auto_vacuum"`
-	BackupOnExit   bool   `mapstructure:"backup_on_exit"`
+	Driver         string        `mapstructure:"driver"` // "sqlite" (default), "postgres" or "mysql"
+	DSN            string        `mapstructure:"dsn"`    // Connection string for postgres/mysql
+	Path           string        `mapstructure:"path"`
+	WALMode        bool          `mapstructure:"wal_mode"`
+	BusyTimeout    time.Duration `mapstructure:"busy_timeout"`
+	WriteQueueSize int           `mapstructure:"write_queue_size"`
+	MaxConnections int           `mapstructure:"max_connections"`
+	AutoVacuum     bool          `mapstructure:"auto_vacuum"`
+	BackupOnExit   bool          `mapstructure:"backup_on_exit"`
+	TrashRetention time.Duration `mapstructure:"trash_retention"` // How long deleted items can be restored, 0 keeps them
 }
 
 // LoggingConfig contains logging settings
@@ -191,14 +412,148 @@ type LoggingConfig struct {
 
 // NetworkConfig contains network settings
 type NetworkConfig struct {
-	Timeout         time.Duration `mapstructure:"timeout"`
-	HTTP2           bool          `mapstructure:"http2"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	IdleConnTimeout time.Duration `mapstructure:"idle_conn_timeout"`
-	UserAgent       string        `mapstructure:"user_agent"`
-	Proxy           string        `mapstructure:"proxy"`
-	VerifyTLS       bool          `mapstructure:"verify_tls"`
-	DNSServers      []string      `mapstructure:"dns_servers"`
+	Timeout         time.Duration  `mapstructure:"timeout"`
+	HTTP2           bool           `mapstructure:"http2"`
+	MaxIdleConns    int            `mapstructure:"max_idle_conns"`
+	IdleConnTimeout time.Duration  `mapstructure:"idle_conn_timeout"`
+	UserAgent       string         `mapstructure:"user_agent"`
+	Proxy           string         `mapstructure:"proxy"`
+	VerifyTLS       bool           `mapstructure:"verify_tls"`
+	DNSServers      []string       `mapstructure:"dns_servers"`
+	Timeouts        TimeoutsConfig `mapstructure:"timeouts"`
+	MonthlyCap      float64        `mapstructure:"monthly_cap"` // GB streamed and downloaded per month, 0 = no cap
+
+	BindInterface      string            `mapstructure:"bind_interface"`      // Interface provider traffic goes out of, empty for the default route
+	ProviderInterfaces map[string]string `mapstructure:"provider_interfaces"` // Overrides keyed by provider name, "default" for the default route
+}
+
+// Built-in provider call timeouts, used when the config leaves them unset
+const (
+	DefaultSearchTimeout  = 30 * time.Second
+	DefaultDetailsTimeout = 30 * time.Second
+	DefaultStreamTimeout  = 30 * time.Second
+)
+
+// TimeoutKind is a class of provider call with its own timeout
+type TimeoutKind string
+
+const (
+	TimeoutSearch  TimeoutKind = "search"  // Searching for media
+	TimeoutDetails TimeoutKind = "details" // Media details, seasons and episodes
+	TimeoutStream  TimeoutKind = "stream"  // Stream URLs, sources and manga pages
+)
+
+// TimeoutsConfig contains the timeouts of provider calls
+type TimeoutsConfig struct {
+	Search    time.Duration               `mapstructure:"search"`
+	Details   time.Duration               `mapstructure:"details"`
+	Stream    time.Duration               `mapstructure:"stream"`
+	Providers map[string]ProviderTimeouts `mapstructure:"providers"` // Overrides keyed by provider name
+}
+
+// ProviderTimeouts overrides the timeouts of one provider. Zero keeps the
+// global value.
+type ProviderTimeouts struct {
+	Search  time.Duration `mapstructure:"search"`
+	Details time.Duration `mapstructure:"details"`
+	Stream  time.Duration `mapstructure:"stream"`
+}
+
+// For returns the timeout of a call to provider: its override if set, else
+// the global value, else the built-in default
+func (t TimeoutsConfig) For(provider string, kind TimeoutKind) time.Duration {
+	override := t.Providers[strings.ToLower(provider)]
+	switch kind {
+	case TimeoutSearch:
+		return firstPositive(override.Search, t.Search, DefaultSearchTimeout)
+	case TimeoutDetails:
+		return firstPositive(override.Details, t.Details, DefaultDetailsTimeout)
+	default:
+		return firstPositive(override.Stream, t.Stream, DefaultStreamTimeout)
+	}
+}
+
+// Budget returns the time allowed for a sequence of calls to provider
+func (t TimeoutsConfig) Budget(provider string, kinds ...TimeoutKind) time.Duration {
+	var total time.Duration
+	for _, kind := range kinds {
+		total += t.For(provider, kind)
+	}
+	return total
+}
+
+func firstPositive(durations ...time.Duration) time.Duration {
+	for _, d := range durations {
+		if d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// MetadataConfig contains external metadata source settings
+type MetadataConfig struct {
+	TMDBAPIKey    string `mapstructure:"tmdb_api_key"`    // Fills in TV episode details; empty disables TMDB
+	ArcsDir       string `mapstructure:"arcs_dir"`        // Arc datasets (empty = "arcs" in the config directory)
+	IDMappingsDir string `mapstructure:"id_mappings_dir"` // Cross-ID mapping datasets (empty = "id-mappings" in the config directory)
+}
+
+// ArcsPath returns the directory holding the arc datasets
+func (m MetadataConfig) ArcsPath() string {
+	if m.ArcsDir != "" {
+		return expandPath(m.ArcsDir)
+	}
+	return filepath.Join(getConfigDir(), "arcs")
+}
+
+// IDMappingsPath returns the directory holding the cross-ID mapping datasets
+func (m MetadataConfig) IDMappingsPath() string {
+	if m.IDMappingsDir != "" {
+		return expandPath(m.IDMappingsDir)
+	}
+	return filepath.Join(getConfigDir(), "id-mappings")
+}
+
+// ExtensionsConfig contains settings for the community extension repository
+type ExtensionsConfig struct {
+	Repo          string `mapstructure:"repo"`           // URL of the repository index (index.json)
+	PublicKey     string `mapstructure:"public_key"`     // Base64 ed25519 key the index is signed with
+	AllowUnsigned bool   `mapstructure:"allow_unsigned"` // Accept an index without a valid signature
+}
+
+// AmbientConfig contains the background radio settings
+type AmbientConfig struct {
+	Volume   int              `mapstructure:"volume"`   // 0-100
+	Stations []AmbientStation `mapstructure:"stations"` // Cycled through with alt+o
+}
+
+// AmbientStation is an audio stream played in the background while browsing
+type AmbientStation struct {
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+}
+
+// PlaylistConfig contains the OAuth clients used to export the OP/ED
+// playlist to music services
+type PlaylistConfig struct {
+	SpotifyClientID     string `mapstructure:"spotify_client_id"`     // Spotify app, PKCE (no secret)
+	YouTubeClientID     string `mapstructure:"youtube_client_id"`     // Google "desktop app" OAuth client
+	YouTubeClientSecret string `mapstructure:"youtube_client_secret"` // Not secret for desktop apps
+	RedirectPort        int    `mapstructure:"redirect_port"`         // Loopback port of the sign-in redirect
+}
+
+// ScriptsConfig contains the settings of the Lua automation scripts
+type ScriptsConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // Run the scripts in Dir
+	Dir     string `mapstructure:"dir"`     // Script directory (empty = "scripts" in the config directory)
+}
+
+// ScriptsDir returns the directory holding the Lua scripts
+func (s ScriptsConfig) ScriptsDir() string {
+	if s.Dir != "" {
+		return expandPath(s.Dir)
+	}
+	return filepath.Join(getConfigDir(), "scripts")
 }
 
 // AdvancedConfig contains advanced settings
@@ -258,6 +613,10 @@ func Load(configPath string) (*Config, *viper.Viper, error) {
 
 	// Expand paths
 	cfg.Downloads.Path = expandPath(cfg.Downloads.Path)
+	cfg.Downloads.Cleanup.ArchivePath = expandPath(cfg.Downloads.Cleanup.ArchivePath)
+	cfg.Downloads.Incoming.Path = expandPath(cfg.Downloads.Incoming.Path)
+	cfg.Downloads.Torrent.LocalPath = expandPath(cfg.Downloads.Torrent.LocalPath)
+	cfg.Downloads.Remote.KeyFile = expandPath(cfg.Downloads.Remote.KeyFile)
 	cfg.Cache.Path = expandPath(cfg.Cache.Path)
 	cfg.Database.Path = expandPath(cfg.Database.Path)
 	cfg.Logging.File = expandPath(cfg.Logging.File)
@@ -265,6 +624,12 @@ func Load(configPath string) (*Config, *viper.Viper, error) {
 	return &cfg, v, nil
 }
 
+// Config returns c, so a *Config can be handed to code taking anything that
+// supplies the configuration
+func (c *Config) Config() *Config {
+	return c
+}
+
 // Save saves the configuration to the file
 func (c *Config) Save() error {
 	// Determine config path
@@ -312,12 +677,31 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("player.subtitle_language", "en")
 	v.SetDefault("player.auto_subtitles", true)
 	v.SetDefault("player.audio_preference", "sub")
+	v.SetDefault("player.audio_normalization", "off")
+	v.SetDefault("player.replaygain", "off")
+	v.SetDefault("player.hwdec", "")
+	v.SetDefault("player.profile", "")
+	v.SetDefault("player.stream_health.auto_switch", true)
+	v.SetDefault("player.stream_health.stalls", 3)
+	v.SetDefault("player.stream_health.window", 2*time.Minute)
+	v.SetDefault("player.stream_health.stall_timeout", 20*time.Second)
+	v.SetDefault("player.stream_health.other_providers", true)
+	v.SetDefault("player.completion.rule", "percent")
+	v.SetDefault("player.completion.percent", 85.0)
+	v.SetDefault("player.completion.remaining", 3*time.Minute)
+	v.SetDefault("player.completion.aniskip", true)
 	v.SetDefault("player.load_user_config", true)
 	v.SetDefault("player.ipc_timeout", 5*time.Second)
+	v.SetDefault("player.source_selector", false)
+	v.SetDefault("player.preload_next", true)
+	v.SetDefault("player.sleep_action", "pause")
+	v.SetDefault("player.sleep_stops_autoplay", true)
+	v.SetDefault("player.daily_limit", time.Duration(0))
 
 	// Provider defaults
 	v.SetDefault("providers.default.anime", "hianime")
 	v.SetDefault("providers.default.movies_and_tv", "sflix") // Combined default for movies and TV
+	v.SetDefault("providers.default.manga", "comix")
 	v.SetDefault("providers.health_check_interval", 5*time.Minute)
 	v.SetDefault("providers.auto_failover", true)
 
@@ -345,14 +729,26 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("providers.comix.enabled", true)
 	v.SetDefault("providers.comix.mode", "local")
 
+	// Comick defaults (API-based)
+	v.SetDefault("providers.comick.enabled", true)
+	v.SetDefault("providers.comick.mode", "local")
+	v.SetDefault("providers.ytdlp.enabled", false)
+	v.SetDefault("providers.ytdlp.binary", "")
+	v.SetDefault("providers.ytdlp.args", []string{})
+	v.SetDefault("providers.scrapers.enabled", true)
+	v.SetDefault("providers.scrapers.dir", "")
+
 	// Tracker defaults
 	v.SetDefault("tracker.anilist.enabled", true)
 	v.SetDefault("tracker.anilist.auto_sync", true)
 	v.SetDefault("tracker.anilist.sync_threshold", 0.85)
+	v.SetDefault("tracker.anilist.chapter_threshold", 0.9)
 	v.SetDefault("tracker.anilist.auto_complete", true)
 	v.SetDefault("tracker.anilist.sync_interval", 5*time.Minute)
 	v.SetDefault("tracker.anilist.redirect_uri", "http://localhost:8000/oauth/callback")
 	v.SetDefault("tracker.anilist.server_port", 8000)
+	v.SetDefault("tracker.anilist.smart_lists", []SmartList{})
+	v.SetDefault("tracker.anilist.progress_conflict", "ask")
 
 	// Download defaults
 	v.SetDefault("downloads.path", filepath.Join(getVideosDir(), "greg"))
@@ -367,6 +763,57 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("downloads.movie_filename_template", "{title} ({year}) [{quality}]")
 	v.SetDefault("downloads.max_speed", 0)
 	v.SetDefault("downloads.min_free_space", 5)
+	v.SetDefault("downloads.intro_chapters", true)
+	v.SetDefault("downloads.cleanup.days", 0)
+	v.SetDefault("downloads.cleanup.action", "delete")
+	v.SetDefault("downloads.cleanup.archive_path", "")
+	v.SetDefault("downloads.cleanup.exclude", []string{})
+	v.SetDefault("downloads.clips.format", "gif")
+	v.SetDefault("downloads.clips.width", 480)
+	v.SetDefault("downloads.clips.fps", 12)
+	v.SetDefault("downloads.clips.hardsub", true)
+	v.SetDefault("downloads.clips.max_duration", 30*time.Second)
+	v.SetDefault("downloads.xdcc.nick", "")
+	v.SetDefault("downloads.xdcc.server", "irc.rizon.net:6667")
+	v.SetDefault("downloads.xdcc.channel", "")
+	v.SetDefault("downloads.xdcc.packlists", []string{})
+	v.SetDefault("downloads.feeds.interval", 15*time.Minute)
+	v.SetDefault("downloads.feeds.anilist_watching", true)
+	v.SetDefault("downloads.feeds.torrent_command", "")
+	v.SetDefault("downloads.releases.preferred_groups", []string{})
+	v.SetDefault("downloads.releases.banned_groups", []string{})
+	v.SetDefault("downloads.releases.preferred_resolutions", []string{})
+	v.SetDefault("downloads.releases.preferred_codecs", []string{})
+	v.SetDefault("downloads.lan.share", false)
+	v.SetDefault("downloads.lan.pull", false)
+	v.SetDefault("downloads.lan.listen", ":7879")
+	v.SetDefault("downloads.lan.token", "")
+	v.SetDefault("downloads.library.name", "greg")
+	v.SetDefault("downloads.library.listen", ":8200")
+	v.SetDefault("downloads.library.transcode", "auto")
+	v.SetDefault("downloads.incoming.path", "")
+	v.SetDefault("downloads.incoming.interval", time.Minute)
+	v.SetDefault("downloads.torrent.client", "")
+	v.SetDefault("downloads.torrent.url", "")
+	v.SetDefault("downloads.torrent.username", "")
+	v.SetDefault("downloads.torrent.password", "")
+	v.SetDefault("downloads.torrent.category", "greg")
+	v.SetDefault("downloads.torrent.save_path", "")
+	v.SetDefault("downloads.torrent.remote_path", "")
+	v.SetDefault("downloads.torrent.local_path", "")
+	v.SetDefault("downloads.torrent.interval", time.Minute)
+	v.SetDefault("downloads.remote.type", "")
+	v.SetDefault("downloads.remote.path", "")
+	v.SetDefault("downloads.remote.keep_local", true)
+	v.SetDefault("downloads.remote.host", "")
+	v.SetDefault("downloads.remote.port", 0)
+	v.SetDefault("downloads.remote.user", "")
+	v.SetDefault("downloads.remote.key_file", "")
+	v.SetDefault("downloads.remote.password", "")
+	v.SetDefault("downloads.remote.region", "")
+	v.SetDefault("downloads.remote.endpoint", "")
+	v.SetDefault("downloads.remote.access_key_id", "")
+	v.SetDefault("downloads.remote.secret_access_key", "")
 
 	// UI defaults
 	v.SetDefault("ui.theme", "default")
@@ -381,12 +828,20 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("ui.time_format", "15:04")
 	v.SetDefault("ui.fuzzy_finder", "builtin")
 	v.SetDefault("ui.show_loading", false)
+	v.SetDefault("ui.clipboard_watch", false)
+	v.SetDefault("ui.low_refresh", false)
+	v.SetDefault("ui.locale", "")
+	v.SetDefault("ui.refresh.downloads", "300ms")
+	v.SetDefault("ui.refresh.playback", "1s")
 
 	// WatchParty defaults
 	v.SetDefault("watchparty.enabled", true)
 	v.SetDefault("watchparty.default_proxy", "")
 	v.SetDefault("watchparty.auto_open_browser", true)
 	v.SetDefault("watchparty.default_origin", "https://videostr.net")
+	v.SetDefault("watchparty.sync_listen", ":7878")
+	v.SetDefault("watchparty.proxy_listen", "127.0.0.1:8787")
+	v.SetDefault("watchparty.proxy_public_url", "")
 
 	// Cache defaults
 	v.SetDefault("cache.enabled", true)
@@ -399,11 +854,16 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("cache.cleanup_on_exit", false)
 
 	// Database defaults
+	v.SetDefault("database.driver", "sqlite")
+	v.SetDefault("database.dsn", "")
 	v.SetDefault("database.path", filepath.Join(getDataDir(), "greg", "greg.db"))
 	v.SetDefault("database.wal_mode", true)
+	v.SetDefault("database.busy_timeout", 5*time.Second)
+	v.SetDefault("database.write_queue_size", 64)
 	v.SetDefault("database.max_connections", 10)
 	v.SetDefault("database.auto_vacuum", true)
 	v.SetDefault("database.backup_on_exit", false)
+	v.SetDefault("database.trash_retention", 7*24*time.Hour)
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
@@ -422,6 +882,38 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("network.idle_conn_timeout", 90*time.Second)
 	v.SetDefault("network.user_agent", "greg/1.0.0")
 	v.SetDefault("network.verify_tls", true)
+	v.SetDefault("network.timeouts.search", DefaultSearchTimeout)
+	v.SetDefault("network.timeouts.details", DefaultDetailsTimeout)
+	v.SetDefault("network.timeouts.stream", DefaultStreamTimeout)
+	v.SetDefault("network.monthly_cap", 0)
+	v.SetDefault("network.bind_interface", "")
+
+	// Metadata defaults
+	v.SetDefault("metadata.tmdb_api_key", "")
+	v.SetDefault("metadata.arcs_dir", "")
+	v.SetDefault("metadata.id_mappings_dir", "")
+
+	// Extensions defaults
+	v.SetDefault("extensions.repo", "")
+	v.SetDefault("extensions.public_key", "")
+	v.SetDefault("extensions.allow_unsigned", false)
+
+	// Ambient defaults
+	v.SetDefault("ambient.volume", 60)
+	v.SetDefault("ambient.stations", []AmbientStation{
+		{Name: "LISTEN.moe", URL: "https://listen.moe/stream"},
+		{Name: "LISTEN.moe K-pop", URL: "https://listen.moe/kpop/stream"},
+	})
+
+	// Playlist defaults
+	v.SetDefault("playlist.spotify_client_id", "")
+	v.SetDefault("playlist.youtube_client_id", "")
+	v.SetDefault("playlist.youtube_client_secret", "")
+	v.SetDefault("playlist.redirect_port", 8766)
+
+	// Scripts defaults
+	v.SetDefault("scripts.enabled", true)
+	v.SetDefault("scripts.dir", "")
 
 	// Advanced defaults
 	v.SetDefault("advanced.experimental", false)
@@ -453,6 +945,12 @@ func GetConfigDir() string {
 	return getConfigDir()
 }
 
+// GetStateDir returns greg's state directory, which holds logs and the
+// instance lock
+func GetStateDir() string {
+	return filepath.Join(getStateDir(), "greg")
+}
+
 // getCacheDir returns the cache directory path
 func getCacheDir() string {
 	// Windows: Use LOCALAPPDATA for cache
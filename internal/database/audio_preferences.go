@@ -2,8 +2,10 @@ package database
 
 import (
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GetAudioPreference retrieves per-show audio preference by AniList ID
@@ -21,21 +23,94 @@ func GetAudioPreference(db *gorm.DB, anilistID int) (string, error) {
 }
 
 // SaveAudioPreference stores or updates per-show audio preference
-// Upserts using GORM Save (updates if exists, inserts if new)
+// Uses an ON CONFLICT clause on the AniList ID so repeated saves update the row
 func SaveAudioPreference(db *gorm.DB, anilistID int, preference string, trackIndex *int) error {
-	// Validate preference value
-	if preference != "dub" && preference != "sub" {
-		return errors.New("invalid audio preference: must be 'dub' or 'sub'")
+	if !validAudioPreference(preference) {
+		return errors.New("invalid audio preference: must be 'dub', 'sub' or a language code")
 	}
 
 	pref := AudioPreference{
 		AniListID:  anilistID,
 		Preference: preference,
 		TrackIndex: trackIndex, // Optional - for reference only
+		UpdatedAt:  time.Now(),
 	}
 
-	// GORM Save performs upsert based on unique index (anilist_id)
-	return db.Save(&pref).Error
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "anilist_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"preference", "track_index", "updated_at"}),
+		}).Create(&pref).Error
+	})
+}
+
+// GetMediaAudioPreference retrieves per-show audio preference by provider media ID
+// Returns empty string if no preference stored (not an error)
+func GetMediaAudioPreference(db *gorm.DB, provider, mediaID string) (string, error) {
+	var pref MediaAudioPreference
+	err := db.Where(&MediaAudioPreference{Provider: provider, MediaID: mediaID}).First(&pref).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return pref.Preference, nil
+}
+
+// SaveMediaAudioPreference stores or updates per-show audio preference for
+// content without an AniList ID
+func SaveMediaAudioPreference(db *gorm.DB, provider, mediaID, preference string, trackIndex *int) error {
+	if !validAudioPreference(preference) {
+		return errors.New("invalid audio preference: must be 'dub', 'sub' or a language code")
+	}
+
+	pref := MediaAudioPreference{
+		Provider:   provider,
+		MediaID:    mediaID,
+		Preference: preference,
+		TrackIndex: trackIndex,
+		UpdatedAt:  time.Now(),
+	}
+
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "media_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"preference", "track_index", "updated_at"}),
+		}).Create(&pref).Error
+	})
+}
+
+// GetAudioNormalization returns the normalization mode overriding the
+// configured one for a show, "" when there is none
+func GetAudioNormalization(db *gorm.DB, mediaID string) (string, error) {
+	var norm AudioNormalization
+	err := db.Where("media_id = ?", mediaID).First(&norm).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return norm.Mode, nil
+}
+
+// SaveAudioNormalization stores the normalization mode for a show; an empty
+// mode removes the override
+func SaveAudioNormalization(db *gorm.DB, mediaID, mode string) error {
+	if mediaID == "" {
+		return errors.New("media ID is required")
+	}
+
+	return Write(db, func(tx *gorm.DB) error {
+		if mode == "" {
+			return tx.Where("media_id = ?", mediaID).Delete(&AudioNormalization{}).Error
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "media_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"mode", "updated_at"}),
+		}).Create(&AudioNormalization{MediaID: mediaID, Mode: mode, UpdatedAt: time.Now()}).Error
+	})
 }
 
 // ClearAudioPreference removes per-show audio preference
@@ -57,3 +132,19 @@ func ClearCompletedPreferences(db *gorm.DB, anilistIDs []int) error {
 	}
 	return db.Where("anilist_id IN ?", anilistIDs).Delete(&AudioPreference{}).Error
 }
+
+// validAudioPreference accepts "dub", "sub" or a two/three-letter language code
+func validAudioPreference(preference string) bool {
+	if preference == "dub" || preference == "sub" {
+		return true
+	}
+	if len(preference) < 2 || len(preference) > 3 {
+		return false
+	}
+	for _, r := range preference {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
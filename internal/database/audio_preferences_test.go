@@ -0,0 +1,73 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAudioPreferenceUpsert(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	require.NoError(t, SaveAudioPreference(db, 21, "sub", nil))
+	require.NoError(t, SaveAudioPreference(db, 21, "dub", nil))
+
+	pref, err := GetAudioPreference(db, 21)
+	require.NoError(t, err)
+	assert.Equal(t, "dub", pref)
+
+	assert.Error(t, SaveAudioPreference(db, 21, "original", nil))
+}
+
+func TestMediaAudioPreference(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	pref, err := GetMediaAudioPreference(db, "hdrezka", "movie/123")
+	require.NoError(t, err)
+	assert.Empty(t, pref)
+
+	track := 2
+	require.NoError(t, SaveMediaAudioPreference(db, "hdrezka", "movie/123", "en", nil))
+	require.NoError(t, SaveMediaAudioPreference(db, "hdrezka", "movie/123", "ru", &track))
+	require.NoError(t, SaveMediaAudioPreference(db, "sflix", "movie/123", "ja", nil))
+
+	pref, err = GetMediaAudioPreference(db, "hdrezka", "movie/123")
+	require.NoError(t, err)
+	assert.Equal(t, "ru", pref)
+
+	pref, err = GetMediaAudioPreference(db, "sflix", "movie/123")
+	require.NoError(t, err)
+	assert.Equal(t, "ja", pref)
+
+	assert.Error(t, SaveMediaAudioPreference(db, "sflix", "movie/123", "Japanese", nil))
+}
+
+func TestAudioNormalizationOverride(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	mode, err := GetAudioNormalization(db, "anilist:21")
+	require.NoError(t, err)
+	assert.Empty(t, mode)
+
+	require.NoError(t, SaveAudioNormalization(db, "anilist:21", "loudnorm"))
+	require.NoError(t, SaveAudioNormalization(db, "anilist:21", "off"))
+	mode, err = GetAudioNormalization(db, "anilist:21")
+	require.NoError(t, err)
+	assert.Equal(t, "off", mode)
+
+	require.NoError(t, SaveAudioNormalization(db, "anilist:21", ""))
+	mode, err = GetAudioNormalization(db, "anilist:21")
+	require.NoError(t, err)
+	assert.Empty(t, mode)
+
+	assert.Error(t, SaveAudioNormalization(db, "", "loudnorm"))
+}
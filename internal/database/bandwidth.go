@@ -0,0 +1,79 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kinds of bandwidth usage
+const (
+	BandwidthStream   = "stream"
+	BandwidthDownload = "download"
+)
+
+// BandwidthMonth returns the month usage at t is recorded under
+func BandwidthMonth(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// AddBandwidth adds bytes streamed or downloaded from a provider to the
+// month of at, and returns the month's total over all providers
+func AddBandwidth(db *gorm.DB, provider, kind string, bytes int64, at time.Time) (int64, error) {
+	if provider == "" || kind == "" {
+		return 0, errors.New("provider and kind are required")
+	}
+	month := BandwidthMonth(at)
+	if bytes > 0 {
+		err := Write(db, func(tx *gorm.DB) error {
+			return tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "month"}, {Name: "provider"}, {Name: "kind"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"bytes":      gorm.Expr("bytes + ?", bytes),
+					"updated_at": at,
+				}),
+			}).Create(&BandwidthUsage{
+				Month:     month,
+				Provider:  provider,
+				Kind:      kind,
+				Bytes:     bytes,
+				UpdatedAt: at,
+			}).Error
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return BandwidthTotal(db, month)
+}
+
+// BandwidthTotal returns the bytes streamed and downloaded in a month
+func BandwidthTotal(db *gorm.DB, month string) (int64, error) {
+	var total int64
+	err := db.Model(&BandwidthUsage{}).
+		Where("month = ?", month).
+		Select("COALESCE(SUM(bytes), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// ListBandwidth returns the usage of the last months (all when months <= 0),
+// newest month first and by provider
+func ListBandwidth(db *gorm.DB, months int) ([]BandwidthUsage, error) {
+	query := db.Order("month DESC, provider, kind")
+	if months > 0 {
+		var recent []string
+		if err := db.Model(&BandwidthUsage{}).Distinct("month").Order("month DESC").Limit(months).Pluck("month", &recent).Error; err != nil {
+			return nil, err
+		}
+		query = query.Where("month IN ?", recent)
+	}
+
+	var usage []BandwidthUsage
+	if err := query.Find(&usage).Error; err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBandwidth(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	march := time.Date(2026, 3, 14, 20, 0, 0, 0, time.Local)
+	april := time.Date(2026, 4, 2, 20, 0, 0, 0, time.Local)
+
+	total, err := AddBandwidth(db, "allanime", BandwidthStream, 100, march)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), total)
+
+	total, err = AddBandwidth(db, "allanime", BandwidthStream, 50, march)
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), total)
+
+	total, err = AddBandwidth(db, "hianime", BandwidthDownload, 1000, march)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1150), total)
+
+	total, err = AddBandwidth(db, "allanime", BandwidthDownload, 7, april)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), total, "months are counted apart")
+
+	total, err = AddBandwidth(db, "allanime", BandwidthStream, 0, march)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1150), total)
+
+	usage, err := ListBandwidth(db, 0)
+	require.NoError(t, err)
+	require.Len(t, usage, 3)
+	assert.Equal(t, "2026-04", usage[0].Month)
+	assert.Equal(t, "2026-03", usage[1].Month)
+	assert.Equal(t, "allanime", usage[1].Provider)
+	assert.Equal(t, int64(150), usage[1].Bytes)
+
+	usage, err = ListBandwidth(db, 1)
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, "2026-04", usage[0].Month)
+
+	_, err = AddBandwidth(db, "", BandwidthStream, 1, march)
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CoWatchSync is the furthest completed episode of an AniList entry watched
+// with a co-watch profile that hasn't been pushed to AniList yet
+type CoWatchSync struct {
+	AniListID  int `gorm:"column:anilist_id"`
+	MediaTitle string
+	Episode    int
+}
+
+// AddCoWatchProfile creates a co-watch profile. Names are unique regardless
+// of case.
+func AddCoWatchProfile(db *gorm.DB, name string) (*CoWatchProfile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("co-watch profile name is empty")
+	}
+
+	existing, err := FindCoWatchProfile(db, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("co-watch profile %q already exists", existing.Name)
+	}
+
+	profile := CoWatchProfile{Name: name, CreatedAt: time.Now()}
+	if err := Write(db, func(tx *gorm.DB) error { return tx.Create(&profile).Error }); err != nil {
+		return nil, fmt.Errorf("failed to create co-watch profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// FindCoWatchProfile returns the co-watch profile with the given name,
+// ignoring case, or nil when there is none
+func FindCoWatchProfile(db *gorm.DB, name string) (*CoWatchProfile, error) {
+	var profile CoWatchProfile
+	err := db.Where("LOWER(name) = ?", strings.ToLower(strings.TrimSpace(name))).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListCoWatchProfiles returns the co-watch profiles sorted by name
+func ListCoWatchProfiles(db *gorm.DB) ([]CoWatchProfile, error) {
+	var profiles []CoWatchProfile
+	if err := db.Order("name").Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// RemoveCoWatchProfile deletes a co-watch profile and its progress
+func RemoveCoWatchProfile(db *gorm.DB, id uint) error {
+	return Write(db, func(tx *gorm.DB) error {
+		if err := tx.Where("profile_id = ?", id).Delete(&CoWatchProgress{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&CoWatchProfile{}, id).Error
+	})
+}
+
+// SaveCoWatchProgress records the progress of a co-watch profile in an
+// episode. An episode stays completed once it has been finished.
+func SaveCoWatchProgress(db *gorm.DB, progress CoWatchProgress) error {
+	return Write(db, func(tx *gorm.DB) error {
+		var existing CoWatchProgress
+		err := tx.Where("profile_id = ? AND media_id = ? AND season = ? AND episode = ?",
+			progress.ProfileID, progress.MediaID, progress.Season, progress.Episode).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(&progress).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		progress.ID = existing.ID
+		progress.Completed = progress.Completed || existing.Completed
+		progress.Synced = existing.Synced
+		return tx.Save(&progress).Error
+	})
+}
+
+// CoWatchResume returns the unfinished progress of a co-watch profile in an
+// episode, or nil when there is none
+func CoWatchResume(db *gorm.DB, profileID uint, mediaID string, season, episode int) (*CoWatchProgress, error) {
+	var progress CoWatchProgress
+	err := db.Where("profile_id = ? AND media_id = ? AND season = ? AND episode = ? AND completed = false",
+		profileID, mediaID, season, episode).
+		First(&progress).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// CoWatchNextEpisode returns the episode a co-watch profile should watch
+// next, or 0 when it hasn't watched the media yet
+func CoWatchNextEpisode(db *gorm.DB, profileID uint, mediaID string) (int, error) {
+	var latest CoWatchProgress
+	err := db.Where("profile_id = ? AND media_id = ?", profileID, mediaID).
+		Order("season DESC, episode DESC").
+		First(&latest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if latest.Completed {
+		return latest.Episode + 1, nil
+	}
+	return latest.Episode, nil
+}
+
+// PendingCoWatchSync returns, per AniList entry, the furthest episode a
+// co-watch profile completed that hasn't been pushed to AniList
+func PendingCoWatchSync(db *gorm.DB, profileID uint) ([]CoWatchSync, error) {
+	var pending []CoWatchSync
+	err := db.Model(&CoWatchProgress{}).
+		Select("anilist_id, MAX(media_title) AS media_title, MAX(episode) AS episode").
+		Where("profile_id = ? AND anilist_id IS NOT NULL AND completed = true AND synced = false", profileID).
+		Group("anilist_id").
+		Order("media_title").
+		Scan(&pending).Error
+	if err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// MarkCoWatchSynced marks the completed episodes of an AniList entry up to
+// episode as pushed to AniList
+func MarkCoWatchSynced(db *gorm.DB, profileID uint, anilistID, episode int) error {
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Model(&CoWatchProgress{}).
+			Where("profile_id = ? AND anilist_id = ? AND episode <= ? AND completed = true", profileID, anilistID, episode).
+			Update("synced", true).Error
+	})
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoWatchProfiles(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	alice, err := AddCoWatchProfile(db, " Alice ")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", alice.Name)
+
+	_, err = AddCoWatchProfile(db, "alice")
+	assert.Error(t, err)
+	_, err = AddCoWatchProfile(db, "  ")
+	assert.Error(t, err)
+
+	found, err := FindCoWatchProfile(db, "ALICE")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, alice.ID, found.ID)
+
+	_, err = AddCoWatchProfile(db, "Bob")
+	require.NoError(t, err)
+	profiles, err := ListCoWatchProfiles(db)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "Alice", profiles[0].Name)
+
+	require.NoError(t, SaveCoWatchProgress(db, CoWatchProgress{
+		ProfileID: alice.ID, MediaID: "frieren", MediaTitle: "Frieren", MediaType: "anime", Episode: 1, WatchedAt: time.Now(),
+	}))
+	require.NoError(t, RemoveCoWatchProfile(db, alice.ID))
+	found, err = FindCoWatchProfile(db, "alice")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	var left int64
+	require.NoError(t, db.Model(&CoWatchProgress{}).Count(&left).Error)
+	assert.Zero(t, left)
+}
+
+func TestCoWatchProgress(t *testing.T) {
+	db := newTrashTestDB(t)
+	alice, err := AddCoWatchProfile(db, "Alice")
+	require.NoError(t, err)
+
+	anilistID := 154587
+	save := func(episode, seconds int, completed bool) {
+		require.NoError(t, SaveCoWatchProgress(db, CoWatchProgress{
+			ProfileID: alice.ID, MediaID: "anilist:154587", MediaTitle: "Frieren", MediaType: "anime",
+			AniListID: &anilistID, Episode: episode, ProgressSeconds: seconds, TotalSeconds: 1440,
+			ProgressPercent: float64(seconds) / 14.4, Completed: completed, WatchedAt: time.Now(),
+		}))
+	}
+
+	next, err := CoWatchNextEpisode(db, alice.ID, "anilist:154587")
+	require.NoError(t, err)
+	assert.Zero(t, next)
+
+	save(1, 1440, true)
+	save(2, 1440, true)
+	save(3, 600, false)
+
+	next, err = CoWatchNextEpisode(db, alice.ID, "anilist:154587")
+	require.NoError(t, err)
+	assert.Equal(t, 3, next)
+
+	resume, err := CoWatchResume(db, alice.ID, "anilist:154587", 0, 3)
+	require.NoError(t, err)
+	require.NotNil(t, resume)
+	assert.Equal(t, 600, resume.ProgressSeconds)
+
+	// Rewatching part of a finished episode keeps it completed
+	save(2, 100, false)
+	resume, err = CoWatchResume(db, alice.ID, "anilist:154587", 0, 2)
+	require.NoError(t, err)
+	assert.Nil(t, resume)
+
+	// Solo history is untouched
+	var history int64
+	require.NoError(t, db.Model(&History{}).Count(&history).Error)
+	assert.Zero(t, history)
+
+	pending, err := PendingCoWatchSync(db, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, CoWatchSync{AniListID: anilistID, MediaTitle: "Frieren", Episode: 2}, pending[0])
+
+	require.NoError(t, MarkCoWatchSynced(db, alice.ID, anilistID, 2))
+	pending, err = PendingCoWatchSync(db, alice.ID)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
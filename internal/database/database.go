@@ -2,20 +2,81 @@ package database
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
 	"github.com/glebarez/sqlite"
 	"github.com/justchokingaround/greg/internal/config"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// Supported database drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
 // DB is the global database instance
 var DB *gorm.DB
 
 // Init initializes the database connection
 func Init(cfg *config.DatabaseConfig) error {
+	switch cfg.Driver {
+	case "", DriverSQLite:
+		return initSQLite(cfg)
+	case DriverPostgres, DriverMySQL:
+		return initServer(cfg)
+	default:
+		return fmt.Errorf("unsupported database driver: %s (expected sqlite, postgres or mysql)", cfg.Driver)
+	}
+}
+
+// initServer connects to a shared PostgreSQL or MySQL server.
+// The SQL file migrations only patch old SQLite databases, so server
+// backends rely on GORM AutoMigrate alone.
+func initServer(cfg *config.DatabaseConfig) error {
+	if cfg.DSN == "" {
+		return fmt.Errorf("database.dsn is required for the %s driver", cfg.Driver)
+	}
+
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case DriverPostgres:
+		dialector = postgres.Open(cfg.DSN)
+	case DriverMySQL:
+		dialector = mysql.Open(cfg.DSN)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", cfg.Driver, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxConnections)
+	sqlDB.SetMaxIdleConns(cfg.MaxConnections / 2)
+
+	if err := Migrate(db); err != nil {
+		return fmt.Errorf("failed to run auto migrations: %w", err)
+	}
+
+	DB = db
+	return nil
+}
+
+// initSQLite opens the local SQLite database file
+func initSQLite(cfg *config.DatabaseConfig) error {
 	// Ensure directory exists
 	dbDir := filepath.Dir(cfg.Path)
 	if err := os.MkdirAll(dbDir, 0755); err != nil {
@@ -28,7 +89,8 @@ func Init(cfg *config.DatabaseConfig) error {
 	}
 
 	// Open database connection
-	db, err := gorm.Open(sqlite.Open(cfg.Path), gormConfig)
+	dsn := sqliteDSN(cfg)
+	db, err := gorm.Open(sqlite.Open(dsn), gormConfig)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -43,17 +105,7 @@ func Init(cfg *config.DatabaseConfig) error {
 	sqlDB.SetMaxOpenConns(cfg.MaxConnections)
 	sqlDB.SetMaxIdleConns(cfg.MaxConnections / 2)
 
-	// Enable WAL mode for better concurrency
-	if cfg.WALMode {
-		if err := db.Exec("PRAGMA journal_mode=WAL").Error; err != nil {
-			return fmt.Errorf("failed to enable WAL mode: %w", err)
-		}
-	}
-
-	// Enable foreign keys
-	if err := db.Exec("PRAGMA foreign_keys=ON").Error; err != nil {
-		return fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
+	// WAL mode, busy timeout and foreign keys are applied per connection via the DSN
 
 	// Auto vacuum
 	if cfg.AutoVacuum {
@@ -74,7 +126,7 @@ func Init(cfg *config.DatabaseConfig) error {
 	}
 
 	// Re-open with fresh GORM connection
-	db, err = gorm.Open(sqlite.Open(cfg.Path), gormConfig)
+	db, err = gorm.Open(sqlite.Open(dsn), gormConfig)
 	if err != nil {
 		return fmt.Errorf("failed to re-open database: %w", err)
 	}
@@ -88,16 +140,6 @@ func Init(cfg *config.DatabaseConfig) error {
 	sqlDB.SetMaxOpenConns(cfg.MaxConnections)
 	sqlDB.SetMaxIdleConns(cfg.MaxConnections / 2)
 
-	if cfg.WALMode {
-		if err := db.Exec("PRAGMA journal_mode=WAL").Error; err != nil {
-			return fmt.Errorf("failed to enable WAL mode: %w", err)
-		}
-	}
-
-	if err := db.Exec("PRAGMA foreign_keys=ON").Error; err != nil {
-		return fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
-
 	if cfg.AutoVacuum {
 		if err := db.Exec("PRAGMA auto_vacuum=INCREMENTAL").Error; err != nil {
 			return fmt.Errorf("failed to enable auto vacuum: %w", err)
@@ -114,15 +156,44 @@ func Init(cfg *config.DatabaseConfig) error {
 	}
 
 	DB = db
+	startWriteQueue(db, cfg.WriteQueueSize)
 	return nil
 }
 
+// sqliteDSN builds the connection string for the configured database.
+// Pragmas are passed through the DSN rather than executed once, because
+// settings like busy_timeout only apply to the connection they run on and
+// the pool hands out several connections.
+func sqliteDSN(cfg *config.DatabaseConfig) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+
+	if cfg.BusyTimeout > 0 {
+		params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	}
+
+	if cfg.WALMode {
+		params.Add("_pragma", "journal_mode(WAL)")
+		// NORMAL is durable in WAL mode and avoids an fsync on every commit
+		params.Add("_pragma", "synchronous(NORMAL)")
+	}
+
+	// Take the write lock when a transaction begins instead of upgrading a
+	// read lock later, which fails immediately with SQLITE_BUSY
+	params.Set("_txlock", "immediate")
+
+	return cfg.Path + "?" + params.Encode()
+}
+
 // Close closes the database connection
 func Close() error {
 	if DB == nil {
 		return nil
 	}
 
+	// Drain pending writes before closing the pool
+	stopWriteQueue()
+
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return err
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SaveExtension records an installed extension, replacing an earlier version
+func SaveExtension(db *gorm.DB, ext *Extension) error {
+	return Write(db, func(tx *gorm.DB) error { return tx.Save(ext).Error })
+}
+
+// GetExtension returns an installed extension
+func GetExtension(db *gorm.DB, name string) (*Extension, error) {
+	var ext Extension
+	if err := db.Where("name = ?", name).First(&ext).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("extension not installed: %s", name)
+		}
+		return nil, err
+	}
+	return &ext, nil
+}
+
+// ListExtensions returns the installed extensions by name
+func ListExtensions(db *gorm.DB) ([]Extension, error) {
+	var exts []Extension
+	err := db.Order("name ASC").Find(&exts).Error
+	return exts, err
+}
+
+// RemoveExtension forgets an installed extension
+func RemoveExtension(db *gorm.DB, name string) error {
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Where("name = ?", name).Delete(&Extension{}).Error
+	})
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ToggleFavorite stars fav, or unstars it when it is already a favorite.
+// Returns whether the title is now a favorite.
+func ToggleFavorite(db *gorm.DB, fav Favorite) (bool, error) {
+	starred := false
+	err := Write(db, func(tx *gorm.DB) error {
+		var existing Favorite
+		err := tx.Where("provider_name = ? AND media_id = ?", fav.ProviderName, fav.MediaID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			starred = true
+			return tx.Create(&fav).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&existing).Error
+	})
+	return starred, err
+}
+
+// ListFavorites returns the favorites of a provider with one of mediaTypes
+// (all types when empty), most recently starred first
+func ListFavorites(db *gorm.DB, providerName string, mediaTypes ...string) ([]Favorite, error) {
+	query := db.Where("provider_name = ?", providerName)
+	if len(mediaTypes) > 0 {
+		query = query.Where("media_type IN ?", mediaTypes)
+	}
+	var favs []Favorite
+	if err := query.Order("created_at DESC, id DESC").Find(&favs).Error; err != nil {
+		return nil, err
+	}
+	return favs, nil
+}
+
+// FavoriteIDs returns the media IDs starred on a provider
+func FavoriteIDs(db *gorm.DB, providerName string) (map[string]bool, error) {
+	var ids []string
+	if err := db.Model(&Favorite{}).Where("provider_name = ?", providerName).Pluck("media_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// LatestUnfinished returns the latest unfinished history entry of a media
+// on a provider, or nil when there is none
+func LatestUnfinished(db *gorm.DB, providerName, mediaID string) (*History, error) {
+	var entry History
+	err := db.Where("provider_name = ? AND media_id = ? AND completed = false", providerName, mediaID).
+		Order("watched_at DESC").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
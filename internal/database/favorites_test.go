@@ -0,0 +1,58 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToggleFavorite(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	frieren := Favorite{MediaID: "frieren", ProviderName: "hianime", MediaTitle: "Frieren", MediaType: "anime"}
+	starred, err := ToggleFavorite(db, frieren)
+	require.NoError(t, err)
+	assert.True(t, starred)
+
+	_, err = ToggleFavorite(db, Favorite{MediaID: "dune", ProviderName: "flixhq", MediaTitle: "Dune", MediaType: "movie"})
+	require.NoError(t, err)
+
+	favs, err := ListFavorites(db, "hianime", "anime")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+	assert.Equal(t, "Frieren", favs[0].MediaTitle)
+
+	ids, err := FavoriteIDs(db, "flixhq")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"dune": true}, ids)
+
+	starred, err = ToggleFavorite(db, frieren)
+	require.NoError(t, err)
+	assert.False(t, starred)
+	favs, err = ListFavorites(db, "hianime")
+	require.NoError(t, err)
+	assert.Empty(t, favs)
+}
+
+func TestLatestUnfinished(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	entry, err := LatestUnfinished(db, "hianime", "frieren")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+
+	now := time.Now()
+	require.NoError(t, db.Create(&[]History{
+		{MediaID: "frieren", MediaTitle: "Frieren", MediaType: "anime", Episode: 3, ProviderName: "hianime", WatchedAt: now.Add(-time.Hour)},
+		{MediaID: "frieren", MediaTitle: "Frieren", MediaType: "anime", Episode: 4, ProviderName: "hianime", WatchedAt: now},
+		{MediaID: "frieren", MediaTitle: "Frieren", MediaType: "anime", Episode: 5, ProviderName: "allanime", WatchedAt: now},
+		{MediaID: "frieren", MediaTitle: "Frieren", MediaType: "anime", Episode: 6, ProviderName: "hianime", WatchedAt: now.Add(time.Hour), Completed: true},
+	}).Error)
+
+	entry, err = LatestUnfinished(db, "hianime", "frieren")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, 4, entry.Episode)
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AddFeed stores a new feed
+// Returns an error if the feed URL is already tracked
+func AddFeed(db *gorm.DB, url, name, match string) (*Feed, error) {
+	var existing Feed
+	if err := db.Where("url = ?", url).First(&existing).Error; err == nil {
+		return nil, fmt.Errorf("feed already tracked (#%d)", existing.ID)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	feed := Feed{URL: url, Name: name, Match: match, CreatedAt: time.Now()}
+	if err := Write(db, func(tx *gorm.DB) error { return tx.Create(&feed).Error }); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// ListFeeds returns all tracked feeds in the order they were added
+func ListFeeds(db *gorm.DB) ([]Feed, error) {
+	var feeds []Feed
+	err := db.Order("id ASC").Find(&feeds).Error
+	return feeds, err
+}
+
+// RemoveFeed deletes a feed, identified by ID or URL, along with its seen entries
+func RemoveFeed(db *gorm.DB, idOrURL string) error {
+	var feed Feed
+	query := db.Where("url = ?", idOrURL)
+	if id, err := strconv.ParseUint(idOrURL, 10, 64); err == nil {
+		query = db.Where("id = ?", id)
+	}
+	if err := query.First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("feed not found: %s", idOrURL)
+		}
+		return err
+	}
+
+	return Write(db, func(tx *gorm.DB) error {
+		if err := tx.Where("feed_id = ?", feed.ID).Delete(&FeedItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&feed).Error
+	})
+}
+
+// FeedItemSeen reports whether an entry of the feed was already recorded
+func FeedItemSeen(db *gorm.DB, feedID uint, guid string) (bool, error) {
+	var count int64
+	err := db.Model(&FeedItem{}).Where("feed_id = ? AND guid = ?", feedID, guid).Count(&count).Error
+	return count > 0, err
+}
+
+// FeedEpisodeQueued reports whether a release of the episode was already
+// queued from any feed
+func FeedEpisodeQueued(db *gorm.DB, show string, episode int) (bool, error) {
+	var count int64
+	err := db.Model(&FeedItem{}).Where(&FeedItem{Show: show, Episode: episode, Queued: true}).Count(&count).Error
+	return count > 0, err
+}
+
+// SaveFeedItem records a feed entry as seen
+func SaveFeedItem(db *gorm.DB, item *FeedItem) error {
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	return Write(db, func(tx *gorm.DB) error { return tx.Create(item).Error })
+}
+
+// MarkFeedChecked updates the last check time of a feed
+func MarkFeedChecked(db *gorm.DB, feedID uint, at time.Time) error {
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Model(&Feed{}).Where("id = ?", feedID).Update("last_checked_at", at).Error
+	})
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// SaveProgress records a playback in the history. A completed watch replaces
+// the unfinished entries of the episode; an unfinished one updates the latest
+// unfinished entry instead of adding another.
+func SaveProgress(db *gorm.DB, entry History) error {
+	return Write(db, func(tx *gorm.DB) error {
+		unfinished := tx.Where("media_id = ? AND season = ? AND episode = ? AND completed = false",
+			entry.MediaID, entry.Season, entry.Episode)
+
+		if entry.Completed {
+			if err := unfinished.Delete(&History{}).Error; err != nil {
+				return err
+			}
+			return tx.Create(&entry).Error
+		}
+
+		var existing History
+		err := unfinished.Order("watched_at DESC").First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(&entry).Error
+		} else if err != nil {
+			return err
+		}
+		entry.ID = existing.ID
+		return tx.Save(&entry).Error
+	})
+}
+
+// UnfinishedProgress returns the latest unfinished history entry of an
+// episode, or nil when there is none
+func UnfinishedProgress(db *gorm.DB, mediaID string, season, episode int) (*History, error) {
+	var entry History
+	err := db.Where("media_id = ? AND season = ? AND episode = ? AND completed = false", mediaID, season, episode).
+		Order("watched_at DESC").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CompletedEpisodes returns how many distinct episodes of each of the given
+// AniList entries were watched to completion
+func CompletedEpisodes(db *gorm.DB, anilistIDs []int) (map[int]int, error) {
+	counts := make(map[int]int, len(anilistIDs))
+	if len(anilistIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		AniListID int `gorm:"column:anilist_id"`
+		Count     int
+	}
+	err := db.Model(&History{}).
+		Select("anilist_id, COUNT(DISTINCT episode) AS count").
+		Where("anilist_id IN ? AND completed = true", anilistIDs).
+		Group("anilist_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.AniListID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveProgress(t *testing.T) {
+	db := newTrashTestDB(t)
+	entry := History{MediaID: "https://example.com/a.mp4", MediaTitle: "a", MediaType: "movie", ProviderName: "manual", TotalSeconds: 600}
+
+	entry.ProgressSeconds, entry.WatchedAt = 60, time.Now()
+	require.NoError(t, SaveProgress(db, entry))
+	entry.ProgressSeconds, entry.WatchedAt = 120, time.Now()
+	require.NoError(t, SaveProgress(db, entry))
+
+	unfinished, err := UnfinishedProgress(db, entry.MediaID, 0, 0)
+	require.NoError(t, err)
+	require.NotNil(t, unfinished)
+	assert.Equal(t, 120, unfinished.ProgressSeconds)
+
+	var count int64
+	db.Model(&History{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	entry.ProgressSeconds, entry.Completed = 590, true
+	require.NoError(t, SaveProgress(db, entry))
+
+	unfinished, err = UnfinishedProgress(db, entry.MediaID, 0, 0)
+	require.NoError(t, err)
+	assert.Nil(t, unfinished)
+	db.Model(&History{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCompletedEpisodes(t *testing.T) {
+	db := newTrashTestDB(t)
+	ova, movie := 4, 3
+	for _, entry := range []History{
+		{AniListID: &ova, Episode: 1, Completed: true},
+		{AniListID: &ova, Episode: 1, Completed: true},
+		{AniListID: &ova, Episode: 2, Completed: true},
+		{AniListID: &movie, Episode: 1},
+	} {
+		entry.MediaID, entry.MediaTitle, entry.MediaType, entry.WatchedAt = "show", "Show", "anime", time.Now()
+		require.NoError(t, db.Create(&entry).Error)
+	}
+
+	counts, err := CompletedEpisodes(db, []int{ova, movie})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{ova: 2}, counts)
+}
@@ -49,16 +49,19 @@ func (Statistic) TableName() string {
 
 // SyncQueue represents items waiting to be synced to tracking services
 type SyncQueue struct {
-	ID        uint       `gorm:"primaryKey"`
-	MediaID   string     `gorm:"not null"`
-	AniListID *int       `gorm:""`
-	Episode   int        `gorm:"not null"`
-	Progress  float64    `gorm:"not null"`
-	Status    string     `gorm:""` // watching, completed, etc.
-	Score     *float64   `gorm:""`
-	Synced    bool       `gorm:"default:false;index"`
-	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
-	SyncedAt  *time.Time `gorm:""`
+	ID            uint       `gorm:"primaryKey"`
+	MediaID       string     `gorm:"not null;uniqueIndex:idx_sync_queue_media_episode"`
+	AniListID     *int       `gorm:""`
+	Episode       int        `gorm:"not null;uniqueIndex:idx_sync_queue_media_episode"`
+	Progress      float64    `gorm:"not null"`
+	Status        string     `gorm:""` // watching, completed, etc.
+	Score         *float64   `gorm:""`
+	Synced        bool       `gorm:"default:false;index"`
+	Attempts      int        `gorm:"default:0"`
+	LastError     string     `gorm:""`
+	NextAttemptAt *time.Time `gorm:"index"` // Retry backoff, nil = as soon as possible
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	SyncedAt      *time.Time `gorm:""`
 }
 
 // TableName overrides the table name
@@ -89,12 +92,18 @@ type Download struct {
 	Quality         string     `gorm:"not null"`
 	Provider        string     `gorm:"not null"`
 	Status          string     `gorm:"not null;index"` // queued, downloading, paused, completed, failed
+	Priority        int        `gorm:"default:0"`      // Queue order, higher first
 	Progress        float64    `gorm:"default:0.0"`
 	BytesDownloaded int64      `gorm:"default:0"` // Bytes downloaded
 	TotalBytes      int64      `gorm:"default:0"` // Total bytes
 	Speed           int64      `gorm:"default:0"` // Download speed (bytes/sec)
 	Error           string     `gorm:""`          // Error message if failed
+	Diagnostics     string     `gorm:""`          // JSON request details of the last failure
 	FilePath        string     `gorm:""`
+	ExpectedSize    int64      `gorm:"default:0"` // Size announced by the source, checked after completion
+	ExpectedSeconds int        `gorm:"default:0"` // Duration from the playlist, checked with ffprobe
+	Checksum        string     `gorm:""`          // Expected "crc32:<hex>" or "sha256:<hex>"
+	RemotePath      string     `gorm:""`          // Where the file was uploaded to (empty = not uploaded)
 	CreatedAt       time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
 	StartedAt       *time.Time `gorm:""` // When download started
 	CompletedAt     *time.Time `gorm:""`
@@ -107,13 +116,18 @@ func (Download) TableName() string {
 
 // AniListMapping represents the mapping between AniList media and provider media
 type AniListMapping struct {
-	ID              uint      `gorm:"primaryKey"`
-	AniListID       int       `gorm:"column:anilist_id;not null;uniqueIndex"`
-	ProviderName    string    `gorm:"not null"`
-	ProviderMediaID string    `gorm:"not null"`
-	Title           string    `gorm:"not null"`
-	CreatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	ID              uint   `gorm:"primaryKey"`
+	AniListID       int    `gorm:"column:anilist_id;not null;uniqueIndex"`
+	ProviderName    string `gorm:"not null"`
+	ProviderMediaID string `gorm:"not null"`
+	Title           string `gorm:"not null"`
+	// EpisodeOffset is how many of the provider's episodes come before the
+	// AniList entry's first one, for providers listing a show AniList splits
+	// into cours as one
+	EpisodeOffset  int       `gorm:"default:0"`
+	OffsetVerified bool      `gorm:"default:false"` // The user set or confirmed EpisodeOffset
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 }
 
 // TableName overrides the table name
@@ -125,7 +139,7 @@ func (AniListMapping) TableName() string {
 type AudioPreference struct {
 	ID         uint      `gorm:"primaryKey"`
 	AniListID  int       `gorm:"column:anilist_id;not null;uniqueIndex"`
-	Preference string    `gorm:"not null"` // "dub" or "sub"
+	Preference string    `gorm:"not null"` // "dub", "sub" or a language code ("ja", "en", ...)
 	TrackIndex *int      `gorm:""`         // Optional: last selected mpv track index (advisory only)
 	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
@@ -136,6 +150,240 @@ func (AudioPreference) TableName() string {
 	return "audio_preferences"
 }
 
+// MediaAudioPreference stores per-show audio preferences for content without
+// an AniList ID, keyed by provider media ID
+type MediaAudioPreference struct {
+	ID         uint      `gorm:"primaryKey"`
+	Provider   string    `gorm:"not null;uniqueIndex:idx_media_audio_preference"`
+	MediaID    string    `gorm:"not null;uniqueIndex:idx_media_audio_preference"`
+	Preference string    `gorm:"not null"` // "dub", "sub" or a language code ("ja", "en", ...)
+	TrackIndex *int      `gorm:""`         // Optional: last selected mpv track index (advisory only)
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (MediaAudioPreference) TableName() string {
+	return "media_audio_preferences"
+}
+
+// AudioNormalization overrides player.audio_normalization for a show, keyed
+// like history ("anilist:<id>" or the provider media ID)
+type AudioNormalization struct {
+	ID        uint      `gorm:"primaryKey"`
+	MediaID   string    `gorm:"not null;uniqueIndex"`
+	Mode      string    `gorm:"not null"` // "off", "loudnorm" or "dynaudnorm"
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (AudioNormalization) TableName() string {
+	return "audio_normalizations"
+}
+
+// SubtitleDelay remembers the subtitle delay set in mpv for a series on a
+// provider, keyed like history ("anilist:<id>" or the provider media ID)
+type SubtitleDelay struct {
+	ID        uint      `gorm:"primaryKey"`
+	Provider  string    `gorm:"not null;uniqueIndex:idx_subtitle_delay"`
+	MediaID   string    `gorm:"not null;uniqueIndex:idx_subtitle_delay"`
+	DelayMS   int64     `gorm:"column:delay_ms;not null"` // Negative shows subtitles earlier
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (SubtitleDelay) TableName() string {
+	return "subtitle_delays"
+}
+
+// BandwidthUsage is the data streamed or downloaded from a provider in a
+// month
+type BandwidthUsage struct {
+	ID        uint      `gorm:"primaryKey"`
+	Month     string    `gorm:"not null;uniqueIndex:idx_bandwidth_usage"` // 2006-01
+	Provider  string    `gorm:"not null;uniqueIndex:idx_bandwidth_usage"`
+	Kind      string    `gorm:"not null;uniqueIndex:idx_bandwidth_usage"` // BandwidthStream or BandwidthDownload
+	Bytes     int64     `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (BandwidthUsage) TableName() string {
+	return "bandwidth_usage"
+}
+
+// TrashItem holds rows removed by a destructive action until the trash is
+// purged, so the action can be undone
+type TrashItem struct {
+	ID        uint      `gorm:"primaryKey"`
+	Kind      string    `gorm:"not null;index"` // TrashDownload, TrashHistory or TrashMapping
+	Label     string    `gorm:"not null"`       // Human-readable description
+	Payload   string    `gorm:"type:text;not null"`
+	Files     string    `gorm:"type:text"` // JSON list of trashed files, if any
+	DeletedAt time.Time `gorm:"index"`
+}
+
+// TableName overrides the table name
+func (TrashItem) TableName() string {
+	return "trash"
+}
+
+// Feed is an RSS feed tracked for new releases
+type Feed struct {
+	ID            uint       `gorm:"primaryKey"`
+	URL           string     `gorm:"not null;uniqueIndex"`
+	Name          string     `gorm:""`
+	Match         string     `gorm:""` // Comma-separated show titles to match, in addition to the AniList watching list
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	LastCheckedAt *time.Time `gorm:""`
+}
+
+// TableName overrides the table name
+func (Feed) TableName() string {
+	return "feeds"
+}
+
+// FeedItem records a feed entry that has already been seen
+type FeedItem struct {
+	ID        uint      `gorm:"primaryKey"`
+	FeedID    uint      `gorm:"not null;uniqueIndex:idx_feed_item_guid"`
+	GUID      string    `gorm:"column:guid;not null;uniqueIndex:idx_feed_item_guid"`
+	Title     string    `gorm:"not null"`
+	Link      string    `gorm:""`
+	Show      string    `gorm:""`              // Subscription the entry matched (empty = no match)
+	Episode   int       `gorm:"default:0"`     // Episode number parsed from the title
+	Queued    bool      `gorm:"default:false"` // Whether the release was handed to a download/torrent pipeline
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (FeedItem) TableName() string {
+	return "feed_items"
+}
+
+// ProviderAvailability tracks whether a provider works from the user's
+// region, from health checks and geo-blocked errors
+type ProviderAvailability struct {
+	Provider      string     `gorm:"primaryKey"`
+	Failures      int        `gorm:"default:0"` // Consecutive failed health checks and geo-blocked errors
+	GeoBlocked    int        `gorm:"default:0"` // Geo-blocked errors among those failures
+	LastFailureAt *time.Time `gorm:""`
+	LastSuccessAt *time.Time `gorm:""`
+}
+
+// TableName overrides the table name
+func (ProviderAvailability) TableName() string {
+	return "provider_availability"
+}
+
+// Extension is a scraper or theme installed from the extension repository
+type Extension struct {
+	Name        string    `gorm:"primaryKey"`
+	Kind        string    `gorm:"not null"` // "scraper" or "theme"
+	Version     string    `gorm:"not null"`
+	Pinned      bool      `gorm:"default:false"` // Installed at a requested version, skipped by updates
+	Path        string    `gorm:"not null"`      // Installed file
+	SHA256      string    `gorm:"column:sha256"`
+	InstalledAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time
+}
+
+// TableName overrides the table name
+func (Extension) TableName() string {
+	return "extensions"
+}
+
+// Favorite is a title starred locally, independent of trackers
+type Favorite struct {
+	ID           uint      `gorm:"primaryKey"`
+	MediaID      string    `gorm:"not null;uniqueIndex:idx_favorite_media"`
+	ProviderName string    `gorm:"not null;uniqueIndex:idx_favorite_media"`
+	MediaTitle   string    `gorm:"not null"`
+	MediaType    string    `gorm:"not null;index"` // anime, movie, tv, manga
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (Favorite) TableName() string {
+	return "favorites"
+}
+
+// CoWatchProfile is someone watched together with; the pairing keeps its
+// own progress, apart from history and AniList
+type CoWatchProfile struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName overrides the table name
+func (CoWatchProfile) TableName() string {
+	return "cowatch_profiles"
+}
+
+// CoWatchProgress is the progress of a co-watch profile in an episode
+type CoWatchProgress struct {
+	ID              uint      `gorm:"primaryKey"`
+	ProfileID       uint      `gorm:"not null;uniqueIndex:idx_cowatch_episode"`
+	MediaID         string    `gorm:"not null;uniqueIndex:idx_cowatch_episode"`
+	Season          int       `gorm:"default:0;uniqueIndex:idx_cowatch_episode"`
+	Episode         int       `gorm:"not null;uniqueIndex:idx_cowatch_episode"`
+	MediaTitle      string    `gorm:"not null"`
+	MediaType       string    `gorm:"not null"` // anime, movie, tv
+	AniListID       *int      `gorm:"column:anilist_id;index"`
+	ProviderName    string    `gorm:"index"`
+	ProgressSeconds int       `gorm:"default:0"`
+	TotalSeconds    int       `gorm:"default:0"`
+	ProgressPercent float64   `gorm:"default:0"`
+	Completed       bool      `gorm:"default:false"`
+	Synced          bool      `gorm:"default:false"` // Pushed to AniList by cowatch sync
+	WatchedAt       time.Time `gorm:"not null;index"`
+}
+
+// TableName overrides the table name
+func (CoWatchProgress) TableName() string {
+	return "cowatch_progress"
+}
+
+// PlaylistTrack is an opening or ending song saved to the local playlist
+// while watching
+type PlaylistTrack struct {
+	ID         uint   `gorm:"primaryKey"`
+	MediaTitle string `gorm:"not null;uniqueIndex:idx_playlist_track"`
+	AniListID  *int   `gorm:"column:anilist_id;index"`
+	Episode    int    `gorm:"default:0"`
+	Theme      string `gorm:"not null;uniqueIndex:idx_playlist_track"` // OP1, ED2, ...
+	Song       string `gorm:"not null"`
+	Artist     string
+	AddedAt    time.Time `gorm:"not null;index"`
+}
+
+// TableName overrides the table name
+func (PlaylistTrack) TableName() string {
+	return "playlist_tracks"
+}
+
+// TorrentJob is a torrent handed to an external client, tracked until its
+// files are imported into the library
+type TorrentJob struct {
+	ID          uint       `gorm:"primaryKey"`
+	Client      string     `gorm:"not null"` // qbittorrent or transmission
+	Hash        string     `gorm:"not null;uniqueIndex"`
+	Link        string     `gorm:"not null"`
+	Name        string     `gorm:""`                              // Release title
+	Show        string     `gorm:""`                              // Show the release was matched to (empty = from the file names)
+	Episode     int        `gorm:"default:0"`                     // Episode parsed from the release title
+	Status      string     `gorm:"not null;index;default:active"` // active, imported or failed
+	Error       string     `gorm:""`
+	AddedAt     time.Time  `gorm:"not null"`
+	CompletedAt *time.Time `gorm:""`
+}
+
+// TableName overrides the table name
+func (TorrentJob) TableName() string {
+	return "torrent_jobs"
+}
+
 // Migrate runs database migrations
 func Migrate(db *gorm.DB) error {
 	return db.AutoMigrate(
@@ -146,5 +394,19 @@ func Migrate(db *gorm.DB) error {
 		&Download{},
 		&AniListMapping{},
 		&AudioPreference{},
+		&MediaAudioPreference{},
+		&AudioNormalization{},
+		&SubtitleDelay{},
+		&BandwidthUsage{},
+		&TrashItem{},
+		&Feed{},
+		&FeedItem{},
+		&ProviderAvailability{},
+		&Extension{},
+		&Favorite{},
+		&CoWatchProfile{},
+		&CoWatchProgress{},
+		&PlaylistTrack{},
+		&TorrentJob{},
 	)
 }
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AddPlaylistTrack saves a song to the playlist. Returns false when the
+// theme of that title is already on it.
+func AddPlaylistTrack(db *gorm.DB, track PlaylistTrack) (bool, error) {
+	var count int64
+	if err := db.Model(&PlaylistTrack{}).
+		Where("media_title = ? AND theme = ?", track.MediaTitle, track.Theme).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if track.AddedAt.IsZero() {
+		track.AddedAt = time.Now()
+	}
+	if err := Write(db, func(tx *gorm.DB) error { return tx.Create(&track).Error }); err != nil {
+		return false, fmt.Errorf("failed to add playlist track: %w", err)
+	}
+	return true, nil
+}
+
+// ListPlaylistTracks returns the playlist in the order songs were added
+func ListPlaylistTracks(db *gorm.DB) ([]PlaylistTrack, error) {
+	var tracks []PlaylistTrack
+	if err := db.Order("added_at, id").Find(&tracks).Error; err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// RemovePlaylistTrack deletes a song from the playlist
+func RemovePlaylistTrack(db *gorm.DB, id uint) error {
+	return Write(db, func(tx *gorm.DB) error {
+		result := tx.Delete(&PlaylistTrack{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no playlist track with ID %d", id)
+		}
+		return nil
+	})
+}
+
+// ClearPlaylist deletes every song from the playlist, returning how many
+// there were
+func ClearPlaylist(db *gorm.DB) (int64, error) {
+	var removed int64
+	err := Write(db, func(tx *gorm.DB) error {
+		result := tx.Where("1 = 1").Delete(&PlaylistTrack{})
+		removed = result.RowsAffected
+		return result.Error
+	})
+	return removed, err
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaylistTracks(t *testing.T) {
+	db := newTrashTestDB(t)
+	now := time.Now()
+
+	added, err := AddPlaylistTrack(db, PlaylistTrack{MediaTitle: "One Piece", Theme: "OP1", Song: "We Are!", Artist: "Hiroshi Kitadani", Episode: 3, AddedAt: now})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = AddPlaylistTrack(db, PlaylistTrack{MediaTitle: "One Piece", Theme: "OP1", Song: "We Are!", Episode: 4})
+	require.NoError(t, err)
+	assert.False(t, added, "the same theme is saved once")
+
+	added, err = AddPlaylistTrack(db, PlaylistTrack{MediaTitle: "Frieren", Theme: "OP1", Song: "Yuusha", Artist: "YOASOBI", AddedAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	tracks, err := ListPlaylistTracks(db)
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "We Are!", tracks[0].Song)
+	assert.Equal(t, "Yuusha", tracks[1].Song)
+
+	require.NoError(t, RemovePlaylistTrack(db, tracks[0].ID))
+	assert.Error(t, RemovePlaylistTrack(db, tracks[0].ID))
+
+	removed, err := ClearPlaylist(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	tracks, err = ListPlaylistTracks(db)
+	require.NoError(t, err)
+	assert.Empty(t, tracks)
+}
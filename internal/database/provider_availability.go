@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LikelyGeoBlocked reports whether the provider fails consistently from the
+// user's region
+func (p ProviderAvailability) LikelyGeoBlocked() bool {
+	return p.GeoBlocked >= 2 || p.Failures >= 3
+}
+
+// RecordProviderSuccess ends the failure streak of provider
+func RecordProviderSuccess(db *gorm.DB, provider string) (*ProviderAvailability, error) {
+	return updateProviderAvailability(db, provider, func(p *ProviderAvailability, now time.Time) {
+		p.Failures, p.GeoBlocked = 0, 0
+		p.LastSuccessAt = &now
+	})
+}
+
+// RecordProviderFailure extends the failure streak of provider. geoBlocked
+// marks failures classified as geo-blocked.
+func RecordProviderFailure(db *gorm.DB, provider string, geoBlocked bool) (*ProviderAvailability, error) {
+	return updateProviderAvailability(db, provider, func(p *ProviderAvailability, now time.Time) {
+		p.Failures++
+		if geoBlocked {
+			p.GeoBlocked++
+		}
+		p.LastFailureAt = &now
+	})
+}
+
+// ListProviderAvailability returns the recorded availability of all providers
+func ListProviderAvailability(db *gorm.DB) ([]ProviderAvailability, error) {
+	var list []ProviderAvailability
+	if err := db.Order("provider").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func updateProviderAvailability(db *gorm.DB, provider string, update func(*ProviderAvailability, time.Time)) (*ProviderAvailability, error) {
+	var availability ProviderAvailability
+	err := Write(db, func(tx *gorm.DB) error {
+		err := tx.Where("provider = ?", provider).First(&availability).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			availability = ProviderAvailability{Provider: provider}
+		} else if err != nil {
+			return err
+		}
+		update(&availability, time.Now())
+		return tx.Save(&availability).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &availability, nil
+}
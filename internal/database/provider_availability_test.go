@@ -0,0 +1,39 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderAvailability(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	for i := 0; i < 2; i++ {
+		availability, err := RecordProviderFailure(db, "hianime", false)
+		require.NoError(t, err)
+		assert.False(t, availability.LikelyGeoBlocked())
+	}
+	availability, err := RecordProviderFailure(db, "hianime", false)
+	require.NoError(t, err)
+	assert.True(t, availability.LikelyGeoBlocked(), "three failures in a row")
+
+	availability, err = RecordProviderSuccess(db, "hianime")
+	require.NoError(t, err)
+	assert.False(t, availability.LikelyGeoBlocked())
+	assert.NotNil(t, availability.LastSuccessAt)
+
+	_, err = RecordProviderFailure(db, "flixhq", true)
+	require.NoError(t, err)
+	availability, err = RecordProviderFailure(db, "flixhq", true)
+	require.NoError(t, err)
+	assert.True(t, availability.LikelyGeoBlocked(), "two geo-blocked errors")
+
+	list, err := ListProviderAvailability(db)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "flixhq", list[0].Provider)
+	assert.Equal(t, 2, list[0].GeoBlocked)
+	assert.Equal(t, 0, list[1].Failures)
+}
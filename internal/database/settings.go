@@ -0,0 +1,43 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSetting retrieves a value from the settings key-value store
+// Returns empty string if the key is not set (not an error)
+func GetSetting(db *gorm.DB, key string) (string, error) {
+	var setting Setting
+	err := db.Where(&Setting{Key: key}).First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return setting.Value, nil
+}
+
+// SetSetting stores or updates a value in the settings key-value store
+// Uses an ON CONFLICT clause so it works on SQLite, PostgreSQL and MySQL
+func SetSetting(db *gorm.DB, key, value string) error {
+	setting := Setting{Key: key, Value: value, UpdatedAt: time.Now()}
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&setting).Error
+	})
+}
+
+// DeleteSetting removes a key from the settings store
+// Note: No error if the key doesn't exist (idempotent)
+func DeleteSetting(db *gorm.DB, key string) error {
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Where(&Setting{Key: key}).Delete(&Setting{}).Error
+	})
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSettingsRoundTrip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	value, err := GetSetting(db, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, value)
+
+	require.NoError(t, SetSetting(db, "theme", "dark"))
+	require.NoError(t, SetSetting(db, "theme", "light"))
+
+	value, err = GetSetting(db, "theme")
+	require.NoError(t, err)
+	assert.Equal(t, "light", value)
+
+	require.NoError(t, DeleteSetting(db, "theme"))
+	value, err = GetSetting(db, "theme")
+	require.NoError(t, err)
+	assert.Empty(t, value)
+}
+
+func TestInitValidatesDriver(t *testing.T) {
+	err := Init(&config.DatabaseConfig{Driver: "oracle"})
+	assert.ErrorContains(t, err, "unsupported database driver")
+
+	err = Init(&config.DatabaseConfig{Driver: DriverPostgres})
+	assert.ErrorContains(t, err, "database.dsn is required")
+}
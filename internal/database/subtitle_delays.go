@@ -0,0 +1,46 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSubtitleDelay returns the subtitle delay remembered for a series on a
+// provider, 0 when none is stored
+func GetSubtitleDelay(db *gorm.DB, provider, mediaID string) (time.Duration, error) {
+	var delay SubtitleDelay
+	err := db.Where(&SubtitleDelay{Provider: provider, MediaID: mediaID}).First(&delay).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return time.Duration(delay.DelayMS) * time.Millisecond, nil
+}
+
+// SaveSubtitleDelay remembers the subtitle delay for a series on a provider;
+// a zero delay forgets it
+func SaveSubtitleDelay(db *gorm.DB, provider, mediaID string, delay time.Duration) error {
+	if provider == "" || mediaID == "" {
+		return errors.New("provider and media ID are required")
+	}
+
+	return Write(db, func(tx *gorm.DB) error {
+		if delay == 0 {
+			return tx.Where("provider = ? AND media_id = ?", provider, mediaID).Delete(&SubtitleDelay{}).Error
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "media_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"delay_ms", "updated_at"}),
+		}).Create(&SubtitleDelay{
+			Provider:  provider,
+			MediaID:   mediaID,
+			DelayMS:   delay.Milliseconds(),
+			UpdatedAt: time.Now(),
+		}).Error
+	})
+}
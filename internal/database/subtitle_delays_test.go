@@ -0,0 +1,40 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSubtitleDelay(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	delay, err := GetSubtitleDelay(db, "allanime", "anilist:21")
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	require.NoError(t, SaveSubtitleDelay(db, "allanime", "anilist:21", 500*time.Millisecond))
+	require.NoError(t, SaveSubtitleDelay(db, "allanime", "anilist:21", -1200*time.Millisecond))
+	require.NoError(t, SaveSubtitleDelay(db, "hianime", "anilist:21", 2*time.Second))
+
+	delay, err = GetSubtitleDelay(db, "allanime", "anilist:21")
+	require.NoError(t, err)
+	assert.Equal(t, -1200*time.Millisecond, delay)
+
+	delay, err = GetSubtitleDelay(db, "hianime", "anilist:21")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, delay)
+
+	require.NoError(t, SaveSubtitleDelay(db, "allanime", "anilist:21", 0))
+	delay, err = GetSubtitleDelay(db, "allanime", "anilist:21")
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	assert.Error(t, SaveSubtitleDelay(db, "", "anilist:21", time.Second))
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sync retry backoff: doubles per failed attempt, capped at a day
+const (
+	syncBackoffBase = time.Minute
+	syncBackoffMax  = 24 * time.Hour
+)
+
+// SyncBackoff returns how long to wait before retrying a sync that failed
+// attempts times
+func SyncBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	backoff := syncBackoffBase
+	for i := 1; i < attempts && backoff < syncBackoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, syncBackoffMax)
+}
+
+// QueueSync stores a failed progress update for a later retry. A queued
+// update for the same media and episode is replaced.
+func QueueSync(db *gorm.DB, mediaID string, anilistID *int, episode int, progress float64, syncErr error) error {
+	now := time.Now()
+	next := now.Add(SyncBackoff(1))
+	item := SyncQueue{
+		MediaID:       mediaID,
+		AniListID:     anilistID,
+		Episode:       episode,
+		Progress:      progress,
+		Attempts:      1,
+		NextAttemptAt: &next,
+		CreatedAt:     now,
+	}
+	if syncErr != nil {
+		item.LastError = syncErr.Error()
+	}
+
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "media_id"}, {Name: "episode"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"progress":        item.Progress,
+				"synced":          false,
+				"synced_at":       nil,
+				"attempts":        item.Attempts,
+				"last_error":      item.LastError,
+				"next_attempt_at": item.NextAttemptAt,
+			}),
+		}).Create(&item).Error
+	})
+}
+
+// PendingSyncs returns unsynced updates, oldest first. Unless force is set,
+// updates still waiting out their backoff are skipped.
+func PendingSyncs(db *gorm.DB, force bool) ([]SyncQueue, error) {
+	query := db.Where("synced = ?", false)
+	if !force {
+		query = query.Where("next_attempt_at IS NULL OR next_attempt_at <= ?", time.Now())
+	}
+
+	var items []SyncQueue
+	err := query.Order("created_at ASC").Find(&items).Error
+	return items, err
+}
+
+// CountPendingSyncs returns the number of unsynced updates
+func CountPendingSyncs(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Model(&SyncQueue{}).Where("synced = ?", false).Count(&count).Error
+	return count, err
+}
+
+// MarkSynced records that a queued update went through
+func MarkSynced(db *gorm.DB, id uint) error {
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Model(&SyncQueue{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"synced":     true,
+			"synced_at":  time.Now(),
+			"last_error": "",
+		}).Error
+	})
+}
+
+// MarkSyncFailed records a failed retry and schedules the next one
+func MarkSyncFailed(db *gorm.DB, item SyncQueue, syncErr error) error {
+	attempts := item.Attempts + 1
+	next := time.Now().Add(SyncBackoff(attempts))
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Model(&SyncQueue{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"last_error":      syncErr.Error(),
+			"next_attempt_at": next,
+		}).Error
+	})
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), SyncBackoff(0))
+	assert.Equal(t, time.Minute, SyncBackoff(1))
+	assert.Equal(t, 4*time.Minute, SyncBackoff(3))
+	assert.Equal(t, 24*time.Hour, SyncBackoff(30))
+}
+
+func TestQueueSyncReplacesSameEpisode(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	require.NoError(t, QueueSync(db, "123", nil, 4, 0.5, errors.New("timeout")))
+	require.NoError(t, QueueSync(db, "123", nil, 4, 1.0, errors.New("rate limited")))
+	require.NoError(t, QueueSync(db, "123", nil, 5, 1.0, nil))
+
+	count, err := CountPendingSyncs(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Both are waiting out their first backoff
+	items, err := PendingSyncs(db, false)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	items, err = PendingSyncs(db, true)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, 1.0, items[0].Progress)
+	assert.Equal(t, "rate limited", items[0].LastError)
+}
+
+func TestMarkSyncFailedAndSynced(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	require.NoError(t, QueueSync(db, "123", nil, 4, 1.0, errors.New("timeout")))
+	items, err := PendingSyncs(db, true)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	require.NoError(t, MarkSyncFailed(db, items[0], errors.New("still down")))
+	items, err = PendingSyncs(db, true)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 2, items[0].Attempts)
+	assert.Equal(t, "still down", items[0].LastError)
+	require.NotNil(t, items[0].NextAttemptAt)
+	assert.True(t, items[0].NextAttemptAt.After(time.Now().Add(time.Minute)))
+
+	require.NoError(t, MarkSynced(db, items[0].ID))
+	count, err := CountPendingSyncs(db)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Torrent job statuses
+const (
+	TorrentActive   = "active"
+	TorrentImported = "imported"
+	TorrentFailed   = "failed"
+)
+
+// AddTorrentJob records a torrent handed to a client. A torrent already
+// tracked is started over, so adding it again imports it again.
+func AddTorrentJob(db *gorm.DB, job *TorrentJob) error {
+	if job.AddedAt.IsZero() {
+		job.AddedAt = time.Now()
+	}
+	job.Status = TorrentActive
+
+	var existing TorrentJob
+	err := db.Where("hash = ?", job.Hash).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil {
+		job.ID = existing.ID
+	}
+	return Write(db, func(tx *gorm.DB) error { return tx.Save(job).Error })
+}
+
+// ActiveTorrentJobs returns the torrents not imported yet, oldest first
+func ActiveTorrentJobs(db *gorm.DB) ([]TorrentJob, error) {
+	var jobs []TorrentJob
+	err := db.Where("status = ?", TorrentActive).Order("added_at ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+// ListTorrentJobs returns every tracked torrent, newest first
+func ListTorrentJobs(db *gorm.DB) ([]TorrentJob, error) {
+	var jobs []TorrentJob
+	err := db.Order("added_at DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+// FinishTorrentJob marks a torrent imported, or failed when err is set
+func FinishTorrentJob(db *gorm.DB, id uint, err error) error {
+	now := time.Now()
+	updates := map[string]any{"status": TorrentImported, "error": "", "completed_at": &now}
+	if err != nil {
+		updates["status"] = TorrentFailed
+		updates["error"] = err.Error()
+	}
+	return Write(db, func(tx *gorm.DB) error {
+		return tx.Model(&TorrentJob{}).Where("id = ?", id).Updates(updates).Error
+	})
+}
@@ -0,0 +1,238 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Trash item kinds
+const (
+	TrashDownload = "download"
+	TrashHistory  = "history"
+	TrashMapping  = "mapping"
+)
+
+// TrashedFile is a file moved aside when its download was trashed
+type TrashedFile struct {
+	Original string `json:"original"`
+	Trashed  string `json:"trashed"`
+}
+
+// TrashHistoryEntries moves history entries to the trash
+// Returns nil if none of the entries exist
+func TrashHistoryEntries(db *gorm.DB, label string, ids ...uint) (*TrashItem, error) {
+	var entries []History
+	if err := db.Where("id IN ?", ids).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return trashRows(db, TrashHistory, label, entries, nil, func(tx *gorm.DB) error {
+		return tx.Where("id IN ?", ids).Delete(&History{}).Error
+	})
+}
+
+// TrashAllHistory moves every history entry to the trash
+func TrashAllHistory(db *gorm.DB) (*TrashItem, error) {
+	var entries []History
+	if err := db.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return trashRows(db, TrashHistory, "all history", entries, nil, func(tx *gorm.DB) error {
+		return tx.Where("1 = 1").Delete(&History{}).Error
+	})
+}
+
+// TrashMappingEntry moves the provider mapping of an AniList entry to the trash
+func TrashMappingEntry(db *gorm.DB, anilistID int) (*TrashItem, error) {
+	var mappings []AniListMapping
+	if err := db.Where("anilist_id = ?", anilistID).Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	label := fmt.Sprintf("mapping for AniList #%d", anilistID)
+	if len(mappings) > 0 {
+		label = fmt.Sprintf("mapping %s → %s", mappings[0].Title, mappings[0].ProviderName)
+	}
+	return trashRows(db, TrashMapping, label, mappings, nil, func(tx *gorm.DB) error {
+		return tx.Where("anilist_id = ?", anilistID).Delete(&AniListMapping{}).Error
+	})
+}
+
+// TrashDownloads moves download records to the trash along with files the
+// caller already moved aside
+func TrashDownloads(db *gorm.DB, label string, ids []string, files []TrashedFile) (*TrashItem, error) {
+	var downloads []Download
+	if err := db.Where("id IN ?", ids).Find(&downloads).Error; err != nil {
+		return nil, err
+	}
+	return trashRows(db, TrashDownload, label, downloads, files, func(tx *gorm.DB) error {
+		return tx.Where("id IN ?", ids).Delete(&Download{}).Error
+	})
+}
+
+// trashRows stores rows in a trash item and deletes them in one transaction
+func trashRows[T any](db *gorm.DB, kind, label string, rows []T, files []TrashedFile, remove func(tx *gorm.DB) error) (*TrashItem, error) {
+	if len(rows) == 0 && len(files) == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode trash payload: %w", err)
+	}
+	item := TrashItem{Kind: kind, Label: label, Payload: string(payload), DeletedAt: time.Now()}
+	if len(files) > 0 {
+		encoded, err := json.Marshal(files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode trashed files: %w", err)
+		}
+		item.Files = string(encoded)
+	}
+
+	err = Write(db, func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx *gorm.DB) error {
+			if err := remove(tx); err != nil {
+				return err
+			}
+			return tx.Create(&item).Error
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListTrash returns trash items, most recently deleted first
+func ListTrash(db *gorm.DB) ([]TrashItem, error) {
+	var items []TrashItem
+	err := db.Order("deleted_at DESC").Find(&items).Error
+	return items, err
+}
+
+// RestoreTrash puts the rows and files of a trash item back and removes it
+// from the trash. Rows that were recreated in the meantime are kept.
+func RestoreTrash(db *gorm.DB, id uint) (*TrashItem, error) {
+	var item TrashItem
+	if err := db.First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("trash item not found: %d", id)
+		}
+		return nil, err
+	}
+
+	var restore func(tx *gorm.DB) error
+	var err error
+	switch item.Kind {
+	case TrashDownload:
+		restore, err = restoreRows[Download](item.Payload)
+	case TrashHistory:
+		restore, err = restoreRows[History](item.Payload)
+	case TrashMapping:
+		restore, err = restoreRows[AniListMapping](item.Payload)
+	default:
+		return nil, fmt.Errorf("unknown trash item kind: %s", item.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := trashedFiles(item)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.Original), 0755); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Original, err)
+		}
+		if err := os.Rename(f.Trashed, f.Original); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Original, err)
+		}
+		removeTrashDir(f.Trashed)
+	}
+
+	err = Write(db, func(tx *gorm.DB) error {
+		return tx.Transaction(func(tx *gorm.DB) error {
+			if err := restore(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&item).Error
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore trash item: %w", err)
+	}
+	return &item, nil
+}
+
+// PurgeTrash permanently deletes trash items older than maxAge (all items
+// when maxAge is 0) along with their trashed files
+// Returns the number of purged items
+func PurgeTrash(db *gorm.DB, maxAge time.Duration) (int, error) {
+	query := db.Model(&TrashItem{})
+	if maxAge > 0 {
+		query = query.Where("deleted_at < ?", time.Now().Add(-maxAge))
+	}
+	var items []TrashItem
+	if err := query.Find(&items).Error; err != nil {
+		return 0, err
+	}
+
+	for _, item := range items {
+		files, err := trashedFiles(item)
+		if err != nil {
+			return 0, err
+		}
+		for _, f := range files {
+			if err := os.Remove(f.Trashed); err != nil && !os.IsNotExist(err) {
+				return 0, fmt.Errorf("failed to delete %s: %w", f.Trashed, err)
+			}
+			removeTrashDir(f.Trashed)
+		}
+		if err := Write(db, func(tx *gorm.DB) error { return tx.Delete(&item).Error }); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+// trashedFiles decodes the files of a trash item
+func trashedFiles(item TrashItem) ([]TrashedFile, error) {
+	if item.Files == "" {
+		return nil, nil
+	}
+	var files []TrashedFile
+	if err := json.Unmarshal([]byte(item.Files), &files); err != nil {
+		return nil, fmt.Errorf("failed to decode trashed files: %w", err)
+	}
+	return files, nil
+}
+
+// restoreRows decodes a trash payload and returns a function recreating its
+// rows. Rows whose key was reused in the meantime are skipped.
+func restoreRows[T any](payload string) (func(tx *gorm.DB) error, error) {
+	var rows []T
+	if err := json.Unmarshal([]byte(payload), &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode trash payload: %w", err)
+	}
+	return func(tx *gorm.DB) error {
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	}, nil
+}
+
+// removeTrashDir removes the directory a trashed file was kept in, and the
+// ".trash" directory above it, once they are empty
+func removeTrashDir(trashed string) {
+	dir := filepath.Dir(trashed)
+	if os.Remove(dir) == nil && filepath.Base(filepath.Dir(dir)) == ".trash" {
+		_ = os.Remove(filepath.Dir(dir))
+	}
+}
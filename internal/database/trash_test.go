@@ -0,0 +1,118 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTrashTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+	return db
+}
+
+func TestTrashHistoryRestore(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	entries := []History{
+		{MediaID: "a", MediaTitle: "Frieren", MediaType: "anime", Episode: 1, ProgressSeconds: 10, TotalSeconds: 1400},
+		{MediaID: "a", MediaTitle: "Frieren", MediaType: "anime", Episode: 2, ProgressSeconds: 20, TotalSeconds: 1400},
+	}
+	require.NoError(t, db.Create(&entries).Error)
+
+	item, err := TrashHistoryEntries(db, "Frieren", entries[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, TrashHistory, item.Kind)
+
+	var count int64
+	db.Model(&History{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	_, err = RestoreTrash(db, item.ID)
+	require.NoError(t, err)
+
+	var restored History
+	require.NoError(t, db.First(&restored, entries[0].ID).Error)
+	assert.Equal(t, 1, restored.Episode)
+
+	items, err := ListTrash(db)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestTrashAllHistoryEmpty(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	item, err := TrashAllHistory(db)
+	require.NoError(t, err)
+	assert.Nil(t, item)
+}
+
+func TestTrashMappingRestoreKeepsNewerMapping(t *testing.T) {
+	db := newTrashTestDB(t)
+
+	require.NoError(t, db.Create(&AniListMapping{AniListID: 5, ProviderName: "allanime", ProviderMediaID: "old", Title: "Show"}).Error)
+
+	item, err := TrashMappingEntry(db, 5)
+	require.NoError(t, err)
+	require.NotNil(t, item)
+
+	// A new mapping made after the delete wins over the trashed one
+	require.NoError(t, db.Create(&AniListMapping{AniListID: 5, ProviderName: "hianime", ProviderMediaID: "new", Title: "Show"}).Error)
+
+	_, err = RestoreTrash(db, item.ID)
+	require.NoError(t, err)
+
+	var mappings []AniListMapping
+	require.NoError(t, db.Find(&mappings).Error)
+	require.Len(t, mappings, 1)
+	assert.Equal(t, "new", mappings[0].ProviderMediaID)
+}
+
+func TestTrashDownloadFiles(t *testing.T) {
+	db := newTrashTestDB(t)
+	dir := t.TempDir()
+
+	original := filepath.Join(dir, "Show", "Show - 01.mkv")
+	trashed := filepath.Join(dir, ".trash", "task1", "Show - 01.mkv")
+	require.NoError(t, os.MkdirAll(filepath.Dir(trashed), 0755))
+	require.NoError(t, os.WriteFile(trashed, []byte("video"), 0644))
+
+	require.NoError(t, db.Create(&Download{ID: "task1", MediaID: "m", MediaTitle: "Show", MediaType: "anime", Episode: 1, Quality: "1080p", Provider: "allanime", Status: "completed", FilePath: original}).Error)
+
+	item, err := TrashDownloads(db, "Show - 01", []string{"task1"}, []TrashedFile{{Original: original, Trashed: trashed}})
+	require.NoError(t, err)
+
+	_, err = RestoreTrash(db, item.ID)
+	require.NoError(t, err)
+	assert.FileExists(t, original)
+	assert.NoDirExists(t, filepath.Join(dir, ".trash"))
+
+	item, err = TrashDownloads(db, "Show - 01", []string{"task1"}, []TrashedFile{{Original: original, Trashed: trashed}})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(trashed), 0755))
+	require.NoError(t, os.Rename(original, trashed))
+
+	// Recent items survive a purge with a retention period
+	n, err := PurgeTrash(db, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = PurgeTrash(db, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoFileExists(t, trashed)
+
+	_, err = RestoreTrash(db, item.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// writeQueue serializes database writes onto a single goroutine.
+// SQLite allows only one writer at a time; funnelling the downloader,
+// playback sync and TUI writes through one goroutine avoids "database is
+// locked" errors when they happen to fire together.
+type writeQueue struct {
+	db  *gorm.DB
+	ops chan writeOp
+	wg  sync.WaitGroup
+
+	mu     sync.Mutex // Held while sending on ops, so close can't race a send
+	closed bool
+}
+
+// writeOp is a single queued write and the channel its result is sent on
+type writeOp struct {
+	fn     func(tx *gorm.DB) error
+	result chan error
+}
+
+var (
+	queueMu sync.RWMutex
+	queue   *writeQueue
+)
+
+// newWriteQueue creates a write queue and starts its worker goroutine
+func newWriteQueue(db *gorm.DB, size int) *writeQueue {
+	if size <= 0 {
+		size = 64
+	}
+
+	q := &writeQueue{
+		db:  db,
+		ops: make(chan writeOp, size),
+	}
+
+	q.wg.Add(1)
+	go q.run()
+
+	return q
+}
+
+// run executes queued writes one at a time until the queue is closed
+func (q *writeQueue) run() {
+	defer q.wg.Done()
+
+	for op := range q.ops {
+		op.result <- op.fn(q.db)
+	}
+}
+
+// do enqueues fn and blocks until it has been executed. Once the queue is
+// closed fn runs directly.
+func (q *writeQueue) do(fn func(tx *gorm.DB) error) error {
+	result := make(chan error, 1)
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return fn(q.db)
+	}
+	// A full queue blocks here until the worker takes an op, which it does
+	// without q.mu
+	q.ops <- writeOp{fn: fn, result: result}
+	q.mu.Unlock()
+	return <-result
+}
+
+// close stops accepting writes and waits for pending ones to finish
+func (q *writeQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	close(q.ops)
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+// startWriteQueue installs the global write queue for db
+func startWriteQueue(db *gorm.DB, size int) {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	if queue != nil {
+		queue.close()
+	}
+	queue = newWriteQueue(db, size)
+}
+
+// stopWriteQueue drains and removes the global write queue
+func stopWriteQueue() {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	if queue != nil {
+		queue.close()
+		queue = nil
+	}
+}
+
+// Write runs fn against db through the serialized write queue.
+// When db is not the global connection (e.g. an in-memory test database)
+// or the queue is not running, fn is executed directly.
+//
+// fn runs on the queue's goroutine and must not call Write itself: the
+// nested write would wait for the goroutine that is waiting for it.
+func Write(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	// Not held while the write waits, so stopping the queue isn't blocked
+	// by writes queued behind a full channel
+	queueMu.RLock()
+	q := queue
+	queueMu.RUnlock()
+
+	if q == nil || q.db != db {
+		return fn(db)
+	}
+	return q.do(fn)
+}
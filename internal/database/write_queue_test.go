@@ -0,0 +1,123 @@
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSQLiteDSN(t *testing.T) {
+	cfg := &config.DatabaseConfig{
+		Path:        "/tmp/greg.db",
+		WALMode:     true,
+		BusyTimeout: 5 * time.Second,
+	}
+
+	dsn := sqliteDSN(cfg)
+	assert.Contains(t, dsn, "busy_timeout%285000%29")
+	assert.Contains(t, dsn, "journal_mode%28WAL%29")
+	assert.Contains(t, dsn, "_txlock=immediate")
+
+	cfg.WALMode = false
+	assert.NotContains(t, sqliteDSN(cfg), "journal_mode")
+}
+
+func TestWriteQueueSerializesConcurrentWrites(t *testing.T) {
+	cfg := &config.DatabaseConfig{
+		Path:        filepath.Join(t.TempDir(), "greg.db"),
+		WALMode:     true,
+		BusyTimeout: time.Second,
+	}
+
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(cfg)), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	startWriteQueue(db, 4)
+	defer stopWriteQueue()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := Write(db, func(tx *gorm.DB) error {
+				return tx.Create(&Setting{Key: fmt.Sprintf("key-%d", i), Value: "v"}).Error
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	var count int64
+	require.NoError(t, db.Model(&Setting{}).Count(&count).Error)
+	assert.Equal(t, int64(50), count)
+}
+
+func TestStopWriteQueueWithWritesWaiting(t *testing.T) {
+	cfg := &config.DatabaseConfig{
+		Path:        filepath.Join(t.TempDir(), "greg.db"),
+		BusyTimeout: time.Second,
+	}
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(cfg)), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	startWriteQueue(db, 1)
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := Write(db, func(tx *gorm.DB) error {
+				<-release
+				return tx.Create(&Setting{Key: fmt.Sprintf("key-%d", i), Value: "v"}).Error
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	// The first write holds the worker and the channel fills up behind it
+	time.Sleep(50 * time.Millisecond)
+	stopped := make(chan struct{})
+	go func() {
+		stopWriteQueue()
+		close(stopped)
+	}()
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopping the write queue deadlocked")
+	}
+	wg.Wait()
+
+	var count int64
+	require.NoError(t, db.Model(&Setting{}).Count(&count).Error)
+	assert.Equal(t, int64(5), count, "writes waiting on the queue still run")
+}
+
+func TestWriteFallsBackWithoutQueue(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(db))
+
+	err = Write(db, func(tx *gorm.DB) error {
+		return tx.Create(&Setting{Key: "k", Value: "v"}).Error
+	})
+	require.NoError(t, err)
+
+	var setting Setting
+	require.NoError(t, db.First(&setting, "key = ?", "k").Error)
+	assert.Equal(t, "v", setting.Value)
+}
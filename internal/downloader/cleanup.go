@@ -0,0 +1,284 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"gorm.io/gorm"
+)
+
+// Cleanup actions (downloads.cleanup.action)
+const (
+	CleanupDelete  = "delete"  // Move to the trash, purged after database.trash_retention
+	CleanupArchive = "archive" // Move to downloads.cleanup.archive_path
+)
+
+// How often the manager applies the cleanup policy while running
+const cleanupInterval = 6 * time.Hour
+
+// CleanupCandidate is a completed download whose episode was watched long
+// enough ago to be cleaned up
+type CleanupCandidate struct {
+	Task      DownloadTask
+	WatchedAt time.Time
+	Size      int64  // Bytes on disk
+	Target    string // Archive destination, empty when deleting
+}
+
+// CleanupReport lists what a cleanup run removed, or would remove when DryRun
+// is set
+type CleanupReport struct {
+	Action     string
+	DryRun     bool
+	Candidates []CleanupCandidate
+	Excluded   []string // Shows skipped because of downloads.cleanup.exclude
+	Freed      int64    // Bytes removed from the download directory
+	Trash      *database.TrashItem
+}
+
+// historyKey identifies an episode in the watch history
+type historyKey struct {
+	mediaID string
+	season  int
+	episode int
+}
+
+// Cleanup applies downloads.cleanup: completed downloads whose episode was
+// last marked completed in the history more than cleanup.days ago are moved
+// to the trash or archived. With dryRun nothing is changed and the report
+// lists what would be. Returns an empty report when the policy is disabled.
+func (m *Manager) Cleanup(ctx context.Context, dryRun bool) (*CleanupReport, error) {
+	m.mu.RLock()
+	policy := m.config.Cleanup
+	downloadDir := m.config.Path
+	m.mu.RUnlock()
+
+	action := strings.ToLower(policy.Action)
+	if action == "" {
+		action = CleanupDelete
+	}
+	if action != CleanupDelete && action != CleanupArchive {
+		return nil, fmt.Errorf("invalid downloads.cleanup.action %q: must be %q or %q", policy.Action, CleanupDelete, CleanupArchive)
+	}
+
+	report := &CleanupReport{Action: action, DryRun: dryRun}
+	if policy.Days <= 0 {
+		return report, nil
+	}
+
+	archiveDir := policy.ArchivePath
+	if archiveDir == "" {
+		archiveDir = filepath.Join(downloadDir, "archive")
+	}
+
+	var downloads []database.Download
+	if err := m.db.WithContext(ctx).
+		Where("status = ? AND file_path <> ''", string(StatusCompleted)).
+		Order("media_title, season, episode").
+		Find(&downloads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load completed downloads: %w", err)
+	}
+	if len(downloads) == 0 {
+		return report, nil
+	}
+
+	watched, err := m.lastCompleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-time.Duration(policy.Days) * 24 * time.Hour)
+	excluded := make(map[string]bool)
+	for _, download := range downloads {
+		watchedAt, ok := watched[historyKey{download.MediaID, download.Season, download.Episode}]
+		if !ok || watchedAt.After(cutoff) {
+			continue
+		}
+		if isExcluded(policy.Exclude, download) {
+			if !excluded[download.MediaTitle] {
+				excluded[download.MediaTitle] = true
+				report.Excluded = append(report.Excluded, download.MediaTitle)
+			}
+			continue
+		}
+
+		candidate := CleanupCandidate{
+			Task:      m.downloadToTask(download),
+			WatchedAt: watchedAt,
+			Size:      download.TotalBytes,
+		}
+		if info, err := os.Stat(download.FilePath); err == nil {
+			candidate.Size = info.Size()
+		}
+		if action == CleanupArchive {
+			if isWithin(archiveDir, download.FilePath) {
+				continue // Archived by an earlier run
+			}
+			rel, err := filepath.Rel(downloadDir, download.FilePath)
+			if err != nil || !filepath.IsLocal(rel) {
+				rel = filepath.Base(download.FilePath)
+			}
+			candidate.Target = filepath.Join(archiveDir, rel)
+		}
+		report.Candidates = append(report.Candidates, candidate)
+	}
+
+	if dryRun || len(report.Candidates) == 0 {
+		for _, c := range report.Candidates {
+			report.Freed += c.Size
+		}
+		return report, nil
+	}
+
+	if action == CleanupDelete {
+		ids := make([]string, 0, len(report.Candidates))
+		for _, c := range report.Candidates {
+			ids = append(ids, c.Task.ID)
+			report.Freed += c.Size
+		}
+		label := fmt.Sprintf("cleanup of %d watched episode(s)", len(ids))
+		item, err := m.TrashTasks(ctx, label, ids...)
+		if err != nil {
+			return nil, err
+		}
+		report.Trash = item
+		return report, nil
+	}
+
+	for _, c := range report.Candidates {
+		if err := moveFile(c.Task.OutputPath, c.Target); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return report, fmt.Errorf("failed to archive %s: %w", c.Task.OutputPath, err)
+		}
+		if err := database.Write(m.db, func(tx *gorm.DB) error {
+			return tx.Model(&database.Download{}).Where("id = ?", c.Task.ID).Update("file_path", c.Target).Error
+		}); err != nil {
+			return report, fmt.Errorf("failed to update archived download: %w", err)
+		}
+		report.Freed += c.Size
+	}
+	return report, nil
+}
+
+// lastCompleted returns when each episode was last marked completed
+func (m *Manager) lastCompleted(ctx context.Context) (map[historyKey]time.Time, error) {
+	var entries []database.History
+	if err := m.db.WithContext(ctx).
+		Select("media_id", "season", "episode", "watched_at").
+		Where("completed = ?", true).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load watch history: %w", err)
+	}
+
+	watched := make(map[historyKey]time.Time, len(entries))
+	for _, entry := range entries {
+		key := historyKey{entry.MediaID, entry.Season, entry.Episode}
+		if entry.WatchedAt.After(watched[key]) {
+			watched[key] = entry.WatchedAt
+		}
+	}
+	return watched, nil
+}
+
+// runCleanup applies the cleanup policy now and every cleanupInterval until
+// the manager stops
+func (m *Manager) runCleanup(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		report, err := m.Cleanup(ctx, false)
+		if err != nil {
+			m.logger.Warn("download cleanup failed", "error", err)
+		} else if len(report.Candidates) > 0 {
+			m.logger.Info("cleaned up watched downloads",
+				"action", report.Action, "count", len(report.Candidates), "freed", report.Freed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isExcluded reports whether a show opted out of cleanup by title or media ID
+func isExcluded(exclude []string, download database.Download) bool {
+	for _, show := range exclude {
+		if strings.EqualFold(show, download.MediaTitle) || show == download.MediaID {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is inside dir
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// moveFile renames src to dst, copying across filesystems when needed
+func moveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	} else if errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// linkFile hard links src at dst, copying when the filesystem doesn't allow
+// it, and leaves src in place
+func linkFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	} else if errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return copyFile(src, dst)
+}
+
+// copyFile copies src to dst, removing dst when the copy fails
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// ClipFormat is the file format a clip is rendered to
+type ClipFormat string
+
+const (
+	ClipGIF  ClipFormat = "gif"  // Palette-optimized, no audio
+	ClipWebM ClipFormat = "webm" // VP9 video with Opus audio
+)
+
+// ErrFFmpegRequired is returned when a clip is added without ffmpeg installed
+var ErrFFmpegRequired = errors.New("ffmpeg is required to export clips")
+
+// Clip is a scene cut from a stream and rendered for sharing
+type Clip struct {
+	Start   time.Duration `json:"start"`
+	End     time.Duration `json:"end"`
+	Format  ClipFormat    `json:"format"`
+	Width   int           `json:"width,omitempty"` // 0 keeps the stream's size
+	FPS     int           `json:"fps,omitempty"`   // GIF frame rate, 0 for 12
+	Hardsub bool          `json:"hardsub,omitempty"`
+}
+
+// Duration is the length of the clip
+func (c Clip) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// Validate checks the clip has a known format and a length up to max
+// (0 = no limit)
+func (c Clip) Validate(max time.Duration) error {
+	if c.Format != ClipGIF && c.Format != ClipWebM {
+		return fmt.Errorf("unknown clip format %q: use gif or webm", c.Format)
+	}
+	if c.Start < 0 || c.Duration() <= 0 {
+		return fmt.Errorf("clip must end after it starts")
+	}
+	if max > 0 && c.Duration() > max {
+		return fmt.Errorf("clip is %s long, longer than the %s limit", c.Duration().Round(time.Second), max)
+	}
+	return nil
+}
+
+// AddClip queues a clip of task's stream, rendered by a worker into
+// <path>/clips/<title>/. task.Clip must be set.
+func (m *Manager) AddClip(ctx context.Context, task DownloadTask) error {
+	if task.Clip == nil {
+		return fmt.Errorf("no clip to export")
+	}
+	if !m.ffmpeg.Available {
+		return ErrFFmpegRequired
+	}
+	if task.StreamURL == "" {
+		return fmt.Errorf("stream URL is empty")
+	}
+	if err := task.Clip.Validate(0); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task.ID = uuid.New().String()
+	// Kept apart from the episode's own download and from cleanup
+	task.MediaID = "clip:" + task.MediaID
+	task.Quality = providers.Quality(task.Clip.Format)
+	task.CreatedAt = time.Now()
+	task.Status = StatusQueued
+	task.Progress = 0
+	task.EmbedSubs = false
+
+	name := SanitizeFilename(task.MediaTitle)
+	if task.Episode > 0 {
+		name += fmt.Sprintf(" E%02d", task.Episode)
+	}
+	name += " " + clipTimestamp(task.Clip.Start) + "." + string(task.Clip.Format)
+	outputPath := filepath.Join(m.config.Path, "clips", SanitizeFilename(task.MediaTitle), name)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	task.OutputPath = EnsureUniqueFilename(outputPath)
+
+	if err := m.addTaskToDB(task); err != nil {
+		return fmt.Errorf("failed to save task to database: %w", err)
+	}
+	if m.running {
+		m.queue.push(&task)
+	}
+	return nil
+}
+
+// clipTimestamp formats a clip's start for its filename, e.g. 12m34s
+func clipTimestamp(d time.Duration) string {
+	d = d.Round(time.Second)
+	if h := int(d.Hours()); h > 0 {
+		return fmt.Sprintf("%dh%02dm%02ds", h, int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// renderClip cuts the clip from the stream and encodes it with ffmpeg,
+// reporting progress through the clip
+func (w *worker) renderClip(ctx context.Context, task *DownloadTask) error {
+	if !w.manager.ffmpeg.Available {
+		return ErrFFmpegRequired
+	}
+
+	subPath := ""
+	if task.Clip.Hardsub {
+		if sub, ok := pickSubtitle(task.Subtitles, w.manager.config.SubtitleLanguages); ok {
+			subPath = filepath.Join(os.TempDir(), fmt.Sprintf("clip_%s.%s", task.ID, getSubtitleExtension(sub.URL)))
+			if err := w.downloadSubtitle(ctx, task.Provider, sub.URL, subPath); err != nil {
+				w.logger.Warn("failed to download clip subtitles, rendering without", "error", err)
+				subPath = ""
+			} else {
+				defer func() { _ = os.Remove(subPath) }()
+			}
+		}
+	}
+
+	partPath := task.OutputPath + ".part"
+	args := clipArgs(task, subPath, partPath)
+	w.logger.Debug("rendering clip", "binary", w.manager.ffmpeg.Binary, "args", args)
+
+	cmd := exec.CommandContext(ctx, w.manager.ffmpeg.Binary, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+	w.monitorClipProgress(stdout, task)
+
+	if err := cmd.Wait(); err != nil {
+		_ = os.Remove(partPath)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg failed to render clip: %w\nOutput: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if err := os.Rename(partPath, task.OutputPath); err != nil {
+		return fmt.Errorf("failed to finalize clip: %w", err)
+	}
+	if info, err := os.Stat(task.OutputPath); err == nil {
+		task.BytesDownloaded = info.Size()
+		task.TotalBytes = info.Size()
+	}
+	return nil
+}
+
+// monitorClipProgress turns ffmpeg's -progress output into a percentage of
+// the clip rendered
+func (w *worker) monitorClipProgress(stdout io.Reader, task *DownloadTask) {
+	scanner := bufio.NewScanner(stdout)
+	lastUpdate := time.Now()
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || key != "out_time_us" {
+			continue
+		}
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || us < 0 {
+			continue
+		}
+		task.Progress = min(99.0, float64(us)/float64(task.Clip.Duration().Microseconds())*100)
+		if time.Since(lastUpdate) >= 500*time.Millisecond {
+			w.manager.triggerProgressCallback(*task)
+			_ = w.manager.updateTaskInDB(*task)
+			lastUpdate = time.Now()
+		}
+	}
+}
+
+// clipArgs builds the ffmpeg arguments rendering task's clip to output,
+// burning in the subtitles at subPath when it isn't empty
+func clipArgs(task *DownloadTask, subPath, output string) []string {
+	clip := task.Clip
+	args := []string{"-progress", "pipe:1", "-loglevel", "warning"}
+	args = append(args, ffmpegHeaderArgs(task)...)
+	args = append(args,
+		"-ss", formatSeconds(clip.Start),
+		"-i", task.StreamURL,
+		"-t", formatSeconds(clip.Duration()),
+	)
+
+	var filters []string
+	if subPath != "" {
+		// Input seeking restarts timestamps at 0; shift them back so the
+		// subtitles line up with the scene
+		filters = append(filters,
+			fmt.Sprintf("setpts=PTS+%s/TB", formatSeconds(clip.Start)),
+			"subtitles="+escapeFilterPath(subPath),
+			"setpts=PTS-STARTPTS",
+		)
+	}
+
+	switch clip.Format {
+	case ClipGIF:
+		fps := clip.FPS
+		if fps <= 0 {
+			fps = 12
+		}
+		filters = append(filters, fmt.Sprintf("fps=%d", fps))
+		if clip.Width > 0 {
+			filters = append(filters, fmt.Sprintf("scale=%d:-1:flags=lanczos", clip.Width))
+		}
+		// Two-pass palette in one graph: much smaller and cleaner than
+		// ffmpeg's default 256-colour GIF
+		graph := strings.Join(filters, ",") + ",split[a][b];[a]palettegen=stats_mode=diff[p];[b][p]paletteuse=dither=bayer:bayer_scale=3"
+		args = append(args, "-filter_complex", graph, "-an", "-loop", "0", "-f", "gif")
+	case ClipWebM:
+		if clip.Width > 0 {
+			filters = append(filters, fmt.Sprintf("scale=%d:-2", clip.Width))
+		}
+		if len(filters) > 0 {
+			args = append(args, "-vf", strings.Join(filters, ","))
+		}
+		args = append(args,
+			"-c:v", "libvpx-vp9", "-crf", "36", "-b:v", "0", "-row-mt", "1", "-deadline", "good", "-cpu-used", "4",
+			"-c:a", "libopus", "-b:a", "96k",
+			"-f", "webm",
+		)
+	}
+	return append(args, "-y", output)
+}
+
+// ffmpegHeaderArgs passes the task's HTTP headers and referer to ffmpeg
+func ffmpegHeaderArgs(task *DownloadTask) []string {
+	var args []string
+	for key, value := range task.Headers {
+		args = append(args, "-headers", fmt.Sprintf("%s: %s", capitalizeHeader(key), value))
+	}
+	if task.Referer != "" {
+		args = append(args, "-headers", fmt.Sprintf("Referer: %s", task.Referer))
+	}
+	return args
+}
+
+// pickSubtitle returns the first subtitle in a preferred language, else the
+// first one
+func pickSubtitle(subtitles []providers.Subtitle, languages []string) (providers.Subtitle, bool) {
+	for _, lang := range languages {
+		for _, sub := range subtitles {
+			if strings.EqualFold(sub.Language, lang) || strings.HasPrefix(strings.ToLower(sub.Language), strings.ToLower(lang)) {
+				return sub, true
+			}
+		}
+	}
+	if len(subtitles) > 0 {
+		return subtitles[0], true
+	}
+	return providers.Subtitle{}, false
+}
+
+// formatSeconds formats d as seconds for ffmpeg
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// escapeFilterPath escapes a path for use as a filtergraph option value
+func escapeFilterPath(path string) string {
+	path = filepath.ToSlash(path)
+	replacer := strings.NewReplacer(`\`, `\\\\`, `'`, `\\\'`, `:`, `\\:`, `,`, `\,`, `[`, `\[`, `]`, `\]`, `;`, `\;`)
+	return replacer.Replace(path)
+}
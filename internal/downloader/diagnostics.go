@@ -0,0 +1,169 @@
+package downloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Download tools, as recorded in Diagnostics
+const (
+	ToolNative = "native" // Built-in HTTP and HLS downloader
+	ToolHTTP   = "http"   // Plain HTTP fallback when ffmpeg isn't needed
+	ToolFFmpeg = "ffmpeg"
+	ToolYTDLP  = "yt-dlp"
+	ToolMPV    = "mpv"
+	ToolXDCC   = "xdcc"
+)
+
+// maxResponseBody is how much of a failed response's body is kept
+const maxResponseBody = 2048
+
+// Diagnostics describe the request a download failed on, so the failure can
+// be reported and reproduced outside greg
+type Diagnostics struct {
+	Tool     string            `json:"tool"`               // Last tool that tried, see the Tool constants
+	URL      string            `json:"url"`                // Resolved stream URL
+	Headers  map[string]string `json:"headers,omitempty"`  // Sent with the requests, Referer included
+	Status   int               `json:"status,omitempty"`   // HTTP status of the failed response
+	Response string            `json:"response,omitempty"` // Start of the failed response's body
+	At       time.Time         `json:"at"`
+}
+
+// StatusError is an HTTP response a download couldn't use
+type StatusError struct {
+	Code int
+	Body string // Start of the body, servers often explain the refusal there
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.Code)
+}
+
+// newStatusError reads the start of a failed response's body into an error
+func newStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
+
+// requestHeaders returns the headers a task sends, Referer included
+func requestHeaders(task *DownloadTask) map[string]string {
+	if len(task.Headers) == 0 && task.Referer == "" {
+		return nil
+	}
+	headers := make(map[string]string, len(task.Headers)+1)
+	for key, value := range task.Headers {
+		headers[capitalizeHeader(key)] = value
+	}
+	if task.Referer != "" {
+		headers["Referer"] = task.Referer
+	}
+	return headers
+}
+
+// diagnose describes the request task failed on with err
+func diagnose(task *DownloadTask, tool string, err error) *Diagnostics {
+	diagnostics := &Diagnostics{
+		Tool:    tool,
+		URL:     task.StreamURL,
+		Headers: requestHeaders(task),
+		At:      time.Now(),
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		diagnostics.Status = statusErr.Code
+		diagnostics.Response = statusErr.Body
+	}
+	return diagnostics
+}
+
+// encodeDiagnostics stores diagnostics as JSON in the downloads table
+func encodeDiagnostics(d *Diagnostics) string {
+	if d == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(d)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// decodeDiagnostics reads diagnostics stored by encodeDiagnostics
+func decodeDiagnostics(s string) *Diagnostics {
+	if s == "" {
+		return nil
+	}
+	var d Diagnostics
+	if err := json.Unmarshal([]byte(s), &d); err != nil {
+		return nil
+	}
+	return &d
+}
+
+// ReproCommand returns a shell command repeating the request the task failed
+// on with the tool that failed, or "" when the failure wasn't diagnosed
+func (t DownloadTask) ReproCommand() string {
+	d := t.Diagnostics
+	if d == nil || d.URL == "" {
+		return ""
+	}
+	output := shellQuote(filepath.Base(t.OutputPath))
+	if t.OutputPath == "" {
+		output = "out"
+	}
+
+	keys := make([]string, 0, len(d.Headers))
+	for key := range d.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	switch d.Tool {
+	case ToolYTDLP:
+		args = []string{"yt-dlp"}
+		for _, key := range keys {
+			args = append(args, "--add-header", shellQuote(key+":"+d.Headers[key]))
+		}
+		args = append(args, "-o", output, shellQuote(d.URL))
+	case ToolFFmpeg:
+		args = []string{"ffmpeg"}
+		for _, key := range keys {
+			args = append(args, "-headers", shellQuote(key+": "+d.Headers[key]))
+		}
+		args = append(args, "-i", shellQuote(d.URL), "-c", "copy", output)
+	case ToolMPV:
+		args = []string{"mpv", "--no-config", "--vo=null", "--ao=null", "--stream-record=" + output}
+		for _, key := range keys {
+			args = append(args, shellQuote("--http-header-fields="+key+": "+d.Headers[key]))
+		}
+		args = append(args, shellQuote(d.URL))
+	case ToolXDCC:
+		// Packs are requested over IRC, there is no command line to repeat
+		return ""
+	default:
+		args = []string{"curl", "-fL"}
+		for _, key := range keys {
+			args = append(args, "-H", shellQuote(key+": "+d.Headers[key]))
+		}
+		args = append(args, "-o", output, shellQuote(d.URL))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell when it needs it
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@%+,", r)
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
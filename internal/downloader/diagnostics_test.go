@@ -0,0 +1,50 @@
+package downloader
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	recorder.WriteHeader(http.StatusForbidden)
+	_, _ = recorder.WriteString("token expired\n")
+	err := fmt.Errorf("HLS download failed: %w", newStatusError(recorder.Result()))
+	assert.Equal(t, "HLS download failed: unexpected status code: 403", err.Error())
+
+	task := &DownloadTask{
+		StreamURL:  "https://cdn.example/ep 5.m3u8",
+		Headers:    map[string]string{"user-agent": "greg"},
+		Referer:    "https://provider.example/",
+		OutputPath: "/downloads/Show - 005.mkv",
+	}
+	task.Diagnostics = diagnose(task, ToolFFmpeg, err)
+	assert.Equal(t, 403, task.Diagnostics.Status)
+	assert.Equal(t, "token expired", task.Diagnostics.Response)
+	assert.Equal(t, map[string]string{"User-Agent": "greg", "Referer": "https://provider.example/"}, task.Diagnostics.Headers)
+
+	assert.Equal(t, "ffmpeg -headers 'Referer: https://provider.example/' -headers 'User-Agent: greg' -i 'https://cdn.example/ep 5.m3u8' -c copy 'Show - 005.mkv'", task.ReproCommand())
+	task.Diagnostics.Tool = ToolNative
+	assert.Equal(t, "curl -fL -H 'Referer: https://provider.example/' -H 'User-Agent: greg' -o 'Show - 005.mkv' 'https://cdn.example/ep 5.m3u8'", task.ReproCommand())
+
+	// Diagnostics are kept with the task in the database
+	m := &Manager{}
+	restored := m.downloadToTask(m.taskToDownload(*task))
+	require.NotNil(t, restored.Diagnostics)
+	assert.Equal(t, task.Diagnostics.Response, restored.Diagnostics.Response)
+	assert.Equal(t, task.ReproCommand(), restored.ReproCommand())
+
+	assert.Empty(t, DownloadTask{}.ReproCommand(), "nothing to reproduce without diagnostics")
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "https://a.example/x.m3u8", shellQuote("https://a.example/x.m3u8"))
+	assert.Equal(t, "''", shellQuote(""))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+	assert.Equal(t, "'a&b'", shellQuote("a&b"))
+}
@@ -0,0 +1,86 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+)
+
+// ErrInsufficientSpace is returned when a download would leave less free
+// space than downloads.min_free_space
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// DiskSpace describes free space on the download volume
+type DiskSpace struct {
+	Free    uint64 // Bytes available to greg
+	Minimum uint64 // Bytes that must stay free (downloads.min_free_space)
+}
+
+// Low reports whether free space is already below the configured minimum
+func (d DiskSpace) Low() bool {
+	return d.Minimum > 0 && d.Free < d.Minimum
+}
+
+// DiskSpace returns free space on the volume holding the download directory
+func (m *Manager) DiskSpace() (DiskSpace, error) {
+	m.mu.RLock()
+	path := m.config.Path
+	minimum := m.minFreeSpace()
+	m.mu.RUnlock()
+
+	free, err := FreeSpace(path)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+	return DiskSpace{Free: free, Minimum: minimum}, nil
+}
+
+// FreeSpace returns the bytes available on the volume holding path. Missing
+// directories are resolved to their closest existing parent.
+func FreeSpace(path string) (uint64, error) {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	return freeSpace(path)
+}
+
+// minFreeSpace returns downloads.min_free_space (in GB) in bytes
+func (m *Manager) minFreeSpace() uint64 {
+	if m.config.MinFreeSpace <= 0 {
+		return 0
+	}
+	return uint64(m.config.MinFreeSpace) << 30
+}
+
+// checkDiskSpace fails with ErrInsufficientSpace if writing need more bytes
+// would leave less than the configured minimum free. Platforms where free
+// space can't be read are not checked.
+func (m *Manager) checkDiskSpace(need int64) error {
+	minimum := m.minFreeSpace()
+	if minimum == 0 || m.config.Path == "" {
+		return nil
+	}
+
+	free, err := FreeSpace(m.config.Path)
+	if err != nil {
+		m.logger.Debug("skipping disk space check", "error", err)
+		return nil
+	}
+
+	need = max(need, 0)
+	if free < uint64(need)+minimum {
+		return fmt.Errorf("%w: %s free, %s needed plus %s kept free",
+			ErrInsufficientSpace, humanize.IBytes(free), humanize.IBytes(uint64(need)), humanize.IBytes(minimum))
+	}
+	return nil
+}
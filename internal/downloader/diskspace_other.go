@@ -1,11 +1,11 @@
-//go:build !(linux || darwin || windows)
+//go:build !(linux || darwin || freebsd || dragonfly || windows)
 
 package downloader
 
-// checkDiskSpace checks if there's enough free space available based on config
-func (m *Manager) checkDiskSpace() error {
-	// For other platforms where disk space checking might not be supported
-	// we'll just return nil to allow the operation to continue
-	// This is a fallback implementation for unsupported platforms
-	return nil
+import "errors"
+
+// freeSpace is not supported on this platform, so disk space checks are
+// skipped
+func freeSpace(path string) (uint64, error) {
+	return 0, errors.New("disk space check not supported on this platform")
 }
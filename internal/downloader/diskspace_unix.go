@@ -1,4 +1,4 @@
-//go:build unix && !linux && !darwin
+//go:build linux || darwin || freebsd || dragonfly
 
 package downloader
 
@@ -7,24 +7,12 @@ import (
 	"syscall"
 )
 
-// checkDiskSpace checks if there's enough free space available based on config
-func (m *Manager) checkDiskSpace() error {
-	if m.config.Path == "" {
-		return nil // Skip check if no download path is set
-	}
-
+// freeSpace returns the bytes available to unprivileged users on the volume
+// holding path
+func freeSpace(path string) (uint64, error) {
 	var stat syscall.Statfs_t
-	if err := syscall.Statfs(m.config.Path, &stat); err != nil {
-		return fmt.Errorf("failed to get disk stats: %w", err)
-	}
-
-	// Available space in bytes
-	availableSpace := stat.Bavail * uint64(stat.Bsize)
-	requiredSpace := uint64(m.config.MinFreeSpace)
-
-	if requiredSpace > 0 && availableSpace < requiredSpace {
-		return fmt.Errorf("insufficient disk space: need %d bytes, available %d bytes", requiredSpace, availableSpace)
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("failed to get disk stats: %w", err)
 	}
-
-	return nil
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/justchokingaround/greg/internal/database"
 	"github.com/justchokingaround/greg/internal/providers"
 )
 
@@ -14,6 +15,7 @@ type Downloader interface {
 	RemoveFromQueue(ctx context.Context, id string) error
 	GetQueue(ctx context.Context) ([]DownloadTask, error)
 	ClearQueue(ctx context.Context) error
+	MoveInQueue(ctx context.Context, id string, offset int) error
 
 	// Download control
 	Start(ctx context.Context) error
@@ -24,6 +26,7 @@ type Downloader interface {
 	ResumeAll(ctx context.Context) error
 	Retry(ctx context.Context, id string) error
 	DeleteTaskAndFile(ctx context.Context, id string) error
+	TrashTasks(ctx context.Context, label string, ids ...string) (*database.TrashItem, error)
 
 	// Progress monitoring
 	OnProgressUpdate(callback func(task DownloadTask))
@@ -38,31 +41,41 @@ type Downloader interface {
 
 // DownloadTask represents a single download task
 type DownloadTask struct {
-	ID              string               `json:"id"`
-	MediaID         string               `json:"media_id"`
-	MediaTitle      string               `json:"media_title"`
-	MediaType       providers.MediaType  `json:"media_type"`
-	Episode         int                  `json:"episode"`
-	Season          int                  `json:"season,omitempty"`
-	Quality         providers.Quality    `json:"quality"`
-	Provider        string               `json:"provider"`
-	StreamURL       string               `json:"stream_url"`
-	StreamType      providers.StreamType `json:"stream_type"`
-	Headers         map[string]string    `json:"headers,omitempty"`
-	Referer         string               `json:"referer,omitempty"`
-	OutputPath      string               `json:"output_path"`
-	Subtitles       []providers.Subtitle `json:"subtitles,omitempty"`
-	EmbedSubs       bool                 `json:"embed_subs"`
-	Status          DownloadStatus       `json:"status"`
-	Progress        float64              `json:"progress"` // 0.0 - 100.0
-	BytesDownloaded int64                `json:"bytes_downloaded"`
-	TotalBytes      int64                `json:"total_bytes"`
-	Speed           int64                `json:"speed"` // bytes per second
-	ETA             time.Duration        `json:"eta"`
-	Error           string               `json:"error,omitempty"`
-	CreatedAt       time.Time            `json:"created_at"`
-	StartedAt       *time.Time           `json:"started_at,omitempty"`
-	CompletedAt     *time.Time           `json:"completed_at,omitempty"`
+	ID               string               `json:"id"`
+	MediaID          string               `json:"media_id"`
+	MediaTitle       string               `json:"media_title"`
+	MediaType        providers.MediaType  `json:"media_type"`
+	Episode          int                  `json:"episode"`
+	Season           int                  `json:"season,omitempty"`
+	Quality          providers.Quality    `json:"quality"`
+	Provider         string               `json:"provider"`
+	StreamURL        string               `json:"stream_url"`
+	StreamType       providers.StreamType `json:"stream_type"`
+	Headers          map[string]string    `json:"headers,omitempty"`
+	Referer          string               `json:"referer,omitempty"`
+	OutputPath       string               `json:"output_path"`
+	Subtitles        []providers.Subtitle `json:"subtitles,omitempty"`
+	EmbedSubs        bool                 `json:"embed_subs"`
+	Status           DownloadStatus       `json:"status"`
+	Priority         int                  `json:"priority,omitempty"` // Higher runs first among queued tasks
+	Progress         float64              `json:"progress"`           // 0.0 - 100.0
+	BytesDownloaded  int64                `json:"bytes_downloaded"`
+	TotalBytes       int64                `json:"total_bytes"`
+	Speed            int64                `json:"speed"` // bytes per second
+	ETA              time.Duration        `json:"eta"`
+	Error            string               `json:"error,omitempty"`
+	Diagnostics      *Diagnostics         `json:"diagnostics,omitempty"`       // Request the last failure happened on
+	ExpectedSize     int64                `json:"expected_size,omitempty"`     // Size announced by the source
+	ExpectedDuration time.Duration        `json:"expected_duration,omitempty"` // Duration from the playlist
+	Checksum         string               `json:"checksum,omitempty"`          // Expected "crc32:<hex>" or "sha256:<hex>"
+	Clip             *Clip                `json:"clip,omitempty"`              // Renders a clip of the stream instead of downloading it
+	Peer             string               `json:"peer,omitempty"`              // Instance the episode was pulled from instead of the provider
+	RemotePath       string               `json:"remote_path,omitempty"`       // Where the file was uploaded to, see downloads.remote
+	PartialPath      string               `json:"-"`                           // File written front to back while downloading, playable before it finishes
+	OnDuplicate      DuplicateAction      `json:"-"`                           // What AddToQueue does when the episode was already downloaded
+	CreatedAt        time.Time            `json:"created_at"`
+	StartedAt        *time.Time           `json:"started_at,omitempty"`
+	CompletedAt      *time.Time           `json:"completed_at,omitempty"`
 }
 
 // DownloadStatus represents the status of a download task
@@ -76,6 +89,7 @@ const (
 	StatusFailed      DownloadStatus = "failed"
 	StatusCancelled   DownloadStatus = "cancelled"
 	StatusProcessing  DownloadStatus = "processing" // Converting/embedding subtitles
+	StatusUploading   DownloadStatus = "uploading"  // Copying to remote storage
 )
 
 // String returns the string representation of DownloadStatus
@@ -85,7 +99,7 @@ func (s DownloadStatus) String() string {
 
 // IsActive returns true if the download is in an active state
 func (s DownloadStatus) IsActive() bool {
-	return s == StatusDownloading || s == StatusProcessing
+	return s == StatusDownloading || s == StatusProcessing || s == StatusUploading
 }
 
 // IsComplete returns true if the download is in a terminal state
@@ -2,7 +2,11 @@ package downloader
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -234,3 +238,750 @@ func TestProcessTaskWithRetry(t *testing.T) {
 	require.Len(t, tasks, 1)
 	require.Equal(t, "retry-test-task", tasks[0].ID)
 }
+
+func TestXDCCTaskSurvivesReload(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	cfg := &config.DownloadsConfig{
+		Path:                  t.TempDir(),
+		Concurrent:            1,
+		AnimeFilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	packURL := "xdcc://irc.rizon.net:6667/nibl/Bot/12"
+	ctx := context.Background()
+	require.NoError(t, manager.AddToQueue(ctx, DownloadTask{
+		MediaID:    packURL,
+		MediaTitle: "Frieren",
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    1,
+		Provider:   "xdcc",
+		StreamURL:  packURL,
+		StreamType: providers.StreamTypeXDCC,
+	}))
+
+	queue, err := manager.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, packURL, queue[0].StreamURL)
+	assert.Equal(t, providers.StreamTypeXDCC, queue[0].StreamType)
+	assert.Equal(t, ".mkv", filepath.Ext(queue[0].OutputPath))
+}
+
+func TestStopPausesActiveDownloads(t *testing.T) {
+	// Serve a body that never finishes so the download stays active
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1048576")
+		_, _ = w.Write(make([]byte, 1024))
+		w.(http.Flusher).Flush()
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	cfg := &config.DownloadsConfig{
+		Path:             t.TempDir(),
+		Concurrent:       1,
+		FilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	require.NoError(t, manager.AddToQueue(ctx, DownloadTask{
+		ID:         "stop-task",
+		MediaID:    "stop-media",
+		MediaTitle: "Stop",
+		MediaType:  providers.MediaTypeTV,
+		Episode:    1,
+		StreamURL:  server.URL + "/video.mp4",
+	}))
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("download did not start")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- manager.Stop() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+
+	var download database.Download
+	require.NoError(t, db.First(&download, "id = ?", "stop-task").Error)
+	assert.Equal(t, string(StatusPaused), download.Status)
+}
+
+func TestSetConcurrencyWhileRunning(t *testing.T) {
+	// Serve bodies that never finish so downloads stay active
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1048576")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write(make([]byte, 1024))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	cfg := &config.DownloadsConfig{
+		Path:             t.TempDir(),
+		Concurrent:       1,
+		FilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	for episode := 1; episode <= 2; episode++ {
+		require.NoError(t, manager.AddToQueue(ctx, DownloadTask{
+			ID:         fmt.Sprintf("task-%d", episode),
+			MediaID:    "concurrency-media",
+			MediaTitle: "Concurrency",
+			MediaType:  providers.MediaTypeTV,
+			Episode:    episode,
+			StreamURL:  fmt.Sprintf("%s/%d.mp4", server.URL, episode),
+		}))
+	}
+
+	active := func() int {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return len(manager.active)
+	}
+	require.Eventually(t, func() bool { return active() == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 1, active(), "one download at a time")
+
+	manager.SetConcurrency(2)
+	assert.Equal(t, 2, manager.Concurrency())
+	require.Eventually(t, func() bool { return active() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	manager.SetConcurrency(0)
+	assert.Equal(t, 1, manager.Concurrency(), "at least one worker")
+	manager.SetConcurrency(50)
+	assert.Equal(t, MaxConcurrency, manager.Concurrency())
+
+	done := make(chan error, 1)
+	go func() { done <- manager.Stop() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
+
+func TestWaitPlayable(t *testing.T) {
+	// Serve enough to start playing, then stall so the download stays active
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/x-matroska")
+		w.Header().Set("Content-Length", "33554432")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write(make([]byte, playableBytes+1024))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	cfg := &config.DownloadsConfig{
+		Path:             t.TempDir(),
+		Concurrent:       1,
+		FilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer func() { _ = manager.Stop() }()
+	require.NoError(t, manager.AddToQueue(ctx, DownloadTask{
+		ID:         "watch-task",
+		MediaID:    "watch-media",
+		MediaTitle: "Watch",
+		MediaType:  providers.MediaTypeTV,
+		Episode:    1,
+		StreamURL:  server.URL + "/video.mkv",
+	}))
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	path, growing, err := manager.WaitPlayable(waitCtx, "watch-task")
+	require.NoError(t, err)
+	assert.True(t, growing)
+	assert.Equal(t, filepath.Join(cfg.Path, "tv", "Watch", "Watch - 001.mp4"), path)
+
+	_, _, err = manager.WaitPlayable(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func TestIsMatroska(t *testing.T) {
+	assert.True(t, isMatroska(&DownloadTask{StreamType: providers.StreamTypeMKV}, ""))
+	assert.True(t, isMatroska(&DownloadTask{StreamURL: "https://cdn.example.com/a.mp4"}, "video/x-matroska"))
+	assert.True(t, isMatroska(&DownloadTask{StreamURL: "https://cdn.example.com/ep.WEBM?token=1"}, ""))
+	assert.False(t, isMatroska(&DownloadTask{StreamURL: "https://cdn.example.com/ep.mp4", StreamType: providers.StreamTypeMP4}, "video/mp4"))
+	assert.False(t, isMatroska(&DownloadTask{StreamURL: "https://cdn.example.com/download?id=1"}, "application/octet-stream"))
+}
+
+func TestEstimateSize(t *testing.T) {
+	// 24 minutes at 1080p (5 Mbps) is 900 MB
+	assert.Equal(t, int64(900_000_000), EstimateSize(providers.Quality1080p, 24*time.Minute))
+	assert.Less(t, EstimateSize(providers.Quality720p, time.Hour), EstimateSize(providers.Quality1080p, time.Hour))
+	// Auto falls back to the 1080p estimate
+	assert.Equal(t, EstimateSize(providers.Quality1080p, time.Hour), EstimateSize(providers.QualityAuto, time.Hour))
+	assert.Zero(t, EstimateSize(providers.Quality1080p, 0))
+}
+
+func TestTaskQueueOrder(t *testing.T) {
+	q := newTaskQueue()
+	now := time.Now()
+	for i, id := range []string{"a", "b", "c"} {
+		require.True(t, q.push(&DownloadTask{ID: id, CreatedAt: now.Add(time.Duration(i) * time.Second)}))
+	}
+	// Duplicates are ignored
+	require.True(t, q.push(&DownloadTask{ID: "a"}))
+
+	q.setPriority("c", 1)
+	q.remove("b")
+
+	ctx := context.Background()
+	task, ok := q.pop(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "c", task.ID)
+	task, ok = q.pop(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "a", task.ID)
+
+	q.close()
+	_, ok = q.pop(ctx)
+	assert.False(t, ok)
+	assert.False(t, q.push(&DownloadTask{ID: "d"}))
+}
+
+func TestMoveInQueue(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	cfg := &config.DownloadsConfig{
+		Path:                  t.TempDir(),
+		Concurrent:            1,
+		AnimeFilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+	for ep := 1; ep <= 3; ep++ {
+		require.NoError(t, manager.AddToQueue(ctx, DownloadTask{
+			ID:         fmt.Sprintf("ep%d", ep),
+			MediaID:    "frieren",
+			MediaTitle: "Frieren",
+			MediaType:  providers.MediaTypeAnime,
+			Episode:    ep,
+			Provider:   "test",
+			StreamURL:  "https://example.com/video.m3u8",
+			CreatedAt:  now.Add(time.Duration(ep) * time.Second),
+		}))
+	}
+
+	queueOrder := func() []string {
+		var downloads []database.Download
+		require.NoError(t, db.Order("priority DESC, created_at ASC").Find(&downloads).Error)
+		ids := make([]string, 0, len(downloads))
+		for _, d := range downloads {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	// Bump the last episode to the front
+	require.NoError(t, manager.MoveInQueue(ctx, "ep3", -10))
+	assert.Equal(t, []string{"ep3", "ep1", "ep2"}, queueOrder())
+
+	require.NoError(t, manager.MoveInQueue(ctx, "ep3", 1))
+	assert.Equal(t, []string{"ep1", "ep3", "ep2"}, queueOrder())
+
+	// Priorities are part of the task so the view can show queue positions
+	tasks, err := manager.GetQueue(ctx)
+	require.NoError(t, err)
+	for _, task := range tasks {
+		if task.ID == "ep1" {
+			assert.Equal(t, 3, task.Priority)
+		}
+	}
+
+	assert.Error(t, manager.MoveInQueue(ctx, "missing", -1))
+}
+
+func TestRequeue(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	cfg := &config.DownloadsConfig{
+		Path:                  t.TempDir(),
+		Concurrent:            1,
+		AnimeFilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, manager.AddToQueue(ctx, DownloadTask{
+		ID:         "ep5",
+		MediaID:    "frieren",
+		MediaTitle: "Frieren",
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    5,
+		Quality:    providers.Quality720p,
+		Provider:   "first",
+		StreamURL:  "https://example.com/video.m3u8",
+	}))
+	queue, err := manager.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	original := queue[0]
+
+	source := DownloadTask{
+		MediaID:   "frieren-elsewhere",
+		Provider:  "second",
+		StreamURL: "https://other.example/frieren/5.m3u8",
+		Referer:   "https://other.example/",
+	}
+	assert.Error(t, manager.Requeue(ctx, "ep5", source), "queued tasks aren't requeued")
+
+	failed := original
+	failed.Status = StatusFailed
+	failed.Progress = 40
+	failed.Error = "unexpected status code: 403"
+	failed.Diagnostics = &Diagnostics{Tool: ToolNative, URL: failed.StreamURL, Status: 403}
+	require.NoError(t, manager.updateTaskInDB(failed))
+
+	require.NoError(t, manager.Requeue(ctx, "ep5", source))
+	queue, err = manager.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	task := queue[0]
+	assert.Equal(t, StatusQueued, task.Status)
+	assert.Equal(t, "second", task.Provider)
+	assert.Equal(t, "frieren-elsewhere", task.MediaID)
+	assert.Equal(t, original.OutputPath, task.OutputPath, "the target path carries over")
+	assert.Equal(t, providers.Quality720p, task.Quality)
+	assert.Equal(t, 5, task.Episode)
+	assert.Zero(t, task.Progress)
+	assert.Empty(t, task.Error)
+	assert.Nil(t, task.Diagnostics)
+
+	assert.Error(t, manager.Requeue(ctx, "missing", source))
+}
+
+func TestEstimateTaskSizeHLS(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/master.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=800000\nlow.m3u8\n#EXT-X-STREAM-INF:BANDWIDTH=4000000\nhigh.m3u8\n")
+	})
+	mux.HandleFunc("/high.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "#EXTM3U\n#EXTINF:10.0,\na.ts\n#EXTINF:10.0,\nb.ts\n#EXT-X-ENDLIST\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	size := EstimateTaskSize(context.Background(), DownloadTask{
+		StreamURL:  server.URL + "/master.m3u8",
+		StreamType: providers.StreamTypeHLS,
+	})
+	// 4 Mbit/s for 20 seconds
+	assert.Equal(t, int64(10_000_000), size)
+}
+
+func TestAddToQueueRefusesWhenDiskIsFull(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1")
+	}))
+	defer server.Close()
+
+	cfg := &config.DownloadsConfig{
+		Path:                  t.TempDir(),
+		Concurrent:            1,
+		AnimeFilenameTemplate: "{title} - {episode:03d}",
+		MinFreeSpace:          1 << 20, // 1 PB can never be free
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+
+	space, err := manager.DiskSpace()
+	if err != nil {
+		t.Skipf("free space not available: %v", err)
+	}
+	assert.True(t, space.Low())
+
+	err = manager.AddToQueue(context.Background(), DownloadTask{
+		ID:         "ep1",
+		MediaTitle: "Frieren",
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    1,
+		StreamURL:  server.URL + "/ep1.mp4",
+		StreamType: providers.StreamTypeMP4,
+	})
+	assert.ErrorIs(t, err, ErrInsufficientSpace)
+
+	tasks, err := manager.GetQueue(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestAddToQueueDuplicates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "anime", "Frieren", "Frieren - 001.mp4")
+	require.NoError(t, os.MkdirAll(filepath.Dir(existing), 0755))
+	require.NoError(t, os.WriteFile(existing, []byte("video"), 0644))
+	// Downloaded earlier from another provider, under a slightly different title
+	require.NoError(t, db.Create(&database.Download{
+		ID: "old", MediaID: "other-id", MediaTitle: "Frieren!", MediaType: "anime", Episode: 1,
+		Quality: "1080p", Provider: "other", Status: string(StatusCompleted), FilePath: existing,
+	}).Error)
+
+	cfg := &config.DownloadsConfig{
+		Path:                  dir,
+		Concurrent:            1,
+		AnimeFilenameTemplate: "{title} - {episode:03d}",
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	task := DownloadTask{
+		MediaID:    "frieren",
+		MediaTitle: "Frieren",
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    1,
+		Quality:    providers.Quality1080p,
+		Provider:   "test",
+		StreamURL:  "https://example.com/ep1.mp4",
+		StreamType: providers.StreamTypeMP4,
+	}
+
+	err = manager.AddToQueue(ctx, task)
+	var dup *DuplicateError
+	require.ErrorAs(t, err, &dup)
+	assert.ErrorIs(t, err, ErrDuplicate)
+	require.NotNil(t, dup.Existing)
+	assert.Equal(t, "old", dup.Existing.ID)
+	assert.Equal(t, existing, dup.Path)
+
+	// Another quality isn't a duplicate
+	other := task
+	other.Quality = providers.Quality720p
+	other.Episode = 2
+	require.NoError(t, manager.AddToQueue(ctx, other))
+
+	// Keeping both downloads next to the existing copy
+	keep := dup.Task
+	keep.OnDuplicate = DuplicateKeepBoth
+	require.NoError(t, manager.AddToQueue(ctx, keep))
+	tasks, err := manager.GetQueue(ctx)
+	require.NoError(t, err)
+	var queued *DownloadTask
+	for i := range tasks {
+		if tasks[i].MediaID == "frieren" && tasks[i].Episode == 1 {
+			queued = &tasks[i]
+		}
+	}
+	require.NotNil(t, queued)
+	assert.Equal(t, filepath.Join(dir, "anime", "Frieren", "Frieren - 001 (1).mp4"), queued.OutputPath)
+	assert.FileExists(t, existing)
+
+	// Overwriting trashes the existing copy and takes its place
+	require.NoError(t, manager.RemoveFromQueue(ctx, queued.ID))
+	overwrite := dup.Task
+	overwrite.OnDuplicate = DuplicateOverwrite
+	require.NoError(t, manager.AddToQueue(ctx, overwrite))
+	assert.NoFileExists(t, existing)
+	tasks, err = manager.GetQueue(ctx)
+	require.NoError(t, err)
+	for _, task := range tasks {
+		assert.NotEqual(t, "old", task.ID)
+		if task.MediaID == "frieren" && task.Episode == 1 {
+			assert.Equal(t, existing, task.OutputPath)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	dir := t.TempDir()
+	seed := func(id, title string, episode int, quality string, onDisk bool) {
+		path := filepath.Join(dir, id+".mp4")
+		if onDisk {
+			require.NoError(t, os.WriteFile(path, []byte("video"), 0644))
+		}
+		completed := time.Now()
+		require.NoError(t, db.Create(&database.Download{
+			ID: id, MediaID: id, MediaTitle: title, MediaType: "anime", Episode: episode,
+			Quality: quality, Provider: "test", Status: string(StatusCompleted), FilePath: path,
+			TotalBytes: 5, CompletedAt: &completed,
+		}).Error)
+	}
+	seed("a1", "Frieren", 1, "1080p", true)
+	seed("a2", "frieren", 1, "1080p", true)
+	seed("a3", "Frieren", 1, "1080p", false) // File is gone
+	seed("b1", "Frieren", 1, "720p", true)   // Other quality
+	seed("c1", "Frieren", 2, "1080p", true)
+
+	manager, err := NewManager(db, &config.DownloadsConfig{Path: dir, Concurrent: 1}, slog.Default())
+	require.NoError(t, err)
+
+	sets, err := manager.FindDuplicates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sets, 1)
+	assert.Equal(t, "a1", sets[0].Keep.ID)
+	require.Len(t, sets[0].Extras, 1)
+	assert.Equal(t, "a2", sets[0].Extras[0].ID)
+}
+
+func TestCleanupWatchedDownloads(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	dir := t.TempDir()
+	now := time.Now()
+	seed := func(id, title string, episode int, watchedAt time.Time) string {
+		path := filepath.Join(dir, title, fmt.Sprintf("%s - %03d.mp4", title, episode))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("video"), 0644))
+		require.NoError(t, db.Create(&database.Download{
+			ID: id, MediaID: title, MediaTitle: title, MediaType: "anime", Episode: episode,
+			Quality: "1080p", Provider: "test", Status: string(StatusCompleted), FilePath: path,
+		}).Error)
+		if !watchedAt.IsZero() {
+			require.NoError(t, db.Create(&database.History{
+				MediaID: title, MediaTitle: title, MediaType: "anime", Episode: episode,
+				ProgressSeconds: 1400, TotalSeconds: 1440, ProgressPercent: 97,
+				WatchedAt: watchedAt, Completed: true,
+			}).Error)
+		}
+		return path
+	}
+
+	old := seed("old", "Frieren", 1, now.Add(-10*24*time.Hour))
+	recent := seed("recent", "Frieren", 2, now.Add(-time.Hour))
+	unwatched := seed("unwatched", "Frieren", 3, time.Time{})
+	kept := seed("kept", "Mushishi", 1, now.Add(-30*24*time.Hour))
+
+	cfg := &config.DownloadsConfig{
+		Path:       dir,
+		Concurrent: 1,
+		Cleanup:    config.CleanupConfig{Days: 7, Action: CleanupArchive, Exclude: []string{"mushishi"}},
+	}
+	manager, err := NewManager(db, cfg, slog.Default())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	report, err := manager.Cleanup(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, report.Candidates, 1)
+	assert.Equal(t, "old", report.Candidates[0].Task.ID)
+	assert.Equal(t, []string{"Mushishi"}, report.Excluded)
+	assert.Equal(t, int64(len("video")), report.Freed)
+	assert.FileExists(t, old, "dry run changes nothing")
+
+	report, err = manager.Cleanup(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, report.Candidates, 1)
+	archived := filepath.Join(dir, "archive", "Frieren", "Frieren - 001.mp4")
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, archived)
+	for _, path := range []string{recent, unwatched, kept} {
+		assert.FileExists(t, path)
+	}
+
+	var download database.Download
+	require.NoError(t, db.First(&download, "id = ?", "old").Error)
+	assert.Equal(t, archived, download.FilePath)
+
+	// Archived files are not archived again
+	report, err = manager.Cleanup(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Candidates)
+
+	// Deleting goes through the trash so it can be undone
+	cfg.Cleanup.Action = CleanupDelete
+	report, err = manager.Cleanup(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, report.Candidates, 1)
+	require.NotNil(t, report.Trash)
+	assert.NoFileExists(t, archived)
+}
+
+func TestChecksumFromName(t *testing.T) {
+	assert.Equal(t, "crc32:abcd1234", ChecksumFromName("[SubsPlease] Frieren - 01 (1080p) [ABCD1234].mkv"))
+	assert.Equal(t, "crc32:0badf00d", ChecksumFromName("[12345678Group] Show - 02 [0BADF00D].mkv"))
+	assert.Empty(t, ChecksumFromName("[SubsPlease] Frieren - 01 (1080p).mkv"))
+}
+
+func TestVerifyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "episode.mkv")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0644))
+	ctx := context.Background()
+
+	// crc32("hello") = 3610a686
+	task := &DownloadTask{OutputPath: path, ExpectedSize: 5, Checksum: "crc32:3610A686"}
+	assert.NoError(t, verifyFile(ctx, task, ""))
+
+	task.Checksum = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	assert.NoError(t, verifyFile(ctx, task, ""))
+
+	task.Checksum = "crc32:00000000"
+	assert.ErrorIs(t, verifyFile(ctx, task, ""), ErrCorrupted)
+
+	task.Checksum = ""
+	task.ExpectedSize = 1024
+	assert.ErrorIs(t, verifyFile(ctx, task, ""), ErrCorrupted, "truncated file")
+
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	task.ExpectedSize = 0
+	assert.ErrorIs(t, verifyFile(ctx, task, ""), ErrCorrupted, "empty file")
+}
+
+func TestClipValidate(t *testing.T) {
+	clip := Clip{Start: 10 * time.Second, End: 25 * time.Second, Format: ClipGIF}
+	assert.NoError(t, clip.Validate(30*time.Second))
+	assert.Error(t, clip.Validate(10*time.Second), "longer than the limit")
+	assert.Error(t, Clip{Start: 10 * time.Second, End: 10 * time.Second, Format: ClipGIF}.Validate(0))
+	assert.Error(t, Clip{End: time.Second, Format: "mp4"}.Validate(0))
+}
+
+func TestClipArgs(t *testing.T) {
+	task := &DownloadTask{
+		StreamURL: "https://cdn.example.com/ep.m3u8",
+		Referer:   "https://example.com/",
+		Clip:      &Clip{Start: 90 * time.Second, End: 95500 * time.Millisecond, Format: ClipGIF, Width: 480, FPS: 10},
+	}
+
+	args := clipArgs(task, "", "/tmp/out.gif.part")
+	assert.Subset(t, args, []string{"-headers", "Referer: https://example.com/", "-ss", "90.000", "-i", task.StreamURL, "-t", "5.500", "-an", "-f", "gif"})
+	assert.Contains(t, args, "fps=10,scale=480:-1:flags=lanczos,split[a][b];[a]palettegen=stats_mode=diff[p];[b][p]paletteuse=dither=bayer:bayer_scale=3")
+	assert.Equal(t, "/tmp/out.gif.part", args[len(args)-1])
+
+	task.Clip.Format = ClipWebM
+	args = clipArgs(task, "/tmp/sub.ass", "/tmp/out.webm.part")
+	assert.Contains(t, args, "setpts=PTS+90.000/TB,subtitles=/tmp/sub.ass,setpts=PTS-STARTPTS,scale=480:-2")
+	assert.Subset(t, args, []string{"libvpx-vp9", "libopus", "webm"})
+}
+
+func TestEscapeFilterPath(t *testing.T) {
+	assert.Equal(t, "/tmp/clip_1.srt", escapeFilterPath("/tmp/clip_1.srt"))
+	assert.Equal(t, `C\\:/Temp/it\\\'s\,here.srt`, escapeFilterPath("C:/Temp/it's,here.srt"))
+}
+
+func TestPickSubtitle(t *testing.T) {
+	subs := []providers.Subtitle{{Language: "Spanish"}, {Language: "English"}}
+	sub, ok := pickSubtitle(subs, []string{"en"})
+	require.True(t, ok)
+	assert.Equal(t, "English", sub.Language)
+
+	sub, ok = pickSubtitle(subs, []string{"fr"})
+	require.True(t, ok)
+	assert.Equal(t, "Spanish", sub.Language, "falls back to the first subtitle")
+
+	_, ok = pickSubtitle(nil, []string{"en"})
+	assert.False(t, ok)
+}
+
+func TestAddClip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	dir := t.TempDir()
+	manager, err := NewManager(db, &config.DownloadsConfig{Path: dir, Concurrent: 1, AnimeFilenameTemplate: "{title} - {episode:03d}"}, slog.Default())
+	require.NoError(t, err)
+
+	task := DownloadTask{
+		MediaID:    "frieren",
+		MediaTitle: "Frieren: Beyond Journey's End",
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    3,
+		StreamURL:  "https://cdn.example.com/ep3.m3u8",
+		Clip:       &Clip{Start: 754 * time.Second, End: 760 * time.Second, Format: ClipWebM},
+	}
+
+	manager.ffmpeg.Available = false
+	assert.ErrorIs(t, manager.AddClip(context.Background(), task), ErrFFmpegRequired)
+
+	manager.ffmpeg.Available = true
+	require.NoError(t, manager.AddClip(context.Background(), task))
+
+	queue, err := manager.GetQueue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, "clip:frieren", queue[0].MediaID)
+	assert.Equal(t, filepath.Join(dir, "clips", "Frieren - Beyond Journey's End", "Frieren - Beyond Journey's End E03 12m34s.webm"), queue[0].OutputPath)
+
+	// The clip doesn't count as the episode's download
+	task.Clip = nil
+	task.StreamURL = "https://cdn.example.com/ep3.mp4"
+	assert.NoError(t, manager.AddToQueue(context.Background(), task))
+}
+
+func TestCompleteRecordsBandwidth(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	manager, err := NewManager(db, &config.DownloadsConfig{Path: t.TempDir(), Concurrent: 1}, slog.Default())
+	require.NoError(t, err)
+	w := newWorker(0, manager)
+
+	w.complete(&DownloadTask{ID: "episode", Provider: "allanime", BytesDownloaded: 300_000_000, TotalBytes: 300_000_000})
+	w.complete(&DownloadTask{ID: "movie", Provider: "allanime", TotalBytes: 1_000})
+	w.complete(&DownloadTask{ID: "clip", Provider: "allanime", BytesDownloaded: 2_000_000, Clip: &Clip{Format: ClipGIF}})
+
+	total, err := database.BandwidthTotal(db, database.BandwidthMonth(time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(300_001_000), total)
+}
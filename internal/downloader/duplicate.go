@@ -0,0 +1,189 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// ErrDuplicate is wrapped by *DuplicateError
+var ErrDuplicate = errors.New("already downloaded")
+
+// DuplicateAction says what AddToQueue does with an episode that was
+// already downloaded
+type DuplicateAction string
+
+const (
+	// DuplicateRefuse returns a *DuplicateError so the caller can ask
+	DuplicateRefuse DuplicateAction = ""
+	// DuplicateOverwrite moves the existing copy to the trash and downloads
+	// the episode again in its place
+	DuplicateOverwrite DuplicateAction = "overwrite"
+	// DuplicateKeepBoth downloads the episode next to the existing copy
+	// under another name
+	DuplicateKeepBoth DuplicateAction = "keep_both"
+)
+
+// DuplicateError is returned by AddToQueue when the episode was already
+// downloaded in the same quality. Queue Task again with OnDuplicate set to
+// go ahead.
+type DuplicateError struct {
+	Task     DownloadTask  // The task that was not queued
+	Existing *DownloadTask // The earlier download, nil for a file greg didn't download
+	Path     string        // File of the existing copy
+}
+
+func (e *DuplicateError) Error() string {
+	name := e.Task.MediaTitle
+	if e.Task.Episode > 0 {
+		name = fmt.Sprintf("%s episode %d", name, e.Task.Episode)
+	}
+	return fmt.Sprintf("%s %v: %s", name, ErrDuplicate, e.Path)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// findDuplicate looks for an existing copy of the task's episode in the
+// same quality: a completed download whose file is still there, from any
+// provider, or a file at the path the task would be saved to. The manager
+// lock must be held.
+func (m *Manager) findDuplicate(task DownloadTask, outputPath string) *DuplicateError {
+	var downloads []database.Download
+	err := m.db.Where("episode = ? AND season = ? AND quality = ? AND status = ?",
+		task.Episode, task.Season, string(task.Quality), string(StatusCompleted)).
+		Order("completed_at").
+		Find(&downloads).Error
+	if err != nil {
+		m.logger.Warn("failed to look for duplicate downloads", "error", err)
+	}
+	title := titleKey(task.MediaTitle)
+	for _, d := range downloads {
+		sameMedia := d.MediaID == task.MediaID && d.Provider == task.Provider
+		if !sameMedia && (title == "" || titleKey(d.MediaTitle) != title) {
+			continue
+		}
+		if d.FilePath == "" || !fileExists(d.FilePath) {
+			continue
+		}
+		existing := m.downloadToTask(d)
+		return &DuplicateError{Task: task, Existing: &existing, Path: d.FilePath}
+	}
+
+	// A copy downloaded before the database was reset, or by hand; the
+	// container depends on the stream, so look for both
+	base := strings.TrimSuffix(outputPath, ".mkv")
+	base = strings.TrimSuffix(base, ".mp4")
+	for _, path := range []string{outputPath, base + ".mkv", base + ".mp4"} {
+		if fileExists(path) {
+			return &DuplicateError{Task: task, Path: path}
+		}
+	}
+	return nil
+}
+
+// replaceDuplicate removes the existing copy of an episode that is being
+// downloaded again. Tracked downloads go to the trash so the overwrite can
+// be undone; untracked files are deleted. The manager lock must be held.
+func (m *Manager) replaceDuplicate(dup *DuplicateError) error {
+	if dup.Existing == nil {
+		if err := os.Remove(dup.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove existing copy: %w", err)
+		}
+		return nil
+	}
+
+	label := dup.Existing.MediaTitle
+	if dup.Existing.Episode > 0 {
+		label = fmt.Sprintf("%s - Episode %d", label, dup.Existing.Episode)
+	}
+	if _, err := m.trashTasks(label, dup.Existing.ID); err != nil {
+		return fmt.Errorf("failed to trash existing copy: %w", err)
+	}
+	return nil
+}
+
+// DuplicateSet is an episode downloaded more than once in the same quality
+type DuplicateSet struct {
+	Keep   DownloadTask   // The first copy, kept
+	Extras []DownloadTask // Later copies
+}
+
+// FindDuplicates returns the episodes among completed downloads that exist
+// more than once in the same quality, matched by title so copies from
+// different providers are found too. Copies whose file is gone don't count.
+func (m *Manager) FindDuplicates(ctx context.Context) ([]DuplicateSet, error) {
+	var downloads []database.Download
+	err := m.db.WithContext(ctx).
+		Where("status = ? AND file_path <> ''", string(StatusCompleted)).
+		Order("completed_at").
+		Find(&downloads).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load downloads: %w", err)
+	}
+
+	type key struct {
+		title   string
+		season  int
+		episode int
+		quality string
+	}
+	sets := make(map[key]*DuplicateSet)
+	var order []key
+	for _, d := range downloads {
+		if !fileExists(d.FilePath) {
+			continue
+		}
+		k := key{titleKey(d.MediaTitle), d.Season, d.Episode, d.Quality}
+		if k.title == "" {
+			k.title = d.Provider + "/" + d.MediaID
+		}
+		task := m.downloadToTask(d)
+		if set, ok := sets[k]; ok {
+			set.Extras = append(set.Extras, task)
+			continue
+		}
+		sets[k] = &DuplicateSet{Keep: task}
+		order = append(order, k)
+	}
+
+	var result []DuplicateSet
+	for _, k := range order {
+		if set := sets[k]; len(set.Extras) > 0 {
+			result = append(result, *set)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].Keep, result[j].Keep
+		if a.MediaTitle != b.MediaTitle {
+			return a.MediaTitle < b.MediaTitle
+		}
+		return a.Episode < b.Episode
+	})
+	return result, nil
+}
+
+// titleKey keeps only the letters and digits of a title, lowercased, so the
+// same show from two providers compares equal
+func titleKey(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
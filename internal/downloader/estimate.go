@@ -0,0 +1,80 @@
+package downloader
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/downloader/hls"
+	"github.com/justchokingaround/greg/internal/netbind"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Typical streaming bitrates in bits per second, used for size estimates
+var qualityBitrates = map[providers.Quality]int64{
+	providers.Quality360p:  600_000,
+	providers.Quality480p:  1_000_000,
+	providers.Quality720p:  2_500_000,
+	providers.Quality1080p: 5_000_000,
+	providers.Quality1440p: 9_000_000,
+	providers.Quality4K:    16_000_000,
+}
+
+// EstimateSize returns a rough file size in bytes for a video of the given
+// duration at the given quality. Unknown qualities (including auto) are
+// estimated as 1080p.
+func EstimateSize(quality providers.Quality, duration time.Duration) int64 {
+	bitrate, ok := qualityBitrates[quality]
+	if !ok {
+		bitrate = qualityBitrates[providers.Quality1080p]
+	}
+	return int64(duration.Seconds() * float64(bitrate) / 8)
+}
+
+// EstimateTaskSize estimates how many bytes a task will download: the
+// Content-Length of direct files, or bandwidth times duration for HLS
+// playlists, falling back to EstimateSize when the playlist has no
+// bandwidth. Returns 0 when nothing can be determined.
+func EstimateTaskSize(ctx context.Context, task DownloadTask) int64 {
+	if task.StreamURL == "" {
+		return 0
+	}
+
+	headers := make(map[string]string, len(task.Headers)+1)
+	for k, v := range task.Headers {
+		headers[k] = v
+	}
+	if task.Referer != "" && headers["Referer"] == "" {
+		headers["Referer"] = task.Referer
+	}
+
+	switch task.StreamType {
+	case providers.StreamTypeHLS:
+		size, duration, err := hls.NewDownloaderWithTransport(netbind.Transport(task.Provider)).EstimateSize(ctx, task.StreamURL, headers)
+		if err != nil {
+			return 0
+		}
+		if size == 0 {
+			size = EstimateSize(task.Quality, duration)
+		}
+		return size
+	case providers.StreamTypeMP4, providers.StreamTypeMKV:
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, task.StreamURL, nil)
+		if err != nil {
+			return 0
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := (&http.Client{Transport: netbind.Transport(task.Provider)}).Do(req)
+		if err != nil {
+			return 0
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return 0
+		}
+		return max(resp.ContentLength, 0)
+	}
+	return 0
+}
@@ -42,9 +42,16 @@ type Downloader struct {
 
 // NewDownloader creates a new HLS downloader
 func NewDownloader() *Downloader {
+	return NewDownloaderWithTransport(nil)
+}
+
+// NewDownloaderWithTransport creates an HLS downloader sending its requests
+// through transport, http.DefaultTransport when nil
+func NewDownloaderWithTransport(transport http.RoundTripper) *Downloader {
 	return &Downloader{
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: transport,
+			Timeout:   30 * time.Second,
 		},
 	}
 }
@@ -119,6 +126,13 @@ func (d *Downloader) parsePlaylist(ctx context.Context, url string, headers map[
 
 // selectBestStream finds the highest quality stream from a master playlist
 func (d *Downloader) selectBestStream(lines []string, baseURL string) string {
+	url, _ := bestStream(lines, baseURL)
+	return url
+}
+
+// bestStream returns the URL and bandwidth of the highest bandwidth variant
+// in a master playlist
+func bestStream(lines []string, baseURL string) (string, int) {
 	type StreamInfo struct {
 		URL       string
 		Bandwidth int
@@ -162,14 +176,50 @@ func (d *Downloader) selectBestStream(lines []string, baseURL string) string {
 				best = s
 			}
 		}
-		return best.URL
+		return best.URL, best.Bandwidth
 	}
 
-	return ""
+	return "", 0
 }
 
-// parseMediaPlaylist fetches and parses a media playlist (not master)
-func (d *Downloader) parseMediaPlaylist(ctx context.Context, url string, headers map[string]string) (*M3U8Playlist, error) {
+// EstimateSize estimates the size of a stream without downloading segments.
+// It returns the total playlist duration and, when a master playlist
+// advertises a bandwidth, the resulting size in bytes (0 otherwise).
+func (d *Downloader) EstimateSize(ctx context.Context, url string, headers map[string]string) (int64, time.Duration, error) {
+	lines, err := d.fetchLines(ctx, url, headers)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bandwidth := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#EXT-X-STREAM-INF:") {
+			url, bandwidth = bestStream(lines, url)
+			if url == "" {
+				return 0, 0, fmt.Errorf("no suitable stream found in master playlist")
+			}
+			if lines, err = d.fetchLines(ctx, url, headers); err != nil {
+				return 0, 0, err
+			}
+			break
+		}
+	}
+
+	playlist, err := d.parseMediaPlaylistLines(lines, url, headers)
+	if err != nil {
+		return 0, 0, err
+	}
+	var seconds float64
+	for _, seg := range playlist.Segments {
+		seconds += seg.Duration
+	}
+
+	duration := time.Duration(seconds * float64(time.Second))
+	return int64(seconds * float64(bandwidth) / 8), duration, nil
+}
+
+// fetchLines downloads a playlist and returns its trimmed lines
+func (d *Downloader) fetchLines(ctx context.Context, url string, headers map[string]string) ([]string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -200,11 +250,18 @@ func (d *Downloader) parseMediaPlaylist(ctx context.Context, url string, headers
 	for scanner.Scan() {
 		lines = append(lines, strings.TrimSpace(scanner.Text()))
 	}
-
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
+	return lines, nil
+}
 
+// parseMediaPlaylist fetches and parses a media playlist (not master)
+func (d *Downloader) parseMediaPlaylist(ctx context.Context, url string, headers map[string]string) (*M3U8Playlist, error) {
+	lines, err := d.fetchLines(ctx, url, headers)
+	if err != nil {
+		return nil, err
+	}
 	return d.parseMediaPlaylistLines(lines, url, headers)
 }
 
@@ -0,0 +1,119 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// ImportMode says what happens to the original of an imported file
+type ImportMode int
+
+const (
+	// ImportMove moves the file into the library
+	ImportMove ImportMode = iota
+	// ImportLink hard links the file into the library, or copies it across
+	// filesystems, so a torrent client can keep seeding the original
+	ImportLink
+)
+
+// ImportFile adds a file downloaded by another program to the library,
+// named by the filename template like a download of the task would be, and
+// records it as a completed download. The task needs the media fields and
+// episode; the file keeps its container. An episode already in the library
+// is handled like AddToQueue does, by the task's OnDuplicate.
+func (m *Manager) ImportFile(ctx context.Context, path string, task DownloadTask, mode ImportMode) (DownloadTask, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return task, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	outputPath, err := m.outputPathFor(task)
+	if err != nil {
+		return task, err
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext != "" {
+		outputPath = strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ext
+	}
+
+	if dup := m.findDuplicate(task, outputPath); dup != nil {
+		switch task.OnDuplicate {
+		case DuplicateOverwrite:
+			if err := m.replaceDuplicate(dup); err != nil {
+				return task, err
+			}
+		case DuplicateKeepBoth:
+		default:
+			return task, dup
+		}
+	}
+
+	task.OutputPath = EnsureUniqueFilename(outputPath)
+	place := moveFile
+	if mode == ImportLink {
+		place = linkFile
+	}
+	if err := place(path, task.OutputPath); err != nil {
+		return task, fmt.Errorf("failed to add file to the library: %w", err)
+	}
+
+	now := time.Now()
+	task.Status = StatusCompleted
+	task.Progress = 100
+	task.TotalBytes = info.Size()
+	task.BytesDownloaded = info.Size()
+	task.CreatedAt = now
+	task.CompletedAt = &now
+	if err := m.addTaskToDB(task); err != nil {
+		// Leave the file where the caller can find it again
+		if mode == ImportLink {
+			_ = os.Remove(task.OutputPath)
+		} else {
+			_ = moveFile(task.OutputPath, path)
+		}
+		return task, fmt.Errorf("failed to save download to database: %w", err)
+	}
+
+	m.logger.Info("imported file", "path", path, "output", task.OutputPath, "title", task.MediaTitle, "episode", task.Episode)
+	go m.triggerCompleteCallback(task)
+	return task, nil
+}
+
+// FindSeries returns the latest completed download of a show, matched by
+// title like duplicates are, so imported files can join a series greg
+// downloaded before
+func (m *Manager) FindSeries(ctx context.Context, title string) (*DownloadTask, error) {
+	key := titleKey(title)
+	if key == "" {
+		return nil, nil
+	}
+
+	var downloads []database.Download
+	err := m.db.WithContext(ctx).
+		Where("status = ?", string(StatusCompleted)).
+		Order("completed_at DESC").
+		Find(&downloads).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load downloads: %w", err)
+	}
+	for _, d := range downloads {
+		if titleKey(d.MediaTitle) == key {
+			task := m.downloadToTask(d)
+			return &task, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,118 @@
+package downloader
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/introdetect"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// maxIntroCandidates is how many other episodes of the series are compared
+// with a new download before giving up on finding its opening
+const maxIntroCandidates = 3
+
+// markIntro finds the opening of a finished anime episode by comparing its
+// audio with other downloaded episodes of the series and writes it as
+// chapters, into the other episode too when it has none yet. Failing to
+// find it doesn't fail the download.
+func (w *worker) markIntro(ctx context.Context, task *DownloadTask) {
+	m := w.manager
+	if !m.config.IntroChapters || task.MediaType != providers.MediaTypeAnime || !m.ffmpeg.Available {
+		return
+	}
+	if introdetect.HasChapters(ctx, m.ffprobe, task.OutputPath) {
+		return
+	}
+
+	candidates := m.introCandidates(task)
+	if len(candidates) == 0 {
+		return
+	}
+
+	task.Status = StatusProcessing
+	_ = m.updateTaskInDB(*task)
+	m.triggerProgressCallback(*task)
+
+	fp, err := introdetect.FingerprintFile(ctx, m.ffmpeg.Binary, task.OutputPath)
+	if err != nil {
+		w.logger.Warn("failed to fingerprint episode audio", "task_id", task.ID, "error", err)
+		return
+	}
+
+	for _, other := range candidates {
+		otherFP, err := introdetect.FingerprintFile(ctx, m.ffmpeg.Binary, other.FilePath)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			w.logger.Debug("failed to fingerprint episode audio", "path", other.FilePath, "error", err)
+			continue
+		}
+		intro, ok := introdetect.Match(fp, otherFP)
+		if !ok {
+			continue
+		}
+
+		w.logger.Info("found opening", "task_id", task.ID, "start", intro.A.Start, "end", intro.A.End, "compared_with", other.Episode)
+		if err := w.writeIntro(ctx, task.OutputPath, intro.A); err != nil {
+			w.logger.Warn("failed to write opening chapters", "task_id", task.ID, "error", err)
+			return
+		}
+		if !introdetect.HasChapters(ctx, m.ffprobe, other.FilePath) {
+			if err := w.writeIntro(ctx, other.FilePath, intro.B); err != nil {
+				w.logger.Warn("failed to write opening chapters", "path", other.FilePath, "error", err)
+			}
+		}
+		return
+	}
+	w.logger.Debug("no opening shared with other episodes", "task_id", task.ID)
+}
+
+// writeIntro writes the chapters of an opening into path
+func (w *worker) writeIntro(ctx context.Context, path string, opening introdetect.Segment) error {
+	duration := opening.End
+	if w.manager.ffprobe != "" {
+		if d, err := probeDuration(ctx, w.manager.ffprobe, path); err == nil && d > 0 {
+			duration = d
+		}
+	}
+	return introdetect.WriteChapters(ctx, w.manager.ffmpeg.Binary, path, opening, duration)
+}
+
+// introCandidates returns completed downloads of the task's series and
+// season that are still on disk, nearest episodes first since openings
+// change between cours
+func (m *Manager) introCandidates(task *DownloadTask) []database.Download {
+	var downloads []database.Download
+	err := m.db.Where("media_id = ? AND provider = ? AND season = ? AND episode <> ? AND status = ? AND file_path <> ''",
+		task.MediaID, task.Provider, task.Season, task.Episode, string(StatusCompleted)).
+		Find(&downloads).Error
+	if err != nil {
+		m.logger.Warn("failed to look up downloaded episodes", "error", err)
+		return nil
+	}
+
+	sort.Slice(downloads, func(i, j int) bool {
+		return abs(downloads[i].Episode-task.Episode) < abs(downloads[j].Episode-task.Episode)
+	})
+	var candidates []database.Download
+	for _, d := range downloads {
+		if len(candidates) == maxIntroCandidates {
+			break
+		}
+		if _, err := os.Stat(d.FilePath); err == nil {
+			candidates = append(candidates, d)
+		}
+	}
+	return candidates
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
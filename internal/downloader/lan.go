@@ -0,0 +1,153 @@
+package downloader
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/lanshare"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+const (
+	// peerBrowseWait is how long to wait for instances to answer mDNS
+	peerBrowseWait = 1500 * time.Millisecond
+
+	// peerCacheTTL is how long discovered instances are reused before the
+	// network is asked again
+	peerCacheTTL = time.Minute
+
+	// peerProgressInterval throttles progress updates of pulls
+	peerProgressInterval = 500 * time.Millisecond
+)
+
+// lanState is the manager's side of sharing downloads on the network
+type lanState struct {
+	server *lanshare.Server
+	client *lanshare.Client
+
+	mu      sync.Mutex
+	peers   []lanshare.Peer
+	peersAt time.Time
+}
+
+// startSharing serves completed downloads to other instances when
+// downloads.lan.share is on. Failing to share doesn't stop downloads.
+func (m *Manager) startSharing() {
+	if !m.config.LAN.Share || m.lan.server != nil {
+		return
+	}
+	server := lanshare.NewServer(m.db, m.config.LAN.Token)
+	if err := server.Listen(m.config.LAN.Listen); err != nil {
+		m.logger.Warn("failed to share downloads on the network", "error", err)
+		return
+	}
+	if err := server.Advertise(); err != nil {
+		m.logger.Warn("share server running but not discoverable", "addr", server.Addr(), "error", err)
+	}
+	m.lan.server = server
+	m.logger.Info("sharing downloads on the network", "addr", server.Addr())
+}
+
+// stopSharing stops the share server
+func (m *Manager) stopSharing() {
+	if m.lan.server == nil {
+		return
+	}
+	if err := m.lan.server.Close(); err != nil {
+		m.logger.Warn("failed to stop share server", "error", err)
+	}
+	m.lan.server = nil
+}
+
+// peers returns the other instances on the network, browsing again once
+// the last answers are older than peerCacheTTL
+func (m *Manager) peers(ctx context.Context) []lanshare.Peer {
+	m.lan.mu.Lock()
+	defer m.lan.mu.Unlock()
+
+	if time.Since(m.lan.peersAt) < peerCacheTTL {
+		return m.lan.peers
+	}
+	found, err := lanshare.Browse(ctx, peerBrowseWait)
+	if err != nil {
+		m.logger.Warn("failed to look for other instances", "error", err)
+	}
+
+	ownID := ""
+	if m.lan.server != nil {
+		ownID = m.lan.server.ID()
+	}
+	var peers []lanshare.Peer
+	for _, peer := range found {
+		if peer.ID != ownID {
+			peers = append(peers, peer)
+		}
+	}
+	m.lan.peers, m.lan.peersAt = peers, time.Now()
+	return peers
+}
+
+// pullFromPeer copies the task's episode from another instance that already
+// downloaded it. It reports false when no instance has it or the transfer
+// failed, so the episode is downloaded from the provider instead.
+func (w *worker) pullFromPeer(ctx context.Context, task *DownloadTask) bool {
+	m := w.manager
+	if !m.config.LAN.Pull || task.Clip != nil || task.MediaType == providers.MediaTypeManga {
+		return false
+	}
+
+	peers := m.peers(ctx)
+	if len(peers) == 0 {
+		return false
+	}
+	query := lanshare.Query{
+		MediaID:   task.MediaID,
+		Provider:  task.Provider,
+		Title:     task.MediaTitle,
+		MediaType: string(task.MediaType),
+		Season:    task.Season,
+		Episode:   task.Episode,
+	}
+	peer, file, ok := m.lan.client.Find(ctx, peers, query)
+	if !ok {
+		return false
+	}
+
+	// Keep the container of the shared file
+	if ext := filepath.Ext(file.Name); ext != "" && !strings.EqualFold(ext, filepath.Ext(task.OutputPath)) {
+		task.OutputPath = EnsureUniqueFilename(strings.TrimSuffix(task.OutputPath, filepath.Ext(task.OutputPath)) + ext)
+	}
+
+	w.logger.Info("pulling episode from another instance", "task_id", task.ID, "peer", peer.Name, "file", file.Name)
+	task.Error = "Copying from " + peer.Name
+	task.TotalBytes = file.Size
+	_ = m.updateTaskInDB(*task)
+
+	lastUpdate := time.Time{}
+	err := m.lan.client.Download(ctx, peer, file, task.OutputPath, func(written, total int64) {
+		m.mu.Lock()
+		task.BytesDownloaded = written
+		if total > 0 {
+			task.Progress = float64(written) / float64(total) * 100
+		}
+		m.mu.Unlock()
+		if time.Since(lastUpdate) >= peerProgressInterval {
+			lastUpdate = time.Now()
+			m.triggerProgressCallback(*task)
+		}
+	})
+	if err != nil {
+		w.logger.Warn("failed to pull episode, downloading from the provider", "task_id", task.ID, "peer", peer.Name, "error", err)
+		task.Error = ""
+		task.BytesDownloaded = 0
+		task.Progress = 0
+		return false
+	}
+
+	task.Error = ""
+	task.Peer = peer.Name
+	return true
+}
@@ -12,7 +12,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader/remote"
 	"github.com/justchokingaround/greg/internal/downloader/tools"
+	"github.com/justchokingaround/greg/internal/downloader/xdcc"
+	"github.com/justchokingaround/greg/internal/lanshare"
 	"github.com/justchokingaround/greg/internal/providers"
 	"gorm.io/gorm"
 )
@@ -22,10 +25,11 @@ type Manager struct {
 	mu sync.RWMutex
 
 	// Worker pool
-	workers  []*worker
-	queue    chan *DownloadTask
-	active   map[string]*activeDownload // task ID -> active download info
-	workerWg sync.WaitGroup             // Wait group for workers
+	workers      []*worker
+	nextWorkerID int
+	queue        *taskQueue
+	active       map[string]*activeDownload // task ID -> active download info
+	workerWg     sync.WaitGroup             // Wait group for workers
 
 	// State
 	running bool
@@ -49,6 +53,18 @@ type Manager struct {
 	// Tools (still maintained for backward compatibility)
 	ytdlp  *tools.ToolInfo
 	ffmpeg *tools.ToolInfo
+
+	// ffprobe binary used to check finished downloads, empty if not installed
+	ffprobe string
+
+	// Sharing downloads with other instances on the network
+	lan lanState
+
+	// Remote storage finished downloads are uploaded to, nil when not set
+	remote *remote.Target
+
+	// Speed of each download over the last SpeedWindow
+	speeds *speedHistory
 }
 
 // activeDownload tracks an in-progress download
@@ -82,6 +98,10 @@ func NewManager(db *gorm.DB, cfg *config.DownloadsConfig, logger *slog.Logger) (
 		ffmpeg = &tools.ToolInfo{Type: tools.ToolFFmpeg, Available: false}
 	}
 
+	// ffprobe is optional; without it downloads are only checked by size
+	// and checksum
+	ffprobe, _ := tools.FindTool("ffprobe")
+
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(cfg.Path, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
@@ -90,15 +110,27 @@ func NewManager(db *gorm.DB, cfg *config.DownloadsConfig, logger *slog.Logger) (
 	ctx, cancel := context.WithCancel(context.Background())
 
 	m := &Manager{
-		queue:  make(chan *DownloadTask, 100), // Buffered queue
-		active: make(map[string]*activeDownload),
-		config: cfg,
-		logger: logger,
-		db:     db,
-		ytdlp:  ytdlp,
-		ffmpeg: ffmpeg,
-		ctx:    ctx,
-		cancel: cancel,
+		queue:   newTaskQueue(),
+		active:  make(map[string]*activeDownload),
+		speeds:  newSpeedHistory(),
+		config:  cfg,
+		logger:  logger,
+		db:      db,
+		ytdlp:   ytdlp,
+		ffmpeg:  ffmpeg,
+		ffprobe: ffprobe,
+		ctx:     ctx,
+		cancel:  cancel,
+		lan:     lanState{client: lanshare.NewClient(cfg.LAN.Token)},
+	}
+
+	if cfg.Remote.Type != "" {
+		target, err := remote.New(cfg.Remote)
+		if err != nil {
+			logger.Warn("remote uploads disabled", "error", err)
+		} else {
+			m.remote = target
+		}
 	}
 
 	// Load existing queued/paused downloads from database
@@ -121,22 +153,30 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.running = true
 	m.startWorkerPool()
 
+	if m.config.Cleanup.Days > 0 {
+		go m.runCleanup(m.ctx)
+	}
+	go m.speeds.run(m.ctx)
+	m.startSharing()
+
 	return nil
 }
 
-// Stop stops the download manager and all workers
+// Stop stops the download manager and all workers. Active downloads are
+// paused in the database so they resume from their partial files next time.
 func (m *Manager) Stop() error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	if !m.running {
+		m.mu.Unlock()
 		return nil
 	}
 
 	m.running = false
 
-	// Cancel all active downloads
+	// Checkpoint and cancel all active downloads
 	for _, ad := range m.active {
+		ad.task.Status = StatusPaused
+		_ = m.updateTaskInDB(*ad.task)
 		if ad.cancel != nil {
 			ad.cancel()
 		}
@@ -147,41 +187,51 @@ func (m *Manager) Stop() error {
 		m.cancel()
 	}
 
-	// Close queue channel
-	close(m.queue)
+	// Wake idle workers
+	m.queue.close()
+	m.stopSharing()
+	m.mu.Unlock()
 
-	// Wait for all workers to finish
+	// Wait for all workers to finish. Workers take the lock to unregister
+	// their tasks, so it must not be held here.
 	m.workerWg.Wait()
 
-	// Update all active downloads to paused in database
-	for _, ad := range m.active {
-		ad.task.Status = StatusPaused
-		_ = m.updateTaskInDB(*ad.task)
-	}
-
 	return nil
 }
 
-// AddToQueue adds a new download task to the queue
+// AddToQueue adds a new download task to the queue. It fails with
+// ErrInsufficientSpace when the estimated size would leave less than
+// downloads.min_free_space free.
 func (m *Manager) AddToQueue(ctx context.Context, task DownloadTask) error {
+	// Estimate the size up front, outside the lock, since it may hit the network
+	var estimate int64
+	if m.config.MinFreeSpace > 0 {
+		estimateCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		estimate = EstimateTaskSize(estimateCtx, task)
+		cancel()
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check for duplicate by MediaID + Episode (before generating new ID)
-	// This prevents re-downloading the same episode
-	var existingDownload database.Download
-	err := m.db.Where("media_id = ? AND episode = ? AND season = ?",
+	// An episode already queued isn't queued twice; failed and cancelled
+	// attempts are replaced. Completed ones are checked for duplicates
+	// once the output path is known.
+	var existingDownloads []database.Download
+	if err := m.db.Where("media_id = ? AND episode = ? AND season = ?",
 		task.MediaID, task.Episode, task.Season).
-		First(&existingDownload).Error
-	if err == nil {
-		// Episode exists - check if we should skip it
-		if existingDownload.Status != string(StatusFailed) &&
-			existingDownload.Status != string(StatusCancelled) {
-			return fmt.Errorf("episode %d already in queue or downloaded (status: %s)",
-				task.Episode, existingDownload.Status)
+		Find(&existingDownloads).Error; err != nil {
+		return fmt.Errorf("failed to check existing downloads: %w", err)
+	}
+	for _, existing := range existingDownloads {
+		switch DownloadStatus(existing.Status) {
+		case StatusFailed, StatusCancelled:
+			m.db.Delete(&existing)
+		case StatusCompleted:
+		default:
+			return fmt.Errorf("episode %d already in queue (status: %s)",
+				task.Episode, existing.Status)
 		}
-		// Episode failed/cancelled - delete old entry and re-add
-		m.db.Delete(&existingDownload)
 	}
 
 	// Generate unique ID if not provided
@@ -209,13 +259,56 @@ func (m *Manager) AddToQueue(ctx context.Context, task DownloadTask) error {
 	}
 
 	// Check disk space
-	if m.config.MinFreeSpace > 0 {
-		if err := m.checkDiskSpace(); err != nil {
-			return fmt.Errorf("insufficient disk space: %w", err)
+	if err := m.checkDiskSpace(estimate); err != nil {
+		return err
+	}
+
+	outputPath, err := m.outputPathFor(task)
+	if err != nil {
+		return err
+	}
+
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Ask before downloading an episode that is already on disk
+	if dup := m.findDuplicate(task, outputPath); dup != nil {
+		switch task.OnDuplicate {
+		case DuplicateOverwrite:
+			if err := m.replaceDuplicate(dup); err != nil {
+				return err
+			}
+		case DuplicateKeepBoth:
+		default:
+			return dup
 		}
 	}
 
-	// Generate output path from template
+	task.OutputPath = EnsureUniqueFilename(outputPath)
+
+	// Set embed subtitles from config if not explicitly set
+	if !task.EmbedSubs {
+		task.EmbedSubs = m.config.EmbedSubtitles
+	}
+
+	// Save to database
+	if err := m.addTaskToDB(task); err != nil {
+		return fmt.Errorf("failed to save task to database: %w", err)
+	}
+
+	// Add to queue if manager is running
+	if m.running {
+		m.queue.push(&task)
+	}
+
+	return nil
+}
+
+// outputPathFor returns where a task is saved: the filename template inside
+// the folder for its media type
+func (m *Manager) outputPathFor(task DownloadTask) (string, error) {
 	template := GetTemplateForMediaType(
 		task.MediaType,
 		m.config.AnimeFilenameTemplate,
@@ -225,7 +318,7 @@ func (m *Manager) AddToQueue(ctx context.Context, task DownloadTask) error {
 
 	filename, err := ParseTemplate(template, task)
 	if err != nil {
-		return fmt.Errorf("failed to parse filename template: %w", err)
+		return "", fmt.Errorf("failed to parse filename template: %w", err)
 	}
 
 	// Create proper folder structure based on media type
@@ -261,37 +354,7 @@ func (m *Manager) AddToQueue(ctx context.Context, task DownloadTask) error {
 		outputPath = filepath.Join(m.config.Path, "downloads", filename)
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	task.OutputPath = EnsureUniqueFilename(outputPath)
-
-	// Set embed subtitles from config if not explicitly set
-	if !task.EmbedSubs {
-		task.EmbedSubs = m.config.EmbedSubtitles
-	}
-
-	// Save to database
-	if err := m.addTaskToDB(task); err != nil {
-		return fmt.Errorf("failed to save task to database: %w", err)
-	}
-
-	// Add to queue if manager is running
-	if m.running {
-		select {
-		case m.queue <- &task:
-			// Task added successfully
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			// Queue is full, but task is saved to database
-			// It will be picked up when queue has space
-		}
-	}
-
-	return nil
+	return outputPath, nil
 }
 
 // RemoveFromQueue removes a task from the queue
@@ -307,6 +370,7 @@ func (m *Manager) RemoveFromQueue(ctx context.Context, id string) error {
 		}
 		delete(m.active, id)
 	}
+	m.queue.remove(id)
 
 	// Update database
 	return m.deleteTaskFromDB(id)
@@ -328,6 +392,57 @@ func (m *Manager) GetQueue(ctx context.Context) ([]DownloadTask, error) {
 	return tasks, nil
 }
 
+// MoveInQueue moves a queued task offset places through the queue; negative
+// offsets move it towards the front. The new order is stored as task
+// priorities so it survives restarts.
+func (m *Manager) MoveInQueue(ctx context.Context, id string, offset int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var queued []database.Download
+	if err := m.db.Where("status = ?", string(StatusQueued)).
+		Order("priority DESC, created_at ASC").Find(&queued).Error; err != nil {
+		return fmt.Errorf("failed to get queued tasks: %w", err)
+	}
+
+	from := -1
+	for i, d := range queued {
+		if d.ID == id {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return fmt.Errorf("task is not queued: %s", id)
+	}
+
+	to := min(max(from+offset, 0), len(queued)-1)
+	if to == from {
+		return nil
+	}
+	moved := queued[from]
+	queued = append(queued[:from], queued[from+1:]...)
+	queued = append(queued[:to], append([]database.Download{moved}, queued[to:]...)...)
+
+	// Renumber so the front of the queue has the highest priority
+	if err := database.Write(m.db, func(tx *gorm.DB) error {
+		for i, d := range queued {
+			if err := tx.Model(&database.Download{}).Where("id = ?", d.ID).
+				Update("priority", len(queued)-i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to update priorities: %w", err)
+	}
+
+	for i, d := range queued {
+		m.queue.setPriority(d.ID, len(queued)-i)
+	}
+	return nil
+}
+
 // HasActiveDownloads returns true if there are any downloads currently in progress
 func (m *Manager) HasActiveDownloads() bool {
 	m.mu.RLock()
@@ -344,6 +459,7 @@ func (m *Manager) HasActiveDownloads() bool {
 		Where("status IN ?", []string{
 			string(StatusDownloading),
 			string(StatusProcessing),
+			string(StatusUploading),
 			string(StatusQueued),
 		}).
 		Count(&count)
@@ -368,6 +484,7 @@ func (m *Manager) ClearQueue(ctx context.Context) error {
 			// Only delete queued, completed, failed, or cancelled tasks
 			status := DownloadStatus(d.Status)
 			if status == StatusQueued || status.IsComplete() {
+				m.queue.remove(d.ID)
 				if err := m.db.Delete(&d).Error; err != nil {
 					return fmt.Errorf("failed to delete task %s: %w", d.ID, err)
 				}
@@ -416,19 +533,14 @@ func (m *Manager) Resume(ctx context.Context, id string) error {
 
 	// Update status and re-queue
 	task.Status = StatusQueued
+	task.Error = ""
 	if err := m.updateTaskInDB(task); err != nil {
 		return err
 	}
 
 	// Add back to queue if running
 	if m.running {
-		select {
-		case m.queue <- &task:
-			// Task re-queued successfully
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+		m.queue.push(&task)
 	}
 
 	return nil
@@ -464,12 +576,7 @@ func (m *Manager) ResumeAll(ctx context.Context) error {
 		_ = m.updateTaskInDB(task)
 
 		if m.running {
-			select {
-			case m.queue <- &task:
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+			m.queue.push(&task)
 		}
 	}
 
@@ -488,6 +595,7 @@ func (m *Manager) Cancel(ctx context.Context, id string) error {
 		}
 		delete(m.active, id)
 	}
+	m.queue.remove(id)
 
 	// Get from database
 	var download database.Download
@@ -529,17 +637,60 @@ func (m *Manager) Retry(ctx context.Context, id string) error {
 
 	// Add back to queue if running
 	if m.running {
-		select {
-		case m.queue <- &task:
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+		m.queue.push(&task)
 	}
 
 	return nil
 }
 
+// Requeue queues a failed or cancelled task again from another source. The
+// provider, media ID, stream and subtitles of source replace the task's;
+// it keeps its ID, episode, quality and output path.
+func (m *Manager) Requeue(ctx context.Context, id string, source DownloadTask) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var download database.Download
+	if err := m.db.First(&download, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("task not found: %w", err)
+	}
+	if download.Status != string(StatusFailed) && download.Status != string(StatusCancelled) {
+		return fmt.Errorf("can only requeue failed or cancelled tasks")
+	}
+
+	task := m.downloadToTask(download)
+	task.MediaID = source.MediaID
+	task.Provider = source.Provider
+	task.StreamURL = source.StreamURL
+	task.StreamType = source.StreamType
+	task.Headers = source.Headers
+	task.Referer = source.Referer
+	task.Subtitles = source.Subtitles
+	task.EmbedSubs = source.EmbedSubs
+
+	// Start over, nothing of the old source carries over
+	task.Status = StatusQueued
+	task.Error = ""
+	task.Diagnostics = nil
+	task.Progress = 0
+	task.BytesDownloaded = 0
+	task.TotalBytes = 0
+	task.Speed = 0
+	task.ExpectedSize = 0
+	task.ExpectedDuration = 0
+	task.Checksum = ""
+	task.StartedAt = nil
+	task.CompletedAt = nil
+	if err := m.updateTaskInDB(task); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	if m.running {
+		m.queue.push(&task)
+	}
+	return nil
+}
+
 // DeleteTaskAndFile deletes a task and its associated file
 func (m *Manager) DeleteTaskAndFile(ctx context.Context, id string) error {
 	m.mu.Lock()
@@ -552,6 +703,7 @@ func (m *Manager) DeleteTaskAndFile(ctx context.Context, id string) error {
 		}
 		delete(m.active, id)
 	}
+	m.queue.remove(id)
 
 	// Get task to find file path
 	var download database.Download
@@ -574,6 +726,65 @@ func (m *Manager) DeleteTaskAndFile(ctx context.Context, id string) error {
 	return nil
 }
 
+// TrashTasks cancels tasks and moves them, with their files, to the trash so
+// the deletion can be undone with database.RestoreTrash. Files are kept in
+// .trash under the download directory until the trash is purged.
+func (m *Manager) TrashTasks(ctx context.Context, label string, ids ...string) (*database.TrashItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.trashTasks(label, ids...)
+}
+
+// trashTasks is TrashTasks with the manager lock held
+func (m *Manager) trashTasks(label string, ids ...string) (*database.TrashItem, error) {
+	var downloads []database.Download
+	if err := m.db.Where("id IN ?", ids).Find(&downloads).Error; err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	var files []database.TrashedFile
+	for _, download := range downloads {
+		if ad, exists := m.active[download.ID]; exists {
+			if ad.cancel != nil {
+				ad.cancel()
+			}
+			delete(m.active, download.ID)
+		}
+		m.queue.remove(download.ID)
+
+		// Restored tasks come back paused instead of restarting on their own
+		status := DownloadStatus(download.Status)
+		if !status.IsComplete() && status != StatusPaused {
+			if err := database.Write(m.db, func(tx *gorm.DB) error {
+				return tx.Model(&database.Download{}).Where("id = ?", download.ID).Update("status", string(StatusPaused)).Error
+			}); err != nil {
+				return nil, fmt.Errorf("failed to pause task: %w", err)
+			}
+		}
+
+		if download.FilePath == "" {
+			continue
+		}
+		trashed := filepath.Join(m.config.Path, ".trash", download.ID, filepath.Base(download.FilePath))
+		if err := os.MkdirAll(filepath.Dir(trashed), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create trash directory: %w", err)
+		}
+		if err := os.Rename(download.FilePath, trashed); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to move file to trash: %w", err)
+		}
+		files = append(files, database.TrashedFile{Original: download.FilePath, Trashed: trashed})
+	}
+
+	item, err := database.TrashDownloads(m.db, label, ids, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trash tasks: %w", err)
+	}
+	return item, nil
+}
+
 // OnProgressUpdate sets the progress update callback
 func (m *Manager) OnProgressUpdate(callback func(task DownloadTask)) {
 	m.mu.Lock()
@@ -595,22 +806,47 @@ func (m *Manager) OnDownloadError(callback func(task DownloadTask, err error)) {
 	m.onError = callback
 }
 
-// SetConcurrency sets the number of concurrent download workers
+const (
+	// MaxConcurrency is the most downloads SetConcurrency allows at once
+	MaxConcurrency = 10
+	// defaultConcurrency is how many downloads run at once when
+	// downloads.concurrent isn't set
+	defaultConcurrency = 3
+)
+
+// SetConcurrency sets the number of concurrent download workers. While
+// running, workers are started or retired right away; a retired worker
+// finishes its current download first.
 func (m *Manager) SetConcurrency(workers int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if workers < 1 {
-		workers = 1
+	workers = min(max(workers, 1), MaxConcurrency)
+	m.config.Concurrent = workers
+	if !m.running {
+		return
 	}
-	if workers > 10 {
-		workers = 10 // Reasonable maximum
+	for len(m.workers) < workers {
+		m.startWorker()
 	}
+	for len(m.workers) > workers {
+		last := m.workers[len(m.workers)-1]
+		last.retire()
+		m.workers = m.workers[:len(m.workers)-1]
+	}
+}
 
-	m.config.Concurrent = workers
-
-	// If running, config takes effect on next Start()
-	_ = m.running
+// Concurrency returns how many downloads run at once
+func (m *Manager) Concurrency() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.running {
+		return len(m.workers)
+	}
+	if m.config.Concurrent < 1 {
+		return defaultConcurrency
+	}
+	return m.config.Concurrent
 }
 
 // SetOutputDir sets the output directory for downloads
@@ -636,22 +872,28 @@ func (m *Manager) SetMaxSpeed(bytesPerSecond int64) {
 func (m *Manager) startWorkerPool() {
 	workerCount := m.config.Concurrent
 	if workerCount < 1 {
-		workerCount = 3 // Default
+		workerCount = defaultConcurrency
 	}
 
-	m.workers = make([]*worker, workerCount)
-	for i := 0; i < workerCount; i++ {
-		w := newWorker(i, m)
-		m.workers[i] = w
-
-		m.workerWg.Add(1)
-		go func() {
-			defer m.workerWg.Done()
-			w.run(m.ctx, m.queue)
-		}()
+	m.workers = make([]*worker, 0, workerCount)
+	for range workerCount {
+		m.startWorker()
 	}
 }
 
+// startWorker starts one more worker (must be called with lock held)
+func (m *Manager) startWorker() {
+	w := newWorker(m.nextWorkerID, m)
+	m.nextWorkerID++
+	m.workers = append(m.workers, w)
+
+	m.workerWg.Add(1)
+	go func() {
+		defer m.workerWg.Done()
+		w.run(m.ctx, m.queue)
+	}()
+}
+
 // loadQueueFromDB loads pending/paused tasks from database
 func (m *Manager) loadQueueFromDB() error {
 	var downloads []database.Download
@@ -666,6 +908,13 @@ func (m *Manager) loadQueueFromDB() error {
 		return fmt.Errorf("failed to load downloads: %w", err)
 	}
 
+	// An upload cut short by a crash left a complete local file
+	if err := m.db.Model(&database.Download{}).
+		Where("status = ?", string(StatusUploading)).
+		Updates(map[string]any{"status": string(StatusCompleted), "progress": 100, "error": "upload interrupted, the file is only kept locally"}).Error; err != nil {
+		return fmt.Errorf("failed to load downloads: %w", err)
+	}
+
 	// Convert to tasks and optionally auto-resume
 	for _, d := range downloads {
 		task := m.downloadToTask(d)
@@ -679,11 +928,7 @@ func (m *Manager) loadQueueFromDB() error {
 		// Add to queue if status is queued and auto-resume is enabled
 		if m.config.AutoResume && task.Status == StatusQueued {
 			// Will be picked up by workers when started
-			select {
-			case m.queue <- &task:
-			default:
-				// Queue is full, task will remain in database
-			}
+			m.queue.push(&task)
 		}
 	}
 
@@ -693,7 +938,7 @@ func (m *Manager) loadQueueFromDB() error {
 // addTaskToDB adds a task to the database
 func (m *Manager) addTaskToDB(task DownloadTask) error {
 	download := m.taskToDownload(task)
-	if err := m.db.Create(&download).Error; err != nil {
+	if err := database.Write(m.db, func(tx *gorm.DB) error { return tx.Create(&download).Error }); err != nil {
 		m.logger.Error("FAILED TO CREATE DOWNLOAD IN DB", "error", err, "task_id", task.ID)
 		return err
 	}
@@ -704,7 +949,7 @@ func (m *Manager) addTaskToDB(task DownloadTask) error {
 // updateTaskInDB updates a task in the database
 func (m *Manager) updateTaskInDB(task DownloadTask) error {
 	download := m.taskToDownload(task)
-	if err := m.db.Save(&download).Error; err != nil {
+	if err := database.Write(m.db, func(tx *gorm.DB) error { return tx.Save(&download).Error }); err != nil {
 		m.logger.Error("FAILED TO UPDATE DOWNLOAD IN DB", "error", err, "task_id", task.ID)
 		return err
 	}
@@ -714,7 +959,9 @@ func (m *Manager) updateTaskInDB(task DownloadTask) error {
 
 // deleteTaskFromDB deletes a task from the database
 func (m *Manager) deleteTaskFromDB(id string) error {
-	return m.db.Delete(&database.Download{}, "id = ?", id).Error
+	return database.Write(m.db, func(tx *gorm.DB) error {
+		return tx.Delete(&database.Download{}, "id = ?", id).Error
+	})
 }
 
 // taskToDownload converts a DownloadTask to database.Download
@@ -729,12 +976,18 @@ func (m *Manager) taskToDownload(task DownloadTask) database.Download {
 		Quality:         string(task.Quality),
 		Provider:        task.Provider,
 		Status:          string(task.Status),
+		Priority:        task.Priority,
 		Progress:        task.Progress,
 		BytesDownloaded: task.BytesDownloaded,
 		TotalBytes:      task.TotalBytes,
 		Speed:           task.Speed,
 		Error:           task.Error,
+		Diagnostics:     encodeDiagnostics(task.Diagnostics),
 		FilePath:        task.OutputPath,
+		ExpectedSize:    task.ExpectedSize,
+		ExpectedSeconds: int(task.ExpectedDuration.Seconds()),
+		Checksum:        task.Checksum,
+		RemotePath:      task.RemotePath,
 		CreatedAt:       task.CreatedAt,
 		StartedAt:       task.StartedAt,
 		CompletedAt:     task.CompletedAt,
@@ -743,26 +996,41 @@ func (m *Manager) taskToDownload(task DownloadTask) database.Download {
 
 // downloadToTask converts a database.Download to DownloadTask
 func (m *Manager) downloadToTask(download database.Download) DownloadTask {
-	return DownloadTask{
-		ID:              download.ID,
-		MediaID:         download.MediaID,
-		MediaTitle:      download.MediaTitle,
-		MediaType:       providers.MediaType(download.MediaType),
-		Episode:         download.Episode,
-		Season:          download.Season,
-		Quality:         providers.Quality(download.Quality),
-		Provider:        download.Provider,
-		Status:          DownloadStatus(download.Status),
-		Progress:        download.Progress,
-		BytesDownloaded: download.BytesDownloaded,
-		TotalBytes:      download.TotalBytes,
-		Speed:           download.Speed,
-		Error:           download.Error,
-		OutputPath:      download.FilePath,
-		CreatedAt:       download.CreatedAt,
-		StartedAt:       download.StartedAt,
-		CompletedAt:     download.CompletedAt,
-	}
+	task := DownloadTask{
+		ID:               download.ID,
+		MediaID:          download.MediaID,
+		MediaTitle:       download.MediaTitle,
+		MediaType:        providers.MediaType(download.MediaType),
+		Episode:          download.Episode,
+		Season:           download.Season,
+		Quality:          providers.Quality(download.Quality),
+		Provider:         download.Provider,
+		Status:           DownloadStatus(download.Status),
+		Priority:         download.Priority,
+		Progress:         download.Progress,
+		BytesDownloaded:  download.BytesDownloaded,
+		TotalBytes:       download.TotalBytes,
+		Speed:            download.Speed,
+		Error:            download.Error,
+		Diagnostics:      decodeDiagnostics(download.Diagnostics),
+		OutputPath:       download.FilePath,
+		ExpectedSize:     download.ExpectedSize,
+		ExpectedDuration: time.Duration(download.ExpectedSeconds) * time.Second,
+		Checksum:         download.Checksum,
+		RemotePath:       download.RemotePath,
+		CreatedAt:        download.CreatedAt,
+		StartedAt:        download.StartedAt,
+		CompletedAt:      download.CompletedAt,
+	}
+
+	// Stream URLs aren't stored, but XDCC tasks use the pack URL as media ID,
+	// so they can be resumed after a restart
+	if xdcc.IsURL(download.MediaID) {
+		task.StreamURL = download.MediaID
+		task.StreamType = providers.StreamTypeXDCC
+	}
+
+	return task
 }
 
 // checkDiskSpace checks if there's enough free disk space
@@ -770,6 +1038,8 @@ func (m *Manager) downloadToTask(download database.Download) DownloadTask {
 
 // triggerProgressCallback safely triggers the progress callback
 func (m *Manager) triggerProgressCallback(task DownloadTask) {
+	m.speeds.report(task, time.Now())
+
 	m.mu.RLock()
 	callback := m.onProgress
 	m.mu.RUnlock()
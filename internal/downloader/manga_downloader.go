@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 )
 
@@ -80,7 +81,7 @@ func (m *Manager) DownloadMangaChapter(ctx context.Context, provider providers.M
 
 			// Download page
 			pagePath := filepath.Join(tempDir, fmt.Sprintf("page_%04d.jpg", index+1))
-			err := m.downloadMangaPage(ctx, url, pagePath, task.Headers, task.Referer)
+			err := m.downloadMangaPage(ctx, task.Provider, url, pagePath, task.Headers, task.Referer)
 
 			results <- downloadResult{
 				index: index,
@@ -125,8 +126,8 @@ func (m *Manager) DownloadMangaChapter(ctx context.Context, provider providers.M
 	}
 }
 
-// downloadMangaPage downloads a single manga page
-func (m *Manager) downloadMangaPage(ctx context.Context, url, outputPath string, headers map[string]string, referer string) error {
+// downloadMangaPage downloads a single manga page of provider
+func (m *Manager) downloadMangaPage(ctx context.Context, provider, url, outputPath string, headers map[string]string, referer string) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
@@ -141,7 +142,7 @@ func (m *Manager) downloadMangaPage(ctx context.Context, url, outputPath string,
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: netbind.Transport(provider), Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -7,7 +7,9 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,6 +18,8 @@ import (
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
 	"github.com/justchokingaround/greg/internal/downloader/hls"
+	"github.com/justchokingaround/greg/internal/netbind"
+	"github.com/justchokingaround/greg/internal/providers"
 	"gorm.io/gorm"
 )
 
@@ -95,7 +99,18 @@ func (d *NativeDownloader) downloadHLS(ctx context.Context, task *DownloadTask)
 	}
 
 	// Create HLS downloader with progress reporting
-	hlsDownloader := hls.NewDownloader()
+	hlsDownloader := hls.NewDownloaderWithTransport(netbind.Transport(task.Provider))
+
+	// Remember the playlist duration so the result can be checked for
+	// truncation
+	if task.ExpectedDuration == 0 {
+		if _, duration, err := hlsDownloader.EstimateSize(downloadCtx, task.StreamURL, requestHeaders); err == nil {
+			task.ExpectedDuration = duration
+		}
+	}
+
+	// Segments are appended in order, so the file plays while it grows
+	task.PartialPath = task.OutputPath
 
 	// Download the HLS stream with progress reporting
 	if err := hlsDownloader.DownloadWithProgress(downloadCtx, task.StreamURL, task.OutputPath, requestHeaders, func(downloaded, total int) {
@@ -140,7 +155,7 @@ func (d *NativeDownloader) downloadDirect(ctx context.Context, task *DownloadTas
 		req.Header.Set("Referer", task.Referer)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Transport: netbind.Transport(task.Provider), Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return d.downloadDirectSingle(ctx, task)
@@ -153,6 +168,9 @@ func (d *NativeDownloader) downloadDirect(ctx context.Context, task *DownloadTas
 
 	acceptRanges := resp.Header.Get("Accept-Ranges")
 	contentLength := resp.ContentLength
+	if contentLength > 0 {
+		task.ExpectedSize = contentLength
+	}
 
 	// Use concurrent download if ranges supported and file is big enough (>10MB)
 	if contentLength > 10*1024*1024 && acceptRanges == "bytes" {
@@ -263,7 +281,7 @@ func (d *NativeDownloader) downloadDirectConcurrent(ctx context.Context, task *D
 				req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36")
 			}
 
-			client := &http.Client{Timeout: 0}
+			client := &http.Client{Transport: netbind.Transport(task.Provider), Timeout: 0}
 			resp, err := client.Do(req)
 			if err != nil {
 				select {
@@ -277,7 +295,7 @@ func (d *NativeDownloader) downloadDirectConcurrent(ctx context.Context, task *D
 
 			if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
 				select {
-				case errChan <- fmt.Errorf("unexpected status code: %d", resp.StatusCode):
+				case errChan <- newStatusError(resp):
 				default:
 				}
 				cancel()
@@ -333,6 +351,25 @@ func (d *NativeDownloader) downloadDirectConcurrent(ctx context.Context, task *D
 	return nil
 }
 
+// isMatroska reports whether a direct download is a Matroska or WebM file,
+// going by the stream type, the Content-Type and the URL's extension
+func isMatroska(task *DownloadTask, contentType string) bool {
+	if task.StreamType == providers.StreamTypeMKV {
+		return true
+	}
+	contentType = strings.ToLower(contentType)
+	if strings.Contains(contentType, "matroska") || strings.Contains(contentType, "webm") {
+		return true
+	}
+	if parsed, err := url.Parse(task.StreamURL); err == nil {
+		switch strings.ToLower(path.Ext(parsed.Path)) {
+		case ".mkv", ".mka", ".webm":
+			return true
+		}
+	}
+	return false
+}
+
 // downloadDirectSingle downloads non-HLS content directly (single connection)
 func (d *NativeDownloader) downloadDirectSingle(ctx context.Context, task *DownloadTask) error {
 	// Create HTTP request
@@ -360,7 +397,8 @@ func (d *NativeDownloader) downloadDirectSingle(ctx context.Context, task *Downl
 
 	// Send request
 	client := &http.Client{
-		Timeout: 0, // No timeout for downloads (was 30s which caused failures)
+		Transport: netbind.Transport(task.Provider),
+		Timeout:   0, // No timeout for downloads (was 30s which caused failures)
 	}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -369,11 +407,14 @@ func (d *NativeDownloader) downloadDirectSingle(ctx context.Context, task *Downl
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newStatusError(resp)
 	}
 
 	// Get content length
 	task.TotalBytes = resp.ContentLength
+	if resp.ContentLength > 0 {
+		task.ExpectedSize = resp.ContentLength
+	}
 
 	// Create output file
 	out, err := os.Create(task.OutputPath)
@@ -382,6 +423,12 @@ func (d *NativeDownloader) downloadDirectSingle(ctx context.Context, task *Downl
 	}
 	defer func() { _ = out.Close() }()
 
+	// Like the ffmpeg path, only Matroska plays while it grows; an mp4 is
+	// usually unplayable until its index at the end has arrived
+	if isMatroska(task, resp.Header.Get("Content-Type")) {
+		task.PartialPath = task.OutputPath
+	}
+
 	// Download with progress tracking
 	buffer := make([]byte, 32*1024) // 32KB buffer
 	var downloaded int64
@@ -469,7 +516,7 @@ func (d *NativeDownloader) triggerProgressCallback(task DownloadTask) {
 // updateTaskInDB updates a task in the database
 func (d *NativeDownloader) updateTaskInDB(task DownloadTask) error {
 	download := d.taskToDownload(task)
-	return d.db.Save(&download).Error
+	return database.Write(d.db, func(tx *gorm.DB) error { return tx.Save(&download).Error })
 }
 
 // taskToDownload converts a DownloadTask to database.Download
@@ -484,12 +531,16 @@ func (d *NativeDownloader) taskToDownload(task DownloadTask) database.Download {
 		Quality:         string(task.Quality),
 		Provider:        task.Provider,
 		Status:          string(task.Status),
+		Priority:        task.Priority,
 		Progress:        task.Progress,
 		BytesDownloaded: task.BytesDownloaded,
 		TotalBytes:      task.TotalBytes,
 		Speed:           task.Speed,
 		Error:           task.Error,
 		FilePath:        task.OutputPath,
+		ExpectedSize:    task.ExpectedSize,
+		ExpectedSeconds: int(task.ExpectedDuration.Seconds()),
+		Checksum:        task.Checksum,
 		CreatedAt:       task.CreatedAt,
 		StartedAt:       task.StartedAt,
 		CompletedAt:     task.CompletedAt,
@@ -0,0 +1,125 @@
+package downloader
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// taskQueue holds tasks waiting for a worker. Workers always take the task
+// with the highest priority, oldest first among equal priorities, so queued
+// tasks can be reordered while they wait.
+type taskQueue struct {
+	mu     sync.Mutex
+	tasks  []*DownloadTask
+	ready  chan struct{} // Signalled when tasks are added or the queue closes
+	closed bool
+}
+
+func newTaskQueue() *taskQueue {
+	return &taskQueue{ready: make(chan struct{}, 1)}
+}
+
+// push adds a task to the queue. Tasks already waiting are not added twice.
+// Returns false if the queue is closed.
+func (q *taskQueue) push(task *DownloadTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	for _, t := range q.tasks {
+		if t.ID == task.ID {
+			return true
+		}
+	}
+	q.tasks = append(q.tasks, task)
+	q.signal()
+	return true
+}
+
+// pop blocks until a task is available and removes the next one in queue
+// order. Returns false once the queue is closed or ctx is done.
+func (q *taskQueue) pop(ctx context.Context) (*DownloadTask, bool) {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return nil, false
+		}
+		if len(q.tasks) > 0 {
+			sortQueue(q.tasks)
+			task := q.tasks[0]
+			q.tasks = q.tasks[1:]
+			if len(q.tasks) > 0 {
+				// Wake another idle worker for the rest
+				q.signal()
+			}
+			q.mu.Unlock()
+			return task, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-q.ready:
+		}
+	}
+}
+
+// setPriority updates the priority of a waiting task
+func (q *taskQueue) setPriority(id string, priority int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, t := range q.tasks {
+		if t.ID == id {
+			t.Priority = priority
+			return
+		}
+	}
+}
+
+// remove drops a waiting task, e.g. after it was cancelled or deleted
+func (q *taskQueue) remove(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, t := range q.tasks {
+		if t.ID == id {
+			q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+			return
+		}
+	}
+}
+
+// close wakes all waiting workers and rejects further tasks
+func (q *taskQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.ready)
+	}
+}
+
+// signal wakes one waiting worker. Callers must hold q.mu.
+func (q *taskQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// sortQueue orders tasks by priority (highest first), then by creation time
+func sortQueue(tasks []*DownloadTask) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Priority != tasks[j].Priority {
+			return tasks[i].Priority > tasks[j].Priority
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+}
@@ -0,0 +1,192 @@
+// Package remote uploads finished downloads to remote storage: an rclone
+// remote, an SFTP server or an S3 bucket. Every backend goes through the
+// rclone binary, SFTP and S3 as on-the-fly remotes configured from greg's
+// settings, so no rclone config is needed for them.
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader/tools"
+)
+
+// Supported target types
+const (
+	TypeRclone = "rclone"
+	TypeSFTP   = "sftp"
+	TypeS3     = "s3"
+)
+
+// Progress is the state of an upload
+type Progress struct {
+	Bytes int64 // Uploaded so far
+	Total int64
+	Speed int64 // Bytes per second
+}
+
+// Target is remote storage uploads go to
+type Target struct {
+	kind   string
+	rclone string
+	base   string   // rclone path uploads go under, e.g. "gdrive:Anime" or ":sftp:/srv/media"
+	env    []string // Backend settings for on-the-fly remotes
+}
+
+// New creates the target configured in cfg. It fails when rclone isn't
+// installed or the settings are incomplete.
+func New(cfg config.RemoteConfig) (*Target, error) {
+	rclone, err := tools.FindTool("rclone")
+	if err != nil {
+		return nil, fmt.Errorf("remote uploads need rclone: %w", err)
+	}
+	t := &Target{kind: strings.ToLower(cfg.Type), rclone: rclone}
+
+	switch t.kind {
+	case TypeRclone:
+		if !strings.Contains(cfg.Path, ":") {
+			return nil, fmt.Errorf("remote path %q is not an rclone remote (like \"gdrive:Anime\")", cfg.Path)
+		}
+		t.base = cfg.Path
+
+	case TypeSFTP:
+		if cfg.Host == "" {
+			return nil, fmt.Errorf("no SFTP host (set downloads.remote.host)")
+		}
+		t.base = ":sftp:" + cfg.Path
+		t.env = append(t.env, "RCLONE_SFTP_HOST="+cfg.Host)
+		if cfg.Port > 0 {
+			t.env = append(t.env, "RCLONE_SFTP_PORT="+strconv.Itoa(cfg.Port))
+		}
+		if cfg.User != "" {
+			t.env = append(t.env, "RCLONE_SFTP_USER="+cfg.User)
+		}
+		if cfg.KeyFile != "" {
+			t.env = append(t.env, "RCLONE_SFTP_KEY_FILE="+cfg.KeyFile)
+		}
+		if cfg.Password != "" {
+			obscured, err := t.obscure(cfg.Password)
+			if err != nil {
+				return nil, err
+			}
+			t.env = append(t.env, "RCLONE_SFTP_PASS="+obscured)
+		}
+
+	case TypeS3:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("no S3 bucket (set downloads.remote.path to \"bucket/prefix\")")
+		}
+		t.base = ":s3:" + cfg.Path
+		provider := "AWS"
+		if cfg.Endpoint != "" {
+			provider = "Other"
+			t.env = append(t.env, "RCLONE_S3_ENDPOINT="+cfg.Endpoint)
+		}
+		t.env = append(t.env, "RCLONE_S3_PROVIDER="+provider)
+		if cfg.Region != "" {
+			t.env = append(t.env, "RCLONE_S3_REGION="+cfg.Region)
+		}
+		if cfg.AccessKeyID != "" {
+			t.env = append(t.env,
+				"RCLONE_S3_ACCESS_KEY_ID="+cfg.AccessKeyID,
+				"RCLONE_S3_SECRET_ACCESS_KEY="+cfg.SecretAccessKey)
+		} else {
+			// Credentials from the AWS environment variables or profile
+			t.env = append(t.env, "RCLONE_S3_ENV_AUTH=true")
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown remote type %q (use rclone, sftp or s3)", cfg.Type)
+	}
+	return t, nil
+}
+
+// Type returns the kind of target, TypeRclone, TypeSFTP or TypeS3
+func (t *Target) Type() string {
+	return t.kind
+}
+
+// Location returns where a file uploaded as rel ends up
+func (t *Target) Location(rel string) string {
+	rel = strings.TrimPrefix(path.Clean("/"+rel), "/")
+	if strings.HasSuffix(t.base, ":") || strings.HasSuffix(t.base, "/") {
+		return t.base + rel
+	}
+	return t.base + "/" + rel
+}
+
+// Upload copies a local file to rel under the target and returns its
+// location. progress is called about once a second.
+func (t *Target) Upload(ctx context.Context, local, rel string, progress func(Progress)) (string, error) {
+	dest := t.Location(rel)
+	cmd := exec.CommandContext(ctx, t.rclone, "copyto", local, dest,
+		"--use-json-log", "--stats", "1s", "--stats-log-level", "NOTICE", "--log-level", "NOTICE")
+	cmd.Env = append(os.Environ(), t.env...)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start rclone: %w", err)
+	}
+
+	// rclone logs JSON lines; the stats ones carry the transfer progress
+	var lastError string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+			Stats *struct {
+				Bytes      int64   `json:"bytes"`
+				TotalBytes int64   `json:"totalBytes"`
+				Speed      float64 `json:"speed"`
+			} `json:"stats"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Stats != nil {
+			if progress != nil {
+				progress(Progress{Bytes: line.Stats.Bytes, Total: line.Stats.TotalBytes, Speed: int64(line.Stats.Speed)})
+			}
+			continue
+		}
+		if line.Level == "error" || line.Level == "critical" {
+			lastError = strings.TrimSpace(line.Msg)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if lastError != "" {
+			return "", fmt.Errorf("rclone failed: %s", lastError)
+		}
+		return "", fmt.Errorf("rclone failed: %w", err)
+	}
+	return dest, nil
+}
+
+// obscure encodes a password the way rclone expects it in its settings,
+// passing it on stdin so it doesn't show in the process list
+func (t *Target) obscure(password string) (string, error) {
+	cmd := exec.Command(t.rclone, "obscure", "-")
+	cmd.Stdin = strings.NewReader(password)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to obscure SFTP password: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
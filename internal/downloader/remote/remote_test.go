@@ -0,0 +1,73 @@
+//go:build !windows
+
+package remote
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+// fakeRclone installs an rclone that logs two stats lines, records its
+// arguments and the SFTP host it was given, and copies nothing
+func fakeRclone(t *testing.T) string {
+	dir := t.TempDir()
+	script := `#!/bin/sh
+echo "$@" > "` + dir + `/args"
+echo "$RCLONE_SFTP_HOST" > "` + dir + `/host"
+echo '{"level":"notice","msg":"stats","stats":{"bytes":50,"totalBytes":100,"speed":25}}' >&2
+echo 'not json' >&2
+echo '{"level":"notice","msg":"stats","stats":{"bytes":100,"totalBytes":100,"speed":50}}' >&2
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rclone"), []byte(script), 0755))
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func TestUploadReportsProgress(t *testing.T) {
+	dir := fakeRclone(t)
+
+	target, err := New(config.RemoteConfig{Type: "sftp", Host: "nas.local", Path: "/srv/media"})
+	require.NoError(t, err)
+
+	var progress []Progress
+	location, err := target.Upload(context.Background(), "/downloads/anime/Show/ep.mkv", "anime/Show/ep.mkv", func(p Progress) {
+		progress = append(progress, p)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ":sftp:/srv/media/anime/Show/ep.mkv", location)
+	assert.Equal(t, []Progress{{Bytes: 50, Total: 100, Speed: 25}, {Bytes: 100, Total: 100, Speed: 50}}, progress)
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "copyto /downloads/anime/Show/ep.mkv :sftp:/srv/media/anime/Show/ep.mkv")
+	host, err := os.ReadFile(filepath.Join(dir, "host"))
+	require.NoError(t, err)
+	assert.Equal(t, "nas.local\n", string(host))
+}
+
+func TestNewValidatesSettings(t *testing.T) {
+	fakeRclone(t)
+
+	_, err := New(config.RemoteConfig{Type: "rclone", Path: "/not/a/remote"})
+	assert.Error(t, err)
+	_, err = New(config.RemoteConfig{Type: "sftp"})
+	assert.Error(t, err)
+	_, err = New(config.RemoteConfig{Type: "ftp"})
+	assert.Error(t, err)
+
+	target, err := New(config.RemoteConfig{Type: "rclone", Path: "gdrive:"})
+	require.NoError(t, err)
+	assert.Equal(t, "gdrive:anime/Show/ep.mkv", target.Location("anime/Show/ep.mkv"))
+
+	target, err = New(config.RemoteConfig{Type: "s3", Path: "bucket/greg"})
+	require.NoError(t, err)
+	assert.Equal(t, ":s3:bucket/greg/ep.mkv", target.Location("../ep.mkv"))
+	assert.Contains(t, target.env, "RCLONE_S3_ENV_AUTH=true")
+}
@@ -0,0 +1,137 @@
+package downloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SpeedWindow is how far back the speed history of downloads goes, one
+// sample a second
+const SpeedWindow = time.Minute
+
+// speedStale is how long a reported speed counts without a new report
+const speedStale = 5 * time.Second
+
+// speedSample is a download speed at a point in time
+type speedSample struct {
+	at    time.Time
+	speed int64 // Bytes per second
+}
+
+// speedHistory samples the speed downloads last reported once a second,
+// keeping SpeedWindow of samples per download and of their sum
+type speedHistory struct {
+	mu     sync.Mutex
+	latest map[string]speedSample   // Task ID -> last reported speed
+	tasks  map[string][]speedSample // Task ID -> samples, oldest first
+	total  []speedSample
+}
+
+func newSpeedHistory() *speedHistory {
+	return &speedHistory{
+		latest: make(map[string]speedSample),
+		tasks:  make(map[string][]speedSample),
+	}
+}
+
+// report notes the speed of a task at, as long as it's downloading
+func (h *speedHistory) report(task DownloadTask, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if task.Status != StatusDownloading {
+		delete(h.latest, task.ID)
+		return
+	}
+	h.latest[task.ID] = speedSample{at: at, speed: task.Speed}
+}
+
+// sample records the speeds last reported, dropping samples older than
+// SpeedWindow and reports older than speedStale
+func (h *speedHistory) sample(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var sum int64
+	for id, latest := range h.latest {
+		if now.Sub(latest.at) > speedStale {
+			delete(h.latest, id)
+			continue
+		}
+		h.tasks[id] = append(h.tasks[id], speedSample{at: now, speed: latest.speed})
+		sum += latest.speed
+	}
+	if len(h.latest) > 0 {
+		h.total = append(h.total, speedSample{at: now, speed: sum})
+	}
+
+	for id, samples := range h.tasks {
+		if samples = pruneSpeeds(samples, now); len(samples) == 0 {
+			delete(h.tasks, id)
+		} else {
+			h.tasks[id] = samples
+		}
+	}
+	h.total = pruneSpeeds(h.total, now)
+}
+
+// run samples once a second until ctx is done
+func (h *speedHistory) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.sample(now)
+		}
+	}
+}
+
+// series returns the speed of a task each second up to now, or of all tasks
+// together for an empty ID
+func (h *speedHistory) series(id string, now time.Time) []int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	samples := h.total
+	if id != "" {
+		samples = h.tasks[id]
+	}
+	return speedSeries(samples, now)
+}
+
+// pruneSpeeds drops the samples older than SpeedWindow
+func pruneSpeeds(samples []speedSample, now time.Time) []speedSample {
+	i := 0
+	for i < len(samples) && now.Sub(samples[i].at) >= SpeedWindow {
+		i++
+	}
+	return samples[i:]
+}
+
+// speedSeries spreads samples over one slot a second of the SpeedWindow up to
+// now, oldest first; slots without a sample are 0
+func speedSeries(samples []speedSample, now time.Time) []int64 {
+	slots := int(SpeedWindow / time.Second)
+	series := make([]int64, slots)
+	for _, s := range samples {
+		age := now.Sub(s.at)
+		if age < 0 || age >= SpeedWindow {
+			continue
+		}
+		series[slots-1-int(age/time.Second)] = s.speed
+	}
+	return series
+}
+
+// SpeedHistory returns the speed of a download each second over the last
+// SpeedWindow, oldest first, 0 while it wasn't downloading
+func (m *Manager) SpeedHistory(id string) []int64 {
+	return m.speeds.series(id, time.Now())
+}
+
+// TotalSpeedHistory returns the speed of all downloads together each second
+// over the last SpeedWindow, oldest first
+func (m *Manager) TotalSpeedHistory() []int64 {
+	return m.speeds.series("", time.Now())
+}
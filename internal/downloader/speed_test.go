@@ -0,0 +1,34 @@
+package downloader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeedHistory(t *testing.T) {
+	h := newSpeedHistory()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	h.report(DownloadTask{ID: "a", Status: StatusDownloading, Speed: 100}, start)
+	h.report(DownloadTask{ID: "b", Status: StatusDownloading, Speed: 50}, start)
+	h.sample(start)
+	h.report(DownloadTask{ID: "b", Status: StatusPaused}, start.Add(time.Second))
+	h.sample(start.Add(time.Second))
+	h.sample(start.Add(2 * time.Second))
+
+	now := start.Add(2 * time.Second)
+	a := h.series("a", now)
+	assert.Len(t, a, 60)
+	assert.Equal(t, []int64{100, 100, 100}, a[57:])
+	assert.Equal(t, []int64{50, 0, 0}, h.series("b", now)[57:], "paused downloads stop being sampled")
+	assert.Equal(t, []int64{150, 100, 100}, h.series("", now)[57:])
+
+	// Reports go stale without updates, and samples leave the window
+	h.sample(start.Add(speedStale + time.Second))
+	assert.Equal(t, int64(0), h.series("a", start.Add(speedStale+time.Second))[59])
+	h.sample(start.Add(SpeedWindow + 10*time.Second))
+	assert.Empty(t, h.tasks)
+	assert.Empty(t, h.total)
+}
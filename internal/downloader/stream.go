@@ -0,0 +1,67 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// ErrNotStreamable is returned by WaitPlayable for downloads that aren't
+// written front to back, which can't be played before they finish
+var ErrNotStreamable = errors.New("download can't be played before it finishes")
+
+// playableBytes is how much of a growing file WaitPlayable waits for, a few
+// seconds of video so the player doesn't catch up with the download at once
+const playableBytes = 8 << 20
+
+// WaitPlayable waits until the download with the given ID can be played and
+// returns the file to play. growing reports a file that is still being
+// written, which a player has to keep reading as it grows (mpv's
+// appending:// protocol).
+func (m *Manager) WaitPlayable(ctx context.Context, id string) (path string, growing bool, err error) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		m.mu.RLock()
+		var partial string
+		var downloading bool
+		if a, ok := m.active[id]; ok {
+			partial = a.task.PartialPath
+			downloading = a.task.Status == StatusDownloading && a.task.BytesDownloaded > 0
+		}
+		m.mu.RUnlock()
+
+		if partial != "" {
+			if info, err := os.Stat(partial); err == nil && info.Size() >= playableBytes {
+				return partial, true, nil
+			}
+		} else if downloading {
+			return "", false, ErrNotStreamable
+		}
+
+		var download database.Download
+		if err := m.db.First(&download, "id = ?", id).Error; err != nil {
+			return "", false, fmt.Errorf("failed to load download: %w", err)
+		}
+		switch DownloadStatus(download.Status) {
+		case StatusCompleted:
+			return download.FilePath, false, nil
+		case StatusFailed, StatusCancelled:
+			if download.Error != "" {
+				return "", false, fmt.Errorf("download %s: %s", download.Status, download.Error)
+			}
+			return "", false, fmt.Errorf("download %s", download.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -73,8 +73,8 @@ func ParseTemplate(template string, task DownloadTask) (string, error) {
 	// Add file extension based on quality/type
 	// Most streaming sources are MP4 or MKV
 	if !strings.HasSuffix(result, ".mp4") && !strings.HasSuffix(result, ".mkv") {
-		// Use mkv for files with subtitles to embed, mp4 otherwise
-		if task.EmbedSubs && len(task.Subtitles) > 0 {
+		// Use mkv for files with subtitles to embed and XDCC releases, mp4 otherwise
+		if (task.EmbedSubs && len(task.Subtitles) > 0) || task.StreamType == providers.StreamTypeXDCC {
 			result += ".mkv"
 		} else {
 			result += ".mp4"
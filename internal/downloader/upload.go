@@ -0,0 +1,66 @@
+package downloader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/downloader/remote"
+)
+
+// upload copies a finished download to the remote storage of
+// downloads.remote, keeping the same folders as under the downloads
+// directory. A failed upload doesn't fail the download: the file stays
+// local and the error is noted on the task.
+func (w *worker) upload(ctx context.Context, task *DownloadTask) {
+	m := w.manager
+	if m.remote == nil || task.Clip != nil {
+		return
+	}
+
+	rel, err := filepath.Rel(m.config.Path, task.OutputPath)
+	if err != nil || !filepath.IsLocal(rel) {
+		rel = filepath.Base(task.OutputPath)
+	}
+
+	m.mu.Lock()
+	task.Status = StatusUploading
+	task.Progress = 0
+	task.Speed = 0
+	task.ETA = 0
+	m.mu.Unlock()
+	_ = m.updateTaskInDB(*task)
+	m.triggerProgressCallback(*task)
+
+	location, err := m.remote.Upload(ctx, task.OutputPath, filepath.ToSlash(rel), func(p remote.Progress) {
+		m.mu.Lock()
+		if p.Total > 0 {
+			task.Progress = float64(p.Bytes) / float64(p.Total) * 100
+		}
+		task.Speed = p.Speed
+		task.ETA = 0
+		if p.Speed > 0 && p.Total > p.Bytes {
+			task.ETA = time.Duration((p.Total-p.Bytes)/p.Speed) * time.Second
+		}
+		m.mu.Unlock()
+		m.triggerProgressCallback(*task)
+	})
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task.Speed = 0
+	task.ETA = 0
+	if err != nil {
+		w.logger.Warn("failed to upload download", "task_id", task.ID, "remote", m.remote.Type(), "error", err)
+		task.Error = "Upload failed, kept locally: " + err.Error()
+		return
+	}
+
+	w.logger.Info("uploaded download", "task_id", task.ID, "remote", location)
+	task.RemotePath = location
+	if !m.config.Remote.KeepLocal {
+		if err := os.Remove(task.OutputPath); err != nil && !os.IsNotExist(err) {
+			w.logger.Warn("failed to remove uploaded file", "path", task.OutputPath, "error", err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// ErrCorrupted is returned when a finished download fails verification
+var ErrCorrupted = errors.New("download corrupted")
+
+// How often a download that fails verification is downloaded again before
+// the task is marked failed
+const maxVerifyRetries = 2
+
+// A probed duration this far below the expected one means the file is
+// truncated; playlist durations are not exact
+const minDurationRatio = 0.9
+
+// crcTag matches the CRC32 fansub releases put in their file names, e.g.
+// "[SubsPlease] Frieren - 01 (1080p) [ABCD1234].mkv"
+var crcTag = regexp.MustCompile(`\[([0-9A-Fa-f]{8})\]`)
+
+// ChecksumFromName returns the checksum encoded in a release name as
+// "crc32:<hex>", or an empty string when the name has none
+func ChecksumFromName(name string) string {
+	m := crcTag.FindAllStringSubmatch(name, -1)
+	if len(m) == 0 {
+		return ""
+	}
+	// The CRC is the last tag; earlier ones are groups or resolutions
+	return "crc32:" + strings.ToLower(m[len(m)-1][1])
+}
+
+// verify checks a finished download against what its source announced
+func (w *worker) verify(ctx context.Context, task *DownloadTask) error {
+	return verifyFile(ctx, task, w.manager.ffprobe)
+}
+
+// verifyFile checks the size and checksum of a downloaded file and, when
+// ffprobe is given, that its container is readable and not truncated.
+// Failures wrap ErrCorrupted.
+func verifyFile(ctx context.Context, task *DownloadTask, ffprobe string) error {
+	info, err := os.Stat(task.OutputPath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	if info.Size() == 0 {
+		return fmt.Errorf("%w: file is empty", ErrCorrupted)
+	}
+	if task.ExpectedSize > 0 && info.Size() != task.ExpectedSize {
+		return fmt.Errorf("%w: size is %s, expected %s", ErrCorrupted,
+			humanize.IBytes(uint64(info.Size())), humanize.IBytes(uint64(task.ExpectedSize)))
+	}
+
+	if task.Checksum != "" {
+		if err := verifyChecksum(task.OutputPath, task.Checksum); err != nil {
+			return err
+		}
+	}
+
+	if ffprobe == "" {
+		return nil
+	}
+	duration, err := probeDuration(ctx, ffprobe, task.OutputPath)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: unreadable file: %v", ErrCorrupted, err)
+	}
+	if task.ExpectedDuration > 0 && duration > 0 &&
+		duration < time.Duration(float64(task.ExpectedDuration)*minDurationRatio) {
+		return fmt.Errorf("%w: %s long, expected %s", ErrCorrupted,
+			duration.Round(time.Second), task.ExpectedDuration.Round(time.Second))
+	}
+	return nil
+}
+
+// verifyChecksum hashes path and compares it with an "algorithm:hex"
+// checksum. Unknown algorithms are not checked.
+func verifyChecksum(path, checksum string) error {
+	algorithm, want, ok := strings.Cut(strings.ToLower(checksum), ":")
+	if !ok {
+		return nil
+	}
+
+	var h hash.Hash
+	switch algorithm {
+	case "crc32":
+		h = crc32.NewIEEE()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open download: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to hash download: %w", err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("%w: %s is %s, expected %s", ErrCorrupted, algorithm, got, want)
+	}
+	return nil
+}
+
+// probeDuration returns the container duration reported by ffprobe. It fails
+// when ffprobe can't read the file; a missing duration is returned as 0.
+func probeDuration(ctx context.Context, ffprobe, path string) (time.Duration, error) {
+	cmd := exec.CommandContext(ctx, ffprobe,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return 0, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return 0, err
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, nil // "N/A" for streams without a duration
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -3,6 +3,7 @@ package downloader
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -14,6 +15,11 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader/xdcc"
+	"github.com/justchokingaround/greg/internal/netbind"
+	"github.com/justchokingaround/greg/internal/providers"
 )
 
 // worker represents a download worker
@@ -23,6 +29,12 @@ type worker struct {
 	logger           *slog.Logger
 	currentTask      *DownloadTask
 	nativeDownloader *NativeDownloader
+	tool             string // Tool that last tried the current task, for Diagnostics
+
+	// idle is done once the worker should take no more tasks: when the
+	// manager stops, or when SetConcurrency retires it
+	idle   context.Context
+	retire context.CancelFunc
 }
 
 // newWorker creates a new worker
@@ -37,41 +49,76 @@ func newWorker(id int, manager *Manager) *worker {
 		nativeDownloader = nil
 	}
 
+	idle, retire := context.WithCancel(manager.ctx)
 	return &worker{
 		id:               id,
 		manager:          manager,
 		logger:           logger,
 		nativeDownloader: nativeDownloader,
+		idle:             idle,
+		retire:           retire,
 	}
 }
 
 // run starts the worker loop
-func (w *worker) run(ctx context.Context, queue <-chan *DownloadTask) {
+func (w *worker) run(ctx context.Context, queue *taskQueue) {
+	defer w.retire()
 	for {
-		select {
-		case <-ctx.Done():
+		if w.idle.Err() != nil {
+			// Shutting down or retired
 			return
-		case task, ok := <-queue:
-			if !ok {
-				// Queue closed
-				return
-			}
+		}
+		task, ok := queue.pop(w.idle)
+		if !ok {
+			// Queue closed, shutting down or retired
+			return
+		}
 
-			// Process the task
-			w.currentTask = task
-			if err := w.processTask(ctx, task); err != nil {
-				task.Status = StatusFailed
-				task.Error = err.Error()
-				_ = w.manager.updateTaskInDB(*task)
-				w.manager.triggerErrorCallback(*task, err)
+		// Process the task
+		w.currentTask = task
+		if err := w.processTask(ctx, task); err != nil {
+			if w.interrupted(ctx, task) {
+				// Paused or shutting down, keep the checkpoint
+				w.currentTask = nil
+				continue
 			}
-			w.currentTask = nil
+			task.Status = StatusFailed
+			task.Error = err.Error()
+			task.Diagnostics = diagnose(task, w.tool, err)
+			_ = w.manager.updateTaskInDB(*task)
+			w.manager.triggerErrorCallback(*task, err)
 		}
+		w.currentTask = nil
+	}
+}
+
+// interrupted reports whether a task stopped because it was paused or the
+// manager is shutting down rather than because it failed
+func (w *worker) interrupted(ctx context.Context, task *DownloadTask) bool {
+	if ctx.Err() != nil {
+		return true
 	}
+	w.manager.mu.RLock()
+	defer w.manager.mu.RUnlock()
+	return task.Status == StatusPaused
 }
 
 // processTask processes a single download task
 func (w *worker) processTask(ctx context.Context, task *DownloadTask) error {
+	// Don't start when the disk is (nearly) full; pause so it can be resumed
+	// once space is freed
+	if err := w.manager.checkDiskSpace(task.TotalBytes - task.BytesDownloaded); err != nil {
+		w.manager.mu.Lock()
+		task.Status = StatusPaused
+		task.Error = err.Error()
+		w.manager.mu.Unlock()
+		_ = w.manager.updateTaskInDB(*task)
+		w.manager.triggerErrorCallback(*task, err)
+		return err
+	}
+
+	w.tool = ToolNative
+
 	// Create cancellable context for this task
 	taskCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -99,6 +146,98 @@ func (w *worker) processTask(ctx context.Context, task *DownloadTask) error {
 	_ = w.manager.updateTaskInDB(*task)
 	w.manager.triggerProgressCallback(*task)
 
+	if task.Clip != nil {
+		w.tool = ToolFFmpeg
+		if err := w.renderClip(taskCtx, task); err != nil {
+			return err
+		}
+		w.complete(task)
+		return nil
+	}
+
+	// Copy the episode from another instance on the network that already
+	// has it; the shared file was verified when it was downloaded there
+	if w.pullFromPeer(taskCtx, task) {
+		w.upload(taskCtx, task)
+		w.complete(task)
+		return nil
+	}
+
+	// Download, and download again when the result fails verification
+	// instead of marking a corrupted file as completed
+	for attempt := 0; ; attempt++ {
+		if err := w.fetch(taskCtx, task); err != nil {
+			return err
+		}
+
+		err := w.verify(taskCtx, task)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrCorrupted) || attempt >= maxVerifyRetries {
+			return err
+		}
+
+		w.logger.Warn("download failed verification, downloading again", "task_id", task.ID, "error", err)
+		if rmErr := os.Remove(task.OutputPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			return fmt.Errorf("failed to remove corrupted file: %w", rmErr)
+		}
+		task.BytesDownloaded = 0
+		task.Progress = 0
+		task.Error = fmt.Sprintf("Corrupted download (%v), downloading again...", err)
+		_ = w.manager.updateTaskInDB(*task)
+		w.manager.triggerProgressCallback(*task)
+	}
+	task.Error = ""
+
+	// Embed subtitles if requested and available
+	if task.EmbedSubs && len(task.Subtitles) > 0 {
+		task.Status = StatusProcessing
+		_ = w.manager.updateTaskInDB(*task)
+		w.manager.triggerProgressCallback(*task)
+
+		if err := w.embedSubtitles(taskCtx, task); err != nil {
+			w.logger.Warn("failed to embed subtitles", "error", err)
+			// Don't fail the entire download, subtitles are optional
+		}
+	}
+
+	w.markIntro(taskCtx, task)
+	w.upload(taskCtx, task)
+
+	w.complete(task)
+	return nil
+}
+
+// complete marks a task as completed
+func (w *worker) complete(task *DownloadTask) {
+	task.Status = StatusCompleted
+	task.Progress = 100.0
+	completedAt := time.Now()
+	task.CompletedAt = &completedAt
+	_ = w.manager.updateTaskInDB(*task)
+	w.recordBandwidth(task)
+	w.manager.triggerCompleteCallback(*task)
+}
+
+// recordBandwidth adds a finished download to the provider's monthly usage.
+// Clips only fetch a few seconds of the stream and aren't counted, nor are
+// episodes pulled from another instance.
+func (w *worker) recordBandwidth(task *DownloadTask) {
+	if task.Clip != nil || task.Provider == "" || task.Peer != "" {
+		return
+	}
+	bytes := task.BytesDownloaded
+	if bytes <= 0 {
+		bytes = task.TotalBytes
+	}
+	if _, err := database.AddBandwidth(w.manager.db, task.Provider, database.BandwidthDownload, bytes, *task.CompletedAt); err != nil {
+		w.logger.Warn("failed to record bandwidth", "task_id", task.ID, "error", err)
+	}
+}
+
+// fetch downloads a task, retrying network-related failures
+func (w *worker) fetch(ctx context.Context, task *DownloadTask) error {
 	// Attempt the download with retries for network-related failures
 	maxRetries := 3
 	var lastErr error
@@ -109,14 +248,39 @@ func (w *worker) processTask(ctx context.Context, task *DownloadTask) error {
 			// Wait a bit before retrying
 			select {
 			case <-time.After(2 * time.Second):
-			case <-taskCtx.Done():
-				return taskCtx.Err()
+			case <-ctx.Done():
+				return ctx.Err()
 			}
 		}
 
-		// Use the native downloader implementation
-		if w.nativeDownloader != nil {
-			if err := w.nativeDownloader.Download(taskCtx, task); err != nil {
+		// XDCC packs are requested over IRC rather than fetched over HTTP;
+		// retries resume from the partial file
+		if xdcc.IsURL(task.StreamURL) {
+			w.tool = ToolXDCC
+			if err := w.downloadWithXDCC(ctx, task); err != nil {
+				w.logger.Warn("xdcc download failed", "error", err)
+				lastErr = err
+			} else {
+				lastErr = nil
+				break
+			}
+		} else if task.StreamType == providers.StreamTypeYTDLP {
+			// Web pages only yt-dlp can extract
+			if !w.manager.ytdlp.Available {
+				return fmt.Errorf("yt-dlp is required to download %s", task.StreamURL)
+			}
+			w.tool = ToolYTDLP
+			if err := w.downloadWithYTDLP(ctx, task); err != nil {
+				w.logger.Warn("yt-dlp download failed", "error", err)
+				lastErr = err
+			} else {
+				lastErr = nil
+				break
+			}
+		} else if w.nativeDownloader != nil {
+			// Use the native downloader implementation
+			w.tool = ToolNative
+			if err := w.nativeDownloader.Download(ctx, task); err != nil {
 				w.logger.Warn("native download failed, trying external tools", "error", err)
 				lastErr = err
 			} else {
@@ -132,18 +296,21 @@ func (w *worker) processTask(ctx context.Context, task *DownloadTask) error {
 
 			// Strategy: Try yt-dlp first for everything
 			if w.manager.ytdlp.Available {
-				if err := w.downloadWithYTDLP(taskCtx, task); err != nil {
+				w.tool = ToolYTDLP
+				if err := w.downloadWithYTDLP(ctx, task); err != nil {
 					w.logger.Warn("yt-dlp download failed", "error", err)
 					lastErr = err
 
 					// If yt-dlp fails and ffmpeg is available, try ffmpeg as fallback
 					if w.manager.ffmpeg.Available {
 						w.logger.Info("trying ffmpeg fallback")
-						if ffmpegErr := w.downloadWithFFmpeg(taskCtx, task); ffmpegErr != nil {
+						w.tool = ToolFFmpeg
+						if ffmpegErr := w.downloadWithFFmpeg(ctx, task); ffmpegErr != nil {
 							// If both fail with 403, try mpv as last resort (works for protected CDNs)
 							if strings.Contains(err.Error(), "403") || strings.Contains(ffmpegErr.Error(), "403") {
 								w.logger.Warn("both yt-dlp and ffmpeg failed with 403, trying mpv fallback")
-								if mpvErr := w.downloadWithMPV(taskCtx, task); mpvErr != nil {
+								w.tool = ToolMPV
+								if mpvErr := w.downloadWithMPV(ctx, task); mpvErr != nil {
 									lastErr = fmt.Errorf("all download methods failed: yt-dlp=%w, ffmpeg=%v, mpv=%v", err, ffmpegErr, mpvErr)
 								} else {
 									// MPV succeeded
@@ -166,7 +333,8 @@ func (w *worker) processTask(ctx context.Context, task *DownloadTask) error {
 			} else if w.manager.ffmpeg.Available {
 				// Only ffmpeg available (yt-dlp not installed)
 				w.logger.Info("using ffmpeg", "reason", "yt-dlp not available")
-				if err := w.downloadWithFFmpeg(taskCtx, task); err != nil {
+				w.tool = ToolFFmpeg
+				if err := w.downloadWithFFmpeg(ctx, task); err != nil {
 					lastErr = fmt.Errorf("ffmpeg download failed: %w", err)
 				} else {
 					// FFmpeg succeeded
@@ -186,31 +354,40 @@ func (w *worker) processTask(ctx context.Context, task *DownloadTask) error {
 		}
 	}
 
-	// Check if all attempts failed
-	if lastErr != nil {
-		return lastErr
+	return lastErr
+}
+
+// downloadWithXDCC requests an XDCC pack from its bot and receives it over DCC
+func (w *worker) downloadWithXDCC(ctx context.Context, task *DownloadTask) error {
+	pack, err := xdcc.ParseURL(task.StreamURL)
+	if err != nil {
+		return err
 	}
 
-	// Embed subtitles if requested and available
-	if task.EmbedSubs && len(task.Subtitles) > 0 {
-		task.Status = StatusProcessing
-		_ = w.manager.updateTaskInDB(*task)
-		w.manager.triggerProgressCallback(*task)
+	w.logger.Debug("requesting xdcc pack", "server", pack.Server, "bot", pack.Bot, "pack", pack.Number)
 
-		if err := w.embedSubtitles(taskCtx, task); err != nil {
-			w.logger.Warn("failed to embed subtitles", "error", err)
-			// Don't fail the entire download, subtitles are optional
+	lastUpdate := time.Now()
+	var lastDownloaded int64
+	task.PartialPath = task.OutputPath // DCC sends the file in order
+	err = xdcc.NewDownloader(w.manager.config.XDCC.Nick).Download(ctx, pack, task.OutputPath, func(downloaded, total int64) {
+		if elapsed := time.Since(lastUpdate); elapsed > 0 && lastDownloaded > 0 {
+			task.Speed = int64(float64(downloaded-lastDownloaded) / elapsed.Seconds())
 		}
+		lastUpdate = time.Now()
+		lastDownloaded = downloaded
+
+		task.BytesDownloaded = downloaded
+		task.TotalBytes = total
+		if total > 0 {
+			task.ExpectedSize = total // Announced in the DCC SEND offer
+			task.Progress = float64(downloaded) / float64(total) * 100.0
+		}
+		_ = w.manager.updateTaskInDB(*task)
+		w.manager.triggerProgressCallback(*task)
+	})
+	if err != nil {
+		return fmt.Errorf("xdcc download failed: %w", err)
 	}
-
-	// Mark as completed
-	task.Status = StatusCompleted
-	task.Progress = 100.0
-	completedAt := time.Now()
-	task.CompletedAt = &completedAt
-	_ = w.manager.updateTaskInDB(*task)
-	w.manager.triggerCompleteCallback(*task)
-
 	return nil
 }
 
@@ -412,6 +589,7 @@ func (w *worker) downloadWithFFmpeg(ctx context.Context, task *DownloadTask) err
 	}
 
 	// Direct HTTP download
+	w.tool = ToolHTTP
 	outputPath := task.OutputPath + ".part"
 	return w.downloadHTTPDirect(ctx, task, outputPath)
 }
@@ -426,18 +604,7 @@ func (w *worker) downloadHLSWithFFmpeg(ctx context.Context, task *DownloadTask)
 		"-reconnect_delay_max", "10", // Maximum reconnect delay
 		"-timeout", "30000000", // 30 seconds timeout for network operations
 	}
-
-	// Add headers if present
-	if len(task.Headers) > 0 || task.Referer != "" {
-		// Add individual headers using -headers option multiple times
-		for key, value := range task.Headers {
-			headerStr := fmt.Sprintf("%s: %s", capitalizeHeader(key), value)
-			args = append(args, "-headers", headerStr)
-		}
-		if task.Referer != "" {
-			args = append(args, "-headers", fmt.Sprintf("Referer: %s", task.Referer))
-		}
-	}
+	args = append(args, ffmpegHeaderArgs(task)...)
 
 	// Determine output format based on file extension (without .part)
 	format := "matroska" // default to mkv
@@ -486,6 +653,12 @@ func (w *worker) downloadHLSWithFFmpeg(ctx context.Context, task *DownloadTask)
 		return fmt.Errorf("failed to start ffmpeg: %w", err)
 	}
 
+	// Matroska is written as it goes, so it plays while it grows; mp4 only
+	// becomes playable once +faststart rewrites it at the end
+	if format == "matroska" {
+		task.PartialPath = outputPath
+	}
+
 	// Capture stderr for error reporting
 	var stderrBuf strings.Builder
 	stderrChan := make(chan string, 1)
@@ -552,7 +725,8 @@ func (w *worker) downloadHTTPDirect(ctx context.Context, task *DownloadTask, out
 
 	// Send request
 	client := &http.Client{
-		Timeout: 0, // No timeout for downloads
+		Transport: netbind.Transport(task.Provider),
+		Timeout:   0, // No timeout for downloads
 	}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -561,7 +735,7 @@ func (w *worker) downloadHTTPDirect(ctx context.Context, task *DownloadTask, out
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newStatusError(resp)
 	}
 
 	// Get content length
@@ -860,7 +1034,7 @@ func (w *worker) embedSubtitles(ctx context.Context, task *DownloadTask) error {
 			"language", sub.Language,
 			"url", sub.URL,
 			"path", subPath)
-		if err := w.downloadSubtitle(ctx, sub.URL, subPath); err != nil {
+		if err := w.downloadSubtitle(ctx, task.Provider, sub.URL, subPath); err != nil {
 			w.logger.Warn("failed to download subtitle", "error", err, "subtitle_index", i, "url", sub.URL)
 			continue
 		}
@@ -938,14 +1112,14 @@ func (w *worker) embedSubtitles(ctx context.Context, task *DownloadTask) error {
 	return nil
 }
 
-// downloadSubtitle downloads a subtitle file
-func (w *worker) downloadSubtitle(ctx context.Context, url, outputPath string) error {
+// downloadSubtitle downloads a subtitle file of provider
+func (w *worker) downloadSubtitle(ctx context.Context, provider, url, outputPath string) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := (&http.Client{Transport: netbind.Transport(provider)}).Do(req)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,177 @@
+package xdcc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// offer is a parsed DCC SEND request
+type offer struct {
+	Filename string
+	IP       net.IP
+	Port     int // 0 for passive DCC
+	Size     int64
+}
+
+// addr returns the host:port to connect to
+func (o offer) addr() string {
+	return net.JoinHostPort(o.IP.String(), strconv.Itoa(o.Port))
+}
+
+// isDCC reports whether a CTCP body is the given DCC command
+func isDCC(text, command string) bool {
+	return strings.HasPrefix(strings.ToUpper(text), "DCC "+command+" ")
+}
+
+// splitDCC splits "DCC <command> <filename> <args...>", where the filename may be quoted
+func splitDCC(text string) (filename string, args []string, err error) {
+	parts := strings.SplitN(text, " ", 3)
+	if len(parts) < 3 {
+		return "", nil, fmt.Errorf("malformed DCC message %q", text)
+	}
+
+	rest := parts[2]
+	if strings.HasPrefix(rest, `"`) {
+		end := strings.Index(rest[1:], `"`)
+		if end < 0 {
+			return "", nil, fmt.Errorf("malformed DCC filename in %q", text)
+		}
+		filename, rest = rest[1:end+1], rest[end+2:]
+	} else {
+		filename, rest, _ = strings.Cut(rest, " ")
+	}
+
+	return filename, strings.Fields(rest), nil
+}
+
+// parseOffer parses "DCC SEND <filename> <ip> <port> [size] [token]"
+func parseOffer(text string) (offer, error) {
+	filename, args, err := splitDCC(text)
+	if err != nil {
+		return offer{}, err
+	}
+	if len(args) < 2 {
+		return offer{}, fmt.Errorf("malformed DCC SEND %q", text)
+	}
+
+	o := offer{Filename: filename}
+	if n, err := strconv.ParseUint(args[0], 10, 32); err == nil {
+		// IPv4 addresses are sent as a single big-endian integer
+		o.IP = net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	} else if o.IP = net.ParseIP(args[0]); o.IP == nil {
+		return offer{}, fmt.Errorf("invalid DCC address %q", args[0])
+	}
+
+	if o.Port, err = strconv.Atoi(args[1]); err != nil {
+		return offer{}, fmt.Errorf("invalid DCC port %q", args[1])
+	}
+	if len(args) > 2 {
+		if o.Size, err = strconv.ParseInt(args[2], 10, 64); err != nil {
+			return offer{}, fmt.Errorf("invalid DCC size %q", args[2])
+		}
+	}
+
+	return o, nil
+}
+
+// parseAccept parses "DCC ACCEPT <filename> <port> <position>" and returns the position
+func parseAccept(text string) (int64, error) {
+	_, args, err := splitDCC(text)
+	if err != nil {
+		return 0, err
+	}
+	if len(args) < 2 {
+		return 0, fmt.Errorf("malformed DCC ACCEPT %q", text)
+	}
+
+	position, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid DCC resume position %q", args[1])
+	}
+	return position, nil
+}
+
+// quoteFilename quotes filenames containing spaces for DCC RESUME
+func quoteFilename(name string) string {
+	if strings.Contains(name, " ") {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// receive connects to the bot and writes the file starting at offset,
+// acknowledging received bytes as the DCC protocol requires
+func (d *Downloader) receive(ctx context.Context, o offer, output string, offset int64, progress ProgressCallback) error {
+	out, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	if err := out.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to prepare output file: %w", err)
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to prepare output file: %w", err)
+	}
+
+	conn, err := d.dialer.DialContext(ctx, "tcp", o.addr())
+	if err != nil {
+		return fmt.Errorf("failed to connect to DCC %s: %w", o.addr(), err)
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	buffer := make([]byte, 64*1024)
+	ack := make([]byte, 4)
+	received := offset
+	lastUpdate := time.Now()
+
+	for o.Size == 0 || received < o.Size {
+		_ = conn.SetReadDeadline(time.Now().Add(d.timeout))
+		n, err := conn.Read(buffer)
+		if n > 0 {
+			if _, writeErr := out.Write(buffer[:n]); writeErr != nil {
+				return fmt.Errorf("failed to write to file: %w", writeErr)
+			}
+			received += int64(n)
+
+			// Acknowledge the total received so far (truncated to 32 bits)
+			binary.BigEndian.PutUint32(ack, uint32(received))
+			if _, ackErr := conn.Write(ack); ackErr != nil {
+				return fmt.Errorf("failed to acknowledge DCC data: %w", ackErr)
+			}
+
+			if progress != nil && time.Since(lastUpdate) >= 500*time.Millisecond {
+				progress(received, o.Size)
+				lastUpdate = time.Now()
+			}
+		}
+
+		if err != nil {
+			if err == io.EOF && o.Size == 0 {
+				break
+			}
+			if err == io.EOF {
+				return fmt.Errorf("transfer interrupted at %d of %d bytes: %w", received, o.Size, io.ErrUnexpectedEOF)
+			}
+			return fmt.Errorf("transfer interrupted at %d bytes: %w", received, err)
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("error closing output file: %w", err)
+	}
+	if progress != nil {
+		progress(received, o.Size)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package xdcc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// message is a parsed IRC protocol line
+type message struct {
+	Prefix  string
+	Command string
+	Params  []string
+}
+
+// nick returns the nickname part of the message prefix
+func (m message) nick() string {
+	nick, _, _ := strings.Cut(m.Prefix, "!")
+	return nick
+}
+
+// trailing returns the last parameter
+func (m message) trailing() string {
+	if len(m.Params) == 0 {
+		return ""
+	}
+	return m.Params[len(m.Params)-1]
+}
+
+// parseMessage parses a raw IRC line (without the trailing CRLF)
+func parseMessage(line string) message {
+	var m message
+	if strings.HasPrefix(line, ":") {
+		m.Prefix, line, _ = strings.Cut(line[1:], " ")
+	}
+
+	line, trailing, hasTrailing := strings.Cut(line, " :")
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		m.Command = strings.ToUpper(fields[0])
+		m.Params = fields[1:]
+	}
+	if hasTrailing {
+		m.Params = append(m.Params, trailing)
+	}
+	return m
+}
+
+// session is a single IRC connection
+type session struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	nick    string
+	timeout time.Duration
+}
+
+func newSession(conn net.Conn, nick string, timeout time.Duration) *session {
+	return &session{conn: conn, reader: bufio.NewReader(conn), nick: nick, timeout: timeout}
+}
+
+// send writes a raw IRC line; write errors surface on the next read
+func (s *session) send(line string) {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
+	_, _ = s.conn.Write([]byte(line + "\r\n"))
+}
+
+// await reads messages until handle reports done, answering PINGs along the way
+func (s *session) await(handle func(m message) (bool, error)) error {
+	_ = s.conn.SetReadDeadline(time.Now().Add(s.timeout))
+	defer func() { _ = s.conn.SetReadDeadline(time.Time{}) }()
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return fmt.Errorf("timed out after %s", s.timeout)
+			}
+			return fmt.Errorf("connection closed: %w", err)
+		}
+
+		m := parseMessage(strings.TrimRight(line, "\r\n"))
+		switch m.Command {
+		case "PING":
+			s.send("PONG :" + m.trailing())
+			continue
+		case "ERROR":
+			return fmt.Errorf("server error: %s", m.trailing())
+		}
+
+		done, err := handle(m)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// register logs in, picking another nick if the chosen one is taken
+func (s *session) register() error {
+	s.send("NICK " + s.nick)
+	s.send(fmt.Sprintf("USER %s 0 * :greg", s.nick))
+
+	return s.await(func(m message) (bool, error) {
+		switch m.Command {
+		case "001":
+			return true, nil
+		case "432", "433", "436":
+			s.nick += "_"
+			s.send("NICK " + s.nick)
+		}
+		return false, nil
+	})
+}
+
+// join joins a channel; many bots only serve users in their channel
+func (s *session) join(channel string) error {
+	s.send("JOIN " + channel)
+
+	return s.await(func(m message) (bool, error) {
+		switch m.Command {
+		case "JOIN":
+			return strings.EqualFold(m.nick(), s.nick), nil
+		case "403", "405", "471", "473", "474", "475", "477":
+			return false, fmt.Errorf("%s", m.trailing())
+		}
+		return false, nil
+	})
+}
+
+// awaitOffer waits for the bot's DCC SEND, remembering its notices so a
+// refusal ("invalid pack", "queue full", ...) ends up in the error
+func (s *session) awaitOffer(bot string) (offer, error) {
+	var result offer
+	var lastNotice string
+
+	err := s.await(func(m message) (bool, error) {
+		if !strings.EqualFold(m.nick(), bot) {
+			return false, nil
+		}
+		if text, ok := ctcp(m.trailing()); ok {
+			if !isDCC(text, "SEND") {
+				return false, nil
+			}
+			o, err := parseOffer(text)
+			if err != nil {
+				return false, err
+			}
+			result = o
+			return true, nil
+		}
+		if m.Command == "NOTICE" || m.Command == "PRIVMSG" {
+			lastNotice = m.trailing()
+		}
+		return false, nil
+	})
+	if err != nil && lastNotice != "" {
+		return offer{}, fmt.Errorf("%w (last message: %s)", err, lastNotice)
+	}
+	return result, err
+}
+
+// awaitAccept waits for the bot's DCC ACCEPT and returns the resume position
+func (s *session) awaitAccept(bot string) (int64, error) {
+	var position int64
+
+	err := s.await(func(m message) (bool, error) {
+		if !strings.EqualFold(m.nick(), bot) {
+			return false, nil
+		}
+		text, ok := ctcp(m.trailing())
+		if !ok || !isDCC(text, "ACCEPT") {
+			return false, nil
+		}
+		p, err := parseAccept(text)
+		if err != nil {
+			return false, err
+		}
+		position = p
+		return true, nil
+	})
+	return position, err
+}
+
+// ctcp extracts the body of a CTCP message (\x01...\x01)
+func ctcp(text string) (string, bool) {
+	if len(text) < 2 || text[0] != '\x01' {
+		return "", false
+	}
+	return strings.TrimSuffix(text[1:], "\x01"), true
+}
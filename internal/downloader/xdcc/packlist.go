@@ -0,0 +1,103 @@
+package xdcc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a pack listed in a bot's packlist
+type Entry struct {
+	Bot      string
+	Number   int
+	Gets     int
+	Size     string // As listed, e.g. "1.2G"
+	Filename string
+}
+
+var (
+	// packLine matches iroffer packlist lines, e.g. "#12   34x [1.2G] [SubsPlease] Show - 01 (1080p).mkv"
+	packLine = regexp.MustCompile(`^#(\d+)\s+(\d+)x\s+\[\s*([^\]]*?)\s*\]\s+(.+?)\s*$`)
+	// botLine matches the packlist header naming the bot, e.g. `** To request a file, type "/msg Bot xdcc send #x" **`
+	botLine = regexp.MustCompile(`(?i)/msg\s+(\S+)\s+xdcc\s+send`)
+)
+
+// ParsePacklist parses an iroffer-style text packlist. Packs are attributed
+// to the bot named in the header, or to bot when the header has none.
+func ParsePacklist(r io.Reader, bot string) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if m := botLine.FindStringSubmatch(line); m != nil {
+			bot = m[1]
+			continue
+		}
+
+		m := packLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(m[1])
+		gets, _ := strconv.Atoi(m[2])
+		entries = append(entries, Entry{
+			Bot:      bot,
+			Number:   number,
+			Gets:     gets,
+			Size:     m[3],
+			Filename: m[4],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read packlist: %w", err)
+	}
+	return entries, nil
+}
+
+// FetchPacklist downloads and parses a packlist served over HTTP
+func FetchPacklist(ctx context.Context, url string) ([]Entry, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch packlist: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch packlist: unexpected status code: %d", resp.StatusCode)
+	}
+
+	return ParsePacklist(resp.Body, "")
+}
+
+// Search returns the entries whose filename contains every word of query
+func Search(entries []Entry, query string) []Entry {
+	words := strings.Fields(strings.ToLower(query))
+	var matches []Entry
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Filename)
+		matched := true
+		for _, word := range words {
+			if !strings.Contains(name, word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,184 @@
+// Package xdcc provides XDCC (IRC bot file transfer) download functionality
+package xdcc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheme is the URL scheme used for XDCC packs in download tasks
+const Scheme = "xdcc"
+
+// DefaultServer is the IRC network most anime XDCC bots live on
+const DefaultServer = "irc.rizon.net:6667"
+
+// ErrPassiveDCC is returned when a bot offers a passive (reverse) DCC transfer,
+// which would require greg to accept an incoming connection
+var ErrPassiveDCC = errors.New("passive DCC transfers are not supported")
+
+// Pack identifies a file offered by an XDCC bot
+type Pack struct {
+	Server  string // IRC server as host:port
+	Channel string // Channel to join before requesting, without the leading '#' (optional)
+	Bot     string
+	Number  int
+}
+
+// IsURL reports whether raw is an xdcc:// pack URL
+func IsURL(raw string) bool {
+	return strings.HasPrefix(raw, Scheme+"://")
+}
+
+// ParseURL parses an xdcc://server[:port]/[channel/]bot/pack URL.
+// Channels are written without '#', since it would start the URL fragment.
+func ParseURL(raw string) (Pack, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Pack{}, fmt.Errorf("invalid XDCC URL: %w", err)
+	}
+	if u.Scheme != Scheme || u.Host == "" {
+		return Pack{}, fmt.Errorf("invalid XDCC URL %q: expected %s://server/[channel/]bot/pack", raw, Scheme)
+	}
+
+	server := u.Host
+	if u.Port() == "" {
+		server = net.JoinHostPort(u.Hostname(), "6667")
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	pack := Pack{Server: server}
+	switch len(segments) {
+	case 2:
+		pack.Bot = segments[0]
+	case 3:
+		pack.Channel = strings.TrimPrefix(segments[0], "#")
+		pack.Bot = segments[1]
+	default:
+		return Pack{}, fmt.Errorf("invalid XDCC URL %q: expected %s://server/[channel/]bot/pack", raw, Scheme)
+	}
+
+	pack.Number, err = strconv.Atoi(strings.TrimPrefix(segments[len(segments)-1], "#"))
+	if err != nil || pack.Number <= 0 || pack.Bot == "" {
+		return Pack{}, fmt.Errorf("invalid XDCC URL %q: bad bot or pack number", raw)
+	}
+
+	return pack, nil
+}
+
+// URL returns the xdcc:// URL for the pack
+func (p Pack) URL() string {
+	path := p.Bot + "/" + strconv.Itoa(p.Number)
+	if p.Channel != "" {
+		path = strings.TrimPrefix(p.Channel, "#") + "/" + path
+	}
+	return Scheme + "://" + p.Server + "/" + path
+}
+
+// ProgressCallback reports transferred bytes; total is the size announced by the bot
+type ProgressCallback func(downloaded, total int64)
+
+// Downloader requests packs from XDCC bots and receives them over DCC
+type Downloader struct {
+	nick    string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+// NewDownloader creates a new XDCC downloader. An empty nick picks a random one.
+func NewDownloader(nick string) *Downloader {
+	if nick == "" {
+		nick = fmt.Sprintf("greg%04d", rand.IntN(10000))
+	}
+	return &Downloader{
+		nick:    nick,
+		timeout: 2 * time.Minute,
+		dialer:  net.Dialer{Timeout: 30 * time.Second},
+	}
+}
+
+// Download requests the pack and writes it to output. If output already holds
+// part of the file, the transfer is resumed from where it stopped.
+func (d *Downloader) Download(ctx context.Context, pack Pack, output string, progress ProgressCallback) error {
+	var offset int64
+	if info, err := os.Stat(output); err == nil {
+		offset = info.Size()
+	}
+
+	conn, err := d.dialIRC(ctx, pack.Server)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", pack.Server, err)
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	s := newSession(conn, d.nick, d.timeout)
+	defer s.send("QUIT :greg")
+
+	if err := s.register(); err != nil {
+		return d.wrapErr(ctx, fmt.Errorf("IRC registration failed: %w", err))
+	}
+	if pack.Channel != "" {
+		if err := s.join("#" + strings.TrimPrefix(pack.Channel, "#")); err != nil {
+			return d.wrapErr(ctx, fmt.Errorf("failed to join #%s: %w", pack.Channel, err))
+		}
+	}
+
+	s.send(fmt.Sprintf("PRIVMSG %s :XDCC SEND #%d", pack.Bot, pack.Number))
+	offer, err := s.awaitOffer(pack.Bot)
+	if err != nil {
+		return d.wrapErr(ctx, fmt.Errorf("bot %s did not send pack #%d: %w", pack.Bot, pack.Number, err))
+	}
+	if offer.Port == 0 {
+		return ErrPassiveDCC
+	}
+
+	switch {
+	case offer.Size > 0 && offset == offer.Size:
+		// Already complete from an earlier attempt
+		s.send(fmt.Sprintf("PRIVMSG %s :XDCC REMOVE #%d", pack.Bot, pack.Number))
+		if progress != nil {
+			progress(offset, offer.Size)
+		}
+		return nil
+	case offset > 0 && (offer.Size == 0 || offset > offer.Size):
+		// Unknown size or a different file: start over
+		offset = 0
+	case offset > 0:
+		s.send(fmt.Sprintf("PRIVMSG %s :\x01DCC RESUME %s %d %d\x01", pack.Bot, quoteFilename(offer.Filename), offer.Port, offset))
+		accepted, err := s.awaitAccept(pack.Bot)
+		if err != nil {
+			return d.wrapErr(ctx, fmt.Errorf("bot %s did not accept resume: %w", pack.Bot, err))
+		}
+		offset = accepted
+	}
+
+	return d.wrapErr(ctx, d.receive(ctx, offer, output, offset, progress))
+}
+
+// dialIRC connects to an IRC server, using TLS on the conventional port 6697
+func (d *Downloader) dialIRC(ctx context.Context, server string) (net.Conn, error) {
+	if _, port, err := net.SplitHostPort(server); err == nil && port == "6697" {
+		tlsDialer := &tls.Dialer{NetDialer: &d.dialer}
+		return tlsDialer.DialContext(ctx, "tcp", server)
+	}
+	return d.dialer.DialContext(ctx, "tcp", server)
+}
+
+// wrapErr prefers the context error when the download was cancelled, since
+// cancellation surfaces as a closed connection
+func (d *Downloader) wrapErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package xdcc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURL(t *testing.T) {
+	pack, err := ParseURL("xdcc://irc.rizon.net/nibl/Ginpachi-Sensei/1234")
+	require.NoError(t, err)
+	assert.Equal(t, Pack{Server: "irc.rizon.net:6667", Channel: "nibl", Bot: "Ginpachi-Sensei", Number: 1234}, pack)
+	assert.Equal(t, "xdcc://irc.rizon.net:6667/nibl/Ginpachi-Sensei/1234", pack.URL())
+
+	pack, err = ParseURL("xdcc://irc.rizon.net:6697/Bot/7")
+	require.NoError(t, err)
+	assert.Equal(t, Pack{Server: "irc.rizon.net:6697", Bot: "Bot", Number: 7}, pack)
+
+	for _, raw := range []string{"https://irc.rizon.net/Bot/1", "xdcc://irc.rizon.net/Bot", "xdcc://irc.rizon.net/Bot/abc", "xdcc:///Bot/1"} {
+		_, err := ParseURL(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestParseOffer(t *testing.T) {
+	o, err := parseOffer(`DCC SEND "[Group] Show - 01 (1080p).mkv" 3232235777 5000 1048576`)
+	require.NoError(t, err)
+	assert.Equal(t, "[Group] Show - 01 (1080p).mkv", o.Filename)
+	assert.Equal(t, "192.168.1.1", o.IP.String())
+	assert.Equal(t, 5000, o.Port)
+	assert.Equal(t, int64(1048576), o.Size)
+
+	o, err = parseOffer("DCC SEND file.mkv 2001:db8::1 0 100 42")
+	require.NoError(t, err)
+	assert.Equal(t, 0, o.Port)
+
+	position, err := parseAccept("DCC ACCEPT file.mkv 5000 4096")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), position)
+}
+
+func TestParsePacklist(t *testing.T) {
+	list := `** 3 packs **  1 of 10 slots open
+** To request a file, type "/msg CR-HOLLAND|NEW xdcc send #x" **
+#1   12x [1.4G] [SubsPlease] Frieren - 01 (1080p) [ABCD1234].mkv
+#2    3x [ 350M] [SubsPlease] Frieren - 02 (480p) [EF567890].mkv
+#3    0x [1.3G] [Erai-raws] Frieren - 02 [1080p].mkv
+Total Offered: 3.1 GB  Total Transferred: 20 GB`
+
+	entries, err := ParsePacklist(strings.NewReader(list), "")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, Entry{Bot: "CR-HOLLAND|NEW", Number: 2, Gets: 3, Size: "350M", Filename: "[SubsPlease] Frieren - 02 (480p) [EF567890].mkv"}, entries[1])
+
+	matches := Search(entries, "frieren 1080P")
+	require.Len(t, matches, 2)
+	assert.Equal(t, 1, matches[0].Number)
+	assert.Equal(t, 3, matches[1].Number)
+}
+
+// fakeBot is a minimal IRC server hosting one XDCC bot
+type fakeBot struct {
+	irc     net.Listener
+	dcc     net.Listener
+	content []byte
+	resumed chan int64
+}
+
+func newFakeBot(t *testing.T, content []byte) *fakeBot {
+	irc, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dcc, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = irc.Close()
+		_ = dcc.Close()
+	})
+
+	b := &fakeBot{irc: irc, dcc: dcc, content: content, resumed: make(chan int64, 1)}
+	go b.serveIRC()
+	go b.serveDCC()
+	return b
+}
+
+func (b *fakeBot) serveIRC() {
+	conn, err := b.irc.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	dccPort := b.dcc.Addr().(*net.TCPAddr).Port
+	nick := ""
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		m := parseMessage(strings.TrimRight(line, "\r\n"))
+		switch m.Command {
+		case "NICK":
+			nick = m.Params[0]
+		case "USER":
+			_, _ = fmt.Fprintf(conn, ":irc.test PING :check\r\n:irc.test 001 %s :Welcome\r\n", nick)
+		case "JOIN":
+			_, _ = fmt.Fprintf(conn, ":%s!user@host JOIN %s\r\n", nick, m.Params[0])
+		case "PRIVMSG":
+			text := m.trailing()
+			if strings.EqualFold(text, "XDCC SEND #5") {
+				_, _ = fmt.Fprintf(conn, ":Bot!bot@host NOTICE %s :** Sending you pack #5\r\n", nick)
+				_, _ = fmt.Fprintf(conn, ":Bot!bot@host PRIVMSG %s :\x01DCC SEND \"Show - 01.mkv\" 2130706433 %d %d\x01\r\n", nick, dccPort, len(b.content))
+			} else if body, ok := ctcp(text); ok && isDCC(body, "RESUME") {
+				fields := strings.Fields(body)
+				position, _ := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+				b.resumed <- position
+				_, _ = fmt.Fprintf(conn, ":Bot!bot@host PRIVMSG %s :\x01DCC ACCEPT \"Show - 01.mkv\" %d %d\x01\r\n", nick, dccPort, position)
+			}
+		case "QUIT":
+			return
+		}
+	}
+}
+
+func (b *fakeBot) serveDCC() {
+	conn, err := b.dcc.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	var position int64
+	select {
+	case position = <-b.resumed:
+	default:
+	}
+	_, _ = conn.Write(b.content[position:])
+
+	// Wait for the final acknowledgement before closing
+	ack := make([]byte, 4)
+	for {
+		if _, err := conn.Read(ack); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBot) pack() Pack {
+	return Pack{Server: b.irc.Addr().String(), Channel: "test", Bot: "Bot", Number: 5}
+}
+
+func TestDownload(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 10000))
+	bot := newFakeBot(t, content)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output := filepath.Join(t.TempDir(), "out.mkv")
+	var lastDownloaded, lastTotal int64
+	err := NewDownloader("tester").Download(ctx, bot.pack(), output, func(downloaded, total int64) {
+		lastDownloaded, lastTotal = downloaded, total
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+	assert.Equal(t, int64(len(content)), lastDownloaded)
+	assert.Equal(t, int64(len(content)), lastTotal)
+}
+
+func TestDownloadResumes(t *testing.T) {
+	content := []byte(strings.Repeat("abcdefghij", 10000))
+	bot := newFakeBot(t, content)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output := filepath.Join(t.TempDir(), "out.mkv")
+	require.NoError(t, os.WriteFile(output, content[:4096], 0644))
+
+	require.NoError(t, NewDownloader("tester").Download(ctx, bot.pack(), output, nil))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+}
@@ -0,0 +1,190 @@
+package extensions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+const testScraper = `name: demo
+base_url: https://demo.example
+search:
+  url: "{base}/search?q={query}"
+  items: .item
+  fields:
+    id: {selector: a, attr: href}
+    title: {selector: a}
+stream:
+  type: ytdlp
+`
+
+const testTheme = `name: dracula
+colors:
+  purple: "#bd93f9"
+`
+
+// testRepo serves a signed index and its files
+type testRepo struct {
+	files map[string]string
+	index Index
+	key   ed25519.PrivateKey
+}
+
+func newTestRepo(t *testing.T) (*testRepo, ed25519.PublicKey) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return &testRepo{files: map[string]string{}, key: key}, pub
+}
+
+func (r *testRepo) publish(name, kind, version, content string) {
+	file := name + "-" + version + ".yaml"
+	r.files["/"+file] = content
+	sum := sha256.Sum256([]byte(content))
+	release := Release{Version: version, URL: file, SHA256: hex.EncodeToString(sum[:])}
+	if entry, ok := r.index.Find(name); ok {
+		entry.Versions = append(entry.Versions, release)
+		return
+	}
+	r.index.Extensions = append(r.index.Extensions, Entry{Name: name, Kind: kind, Versions: []Release{release}})
+}
+
+func (r *testRepo) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	index, _ := json.Marshal(r.index)
+	switch req.URL.Path {
+	case "/index.json":
+		_, _ = w.Write(index)
+	case "/index.json.sig":
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(ed25519.Sign(r.key, index))))
+	default:
+		content, ok := r.files[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}
+}
+
+func newTestManager(t *testing.T, repo *testRepo, pub ed25519.PublicKey) *Manager {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	server := httptest.NewServer(repo)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	return &Manager{
+		db:     db,
+		client: server.Client(),
+		cfg: config.ExtensionsConfig{
+			Repo:      server.URL + "/index.json",
+			PublicKey: base64.StdEncoding.EncodeToString(pub),
+		},
+		scrapersDir: filepath.Join(dir, "scrapers"),
+		themesDir:   filepath.Join(dir, "themes"),
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, CompareVersions("1.2.0", "v1.2.0"))
+	assert.Equal(t, -1, CompareVersions("1.2", "1.10"))
+	assert.Equal(t, 1, CompareVersions("1.2.1", "1.2"))
+	assert.Equal(t, -1, CompareVersions("1.0-beta", "1.0-rc"))
+}
+
+func TestInstallAndUpdate(t *testing.T) {
+	repo, pub := newTestRepo(t)
+	repo.publish("demo", KindScraper, "1.0.0", testScraper)
+	repo.publish("dracula", KindTheme, "1.0.0", testTheme)
+	m := newTestManager(t, repo, pub)
+	ctx := context.Background()
+
+	ext, err := m.Install(ctx, "demo", "")
+	require.NoError(t, err)
+	assert.False(t, ext.Pinned)
+	data, err := os.ReadFile(filepath.Join(m.scrapersDir, "demo.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, testScraper, string(data))
+
+	theme, err := m.Install(ctx, "dracula", "1.0.0")
+	require.NoError(t, err)
+	assert.True(t, theme.Pinned)
+	assert.FileExists(t, filepath.Join(m.themesDir, "dracula.yaml"))
+
+	repo.publish("demo", KindScraper, "1.1.0", testScraper+"# v1.1\n")
+	repo.publish("dracula", KindTheme, "2.0.0", testTheme)
+	m.index = nil
+
+	updates, err := m.Update(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, Update{Name: "demo", From: "1.0.0", To: "1.1.0"}, updates[0])
+	assert.Equal(t, Update{Name: "dracula", From: "1.0.0"}, updates[1], "pinned themes stay")
+
+	installed, err := database.GetExtension(m.db, "demo")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", installed.Version)
+
+	updates, err = m.Update(ctx, "dracula")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", updates[0].To, "naming a pinned extension updates it")
+
+	require.NoError(t, m.Remove("demo"))
+	assert.NoFileExists(t, filepath.Join(m.scrapersDir, "demo.yaml"))
+	_, err = database.GetExtension(m.db, "demo")
+	assert.Error(t, err)
+}
+
+func TestIndexRejectsBadSignature(t *testing.T) {
+	repo, _ := newTestRepo(t)
+	repo.publish("demo", KindScraper, "1.0.0", testScraper)
+	other, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	m := newTestManager(t, repo, other)
+
+	_, err = m.Index(context.Background())
+	assert.ErrorContains(t, err, "signature")
+
+	m.cfg.AllowUnsigned = true
+	_, err = m.Index(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestInstallRejectsTamperedFile(t *testing.T) {
+	repo, pub := newTestRepo(t)
+	repo.publish("demo", KindScraper, "1.0.0", testScraper)
+	repo.files["/demo-1.0.0.yaml"] = testScraper + "# tampered\n"
+	m := newTestManager(t, repo, pub)
+
+	_, err := m.Install(context.Background(), "demo", "")
+	assert.ErrorContains(t, err, "checksum")
+	assert.NoFileExists(t, filepath.Join(m.scrapersDir, "demo.yaml"))
+}
+
+func TestInstallKeepsUnmanagedFiles(t *testing.T) {
+	repo, pub := newTestRepo(t)
+	repo.publish("demo", KindScraper, "1.0.0", testScraper)
+	m := newTestManager(t, repo, pub)
+	require.NoError(t, os.MkdirAll(m.scrapersDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(m.scrapersDir, "demo.yaml"), []byte("mine"), 0o644))
+
+	_, err := m.Install(context.Background(), "demo", "")
+	assert.ErrorContains(t, err, "wasn't installed by greg")
+}
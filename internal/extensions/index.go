@@ -0,0 +1,157 @@
+// Package extensions installs community scrapers and themes from a signed
+// repository index.
+package extensions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Kinds of extensions
+const (
+	KindScraper = "scraper" // YAML scraper descriptor, see providers/generic
+	KindTheme   = "theme"   // TUI color theme
+)
+
+// Index lists the extensions of a repository
+type Index struct {
+	Extensions []Entry `json:"extensions"`
+}
+
+// Entry is an extension and its published versions
+type Entry struct {
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	Author      string    `json:"author,omitempty"`
+	Versions    []Release `json:"versions"`
+}
+
+// Release is one published version of an extension
+type Release struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`    // Relative URLs resolve against the index
+	SHA256  string `json:"sha256"` // Hex digest of the file
+}
+
+// Find returns the entry of an extension
+func (idx *Index) Find(name string) (*Entry, bool) {
+	for i := range idx.Extensions {
+		if idx.Extensions[i].Name == name {
+			return &idx.Extensions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Latest returns the highest version
+func (e *Entry) Latest() (Release, bool) {
+	var latest Release
+	for _, r := range e.Versions {
+		if latest.Version == "" || CompareVersions(r.Version, latest.Version) > 0 {
+			latest = r
+		}
+	}
+	return latest, latest.Version != ""
+}
+
+// Release returns a given version
+func (e *Entry) Release(version string) (Release, bool) {
+	for _, r := range e.Versions {
+		if CompareVersions(r.Version, version) == 0 {
+			return r, true
+		}
+	}
+	return Release{}, false
+}
+
+// CompareVersions compares dotted versions such as "1.2.10" and "v1.3",
+// returning -1, 0 or 1
+func CompareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case x == y:
+			continue
+		case xerr == nil && yerr == nil:
+			if xn < yn {
+				return -1
+			}
+			if xn > yn {
+				return 1
+			}
+		case x < y:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
+
+// verifyIndex checks the base64 ed25519 signature of the index bytes
+func verifyIndex(data []byte, signature, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid extensions.public_key: want a base64 ed25519 key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("invalid index signature: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return fmt.Errorf("index signature doesn't match extensions.public_key")
+	}
+	return nil
+}
+
+// fetch returns the body of url
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxFileSize)
+	}
+	return data, nil
+}
+
+// parseIndex decodes an index
+func parseIndex(data []byte) (*Index, error) {
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse extension index: %w", err)
+	}
+	return &idx, nil
+}
@@ -0,0 +1,309 @@
+package extensions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers/generic"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// maxFileSize bounds the index and extension files
+const maxFileSize = 1 << 20
+
+// ThemesDir returns the directory holding installed themes
+func ThemesDir() string {
+	return filepath.Join(config.GetConfigDir(), "themes")
+}
+
+// Manager installs, updates and removes extensions
+type Manager struct {
+	db          *gorm.DB
+	client      *http.Client
+	cfg         config.ExtensionsConfig
+	scrapersDir string
+	themesDir   string
+	index       *Index
+}
+
+// NewManager manages the extensions of the repository in cfg
+func NewManager(db *gorm.DB, cfg *config.Config) *Manager {
+	return &Manager{
+		db:          db,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		cfg:         cfg.Extensions,
+		scrapersDir: cfg.Providers.Scrapers.ScrapersDir(),
+		themesDir:   ThemesDir(),
+	}
+}
+
+// Index fetches and verifies the repository index
+func (m *Manager) Index(ctx context.Context) (*Index, error) {
+	if m.index != nil {
+		return m.index, nil
+	}
+	if m.cfg.Repo == "" {
+		return nil, fmt.Errorf("no extension repository: set extensions.repo")
+	}
+
+	data, err := fetch(ctx, m.client, m.cfg.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(ctx, data); err != nil {
+		if !m.cfg.AllowUnsigned {
+			return nil, err
+		}
+	}
+
+	idx, err := parseIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	m.index = idx
+	return idx, nil
+}
+
+func (m *Manager) verify(ctx context.Context, data []byte) error {
+	if m.cfg.PublicKey == "" {
+		return fmt.Errorf("can't verify the extension index: set extensions.public_key (or extensions.allow_unsigned)")
+	}
+	signature, err := fetch(ctx, m.client, m.cfg.Repo+".sig")
+	if err != nil {
+		return fmt.Errorf("failed to fetch index signature: %w", err)
+	}
+	return verifyIndex(data, string(signature), m.cfg.PublicKey)
+}
+
+// Install installs an extension at version, or at its latest version when
+// version is empty. A requested version is pinned: updates leave it alone.
+func (m *Manager) Install(ctx context.Context, name, version string) (*database.Extension, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := idx.Find(name)
+	if !ok {
+		return nil, fmt.Errorf("extension not found in the repository: %s", name)
+	}
+
+	var release Release
+	if version == "" {
+		release, ok = entry.Latest()
+	} else {
+		release, ok = entry.Release(version)
+	}
+	if !ok {
+		return nil, fmt.Errorf("no version %q of %s", version, name)
+	}
+	return m.install(ctx, entry, release, version != "")
+}
+
+func (m *Manager) install(ctx context.Context, entry *Entry, release Release, pinned bool) (*database.Extension, error) {
+	path, err := m.path(entry)
+	if err != nil {
+		return nil, err
+	}
+	installed, _ := database.GetExtension(m.db, entry.Name)
+	if installed == nil {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%s exists and wasn't installed by greg, remove it first", path)
+		}
+	}
+
+	fileURL, err := resolve(m.cfg.Repo, release.URL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fetch(ctx, m.client, fileURL)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if release.SHA256 != digest && (release.SHA256 != "" || !m.cfg.AllowUnsigned) {
+		return nil, fmt.Errorf("checksum mismatch for %s %s", entry.Name, release.Version)
+	}
+	if err := validate(entry, data); err != nil {
+		return nil, fmt.Errorf("invalid %s %s: %w", entry.Kind, entry.Name, err)
+	}
+
+	if err := writeFile(path, data); err != nil {
+		return nil, err
+	}
+	ext := &database.Extension{
+		Name:        entry.Name,
+		Kind:        entry.Kind,
+		Version:     release.Version,
+		Pinned:      pinned,
+		Path:        path,
+		SHA256:      digest,
+		InstalledAt: time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if installed != nil {
+		ext.InstalledAt = installed.InstalledAt
+	}
+	if err := database.SaveExtension(m.db, ext); err != nil {
+		return nil, fmt.Errorf("failed to record extension: %w", err)
+	}
+	return ext, nil
+}
+
+// path returns where an extension is installed
+func (m *Manager) path(entry *Entry) (string, error) {
+	if !namePattern(entry.Name) {
+		return "", fmt.Errorf("invalid extension name %q", entry.Name)
+	}
+	switch entry.Kind {
+	case KindScraper:
+		return filepath.Join(m.scrapersDir, entry.Name+".yaml"), nil
+	case KindTheme:
+		return filepath.Join(m.themesDir, entry.Name+".yaml"), nil
+	default:
+		return "", fmt.Errorf("unsupported extension kind %q", entry.Kind)
+	}
+}
+
+// namePattern keeps names usable as file names
+func namePattern(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks that data is a working extension of its kind
+func validate(entry *Entry, data []byte) error {
+	switch entry.Kind {
+	case KindScraper:
+		d, err := generic.Parse(data)
+		if err != nil {
+			return err
+		}
+		if d.Name != entry.Name {
+			return fmt.Errorf("descriptor is named %q", d.Name)
+		}
+	case KindTheme:
+		if _, err := styles.ParseTheme(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolve(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid extensions.repo: %w", err)
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid extension URL %q: %w", ref, err)
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// writeFile replaces path with data without leaving a partial file behind
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to install %s: %w", path, err)
+	}
+	return nil
+}
+
+// Update is the outcome of updating one extension
+type Update struct {
+	Name string
+	From string
+	To   string // Empty when already up to date or pinned
+	Err  error
+}
+
+// Update moves installed extensions (all when names is empty) to their
+// latest version. Pinned extensions are kept unless named.
+func (m *Manager) Update(ctx context.Context, names ...string) ([]Update, error) {
+	idx, err := m.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var exts []database.Extension
+	if len(names) == 0 {
+		if exts, err = database.ListExtensions(m.db); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range names {
+		ext, err := database.GetExtension(m.db, name)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, *ext)
+	}
+
+	var updates []Update
+	for _, ext := range exts {
+		update := Update{Name: ext.Name, From: ext.Version}
+		entry, ok := idx.Find(ext.Name)
+		if !ok {
+			update.Err = fmt.Errorf("no longer in the repository")
+			updates = append(updates, update)
+			continue
+		}
+		latest, ok := entry.Latest()
+		if !ok || (ext.Pinned && len(names) == 0) || CompareVersions(latest.Version, ext.Version) <= 0 {
+			updates = append(updates, update)
+			continue
+		}
+		if _, err := m.install(ctx, entry, latest, false); err != nil {
+			update.Err = err
+		} else {
+			update.To = latest.Version
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// Remove uninstalls an extension
+func (m *Manager) Remove(name string) error {
+	ext, err := database.GetExtension(m.db, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(ext.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", ext.Path, err)
+	}
+	return database.RemoveExtension(m.db, name)
+}
+
+// Installed returns the installed extensions
+func (m *Manager) Installed() ([]database.Extension, error) {
+	return database.ListExtensions(m.db)
+}
@@ -0,0 +1,94 @@
+// Package feeds ingests RSS release feeds (SubsPlease, nyaa searches, ...)
+// and matches their entries against subscribed shows
+package feeds
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Item is a single feed entry
+type Item struct {
+	GUID      string
+	Title     string
+	Link      string // Download link: torrent/magnet, XDCC pack or direct file
+	Published time.Time
+}
+
+// rssDocument is the subset of RSS 2.0 used by release feeds
+type rssDocument struct {
+	Channel struct {
+		Items []struct {
+			Title     string `xml:"title"`
+			Link      string `xml:"link"`
+			GUID      string `xml:"guid"`
+			PubDate   string `xml:"pubDate"`
+			Enclosure struct {
+				URL string `xml:"url,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// Parse parses an RSS 2.0 feed
+func Parse(r io.Reader) ([]Item, error) {
+	var doc rssDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	items := make([]Item, 0, len(doc.Channel.Items))
+	for _, entry := range doc.Channel.Items {
+		item := Item{
+			GUID:  strings.TrimSpace(entry.GUID),
+			Title: strings.TrimSpace(entry.Title),
+			Link:  strings.TrimSpace(entry.Link),
+		}
+		// Enclosures point at the actual file (e.g. the .torrent), links often at a web page
+		if entry.Enclosure.URL != "" {
+			item.Link = strings.TrimSpace(entry.Enclosure.URL)
+		}
+		if item.GUID == "" {
+			item.GUID = item.Link
+		}
+		if item.GUID == "" {
+			item.GUID = item.Title
+		}
+		for _, layout := range []string{time.RFC1123Z, time.RFC1123} {
+			if t, err := time.Parse(layout, strings.TrimSpace(entry.PubDate)); err == nil {
+				item.Published = t
+				break
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// Fetch downloads and parses a feed
+func Fetch(ctx context.Context, url string) ([]Item, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "greg")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch feed: unexpected status code: %d", resp.StatusCode)
+	}
+
+	return Parse(resp.Body)
+}
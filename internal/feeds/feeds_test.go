@@ -0,0 +1,159 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/releases"
+	"github.com/justchokingaround/greg/internal/torrentclient"
+)
+
+const subsPleaseFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>SubsPlease RSS</title>
+    <item>
+      <title>[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv</title>
+      <link>magnet:?xt=urn:btih:abcd</link>
+      <guid isPermaLink="false">ABCD1234</guid>
+      <pubDate>Fri, 06 Oct 2023 15:00:00 +0000</pubDate>
+    </item>
+    <item>
+      <title>[SubsPlease] Other Show - 12 (1080p) [EF567890].mkv</title>
+      <link>https://nyaa.si/view/1</link>
+      <enclosure url="https://nyaa.si/download/1.torrent" type="application/x-bittorrent"/>
+    </item>
+  </channel>
+</rss>`
+
+func TestParse(t *testing.T) {
+	items, err := Parse(strings.NewReader(subsPleaseFeed))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	assert.Equal(t, "ABCD1234", items[0].GUID)
+	assert.Equal(t, "magnet:?xt=urn:btih:abcd", items[0].Link)
+	assert.Equal(t, 2023, items[0].Published.Year())
+
+	// Enclosure wins over the web page link and doubles as the GUID
+	assert.Equal(t, "https://nyaa.si/download/1.torrent", items[1].Link)
+	assert.Equal(t, items[1].Link, items[1].GUID)
+}
+
+func TestParseReleaseTitle(t *testing.T) {
+	tests := []struct {
+		title   string
+		show    string
+		episode int
+	}{
+		{"[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv", "Sousou no Frieren", 5},
+		{"[Erai-raws] Kusuriya no Hitorigoto - 12v2 [1080p][Multiple Subtitle]", "Kusuriya no Hitorigoto", 12},
+		{"Show Name S02E07 1080p WEB", "Show Name", 7},
+		{"[Group] Show Name EP03 [720p]", "Show Name", 3},
+		{"[Group] Re:Zero - Starting Life in Another World (Batch)", "Re:Zero - Starting Life in Another World", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			show, episode := ParseReleaseTitle(tt.title)
+			assert.Equal(t, tt.show, show)
+			assert.Equal(t, tt.episode, episode)
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher([]string{"Frieren", "Sousou no Frieren", "frieren", "Oshi no Ko"})
+
+	release, ok := m.Match(Item{Title: "[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv"})
+	require.True(t, ok)
+	assert.Equal(t, "Sousou no Frieren", release.Show, "longest subscription wins")
+	assert.Equal(t, 5, release.Episode)
+
+	_, ok = m.Match(Item{Title: "[SubsPlease] Oshi no Kotoba - 01 (1080p)"})
+	assert.False(t, ok, "subscriptions only match whole words")
+
+	_, ok = m.Match(Item{Title: "[SubsPlease] Other Show - 12 (1080p)"})
+	assert.False(t, ok)
+}
+
+func TestTorrentLinks(t *testing.T) {
+	assert.True(t, torrentclient.IsTorrent("magnet:?xt=urn:btih:abcd"))
+	assert.True(t, torrentclient.IsTorrent("https://nyaa.si/download/1.TORRENT?key=x"))
+	assert.False(t, torrentclient.IsTorrent("https://example.com/show-05.mkv"))
+
+	assert.Equal(t, []string{"transmission-remote", "-a", "magnet:x"}, torrentArgs("transmission-remote -a {url}", "magnet:x"))
+	assert.Equal(t, []string{"qbittorrent", "magnet:x"}, torrentArgs("qbittorrent", "magnet:x"))
+}
+
+// fakeQueue records queued download tasks
+type fakeQueue struct {
+	tasks []downloader.DownloadTask
+}
+
+func (q *fakeQueue) AddToQueue(ctx context.Context, task downloader.DownloadTask) error {
+	q.tasks = append(q.tasks, task)
+	return nil
+}
+
+func TestCheckQueuesPreferredRelease(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	var entries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `<rss version="2.0"><channel>`)
+		for _, title := range entries {
+			_, _ = fmt.Fprintf(w, `<item><title>%s</title><link>https://example.com/%s.mkv</link></item>`, title, strings.ReplaceAll(title, " ", "_"))
+		}
+		_, _ = fmt.Fprint(w, `</channel></rss>`)
+	}))
+	defer srv.Close()
+
+	feed, err := database.AddFeed(db, srv.URL, "", "Frieren")
+	require.NoError(t, err)
+
+	queue := &fakeQueue{}
+	in := NewIngester(db, queue, "")
+	in.SetPreferences(releases.Preferences{PreferredGroups: []string{"Erai-raws"}, BannedGroups: []string{"BadSubs"}})
+
+	// The first check only records the backlog
+	entries = []string{"[SubsPlease] Frieren - 04 (1080p)"}
+	result, err := in.Check(context.Background(), *feed, CheckOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Primed)
+	assert.Empty(t, queue.tasks)
+
+	feeds, err := database.ListFeeds(db)
+	require.NoError(t, err)
+	entries = append(entries,
+		"[BadSubs] Frieren - 05 (1080p)",
+		"[SubsPlease] Frieren - 05 (1080p)",
+		"[Erai-raws] Frieren - 05 [1080p]",
+	)
+	result, err = in.Check(context.Background(), feeds[0], CheckOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.New)
+	require.Len(t, queue.tasks, 1)
+	assert.Equal(t, 5, queue.tasks[0].Episode)
+	assert.Contains(t, queue.tasks[0].StreamURL, "Erai-raws")
+
+	// A later release of an already queued episode is ignored
+	entries = append(entries, "[Other] Frieren - 05 (720p)")
+	result, err = in.Check(context.Background(), feeds[0], CheckOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.New)
+	assert.Len(t, queue.tasks, 1)
+}
@@ -0,0 +1,258 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/downloader/xdcc"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/releases"
+	"github.com/justchokingaround/greg/internal/torrentclient"
+)
+
+// ErrNoTorrentClient is returned for torrent releases when neither a torrent
+// client nor a torrent command is configured
+var ErrNoTorrentClient = errors.New("no torrent client configured (set downloads.torrent or downloads.feeds.torrent_command)")
+
+// Queuer adds tasks to the download queue; implemented by *downloader.Manager
+type Queuer interface {
+	AddToQueue(ctx context.Context, task downloader.DownloadTask) error
+}
+
+// Ingester checks feeds and hands matching releases to the download queue or
+// an external torrent client
+type Ingester struct {
+	db             *gorm.DB
+	queue          Queuer
+	torrentCommand string
+	torrentClient  torrentclient.Client
+	prefs          releases.Preferences
+}
+
+// NewIngester creates a new feed ingester. torrentCommand is run for magnet
+// and .torrent links, with {url} replaced by the link (appended when absent).
+func NewIngester(db *gorm.DB, queue Queuer, torrentCommand string) *Ingester {
+	return &Ingester{db: db, queue: queue, torrentCommand: torrentCommand}
+}
+
+// SetPreferences sets the release group preferences used to pick among
+// releases of the same episode
+func (in *Ingester) SetPreferences(prefs releases.Preferences) {
+	in.prefs = prefs
+}
+
+// SetTorrentClient hands torrents to a qBittorrent or Transmission instance
+// instead of running the torrent command. They are recorded so their files
+// can be imported once finished.
+func (in *Ingester) SetTorrentClient(client torrentclient.Client) {
+	in.torrentClient = client
+}
+
+// CheckOptions controls a feed check
+type CheckOptions struct {
+	Watching []string // Extra show titles to match, e.g. the AniList watching list
+	DryRun   bool     // Report matches without recording or queueing them
+	Backfill bool     // Queue matches on the first check instead of only recording them
+}
+
+// QueuedRelease is a matched release and the outcome of queueing it
+type QueuedRelease struct {
+	Release
+	Err error // nil when queued (or skipped on a dry run or first check)
+}
+
+// Result summarizes a feed check
+type Result struct {
+	Feed     database.Feed
+	New      int // Entries not seen before
+	Primed   bool
+	Releases []QueuedRelease
+}
+
+// Check fetches a feed and queues new entries that match a subscription.
+// The first check of a feed only records its entries, so subscribing doesn't
+// queue the whole backlog, unless opts.Backfill is set. Releases from banned
+// groups are skipped, and of several releases of the same episode only the
+// preferred one is queued.
+func (in *Ingester) Check(ctx context.Context, feed database.Feed, opts CheckOptions) (*Result, error) {
+	items, err := Fetch(ctx, feed.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := append(splitMatch(feed.Match), opts.Watching...)
+	matcher := NewMatcher(titles)
+	result := &Result{Feed: feed, Primed: feed.LastCheckedAt == nil && !opts.Backfill}
+
+	var records []*database.FeedItem
+	var matched []Release
+	for _, item := range items {
+		seen, err := database.FeedItemSeen(in.db, feed.ID, item.GUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check feed entry: %w", err)
+		}
+		if seen {
+			continue
+		}
+		result.New++
+
+		records = append(records, &database.FeedItem{FeedID: feed.ID, GUID: item.GUID, Title: item.Title, Link: item.Link})
+		if release, ok := matcher.Match(item); ok {
+			matched = append(matched, release)
+		}
+	}
+
+	queued := make(map[string]bool) // show/episode pairs handled in this check
+	for _, release := range releases.Select(in.prefs, matched, func(r Release) string { return r.Title }) {
+		key := fmt.Sprintf("%s/%d", release.Show, release.Episode)
+		if release.Episode > 0 && queued[key] {
+			continue
+		}
+
+		outcome := QueuedRelease{Release: release}
+		if !opts.DryRun && !result.Primed {
+			if release.Episode > 0 {
+				done, err := database.FeedEpisodeQueued(in.db, release.Show, release.Episode)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check feed entry: %w", err)
+				}
+				if done {
+					continue
+				}
+			}
+			outcome.Err = in.Queue(ctx, release)
+		}
+		if outcome.Err == nil {
+			queued[key] = true
+		}
+		result.Releases = append(result.Releases, outcome)
+
+		for _, record := range records {
+			if record.GUID == release.GUID {
+				record.Show = release.Show
+				record.Episode = release.Episode
+				record.Queued = outcome.Err == nil && !opts.DryRun && !result.Primed
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, record := range records {
+		if err := database.SaveFeedItem(in.db, record); err != nil {
+			return nil, fmt.Errorf("failed to record feed entry: %w", err)
+		}
+	}
+	if err := database.MarkFeedChecked(in.db, feed.ID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to update feed: %w", err)
+	}
+	return result, nil
+}
+
+// Queue hands a release to the torrent client or the download queue
+func (in *Ingester) Queue(ctx context.Context, release Release) error {
+	link := release.Link
+	if link == "" {
+		return fmt.Errorf("entry has no link")
+	}
+
+	if torrentclient.IsTorrent(link) {
+		if in.torrentClient != nil {
+			return in.addTorrent(ctx, release)
+		}
+		if in.torrentCommand == "" {
+			return ErrNoTorrentClient
+		}
+		args := torrentArgs(in.torrentCommand, link)
+		if out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput(); err != nil {
+			return fmt.Errorf("torrent command failed: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if in.queue == nil {
+		return fmt.Errorf("download queue unavailable")
+	}
+
+	task := downloader.DownloadTask{
+		MediaID:    link,
+		MediaTitle: release.Show,
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    release.Episode,
+		Provider:   "feed",
+		StreamURL:  link,
+		StreamType: providers.StreamTypeMKV,
+		Checksum:   downloader.ChecksumFromName(release.Title),
+	}
+	switch {
+	case xdcc.IsURL(link):
+		task.StreamType = providers.StreamTypeXDCC
+	case strings.Contains(link, ".m3u8"):
+		task.StreamType = providers.StreamTypeHLS
+	case strings.EqualFold(path.Ext(link), ".mp4"):
+		task.StreamType = providers.StreamTypeMP4
+	}
+
+	if err := in.queue.AddToQueue(ctx, task); err != nil {
+		return fmt.Errorf("failed to add download to queue: %w", err)
+	}
+	return nil
+}
+
+// addTorrent hands a release to the torrent client and records it
+func (in *Ingester) addTorrent(ctx context.Context, release Release) error {
+	hash, err := in.torrentClient.Add(ctx, release.Link)
+	if err != nil {
+		return fmt.Errorf("failed to add torrent to %s: %w", in.torrentClient.Name(), err)
+	}
+	job := &database.TorrentJob{
+		Client:  in.torrentClient.Name(),
+		Hash:    hash,
+		Link:    release.Link,
+		Name:    release.Title,
+		Show:    release.Show,
+		Episode: release.Episode,
+	}
+	if err := database.AddTorrentJob(in.db, job); err != nil {
+		return fmt.Errorf("failed to record torrent: %w", err)
+	}
+	return nil
+}
+
+// torrentArgs builds the torrent client command line for a link
+func torrentArgs(command, link string) []string {
+	args := strings.Fields(command)
+	replaced := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{url}") {
+			args[i] = strings.ReplaceAll(arg, "{url}", link)
+			replaced = true
+		}
+	}
+	if !replaced {
+		args = append(args, link)
+	}
+	return args
+}
+
+// splitMatch splits a feed's comma-separated match list
+func splitMatch(match string) []string {
+	var titles []string
+	for _, title := range strings.Split(match, ",") {
+		if title = strings.TrimSpace(title); title != "" {
+			titles = append(titles, title)
+		}
+	}
+	return titles
+}
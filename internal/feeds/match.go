@@ -0,0 +1,95 @@
+package feeds
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Release is a feed entry recognized as an episode of a subscribed show
+type Release struct {
+	Item
+	Show    string // Subscription that matched
+	Episode int    // 0 when the title has no episode number
+}
+
+var (
+	// bracketed matches release tags such as "[SubsPlease]", "(1080p)" or "[ABCD1234]"
+	bracketed = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)|【[^】]*】`)
+	// fileExtension matches a trailing video file extension
+	fileExtension = regexp.MustCompile(`(?i)\.(mkv|mp4|avi|webm)$`)
+	// episodePatterns find the episode number, in order of preference
+	episodePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\s[-–]\s(\d{1,4})(?:v\d+)?(?:\s|$)`),                  // "Show - 05", "Show - 05v2"
+		regexp.MustCompile(`(?i)\sS\d{1,2}E(\d{1,4})(?:v\d+)?(?:\s|$)`),           // "Show S01E05"
+		regexp.MustCompile(`(?i)\s(?:E|EP|Episode)\s?(\d{1,4})(?:v\d+)?(?:\s|$)`), // "Show EP05"
+	}
+)
+
+// ParseReleaseTitle extracts the show name and episode number from a release
+// title like "[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv"
+func ParseReleaseTitle(title string) (show string, episode int) {
+	cleaned := fileExtension.ReplaceAllString(strings.TrimSpace(title), "")
+	cleaned = bracketed.ReplaceAllString(cleaned, " ")
+	cleaned = " " + strings.Join(strings.Fields(cleaned), " ") + " "
+
+	for _, pattern := range episodePatterns {
+		if m := pattern.FindStringSubmatchIndex(cleaned); m != nil {
+			episode, _ = strconv.Atoi(cleaned[m[2]:m[3]])
+			return strings.TrimSpace(cleaned[:m[0]]), episode
+		}
+	}
+	return strings.TrimSpace(cleaned), 0
+}
+
+// normalize lowercases a title and reduces punctuation to single spaces
+func normalize(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// subscription is a show title and its normalized form
+type subscription struct {
+	title string
+	key   string
+}
+
+// Matcher matches feed entries against subscribed show titles
+type Matcher struct {
+	subs []subscription
+}
+
+// NewMatcher creates a matcher for the given show titles
+func NewMatcher(titles []string) *Matcher {
+	m := &Matcher{}
+	seen := make(map[string]bool)
+	for _, title := range titles {
+		key := normalize(title)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		m.subs = append(m.subs, subscription{title: strings.TrimSpace(title), key: key})
+	}
+	return m
+}
+
+// Match reports whether the item is a release of a subscribed show. The show
+// name must contain a subscription as whole words; the longest one wins.
+func (m *Matcher) Match(item Item) (Release, bool) {
+	show, episode := ParseReleaseTitle(item.Title)
+	key := " " + normalize(show) + " "
+
+	var best *subscription
+	for i, sub := range m.subs {
+		if strings.Contains(key, " "+sub.key+" ") && (best == nil || len(sub.key) > len(best.key)) {
+			best = &m.subs[i]
+		}
+	}
+	if best == nil {
+		return Release{}, false
+	}
+	return Release{Item: item, Show: best.title, Episode: episode}, true
+}
@@ -0,0 +1,290 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+// ExportFormat is a file format watch history can be exported to
+type ExportFormat string
+
+const (
+	ExportCSV        ExportFormat = "csv"
+	ExportLetterboxd ExportFormat = "letterboxd" // Letterboxd diary import (movies)
+	ExportMALXML     ExportFormat = "mal_xml"    // MyAnimeList list import (anime)
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []ExportFormat{ExportCSV, ExportLetterboxd, ExportMALXML}
+
+// ExportEntry is a title of the watch history with its episodes combined
+type ExportEntry struct {
+	MediaID       string
+	Title         string
+	MediaType     string
+	ProviderName  string
+	AniListID     int
+	MALID         int
+	Watched       int // Episodes (or chapters) completed
+	TotalEpisodes int // 0 if unknown
+	Completed     bool
+	StartedAt     time.Time
+	CompletedAt   *time.Time // nil while unfinished
+	LastWatched   time.Time
+	Score         float64 // 0-10, 0 if unscored
+}
+
+// ExportEntries combines the history into one entry per title, oldest
+// first. Completed titles and scores come from library (the tracker list)
+// where a title is on it.
+func (s *Service) ExportEntries(library []tracker.TrackedMedia) ([]ExportEntry, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var records []database.History
+	if err := s.db.Order("watched_at ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch history: %w", err)
+	}
+	return buildExportEntries(records, library), nil
+}
+
+// buildExportEntries combines history records, oldest first, per title
+func buildExportEntries(records []database.History, library []tracker.TrackedMedia) []ExportEntry {
+	tracked := make(map[int]tracker.TrackedMedia, len(library))
+	for _, media := range library {
+		if id, err := strconv.Atoi(media.ServiceID); err == nil {
+			tracked[id] = media
+		}
+	}
+
+	var entries []*ExportEntry
+	byMedia := make(map[string]*ExportEntry)
+	watched := make(map[string]map[[2]int]bool)
+	for _, record := range records {
+		// The same AniList entry may have been watched on several providers
+		key := record.ProviderName + "\x00" + record.MediaID
+		if record.AniListID != nil {
+			key = fmt.Sprintf("anilist:%d", *record.AniListID)
+		}
+		entry, ok := byMedia[key]
+		if !ok {
+			entry = &ExportEntry{
+				MediaID:      record.MediaID,
+				Title:        record.MediaTitle,
+				MediaType:    record.MediaType,
+				ProviderName: record.ProviderName,
+				StartedAt:    record.WatchedAt,
+			}
+			byMedia[key] = entry
+			watched[key] = make(map[[2]int]bool)
+			entries = append(entries, entry)
+		}
+		if record.AniListID != nil {
+			entry.AniListID = *record.AniListID
+		}
+		entry.LastWatched = record.WatchedAt
+		if record.Completed {
+			watched[key][[2]int{record.Season, record.Episode}] = true
+		}
+	}
+
+	result := make([]ExportEntry, 0, len(entries))
+	for key, entry := range byMedia {
+		entry.Watched = len(watched[key])
+	}
+	for _, entry := range entries {
+		if media, ok := tracked[entry.AniListID]; ok && entry.AniListID != 0 {
+			entry.MALID = media.MALID
+			entry.TotalEpisodes = media.TotalEpisodes
+			entry.Score = media.ScoreTen
+			entry.Watched = max(entry.Watched, media.Progress)
+			if media.Status == tracker.StatusCompleted {
+				entry.Completed = true
+				entry.CompletedAt = media.EndDate
+			}
+		}
+		if !entry.Completed {
+			switch {
+			case entry.MediaType == "movie" && entry.Watched > 0:
+				entry.Completed = true
+			case entry.TotalEpisodes > 0 && entry.Watched >= entry.TotalEpisodes:
+				entry.Completed = true
+			}
+		}
+		if entry.Completed && entry.CompletedAt == nil {
+			last := entry.LastWatched
+			entry.CompletedAt = &last
+		}
+		result = append(result, *entry)
+	}
+	return result
+}
+
+// WriteCSV writes entries as CSV with a header row
+func WriteCSV(w io.Writer, entries []ExportEntry) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{
+		"title", "type", "provider", "anilist_id", "mal_id", "episodes_watched", "total_episodes",
+		"status", "started", "completed", "last_watched", "score",
+	}); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		status := "watching"
+		if entry.Completed {
+			status = "completed"
+		}
+		if err := out.Write([]string{
+			entry.Title,
+			entry.MediaType,
+			entry.ProviderName,
+			optionalInt(entry.AniListID),
+			optionalInt(entry.MALID),
+			strconv.Itoa(entry.Watched),
+			optionalInt(entry.TotalEpisodes),
+			status,
+			entry.StartedAt.Format(time.DateOnly),
+			optionalDate(entry.CompletedAt),
+			entry.LastWatched.Format(time.RFC3339),
+			optionalScore(entry.Score),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteLetterboxd writes the completed movies of entries in Letterboxd's
+// diary import format. Returns how many entries were left out.
+func WriteLetterboxd(w io.Writer, entries []ExportEntry) (int, error) {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"Title", "WatchedDate", "Rating"}); err != nil {
+		return 0, err
+	}
+
+	skipped := 0
+	for _, entry := range entries {
+		if entry.MediaType != "movie" || !entry.Completed {
+			skipped++
+			continue
+		}
+		rating := ""
+		if entry.Score > 0 {
+			// Letterboxd rates 0.5-5 in half stars
+			rating = strconv.FormatFloat(math.Max(0.5, math.Round(entry.Score)/2), 'f', 1, 64)
+		}
+		if err := out.Write([]string{entry.Title, entry.CompletedAt.Format(time.DateOnly), rating}); err != nil {
+			return skipped, err
+		}
+	}
+	out.Flush()
+	return skipped, out.Error()
+}
+
+// malExport is the MyAnimeList list export document
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	Info    malInfo    `xml:"myinfo"`
+	Anime   []malEntry `xml:"anime"`
+}
+
+type malInfo struct {
+	ExportType int `xml:"user_export_type"` // 1 is anime
+	Total      int `xml:"user_total_anime"`
+}
+
+type malEntry struct {
+	ID             int      `xml:"series_animedb_id"`
+	Title          malCDATA `xml:"series_title"`
+	Episodes       int      `xml:"series_episodes"`
+	Watched        int      `xml:"my_watched_episodes"`
+	StartDate      string   `xml:"my_start_date"`
+	FinishDate     string   `xml:"my_finish_date"`
+	Score          int      `xml:"my_score"`
+	Status         string   `xml:"my_status"`
+	UpdateOnImport int      `xml:"update_on_import"`
+}
+
+type malCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+// WriteMALXML writes the anime of entries in MyAnimeList's list import
+// format. Anime without a MyAnimeList ID can't be imported and are left
+// out; returns how many entries were.
+func WriteMALXML(w io.Writer, entries []ExportEntry) (int, error) {
+	doc := malExport{Info: malInfo{ExportType: 1}}
+	skipped := 0
+	for _, entry := range entries {
+		if entry.MediaType != "anime" || entry.MALID == 0 {
+			skipped++
+			continue
+		}
+		status := "Watching"
+		finish := "0000-00-00"
+		if entry.Completed {
+			status = "Completed"
+			finish = entry.CompletedAt.Format(time.DateOnly)
+		}
+		doc.Anime = append(doc.Anime, malEntry{
+			ID:             entry.MALID,
+			Title:          malCDATA{Text: entry.Title},
+			Episodes:       entry.TotalEpisodes,
+			Watched:        entry.Watched,
+			StartDate:      entry.StartedAt.Format(time.DateOnly),
+			FinishDate:     finish,
+			Score:          int(math.Round(entry.Score)),
+			Status:         status,
+			UpdateOnImport: 1,
+		})
+	}
+	sort.SliceStable(doc.Anime, func(i, j int) bool { return doc.Anime[i].Title.Text < doc.Anime[j].Title.Text })
+	doc.Info.Total = len(doc.Anime)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return skipped, err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(doc); err != nil {
+		return skipped, err
+	}
+	_, err := io.WriteString(w, "\n")
+	return skipped, err
+}
+
+// optionalInt formats n, or nothing when it is 0
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// optionalDate formats t as a date, or nothing when it is nil
+func optionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+// optionalScore formats a 0-10 score, or nothing when unscored
+func optionalScore(score float64) string {
+	if score == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
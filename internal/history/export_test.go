@@ -0,0 +1,86 @@
+package history
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+func exportFixture() []ExportEntry {
+	day := time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC)
+	frieren := 154587
+	finished := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	records := []database.History{
+		{MediaID: "anilist:154587", MediaTitle: "Frieren", MediaType: "anime", Episode: 1, Completed: true, AniListID: &frieren, ProviderName: "hianime", WatchedAt: day},
+		{MediaID: "anilist:154587", MediaTitle: "Frieren", MediaType: "anime", Episode: 2, Completed: true, AniListID: &frieren, ProviderName: "allanime", WatchedAt: day.Add(time.Hour)},
+		{MediaID: "dune", MediaTitle: "Dune, Part Two", MediaType: "movie", Completed: true, ProviderName: "flixhq", WatchedAt: day.Add(24 * time.Hour)},
+		{MediaID: "arrival", MediaTitle: "Arrival", MediaType: "movie", ProgressPercent: 30, ProviderName: "flixhq", WatchedAt: day.Add(48 * time.Hour)},
+		{MediaID: "severance", MediaTitle: "Severance", MediaType: "tv", Season: 1, Episode: 1, Completed: true, ProviderName: "flixhq", WatchedAt: day.Add(72 * time.Hour)},
+	}
+	library := []tracker.TrackedMedia{
+		{ServiceID: "154587", MALID: 52991, Title: "Frieren", TotalEpisodes: 28, Progress: 28, Status: tracker.StatusCompleted, ScoreTen: 9.5, EndDate: &finished},
+	}
+	return buildExportEntries(records, library)
+}
+
+func TestBuildExportEntries(t *testing.T) {
+	entries := exportFixture()
+	require.Len(t, entries, 4)
+
+	frieren := entries[0]
+	assert.Equal(t, "Frieren", frieren.Title)
+	assert.Equal(t, 52991, frieren.MALID)
+	assert.Equal(t, 28, frieren.Watched)
+	assert.True(t, frieren.Completed)
+	assert.Equal(t, "2025-03-20", frieren.CompletedAt.Format(time.DateOnly))
+	assert.Equal(t, 9.5, frieren.Score)
+
+	assert.True(t, entries[1].Completed, "a watched movie is completed")
+	assert.False(t, entries[2].Completed)
+	assert.Nil(t, entries[2].CompletedAt)
+	assert.False(t, entries[3].Completed, "a show with an unknown episode count stays in progress")
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, exportFixture()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, "title,type,provider,anilist_id,mal_id,episodes_watched,total_episodes,status,started,completed,last_watched,score", lines[0])
+	assert.Equal(t, "Frieren,anime,hianime,154587,52991,28,28,completed,2025-03-01,2025-03-20,2025-03-01T21:00:00Z,9.5", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], `"Dune, Part Two",movie,flixhq,,,1,,completed,`))
+}
+
+func TestWriteLetterboxd(t *testing.T) {
+	var buf bytes.Buffer
+	skipped, err := WriteLetterboxd(&buf, exportFixture())
+	require.NoError(t, err)
+	assert.Equal(t, 3, skipped)
+	assert.Equal(t, "Title,WatchedDate,Rating\n\"Dune, Part Two\",2025-03-02,\n", buf.String())
+}
+
+func TestWriteMALXML(t *testing.T) {
+	var buf bytes.Buffer
+	skipped, err := WriteMALXML(&buf, exportFixture())
+	require.NoError(t, err)
+	assert.Equal(t, 3, skipped)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, out, "<user_total_anime>1</user_total_anime>")
+	assert.Contains(t, out, "<series_animedb_id>52991</series_animedb_id>")
+	assert.Contains(t, out, "<series_title><![CDATA[Frieren]]></series_title>")
+	assert.Contains(t, out, "<my_watched_episodes>28</my_watched_episodes>")
+	assert.Contains(t, out, "<my_finish_date>2025-03-20</my_finish_date>")
+	assert.Contains(t, out, "<my_score>10</my_score>")
+	assert.Contains(t, out, "<my_status>Completed</my_status>")
+}
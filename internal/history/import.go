@@ -0,0 +1,248 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// ImportFormat is an export of another service that history can be
+// imported from
+type ImportFormat string
+
+const (
+	ImportMALXML ImportFormat = "mal_xml" // MyAnimeList list export, also written by AniList
+	ImportTrakt  ImportFormat = "trakt"   // Trakt watched history (JSON)
+)
+
+// Provider names imported history is recorded under
+const (
+	importMALProvider   = "myanimelist"
+	importTraktProvider = "trakt"
+)
+
+// ImportEntry is a title read from another service's export, with one
+// completed history record per episode watched
+type ImportEntry struct {
+	MALID     int // MyAnimeList ID, 0 for other sources
+	Title     string
+	MediaType string
+	Records   []database.History
+}
+
+// SetAniListID links the entry to an AniList entry, recording it the way
+// history of AniList titles is
+func (e *ImportEntry) SetAniListID(id int) {
+	for i := range e.Records {
+		anilistID := id
+		e.Records[i].MediaID = fmt.Sprintf("anilist:%d", id)
+		e.Records[i].AniListID = &anilistID
+	}
+}
+
+// DetectImportFormat tells a MyAnimeList XML export from a Trakt JSON one
+func DetectImportFormat(data []byte) (ImportFormat, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return ImportMALXML, nil
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return ImportTrakt, nil
+	default:
+		return "", errors.New("unrecognized export: expected MyAnimeList XML or Trakt JSON")
+	}
+}
+
+// malImport is the part of a MyAnimeList export that is imported
+type malImport struct {
+	Anime []struct {
+		ID         int    `xml:"series_animedb_id"`
+		Title      string `xml:"series_title"`
+		Watched    int    `xml:"my_watched_episodes"`
+		StartDate  string `xml:"my_start_date"`
+		FinishDate string `xml:"my_finish_date"`
+	} `xml:"anime"`
+	Manga []struct {
+		ID         int    `xml:"manga_mangadb_id"`
+		Title      string `xml:"manga_title"`
+		Read       int    `xml:"my_read_chapters"`
+		StartDate  string `xml:"my_start_date"`
+		FinishDate string `xml:"my_finish_date"`
+	} `xml:"manga"`
+}
+
+// ParseMALXML reads a MyAnimeList list export. Episodes are dated with the
+// finish date, else the start date, else now.
+func ParseMALXML(r io.Reader, now time.Time) ([]ImportEntry, error) {
+	var doc malImport
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse MyAnimeList export: %w", err)
+	}
+
+	var entries []ImportEntry
+	for _, anime := range doc.Anime {
+		if anime.ID == 0 || anime.Watched == 0 {
+			continue
+		}
+		entries = append(entries, malEntryRecords(anime.ID, anime.Title, "anime", anime.Watched,
+			malDate(anime.FinishDate, malDate(anime.StartDate, now))))
+	}
+	for _, manga := range doc.Manga {
+		if manga.ID == 0 || manga.Read == 0 {
+			continue
+		}
+		entries = append(entries, malEntryRecords(manga.ID, manga.Title, "manga", manga.Read,
+			malDate(manga.FinishDate, malDate(manga.StartDate, now))))
+	}
+	return entries, nil
+}
+
+// malEntryRecords builds the history of a MyAnimeList entry with watched
+// episodes (or chapters)
+func malEntryRecords(id int, title, mediaType string, watched int, at time.Time) ImportEntry {
+	title = strings.TrimSpace(title)
+	entry := ImportEntry{MALID: id, Title: title, MediaType: mediaType}
+	for episode := 1; episode <= watched; episode++ {
+		entry.Records = append(entry.Records, completedRecord(fmt.Sprintf("mal:%d", id), title, mediaType, importMALProvider, 0, episode, at))
+	}
+	return entry
+}
+
+// malDate parses a MyAnimeList date, returning fallback for the
+// 0000-00-00 placeholder
+func malDate(value string, fallback time.Time) time.Time {
+	t, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+	if err != nil {
+		return fallback
+	}
+	return t
+}
+
+// traktItem is an entry of a Trakt watched history export
+type traktItem struct {
+	WatchedAt time.Time `json:"watched_at"`
+	Type      string    `json:"type"` // movie or episode
+	Movie     *struct {
+		Title string   `json:"title"`
+		IDs   traktIDs `json:"ids"`
+	} `json:"movie"`
+	Show *struct {
+		Title string   `json:"title"`
+		IDs   traktIDs `json:"ids"`
+	} `json:"show"`
+	Episode *struct {
+		Season int `json:"season"`
+		Number int `json:"number"`
+	} `json:"episode"`
+}
+
+type traktIDs struct {
+	Trakt int    `json:"trakt"`
+	Slug  string `json:"slug"`
+}
+
+// key returns the media ID imported Trakt history is recorded under
+func (ids traktIDs) key(kind string) string {
+	if ids.Slug != "" {
+		return fmt.Sprintf("trakt:%s:%s", kind, ids.Slug)
+	}
+	return fmt.Sprintf("trakt:%s:%d", kind, ids.Trakt)
+}
+
+// ParseTraktJSON reads a Trakt watched history export
+func ParseTraktJSON(r io.Reader) ([]ImportEntry, error) {
+	var items []traktItem
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to parse Trakt export: %w", err)
+	}
+
+	var entries []*ImportEntry
+	byMedia := make(map[string]*ImportEntry)
+	for _, item := range items {
+		var mediaID, title, mediaType string
+		season, episode := 0, 0
+		switch {
+		case item.Type == "movie" && item.Movie != nil:
+			mediaID, title, mediaType = item.Movie.IDs.key("movie"), item.Movie.Title, "movie"
+		case item.Type == "episode" && item.Show != nil && item.Episode != nil:
+			mediaID, title, mediaType = item.Show.IDs.key("show"), item.Show.Title, "tv"
+			season, episode = item.Episode.Season, item.Episode.Number
+		default:
+			continue
+		}
+
+		entry, ok := byMedia[mediaID]
+		if !ok {
+			entry = &ImportEntry{Title: title, MediaType: mediaType}
+			byMedia[mediaID] = entry
+			entries = append(entries, entry)
+		}
+		entry.Records = append(entry.Records, completedRecord(mediaID, title, mediaType, importTraktProvider, season, episode, item.WatchedAt))
+	}
+
+	result := make([]ImportEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = *entry
+	}
+	return result, nil
+}
+
+// completedRecord is the history record of a finished episode
+func completedRecord(mediaID, title, mediaType, provider string, season, episode int, at time.Time) database.History {
+	return database.History{
+		MediaID:         mediaID,
+		MediaTitle:      title,
+		MediaType:       mediaType,
+		Season:          season,
+		Episode:         episode,
+		ProgressPercent: 100,
+		WatchedAt:       at,
+		Completed:       true,
+		ProviderName:    provider,
+	}
+}
+
+// Import adds the history of entries, skipping episodes already recorded as
+// completed so importing twice adds nothing. Returns how many records were
+// added and skipped.
+func (s *Service) Import(entries []ImportEntry) (int, int, error) {
+	if s.db == nil {
+		return 0, 0, fmt.Errorf("database connection is nil")
+	}
+
+	added, skipped := 0, 0
+	err := database.Write(s.db, func(tx *gorm.DB) error {
+		for _, entry := range entries {
+			for _, record := range entry.Records {
+				var count int64
+				if err := tx.Model(&database.History{}).
+					Where("media_id = ? AND season = ? AND episode = ? AND completed = true", record.MediaID, record.Season, record.Episode).
+					Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					skipped++
+					continue
+				}
+				if err := tx.Create(&record).Error; err != nil {
+					return err
+				}
+				added++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to import history: %w", err)
+	}
+	return added, skipped, nil
+}
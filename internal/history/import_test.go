@@ -0,0 +1,121 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+const malExportFixture = `<?xml version="1.0" encoding="UTF-8" ?>
+<myanimelist>
+	<myinfo><user_export_type>1</user_export_type></myinfo>
+	<anime>
+		<series_animedb_id>52991</series_animedb_id>
+		<series_title><![CDATA[Sousou no Frieren]]></series_title>
+		<my_watched_episodes>3</my_watched_episodes>
+		<my_start_date>2025-03-01</my_start_date>
+		<my_finish_date>0000-00-00</my_finish_date>
+	</anime>
+	<anime>
+		<series_animedb_id>5114</series_animedb_id>
+		<series_title><![CDATA[Fullmetal Alchemist: Brotherhood]]></series_title>
+		<my_watched_episodes>0</my_watched_episodes>
+	</anime>
+	<manga>
+		<manga_mangadb_id>2</manga_mangadb_id>
+		<manga_title><![CDATA[Berserk]]></manga_title>
+		<my_read_chapters>2</my_read_chapters>
+		<my_finish_date>2024-12-24</my_finish_date>
+	</manga>
+</myanimelist>`
+
+const traktExportFixture = `[
+	{"id": 1, "watched_at": "2025-01-05T21:00:00.000Z", "action": "watch", "type": "episode",
+	 "episode": {"season": 1, "number": 1, "title": "Good News About Hell"},
+	 "show": {"title": "Severance", "year": 2022, "ids": {"trakt": 154997, "slug": "severance"}}},
+	{"id": 2, "watched_at": "2025-01-06T21:00:00.000Z", "action": "watch", "type": "episode",
+	 "episode": {"season": 1, "number": 2, "title": "Half Loop"},
+	 "show": {"title": "Severance", "year": 2022, "ids": {"trakt": 154997, "slug": "severance"}}},
+	{"id": 3, "watched_at": "2025-01-07T20:00:00.000Z", "action": "watch", "type": "movie",
+	 "movie": {"title": "Dune: Part Two", "year": 2024, "ids": {"trakt": 537130}}}
+]`
+
+func TestDetectImportFormat(t *testing.T) {
+	format, err := DetectImportFormat([]byte(malExportFixture))
+	require.NoError(t, err)
+	assert.Equal(t, ImportMALXML, format)
+
+	format, err = DetectImportFormat([]byte("\n" + traktExportFixture))
+	require.NoError(t, err)
+	assert.Equal(t, ImportTrakt, format)
+
+	_, err = DetectImportFormat([]byte("title,episode"))
+	assert.Error(t, err)
+}
+
+func TestParseMALXML(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries, err := ParseMALXML(strings.NewReader(malExportFixture), now)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	frieren := entries[0]
+	assert.Equal(t, 52991, frieren.MALID)
+	require.Len(t, frieren.Records, 3)
+	assert.Equal(t, "mal:52991", frieren.Records[2].MediaID)
+	assert.Equal(t, 3, frieren.Records[2].Episode)
+	assert.True(t, frieren.Records[2].Completed)
+	assert.Equal(t, "2025-03-01", frieren.Records[0].WatchedAt.Format(time.DateOnly))
+
+	berserk := entries[1]
+	assert.Equal(t, "manga", berserk.MediaType)
+	require.Len(t, berserk.Records, 2)
+	assert.Equal(t, "2024-12-24", berserk.Records[0].WatchedAt.Format(time.DateOnly))
+
+	frieren.SetAniListID(154587)
+	assert.Equal(t, "anilist:154587", frieren.Records[0].MediaID)
+	require.NotNil(t, frieren.Records[0].AniListID)
+	assert.Equal(t, 154587, *frieren.Records[0].AniListID)
+}
+
+func TestParseTraktJSON(t *testing.T) {
+	entries, err := ParseTraktJSON(strings.NewReader(traktExportFixture))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "Severance", entries[0].Title)
+	assert.Equal(t, "tv", entries[0].MediaType)
+	require.Len(t, entries[0].Records, 2)
+	assert.Equal(t, "trakt:show:severance", entries[0].Records[1].MediaID)
+	assert.Equal(t, 2, entries[0].Records[1].Episode)
+
+	assert.Equal(t, "movie", entries[1].MediaType)
+	assert.Equal(t, "trakt:movie:537130", entries[1].Records[0].MediaID)
+}
+
+func TestImport(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+	svc := NewService(db)
+
+	entries, err := ParseTraktJSON(strings.NewReader(traktExportFixture))
+	require.NoError(t, err)
+
+	added, skipped, err := svc.Import(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 3, added)
+	assert.Zero(t, skipped)
+
+	added, skipped, err = svc.Import(entries)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+	assert.Equal(t, 3, skipped)
+}
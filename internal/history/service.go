@@ -92,19 +92,21 @@ func (s *Service) AddOrUpdate(history database.History) error {
 			existing.WatchedAt = time.Now()
 			existing.ProviderName = history.ProviderName
 
-			return s.db.Save(&existing).Error
+			return database.Write(s.db, func(tx *gorm.DB) error { return tx.Save(&existing).Error })
 		}
 	}
 
-	// If this is a completed watch, delete any previous incomplete records for this media/episode
-	if history.Completed {
-		s.db.Where("media_id = ? AND episode = ? AND completed = false", history.MediaID, history.Episode).
-			Delete(&database.History{})
-	}
+	return database.Write(s.db, func(tx *gorm.DB) error {
+		// If this is a completed watch, delete any previous incomplete records for this media/episode
+		if history.Completed {
+			tx.Where("media_id = ? AND episode = ? AND completed = false", history.MediaID, history.Episode).
+				Delete(&database.History{})
+		}
 
-	// Create new record
-	history.WatchedAt = time.Now()
-	return s.db.Create(&history).Error
+		// Create new record
+		history.WatchedAt = time.Now()
+		return tx.Create(&history).Error
+	})
 }
 
 // GetHistory retrieves history items with filtering and sorting
@@ -242,6 +244,30 @@ func (s *Service) DeleteByMediaID(mediaID string) error {
 	return s.db.Where("media_id = ?", mediaID).Delete(&database.History{}).Error
 }
 
+// LastWatchedByAniList returns when each AniList media was last watched
+func (s *Service) LastWatchedByAniList() (map[int]time.Time, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var rows []database.History
+	err := s.db.Select("anilist_id", "watched_at").
+		Where("anilist_id IS NOT NULL").
+		Order("watched_at DESC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lastWatched := make(map[int]time.Time)
+	for _, row := range rows {
+		if _, ok := lastWatched[*row.AniListID]; !ok {
+			lastWatched[*row.AniListID] = row.WatchedAt
+		}
+	}
+	return lastWatched, nil
+}
+
 // MarkAsCompleted marks a history item as completed
 func (s *Service) MarkAsCompleted(id uint) error {
 	if s.db == nil {
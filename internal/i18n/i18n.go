@@ -0,0 +1,175 @@
+// Package i18n translates user-facing TUI strings.
+//
+// Messages are looked up by their English text, so untranslated strings fall
+// back to English as written in the code. A catalog is a JSON object mapping
+// English format strings to translations. Built-in catalogs live in
+// locales/; users can add or override translations with
+// <config dir>/locales/<locale>.json.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+var (
+	mu      sync.RWMutex
+	current = map[string]string{}
+	locale  = "en"
+)
+
+// Init selects the locale used by T. An empty locale is detected from the
+// environment. Translations are merged from the built-in catalog and from
+// userDir/locales, the more specific locale ("pt-BR") overriding its base
+// language ("pt"). Locales without a catalog fall back to English.
+func Init(requested, userDir string) error {
+	tag := Normalize(requested)
+	if tag == "" {
+		tag = Detect()
+	}
+
+	messages := map[string]string{}
+	var errs []string
+	for _, candidate := range candidates(tag) {
+		if err := mergeBuiltin(messages, candidate); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if userDir != "" {
+			if err := mergeFile(messages, filepath.Join(userDir, "locales", candidate+".json")); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	mu.Lock()
+	current = messages
+	locale = tag
+	mu.Unlock()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to load translations: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Locale returns the active locale tag
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T translates an English format string and formats it with args
+func T(msg string, args ...interface{}) string {
+	mu.RLock()
+	translated, ok := current[msg]
+	mu.RUnlock()
+	if !ok || translated == "" {
+		translated = msg
+	}
+
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
+
+// Detect returns the locale from LC_ALL, LC_MESSAGES or LANG, or "en"
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if tag := Normalize(os.Getenv(env)); tag != "" {
+			return tag
+		}
+	}
+	return "en"
+}
+
+// Normalize turns POSIX locale names into tags: "pt_BR.UTF-8" becomes
+// "pt-BR". "C" and "POSIX" mean English.
+func Normalize(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return ""
+	}
+	if name == "C" || name == "POSIX" {
+		return "en"
+	}
+
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	tag := strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		tag += "-" + strings.ToUpper(parts[1])
+	}
+	return tag
+}
+
+// Available lists the locales with a built-in catalog
+func Available() []string {
+	entries, err := builtin.ReadDir("locales")
+	if err != nil {
+		return nil
+	}
+
+	tags := []string{"en"}
+	for _, entry := range entries {
+		tag := strings.TrimSuffix(entry.Name(), ".json")
+		if tag != "en" {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags[1:])
+	return tags
+}
+
+// candidates returns the catalogs to merge for tag, least specific first
+func candidates(tag string) []string {
+	if base, _, ok := strings.Cut(tag, "-"); ok {
+		return []string{base, tag}
+	}
+	return []string{tag}
+}
+
+// mergeBuiltin merges the embedded catalog for tag, if there is one
+func mergeBuiltin(messages map[string]string, tag string) error {
+	data, err := builtin.ReadFile("locales/" + tag + ".json")
+	if err != nil {
+		return nil
+	}
+	return merge(messages, data, tag)
+}
+
+// mergeFile merges a user catalog, if it exists
+func mergeFile(messages map[string]string, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return merge(messages, data, path)
+}
+
+// merge decodes a catalog into messages
+func merge(messages map[string]string, data []byte, source string) error {
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("invalid catalog %s: %w", source, err)
+	}
+	for msg, translated := range catalog {
+		messages[msg] = translated
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package i18n
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "pt-BR", Normalize("pt_BR.UTF-8"))
+	assert.Equal(t, "de-DE", Normalize("de_DE@euro"))
+	assert.Equal(t, "es", Normalize("ES"))
+	assert.Equal(t, "en", Normalize("C"))
+	assert.Equal(t, "", Normalize(""))
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "fr_FR.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "fr-FR", Detect())
+
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	assert.Equal(t, "en", Detect())
+}
+
+func TestTranslateWithFallback(t *testing.T) {
+	t.Cleanup(func() { _ = Init("en", "") })
+
+	require.NoError(t, Init("es_MX.UTF-8", ""))
+	assert.Equal(t, "es-MX", Locale())
+	assert.Equal(t, "Buscando...", T("Searching..."))
+	assert.Equal(t, "hace 3 días", T("%d days ago", 3))
+	// Untranslated strings stay English
+	assert.Equal(t, "Not in any catalog 2", T("Not in any catalog %d", 2))
+
+	require.NoError(t, Init("ja", ""))
+	assert.Equal(t, "Searching...", T("Searching..."))
+}
+
+func TestUserCatalogOverridesBuiltin(t *testing.T) {
+	t.Cleanup(func() { _ = Init("en", "") })
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "locales"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "locales", "es-AR.json"),
+		[]byte(`{"Searching...": "Buscando, che..."}`), 0644))
+
+	require.NoError(t, Init("es-AR", dir))
+	assert.Equal(t, "Buscando, che...", T("Searching..."))
+	assert.Equal(t, "Cargando...", T("Loading..."))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "locales", "es-AR.json"), []byte(`{`), 0644))
+	assert.Error(t, Init("es-AR", dir))
+}
+
+// formatVerb matches fmt verbs, ignoring escaped percent signs
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func verbs(s string) []string {
+	var found []string
+	for _, verb := range formatVerb.FindAllString(s, -1) {
+		if verb != "%%" {
+			found = append(found, verb)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+func TestBuiltinCatalogsKeepFormatVerbs(t *testing.T) {
+	for _, tag := range Available()[1:] {
+		data, err := builtin.ReadFile("locales/" + tag + ".json")
+		require.NoError(t, err)
+
+		var catalog map[string]string
+		require.NoError(t, json.Unmarshal(data, &catalog), tag)
+		for msg, translated := range catalog {
+			assert.Equal(t, verbs(msg), verbs(translated), "%s: %q", tag, msg)
+		}
+	}
+}
+
+// sourceMessages collects the string literals passed to i18n.T in the
+// repository's non-test Go files
+func sourceMessages(t *testing.T, root string) map[string]string {
+	t.Helper()
+	messages := map[string]string{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "T" {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if msg, err := strconv.Unquote(lit.Value); err == nil {
+					messages[msg] = fset.Position(lit.Pos()).String()
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	return messages
+}
+
+func TestBuiltinCatalogsCoverSource(t *testing.T) {
+	messages := sourceMessages(t, filepath.Join("..", ".."))
+	require.NotEmpty(t, messages)
+
+	for _, tag := range Available()[1:] {
+		data, err := builtin.ReadFile("locales/" + tag + ".json")
+		require.NoError(t, err)
+
+		var catalog map[string]string
+		require.NoError(t, json.Unmarshal(data, &catalog), tag)
+		for msg, pos := range messages {
+			assert.Contains(t, catalog, msg, "%s: untranslated message at %s", tag, pos)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Package imagecache is a content-addressed on-disk cache for poster and
+// manga page images, shared by the TUI components that display them
+package imagecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	prefetchWorkers = 4
+	prefetchQueue   = 64
+	// pruneEvery is how many stored images trigger a background prune
+	pruneEvery = 32
+)
+
+// Cache stores images under objects/ by the SHA-256 of their content, with
+// refs/ mapping the SHA-256 of each URL to its object. Identical images
+// served from different URLs are stored once.
+type Cache struct {
+	dir     string
+	maxSize int64
+	ttl     time.Duration
+	client  *http.Client
+
+	mu       sync.Mutex
+	inflight map[string]*fetch
+	stored   int
+
+	queue     chan string
+	startOnce sync.Once
+}
+
+// fetch is a download in progress that concurrent callers wait on
+type fetch struct {
+	done chan struct{}
+	path string
+	err  error
+}
+
+// New opens the cache in dir. maxSize is the size cap in bytes and ttl how
+// long an image stays fresh; zero disables either limit.
+func New(dir string, maxSize int64, ttl time.Duration) (*Cache, error) {
+	for _, sub := range []string{"objects", "refs"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create image cache directory: %w", err)
+		}
+	}
+
+	return &Cache{
+		dir:      dir,
+		maxSize:  maxSize,
+		ttl:      ttl,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		inflight: make(map[string]*fetch),
+		queue:    make(chan string, prefetchQueue),
+	}, nil
+}
+
+func hashString(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) refPath(url string) string {
+	return filepath.Join(c.dir, "refs", hashString([]byte(url)))
+}
+
+func (c *Cache) objectPath(sum string) string {
+	return filepath.Join(c.dir, "objects", sum[:2], sum)
+}
+
+// Lookup returns the path of a fresh cached image for url without fetching it
+func (c *Cache) Lookup(url string) (string, bool) {
+	ref := c.refPath(url)
+	info, err := os.Stat(ref)
+	if err != nil {
+		return "", false
+	}
+	if c.ttl > 0 && time.Since(info.ModTime()) > c.ttl {
+		return "", false
+	}
+
+	sum, err := os.ReadFile(ref)
+	if err != nil || len(sum) < 2 {
+		return "", false
+	}
+	path := c.objectPath(string(sum))
+	if _, err := os.Stat(path); err != nil {
+		// Object was evicted, drop the dangling ref
+		_ = os.Remove(ref)
+		return "", false
+	}
+
+	// Mark as recently used for eviction
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return path, true
+}
+
+// Get returns the path of the cached image for url, downloading it first if
+// needed. Concurrent requests for the same URL share one download.
+func (c *Cache) Get(ctx context.Context, url string) (string, error) {
+	if path, ok := c.Lookup(url); ok {
+		return path, nil
+	}
+
+	c.mu.Lock()
+	if f, ok := c.inflight[url]; ok {
+		c.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.path, f.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	f := &fetch{done: make(chan struct{})}
+	c.inflight[url] = f
+	c.mu.Unlock()
+
+	f.path, f.err = c.download(ctx, url)
+
+	c.mu.Lock()
+	delete(c.inflight, url)
+	c.mu.Unlock()
+	close(f.done)
+
+	return f.path, f.err
+}
+
+// download fetches url and stores it as an object
+func (c *Cache) download(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image request returned status %d", resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hasher), resp.Body)
+	_ = tmp.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to save image: %w", err)
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	path := c.objectPath(sum)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create object directory: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			return "", fmt.Errorf("failed to store image: %w", err)
+		}
+	} else {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+	}
+
+	if err := os.WriteFile(c.refPath(url), []byte(sum), 0644); err != nil {
+		return "", fmt.Errorf("failed to write image ref: %w", err)
+	}
+
+	c.mu.Lock()
+	c.stored++
+	prune := c.maxSize > 0 && c.stored%pruneEvery == 0
+	c.mu.Unlock()
+	if prune {
+		go func() { _ = c.Prune() }()
+	}
+
+	return path, nil
+}
+
+// Prefetch queues urls for download in the background. URLs that are already
+// cached are skipped, and URLs are dropped when the queue is full.
+func (c *Cache) Prefetch(urls ...string) {
+	c.startOnce.Do(func() {
+		for i := 0; i < prefetchWorkers; i++ {
+			go c.worker()
+		}
+	})
+
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		if _, ok := c.Lookup(url); ok {
+			continue
+		}
+		select {
+		case c.queue <- url:
+		default:
+		}
+	}
+}
+
+func (c *Cache) worker() {
+	for url := range c.queue {
+		_, _ = c.Get(context.Background(), url)
+	}
+}
+
+type object struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Prune removes expired refs, objects nothing refers to and, when over the
+// size cap, the least recently used objects
+func (c *Cache) Prune() error {
+	referenced := make(map[string]bool)
+	refs, err := os.ReadDir(filepath.Join(c.dir, "refs"))
+	if err != nil {
+		return fmt.Errorf("failed to read image refs: %w", err)
+	}
+	for _, entry := range refs {
+		ref := filepath.Join(c.dir, "refs", entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if c.ttl > 0 && time.Since(info.ModTime()) > c.ttl {
+			_ = os.Remove(ref)
+			continue
+		}
+		if sum, err := os.ReadFile(ref); err == nil {
+			referenced[strings.TrimSpace(string(sum))] = true
+		}
+	}
+
+	var objects []object
+	var total int64
+	err = filepath.WalkDir(filepath.Join(c.dir, "objects"), func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !referenced[d.Name()] {
+			_ = os.Remove(path)
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		objects = append(objects, object{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan image cache: %w", err)
+	}
+
+	if c.maxSize <= 0 || total <= c.maxSize {
+		return nil
+	}
+
+	// Evict least recently used first; their refs are dropped on next lookup
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].modTime.Before(objects[j].modTime)
+	})
+	for _, obj := range objects {
+		if total <= c.maxSize {
+			break
+		}
+		if err := os.Remove(obj.path); err == nil {
+			total -= obj.size
+		}
+	}
+	return nil
+}
+
+// Clear removes every cached image
+func (c *Cache) Clear() error {
+	for _, sub := range []string{"objects", "refs"} {
+		if err := os.RemoveAll(filepath.Join(c.dir, sub)); err != nil {
+			return fmt.Errorf("failed to clear image cache: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Join(c.dir, sub), 0755); err != nil {
+			return fmt.Errorf("failed to create image cache directory: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package imagecache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		switch r.URL.Path {
+		case "/a.jpg", "/mirror/a.jpg":
+			_, _ = w.Write([]byte("image-a"))
+		case "/big.jpg":
+			_, _ = w.Write([]byte(strings.Repeat("b", 64)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetCachesAndDeduplicatesContent(t *testing.T) {
+	var hits int32
+	server := newTestServer(t, &hits)
+	c, err := New(t.TempDir(), 0, time.Hour)
+	require.NoError(t, err)
+
+	path, err := c.Get(context.Background(), server.URL+"/a.jpg")
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "image-a", string(data))
+
+	again, err := c.Get(context.Background(), server.URL+"/a.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, path, again)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	// Same bytes from another URL share the object
+	mirror, err := c.Get(context.Background(), server.URL+"/mirror/a.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, path, mirror)
+}
+
+func TestGetFailsOnBadStatus(t *testing.T) {
+	var hits int32
+	server := newTestServer(t, &hits)
+	c, err := New(t.TempDir(), 0, time.Hour)
+	require.NoError(t, err)
+
+	_, err = c.Get(context.Background(), server.URL+"/missing.jpg")
+	assert.Error(t, err)
+	_, ok := c.Lookup(server.URL + "/missing.jpg")
+	assert.False(t, ok)
+}
+
+func TestPruneEnforcesSizeCap(t *testing.T) {
+	var hits int32
+	server := newTestServer(t, &hits)
+	c, err := New(t.TempDir(), 70, time.Hour)
+	require.NoError(t, err)
+
+	small, err := c.Get(context.Background(), server.URL+"/a.jpg")
+	require.NoError(t, err)
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(small, old, old))
+	_, err = c.Get(context.Background(), server.URL+"/big.jpg")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Prune())
+
+	// Evicting the least recently used image brings the cache under the cap
+	_, ok := c.Lookup(server.URL + "/a.jpg")
+	assert.False(t, ok)
+	_, ok = c.Lookup(server.URL + "/big.jpg")
+	assert.True(t, ok)
+}
+
+func TestPruneDropsExpiredRefs(t *testing.T) {
+	var hits int32
+	server := newTestServer(t, &hits)
+	c, err := New(t.TempDir(), 0, time.Hour)
+	require.NoError(t, err)
+
+	path, err := c.Get(context.Background(), server.URL+"/a.jpg")
+	require.NoError(t, err)
+	expired := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(c.refPath(server.URL+"/a.jpg"), expired, expired))
+
+	require.NoError(t, c.Prune())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,246 @@
+// Package incoming imports video files other programs, such as torrent
+// clients, drop into a folder: they are matched to a show, renamed with the
+// filename templates and moved into the download library.
+package incoming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/feeds"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/releases"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+// SettleTime is how long a file must go unmodified before it's imported, so
+// files still being written are left alone
+const SettleTime = 30 * time.Second
+
+var (
+	// videoExtensions are the files picked up, anything else is ignored
+	videoExtensions = map[string]bool{".mkv": true, ".mp4": true, ".m4v": true, ".avi": true, ".webm": true}
+	// seasonTag finds the season of "Show S02E05"
+	seasonTag = regexp.MustCompile(`(?i)(?:^|[\s._])S(\d{1,2})E\d{1,4}`)
+)
+
+// Result is the outcome of importing one file
+type Result struct {
+	Path string
+	Task downloader.DownloadTask // Where the file went, when imported
+	Err  error                   // Why it wasn't imported, nil on success
+}
+
+// ErrNoMatch is returned for files that match no known show
+var ErrNoMatch = errors.New("no matching show")
+
+// Watcher imports the files of an incoming folder
+type Watcher struct {
+	dir      string
+	manager  *downloader.Manager
+	mappings *mapping.Manager
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	reported map[string]time.Time // Files left in place, by modification time, so they are reported once
+}
+
+// New creates a watcher of dir. mappings may be nil, then files are only
+// matched to shows downloaded before.
+func New(dir string, manager *downloader.Manager, mappings *mapping.Manager, logger *slog.Logger) *Watcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Watcher{
+		dir:      filepath.Clean(dir),
+		manager:  manager,
+		mappings: mappings,
+		logger:   logger,
+		reported: make(map[string]time.Time),
+	}
+}
+
+// Run scans the folder every interval until ctx is done
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, r := range w.Scan(ctx) {
+			if r.Err != nil {
+				w.logger.Warn("failed to import file", "path", r.Path, "error", r.Err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan imports the video files of the folder that settled. Files that
+// failed before and haven't changed since aren't reported again.
+func (w *Watcher) Scan(ctx context.Context) []Result {
+	var results []Result
+	now := time.Now()
+	err := filepath.WalkDir(w.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == w.dir {
+				return err
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != w.dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !videoExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || now.Sub(info.ModTime()) < SettleTime {
+			return nil
+		}
+
+		w.mu.Lock()
+		seen, ok := w.reported[path]
+		w.mu.Unlock()
+		if ok && seen.Equal(info.ModTime()) {
+			return nil
+		}
+
+		task, err := w.Import(ctx, path)
+		if err != nil {
+			w.mu.Lock()
+			w.reported[path] = info.ModTime()
+			w.mu.Unlock()
+		}
+		results = append(results, Result{Path: path, Task: task, Err: err})
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		results = append(results, Result{Path: w.dir, Err: fmt.Errorf("failed to scan folder: %w", err)})
+	}
+	return results
+}
+
+// Import matches one file to a show and moves it into the library
+func (w *Watcher) Import(ctx context.Context, path string) (downloader.DownloadTask, error) {
+	task, err := w.Match(ctx, filepath.Base(path))
+	if err != nil {
+		return task, err
+	}
+	task, err = w.manager.ImportFile(ctx, path, task, downloader.ImportMove)
+	if err != nil {
+		return task, err
+	}
+	w.removeEmptyParents(filepath.Dir(path))
+	return task, nil
+}
+
+// Match builds the task a file would be imported as: the show from the
+// saved mappings or an earlier download of the same title, the episode,
+// season and quality from the name
+func (w *Watcher) Match(ctx context.Context, name string) (downloader.DownloadTask, error) {
+	show, episode, season, quality := ParseName(name)
+	task := downloader.DownloadTask{
+		MediaTitle: show,
+		Episode:    episode,
+		Season:     season,
+		Quality:    quality,
+	}
+	if show == "" {
+		return task, ErrNoMatch
+	}
+
+	if w.mappings != nil {
+		m, err := w.mappings.MatchTitle(ctx, show)
+		if err != nil {
+			return task, err
+		}
+		if m != nil {
+			task.MediaID = m.ProviderMediaID
+			task.Provider = m.ProviderName
+			task.MediaTitle = m.Media.Title
+			task.MediaType = m.Media.Type
+		}
+	}
+	if task.MediaID == "" {
+		series, err := w.manager.FindSeries(ctx, show)
+		if err != nil {
+			return task, err
+		}
+		if series == nil {
+			return task, fmt.Errorf("%w: %q", ErrNoMatch, show)
+		}
+		task.MediaID = series.MediaID
+		task.Provider = series.Provider
+		task.MediaTitle = series.MediaTitle
+		task.MediaType = series.MediaType
+	}
+
+	switch task.MediaType {
+	case "":
+		// Saved mappings are AniList entries
+		task.MediaType = providers.MediaTypeAnime
+	case providers.MediaTypeMovieTV:
+		task.MediaType = providers.MediaTypeTV
+		if episode == 0 {
+			task.MediaType = providers.MediaTypeMovie
+		}
+	}
+	if task.Episode == 0 && task.MediaType != providers.MediaTypeMovie {
+		return task, fmt.Errorf("no episode number in %q", name)
+	}
+	return task, nil
+}
+
+// ParseName reads the show, episode, season and quality of a release file
+// name. Scene names with dots or underscores for spaces are read too.
+func ParseName(name string) (show string, episode, season int, quality providers.Quality) {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if !strings.Contains(base, " ") {
+		base = strings.NewReplacer(".", " ", "_", " ").Replace(base)
+	}
+
+	show, episode = feeds.ParseReleaseTitle(base)
+	if m := seasonTag.FindStringSubmatch(base); m != nil {
+		season, _ = strconv.Atoi(m[1])
+	}
+	quality = providers.QualityAuto
+	if res := releases.Parse(name).Resolution; res != "" {
+		quality = providers.Quality(res)
+	}
+	return show, episode, season, quality
+}
+
+// removeEmptyParents removes the folders a torrent created once importing
+// left them empty
+func (w *Watcher) removeEmptyParents(dir string) {
+	for dir != w.dir && strings.HasPrefix(dir, w.dir+string(filepath.Separator)) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,166 @@
+package incoming
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/torrentclient"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		show    string
+		episode int
+		season  int
+		quality providers.Quality
+	}{
+		{"[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv", "Sousou no Frieren", 5, 0, providers.Quality1080p},
+		{"Severance.S02E03.720p.WEB.x264-GROUP.mkv", "Severance", 3, 2, providers.Quality720p},
+		{"Dandadan_EP12.mp4", "Dandadan", 12, 0, providers.QualityAuto},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			show, episode, season, quality := ParseName(tt.name)
+			assert.Equal(t, tt.show, show)
+			assert.Equal(t, tt.episode, episode)
+			assert.Equal(t, tt.season, season)
+			assert.Equal(t, tt.quality, quality)
+		})
+	}
+}
+
+func TestScanImportsMatchedFiles(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+	require.NoError(t, db.Create(&database.AniListMapping{
+		AniListID: 154587, ProviderName: "test", ProviderMediaID: "frieren", Title: "Sousou no Frieren",
+	}).Error)
+
+	library := t.TempDir()
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	drop := func(rel string) string {
+		path := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("video"), 0644))
+		require.NoError(t, os.Chtimes(path, old, old))
+		return path
+	}
+	drop("[SubsPlease] Sousou no Frieren - 05 (1080p)/[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv")
+	unknown := drop("Some Other Show - 01.mkv")
+	drop("notes.txt")
+	fresh := filepath.Join(dir, "[SubsPlease] Sousou no Frieren - 06 (1080p).mkv")
+	require.NoError(t, os.WriteFile(fresh, []byte("vid"), 0644)) // Still being written
+
+	manager, err := downloader.NewManager(db, &config.DownloadsConfig{
+		Path: library, Concurrent: 1, AnimeFilenameTemplate: "{title} - {episode:03d} [{quality}]",
+	}, slog.Default())
+	require.NoError(t, err)
+	watcher := New(dir, manager, mapping.NewManager(db, "", slog.Default()), slog.Default())
+
+	results := watcher.Scan(context.Background())
+	require.Len(t, results, 2)
+	byPath := map[string]Result{}
+	for _, r := range results {
+		byPath[filepath.Base(r.Path)] = r
+	}
+
+	imported := byPath["[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv"]
+	require.NoError(t, imported.Err)
+	want := filepath.Join(library, "anime", "Sousou no Frieren", "Sousou no Frieren - 005 [1080p].mkv")
+	assert.Equal(t, want, imported.Task.OutputPath)
+	assert.FileExists(t, want)
+	assert.NoDirExists(t, filepath.Join(dir, "[SubsPlease] Sousou no Frieren - 05 (1080p)"), "emptied torrent folder is removed")
+
+	var download database.Download
+	require.NoError(t, db.First(&download, "file_path = ?", want).Error)
+	assert.Equal(t, "frieren", download.MediaID)
+	assert.Equal(t, "test", download.Provider)
+	assert.Equal(t, string(downloader.StatusCompleted), download.Status)
+	assert.Equal(t, 5, download.Episode)
+
+	assert.True(t, errors.Is(byPath[filepath.Base(unknown)].Err, ErrNoMatch))
+	assert.FileExists(t, unknown)
+	assert.FileExists(t, fresh)
+
+	// Files left in place aren't reported again until they change
+	assert.Empty(t, watcher.Scan(context.Background()))
+
+	// The same episode dropped again is a duplicate and stays
+	again := drop("Sousou no Frieren - 05 [1080p].mkv")
+	results = watcher.Scan(context.Background())
+	require.Len(t, results, 1)
+	var dup *downloader.DuplicateError
+	assert.True(t, errors.As(results[0].Err, &dup))
+	assert.FileExists(t, again)
+}
+
+// fakeClient is a torrent client whose torrents are all finished
+type fakeClient struct {
+	torrents []torrentclient.Torrent
+}
+
+func (c *fakeClient) Name() string { return torrentclient.QBittorrent }
+
+func (c *fakeClient) Add(ctx context.Context, link string) (string, error) { return "", nil }
+
+func (c *fakeClient) Torrents(ctx context.Context, hashes []string) ([]torrentclient.Torrent, error) {
+	return c.torrents, nil
+}
+
+func TestCheckTorrentsImportsFinishedTorrents(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	library := t.TempDir()
+	seeding := t.TempDir()
+	video := filepath.Join(seeding, "[SubsPlease] Kaiju No 8 - 03 (1080p).mkv")
+	require.NoError(t, os.WriteFile(video, []byte("video"), 0644))
+	require.NoError(t, database.AddTorrentJob(db, &database.TorrentJob{
+		Client: torrentclient.QBittorrent, Hash: "done", Link: "magnet:?xt=urn:btih:done", Show: "Kaiju No. 8", Episode: 3,
+	}))
+	require.NoError(t, database.AddTorrentJob(db, &database.TorrentJob{
+		Client: torrentclient.QBittorrent, Hash: "active", Link: "magnet:?xt=urn:btih:active", Show: "Kaiju No. 8", Episode: 4,
+	}))
+	client := &fakeClient{torrents: []torrentclient.Torrent{
+		{Hash: "done", Done: true, Progress: 1, Files: []string{video, filepath.Join(seeding, "info.nfo")}},
+		{Hash: "active", Progress: 0.4},
+	}}
+
+	manager, err := downloader.NewManager(db, &config.DownloadsConfig{
+		Path: library, Concurrent: 1, AnimeFilenameTemplate: "{title} - {episode:03d}",
+	}, slog.Default())
+	require.NoError(t, err)
+	watcher := New("", manager, mapping.NewManager(db, "", slog.Default()), slog.Default())
+
+	results, err := watcher.CheckTorrents(context.Background(), db, client)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, filepath.Join(library, "anime", "Kaiju No. 8", "Kaiju No. 8 - 003.mkv"), results[0].Task.OutputPath)
+	assert.FileExists(t, results[0].Task.OutputPath)
+	assert.FileExists(t, video, "the client keeps seeding the original")
+
+	jobs, err := database.ActiveTorrentJobs(db)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "active", jobs[0].Hash)
+}
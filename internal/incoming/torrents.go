@@ -0,0 +1,143 @@
+package incoming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/torrentclient"
+)
+
+// TrackTorrents imports the torrents handed to client as they finish,
+// checking every interval until ctx is done
+func (w *Watcher) TrackTorrents(ctx context.Context, db *gorm.DB, client torrentclient.Client, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := w.CheckTorrents(ctx, db, client)
+		if err != nil {
+			w.logger.Warn("failed to check torrents", "client", client.Name(), "error", err)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				w.logger.Warn("failed to import torrent file", "path", r.Path, "error", r.Err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckTorrents imports the files of tracked torrents the client finished.
+// The originals stay where they are so the client keeps seeding.
+func (w *Watcher) CheckTorrents(ctx context.Context, db *gorm.DB, client torrentclient.Client) ([]Result, error) {
+	jobs, err := database.ActiveTorrentJobs(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load torrents: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	hashes := make([]string, len(jobs))
+	for i, job := range jobs {
+		hashes[i] = job.Hash
+	}
+	torrents, err := client.Torrents(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	byHash := make(map[string]torrentclient.Torrent, len(torrents))
+	for _, t := range torrents {
+		byHash[t.Hash] = t
+	}
+
+	var results []Result
+	for _, job := range jobs {
+		torrent, ok := byHash[job.Hash]
+		var jobErr error
+		switch {
+		case !ok:
+			jobErr = fmt.Errorf("removed from %s", client.Name())
+		case torrent.Error != "":
+			jobErr = fmt.Errorf("%s: %s", client.Name(), torrent.Error)
+		case !torrent.Done:
+			continue
+		default:
+			var imported []Result
+			imported, jobErr = w.importTorrent(ctx, job, torrent)
+			results = append(results, imported...)
+		}
+		if jobErr != nil {
+			results = append(results, Result{Path: job.Name, Err: jobErr})
+		}
+		if err := database.FinishTorrentJob(db, job.ID, jobErr); err != nil {
+			w.logger.Warn("failed to update torrent", "hash", job.Hash, "error", err)
+		}
+	}
+	return results, nil
+}
+
+// importTorrent imports the videos of a finished torrent. Episodes already
+// in the library are skipped; any other failure fails the torrent.
+func (w *Watcher) importTorrent(ctx context.Context, job database.TorrentJob, torrent torrentclient.Torrent) ([]Result, error) {
+	var results []Result
+	var failed error
+	for _, path := range torrent.Files {
+		if !videoExtensions[strings.ToLower(filepath.Ext(path))] {
+			continue
+		}
+
+		task, err := w.Match(ctx, filepath.Base(path))
+		if errors.Is(err, ErrNoMatch) && job.Show != "" {
+			// Feed subscriptions name shows greg may not have seen yet
+			task, err = torrentTask(job, filepath.Base(path)), nil
+		}
+		if err == nil {
+			task, err = w.manager.ImportFile(ctx, path, task, downloader.ImportLink)
+		}
+		results = append(results, Result{Path: path, Task: task, Err: err})
+
+		var dup *downloader.DuplicateError
+		if err != nil && !errors.As(err, &dup) && failed == nil {
+			failed = err
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no video files in %s", torrent.Name)
+	}
+	return results, failed
+}
+
+// torrentTask is the task of a torrent file whose show is only known from
+// the feed subscription that matched it
+func torrentTask(job database.TorrentJob, name string) downloader.DownloadTask {
+	_, episode, season, quality := ParseName(name)
+	if episode == 0 {
+		episode = job.Episode
+	}
+	return downloader.DownloadTask{
+		MediaID:    job.Hash,
+		MediaTitle: job.Show,
+		MediaType:  providers.MediaTypeAnime,
+		Episode:    episode,
+		Season:     season,
+		Quality:    quality,
+		Provider:   job.Client,
+	}
+}
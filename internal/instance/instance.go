@@ -0,0 +1,153 @@
+// Package instance keeps a single greg process running per user and lets
+// later invocations forward commands to it over a local socket.
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	lockName   = "greg.lock"
+	socketName = "greg.sock"
+
+	// CommandOpen opens a link in the running instance
+	CommandOpen = "open"
+	// CommandPlay plays a stream URL in the running instance. Args are the
+	// URL, the Referer, the title and then "Name: value" headers.
+	CommandPlay = "play"
+	// CommandDownload queues downloads in the running instance. Args are the
+	// provider, the media ID, the episode range, the quality and the
+	// duplicate action.
+	CommandDownload = "download"
+)
+
+// ErrRunning is returned by Acquire when another instance holds the lock
+var ErrRunning = errors.New("another greg instance is running")
+
+// Request is a command forwarded from another invocation
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// response is the reply to a forwarded request
+type response struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Handler runs a forwarded request. The returned error is reported back to
+// the invocation that sent it.
+type Handler func(Request) error
+
+// Instance is the lock held by the running greg process
+type Instance struct {
+	lock       *os.File
+	listener   net.Listener
+	socketPath string
+	closeOnce  sync.Once
+}
+
+// Acquire takes the instance lock in dir and starts listening for forwarded
+// commands. Returns ErrRunning when another instance already holds it.
+func Acquire(dir string) (*Instance, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create instance directory: %w", err)
+	}
+
+	lock, err := openLock(filepath.Join(dir, lockName))
+	if err != nil {
+		return nil, err
+	}
+
+	// The socket of a crashed instance is left behind, and only the lock
+	// holder may remove it
+	socketPath := filepath.Join(dir, socketName)
+	_ = os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+
+	return &Instance{
+		lock:       lock,
+		listener:   listener,
+		socketPath: socketPath,
+	}, nil
+}
+
+// Serve runs handler for every forwarded request until the instance is closed
+func (i *Instance) Serve(handler Handler) {
+	for {
+		conn, err := i.listener.Accept()
+		if err != nil {
+			return
+		}
+		go serveConn(conn, handler)
+	}
+}
+
+// serveConn reads one request from conn and writes the handler's reply
+func serveConn(conn net.Conn, handler Handler) {
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var req Request
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		resp.Error = fmt.Sprintf("invalid request: %v", err)
+	} else if err := handler(req); err != nil {
+		resp.Error = err.Error()
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// Close stops listening and releases the lock
+func (i *Instance) Close() error {
+	var err error
+	i.closeOnce.Do(func() {
+		err = i.listener.Close()
+		_ = os.Remove(i.socketPath)
+		if lockErr := i.lock.Close(); err == nil {
+			err = lockErr
+		}
+	})
+	return err
+}
+
+// Forward sends a request to the instance running in dir and waits for its
+// reply
+func Forward(ctx context.Context, dir string, req Request) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", filepath.Join(dir, socketName))
+	if err != nil {
+		return fmt.Errorf("failed to connect to running instance: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return fmt.Errorf("failed to read reply: %w", err)
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
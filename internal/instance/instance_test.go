@@ -0,0 +1,59 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireIsExclusive(t *testing.T) {
+	dir := t.TempDir()
+
+	inst, err := Acquire(dir)
+	require.NoError(t, err)
+
+	_, err = Acquire(dir)
+	assert.ErrorIs(t, err, ErrRunning)
+
+	require.NoError(t, inst.Close())
+	inst, err = Acquire(dir)
+	require.NoError(t, err)
+	require.NoError(t, inst.Close())
+}
+
+func TestForward(t *testing.T) {
+	dir := t.TempDir()
+	inst, err := Acquire(dir)
+	require.NoError(t, err)
+	defer func() { _ = inst.Close() }()
+
+	received := make(chan Request, 1)
+	go inst.Serve(func(req Request) error {
+		if req.Command != CommandOpen {
+			return errors.New("unknown command")
+		}
+		received <- req
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, Forward(ctx, dir, Request{Command: CommandOpen, Args: []string{"https://anilist.co/anime/1"}}))
+	req := <-received
+	assert.Equal(t, []string{"https://anilist.co/anime/1"}, req.Args)
+
+	err = Forward(ctx, dir, Request{Command: "bogus"})
+	assert.EqualError(t, err, "unknown command")
+}
+
+func TestForwardWithoutInstance(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Error(t, Forward(ctx, t.TempDir(), Request{Command: CommandOpen}))
+}
@@ -0,0 +1,17 @@
+//go:build !(unix || windows)
+
+package instance
+
+import (
+	"fmt"
+	"os"
+)
+
+// openLock opens path without locking on platforms without file locks
+func openLock(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	return f, nil
+}
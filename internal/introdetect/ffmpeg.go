@@ -0,0 +1,139 @@
+package introdetect
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SidecarExt is appended to the name of a video whose container can't hold
+// chapters; mpv loads it with --chapters-file
+const SidecarExt = ".chapters.txt"
+
+// ChapterTitle names the chapter of the opening; mpv scripts and players
+// that skip intros look for it
+const ChapterTitle = "Opening"
+
+// FingerprintFile decodes the first Window of a video's audio with ffmpeg
+// and fingerprints it
+func FingerprintFile(ctx context.Context, ffmpeg, path string) (Fingerprint, error) {
+	cmd := exec.CommandContext(ctx, ffmpeg,
+		"-hide_banner", "-loglevel", "error", "-nostdin",
+		"-t", strconv.Itoa(int(Window.Seconds())),
+		"-i", path,
+		"-map", "0:a:0", "-vn", "-sn",
+		"-ac", "1", "-ar", strconv.Itoa(SampleRate),
+		"-f", "s16le", "pipe:1",
+	)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	raw, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to decode audio: %s", msg)
+		}
+		return nil, fmt.Errorf("failed to decode audio: %w", err)
+	}
+
+	samples := make([]float64, len(raw)/2)
+	for i := range samples {
+		samples[i] = float64(int16(binary.LittleEndian.Uint16(raw[2*i:]))) / 32768
+	}
+	return Compute(samples), nil
+}
+
+// HasChapters reports whether an opening was already marked in path, in the
+// file itself or in a sidecar
+func HasChapters(ctx context.Context, ffprobe, path string) bool {
+	if _, err := os.Stat(path + SidecarExt); err == nil {
+		return true
+	}
+	if ffprobe == "" {
+		return false
+	}
+	out, err := exec.CommandContext(ctx, ffprobe,
+		"-v", "error", "-show_chapters", "-of", "csv=p=0", path,
+	).Output()
+	return err == nil && strings.Contains(string(out), ChapterTitle)
+}
+
+// WriteChapters marks the opening in a video. MKV and MP4 files are remuxed
+// with the chapters in place (streams are copied, not re-encoded); other
+// containers get an ffmetadata sidecar next to them. duration is the length
+// of the video, 0 when unknown.
+func WriteChapters(ctx context.Context, ffmpeg, path string, opening Segment, duration time.Duration) error {
+	metadata := ffmetadata(opening, duration)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mkv", ".mp4", ".m4v":
+	default:
+		return os.WriteFile(path+SidecarExt, []byte(metadata), 0644)
+	}
+
+	metaFile, err := os.CreateTemp(filepath.Dir(path), ".greg-chapters-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to write chapters: %w", err)
+	}
+	defer func() { _ = os.Remove(metaFile.Name()) }()
+	if _, err := metaFile.WriteString(metadata); err != nil {
+		_ = metaFile.Close()
+		return fmt.Errorf("failed to write chapters: %w", err)
+	}
+	if err := metaFile.Close(); err != nil {
+		return fmt.Errorf("failed to write chapters: %w", err)
+	}
+
+	// Keep the extension so ffmpeg picks the same muxer
+	tmp := strings.TrimSuffix(path, filepath.Ext(path)) + ".chapters" + filepath.Ext(path)
+	cmd := exec.CommandContext(ctx, ffmpeg,
+		"-hide_banner", "-loglevel", "error", "-nostdin", "-y",
+		"-i", path,
+		"-f", "ffmetadata", "-i", metaFile.Name(),
+		"-map", "0", "-map_metadata", "0", "-map_chapters", "1",
+		"-c", "copy",
+		tmp,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.Remove(tmp)
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("failed to add chapters: %s", msg)
+		}
+		return fmt.Errorf("failed to add chapters: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace video: %w", err)
+	}
+	return nil
+}
+
+// ffmetadata renders the chapters around an opening in ffmpeg's metadata
+// format: what comes before it, the opening, and the rest of the episode
+func ffmetadata(opening Segment, duration time.Duration) string {
+	type chapter struct {
+		title      string
+		start, end time.Duration
+	}
+	var chapters []chapter
+	if opening.Start > 0 {
+		chapters = append(chapters, chapter{"Prologue", 0, opening.Start})
+	}
+	chapters = append(chapters, chapter{ChapterTitle, opening.Start, opening.End})
+	if duration > opening.End {
+		chapters = append(chapters, chapter{"Episode", opening.End, duration})
+	}
+
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for _, c := range chapters {
+		fmt.Fprintf(&b, "\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			c.start.Milliseconds(), c.end.Milliseconds(), c.title)
+	}
+	return b.String()
+}
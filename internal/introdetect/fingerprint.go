@@ -0,0 +1,121 @@
+package introdetect
+
+import (
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+const (
+	// SampleRate is the rate audio is decoded at; the bands fingerprinted
+	// are all below its Nyquist frequency
+	SampleRate = 5512
+
+	frameSize = 2048 // Samples per analysis frame, about 0.37s
+	hopSize   = 512  // Samples between frames, about 10.8 frames per second
+
+	// Frequency range split into bands; where most music energy is and
+	// least affected by compression
+	minFreq = 300.0
+	maxFreq = 2000.0
+	bands   = 33 // 33 bands give 32 energy differences, one per bit
+)
+
+// Fingerprint is a 32-bit hash per audio frame. Each bit says whether the
+// energy difference between two neighbouring frequency bands grew or shrank
+// since the previous frame, which survives re-encoding and volume changes.
+type Fingerprint []uint32
+
+// framesIn is the number of frames covering d
+func framesIn(d time.Duration) int {
+	return int(d.Seconds() * SampleRate / hopSize)
+}
+
+// frameTime is the position of frame i
+func frameTime(i int) time.Duration {
+	return time.Duration(float64(i) * hopSize / SampleRate * float64(time.Second))
+}
+
+// Compute fingerprints mono samples at SampleRate
+func Compute(samples []float64) Fingerprint {
+	if len(samples) < frameSize {
+		return nil
+	}
+
+	window := make([]float64, frameSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(frameSize-1))
+	}
+	edges := bandEdges()
+
+	buf := make([]complex128, frameSize)
+	prev := make([]float64, bands)
+	energy := make([]float64, bands)
+	var fp Fingerprint
+	for start := 0; start+frameSize <= len(samples); start += hopSize {
+		for i := range buf {
+			buf[i] = complex(samples[start+i]*window[i], 0)
+		}
+		fft(buf)
+
+		for b := 0; b < bands; b++ {
+			sum := 0.0
+			for k := edges[b]; k < edges[b+1]; k++ {
+				sum += real(buf[k])*real(buf[k]) + imag(buf[k])*imag(buf[k])
+			}
+			energy[b] = sum
+		}
+
+		if start > 0 {
+			var hash uint32
+			for b := 0; b < bands-1; b++ {
+				if (energy[b]-energy[b+1])-(prev[b]-prev[b+1]) > 0 {
+					hash |= 1 << b
+				}
+			}
+			fp = append(fp, hash)
+		}
+		copy(prev, energy)
+	}
+	return fp
+}
+
+// bandEdges returns the FFT bins bounding the bands, spaced logarithmically
+// like pitch is perceived
+func bandEdges() []int {
+	edges := make([]int, bands+1)
+	ratio := math.Pow(maxFreq/minFreq, 1.0/bands)
+	for i := range edges {
+		freq := minFreq * math.Pow(ratio, float64(i))
+		edges[i] = int(freq * frameSize / SampleRate)
+	}
+	return edges
+}
+
+// fft is an in-place radix-2 Cooley-Tukey transform; len(x) must be a power
+// of two
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], x[start+k+size/2]*w
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
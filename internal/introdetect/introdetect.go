@@ -0,0 +1,96 @@
+// Package introdetect finds the opening song of a series by comparing the
+// audio of two of its episodes. The opening is the longest stretch of audio
+// both episodes share near their start; its position in each file is
+// written as chapters so local playback can skip it.
+package introdetect
+
+import (
+	"math/bits"
+	"time"
+)
+
+const (
+	// Window is how much of each episode's start is searched. Cold opens
+	// push the opening a few minutes in.
+	Window = 8 * time.Minute
+
+	// MinLength and MaxLength bound what counts as an opening; shorter
+	// matches are recaps or eyecatches, longer ones are the same file
+	MinLength = 20 * time.Second
+	MaxLength = 3 * time.Minute
+
+	// maxBitErrors is how many of a frame's 32 bits may differ for two
+	// frames to still be the same audio (different encodes never match
+	// exactly)
+	maxBitErrors = 10
+
+	// maxGap is how many consecutive frames may mismatch inside a match,
+	// e.g. where a sound effect plays over the song in one episode
+	maxGap = 8
+)
+
+// Segment is a stretch of an episode
+type Segment struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Length is the duration of the segment
+func (s Segment) Length() time.Duration {
+	return s.End - s.Start
+}
+
+// Intro is an opening found in two episodes
+type Intro struct {
+	A Segment // Position in the first episode
+	B Segment // Position in the second episode
+}
+
+// Match finds the opening shared by two fingerprints. ok is false when they
+// share no stretch between MinLength and MaxLength.
+func Match(a, b Fingerprint) (intro Intro, ok bool) {
+	minFrames := framesIn(MinLength)
+	maxFrames := framesIn(MaxLength)
+
+	bestLen, bestA, bestB := 0, 0, 0
+	// Every alignment of b against a: offset is a's frame minus b's frame
+	for offset := -(len(b) - minFrames); offset <= len(a)-minFrames; offset++ {
+		i := max(offset, 0)
+		j := i - offset
+
+		runStart, lastMatch, gap := -1, -1, 0
+		for ; i < len(a) && j < len(b); i, j = i+1, j+1 {
+			if bits.OnesCount32(a[i]^b[j]) <= maxBitErrors {
+				if runStart < 0 {
+					runStart = i
+				}
+				lastMatch = i
+				gap = 0
+				continue
+			}
+			if runStart < 0 {
+				continue
+			}
+			gap++
+			if gap > maxGap {
+				if n := lastMatch - runStart + 1; n > bestLen && n <= maxFrames {
+					bestLen, bestA, bestB = n, runStart, runStart-offset
+				}
+				runStart, gap = -1, 0
+			}
+		}
+		if runStart >= 0 {
+			if n := lastMatch - runStart + 1; n > bestLen && n <= maxFrames {
+				bestLen, bestA, bestB = n, runStart, runStart-offset
+			}
+		}
+	}
+
+	if bestLen < minFrames {
+		return Intro{}, false
+	}
+	return Intro{
+		A: Segment{Start: frameTime(bestA), End: frameTime(bestA + bestLen)},
+		B: Segment{Start: frameTime(bestB), End: frameTime(bestB + bestLen)},
+	}, true
+}
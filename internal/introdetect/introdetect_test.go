@@ -0,0 +1,75 @@
+package introdetect
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noise(r *rand.Rand, d time.Duration) []float64 {
+	samples := make([]float64, int(d.Seconds()*SampleRate))
+	for i := range samples {
+		samples[i] = r.Float64()*2 - 1
+	}
+	return samples
+}
+
+// episode surrounds the song with unrelated audio; jitter adds a little
+// noise to the song like a different encode would
+func episode(r *rand.Rand, before time.Duration, song []float64, after time.Duration, jitter float64) []float64 {
+	samples := noise(r, before)
+	for _, s := range song {
+		samples = append(samples, s*0.8+(r.Float64()*2-1)*jitter)
+	}
+	return append(samples, noise(r, after)...)
+}
+
+func assertNear(t *testing.T, want, got time.Duration) {
+	t.Helper()
+	assert.InDelta(t, want.Seconds(), got.Seconds(), 1, "want %s, got %s", want, got)
+}
+
+func TestMatchFindsSharedOpening(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	song := noise(r, 60*time.Second)
+
+	a := Compute(episode(r, 30*time.Second, song, 20*time.Second, 0))
+	b := Compute(episode(r, 70*time.Second, song, 10*time.Second, 0.05))
+
+	intro, ok := Match(a, b)
+	require.True(t, ok)
+	assertNear(t, 30*time.Second, intro.A.Start)
+	assertNear(t, 90*time.Second, intro.A.End)
+	assertNear(t, 70*time.Second, intro.B.Start)
+	assertNear(t, 130*time.Second, intro.B.End)
+}
+
+func TestMatchIgnoresShortOrMissingMatches(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+
+	_, ok := Match(Compute(noise(r, 90*time.Second)), Compute(noise(r, 90*time.Second)))
+	assert.False(t, ok, "unrelated audio")
+
+	jingle := noise(r, 5*time.Second)
+	a := Compute(episode(r, 20*time.Second, jingle, 40*time.Second, 0))
+	b := Compute(episode(r, 50*time.Second, jingle, 10*time.Second, 0))
+	_, ok = Match(a, b)
+	assert.False(t, ok, "eyecatch shorter than MinLength")
+}
+
+func TestFFMetadata(t *testing.T) {
+	got := ffmetadata(Segment{Start: 90 * time.Second, End: 180 * time.Second}, 24*time.Minute)
+	assert.True(t, strings.HasPrefix(got, ";FFMETADATA1\n"))
+	assert.Contains(t, got, "START=0\nEND=90000\ntitle=Prologue")
+	assert.Contains(t, got, "START=90000\nEND=180000\ntitle=Opening")
+	assert.Contains(t, got, "START=180000\nEND=1440000\ntitle=Episode")
+
+	// A cold-open-less episode starts with the opening
+	got = ffmetadata(Segment{Start: 0, End: 90 * time.Second}, 0)
+	assert.NotContains(t, got, "Prologue")
+	assert.NotContains(t, got, "title=Episode")
+}
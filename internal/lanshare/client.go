@@ -0,0 +1,164 @@
+package lanshare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Client finds and pulls files from peers
+type Client struct {
+	token string
+	http  *http.Client
+}
+
+// NewClient creates a client sending token to peers
+func NewClient(token string) *Client {
+	// No overall timeout: transfers of large files take a while. Requests
+	// are bounded by their contexts.
+	return &Client{token: token, http: &http.Client{}}
+}
+
+func (c *Client) get(ctx context.Context, u string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.token != "" {
+		req.Header.Set(TokenHeader, c.token)
+	}
+	return c.http.Do(req)
+}
+
+// Files lists the files a peer shares matching q, or every shared file
+// when q is nil
+func (c *Client) Files(ctx context.Context, peer Peer, q *Query) ([]File, error) {
+	u := "http://" + peer.Addr + filesPath
+	if q != nil {
+		params := url.Values{}
+		params.Set("media_id", q.MediaID)
+		params.Set("provider", q.Provider)
+		params.Set("title", q.Title)
+		params.Set("type", q.MediaType)
+		params.Set("season", strconv.Itoa(q.Season))
+		params.Set("episode", strconv.Itoa(q.Episode))
+		u += "?" + params.Encode()
+	}
+
+	resp, err := c.get(ctx, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", peer.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %s", peer.Name, resp.Status)
+	}
+
+	var files []File
+	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("failed to decode file list of %s: %w", peer.Name, err)
+	}
+	return files, nil
+}
+
+// Find returns the first peer sharing the queried episode and its file.
+// Peers that can't be reached are skipped.
+func (c *Client) Find(ctx context.Context, peers []Peer, q Query) (Peer, File, bool) {
+	for _, peer := range peers {
+		findCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		files, err := c.Files(findCtx, peer, &q)
+		cancel()
+		if err != nil || len(files) == 0 {
+			continue
+		}
+		return peer, files[0], true
+	}
+	return Peer{}, File{}, false
+}
+
+// Download pulls a file from a peer into dest. The file is written next to
+// dest first and renamed once complete; an interrupted download resumes
+// from what was written. progress, if set, is called as data arrives.
+func (c *Client) Download(ctx context.Context, peer Peer, file File, dest string, progress func(written, total int64)) error {
+	partial := dest + ".part"
+	out, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", partial, err)
+	}
+	defer func() { _ = out.Close() }()
+
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to resume %s: %w", partial, err)
+	}
+	if offset > file.Size {
+		offset = 0
+	}
+
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.get(ctx, "http://"+peer.Addr+filesPath+"/"+url.PathEscape(file.ID), header)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", peer.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The whole file, start over
+		offset = 0
+	default:
+		return fmt.Errorf("%s answered %s", peer.Name, resp.Status)
+	}
+	if err := out.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", partial, err)
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", partial, err)
+	}
+
+	written := offset
+	buf := make([]byte, 256*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return fmt.Errorf("failed to write %s: %w", partial, err)
+			}
+			written += int64(n)
+			if progress != nil {
+				progress(written, file.Size)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("transfer from %s interrupted: %w", peer.Name, readErr)
+		}
+	}
+
+	if written != file.Size {
+		return fmt.Errorf("transfer from %s incomplete: got %d of %d bytes", peer.Name, written, file.Size)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", partial, err)
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", dest, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package lanshare shares completed downloads between greg instances on the
+// same network. Instances announce themselves over mDNS (DNS-SD service
+// _greg._tcp) and serve their downloads over HTTP, so an episode one machine
+// already downloaded can be pulled from it instead of the provider.
+package lanshare
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TokenHeader carries the shared secret when lan.token is set
+const TokenHeader = "X-Greg-Token"
+
+// Peer is another greg instance sharing its downloads
+type Peer struct {
+	ID   string `json:"id"`   // Random per run, tells instances on the same host apart
+	Name string `json:"name"` // Host name
+	Addr string `json:"addr"` // host:port of its share server
+}
+
+// File is a completed download a peer shares
+type File struct {
+	ID         string `json:"id"`
+	MediaID    string `json:"media_id"`
+	MediaTitle string `json:"media_title"`
+	MediaType  string `json:"media_type"`
+	Season     int    `json:"season,omitempty"`
+	Episode    int    `json:"episode"`
+	Quality    string `json:"quality"`
+	Provider   string `json:"provider"`
+	Name       string `json:"name"` // Base name of the file
+	Size       int64  `json:"size"`
+}
+
+// Query describes the episode being looked for. The same episode from
+// another provider has another media ID, so it is also matched by title.
+type Query struct {
+	MediaID   string
+	Provider  string
+	Title     string
+	MediaType string
+	Season    int
+	Episode   int
+}
+
+// Matches reports whether a shared file is the queried episode
+func (q Query) Matches(f File) bool {
+	if f.Episode != q.Episode || normalizeSeason(f.Season) != normalizeSeason(q.Season) {
+		return false
+	}
+	if q.MediaType != "" && f.MediaType != "" && q.MediaType != f.MediaType {
+		return false
+	}
+	if q.MediaID != "" && f.MediaID == q.MediaID && f.Provider == q.Provider {
+		return true
+	}
+	title := normalizeTitle(q.Title)
+	return title != "" && normalizeTitle(f.MediaTitle) == title
+}
+
+// normalizeSeason treats a missing season as the first one, since providers
+// disagree on numbering single-season shows
+func normalizeSeason(season int) int {
+	if season <= 1 {
+		return 1
+	}
+	return season
+}
+
+// normalizeTitle keeps only the letters and digits of a title, lowercased
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package lanshare
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+func TestQueryMatches(t *testing.T) {
+	file := File{MediaID: "abc", Provider: "allanime", MediaTitle: "Frieren: Beyond Journey's End", MediaType: "anime", Episode: 3}
+
+	assert.True(t, Query{MediaID: "abc", Provider: "allanime", Episode: 3}.Matches(file))
+	assert.True(t, Query{MediaID: "other", Provider: "hianime", Title: "frieren beyond journeys end", Episode: 3, Season: 1}.Matches(file),
+		"same title from another provider, season 0 and 1 are the same")
+	assert.False(t, Query{MediaID: "abc", Provider: "allanime", Episode: 4}.Matches(file))
+	assert.False(t, Query{MediaID: "abc", Provider: "allanime", Episode: 3, Season: 2}.Matches(file))
+	assert.False(t, Query{Title: "Frieren: Beyond Journey's End", MediaType: "tv", Episode: 3}.Matches(file))
+	assert.False(t, Query{MediaID: "abc", Provider: "hianime", Episode: 3}.Matches(file), "media IDs are per provider")
+	assert.False(t, Query{Title: "!!!", Episode: 3}.Matches(File{MediaTitle: "???", Episode: 3}))
+}
+
+func TestMDNSAnswer(t *testing.T) {
+	svc := service{id: "abcd1234", host: "desktop", port: 7879}
+
+	query, err := browseQuery()
+	require.NoError(t, err)
+	response, ok := svc.answer(query, true)
+	require.True(t, ok)
+
+	peer, ok := parseAnswer(response, net.IPv4(192, 168, 1, 20))
+	require.True(t, ok)
+	assert.Equal(t, Peer{ID: "abcd1234", Name: "desktop", Addr: "192.168.1.20:7879"}, peer)
+
+	// Responses and other services are ignored
+	_, ok = svc.answer(response, false)
+	assert.False(t, ok)
+	_, ok = parseAnswer(query, net.IPv4(192, 168, 1, 20))
+	assert.False(t, ok)
+}
+
+func newTestServer(t *testing.T, token string) (*httptest.Server, string) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Frieren - 03.mkv")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("frieren", 1000)), 0644))
+	require.NoError(t, db.Create(&database.Download{
+		ID: "done", MediaID: "abc", MediaTitle: "Frieren", MediaType: "anime", Episode: 3,
+		Quality: "1080p", Provider: "allanime", Status: "completed", FilePath: path,
+	}).Error)
+	require.NoError(t, db.Create(&database.Download{
+		ID: "queued", MediaID: "abc", MediaTitle: "Frieren", MediaType: "anime", Episode: 4,
+		Quality: "1080p", Provider: "allanime", Status: "queued", FilePath: filepath.Join(dir, "Frieren - 04.mkv"),
+	}).Error)
+	require.NoError(t, db.Create(&database.Download{
+		ID: "gone", MediaID: "abc", MediaTitle: "Frieren", MediaType: "anime", Episode: 5,
+		Quality: "1080p", Provider: "allanime", Status: "completed", FilePath: filepath.Join(dir, "deleted.mkv"),
+	}).Error)
+
+	server := httptest.NewServer(NewServer(db, token))
+	t.Cleanup(server.Close)
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestShareAndPull(t *testing.T) {
+	_, addr := newTestServer(t, "secret")
+	peer := Peer{Name: "desktop", Addr: addr}
+	client := NewClient("secret")
+	ctx := context.Background()
+
+	files, err := client.Files(ctx, peer, nil)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "only completed downloads still on disk are shared")
+	assert.Equal(t, "Frieren - 03.mkv", files[0].Name)
+	assert.Equal(t, int64(7000), files[0].Size)
+
+	_, _, ok := client.Find(ctx, []Peer{peer}, Query{Title: "Frieren", Episode: 4})
+	assert.False(t, ok)
+
+	found, file, ok := client.Find(ctx, []Peer{{Name: "offline", Addr: "127.0.0.1:1"}, peer}, Query{Title: "frieren", Episode: 3})
+	require.True(t, ok)
+	assert.Equal(t, "desktop", found.Name)
+
+	dest := filepath.Join(t.TempDir(), "Frieren - 03.mkv")
+	// Resume from a partial transfer
+	require.NoError(t, os.WriteFile(dest+".part", []byte("frieren"), 0644))
+	var last int64
+	require.NoError(t, client.Download(ctx, found, file, dest, func(written, total int64) { last = written }))
+	assert.Equal(t, int64(7000), last)
+
+	data, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("frieren", 1000), string(data))
+	assert.NoFileExists(t, dest+".part")
+}
+
+func TestShareRequiresToken(t *testing.T) {
+	server, addr := newTestServer(t, "secret")
+
+	_, err := NewClient("wrong").Files(context.Background(), Peer{Name: "desktop", Addr: addr}, nil)
+	assert.ErrorContains(t, err, "403")
+
+	resp, err := http.Get(server.URL + filesPath + "/queued")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestShareOnlyServesCompletedDownloads(t *testing.T) {
+	server, _ := newTestServer(t, "")
+
+	for id, status := range map[string]int{"done": http.StatusOK, "queued": http.StatusNotFound, "gone": http.StatusNotFound, "missing": http.StatusNotFound} {
+		resp, err := http.Get(server.URL + filesPath + "/" + id)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode, id)
+	}
+}
@@ -0,0 +1,256 @@
+package lanshare
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ServiceName is the DNS-SD service greg instances announce
+const ServiceName = "_greg._tcp.local."
+
+// mdnsTTL is how long announced records stay valid, in seconds
+const mdnsTTL = 120
+
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// service is the announcement of one share server
+type service struct {
+	id   string
+	host string // Host name without ".local."
+	port int
+}
+
+// instance is the DNS-SD instance name of the service
+func (s service) instance() string {
+	return s.host + "." + ServiceName
+}
+
+// answer builds the response to an mDNS query, or reports false when the
+// query isn't about greg instances
+func (s service) answer(packet []byte, unicast bool) ([]byte, bool) {
+	var query dnsmessage.Message
+	if err := query.Unpack(packet); err != nil || query.Header.Response {
+		return nil, false
+	}
+
+	asked := false
+	for _, q := range query.Questions {
+		if (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL) && strings.EqualFold(q.Name.String(), ServiceName) {
+			asked = true
+		}
+	}
+	if !asked {
+		return nil, false
+	}
+
+	serviceName := dnsmessage.MustNewName(ServiceName)
+	instanceName, err := dnsmessage.NewName(s.instance())
+	if err != nil {
+		return nil, false
+	}
+	hostName, err := dnsmessage.NewName(s.host + ".local.")
+	if err != nil {
+		return nil, false
+	}
+
+	header := func(name dnsmessage.Name, typ dnsmessage.Type) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Type: typ, Class: dnsmessage.ClassINET, TTL: mdnsTTL}
+	}
+	response := dnsmessage.Message{
+		Header: dnsmessage.Header{Response: true, Authoritative: true},
+		Answers: []dnsmessage.Resource{{
+			Header: header(serviceName, dnsmessage.TypePTR),
+			Body:   &dnsmessage.PTRResource{PTR: instanceName},
+		}},
+		Additionals: []dnsmessage.Resource{
+			{
+				Header: header(instanceName, dnsmessage.TypeSRV),
+				Body:   &dnsmessage.SRVResource{Target: hostName, Port: uint16(s.port)},
+			},
+			{
+				Header: header(instanceName, dnsmessage.TypeTXT),
+				Body:   &dnsmessage.TXTResource{TXT: []string{"id=" + s.id, "v=1"}},
+			},
+		},
+	}
+	for _, ip := range localIPv4s() {
+		response.Additionals = append(response.Additionals, dnsmessage.Resource{
+			Header: header(hostName, dnsmessage.TypeA),
+			Body:   &dnsmessage.AResource{A: [4]byte(ip)},
+		})
+	}
+	// Legacy unicast queries expect their ID and question back
+	if unicast {
+		response.Header.ID = query.Header.ID
+		response.Questions = query.Questions
+	}
+
+	data, err := response.Pack()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// localIPv4s returns the IPv4 addresses of the machine's active, non-loopback
+// interfaces
+func localIPv4s() []net.IP {
+	var ips []net.IP
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				if ip := ipNet.IP.To4(); ip != nil {
+					ips = append(ips, ip)
+				}
+			}
+		}
+	}
+	return ips
+}
+
+// advertiser answers mDNS queries for greg instances
+type advertiser struct {
+	conn *net.UDPConn
+	svc  service
+}
+
+// advertise announces a share server on the local network until closed
+func advertise(svc service) (*advertiser, error) {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join mDNS group: %w", err)
+	}
+	a := &advertiser{conn: conn, svc: svc}
+	go a.serve()
+	return a, nil
+}
+
+func (a *advertiser) serve() {
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		// Queries not sent from the mDNS port want a unicast reply
+		unicast := src.Port != mdnsAddr.Port
+		response, ok := a.svc.answer(buf[:n], unicast)
+		if !ok {
+			continue
+		}
+		dst := mdnsAddr
+		if unicast {
+			dst = src
+		}
+		_, _ = a.conn.WriteToUDP(response, dst)
+	}
+}
+
+func (a *advertiser) Close() error {
+	return a.conn.Close()
+}
+
+// browseQuery is the mDNS question for greg instances
+func browseQuery() ([]byte, error) {
+	query := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(ServiceName),
+			Type:  dnsmessage.TypePTR,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	return query.Pack()
+}
+
+// parseAnswer reads the peer announced in an mDNS response. Its address is
+// the one the response came from, which is known to be reachable.
+func parseAnswer(packet []byte, src net.IP) (Peer, bool) {
+	var response dnsmessage.Message
+	if err := response.Unpack(packet); err != nil || !response.Header.Response {
+		return Peer{}, false
+	}
+
+	var peer Peer
+	port := 0
+	records := append(response.Answers, response.Additionals...)
+	for _, r := range records {
+		name := r.Header.Name.String()
+		if !strings.HasSuffix(strings.ToLower(name), "."+ServiceName) {
+			continue
+		}
+		switch body := r.Body.(type) {
+		case *dnsmessage.SRVResource:
+			port = int(body.Port)
+			peer.Name = strings.TrimSuffix(name[:len(name)-len(ServiceName)], ".")
+		case *dnsmessage.TXTResource:
+			for _, txt := range body.TXT {
+				if id, ok := strings.CutPrefix(txt, "id="); ok {
+					peer.ID = id
+				}
+			}
+		}
+	}
+	if port == 0 {
+		return Peer{}, false
+	}
+	peer.Addr = net.JoinHostPort(src.String(), fmt.Sprint(port))
+	return peer, true
+}
+
+// Browse asks the local network for greg instances and collects the ones
+// answering within wait
+func Browse(ctx context.Context, wait time.Duration) ([]Peer, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mDNS socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	query, err := browseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(query, mdnsAddr); err != nil {
+		return nil, fmt.Errorf("failed to send mDNS query: %w", err)
+	}
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	var peers []Peer
+	seen := make(map[string]bool)
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			// The deadline passed
+			break
+		}
+		peer, ok := parseAnswer(buf[:n], src.IP)
+		if !ok || seen[peer.ID+peer.Addr] {
+			continue
+		}
+		seen[peer.ID+peer.Addr] = true
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
@@ -0,0 +1,227 @@
+package lanshare
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// filesPath lists shared files; filesPath/<id> serves one
+const filesPath = "/lanshare/v1/files"
+
+// Server shares the completed downloads of this instance
+type Server struct {
+	db    *gorm.DB
+	token string
+	id    string
+
+	server     *http.Server
+	listener   net.Listener
+	advertiser *advertiser
+}
+
+// NewServer creates a share server of the downloads in db. When token is
+// set, peers must send the same token.
+func NewServer(db *gorm.DB, token string) *Server {
+	return &Server{db: db, token: token, id: uuid.NewString()[:8]}
+}
+
+// ID identifies this server in mDNS answers, so an instance can skip itself
+// when browsing
+func (s *Server) ID() string {
+	return s.id
+}
+
+// Listen starts serving on addr (e.g. ":7879") in the background
+func (s *Server) Listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: s}
+	go func() { _ = s.server.Serve(listener) }()
+	return nil
+}
+
+// Advertise announces the server over mDNS until it is closed. A server
+// that can't be announced is still reachable by address.
+func (s *Server) Advertise() error {
+	if s.listener == nil {
+		return fmt.Errorf("share server is not listening")
+	}
+	port := s.listener.Addr().(*net.TCPAddr).Port
+	a, err := advertise(service{id: s.id, host: hostLabel(), port: port})
+	if err != nil {
+		return fmt.Errorf("failed to announce share server: %w", err)
+	}
+	s.advertiser = a
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Close stops announcing and serving
+func (s *Server) Close() error {
+	if s.advertiser != nil {
+		_ = s.advertiser.Close()
+	}
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		// Transfers still running are cut off
+		return s.server.Close()
+	}
+	return nil
+}
+
+// hostLabel returns the host name as a single DNS label
+func hostLabel() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "greg"
+	}
+	host, _, _ = strings.Cut(host, ".")
+	return strings.Map(func(r rune) rune {
+		if r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, host)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), []byte(s.token)) != 1 {
+		http.Error(w, "invalid token", http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path == filesPath {
+		s.serveList(w, r)
+		return
+	}
+	if id, ok := strings.CutPrefix(r.URL.Path, filesPath+"/"); ok && id != "" {
+		s.serveFile(w, r, id)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// serveList lists the shared files matching the query parameters title,
+// media_id, provider, type, season and episode. Without parameters every
+// shared file is listed.
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var q *Query
+	if params.Has("episode") {
+		episode, _ := strconv.Atoi(params.Get("episode"))
+		season, _ := strconv.Atoi(params.Get("season"))
+		q = &Query{
+			MediaID:   params.Get("media_id"),
+			Provider:  params.Get("provider"),
+			Title:     params.Get("title"),
+			MediaType: params.Get("type"),
+			Season:    season,
+			Episode:   episode,
+		}
+	}
+
+	files, err := s.files()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	matching := []File{}
+	for _, f := range files {
+		if q == nil || q.Matches(f) {
+			matching = append(matching, f)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(matching)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, id string) {
+	var download database.Download
+	err := s.db.Where("id = ? AND status = ?", id, "completed").First(&download).Error
+	if err != nil || download.FilePath == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open(download.FilePath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	// ServeContent answers Range requests, so interrupted pulls resume
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
+
+// files returns the completed downloads that are still on disk
+func (s *Server) files() ([]File, error) {
+	var downloads []database.Download
+	if err := s.db.Where("status = ?", "completed").Find(&downloads).Error; err != nil {
+		return nil, fmt.Errorf("failed to list downloads: %w", err)
+	}
+
+	var files []File
+	for _, d := range downloads {
+		if d.FilePath == "" {
+			continue
+		}
+		info, err := os.Stat(d.FilePath)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, File{
+			ID:         d.ID,
+			MediaID:    d.MediaID,
+			MediaTitle: d.MediaTitle,
+			MediaType:  d.MediaType,
+			Season:     d.Season,
+			Episode:    d.Episode,
+			Quality:    d.Quality,
+			Provider:   d.Provider,
+			Name:       filepath.Base(d.FilePath),
+			Size:       info.Size(),
+		})
+	}
+	return files, nil
+}
@@ -0,0 +1,133 @@
+// Package links recognizes AniList, MyAnimeList and provider URLs so greg can
+// jump straight to a title without a manual search.
+package links
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Source identifies where a link points to
+type Source string
+
+const (
+	SourceAniList  Source = "anilist"
+	SourceMAL      Source = "mal"
+	SourceProvider Source = "provider"
+)
+
+// Link is a parsed media link
+type Link struct {
+	Source    Source
+	TrackerID int    // AniList or MyAnimeList ID (tracker links only)
+	Provider  string // Provider name (provider links only)
+	MediaID   string // Provider media ID (provider links only)
+	Type      providers.MediaType
+	URL       string
+}
+
+// hianimeID matches hianime media IDs, e.g. "frieren-beyond-journeys-end-18542"
+var hianimeID = regexp.MustCompile(`^[a-z0-9-]+-\d+$`)
+
+// Parse recognizes a supported media link. Surrounding whitespace is ignored
+// and the text must be a single URL.
+func Parse(raw string) (*Link, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.ContainsAny(raw, " \n\t") {
+		return nil, false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var link *Link
+	switch {
+	case host == "anilist.co":
+		link = parseTrackerLink(SourceAniList, segments)
+	case host == "myanimelist.net":
+		link = parseTrackerLink(SourceMAL, segments)
+	case strings.HasPrefix(host, "hianime."):
+		link = parseHiAnimeLink(segments)
+	case host == "flixhq.to":
+		link = parseMovieTVLink("flixhq", segments)
+	case strings.HasPrefix(host, "sflix."):
+		link = parseMovieTVLink("sflix", segments)
+	}
+
+	if link == nil {
+		return nil, false
+	}
+	link.URL = raw
+	return link, true
+}
+
+// parseTrackerLink handles /anime/<id>/... and /manga/<id>/... paths
+func parseTrackerLink(source Source, segments []string) *Link {
+	if len(segments) < 2 {
+		return nil
+	}
+
+	var mediaType providers.MediaType
+	switch segments[0] {
+	case "anime":
+		mediaType = providers.MediaTypeAnime
+	case "manga":
+		mediaType = providers.MediaTypeManga
+	default:
+		return nil
+	}
+
+	id, err := strconv.Atoi(segments[1])
+	if err != nil || id <= 0 {
+		return nil
+	}
+
+	return &Link{Source: source, TrackerID: id, Type: mediaType}
+}
+
+// parseHiAnimeLink handles /<id> and /watch/<id> paths
+func parseHiAnimeLink(segments []string) *Link {
+	id := segments[0]
+	if id == "watch" && len(segments) > 1 {
+		id = segments[1]
+	}
+	if !hianimeID.MatchString(id) {
+		return nil
+	}
+
+	return &Link{Source: SourceProvider, Provider: "hianime", MediaID: id, Type: providers.MediaTypeAnime}
+}
+
+// parseMovieTVLink handles /movie/<slug> and /tv/<slug> paths; the provider
+// media ID keeps the type prefix, matching what the providers' search returns
+func parseMovieTVLink(provider string, segments []string) *Link {
+	if len(segments) < 2 || segments[1] == "" {
+		return nil
+	}
+
+	var mediaType providers.MediaType
+	switch segments[0] {
+	case "movie":
+		mediaType = providers.MediaTypeMovie
+	case "tv":
+		mediaType = providers.MediaTypeTV
+	default:
+		return nil
+	}
+
+	return &Link{
+		Source:   SourceProvider,
+		Provider: provider,
+		MediaID:  segments[0] + "/" + segments[1],
+		Type:     mediaType,
+	}
+}
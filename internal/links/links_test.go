@@ -0,0 +1,78 @@
+package links
+
+import (
+	"testing"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Link
+	}{
+		{
+			name: "AniList anime",
+			raw:  "https://anilist.co/anime/154587/Sousou-no-Frieren/",
+			want: Link{Source: SourceAniList, TrackerID: 154587, Type: providers.MediaTypeAnime},
+		},
+		{
+			name: "AniList manga",
+			raw:  "https://anilist.co/manga/30002",
+			want: Link{Source: SourceAniList, TrackerID: 30002, Type: providers.MediaTypeManga},
+		},
+		{
+			name: "MyAnimeList anime",
+			raw:  "  https://myanimelist.net/anime/52991/Sousou_no_Frieren\n",
+			want: Link{Source: SourceMAL, TrackerID: 52991, Type: providers.MediaTypeAnime},
+		},
+		{
+			name: "HiAnime watch page",
+			raw:  "https://hianime.to/watch/frieren-beyond-journeys-end-18542?ep=107257",
+			want: Link{Source: SourceProvider, Provider: "hianime", MediaID: "frieren-beyond-journeys-end-18542", Type: providers.MediaTypeAnime},
+		},
+		{
+			name: "HiAnime info page",
+			raw:  "https://hianime.to/frieren-beyond-journeys-end-18542",
+			want: Link{Source: SourceProvider, Provider: "hianime", MediaID: "frieren-beyond-journeys-end-18542", Type: providers.MediaTypeAnime},
+		},
+		{
+			name: "FlixHQ movie",
+			raw:  "https://flixhq.to/movie/watch-inception-19764",
+			want: Link{Source: SourceProvider, Provider: "flixhq", MediaID: "movie/watch-inception-19764", Type: providers.MediaTypeMovie},
+		},
+		{
+			name: "SFlix show",
+			raw:  "https://sflix.ps/tv/free-stranger-things-hd-39444",
+			want: Link{Source: SourceProvider, Provider: "sflix", MediaID: "tv/free-stranger-things-hd-39444", Type: providers.MediaTypeTV},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, ok := Parse(tt.raw)
+			require.True(t, ok)
+			tt.want.URL = link.URL
+			assert.Equal(t, tt.want, *link)
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"Frieren",
+		"https://anilist.co/user/someone",
+		"https://anilist.co/anime/abc",
+		"https://hianime.to/home",
+		"https://example.com/anime/1",
+		"check this out https://anilist.co/anime/1",
+		"ftp://anilist.co/anime/1",
+	} {
+		_, ok := Parse(raw)
+		assert.False(t, ok, raw)
+	}
+}
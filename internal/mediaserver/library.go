@@ -0,0 +1,156 @@
+package mediaserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RootID is the object ID of the downloads directory
+const RootID = "0"
+
+// videoTypes maps the video extensions served to their MIME types
+var videoTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".avi":  "video/x-msvideo",
+	".mov":  "video/quicktime",
+	".ts":   "video/mp2t",
+}
+
+// Object is a folder or video of the library
+type Object struct {
+	ID       string
+	ParentID string
+	Title    string
+	Path     string // Absolute path on disk
+	Dir      bool
+	Size     int64
+	ModTime  time.Time
+	MIME     string // Video MIME type, empty for folders
+}
+
+// Library is a directory tree of videos. Objects are identified by their
+// path below the root, so IDs stay valid while files come and go.
+type Library struct {
+	root string
+}
+
+// NewLibrary returns the library of the videos below root
+func NewLibrary(root string) *Library {
+	return &Library{root: root}
+}
+
+// objectID returns the ID of a path relative to the root
+func objectID(rel string) string {
+	if rel == "" || rel == "." {
+		return RootID
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(filepath.ToSlash(rel)))
+}
+
+// relPath resolves an object ID to its path relative to the root. IDs
+// pointing outside the root are rejected.
+func relPath(id string) (string, error) {
+	if id == RootID {
+		return ".", nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid object ID %q", id)
+	}
+	rel := filepath.FromSlash(string(data))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid object ID %q", id)
+	}
+	return rel, nil
+}
+
+// Object returns the folder or video with the given ID
+func (l *Library) Object(id string) (Object, error) {
+	rel, err := relPath(id)
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := os.Stat(filepath.Join(l.root, rel))
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	obj, ok := l.object(rel, info)
+	if !ok {
+		return Object{}, fmt.Errorf("%s is not a video", rel)
+	}
+	return obj, nil
+}
+
+// object describes a path of the library, or reports false for files that
+// aren't served
+func (l *Library) object(rel string, info os.FileInfo) (Object, bool) {
+	obj := Object{
+		ID:      objectID(rel),
+		Path:    filepath.Join(l.root, rel),
+		Dir:     info.IsDir(),
+		ModTime: info.ModTime(),
+	}
+	if rel == "." {
+		obj.ParentID = "-1"
+		obj.Title = filepath.Base(l.root)
+	} else {
+		obj.ParentID = objectID(filepath.Dir(rel))
+		obj.Title = info.Name()
+	}
+	if obj.Dir {
+		return obj, true
+	}
+
+	ext := strings.ToLower(filepath.Ext(info.Name()))
+	mime, ok := videoTypes[ext]
+	if !ok {
+		return Object{}, false
+	}
+	obj.Title = strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
+	obj.Size = info.Size()
+	obj.MIME = mime
+	return obj, true
+}
+
+// Children returns the folders and videos in a folder, folders first and
+// then by name. Hidden files and partial downloads are left out.
+func (l *Library) Children(id string) ([]Object, error) {
+	rel, err := relPath(id)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(l.root, rel))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", rel, err)
+	}
+
+	var children []Object
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".part") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if obj, ok := l.object(filepath.Join(rel, name), info); ok {
+			children = append(children, obj)
+		}
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		if children[i].Dir != children[j].Dir {
+			return children[i].Dir
+		}
+		return strings.ToLower(children[i].Title) < strings.ToLower(children[j].Title)
+	})
+	return children, nil
+}
@@ -0,0 +1,203 @@
+package mediaserver
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLibrary(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		"anime/Frieren/Frieren - 01.mkv":      "mkv data",
+		"anime/Frieren/Frieren - 02.mp4":      "mp4 data",
+		"anime/Frieren/Frieren - 03.mkv.part": "partial",
+		"anime/Frieren/.hidden.mkv":           "hidden",
+		"anime/Frieren/notes.txt":             "not a video",
+		"movies/Akira (1988).mp4":             "movie",
+	} {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	return root
+}
+
+func TestLibrary(t *testing.T) {
+	library := NewLibrary(newTestLibrary(t))
+
+	root, err := library.Children(RootID)
+	require.NoError(t, err)
+	require.Len(t, root, 2)
+	assert.Equal(t, "anime", root[0].Title)
+	assert.True(t, root[0].Dir)
+	assert.Equal(t, RootID, root[0].ParentID)
+
+	anime, err := library.Children(root[0].ID)
+	require.NoError(t, err)
+	require.Len(t, anime, 1)
+
+	episodes, err := library.Children(anime[0].ID)
+	require.NoError(t, err)
+	require.Len(t, episodes, 2, "partial, hidden and non-video files are left out")
+	assert.Equal(t, "Frieren - 01", episodes[0].Title)
+	assert.Equal(t, "video/x-matroska", episodes[0].MIME)
+	assert.Equal(t, int64(8), episodes[0].Size)
+	assert.Equal(t, anime[0].ID, episodes[0].ParentID)
+
+	obj, err := library.Object(episodes[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, episodes[1].Path, obj.Path)
+}
+
+func TestLibraryRejectsPathsOutsideRoot(t *testing.T) {
+	library := NewLibrary(newTestLibrary(t))
+
+	_, err := library.Object(objectID("../../etc/passwd"))
+	assert.Error(t, err)
+	_, err = library.Children(objectID("/etc"))
+	assert.Error(t, err)
+	_, err = library.Object("not base64!")
+	assert.Error(t, err)
+}
+
+func TestParseTimeSeek(t *testing.T) {
+	assert.Equal(t, 123.5, parseTimeSeek("npt=123.5-"))
+	assert.Equal(t, 3723.0, parseTimeSeek("npt=01:02:03-"))
+	assert.Equal(t, 0.0, parseTimeSeek("npt=0-"))
+	assert.Equal(t, 0.0, parseTimeSeek("bytes=0-"))
+}
+
+func TestTranscodeArgs(t *testing.T) {
+	args := transcodeArgs("/videos/a.mkv", 90)
+	assert.Equal(t, []string{"-ss", "90.000", "-i", "/videos/a.mkv"}, args[4:8])
+	assert.Equal(t, "pipe:1", args[len(args)-1])
+	assert.NotContains(t, transcodeArgs("/videos/a.mkv", 0), "-ss")
+}
+
+func browse(t *testing.T, server *httptest.Server, objectID, flag string) (string, int) {
+	t.Helper()
+	body := `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+		`<u:Browse xmlns:u="urn:schemas-upnp-org:service:ContentDirectory:1"><ObjectID>` + objectID + `</ObjectID>` +
+		`<BrowseFlag>` + flag + `</BrowseFlag><Filter>*</Filter><StartingIndex>0</StartingIndex><RequestedCount>0</RequestedCount>` +
+		`<SortCriteria></SortCriteria></u:Browse></s:Body></s:Envelope>`
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/control/ContentDirectory", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("SOAPACTION", `"urn:schemas-upnp-org:service:ContentDirectory:1#Browse"`)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var envelope struct {
+		Body struct {
+			Response struct {
+				Result         string `xml:"Result"`
+				NumberReturned int    `xml:"NumberReturned"`
+			} `xml:"BrowseResponse"`
+		} `xml:"Body"`
+	}
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if resp.StatusCode != http.StatusOK {
+		return string(data), -1
+	}
+	require.NoError(t, xml.Unmarshal(data, &envelope))
+	return envelope.Body.Response.Result, envelope.Body.Response.NumberReturned
+}
+
+func TestContentDirectoryBrowse(t *testing.T) {
+	root := newTestLibrary(t)
+	server := httptest.NewServer(NewServer(root, "greg", TranscodeAuto, "/usr/bin/ffmpeg", nil))
+	defer server.Close()
+
+	didl, n := browse(t, server, RootID, "BrowseDirectChildren")
+	assert.Equal(t, 2, n)
+	assert.Contains(t, didl, `<dc:title>anime</dc:title><upnp:class>object.container.storageFolder</upnp:class>`)
+	assert.Contains(t, didl, `childCount="1"`)
+
+	episodes := objectID("anime/Frieren")
+	didl, n = browse(t, server, episodes, "BrowseDirectChildren")
+	assert.Equal(t, 2, n)
+	mkv := objectID("anime/Frieren/Frieren - 01.mkv")
+	mp4 := objectID("anime/Frieren/Frieren - 02.mp4")
+	assert.Contains(t, didl, server.URL+"/transcode/"+mkv)
+	assert.Less(t, strings.Index(didl, "/transcode/"+mkv), strings.Index(didl, "/media/"+mkv), "transcoded stream comes first")
+	assert.Contains(t, didl, server.URL+"/media/"+mp4)
+	assert.NotContains(t, didl, "/transcode/"+mp4, "MP4 plays as is")
+
+	didl, n = browse(t, server, mkv, "BrowseMetadata")
+	assert.Equal(t, 1, n)
+	assert.Contains(t, didl, `<dc:title>Frieren - 01</dc:title>`)
+
+	fault, n := browse(t, server, objectID("missing"), "BrowseMetadata")
+	assert.Equal(t, -1, n)
+	assert.Contains(t, fault, "<errorCode>701</errorCode>")
+}
+
+func TestServeMediaAndPages(t *testing.T) {
+	root := newTestLibrary(t)
+	server := httptest.NewServer(NewServer(root, "Living room", TranscodeNever, "", nil))
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := get("/media/" + objectID("movies/Akira (1988).mp4"))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "movie", body)
+
+	status, _ = get("/transcode/" + objectID("movies/Akira (1988).mp4"))
+	assert.Equal(t, http.StatusNotFound, status, "transcoding is off")
+	status, _ = get("/media/" + objectID("anime/Frieren/notes.txt"))
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = get("/description.xml")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "<friendlyName>Living room</friendlyName>")
+	assert.Contains(t, body, "<UDN>uuid:")
+
+	status, body = get("/")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<a href="/browse/`+objectID("anime")+`">anime</a>`)
+	status, body = get("/browse/" + objectID("anime/Frieren"))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "Frieren - 01")
+	assert.NotContains(t, body, "transcoded")
+}
+
+func TestSSDP(t *testing.T) {
+	udn := "uuid:1234"
+	search := "M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 2\r\nST: urn:schemas-upnp-org:device:MediaServer:1\r\n\r\n"
+
+	st, ok := parseSearch([]byte(search))
+	require.True(t, ok)
+	responses := searchResponses(udn, st, "http://192.168.1.10:8200/description.xml")
+	require.Len(t, responses, 1)
+	assert.Contains(t, responses[0], "LOCATION: http://192.168.1.10:8200/description.xml\r\n")
+	assert.Contains(t, responses[0], "USN: uuid:1234::urn:schemas-upnp-org:device:MediaServer:1\r\n")
+
+	assert.Len(t, searchResponses(udn, "ssdp:all", "http://x/description.xml"), 5)
+	assert.Empty(t, searchResponses(udn, "urn:schemas-upnp-org:device:MediaRenderer:1", "http://x/description.xml"))
+
+	_, ok = parseSearch([]byte(notifications(udn, "ssdp:alive", "http://x/description.xml")[0]))
+	assert.False(t, ok, "announcements of others aren't searches")
+
+	byebye := notifications(udn, "ssdp:byebye", "")
+	assert.Len(t, byebye, 5)
+	assert.NotContains(t, byebye[0], "LOCATION")
+}
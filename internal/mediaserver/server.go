@@ -0,0 +1,252 @@
+// Package mediaserver serves the downloads directory to smart TVs and
+// browsers: a DLNA/UPnP media server (SSDP discovery and a ContentDirectory
+// service) plus plain HTTP pages, with videos TVs can't play transcoded on
+// the fly by ffmpeg.
+package mediaserver
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Server is a media server of a downloads directory
+type Server struct {
+	library   *Library
+	name      string
+	udn       string
+	transcode TranscodeMode
+	ffmpeg    string // Empty when ffmpeg isn't installed
+	logger    *slog.Logger
+
+	server    *http.Server
+	listener  net.Listener
+	announcer *announcer
+}
+
+// NewServer creates a media server of the videos below root, announced as
+// name. Videos are transcoded with the ffmpeg binary as mode says; without
+// ffmpeg they are only served as they are.
+func NewServer(root, name string, mode TranscodeMode, ffmpeg string, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	host, _ := os.Hostname()
+	return &Server{
+		library: NewLibrary(root),
+		name:    name,
+		// Stable across restarts so TVs keep the server in their source list
+		udn:       "uuid:" + uuid.NewSHA1(uuid.NameSpaceURL, []byte("greg-library:"+host+":"+root)).String(),
+		transcode: mode,
+		ffmpeg:    ffmpeg,
+		logger:    logger,
+	}
+}
+
+// Listen starts serving on addr (e.g. ":8200") in the background
+func (s *Server) Listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = s.server.Serve(listener) }()
+	return nil
+}
+
+// Advertise announces the server to DLNA clients over SSDP until it is
+// closed
+func (s *Server) Advertise() error {
+	if s.listener == nil {
+		return fmt.Errorf("media server is not listening")
+	}
+	a, err := announce(s.udn, s.listener.Addr().(*net.TCPAddr).Port)
+	if err != nil {
+		return fmt.Errorf("failed to announce media server: %w", err)
+	}
+	s.announcer = a
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Close withdraws the announcement and stops serving
+func (s *Server) Close() error {
+	if s.announcer != nil {
+		_ = s.announcer.Close()
+	}
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		// Streams still running are cut off
+		return s.server.Close()
+	}
+	return nil
+}
+
+// canTranscode reports whether videos can be offered transcoded
+func (s *Server) canTranscode() bool {
+	return s.ffmpeg != "" && s.transcode != TranscodeNever
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case path == "/description.xml":
+		w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+		fmt.Fprintf(w, deviceDescription, escapeXML(s.name), s.udn)
+	case path == "/ContentDirectory.xml":
+		w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+		_, _ = w.Write([]byte(contentDirectorySCPD))
+	case path == "/ConnectionManager.xml":
+		w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+		_, _ = w.Write([]byte(connectionManagerSCPD))
+	case path == "/control/ContentDirectory" && r.Method == http.MethodPost:
+		s.handleContentDirectory(w, r)
+	case path == "/control/ConnectionManager" && r.Method == http.MethodPost:
+		s.handleConnectionManager(w, r)
+	case strings.HasPrefix(path, "/event/"):
+		// Eventing isn't supported; the library is read on every browse
+		http.Error(w, "eventing not supported", http.StatusNotImplemented)
+	case strings.HasPrefix(path, "/media/"):
+		s.serveMedia(w, r, strings.TrimPrefix(path, "/media/"))
+	case strings.HasPrefix(path, "/transcode/"):
+		s.serveTranscode(w, r, strings.TrimPrefix(path, "/transcode/"))
+	case path == "/":
+		s.serveIndex(w, r, RootID)
+	case strings.HasPrefix(path, "/browse/"):
+		s.serveIndex(w, r, strings.TrimPrefix(path, "/browse/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// video returns the video with the given ID
+func (s *Server) video(id string) (Object, bool) {
+	obj, err := s.library.Object(id)
+	if err != nil || obj.Dir {
+		return Object{}, false
+	}
+	return obj, true
+}
+
+// serveMedia serves a video as it is, with byte range seeking
+func (s *Server) serveMedia(w http.ResponseWriter, r *http.Request, id string) {
+	obj, ok := s.video(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(obj.Path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	w.Header().Set("Content-Type", obj.MIME)
+	w.Header().Set("transferMode.dlna.org", "Streaming")
+	w.Header().Set("contentFeatures.dlna.org", "DLNA.ORG_OP=01;DLNA.ORG_CI=0")
+	http.ServeContent(w, r, "", obj.ModTime, f)
+}
+
+// serveTranscode streams a video transcoded by ffmpeg. TVs seek by asking
+// for a start time (TimeSeekRange.dlna.org), browsers with ?t=<seconds>.
+func (s *Server) serveTranscode(w http.ResponseWriter, r *http.Request, id string) {
+	obj, ok := s.video(id)
+	if !ok || !s.canTranscode() {
+		http.NotFound(w, r)
+		return
+	}
+
+	start := parseTimeSeek(r.Header.Get("TimeSeekRange.dlna.org"))
+	if t, err := strconv.ParseFloat(r.URL.Query().Get("t"), 64); err == nil && t > 0 {
+		start = t
+	}
+
+	w.Header().Set("Content-Type", transcodedMIME)
+	w.Header().Set("transferMode.dlna.org", "Streaming")
+	w.Header().Set("contentFeatures.dlna.org", "DLNA.ORG_OP=10;DLNA.ORG_CI=1")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	// The request context stops ffmpeg when the TV hangs up
+	cmd := exec.CommandContext(r.Context(), s.ffmpeg, transcodeArgs(obj.Path, start)...)
+	cmd.Stdout = w
+	s.logger.Info("transcoding for playback", "file", obj.Path, "start", start, "client", r.RemoteAddr)
+	if err := cmd.Run(); err != nil && r.Context().Err() == nil {
+		s.logger.Warn("transcode failed", "file", obj.Path, "error", err)
+	}
+}
+
+// indexTemplate is the HTTP page of a folder
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Name}} - {{.Folder.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; }
+li { margin: .4rem 0; }
+small a { margin-left: .5rem; }
+</style>
+</head>
+<body>
+<h1>{{.Folder.Title}}</h1>
+{{if ne .Folder.ID "0"}}<p><a href="/browse/{{.Folder.ParentID}}">&larr; Back</a></p>{{end}}
+<ul>
+{{range .Children}}{{if .Dir}}<li>&#128193; <a href="/browse/{{.ID}}">{{.Title}}</a></li>
+{{else}}<li><a href="/media/{{.ID}}">{{.Title}}</a>{{if $.Transcode}} <small><a href="/transcode/{{.ID}}">transcoded</a></small>{{end}}</li>
+{{end}}{{else}}<li>No videos</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// serveIndex serves the HTML page of a folder
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request, id string) {
+	folder, err := s.library.Object(id)
+	if err != nil || !folder.Dir {
+		http.NotFound(w, r)
+		return
+	}
+	children, err := s.library.Children(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if id == RootID {
+		folder.Title = s.name
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = indexTemplate.Execute(w, struct {
+		Name      string
+		Folder    Object
+		Children  []Object
+		Transcode bool
+	}{s.name, folder, children, s.canTranscode()})
+}
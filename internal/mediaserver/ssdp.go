@@ -0,0 +1,170 @@
+package mediaserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ssdpMaxAge is how long TVs may cache the announcement, in seconds
+	ssdpMaxAge = 1800
+
+	// ssdpNotifyInterval is how often the server is announced, well within
+	// ssdpMaxAge
+	ssdpNotifyInterval = 10 * time.Minute
+
+	ssdpServer = "Linux/1.0 UPnP/1.0 greg/1.0"
+)
+
+var ssdpAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+// ssdpTargets returns the search targets a media server answers to
+func ssdpTargets(udn string) []string {
+	return []string{"upnp:rootdevice", udn, mediaServerType, contentDirectoryType, connectionManagerType}
+}
+
+// usn returns the unique service name of a target
+func usn(udn, target string) string {
+	if target == udn {
+		return udn
+	}
+	return udn + "::" + target
+}
+
+// searchResponses returns the replies to an M-SEARCH for st
+func searchResponses(udn, st, location string) []string {
+	var responses []string
+	for _, target := range ssdpTargets(udn) {
+		if st != "ssdp:all" && !strings.EqualFold(st, target) {
+			continue
+		}
+		responses = append(responses, fmt.Sprintf("HTTP/1.1 200 OK\r\n"+
+			"CACHE-CONTROL: max-age=%d\r\n"+
+			"DATE: %s\r\n"+
+			"EXT:\r\n"+
+			"LOCATION: %s\r\n"+
+			"SERVER: %s\r\n"+
+			"ST: %s\r\n"+
+			"USN: %s\r\n"+
+			"Content-Length: 0\r\n\r\n",
+			ssdpMaxAge, time.Now().UTC().Format(http.TimeFormat), location, ssdpServer, target, usn(udn, target)))
+	}
+	return responses
+}
+
+// notifications returns the NOTIFY messages announcing (nts "ssdp:alive")
+// or withdrawing (nts "ssdp:byebye") the server
+func notifications(udn, nts, location string) []string {
+	var messages []string
+	for _, target := range ssdpTargets(udn) {
+		msg := "NOTIFY * HTTP/1.1\r\n" +
+			"HOST: 239.255.255.250:1900\r\n" +
+			"NT: " + target + "\r\n" +
+			"NTS: " + nts + "\r\n" +
+			"USN: " + usn(udn, target) + "\r\n"
+		if nts == "ssdp:alive" {
+			msg += fmt.Sprintf("CACHE-CONTROL: max-age=%d\r\nLOCATION: %s\r\nSERVER: %s\r\n", ssdpMaxAge, location, ssdpServer)
+		}
+		messages = append(messages, msg+"\r\n")
+	}
+	return messages
+}
+
+// parseSearch returns the search target of an M-SEARCH request, or reports
+// false for other SSDP traffic
+func parseSearch(packet []byte) (string, bool) {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(packet)))
+	if err != nil || req.Method != "M-SEARCH" {
+		return "", false
+	}
+	if strings.Trim(req.Header.Get("MAN"), `"`) != "ssdp:discover" {
+		return "", false
+	}
+	st := req.Header.Get("ST")
+	return st, st != ""
+}
+
+// announcer answers SSDP searches and periodically announces the server
+type announcer struct {
+	conn *net.UDPConn
+	udn  string
+	port int
+	done chan struct{}
+}
+
+// announce makes the server discoverable by TVs until closed
+func announce(udn string, port int) (*announcer, error) {
+	conn, err := net.ListenMulticastUDP("udp4", nil, ssdpAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join SSDP group: %w", err)
+	}
+	a := &announcer{conn: conn, udn: udn, port: port, done: make(chan struct{})}
+	go a.serve()
+	go a.notifyLoop()
+	return a, nil
+}
+
+// location returns the description URL as reached from addr
+func (a *announcer) location(addr *net.UDPAddr) string {
+	ip := localIPFor(addr)
+	return fmt.Sprintf("http://%s/description.xml", net.JoinHostPort(ip, fmt.Sprint(a.port)))
+}
+
+// localIPFor returns the local address packets to addr leave from
+func localIPFor(addr *net.UDPAddr) string {
+	conn, err := net.DialUDP("udp4", nil, addr)
+	if err != nil {
+		return "127.0.0.1"
+	}
+	defer func() { _ = conn.Close() }()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
+
+func (a *announcer) serve() {
+	buf := make([]byte, 4096)
+	for {
+		n, src, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		st, ok := parseSearch(buf[:n])
+		if !ok {
+			continue
+		}
+		for _, response := range searchResponses(a.udn, st, a.location(src)) {
+			_, _ = a.conn.WriteToUDP([]byte(response), src)
+		}
+	}
+}
+
+func (a *announcer) notify(nts string) {
+	for _, msg := range notifications(a.udn, nts, a.location(ssdpAddr)) {
+		_, _ = a.conn.WriteToUDP([]byte(msg), ssdpAddr)
+	}
+}
+
+func (a *announcer) notifyLoop() {
+	a.notify("ssdp:alive")
+	ticker := time.NewTicker(ssdpNotifyInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.notify("ssdp:alive")
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// Close withdraws the announcement
+func (a *announcer) Close() error {
+	close(a.done)
+	a.notify("ssdp:byebye")
+	return a.conn.Close()
+}
@@ -0,0 +1,70 @@
+package mediaserver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TranscodeMode says which videos are offered transcoded
+type TranscodeMode string
+
+const (
+	// TranscodeAuto transcodes containers TVs commonly can't play (all but MP4)
+	TranscodeAuto TranscodeMode = "auto"
+	// TranscodeAlways transcodes every video
+	TranscodeAlways TranscodeMode = "always"
+	// TranscodeNever serves the files as they are
+	TranscodeNever TranscodeMode = "never"
+)
+
+// transcodedMIME is the MIME type of transcoded streams
+const transcodedMIME = "video/mpeg"
+
+// wantsTranscode reports whether a video is offered transcoded first
+func (m TranscodeMode) wantsTranscode(obj Object) bool {
+	switch m {
+	case TranscodeAlways:
+		return true
+	case TranscodeNever:
+		return false
+	}
+	return obj.MIME != "video/mp4"
+}
+
+// transcodeArgs returns the ffmpeg arguments that transcode a video to an
+// H.264/AAC MPEG transport stream on stdout, which virtually every TV plays
+// and which can be streamed without knowing its length. start skips into
+// the video, in seconds.
+func transcodeArgs(path string, start float64) []string {
+	args := []string{"-hide_banner", "-loglevel", "error", "-nostdin"}
+	if start > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(start, 'f', 3, 64))
+	}
+	return append(args,
+		"-i", path,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "21", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-ac", "2", "-b:a", "192k",
+		"-f", "mpegts", "pipe:1",
+	)
+}
+
+// parseTimeSeek reads the start of a DLNA time seek header such as
+// "npt=123.5-" or "npt=00:02:03.5-", in seconds
+func parseTimeSeek(header string) float64 {
+	value, ok := strings.CutPrefix(strings.TrimSpace(header), "npt=")
+	if !ok {
+		return 0
+	}
+	start, _, _ := strings.Cut(value, "-")
+
+	seconds := 0.0
+	for _, part := range strings.Split(start, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds
+}
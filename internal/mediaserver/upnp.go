@@ -0,0 +1,318 @@
+package mediaserver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentDirectoryType  = "urn:schemas-upnp-org:service:ContentDirectory:1"
+	connectionManagerType = "urn:schemas-upnp-org:service:ConnectionManager:1"
+	mediaServerType       = "urn:schemas-upnp-org:device:MediaServer:1"
+)
+
+// deviceDescription is served at /description.xml; %s are the friendly
+// name and the UDN
+const deviceDescription = `<?xml version="1.0" encoding="utf-8"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0" xmlns:dlna="urn:schemas-dlna-org:device-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <device>
+    <deviceType>` + mediaServerType + `</deviceType>
+    <friendlyName>%s</friendlyName>
+    <manufacturer>greg</manufacturer>
+    <manufacturerURL>https://github.com/justchokingaround/greg</manufacturerURL>
+    <modelName>greg library</modelName>
+    <modelNumber>1</modelNumber>
+    <UDN>%s</UDN>
+    <dlna:X_DLNADOC>DMS-1.50</dlna:X_DLNADOC>
+    <serviceList>
+      <service>
+        <serviceType>` + contentDirectoryType + `</serviceType>
+        <serviceId>urn:upnp-org:serviceId:ContentDirectory</serviceId>
+        <SCPDURL>/ContentDirectory.xml</SCPDURL>
+        <controlURL>/control/ContentDirectory</controlURL>
+        <eventSubURL>/event/ContentDirectory</eventSubURL>
+      </service>
+      <service>
+        <serviceType>` + connectionManagerType + `</serviceType>
+        <serviceId>urn:upnp-org:serviceId:ConnectionManager</serviceId>
+        <SCPDURL>/ConnectionManager.xml</SCPDURL>
+        <controlURL>/control/ConnectionManager</controlURL>
+        <eventSubURL>/event/ConnectionManager</eventSubURL>
+      </service>
+    </serviceList>
+  </device>
+</root>`
+
+// contentDirectorySCPD describes the ContentDirectory actions greg answers
+const contentDirectorySCPD = `<?xml version="1.0" encoding="utf-8"?>
+<scpd xmlns="urn:schemas-upnp-org:service-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <actionList>
+    <action>
+      <name>Browse</name>
+      <argumentList>
+        <argument><name>ObjectID</name><direction>in</direction><relatedStateVariable>A_ARG_TYPE_ObjectID</relatedStateVariable></argument>
+        <argument><name>BrowseFlag</name><direction>in</direction><relatedStateVariable>A_ARG_TYPE_BrowseFlag</relatedStateVariable></argument>
+        <argument><name>Filter</name><direction>in</direction><relatedStateVariable>A_ARG_TYPE_Filter</relatedStateVariable></argument>
+        <argument><name>StartingIndex</name><direction>in</direction><relatedStateVariable>A_ARG_TYPE_Index</relatedStateVariable></argument>
+        <argument><name>RequestedCount</name><direction>in</direction><relatedStateVariable>A_ARG_TYPE_Count</relatedStateVariable></argument>
+        <argument><name>SortCriteria</name><direction>in</direction><relatedStateVariable>A_ARG_TYPE_SortCriteria</relatedStateVariable></argument>
+        <argument><name>Result</name><direction>out</direction><relatedStateVariable>A_ARG_TYPE_Result</relatedStateVariable></argument>
+        <argument><name>NumberReturned</name><direction>out</direction><relatedStateVariable>A_ARG_TYPE_Count</relatedStateVariable></argument>
+        <argument><name>TotalMatches</name><direction>out</direction><relatedStateVariable>A_ARG_TYPE_Count</relatedStateVariable></argument>
+        <argument><name>UpdateID</name><direction>out</direction><relatedStateVariable>A_ARG_TYPE_UpdateID</relatedStateVariable></argument>
+      </argumentList>
+    </action>
+    <action>
+      <name>GetSystemUpdateID</name>
+      <argumentList>
+        <argument><name>Id</name><direction>out</direction><relatedStateVariable>SystemUpdateID</relatedStateVariable></argument>
+      </argumentList>
+    </action>
+    <action>
+      <name>GetSearchCapabilities</name>
+      <argumentList>
+        <argument><name>SearchCaps</name><direction>out</direction><relatedStateVariable>SearchCapabilities</relatedStateVariable></argument>
+      </argumentList>
+    </action>
+    <action>
+      <name>GetSortCapabilities</name>
+      <argumentList>
+        <argument><name>SortCaps</name><direction>out</direction><relatedStateVariable>SortCapabilities</relatedStateVariable></argument>
+      </argumentList>
+    </action>
+  </actionList>
+  <serviceStateTable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_ObjectID</name><dataType>string</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_BrowseFlag</name><dataType>string</dataType>
+      <allowedValueList><allowedValue>BrowseMetadata</allowedValue><allowedValue>BrowseDirectChildren</allowedValue></allowedValueList>
+    </stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_Filter</name><dataType>string</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_Index</name><dataType>ui4</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_Count</name><dataType>ui4</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_SortCriteria</name><dataType>string</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_Result</name><dataType>string</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>A_ARG_TYPE_UpdateID</name><dataType>ui4</dataType></stateVariable>
+    <stateVariable sendEvents="yes"><name>SystemUpdateID</name><dataType>ui4</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>SearchCapabilities</name><dataType>string</dataType></stateVariable>
+    <stateVariable sendEvents="no"><name>SortCapabilities</name><dataType>string</dataType></stateVariable>
+  </serviceStateTable>
+</scpd>`
+
+// connectionManagerSCPD describes the ConnectionManager actions greg answers
+const connectionManagerSCPD = `<?xml version="1.0" encoding="utf-8"?>
+<scpd xmlns="urn:schemas-upnp-org:service-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <actionList>
+    <action>
+      <name>GetProtocolInfo</name>
+      <argumentList>
+        <argument><name>Source</name><direction>out</direction><relatedStateVariable>SourceProtocolInfo</relatedStateVariable></argument>
+        <argument><name>Sink</name><direction>out</direction><relatedStateVariable>SinkProtocolInfo</relatedStateVariable></argument>
+      </argumentList>
+    </action>
+  </actionList>
+  <serviceStateTable>
+    <stateVariable sendEvents="yes"><name>SourceProtocolInfo</name><dataType>string</dataType></stateVariable>
+    <stateVariable sendEvents="yes"><name>SinkProtocolInfo</name><dataType>string</dataType></stateVariable>
+  </serviceStateTable>
+</scpd>`
+
+// soapFault codes of UPnP errors
+const (
+	faultInvalidAction = 401
+	faultInvalidArgs   = 402
+	faultNoSuchObject  = 701
+)
+
+// soapArg is an output argument of a SOAP action, in order
+type soapArg struct {
+	name  string
+	value string
+}
+
+// browseArgs are the input arguments of ContentDirectory Browse
+type browseArgs struct {
+	ObjectID       string `xml:"ObjectID"`
+	BrowseFlag     string `xml:"BrowseFlag"`
+	StartingIndex  int    `xml:"StartingIndex"`
+	RequestedCount int    `xml:"RequestedCount"`
+}
+
+// soapAction returns the action named in a SOAPACTION header, e.g.
+// "urn:schemas-upnp-org:service:ContentDirectory:1#Browse"
+func soapAction(header string) string {
+	_, action, _ := strings.Cut(strings.Trim(header, `"`), "#")
+	return action
+}
+
+// decodeSOAP decodes the action element of a SOAP request into v
+func decodeSOAP(r io.Reader, v interface{}) error {
+	var envelope struct {
+		Body struct {
+			Action struct {
+				Inner []byte `xml:",innerxml"`
+			} `xml:",any"`
+		} `xml:"Body"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(r, 1<<20)).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode SOAP request: %w", err)
+	}
+	inner := "<action>" + string(envelope.Body.Action.Inner) + "</action>"
+	return xml.Unmarshal([]byte(inner), v)
+}
+
+// writeSOAP writes the response of a SOAP action
+func writeSOAP(w http.ResponseWriter, service, action string, args []soapArg) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&b, `<u:%sResponse xmlns:u="%s">`, action, service)
+	for _, arg := range args {
+		fmt.Fprintf(&b, "<%s>", arg.name)
+		_ = xml.EscapeText(&b, []byte(arg.value))
+		fmt.Fprintf(&b, "</%s>", arg.name)
+	}
+	fmt.Fprintf(&b, `</u:%sResponse></s:Body></s:Envelope>`, action)
+
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.Header().Set("EXT", "")
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeSOAPFault writes a UPnP error
+func writeSOAPFault(w http.ResponseWriter, code int, description string) {
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusInternalServerError)
+	var desc strings.Builder
+	_ = xml.EscapeText(&desc, []byte(description))
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body><s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode><errorDescription>%s</errorDescription></UPnPError></detail></s:Fault></s:Body></s:Envelope>`, code, desc.String())
+}
+
+// didl renders objects as a DIDL-Lite document. baseURL is where the TV
+// reached the server, e.g. "http://192.168.1.10:8200".
+func (s *Server) didl(objects []Object, childCounts map[string]int, baseURL string) string {
+	var b strings.Builder
+	b.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/" xmlns:dlna="urn:schemas-dlna-org:metadata-1-0/">`)
+	for _, obj := range objects {
+		title := escapeXML(obj.Title)
+		if obj.Dir {
+			fmt.Fprintf(&b, `<container id="%s" parentID="%s" restricted="1" childCount="%d"><dc:title>%s</dc:title><upnp:class>object.container.storageFolder</upnp:class></container>`,
+				obj.ID, obj.ParentID, childCounts[obj.ID], title)
+			continue
+		}
+
+		fmt.Fprintf(&b, `<item id="%s" parentID="%s" restricted="1"><dc:title>%s</dc:title><upnp:class>object.item.videoItem</upnp:class>`,
+			obj.ID, obj.ParentID, title)
+		original := fmt.Sprintf(`<res protocolInfo="http-get:*:%s:DLNA.ORG_OP=01;DLNA.ORG_CI=0" size="%d">%s/media/%s</res>`,
+			obj.MIME, obj.Size, baseURL, obj.ID)
+		// TVs play the first resource they support, so the transcoded one
+		// comes first when the original likely isn't playable
+		if s.canTranscode() && s.transcode.wantsTranscode(obj) {
+			fmt.Fprintf(&b, `<res protocolInfo="http-get:*:%s:DLNA.ORG_OP=10;DLNA.ORG_CI=1">%s/transcode/%s</res>`,
+				transcodedMIME, baseURL, obj.ID)
+		}
+		b.WriteString(original)
+		b.WriteString(`</item>`)
+	}
+	b.WriteString(`</DIDL-Lite>`)
+	return b.String()
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// handleContentDirectory answers ContentDirectory actions
+func (s *Server) handleContentDirectory(w http.ResponseWriter, r *http.Request) {
+	action := soapAction(r.Header.Get("SOAPACTION"))
+	switch action {
+	case "GetSystemUpdateID":
+		writeSOAP(w, contentDirectoryType, action, []soapArg{{"Id", "1"}})
+	case "GetSearchCapabilities":
+		writeSOAP(w, contentDirectoryType, action, []soapArg{{"SearchCaps", ""}})
+	case "GetSortCapabilities":
+		writeSOAP(w, contentDirectoryType, action, []soapArg{{"SortCaps", "dc:title"}})
+	case "Browse":
+		var args browseArgs
+		if err := decodeSOAP(r.Body, &args); err != nil {
+			writeSOAPFault(w, faultInvalidArgs, err.Error())
+			return
+		}
+		s.browse(w, r, args)
+	default:
+		writeSOAPFault(w, faultInvalidAction, "unsupported action "+action)
+	}
+}
+
+// browse answers a Browse action with an object or a page of its children
+func (s *Server) browse(w http.ResponseWriter, r *http.Request, args browseArgs) {
+	var objects []Object
+	total := 1
+	switch args.BrowseFlag {
+	case "BrowseMetadata":
+		obj, err := s.library.Object(args.ObjectID)
+		if err != nil {
+			writeSOAPFault(w, faultNoSuchObject, err.Error())
+			return
+		}
+		objects = []Object{obj}
+	case "BrowseDirectChildren":
+		children, err := s.library.Children(args.ObjectID)
+		if err != nil {
+			writeSOAPFault(w, faultNoSuchObject, err.Error())
+			return
+		}
+		total = len(children)
+		start := min(max(args.StartingIndex, 0), total)
+		end := total
+		if args.RequestedCount > 0 {
+			end = min(start+args.RequestedCount, total)
+		}
+		objects = children[start:end]
+	default:
+		writeSOAPFault(w, faultInvalidArgs, "invalid BrowseFlag "+args.BrowseFlag)
+		return
+	}
+
+	childCounts := make(map[string]int)
+	for _, obj := range objects {
+		if obj.Dir {
+			children, _ := s.library.Children(obj.ID)
+			childCounts[obj.ID] = len(children)
+		}
+	}
+
+	writeSOAP(w, contentDirectoryType, "Browse", []soapArg{
+		{"Result", s.didl(objects, childCounts, "http://"+r.Host)},
+		{"NumberReturned", strconv.Itoa(len(objects))},
+		{"TotalMatches", strconv.Itoa(total)},
+		{"UpdateID", "1"},
+	})
+}
+
+// handleConnectionManager answers ConnectionManager actions
+func (s *Server) handleConnectionManager(w http.ResponseWriter, r *http.Request) {
+	action := soapAction(r.Header.Get("SOAPACTION"))
+	if action != "GetProtocolInfo" {
+		writeSOAPFault(w, faultInvalidAction, "unsupported action "+action)
+		return
+	}
+
+	var sources []string
+	for _, mime := range []string{transcodedMIME, "video/mp4", "video/x-matroska", "video/webm", "video/x-msvideo", "video/quicktime", "video/mp2t"} {
+		sources = append(sources, "http-get:*:"+mime+":*")
+	}
+	writeSOAP(w, connectionManagerType, action, []soapArg{
+		{"Source", strings.Join(sources, ",")},
+		{"Sink", ""},
+	})
+}
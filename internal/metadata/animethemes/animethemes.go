@@ -0,0 +1,177 @@
+// Package animethemes fetches anime opening and ending songs from
+// AnimeThemes.moe, which links its entries to AniList IDs
+package animethemes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Theme is an opening or ending of an anime
+type Theme struct {
+	Slug     string // OP1, ED2, ...
+	Type     string // OP or ED
+	Song     string
+	Artists  []string
+	Episodes string // Episodes it plays in, e.g. "1-12, 14"; empty when unknown
+}
+
+// Artist joins the theme's artists for display
+func (t Theme) Artist() string {
+	return strings.Join(t.Artists, ", ")
+}
+
+// Client is a minimal AnimeThemes API client
+type Client struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewClient returns a client for the public AnimeThemes API
+func NewClient() *Client {
+	return &Client{
+		BaseURL: "https://api.animethemes.moe",
+		Client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// animeResponse is the part of an /anime response that is used
+type animeResponse struct {
+	Anime []struct {
+		Name   string `json:"name"`
+		Themes []struct {
+			Type     string `json:"type"`
+			Sequence *int   `json:"sequence"`
+			Slug     string `json:"slug"`
+			Song     *struct {
+				Title   string `json:"title"`
+				Artists []struct {
+					Name string `json:"name"`
+				} `json:"artists"`
+			} `json:"song"`
+			Entries []struct {
+				Episodes string `json:"episodes"`
+			} `json:"animethemeentries"`
+		} `json:"animethemes"`
+	} `json:"anime"`
+}
+
+// ByAniListID returns the themes of the anime with an AniList ID
+func (c *Client) ByAniListID(ctx context.Context, anilistID int) ([]Theme, error) {
+	query := url.Values{}
+	query.Set("filter[has]", "resources")
+	query.Set("filter[site]", "AniList")
+	query.Set("filter[external_id]", strconv.Itoa(anilistID))
+	return c.themes(ctx, query)
+}
+
+// Search returns the themes of the best match for an anime title
+func (c *Client) Search(ctx context.Context, title string) ([]Theme, error) {
+	query := url.Values{}
+	query.Set("q", title)
+	return c.themes(ctx, query)
+}
+
+// themes fetches /anime with query and returns the themes of the first match
+func (c *Client) themes(ctx context.Context, query url.Values) ([]Theme, error) {
+	query.Set("include", "animethemes.animethemeentries,animethemes.song.artists")
+	query.Set("page[size]", "1")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/anime?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("animethemes request returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result animeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode animethemes response: %w", err)
+	}
+	if len(result.Anime) == 0 {
+		return nil, nil
+	}
+
+	var themes []Theme
+	for _, t := range result.Anime[0].Themes {
+		if t.Song == nil || t.Song.Title == "" {
+			continue
+		}
+		theme := Theme{Slug: t.Slug, Type: t.Type, Song: t.Song.Title}
+		if theme.Slug == "" {
+			theme.Slug = t.Type
+			if t.Sequence != nil {
+				theme.Slug += strconv.Itoa(*t.Sequence)
+			}
+		}
+		for _, artist := range t.Song.Artists {
+			theme.Artists = append(theme.Artists, artist.Name)
+		}
+		var episodes []string
+		for _, entry := range t.Entries {
+			if entry.Episodes != "" {
+				episodes = append(episodes, entry.Episodes)
+			}
+		}
+		theme.Episodes = strings.Join(episodes, ", ")
+		themes = append(themes, theme)
+	}
+	return themes, nil
+}
+
+// Covers reports whether the theme plays in episode. Themes without
+// episode data cover every episode.
+func (t Theme) Covers(episode int) bool {
+	if strings.TrimSpace(t.Episodes) == "" {
+		return true
+	}
+	for _, part := range strings.Split(t.Episodes, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			continue
+		}
+		last := first
+		if isRange {
+			// "25-" runs to the end
+			if to = strings.TrimSpace(to); to == "" {
+				last = int(^uint(0) >> 1)
+			} else if last, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		if episode >= first && episode <= last {
+			return true
+		}
+	}
+	return false
+}
+
+// ForEpisode returns the themes playing in episode; the opening first
+func ForEpisode(themes []Theme, episode int) []Theme {
+	var matched []Theme
+	for _, theme := range themes {
+		if theme.Covers(episode) {
+			matched = append(matched, theme)
+		}
+	}
+	return matched
+}
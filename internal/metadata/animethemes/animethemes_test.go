@@ -0,0 +1,98 @@
+package animethemes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/anime", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Contains(t, query.Get("include"), "animethemes.song.artists")
+		if query.Get("filter[external_id]") == "404" {
+			fmt.Fprint(w, `{"anime":[]}`)
+			return
+		}
+		if query.Get("q") == "" {
+			assert.Equal(t, "AniList", query.Get("filter[site]"))
+			assert.Equal(t, "21", query.Get("filter[external_id]"))
+		}
+		fmt.Fprint(w, `{"anime":[{"name":"One Piece","animethemes":[
+			{"type":"OP","sequence":1,"slug":"OP1","song":{"title":"We Are!","artists":[{"name":"Hiroshi Kitadani"}]},
+			 "animethemeentries":[{"episodes":"1-47"},{"episodes":"1000"}]},
+			{"type":"ED","sequence":1,"slug":"","song":{"title":"memories","artists":[{"name":"Maki Otsuki"}]},
+			 "animethemeentries":[{"episodes":"1-30"}]},
+			{"type":"OP","sequence":2,"slug":"OP2","song":null,"animethemeentries":[]}
+		]}]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	c := NewClient()
+	c.BaseURL = server.URL
+	return c
+}
+
+func TestByAniListID(t *testing.T) {
+	c := newTestClient(t)
+
+	themes, err := c.ByAniListID(context.Background(), 21)
+	require.NoError(t, err)
+	require.Len(t, themes, 2, "themes without a song are left out")
+
+	assert.Equal(t, Theme{Slug: "OP1", Type: "OP", Song: "We Are!", Artists: []string{"Hiroshi Kitadani"}, Episodes: "1-47, 1000"}, themes[0])
+	assert.Equal(t, "ED1", themes[1].Slug, "the slug falls back to type and sequence")
+	assert.Equal(t, "Maki Otsuki", themes[1].Artist())
+}
+
+func TestSearch(t *testing.T) {
+	themes, err := newTestClient(t).Search(context.Background(), "One Piece")
+	require.NoError(t, err)
+	assert.Len(t, themes, 2)
+}
+
+func TestByAniListIDNotFound(t *testing.T) {
+	themes, err := newTestClient(t).ByAniListID(context.Background(), 404)
+	require.NoError(t, err)
+	assert.Empty(t, themes)
+}
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		episodes string
+		episode  int
+		want     bool
+	}{
+		{"", 5, true},
+		{"1-12", 12, true},
+		{"1-12", 13, false},
+		{"1-12, 14", 14, true},
+		{"25-", 300, true},
+		{"25-", 24, false},
+		{"7", 7, true},
+		{"OVA", 1, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Theme{Episodes: tt.episodes}.Covers(tt.episode), "%q covers %d", tt.episodes, tt.episode)
+	}
+}
+
+func TestForEpisode(t *testing.T) {
+	themes := []Theme{
+		{Slug: "OP1", Episodes: "1-12"},
+		{Slug: "OP2", Episodes: "13-24"},
+		{Slug: "ED1", Episodes: "1-24"},
+	}
+	matched := ForEpisode(themes, 14)
+	require.Len(t, matched, 2)
+	assert.Equal(t, "OP2", matched[0].Slug)
+	assert.Equal(t, "ED1", matched[1].Slug)
+}
@@ -0,0 +1,113 @@
+// Package aniskip fetches where openings and endings play in anime
+// episodes from AniSkip, which keys its entries by MyAnimeList ID
+package aniskip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Skip types
+const (
+	TypeOpening = "op"
+	TypeEnding  = "ed"
+)
+
+// Interval is where an opening or ending plays in an episode
+type Interval struct {
+	Type  string // TypeOpening or TypeEnding
+	Start time.Duration
+	End   time.Duration
+}
+
+// Client is a minimal AniSkip API client
+type Client struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewClient returns a client for the public AniSkip API
+func NewClient() *Client {
+	return &Client{
+		BaseURL: "https://api.aniskip.com",
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// skipTimesResponse is the part of a /v2/skip-times response that is used
+type skipTimesResponse struct {
+	Found   bool `json:"found"`
+	Results []struct {
+		Interval struct {
+			StartTime float64 `json:"startTime"`
+			EndTime   float64 `json:"endTime"`
+		} `json:"interval"`
+		SkipType string `json:"skipType"`
+	} `json:"results"`
+}
+
+// Ending returns where the ending plays in an episode of the anime with a
+// MyAnimeList ID, nil when AniSkip doesn't know. length is the episode's
+// duration, so times submitted for a different cut are left out.
+func (c *Client) Ending(ctx context.Context, malID, episode int, length time.Duration) (*Interval, error) {
+	intervals, err := c.SkipTimes(ctx, malID, episode, length, TypeEnding)
+	if err != nil || len(intervals) == 0 {
+		return nil, err
+	}
+	return &intervals[0], nil
+}
+
+// SkipTimes returns the intervals of the given types in an episode
+func (c *Client) SkipTimes(ctx context.Context, malID, episode int, length time.Duration, types ...string) ([]Interval, error) {
+	query := url.Values{}
+	for _, t := range types {
+		query.Add("types[]", t)
+	}
+	query.Set("episodeLength", strconv.FormatFloat(length.Seconds(), 'f', 3, 64))
+
+	endpoint := fmt.Sprintf("%s/v2/skip-times/%d/%d?%s", c.BaseURL, malID, episode, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Episodes nobody submitted times for are a 404
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("aniskip request returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result skipTimesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode aniskip response: %w", err)
+	}
+	if !result.Found {
+		return nil, nil
+	}
+
+	intervals := make([]Interval, 0, len(result.Results))
+	for _, r := range result.Results {
+		intervals = append(intervals, Interval{
+			Type:  r.SkipType,
+			Start: time.Duration(r.Interval.StartTime * float64(time.Second)),
+			End:   time.Duration(r.Interval.EndTime * float64(time.Second)),
+		})
+	}
+	return intervals, nil
+}
@@ -0,0 +1,44 @@
+package aniskip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnding(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/skip-times/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, []string{"ed"}, query["types[]"])
+		assert.Equal(t, "1420.000", query.Get("episodeLength"))
+		if r.URL.Path != "/v2/skip-times/21/5" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"found":false,"results":[],"message":"No episode found","statusCode":404}`)
+			return
+		}
+		fmt.Fprint(w, `{"found":true,"results":[
+			{"interval":{"startTime":1303.5,"endTime":1393.5},"skipType":"ed","skipId":"x","episodeLength":1420.1}
+		],"message":"Successfully found skip times","statusCode":200}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL = server.URL
+
+	ending, err := c.Ending(context.Background(), 21, 5, 1420*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, ending)
+	assert.Equal(t, Interval{Type: TypeEnding, Start: 1303500 * time.Millisecond, End: 1393500 * time.Millisecond}, *ending)
+
+	ending, err = c.Ending(context.Background(), 21, 6, 1420*time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, ending)
+}
@@ -0,0 +1,124 @@
+// Package arcs groups the episodes of long-running anime into story arcs or
+// cours, from local arc datasets or from an anime's chain of sequels
+package arcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Arc is a run of episodes, numbered as the provider numbers them
+type Arc struct {
+	Name  string `json:"name"`
+	Start int    `json:"start"`
+	End   int    `json:"end"` // 0 for an arc that is still airing
+}
+
+// Series is the arcs of one anime in a dataset file
+type Series struct {
+	AniListID int    `json:"anilist_id"`
+	Title     string `json:"title"`
+	Arcs      []Arc  `json:"arcs"`
+}
+
+// Dataset is the series of every dataset file in a directory
+type Dataset struct {
+	byID    map[int][]Arc
+	byTitle map[string][]Arc
+}
+
+// Load reads the *.json files of dir. A file holds one series or a list of
+// them. A missing directory is an empty dataset.
+func Load(dir string) (*Dataset, error) {
+	d := &Dataset{byID: make(map[int][]Arc), byTitle: make(map[string][]Arc)}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list arc datasets: %w", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read arc dataset %s: %w", file, err)
+		}
+
+		var series []Series
+		if err := json.Unmarshal(data, &series); err != nil {
+			var one Series
+			if err := json.Unmarshal(data, &one); err != nil {
+				return nil, fmt.Errorf("failed to parse arc dataset %s: %w", file, err)
+			}
+			series = []Series{one}
+		}
+		for _, s := range series {
+			if s.AniListID > 0 {
+				d.byID[s.AniListID] = s.Arcs
+			}
+			if s.Title != "" {
+				d.byTitle[normalizeTitle(s.Title)] = s.Arcs
+			}
+		}
+	}
+	return d, nil
+}
+
+// Lookup returns the arcs of an anime by AniList ID, or by title when the ID
+// is 0 or not in the dataset
+func (d *Dataset) Lookup(anilistID int, title string) ([]Arc, bool) {
+	if arcs, ok := d.byID[anilistID]; ok && anilistID > 0 {
+		return arcs, true
+	}
+	arcs, ok := d.byTitle[normalizeTitle(title)]
+	return arcs, ok
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// Cour is an entry of a chain of sequels and how many episodes it has
+type Cour struct {
+	Title    string
+	Episodes int
+}
+
+// FromCours numbers the cours of a chain of sequels one after another, for
+// providers that list a whole chain as one series. A cour with an unknown
+// episode count (still airing) ends the chain.
+func FromCours(cours []Cour) []Arc {
+	arcs := make([]Arc, 0, len(cours))
+	start := 1
+	for _, cour := range cours {
+		if cour.Episodes <= 0 {
+			arcs = append(arcs, Arc{Name: cour.Title, Start: start})
+			break
+		}
+		arcs = append(arcs, Arc{Name: cour.Title, Start: start, End: start + cour.Episodes - 1})
+		start += cour.Episodes
+	}
+	return arcs
+}
+
+// Of returns the name of the first arc episode number falls in, or ""
+func Of(arcs []Arc, number int) string {
+	for _, arc := range arcs {
+		if number >= arc.Start && (arc.End == 0 || number <= arc.End) {
+			return arc.Name
+		}
+	}
+	return ""
+}
+
+// Apply sets the arc of the episodes that don't have one yet
+func Apply(arcs []Arc, episodes []providers.Episode) {
+	for i := range episodes {
+		if episodes[i].Arc == "" {
+			episodes[i].Arc = Of(arcs, episodes[i].Number)
+		}
+	}
+}
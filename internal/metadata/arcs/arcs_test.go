@@ -0,0 +1,83 @@
+package arcs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "one.json"), []byte(`{
+		"anilist_id": 100, "title": "Long  Runner",
+		"arcs": [{"name": "First", "start": 1, "end": 3}, {"name": "Second", "start": 4}]
+	}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "many.json"), []byte(`[
+		{"title": "Other Show", "arcs": [{"name": "Only", "start": 1, "end": 12}]}
+	]`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a dataset"), 0o644))
+
+	d, err := Load(dir)
+	require.NoError(t, err)
+
+	arcs, ok := d.Lookup(100, "")
+	require.True(t, ok)
+	assert.Equal(t, []Arc{{Name: "First", Start: 1, End: 3}, {Name: "Second", Start: 4}}, arcs)
+
+	arcs, ok = d.Lookup(0, "long runner")
+	require.True(t, ok, "titles match case and spacing insensitively")
+	assert.Len(t, arcs, 2)
+
+	arcs, ok = d.Lookup(999, "Other Show")
+	require.True(t, ok, "unknown IDs fall back to the title")
+	assert.Equal(t, "Only", arcs[0].Name)
+
+	_, ok = d.Lookup(0, "Unknown")
+	assert.False(t, ok)
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	d, err := Load(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	_, ok := d.Lookup(1, "Anything")
+	assert.False(t, ok)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"arcs": "nope"}`), 0o644))
+
+	_, err := Load(dir)
+	assert.Error(t, err)
+}
+
+func TestFromCours(t *testing.T) {
+	arcs := FromCours([]Cour{
+		{Title: "Season 1", Episodes: 25},
+		{Title: "Season 2", Episodes: 12},
+		{Title: "Season 3", Episodes: 0},
+		{Title: "Season 4", Episodes: 10},
+	})
+	assert.Equal(t, []Arc{
+		{Name: "Season 1", Start: 1, End: 25},
+		{Name: "Season 2", Start: 26, End: 37},
+		{Name: "Season 3", Start: 38},
+	}, arcs)
+}
+
+func TestApply(t *testing.T) {
+	arcs := []Arc{{Name: "A", Start: 1, End: 2}, {Name: "B", Start: 3, End: 4}}
+	episodes := []providers.Episode{{Number: 1}, {Number: 3, Arc: "Provider Arc"}, {Number: 4}, {Number: 9}}
+
+	Apply(arcs, episodes)
+
+	assert.Equal(t, "A", episodes[0].Arc)
+	assert.Equal(t, "Provider Arc", episodes[1].Arc, "arcs the provider set are kept")
+	assert.Equal(t, "B", episodes[2].Arc)
+	assert.Empty(t, episodes[3].Arc)
+}
@@ -0,0 +1,181 @@
+// Package numbering translates between the absolute episode numbers
+// providers give long-running anime and the season/episode numbers of TMDB
+// and the per-season entries of AniList, from cross-ID mapping datasets
+package numbering
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Entry maps an AniList entry to the TMDB show and season it is part of
+type Entry struct {
+	AniListID     int     `json:"anilist_id"`
+	MALID         int     `json:"mal_id"`
+	TMDBID        int     `json:"themoviedb_id"`
+	Season        Seasons `json:"season"`
+	EpisodeOffset int     `json:"episode_offset"` // Episodes of the TMDB season before the entry's first
+	Episodes      int     `json:"episodes"`       // 0 when unknown or still airing
+}
+
+// Seasons is the season an entry is in at each metadata source
+type Seasons struct {
+	TMDB int `json:"tmdb"` // 0 for specials
+}
+
+// Dataset is the entries of every dataset file in a directory
+type Dataset struct {
+	byAniList map[int]Entry
+	byShow    map[int][]Entry // By TMDB ID
+}
+
+// Load reads the *.json files of dir. A file holds one entry or a list of
+// them. A missing directory is an empty dataset.
+func Load(dir string) (*Dataset, error) {
+	d := &Dataset{byAniList: make(map[int]Entry), byShow: make(map[int][]Entry)}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list id mapping datasets: %w", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read id mapping dataset %s: %w", file, err)
+		}
+
+		var entries []Entry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			var one Entry
+			if err := json.Unmarshal(data, &one); err != nil {
+				return nil, fmt.Errorf("failed to parse id mapping dataset %s: %w", file, err)
+			}
+			entries = []Entry{one}
+		}
+		for _, entry := range entries {
+			if entry.AniListID <= 0 || entry.TMDBID <= 0 {
+				continue
+			}
+			d.byAniList[entry.AniListID] = entry
+			d.byShow[entry.TMDBID] = append(d.byShow[entry.TMDBID], entry)
+		}
+	}
+	return d, nil
+}
+
+// Lookup returns the entry of an AniList entry
+func (d *Dataset) Lookup(anilistID int) (Entry, bool) {
+	entry, ok := d.byAniList[anilistID]
+	return entry, ok
+}
+
+// Translator returns the numbering of the TMDB show an AniList entry is
+// part of
+func (d *Dataset) Translator(anilistID int) (*Translator, bool) {
+	entry, ok := d.byAniList[anilistID]
+	if !ok {
+		return nil, false
+	}
+	return NewTranslator(d.byShow[entry.TMDBID]), true
+}
+
+// span is an entry and the absolute number of its first episode
+type span struct {
+	Entry
+	start int
+}
+
+// Translator converts episode numbers of one TMDB show. Absolute numbers
+// count the episodes of its AniList entries one after another from 1, in
+// season order, leaving specials (season 0) out.
+type Translator struct {
+	TMDBID int
+	spans  []span
+}
+
+// NewTranslator numbers the entries of one show. An entry with an unknown
+// episode count ends the absolute numbering, as the ones after it can't be
+// placed.
+func NewTranslator(entries []Entry) *Translator {
+	sorted := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Season.TMDB > 0 {
+			sorted = append(sorted, entry)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Season.TMDB != sorted[j].Season.TMDB {
+			return sorted[i].Season.TMDB < sorted[j].Season.TMDB
+		}
+		return sorted[i].EpisodeOffset < sorted[j].EpisodeOffset
+	})
+
+	t := &Translator{}
+	if len(sorted) > 0 {
+		t.TMDBID = sorted[0].TMDBID
+	}
+	start := 1
+	for _, entry := range sorted {
+		t.spans = append(t.spans, span{Entry: entry, start: start})
+		if entry.Episodes <= 0 {
+			break
+		}
+		start += entry.Episodes
+	}
+	return t
+}
+
+// find returns the span an absolute episode number falls in
+func (t *Translator) find(absolute int) (span, bool) {
+	for _, s := range t.spans {
+		if absolute >= s.start && (s.Episodes <= 0 || absolute < s.start+s.Episodes) {
+			return s, true
+		}
+	}
+	return span{}, false
+}
+
+// Start returns the absolute number of the first episode of an AniList entry
+func (t *Translator) Start(anilistID int) (int, bool) {
+	for _, s := range t.spans {
+		if s.AniListID == anilistID {
+			return s.start, true
+		}
+	}
+	return 0, false
+}
+
+// Seasonal returns the TMDB season and episode of an absolute episode number
+func (t *Translator) Seasonal(absolute int) (season, episode int, ok bool) {
+	s, ok := t.find(absolute)
+	if !ok {
+		return 0, 0, false
+	}
+	return s.Season.TMDB, s.EpisodeOffset + absolute - s.start + 1, true
+}
+
+// Absolute returns the absolute number of a TMDB season's episode
+func (t *Translator) Absolute(season, episode int) (int, bool) {
+	for _, s := range t.spans {
+		if s.Season.TMDB != season || episode <= s.EpisodeOffset {
+			continue
+		}
+		if s.Episodes <= 0 || episode <= s.EpisodeOffset+s.Episodes {
+			return s.start + episode - s.EpisodeOffset - 1, true
+		}
+	}
+	return 0, false
+}
+
+// AniList returns the AniList entry and its episode of an absolute episode
+// number
+func (t *Translator) AniList(absolute int) (anilistID, episode int, ok bool) {
+	s, ok := t.find(absolute)
+	if !ok {
+		return 0, 0, false
+	}
+	return s.AniListID, absolute - s.start + 1, true
+}
@@ -0,0 +1,122 @@
+package numbering
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// showDataset is a show whose first TMDB season AniList splits into two
+// cours, with a second season still airing and a special
+const showDataset = `[
+	{"anilist_id": 12, "mal_id": 112, "themoviedb_id": 100, "season": {"tmdb": 2}, "episode_offset": 0},
+	{"anilist_id": 11, "mal_id": 111, "themoviedb_id": 100, "season": {"tmdb": 1}, "episode_offset": 12, "episodes": 13},
+	{"anilist_id": 10, "mal_id": 110, "themoviedb_id": 100, "season": {"tmdb": 1}, "episode_offset": 0, "episodes": 12},
+	{"anilist_id": 13, "themoviedb_id": 100, "season": {"tmdb": 0}, "episodes": 1},
+	{"anilist_id": 14, "season": {"tmdb": 1}, "episodes": 5}
+]`
+
+func loadShow(t *testing.T) *Dataset {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "show.json"), []byte(showDataset), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "one.json"), []byte(`{"anilist_id": 20, "themoviedb_id": 200, "season": {"tmdb": 1}, "episodes": 24}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a dataset"), 0o644))
+
+	d, err := Load(dir)
+	require.NoError(t, err)
+	return d
+}
+
+func TestLoad(t *testing.T) {
+	d := loadShow(t)
+
+	entry, ok := d.Lookup(11)
+	require.True(t, ok)
+	assert.Equal(t, 100, entry.TMDBID)
+	assert.Equal(t, 1, entry.Season.TMDB)
+	assert.Equal(t, 12, entry.EpisodeOffset)
+	assert.Equal(t, 111, entry.MALID)
+
+	_, ok = d.Lookup(20)
+	assert.True(t, ok, "a file may hold a single entry")
+
+	_, ok = d.Lookup(14)
+	assert.False(t, ok, "entries without a TMDB show are left out")
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	d, err := Load(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	_, ok := d.Translator(1)
+	assert.False(t, ok)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"anilist_id": "nope"}`), 0o644))
+
+	_, err := Load(dir)
+	assert.Error(t, err)
+}
+
+func TestTranslator(t *testing.T) {
+	tr, ok := loadShow(t).Translator(12)
+	require.True(t, ok)
+	assert.Equal(t, 100, tr.TMDBID)
+
+	start, ok := tr.Start(11)
+	require.True(t, ok)
+	assert.Equal(t, 13, start)
+	start, _ = tr.Start(12)
+	assert.Equal(t, 26, start)
+	_, ok = tr.Start(13)
+	assert.False(t, ok, "specials have no absolute number")
+
+	for _, tc := range []struct {
+		absolute, season, episode, anilistID, anilistEpisode int
+	}{
+		{1, 1, 1, 10, 1},
+		{12, 1, 12, 10, 12},
+		{13, 1, 13, 11, 1},
+		{25, 1, 25, 11, 13},
+		{26, 2, 1, 12, 1},
+		{40, 2, 15, 12, 15},
+	} {
+		season, episode, ok := tr.Seasonal(tc.absolute)
+		require.True(t, ok, "absolute %d", tc.absolute)
+		assert.Equal(t, [2]int{tc.season, tc.episode}, [2]int{season, episode}, "absolute %d", tc.absolute)
+
+		absolute, ok := tr.Absolute(tc.season, tc.episode)
+		require.True(t, ok, "S%dE%d", tc.season, tc.episode)
+		assert.Equal(t, tc.absolute, absolute, "S%dE%d", tc.season, tc.episode)
+
+		anilistID, anilistEpisode, ok := tr.AniList(tc.absolute)
+		require.True(t, ok, "absolute %d", tc.absolute)
+		assert.Equal(t, [2]int{tc.anilistID, tc.anilistEpisode}, [2]int{anilistID, anilistEpisode}, "absolute %d", tc.absolute)
+	}
+
+	_, _, ok = tr.Seasonal(0)
+	assert.False(t, ok)
+	_, ok = tr.Absolute(1, 26)
+	assert.False(t, ok, "past the last cour of the season")
+	_, ok = tr.Absolute(3, 1)
+	assert.False(t, ok)
+}
+
+func TestTranslatorStopsAtUnknownLength(t *testing.T) {
+	tr := NewTranslator([]Entry{
+		{AniListID: 1, Episodes: 10, Season: Seasons{TMDB: 1}},
+		{AniListID: 2, Season: Seasons{TMDB: 2}},
+		{AniListID: 3, Episodes: 10, Season: Seasons{TMDB: 3}},
+	})
+
+	_, ok := tr.Start(3)
+	assert.False(t, ok, "entries after one still airing can't be placed")
+	anilistID, episode, ok := tr.AniList(500)
+	require.True(t, ok)
+	assert.Equal(t, [2]int{2, 490}, [2]int{anilistID, episode})
+}
@@ -0,0 +1,224 @@
+// Package tmdb fetches TV season metadata from The Movie Database, used to
+// fill in episode titles, air dates and synopses that providers leave out
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Episode is one episode of a TMDB season
+type Episode struct {
+	Number   int
+	Title    string
+	Overview string
+	AirDate  time.Time
+	Runtime  time.Duration
+	Still    string // Still image URL, empty when TMDB has none
+}
+
+// stillBaseURL serves episode stills at a width that suits a preview pane
+const stillBaseURL = "https://image.tmdb.org/t/p/w300"
+
+// Client is a minimal TMDB v3 API client
+type Client struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+// NewClient returns a client using the given v3 API key
+func NewClient(apiKey string) *Client {
+	return &Client{
+		BaseURL: "https://api.themoviedb.org/3",
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// getJSON fetches an API path with the given query and decodes the response into v
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, v interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api_key", c.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("tmdb request to %s returned status %d: %s", path, resp.StatusCode, string(bodyBytes))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode tmdb response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// SearchShow returns the TMDB ID of the best matching TV show. Year narrows
+// the search when it is non-zero.
+func (c *Client) SearchShow(ctx context.Context, title string, year int) (int, error) {
+	var result struct {
+		Results []struct {
+			ID           int    `json:"id"`
+			Name         string `json:"name"`
+			FirstAirDate string `json:"first_air_date"`
+		} `json:"results"`
+	}
+
+	query := url.Values{"query": {title}}
+	if year > 0 {
+		query.Set("first_air_date_year", strconv.Itoa(year))
+	}
+	if err := c.getJSON(ctx, "/search/tv", query, &result); err != nil {
+		return 0, err
+	}
+	if len(result.Results) == 0 {
+		return 0, fmt.Errorf("no tmdb show found for %q", title)
+	}
+
+	// Prefer an exact title match over TMDB's popularity ranking
+	for _, show := range result.Results {
+		if strings.EqualFold(show.Name, title) {
+			return show.ID, nil
+		}
+	}
+	return result.Results[0].ID, nil
+}
+
+// GetSeason returns the episodes of a season of the given show
+func (c *Client) GetSeason(ctx context.Context, showID, season int) ([]Episode, error) {
+	var result struct {
+		Episodes []struct {
+			EpisodeNumber int    `json:"episode_number"`
+			Name          string `json:"name"`
+			Overview      string `json:"overview"`
+			AirDate       string `json:"air_date"`
+			Runtime       int    `json:"runtime"`
+			StillPath     string `json:"still_path"`
+		} `json:"episodes"`
+	}
+
+	if err := c.getJSON(ctx, fmt.Sprintf("/tv/%d/season/%d", showID, season), nil, &result); err != nil {
+		return nil, err
+	}
+
+	episodes := make([]Episode, 0, len(result.Episodes))
+	for _, ep := range result.Episodes {
+		airDate, _ := time.Parse("2006-01-02", ep.AirDate)
+		var still string
+		if ep.StillPath != "" {
+			still = stillBaseURL + ep.StillPath
+		}
+		episodes = append(episodes, Episode{
+			Number:   ep.EpisodeNumber,
+			Title:    ep.Name,
+			Overview: ep.Overview,
+			AirDate:  airDate,
+			Runtime:  time.Duration(ep.Runtime) * time.Minute,
+			Still:    still,
+		})
+	}
+	return episodes, nil
+}
+
+// storyArcGroup is the TMDB episode group type of story arcs
+const storyArcGroup = 5
+
+// Arc is a story arc of a show and the episodes in it
+type Arc struct {
+	Name     string
+	Episodes []ArcEpisode
+}
+
+// ArcEpisode is an episode of a story arc. Absolute counts regular episodes
+// across all arcs from 1, the numbering of long-running anime.
+type ArcEpisode struct {
+	Season   int
+	Number   int
+	Absolute int
+}
+
+// GetStoryArcs returns a show's story arcs in order, from its largest story
+// arc episode group. It returns no arcs when the show has no such group.
+func (c *Client) GetStoryArcs(ctx context.Context, showID int) ([]Arc, error) {
+	var groups struct {
+		Results []struct {
+			ID           string `json:"id"`
+			Type         int    `json:"type"`
+			EpisodeCount int    `json:"episode_count"`
+		} `json:"results"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("/tv/%d/episode_groups", showID), nil, &groups); err != nil {
+		return nil, err
+	}
+
+	groupID, most := "", 0
+	for _, group := range groups.Results {
+		if group.Type == storyArcGroup && group.EpisodeCount > most {
+			groupID, most = group.ID, group.EpisodeCount
+		}
+	}
+	if groupID == "" {
+		return nil, nil
+	}
+
+	var result struct {
+		Groups []struct {
+			Name     string `json:"name"`
+			Order    int    `json:"order"`
+			Episodes []struct {
+				SeasonNumber  int `json:"season_number"`
+				EpisodeNumber int `json:"episode_number"`
+				Order         int `json:"order"`
+			} `json:"episodes"`
+		} `json:"groups"`
+	}
+	if err := c.getJSON(ctx, "/tv/episode_group/"+groupID, nil, &result); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Groups, func(i, j int) bool {
+		return result.Groups[i].Order < result.Groups[j].Order
+	})
+	arcs := make([]Arc, 0, len(result.Groups))
+	absolute := 0
+	for _, group := range result.Groups {
+		episodes := group.Episodes
+		sort.SliceStable(episodes, func(i, j int) bool {
+			return episodes[i].Order < episodes[j].Order
+		})
+
+		arc := Arc{Name: group.Name}
+		for _, ep := range episodes {
+			// Specials (season 0) sit outside the absolute numbering
+			number := 0
+			if ep.SeasonNumber > 0 {
+				absolute++
+				number = absolute
+			}
+			arc.Episodes = append(arc.Episodes, ArcEpisode{Season: ep.SeasonNumber, Number: ep.EpisodeNumber, Absolute: number})
+		}
+		arcs = append(arcs, arc)
+	}
+	return arcs, nil
+}
@@ -0,0 +1,117 @@
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search/tv", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("api_key"))
+		assert.Equal(t, "2008", r.URL.Query().Get("first_air_date_year"))
+		fmt.Fprint(w, `{"results":[{"id":1,"name":"Breaking Bad Spin-off"},{"id":1396,"name":"Breaking Bad"}]}`)
+	})
+	mux.HandleFunc("/tv/1396/season/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"episodes":[
+			{"episode_number":1,"name":"Pilot","overview":"Walter gets a diagnosis.","air_date":"2008-01-20","runtime":58,"still_path":"/pilot.jpg"},
+			{"episode_number":2,"name":"Cat's in the Bag...","overview":"","air_date":"","runtime":0,"still_path":null}
+		]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	c := NewClient("secret")
+	c.BaseURL = server.URL
+	return c
+}
+
+func TestSearchShowPrefersExactTitle(t *testing.T) {
+	c := newTestClient(t)
+
+	id, err := c.SearchShow(context.Background(), "breaking bad", 2008)
+	require.NoError(t, err)
+	assert.Equal(t, 1396, id)
+}
+
+func TestGetSeason(t *testing.T) {
+	c := newTestClient(t)
+
+	episodes, err := c.GetSeason(context.Background(), 1396, 1)
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+
+	assert.Equal(t, "Pilot", episodes[0].Title)
+	assert.Equal(t, 58*time.Minute, episodes[0].Runtime)
+	assert.Equal(t, time.Date(2008, 1, 20, 0, 0, 0, 0, time.UTC), episodes[0].AirDate)
+	assert.Equal(t, "https://image.tmdb.org/t/p/w300/pilot.jpg", episodes[0].Still)
+	assert.True(t, episodes[1].AirDate.IsZero())
+	assert.Empty(t, episodes[1].Still)
+}
+
+func TestGetStoryArcs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tv/37854/episode_groups", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[
+			{"id":"dvd","type":3,"episode_count":900},
+			{"id":"short","type":5,"episode_count":10},
+			{"id":"arcs","type":5,"episode_count":1100}
+		]}`)
+	})
+	mux.HandleFunc("/tv/episode_group/arcs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"groups":[
+			{"name":"Orange Town","order":1,"episodes":[
+				{"season_number":1,"episode_number":5,"order":1},
+				{"season_number":1,"episode_number":4,"order":0}
+			]},
+			{"name":"Romance Dawn","order":0,"episodes":[
+				{"season_number":1,"episode_number":1,"order":0},
+				{"season_number":0,"episode_number":1,"order":1},
+				{"season_number":1,"episode_number":2,"order":2},
+				{"season_number":1,"episode_number":3,"order":3}
+			]}
+		]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	c := NewClient("secret")
+	c.BaseURL = server.URL
+
+	arcs, err := c.GetStoryArcs(context.Background(), 37854)
+	require.NoError(t, err)
+	assert.Equal(t, []Arc{
+		{Name: "Romance Dawn", Episodes: []ArcEpisode{
+			{Season: 1, Number: 1, Absolute: 1},
+			{Season: 0, Number: 1},
+			{Season: 1, Number: 2, Absolute: 2},
+			{Season: 1, Number: 3, Absolute: 3},
+		}},
+		{Name: "Orange Town", Episodes: []ArcEpisode{
+			{Season: 1, Number: 4, Absolute: 4},
+			{Season: 1, Number: 5, Absolute: 5},
+		}},
+	}, arcs)
+}
+
+func TestGetStoryArcsWithoutGroup(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tv/1396/episode_groups", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[{"id":"dvd","type":3,"episode_count":62}]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	c := NewClient("secret")
+	c.BaseURL = server.URL
+
+	arcs, err := c.GetStoryArcs(context.Background(), 1396)
+	require.NoError(t, err)
+	assert.Empty(t, arcs)
+}
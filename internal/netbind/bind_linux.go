@@ -0,0 +1,23 @@
+//go:build linux
+
+package netbind
+
+import (
+	"net"
+	"syscall"
+)
+
+// bindDialer makes dialer's sockets send out of ifi with SO_BINDTODEVICE,
+// which holds across the interface's addresses changing
+func bindDialer(dialer *net.Dialer, ifi *net.Interface, network string) (string, error) {
+	dialer.Control = func(_, _ string, conn syscall.RawConn) error {
+		var sockErr error
+		if err := conn.Control(func(fd uintptr) {
+			sockErr = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, ifi.Name)
+		}); err != nil {
+			return err
+		}
+		return sockErr
+	}
+	return network, nil
+}
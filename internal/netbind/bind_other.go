@@ -0,0 +1,56 @@
+//go:build !linux
+
+package netbind
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// bindDialer makes dialer's connections start from an address of ifi, and
+// returns the network restricted to that address's IP version. Routes
+// matching the source address take the traffic through the interface.
+func bindDialer(dialer *net.Dialer, ifi *net.Interface, network string) (string, error) {
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	var v4, v6 net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			if v4 == nil {
+				v4 = ip
+			}
+		} else if v6 == nil {
+			v6 = ipNet.IP
+		}
+	}
+
+	// Prefer IPv4, which VPN tunnels are most often set up for
+	base := strings.TrimRight(network, "46")
+	switch {
+	case v4 != nil && !strings.HasSuffix(network, "6"):
+		network = base + "4"
+		dialer.LocalAddr = localAddr(base, v4)
+	case v6 != nil && !strings.HasSuffix(network, "4"):
+		network = base + "6"
+		dialer.LocalAddr = localAddr(base, v6)
+	default:
+		return "", errors.New("interface has no usable address")
+	}
+	return network, nil
+}
+
+// localAddr returns ip as a local address of network
+func localAddr(network string, ip net.IP) net.Addr {
+	if network == "udp" {
+		return &net.UDPAddr{IP: ip}
+	}
+	return &net.TCPAddr{IP: ip}
+}
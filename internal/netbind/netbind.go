@@ -0,0 +1,118 @@
+// Package netbind sends provider traffic out of a chosen network interface,
+// such as a VPN tunnel, while the rest of the system keeps the default
+// route.
+package netbind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRoute is the per-provider override that leaves a provider unbound
+// when network.bind_interface is set
+const DefaultRoute = "default"
+
+var (
+	mu         sync.RWMutex
+	iface      string            // Interface every provider is bound to, empty for none
+	overrides  map[string]string // Interfaces keyed by lowercase provider name
+	transports = make(map[string]*http.Transport)
+)
+
+// Configure sets the interface provider traffic goes out of, and the
+// per-provider overrides. Requests already made keep their connections.
+func Configure(bindInterface string, providerInterfaces map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	iface = strings.TrimSpace(bindInterface)
+	overrides = make(map[string]string, len(providerInterfaces))
+	for name, value := range providerInterfaces {
+		overrides[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+	// Drop pooled connections, they may be bound to an interface no longer
+	// configured
+	for name, transport := range transports {
+		transport.CloseIdleConnections()
+		delete(transports, name)
+	}
+}
+
+// Interface returns the interface provider is bound to, empty when it uses
+// the default route
+func Interface(provider string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	name := iface
+	if override, ok := overrides[strings.ToLower(provider)]; ok && override != "" {
+		name = override
+	}
+	if name == DefaultRoute {
+		return ""
+	}
+	return name
+}
+
+// Transport returns the transport for provider's requests. It follows later
+// calls to Configure, so clients can keep it for their lifetime.
+func Transport(provider string) http.RoundTripper {
+	return providerTransport(provider)
+}
+
+// providerTransport routes a provider's requests through the transport of
+// its interface
+type providerTransport string
+
+func (p providerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := Interface(string(p))
+	// Looked up per request, so --record/--replay apply; their transports
+	// aren't bound
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if name == "" || !ok {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return boundTransport(base, name).RoundTrip(req)
+}
+
+// boundTransport returns the pooled transport dialing out of the interface,
+// set up like base
+func boundTransport(base *http.Transport, name string) *http.Transport {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if transport, ok := transports[name]; ok {
+		return transport
+	}
+	transport := base.Clone()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return Dial(ctx, name, network, address)
+	}
+	transports[name] = transport
+	return transport
+}
+
+// Dial connects to address out of the named interface. It fails rather than
+// falling back to the default route when the interface is missing or down,
+// so a dropped VPN doesn't leak traffic.
+func Dial(ctx context.Context, name, network, address string) (net.Conn, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to interface %s: %w", name, err)
+	}
+	if ifi.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("failed to bind to interface %s: interface is down", name)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	network, err = bindDialer(dialer, ifi, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to interface %s: %w", name, err)
+	}
+	return dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,83 @@
+package netbind
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterface(t *testing.T) {
+	t.Cleanup(func() { Configure("", nil) })
+
+	Configure("", nil)
+	assert.Empty(t, Interface("allanime"))
+
+	Configure("", map[string]string{"AllAnime": "wg0"})
+	assert.Equal(t, "wg0", Interface("allanime"))
+	assert.Empty(t, Interface("hianime"))
+
+	Configure("tun0", map[string]string{"allanime": "wg0", "comick": DefaultRoute, "sflix": ""})
+	assert.Equal(t, "wg0", Interface("allanime"))
+	assert.Equal(t, "tun0", Interface("hianime"))
+	assert.Equal(t, "tun0", Interface("sflix"))
+	assert.Empty(t, Interface("comick"))
+}
+
+func TestTransportFailsWithoutInterface(t *testing.T) {
+	t.Cleanup(func() { Configure("", nil) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport("allanime")}
+	Configure("", map[string]string{"allanime": "greg-missing0"})
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "greg-missing0")
+
+	// Unbound providers and later reconfiguration aren't affected
+	resp, err := (&http.Client{Transport: Transport("hianime")}).Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	Configure("", nil)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+}
+
+func TestDialLoopback(t *testing.T) {
+	var loopback string
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	for _, ifi := range ifaces {
+		if ifi.Flags&net.FlagLoopback != 0 && ifi.Flags&net.FlagUp != 0 {
+			loopback = ifi.Name
+			break
+		}
+	}
+	if loopback == "" {
+		t.Skip("no loopback interface")
+	}
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	conn, err := Dial(context.Background(), loopback, "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	_ = conn.Close()
+}
@@ -0,0 +1,44 @@
+package player
+
+import "time"
+
+// Completion rules
+const (
+	// CompletionPercent counts an episode as watched past a share of it
+	CompletionPercent = "percent"
+	// CompletionRemaining counts an episode as watched once little of it is
+	// left, which suits long specials and movies with long credits
+	CompletionRemaining = "remaining"
+)
+
+// DefaultCompletionPercent is the watched share the percent rule uses when
+// none is set
+const DefaultCompletionPercent = 85.0
+
+// CompletionRule decides when an episode counts as watched
+type CompletionRule struct {
+	Rule      string        // CompletionPercent or CompletionRemaining
+	Percent   float64       // Watched share for CompletionPercent
+	Remaining time.Duration // Time left for CompletionRemaining
+	// Ending is where the ending starts, 0 when unknown. The remaining rule
+	// counts the time left up to it rather than to the end of the file.
+	Ending time.Duration
+}
+
+// Completed reports whether playback that got as far as progress watched the
+// episode
+func (r CompletionRule) Completed(progress PlaybackProgress) bool {
+	if r.Rule == CompletionRemaining && r.Remaining > 0 && progress.Duration > 0 {
+		end := progress.Duration
+		if r.Ending > 0 && r.Ending < end {
+			end = r.Ending
+		}
+		return end-progress.CurrentTime <= r.Remaining
+	}
+
+	percent := r.Percent
+	if percent <= 0 {
+		percent = DefaultCompletionPercent
+	}
+	return progress.Percentage >= percent
+}
@@ -0,0 +1,31 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionRule(t *testing.T) {
+	// A 24 minute episode whose ending starts at 22:00
+	at := func(current time.Duration) PlaybackProgress {
+		duration := 24 * time.Minute
+		return PlaybackProgress{CurrentTime: current, Duration: duration, Percentage: float64(current) / float64(duration) * 100}
+	}
+
+	percent := CompletionRule{Rule: CompletionPercent}
+	assert.False(t, percent.Completed(at(20*time.Minute)))
+	assert.True(t, percent.Completed(at(21*time.Minute)))
+
+	remaining := CompletionRule{Rule: CompletionRemaining, Remaining: 3 * time.Minute}
+	assert.False(t, remaining.Completed(at(20*time.Minute)))
+	assert.True(t, remaining.Completed(at(21*time.Minute)))
+
+	remaining.Ending = 22 * time.Minute
+	assert.False(t, remaining.Completed(at(18*time.Minute)))
+	assert.True(t, remaining.Completed(at(19*time.Minute)))
+
+	// Without a duration the remaining rule falls back to the percentage
+	assert.True(t, remaining.Completed(PlaybackProgress{Percentage: 90}))
+}
@@ -0,0 +1,254 @@
+// Package fake is a player that plays nothing: playback advances on a clock,
+// faster than real time if asked, up to the end of the file, where it waits
+// to be stopped like mpv does. It stands in for mpv in tests and demos
+// (greg --demo) and records what it was asked to play.
+package fake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+// DefaultDuration is the length of every file when none is set
+const DefaultDuration = 24 * time.Minute
+
+// tick is how often playback advances and progress callbacks run
+const tick = 100 * time.Millisecond
+
+var _ player.Player = (*Player)(nil)
+
+// Play is a call to Player.Play
+type Play struct {
+	URL     string
+	Options player.PlayOptions
+}
+
+// Player simulates playback. The zero value plays 24 minute files in real
+// time.
+type Player struct {
+	Duration time.Duration // Length of every file, DefaultDuration when 0
+	Speed    float64       // Simulated time per real time, 1 when 0
+
+	mu       sync.Mutex
+	plays    []Play
+	playing  bool
+	paused   bool
+	eof      bool
+	position time.Duration
+	duration time.Duration
+	stop     chan struct{} // Closed to stop the current playback
+	done     chan struct{} // Closed once the current playback stopped
+
+	onProgress []func(player.PlaybackProgress)
+	onEnd      []func()
+	onError    []func(error)
+}
+
+// New returns a player simulating files of duration at speed times real time
+func New(duration time.Duration, speed float64) *Player {
+	return &Player{Duration: duration, Speed: speed}
+}
+
+// Play starts simulating url from options.StartTime, stopping whatever
+// played before
+func (p *Player) Play(ctx context.Context, url string, options player.PlayOptions) error {
+	if url == "" {
+		return errors.New("no URL to play")
+	}
+	_ = p.Stop(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.plays = append(p.plays, Play{URL: url, Options: options})
+	p.duration = p.Duration
+	if p.duration <= 0 {
+		p.duration = DefaultDuration
+	}
+	p.position = min(max(options.StartTime, 0), p.duration)
+	p.playing, p.paused, p.eof = true, false, false
+	p.stop, p.done = make(chan struct{}), make(chan struct{})
+	go p.run(p.stop, p.done)
+	return nil
+}
+
+// run advances playback until it is stopped. Like mpv kept idle, the
+// player stays open at the end of the file until stopped.
+func (p *Player) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		if p.stop != stop {
+			// Stopped while waiting for the lock, maybe replayed since
+			p.mu.Unlock()
+			return
+		}
+		if p.eof {
+			p.mu.Unlock()
+			continue
+		}
+		if !p.paused {
+			p.position = min(p.position+time.Duration(float64(tick)*p.speed()), p.duration)
+		}
+		p.eof = p.position >= p.duration
+		progress := p.progressLocked()
+		onProgress, onEnd := p.onProgress, p.onEnd
+		p.mu.Unlock()
+
+		for _, fn := range onProgress {
+			fn(progress)
+		}
+		if progress.EOF {
+			for _, fn := range onEnd {
+				fn()
+			}
+		}
+	}
+}
+
+func (p *Player) speed() float64 {
+	if p.Speed <= 0 {
+		return 1
+	}
+	return p.Speed
+}
+
+// Stop ends the current playback where it is
+func (p *Player) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	p.playing = false
+	return nil
+}
+
+// GetProgress returns where the current or last playback is
+func (p *Player) GetProgress(ctx context.Context) (*player.PlaybackProgress, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done == nil {
+		return nil, errors.New("player not initialized")
+	}
+	progress := p.progressLocked()
+	return &progress, nil
+}
+
+func (p *Player) progressLocked() player.PlaybackProgress {
+	progress := player.PlaybackProgress{
+		CurrentTime: p.position,
+		Duration:    p.duration,
+		Paused:      p.paused,
+		Volume:      100,
+		Speed:       1,
+		EOF:         p.eof,
+	}
+	if p.duration > 0 {
+		progress.Percentage = float64(p.position) / float64(p.duration) * 100
+	}
+	return progress
+}
+
+// Seek moves the current playback to position
+func (p *Player) Seek(ctx context.Context, position time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.playing {
+		return errors.New("not playing")
+	}
+	p.position = min(max(position, 0), p.duration)
+	return nil
+}
+
+// SetPaused pauses or resumes the current playback
+func (p *Player) SetPaused(ctx context.Context, paused bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.playing {
+		return errors.New("not playing")
+	}
+	p.paused = paused
+	return nil
+}
+
+// Wait blocks until the current playback is stopped
+func (p *Player) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	done := p.done
+	p.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// OnProgressUpdate registers a callback run as playback advances
+func (p *Player) OnProgressUpdate(fn func(player.PlaybackProgress)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onProgress = append(p.onProgress, fn)
+}
+
+// OnPlaybackEnd registers a callback run when playback reaches the end of
+// the file
+func (p *Player) OnPlaybackEnd(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onEnd = append(p.onEnd, fn)
+}
+
+// OnError registers a callback for playback errors, which never happen
+func (p *Player) OnError(fn func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onError = append(p.onError, fn)
+}
+
+// IsPlaying reports whether playback is running, paused or not
+func (p *Player) IsPlaying() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playing
+}
+
+// IsPaused reports whether the current playback is paused
+func (p *Player) IsPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playing && p.paused
+}
+
+// Plays returns the calls to Play so far, oldest first
+func (p *Player) Plays() []Play {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Play(nil), p.plays...)
+}
+
+// LastPlay returns the latest call to Play, false when there was none
+func (p *Player) LastPlay() (Play, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.plays) == 0 {
+		return Play{}, false
+	}
+	return p.plays[len(p.plays)-1], true
+}
@@ -0,0 +1,77 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+func TestPlayToEnd(t *testing.T) {
+	ctx := context.Background()
+	p := New(time.Minute, 120) // Half a second of real time
+
+	ended := make(chan struct{})
+	p.OnPlaybackEnd(func() { close(ended) })
+	require.NoError(t, p.Play(ctx, "https://demo.invalid/a.m3u8", player.PlayOptions{StartTime: 30 * time.Second, Episode: 2}))
+	assert.True(t, p.IsPlaying())
+
+	progress, err := p.GetProgress(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, progress.CurrentTime, "starts at the start time")
+	assert.Equal(t, time.Minute, progress.Duration)
+
+	select {
+	case <-ended:
+	case <-time.After(5 * time.Second):
+		t.Fatal("playback didn't end")
+	}
+	progress, err = p.GetProgress(ctx)
+	require.NoError(t, err)
+	assert.True(t, progress.EOF)
+	assert.Equal(t, 100.0, progress.Percentage)
+	assert.True(t, p.IsPlaying(), "open at the end until stopped")
+
+	require.NoError(t, p.Stop(ctx))
+	require.NoError(t, p.Wait(ctx))
+	assert.False(t, p.IsPlaying())
+
+	play, ok := p.LastPlay()
+	require.True(t, ok)
+	assert.Equal(t, "https://demo.invalid/a.m3u8", play.URL)
+	assert.Equal(t, 2, play.Options.Episode)
+}
+
+func TestPauseSeekStop(t *testing.T) {
+	ctx := context.Background()
+	p := &Player{}
+
+	_, err := p.GetProgress(ctx)
+	assert.Error(t, err, "nothing played yet")
+	require.NoError(t, p.Wait(ctx), "nothing to wait for")
+
+	require.NoError(t, p.Play(ctx, "https://demo.invalid/a.m3u8", player.PlayOptions{}))
+	require.NoError(t, p.SetPaused(ctx, true))
+	require.NoError(t, p.Seek(ctx, 5*time.Minute))
+	time.Sleep(3 * tick)
+
+	progress, err := p.GetProgress(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, progress.CurrentTime, "paused playback stays put")
+	assert.Equal(t, DefaultDuration, progress.Duration)
+	assert.True(t, p.IsPaused())
+
+	require.NoError(t, p.Play(ctx, "https://demo.invalid/b.m3u8", player.PlayOptions{}))
+	assert.Len(t, p.Plays(), 2)
+	assert.False(t, p.IsPaused(), "the next file plays from the start")
+
+	require.NoError(t, p.Stop(ctx))
+	require.NoError(t, p.Wait(ctx))
+	assert.False(t, p.IsPlaying())
+	assert.Error(t, p.Seek(ctx, 0))
+	require.NoError(t, p.Stop(ctx), "stopping twice is harmless")
+}
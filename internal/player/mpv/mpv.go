@@ -24,6 +24,7 @@ type MPVPlayer struct {
 	// mpv process and IPC
 	client    *gopv.Client
 	cmd       *exec.Cmd
+	exit      *processExit // Exit of the latest mpv process
 	ipcConfig *IPCConfig
 	platform  Platform
 
@@ -31,6 +32,11 @@ type MPVPlayer struct {
 	state      player.PlaybackState
 	currentURL string
 	options    player.PlayOptions
+	watchLater string // Directory mpv saves the position to on quit-watch-later
+
+	// Position read from watchLater when playback stopped, see SavedPosition
+	savedPosition    time.Duration
+	hasSavedPosition bool
 
 	// Callbacks
 	onProgress func(player.PlaybackProgress)
@@ -46,6 +52,14 @@ type MPVPlayer struct {
 	// Configuration
 	debug          bool
 	loadUserConfig bool
+	hwdec          string // Passed as --hwdec, "" leaves it to mpv
+	profile        string // Performance profile, see profileArgs
+}
+
+// processExit reports when a spawned mpv process exits
+type processExit struct {
+	done chan struct{} // Closed once the process has exited
+	err  error         // Result of cmd.Wait, set before done is closed
 }
 
 // NewMPVPlayer creates a new mpv player instance
@@ -69,6 +83,8 @@ func NewMPVPlayerWithConfig(cfg *config.Config, debug bool) (*MPVPlayer, error)
 		platform:       platform,
 		debug:          debug,
 		loadUserConfig: cfg.Player.LoadUserConfig,
+		hwdec:          cfg.Player.HWDec,
+		profile:        cfg.Player.Profile,
 	}
 
 	return player, nil
@@ -101,6 +117,8 @@ func NewMPVPlayerWithDebug(debug bool) (*MPVPlayer, error) {
 		platform:       platform,
 		debug:          debug,
 		loadUserConfig: defaultConfig.Player.LoadUserConfig,
+		hwdec:          defaultConfig.Player.HWDec,
+		profile:        defaultConfig.Player.Profile,
 	}, nil
 }
 
@@ -132,6 +150,7 @@ func (p *MPVPlayer) Play(ctx context.Context, url string, options player.PlayOpt
 	p.ipcConfig = ipcConfig
 
 	// Build mpv arguments
+	p.newWatchLaterDir()
 	args := p.buildMPVArgs(url, options)
 
 	// Start mpv process
@@ -153,6 +172,10 @@ func (p *MPVPlayer) Play(ctx context.Context, url string, options player.PlayOpt
 		return fmt.Errorf("failed to start %s: %w", mpvExec, err)
 	}
 
+	// Watch the process itself, so exits are noticed even before IPC is up
+	p.exit = &processExit{done: make(chan struct{})}
+	go p.monitorProcess(p.cmd, p.exit)
+
 	// Verify process started successfully
 	// On Windows, the process may fail immediately if it can't create the named pipe
 	time.Sleep(100 * time.Millisecond)
@@ -165,32 +188,25 @@ func (p *MPVPlayer) Play(ctx context.Context, url string, options player.PlayOpt
 	p.currentURL = url
 	p.options = options
 	p.state = player.StateLoading
+	p.clientClosed = false // Let Stop kill this process before IPC is up
 
 	// Start async initialization - this will handle IPC connection and state updates
 	p.ctx, p.cancel = context.WithCancel(context.Background())
-	go p.asyncInitialize(ctx, ipcConfig)
+	go p.asyncInitialize(ctx, ipcConfig, p.exit)
 
 	return nil
 }
 
 // asyncInitialize handles the async parts of player initialization
 // Reports errors via OnError callback and updates state when ready
-func (p *MPVPlayer) asyncInitialize(ctx context.Context, ipcConfig *IPCConfig) {
+func (p *MPVPlayer) asyncInitialize(ctx context.Context, ipcConfig *IPCConfig, exit *processExit) {
 	// Create a timeout context for the initialization
 	initCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	// Wait for IPC to be ready
-	if err := p.waitForIPC(initCtx); err != nil {
-		p.mu.Lock()
-		errorCallback := p.onError
-		p.mu.Unlock()
-
-		// Kill the process and cleanup
-		if p.cmd != nil && p.cmd.Process != nil {
-			_ = p.cmd.Process.Kill()
-		}
-		p.cleanupIPC()
+	if err := p.waitForIPC(initCtx, ipcConfig, exit); err != nil {
+		errorCallback := p.abortLaunch(exit)
 
 		if errorCallback != nil {
 			if p.platform == PlatformWindows {
@@ -200,10 +216,6 @@ func (p *MPVPlayer) asyncInitialize(ctx context.Context, ipcConfig *IPCConfig) {
 				errorCallback(fmt.Errorf("timeout waiting for mpv IPC at %s: %w", ipcConfig.Address, err))
 			}
 		}
-
-		p.mu.Lock()
-		p.state = player.StateError
-		p.mu.Unlock()
 		return
 	}
 
@@ -218,15 +230,7 @@ func (p *MPVPlayer) asyncInitialize(ctx context.Context, ipcConfig *IPCConfig) {
 		}
 	})
 	if err != nil {
-		p.mu.Lock()
-		errorCallback := p.onError
-		p.mu.Unlock()
-
-		// Kill the process and cleanup
-		if p.cmd != nil && p.cmd.Process != nil {
-			_ = p.cmd.Process.Kill()
-		}
-		p.cleanupIPC()
+		errorCallback := p.abortLaunch(exit)
 
 		if errorCallback != nil {
 			// Provide platform-specific error messages
@@ -237,22 +241,38 @@ func (p *MPVPlayer) asyncInitialize(ctx context.Context, ipcConfig *IPCConfig) {
 				errorCallback(fmt.Errorf("failed to connect to mpv IPC at %s: %w", connStr, err))
 			}
 		}
-
-		p.mu.Lock()
-		p.state = player.StateError
-		p.mu.Unlock()
 		return
 	}
 
 	p.mu.Lock()
+	if p.exit != exit || p.state == player.StateStopped {
+		// Stopped or replaced while connecting; the dead process closes the client
+		p.mu.Unlock()
+		return
+	}
 	p.client = client
 	p.clientClosed = false // Reset for new connection
 	p.state = player.StatePlaying
 	p.mu.Unlock()
 
-	// Start monitoring goroutines
+	// Start monitoring progress
 	go p.monitorProgress()
-	go p.monitorProcess()
+}
+
+// abortLaunch kills the mpv process of a launch that failed, unless a newer
+// playback replaced it, and returns the error callback
+func (p *MPVPlayer) abortLaunch(exit *processExit) func(error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.exit == exit {
+		if p.cmd != nil && p.cmd.Process != nil {
+			_ = p.cmd.Process.Kill()
+		}
+		p.cleanupIPC()
+		p.state = player.StateError
+	}
+	return p.onError
 }
 
 // Stop stops playback and cleans up resources
@@ -316,6 +336,9 @@ func (p *MPVPlayer) stopLocked() error {
 	}
 	p.cmd = nil
 
+	// mpv has written its watch-later file by the time it exits
+	p.collectWatchLater()
+
 	// Cleanup IPC resources
 	p.cleanupIPC()
 
@@ -357,8 +380,8 @@ func (p *MPVPlayer) GetProgress(ctx context.Context) (*player.PlaybackProgress,
 
 // getProgressLocked gets progress without locking (must be called with lock held)
 func (p *MPVPlayer) getProgressLocked() (*player.PlaybackProgress, error) {
-	var timePos, duration, volume, speed float64
-	var paused, eof bool
+	var timePos, duration, volume, speed, subDelay, cacheSpeed float64
+	var paused, eof, buffering bool
 	var propertyErrors int
 
 	// Get properties from mpv
@@ -419,6 +442,27 @@ func (p *MPVPlayer) getProgressLocked() (*player.PlaybackProgress, error) {
 		}
 	}
 
+	// Playback stalled waiting for the network
+	if result, err := p.client.Request("get_property", "paused-for-cache"); err == nil {
+		if val, ok := result.(bool); ok {
+			buffering = val
+		}
+	}
+
+	// Bytes per second read from the network, averaged over the last second
+	if result, err := p.client.Request("get_property", "cache-speed"); err == nil {
+		if val, ok := result.(float64); ok {
+			cacheSpeed = val
+		}
+	}
+
+	// Adjusted with z/x in mpv; 0 when there are no subtitles
+	if result, err := p.client.Request("get_property", "sub-delay"); err == nil {
+		if val, ok := result.(float64); ok {
+			subDelay = val
+		}
+	}
+
 	// If we got too many property errors, the IPC connection is likely dead
 	if propertyErrors >= 3 {
 		if runtime.GOOS == "windows" {
@@ -441,6 +485,10 @@ func (p *MPVPlayer) getProgressLocked() (*player.PlaybackProgress, error) {
 		Volume:      int(volume),
 		Speed:       speed,
 		EOF:         eof,
+		Buffering:   buffering,
+		CacheSpeed:  int64(cacheSpeed),
+
+		SubtitleDelay: time.Duration(subDelay * float64(time.Second)).Round(time.Millisecond),
 	}, nil
 }
 
@@ -461,6 +509,108 @@ func (p *MPVPlayer) Seek(ctx context.Context, position time.Duration) error {
 	return nil
 }
 
+// SwitchStream replaces the stream of the running playback with url without
+// restarting mpv, starting at options.StartTime. The headers, referer,
+// subtitles and audio track of options replace those of the old stream.
+func (p *MPVPlayer) SwitchStream(ctx context.Context, url string, options player.PlayOptions) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return fmt.Errorf("player not initialized")
+	}
+
+	var aid any = "auto"
+	if options.AudioTrack > 0 {
+		aid = options.AudioTrack
+	}
+	// Options set at runtime apply to the next file loaded
+	properties := []struct {
+		name  string
+		value any
+	}{
+		{"referrer", options.Referer},
+		{"http-header-fields", httpHeaderFields(options.Headers)},
+		{"ytdl", options.YTDL},
+		{"aid", aid},
+		{"start", fmt.Sprintf("%f", options.StartTime.Seconds())},
+	}
+	for _, prop := range properties {
+		if _, err := p.client.Request("set_property", prop.name, prop.value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", prop.name, err)
+		}
+	}
+	if _, err := p.client.Request("change-list", "sub-files", "clr", ""); err != nil {
+		return fmt.Errorf("failed to clear subtitles: %w", err)
+	}
+	if options.SubtitleURL != "" {
+		if _, err := p.client.Request("change-list", "sub-files", "append", options.SubtitleURL); err != nil {
+			return fmt.Errorf("failed to set subtitles: %w", err)
+		}
+	}
+
+	if _, err := p.client.Request("loadfile", url, "replace"); err != nil {
+		return fmt.Errorf("failed to load stream: %w", err)
+	}
+	p.currentURL = url
+	p.options = options
+	return nil
+}
+
+// SetAudioNormalization switches the loudness normalization of the running
+// playback to mode
+func (p *MPVPlayer) SetAudioNormalization(ctx context.Context, mode string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return fmt.Errorf("player not initialized")
+	}
+
+	// Removing a label that isn't there is not an error worth reporting
+	_, _ = p.client.Request("af", "remove", normalizationLabel)
+	if filter := normalizationFilter(mode); filter != "" {
+		if _, err := p.client.Request("af", "add", filter); err != nil {
+			return fmt.Errorf("failed to set audio filter: %w", err)
+		}
+	}
+	p.options.AudioNormalization = mode
+	return nil
+}
+
+// normalizationLabel names greg's filter in mpv's filter chain
+const normalizationLabel = "@greg-normalize"
+
+// normalizationFilter returns the labelled mpv audio filter for a
+// normalization mode, or "" for none
+func normalizationFilter(mode string) string {
+	switch mode {
+	case player.NormalizationLoudnorm:
+		// Streaming service targets: -16 LUFS, -1.5 dBTP
+		return normalizationLabel + ":lavfi=[loudnorm=I=-16:TP=-1.5:LRA=11]"
+	case player.NormalizationDynaudnorm:
+		return normalizationLabel + ":lavfi=[dynaudnorm=f=250:g=31:p=0.9]"
+	default:
+		return ""
+	}
+}
+
+// SetPaused pauses or resumes playback
+func (p *MPVPlayer) SetPaused(ctx context.Context, paused bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return fmt.Errorf("player not initialized")
+	}
+
+	if _, err := p.client.Request("set_property", "pause", paused); err != nil {
+		return fmt.Errorf("failed to set pause: %w", err)
+	}
+
+	return nil
+}
+
 // OnProgressUpdate sets the progress update callback
 func (p *MPVPlayer) OnProgressUpdate(callback func(progress player.PlaybackProgress)) {
 	p.mu.Lock()
@@ -535,33 +685,47 @@ func (p *MPVPlayer) monitorProgress() {
 	}
 }
 
-// monitorProcess monitors the mpv process and handles unexpected exits
-func (p *MPVPlayer) monitorProcess() {
-	// Take a local reference to cmd so we can wait even if p.cmd becomes nil
-	p.mu.RLock()
-	cmd := p.cmd
-	p.mu.RUnlock()
-
-	if cmd == nil {
-		return
-	}
-
+// monitorProcess waits for an mpv process to exit, reports the exit through
+// exit and cleans up when it was the current playback
+func (p *MPVPlayer) monitorProcess(cmd *exec.Cmd, exit *processExit) {
 	// This will block until the process exits (killed or natural exit)
 	err := cmd.Wait()
+	exit.err = err
+	close(exit.done)
 
-	// Only report error if it wasn't a normal kill signal
 	p.mu.Lock()
+	if p.cmd != cmd {
+		// Stopped on purpose, or replaced by a newer playback
+		p.mu.Unlock()
+		return
+	}
 	errorCallback := p.onError
 	currentState := p.state
+	_ = p.stopLocked()
 	p.mu.Unlock()
 
 	// Don't report error if we're already stopped (user requested quit)
 	if err != nil && errorCallback != nil && currentState != player.StateStopped {
 		errorCallback(fmt.Errorf("mpv process exited unexpectedly: %w", err))
 	}
+}
+
+// Wait blocks until the mpv process of the latest playback exits and returns
+// its exit error. It returns nil right away when nothing was started.
+func (p *MPVPlayer) Wait(ctx context.Context) error {
+	p.mu.RLock()
+	exit := p.exit
+	p.mu.RUnlock()
 
-	// Clean up after process exits (safe to call multiple times)
-	_ = p.Stop(context.Background())
+	if exit == nil {
+		return nil
+	}
+	select {
+	case <-exit.done:
+		return exit.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // buildMPVArgs builds the command-line arguments for mpv
@@ -569,7 +733,11 @@ func (p *MPVPlayer) buildMPVArgs(url string, opts player.PlayOptions) []string {
 	args := []string{
 		GetMPVIPCArgument(p.ipcConfig),
 		"--idle=yes", // Keep mpv running even after playback ends
-		"--no-ytdl",  // Disable youtube-dl/yt-dlp hook for direct streams
+	}
+	if opts.YTDL {
+		args = append(args, "--ytdl=yes") // Page URL, resolved by yt-dlp
+	} else {
+		args = append(args, "--no-ytdl") // Disable youtube-dl/yt-dlp hook for direct streams
 	}
 
 	// Only add --no-config if loadUserConfig is false
@@ -583,6 +751,22 @@ func (p *MPVPlayer) buildMPVArgs(url string, opts player.PlayOptions) []string {
 		args = append(args, "--msg-level=all=warn")
 	}
 
+	// Hardware decoding and performance profile, before the custom args so
+	// those can still override single options
+	switch p.hwdec {
+	case "":
+	case "off":
+		args = append(args, "--hwdec=no")
+	default:
+		args = append(args, "--hwdec="+p.hwdec)
+	}
+	args = append(args, profileArgs(p.profile)...)
+
+	// Where Shift+Q (quit-watch-later) saves the position, read back once mpv exits
+	if p.watchLater != "" {
+		args = append(args, "--watch-later-dir="+p.watchLater)
+	}
+
 	// Start time
 	if opts.StartTime > 0 {
 		args = append(args, fmt.Sprintf("--start=%f", opts.StartTime.Seconds()))
@@ -612,7 +796,7 @@ func (p *MPVPlayer) buildMPVArgs(url string, opts player.PlayOptions) []string {
 		args = append(args, fmt.Sprintf("--slang=%s", opts.SubtitleLang))
 	}
 
-	if opts.SubtitleDelay > 0 {
+	if opts.SubtitleDelay != 0 {
 		args = append(args, fmt.Sprintf("--sub-delay=%f", opts.SubtitleDelay.Seconds()))
 	}
 
@@ -621,6 +805,14 @@ func (p *MPVPlayer) buildMPVArgs(url string, opts player.PlayOptions) []string {
 		args = append(args, fmt.Sprintf("--aid=%d", opts.AudioTrack))
 	}
 
+	// Loudness normalization, appended so filters from the user's mpv.conf stay
+	if filter := normalizationFilter(opts.AudioNormalization); filter != "" {
+		args = append(args, "--af-append="+filter)
+	}
+	if opts.ReplayGain == "track" || opts.ReplayGain == "album" {
+		args = append(args, "--replaygain="+opts.ReplayGain)
+	}
+
 	// User-Agent
 	if opts.UserAgent != "" {
 		args = append(args, fmt.Sprintf("--user-agent=%s", opts.UserAgent))
@@ -634,18 +826,9 @@ func (p *MPVPlayer) buildMPVArgs(url string, opts player.PlayOptions) []string {
 		args = append(args, fmt.Sprintf("--referrer=%s", opts.Referer))
 	}
 
-	// Additional HTTP headers (Origin, etc)
-	headersList := []string{}
-	for key, value := range opts.Headers {
-		if key != "User-Agent" && key != "Referer" { // Already handled above
-			headersList = append(headersList, fmt.Sprintf("%s: %s", key, value))
-		}
-	}
-
-	// Add other headers as comma-separated list in a single argument
-	if len(headersList) > 0 {
-		headersStr := strings.Join(headersList, ",")
-		args = append(args, fmt.Sprintf("--http-header-fields=%s", headersStr))
+	// Additional HTTP headers (Origin, etc) as comma-separated list in a single argument
+	if headers := httpHeaderFields(opts.Headers); headers != "" {
+		args = append(args, fmt.Sprintf("--http-header-fields=%s", headers))
 	}
 
 	// Title - use force-media-title to ensure it's displayed in mpv
@@ -662,11 +845,58 @@ func (p *MPVPlayer) buildMPVArgs(url string, opts player.PlayOptions) []string {
 	return args
 }
 
+// httpHeaderFields formats headers for mpv's http-header-fields, leaving out
+// the User-Agent and Referer, which have their own options
+func httpHeaderFields(headers map[string]string) string {
+	var fields []string
+	for key, value := range headers {
+		if key != "User-Agent" && key != "Referer" {
+			fields = append(fields, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// profileArgs returns the mpv options of a performance profile: "low-end"
+// trades picture quality for smooth playback on weak laptops, "desktop" uses
+// the higher quality scalers a dedicated GPU handles easily
+func profileArgs(profile string) []string {
+	switch profile {
+	case "low-end":
+		return []string{
+			"--scale=bilinear",
+			"--cscale=bilinear",
+			"--dscale=bilinear",
+			"--dither-depth=no",
+			"--correct-downscaling=no",
+			"--linear-downscaling=no",
+			"--sigmoid-upscaling=no",
+			"--deband=no",
+			"--vd-lavc-skiploopfilter=nonkey",
+			"--framedrop=vo",
+			"--demuxer-max-bytes=50MiB",
+		}
+	case "desktop":
+		return []string{
+			"--scale=ewa_lanczossharp",
+			"--cscale=ewa_lanczossharp",
+			"--dscale=mitchell",
+			"--correct-downscaling=yes",
+			"--linear-downscaling=yes",
+			"--sigmoid-upscaling=yes",
+			"--deband=yes",
+			"--demuxer-max-bytes=400MiB",
+		}
+	default:
+		return nil
+	}
+}
+
 // waitForIPC waits for the IPC connection to be ready
-func (p *MPVPlayer) waitForIPC(ctx context.Context) error {
+func (p *MPVPlayer) waitForIPC(ctx context.Context, ipcConfig *IPCConfig, exit *processExit) error {
 	// Use longer timeout for named pipes and TCP (mpv.exe takes longer to start from WSL)
 	timeoutDuration := 5 * time.Second
-	if p.ipcConfig.Type == IPCTCP || p.ipcConfig.Type == IPCNamedPipe {
+	if ipcConfig.Type == IPCTCP || ipcConfig.Type == IPCNamedPipe {
 		timeoutDuration = 10 * time.Second
 	}
 
@@ -681,28 +911,33 @@ func (p *MPVPlayer) waitForIPC(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-exit.done:
+			if exit.err != nil {
+				return fmt.Errorf("mpv exited before IPC was ready: %w", exit.err)
+			}
+			return fmt.Errorf("mpv exited before IPC was ready")
 		case <-timeout:
-			return fmt.Errorf("timeout waiting for IPC at %s after %v", p.ipcConfig.Address, timeoutDuration)
+			return fmt.Errorf("timeout waiting for IPC at %s after %v", ipcConfig.Address, timeoutDuration)
 		case <-ticker.C:
-			if p.ipcConfig.IsSocket {
+			if ipcConfig.IsSocket {
 				// For Unix sockets, check if file exists
-				if _, err := os.Stat(p.ipcConfig.Address); err == nil {
+				if _, err := os.Stat(ipcConfig.Address); err == nil {
 					// Socket exists, wait a bit more for it to be ready
 					time.Sleep(200 * time.Millisecond)
 					return nil
 				}
-			} else if p.ipcConfig.Type == IPCTCP {
+			} else if ipcConfig.Type == IPCTCP {
 				// For TCP, try to connect
-				conn, err := net.DialTimeout("tcp", p.ipcConfig.Address, 200*time.Millisecond)
+				conn, err := net.DialTimeout("tcp", ipcConfig.Address, 200*time.Millisecond)
 				if err == nil {
 					_ = conn.Close()
 					// Wait a bit longer to ensure mpv IPC is fully ready
 					time.Sleep(300 * time.Millisecond)
 					return nil
 				}
-			} else if p.ipcConfig.Type == IPCNamedPipe {
+			} else if ipcConfig.Type == IPCNamedPipe {
 				// For Windows named pipes, try to check if pipe is accessible
-				if isPipeReady(p.ipcConfig.Address) {
+				if isPipeReady(ipcConfig.Address) {
 					// Pipe is ready, wait a bit more for mpv to be fully initialized
 					time.Sleep(200 * time.Millisecond)
 					return nil
@@ -1,7 +1,12 @@
 package mpv
 
 import (
+	"context"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -164,6 +169,18 @@ func TestBuildMPVArgs(t *testing.T) {
 				"https://example.com/video.mp4",
 			},
 		},
+		{
+			name: "page resolved by yt-dlp",
+			url:  "https://example.com/watch/ep1",
+			options: player.PlayOptions{
+				YTDL: true,
+			},
+			expected: []string{
+				"--idle=yes",
+				"--ytdl=yes",
+				"https://example.com/watch/ep1",
+			},
+		},
 		{
 			name: "with custom mpv args",
 			url:  "https://example.com/video.mp4",
@@ -211,6 +228,30 @@ func TestBuildMPVArgs(t *testing.T) {
 				"https://example.com/video.mp4",
 			},
 		},
+		{
+			name: "audio normalization",
+			url:  "https://example.com/video.mp4",
+			options: player.PlayOptions{
+				AudioNormalization: player.NormalizationLoudnorm,
+				ReplayGain:         "album",
+			},
+			expected: []string{
+				"--af-append=@greg-normalize:lavfi=[loudnorm=I=-16:TP=-1.5:LRA=11]",
+				"--replaygain=album",
+				"https://example.com/video.mp4",
+			},
+		},
+		{
+			name: "negative subtitle delay",
+			url:  "https://example.com/video.mp4",
+			options: player.PlayOptions{
+				SubtitleDelay: -1500 * time.Millisecond,
+			},
+			expected: []string{
+				"--sub-delay=-1.5",
+				"https://example.com/video.mp4",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -248,6 +289,29 @@ func TestBuildMPVArgs(t *testing.T) {
 	}
 }
 
+func TestBuildMPVArgsPerformance(t *testing.T) {
+	ipcConfig := &IPCConfig{Type: IPCUnixSocket, Address: "/tmp/test.sock", IsSocket: true}
+
+	p := &MPVPlayer{ipcConfig: ipcConfig}
+	args := p.buildMPVArgs("https://example.com/video.mp4", player.PlayOptions{})
+	for _, arg := range args {
+		assert.NotContains(t, arg, "--hwdec")
+		assert.NotContains(t, arg, "--scale=")
+	}
+
+	p = &MPVPlayer{ipcConfig: ipcConfig, hwdec: "off", profile: "low-end"}
+	args = p.buildMPVArgs("https://example.com/video.mp4", player.PlayOptions{MPVArgs: []string{"--framedrop=no"}})
+	assert.Contains(t, args, "--hwdec=no")
+	assert.Contains(t, args, "--scale=bilinear")
+	// Custom args come after the profile, so they win
+	assert.Greater(t, slices.Index(args, "--framedrop=no"), slices.Index(args, "--framedrop=vo"))
+
+	p = &MPVPlayer{ipcConfig: ipcConfig, hwdec: "vaapi", profile: "desktop"}
+	args = p.buildMPVArgs("https://example.com/video.mp4", player.PlayOptions{})
+	assert.Contains(t, args, "--hwdec=vaapi")
+	assert.Contains(t, args, "--scale=ewa_lanczossharp")
+}
+
 func TestPlayerState(t *testing.T) {
 	p, err := NewMPVPlayer()
 	require.NoError(t, err)
@@ -324,3 +388,81 @@ func TestStopWhenAlreadyStopped(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, player.StateStopped, p.state)
 }
+
+func TestWaitReportsProcessExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+
+	// Stand-in for an mpv process that gets killed from outside greg
+	cmd := exec.Command("sh", "-c", "exit 3")
+	require.NoError(t, cmd.Start())
+
+	p := &MPVPlayer{state: player.StatePlaying, cmd: cmd, exit: &processExit{done: make(chan struct{})}}
+	reported := make(chan error, 1)
+	p.OnError(func(err error) { reported <- err })
+	go p.monitorProcess(cmd, p.exit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := p.Wait(ctx)
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode())
+
+	// The player cleans up once the process is gone
+	select {
+	case err := <-reported:
+		assert.ErrorContains(t, err, "exited unexpectedly")
+	case <-ctx.Done():
+		t.Fatal("exit was not reported")
+	}
+	assert.False(t, p.IsPlaying())
+}
+
+func TestWaitWithoutPlayback(t *testing.T) {
+	p := &MPVPlayer{state: player.StateStopped}
+	assert.NoError(t, p.Wait(context.Background()))
+}
+
+func TestSavedPosition(t *testing.T) {
+	dir := t.TempDir()
+	ipcConfig := &IPCConfig{Type: IPCUnixSocket, Address: "/tmp/test.sock", IsSocket: true}
+	p := &MPVPlayer{ipcConfig: ipcConfig, watchLater: dir}
+	args := p.buildMPVArgs("https://example.com/video.mp4", player.PlayOptions{})
+	assert.Contains(t, args, "--watch-later-dir="+dir)
+
+	// Nothing saved when mpv was quit normally
+	_, ok := (&MPVPlayer{watchLater: t.TempDir()}).SavedPosition()
+	assert.False(t, ok)
+
+	content := "# https://example.com/video.mp4\nvolume=80.000000\nstart=754.250000\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0123456789ABCDEF0123456789ABCDEF"), []byte(content), 0644))
+	position, ok := p.SavedPosition()
+	require.True(t, ok)
+	assert.Equal(t, 754250*time.Millisecond, position)
+
+	// Read once, the directory is gone afterwards
+	_, ok = p.SavedPosition()
+	assert.False(t, ok)
+	assert.NoDirExists(t, dir)
+}
+
+func TestStopRemovesWatchLaterDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "watch-later")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	content := "# https://example.com/video.mp4\nstart=12.500000\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0123456789ABCDEF0123456789ABCDEF"), []byte(content), 0644))
+
+	p := &MPVPlayer{state: player.StatePlaying, watchLater: dir}
+	require.NoError(t, p.Stop(context.Background()))
+	assert.NoDirExists(t, dir)
+
+	// The position survives the directory
+	position, ok := p.SavedPosition()
+	require.True(t, ok)
+	assert.Equal(t, 12500*time.Millisecond, position)
+	_, ok = p.SavedPosition()
+	assert.False(t, ok)
+}
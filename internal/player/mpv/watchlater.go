@@ -0,0 +1,96 @@
+package mpv
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newWatchLaterDir replaces the watch-later directory of the previous
+// playback with an empty one, so a position found in it after mpv exits
+// was saved by this playback
+func (p *MPVPlayer) newWatchLaterDir() {
+	if p.watchLater != "" {
+		_ = os.RemoveAll(p.watchLater)
+		p.watchLater = ""
+	}
+	p.savedPosition, p.hasSavedPosition = 0, false
+	if dir, err := os.MkdirTemp("", "greg-watch-later-"); err == nil {
+		p.watchLater = dir
+	}
+}
+
+// collectWatchLater keeps the position mpv saved, if any, and removes the
+// watch-later directory. Must be called with the lock held.
+func (p *MPVPlayer) collectWatchLater() {
+	if p.watchLater == "" {
+		return
+	}
+	if position, ok := readWatchLater(p.watchLater); ok {
+		p.savedPosition, p.hasSavedPosition = position, true
+	}
+	_ = os.RemoveAll(p.watchLater)
+	p.watchLater = ""
+}
+
+// SavedPosition returns the position mpv saved when it was quit with
+// quit-watch-later (Shift+Q), which is more precise than the last polled
+// progress. It is only returned once.
+func (p *MPVPlayer) SavedPosition() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.collectWatchLater()
+	position, ok := p.savedPosition, p.hasSavedPosition
+	p.savedPosition, p.hasSavedPosition = 0, false
+	return position, ok
+}
+
+// readWatchLater reads the start position from the watch-later files mpv
+// wrote to dir, one per file played
+func readWatchLater(dir string) (time.Duration, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, false
+	}
+
+	var position time.Duration
+	var found bool
+	var latest time.Time
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || (found && info.ModTime().Before(latest)) {
+			continue
+		}
+		if start, ok := watchLaterStart(filepath.Join(dir, entry.Name())); ok {
+			position, found, latest = start, true, info.ModTime()
+		}
+	}
+	return position, found
+}
+
+// watchLaterStart parses the "start=<seconds>" line of a watch-later file
+func watchLaterStart(path string) (time.Duration, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "start=")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	return 0, false
+}
@@ -14,6 +14,11 @@ type Player interface {
 	// Progress monitoring
 	GetProgress(ctx context.Context) (*PlaybackProgress, error)
 	Seek(ctx context.Context, position time.Duration) error
+	SetPaused(ctx context.Context, paused bool) error
+
+	// Process supervision: blocks until the player process of the latest
+	// playback exits and returns its exit error
+	Wait(ctx context.Context) error
 
 	// Callbacks
 	OnProgressUpdate(callback func(progress PlaybackProgress))
@@ -39,7 +44,9 @@ type PlayOptions struct {
 	SubtitleDelay time.Duration `json:"subtitle_delay,omitempty"`
 
 	// Audio options
-	AudioTrack int `json:"audio_track,omitempty"`
+	AudioTrack         int    `json:"audio_track,omitempty"`
+	AudioNormalization string `json:"audio_normalization,omitempty"` // One of the Normalization modes
+	ReplayGain         string `json:"replaygain,omitempty"`          // "track" or "album", else off
 
 	// mpv-specific options
 	MPVArgs []string `json:"mpv_args,omitempty"`
@@ -49,12 +56,25 @@ type PlayOptions struct {
 	Referer   string            `json:"referer,omitempty"`
 	UserAgent string            `json:"user_agent,omitempty"`
 
+	// Let mpv's ytdl hook resolve the URL (web pages extracted by yt-dlp)
+	YTDL bool `json:"ytdl,omitempty"`
+
 	// Metadata for display/tracking
 	Title   string `json:"title,omitempty"`
 	Episode int    `json:"episode,omitempty"`
 	Season  int    `json:"season,omitempty"`
 }
 
+// Audio normalization modes
+const (
+	NormalizationOff        = "off"
+	NormalizationLoudnorm   = "loudnorm"   // EBU R128 loudness, steady across shows
+	NormalizationDynaudnorm = "dynaudnorm" // Dynamic, lifts quiet dialogue and tames loud scenes
+)
+
+// NormalizationModes lists the audio normalization modes in cycling order
+var NormalizationModes = []string{NormalizationOff, NormalizationLoudnorm, NormalizationDynaudnorm}
+
 // PlaybackProgress represents the current playback state
 type PlaybackProgress struct {
 	CurrentTime time.Duration `json:"current_time"`
@@ -63,7 +83,11 @@ type PlaybackProgress struct {
 	Paused      bool          `json:"paused"`
 	Volume      int           `json:"volume"`
 	Speed       float64       `json:"speed"`
-	EOF         bool          `json:"eof"` // End of file reached
+	EOF         bool          `json:"eof"`         // End of file reached
+	Buffering   bool          `json:"buffering"`   // Stalled waiting for the network
+	CacheSpeed  int64         `json:"cache_speed"` // Bytes per second read from the network
+
+	SubtitleDelay time.Duration `json:"subtitle_delay,omitempty"` // mpv's sub-delay, negative shows subtitles earlier
 }
 
 // PlaybackState represents the state of the player
@@ -0,0 +1,84 @@
+package playlist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/browser"
+	"golang.org/x/oauth2"
+)
+
+// RedirectURI is the loopback address a service redirects to after sign-in;
+// register it with the OAuth client
+func RedirectURI(port int) string {
+	return fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+}
+
+// Authorize signs in through the browser with the authorization code flow
+// and PKCE, receiving the code on the loopback RedirectURI. Returns a client
+// that authenticates its requests.
+func Authorize(ctx context.Context, conf *oauth2.Config, port int) (*http.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the sign-in redirect: %w", err)
+	}
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	state := hex.EncodeToString(stateBytes)
+	verifier := oauth2.GenerateVerifier()
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "Sign-in failed: state mismatch", http.StatusBadRequest)
+			errCh <- fmt.Errorf("state mismatch in sign-in redirect")
+		case query.Get("error") != "":
+			http.Error(w, "Sign-in failed: "+query.Get("error"), http.StatusBadRequest)
+			errCh <- fmt.Errorf("oauth error: %s", query.Get("error"))
+		default:
+			_, _ = fmt.Fprint(w, "Signed in. You can close this window and return to the terminal.")
+			codeCh <- query.Get("code")
+		}
+	})
+	srv := &http.Server{Handler: mux}
+	go func() { _ = srv.Serve(listener) }()
+	defer func() { _ = srv.Shutdown(context.Background()) }()
+
+	conf.RedirectURL = RedirectURI(port)
+	authURL := conf.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	fmt.Printf("If the browser doesn't open automatically, visit: %s\n", authURL)
+	if err := browser.OpenURL(authURL); err != nil {
+		fmt.Printf("Failed to open browser automatically: %v\n", err)
+	}
+
+	var code string
+	select {
+	case code = <-codeCh:
+	case err := <-errCh:
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("authentication timeout after 5 minutes")
+	}
+
+	token, err := conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	return conf.Client(context.Background(), token), nil
+}
@@ -0,0 +1,80 @@
+// Package playlist exports the opening and ending songs saved while
+// watching to a file or a music service playlist
+package playlist
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// Service is a music service a playlist can be created on
+type Service interface {
+	// Find returns the service's ID of the best match for a track, or ""
+	// when there is none
+	Find(ctx context.Context, track database.PlaylistTrack) (string, error)
+	// Create creates a private playlist of the found tracks and returns
+	// its URL
+	Create(ctx context.Context, name string, ids []string) (string, error)
+}
+
+// Export looks up every track on svc and creates a playlist of the ones
+// found. Returns the playlist URL and the tracks that weren't found.
+func Export(ctx context.Context, svc Service, name string, tracks []database.PlaylistTrack) (string, []database.PlaylistTrack, error) {
+	var ids []string
+	var missing []database.PlaylistTrack
+	for _, track := range tracks {
+		id, err := svc.Find(ctx, track)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to look up %q: %w", track.Song, err)
+		}
+		if id == "" {
+			missing = append(missing, track)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return "", missing, fmt.Errorf("none of the %d songs were found", len(tracks))
+	}
+
+	url, err := svc.Create(ctx, name, ids)
+	if err != nil {
+		return "", missing, fmt.Errorf("failed to create playlist: %w", err)
+	}
+	return url, missing, nil
+}
+
+// Query is the search query for a track
+func Query(track database.PlaylistTrack) string {
+	if track.Artist == "" {
+		return track.Song
+	}
+	return track.Song + " " + track.Artist
+}
+
+// WriteCSV writes tracks as CSV with a header row
+func WriteCSV(w io.Writer, tracks []database.PlaylistTrack) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"song", "artist", "anime", "theme", "episode", "added"}); err != nil {
+		return err
+	}
+	for _, track := range tracks {
+		episode := ""
+		if track.Episode > 0 {
+			episode = strconv.Itoa(track.Episode)
+		}
+		if err := out.Write([]string{
+			track.Song, track.Artist, track.MediaTitle, track.Theme, episode, track.AddedAt.Format(time.DateOnly),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,140 @@
+package playlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+var testTracks = []database.PlaylistTrack{
+	{MediaTitle: "One Piece", Theme: "OP1", Song: "We Are!", Artist: "Hiroshi Kitadani", Episode: 3, AddedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	{MediaTitle: "Obscure", Theme: "ED1", Song: "Unknown Song", AddedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testTracks))
+	assert.Equal(t, "song,artist,anime,theme,episode,added\n"+
+		"We Are!,Hiroshi Kitadani,One Piece,OP1,3,2026-03-01\n"+
+		"Unknown Song,,Obscure,ED1,,2026-03-02\n", buf.String())
+}
+
+func TestQuery(t *testing.T) {
+	assert.Equal(t, "We Are! Hiroshi Kitadani", Query(testTracks[0]))
+	assert.Equal(t, "Unknown Song", Query(testTracks[1]))
+}
+
+func TestExportSpotify(t *testing.T) {
+	var added []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "track", r.URL.Query().Get("type"))
+		if r.URL.Query().Get("q") == "We Are! Hiroshi Kitadani" {
+			fmt.Fprint(w, `{"tracks":{"items":[{"uri":"spotify:track:1"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"tracks":{"items":[]}}`)
+	})
+	mux.HandleFunc("POST /me/playlists", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "greg themes", body["name"])
+		assert.Equal(t, false, body["public"])
+		fmt.Fprint(w, `{"id":"pl1","external_urls":{"spotify":"https://open.spotify.com/playlist/pl1"}}`)
+	})
+	mux.HandleFunc("POST /playlists/pl1/tracks", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		added = append(added, body.URIs...)
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewSpotify(server.Client())
+	svc.BaseURL = server.URL
+
+	url, missing, err := Export(context.Background(), svc, "greg themes", testTracks)
+	require.NoError(t, err)
+	assert.Equal(t, "https://open.spotify.com/playlist/pl1", url)
+	assert.Equal(t, []string{"spotify:track:1"}, added)
+	require.Len(t, missing, 1)
+	assert.Equal(t, "Unknown Song", missing[0].Song)
+}
+
+func TestExportYouTube(t *testing.T) {
+	var added []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.URL.Query().Get("videoCategoryId"))
+		fmt.Fprint(w, `{"items":[{"id":{"videoId":"vid-`+r.URL.Query().Get("q")[:2]+`"}}]}`)
+	})
+	mux.HandleFunc("POST /playlists", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"PL1"}`)
+	})
+	mux.HandleFunc("POST /playlistItems", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Snippet struct {
+				PlaylistID string `json:"playlistId"`
+				ResourceID struct {
+					VideoID string `json:"videoId"`
+				} `json:"resourceId"`
+			} `json:"snippet"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "PL1", body.Snippet.PlaylistID)
+		added = append(added, body.Snippet.ResourceID.VideoID)
+		fmt.Fprint(w, `{}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewYouTube(server.Client())
+	svc.BaseURL = server.URL
+
+	url, missing, err := Export(context.Background(), svc, "greg themes", testTracks)
+	require.NoError(t, err)
+	assert.Equal(t, "https://music.youtube.com/playlist?list=PL1", url)
+	assert.Equal(t, []string{"vid-We", "vid-Un"}, added)
+	assert.Empty(t, missing)
+}
+
+func TestExportNothingFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tracks":{"items":[]}}`)
+	}))
+	defer server.Close()
+
+	svc := NewSpotify(server.Client())
+	svc.BaseURL = server.URL
+
+	_, missing, err := Export(context.Background(), svc, "greg themes", testTracks)
+	assert.Error(t, err)
+	assert.Len(t, missing, 2)
+}
+
+func TestExportServiceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid token"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	svc := NewSpotify(server.Client())
+	svc.BaseURL = server.URL
+
+	_, _, err := Export(context.Background(), svc, "greg themes", testTracks)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
@@ -0,0 +1,200 @@
+package playlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+
+	"github.com/justchokingaround/greg/internal/database"
+)
+
+// playlistDescription is set on exported playlists
+const playlistDescription = "Anime openings and endings saved with greg"
+
+// SpotifyOAuth is the OAuth config for a Spotify app's client ID. Spotify
+// needs no client secret with PKCE.
+func SpotifyOAuth(clientID string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: clientID,
+		Endpoint: endpoints.Spotify,
+		Scopes:   []string{"playlist-modify-private"},
+	}
+}
+
+// YouTubeOAuth is the OAuth config for a Google "desktop app" client.
+// Playlists created on YouTube show up in YouTube Music.
+func YouTubeOAuth(clientID, clientSecret string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint:     endpoints.Google,
+		Scopes:       []string{"https://www.googleapis.com/auth/youtube"},
+	}
+}
+
+// Spotify creates playlists through the Spotify Web API
+type Spotify struct {
+	BaseURL string
+	Client  *http.Client // Authenticated, from Authorize
+}
+
+// NewSpotify returns a Spotify service using an authenticated client
+func NewSpotify(client *http.Client) *Spotify {
+	return &Spotify{BaseURL: "https://api.spotify.com/v1", Client: client}
+}
+
+// Find returns the URI of the best matching Spotify track
+func (s *Spotify) Find(ctx context.Context, track database.PlaylistTrack) (string, error) {
+	query := url.Values{}
+	query.Set("q", Query(track))
+	query.Set("type", "track")
+	query.Set("limit", "1")
+
+	var result struct {
+		Tracks struct {
+			Items []struct {
+				URI string `json:"uri"`
+			} `json:"items"`
+		} `json:"tracks"`
+	}
+	if err := doJSON(ctx, s.Client, "GET", s.BaseURL+"/search?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	if len(result.Tracks.Items) == 0 {
+		return "", nil
+	}
+	return result.Tracks.Items[0].URI, nil
+}
+
+// Create creates a private Spotify playlist of track URIs
+func (s *Spotify) Create(ctx context.Context, name string, uris []string) (string, error) {
+	var playlist struct {
+		ID           string `json:"id"`
+		ExternalURLs struct {
+			Spotify string `json:"spotify"`
+		} `json:"external_urls"`
+	}
+	body := map[string]any{"name": name, "public": false, "description": playlistDescription}
+	if err := doJSON(ctx, s.Client, "POST", s.BaseURL+"/me/playlists", body, &playlist); err != nil {
+		return "", err
+	}
+
+	// Spotify adds at most 100 tracks per request
+	for start := 0; start < len(uris); start += 100 {
+		end := min(start+100, len(uris))
+		body := map[string]any{"uris": uris[start:end]}
+		if err := doJSON(ctx, s.Client, "POST", s.BaseURL+"/playlists/"+playlist.ID+"/tracks", body, nil); err != nil {
+			return "", err
+		}
+	}
+	return playlist.ExternalURLs.Spotify, nil
+}
+
+// YouTube creates playlists through the YouTube Data API
+type YouTube struct {
+	BaseURL string
+	Client  *http.Client // Authenticated, from Authorize
+}
+
+// NewYouTube returns a YouTube service using an authenticated client
+func NewYouTube(client *http.Client) *YouTube {
+	return &YouTube{BaseURL: "https://www.googleapis.com/youtube/v3", Client: client}
+}
+
+// Find returns the ID of the best matching music video
+func (y *YouTube) Find(ctx context.Context, track database.PlaylistTrack) (string, error) {
+	query := url.Values{}
+	query.Set("part", "snippet")
+	query.Set("type", "video")
+	query.Set("videoCategoryId", "10") // Music
+	query.Set("maxResults", "1")
+	query.Set("q", Query(track))
+
+	var result struct {
+		Items []struct {
+			ID struct {
+				VideoID string `json:"videoId"`
+			} `json:"id"`
+		} `json:"items"`
+	}
+	if err := doJSON(ctx, y.Client, "GET", y.BaseURL+"/search?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	if len(result.Items) == 0 {
+		return "", nil
+	}
+	return result.Items[0].ID.VideoID, nil
+}
+
+// Create creates a private YouTube playlist of video IDs and returns its
+// YouTube Music URL
+func (y *YouTube) Create(ctx context.Context, name string, videoIDs []string) (string, error) {
+	var playlist struct {
+		ID string `json:"id"`
+	}
+	body := map[string]any{
+		"snippet": map[string]any{"title": name, "description": playlistDescription},
+		"status":  map[string]any{"privacyStatus": "private"},
+	}
+	if err := doJSON(ctx, y.Client, "POST", y.BaseURL+"/playlists?part=snippet,status", body, &playlist); err != nil {
+		return "", err
+	}
+
+	for _, id := range videoIDs {
+		body := map[string]any{"snippet": map[string]any{
+			"playlistId": playlist.ID,
+			"resourceId": map[string]any{"kind": "youtube#video", "videoId": id},
+		}}
+		if err := doJSON(ctx, y.Client, "POST", y.BaseURL+"/playlistItems?part=snippet", body, nil); err != nil {
+			return "", err
+		}
+	}
+	return "https://music.youtube.com/playlist?list=" + playlist.ID, nil
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON
+// response into v when it isn't nil
+func doJSON(ctx context.Context, client *http.Client, method, endpoint string, body, v any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s returned status %d: %s", method, req.URL.Path, resp.StatusCode, string(bodyBytes))
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Path, err)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/types"
 )
@@ -24,13 +25,16 @@ type AllAnime struct {
 	Client      *http.Client
 	searchCache sync.Map
 	infoCache   sync.Map
+
+	mu        sync.RWMutex
+	audioType string // "dub" or "sub" (default)
 }
 
 func New() *AllAnime {
 	return &AllAnime{
 		BaseURL: "https://allanime.to",
 		APIURL:  "https://api.allanime.day",
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: netbind.Transport("allanime")},
 	}
 }
 
@@ -42,6 +46,28 @@ func (a *AllAnime) Type() providers.MediaType {
 	return providers.MediaTypeAnime
 }
 
+// Capabilities reports sub and dub servers in several qualities, without subtitle tracks
+func (a *AllAnime) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true, Qualities: true, Sources: true, Audio: true}
+}
+
+// SetAudioPreference selects the dub or sub translation for later stream lookups
+func (a *AllAnime) SetAudioPreference(preference string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.audioType = providers.AnimeAudioType(preference)
+}
+
+// translationTypes returns the translations to try, preferred first
+func (a *AllAnime) translationTypes() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.audioType == "dub" {
+		return []string{"dub", "sub"}
+	}
+	return []string{"sub"}
+}
+
 // GraphQL response structures
 type searchResponse struct {
 	Data struct {
@@ -82,6 +108,19 @@ type episodeResponse struct {
 	} `json:"data"`
 }
 
+type episodeInfosResponse struct {
+	Data struct {
+		EpisodeInfos []struct {
+			EpisodeIDNum float64  `json:"episodeIdNum"`
+			Notes        string   `json:"notes"`
+			Thumbnails   []string `json:"thumbnails"`
+		} `json:"episodeInfos"`
+	} `json:"data"`
+}
+
+// thumbnailHost serves the episode thumbnails the API lists as paths
+const thumbnailHost = "https://wp.youtube-anime.com/aln.youtube-anime.com/"
+
 type linkData struct {
 	Links []struct {
 		Link string `json:"link"`
@@ -143,6 +182,10 @@ func (a *AllAnime) Search(ctx context.Context, query string) ([]providers.Media,
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(a.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -150,7 +193,7 @@ func (a *AllAnime) Search(ctx context.Context, query string) ([]providers.Media,
 
 	var searchResp searchResponse
 	if err := json.Unmarshal(body, &searchResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, a.Name(), fmt.Errorf("failed to parse response: %w", err))
 	}
 
 	var results []providers.Media
@@ -220,6 +263,10 @@ func (a *AllAnime) GetInfo(id string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(a.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -227,7 +274,7 @@ func (a *AllAnime) GetInfo(id string) (interface{}, error) {
 
 	var infoResp infoResponse
 	if err := json.Unmarshal(body, &infoResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, a.Name(), fmt.Errorf("failed to parse response: %w", err))
 	}
 
 	show := infoResp.Data.Show
@@ -336,6 +383,72 @@ func (a *AllAnime) GetEpisodes(ctx context.Context, seasonID string) ([]provider
 	return episodes, nil
 }
 
+// GetEpisodeDetail looks up the title and thumbnail of a single episode,
+// which the episode list leaves out
+func (a *AllAnime) GetEpisodeDetail(ctx context.Context, mediaID, episodeID string) (*providers.Episode, error) {
+	number, err := strconv.Atoi(episodeID[strings.LastIndex(episodeID, "-")+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid episode ID format: %s", episodeID)
+	}
+
+	query := `query($showId:String!,$episodeNumStart:Float!,$episodeNumEnd:Float!){episodeInfos(showId:$showId,episodeNumStart:$episodeNumStart,episodeNumEnd:$episodeNumEnd){episodeIdNum notes thumbnails}}`
+	variablesJSON, err := json.Marshal(map[string]interface{}{
+		"showId":          mediaID,
+		"episodeNumStart": number,
+		"episodeNumEnd":   number,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal variables: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/api?variables=%s&query=%s",
+		a.APIURL,
+		url.QueryEscape(string(variablesJSON)),
+		url.QueryEscape(query))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:109.0) Gecko/20100101 Firefox/121.0")
+	req.Header.Set("Referer", a.BaseURL)
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch episode info: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := providers.CheckResponse(a.Name(), resp); err != nil {
+		return nil, err
+	}
+
+	var infoResp episodeInfosResponse
+	if err := json.NewDecoder(resp.Body).Decode(&infoResp); err != nil {
+		return nil, providers.NewError(providers.ErrorParserBroken, a.Name(), fmt.Errorf("failed to parse response: %w", err))
+	}
+
+	episode := &providers.Episode{ID: episodeID, Number: number, Season: 1}
+	for _, info := range infoResp.Data.EpisodeInfos {
+		if int(info.EpisodeIDNum) != number {
+			continue
+		}
+		episode.Title = strings.TrimSpace(info.Notes)
+		for _, thumbnail := range info.Thumbnails {
+			if thumbnail == "" {
+				continue
+			}
+			if !strings.HasPrefix(thumbnail, "http") {
+				thumbnail = thumbnailHost + strings.TrimPrefix(thumbnail, "/")
+			}
+			episode.ThumbnailURL = thumbnail
+			break
+		}
+	}
+	return episode, nil
+}
+
 func (a *AllAnime) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
 	res, err := a.GetSources(episodeID)
 	if err != nil {
@@ -369,6 +482,43 @@ func (a *AllAnime) GetStreamURL(ctx context.Context, episodeID string, quality p
 	}, nil
 }
 
+// GetStreamSources returns every mirror AllAnime lists for an episode
+func (a *AllAnime) GetStreamSources(ctx context.Context, episodeID string) ([]providers.StreamSource, error) {
+	v, translationType, err := a.getSources(episodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]providers.StreamSource, 0, len(v.Sources))
+	for _, src := range v.Sources {
+		streamType := providers.StreamTypeHLS
+		if !src.IsM3U8 {
+			streamType = providers.StreamTypeMP4
+		}
+
+		// Mirrors have no names of their own, so use their host
+		server := src.URL
+		if u, err := url.Parse(src.URL); err == nil && u.Host != "" {
+			server = strings.TrimPrefix(u.Host, "www.")
+		}
+
+		sources = append(sources, providers.StreamSource{
+			Server: server,
+			Audio:  translationType,
+			Stream: &providers.StreamURL{
+				URL:     src.URL,
+				Quality: providers.Quality(src.Quality),
+				Type:    streamType,
+				Referer: src.Referer,
+				Headers: map[string]string{
+					"Referer": src.Referer,
+				},
+			},
+		})
+	}
+	return sources, nil
+}
+
 func (a *AllAnime) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
 	res, err := a.GetSources(episodeID)
 	if err != nil {
@@ -486,26 +636,45 @@ func (a *AllAnime) isValidStreamURL(urlStr string) bool {
 
 // GetSources fetches video sources for an episode
 func (a *AllAnime) GetSources(episodeID string) (interface{}, error) {
+	sources, _, err := a.getSources(episodeID)
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// getSources fetches video sources for an episode in the preferred
+// translation, falling back to sub, and returns the translation used
+func (a *AllAnime) getSources(episodeID string) (*types.VideoSources, string, error) {
 	// Parse episodeID format: "animeID-episodeNumber"
 	parts := strings.Split(episodeID, "-")
 	if len(parts) < 2 {
-		return nil, fmt.Errorf("invalid episode ID format: %s", episodeID)
+		return nil, "", fmt.Errorf("invalid episode ID format: %s", episodeID)
 	}
 
 	episodeNum := parts[len(parts)-1]
 	animeID := strings.Join(parts[:len(parts)-1], "-")
 
 	// Fetch source URLs from API
-	links, err := a.getEpisodeLinks(animeID, episodeNum)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get episode links: %w", err)
+	var links []string
+	var translationType string
+	for _, tt := range a.translationTypes() {
+		var err error
+		links, err = a.getEpisodeLinks(animeID, episodeNum, tt)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get episode links: %w", err)
+		}
+		translationType = tt
+		if len(links) > 0 {
+			break
+		}
 	}
 
 	if len(links) == 0 {
 		return &types.VideoSources{
 			Sources:   []types.Source{},
 			Subtitles: []types.Subtitle{},
-		}, nil
+		}, translationType, nil
 	}
 
 	sources := &types.VideoSources{
@@ -533,19 +702,19 @@ func (a *AllAnime) GetSources(episodeID string) (interface{}, error) {
 
 	// If no valid sources were found, return an error
 	if len(sources.Sources) == 0 {
-		return nil, fmt.Errorf("no valid streaming sources found for episode %s", episodeID)
+		return nil, "", fmt.Errorf("no valid streaming sources found for episode %s", episodeID)
 	}
 
-	return sources, nil
+	return sources, translationType, nil
 }
 
 // getEpisodeLinks fetches episode video links using GraphQL
-func (a *AllAnime) getEpisodeLinks(animeID, episodeNum string) ([]string, error) {
+func (a *AllAnime) getEpisodeLinks(animeID, episodeNum, translationType string) ([]string, error) {
 	query := `query($showId:String!,$translationType:VaildTranslationTypeEnumType!,$episodeString:String!){episode(showId:$showId,translationType:$translationType,episodeString:$episodeString){episodeString sourceUrls}}`
 
 	variables := map[string]string{
 		"showId":          animeID,
-		"translationType": "sub",
+		"translationType": translationType,
 		"episodeString":   episodeNum,
 	}
 
@@ -573,6 +742,10 @@ func (a *AllAnime) getEpisodeLinks(animeID, episodeNum string) ([]string, error)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(a.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -580,7 +753,7 @@ func (a *AllAnime) getEpisodeLinks(animeID, episodeNum string) ([]string, error)
 
 	var epResp episodeResponse
 	if err := json.Unmarshal(body, &epResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, a.Name(), fmt.Errorf("failed to parse response: %w", err))
 	}
 
 	// Extract and decode provider IDs
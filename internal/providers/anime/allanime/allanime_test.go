@@ -0,0 +1,42 @@
+package allanime
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+func TestGetEpisodeDetail(t *testing.T) {
+	var variables map[string]interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.Unmarshal([]byte(r.URL.Query().Get("variables")), &variables))
+		_, _ = io.WriteString(w, `{"data":{"episodeInfos":[
+			{"episodeIdNum":3,"notes":" The Mage's Funeral ","thumbnails":["/images/ep3.jpg","https://cdn.example.com/ep3.jpg"]}
+		]}}`)
+	}))
+	defer api.Close()
+
+	a := New()
+	a.APIURL = api.URL
+	a.Client = api.Client()
+
+	var _ providers.EpisodeDetailer = a
+	episode, err := a.GetEpisodeDetail(context.Background(), "show-id", "show-id-3")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"showId": "show-id", "episodeNumStart": 3.0, "episodeNumEnd": 3.0}, variables)
+	assert.Equal(t, 3, episode.Number)
+	assert.Equal(t, "The Mage's Funeral", episode.Title)
+	assert.Equal(t, thumbnailHost+"images/ep3.jpg", episode.ThumbnailURL)
+
+	_, err = a.GetEpisodeDetail(context.Background(), "show-id", "show-id-x")
+	assert.Error(t, err)
+}
@@ -26,6 +26,11 @@ func (p *HDRezka) Type() providers.MediaType {
 	return providers.MediaTypeAnime
 }
 
+// Capabilities are those of the movie and TV provider
+func (p *HDRezka) Capabilities() providers.Capabilities {
+	return p.HDRezka.Capabilities()
+}
+
 func (p *HDRezka) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	return p.HDRezka.Search(ctx, query)
 }
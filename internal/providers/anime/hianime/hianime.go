@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/extractors"
 	"github.com/justchokingaround/greg/pkg/types"
@@ -23,12 +24,15 @@ type HiAnime struct {
 	Client      *http.Client
 	searchCache sync.Map
 	infoCache   sync.Map
+
+	mu        sync.RWMutex
+	audioType string // Server type tried first: "dub", "sub" or "" (site order)
 }
 
 func New() *HiAnime {
 	return &HiAnime{
 		BaseURL: "https://hianime.to",
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: netbind.Transport("hianime")},
 	}
 }
 
@@ -40,6 +44,11 @@ func (h *HiAnime) Type() providers.MediaType {
 	return providers.MediaTypeAnime
 }
 
+// Capabilities reports sub and dub servers with subtitle tracks
+func (h *HiAnime) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true, Qualities: true, Subtitles: true, Sources: true, Audio: true}
+}
+
 // Search searches for anime by query
 func (h *HiAnime) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	if cached, ok := h.searchCache.Load(query); ok {
@@ -54,6 +63,10 @@ func (h *HiAnime) Search(ctx context.Context, query string) ([]providers.Media,
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(h.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -116,6 +129,10 @@ func (h *HiAnime) GetInfo(id string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(h.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -201,6 +218,10 @@ func (h *HiAnime) fetchEpisodeList(animeDataID string) ([]types.Episode, error)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(h.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read episode list response: %w", err)
@@ -213,11 +234,11 @@ func (h *HiAnime) fetchEpisodeList(animeDataID string) ([]types.Episode, error)
 	}
 
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse episode list JSON: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, h.Name(), fmt.Errorf("failed to parse episode list JSON: %w", err))
 	}
 
 	if !jsonResponse.Status || jsonResponse.HTML == "" {
-		return nil, fmt.Errorf("invalid episode list response")
+		return nil, providers.NewError(providers.ErrorParserBroken, h.Name(), fmt.Errorf("invalid episode list response"))
 	}
 
 	// Parse the HTML content
@@ -239,6 +260,7 @@ func (h *HiAnime) fetchEpisodeList(animeDataID string) ([]types.Episode, error)
 		epNum = strings.TrimSpace(epNum)
 		epTitle, _ := s.Attr("title")
 		epTitle = strings.TrimSpace(epTitle)
+		epHref, _ := s.Attr("href") // "/watch/naruto-100?ep=1234"
 
 		// Parse episode number
 		num := i + 1
@@ -246,11 +268,15 @@ func (h *HiAnime) fetchEpisodeList(animeDataID string) ([]types.Episode, error)
 			num = parsedNum
 		}
 
-		episodes = append(episodes, types.Episode{
+		episode := types.Episode{
 			ID:     epID,
 			Number: num,
 			Title:  epTitle,
-		})
+		}
+		if strings.HasPrefix(epHref, "/") {
+			episode.URL = h.BaseURL + epHref
+		}
+		episodes = append(episodes, episode)
 	})
 
 	return episodes, nil
@@ -305,10 +331,11 @@ func (h *HiAnime) GetEpisodes(ctx context.Context, seasonID string) ([]providers
 	var episodes []providers.Episode
 	for _, ep := range animeInfo.Episodes {
 		episodes = append(episodes, providers.Episode{
-			ID:     ep.ID,
-			Number: ep.Number,
-			Title:  ep.Title,
-			Season: 1,
+			ID:      ep.ID,
+			Number:  ep.Number,
+			Title:   ep.Title,
+			Season:  1,
+			PageURL: ep.URL,
 		})
 	}
 
@@ -406,9 +433,9 @@ func (h *HiAnime) GetSources(episodeID string) (interface{}, error) {
 		}, nil
 	}
 
-	// Try each server until we get valid sources
+	// Try each server until we get valid sources, preferred audio first
 	var lastErr error
-	for _, server := range servers {
+	for _, server := range h.preferAudio(servers) {
 		sources, err := h.extractSourcesFromServer(server)
 		if err != nil {
 			lastErr = err
@@ -431,6 +458,94 @@ func (h *HiAnime) GetSources(episodeID string) (interface{}, error) {
 	}, nil
 }
 
+// SetAudioPreference makes later stream lookups try dub or sub servers first
+func (h *HiAnime) SetAudioPreference(preference string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.audioType = providers.AnimeAudioType(preference)
+}
+
+// preferAudio stably moves servers of the preferred audio type to the front
+func (h *HiAnime) preferAudio(servers []types.EpisodeServer) []types.EpisodeServer {
+	h.mu.RLock()
+	audioType := h.audioType
+	h.mu.RUnlock()
+	if audioType == "" {
+		return servers
+	}
+
+	suffix := " (" + audioType + ")"
+	ordered := make([]types.EpisodeServer, 0, len(servers))
+	var rest []types.EpisodeServer
+	for _, server := range servers {
+		if strings.HasSuffix(server.Name, suffix) {
+			ordered = append(ordered, server)
+		} else {
+			rest = append(rest, server)
+		}
+	}
+	return append(ordered, rest...)
+}
+
+// GetStreamSources returns the sources of every server (sub and dub) for an episode
+func (h *HiAnime) GetStreamSources(ctx context.Context, episodeID string) ([]providers.StreamSource, error) {
+	servers, err := h.GetServers(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch servers: %w", err)
+	}
+
+	var result []providers.StreamSource
+	var lastErr error
+	for _, server := range h.preferAudio(servers) {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sources, err := h.extractSourcesFromServer(server)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// GetServers appends the server type, e.g. "HD-1 (dub)"
+		name, audio := server.Name, ""
+		if i := strings.LastIndex(name, " ("); i > 0 && strings.HasSuffix(name, ")") {
+			name, audio = name[:i], name[i+2:len(name)-1]
+		}
+
+		for _, src := range sources.Sources {
+			streamType := providers.StreamTypeHLS
+			if !src.IsM3U8 {
+				streamType = providers.StreamTypeMP4
+			}
+			stream := &providers.StreamURL{
+				URL:     src.URL,
+				Quality: providers.Quality(src.Quality),
+				Type:    streamType,
+				Referer: src.Referer,
+				Headers: map[string]string{
+					"Referer": src.Referer,
+				},
+			}
+			for _, sub := range sources.Subtitles {
+				stream.Subtitles = append(stream.Subtitles, providers.Subtitle{
+					Language: sub.Lang,
+					URL:      sub.URL,
+				})
+			}
+			result = append(result, providers.StreamSource{Server: name, Audio: audio, Stream: stream})
+		}
+	}
+
+	if len(result) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("failed to extract sources from all servers: %w", lastErr)
+		}
+		return nil, fmt.Errorf("no sources found")
+	}
+	return result, nil
+}
+
 // extractSourcesFromServer extracts video sources from a specific server
 func (h *HiAnime) extractSourcesFromServer(server types.EpisodeServer) (*types.VideoSources, error) {
 	// The server.URL contains the server ID
@@ -454,6 +569,10 @@ func (h *HiAnime) extractSourcesFromServer(server types.EpisodeServer) (*types.V
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(h.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read sources response: %w", err)
@@ -468,11 +587,11 @@ func (h *HiAnime) extractSourcesFromServer(server types.EpisodeServer) (*types.V
 	}
 
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse sources JSON: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, h.Name(), fmt.Errorf("failed to parse sources JSON: %w", err))
 	}
 
 	if jsonResponse.Link == "" {
-		return nil, fmt.Errorf("no embed link found in response")
+		return nil, providers.NewError(providers.ErrorParserBroken, h.Name(), fmt.Errorf("no embed link found in response"))
 	}
 
 	embedURL := jsonResponse.Link
@@ -506,6 +625,10 @@ func (h *HiAnime) GetServers(episodeID string) ([]types.EpisodeServer, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(h.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read servers response: %w", err)
@@ -518,11 +641,11 @@ func (h *HiAnime) GetServers(episodeID string) ([]types.EpisodeServer, error) {
 	}
 
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse servers JSON: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, h.Name(), fmt.Errorf("failed to parse servers JSON: %w", err))
 	}
 
 	if !jsonResponse.Status || jsonResponse.HTML == "" {
-		return nil, fmt.Errorf("invalid servers response")
+		return nil, providers.NewError(providers.ErrorParserBroken, h.Name(), fmt.Errorf("invalid servers response"))
 	}
 
 	// Parse the HTML content
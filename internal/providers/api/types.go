@@ -83,9 +83,11 @@ type ErrorResponse struct {
 
 // MangaChapter represents a manga chapter in the API response
 type MangaChapter struct {
-	ID     string `json:"id"`
-	Number string `json:"number"` // Can be "1", "1.5", etc.
-	Title  string `json:"title"`
+	ID        string `json:"id"`
+	Number    string `json:"number"` // Can be "1", "1.5", etc.
+	Title     string `json:"title"`
+	Volume    string `json:"volume,omitempty"`
+	ScanGroup string `json:"scan_group,omitempty"`
 }
 
 // MangaPagesResponse represents the API's manga pages response
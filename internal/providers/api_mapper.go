@@ -202,14 +202,17 @@ func GetChaptersAsEpisodesFromAPIInfo(ir api.InfoResponse) []Episode {
 
 	for _, chapter := range ir.Chapters {
 		// Parse chapter number from string (could be "1", "1.5", etc.)
-		var chapterNum int
+		var chapterNum, volume int
 		_, _ = fmt.Sscanf(chapter.Number, "%d", &chapterNum)
+		_, _ = fmt.Sscanf(chapter.Volume, "%d", &volume)
 
 		episodes = append(episodes, Episode{
-			ID:     chapter.ID,
-			Number: chapterNum,
-			Title:  chapter.Title,
-			Season: 1, // Manga uses single season
+			ID:        chapter.ID,
+			Number:    chapterNum,
+			Title:     chapter.Title,
+			Season:    1, // Manga uses single season
+			Volume:    volume,
+			ScanGroup: chapter.ScanGroup,
 		})
 	}
 
@@ -0,0 +1,39 @@
+package providers
+
+// Capabilities are the features a provider supports beyond listing and
+// playing episodes, so the TUI and CLI can leave out actions it would fail at
+type Capabilities struct {
+	Streams   bool // Resolves video streams; false for manga providers
+	Movies    bool // Resolves a movie's stream from its media ID, without listing seasons (MovieProvider)
+	Qualities bool // Offers several qualities of a stream rather than one adaptive stream
+	Subtitles bool // Streams come with external subtitle tracks
+	Sources   bool // Offers several servers or mirrors per episode (SourceLister)
+	Audio     bool // Serves sub and dub or other language versions separately (AudioPreferenceSetter)
+}
+
+// CanPickSource reports whether an episode has more than one stream to pick
+// from, either from several servers or in several qualities
+func (c Capabilities) CanPickSource() bool {
+	return c.Sources || c.Qualities
+}
+
+// Names returns the names of the supported features, for listing them
+func (c Capabilities) Names() []string {
+	var names []string
+	for _, feature := range []struct {
+		name      string
+		supported bool
+	}{
+		{"streams", c.Streams},
+		{"movies", c.Movies},
+		{"qualities", c.Qualities},
+		{"subtitles", c.Subtitles},
+		{"sources", c.Sources},
+		{"audio", c.Audio},
+	} {
+		if feature.supported {
+			names = append(names, feature.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,18 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilitiesNames(t *testing.T) {
+	assert.Empty(t, Capabilities{}.Names())
+	assert.Equal(t, []string{"streams", "movies", "subtitles"}, Capabilities{Streams: true, Movies: true, Subtitles: true}.Names())
+}
+
+func TestCanPickSource(t *testing.T) {
+	assert.False(t, Capabilities{Streams: true}.CanPickSource(), "one stream per episode")
+	assert.True(t, Capabilities{Streams: true, Qualities: true}.CanPickSource())
+	assert.True(t, Capabilities{Streams: true, Sources: true}.CanPickSource())
+}
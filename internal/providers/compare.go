@@ -0,0 +1,151 @@
+package providers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Comparison is how one provider serves an episode, a row of a provider
+// comparison
+type Comparison struct {
+	Provider  string        `json:"provider"`
+	Title     string        `json:"title,omitempty"`     // Title of the show matched on the provider
+	Qualities []Quality     `json:"qualities,omitempty"` // Best first
+	Sources   int           `json:"sources"`             // Servers or mirrors offering the episode
+	Subtitles []string      `json:"subtitles,omitempty"` // Subtitle languages
+	Audio     []string      `json:"audio,omitempty"`     // "sub"/"dub" and audio track languages
+	Latency   time.Duration `json:"latency"`             // From search to resolved sources
+	Error     string        `json:"error,omitempty"`
+}
+
+// Best returns the highest quality the provider offers, or "" when unknown
+func (c Comparison) Best() Quality {
+	if len(c.Qualities) == 0 {
+		return ""
+	}
+	return c.Qualities[0]
+}
+
+// CompareProviders resolves the same episode on every provider at once,
+// matching the show by title as FindEpisodeStream does. The comparisons keep
+// the order of ps; a provider that fails has its Error set.
+func CompareProviders(ctx context.Context, ps []Provider, title string, season, episode int) []Comparison {
+	comparisons := make([]Comparison, len(ps))
+	var wg sync.WaitGroup
+	for i, p := range ps {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			comparisons[i] = compareProvider(ctx, provider, title, season, episode)
+		}(i, p)
+	}
+	wg.Wait()
+	return comparisons
+}
+
+func compareProvider(ctx context.Context, p Provider, title string, season, episode int) Comparison {
+	c := Comparison{Provider: p.Name()}
+	start := time.Now()
+	fail := func(err error) Comparison {
+		c.Latency = time.Since(start)
+		c.Error = err.Error()
+		return c
+	}
+
+	media, err := FindMedia(ctx, p, title)
+	if err != nil {
+		return fail(err)
+	}
+	c.Title = media.Title
+
+	// Movies are listed as a single episode
+	if episode <= 0 {
+		episode = 1
+	}
+	episodeID, err := EpisodeID(ctx, p, media.ID, season, episode)
+	if err != nil {
+		return fail(err)
+	}
+	sources, err := ListStreamSources(ctx, p, episodeID)
+	if err != nil {
+		return fail(err)
+	}
+	c.Latency = time.Since(start)
+	c.Sources = len(sources)
+
+	var qualities []Quality
+	for _, source := range sources {
+		qualities = append(qualities, source.Stream.Quality)
+		c.Subtitles = appendUnique(c.Subtitles, subtitleLanguages(source.Stream)...)
+		if source.Audio != "" {
+			c.Audio = appendUnique(c.Audio, source.Audio)
+		}
+		for _, track := range source.Stream.AudioTracks {
+			c.Audio = appendUnique(c.Audio, track.Language)
+		}
+	}
+	// Servers often don't say their quality; the provider's list does
+	if _, ok := As[SourceLister](p); ok {
+		if available, err := p.GetAvailableQualities(ctx, episodeID); err == nil {
+			qualities = append(qualities, available...)
+		}
+	}
+	c.Qualities = sortQualities(qualities)
+	return c
+}
+
+func subtitleLanguages(stream *StreamURL) []string {
+	languages := make([]string, 0, len(stream.Subtitles))
+	for _, sub := range stream.Subtitles {
+		languages = append(languages, sub.Language)
+	}
+	return languages
+}
+
+// appendUnique appends the non-empty values not in list yet
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := value == ""
+		for _, existing := range list {
+			found = found || existing == value
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// sortQualities dedupes qualities, best first, with ones of unknown
+// resolution such as auto last
+func sortQualities(qualities []Quality) []Quality {
+	var sorted []Quality
+	seen := make(map[Quality]bool)
+	for _, q := range qualities {
+		if q != "" && !seen[q] {
+			seen[q] = true
+			sorted = append(sorted, q)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ResolutionHeight() > sorted[j].ResolutionHeight()
+	})
+	return sorted
+}
+
+// RankComparisons orders comparisons best first: providers that resolved the
+// episode before ones that failed, then by best quality, then by latency
+func RankComparisons(comparisons []Comparison) {
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		a, b := comparisons[i], comparisons[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		if a.Best().ResolutionHeight() != b.Best().ResolutionHeight() {
+			return a.Best().ResolutionHeight() > b.Best().ResolutionHeight()
+		}
+		return a.Latency < b.Latency
+	})
+}
@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servingProvider lists the same episode on two servers, with subtitles and
+// audio tracks
+type servingProvider struct {
+	catalogProvider
+}
+
+func (p *servingProvider) GetStreamSources(ctx context.Context, episodeID string) ([]StreamSource, error) {
+	return []StreamSource{
+		{Server: "HD-1", Audio: "sub", Stream: &StreamURL{
+			URL:       "https://a.example/1.m3u8",
+			Quality:   QualityAuto,
+			Subtitles: []Subtitle{{Language: "English"}, {Language: "Spanish"}},
+		}},
+		{Server: "HD-2", Audio: "dub", Stream: &StreamURL{
+			URL:         "https://a.example/2.m3u8",
+			Quality:     Quality720p,
+			Subtitles:   []Subtitle{{Language: "English"}},
+			AudioTracks: []AudioTrack{{Language: "ja"}, {Language: "en"}},
+		}},
+	}, nil
+}
+
+func (p *servingProvider) GetAvailableQualities(ctx context.Context, episodeID string) ([]Quality, error) {
+	return []Quality{Quality1080p, Quality720p}, nil
+}
+
+// failingSearchProvider can't be searched
+type failingSearchProvider struct {
+	mockProvider
+}
+
+func (p *failingSearchProvider) Search(ctx context.Context, query string) ([]Media, error) {
+	return nil, errors.New("blocked")
+}
+
+func TestCompareProviders(t *testing.T) {
+	ps := []Provider{
+		&servingProvider{catalogProvider{mockProvider{name: "serving"}}},
+		&failingSearchProvider{mockProvider{name: "down"}},
+		&qualityProvider{mockProvider: mockProvider{name: "missing"}},
+	}
+
+	comparisons := CompareProviders(context.Background(), ps, "Frieren: Beyond Journey's End", 1, 2)
+	require.Len(t, comparisons, 3)
+
+	serving := comparisons[0]
+	assert.Equal(t, "serving", serving.Provider)
+	assert.Empty(t, serving.Error)
+	assert.Equal(t, "Frieren: Beyond Journey's End", serving.Title)
+	assert.Equal(t, 2, serving.Sources)
+	assert.Equal(t, []Quality{Quality1080p, Quality720p, QualityAuto}, serving.Qualities)
+	assert.Equal(t, Quality1080p, serving.Best())
+	assert.Equal(t, []string{"English", "Spanish"}, serving.Subtitles)
+	assert.Equal(t, []string{"sub", "dub", "ja", "en"}, serving.Audio)
+
+	assert.Equal(t, "down", comparisons[1].Provider)
+	assert.Contains(t, comparisons[1].Error, "blocked")
+	assert.Equal(t, "missing", comparisons[2].Provider)
+	assert.Contains(t, comparisons[2].Error, "not found")
+	assert.Empty(t, comparisons[2].Best())
+}
+
+func TestRankComparisons(t *testing.T) {
+	comparisons := []Comparison{
+		{Provider: "failed", Error: "timeout"},
+		{Provider: "slow-hd", Qualities: []Quality{Quality1080p}, Latency: 3 * time.Second},
+		{Provider: "sd", Qualities: []Quality{Quality480p}, Latency: time.Second},
+		{Provider: "fast-hd", Qualities: []Quality{Quality1080p, Quality720p}, Latency: time.Second},
+	}
+
+	RankComparisons(comparisons)
+
+	var order []string
+	for _, c := range comparisons {
+		order = append(order, c.Provider)
+	}
+	assert.Equal(t, []string{"fast-hd", "slow-hd", "sd", "failed"}, order)
+}
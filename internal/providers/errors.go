@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorKind classifies why a provider call failed, so the UI can tell the
+// user what to do about it
+type ErrorKind int
+
+const (
+	ErrorUnknown         ErrorKind = iota
+	ErrorNotFound                  // The site doesn't have the media or episode
+	ErrorGeoBlocked                // The site refuses requests from this region
+	ErrorCaptchaRequired           // A bot check (e.g. Cloudflare) must be passed first
+	ErrorRateLimited               // Too many requests, retry later
+	ErrorParserBroken              // The site changed and greg can't read it anymore
+)
+
+// String returns the name of the kind
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorNotFound:
+		return "not found"
+	case ErrorGeoBlocked:
+		return "geo-blocked"
+	case ErrorCaptchaRequired:
+		return "captcha required"
+	case ErrorRateLimited:
+		return "rate limited"
+	case ErrorParserBroken:
+		return "parser broken"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is a provider failure of a known kind
+type Error struct {
+	Kind       ErrorKind
+	Provider   string
+	URL        string        // Page that failed, e.g. to pass a captcha in the browser
+	RetryAfter time.Duration // How long the site asked to wait, for ErrorRateLimited
+	Err        error
+}
+
+// NewError classifies err as kind for provider
+func NewError(kind ErrorKind, provider string, err error) *Error {
+	return &Error{Kind: kind, Provider: provider, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Provider == "" {
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKindOf returns the kind of the first *Error in err's chain
+func ErrorKindOf(err error) ErrorKind {
+	var providerErr *Error
+	if errors.As(err, &providerErr) {
+		return providerErr.Kind
+	}
+	return ErrorUnknown
+}
+
+// challengeMarkers appear in the pages of bot checks
+var challengeMarkers = []string{"cf-chl", "challenge-platform", "cf_captcha", "g-recaptcha", "h-captcha", "just a moment..."}
+
+// geoBlockMarkers appear in the pages of sites refusing a region
+var geoBlockMarkers = []string{"not available in your country", "not available in your region", "geo-restricted", "geoblocked"}
+
+// CheckResponse returns a classified *Error when resp is an HTTP error or a
+// bot check, and nil otherwise. It reads the body of failed responses only.
+func CheckResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	page := strings.ToLower(string(body))
+	providerErr := &Error{
+		Provider: provider,
+		URL:      resp.Request.URL.String(),
+		Err:      fmt.Errorf("HTTP %d from %s", resp.StatusCode, resp.Request.URL.Host),
+	}
+
+	switch {
+	case containsAny(page, challengeMarkers):
+		providerErr.Kind = ErrorCaptchaRequired
+	case resp.StatusCode == http.StatusUnavailableForLegalReasons || containsAny(page, geoBlockMarkers):
+		providerErr.Kind = ErrorGeoBlocked
+	case resp.StatusCode == http.StatusTooManyRequests:
+		providerErr.Kind = ErrorRateLimited
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			providerErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		providerErr.Kind = ErrorNotFound
+	}
+	return providerErr
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
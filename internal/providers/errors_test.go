@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		want   ErrorKind
+	}{
+		{"not found", http.StatusNotFound, nil, "no such page", ErrorNotFound},
+		{"cloudflare challenge", http.StatusForbidden, nil, "<title>Just a moment...</title><script src=/cdn-cgi/challenge-platform/x>", ErrorCaptchaRequired},
+		{"geo-blocked", http.StatusForbidden, nil, "This content is not available in your country", ErrorGeoBlocked},
+		{"legal reasons", http.StatusUnavailableForLegalReasons, nil, "", ErrorGeoBlocked},
+		{"rate limited", http.StatusTooManyRequests, map[string]string{"Retry-After": "12"}, "slow down", ErrorRateLimited},
+		{"server error", http.StatusInternalServerError, nil, "oops", ErrorUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/watch/1")
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			err = CheckResponse("site", resp)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, ErrorKindOf(err))
+
+			var providerErr *Error
+			require.ErrorAs(t, err, &providerErr)
+			assert.Equal(t, "site", providerErr.Provider)
+			assert.Equal(t, server.URL+"/watch/1", providerErr.URL)
+			if tt.want == ErrorRateLimited {
+				assert.Equal(t, 12*time.Second, providerErr.RetryAfter)
+			}
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.NoError(t, CheckResponse("site", resp))
+	})
+}
+
+func TestErrorKindOf(t *testing.T) {
+	err := fmt.Errorf("failed to get stream URL: %w", NewError(ErrorParserBroken, "site", fmt.Errorf("no embed link")))
+	assert.Equal(t, ErrorParserBroken, ErrorKindOf(err))
+	assert.Equal(t, "failed to get stream URL: site: parser broken: no embed link", err.Error())
+	assert.Equal(t, ErrorUnknown, ErrorKindOf(fmt.Errorf("plain")))
+}
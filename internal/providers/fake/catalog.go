@@ -0,0 +1,65 @@
+package fake
+
+import (
+	"time"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Anime is the catalog of the demo anime provider
+var Anime = []Title{
+	{
+		Media: providers.Media{
+			ID: "starfall-academy", Title: "Starfall Academy", Type: providers.MediaTypeAnime, Year: 2024,
+			Synopsis: "Students of a floating academy learn to catch falling stars before they reach the ground.",
+			Rating:   8.4, Genres: []string{"Fantasy", "School"}, TotalEpisodes: 24, Status: "Completed",
+		},
+		Seasons: []int{12, 12},
+	},
+	{
+		Media: providers.Media{
+			ID: "lighthouse-keeper", Title: "The Last Lighthouse Keeper", Type: providers.MediaTypeAnime, Year: 2025,
+			Synopsis: "A retired keeper and a lost android tend the last lighthouse on a drowned coast.",
+			Rating:   8.9, Genres: []string{"Drama", "Sci-Fi", "Slice of Life"}, TotalEpisodes: 8, Status: "Ongoing",
+		},
+		Seasons: []int{8},
+	},
+	{
+		Media: providers.Media{
+			ID: "noodle-knights", Title: "Noodle Knights", Type: providers.MediaTypeAnime, Year: 2022,
+			Synopsis: "Three rival ramen stalls settle their disputes in increasingly formal duels.",
+			Rating:   7.6, Genres: []string{"Comedy", "Gourmet"}, TotalEpisodes: 13, Status: "Completed",
+		},
+		Seasons: []int{13},
+		Runtime: 12 * time.Minute,
+	},
+}
+
+// MoviesTV is the catalog of the demo movie and TV provider
+var MoviesTV = []Title{
+	{
+		Media: providers.Media{
+			ID: "quiet-harbor", Title: "Quiet Harbor", Type: providers.MediaTypeTV, Year: 2023,
+			Synopsis: "A small-town harbor master keeps finding boats that left port decades ago.",
+			Rating:   8.2, Genres: []string{"Mystery", "Drama"}, TotalEpisodes: 16, Status: "Ongoing",
+		},
+		Seasons: []int{10, 6},
+		Runtime: 48 * time.Minute,
+	},
+	{
+		Media: providers.Media{
+			ID: "paper-satellites", Title: "Paper Satellites", Type: providers.MediaTypeMovie, Year: 2021,
+			Synopsis: "Two engineers try to launch a satellite folded from a single sheet of paper.",
+			Rating:   7.3, Genres: []string{"Comedy", "Adventure"}, TotalEpisodes: 1, Status: "Completed",
+		},
+		Runtime: 98 * time.Minute,
+	},
+	{
+		Media: providers.Media{
+			ID: "the-cartographer", Title: "The Cartographer", Type: providers.MediaTypeMovie, Year: 2024,
+			Synopsis: "A mapmaker is hired to chart a city that rearranges itself every night.",
+			Rating:   7.9, Genres: []string{"Thriller", "Fantasy"}, TotalEpisodes: 1, Status: "Completed",
+		},
+		Runtime: 121 * time.Minute,
+	},
+}
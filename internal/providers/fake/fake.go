@@ -0,0 +1,214 @@
+// Package fake serves a fixed catalog without network access, for tests and
+// demos (greg --demo). Everything it returns is derived from the catalog, so
+// the same calls always give the same results: seasons, episodes and streams
+// get IDs built from the media ID, and stream URLs point at demo.invalid,
+// which never resolves.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Names of the demo providers
+const (
+	AnimeName    = "demo-anime"
+	MoviesTVName = "demo-movies"
+)
+
+// defaultRuntime is the length of an episode whose title sets none
+const defaultRuntime = 24 * time.Minute
+
+var _ providers.MovieProvider = (*Provider)(nil)
+
+// Title is a show or movie of a catalog
+type Title struct {
+	providers.Media
+	Seasons []int         // Episodes in each season; movies have none
+	Runtime time.Duration // Length of each episode, 24 minutes when 0
+}
+
+// Provider serves a catalog of titles
+type Provider struct {
+	name      string
+	mediaType providers.MediaType
+	titles    []Title
+}
+
+// New returns a provider serving titles under name
+func New(name string, mediaType providers.MediaType, titles ...Title) *Provider {
+	return &Provider{name: name, mediaType: mediaType, titles: titles}
+}
+
+// NewAnime returns the demo anime provider
+func NewAnime() *Provider {
+	return New(AnimeName, providers.MediaTypeAnime, Anime...)
+}
+
+// NewMoviesTV returns the demo movie and TV provider
+func NewMoviesTV() *Provider {
+	return New(MoviesTVName, providers.MediaTypeMovieTV, MoviesTV...)
+}
+
+// SeasonID returns the ID of a season of a title
+func SeasonID(mediaID string, season int) string {
+	return fmt.Sprintf("%s-s%d", mediaID, season)
+}
+
+// EpisodeID returns the ID of an episode of a season of a title
+func EpisodeID(mediaID string, season, episode int) string {
+	return fmt.Sprintf("%s-e%d", SeasonID(mediaID, season), episode)
+}
+
+// MovieEpisodeID returns the ID of the only episode of a movie
+func MovieEpisodeID(mediaID string) string {
+	return mediaID + "-movie"
+}
+
+// StreamURL returns the URL an episode streams from in a quality
+func StreamURL(episodeID string, quality providers.Quality) string {
+	return fmt.Sprintf("https://stream.demo.invalid/%s/%s.m3u8", episodeID, quality)
+}
+
+func (p *Provider) Name() string              { return p.name }
+func (p *Provider) Type() providers.MediaType { return p.mediaType }
+
+func (p *Provider) Capabilities() providers.Capabilities {
+	caps := providers.Capabilities{Streams: true}
+	for _, t := range p.titles {
+		if t.Type == providers.MediaTypeMovie {
+			caps.Movies = true
+		}
+	}
+	return caps
+}
+
+// Search returns the titles whose title contains query, ignoring case
+func (p *Provider) Search(ctx context.Context, query string) ([]providers.Media, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var results []providers.Media
+	for _, t := range p.titles {
+		if strings.Contains(strings.ToLower(t.Title), query) {
+			results = append(results, t.Media)
+		}
+	}
+	return results, nil
+}
+
+// GetTrending returns the catalog in order
+func (p *Provider) GetTrending(ctx context.Context) ([]providers.Media, error) {
+	results := make([]providers.Media, 0, len(p.titles))
+	for _, t := range p.titles {
+		results = append(results, t.Media)
+	}
+	return results, nil
+}
+
+// GetRecent returns the catalog newest first
+func (p *Provider) GetRecent(ctx context.Context) ([]providers.Media, error) {
+	results, _ := p.GetTrending(ctx)
+	slices.SortStableFunc(results, func(a, b providers.Media) int { return b.Year - a.Year })
+	return results, nil
+}
+
+func (p *Provider) GetMediaDetails(ctx context.Context, id string) (*providers.MediaDetails, error) {
+	t, err := p.title(id)
+	if err != nil {
+		return nil, err
+	}
+	seasons, _ := p.GetSeasons(ctx, id)
+	return &providers.MediaDetails{Media: t.Media, Seasons: seasons}, nil
+}
+
+// GetSeasons lists the seasons of a show, and none for a movie
+func (p *Provider) GetSeasons(ctx context.Context, mediaID string) ([]providers.Season, error) {
+	t, err := p.title(mediaID)
+	if err != nil {
+		return nil, err
+	}
+	var seasons []providers.Season
+	for i := range t.Seasons {
+		seasons = append(seasons, providers.Season{ID: SeasonID(t.ID, i+1), Number: i + 1, Title: fmt.Sprintf("Season %d", i+1)})
+	}
+	return seasons, nil
+}
+
+// GetEpisodes lists the episodes of a season, or the only episode of a movie
+// given its media ID
+func (p *Provider) GetEpisodes(ctx context.Context, seasonID string) ([]providers.Episode, error) {
+	for _, t := range p.titles {
+		if t.ID == seasonID && len(t.Seasons) == 0 {
+			return []providers.Episode{{ID: MovieEpisodeID(t.ID), Number: 1, Title: t.Title, Duration: t.runtime()}}, nil
+		}
+		for i, count := range t.Seasons {
+			if SeasonID(t.ID, i+1) != seasonID {
+				continue
+			}
+			episodes := make([]providers.Episode, 0, count)
+			for n := 1; n <= count; n++ {
+				episodes = append(episodes, providers.Episode{
+					ID:       EpisodeID(t.ID, i+1, n),
+					Number:   n,
+					Season:   i + 1,
+					Title:    fmt.Sprintf("Episode %d", n),
+					Duration: t.runtime(),
+				})
+			}
+			return episodes, nil
+		}
+	}
+	return nil, p.notFound("season %s", seasonID)
+}
+
+// GetMovieEpisodeID returns the ID of a movie's only episode
+func (p *Provider) GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error) {
+	t, err := p.title(mediaID)
+	if err != nil {
+		return "", err
+	}
+	if len(t.Seasons) > 0 {
+		return "", fmt.Errorf("%s is not a movie", t.Title)
+	}
+	return MovieEpisodeID(t.ID), nil
+}
+
+func (p *Provider) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
+	if quality == "" || quality == providers.QualityAuto {
+		quality = providers.Quality1080p
+	}
+	return &providers.StreamURL{URL: StreamURL(episodeID, quality), Quality: quality, Type: providers.StreamTypeHLS}, nil
+}
+
+func (p *Provider) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
+	return []providers.Quality{providers.Quality1080p, providers.Quality720p}, nil
+}
+
+// HealthCheck always passes
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (p *Provider) title(id string) (Title, error) {
+	for _, t := range p.titles {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return Title{}, p.notFound("media %s", id)
+}
+
+func (p *Provider) notFound(format string, args ...any) error {
+	return providers.NewError(providers.ErrorNotFound, p.name, fmt.Errorf(format, args...))
+}
+
+func (t Title) runtime() time.Duration {
+	if t.Runtime > 0 {
+		return t.Runtime
+	}
+	return defaultRuntime
+}
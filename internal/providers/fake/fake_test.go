@@ -0,0 +1,60 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+func TestShow(t *testing.T) {
+	ctx := context.Background()
+	p := NewAnime()
+
+	results, err := p.Search(ctx, "STARFALL")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "starfall-academy", results[0].ID)
+
+	seasons, err := p.GetSeasons(ctx, "starfall-academy")
+	require.NoError(t, err)
+	require.Len(t, seasons, 2)
+	assert.Equal(t, "starfall-academy-s2", seasons[1].ID)
+
+	episodes, err := p.GetEpisodes(ctx, seasons[1].ID)
+	require.NoError(t, err)
+	require.Len(t, episodes, 12)
+	assert.Equal(t, providers.Episode{ID: "starfall-academy-s2-e3", Number: 3, Season: 2, Title: "Episode 3", Duration: defaultRuntime}, episodes[2])
+
+	stream, err := p.GetStreamURL(ctx, episodes[2].ID, providers.QualityAuto)
+	require.NoError(t, err)
+	assert.Equal(t, "https://stream.demo.invalid/starfall-academy-s2-e3/1080p.m3u8", stream.URL)
+	assert.False(t, p.Capabilities().Movies)
+
+	_, err = p.GetEpisodes(ctx, "starfall-academy-s3")
+	assert.Equal(t, providers.ErrorNotFound, providers.ErrorKindOf(err))
+}
+
+func TestMovie(t *testing.T) {
+	ctx := context.Background()
+	p := NewMoviesTV()
+	assert.True(t, p.Capabilities().Movies)
+
+	seasons, err := p.GetSeasons(ctx, "paper-satellites")
+	require.NoError(t, err)
+	assert.Empty(t, seasons)
+
+	id, err := providers.MovieEpisodeID(ctx, p, "paper-satellites")
+	require.NoError(t, err)
+	assert.Equal(t, "paper-satellites-movie", id)
+
+	_, err = p.GetMovieEpisodeID(ctx, "quiet-harbor")
+	assert.Error(t, err, "a show isn't a movie")
+
+	recent, err := p.GetRecent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "the-cartographer", recent[0].ID)
+}
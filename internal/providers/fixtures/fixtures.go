@@ -0,0 +1,204 @@
+// Package fixtures records provider HTTP traffic into fixture files and
+// replays it, so provider parsers can be developed and tested offline.
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxRecordedBody is the largest response body recorded; bigger ones (video
+// segments, images) pass through unrecorded
+const maxRecordedBody = 10 << 20
+
+// Fixture is one recorded request and its response
+type Fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body,omitempty"`        // Text bodies, kept readable
+	BodyBase64  string      `json:"body_base64,omitempty"` // Binary bodies
+}
+
+// FileName returns the fixture file of a request: the host for browsing,
+// and a hash of the method, URL and body to tell requests apart
+func FileName(method, url string, body []byte) string {
+	sum := sha256.Sum256([]byte(method + " " + url + "\n" + string(body)))
+	host := url
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.NewReplacer(":", "_", "@", "_").Replace(host)
+	return fmt.Sprintf("%s_%s_%s.json", host, method, hex.EncodeToString(sum[:6]))
+}
+
+// readRequestBody returns the body of req and leaves req readable again
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder is an http.RoundTripper saving every response into a fixture
+// directory
+type Recorder struct {
+	dir  string
+	base http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewRecorder records the responses of base into dir. A nil base is
+// http.DefaultTransport.
+func NewRecorder(dir string, base http.RoundTripper) (*Recorder, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	return &Recorder{dir: dir, base: base}, nil
+}
+
+// RoundTrip performs req and records its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil || resp.ContentLength > maxRecordedBody {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRecordedBody+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxRecordedBody {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: string(reqBody),
+		Status:      resp.StatusCode,
+		Header:      resp.Header.Clone(),
+	}
+	fixture.Header.Del("Set-Cookie")
+	if utf8.Valid(body) {
+		fixture.Body = string(body)
+	} else {
+		fixture.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	if err := r.save(FileName(req.Method, fixture.URL, reqBody), &fixture); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(name string, fixture *Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.WriteFile(filepath.Join(r.dir, name), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// Replayer is an http.RoundTripper answering requests from a fixture
+// directory, without touching the network
+type Replayer struct {
+	dir string
+}
+
+// NewReplayer answers requests from the fixtures in dir
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{dir: dir}
+}
+
+// Client returns an http.Client replaying the fixtures in dir
+func Client(dir string) *http.Client {
+	return &http.Client{Transport: NewReplayer(dir)}
+}
+
+// RoundTrip answers req from its fixture, failing when there is none
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	name := FileName(req.Method, req.URL.String(), reqBody)
+	data, err := os.ReadFile(filepath.Join(r.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no fixture %s in %s", name, r.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", name, err)
+	}
+
+	body := []byte(fixture.Body)
+	if fixture.BodyBase64 != "" {
+		if body, err = base64.StdEncoding.DecodeString(fixture.BodyBase64); err != nil {
+			return nil, fmt.Errorf("failed to decode fixture %s: %w", name, err)
+		}
+	}
+	header := fixture.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package fixtures
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, client *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Set-Cookie", "session=secret")
+			_, _ = w.Write([]byte("results for " + string(body)))
+		case "/poster.jpg":
+			_, _ = w.Write([]byte{0xff, 0xd8, 0xff, 0x00})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir, nil)
+	require.NoError(t, err)
+	recording := &http.Client{Transport: recorder}
+
+	status, body := get(t, recording, http.MethodPost, server.URL+"/search", "bebop")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "results for bebop", body, "recording doesn't change the response")
+	get(t, recording, http.MethodPost, server.URL+"/search", "trigun")
+	get(t, recording, http.MethodGet, server.URL+"/poster.jpg", "")
+	get(t, recording, http.MethodGet, server.URL+"/missing", "")
+	server.Close()
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+	data, err := os.ReadFile(filepath.Join(dir, FileName(http.MethodPost, server.URL+"/search", []byte("bebop"))))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	replaying := Client(dir)
+	status, body = get(t, replaying, http.MethodPost, server.URL+"/search", "trigun")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "results for trigun", body)
+
+	_, body = get(t, replaying, http.MethodGet, server.URL+"/poster.jpg", "")
+	assert.Equal(t, string([]byte{0xff, 0xd8, 0xff, 0x00}), body)
+
+	status, _ = get(t, replaying, http.MethodGet, server.URL+"/missing", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, err = replaying.Get(server.URL + "/never-recorded")
+	assert.ErrorContains(t, err, "no fixture "+FileName(http.MethodGet, server.URL+"/never-recorded", nil))
+}
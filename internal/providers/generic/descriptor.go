@@ -0,0 +1,202 @@
+// Package generic implements providers described by a YAML file of URL
+// templates and CSS selectors, so simple sites can be added without Go.
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Descriptor defines a scraping provider
+type Descriptor struct {
+	Name     string            `yaml:"name"`     // Provider name, lowercase
+	Type     string            `yaml:"type"`     // anime, movie, tv or movie_tv
+	BaseURL  string            `yaml:"base_url"` // Site root, {base} in templates
+	Headers  map[string]string `yaml:"headers"`  // Sent with every request
+	Hosts    []string          `yaml:"hosts"`    // Other hosts it may fetch from, besides base_url's
+	Search   ListStep          `yaml:"search"`
+	Episodes ListStep          `yaml:"episodes"` // Leave out for movie sites, each result is then one episode
+	Stream   StreamStep        `yaml:"stream"`
+}
+
+// ListStep fetches a page and extracts one item per element matching Items
+type ListStep struct {
+	// URL template with {base}, {query} (search only) and {id} (the media
+	// ID). Relative URLs resolve against base_url. Episodes default to "{id}".
+	URL     string             `yaml:"url"`
+	Items   string             `yaml:"items"`   // CSS selector of each item
+	Fields  map[string]Extract `yaml:"fields"`  // search: id, title, poster, year; episodes: id, number, title
+	Reverse bool               `yaml:"reverse"` // Items are listed newest first
+}
+
+// StreamStep fetches an episode page and extracts the stream URL
+type StreamStep struct {
+	URL     string  `yaml:"url"`     // Page template with {base} and {id}, default "{id}"
+	Source  Extract `yaml:"source"`  // Stream URL on the page; a regex alone searches the raw page
+	Type    string  `yaml:"type"`    // hls, dash, mp4, mkv or ytdlp (the page is handed to yt-dlp), guessed when empty
+	Referer string  `yaml:"referer"` // Template, the episode page when empty
+}
+
+// Extract reads one value out of an element
+type Extract struct {
+	Selector string `yaml:"selector"` // CSS selector inside the element, the element itself when empty
+	Attr     string `yaml:"attr"`     // Attribute to read, the text when empty, "html" for the markup
+	Regex    string `yaml:"regex"`    // Keeps the first capture group, or the whole match
+	Template string `yaml:"template"` // Rewrites the value, e.g. "{base}/watch/{value}"
+
+	re *regexp.Regexp
+}
+
+// empty reports whether nothing is extracted
+func (e Extract) empty() bool {
+	return e.Selector == "" && e.Attr == "" && e.Regex == ""
+}
+
+// mediaTypes maps descriptor types to media types
+var mediaTypes = map[string]providers.MediaType{
+	"anime":    providers.MediaTypeAnime,
+	"movie":    providers.MediaTypeMovie,
+	"tv":       providers.MediaTypeTV,
+	"movie_tv": providers.MediaTypeMovieTV,
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Parse reads and validates a descriptor
+func Parse(data []byte) (*Descriptor, error) {
+	var d Descriptor
+	if err := yaml.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor: %w", err)
+	}
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (d *Descriptor) validate() error {
+	switch {
+	case !namePattern.MatchString(d.Name):
+		return fmt.Errorf("name %q must be lowercase letters, digits, - or _", d.Name)
+	case d.BaseURL == "":
+		return fmt.Errorf("%s: base_url is required", d.Name)
+	case !httpURL(d.BaseURL):
+		return fmt.Errorf("%s: base_url must be an http(s) URL", d.Name)
+	case d.Search.URL == "" || d.Search.Items == "":
+		return fmt.Errorf("%s: search.url and search.items are required", d.Name)
+	case d.Search.Fields["id"].empty() || d.Search.Fields["title"].empty():
+		return fmt.Errorf("%s: search.fields needs id and title", d.Name)
+	case d.Episodes.Items != "" && d.Episodes.Fields["id"].empty():
+		return fmt.Errorf("%s: episodes.fields needs id", d.Name)
+	case d.Stream.Source.empty() && d.Stream.Type != string(providers.StreamTypeYTDLP):
+		return fmt.Errorf("%s: stream.source is required unless stream.type is ytdlp", d.Name)
+	}
+	switch providers.StreamType(d.Stream.Type) {
+	case "", providers.StreamTypeHLS, providers.StreamTypeDASH, providers.StreamTypeMP4, providers.StreamTypeMKV, providers.StreamTypeYTDLP:
+	default:
+		return fmt.Errorf("%s: unknown stream.type %q (hls, dash, mp4, mkv or ytdlp)", d.Name, d.Stream.Type)
+	}
+	if d.Type == "" {
+		d.Type = "anime"
+	}
+	if _, ok := mediaTypes[d.Type]; !ok {
+		return fmt.Errorf("%s: unknown type %q (anime, movie, tv or movie_tv)", d.Name, d.Type)
+	}
+	d.BaseURL = strings.TrimRight(d.BaseURL, "/")
+	if d.Episodes.URL == "" {
+		d.Episodes.URL = "{id}"
+	}
+	if d.Stream.URL == "" {
+		d.Stream.URL = "{id}"
+	}
+
+	if err := compileFields(d.Name, "search", d.Search.Fields); err != nil {
+		return err
+	}
+	if err := compileFields(d.Name, "episodes", d.Episodes.Fields); err != nil {
+		return err
+	}
+	return d.Stream.Source.compile(d.Name, "stream.source")
+}
+
+// httpURL reports whether s is an absolute http(s) URL
+func httpURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// allowedHosts returns the hosts the scraper may reach
+func (d *Descriptor) allowedHosts() []string {
+	hosts := make([]string, 0, len(d.Hosts)+1)
+	if u, err := url.Parse(d.BaseURL); err == nil {
+		hosts = append(hosts, strings.ToLower(u.Hostname()))
+	}
+	for _, h := range d.Hosts {
+		hosts = append(hosts, strings.ToLower(strings.TrimPrefix(h, "*.")))
+	}
+	return hosts
+}
+
+func compileFields(name, step string, fields map[string]Extract) error {
+	for field, extract := range fields {
+		if err := extract.compile(name, step+".fields."+field); err != nil {
+			return err
+		}
+		fields[field] = extract
+	}
+	return nil
+}
+
+func (e *Extract) compile(name, path string) error {
+	if e.Regex == "" {
+		return nil
+	}
+	re, err := regexp.Compile(e.Regex)
+	if err != nil {
+		return fmt.Errorf("%s: invalid regex in %s: %w", name, path, err)
+	}
+	e.re = re
+	return nil
+}
+
+// LoadDir creates a provider for each descriptor (*.yaml, *.yml) in dir.
+// A missing dir has no providers; broken descriptors are skipped and
+// reported in the error.
+func LoadDir(dir string) ([]*Provider, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrapers directory: %w", err)
+	}
+	var loaded []*Provider
+	var errs []error
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		d, err := Parse(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		loaded = append(loaded, New(d))
+	}
+	return loaded, errors.Join(errs...)
+}
@@ -0,0 +1,199 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+const descriptor = `
+name: animesite
+type: anime
+base_url: %s
+headers:
+  X-Requested-With: greg
+search:
+  url: "{base}/search?keyword={query}"
+  items: div.result
+  fields:
+    id: {selector: a, attr: href}
+    title: {selector: h3}
+    poster: {selector: img, attr: data-src}
+    year: {selector: .meta, regex: '(\d{4})'}
+episodes:
+  url: "{id}/episodes"
+  items: ul.episodes li
+  reverse: true
+  fields:
+    id: {selector: a, attr: href}
+    number: {selector: a}
+    title: {selector: a, attr: title}
+stream:
+  source: {regex: 'file:\s*"([^"]+)"'}
+`
+
+func newTestSite(t *testing.T) *Provider {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "greg", r.Header.Get("X-Requested-With"))
+		assert.Equal(t, "cowboy bebop", r.URL.Query().Get("keyword"))
+		fmt.Fprint(w, `<div class="result"><a href="/anime/bebop"><h3>Cowboy Bebop</h3></a><img data-src="/img/bebop.jpg"><span class="meta">TV · 1998</span></div>
+			<div class="result"><h3>No link</h3></div>`)
+	})
+	mux.HandleFunc("/anime/bebop/episodes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<ul class="episodes">
+			<li><a href="/watch/bebop-2" title="Stray Dog Strut">Episode 2</a></li>
+			<li><a href="/watch/bebop-1" title="Asteroid Blues">Episode 1</a></li>
+		</ul>`)
+	})
+	mux.HandleFunc("/watch/bebop-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<script>player.setup({file: "/hls/bebop-1/master.m3u8"})</script>`)
+	})
+	mux.HandleFunc("/watch/elsewhere", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<script>player.setup({file: "https://cdn.elsewhere.example/hls/master.m3u8"})</script>`)
+	})
+	mux.HandleFunc("/watch/broken", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<p>The player moved</p>`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	d, err := Parse([]byte(fmt.Sprintf(descriptor, server.URL+"/")))
+	require.NoError(t, err)
+	return New(d)
+}
+
+func TestProvider(t *testing.T) {
+	p := newTestSite(t)
+	base := strings.TrimRight(p.desc.BaseURL, "/")
+	ctx := context.Background()
+
+	assert.Equal(t, "animesite", p.Name())
+	assert.Equal(t, providers.MediaTypeAnime, p.Type())
+
+	results, err := p.Search(ctx, "cowboy bebop")
+	require.NoError(t, err)
+	require.Len(t, results, 1, "results without an id are dropped")
+	assert.Equal(t, "/anime/bebop", results[0].ID)
+	assert.Equal(t, "Cowboy Bebop", results[0].Title)
+	assert.Equal(t, base+"/img/bebop.jpg", results[0].PosterURL)
+	assert.Equal(t, 1998, results[0].Year)
+
+	details, err := p.GetMediaDetails(ctx, results[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cowboy Bebop", details.Title)
+
+	episodes, err := p.GetEpisodes(ctx, results[0].ID)
+	require.NoError(t, err)
+	require.Len(t, episodes, 2)
+	assert.Equal(t, 1, episodes[0].Number, "reverse lists oldest first")
+	assert.Equal(t, "Asteroid Blues", episodes[0].Title)
+	assert.Equal(t, base+"/watch/bebop-1", episodes[0].PageURL)
+
+	stream, err := p.GetStreamURL(ctx, episodes[0].ID, providers.QualityAuto)
+	require.NoError(t, err)
+	assert.Equal(t, base+"/hls/bebop-1/master.m3u8", stream.URL)
+	assert.Equal(t, providers.StreamTypeHLS, stream.Type)
+	assert.Equal(t, base+"/watch/bebop-1", stream.Referer)
+
+	_, err = p.GetStreamURL(ctx, "/watch/broken", providers.QualityAuto)
+	assert.Equal(t, providers.ErrorParserBroken, providers.ErrorKindOf(err))
+
+	_, err = p.GetStreamURL(ctx, "/watch/elsewhere", providers.QualityAuto)
+	assert.Equal(t, providers.ErrorParserBroken, providers.ErrorKindOf(err), "streams stay on the declared hosts")
+}
+
+func TestMovieSiteWithYTDLP(t *testing.T) {
+	d, err := Parse([]byte(`
+name: moviesite
+type: movie
+base_url: https://movies.example
+search:
+  url: /find/{query}
+  items: .card
+  fields:
+    id: {selector: a, attr: href}
+    title: {selector: a}
+stream:
+  type: ytdlp
+`))
+	require.NoError(t, err)
+	p := New(d)
+
+	episodes, err := p.GetEpisodes(context.Background(), "/movie/heat")
+	require.NoError(t, err)
+	require.Len(t, episodes, 1)
+
+	stream, err := p.GetStreamURL(context.Background(), episodes[0].ID, providers.QualityAuto)
+	require.NoError(t, err)
+	assert.Equal(t, "https://movies.example/movie/heat", stream.URL)
+	assert.Equal(t, providers.StreamTypeYTDLP, stream.Type)
+
+	_, err = p.GetStreamURL(context.Background(), "https://intranet.local/movie/heat", providers.QualityAuto)
+	assert.Equal(t, providers.ErrorParserBroken, providers.ErrorKindOf(err), "yt-dlp only gets pages on the declared hosts")
+	assert.Empty(t, p.EpisodePageURL("https://intranet.local/movie/heat"))
+}
+
+func TestParseRejectsIncompleteDescriptors(t *testing.T) {
+	tests := map[string]string{
+		"bad name":      "name: My Site\nbase_url: https://x",
+		"no search":     "name: site\nbase_url: https://x",
+		"no title":      "name: site\nbase_url: https://x\nsearch: {url: /s, items: a, fields: {id: {attr: href}}}",
+		"no stream":     "name: site\nbase_url: https://x\nsearch: {url: /s, items: a, fields: {id: {attr: href}, title: {selector: b}}}",
+		"bad regex":     "name: site\nbase_url: https://x\nsearch: {url: /s, items: a, fields: {id: {regex: '('}, title: {selector: b}}}\nstream: {type: ytdlp}",
+		"unknown type":  "name: site\ntype: manga\nbase_url: https://x\nsearch: {url: /s, items: a, fields: {id: {attr: href}, title: {selector: b}}}\nstream: {type: ytdlp}",
+		"file base_url": "name: site\nbase_url: file:///etc\nsearch: {url: /s, items: a, fields: {id: {attr: href}, title: {selector: b}}}\nstream: {type: ytdlp}",
+		"unknown video": "name: site\nbase_url: https://x\nsearch: {url: /s, items: a, fields: {id: {attr: href}, title: {selector: b}}}\nstream: {type: flv, source: {attr: src}}",
+	}
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	p := newTestSite(t)
+	ctx := context.Background()
+
+	_, _, err := p.fetch(ctx, "http://localhost.invalid/steal")
+	assert.ErrorContains(t, err, "not declared")
+	_, _, err = p.fetch(ctx, "file:///etc/passwd")
+	assert.ErrorContains(t, err, "not allowed")
+
+	s := &sandbox{name: "site", hosts: (&Descriptor{BaseURL: "https://site.example", Hosts: []string{"*.cdn.example"}}).allowedHosts()}
+	assert.True(t, s.allowed("site.example"))
+	assert.True(t, s.allowed("www.site.example"))
+	assert.True(t, s.allowed("img.cdn.example"))
+	assert.False(t, s.allowed("evilsite.example"))
+	assert.False(t, s.allowed("cdn.example.evil"))
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	valid := "name: site\nbase_url: https://x\nsearch: {url: /s, items: a, fields: {id: {attr: href}, title: {selector: b}}}\nstream: {type: ytdlp}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "site.yaml"), []byte(valid), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yml"), []byte("name: broken\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	loaded, err := LoadDir(dir)
+	assert.ErrorContains(t, err, "broken.yml")
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "site", loaded[0].Name())
+
+	loaded, err = LoadDir(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, loaded)
+}
@@ -0,0 +1,343 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+const userAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+var digits = regexp.MustCompile(`\d+`)
+
+// Provider scrapes a site as told by its Descriptor
+type Provider struct {
+	Client *http.Client
+	desc   *Descriptor
+	base   *url.URL
+	titles sync.Map // Media ID -> title, from search results
+}
+
+// New creates a provider from a validated descriptor
+func New(d *Descriptor) *Provider {
+	base, err := url.Parse(d.BaseURL)
+	if err != nil {
+		base = &url.URL{}
+	}
+	return &Provider{Client: sandboxClient(d), desc: d, base: base}
+}
+
+func (p *Provider) Name() string {
+	return p.desc.Name
+}
+
+func (p *Provider) Type() providers.MediaType {
+	return mediaTypes[p.desc.Type]
+}
+
+// Capabilities reports a single stream per episode, as descriptors scrape one
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true}
+}
+
+// expand fills a URL template and resolves it against the base URL
+func (p *Provider) expand(template string, vars map[string]string) string {
+	pairs := []string{"{base}", p.desc.BaseURL}
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	expanded := strings.NewReplacer(pairs...).Replace(template)
+	if ref, err := url.Parse(expanded); err == nil {
+		return p.base.ResolveReference(ref).String()
+	}
+	return expanded
+}
+
+// fetch returns the page at pageURL and its raw markup
+func (p *Provider) fetch(ctx context.Context, pageURL string) (*goquery.Document, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range p.desc.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := providers.CheckResponse(p.Name(), resp); err != nil {
+		return nil, "", err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+	if len(body) > maxPageSize {
+		return nil, "", fmt.Errorf("%s is larger than %d bytes", pageURL, maxPageSize)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err != nil {
+		return nil, "", providers.NewError(providers.ErrorParserBroken, p.Name(), fmt.Errorf("failed to parse %s: %w", pageURL, err))
+	}
+	return doc, string(body), nil
+}
+
+// extract reads a value from sel. raw is searched by a regex without a
+// selector or attribute, for values hidden in scripts.
+func (p *Provider) extract(e Extract, sel *goquery.Selection, raw string) string {
+	if e.empty() {
+		return ""
+	}
+
+	var value string
+	switch {
+	case e.Selector == "" && e.Attr == "" && raw != "":
+		value = raw
+	default:
+		target := sel
+		if e.Selector != "" {
+			target = sel.Find(e.Selector).First()
+		}
+		switch e.Attr {
+		case "":
+			value = target.Text()
+		case "html":
+			value, _ = target.Html()
+		default:
+			value, _ = target.Attr(e.Attr)
+		}
+	}
+	value = strings.TrimSpace(value)
+
+	if e.re != nil {
+		match := e.re.FindStringSubmatch(value)
+		switch {
+		case match == nil:
+			value = ""
+		case len(match) > 1:
+			value = match[1]
+		default:
+			value = match[0]
+		}
+	}
+	if e.Template != "" && value != "" {
+		value = strings.NewReplacer("{base}", p.desc.BaseURL, "{value}", value).Replace(e.Template)
+	}
+	return value
+}
+
+// items runs a list step, returning the fields of each item
+func (p *Provider) items(ctx context.Context, step ListStep, vars map[string]string) ([]map[string]string, error) {
+	doc, _, err := p.fetch(ctx, p.expand(step.URL, vars))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]string
+	doc.Find(step.Items).Each(func(_ int, sel *goquery.Selection) {
+		item := make(map[string]string, len(step.Fields))
+		for name, e := range step.Fields {
+			item[name] = p.extract(e, sel, "")
+		}
+		if item["id"] != "" && len(items) < maxItems {
+			items = append(items, item)
+		}
+	})
+	if step.Reverse {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	return items, nil
+}
+
+func firstNumber(s string) int {
+	n, _ := strconv.Atoi(digits.FindString(s))
+	return n
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]providers.Media, error) {
+	items, err := p.items(ctx, p.desc.Search, map[string]string{"query": url.QueryEscape(query)})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]providers.Media, 0, len(items))
+	for _, item := range items {
+		media := providers.Media{
+			ID:        item["id"],
+			Title:     item["title"],
+			Type:      p.Type(),
+			PosterURL: item["poster"],
+			Year:      firstNumber(item["year"]),
+		}
+		if media.PosterURL != "" {
+			media.PosterURL = p.expand(media.PosterURL, nil)
+		}
+		p.titles.Store(media.ID, media.Title)
+		results = append(results, media)
+	}
+	return results, nil
+}
+
+func (p *Provider) GetTrending(ctx context.Context) ([]providers.Media, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (p *Provider) GetRecent(ctx context.Context) ([]providers.Media, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (p *Provider) GetMediaDetails(ctx context.Context, id string) (*providers.MediaDetails, error) {
+	seasons, _ := p.GetSeasons(ctx, id)
+	title, _ := p.titles.Load(id)
+	name, _ := title.(string)
+	return &providers.MediaDetails{
+		Media:   providers.Media{ID: id, Title: name, Type: p.Type()},
+		Seasons: seasons,
+	}, nil
+}
+
+// GetSeasons returns a single season holding all episodes
+func (p *Provider) GetSeasons(ctx context.Context, mediaID string) ([]providers.Season, error) {
+	return []providers.Season{{ID: mediaID, Number: 1, Title: "Season 1"}}, nil
+}
+
+// GetEpisodes lists the episodes of a media. Sites without an episodes step
+// serve each media as a single episode.
+func (p *Provider) GetEpisodes(ctx context.Context, seasonID string) ([]providers.Episode, error) {
+	if p.desc.Episodes.Items == "" {
+		title, _ := p.titles.Load(seasonID)
+		name, _ := title.(string)
+		return []providers.Episode{{ID: seasonID, Number: 1, Title: name, PageURL: p.EpisodePageURL(seasonID)}}, nil
+	}
+
+	items, err := p.items(ctx, p.desc.Episodes, map[string]string{"id": seasonID})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, providers.NewError(providers.ErrorNotFound, p.Name(), fmt.Errorf("no episodes found for %s", seasonID))
+	}
+
+	episodes := make([]providers.Episode, 0, len(items))
+	for i, item := range items {
+		number := firstNumber(item["number"])
+		if number == 0 {
+			number = i + 1
+		}
+		episodes = append(episodes, providers.Episode{
+			ID:      item["id"],
+			Number:  number,
+			Title:   item["title"],
+			PageURL: p.EpisodePageURL(item["id"]),
+		})
+	}
+	return episodes, nil
+}
+
+// EpisodePageURL returns the page of an episode, for the yt-dlp fallback.
+// Pages outside the declared hosts are not offered.
+func (p *Provider) EpisodePageURL(episodeID string) string {
+	page := p.pageURL(episodeID)
+	if p.checkStreamHost(page) != nil {
+		return ""
+	}
+	return page
+}
+
+// pageURL returns the page the stream of an episode is scraped from
+func (p *Provider) pageURL(episodeID string) string {
+	return p.expand(p.desc.Stream.URL, map[string]string{"id": episodeID})
+}
+
+func (p *Provider) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
+	pageURL := p.pageURL(episodeID)
+	stream := &providers.StreamURL{
+		Quality: providers.QualityAuto,
+		Type:    providers.StreamType(p.desc.Stream.Type),
+		Headers: p.desc.Headers,
+		Referer: pageURL,
+	}
+	if p.desc.Stream.Referer != "" {
+		stream.Referer = p.expand(p.desc.Stream.Referer, map[string]string{"id": episodeID})
+	}
+
+	if p.desc.Stream.Source.empty() {
+		// yt-dlp resolves the page at play time
+		if err := p.checkStreamHost(pageURL); err != nil {
+			return nil, err
+		}
+		stream.URL = pageURL
+		return stream, nil
+	}
+
+	doc, raw, err := p.fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	source := p.extract(p.desc.Stream.Source, doc.Selection, raw)
+	if source == "" {
+		return nil, providers.NewError(providers.ErrorParserBroken, p.Name(), fmt.Errorf("no stream found on %s", pageURL))
+	}
+	if ref, err := url.Parse(source); err == nil {
+		if page, err := url.Parse(pageURL); err == nil {
+			source = page.ResolveReference(ref).String()
+		}
+	}
+	if err := p.checkStreamHost(source); err != nil {
+		return nil, err
+	}
+	stream.URL = source
+
+	if stream.Type == "" {
+		switch {
+		case strings.Contains(source, ".m3u8"):
+			stream.Type = providers.StreamTypeHLS
+		case strings.Contains(source, ".mpd"):
+			stream.Type = providers.StreamTypeDASH
+		case strings.Contains(source, ".mkv"):
+			stream.Type = providers.StreamTypeMKV
+		default:
+			stream.Type = providers.StreamTypeMP4
+		}
+	}
+	return stream, nil
+}
+
+// checkStreamHost fails when a stream URL leaves the declared hosts. mpv and
+// yt-dlp fetch it outside the sandbox, so it has to be checked here.
+func (p *Provider) checkStreamHost(streamURL string) error {
+	allowed := sandbox{hosts: p.desc.allowedHosts()}
+	u, err := url.Parse(streamURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !allowed.allowed(u.Hostname()) {
+		return providers.NewError(providers.ErrorParserBroken, p.Name(),
+			fmt.Errorf("stream %s is not on a host declared in base_url or hosts", streamURL))
+	}
+	return nil
+}
+
+func (p *Provider) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
+	return []providers.Quality{providers.QualityAuto}, nil
+}
+
+// HealthCheck checks that the site answers
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	_, _, err := p.fetch(ctx, p.desc.BaseURL)
+	return err
+}
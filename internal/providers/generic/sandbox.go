@@ -0,0 +1,62 @@
+package generic
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/netbind"
+)
+
+// Limits of a scraper, so a hostile descriptor can't hang or exhaust greg
+const (
+	callTimeout = 30 * time.Second // Per page fetch, redirects included
+	maxPageSize = 5 << 20          // Bytes read of a page
+	maxItems    = 5000             // Search results or episodes kept
+)
+
+// sandbox is the transport of a scraper: it only reaches the scraper's
+// declared hosts over http(s). Scrapers never touch the filesystem and get
+// no access to greg's tokens or database; this keeps their network access
+// to the sites they describe.
+type sandbox struct {
+	name  string
+	hosts []string // Lowercase host names, subdomains included
+	base  http.RoundTripper
+}
+
+// RoundTrip rejects requests outside the declared hosts. Redirects pass
+// through here too, so they can't leave the allowed hosts either.
+func (s *sandbox) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("%s: scheme %q is not allowed", s.name, req.URL.Scheme)
+	}
+	if !s.allowed(req.URL.Hostname()) {
+		return nil, fmt.Errorf("%s: host %s is not declared in base_url or hosts", s.name, req.URL.Hostname())
+	}
+	base := s.base
+	if base == nil {
+		// Looked up per request, so --record/--replay apply
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+func (s *sandbox) allowed(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, h := range s.hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// sandboxClient returns the http.Client of a scraper
+func sandboxClient(d *Descriptor) *http.Client {
+	return &http.Client{
+		Transport: &sandbox{name: d.Name, hosts: d.allowedHosts(), base: netbind.Transport(d.Name)},
+		Timeout:   callTimeout,
+	}
+}
@@ -0,0 +1,402 @@
+package comick
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/justchokingaround/greg/internal/netbind"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/pkg/types"
+)
+
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"
+
+type Comick struct {
+	BaseURL   string // API base
+	ImageURL  string // Page image CDN
+	Language  string // Chapter language
+	Client    *http.Client
+	infoCache sync.Map
+}
+
+func New() *Comick {
+	return &Comick{
+		BaseURL:  "https://api.comick.fun",
+		ImageURL: "https://meo.comick.pictures",
+		Language: "en",
+		Client:   &http.Client{Transport: netbind.Transport("comick")},
+	}
+}
+
+func (c *Comick) Name() string {
+	return "comick"
+}
+
+// Type returns the media type this provider supports
+func (c *Comick) Type() providers.MediaType {
+	return providers.MediaTypeManga
+}
+
+// Capabilities reports none, chapters are read rather than streamed
+func (c *Comick) Capabilities() providers.Capabilities {
+	return providers.Capabilities{}
+}
+
+// getJSON fetches an API path and decodes the JSON response into v
+func (c *Comick) getJSON(ctx context.Context, path string, v interface{}) error {
+	reqURL := c.BaseURL + path
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := providers.CheckResponse(c.Name(), resp); err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request to %s returned status %d: %s", reqURL, resp.StatusCode, string(bodyBytes))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return providers.NewError(providers.ErrorParserBroken, c.Name(), fmt.Errorf("failed to decode response from %s: %w", reqURL, err))
+	}
+	return nil
+}
+
+type cover struct {
+	B2Key string `json:"b2key"`
+}
+
+// coverURL returns the URL of the first cover, if any
+func (c *Comick) coverURL(covers []cover) string {
+	if len(covers) == 0 || covers[0].B2Key == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", c.ImageURL, covers[0].B2Key)
+}
+
+// comicStatus maps Comick's numeric publication status
+func comicStatus(status int) string {
+	switch status {
+	case 1:
+		return "Ongoing"
+	case 2:
+		return "Completed"
+	case 3:
+		return "Cancelled"
+	case 4:
+		return "Hiatus"
+	default:
+		return ""
+	}
+}
+
+// splitID splits a media ID of the form "hid::slug"
+func splitID(id string) (hid, slug string, err error) {
+	parts := strings.Split(id, "::")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid comick id: %s", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// Search searches for manga by query
+func (c *Comick) Search(ctx context.Context, query string) ([]providers.Media, error) {
+	var items []struct {
+		HID      string  `json:"hid"`
+		Slug     string  `json:"slug"`
+		Title    string  `json:"title"`
+		Desc     string  `json:"desc"`
+		Year     int     `json:"year"`
+		Status   int     `json:"status"`
+		Rating   string  `json:"rating"`
+		MDCovers []cover `json:"md_covers"`
+	}
+
+	path := fmt.Sprintf("/v1.0/search/?q=%s&limit=20&page=1", url.QueryEscape(query))
+	if err := c.getJSON(ctx, path, &items); err != nil {
+		return nil, err
+	}
+
+	var mediaList []providers.Media
+	for _, item := range items {
+		rating, _ := strconv.ParseFloat(item.Rating, 64)
+		mediaList = append(mediaList, providers.Media{
+			ID:        fmt.Sprintf("%s::%s", item.HID, item.Slug),
+			Title:     item.Title,
+			Type:      providers.MediaTypeManga,
+			Year:      item.Year,
+			Synopsis:  item.Desc,
+			PosterURL: c.coverURL(item.MDCovers),
+			Rating:    rating,
+			Status:    comicStatus(item.Status),
+		})
+	}
+	return mediaList, nil
+}
+
+// GetInfo fetches a manga and its chapters
+func (c *Comick) GetInfo(ctx context.Context, id string) (*types.MangaInfo, error) {
+	if cached, ok := c.infoCache.Load(id); ok {
+		return cached.(*types.MangaInfo), nil
+	}
+
+	hid, slug, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var comicResponse struct {
+		Comic struct {
+			Title    string  `json:"title"`
+			Desc     string  `json:"desc"`
+			Status   int     `json:"status"`
+			MDCovers []cover `json:"md_covers"`
+			Genres   []struct {
+				MDGenres struct {
+					Name string `json:"name"`
+				} `json:"md_genres"`
+			} `json:"md_comic_md_genres"`
+		} `json:"comic"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("/comic/%s/", url.PathEscape(slug)), &comicResponse); err != nil {
+		return nil, err
+	}
+
+	comic := comicResponse.Comic
+	mangaInfo := &types.MangaInfo{
+		ID:          id,
+		Title:       comic.Title,
+		Description: comic.Desc,
+		Image:       c.coverURL(comic.MDCovers),
+		URL:         fmt.Sprintf("https://comick.io/comic/%s", slug),
+		Status:      types.MediaStatus(comicStatus(comic.Status)),
+	}
+	for _, genre := range comic.Genres {
+		if genre.MDGenres.Name != "" {
+			mangaInfo.Genres = append(mangaInfo.Genres, genre.MDGenres.Name)
+		}
+	}
+
+	chapters, err := c.getChapters(ctx, hid)
+	if err != nil {
+		return nil, err
+	}
+	mangaInfo.Chapters = chapters
+
+	c.infoCache.Store(id, mangaInfo)
+	return mangaInfo, nil
+}
+
+type chapterItem struct {
+	HID       string   `json:"hid"`
+	Chap      string   `json:"chap"`
+	Vol       string   `json:"vol"`
+	Title     string   `json:"title"`
+	UpCount   int      `json:"up_count"`
+	GroupName []string `json:"group_name"`
+}
+
+// getChapters fetches all chapters and keeps one release per chapter number
+func (c *Comick) getChapters(ctx context.Context, hid string) ([]types.MangaChapter, error) {
+	const limit = 300
+
+	var all []chapterItem
+	for page := 1; ; page++ {
+		var chaptersResponse struct {
+			Chapters []chapterItem `json:"chapters"`
+			Total    int           `json:"total"`
+		}
+		path := fmt.Sprintf("/comic/%s/chapters?lang=%s&limit=%d&page=%d&chap-order=1",
+			url.PathEscape(hid), url.QueryEscape(c.Language), limit, page)
+		if err := c.getJSON(ctx, path, &chaptersResponse); err != nil {
+			return nil, err
+		}
+
+		all = append(all, chaptersResponse.Chapters...)
+		if len(chaptersResponse.Chapters) < limit || len(all) >= chaptersResponse.Total {
+			break
+		}
+	}
+
+	return pickReleases(all), nil
+}
+
+// pickReleases keeps the most upvoted release of every chapter, sorted by
+// chapter number
+func pickReleases(items []chapterItem) []types.MangaChapter {
+	best := make(map[string]chapterItem)
+	var order []string
+	for _, item := range items {
+		key := item.Chap
+		if key == "" {
+			// Oneshots and extras without a number are kept as they are
+			key = "hid:" + item.HID
+		}
+		current, seen := best[key]
+		if !seen {
+			order = append(order, key)
+		}
+		if !seen || item.UpCount > current.UpCount {
+			best[key] = item
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(best[order[i]].Chap, 64)
+		b, errB := strconv.ParseFloat(best[order[j]].Chap, 64)
+		if errA != nil || errB != nil {
+			return errA == nil
+		}
+		return a < b
+	})
+
+	chapters := make([]types.MangaChapter, 0, len(order))
+	for _, key := range order {
+		item := best[key]
+		title := item.Title
+		if title == "" {
+			title = fmt.Sprintf("Chapter %s", item.Chap)
+		}
+		chapters = append(chapters, types.MangaChapter{
+			ID:        item.HID,
+			Title:     title,
+			Number:    item.Chap,
+			Volume:    item.Vol,
+			ScanGroup: strings.Join(item.GroupName, ", "),
+		})
+	}
+	return chapters
+}
+
+// GetMediaDetails fetches detailed info for a manga
+func (c *Comick) GetMediaDetails(ctx context.Context, id string) (*providers.MediaDetails, error) {
+	mangaInfo, err := c.GetInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &providers.MediaDetails{
+		Media: providers.Media{
+			ID:            mangaInfo.ID,
+			Title:         mangaInfo.Title,
+			Type:          providers.MediaTypeManga,
+			PosterURL:     mangaInfo.Image,
+			Synopsis:      mangaInfo.Description,
+			Genres:        mangaInfo.Genres,
+			Status:        string(mangaInfo.Status),
+			TotalEpisodes: len(mangaInfo.Chapters),
+		},
+		// Manga has one "season" containing all chapters
+		Seasons: []providers.Season{{
+			ID:     id,
+			Number: 1,
+			Title:  "Chapters",
+		}},
+	}, nil
+}
+
+// GetSeasons returns seasons for a manga (always single season)
+func (c *Comick) GetSeasons(ctx context.Context, mediaID string) ([]providers.Season, error) {
+	return []providers.Season{{
+		ID:     mediaID,
+		Number: 1,
+		Title:  "Chapters",
+	}}, nil
+}
+
+// GetEpisodes returns chapters as episodes
+func (c *Comick) GetEpisodes(ctx context.Context, seasonID string) ([]providers.Episode, error) {
+	mangaInfo, err := c.GetInfo(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var episodes []providers.Episode
+	for i, ch := range mangaInfo.Chapters {
+		epNum := i + 1
+		if num, err := strconv.ParseFloat(ch.Number, 64); err == nil {
+			epNum = int(num)
+		}
+
+		var volume int
+		_, _ = fmt.Sscanf(ch.Volume, "%d", &volume)
+
+		episodes = append(episodes, providers.Episode{
+			ID:        ch.ID,
+			Number:    epNum,
+			Title:     ch.Title,
+			Season:    1,
+			Volume:    volume,
+			ScanGroup: ch.ScanGroup,
+		})
+	}
+	return episodes, nil
+}
+
+// GetMangaPages fetches manga pages for a chapter
+func (c *Comick) GetMangaPages(ctx context.Context, chapterID string) ([]string, error) {
+	var chapterResponse struct {
+		Chapter struct {
+			MDImages []cover `json:"md_images"`
+		} `json:"chapter"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("/chapter/%s/", url.PathEscape(chapterID)), &chapterResponse); err != nil {
+		return nil, err
+	}
+
+	var pages []string
+	for _, img := range chapterResponse.Chapter.MDImages {
+		if img.B2Key != "" {
+			pages = append(pages, fmt.Sprintf("%s/%s", c.ImageURL, img.B2Key))
+		}
+	}
+	if len(pages) == 0 {
+		return nil, providers.NewError(providers.ErrorNotFound, c.Name(), fmt.Errorf("no pages found for chapter %s", chapterID))
+	}
+	return pages, nil
+}
+
+// GetStreamURL not applicable for manga
+func (c *Comick) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
+	return nil, fmt.Errorf("not applicable for manga")
+}
+
+// GetAvailableQualities not applicable for manga
+func (c *Comick) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
+	return nil, fmt.Errorf("not applicable for manga")
+}
+
+// GetTrending returns trending manga
+func (c *Comick) GetTrending(ctx context.Context) ([]providers.Media, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetRecent returns recent manga
+func (c *Comick) GetRecent(ctx context.Context) ([]providers.Media, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// HealthCheck checks if the provider is accessible
+func (c *Comick) HealthCheck(ctx context.Context) error {
+	var items []json.RawMessage
+	return c.getJSON(ctx, "/v1.0/search/?q=one&limit=1", &items)
+}
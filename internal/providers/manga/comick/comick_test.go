@@ -0,0 +1,96 @@
+package comick
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers/fixtures"
+)
+
+func newTestServer(t *testing.T) *Comick {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1.0/search/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "frieren", r.URL.Query().Get("q"))
+		fmt.Fprint(w, `[{"hid":"abc","slug":"frieren","title":"Frieren","year":2020,"status":1,"rating":"8.9","md_covers":[{"b2key":"cover.jpg"}]}]`)
+	})
+	mux.HandleFunc("/comic/frieren/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"comic":{"title":"Frieren","desc":"After the party","status":1,"md_comic_md_genres":[{"md_genres":{"name":"Fantasy"}}]}}`)
+	})
+	mux.HandleFunc("/comic/abc/chapters", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "en", r.URL.Query().Get("lang"))
+		fmt.Fprint(w, `{"total":4,"chapters":[
+			{"hid":"c2a","chap":"2","vol":"1","up_count":3,"group_name":["Low"]},
+			{"hid":"c1","chap":"1","vol":"1","up_count":5,"group_name":["Alpha"]},
+			{"hid":"c2b","chap":"2","vol":"1","up_count":9,"group_name":["High","Co"]},
+			{"hid":"c10","chap":"10","vol":"","title":"Ten","up_count":1}
+		]}`)
+	})
+	mux.HandleFunc("/chapter/c1/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"chapter":{"md_images":[{"b2key":"1.jpg"},{"b2key":"2.jpg"}]}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	c := New()
+	c.BaseURL = server.URL
+	c.ImageURL = "https://img.test"
+	return c
+}
+
+func TestSearch(t *testing.T) {
+	c := newTestServer(t)
+
+	results, err := c.Search(context.Background(), "frieren")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "abc::frieren", results[0].ID)
+	assert.Equal(t, "https://img.test/cover.jpg", results[0].PosterURL)
+	assert.Equal(t, "Ongoing", results[0].Status)
+	assert.Equal(t, 8.9, results[0].Rating)
+}
+
+func TestGetEpisodesPicksMostUpvotedRelease(t *testing.T) {
+	c := newTestServer(t)
+
+	episodes, err := c.GetEpisodes(context.Background(), "abc::frieren")
+	require.NoError(t, err)
+	require.Len(t, episodes, 3)
+
+	assert.Equal(t, 1, episodes[0].Number)
+	assert.Equal(t, "Alpha", episodes[0].ScanGroup)
+	assert.Equal(t, 2, episodes[1].Number)
+	assert.Equal(t, "c2b", episodes[1].ID)
+	assert.Equal(t, "High, Co", episodes[1].ScanGroup)
+	assert.Equal(t, 1, episodes[1].Volume)
+	assert.Equal(t, 10, episodes[2].Number)
+	assert.Equal(t, "Ten", episodes[2].Title)
+	assert.Zero(t, episodes[2].Volume)
+}
+
+func TestGetMangaPages(t *testing.T) {
+	c := newTestServer(t)
+
+	pages, err := c.GetMangaPages(context.Background(), "c1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://img.test/1.jpg", "https://img.test/2.jpg"}, pages)
+}
+
+func TestSearchReplaysFixture(t *testing.T) {
+	c := New()
+	c.Client = fixtures.Client("testdata/fixtures")
+
+	results, err := c.Search(context.Background(), "chainsaw man")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "jqMG5rKd::chainsaw-man", results[0].ID)
+	assert.Equal(t, "Chainsaw Man", results[0].Title)
+	assert.Equal(t, 2018, results[0].Year)
+	assert.Equal(t, "Completed", results[1].Status)
+}
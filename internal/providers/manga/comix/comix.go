@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/types"
 )
@@ -27,7 +28,7 @@ type Comix struct {
 func New() *Comix {
 	return &Comix{
 		BaseURL: "https://comix.to",
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: netbind.Transport("comix")},
 	}
 }
 
@@ -55,6 +56,10 @@ func (c *Comix) searchOld(query string) (*types.SearchResults, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(c.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("request to %s returned status %d: %s", urlStr, resp.StatusCode, string(bodyBytes))
@@ -120,6 +125,10 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(c.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -137,7 +146,7 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 		endMarker := `,\"scanlationGroups\"`
 		endIndex := strings.Index(tempStr, endMarker)
 		if endIndex == -1 {
-			return nil, fmt.Errorf("could not find manga data end in response body (escaped format)")
+			return nil, providers.NewError(providers.ErrorParserBroken, c.Name(), fmt.Errorf("could not find manga data end in response body (escaped format)"))
 		}
 		valueStr := tempStr[len(escapedMarker):endIndex]
 		jsonData = strings.ReplaceAll(valueStr, `\"`, `"`)
@@ -145,13 +154,13 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 	} else {
 		startIndex = strings.Index(string(bodyBytes), `"manga":`)
 		if startIndex == -1 {
-			return nil, fmt.Errorf("could not find manga data start in response body")
+			return nil, providers.NewError(providers.ErrorParserBroken, c.Name(), fmt.Errorf("could not find manga data start in response body"))
 		}
 
 		tempStr := string(bodyBytes[startIndex:])
 		endIndex := strings.Index(tempStr, `,"scanlationGroups"`)
 		if endIndex == -1 {
-			return nil, fmt.Errorf("could not find manga data end in response body")
+			return nil, providers.NewError(providers.ErrorParserBroken, c.Name(), fmt.Errorf("could not find manga data end in response body"))
 		}
 
 		jsonData = tempStr[len(`"manga":`):endIndex]
@@ -170,7 +179,7 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 	}
 
 	if err := json.Unmarshal([]byte(jsonData), &mangaData); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON from manga data: %w\nRaw JSON: %s", err, jsonData)
+		return nil, providers.NewError(providers.ErrorParserBroken, c.Name(), fmt.Errorf("failed to parse JSON from manga data: %w\nRaw JSON: %s", err, jsonData))
 	}
 
 	mangaInfo := &types.MangaInfo{
@@ -191,6 +200,7 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 	var allChapters []struct {
 		ChapterID       int         `json:"chapter_id"`
 		Number          interface{} `json:"number"`
+		Volume          interface{} `json:"volume"`
 		Name            string      `json:"name"`
 		IsOfficial      int         `json:"is_official"`
 		ScanlationGroup struct {
@@ -213,11 +223,16 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 		}
 		defer func() { _ = resp.Body.Close() }()
 
+		if err := providers.CheckResponse(c.Name(), resp); err != nil {
+			return nil, err
+		}
+
 		var chaptersResponse struct {
 			Result struct {
 				Items []struct {
 					ChapterID       int         `json:"chapter_id"`
 					Number          interface{} `json:"number"`
+					Volume          interface{} `json:"volume"`
 					Name            string      `json:"name"`
 					IsOfficial      int         `json:"is_official"`
 					ScanlationGroup struct {
@@ -247,6 +262,7 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 	type ChapterItem struct {
 		ChapterID       int
 		Number          interface{}
+		Volume          interface{}
 		Name            string
 		IsOfficial      int
 		ScanlationGroup string
@@ -258,6 +274,7 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 		chapterMap[numStr] = append(chapterMap[numStr], ChapterItem{
 			ChapterID:       item.ChapterID,
 			Number:          item.Number,
+			Volume:          item.Volume,
 			Name:            item.Name,
 			IsOfficial:      item.IsOfficial,
 			ScanlationGroup: item.ScanlationGroup.Name,
@@ -328,9 +345,11 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 		}
 
 		mangaInfo.Chapters = append(mangaInfo.Chapters, types.MangaChapter{
-			ID:     fmt.Sprintf("%s::%s::%d::%v", hashId, slug, selectedItem.ChapterID, selectedItem.Number),
-			Title:  title,
-			Number: fmt.Sprintf("%v", selectedItem.Number),
+			ID:        fmt.Sprintf("%s::%s::%d::%v", hashId, slug, selectedItem.ChapterID, selectedItem.Number),
+			Title:     title,
+			Number:    fmt.Sprintf("%v", selectedItem.Number),
+			Volume:    chapterVolume(selectedItem.Volume),
+			ScanGroup: selectedItem.ScanlationGroup,
 		})
 	}
 
@@ -345,6 +364,25 @@ func (c *Comix) GetInfo(id string) (interface{}, error) {
 	return mangaInfo, nil
 }
 
+// chapterVolume formats the volume of a chapter, empty when the API has none
+func chapterVolume(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		if v <= 0 {
+			return ""
+		}
+		return strconv.Itoa(int(v))
+	default:
+		s := fmt.Sprintf("%v", v)
+		if s == "0" {
+			return ""
+		}
+		return s
+	}
+}
+
 func (c *Comix) GetSources(episodeID string) (interface{}, error) {
 	parts := strings.Split(episodeID, "::")
 	if len(parts) != 4 {
@@ -372,6 +410,10 @@ func (c *Comix) GetSources(episodeID string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(c.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -421,7 +463,7 @@ func (c *Comix) GetSources(episodeID string) (interface{}, error) {
 		URL string `json:"url"`
 	}
 	if err := json.Unmarshal([]byte(jsonStr), &images); err != nil {
-		return nil, fmt.Errorf("failed to parse images JSON: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, c.Name(), fmt.Errorf("failed to parse images JSON: %w", err))
 	}
 
 	var pages []*types.MangaPage
@@ -444,6 +486,11 @@ func (c *Comix) Type() providers.MediaType {
 	return providers.MediaTypeManga
 }
 
+// Capabilities reports none, chapters are read rather than streamed
+func (c *Comix) Capabilities() providers.Capabilities {
+	return providers.Capabilities{}
+}
+
 // Search (new interface) searches for manga by query
 func (c *Comix) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	oldResults, err := c.searchOld(query)
@@ -533,11 +580,16 @@ func (c *Comix) GetEpisodes(ctx context.Context, seasonID string) ([]providers.E
 			epNum = int(num)
 		}
 
+		var volume int
+		_, _ = fmt.Sscanf(ch.Volume, "%d", &volume)
+
 		episodes = append(episodes, providers.Episode{
-			ID:     ch.ID,
-			Number: epNum,
-			Title:  ch.Title,
-			Season: 1,
+			ID:        ch.ID,
+			Number:    epNum,
+			Title:     ch.Title,
+			Season:    1,
+			Volume:    volume,
+			ScanGroup: ch.ScanGroup,
 		})
 	}
 
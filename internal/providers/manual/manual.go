@@ -0,0 +1,89 @@
+// Package manual plays stream URLs pasted by the user, outside of any
+// provider. History entries of such playback use ProviderName and the stream
+// URL as media ID, so they can be resumed later.
+package manual
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// ProviderName is the provider recorded in history for pasted URLs
+const ProviderName = "manual"
+
+// Stream validates a pasted URL and returns it as a stream. The stream type
+// is guessed from the file extension; anything that isn't HLS, DASH or MKV is
+// played as a direct file.
+func Stream(rawURL, referer string, headers map[string]string) (*providers.StreamURL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("not an http(s) URL: %s", rawURL)
+	}
+
+	stream := &providers.StreamURL{
+		URL:     rawURL,
+		Quality: providers.QualityAuto,
+		Type:    providers.StreamTypeMP4,
+		Referer: referer,
+		Headers: headers,
+	}
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".m3u8", ".m3u":
+		stream.Type = providers.StreamTypeHLS
+	case ".mpd":
+		stream.Type = providers.StreamTypeDASH
+	case ".mkv":
+		stream.Type = providers.StreamTypeMKV
+	}
+	return stream, nil
+}
+
+// Title returns a display title for a pasted URL: the file name without its
+// extension, or the host for playlists and paths without one
+func Title(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return u.Host
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	name = strings.TrimSuffix(name, path.Ext(name))
+	// "index.m3u8", "master.m3u8" and the like say nothing about the video
+	switch strings.ToLower(name) {
+	case "", "index", "master", "playlist", "manifest", "video":
+		return u.Host
+	}
+	return name
+}
+
+// ParseHeaders parses "Name: value" header flags
+func ParseHeaders(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	headers := make(map[string]string, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: expected \"Name: value\"", v)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
@@ -0,0 +1,52 @@
+package manual
+
+import (
+	"testing"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		url  string
+		want providers.StreamType
+	}{
+		{"https://cdn.example.com/hls/master.m3u8?token=abc", providers.StreamTypeHLS},
+		{"https://cdn.example.com/dash/manifest.mpd", providers.StreamTypeDASH},
+		{"http://files.example.com/Frieren%20-%2001.mkv", providers.StreamTypeMKV},
+		{"https://files.example.com/video.mp4", providers.StreamTypeMP4},
+		{"https://files.example.com/stream", providers.StreamTypeMP4},
+	}
+	for _, tt := range tests {
+		stream, err := Stream(tt.url, "https://example.com/", nil)
+		require.NoError(t, err, tt.url)
+		assert.Equal(t, tt.want, stream.Type, tt.url)
+		assert.Equal(t, "https://example.com/", stream.Referer)
+	}
+
+	for _, bad := range []string{"", "not a url", "ftp://example.com/a.mp4", "file:///tmp/a.mkv", "https://"} {
+		_, err := Stream(bad, "", nil)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "Frieren - 01", Title("http://files.example.com/Frieren%20-%2001.mkv"))
+	assert.Equal(t, "cdn.example.com", Title("https://cdn.example.com/hls/master.m3u8?token=abc"))
+	assert.Equal(t, "example.com", Title("https://example.com/"))
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders([]string{"Origin: https://example.com", "X-Token:abc"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Origin": "https://example.com", "X-Token": "abc"}, headers)
+
+	_, err = ParseHeaders([]string{"no colon"})
+	assert.Error(t, err)
+
+	headers, err = ParseHeaders(nil)
+	require.NoError(t, err)
+	assert.Nil(t, headers)
+}
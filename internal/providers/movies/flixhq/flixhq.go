@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/extractors"
 	"github.com/justchokingaround/greg/pkg/types"
@@ -27,7 +28,7 @@ type FlixHQ struct {
 func New() *FlixHQ {
 	return &FlixHQ{
 		BaseURL: "https://flixhq.to",
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: netbind.Transport("flixhq")},
 	}
 }
 
@@ -62,6 +63,10 @@ func (f *FlixHQ) searchOld(query string) (*types.SearchResults, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(f.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("search returned status code %d", resp.StatusCode)
 	}
@@ -155,6 +160,10 @@ func (f *FlixHQ) GetInfo(id string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(f.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("info request returned status code %d", resp.StatusCode)
 	}
@@ -304,6 +313,10 @@ func (f *FlixHQ) GetServers(episodeID string) ([]types.EpisodeServer, error) {
 	}
 	defer func() { _ = resp2.Body.Close() }()
 
+	if err := providers.CheckResponse(f.Name(), resp2); err != nil {
+		return nil, err
+	}
+
 	// Read response body
 	body, err := io.ReadAll(resp2.Body)
 	if err != nil {
@@ -437,6 +450,10 @@ func (f *FlixHQ) extractSourcesFromServer(server types.EpisodeServer) (*types.Vi
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(f.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("server returned status %d for URL: %s", resp.StatusCode, server.URL)
 	}
@@ -486,7 +503,7 @@ func (f *FlixHQ) extractSourcesFromServer(server types.EpisodeServer) (*types.Vi
 	}
 
 	// If no embed URL found, return error with more context
-	return nil, fmt.Errorf("no embed URL found in response from %s", server.URL)
+	return nil, providers.NewError(providers.ErrorParserBroken, f.Name(), fmt.Errorf("no embed URL found in response from %s", server.URL))
 }
 
 // Type returns the media type this provider supports
@@ -494,6 +511,11 @@ func (f *FlixHQ) Type() providers.MediaType {
 	return providers.MediaTypeMovieTV
 }
 
+// Capabilities reports movies, qualities and subtitle tracks
+func (f *FlixHQ) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true, Movies: true, Qualities: true, Subtitles: true}
+}
+
 // Search (new interface) searches for movies/shows by query
 func (f *FlixHQ) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	oldResults, err := f.searchOld(query)
@@ -668,10 +690,11 @@ func (f *FlixHQ) GetEpisodes(ctx context.Context, seasonID string) ([]providers.
 	if len(movieInfo.Episodes) == 0 && movieInfo.Type == "Movie" {
 		// Single movie episode
 		episodes = append(episodes, providers.Episode{
-			ID:     movieInfo.ID,
-			Number: 1,
-			Title:  movieInfo.Title,
-			Season: 1,
+			ID:      movieInfo.ID,
+			Number:  1,
+			Title:   movieInfo.Title,
+			Season:  1,
+			PageURL: movieInfo.URL,
 		})
 	} else {
 		for _, ep := range movieInfo.Episodes {
@@ -794,5 +817,5 @@ func (f *FlixHQ) GetMovieEpisodeID(ctx context.Context, mediaID string) (string,
 	if len(movieInfo.Episodes) > 0 {
 		return movieInfo.Episodes[0].ID, nil
 	}
-	return "", fmt.Errorf("no episodes found for movie %s", mediaID)
+	return "", providers.NewError(providers.ErrorNotFound, f.Name(), fmt.Errorf("no episodes found for movie %s", mediaID))
 }
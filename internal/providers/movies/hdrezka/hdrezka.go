@@ -14,6 +14,7 @@ import (
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/types"
 )
@@ -26,12 +27,8 @@ type HDRezka struct {
 }
 
 func New() *HDRezka {
-	// Create client with transport that handles compression
-	transport := &http.Transport{
-		DisableCompression: false,
-	}
 	return &HDRezka{
-		Client:  &http.Client{Transport: transport},
+		Client:  &http.Client{Transport: netbind.Transport("hdrezka")},
 		BaseURL: "https://hdrezka.website",
 	}
 }
@@ -59,6 +56,10 @@ func (p *HDRezka) searchOld(query string) (*types.SearchResults, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(p.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, err
@@ -145,6 +146,10 @@ func (p *HDRezka) GetInfo(id string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(p.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -257,6 +262,10 @@ func (p *HDRezka) fetchEpisodes(dataID, translatorID, seasonID string) ([]types.
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(p.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	var result struct {
 		Success  bool   `json:"success"`
 		Episodes string `json:"episodes"`
@@ -333,6 +342,10 @@ func (p *HDRezka) GetSources(episodeID string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(p.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -410,6 +423,11 @@ func (p *HDRezka) Type() providers.MediaType {
 	return providers.MediaTypeMovieTV
 }
 
+// Capabilities reports streams in several qualities; movies are listed as a season
+func (p *HDRezka) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true, Qualities: true}
+}
+
 // Search (new interface) searches for movies/shows by query
 func (p *HDRezka) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	oldResults, err := p.searchOld(query)
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/extractors"
 	"github.com/justchokingaround/greg/pkg/types"
@@ -26,7 +27,7 @@ type SFlix struct {
 func New() *SFlix {
 	return &SFlix{
 		BaseURL: "https://sflix.ps",
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: netbind.Transport("sflix")},
 	}
 }
 
@@ -38,6 +39,11 @@ func (s *SFlix) Type() providers.MediaType {
 	return providers.MediaTypeMovieTV
 }
 
+// Capabilities reports movies, qualities and subtitle tracks
+func (s *SFlix) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true, Movies: true, Qualities: true, Subtitles: true}
+}
+
 // Search searches for movies/shows by query
 func (s *SFlix) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	if cached, ok := s.searchCache.Load(query); ok {
@@ -62,6 +68,10 @@ func (s *SFlix) Search(ctx context.Context, query string) ([]providers.Media, er
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(s.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -391,6 +401,10 @@ func (s *SFlix) GetInfo(id string) (interface{}, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(s.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
@@ -521,6 +535,10 @@ func (s *SFlix) fetchEpisodeList(showID string) ([]types.Episode, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(s.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	seasonBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read season list response: %w", err)
@@ -660,6 +678,10 @@ func (s *SFlix) FetchEpisodeServersWithMediaID(episodeID string, mediaID string)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(s.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read servers response: %w", err)
@@ -790,6 +812,10 @@ func (s *SFlix) extractSourcesFromServer(server types.EpisodeServer) (*types.Vid
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := providers.CheckResponse(s.Name(), resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read sources response: %w", err)
@@ -802,11 +828,11 @@ func (s *SFlix) extractSourcesFromServer(server types.EpisodeServer) (*types.Vid
 	}
 
 	if err := json.Unmarshal(body, &jsonResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse sources JSON: %w", err)
+		return nil, providers.NewError(providers.ErrorParserBroken, s.Name(), fmt.Errorf("failed to parse sources JSON: %w", err))
 	}
 
 	if jsonResponse.Link == "" {
-		return nil, fmt.Errorf("no embed link found in response")
+		return nil, providers.NewError(providers.ErrorParserBroken, s.Name(), fmt.Errorf("no embed link found in response"))
 	}
 
 	embedURL := jsonResponse.Link
@@ -839,5 +865,5 @@ func (s *SFlix) GetMovieEpisodeID(ctx context.Context, mediaID string) (string,
 		}
 		return ep.ID, nil
 	}
-	return "", fmt.Errorf("no episodes found for movie %s", mediaID)
+	return "", providers.NewError(providers.ErrorNotFound, s.Name(), fmt.Errorf("no episodes found for movie %s", mediaID))
 }
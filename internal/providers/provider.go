@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -10,6 +11,7 @@ type Provider interface {
 	// Metadata
 	Name() string
 	Type() MediaType
+	Capabilities() Capabilities
 
 	// Search and discovery
 	Search(ctx context.Context, query string) ([]Media, error)
@@ -48,6 +50,15 @@ type MangaProvider interface {
 	GetMangaPages(ctx context.Context, chapterID string) ([]string, error)
 }
 
+// MovieProvider is implemented by providers that resolve the episode ID a
+// movie streams from straight from its media ID, without listing seasons.
+// Capabilities().Movies reports it. Use MovieEpisodeID to resolve a movie on
+// any provider.
+type MovieProvider interface {
+	Provider
+	GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
+}
+
 // Media represents a single media item
 type Media struct {
 	ID            string    `json:"id"`
@@ -85,11 +96,15 @@ type Episode struct {
 	ID           string        `json:"id"`
 	Number       int           `json:"number"`
 	Season       int           `json:"season"`
+	Volume       int           `json:"volume,omitempty"`     // Manga volume, 0 when unknown
+	ScanGroup    string        `json:"scan_group,omitempty"` // Manga scanlation group
+	Arc          string        `json:"arc,omitempty"`        // Story arc, empty when unknown
 	Title        string        `json:"title"`
 	Synopsis     string        `json:"synopsis"`
 	ThumbnailURL string        `json:"thumbnail_url"`
 	Duration     time.Duration `json:"duration"`
 	ReleaseDate  time.Time     `json:"release_date"`
+	PageURL      string        `json:"page_url,omitempty"` // Episode page on the provider's site, for the yt-dlp fallback
 }
 
 // StreamURL contains streaming information
@@ -103,6 +118,78 @@ type StreamURL struct {
 	Referer     string            `json:"referer,omitempty"`
 }
 
+// StreamSource is one server or mirror offering an episode
+type StreamSource struct {
+	Server string     `json:"server"`          // Server or mirror name
+	Audio  string     `json:"audio,omitempty"` // "sub", "dub" or empty when unknown
+	Stream *StreamURL `json:"stream"`
+}
+
+// SourceLister is implemented by providers that offer several servers or
+// mirrors per episode
+type SourceLister interface {
+	GetStreamSources(ctx context.Context, episodeID string) ([]StreamSource, error)
+}
+
+// EpisodePager is implemented by providers that can link any episode ID to
+// its page on their website without listing episodes first. The page is
+// handed to yt-dlp when the provider can't resolve a stream itself.
+type EpisodePager interface {
+	EpisodePageURL(episodeID string) string
+}
+
+// EpisodeDetailer is implemented by providers that can look up the
+// synopsis, air date, runtime or thumbnail of a single episode, which their
+// episode lists leave out. Fields the provider doesn't know are left empty.
+type EpisodeDetailer interface {
+	GetEpisodeDetail(ctx context.Context, mediaID, episodeID string) (*Episode, error)
+}
+
+// AudioPreferenceSetter is implemented by providers that serve several audio
+// versions of an episode (sub and dub servers, translations). The preference
+// is "dub", "sub" or a language code and applies to later stream lookups.
+type AudioPreferenceSetter interface {
+	SetAudioPreference(preference string)
+}
+
+// Wrapper is implemented by providers that wrap another provider to change
+// part of its behaviour, like the yt-dlp fallback
+type Wrapper interface {
+	Unwrap() Provider
+}
+
+// As returns p as the optional interface T, from p itself or else from the
+// providers it wraps, so wrappers don't hide what the wrapped provider
+// supports
+func As[T any](p Provider) (T, bool) {
+	for p != nil {
+		if t, ok := p.(T); ok {
+			return t, true
+		}
+		wrapper, ok := p.(Wrapper)
+		if !ok {
+			break
+		}
+		p = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// AnimeAudioType maps an audio preference to the sub/dub version of an anime
+// release: Japanese audio is the original ("sub"), English the dub. Returns
+// an empty string for other languages.
+func AnimeAudioType(preference string) string {
+	switch strings.ToLower(preference) {
+	case "sub", "ja", "jp", "jpn", "japanese":
+		return "sub"
+	case "dub", "en", "eng", "english":
+		return "dub"
+	default:
+		return ""
+	}
+}
+
 // Subtitle represents a subtitle track
 type Subtitle struct {
 	Language string `json:"language"`
@@ -135,10 +222,12 @@ const (
 type StreamType string
 
 const (
-	StreamTypeHLS  StreamType = "hls"  // HTTP Live Streaming (.m3u8)
-	StreamTypeDASH StreamType = "dash" // MPEG-DASH (.mpd)
-	StreamTypeMP4  StreamType = "mp4"  // Direct MP4
-	StreamTypeMKV  StreamType = "mkv"  // Direct MKV
+	StreamTypeHLS   StreamType = "hls"   // HTTP Live Streaming (.m3u8)
+	StreamTypeDASH  StreamType = "dash"  // MPEG-DASH (.mpd)
+	StreamTypeMP4   StreamType = "mp4"   // Direct MP4
+	StreamTypeMKV   StreamType = "mkv"   // Direct MKV
+	StreamTypeXDCC  StreamType = "xdcc"  // IRC XDCC pack (xdcc://server/channel/bot/pack)
+	StreamTypeYTDLP StreamType = "ytdlp" // Web page resolved by yt-dlp (or mpv's ytdl hook) at play/download time
 )
 
 // HealthCheckResult holds detailed health check information
@@ -148,6 +237,7 @@ type HealthCheckResult struct {
 	StatusCode  int
 	Duration    time.Duration
 	Error       string
+	Kind        ErrorKind // Classification of Error
 	CheckedAt   time.Time
 }
 
@@ -158,6 +248,9 @@ type ProviderStatus struct {
 	Status       string // e.g., "Online", "Offline", "Error: ...", "Checking..."
 	LastCheck    time.Time
 	LastResult   *HealthCheckResult
+	// LikelyGeoBlocked is set when the provider fails consistently from
+	// the user's region
+	LikelyGeoBlocked bool
 }
 
 // ParseQuality parses a quality string into a Quality type
@@ -135,7 +135,8 @@ func (r *Registry) Get(name string) (Provider, error) {
 	return provider, nil
 }
 
-// GetByType returns all providers that support the given media type
+// GetByType returns all providers that support the given media type.
+// Providers likely geo-blocked come last.
 func (r *Registry) GetByType(mediaType MediaType) []Provider {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -144,9 +145,55 @@ func (r *Registry) GetByType(mediaType MediaType) []Provider {
 	// Return a copy to prevent external modification
 	result := make([]Provider, len(providers))
 	copy(result, providers)
+	sort.SliceStable(result, func(i, j int) bool {
+		return !r.likelyGeoBlocked(result[i].Name()) && r.likelyGeoBlocked(result[j].Name())
+	})
 	return result
 }
 
+// Preferred returns the named provider, or the first provider of mediaType
+// not likely geo-blocked when the named one is missing or likely blocked
+func (r *Registry) Preferred(name string, mediaType MediaType) (Provider, error) {
+	provider, err := r.Get(name)
+	if err == nil && !r.LikelyGeoBlocked(name) {
+		return provider, nil
+	}
+
+	candidates := r.GetByType(mediaType)
+	if len(candidates) > 0 && (provider == nil || !r.LikelyGeoBlocked(candidates[0].Name())) {
+		return candidates[0], nil
+	}
+	if provider != nil {
+		return provider, nil
+	}
+	return nil, fmt.Errorf("no provider available for %s", mediaType)
+}
+
+// SetLikelyGeoBlocked flags a provider as failing consistently from the
+// user's region
+func (r *Registry) SetLikelyGeoBlocked(name string, likely bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if status, ok := r.statuses[name]; ok {
+		status.LikelyGeoBlocked = likely
+	}
+}
+
+// LikelyGeoBlocked reports whether a provider is flagged as failing
+// consistently from the user's region
+func (r *Registry) LikelyGeoBlocked(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.likelyGeoBlocked(name)
+}
+
+func (r *Registry) likelyGeoBlocked(name string) bool {
+	status, ok := r.statuses[name]
+	return ok && status.LikelyGeoBlocked
+}
+
 // GetAll returns all registered providers
 func (r *Registry) GetAll() []Provider {
 	r.mu.RLock()
@@ -223,7 +270,8 @@ func (r *Registry) CheckAllProviders(ctx context.Context) {
 				"sflix":    "https://sflix.to",
 				"flixhq":   "https://flixhq.to",
 				"hdrezka":  "https://hdrezka.me",
-				"comix":    "https://comick.io",
+				"comix":    "https://comix.to",
+				"comick":   "https://api.comick.fun",
 			}
 
 			healthURL := urlMap[provider.Name()]
@@ -259,6 +307,7 @@ func (r *Registry) CheckAllProviders(ctx context.Context) {
 				r.statuses[provider.Name()].Healthy = false
 				r.statuses[provider.Name()].Status = fmt.Sprintf("Offline: %v", err)
 				result.Error = err.Error()
+				result.Kind = ErrorKindOf(err)
 				result.StatusCode = 0
 			} else {
 				r.statuses[provider.Name()].Healthy = true
@@ -397,6 +446,24 @@ func GetByType(mediaType MediaType) []Provider {
 	return globalRegistry.GetByType(mediaType)
 }
 
+// Preferred returns the named provider from the global registry, or a
+// replacement of mediaType when it is missing or likely geo-blocked
+func Preferred(name string, mediaType MediaType) (Provider, error) {
+	return globalRegistry.Preferred(name, mediaType)
+}
+
+// SetLikelyGeoBlocked flags a provider of the global registry as failing
+// consistently from the user's region
+func SetLikelyGeoBlocked(name string, likely bool) {
+	globalRegistry.SetLikelyGeoBlocked(name, likely)
+}
+
+// LikelyGeoBlocked reports whether a provider of the global registry is
+// flagged as likely geo-blocked
+func LikelyGeoBlocked(name string) bool {
+	return globalRegistry.LikelyGeoBlocked(name)
+}
+
 // Configurable is an interface for providers that can be configured at runtime
 type Configurable interface {
 	SetConfig(cfg *config.Config, logger *slog.Logger)
@@ -408,7 +475,7 @@ func ConfigureAll(cfg *config.Config, logger *slog.Logger) {
 	defer globalRegistry.mu.RUnlock()
 
 	for _, provider := range globalRegistry.providers {
-		if configurable, ok := provider.(Configurable); ok {
+		if configurable, ok := As[Configurable](provider); ok {
 			configurable.SetConfig(cfg, logger)
 		}
 	}
@@ -16,6 +16,7 @@ type mockProvider struct {
 
 func (m *mockProvider) Name() string                                              { return m.name }
 func (m *mockProvider) Type() MediaType                                           { return m.mediaType }
+func (m *mockProvider) Capabilities() Capabilities                                { return Capabilities{Streams: true} }
 func (m *mockProvider) Search(ctx context.Context, query string) ([]Media, error) { return nil, nil }
 func (m *mockProvider) GetTrending(ctx context.Context) ([]Media, error)          { return nil, nil }
 func (m *mockProvider) GetRecent(ctx context.Context) ([]Media, error)            { return nil, nil }
@@ -218,6 +219,46 @@ func TestRegistry_GetByType(t *testing.T) {
 	})
 }
 
+func TestRegistry_LikelyGeoBlocked(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.Register(&mockProvider{name: "anime1", mediaType: MediaTypeAnime})
+	_ = registry.Register(&mockProvider{name: "anime2", mediaType: MediaTypeAnime})
+
+	registry.SetLikelyGeoBlocked("anime1", true)
+	assert.True(t, registry.LikelyGeoBlocked("anime1"))
+
+	t.Run("sorts likely blocked providers last", func(t *testing.T) {
+		providers := registry.GetByType(MediaTypeAnime)
+		require.Len(t, providers, 2)
+		assert.Equal(t, "anime2", providers[0].Name())
+		assert.Equal(t, "anime1", providers[1].Name())
+	})
+
+	t.Run("prefers a provider that isn't blocked", func(t *testing.T) {
+		provider, err := registry.Preferred("anime1", MediaTypeAnime)
+		require.NoError(t, err)
+		assert.Equal(t, "anime2", provider.Name())
+
+		provider, err = registry.Preferred("missing", MediaTypeAnime)
+		require.NoError(t, err)
+		assert.Equal(t, "anime2", provider.Name())
+	})
+
+	t.Run("keeps the named provider when all are blocked", func(t *testing.T) {
+		registry.SetLikelyGeoBlocked("anime2", true)
+		defer registry.SetLikelyGeoBlocked("anime2", false)
+
+		provider, err := registry.Preferred("anime1", MediaTypeAnime)
+		require.NoError(t, err)
+		assert.Equal(t, "anime1", provider.Name())
+	})
+
+	t.Run("fails without providers of the type", func(t *testing.T) {
+		_, err := registry.Preferred("missing", MediaTypeManga)
+		assert.Error(t, err)
+	})
+}
+
 func TestRegistry_GetAll(t *testing.T) {
 	t.Run("gets all providers", func(t *testing.T) {
 		registry := NewRegistry()
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/justchokingaround/greg/internal/netbind"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/pkg/types"
 )
@@ -23,7 +24,7 @@ func New(name, baseURL string) *Client {
 	return &Client{
 		NameStr: name,
 		BaseURL: strings.TrimRight(baseURL, "/"),
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: netbind.Transport(name)},
 	}
 }
 
@@ -200,6 +201,11 @@ func (c *Client) Type() providers.MediaType {
 	return providers.MediaTypeAnime
 }
 
+// Capabilities reports the qualities and subtitle tracks the API serves
+func (c *Client) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Streams: true, Qualities: true, Subtitles: true}
+}
+
 // Search (new interface) searches by query
 func (c *Client) Search(ctx context.Context, query string) ([]providers.Media, error) {
 	oldResults, err := c.searchOld(query)
@@ -481,11 +487,15 @@ func (c *Client) GetEpisodes(ctx context.Context, seasonID string) ([]providers.
 		var episodes []providers.Episode
 		for i, ch := range mangaInfo.Chapters {
 			epNum := i + 1
+			var volume int
+			_, _ = fmt.Sscanf(ch.Volume, "%d", &volume)
 			episodes = append(episodes, providers.Episode{
-				ID:     ch.ID,
-				Number: epNum,
-				Title:  ch.Title,
-				Season: 1,
+				ID:        ch.ID,
+				Number:    epNum,
+				Title:     ch.Title,
+				Season:    1,
+				Volume:    volume,
+				ScanGroup: ch.ScanGroup,
 			})
 		}
 		return episodes, nil
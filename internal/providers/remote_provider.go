@@ -36,6 +36,11 @@ func (p *RemoteProvider) Type() MediaType {
 	return p.pType
 }
 
+// Capabilities reports the qualities the API serves; manga providers don't stream
+func (p *RemoteProvider) Capabilities() Capabilities {
+	return Capabilities{Streams: p.pType != MediaTypeManga, Qualities: p.pType != MediaTypeManga}
+}
+
 func (p *RemoteProvider) Search(ctx context.Context, query string) ([]Media, error) {
 	// Determine API media type based on provider type
 	apiMediaType := "movies"
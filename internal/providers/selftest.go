@@ -0,0 +1,257 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// selfTestQueries are well-known titles each kind of provider should find
+var selfTestQueries = map[MediaType]string{
+	MediaTypeAnime:        "Cowboy Bebop",
+	MediaTypeManga:        "Chainsaw Man",
+	MediaTypeMovie:        "Inception",
+	MediaTypeTV:           "Breaking Bad",
+	MediaTypeMovieTV:      "Breaking Bad",
+	MediaTypeAnimeMovieTV: "Breaking Bad",
+	MediaTypeAll:          "Breaking Bad",
+}
+
+// SelfTestQuery returns the title searched by the self-test of a provider of
+// mediaType
+func SelfTestQuery(mediaType MediaType) string {
+	if query, ok := selfTestQueries[mediaType]; ok {
+		return query
+	}
+	return selfTestQueries[MediaTypeAnime]
+}
+
+// SelfTestResult is the outcome of a self-test step
+type SelfTestResult string
+
+const (
+	SelfTestPass SelfTestResult = "pass"
+	SelfTestFail SelfTestResult = "fail"
+	SelfTestSkip SelfTestResult = "skip" // An earlier step failed or the step doesn't apply
+)
+
+// SelfTestStep is one step of a provider self-test
+type SelfTestStep struct {
+	Name     string         `json:"name"`
+	Result   SelfTestResult `json:"result"`
+	Detail   string         `json:"detail,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Kind     string         `json:"kind,omitempty"` // Error classification, see ErrorKind
+	Duration time.Duration  `json:"duration"`
+}
+
+// SelfTestReport is the outcome of a provider self-test
+type SelfTestReport struct {
+	Provider string         `json:"provider"`
+	Query    string         `json:"query"`
+	Steps    []SelfTestStep `json:"steps"`
+}
+
+// Passed reports whether no step failed
+func (r *SelfTestReport) Passed() bool {
+	for _, step := range r.Steps {
+		if step.Result == SelfTestFail {
+			return false
+		}
+	}
+	return true
+}
+
+// SelfTestOptions tunes a provider self-test
+type SelfTestOptions struct {
+	Query  string       // Title to search, SelfTestQuery of the provider's type when empty
+	Client *http.Client // Client checking the stream URL, http.DefaultClient when nil
+}
+
+// selfTest runs the steps of a self-test, skipping the rest after a failure
+type selfTest struct {
+	report *SelfTestReport
+	failed bool
+}
+
+func (t *selfTest) run(name string, step func() (string, error)) {
+	if t.failed {
+		t.skip(name, "")
+		return
+	}
+
+	start := time.Now()
+	detail, err := step()
+	result := SelfTestStep{Name: name, Result: SelfTestPass, Detail: detail, Duration: time.Since(start)}
+	if err != nil {
+		t.failed = true
+		result.Result = SelfTestFail
+		result.Error = err.Error()
+		if kind := ErrorKindOf(err); kind != ErrorUnknown {
+			result.Kind = kind.String()
+		}
+	}
+	t.report.Steps = append(t.report.Steps, result)
+}
+
+func (t *selfTest) skip(name, detail string) {
+	t.report.Steps = append(t.report.Steps, SelfTestStep{Name: name, Result: SelfTestSkip, Detail: detail})
+}
+
+// SelfTest runs a canned scenario against p: search a known title, fetch
+// its seasons and episodes, resolve a stream (or manga pages) and check that
+// the stream URL answers. Site layout changes show up as failed steps.
+func SelfTest(ctx context.Context, p Provider, opts SelfTestOptions) *SelfTestReport {
+	query := opts.Query
+	if query == "" {
+		query = SelfTestQuery(p.Type())
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	t := &selfTest{report: &SelfTestReport{Provider: p.Name(), Query: query}}
+
+	var media Media
+	t.run("search", func() (string, error) {
+		results, err := p.Search(ctx, query)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "", fmt.Errorf("no results for %q", query)
+		}
+		media = results[0]
+		return fmt.Sprintf("%d results, first: %s (%s)", len(results), media.Title, media.ID), nil
+	})
+
+	var seasons []Season
+	t.run("seasons", func() (string, error) {
+		var err error
+		seasons, err = p.GetSeasons(ctx, media.ID)
+		if err != nil {
+			return "", err
+		}
+		if len(seasons) == 0 && media.Type != MediaTypeMovie {
+			return "", fmt.Errorf("no seasons for %s", media.ID)
+		}
+		return fmt.Sprintf("%d seasons", len(seasons)), nil
+	})
+
+	var episode Episode
+	t.run("episodes", func() (string, error) {
+		if len(seasons) == 0 {
+			id, err := MovieEpisodeID(ctx, p, media.ID)
+			if err != nil {
+				return "", err
+			}
+			episode = Episode{ID: id, Number: 1, Title: media.Title}
+			return fmt.Sprintf("movie episode %s", id), nil
+		}
+
+		episodes, err := p.GetEpisodes(ctx, seasons[0].ID)
+		if err != nil {
+			return "", err
+		}
+		if len(episodes) == 0 {
+			return "", fmt.Errorf("no episodes in season %s", seasons[0].ID)
+		}
+		episode = episodes[0]
+		return fmt.Sprintf("%d episodes, first: %d (%s)", len(episodes), episode.Number, episode.ID), nil
+	})
+
+	if manga, ok := p.(MangaProvider); ok {
+		var pages []string
+		t.run("pages", func() (string, error) {
+			var err error
+			pages, err = manga.GetMangaPages(ctx, episode.ID)
+			if err != nil {
+				return "", err
+			}
+			if len(pages) == 0 {
+				return "", fmt.Errorf("no pages in chapter %s", episode.ID)
+			}
+			return fmt.Sprintf("%d pages", len(pages)), nil
+		})
+		t.run("liveness", func() (string, error) {
+			status, _, err := checkURL(ctx, client, pages[0], nil)
+			if err != nil {
+				return "", err
+			}
+			return urlCheckDetail(status, false), nil
+		})
+		return t.report
+	}
+
+	var stream *StreamURL
+	t.run("stream", func() (string, error) {
+		var err error
+		stream, err = p.GetStreamURL(ctx, episode.ID, QualityAuto)
+		if err != nil {
+			return "", err
+		}
+		if stream == nil || stream.URL == "" {
+			return "", fmt.Errorf("empty stream URL for %s", episode.ID)
+		}
+		return fmt.Sprintf("%s %s", stream.Type, stream.URL), nil
+	})
+
+	if stream != nil && (stream.Type == StreamTypeXDCC || stream.Type == StreamTypeYTDLP) {
+		t.skip("liveness", fmt.Sprintf("%s streams are resolved at play time", stream.Type))
+		return t.report
+	}
+	t.run("liveness", func() (string, error) {
+		headers := make(map[string]string, len(stream.Headers)+1)
+		for k, v := range stream.Headers {
+			headers[k] = v
+		}
+		if stream.Referer != "" {
+			headers["Referer"] = stream.Referer
+		}
+		status, playlist, err := checkURL(ctx, client, stream.URL, headers)
+		if err != nil {
+			return "", err
+		}
+		if stream.Type == StreamTypeHLS && !playlist {
+			return "", NewError(ErrorParserBroken, p.Name(), fmt.Errorf("HLS stream is not an m3u8 playlist"))
+		}
+		return urlCheckDetail(status, playlist), nil
+	})
+	return t.report
+}
+
+// checkURL fetches the start of url and reports its status and whether it
+// is an m3u8 playlist
+func checkURL(ctx context.Context, client *http.Client, url string, headers map[string]string) (int, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Range", "bytes=0-1023")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, false, fmt.Errorf("HTTP %d from %s", resp.StatusCode, req.URL.Host)
+	}
+	head, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode, strings.HasPrefix(strings.TrimSpace(string(head)), "#EXTM3U"), nil
+}
+
+func urlCheckDetail(status int, playlist bool) string {
+	if playlist {
+		return fmt.Sprintf("HTTP %d, m3u8 playlist", status)
+	}
+	return fmt.Sprintf("HTTP %d", status)
+}
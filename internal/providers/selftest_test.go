@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scenarioProvider serves one show with one episode streamed from streamURL
+type scenarioProvider struct {
+	mockProvider
+	streamURL string
+	searchErr error
+}
+
+func (p *scenarioProvider) Search(ctx context.Context, query string) ([]Media, error) {
+	if p.searchErr != nil {
+		return nil, p.searchErr
+	}
+	return []Media{{ID: "show", Title: query, Type: MediaTypeAnime}}, nil
+}
+
+func (p *scenarioProvider) GetSeasons(ctx context.Context, mediaID string) ([]Season, error) {
+	return []Season{{ID: mediaID, Number: 1}}, nil
+}
+
+func (p *scenarioProvider) GetEpisodes(ctx context.Context, seasonID string) ([]Episode, error) {
+	return []Episode{{ID: seasonID + "-1", Number: 1}}, nil
+}
+
+func (p *scenarioProvider) GetStreamURL(ctx context.Context, episodeID string, quality Quality) (*StreamURL, error) {
+	return &StreamURL{URL: p.streamURL, Type: StreamTypeHLS, Referer: "https://site.example/"}, nil
+}
+
+func stepResults(report *SelfTestReport) map[string]SelfTestResult {
+	results := make(map[string]SelfTestResult)
+	for _, step := range report.Steps {
+		results[step.Name] = step.Result
+	}
+	return results
+}
+
+func TestSelfTest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/master.m3u8":
+			assert.Equal(t, "https://site.example/", r.Header.Get("Referer"))
+			_, _ = w.Write([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1\nindex.m3u8\n"))
+		case "/page.html":
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("passes a working provider", func(t *testing.T) {
+		p := &scenarioProvider{mockProvider: mockProvider{name: "site", mediaType: MediaTypeAnime}, streamURL: server.URL + "/master.m3u8"}
+		report := SelfTest(context.Background(), p, SelfTestOptions{})
+
+		assert.True(t, report.Passed())
+		assert.Equal(t, SelfTestQuery(MediaTypeAnime), report.Query)
+		assert.Equal(t, map[string]SelfTestResult{
+			"search": SelfTestPass, "seasons": SelfTestPass, "episodes": SelfTestPass,
+			"stream": SelfTestPass, "liveness": SelfTestPass,
+		}, stepResults(report))
+	})
+
+	t.Run("fails a dead stream", func(t *testing.T) {
+		p := &scenarioProvider{mockProvider: mockProvider{name: "site"}, streamURL: server.URL + "/gone.m3u8"}
+		report := SelfTest(context.Background(), p, SelfTestOptions{})
+
+		assert.False(t, report.Passed())
+		assert.Equal(t, SelfTestFail, stepResults(report)["liveness"])
+	})
+
+	t.Run("fails an HLS stream that isn't a playlist", func(t *testing.T) {
+		p := &scenarioProvider{mockProvider: mockProvider{name: "site"}, streamURL: server.URL + "/page.html"}
+		report := SelfTest(context.Background(), p, SelfTestOptions{})
+
+		require.Len(t, report.Steps, 5)
+		assert.Equal(t, SelfTestFail, report.Steps[4].Result)
+		assert.Equal(t, ErrorParserBroken.String(), report.Steps[4].Kind)
+	})
+
+	t.Run("skips the steps after a failure", func(t *testing.T) {
+		p := &scenarioProvider{mockProvider: mockProvider{name: "site"}, searchErr: NewError(ErrorGeoBlocked, "site", fmt.Errorf("HTTP 451"))}
+		report := SelfTest(context.Background(), p, SelfTestOptions{Query: "Trigun"})
+
+		assert.Equal(t, "Trigun", report.Query)
+		assert.Equal(t, "geo-blocked", report.Steps[0].Kind)
+		assert.Equal(t, map[string]SelfTestResult{
+			"search": SelfTestFail, "seasons": SelfTestSkip, "episodes": SelfTestSkip,
+			"stream": SelfTestSkip, "liveness": SelfTestSkip,
+		}, stepResults(report))
+	})
+}
@@ -0,0 +1,168 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ListStreamSources returns every server/mirror a provider offers for an
+// episode. Providers without SourceLister get one source per available quality.
+func ListStreamSources(ctx context.Context, p Provider, episodeID string) ([]StreamSource, error) {
+	if lister, ok := As[SourceLister](p); ok {
+		return lister.GetStreamSources(ctx, episodeID)
+	}
+
+	qualities, err := p.GetAvailableQualities(ctx, episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get qualities: %w", err)
+	}
+
+	var sources []StreamSource
+	seen := make(map[string]bool)
+	for _, q := range qualities {
+		stream, err := p.GetStreamURL(ctx, episodeID, q)
+		if err != nil || seen[stream.URL] {
+			continue
+		}
+		seen[stream.URL] = true
+		sources = append(sources, StreamSource{Server: p.Name(), Stream: stream})
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no sources found")
+	}
+	return sources, nil
+}
+
+// FindEpisodeStream looks up an episode on another provider by the show's
+// title and returns its stream, to switch providers mid-playback. Only a
+// search result with the same title is used, so a wrong show isn't played.
+func FindEpisodeStream(ctx context.Context, p Provider, title string, season, episode int) (*StreamURL, error) {
+	if episode <= 0 {
+		return nil, fmt.Errorf("only episodes can be looked up on another provider")
+	}
+
+	media, err := FindMedia(ctx, p, title)
+	if err != nil {
+		return nil, err
+	}
+	return EpisodeStream(ctx, p, media.ID, season, episode, Quality1080p)
+}
+
+// FindMedia searches p for the show or movie with the same title
+func FindMedia(ctx context.Context, p Provider, title string) (*Media, error) {
+	results, err := p.Search(ctx, title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", p.Name(), err)
+	}
+	for i := range results {
+		if SameTitle(results[i].Title, title) {
+			return &results[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%q not found on %s", title, p.Name())
+}
+
+// EpisodeStream returns the stream of an episode of mediaID on p, from the
+// numbered season when it has one, else its first season
+func EpisodeStream(ctx context.Context, p Provider, mediaID string, season, episode int, quality Quality) (*StreamURL, error) {
+	episodeID, err := EpisodeID(ctx, p, mediaID, season, episode)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetStreamURL(ctx, episodeID, quality)
+}
+
+// EpisodeID returns the ID p gives an episode of mediaID, from the numbered
+// season when it has one, else its first season
+func EpisodeID(ctx context.Context, p Provider, mediaID string, season, episode int) (string, error) {
+	details, err := p.GetMediaDetails(ctx, mediaID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get details from %s: %w", p.Name(), err)
+	}
+	if details == nil || len(details.Seasons) == 0 {
+		return "", fmt.Errorf("no episodes found on %s", p.Name())
+	}
+	seasonID := details.Seasons[0].ID
+	for _, s := range details.Seasons {
+		if season > 0 && s.Number == season {
+			seasonID = s.ID
+			break
+		}
+	}
+
+	episodes, err := p.GetEpisodes(ctx, seasonID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get episodes from %s: %w", p.Name(), err)
+	}
+	for _, ep := range episodes {
+		if ep.Number == episode {
+			return ep.ID, nil
+		}
+	}
+	return "", fmt.Errorf("episode %d not found on %s", episode, p.Name())
+}
+
+// MovieEpisodeID returns the episode ID a movie streams from: from a
+// MovieProvider directly, else (or when that fails) the first episode of
+// its first season
+func MovieEpisodeID(ctx context.Context, p Provider, mediaID string) (string, error) {
+	movies, ok := As[MovieProvider](p)
+	if !ok {
+		return FirstEpisodeID(ctx, p, mediaID)
+	}
+	id, err := movies.GetMovieEpisodeID(ctx, mediaID)
+	if err == nil {
+		return id, nil
+	}
+	if fallback, fErr := FirstEpisodeID(ctx, p, mediaID); fErr == nil {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("failed to get movie episode ID: %w", err)
+}
+
+// FirstEpisodeID returns the ID of the first episode of mediaID's first
+// season, from its seasons or else its media details
+func FirstEpisodeID(ctx context.Context, p Provider, mediaID string) (string, error) {
+	seasons, err := p.GetSeasons(ctx, mediaID)
+	if err != nil || len(seasons) == 0 {
+		details, dErr := p.GetMediaDetails(ctx, mediaID)
+		if dErr != nil {
+			if err == nil {
+				err = dErr
+			}
+			return "", fmt.Errorf("failed to get seasons from %s: %w", p.Name(), err)
+		}
+		if details != nil {
+			seasons = details.Seasons
+		}
+	}
+	if len(seasons) == 0 {
+		return "", fmt.Errorf("no episodes found on %s", p.Name())
+	}
+
+	episodes, err := p.GetEpisodes(ctx, seasons[0].ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get episodes from %s: %w", p.Name(), err)
+	}
+	if len(episodes) == 0 {
+		return "", fmt.Errorf("no episodes found on %s", p.Name())
+	}
+	return episodes[0].ID, nil
+}
+
+// SameTitle compares titles ignoring case, spacing and punctuation
+func SameTitle(a, b string) bool {
+	key := func(s string) string {
+		var sb strings.Builder
+		for _, r := range strings.ToLower(s) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String()
+	}
+	return key(a) != "" && key(a) == key(b)
+}
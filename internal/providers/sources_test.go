@@ -0,0 +1,156 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// qualityProvider offers one stream per quality
+type qualityProvider struct {
+	mockProvider
+	qualities []Quality
+}
+
+func (p *qualityProvider) GetAvailableQualities(ctx context.Context, episodeID string) ([]Quality, error) {
+	return p.qualities, nil
+}
+
+func (p *qualityProvider) GetStreamURL(ctx context.Context, episodeID string, quality Quality) (*StreamURL, error) {
+	return &StreamURL{URL: "https://example.com/" + string(quality) + ".m3u8", Quality: quality, Type: StreamTypeHLS}, nil
+}
+
+// listingProvider implements SourceLister
+type listingProvider struct {
+	mockProvider
+}
+
+func (p *listingProvider) GetStreamSources(ctx context.Context, episodeID string) ([]StreamSource, error) {
+	return []StreamSource{
+		{Server: "HD-1", Audio: "sub", Stream: &StreamURL{URL: "https://a.example/1.m3u8"}},
+		{Server: "HD-1", Audio: "dub", Stream: &StreamURL{URL: "https://a.example/2.m3u8"}},
+	}, nil
+}
+
+func TestListStreamSources(t *testing.T) {
+	t.Run("uses the provider's source list", func(t *testing.T) {
+		sources, err := ListStreamSources(context.Background(), &listingProvider{mockProvider{name: "lister"}}, "ep")
+		require.NoError(t, err)
+		require.Len(t, sources, 2)
+		assert.Equal(t, "dub", sources[1].Audio)
+	})
+
+	t.Run("falls back to one source per quality", func(t *testing.T) {
+		p := &qualityProvider{mockProvider: mockProvider{name: "plain"}, qualities: []Quality{Quality1080p, Quality720p, Quality1080p}}
+		sources, err := ListStreamSources(context.Background(), p, "ep")
+		require.NoError(t, err)
+		require.Len(t, sources, 2)
+		assert.Equal(t, "plain", sources[0].Server)
+		assert.Equal(t, Quality720p, sources[1].Stream.Quality)
+	})
+
+	t.Run("fails without sources", func(t *testing.T) {
+		_, err := ListStreamSources(context.Background(), &qualityProvider{mockProvider: mockProvider{name: "empty"}}, "ep")
+		assert.Error(t, err)
+	})
+}
+
+// catalogProvider serves one show with two seasons
+type catalogProvider struct {
+	mockProvider
+}
+
+func (p *catalogProvider) Search(ctx context.Context, query string) ([]Media, error) {
+	return []Media{{ID: "other", Title: "Frieren Movie"}, {ID: "frieren", Title: "Frieren: Beyond Journey's End"}}, nil
+}
+
+func (p *catalogProvider) GetMediaDetails(ctx context.Context, id string) (*MediaDetails, error) {
+	return &MediaDetails{Seasons: []Season{{ID: id + "-s1", Number: 1}, {ID: id + "-s2", Number: 2}}}, nil
+}
+
+func (p *catalogProvider) GetEpisodes(ctx context.Context, seasonID string) ([]Episode, error) {
+	return []Episode{{ID: seasonID + "-e1", Number: 1}, {ID: seasonID + "-e2", Number: 2}}, nil
+}
+
+func (p *catalogProvider) GetStreamURL(ctx context.Context, episodeID string, quality Quality) (*StreamURL, error) {
+	return &StreamURL{URL: "https://b.example/" + episodeID + ".m3u8"}, nil
+}
+
+func TestFindEpisodeStream(t *testing.T) {
+	p := &catalogProvider{mockProvider{name: "catalog"}}
+
+	stream, err := FindEpisodeStream(context.Background(), p, "Frieren - Beyond Journey's End", 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example/frieren-s2-e2.m3u8", stream.URL)
+
+	stream, err = FindEpisodeStream(context.Background(), p, "frieren: beyond journey's end", 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example/frieren-s1-e1.m3u8", stream.URL)
+
+	_, err = FindEpisodeStream(context.Background(), p, "Frieren", 1, 1)
+	assert.Error(t, err, "a different title must not match")
+
+	_, err = FindEpisodeStream(context.Background(), p, "Frieren: Beyond Journey's End", 1, 13)
+	assert.Error(t, err)
+
+	_, err = FindEpisodeStream(context.Background(), p, "Frieren: Beyond Journey's End", 0, 0)
+	assert.Error(t, err)
+}
+
+// movieCatalogProvider resolves movies directly, but not "broken" ones
+type movieCatalogProvider struct {
+	catalogProvider
+}
+
+func (p *movieCatalogProvider) GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error) {
+	if mediaID == "broken" {
+		return "", errors.New("no movie page")
+	}
+	return mediaID + "-movie", nil
+}
+
+func TestMovieEpisodeID(t *testing.T) {
+	ctx := context.Background()
+
+	id, err := MovieEpisodeID(ctx, &movieCatalogProvider{catalogProvider{mockProvider{name: "movies"}}}, "dune")
+	require.NoError(t, err)
+	assert.Equal(t, "dune-movie", id)
+
+	id, err = MovieEpisodeID(ctx, &movieCatalogProvider{catalogProvider{mockProvider{name: "movies"}}}, "broken")
+	require.NoError(t, err)
+	assert.Equal(t, "broken-s1-e1", id, "falls back to the first episode")
+
+	id, err = MovieEpisodeID(ctx, &catalogProvider{mockProvider{name: "catalog"}}, "dune")
+	require.NoError(t, err)
+	assert.Equal(t, "dune-s1-e1", id, "providers without movie lookup list the movie as an episode")
+
+	_, err = MovieEpisodeID(ctx, &mockProvider{name: "empty"}, "dune")
+	assert.Error(t, err)
+}
+
+// wrappingProvider wraps another provider without adding any interface
+type wrappingProvider struct {
+	Provider
+}
+
+func (p *wrappingProvider) Unwrap() Provider {
+	return p.Provider
+}
+
+func TestAs(t *testing.T) {
+	lister := &listingProvider{mockProvider{name: "lister"}}
+
+	found, ok := As[SourceLister](&wrappingProvider{&wrappingProvider{lister}})
+	require.True(t, ok)
+	assert.Same(t, lister, found)
+
+	_, ok = As[SourceLister](&wrappingProvider{&mockProvider{name: "plain"}})
+	assert.False(t, ok)
+
+	sources, err := ListStreamSources(context.Background(), &wrappingProvider{lister}, "ep")
+	require.NoError(t, err)
+	assert.Len(t, sources, 2)
+}
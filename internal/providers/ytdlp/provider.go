@@ -0,0 +1,135 @@
+package ytdlp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// ServerName is the source name of streams extracted by yt-dlp
+const ServerName = "yt-dlp"
+
+// Provider wraps a provider so episodes whose streams it can't resolve are
+// extracted by yt-dlp from their page URL instead. Everything else is passed
+// through, and the optional provider interfaces it doesn't add a fallback to
+// are found on the wrapped provider with providers.As.
+type Provider struct {
+	providers.Provider
+	extractor *Extractor
+	logger    *slog.Logger
+
+	mu    sync.RWMutex
+	pages map[string]string // Episode ID -> page URL, learned from GetEpisodes
+}
+
+// sourceProvider adds the yt-dlp fallback to the sources of wrapped
+// providers that list several servers
+type sourceProvider struct {
+	*Provider
+}
+
+// Wrap adds the yt-dlp fallback to a provider. The result is a *Provider,
+// also a providers.SourceLister when p is one.
+func Wrap(p providers.Provider, extractor *Extractor, logger *slog.Logger) providers.Provider {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	wrapped := &Provider{
+		Provider:  p,
+		extractor: extractor,
+		logger:    logger,
+		pages:     make(map[string]string),
+	}
+	if _, ok := providers.As[providers.SourceLister](p); ok {
+		return &sourceProvider{Provider: wrapped}
+	}
+	return wrapped
+}
+
+// Unwrap returns the wrapped provider
+func (p *Provider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// GetEpisodes lists episodes and remembers their page URLs
+func (p *Provider) GetEpisodes(ctx context.Context, seasonID string) ([]providers.Episode, error) {
+	episodes, err := p.Provider.GetEpisodes(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	for _, ep := range episodes {
+		if ep.PageURL != "" {
+			p.pages[ep.ID] = ep.PageURL
+		}
+	}
+	p.mu.Unlock()
+	return episodes, nil
+}
+
+// GetStreamURL resolves a stream with the provider, then with yt-dlp
+func (p *Provider) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
+	stream, err := p.Provider.GetStreamURL(ctx, episodeID, quality)
+	if err == nil {
+		return stream, nil
+	}
+
+	page := p.pageURL(episodeID)
+	if page == "" {
+		return nil, err
+	}
+	p.logger.Info("provider failed, extracting with yt-dlp", "provider", p.Name(), "page", page, "error", err)
+
+	extracted, xErr := p.extractor.Extract(ctx, page, quality)
+	if xErr != nil {
+		return nil, fmt.Errorf("%w (yt-dlp fallback: %v)", err, xErr)
+	}
+	return extracted, nil
+}
+
+// GetAvailableQualities offers auto for episodes only yt-dlp can resolve
+func (p *Provider) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
+	qualities, err := p.Provider.GetAvailableQualities(ctx, episodeID)
+	if err != nil && p.pageURL(episodeID) != "" {
+		return []providers.Quality{providers.QualityAuto}, nil
+	}
+	return qualities, err
+}
+
+// GetStreamSources lists the provider's sources, or a single yt-dlp source
+// when it has none
+func (p *sourceProvider) GetStreamSources(ctx context.Context, episodeID string) ([]providers.StreamSource, error) {
+	sources, err := providers.ListStreamSources(ctx, p.Provider.Provider, episodeID)
+	if err == nil && len(sources) > 0 {
+		return sources, nil
+	}
+
+	page := p.pageURL(episodeID)
+	if page == "" {
+		return sources, err
+	}
+	stream, xErr := p.extractor.Extract(ctx, page, providers.QualityAuto)
+	if xErr != nil {
+		if err == nil {
+			err = fmt.Errorf("no sources found")
+		}
+		return nil, fmt.Errorf("%w (yt-dlp fallback: %v)", err, xErr)
+	}
+	return []providers.StreamSource{{Server: ServerName, Stream: stream}}, nil
+}
+
+// pageURL returns the page of an episode, if the provider listed one
+func (p *Provider) pageURL(episodeID string) string {
+	if pager, ok := providers.As[providers.EpisodePager](p.Provider); ok {
+		if page := pager.EpisodePageURL(episodeID); page != "" {
+			return page
+		}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pages[episodeID]
+}
@@ -0,0 +1,179 @@
+// Package ytdlp resolves episode pages with yt-dlp for providers greg can't
+// extract streams from itself
+package ytdlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// Extractor runs yt-dlp to resolve web pages into streams
+type Extractor struct {
+	Binary string   // yt-dlp executable
+	Args   []string // Extra arguments passed on every run
+}
+
+// New returns an extractor for the given yt-dlp binary ("yt-dlp" when empty)
+func New(binary string, args []string) *Extractor {
+	if binary == "" {
+		binary = "yt-dlp"
+	}
+	return &Extractor{Binary: binary, Args: args}
+}
+
+// Available reports whether the yt-dlp binary can be found
+func (e *Extractor) Available() bool {
+	_, err := exec.LookPath(e.Binary)
+	return err == nil
+}
+
+// info is the part of yt-dlp's --dump-single-json output greg uses
+type info struct {
+	URL         string                `json:"url"`
+	Protocol    string                `json:"protocol"`
+	Ext         string                `json:"ext"`
+	Height      int                   `json:"height"`
+	HTTPHeaders map[string]string     `json:"http_headers"`
+	Subtitles   map[string][]subtitle `json:"subtitles"`
+	Requested   []json.RawMessage     `json:"requested_formats"` // Set when video and audio are separate
+}
+
+type subtitle struct {
+	URL string `json:"url"`
+	Ext string `json:"ext"`
+}
+
+// Extract resolves pageURL into a stream. Single-file formats are returned as
+// direct HLS/MP4 streams so they go through greg's own player and download
+// paths; formats yt-dlp would have to merge are returned as a
+// providers.StreamTypeYTDLP stream of the page itself.
+func (e *Extractor) Extract(ctx context.Context, pageURL string, quality providers.Quality) (*providers.StreamURL, error) {
+	args := append([]string{
+		"--dump-single-json",
+		"--no-playlist",
+		"--no-warnings",
+		"--format", FormatSelector(quality),
+	}, e.Args...)
+	args = append(args, pageURL)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Binary, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("yt-dlp failed: %s", lastLine(msg))
+		}
+		return nil, fmt.Errorf("yt-dlp failed: %w", err)
+	}
+
+	var result info
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse yt-dlp output: %w", err)
+	}
+
+	stream := &providers.StreamURL{
+		Quality:   qualityFromHeight(result.Height, quality),
+		Headers:   result.HTTPHeaders,
+		Subtitles: subtitles(result.Subtitles),
+	}
+	if referer := result.HTTPHeaders["Referer"]; referer != "" {
+		stream.Referer = referer
+	}
+
+	switch {
+	case result.URL == "" || len(result.Requested) > 0:
+		// Separate video and audio: let yt-dlp (or mpv's ytdl hook) fetch it
+		stream.URL = pageURL
+		stream.Type = providers.StreamTypeYTDLP
+		stream.Headers = nil
+	case strings.HasPrefix(result.Protocol, "m3u8"):
+		stream.URL = result.URL
+		stream.Type = providers.StreamTypeHLS
+	case result.Ext == "mkv":
+		stream.URL = result.URL
+		stream.Type = providers.StreamTypeMKV
+	case strings.HasPrefix(result.Protocol, "http"):
+		stream.URL = result.URL
+		stream.Type = providers.StreamTypeMP4
+	default:
+		stream.URL = pageURL
+		stream.Type = providers.StreamTypeYTDLP
+		stream.Headers = nil
+	}
+	return stream, nil
+}
+
+// FormatSelector returns the yt-dlp format for a quality, preferring single
+// files so the result can be streamed directly
+func FormatSelector(quality providers.Quality) string {
+	height := qualityHeight(quality)
+	if height == 0 {
+		return "best/bestvideo+bestaudio"
+	}
+	return fmt.Sprintf("best[height<=%d]/bestvideo[height<=%d]+bestaudio/best", height, height)
+}
+
+// qualityHeight returns the height of a quality, or 0 for auto/unknown
+func qualityHeight(quality providers.Quality) int {
+	var height int
+	if _, err := fmt.Sscanf(string(quality), "%dp", &height); err != nil {
+		return 0
+	}
+	return height
+}
+
+// qualityFromHeight maps the extracted height back to a quality
+func qualityFromHeight(height int, requested providers.Quality) providers.Quality {
+	switch {
+	case height >= 2160:
+		return providers.Quality4K
+	case height >= 1440:
+		return providers.Quality1440p
+	case height >= 1080:
+		return providers.Quality1080p
+	case height >= 720:
+		return providers.Quality720p
+	case height >= 480:
+		return providers.Quality480p
+	case height > 0:
+		return providers.Quality360p
+	}
+	return requested
+}
+
+// subtitles converts yt-dlp subtitle tracks, taking the first format greg's
+// players understand for each language
+func subtitles(tracks map[string][]subtitle) []providers.Subtitle {
+	langs := make([]string, 0, len(tracks))
+	for lang := range tracks {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var subs []providers.Subtitle
+	for _, lang := range langs {
+		for _, f := range tracks[lang] {
+			if f.Ext == "vtt" || f.Ext == "srt" || f.Ext == "ass" {
+				subs = append(subs, providers.Subtitle{Language: lang, URL: f.URL, Format: f.Ext})
+				break
+			}
+		}
+	}
+	return subs
+}
+
+// lastLine returns the last line of yt-dlp's stderr, which holds the error
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
@@ -0,0 +1,172 @@
+package ytdlp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// brokenProvider lists episodes but can't resolve their streams
+type brokenProvider struct {
+	providers.Provider
+}
+
+func (p *brokenProvider) Name() string { return "broken" }
+
+func (p *brokenProvider) GetEpisodes(ctx context.Context, seasonID string) ([]providers.Episode, error) {
+	return []providers.Episode{{ID: "ep1", Number: 1, PageURL: "https://example.com/watch/ep1"}, {ID: "ep2", Number: 2}}, nil
+}
+
+func (p *brokenProvider) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
+	return nil, errors.New("extractor broke")
+}
+
+func (p *brokenProvider) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
+	return nil, errors.New("extractor broke")
+}
+
+// brokenMovieProvider streams movies through an episode ID and looks up
+// single episodes
+type brokenMovieProvider struct {
+	brokenProvider
+}
+
+func (p *brokenMovieProvider) GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error) {
+	return "movie-" + mediaID, nil
+}
+
+func (p *brokenMovieProvider) GetEpisodeDetail(ctx context.Context, mediaID, episodeID string) (*providers.Episode, error) {
+	return &providers.Episode{ID: episodeID}, nil
+}
+
+// brokenSourceProvider lists servers, but none work
+type brokenSourceProvider struct {
+	brokenProvider
+}
+
+func (p *brokenSourceProvider) GetStreamSources(ctx context.Context, episodeID string) ([]providers.StreamSource, error) {
+	return nil, errors.New("no servers")
+}
+
+// fakeYTDLP writes a script that prints output like yt-dlp --dump-single-json
+func fakeYTDLP(t *testing.T, output string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	path := filepath.Join(t.TempDir(), "yt-dlp")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestFormatSelector(t *testing.T) {
+	assert.Equal(t, "best/bestvideo+bestaudio", FormatSelector(providers.QualityAuto))
+	assert.Equal(t, "best[height<=720]/bestvideo[height<=720]+bestaudio/best", FormatSelector(providers.Quality720p))
+}
+
+func TestQualityFromHeight(t *testing.T) {
+	assert.Equal(t, providers.Quality1080p, qualityFromHeight(1080, providers.QualityAuto))
+	assert.Equal(t, providers.Quality720p, qualityFromHeight(800, providers.QualityAuto))
+	assert.Equal(t, providers.Quality480p, qualityFromHeight(0, providers.Quality480p))
+}
+
+func TestSubtitles(t *testing.T) {
+	subs := subtitles(map[string][]subtitle{
+		"es": {{URL: "https://example.com/es.json3", Ext: "json3"}, {URL: "https://example.com/es.vtt", Ext: "vtt"}},
+		"en": {{URL: "https://example.com/en.srt", Ext: "srt"}},
+		"de": {{URL: "https://example.com/de.json3", Ext: "json3"}},
+	})
+	require.Len(t, subs, 2)
+	assert.Equal(t, "en", subs[0].Language)
+	assert.Equal(t, "https://example.com/es.vtt", subs[1].URL)
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("direct stream", func(t *testing.T) {
+		bin := fakeYTDLP(t, `{"url":"https://cdn.example.com/master.m3u8","protocol":"m3u8_native","ext":"mp4","height":1080,"http_headers":{"Referer":"https://example.com/"}}`)
+		stream, err := New(bin, nil).Extract(context.Background(), "https://example.com/watch/ep1", providers.QualityAuto)
+		require.NoError(t, err)
+		assert.Equal(t, providers.StreamTypeHLS, stream.Type)
+		assert.Equal(t, "https://cdn.example.com/master.m3u8", stream.URL)
+		assert.Equal(t, providers.Quality1080p, stream.Quality)
+		assert.Equal(t, "https://example.com/", stream.Referer)
+	})
+
+	t.Run("separate video and audio", func(t *testing.T) {
+		bin := fakeYTDLP(t, `{"height":720,"requested_formats":[{},{}]}`)
+		stream, err := New(bin, nil).Extract(context.Background(), "https://example.com/watch/ep1", providers.QualityAuto)
+		require.NoError(t, err)
+		assert.Equal(t, providers.StreamTypeYTDLP, stream.Type)
+		assert.Equal(t, "https://example.com/watch/ep1", stream.URL)
+	})
+}
+
+func TestProviderFallback(t *testing.T) {
+	bin := fakeYTDLP(t, `{"url":"https://cdn.example.com/video.mp4","protocol":"https","ext":"mp4","height":720}`)
+	p, ok := Wrap(&brokenProvider{}, New(bin, nil), nil).(*Provider)
+	require.True(t, ok)
+	ctx := context.Background()
+
+	_, err := p.GetEpisodes(ctx, "season")
+	require.NoError(t, err)
+
+	stream, err := p.GetStreamURL(ctx, "ep1", providers.QualityAuto)
+	require.NoError(t, err)
+	assert.Equal(t, providers.StreamTypeMP4, stream.Type)
+	assert.Equal(t, "https://cdn.example.com/video.mp4", stream.URL)
+
+	qualities, err := p.GetAvailableQualities(ctx, "ep1")
+	require.NoError(t, err)
+	assert.Equal(t, []providers.Quality{providers.QualityAuto}, qualities)
+
+	sources, err := providers.ListStreamSources(ctx, p, "ep1")
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.Equal(t, "https://cdn.example.com/video.mp4", sources[0].Stream.URL)
+
+	// Episodes without a page keep the provider's error
+	_, err = p.GetStreamURL(ctx, "ep2", providers.QualityAuto)
+	assert.EqualError(t, err, "extractor broke")
+}
+
+func TestWrapKeepsOptionalInterfaces(t *testing.T) {
+	wrapped := Wrap(&brokenMovieProvider{}, New("", nil), nil)
+	getter, ok := providers.As[providers.MovieProvider](wrapped)
+	require.True(t, ok)
+	id, err := getter.GetMovieEpisodeID(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, "movie-42", id)
+	_, ok = providers.As[providers.EpisodeDetailer](wrapped)
+	assert.True(t, ok)
+
+	// Only providers that list servers get the yt-dlp source
+	_, ok = wrapped.(providers.SourceLister)
+	assert.False(t, ok)
+	_, ok = Wrap(&brokenSourceProvider{}, New("", nil), nil).(providers.SourceLister)
+	assert.True(t, ok)
+
+	_, ok = providers.As[providers.MovieProvider](Wrap(&brokenProvider{}, New("", nil), nil))
+	assert.False(t, ok)
+}
+
+func TestSourceFallback(t *testing.T) {
+	bin := fakeYTDLP(t, `{"url":"https://cdn.example.com/video.mp4","protocol":"https","ext":"mp4","height":720}`)
+	wrapped := Wrap(&brokenSourceProvider{}, New(bin, nil), nil)
+	ctx := context.Background()
+
+	_, err := wrapped.GetEpisodes(ctx, "season")
+	require.NoError(t, err)
+
+	sources, err := providers.ListStreamSources(ctx, wrapped, "ep1")
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.Equal(t, ServerName, sources[0].Server)
+}
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/justchokingaround/greg/internal/config"
@@ -9,15 +10,19 @@ import (
 	"github.com/justchokingaround/greg/internal/providers/anime/allanime"
 	"github.com/justchokingaround/greg/internal/providers/anime/hdrezka"
 	"github.com/justchokingaround/greg/internal/providers/anime/hianime"
+	"github.com/justchokingaround/greg/internal/providers/generic"
+	"github.com/justchokingaround/greg/internal/providers/manga/comick"
 	"github.com/justchokingaround/greg/internal/providers/manga/comix"
 	"github.com/justchokingaround/greg/internal/providers/movies/flixhq"
 	hdrezkamovie "github.com/justchokingaround/greg/internal/providers/movies/hdrezka"
 	"github.com/justchokingaround/greg/internal/providers/movies/sflix"
 	"github.com/justchokingaround/greg/internal/providers/remote"
+	"github.com/justchokingaround/greg/internal/providers/ytdlp"
 )
 
 type Registry struct {
 	providers map[string]providers.Provider
+	scrapers  []string // Names of the providers loaded from descriptors
 }
 
 func New() *Registry {
@@ -61,6 +66,44 @@ func (r *Registry) Load(cfg *config.Config) {
 	register("hdrezka", cfg.Providers.HDRezka, func() providers.Provider { return hdrezkamovie.New() }, "movies")
 	register("hdrezka_anime", cfg.Providers.HDRezka, func() providers.Provider { return hdrezka.New() }, "anime") // Special case for anime wrapper
 	register("comix", cfg.Providers.Comix, func() providers.Provider { return comix.New() }, "manga")
+	register("comick", cfg.Providers.Comick, func() providers.Provider { return comick.New() }, "manga")
+
+	// Providers described in YAML. The scrapers of a previous load go first,
+	// their descriptors may be gone.
+	for _, name := range r.scrapers {
+		delete(r.providers, name)
+	}
+	r.scrapers = nil
+	if cfg.Providers.Scrapers.Enabled {
+		r.loadScrapers(cfg.Providers.Scrapers.ScrapersDir())
+	}
+
+	// Let yt-dlp resolve episodes video providers can't
+	if cfg.Providers.YTDLP.Enabled {
+		extractor := ytdlp.New(cfg.Providers.YTDLP.Binary, cfg.Providers.YTDLP.Args)
+		for name, p := range r.providers {
+			if _, isManga := p.(providers.MangaProvider); !isManga {
+				r.providers[name] = ytdlp.Wrap(p, extractor, nil)
+			}
+		}
+	}
+}
+
+// loadScrapers registers the generic providers described in dir, keeping
+// built-in providers of the same name
+func (r *Registry) loadScrapers(dir string) {
+	scrapers, err := generic.LoadDir(dir)
+	if err != nil {
+		slog.Warn("failed to load scrapers", "dir", dir, "error", err)
+	}
+	for _, p := range scrapers {
+		if _, exists := r.providers[p.Name()]; exists {
+			slog.Warn("scraper name taken by a built-in provider", "name", p.Name())
+			continue
+		}
+		r.providers[p.Name()] = p
+		r.scrapers = append(r.scrapers, p.Name())
+	}
 }
 
 func (r *Registry) Get(name string) (providers.Provider, error) {
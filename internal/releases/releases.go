@@ -0,0 +1,182 @@
+// Package releases parses fansub/scene release names and ranks alternatives
+// of the same episode by the user's group, resolution and codec preferences
+package releases
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+// Info is the metadata found in a release name
+type Info struct {
+	Group      string // Release/fansub group, e.g. "SubsPlease" (empty = unknown)
+	Resolution string // Normalized resolution, e.g. "1080p" (empty = unknown)
+	Codec      string // Normalized codec: "hevc", "avc" or "av1" (empty = unknown)
+}
+
+var (
+	// leadingGroup matches "[Group] Show - 01 ..."
+	leadingGroup = regexp.MustCompile(`^\s*\[([^\]]+)\]`)
+	// trailingGroup matches scene names like "Show.S01E01.1080p.WEB.x264-GROUP.mkv"
+	trailingGroup = regexp.MustCompile(`-([A-Za-z0-9]+)(?:\.[A-Za-z0-9]{2,4})?\s*$`)
+	// resolutionTag matches "1080p", "720P", "1920x1080" or "4K"
+	resolutionTag = regexp.MustCompile(`(?i)\b(?:\d{3,4}x(\d{3,4})|(\d{3,4})p|(4k|uhd))\b`)
+	// codecTag matches common video codec names
+	codecTag = regexp.MustCompile(`(?i)\b(hevc|[xh]\.?265|avc|[xh]\.?264|av1)\b`)
+)
+
+// Parse extracts the group, resolution and codec from a release name
+func Parse(name string) Info {
+	var info Info
+
+	if m := leadingGroup.FindStringSubmatch(name); m != nil {
+		info.Group = strings.TrimSpace(m[1])
+	} else if m := trailingGroup.FindStringSubmatch(name); m != nil && !resolutionTag.MatchString(m[1]) && NormalizeCodec(m[1]) == "" {
+		info.Group = m[1]
+	}
+
+	if m := resolutionTag.FindStringSubmatch(name); m != nil {
+		switch {
+		case m[1] != "":
+			info.Resolution = NormalizeResolution(m[1] + "p")
+		case m[2] != "":
+			info.Resolution = NormalizeResolution(m[2] + "p")
+		default:
+			info.Resolution = NormalizeResolution(m[3])
+		}
+	}
+
+	if m := codecTag.FindStringSubmatch(name); m != nil {
+		info.Codec = NormalizeCodec(m[1])
+	}
+
+	return info
+}
+
+// NormalizeResolution maps resolution spellings to the "1080p" form
+func NormalizeResolution(res string) string {
+	res = strings.ToLower(strings.TrimSpace(res))
+	switch res {
+	case "4k", "uhd", "2160", "2160p":
+		return "2160p"
+	case "":
+		return ""
+	}
+	if !strings.HasSuffix(res, "p") {
+		res += "p"
+	}
+	return res
+}
+
+// NormalizeCodec maps codec spellings to "hevc", "avc" or "av1"
+// Returns an empty string for unknown codecs
+func NormalizeCodec(codec string) string {
+	switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(codec)), ".", "") {
+	case "hevc", "x265", "h265":
+		return "hevc"
+	case "avc", "x264", "h264":
+		return "avc"
+	case "av1":
+		return "av1"
+	default:
+		return ""
+	}
+}
+
+// Preferences ranks and filters alternatives of the same release
+type Preferences struct {
+	PreferredGroups      []string // Most preferred first
+	BannedGroups         []string // Never selected
+	PreferredResolutions []string // Most preferred first, e.g. ["1080p", "720p"]
+	PreferredCodecs      []string // Most preferred first, e.g. ["hevc", "avc"]
+}
+
+// FromConfig builds preferences from the downloads.releases config
+func FromConfig(cfg *config.ReleasesConfig) Preferences {
+	return Preferences{
+		PreferredGroups:      cfg.PreferredGroups,
+		BannedGroups:         cfg.BannedGroups,
+		PreferredResolutions: cfg.PreferredResolutions,
+		PreferredCodecs:      cfg.PreferredCodecs,
+	}
+}
+
+// IsZero reports whether no preference is set
+func (p Preferences) IsZero() bool {
+	return len(p.PreferredGroups) == 0 && len(p.BannedGroups) == 0 &&
+		len(p.PreferredResolutions) == 0 && len(p.PreferredCodecs) == 0
+}
+
+// Allowed reports whether a release is not from a banned group
+func (p Preferences) Allowed(info Info) bool {
+	return info.Group == "" || indexOf(p.BannedGroups, info.Group, strings.ToLower) < 0
+}
+
+// Less reports whether release a is preferred over release b. Group
+// preference outweighs resolution, which outweighs codec; releases matching
+// no preference rank after those that do.
+func (p Preferences) Less(a, b Info) bool {
+	ranks := [][2]int{
+		{rank(p.PreferredGroups, a.Group, strings.ToLower), rank(p.PreferredGroups, b.Group, strings.ToLower)},
+		{rank(p.PreferredResolutions, a.Resolution, NormalizeResolution), rank(p.PreferredResolutions, b.Resolution, NormalizeResolution)},
+		{rank(p.PreferredCodecs, a.Codec, NormalizeCodec), rank(p.PreferredCodecs, b.Codec, NormalizeCodec)},
+	}
+	for _, r := range ranks {
+		if r[0] != r[1] {
+			return r[0] < r[1]
+		}
+	}
+	return false
+}
+
+// Select drops banned releases and stably sorts the rest by preference.
+// name returns the release name of an element.
+func Select[T any](p Preferences, items []T, name func(T) string) []T {
+	type candidate struct {
+		item T
+		info Info
+	}
+
+	candidates := make([]candidate, 0, len(items))
+	for _, item := range items {
+		info := Parse(name(item))
+		if p.Allowed(info) {
+			candidates = append(candidates, candidate{item: item, info: info})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return p.Less(candidates[i].info, candidates[j].info)
+	})
+
+	selected := make([]T, len(candidates))
+	for i, c := range candidates {
+		selected[i] = c.item
+	}
+	return selected
+}
+
+// rank returns the position of value in a preference list, or len(list)
+// when it isn't listed
+func rank(list []string, value string, normalize func(string) string) int {
+	if i := indexOf(list, value, normalize); i >= 0 {
+		return i
+	}
+	return len(list)
+}
+
+// indexOf finds value in list, comparing normalized forms
+func indexOf(list []string, value string, normalize func(string) string) int {
+	if value == "" {
+		return -1
+	}
+	value = normalize(value)
+	for i, entry := range list {
+		if n := normalize(entry); n != "" && n == value {
+			return i
+		}
+	}
+	return -1
+}
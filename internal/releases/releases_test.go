@@ -0,0 +1,59 @@
+package releases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Info
+	}{
+		{"[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv", Info{Group: "SubsPlease", Resolution: "1080p"}},
+		{"[Erai-raws] Kusuriya no Hitorigoto - 12 [720p][HEVC][Multiple Subtitle]", Info{Group: "Erai-raws", Resolution: "720p", Codec: "hevc"}},
+		{"Show.Name.S01E05.2160p.WEB.H.265-GROUP.mkv", Info{Group: "GROUP", Resolution: "2160p", Codec: "hevc"}},
+		{"Show Name - 05 (BD 1920x1080 x264 FLAC)", Info{Resolution: "1080p", Codec: "avc"}},
+		{"Show.Name.S01E05.4K.WEB-x265", Info{Resolution: "2160p", Codec: "hevc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.name))
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	prefs := Preferences{
+		PreferredGroups:      []string{"subsplease", "Erai-raws"},
+		BannedGroups:         []string{"BadSubs"},
+		PreferredResolutions: []string{"1080p", "720p"},
+		PreferredCodecs:      []string{"x265"},
+	}
+
+	names := []string{
+		"[BadSubs] Show - 01 [1080p]",
+		"[Other] Show - 01 [1080p] [HEVC]",
+		"[Erai-raws] Show - 01 [720p]",
+		"[Other] Show - 01 [1080p]",
+		"[SubsPlease] Show - 01 (480p)",
+		"[Erai-raws] Show - 01 [1080p]",
+	}
+	selected := Select(prefs, names, func(s string) string { return s })
+
+	assert.Equal(t, []string{
+		"[SubsPlease] Show - 01 (480p)",
+		"[Erai-raws] Show - 01 [1080p]",
+		"[Erai-raws] Show - 01 [720p]",
+		"[Other] Show - 01 [1080p] [HEVC]",
+		"[Other] Show - 01 [1080p]",
+	}, selected)
+}
+
+func TestSelectWithoutPreferencesKeepsOrder(t *testing.T) {
+	names := []string{"[B] Show - 01", "[A] Show - 01 [720p]", "Show - 01"}
+	assert.True(t, Preferences{}.IsZero())
+	assert.Equal(t, names, Select(Preferences{}, names, func(s string) string { return s }))
+}
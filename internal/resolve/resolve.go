@@ -0,0 +1,212 @@
+// Package resolve walks a provider from a show or movie to an episode and its
+// stream: search, details, seasons, episodes, stream. The CLI commands and the
+// TUI resolve through it so they fall back the same way.
+package resolve
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// MediaRef names a show or movie on a provider: by its ID there when known,
+// else by a title to search for. With both, the title is searched when the ID
+// no longer resolves.
+type MediaRef struct {
+	ID    string
+	Title string
+	Type  providers.MediaType // Optional; a movie resolves without listing seasons
+}
+
+// EpisodeSelector picks an episode of a show
+type EpisodeSelector struct {
+	Season  int // 0 for the first season
+	Episode int // 0 for the movie, or the first episode of a show
+}
+
+// Prefs are the stream preferences of a resolution
+type Prefs struct {
+	Quality providers.Quality // Quality1080p when empty
+	Audio   string            // Sub/dub preference passed to providers taking one; empty keeps theirs
+}
+
+// Result is what a reference and selector resolved to
+type Result struct {
+	Media    providers.Media
+	Episode  providers.Episode
+	Episodes []providers.Episode  // The episode's season; nil for movies
+	Stream   *providers.StreamURL // Set by EpisodeStream only
+}
+
+// EpisodeStream resolves ref and sel on p and fetches the episode's stream
+func EpisodeStream(ctx context.Context, p providers.Provider, ref MediaRef, sel EpisodeSelector, prefs Prefs) (*Result, error) {
+	res, err := Episode(ctx, p, ref, sel)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefs.Audio != "" {
+		if setter, ok := providers.As[providers.AudioPreferenceSetter](p); ok {
+			setter.SetAudioPreference(prefs.Audio)
+		}
+	}
+	quality := prefs.Quality
+	if quality == "" {
+		quality = providers.Quality1080p
+	}
+	res.Stream, err = p.GetStreamURL(ctx, res.Episode.ID, quality)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream URL: %w", err)
+	}
+	return res, nil
+}
+
+// Episode resolves ref and sel on p to an episode, searching ref's title
+// when its ID no longer resolves
+func Episode(ctx context.Context, p providers.Provider, ref MediaRef, sel EpisodeSelector) (*Result, error) {
+	media, err := Media(ctx, p, ref)
+	if err != nil {
+		return nil, err
+	}
+	res, err := pickEpisode(ctx, p, media, ref.Type, sel)
+	if err == nil || ref.ID == "" || ref.Title == "" {
+		return res, err
+	}
+
+	// The ID may be stale, so look the media up again by title
+	found, sErr := Search(ctx, p, ref.Title)
+	if sErr != nil || found.ID == ref.ID {
+		return nil, err
+	}
+	return pickEpisode(ctx, p, *found, ref.Type, sel)
+}
+
+// Media returns the media ref names on p, searching its title when it has
+// no ID
+func Media(ctx context.Context, p providers.Provider, ref MediaRef) (providers.Media, error) {
+	if ref.ID != "" {
+		mediaType := ref.Type
+		if mediaType == "" {
+			mediaType = p.Type()
+		}
+		return providers.Media{ID: ref.ID, Title: ref.Title, Type: mediaType}, nil
+	}
+	if ref.Title == "" {
+		return providers.Media{}, fmt.Errorf("no media to resolve")
+	}
+	found, err := Search(ctx, p, ref.Title)
+	if err != nil {
+		return providers.Media{}, err
+	}
+	return *found, nil
+}
+
+// Search returns the result of searching p for title with the same title,
+// else its first result
+func Search(ctx context.Context, p providers.Provider, title string) (*providers.Media, error) {
+	results, err := p.Search(ctx, title)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results found for query: %s", title)
+	}
+	for i := range results {
+		if providers.SameTitle(results[i].Title, title) {
+			return &results[i], nil
+		}
+	}
+	return &results[0], nil
+}
+
+// Episodes lists a season of mediaID on p: the numbered one, else the first.
+// Seasons come from GetSeasons, else the media details; providers listing
+// neither have their episodes under the media ID itself. Episodes without a
+// season number get the season's.
+func Episodes(ctx context.Context, p providers.Provider, mediaID string, season int) ([]providers.Episode, error) {
+	seasons, err := p.GetSeasons(ctx, mediaID)
+	if err != nil || len(seasons) == 0 {
+		if details, dErr := p.GetMediaDetails(ctx, mediaID); dErr == nil && details != nil {
+			seasons = details.Seasons
+		}
+	}
+
+	seasonID, number := mediaID, 0
+	if len(seasons) > 0 {
+		seasonID, number = seasons[0].ID, seasons[0].Number
+		if season > 0 {
+			found := false
+			for _, s := range seasons {
+				if s.Number == season {
+					seasonID, number, found = s.ID, s.Number, true
+					break
+				}
+			}
+			if !found && len(seasons) > 1 {
+				return nil, fmt.Errorf("season %d not found (%d seasons)", season, len(seasons))
+			}
+		}
+	}
+
+	episodes, err := p.GetEpisodes(ctx, seasonID)
+	if err != nil && seasonID != mediaID {
+		episodes, err = p.GetEpisodes(ctx, mediaID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get episodes: %w", err)
+	}
+	for i := range episodes {
+		if episodes[i].Season == 0 {
+			episodes[i].Season = number
+		}
+	}
+	return episodes, nil
+}
+
+// pickEpisode finds sel among the episodes of media on p. A movie resolves
+// through providers.MovieEpisodeID, and so does the first episode of
+// anything listing none.
+func pickEpisode(ctx context.Context, p providers.Provider, media providers.Media, mediaType providers.MediaType, sel EpisodeSelector) (*Result, error) {
+	movie := func() (*Result, error) {
+		id, err := providers.MovieEpisodeID(ctx, p, media.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Media: media, Episode: providers.Episode{ID: id, Title: media.Title}}, nil
+	}
+	var movieErr error
+	if sel.Episode == 0 && (mediaType == providers.MediaTypeMovie || media.Type == providers.MediaTypeMovie) {
+		res, err := movie()
+		if err == nil {
+			return res, nil
+		}
+		movieErr = err
+	}
+
+	episodes, err := Episodes(ctx, p, media.ID, sel.Season)
+	if sel.Episode == 0 && (err != nil || len(episodes) == 0) {
+		if movieErr != nil {
+			return nil, movieErr
+		}
+		return movie()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(episodes) == 0 {
+		return nil, fmt.Errorf("no episodes available")
+	}
+
+	res := &Result{Media: media, Episode: episodes[0], Episodes: episodes}
+	if sel.Episode == 0 {
+		return res, nil
+	}
+	for _, ep := range episodes {
+		if ep.Number == sel.Episode {
+			res.Episode = ep
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("episode %d not found", sel.Episode)
+}
@@ -0,0 +1,136 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// catalog serves "show" with two seasons of two episodes and the movie
+// "film", which it lists under no season. Media IDs it doesn't know are stale.
+type catalog struct {
+	audio string // Last audio preference set
+}
+
+func (c *catalog) Name() string                                           { return "catalog" }
+func (c *catalog) Type() providers.MediaType                              { return providers.MediaTypeMovieTV }
+func (c *catalog) Capabilities() providers.Capabilities                   { return providers.Capabilities{Streams: true} }
+func (c *catalog) GetTrending(context.Context) ([]providers.Media, error) { return nil, nil }
+func (c *catalog) GetRecent(context.Context) ([]providers.Media, error)   { return nil, nil }
+func (c *catalog) HealthCheck(context.Context) error                      { return nil }
+
+func (c *catalog) Search(ctx context.Context, query string) ([]providers.Media, error) {
+	return []providers.Media{
+		{ID: "other", Title: "Show: The Movie", Type: providers.MediaTypeMovie},
+		{ID: "show", Title: "Show", Type: providers.MediaTypeTV},
+		{ID: "film", Title: "Film", Type: providers.MediaTypeMovie},
+	}, nil
+}
+
+func (c *catalog) GetMediaDetails(ctx context.Context, id string) (*providers.MediaDetails, error) {
+	if id != "show" && id != "film" {
+		return nil, errors.New("not found")
+	}
+	return &providers.MediaDetails{Media: providers.Media{ID: id}}, nil
+}
+
+func (c *catalog) GetSeasons(ctx context.Context, mediaID string) ([]providers.Season, error) {
+	if mediaID != "show" {
+		return nil, errors.New("no seasons")
+	}
+	return []providers.Season{{ID: "show-s1", Number: 1}, {ID: "show-s2", Number: 2}}, nil
+}
+
+func (c *catalog) GetEpisodes(ctx context.Context, seasonID string) ([]providers.Episode, error) {
+	switch seasonID {
+	case "show-s1", "show-s2":
+		return []providers.Episode{{ID: seasonID + "-e1", Number: 1}, {ID: seasonID + "-e2", Number: 2}}, nil
+	case "film":
+		return []providers.Episode{{ID: "film-full", Number: 1}}, nil
+	}
+	return nil, errors.New("no episodes")
+}
+
+func (c *catalog) GetStreamURL(ctx context.Context, episodeID string, quality providers.Quality) (*providers.StreamURL, error) {
+	return &providers.StreamURL{URL: "https://c.example/" + episodeID + ".m3u8", Quality: quality}, nil
+}
+
+func (c *catalog) GetAvailableQualities(ctx context.Context, episodeID string) ([]providers.Quality, error) {
+	return []providers.Quality{providers.Quality1080p}, nil
+}
+
+func (c *catalog) SetAudioPreference(preference string) { c.audio = preference }
+
+// movieCatalog looks movies up directly
+type movieCatalog struct {
+	catalog
+}
+
+func (c *movieCatalog) GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error) {
+	if mediaID != "film" {
+		return "", errors.New("not a movie")
+	}
+	return "film-direct", nil
+}
+
+func TestEpisodeStream(t *testing.T) {
+	ctx := context.Background()
+	p := &catalog{}
+
+	res, err := EpisodeStream(ctx, p, MediaRef{Title: "show"}, EpisodeSelector{Season: 2, Episode: 2}, Prefs{Audio: "dub"})
+	require.NoError(t, err)
+	assert.Equal(t, "show", res.Media.ID, "the result with the same title wins over the first")
+	assert.Equal(t, "show-s2-e2", res.Episode.ID)
+	assert.Equal(t, 2, res.Episode.Season)
+	assert.Len(t, res.Episodes, 2)
+	assert.Equal(t, "https://c.example/show-s2-e2.m3u8", res.Stream.URL)
+	assert.Equal(t, providers.Quality1080p, res.Stream.Quality)
+	assert.Equal(t, "dub", p.audio)
+
+	res, err = EpisodeStream(ctx, p, MediaRef{ID: "show"}, EpisodeSelector{}, Prefs{Quality: providers.Quality720p})
+	require.NoError(t, err)
+	assert.Equal(t, "show-s1-e1", res.Episode.ID, "no selector picks the first episode")
+	assert.Equal(t, providers.Quality720p, res.Stream.Quality)
+}
+
+func TestEpisodeMovie(t *testing.T) {
+	ctx := context.Background()
+
+	res, err := Episode(ctx, &catalog{}, MediaRef{Title: "Film"}, EpisodeSelector{})
+	require.NoError(t, err)
+	assert.Equal(t, "film-full", res.Episode.ID, "listed as its only episode")
+
+	res, err = Episode(ctx, &movieCatalog{}, MediaRef{ID: "film", Title: "film", Type: providers.MediaTypeMovie}, EpisodeSelector{})
+	require.NoError(t, err)
+	assert.Equal(t, "film-direct", res.Episode.ID)
+	assert.Equal(t, "film", res.Episode.Title)
+	assert.Nil(t, res.Episodes)
+}
+
+func TestEpisodeStaleID(t *testing.T) {
+	res, err := Episode(context.Background(), &catalog{}, MediaRef{ID: "gone", Title: "Show"}, EpisodeSelector{Season: 1, Episode: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "show", res.Media.ID)
+	assert.Equal(t, "show-s1-e2", res.Episode.ID)
+
+	_, err = Episode(context.Background(), &catalog{}, MediaRef{ID: "gone"}, EpisodeSelector{Episode: 2})
+	assert.Error(t, err, "without a title there is nothing to search")
+}
+
+func TestEpisodeErrors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := Episode(ctx, &catalog{}, MediaRef{ID: "show"}, EpisodeSelector{Season: 1, Episode: 5})
+	assert.EqualError(t, err, "episode 5 not found")
+
+	_, err = Episode(ctx, &catalog{}, MediaRef{ID: "show"}, EpisodeSelector{Season: 3, Episode: 1})
+	assert.EqualError(t, err, "season 3 not found (2 seasons)")
+
+	_, err = Episode(ctx, &catalog{}, MediaRef{}, EpisodeSelector{})
+	assert.Error(t, err)
+}
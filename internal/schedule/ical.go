@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// icalTime is the UTC date-time format of iCal
+const icalTime = "20060102T150405Z"
+
+// WriteICal writes the upcoming episodes as an iCal calendar (RFC 5545),
+// one event per episode
+func WriteICal(w io.Writer, s *Schedule) error {
+	bw := bufio.NewWriter(w)
+	line := func(format string, args ...interface{}) {
+		writeFolded(bw, fmt.Sprintf(format, args...))
+	}
+
+	stamp := s.GeneratedAt.UTC().Format(icalTime)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//greg//airing schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:greg airing schedule")
+	line("X-PUBLISHED-TTL:PT1H")
+	for _, ep := range s.Episodes {
+		summary := fmt.Sprintf("%s - Episode %d", ep.Title, ep.Episode)
+		description := fmt.Sprintf("Episode %d", ep.Episode)
+		if ep.Total > 0 {
+			description += fmt.Sprintf(" of %d", ep.Total)
+			if ep.Episode == ep.Total {
+				summary += " (final)"
+			}
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:greg-%d-%d@anilist.co", ep.MediaID, ep.Episode)
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART:%s", ep.AiringAt.UTC().Format(icalTime))
+		line("DTEND:%s", ep.AiringAt.Add(episodeLength(ep)).UTC().Format(icalTime))
+		line("SUMMARY:%s", escapeText(summary))
+		line("DESCRIPTION:%s", escapeText(description))
+		line("URL:%s", ep.URL)
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func episodeLength(ep Episode) time.Duration {
+	if ep.Duration > 0 {
+		return ep.Duration
+	}
+	return DefaultEpisodeLength
+}
+
+// escapeText escapes an iCal TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line ending in CRLF, folded into lines of at
+// most 75 octets without splitting UTF-8 characters
+func writeFolded(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts
+		limit = 74
+	}
+	_, _ = w.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,140 @@
+// Package schedule builds the airing schedule and watch status of the shows
+// followed on AniList, for calendars (iCal) and dashboard widgets (JSON)
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/anilist"
+)
+
+// DefaultEpisodeLength is the length of an episode of unknown duration
+const DefaultEpisodeLength = 24 * time.Minute
+
+// Client is the AniList client a schedule is built from
+type Client interface {
+	GetUserLibrary(ctx context.Context, mediaType providers.MediaType) ([]tracker.TrackedMedia, error)
+	GetAiringSchedule(ctx context.Context, ids []int, from, to time.Time) ([]anilist.AiringEpisode, error)
+}
+
+// Episode is an upcoming episode of a followed show
+type Episode struct {
+	MediaID  int           `json:"media_id"`
+	Title    string        `json:"title"`
+	Episode  int           `json:"episode"`
+	Total    int           `json:"total_episodes,omitempty"` // 0 when unknown
+	AiringAt time.Time     `json:"airing_at"`
+	Duration time.Duration `json:"-"`
+	Cover    string        `json:"cover,omitempty"`
+	URL      string        `json:"url"`
+}
+
+// Show is the watch status of a show being watched
+type Show struct {
+	MediaID  int    `json:"media_id"`
+	Title    string `json:"title"`
+	Progress int    `json:"progress"`
+	Aired    int    `json:"aired"`                    // Episodes out so far, 0 when unknown
+	Total    int    `json:"total_episodes,omitempty"` // 0 when unknown
+	Behind   int    `json:"behind"`                   // Aired episodes not watched yet
+	Airing   bool   `json:"airing"`
+	Cover    string `json:"cover,omitempty"`
+	URL      string `json:"url"`
+}
+
+// Schedule is the upcoming episodes of the followed shows and the watch
+// status of the ones being watched
+type Schedule struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Episodes    []Episode `json:"episodes"` // In airing order
+	Shows       []Show    `json:"shows"`    // Most episodes behind first
+}
+
+// Followed reports whether a library entry's episodes belong in the
+// schedule: it's being watched, rewatched or planned
+func Followed(media tracker.TrackedMedia) bool {
+	switch media.Status {
+	case tracker.StatusWatching, tracker.StatusRewatching, tracker.StatusPlanToWatch:
+		return true
+	}
+	return false
+}
+
+// Load builds the schedule of the episodes airing in the next days
+func Load(ctx context.Context, client Client, now time.Time, days int) (*Schedule, error) {
+	library, err := client.GetUserLibrary(ctx, providers.MediaTypeAnime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch AniList library: %w", err)
+	}
+
+	s := &Schedule{GeneratedAt: now, Episodes: []Episode{}, Shows: []Show{}}
+	followed := make(map[int]tracker.TrackedMedia)
+	var airing []int
+	for _, media := range library {
+		id, err := strconv.Atoi(media.ServiceID)
+		if err != nil || !Followed(media) {
+			continue
+		}
+		followed[id] = media
+		if media.IsAiring() || media.NextEpisode > 0 || media.AiringStatus == "NOT_YET_RELEASED" {
+			airing = append(airing, id)
+		}
+		if media.Status != tracker.StatusPlanToWatch {
+			s.Shows = append(s.Shows, showOf(id, media))
+		}
+	}
+	sort.SliceStable(s.Shows, func(i, j int) bool { return s.Shows[i].Behind > s.Shows[j].Behind })
+	sort.Ints(airing)
+
+	upcoming, err := client.GetAiringSchedule(ctx, airing, now, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch airing schedule: %w", err)
+	}
+	for _, ep := range upcoming {
+		media, ok := followed[ep.MediaID]
+		if !ok {
+			continue
+		}
+		duration := ep.Duration
+		if duration <= 0 {
+			duration = DefaultEpisodeLength
+		}
+		s.Episodes = append(s.Episodes, Episode{
+			MediaID:  ep.MediaID,
+			Title:    media.Title,
+			Episode:  ep.Episode,
+			Total:    media.TotalEpisodes,
+			AiringAt: ep.AiringAt,
+			Duration: duration,
+			Cover:    media.PosterURL,
+			URL:      mediaURL(ep.MediaID),
+		})
+	}
+	sort.SliceStable(s.Episodes, func(i, j int) bool { return s.Episodes[i].AiringAt.Before(s.Episodes[j].AiringAt) })
+	return s, nil
+}
+
+func showOf(id int, media tracker.TrackedMedia) Show {
+	aired := media.AiredEpisodes()
+	return Show{
+		MediaID:  id,
+		Title:    media.Title,
+		Progress: media.Progress,
+		Aired:    aired,
+		Total:    media.TotalEpisodes,
+		Behind:   max(aired-media.Progress, 0),
+		Airing:   media.IsAiring(),
+		Cover:    media.PosterURL,
+		URL:      mediaURL(id),
+	}
+}
+
+func mediaURL(id int) string {
+	return fmt.Sprintf("https://anilist.co/anime/%d", id)
+}
@@ -0,0 +1,204 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/anilist"
+)
+
+type fakeClient struct {
+	library  []tracker.TrackedMedia
+	episodes []anilist.AiringEpisode
+	ids      []int
+	err      error
+}
+
+func (c *fakeClient) GetUserLibrary(ctx context.Context, mediaType providers.MediaType) ([]tracker.TrackedMedia, error) {
+	return c.library, c.err
+}
+
+func (c *fakeClient) GetAiringSchedule(ctx context.Context, ids []int, from, to time.Time) ([]anilist.AiringEpisode, error) {
+	c.ids = ids
+	return c.episodes, nil
+}
+
+var now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		library: []tracker.TrackedMedia{
+			{ServiceID: "1", Title: "Frieren", Status: tracker.StatusWatching, Progress: 3, TotalEpisodes: 12, AiringStatus: "RELEASING", NextEpisode: 6},
+			{ServiceID: "2", Title: "Dungeon Meshi", Status: tracker.StatusPlanToWatch, AiringStatus: "NOT_YET_RELEASED"},
+			{ServiceID: "3", Title: "Cowboy Bebop", Status: tracker.StatusWatching, Progress: 20, TotalEpisodes: 26, AiringStatus: "FINISHED"},
+			{ServiceID: "4", Title: "Dropped Show", Status: tracker.StatusDropped, AiringStatus: "RELEASING", NextEpisode: 2},
+		},
+		episodes: []anilist.AiringEpisode{
+			{MediaID: 2, Episode: 1, AiringAt: now.Add(3 * 24 * time.Hour)},
+			{MediaID: 1, Episode: 6, AiringAt: now.Add(2 * time.Hour), Duration: 25 * time.Minute},
+			{MediaID: 4, Episode: 2, AiringAt: now.Add(time.Hour)},
+		},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	client := newFakeClient()
+	s, err := Load(context.Background(), client, now, 14)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2}, client.ids, "only followed, airing shows")
+	require.Len(t, s.Episodes, 2)
+	assert.Equal(t, "Frieren", s.Episodes[0].Title)
+	assert.Equal(t, 12, s.Episodes[0].Total)
+	assert.Equal(t, 25*time.Minute, s.Episodes[0].Duration)
+	assert.Equal(t, "https://anilist.co/anime/1", s.Episodes[0].URL)
+	assert.Equal(t, "Dungeon Meshi", s.Episodes[1].Title)
+	assert.Equal(t, DefaultEpisodeLength, s.Episodes[1].Duration)
+
+	require.Len(t, s.Shows, 2, "planned shows aren't being watched")
+	assert.Equal(t, "Cowboy Bebop", s.Shows[0].Title)
+	assert.Equal(t, 6, s.Shows[0].Behind)
+	assert.Equal(t, "Frieren", s.Shows[1].Title)
+	assert.Equal(t, 5, s.Shows[1].Aired)
+	assert.Equal(t, 2, s.Shows[1].Behind)
+	assert.True(t, s.Shows[1].Airing)
+}
+
+func TestLoadLibraryError(t *testing.T) {
+	_, err := Load(context.Background(), &fakeClient{err: errors.New("offline")}, now, 14)
+	assert.ErrorContains(t, err, "offline")
+}
+
+func TestWriteICal(t *testing.T) {
+	s, err := Load(context.Background(), newFakeClient(), now, 14)
+	require.NoError(t, err)
+	s.Episodes[0].Title = "Frieren; Beyond, the End"
+
+	var b strings.Builder
+	require.NoError(t, WriteICal(&b, s))
+	ical := b.String()
+
+	assert.True(t, strings.HasPrefix(ical, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ical, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ical, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, ical, "UID:greg-1-6@anilist.co\r\n")
+	assert.Contains(t, ical, "DTSTART:20260101T140000Z\r\n")
+	assert.Contains(t, ical, "DTEND:20260101T142500Z\r\n")
+	assert.Contains(t, ical, `SUMMARY:Frieren\; Beyond\, the End - Episode 6`)
+	assert.Contains(t, ical, "DESCRIPTION:Episode 6 of 12\r\n")
+	assert.Contains(t, ical, "DTSTAMP:20260101T120000Z\r\n")
+}
+
+func TestWriteICalFoldsLongLines(t *testing.T) {
+	s := &Schedule{GeneratedAt: now, Episodes: []Episode{{
+		MediaID:  1,
+		Title:    strings.Repeat("長い", 40),
+		Episode:  1,
+		AiringAt: now,
+	}}}
+
+	var b strings.Builder
+	require.NoError(t, WriteICal(&b, s))
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, strings.ToValidUTF8(line, "") == line, "folding must not split characters")
+	}
+	assert.Contains(t, strings.ReplaceAll(b.String(), "\r\n ", ""), "SUMMARY:"+strings.Repeat("長い", 40))
+}
+
+func TestWidget(t *testing.T) {
+	s, err := Load(context.Background(), newFakeClient(), now, 14)
+	require.NoError(t, err)
+
+	w := s.Widget(now)
+	assert.Equal(t, 2, w.Watching)
+	assert.Equal(t, 2, w.Behind)
+	assert.Equal(t, 8, w.Unwatched)
+	assert.Equal(t, 1, w.AiringToday)
+	require.NotNil(t, w.Next)
+	assert.Equal(t, "Frieren", w.Next.Title)
+	assert.Equal(t, "2h 0m", w.Next.AiringIn)
+	assert.Equal(t, int64(7200), w.Next.AiringInSeconds)
+	require.Len(t, w.Upcoming, 2)
+	assert.Equal(t, "3d 0h", w.Upcoming[1].AiringIn)
+
+	// Episodes that aired since the schedule was loaded drop out
+	w = s.Widget(now.Add(3 * time.Hour))
+	require.Len(t, w.Upcoming, 1)
+	assert.Equal(t, "Dungeon Meshi", w.Next.Title)
+}
+
+func TestWidgetEmpty(t *testing.T) {
+	data, err := json.Marshal((&Schedule{GeneratedAt: now}).Widget(now))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"next":null`)
+	assert.Contains(t, string(data), `"upcoming":[]`)
+	assert.Contains(t, string(data), `"shows":[]`)
+}
+
+func TestFormatCountdown(t *testing.T) {
+	assert.Equal(t, "35m", formatCountdown(35*time.Minute))
+	assert.Equal(t, "1h 5m", formatCountdown(65*time.Minute))
+	assert.Equal(t, "2d 4h", formatCountdown(52*time.Hour+10*time.Minute))
+}
+
+func TestServer(t *testing.T) {
+	loads := 0
+	server := NewServer(func(ctx context.Context) (*Schedule, error) {
+		loads++
+		if loads > 1 {
+			return nil, errors.New("offline")
+		}
+		return Load(ctx, newFakeClient(), now, 14)
+	}, time.Hour)
+	clock := now
+	server.now = func() time.Time { return clock }
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/schedule.ics")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "BEGIN:VEVENT")
+
+	rec = get("/widget.json")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	var w Widget
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &w))
+	assert.Equal(t, "Frieren", w.Next.Title)
+	assert.Equal(t, 1, loads, "cached until the refresh interval")
+
+	// A failed reload keeps serving the last schedule
+	clock = now.Add(2 * time.Hour)
+	rec = get("/schedule.json")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, loads)
+
+	assert.Equal(t, http.StatusNotFound, get("/other").Code)
+}
+
+func TestServerLoadError(t *testing.T) {
+	server := NewServer(func(ctx context.Context) (*Schedule, error) {
+		return nil, errors.New("offline")
+	}, time.Hour)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/widget.json", nil))
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
@@ -0,0 +1,90 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Server serves a schedule to calendars and dashboards:
+//
+//	/schedule.ics  the iCal calendar, to subscribe to
+//	/widget.json   the Widget JSON
+//	/schedule.json the whole schedule
+//
+// The schedule is loaded on the first request and reloaded once it is
+// older than the refresh interval.
+type Server struct {
+	load    func(ctx context.Context) (*Schedule, error)
+	refresh time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	cached   *Schedule
+	loadedAt time.Time
+}
+
+// NewServer returns a server of the schedules load returns
+func NewServer(load func(ctx context.Context) (*Schedule, error), refresh time.Duration) *Server {
+	return &Server{load: load, refresh: refresh, now: time.Now}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch r.URL.Path {
+	case "/schedule.ics", "/widget.json", "/schedule.json":
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	schedule, err := s.schedule(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	switch r.URL.Path {
+	case "/schedule.ics":
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_ = WriteICal(w, schedule)
+	case "/widget.json":
+		writeJSON(w, schedule.Widget(s.now()))
+	case "/schedule.json":
+		writeJSON(w, schedule)
+	}
+}
+
+// schedule returns the cached schedule, reloading it when stale. A failed
+// reload keeps serving the last schedule.
+func (s *Server) schedule(ctx context.Context) (*Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && s.now().Sub(s.loadedAt) < s.refresh {
+		return s.cached, nil
+	}
+	schedule, err := s.load(ctx)
+	if err != nil {
+		if s.cached != nil {
+			return s.cached, nil
+		}
+		return nil, err
+	}
+	s.cached, s.loadedAt = schedule, s.now()
+	return schedule, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// upcomingLimit is how many upcoming episodes a widget lists
+const upcomingLimit = 10
+
+// Widget is the JSON served to dashboards such as Homepage and Glance: a few
+// counters for stat tiles and the next episodes for lists
+type Widget struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Watching    int             `json:"watching"`     // Shows being watched
+	Behind      int             `json:"behind"`       // Shows with aired episodes not watched yet
+	Unwatched   int             `json:"unwatched"`    // Aired episodes not watched yet
+	AiringToday int             `json:"airing_today"` // Episodes airing in the next 24 hours
+	Next        *WidgetEpisode  `json:"next"`         // null when nothing is scheduled
+	Upcoming    []WidgetEpisode `json:"upcoming"`
+	Shows       []Show          `json:"shows"`
+}
+
+// WidgetEpisode is an upcoming episode with its countdown spelled out, as
+// widgets can't compute one
+type WidgetEpisode struct {
+	Episode
+	AiringIn        string `json:"airing_in"` // "2d 4h", "35m"
+	AiringInSeconds int64  `json:"airing_in_seconds"`
+}
+
+// Widget summarizes the schedule as of now
+func (s *Schedule) Widget(now time.Time) Widget {
+	w := Widget{
+		GeneratedAt: s.GeneratedAt,
+		Upcoming:    []WidgetEpisode{},
+		Shows:       s.Shows,
+	}
+	if w.Shows == nil {
+		w.Shows = []Show{}
+	}
+
+	for _, show := range s.Shows {
+		w.Watching++
+		if show.Behind > 0 {
+			w.Behind++
+			w.Unwatched += show.Behind
+		}
+	}
+
+	for _, ep := range s.Episodes {
+		until := ep.AiringAt.Sub(now)
+		if until < 0 {
+			continue
+		}
+		if until < 24*time.Hour {
+			w.AiringToday++
+		}
+		if len(w.Upcoming) < upcomingLimit {
+			w.Upcoming = append(w.Upcoming, WidgetEpisode{
+				Episode:         ep,
+				AiringIn:        formatCountdown(until),
+				AiringInSeconds: int64(until.Seconds()),
+			})
+		}
+	}
+	if len(w.Upcoming) > 0 {
+		next := w.Upcoming[0]
+		w.Next = &next
+	}
+	return w
+}
+
+// formatCountdown spells out a duration in its two largest units
+func formatCountdown(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
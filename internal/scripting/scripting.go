@@ -0,0 +1,336 @@
+// Package scripting runs the user's Lua automations: scripts in the scripts
+// directory register hooks on search, playback and download events and can
+// queue downloads and show notifications.
+package scripting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Events scripts can hook with greg.on
+const (
+	EventSearch            = "search"            // query, provider, media_type, results
+	EventPlaybackStarted   = "playback_started"  // title, media_id, provider, media_type, season, episode, anilist_id
+	EventPlaybackEnded     = "playback_ended"    // as playback_started, plus position, duration, percentage, completed
+	EventEpisodeCompleted  = "episode_completed" // as playback_ended, when the episode counts as watched
+	EventDownloadCompleted = "download_completed"
+	EventDownloadFailed    = "download_failed" // as download_completed, plus error
+)
+
+// Events lists the events scripts can hook
+var Events = []string{
+	EventSearch,
+	EventPlaybackStarted,
+	EventPlaybackEnded,
+	EventEpisodeCompleted,
+	EventDownloadCompleted,
+	EventDownloadFailed,
+}
+
+const queueSize = 64 // Events waiting for the scripts
+
+// hookTimeout limits each hook call, so a stuck script can't stall the others
+var hookTimeout = 5 * time.Second
+
+// Event is passed to the hooks as a table. Values are strings, ints,
+// float64s or bools.
+type Event map[string]any
+
+// DownloadRequest is an episode a script asks to download
+type DownloadRequest struct {
+	Script   string // File name of the script asking
+	Provider string
+	MediaID  string // Provider media ID; looked up by Title when empty
+	Title    string // Required
+	Season   int
+	Episode  int
+	Quality  string // e.g. 720p, empty for the default
+}
+
+// Host carries out what scripts ask for. Its methods are called from the
+// script goroutine and must not block.
+type Host interface {
+	Download(req DownloadRequest) error
+	Notify(script, text string)
+}
+
+// Engine runs the loaded scripts, one event at a time on its own goroutine
+type Engine struct {
+	scripts []*script
+	host    Host
+	logger  *slog.Logger
+	events  chan emitted
+	done    chan struct{}
+}
+
+// script is one loaded file with its own Lua state and hooks
+type script struct {
+	name  string
+	state *lua.LState
+	hooks map[string][]*lua.LFunction
+}
+
+type emitted struct {
+	name  string
+	event Event
+}
+
+// Load runs every *.lua file of dir so it can register its hooks, and
+// starts delivering events to them. Scripts that fail to load are skipped
+// and reported in the returned error.
+func Load(dir string, host Host, logger *slog.Logger) (*Engine, error) {
+	e := &Engine{
+		host:   host,
+		logger: logger,
+		events: make(chan emitted, queueSize),
+		done:   make(chan struct{}),
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.lua"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scripts: %w", err)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		s, err := e.load(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		e.scripts = append(e.scripts, s)
+	}
+
+	go e.run()
+	return e, errors.Join(errs...)
+}
+
+// Scripts returns the file names of the loaded scripts
+func (e *Engine) Scripts() []string {
+	names := make([]string, 0, len(e.scripts))
+	for _, s := range e.scripts {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// Emit hands an event to the scripts without waiting for them. It is
+// dropped when the scripts are too far behind.
+func (e *Engine) Emit(name string, event Event) {
+	if e == nil || len(e.scripts) == 0 {
+		return
+	}
+	select {
+	case e.events <- emitted{name: name, event: event}:
+	default:
+		e.logger.Warn("dropped script event, scripts are busy", "event", name)
+	}
+}
+
+// Close stops delivering events once the ones queued are handled
+func (e *Engine) Close() {
+	if e == nil {
+		return
+	}
+	close(e.events)
+	<-e.done
+	for _, s := range e.scripts {
+		s.state.Close()
+	}
+}
+
+func (e *Engine) run() {
+	defer close(e.done)
+	for ev := range e.events {
+		for _, s := range e.scripts {
+			for _, fn := range s.hooks[ev.name] {
+				if err := e.call(s, fn, ev); err != nil {
+					e.logger.Warn("script hook failed", "script", s.name, "event", ev.name, "error", err)
+					e.host.Notify(s.name, fmt.Sprintf("%s hook failed: %v", ev.name, err))
+				}
+			}
+		}
+	}
+}
+
+// call runs a hook with the event, giving up after hookTimeout
+func (e *Engine) call(s *script, fn *lua.LFunction, ev emitted) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	s.state.SetContext(ctx)
+	defer s.state.RemoveContext()
+
+	return s.state.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, toTable(s.state, ev.event))
+}
+
+// load runs a script file, which registers its hooks with greg.on
+func (e *Engine) load(path string) (*script, error) {
+	s := &script{
+		name:  filepath.Base(path),
+		state: newState(),
+		hooks: make(map[string][]*lua.LFunction),
+	}
+	s.state.SetGlobal("greg", e.api(s))
+	s.state.SetGlobal("print", s.state.NewFunction(func(L *lua.LState) int {
+		e.logger.Info("script", "script", s.name, "message", joinArgs(L))
+		return 0
+	}))
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		s.state.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", s.name, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	s.state.SetContext(ctx)
+	defer s.state.RemoveContext()
+	if err := s.state.DoString(string(source)); err != nil {
+		s.state.Close()
+		return nil, fmt.Errorf("failed to load %s: %w", s.name, err)
+	}
+	return s, nil
+}
+
+// newState returns a Lua state with the standard libraries scripts need:
+// no io, no loading other files, and only the clock functions of os
+func newState() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+		{lua.OsLibName, lua.OpenOs},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	osLib := L.GetGlobal("os").(*lua.LTable)
+	clock := L.NewTable()
+	for _, name := range []string{"clock", "date", "difftime", "time"} {
+		clock.RawSetString(name, osLib.RawGetString(name))
+	}
+	L.SetGlobal("os", clock)
+	return L
+}
+
+// api returns the greg table of a script
+func (e *Engine) api(s *script) *lua.LTable {
+	L := s.state
+	return L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		// greg.on(event, function(ev) ... end)
+		"on": func(L *lua.LState) int {
+			name := L.CheckString(1)
+			fn := L.CheckFunction(2)
+			if !slices.Contains(Events, name) {
+				L.ArgError(1, fmt.Sprintf("unknown event %q (one of %s)", name, strings.Join(Events, ", ")))
+			}
+			s.hooks[name] = append(s.hooks[name], fn)
+			return 0
+		},
+		// greg.download(ev[, overrides]) returns true, or nil and an error
+		"download": func(L *lua.LState) int {
+			fields := L.CheckTable(1)
+			if L.GetTop() >= 2 {
+				overrides := L.CheckTable(2)
+				merged := L.NewTable()
+				fields.ForEach(func(k, v lua.LValue) { merged.RawSet(k, v) })
+				overrides.ForEach(func(k, v lua.LValue) { merged.RawSet(k, v) })
+				fields = merged
+			}
+			req := DownloadRequest{
+				Script:   s.name,
+				Provider: lua.LVAsString(fields.RawGetString("provider")),
+				MediaID:  lua.LVAsString(fields.RawGetString("media_id")),
+				Title:    lua.LVAsString(fields.RawGetString("title")),
+				Season:   int(lua.LVAsNumber(fields.RawGetString("season"))),
+				Episode:  int(lua.LVAsNumber(fields.RawGetString("episode"))),
+				Quality:  lua.LVAsString(fields.RawGetString("quality")),
+			}
+			err := req.validate()
+			if err == nil {
+				err = e.host.Download(req)
+			}
+			if err != nil {
+				L.Push(lua.LNil)
+				L.Push(lua.LString(err.Error()))
+				return 2
+			}
+			L.Push(lua.LTrue)
+			return 1
+		},
+		// greg.notify(text) shows a toast
+		"notify": func(L *lua.LState) int {
+			e.host.Notify(s.name, joinArgs(L))
+			return 0
+		},
+		// greg.log(text) writes to greg's log
+		"log": func(L *lua.LState) int {
+			e.logger.Info("script", "script", s.name, "message", joinArgs(L))
+			return 0
+		},
+	})
+}
+
+// validate checks a download names a provider, a show and an episode
+func (r DownloadRequest) validate() error {
+	switch {
+	case r.Provider == "":
+		return errors.New("provider is required")
+	case r.Title == "":
+		return errors.New("title is required")
+	case r.Episode <= 0:
+		return errors.New("episode must be 1 or more")
+	}
+	return nil
+}
+
+// toTable converts an event to a Lua table
+func toTable(L *lua.LState, event Event) *lua.LTable {
+	table := L.NewTable()
+	for key, value := range event {
+		switch v := value.(type) {
+		case string:
+			table.RawSetString(key, lua.LString(v))
+		case int:
+			table.RawSetString(key, lua.LNumber(v))
+		case int64:
+			table.RawSetString(key, lua.LNumber(v))
+		case float64:
+			table.RawSetString(key, lua.LNumber(v))
+		case bool:
+			table.RawSetString(key, lua.LBool(v))
+		}
+	}
+	return table
+}
+
+// joinArgs joins the arguments of a call like print does
+func joinArgs(L *lua.LState) string {
+	parts := make([]string, 0, L.GetTop())
+	for i := 1; i <= L.GetTop(); i++ {
+		parts = append(parts, L.ToStringMeta(L.Get(i)).String())
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,107 @@
+package scripting
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHost keeps what the scripts asked for
+type recordingHost struct {
+	mu        sync.Mutex
+	downloads []DownloadRequest
+	notes     []string
+}
+
+func (h *recordingHost) Download(req DownloadRequest) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.downloads = append(h.downloads, req)
+	return nil
+}
+
+func (h *recordingHost) Notify(script, text string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.notes = append(h.notes, script+": "+text)
+}
+
+func writeScript(t *testing.T, dir, name, source string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(source), 0644))
+}
+
+func TestDownloadNextEpisodes(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "next.lua", `
+greg.on("episode_completed", function(ev)
+  if not ev.title:find("Frieren") then return end
+  for i = 1, 2 do
+    local ok, err = greg.download(ev, { episode = ev.episode + i, quality = "720p" })
+    if not ok then greg.notify(err) end
+  end
+  greg.notify("queued after " .. ev.episode)
+end)
+`)
+
+	host := &recordingHost{}
+	engine, err := Load(dir, host, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"next.lua"}, engine.Scripts())
+
+	engine.Emit(EventEpisodeCompleted, Event{"title": "Bocchi the Rock!", "provider": "allanime", "media_id": "b", "episode": 3})
+	engine.Emit(EventEpisodeCompleted, Event{"title": "Frieren", "provider": "allanime", "media_id": "f", "season": 1, "episode": 4, "completed": true})
+	engine.Emit(EventPlaybackStarted, Event{"title": "Frieren", "episode": 5})
+	engine.Close()
+
+	require.Len(t, host.downloads, 2)
+	assert.Equal(t, DownloadRequest{Script: "next.lua", Provider: "allanime", MediaID: "f", Title: "Frieren", Season: 1, Episode: 5, Quality: "720p"}, host.downloads[0])
+	assert.Equal(t, 6, host.downloads[1].Episode)
+	assert.Equal(t, []string{"next.lua: queued after 4"}, host.notes)
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "broken.lua", `greg.on("episode_completed", function(ev)`)
+	writeScript(t, dir, "typo.lua", `greg.on("episode_complete", function(ev) end)`)
+	writeScript(t, dir, "io.lua", `io.open("/etc/passwd")`)
+	writeScript(t, dir, "ok.lua", `greg.on("search", function(ev) end)`)
+	writeScript(t, dir, "notes.txt", `not a script`)
+
+	engine, err := Load(dir, &recordingHost{}, slog.Default())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken.lua")
+	assert.Contains(t, err.Error(), "unknown event")
+	assert.Contains(t, err.Error(), "io.lua")
+	assert.Equal(t, []string{"ok.lua"}, engine.Scripts())
+	engine.Close()
+}
+
+func TestHookErrorsAreReported(t *testing.T) {
+	defer func(timeout time.Duration) { hookTimeout = timeout }(hookTimeout)
+	hookTimeout = 100 * time.Millisecond
+
+	dir := t.TempDir()
+	writeScript(t, dir, "loop.lua", `
+greg.on("search", function(ev) while true do end end)
+greg.on("download_failed", function(ev) greg.download({ provider = ev.provider }) error("boom " .. ev.error) end)
+`)
+
+	host := &recordingHost{}
+	engine, err := Load(dir, host, slog.Default())
+	require.NoError(t, err)
+	engine.Emit(EventSearch, Event{"query": "frieren"})
+	engine.Emit(EventDownloadFailed, Event{"provider": "allanime", "error": "404"})
+	engine.Close()
+
+	assert.Empty(t, host.downloads, "a download without a show or episode is refused")
+	require.Len(t, host.notes, 2)
+	assert.Contains(t, host.notes[0], "search hook failed")
+	assert.Contains(t, host.notes[1], "boom 404")
+}
@@ -0,0 +1,251 @@
+package torrentclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+// qbittorrent talks to the qBittorrent Web API (v2)
+type qbittorrent struct {
+	cfg   config.TorrentConfig
+	paths pathMap
+	http  *http.Client
+
+	mu  sync.Mutex
+	sid string // Session cookie
+}
+
+func (q *qbittorrent) Name() string {
+	return QBittorrent
+}
+
+func (q *qbittorrent) endpoint(method string) string {
+	return strings.TrimSuffix(q.cfg.URL, "/") + "/api/v2/" + method
+}
+
+// login starts a session; without a username the client must allow
+// unauthenticated access (e.g. bypass for localhost)
+func (q *qbittorrent) login(ctx context.Context) error {
+	if q.cfg.Username == "" {
+		return nil
+	}
+	form := url.Values{"username": {q.cfg.Username}, "password": {q.cfg.Password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.endpoint("auth/login"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", q.cfg.URL)
+	resp, err := q.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach qBittorrent: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("qBittorrent login failed: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "SID" {
+			q.mu.Lock()
+			q.sid = cookie.Value
+			q.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// do sends a request, logging in first and again when the session expired
+func (q *qbittorrent) do(ctx context.Context, newRequest func() (*http.Request, error)) ([]byte, error) {
+	q.mu.Lock()
+	sid := q.sid
+	q.mu.Unlock()
+	if sid == "" {
+		if err := q.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Referer", q.cfg.URL)
+		q.mu.Lock()
+		if q.sid != "" {
+			req.AddCookie(&http.Cookie{Name: "SID", Value: q.sid})
+		}
+		q.mu.Unlock()
+
+		resp, err := q.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach qBittorrent: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read qBittorrent response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusForbidden && attempt == 0 && q.cfg.Username != "" {
+			if err := q.login(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("qBittorrent answered %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		return body, nil
+	}
+}
+
+func (q *qbittorrent) Add(ctx context.Context, link string) (string, error) {
+	var hash string
+	var data []byte
+	var err error
+	if strings.HasPrefix(link, "magnet:") {
+		hash, err = MagnetHash(link)
+	} else {
+		// Upload the file so the client doesn't need to reach the tracker's site
+		data, hash, err = fetchTorrent(ctx, q.http, link)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	body, err := q.do(ctx, func() (*http.Request, error) {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		if data != nil {
+			part, err := w.CreateFormFile("torrents", path.Base(strings.SplitN(link, "?", 2)[0]))
+			if err != nil {
+				return nil, err
+			}
+			if _, err := part.Write(data); err != nil {
+				return nil, err
+			}
+		} else {
+			_ = w.WriteField("urls", link)
+		}
+		if q.cfg.Category != "" {
+			_ = w.WriteField("category", q.cfg.Category)
+		}
+		if q.cfg.SavePath != "" {
+			_ = w.WriteField("savepath", q.cfg.SavePath)
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.endpoint("torrents/add"), &buf)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// "Fails." is also the answer for a torrent the client already has
+	if strings.TrimSpace(string(body)) == "Fails." {
+		existing, err := q.Torrents(ctx, []string{hash})
+		if err != nil || len(existing) == 0 {
+			return "", fmt.Errorf("qBittorrent refused the torrent")
+		}
+	}
+	return hash, nil
+}
+
+// qbTorrent is an entry of /torrents/info
+type qbTorrent struct {
+	Hash     string  `json:"hash"`
+	Name     string  `json:"name"`
+	Progress float64 `json:"progress"`
+	State    string  `json:"state"`
+	SavePath string  `json:"save_path"`
+}
+
+// qbFile is an entry of /torrents/files
+type qbFile struct {
+	Name     string `json:"name"` // Relative to the save path
+	Priority int    `json:"priority"`
+}
+
+func (q *qbittorrent) Torrents(ctx context.Context, hashes []string) ([]Torrent, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	params := url.Values{"hashes": {strings.Join(hashes, "|")}}
+	body, err := q.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, q.endpoint("torrents/info")+"?"+params.Encode(), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var infos []qbTorrent
+	if err := json.Unmarshal(body, &infos); err != nil {
+		return nil, fmt.Errorf("failed to decode qBittorrent torrents: %w", err)
+	}
+
+	torrents := make([]Torrent, 0, len(infos))
+	for _, info := range infos {
+		t := Torrent{
+			Hash:     strings.ToLower(info.Hash),
+			Name:     info.Name,
+			Progress: info.Progress,
+			Done:     info.Progress >= 1,
+		}
+		switch info.State {
+		case "error", "missingFiles":
+			t.Error = info.State
+		}
+		if t.Done {
+			files, err := q.files(ctx, info)
+			if err != nil {
+				return nil, err
+			}
+			t.Files = files
+		}
+		torrents = append(torrents, t)
+	}
+	return torrents, nil
+}
+
+// files returns the wanted files of a torrent
+func (q *qbittorrent) files(ctx context.Context, info qbTorrent) ([]string, error) {
+	params := url.Values{"hash": {info.Hash}}
+	body, err := q.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, q.endpoint("torrents/files")+"?"+params.Encode(), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var entries []qbFile
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode qBittorrent files: %w", err)
+	}
+
+	var files []string
+	for _, f := range entries {
+		if f.Priority == 0 {
+			continue // Not downloaded
+		}
+		files = append(files, q.paths.toLocal(path.Join(info.SavePath, f.Name)))
+	}
+	return files, nil
+}
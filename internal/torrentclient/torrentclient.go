@@ -0,0 +1,241 @@
+// Package torrentclient hands torrents to a qBittorrent or Transmission
+// instance over its web API and reports their progress, so greg can import
+// the files once they finish.
+package torrentclient
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+// Supported clients
+const (
+	QBittorrent  = "qbittorrent"
+	Transmission = "transmission"
+)
+
+// maxTorrentSize bounds the .torrent files downloaded to compute their hash
+const maxTorrentSize = 10 << 20
+
+// Torrent is the state of a torrent in the client
+type Torrent struct {
+	Hash     string
+	Name     string
+	Progress float64  // 0-1
+	Done     bool     // Every wanted file is complete
+	Error    string   // Set when the client reports a problem
+	Files    []string // Paths of the files, as seen by greg
+}
+
+// Client is a torrent client greg hands torrents to
+type Client interface {
+	// Name returns the client kind, QBittorrent or Transmission
+	Name() string
+	// Add starts a magnet link or .torrent URL and returns its info hash
+	Add(ctx context.Context, link string) (string, error)
+	// Torrents returns the torrents with the given hashes; missing ones are
+	// left out
+	Torrents(ctx context.Context, hashes []string) ([]Torrent, error)
+}
+
+// New creates the client configured in cfg
+func New(cfg config.TorrentConfig) (Client, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no torrent client address (set downloads.torrent.url)")
+	}
+	paths := pathMap{remote: cfg.RemotePath, local: cfg.LocalPath}
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	switch strings.ToLower(cfg.Client) {
+	case QBittorrent:
+		return &qbittorrent{cfg: cfg, paths: paths, http: httpClient}, nil
+	case Transmission:
+		return &transmission{cfg: cfg, paths: paths, http: httpClient}, nil
+	default:
+		return nil, fmt.Errorf("unknown torrent client %q (use qbittorrent or transmission)", cfg.Client)
+	}
+}
+
+// IsTorrent reports whether a link must go to a torrent client
+func IsTorrent(link string) bool {
+	if strings.HasPrefix(link, "magnet:") {
+		return true
+	}
+	u := strings.SplitN(link, "?", 2)[0]
+	return strings.HasSuffix(strings.ToLower(u), ".torrent")
+}
+
+// MagnetHash returns the hex info hash of a magnet link
+func MagnetHash(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "magnet" {
+		return "", fmt.Errorf("not a magnet link")
+	}
+	for _, xt := range u.Query()["xt"] {
+		hash, ok := strings.CutPrefix(xt, "urn:btih:")
+		if !ok {
+			continue
+		}
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err == nil {
+				return strings.ToLower(hash), nil
+			}
+		case 32:
+			if b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+				return hex.EncodeToString(b), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("magnet link has no info hash")
+}
+
+// MagnetName returns the display name of a magnet link, if it has one
+func MagnetName(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("dn")
+}
+
+// fetchTorrent downloads a .torrent file and returns it with its info hash
+func fetchTorrent(ctx context.Context, client *http.Client, link string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download torrent: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download torrent: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTorrentSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download torrent: %w", err)
+	}
+	hash, err := InfoHash(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, hash, nil
+}
+
+// InfoHash returns the hex SHA-1 of the info dictionary of a .torrent file
+func InfoHash(data []byte) (string, error) {
+	if len(data) == 0 || data[0] != 'd' {
+		return "", errBadTorrent
+	}
+	pos := 1
+	for pos < len(data) && data[pos] != 'e' {
+		key, next, err := bencodeString(data, pos)
+		if err != nil {
+			return "", err
+		}
+		end, err := bencodeSkip(data, next)
+		if err != nil {
+			return "", err
+		}
+		if key == "info" {
+			sum := sha1.Sum(data[next:end])
+			return hex.EncodeToString(sum[:]), nil
+		}
+		pos = end
+	}
+	return "", fmt.Errorf("%w: no info dictionary", errBadTorrent)
+}
+
+var errBadTorrent = errors.New("invalid torrent file")
+
+// bencodeString reads the string at pos and returns it with the position
+// after it
+func bencodeString(data []byte, pos int) (string, int, error) {
+	colon := pos
+	for colon < len(data) && data[colon] >= '0' && data[colon] <= '9' {
+		colon++
+	}
+	if colon == pos || colon >= len(data) || data[colon] != ':' {
+		return "", 0, errBadTorrent
+	}
+	n := 0
+	for _, c := range data[pos:colon] {
+		n = n*10 + int(c-'0')
+		if n > len(data) {
+			return "", 0, errBadTorrent
+		}
+	}
+	end := colon + 1 + n
+	if end > len(data) {
+		return "", 0, errBadTorrent
+	}
+	return string(data[colon+1 : end]), end, nil
+}
+
+// bencodeSkip returns the position after the value at pos
+func bencodeSkip(data []byte, pos int) (int, error) {
+	if pos >= len(data) {
+		return 0, errBadTorrent
+	}
+	switch c := data[pos]; {
+	case c == 'i':
+		end := pos + 1
+		for end < len(data) && data[end] != 'e' {
+			end++
+		}
+		if end >= len(data) {
+			return 0, errBadTorrent
+		}
+		return end + 1, nil
+	case c == 'l' || c == 'd':
+		pos++
+		for pos < len(data) && data[pos] != 'e' {
+			next, err := bencodeSkip(data, pos)
+			if err != nil {
+				return 0, err
+			}
+			pos = next
+		}
+		if pos >= len(data) {
+			return 0, errBadTorrent
+		}
+		return pos + 1, nil
+	case c >= '0' && c <= '9':
+		_, end, err := bencodeString(data, pos)
+		return end, err
+	default:
+		return 0, errBadTorrent
+	}
+}
+
+// pathMap translates the client's download paths to greg's, for clients
+// running in a container or on another machine with the folder mounted
+type pathMap struct {
+	remote string
+	local  string
+}
+
+func (p pathMap) toLocal(path string) string {
+	if p.remote == "" || p.local == "" {
+		return path
+	}
+	rest, ok := strings.CutPrefix(path, strings.TrimSuffix(p.remote, "/"))
+	if !ok || (rest != "" && rest[0] != '/') {
+		return path
+	}
+	return filepath.Join(p.local, filepath.FromSlash(rest))
+}
@@ -0,0 +1,168 @@
+package torrentclient
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+const hash = "0123456789abcdef0123456789abcdef01234567"
+
+func TestMagnetHash(t *testing.T) {
+	got, err := MagnetHash("magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567&dn=Show")
+	require.NoError(t, err)
+	assert.Equal(t, hash, got)
+
+	// Base32 form of the same hash
+	got, err = MagnetHash("magnet:?xt=urn:btih:AERUKZ4JVPG66AJDIVTYTK6N54ASGRLH")
+	require.NoError(t, err)
+	assert.Equal(t, hash, got)
+
+	_, err = MagnetHash("magnet:?dn=Show")
+	assert.Error(t, err)
+}
+
+func TestInfoHash(t *testing.T) {
+	info := "d6:lengthi5e4:name8:show.mkv12:piece lengthi16384ee"
+	data := []byte("d8:announce3:url13:creation datei1700000000e4:info" + info + "e")
+
+	got, err := InfoHash(data)
+	require.NoError(t, err)
+	sum := sha1.Sum([]byte(info))
+	assert.Equal(t, hex.EncodeToString(sum[:]), got)
+
+	_, err = InfoHash([]byte("d8:announce3:urle"))
+	assert.Error(t, err)
+	_, err = InfoHash([]byte("d4:infod"))
+	assert.Error(t, err)
+}
+
+func TestPathMap(t *testing.T) {
+	p := pathMap{remote: "/downloads", local: "/mnt/nas/torrents"}
+	assert.Equal(t, "/mnt/nas/torrents/Show/ep.mkv", p.toLocal("/downloads/Show/ep.mkv"))
+	assert.Equal(t, "/downloads-old/ep.mkv", p.toLocal("/downloads-old/ep.mkv"))
+	assert.Equal(t, "/data/ep.mkv", pathMap{}.toLocal("/data/ep.mkv"))
+}
+
+func TestQBittorrent(t *testing.T) {
+	var added map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("username") != "admin" || r.FormValue("password") != "secret" {
+			_, _ = io.WriteString(w, "Fails.")
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session"})
+		_, _ = io.WriteString(w, "Ok.")
+	})
+	authed := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if c, err := r.Cookie("SID"); err != nil || c.Value != "session" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("/api/v2/torrents/add", authed(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		added = map[string]string{"urls": r.FormValue("urls"), "category": r.FormValue("category")}
+		_, _ = io.WriteString(w, "Ok.")
+	}))
+	mux.HandleFunc("/api/v2/torrents/info", authed(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, hash, r.URL.Query().Get("hashes"))
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"hash": hash, "name": "Show - 05", "progress": 1.0, "state": "stalledUP", "save_path": "/downloads"},
+		})
+	}))
+	mux.HandleFunc("/api/v2/torrents/files", authed(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"name": "Show - 05/Show - 05.mkv", "priority": 1},
+			{"name": "Show - 05/extras.mkv", "priority": 0},
+		})
+	}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := New(config.TorrentConfig{
+		Client: "qBittorrent", URL: server.URL, Username: "admin", Password: "secret", Category: "greg",
+		RemotePath: "/downloads", LocalPath: "/mnt/torrents",
+	})
+	require.NoError(t, err)
+
+	magnet := "magnet:?xt=urn:btih:" + hash + "&dn=Show+-+05"
+	got, err := client.Add(context.Background(), magnet)
+	require.NoError(t, err)
+	assert.Equal(t, hash, got)
+	assert.Equal(t, map[string]string{"urls": magnet, "category": "greg"}, added)
+
+	torrents, err := client.Torrents(context.Background(), []string{hash})
+	require.NoError(t, err)
+	require.Len(t, torrents, 1)
+	assert.True(t, torrents[0].Done)
+	assert.Equal(t, []string{"/mnt/torrents/Show - 05/Show - 05.mkv"}, torrents[0].Files)
+}
+
+func TestTransmission(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/transmission/rpc", r.URL.Path)
+		if r.Header.Get(sessionHeader) != "token" {
+			w.Header().Set(sessionHeader, "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		var req struct {
+			Method    string         `json:"method"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		methods = append(methods, req.Method)
+
+		switch req.Method {
+		case "torrent-add":
+			assert.Equal(t, []any{"greg"}, req.Arguments["labels"])
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"result":    "success",
+				"arguments": map[string]any{"torrent-duplicate": map[string]any{"hashString": hash}},
+			})
+		case "torrent-get":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"result": "success",
+				"arguments": map[string]any{"torrents": []map[string]any{{
+					"hashString": hash, "name": "Show - 05", "percentDone": 1.0, "leftUntilDone": 0,
+					"downloadDir": "/data", "error": 0,
+					"files": []map[string]any{
+						{"name": "Show - 05.mkv", "length": 10, "bytesCompleted": 10},
+						{"name": "Sample.mkv", "length": 10, "bytesCompleted": 0},
+					},
+				}}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(config.TorrentConfig{Client: "transmission", URL: server.URL, Category: "greg"})
+	require.NoError(t, err)
+
+	got, err := client.Add(context.Background(), "magnet:?xt=urn:btih:"+hash)
+	require.NoError(t, err)
+	assert.Equal(t, hash, got)
+
+	torrents, err := client.Torrents(context.Background(), []string{hash})
+	require.NoError(t, err)
+	require.Len(t, torrents, 1)
+	assert.True(t, torrents[0].Done)
+	assert.Equal(t, []string{"/data/Show - 05.mkv"}, torrents[0].Files)
+	assert.Equal(t, []string{"torrent-add", "torrent-get"}, methods)
+}
@@ -0,0 +1,203 @@
+package torrentclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+// sessionHeader carries Transmission's CSRF token
+const sessionHeader = "X-Transmission-Session-Id"
+
+// transmission talks to the Transmission RPC API
+type transmission struct {
+	cfg   config.TorrentConfig
+	paths pathMap
+	http  *http.Client
+
+	mu      sync.Mutex
+	session string
+}
+
+func (t *transmission) Name() string {
+	return Transmission
+}
+
+// endpoint returns the RPC URL; a bare address gets the default path
+func (t *transmission) endpoint() string {
+	u, err := url.Parse(t.cfg.URL)
+	if err == nil && (u.Path == "" || u.Path == "/") {
+		u.Path = "/transmission/rpc"
+		return u.String()
+	}
+	return t.cfg.URL
+}
+
+// call runs an RPC method and decodes its arguments into result
+func (t *transmission) call(ctx context.Context, method string, args any, result any) error {
+	payload, err := json.Marshal(map[string]any{"method": method, "arguments": args})
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint(), bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if t.cfg.Username != "" {
+			req.SetBasicAuth(t.cfg.Username, t.cfg.Password)
+		}
+		t.mu.Lock()
+		if t.session != "" {
+			req.Header.Set(sessionHeader, t.session)
+		}
+		t.mu.Unlock()
+
+		resp, err := t.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to reach Transmission: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read Transmission response: %w", err)
+		}
+
+		// A new or expired session answers 409 with the token to use
+		if resp.StatusCode == http.StatusConflict && attempt == 0 {
+			t.mu.Lock()
+			t.session = resp.Header.Get(sessionHeader)
+			t.mu.Unlock()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("transmission answered %s", resp.Status)
+		}
+
+		var reply struct {
+			Result    string          `json:"result"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(body, &reply); err != nil {
+			return fmt.Errorf("failed to decode Transmission response: %w", err)
+		}
+		if reply.Result != "success" {
+			return fmt.Errorf("transmission %s failed: %s", method, reply.Result)
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(reply.Arguments, result); err != nil {
+			return fmt.Errorf("failed to decode Transmission response: %w", err)
+		}
+		return nil
+	}
+}
+
+func (t *transmission) Add(ctx context.Context, link string) (string, error) {
+	args := map[string]any{}
+	if strings.HasPrefix(link, "magnet:") {
+		args["filename"] = link
+	} else {
+		// Upload the file so the client doesn't need to reach the tracker's site
+		data, _, err := fetchTorrent(ctx, t.http, link)
+		if err != nil {
+			return "", err
+		}
+		args["metainfo"] = base64.StdEncoding.EncodeToString(data)
+	}
+	if t.cfg.SavePath != "" {
+		args["download-dir"] = t.cfg.SavePath
+	}
+	if t.cfg.Category != "" {
+		args["labels"] = []string{t.cfg.Category}
+	}
+
+	type added struct {
+		HashString string `json:"hashString"`
+	}
+	var result struct {
+		Added     *added `json:"torrent-added"`
+		Duplicate *added `json:"torrent-duplicate"`
+	}
+	if err := t.call(ctx, "torrent-add", args, &result); err != nil {
+		return "", err
+	}
+	switch {
+	case result.Added != nil:
+		return strings.ToLower(result.Added.HashString), nil
+	case result.Duplicate != nil:
+		return strings.ToLower(result.Duplicate.HashString), nil
+	default:
+		return "", fmt.Errorf("transmission didn't return the added torrent")
+	}
+}
+
+// trTorrent is an entry of torrent-get
+type trTorrent struct {
+	HashString    string  `json:"hashString"`
+	Name          string  `json:"name"`
+	PercentDone   float64 `json:"percentDone"`
+	LeftUntilDone int64   `json:"leftUntilDone"`
+	DownloadDir   string  `json:"downloadDir"`
+	Error         int     `json:"error"`
+	ErrorString   string  `json:"errorString"`
+	Files         []struct {
+		Name           string `json:"name"` // Relative to the download dir
+		Length         int64  `json:"length"`
+		BytesCompleted int64  `json:"bytesCompleted"`
+	} `json:"files"`
+}
+
+func (t *transmission) Torrents(ctx context.Context, hashes []string) ([]Torrent, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	args := map[string]any{
+		"ids":    hashes,
+		"fields": []string{"hashString", "name", "percentDone", "leftUntilDone", "downloadDir", "error", "errorString", "files"},
+	}
+	var result struct {
+		Torrents []trTorrent `json:"torrents"`
+	}
+	if err := t.call(ctx, "torrent-get", args, &result); err != nil {
+		return nil, err
+	}
+
+	torrents := make([]Torrent, 0, len(result.Torrents))
+	for _, info := range result.Torrents {
+		torrent := Torrent{
+			Hash:     strings.ToLower(info.HashString),
+			Name:     info.Name,
+			Progress: info.PercentDone,
+			Done:     info.PercentDone >= 1 && info.LeftUntilDone == 0,
+		}
+		// Errors 1 and 2 are tracker warnings and errors, the download goes on
+		if info.Error == 3 {
+			torrent.Error = info.ErrorString
+		}
+		if torrent.Done {
+			for _, f := range info.Files {
+				// Files not wanted aren't complete
+				if f.BytesCompleted < f.Length {
+					continue
+				}
+				torrent.Files = append(torrent.Files, t.paths.toLocal(path.Join(info.DownloadDir, f.Name)))
+			}
+		}
+		torrents = append(torrents, torrent)
+	}
+	return torrents, nil
+}
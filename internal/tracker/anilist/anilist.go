@@ -212,12 +212,15 @@ func (c *Client) GetUserLibrary(ctx context.Context, mediaType providers.MediaTy
 					id
 					status
 					score
+					scoreTen: score(format: POINT_10_DECIMAL)
 					progress
+					customLists(asArray: false)
 					startedAt { year month day }
 					completedAt { year month day }
 					updatedAt
 					media {
 						id
+						idMal
 						title {
 							romaji
 							english
@@ -230,6 +233,13 @@ func (c *Client) GetUserLibrary(ctx context.Context, mediaType providers.MediaTy
 							large
 						}
 						type
+						status
+						averageScore
+						genres
+						nextAiringEpisode {
+							episode
+							airingAt
+						}
 					}
 				}
 			}
@@ -256,9 +266,15 @@ func (c *Client) GetUserLibrary(ctx context.Context, mediaType providers.MediaTy
 		return nil, err
 	}
 
+	// Entries in custom lists are listed once per list they belong to
 	var result []tracker.TrackedMedia
+	seen := make(map[int]bool)
 	for _, list := range response.Data.MediaListCollection.Lists {
 		for _, entry := range list.Entries {
+			if seen[entry.ID] {
+				continue
+			}
+			seen[entry.ID] = true
 			tracked := entryToTrackedMedia(entry)
 			result = append(result, tracked)
 		}
@@ -456,7 +472,10 @@ func (c *Client) SearchMedia(ctx context.Context, query string, mediaType provid
 			Status:        status,
 			Score:         float64(media.AverageScore) / 10.0,
 			StartDate:     media.StartDate.ToTime(),
+			AiringStatus:  media.Status,
+			Genres:        media.Genres,
 		}
+		trackedMedia.NextEpisode, trackedMedia.NextAiringAt = media.NextAiringEpisode.schedule()
 
 		// Additional fields that might be useful
 		if media.StartDate.Year > 0 {
@@ -475,6 +494,88 @@ func (c *Client) SearchMedia(ctx context.Context, query string, mediaType provid
 	return result, nil
 }
 
+// GetMediaByID looks up a single anime/manga by AniList ID, or by MyAnimeList ID when mal is true
+func (c *Client) GetMediaByID(ctx context.Context, id int, mal bool, mediaType providers.MediaType) (*tracker.TrackedMedia, error) {
+	graphqlQuery := `
+	query($id: Int, $idMal: Int, $type: MediaType) {
+		Media(id: $id, idMal: $idMal, type: $type) {
+			id
+			title {
+				userPreferred
+				romaji
+				english
+				native
+			}
+			coverImage {
+				extraLarge
+			}
+			description
+			type
+			status
+			episodes
+			chapters
+			nextAiringEpisode {
+				episode
+				airingAt
+			}
+			mediaListEntry {
+				id
+				status
+			}
+		}
+	}
+	`
+
+	variables := map[string]interface{}{
+		"type": anilistMediaType(mediaType),
+	}
+	if mal {
+		variables["idMal"] = id
+	} else {
+		variables["id"] = id
+	}
+
+	var response struct {
+		Data struct {
+			Media *anilistMedia `json:"Media"`
+		} `json:"data"`
+	}
+
+	if err := c.query(ctx, graphqlQuery, variables, &response); err != nil {
+		return nil, err
+	}
+
+	media := response.Data.Media
+	if media == nil {
+		return nil, fmt.Errorf("media %d not found", id)
+	}
+
+	totalUnits := media.Episodes
+	if media.Type == "MANGA" {
+		totalUnits = media.Chapters
+	}
+
+	trackedMedia := &tracker.TrackedMedia{
+		ServiceID:     fmt.Sprintf("%d", media.ID),
+		Title:         getBestTitle(media.Title),
+		Type:          mediaType,
+		TotalEpisodes: totalUnits,
+		Synopsis:      media.Description,
+		PosterURL:     media.CoverImage.ExtraLarge,
+		Status:        tracker.StatusPlanToWatch,
+		AiringStatus:  media.Status,
+	}
+	trackedMedia.NextEpisode, trackedMedia.NextAiringAt = media.NextAiringEpisode.schedule()
+	if media.MediaListEntry != nil {
+		trackedMedia.ListEntryID = media.MediaListEntry.ID
+		if status, err := tracker.ParseWatchStatus(mapAniListStatus(media.MediaListEntry.Status)); err == nil {
+			trackedMedia.Status = status
+		}
+	}
+
+	return trackedMedia, nil
+}
+
 // UpdateProgress updates the watch progress for a media item
 func (c *Client) UpdateProgress(ctx context.Context, mediaID string, episode int, progress float64) error {
 	if !c.IsAuthenticated() {
@@ -633,6 +734,38 @@ func (c *Client) UpdateScore(ctx context.Context, mediaID string, score float64)
 	return c.query(ctx, mutation, variables, &response)
 }
 
+// UpdateVolumeProgress sets the number of volumes read for a manga
+func (c *Client) UpdateVolumeProgress(ctx context.Context, mediaID string, volumes int) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("not authenticated")
+	}
+
+	mutation := `
+	mutation ($mediaId: Int, $progressVolumes: Int) {
+		SaveMediaListEntry(mediaId: $mediaId, progressVolumes: $progressVolumes) {
+			id
+			progressVolumes
+		}
+	}
+	`
+
+	variables := map[string]interface{}{
+		"mediaId":         mustParseInt(mediaID),
+		"progressVolumes": volumes,
+	}
+
+	var response struct {
+		Data struct {
+			SaveMediaListEntry struct {
+				ID              int `json:"id"`
+				ProgressVolumes int `json:"progressVolumes"`
+			} `json:"SaveMediaListEntry"`
+		} `json:"data"`
+	}
+
+	return c.query(ctx, mutation, variables, &response)
+}
+
 // UpdateDates updates the start and end dates for a media item
 func (c *Client) UpdateDates(ctx context.Context, mediaID string, startDate, endDate *time.Time) error {
 	if !c.IsAuthenticated() {
@@ -106,3 +106,25 @@ func TestGetAuthURL(t *testing.T) {
 		t.Errorf("Auth URL seems too short: %s", authURL)
 	}
 }
+
+func TestEntryToTrackedMediaAiring(t *testing.T) {
+	entry := anilistEntry{Status: "CURRENT"}
+	entry.Media.Type = "ANIME"
+	entry.Media.Status = "RELEASING"
+	entry.Media.NextAiringEpisode = &anilistAiringEpisode{Episode: 9, AiringAt: 1767225600}
+
+	media := entryToTrackedMedia(entry)
+	next, at := media.NextAiring()
+	if next != 9 {
+		t.Errorf("expected next episode 9, got %d", next)
+	}
+	if !at.Equal(time.Unix(1767225600, 0)) {
+		t.Errorf("expected airing time %v, got %v", time.Unix(1767225600, 0), at)
+	}
+
+	entry.Media.Status = "FINISHED"
+	entry.Media.NextAiringEpisode = nil
+	if next, _ := entryToTrackedMedia(entry).NextAiring(); next != 0 {
+		t.Errorf("expected no next episode for a finished show, got %d", next)
+	}
+}
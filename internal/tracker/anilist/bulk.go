@@ -0,0 +1,152 @@
+package anilist
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+// bulkChunkSize caps the aliased mutations sent in one request, keeping it
+// under AniList's query complexity limit
+const bulkChunkSize = 25
+
+// BulkUpdateStatus sets the status of several list entries in one mutation
+func (c *Client) BulkUpdateStatus(ctx context.Context, entryIDs []int, status tracker.WatchStatus) error {
+	mutation := `
+	mutation ($ids: [Int], $status: MediaListStatus) {
+		UpdateMediaListEntries(ids: $ids, status: $status) {
+			id
+		}
+	}
+	`
+	return c.updateEntries(ctx, mutation, entryIDs, map[string]interface{}{
+		"status": anilistStatus(status),
+	})
+}
+
+// BulkUpdateScore sets the score of several list entries in one mutation
+func (c *Client) BulkUpdateScore(ctx context.Context, entryIDs []int, score float64) error {
+	mutation := `
+	mutation ($ids: [Int], $score: Float) {
+		UpdateMediaListEntries(ids: $ids, score: $score) {
+			id
+		}
+	}
+	`
+	return c.updateEntries(ctx, mutation, entryIDs, map[string]interface{}{
+		"score": score,
+	})
+}
+
+// updateEntries runs an UpdateMediaListEntries mutation for entryIDs
+func (c *Client) updateEntries(ctx context.Context, mutation string, entryIDs []int, variables map[string]interface{}) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("not authenticated")
+	}
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	variables["ids"] = entryIDs
+
+	var response struct {
+		Data struct {
+			UpdateMediaListEntries []struct {
+				ID int `json:"id"`
+			} `json:"UpdateMediaListEntries"`
+		} `json:"data"`
+	}
+
+	if err := c.query(ctx, mutation, variables, &response); err != nil {
+		return fmt.Errorf("failed to update list entries: %w", err)
+	}
+	return nil
+}
+
+// BulkDelete removes several list entries. AniList has no bulk delete, so
+// the deletes are sent as aliased mutations, bulkChunkSize per request.
+func (c *Client) BulkDelete(ctx context.Context, entryIDs []int) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("not authenticated")
+	}
+
+	for start := 0; start < len(entryIDs); start += bulkChunkSize {
+		chunk := entryIDs[start:min(start+bulkChunkSize, len(entryIDs))]
+
+		var b strings.Builder
+		b.WriteString("mutation {\n")
+		for i, id := range chunk {
+			fmt.Fprintf(&b, "\te%d: DeleteMediaListEntry(id: %d) { deleted }\n", i, id)
+		}
+		b.WriteString("}")
+
+		var response struct {
+			Data map[string]struct {
+				Deleted bool `json:"deleted"`
+			} `json:"data"`
+		}
+		if err := c.query(ctx, b.String(), nil, &response); err != nil {
+			return fmt.Errorf("failed to delete list entries: %w", err)
+		}
+		for i, id := range chunk {
+			if !response.Data[fmt.Sprintf("e%d", i)].Deleted {
+				return fmt.Errorf("failed to delete list entry %d", id)
+			}
+		}
+	}
+	return nil
+}
+
+// AddToCustomList adds several media to a custom list, keeping the other
+// custom lists they are in. AniList only replaces the whole set of custom
+// lists per entry, so the saves are sent as aliased mutations.
+func (c *Client) AddToCustomList(ctx context.Context, media []tracker.TrackedMedia, list string) error {
+	if !c.IsAuthenticated() {
+		return fmt.Errorf("not authenticated")
+	}
+
+	// Skip media already in the list
+	var pending []tracker.TrackedMedia
+	for _, m := range media {
+		if !m.CustomLists[list] {
+			pending = append(pending, m)
+		}
+	}
+
+	for start := 0; start < len(pending); start += bulkChunkSize {
+		chunk := pending[start:min(start+bulkChunkSize, len(pending))]
+
+		var params, body strings.Builder
+		variables := make(map[string]interface{}, len(chunk))
+		for i, m := range chunk {
+			lists := []string{list}
+			for name, in := range m.CustomLists {
+				if in && name != list {
+					lists = append(lists, name)
+				}
+			}
+			sort.Strings(lists)
+
+			if i > 0 {
+				params.WriteString(", ")
+			}
+			fmt.Fprintf(&params, "$lists%d: [String]", i)
+			fmt.Fprintf(&body, "\ts%d: SaveMediaListEntry(mediaId: %d, customLists: $lists%d) { id }\n", i, mustParseInt(m.ServiceID), i)
+			variables[fmt.Sprintf("lists%d", i)] = lists
+		}
+		mutation := fmt.Sprintf("mutation (%s) {\n%s}", params.String(), body.String())
+
+		var response struct {
+			Data map[string]struct {
+				ID int `json:"id"`
+			} `json:"data"`
+		}
+		if err := c.query(ctx, mutation, variables, &response); err != nil {
+			return fmt.Errorf("failed to add to custom list: %w", err)
+		}
+	}
+	return nil
+}
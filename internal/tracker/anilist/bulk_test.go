@@ -0,0 +1,115 @@
+package anilist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+// recordingTransport records GraphQL requests and answers them with respond
+type recordingTransport struct {
+	requests []map[string]interface{}
+	respond  func(query string) string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	t.requests = append(t.requests, payload)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(t.respond(payload["query"].(string)))),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func newBulkTestClient(transport *recordingTransport) *Client {
+	return &Client{
+		httpClient:  &http.Client{Transport: transport},
+		token:       &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour)},
+		lastRequest: time.Now().Add(-time.Minute),
+	}
+}
+
+func TestBulkUpdateStatusSingleMutation(t *testing.T) {
+	transport := &recordingTransport{respond: func(string) string {
+		return `{"data":{"UpdateMediaListEntries":[{"id":1},{"id":2},{"id":3}]}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	require.NoError(t, client.BulkUpdateStatus(context.Background(), []int{1, 2, 3}, tracker.StatusDropped))
+
+	require.Len(t, transport.requests, 1)
+	variables := transport.requests[0]["variables"].(map[string]interface{})
+	assert.Equal(t, "DROPPED", variables["status"])
+	assert.Len(t, variables["ids"], 3)
+}
+
+func TestBulkDeleteChunks(t *testing.T) {
+	transport := &recordingTransport{respond: func(query string) string {
+		var fields []string
+		for i := 0; i < strings.Count(query, "DeleteMediaListEntry"); i++ {
+			fields = append(fields, fmt.Sprintf(`"e%d":{"deleted":true}`, i))
+		}
+		return `{"data":{` + strings.Join(fields, ",") + `}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	ids := make([]int, bulkChunkSize+5)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	require.NoError(t, client.BulkDelete(context.Background(), ids))
+	assert.Len(t, transport.requests, 2)
+}
+
+func TestAddToCustomListKeepsOtherLists(t *testing.T) {
+	transport := &recordingTransport{respond: func(string) string {
+		return `{"data":{"s0":{"id":10}}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	media := []tracker.TrackedMedia{
+		{ServiceID: "1", CustomLists: map[string]bool{"Favourites": true, "Rewatch": false}},
+		{ServiceID: "2", CustomLists: map[string]bool{"Favourites": false, "Rewatch": true}},
+	}
+	require.NoError(t, client.AddToCustomList(context.Background(), media, "Rewatch"))
+
+	require.Len(t, transport.requests, 1)
+	variables := transport.requests[0]["variables"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"Favourites", "Rewatch"}, variables["lists0"])
+	assert.NotContains(t, variables, "lists1")
+}
+
+func TestUpdateVolumeProgress(t *testing.T) {
+	transport := &recordingTransport{respond: func(string) string {
+		return `{"data":{"SaveMediaListEntry":{"id":5,"progressVolumes":3}}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	require.NoError(t, client.UpdateVolumeProgress(context.Background(), "30013", 3))
+
+	require.Len(t, transport.requests, 1)
+	assert.Contains(t, transport.requests[0]["query"], "progressVolumes")
+	variables := transport.requests[0]["variables"].(map[string]interface{})
+	assert.EqualValues(t, 30013, variables["mediaId"])
+	assert.EqualValues(t, 3, variables["progressVolumes"])
+}
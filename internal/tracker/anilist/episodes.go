@@ -0,0 +1,298 @@
+package anilist
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamingTitle matches AniList streaming episode titles like
+// "Episode 3 - The Killing Magic"
+var streamingTitle = regexp.MustCompile(`(?i)^episode\s+(\d+)\s*(?:[-:]\s*(.*))?$`)
+
+// StreamingEpisode is an episode AniList lists from an official streaming site
+type StreamingEpisode struct {
+	Number    int // 0 when the title carries no episode number
+	Title     string
+	Thumbnail string
+}
+
+// GetStreamingEpisodes returns the episode titles and thumbnails AniList
+// lists from official streaming sites for an anime
+func (c *Client) GetStreamingEpisodes(ctx context.Context, id int) ([]StreamingEpisode, error) {
+	graphqlQuery := `
+	query($id: Int) {
+		Media(id: $id, type: ANIME) {
+			streamingEpisodes {
+				title
+				thumbnail
+			}
+		}
+	}
+	`
+
+	var response struct {
+		Data struct {
+			Media *struct {
+				StreamingEpisodes []struct {
+					Title     string `json:"title"`
+					Thumbnail string `json:"thumbnail"`
+				} `json:"streamingEpisodes"`
+			} `json:"Media"`
+		} `json:"data"`
+	}
+
+	if err := c.query(ctx, graphqlQuery, map[string]interface{}{"id": id}, &response); err != nil {
+		return nil, err
+	}
+	if response.Data.Media == nil {
+		return nil, fmt.Errorf("media %d not found", id)
+	}
+
+	episodes := make([]StreamingEpisode, 0, len(response.Data.Media.StreamingEpisodes))
+	for _, ep := range response.Data.Media.StreamingEpisodes {
+		episode := StreamingEpisode{Title: strings.TrimSpace(ep.Title), Thumbnail: ep.Thumbnail}
+		if match := streamingTitle.FindStringSubmatch(episode.Title); match != nil {
+			episode.Number, _ = strconv.Atoi(match[1])
+			episode.Title = strings.TrimSpace(match[2])
+		}
+		episodes = append(episodes, episode)
+	}
+	return episodes, nil
+}
+
+// Sequel is an entry of an anime's chain of sequels or prequels
+type Sequel struct {
+	ID       int
+	Title    string
+	Episodes int // 0 when unknown, e.g. still airing
+}
+
+// GetSequelChain returns an anime followed by its TV sequels in airing
+// order, at most limit entries. Spin-offs, movies and specials are skipped.
+func (c *Client) GetSequelChain(ctx context.Context, id, limit int) ([]Sequel, error) {
+	return c.followChain(ctx, id, limit, "SEQUEL")
+}
+
+// GetPrequelChain returns an anime followed by its TV prequels, the nearest
+// first, at most limit entries. Spin-offs, movies and specials are skipped.
+func (c *Client) GetPrequelChain(ctx context.Context, id, limit int) ([]Sequel, error) {
+	return c.followChain(ctx, id, limit, "PREQUEL")
+}
+
+// followChain follows an anime's TV, TV short and ONA relations of the
+// given type, at most limit entries including the anime itself
+func (c *Client) followChain(ctx context.Context, id, limit int, relation string) ([]Sequel, error) {
+	graphqlQuery := `
+	query($id: Int) {
+		Media(id: $id, type: ANIME) {
+			id
+			title {
+				userPreferred
+				romaji
+				english
+				native
+			}
+			episodes
+			relations {
+				edges {
+					relationType
+					node {
+						id
+						type
+						format
+					}
+				}
+			}
+		}
+	}
+	`
+
+	var chain []Sequel
+	seen := make(map[int]bool)
+	for id > 0 && len(chain) < limit && !seen[id] {
+		seen[id] = true
+
+		var response struct {
+			Data struct {
+				Media *struct {
+					ID        int          `json:"id"`
+					Title     anilistTitle `json:"title"`
+					Episodes  int          `json:"episodes"`
+					Relations struct {
+						Edges []struct {
+							RelationType string `json:"relationType"`
+							Node         struct {
+								ID     int    `json:"id"`
+								Type   string `json:"type"`
+								Format string `json:"format"`
+							} `json:"node"`
+						} `json:"edges"`
+					} `json:"relations"`
+				} `json:"Media"`
+			} `json:"data"`
+		}
+		if err := c.query(ctx, graphqlQuery, map[string]interface{}{"id": id}, &response); err != nil {
+			return chain, err
+		}
+		media := response.Data.Media
+		if media == nil {
+			return chain, fmt.Errorf("media %d not found", id)
+		}
+		chain = append(chain, Sequel{ID: media.ID, Title: getBestTitle(media.Title), Episodes: media.Episodes})
+
+		id = 0
+		for _, edge := range media.Relations.Edges {
+			if edge.RelationType != relation || edge.Node.Type != "ANIME" {
+				continue
+			}
+			switch edge.Node.Format {
+			case "TV", "TV_SHORT", "ONA":
+				id = edge.Node.ID
+			}
+			if id > 0 {
+				break
+			}
+		}
+	}
+	return chain, nil
+}
+
+// specialRelations are the relations of an anime its OVAs, specials and
+// movies hang off
+var specialRelations = map[string]bool{
+	"SIDE_STORY": true,
+	"SEQUEL":     true,
+	"PREQUEL":    true,
+	"SUMMARY":    true,
+}
+
+// Special is an OVA, special or movie attached to an anime
+type Special struct {
+	ID        int
+	MALID     int // 0 if unknown
+	Title     string
+	Format    string // OVA, SPECIAL, MOVIE or ONA
+	Relation  string // How it relates to the anime, e.g. SIDE_STORY
+	Episodes  int    // 0 when unknown
+	StartDate *time.Time
+	Status    string // The user's list status, empty when not on their list
+	Progress  int    // Episodes the user watched according to their list
+}
+
+// GetSpecials returns the OVAs, specials and movies related to an anime in
+// release order. ONAs only count as side stories, as ONA sequels are usually
+// the next season.
+func (c *Client) GetSpecials(ctx context.Context, id int) ([]Special, error) {
+	graphqlQuery := `
+	query($id: Int) {
+		Media(id: $id, type: ANIME) {
+			relations {
+				edges {
+					relationType(version: 2)
+					node {
+						id
+						idMal
+						type
+						format
+						episodes
+						title {
+							userPreferred
+							romaji
+							english
+							native
+						}
+						startDate {
+							year
+							month
+							day
+						}
+						mediaListEntry {
+							status
+							progress
+						}
+					}
+				}
+			}
+		}
+	}
+	`
+
+	var response struct {
+		Data struct {
+			Media *struct {
+				Relations struct {
+					Edges []struct {
+						RelationType string `json:"relationType"`
+						Node         struct {
+							ID             int          `json:"id"`
+							IDMal          int          `json:"idMal"`
+							Type           string       `json:"type"`
+							Format         string       `json:"format"`
+							Episodes       int          `json:"episodes"`
+							Title          anilistTitle `json:"title"`
+							StartDate      anilistDate  `json:"startDate"`
+							MediaListEntry *struct {
+								Status   string `json:"status"`
+								Progress int    `json:"progress"`
+							} `json:"mediaListEntry"`
+						} `json:"node"`
+					} `json:"edges"`
+				} `json:"relations"`
+			} `json:"Media"`
+		} `json:"data"`
+	}
+
+	if err := c.query(ctx, graphqlQuery, map[string]interface{}{"id": id}, &response); err != nil {
+		return nil, err
+	}
+	if response.Data.Media == nil {
+		return nil, fmt.Errorf("media %d not found", id)
+	}
+
+	var specials []Special
+	for _, edge := range response.Data.Media.Relations.Edges {
+		node := edge.Node
+		if node.Type != "ANIME" || !specialRelations[edge.RelationType] {
+			continue
+		}
+		switch node.Format {
+		case "OVA", "SPECIAL", "MOVIE":
+		case "ONA":
+			if edge.RelationType != "SIDE_STORY" {
+				continue
+			}
+		default:
+			continue
+		}
+
+		special := Special{
+			ID:        node.ID,
+			MALID:     node.IDMal,
+			Title:     getBestTitle(node.Title),
+			Format:    node.Format,
+			Relation:  edge.RelationType,
+			Episodes:  node.Episodes,
+			StartDate: node.StartDate.ToTime(),
+		}
+		if node.MediaListEntry != nil {
+			special.Status = node.MediaListEntry.Status
+			special.Progress = node.MediaListEntry.Progress
+		}
+		specials = append(specials, special)
+	}
+
+	// Unknown release dates last
+	sort.SliceStable(specials, func(i, j int) bool {
+		a, b := specials[i].StartDate, specials[j].StartDate
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+	return specials, nil
+}
@@ -0,0 +1,125 @@
+package anilist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStreamingEpisodes(t *testing.T) {
+	transport := &recordingTransport{respond: func(string) string {
+		return `{"data":{"Media":{"streamingEpisodes":[
+			{"title":"Episode 2 - It Didn't Have to Be Magic...","thumbnail":"https://img.example/2.jpg"},
+			{"title":"Episode 1 - The Journey's End","thumbnail":"https://img.example/1.jpg"},
+			{"title":"Recap Special","thumbnail":"https://img.example/recap.jpg"}
+		]}}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	episodes, err := client.GetStreamingEpisodes(context.Background(), 154587)
+	require.NoError(t, err)
+
+	require.Len(t, transport.requests, 1)
+	assert.Equal(t, float64(154587), transport.requests[0]["variables"].(map[string]interface{})["id"])
+	assert.Equal(t, []StreamingEpisode{
+		{Number: 2, Title: "It Didn't Have to Be Magic...", Thumbnail: "https://img.example/2.jpg"},
+		{Number: 1, Title: "The Journey's End", Thumbnail: "https://img.example/1.jpg"},
+		{Title: "Recap Special", Thumbnail: "https://img.example/recap.jpg"},
+	}, episodes)
+}
+
+func TestGetStreamingEpisodesNotFound(t *testing.T) {
+	transport := &recordingTransport{respond: func(string) string {
+		return `{"data":{"Media":null}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	_, err := client.GetStreamingEpisodes(context.Background(), 1)
+	assert.Error(t, err)
+}
+
+func TestGetSequelChain(t *testing.T) {
+	transport := &recordingTransport{}
+	transport.respond = func(string) string {
+		if len(transport.requests) == 1 {
+			return `{"data":{"Media":{"id":1,"title":{"english":"Show"},"episodes":25,"relations":{"edges":[
+				{"relationType":"PREQUEL","node":{"id":9,"type":"ANIME","format":"TV"}},
+				{"relationType":"SEQUEL","node":{"id":5,"type":"ANIME","format":"MOVIE"}},
+				{"relationType":"SEQUEL","node":{"id":2,"type":"ANIME","format":"TV"}}
+			]}}}}`
+		}
+		return `{"data":{"Media":{"id":2,"title":{"english":"Show Season 2"},"episodes":null,"relations":{"edges":[
+			{"relationType":"SEQUEL","node":{"id":3,"type":"ANIME","format":"TV"}}
+		]}}}}`
+	}
+	client := newBulkTestClient(transport)
+
+	chain, err := client.GetSequelChain(context.Background(), 1, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Sequel{
+		{ID: 1, Title: "Show", Episodes: 25},
+		{ID: 2, Title: "Show Season 2"},
+	}, chain)
+	require.Len(t, transport.requests, 2, "stops at the limit")
+	assert.Equal(t, float64(2), transport.requests[1]["variables"].(map[string]interface{})["id"])
+}
+
+func TestGetPrequelChain(t *testing.T) {
+	transport := &recordingTransport{}
+	transport.respond = func(string) string {
+		switch len(transport.requests) {
+		case 1:
+			return `{"data":{"Media":{"id":3,"title":{"english":"Show Part 3"},"episodes":12,"relations":{"edges":[
+				{"relationType":"PREQUEL","node":{"id":8,"type":"ANIME","format":"OVA"}},
+				{"relationType":"PREQUEL","node":{"id":2,"type":"ANIME","format":"TV"}},
+				{"relationType":"SEQUEL","node":{"id":4,"type":"ANIME","format":"TV"}}
+			]}}}}`
+		case 2:
+			return `{"data":{"Media":{"id":2,"title":{"english":"Show Part 2"},"episodes":13,"relations":{"edges":[
+				{"relationType":"PREQUEL","node":{"id":1,"type":"ANIME","format":"TV"}}
+			]}}}}`
+		}
+		return `{"data":{"Media":{"id":1,"title":{"english":"Show"},"episodes":12,"relations":{"edges":[]}}}}`
+	}
+	client := newBulkTestClient(transport)
+
+	chain, err := client.GetPrequelChain(context.Background(), 3, 8)
+	require.NoError(t, err)
+	assert.Equal(t, []Sequel{
+		{ID: 3, Title: "Show Part 3", Episodes: 12},
+		{ID: 2, Title: "Show Part 2", Episodes: 13},
+		{ID: 1, Title: "Show", Episodes: 12},
+	}, chain)
+}
+
+func TestGetSpecials(t *testing.T) {
+	transport := &recordingTransport{respond: func(string) string {
+		return `{"data":{"Media":{"relations":{"edges":[
+			{"relationType":"SEQUEL","node":{"id":2,"type":"ANIME","format":"TV","title":{"english":"Show Season 2"}}},
+			{"relationType":"SEQUEL","node":{"id":3,"idMal":30,"type":"ANIME","format":"MOVIE","episodes":1,"title":{"english":"Show: The Movie"},"startDate":{"year":2021,"month":7},
+				"mediaListEntry":{"status":"COMPLETED","progress":1}}},
+			{"relationType":"SIDE_STORY","node":{"id":4,"type":"ANIME","format":"OVA","episodes":2,"title":{"english":"Show OVA"},"startDate":{"year":2019,"month":3,"day":2}}},
+			{"relationType":"SEQUEL","node":{"id":5,"type":"ANIME","format":"ONA","title":{"english":"Show Web Season"}}},
+			{"relationType":"SIDE_STORY","node":{"id":6,"type":"ANIME","format":"SPECIAL","title":{"english":"Show Specials"}}},
+			{"relationType":"ADAPTATION","node":{"id":7,"type":"MANGA","format":"MANGA","title":{"english":"Show"}}}
+		]}}}}`
+	}}
+	client := newBulkTestClient(transport)
+
+	specials, err := client.GetSpecials(context.Background(), 1)
+	require.NoError(t, err)
+
+	require.Len(t, specials, 3)
+	assert.Equal(t, []int{4, 3, 6}, []int{specials[0].ID, specials[1].ID, specials[2].ID}, "in release order, undated last")
+	assert.Equal(t, "OVA", specials[0].Format)
+	assert.Equal(t, 2, specials[0].Episodes)
+	assert.Equal(t, Special{
+		ID: 3, MALID: 30, Title: "Show: The Movie", Format: "MOVIE", Relation: "SEQUEL", Episodes: 1,
+		StartDate: specials[1].StartDate, Status: "COMPLETED", Progress: 1,
+	}, specials[1])
+	require.NotNil(t, specials[1].StartDate)
+	assert.Equal(t, 2021, specials[1].StartDate.Year())
+}
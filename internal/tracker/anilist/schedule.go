@@ -0,0 +1,87 @@
+package anilist
+
+import (
+	"context"
+	"time"
+)
+
+// schedulePageLimit bounds the pages of airing episodes fetched at once
+const schedulePageLimit = 10
+
+// AiringEpisode is an episode AniList has an airing time for
+type AiringEpisode struct {
+	MediaID  int
+	Episode  int
+	AiringAt time.Time
+	Duration time.Duration // Episode length, 0 when unknown
+}
+
+// GetAiringSchedule returns the episodes of the given anime that air
+// between from and to, in airing order
+func (c *Client) GetAiringSchedule(ctx context.Context, ids []int, from, to time.Time) ([]AiringEpisode, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	graphqlQuery := `
+	query($ids: [Int], $from: Int, $to: Int, $page: Int) {
+		Page(page: $page, perPage: 50) {
+			pageInfo {
+				hasNextPage
+			}
+			airingSchedules(mediaId_in: $ids, airingAt_greater: $from, airingAt_lesser: $to, sort: TIME) {
+				mediaId
+				episode
+				airingAt
+				media {
+					duration
+				}
+			}
+		}
+	}
+	`
+
+	var episodes []AiringEpisode
+	for page := 1; page <= schedulePageLimit; page++ {
+		var response struct {
+			Data struct {
+				Page struct {
+					PageInfo struct {
+						HasNextPage bool `json:"hasNextPage"`
+					} `json:"pageInfo"`
+					AiringSchedules []struct {
+						MediaID  int   `json:"mediaId"`
+						Episode  int   `json:"episode"`
+						AiringAt int64 `json:"airingAt"`
+						Media    struct {
+							Duration int `json:"duration"`
+						} `json:"media"`
+					} `json:"airingSchedules"`
+				} `json:"Page"`
+			} `json:"data"`
+		}
+
+		variables := map[string]interface{}{
+			"ids":  ids,
+			"from": from.Unix(),
+			"to":   to.Unix(),
+			"page": page,
+		}
+		if err := c.query(ctx, graphqlQuery, variables, &response); err != nil {
+			return nil, err
+		}
+
+		for _, s := range response.Data.Page.AiringSchedules {
+			episodes = append(episodes, AiringEpisode{
+				MediaID:  s.MediaID,
+				Episode:  s.Episode,
+				AiringAt: time.Unix(s.AiringAt, 0),
+				Duration: time.Duration(s.Media.Duration) * time.Minute,
+			})
+		}
+		if !response.Data.Page.PageInfo.HasNextPage {
+			break
+		}
+	}
+	return episodes, nil
+}
@@ -0,0 +1,46 @@
+package anilist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAiringSchedule(t *testing.T) {
+	transport := &recordingTransport{}
+	transport.respond = func(string) string {
+		if len(transport.requests) == 1 {
+			return `{"data":{"Page":{"pageInfo":{"hasNextPage":true},"airingSchedules":[
+				{"mediaId":1,"episode":5,"airingAt":1767225600,"media":{"duration":24}}
+			]}}}`
+		}
+		return `{"data":{"Page":{"pageInfo":{"hasNextPage":false},"airingSchedules":[
+			{"mediaId":2,"episode":1,"airingAt":1767312000,"media":{"duration":null}}
+		]}}}`
+	}
+	client := newBulkTestClient(transport)
+
+	from := time.Unix(1767000000, 0)
+	episodes, err := client.GetAiringSchedule(context.Background(), []int{1, 2}, from, from.Add(14*24*time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, transport.requests, 2)
+	variables := transport.requests[1]["variables"].(map[string]interface{})
+	assert.Equal(t, float64(2), variables["page"])
+	assert.Equal(t, float64(1767000000), variables["from"])
+	assert.Equal(t, []AiringEpisode{
+		{MediaID: 1, Episode: 5, AiringAt: time.Unix(1767225600, 0), Duration: 24 * time.Minute},
+		{MediaID: 2, Episode: 1, AiringAt: time.Unix(1767312000, 0)},
+	}, episodes)
+}
+
+func TestGetAiringScheduleNoMedia(t *testing.T) {
+	transport := &recordingTransport{}
+	episodes, err := newBulkTestClient(transport).GetAiringSchedule(context.Background(), nil, time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, episodes)
+	assert.Empty(t, transport.requests)
+}
@@ -3,8 +3,8 @@ package anilist
 import (
 	"encoding/json"
 	"fmt"
-	"time"
 
+	"github.com/justchokingaround/greg/internal/database"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 )
@@ -27,7 +27,7 @@ func NewTokenStorage(db *gorm.DB) *TokenStorage {
 func (s *TokenStorage) SaveToken(token *oauth2.Token) error {
 	if token == nil {
 		// Delete the token
-		return s.db.Exec("DELETE FROM settings WHERE key = ?", tokenSettingKey).Error
+		return database.DeleteSetting(s.db, tokenSettingKey)
 	}
 
 	data, err := json.Marshal(token)
@@ -35,24 +35,13 @@ func (s *TokenStorage) SaveToken(token *oauth2.Token) error {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
 
-	// Use raw SQL to do an upsert
-	return s.db.Exec(`
-		INSERT INTO settings (key, value, updated_at)
-		VALUES (?, ?, ?)
-		ON CONFLICT(key) DO UPDATE SET
-			value = excluded.value,
-			updated_at = excluded.updated_at
-	`, tokenSettingKey, string(data), time.Now()).Error
+	return database.SetSetting(s.db, tokenSettingKey, string(data))
 }
 
 // LoadToken loads an OAuth2 token from the database
 func (s *TokenStorage) LoadToken() (*oauth2.Token, error) {
-	var value string
-	err := s.db.Raw("SELECT value FROM settings WHERE key = ?", tokenSettingKey).Scan(&value).Error
+	value, err := database.GetSetting(s.db, tokenSettingKey)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
 		return nil, fmt.Errorf("failed to load token: %w", err)
 	}
 
@@ -12,6 +12,7 @@ import (
 // anilistMedia represents a media item from AniList
 type anilistMedia struct {
 	ID                int                    `json:"id"`
+	IDMal             int                    `json:"idMal"`
 	Title             anilistTitle           `json:"title"`
 	Episodes          int                    `json:"episodes"`
 	Chapters          int                    `json:"chapters"`
@@ -42,6 +43,18 @@ type anilistAiringEpisode struct {
 	Episode         int   `json:"episode"`
 }
 
+// schedule returns the next episode to air and when, 0 when not airing
+func (e *anilistAiringEpisode) schedule() (int, time.Time) {
+	if e == nil {
+		return 0, time.Time{}
+	}
+	var at time.Time
+	if e.AiringAt > 0 {
+		at = time.Unix(e.AiringAt, 0)
+	}
+	return e.Episode, at
+}
+
 // anilistMediaListEntry represents media list entry
 type anilistMediaListEntry struct {
 	ID     int    `json:"id"`
@@ -109,14 +122,16 @@ func (d *anilistDate) ToTime() *time.Time {
 
 // anilistEntry represents a media list entry from AniList
 type anilistEntry struct {
-	ID          int          `json:"id"` // This is the MediaListEntry ID - use this for deletion
-	Status      string       `json:"status"`
-	Score       float64      `json:"score"`
-	Progress    int          `json:"progress"`
-	StartedAt   anilistDate  `json:"startedAt"`
-	CompletedAt anilistDate  `json:"completedAt"`
-	UpdatedAt   int64        `json:"updatedAt"`
-	Media       anilistMedia `json:"media"`
+	ID          int             `json:"id"` // This is the MediaListEntry ID - use this for deletion
+	Status      string          `json:"status"`
+	Score       float64         `json:"score"`
+	ScoreTen    float64         `json:"scoreTen"` // Score on a 0-10 scale, whatever the user's format
+	Progress    int             `json:"progress"`
+	StartedAt   anilistDate     `json:"startedAt"`
+	CompletedAt anilistDate     `json:"completedAt"`
+	UpdatedAt   int64           `json:"updatedAt"`
+	CustomLists map[string]bool `json:"customLists"` // Every custom list of the user, true if the entry is in it
+	Media       anilistMedia    `json:"media"`
 }
 
 // entryToTrackedMedia converts an AniList entry to a TrackedMedia
@@ -128,6 +143,8 @@ func entryToTrackedMedia(entry anilistEntry) tracker.TrackedMedia {
 		totalUnits = entry.Media.Chapters
 	}
 
+	nextEpisode, nextAiringAt := entry.Media.NextAiringEpisode.schedule()
+
 	return tracker.TrackedMedia{
 		ServiceID:     fmt.Sprintf("%d", entry.Media.ID),
 		Title:         getBestTitle(entry.Media.Title),
@@ -136,12 +153,20 @@ func entryToTrackedMedia(entry anilistEntry) tracker.TrackedMedia {
 		TotalEpisodes: totalUnits,
 		Status:        status,
 		Score:         entry.Score,
+		ScoreTen:      entry.ScoreTen,
+		MALID:         entry.Media.IDMal,
 		StartDate:     entry.StartedAt.ToTime(),
 		EndDate:       entry.CompletedAt.ToTime(),
 		Synopsis:      entry.Media.Description,
 		PosterURL:     entry.Media.CoverImage.Large,
 		UpdatedAt:     time.Unix(entry.UpdatedAt, 0),
 		ListEntryID:   entry.ID, // Store the MediaListEntry ID for deletion
+		AiringStatus:  entry.Media.Status,
+		AverageScore:  float64(entry.Media.AverageScore) / 10,
+		NextEpisode:   nextEpisode,
+		NextAiringAt:  nextAiringAt,
+		CustomLists:   entry.CustomLists,
+		Genres:        entry.Media.Genres,
 	}
 }
 
@@ -2,10 +2,13 @@ package tracker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
 	"github.com/justchokingaround/greg/internal/providers"
 	"gorm.io/gorm"
 )
@@ -52,87 +55,91 @@ func (m *Manager) IsAniListAuthenticated() bool {
 	return m.anilist != nil && m.anilist.IsAuthenticated()
 }
 
-// UpdateProgress updates progress on all enabled trackers
+// ErrSyncQueued is wrapped by UpdateProgress errors when the update was
+// stored in the sync queue for a later retry
+var ErrSyncQueued = errors.New("queued for retry")
+
+// UpdateProgress updates progress on all enabled trackers. Failed updates
+// are stored in the sync queue and retried by ProcessSyncQueue.
 func (m *Manager) UpdateProgress(ctx context.Context, mediaID string, episode int, progress float64) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if m.anilist != nil && m.cfg.Tracker.AniList.Enabled && m.cfg.Tracker.AniList.AutoSync {
-		// Check if we should sync based on threshold
-		if progress >= m.cfg.Tracker.AniList.SyncThreshold {
-			if err := m.anilist.UpdateProgress(ctx, mediaID, episode, progress); err != nil {
-				// Don't fail the operation if sync fails, just log it
-				return fmt.Errorf("anilist sync failed: %w", err)
-			}
-		} else {
-			// Queue for later sync
-			if err := m.queueSync(mediaID, episode, progress); err != nil {
-				return fmt.Errorf("failed to queue sync: %w", err)
-			}
-		}
+	if m.anilist == nil || !m.cfg.Tracker.AniList.Enabled || !m.cfg.Tracker.AniList.AutoSync {
+		return nil
 	}
 
-	return nil
-}
+	// Only sync once the episode counts as watched
+	if progress < m.cfg.Tracker.AniList.SyncThreshold {
+		return nil
+	}
+
+	err := m.anilist.UpdateProgress(ctx, mediaID, episode, progress)
+	if err == nil {
+		return nil
+	}
 
-// queueSync adds an item to the sync queue
-func (m *Manager) queueSync(mediaID string, episode int, progress float64) error {
-	// Insert into sync_queue table
-	return m.db.Exec(`
-		INSERT INTO sync_queue (media_id, episode, progress, synced, created_at)
-		VALUES (?, ?, ?, false, CURRENT_TIMESTAMP)
-		ON CONFLICT(media_id, episode) DO UPDATE SET
-			progress = excluded.progress,
-			synced = false
-	`, mediaID, episode, progress).Error
+	var anilistID *int
+	if id, convErr := strconv.Atoi(mediaID); convErr == nil {
+		anilistID = &id
+	}
+	if m.db == nil {
+		return fmt.Errorf("anilist sync failed: %w", err)
+	}
+	if queueErr := database.QueueSync(m.db, mediaID, anilistID, episode, progress, err); queueErr != nil {
+		return fmt.Errorf("anilist sync failed: %w (failed to queue: %v)", err, queueErr)
+	}
+	return fmt.Errorf("anilist sync failed, %w: %v", ErrSyncQueued, err)
 }
 
-// ProcessSyncQueue processes pending sync items
-func (m *Manager) ProcessSyncQueue(ctx context.Context) error {
+// ProcessSyncQueue retries queued progress updates whose backoff has passed,
+// or all of them when force is set. Returns how many went through and how
+// many failed again.
+func (m *Manager) ProcessSyncQueue(ctx context.Context, force bool) (synced, failed int, err error) {
 	m.mu.RLock()
 	anilist := m.anilist
 	m.mu.RUnlock()
 
 	if anilist == nil || !m.cfg.Tracker.AniList.Enabled {
-		return nil
+		return 0, 0, nil
 	}
-
-	// Get pending sync items
-	var items []struct {
-		ID       uint
-		MediaID  string
-		Episode  int
-		Progress float64
+	if !anilist.IsAuthenticated() {
+		return 0, 0, fmt.Errorf("anilist is not authenticated")
 	}
 
-	if err := m.db.Raw(`
-		SELECT id, media_id, episode, progress
-		FROM sync_queue
-		WHERE synced = false
-		ORDER BY created_at ASC
-		LIMIT 100
-	`).Scan(&items).Error; err != nil {
-		return fmt.Errorf("failed to get sync queue: %w", err)
+	items, err := database.PendingSyncs(m.db, force)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get sync queue: %w", err)
 	}
 
 	for _, item := range items {
-		if err := anilist.UpdateProgress(ctx, item.MediaID, item.Episode, item.Progress); err != nil {
-			// Mark as failed but continue
-			continue
+		if ctx.Err() != nil {
+			return synced, failed, ctx.Err()
 		}
 
-		// Mark as synced
-		if err := m.db.Exec(`
-			UPDATE sync_queue
-			SET synced = true, synced_at = CURRENT_TIMESTAMP
-			WHERE id = ?
-		`, item.ID).Error; err != nil {
-			// Log but continue
+		if syncErr := anilist.UpdateProgress(ctx, item.MediaID, item.Episode, item.Progress); syncErr != nil {
+			failed++
+			if err := database.MarkSyncFailed(m.db, item, syncErr); err != nil {
+				return synced, failed, fmt.Errorf("failed to update sync queue: %w", err)
+			}
 			continue
 		}
+
+		synced++
+		if err := database.MarkSynced(m.db, item.ID); err != nil {
+			return synced, failed, fmt.Errorf("failed to update sync queue: %w", err)
+		}
 	}
 
-	return nil
+	return synced, failed, nil
+}
+
+// PendingSyncs returns the number of queued progress updates
+func (m *Manager) PendingSyncs() (int64, error) {
+	if m.db == nil {
+		return 0, nil
+	}
+	return database.CountPendingSyncs(m.db)
 }
 
 // SearchMedia searches for media on enabled trackers
@@ -2,10 +2,13 @@ package mapping
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -18,6 +21,8 @@ import (
 type Manager struct {
 	db                *gorm.DB
 	preferredProvider string
+	preferredByType   map[providers.MediaType]string // Overrides preferredProvider per media type
+	mu                sync.RWMutex
 	minMatchScore     float64 // Minimum similarity score for fuzzy matching (0.0-1.0)
 	debug             bool
 	logger            *slog.Logger
@@ -49,6 +54,26 @@ func (m *Manager) SetMinMatchScore(score float64) {
 	}
 }
 
+// SetPreferredProvider sets the provider searched first for a media type
+func (m *Manager) SetPreferredProvider(mediaType providers.MediaType, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.preferredByType == nil {
+		m.preferredByType = make(map[providers.MediaType]string)
+	}
+	m.preferredByType[mediaType] = name
+}
+
+// preferredFor returns the preferred provider for a media type
+func (m *Manager) preferredFor(mediaType providers.MediaType) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if name, ok := m.preferredByType[mediaType]; ok {
+		return name
+	}
+	return m.preferredProvider
+}
+
 // ProviderMapping represents a mapping result
 type ProviderMapping struct {
 	AniListID       int
@@ -56,6 +81,23 @@ type ProviderMapping struct {
 	ProviderMediaID string
 	Media           *providers.Media // The actual media details from provider
 	IsNew           bool             // True if this is a new mapping
+	// EpisodeOffset is how many of the provider's episodes come before the
+	// AniList entry's first one, when the provider lists a show AniList splits
+	// into cours as one: with an offset of 25, provider episode 27 is episode
+	// 2 of the AniList entry
+	EpisodeOffset  int
+	OffsetVerified bool // The user set or confirmed EpisodeOffset
+}
+
+// ProviderEpisode returns the provider's number of an AniList episode
+func (p *ProviderMapping) ProviderEpisode(episode int) int {
+	return episode + p.EpisodeOffset
+}
+
+// AniListEpisode returns the AniList number of a provider episode, 0 or less
+// for episodes before the AniList entry
+func (p *ProviderMapping) AniListEpisode(episode int) int {
+	return episode - p.EpisodeOffset
 }
 
 // SearchResult represents a provider search result with similarity score
@@ -86,6 +128,41 @@ func (m *Manager) GetMapping(ctx context.Context, anilistID int) (*ProviderMappi
 		ProviderName:    mapping.ProviderName,
 		ProviderMediaID: mapping.ProviderMediaID,
 		IsNew:           false,
+		EpisodeOffset:   mapping.EpisodeOffset,
+		OffsetVerified:  mapping.OffsetVerified,
+	}, nil
+}
+
+// MatchTitle returns the saved mapping whose title is most similar to the
+// given one, for names that don't come with an AniList ID such as release
+// filenames. Returns nil when no mapping scores above the minimum.
+func (m *Manager) MatchTitle(ctx context.Context, title string) (*ProviderMapping, error) {
+	var mappings []database.AniListMapping
+	if err := m.db.WithContext(ctx).Where("title <> ''").Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed to load mappings: %w", err)
+	}
+
+	var best *database.AniListMapping
+	bestScore := m.minMatchScore
+	for i := range mappings {
+		if score := utils.SimilarityScore(title, mappings[i].Title); score >= bestScore {
+			best, bestScore = &mappings[i], score
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	m.logger.Debug("matched title to mapping", "title", title, "mapping", best.Title, "score", bestScore)
+	media := &providers.Media{ID: best.ProviderMediaID, Title: best.Title}
+	if provider, err := providers.Get(best.ProviderName); err == nil {
+		media.Type = provider.Type()
+	}
+	return &ProviderMapping{
+		AniListID:       best.AniListID,
+		ProviderName:    best.ProviderName,
+		ProviderMediaID: best.ProviderMediaID,
+		Media:           media,
 	}, nil
 }
 
@@ -170,16 +247,18 @@ func (m *Manager) searchProvider(ctx context.Context, provider providers.Provide
 	return matches, nil
 }
 
-// GetOrCreateMapping retrieves an existing mapping or searches the provider
+// GetOrCreateMapping retrieves an existing mapping or searches the providers.
 // Returns the mapping if it exists, or search results for user selection
-func (m *Manager) GetOrCreateMapping(ctx context.Context, anilistID int, title string, mediaType providers.MediaType) (*ProviderMapping, []providers.Media, error) {
+// along with the provider that found them. The preferred provider is
+// searched first; the others of the same type are tried when it finds nothing.
+func (m *Manager) GetOrCreateMapping(ctx context.Context, anilistID int, title string, mediaType providers.MediaType) (*ProviderMapping, []providers.Media, string, error) {
 	m.logger.Debug("checking for mapping", "anilist_id", anilistID, "title", title)
 
 	// First, check if mapping already exists
 	existing, err := m.GetMapping(ctx, anilistID)
 	if err != nil {
 		m.logger.Error("get mapping failed", "error", err)
-		return nil, nil, fmt.Errorf("failed to check existing mapping: %w", err)
+		return nil, nil, "", fmt.Errorf("failed to check existing mapping: %w", err)
 	}
 
 	if existing != nil {
@@ -196,37 +275,50 @@ func (m *Manager) GetOrCreateMapping(ctx context.Context, anilistID int, title s
 			Type:  providers.MediaTypeAnime,
 		}
 
-		return existing, nil, nil
+		return existing, nil, existing.ProviderName, nil
 	}
 
-	m.logger.Debug("no existing mapping found, searching provider")
+	m.logger.Debug("no existing mapping found, searching providers")
 
-	// No existing mapping, search the preferred anime provider
-	animeProviders := providers.GetByType(mediaType)
-	if len(animeProviders) == 0 {
-		return nil, nil, fmt.Errorf("no providers available for media type: %s", mediaType)
+	typeProviders := providers.GetByType(mediaType)
+	if len(typeProviders) == 0 {
+		return nil, nil, "", fmt.Errorf("no providers available for media type: %s", mediaType)
 	}
 
-	// Use preferred provider if available, otherwise use first provider
-	provider := animeProviders[0] // Default to first provider
-	availableProviders := make([]string, len(animeProviders))
-	for i, p := range animeProviders {
+	// Search the preferred provider first, then fall back to the others
+	candidates := make([]providers.Provider, 0, len(typeProviders))
+	preferred := m.preferredFor(mediaType)
+	availableProviders := make([]string, len(typeProviders))
+	for i, p := range typeProviders {
 		availableProviders[i] = p.Name()
+		if p.Name() == preferred {
+			candidates = append([]providers.Provider{p}, candidates...)
+		} else {
+			candidates = append(candidates, p)
+		}
+	}
+	m.logger.Debug("provider selection", "preferred_provider", preferred, "default_provider", candidates[0].Name(), "available_providers", availableProviders)
+	if preferred == "" {
+		m.logger.Warn("no preferred provider set, using default", "provider", candidates[0].Name())
 	}
-	m.logger.Debug("provider selection", "preferred_provider", m.preferredProvider, "default_provider", provider.Name(), "available_providers", availableProviders)
 
-	if m.preferredProvider != "" {
-		for _, p := range animeProviders {
-			if p.Name() == m.preferredProvider {
-				provider = p
-				m.logger.Debug("using preferred provider", "provider", provider.Name())
-				break
-			}
+	var lastErr error
+	for _, provider := range candidates {
+		results, err := m.searchVariations(ctx, provider, title)
+		if err != nil {
+			m.logger.Debug("provider search failed, trying next provider", "provider", provider.Name(), "error", err)
+			lastErr = err
+			continue
 		}
-	} else {
-		m.logger.Warn("no preferred provider set, using default", "provider", provider.Name())
+		return nil, results, provider.Name(), nil
 	}
 
+	return nil, nil, "", lastErr
+}
+
+// searchVariations searches a provider with several variations of the title
+// and returns the unique results
+func (m *Manager) searchVariations(ctx context.Context, provider providers.Provider, title string) ([]providers.Media, error) {
 	// Try multiple search queries to handle variations in naming
 	// e.g., "Cyberpunk: Edgerunners" vs "Cyberpunk Edgerunners"
 	// IMPORTANT: Try variations without special characters FIRST, as they tend to work better
@@ -308,15 +400,15 @@ func (m *Manager) GetOrCreateMapping(ctx context.Context, anilistID int, title s
 
 		m.logger.Debug("total unique results across queries", "count", len(results))
 
-		return nil, results, nil
+		return results, nil
 	}
 
 	// If we get here, all variations failed - return the last error if any, or a general error
 	if lastErr != nil {
-		return nil, nil, fmt.Errorf("failed to search provider with all variations: %w", lastErr)
+		return nil, fmt.Errorf("failed to search provider with all variations: %w", lastErr)
 	}
 
-	return nil, nil, fmt.Errorf("no results found for '%s' or any of its variations", title)
+	return nil, fmt.Errorf("no results found for '%s' or any of its variations", title)
 }
 
 // SaveMapping persists a mapping to the database
@@ -332,15 +424,48 @@ func (m *Manager) SaveMapping(ctx context.Context, mapping *ProviderMapping) err
 		dbMapping.Title = mapping.Media.Title
 	}
 
-	// Upsert: Update if exists, create if not
-	result := m.db.Where("ani_list_id = ?", mapping.AniListID).Assign(dbMapping).FirstOrCreate(&dbMapping)
-	if result.Error != nil {
-		return fmt.Errorf("failed to save mapping: %w", result.Error)
+	// Upsert: Update if exists, create if not. The episode offset belongs
+	// to the pairing, so it only carries over when the provider media stays.
+	var existing database.AniListMapping
+	err := m.db.Where("anilist_id = ?", mapping.AniListID).First(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = m.db.Create(&dbMapping).Error
+	case err == nil:
+		if existing.ProviderName == dbMapping.ProviderName && existing.ProviderMediaID == dbMapping.ProviderMediaID {
+			dbMapping.EpisodeOffset = existing.EpisodeOffset
+			dbMapping.OffsetVerified = existing.OffsetVerified
+		}
+		dbMapping.ID = existing.ID
+		dbMapping.CreatedAt = existing.CreatedAt
+		dbMapping.UpdatedAt = time.Now()
+		err = m.db.Save(&dbMapping).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save mapping: %w", err)
 	}
 
 	return nil
 }
 
+// SetEpisodeOffset sets how many of the provider's episodes come before the
+// first episode of an AniList entry, and marks it as verified
+func (m *Manager) SetEpisodeOffset(ctx context.Context, anilistID, offset int) error {
+	if offset < 0 {
+		return fmt.Errorf("episode offset can't be negative")
+	}
+	result := m.db.WithContext(ctx).Model(&database.AniListMapping{}).
+		Where("anilist_id = ?", anilistID).
+		Updates(map[string]interface{}{"episode_offset": offset, "offset_verified": true, "updated_at": time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save episode offset: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no mapping for AniList entry %d", anilistID)
+	}
+	return nil
+}
+
 // RemapMedia searches for a new provider mapping and updates the database
 // Used when user wants to manually change the mapping
 func (m *Manager) RemapMedia(ctx context.Context, anilistID int, title string, mediaType providers.MediaType) ([]providers.Media, error) {
@@ -365,12 +490,20 @@ func (m *Manager) RemapMedia(ctx context.Context, anilistID int, title string, m
 }
 
 // DeleteMapping removes a mapping from the database
+// The mapping is kept in the trash so the deletion can be undone
 func (m *Manager) DeleteMapping(ctx context.Context, anilistID int) error {
-	result := m.db.Where("ani_list_id = ?", anilistID).Delete(&database.AniListMapping{})
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete mapping: %w", result.Error)
+	_, err := m.TrashMapping(ctx, anilistID)
+	return err
+}
+
+// TrashMapping moves a mapping to the trash
+// Returns nil if there was no mapping to delete
+func (m *Manager) TrashMapping(ctx context.Context, anilistID int) (*database.TrashItem, error) {
+	item, err := database.TrashMappingEntry(m.db, anilistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete mapping: %w", err)
 	}
-	return nil
+	return item, nil
 }
 
 // SelectMapping saves a user-selected mapping from search results
@@ -0,0 +1,58 @@
+package mapping
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+	return NewManager(db, "", nil)
+}
+
+func TestEpisodeOffset(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+
+	// The second cour of a show the provider lists as one
+	require.NoError(t, m.SelectMapping(ctx, 2, "allanime", providers.Media{ID: "show", Title: "Show"}))
+	mapping, err := m.GetMapping(ctx, 2)
+	require.NoError(t, err)
+	require.NotNil(t, mapping)
+	assert.False(t, mapping.OffsetVerified)
+
+	require.NoError(t, m.SetEpisodeOffset(ctx, 2, 25))
+	mapping, err = m.GetMapping(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 25, mapping.EpisodeOffset)
+	assert.True(t, mapping.OffsetVerified)
+	assert.Equal(t, 27, mapping.ProviderEpisode(2))
+	assert.Equal(t, 2, mapping.AniListEpisode(27))
+
+	// Saving the same pairing again keeps the offset
+	require.NoError(t, m.SelectMapping(ctx, 2, "allanime", providers.Media{ID: "show", Title: "Show"}))
+	mapping, err = m.GetMapping(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 25, mapping.EpisodeOffset)
+
+	// A new pairing starts over
+	require.NoError(t, m.SelectMapping(ctx, 2, "allanime", providers.Media{ID: "show-season-2", Title: "Show Season 2"}))
+	mapping, err = m.GetMapping(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "show-season-2", mapping.ProviderMediaID)
+	assert.Zero(t, mapping.EpisodeOffset)
+	assert.False(t, mapping.OffsetVerified)
+
+	assert.Error(t, m.SetEpisodeOffset(ctx, 3, 12), "no mapping to set it on")
+	assert.Error(t, m.SetEpisodeOffset(ctx, 2, -1))
+}
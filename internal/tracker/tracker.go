@@ -34,6 +34,21 @@ type Tracker interface {
 	DeleteFromList(ctx context.Context, mediaListID int) error
 }
 
+// BulkEditor is implemented by trackers that can change several list entries
+// in as few requests as their API allows
+type BulkEditor interface {
+	BulkUpdateStatus(ctx context.Context, entryIDs []int, status WatchStatus) error
+	BulkUpdateScore(ctx context.Context, entryIDs []int, score float64) error
+	BulkDelete(ctx context.Context, entryIDs []int) error
+	AddToCustomList(ctx context.Context, media []TrackedMedia, list string) error
+}
+
+// VolumeUpdater is implemented by trackers that track read volumes for manga
+// separately from chapters
+type VolumeUpdater interface {
+	UpdateVolumeProgress(ctx context.Context, mediaID string, volumes int) error
+}
+
 // TrackedMedia represents a media item in a tracking service
 type TrackedMedia struct {
 	ServiceID     string              `json:"service_id"` // ID in tracking service (AniList, MAL, etc.)
@@ -43,12 +58,49 @@ type TrackedMedia struct {
 	TotalEpisodes int                 `json:"total_episodes"`
 	Status        WatchStatus         `json:"status"`
 	Score         float64             `json:"score"`
+	ScoreTen      float64             `json:"score_ten,omitempty"` // Score on a 0-10 scale, 0 if unknown
+	MALID         int                 `json:"mal_id,omitempty"`    // MyAnimeList ID, 0 if unknown
 	StartDate     *time.Time          `json:"start_date,omitempty"`
 	EndDate       *time.Time          `json:"end_date,omitempty"`
 	Synopsis      string              `json:"synopsis"`
 	PosterURL     string              `json:"poster_url"`
 	UpdatedAt     time.Time           `json:"updated_at"`
-	ListEntryID   int                 `json:"list_entry_id,omitempty"` // ID of the list entry (AniList MediaListEntry ID)
+	ListEntryID   int                 `json:"list_entry_id,omitempty"`  // ID of the list entry (AniList MediaListEntry ID)
+	AiringStatus  string              `json:"airing_status,omitempty"`  // RELEASING, FINISHED, NOT_YET_RELEASED, ...
+	AverageScore  float64             `json:"average_score,omitempty"`  // Community score (0-10)
+	NextEpisode   int                 `json:"next_episode,omitempty"`   // Next episode to air, 0 if unknown
+	NextAiringAt  time.Time           `json:"next_airing_at,omitempty"` // When NextEpisode airs, zero if unknown
+	CustomLists   map[string]bool     `json:"custom_lists,omitempty"`   // Custom list name -> whether the entry is in it
+	Genres        []string            `json:"genres,omitempty"`
+}
+
+// IsAiring reports whether the media is currently releasing
+func (m TrackedMedia) IsAiring() bool {
+	return m.AiringStatus == "RELEASING"
+}
+
+// AiredEpisodes returns the number of episodes released so far, 0 if unknown
+func (m TrackedMedia) AiredEpisodes() int {
+	if m.NextEpisode > 0 {
+		return m.NextEpisode - 1
+	}
+	if m.AiringStatus == "RELEASING" {
+		return 0
+	}
+	return m.TotalEpisodes
+}
+
+// NextAiring returns the first episode that hasn't aired yet and when it
+// airs (zero if unknown), or 0 when every episode is out or the schedule
+// is unknown
+func (m TrackedMedia) NextAiring() (int, time.Time) {
+	if m.NextEpisode > 0 {
+		return m.NextEpisode, m.NextAiringAt
+	}
+	if m.AiringStatus == "NOT_YET_RELEASED" {
+		return 1, time.Time{}
+	}
+	return 0, time.Time{}
 }
 
 // Progress represents viewing progress for a media item
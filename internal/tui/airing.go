@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/components/episodes"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// airingSchedule returns the first episode of the AniList entry being
+// watched that hasn't aired yet, in the provider's numbering, and when it
+// airs, 0 when unknown
+func (a *App) airingSchedule() (int, time.Time) {
+	if !a.watchingFromAniList || a.currentAniListMedia == nil || a.currentAniListMedia.Type == providers.MediaTypeManga {
+		return 0, time.Time{}
+	}
+	next, at := a.currentAniListMedia.NextAiring()
+	if next > 0 {
+		next = a.providerEpisode(next)
+	}
+	return next, at
+}
+
+// refuseUnaired reports whether an episode hasn't aired yet, in which case
+// playing or downloading it would fail. The episode list is shown with the
+// cursor on it and the status bar says when it airs.
+func (a *App) refuseUnaired(number int) (tea.Cmd, bool) {
+	next, at := a.airingSchedule()
+	var episode *providers.Episode
+	for i := range a.episodes {
+		if a.episodes[i].Number == number {
+			episode = &a.episodes[i]
+			break
+		}
+	}
+	if episode == nil || !episodes.Unaired(*episode, next, time.Now()) {
+		return nil, false
+	}
+
+	switch {
+	case number == next && !at.IsZero():
+		a.statusMsg = i18n.T("⚠ Episode %d hasn't aired yet, it airs in %s", number, utils.FormatCountdown(time.Until(at)))
+	case !episode.ReleaseDate.IsZero():
+		a.statusMsg = i18n.T("⚠ Episode %d hasn't aired yet, it airs %s", number, episode.ReleaseDate.Format("Jan 2, 2006"))
+	default:
+		a.statusMsg = i18n.T("⚠ Episode %d hasn't aired yet", number)
+	}
+	a.statusMsgTime = time.Now()
+
+	if a.state != episodeView {
+		a.episodesComponent.SetMediaType(a.selectedMedia.Type)
+		a.episodesComponent.SetAiring(next, at)
+		a.episodesComponent.SetEpisodes(a.episodes)
+		a.episodesComponent.SetCursorToEpisode(number)
+		a.state = episodeView
+	}
+	return func() tea.Msg {
+		time.Sleep(3 * time.Second)
+		return clearStatusMsg{}
+	}, true
+}
+
+// airedOnly drops the episodes of a batch download that haven't aired yet
+func (a *App) airedOnly(list []common.EpisodeInfo) []common.EpisodeInfo {
+	next, _ := a.airingSchedule()
+	now := time.Now()
+	unaired := make(map[int]bool)
+	for _, ep := range a.episodes {
+		if episodes.Unaired(ep, next, now) {
+			unaired[ep.Number] = true
+		}
+	}
+
+	var aired []common.EpisodeInfo
+	for _, ep := range list {
+		if !unaired[ep.Number] {
+			aired = append(aired, ep)
+		}
+	}
+	return aired
+}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/ambient"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player/mpv"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+func init() {
+	handleCmd((*App).handleAmbientDoneMsg)
+}
+
+// ambientTimeout bounds starting, pausing or stopping the radio
+const ambientTimeout = 10 * time.Second
+
+// ambientDoneMsg reports the outcome of a radio command
+type ambientDoneMsg struct {
+	err error
+}
+
+// handleAmbientKeys handles the radio keys, available everywhere. Returns
+// false when the key isn't one of them.
+func (a *App) handleAmbientKeys(key string) (tea.Cmd, bool) {
+	var action func(*ambient.Radio, context.Context) error
+	switch key {
+	case "ctrl+o":
+		action = (*ambient.Radio).Toggle
+	case "alt+o":
+		action = (*ambient.Radio).Next
+	case "alt+O":
+		if a.radio == nil {
+			return nil, true
+		}
+		action = (*ambient.Radio).Stop
+	default:
+		return nil, false
+	}
+
+	radio, err := a.ambientRadio()
+	if err != nil {
+		return a.toast(severityError, i18n.T("Ambient radio unavailable: %v", err)), true
+	}
+	a.radioHeld = false
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ambientTimeout)
+		defer cancel()
+		return ambientDoneMsg{err: action(radio, ctx)}
+	}, true
+}
+
+// handleAmbientDoneMsg reports a radio command that failed
+func (a *App) handleAmbientDoneMsg(msg ambientDoneMsg) tea.Cmd {
+	if msg.err == nil {
+		return nil
+	}
+	if errors.Is(msg.err, ambient.ErrNoStations) {
+		return a.toast(severityWarning, i18n.T("No ambient stations configured (ambient.stations)"))
+	}
+	a.logger.Warn("ambient radio failed", "error", msg.err)
+	return a.toast(severityError, i18n.T("Ambient radio: %v", msg.err))
+}
+
+// ambientRadio returns the radio, creating its mpv on first use
+func (a *App) ambientRadio() (*ambient.Radio, error) {
+	if a.radio != nil {
+		return a.radio, nil
+	}
+	cfg := a.config()
+	if cfg == nil {
+		return nil, fmt.Errorf("no configuration")
+	}
+	p, err := mpv.NewMPVPlayerWithConfig(cfg, cfg.Advanced.Debug)
+	if err != nil {
+		return nil, err
+	}
+	a.radio = ambient.New(p, cfg.Ambient.Stations, cfg.Ambient.Volume)
+	return a.radio, nil
+}
+
+// holdAmbient pauses the radio while a video plays
+func (a *App) holdAmbient() {
+	if a.radio == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ambientTimeout)
+	defer cancel()
+	if a.radio.Hold(ctx) {
+		a.radioHeld = true
+	}
+}
+
+// releaseAmbient resumes the radio held for a video
+func (a *App) releaseAmbient() {
+	if a.radio == nil || !a.radioHeld {
+		return
+	}
+	a.radioHeld = false
+	ctx, cancel := context.WithTimeout(context.Background(), ambientTimeout)
+	defer cancel()
+	if err := a.radio.Release(ctx); err != nil {
+		a.logger.Warn("failed to resume ambient radio", "error", err)
+	}
+}
+
+// stopAmbient stops the radio on exit
+func (a *App) stopAmbient() {
+	if a.radio == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := a.radio.Stop(ctx); err != nil {
+		a.logger.Warn("failed to stop ambient radio", "error", err)
+	}
+}
+
+// renderMiniPlayer renders the radio's status bar, or nothing while it is
+// stopped
+func (a *App) renderMiniPlayer(width int) string {
+	if a.radio == nil {
+		return ""
+	}
+	state := a.radio.State()
+	station, ok := a.radio.Station()
+	if state == ambient.Stopped || !ok {
+		return ""
+	}
+
+	icon, toggle := "▶", i18n.T("pause")
+	if state == ambient.Paused {
+		icon, toggle = "⏸", i18n.T("play")
+	}
+	now := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("♪ %s %s", icon, station.Name))
+	keys := lipgloss.NewStyle().Foreground(styles.OxocarbonBase03).
+		Render(strings.Join([]string{"ctrl+o " + toggle, "alt+o " + i18n.T("next"), "alt+O " + i18n.T("stop")}, " · "))
+
+	return styles.FooterStyle.
+		Width(width).
+		Foreground(styles.OxocarbonMauve).
+		Render(now + "  " + keys)
+}
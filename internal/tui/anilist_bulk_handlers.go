@@ -0,0 +1,249 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tui/components/anilist"
+)
+
+func init() {
+	handle((*App).handleBulkActionMsg)
+	handle((*App).handleBulkUpdatedMsg)
+}
+
+// bulkStatusOptions matches the order of the status dialog
+var bulkStatusOptions = []string{"CURRENT", "COMPLETED", "PAUSED", "DROPPED", "PLANNING", "REPEATING"}
+
+// handleBulkActionMsg opens the dialog for a bulk action on the marked entries
+func (a *App) handleBulkActionMsg(msg anilist.BulkActionMsg) (*App, tea.Cmd) {
+	if len(msg.Media) == 0 {
+		return a, nil
+	}
+
+	switch msg.Action {
+	case anilist.BulkStatus:
+		a.dialogMode = anilist.DialogStatus
+		a.dialogState.StatusIndex = 0
+	case anilist.BulkScore:
+		a.dialogMode = anilist.DialogScore
+		a.dialogState.ScoreInput.SetValue("")
+		a.dialogState.ScoreInput.Focus()
+	case anilist.BulkCustomList:
+		if len(a.anilistComponent.CustomLists()) == 0 {
+			a.statusMsg = i18n.T("No custom lists: create one in your AniList list settings")
+			a.statusMsgTime = time.Now()
+			return a, func() tea.Msg {
+				time.Sleep(3 * time.Second)
+				return clearStatusMsg{}
+			}
+		}
+		a.dialogMode = anilist.DialogCustomList
+		a.dialogState.ListIndex = 0
+	case anilist.BulkDelete:
+		a.dialogMode = anilist.DialogDelete
+	default:
+		return a, nil
+	}
+
+	a.bulkMedia = msg.Media
+	return a, nil
+}
+
+// handleBulkDialogInput handles keyboard input for dialogs opened on marked entries
+func (a *App) handleBulkDialogInput(msg tea.KeyMsg) (*App, tea.Cmd) {
+	media := a.bulkMedia
+
+	switch msg.String() {
+	case "esc":
+		a.closeBulkDialog()
+		return a, nil
+
+	case "up", "k":
+		switch a.dialogMode {
+		case anilist.DialogStatus:
+			if a.dialogState.StatusIndex > 0 {
+				a.dialogState.StatusIndex--
+			}
+		case anilist.DialogCustomList:
+			if a.dialogState.ListIndex > 0 {
+				a.dialogState.ListIndex--
+			}
+		}
+		if a.dialogMode != anilist.DialogScore {
+			return a, nil
+		}
+
+	case "down", "j":
+		switch a.dialogMode {
+		case anilist.DialogStatus:
+			if a.dialogState.StatusIndex < len(bulkStatusOptions)-1 {
+				a.dialogState.StatusIndex++
+			}
+		case anilist.DialogCustomList:
+			if a.dialogState.ListIndex < len(a.anilistComponent.CustomLists())-1 {
+				a.dialogState.ListIndex++
+			}
+		}
+		if a.dialogMode != anilist.DialogScore {
+			return a, nil
+		}
+
+	case "enter":
+		switch a.dialogMode {
+		case anilist.DialogStatus:
+			status, err := tracker.ParseWatchStatus(strings.ToLower(bulkStatusOptions[a.dialogState.StatusIndex]))
+			if err != nil {
+				a.closeBulkDialog()
+				return a, nil
+			}
+			a.closeBulkDialog()
+			return a, a.bulkAniListCmd(anilist.BulkStatus, media, func(ctx context.Context, t tracker.Tracker, ids []int) error {
+				if editor, ok := t.(tracker.BulkEditor); ok {
+					return editor.BulkUpdateStatus(ctx, ids, status)
+				}
+				for _, m := range media {
+					if err := t.UpdateStatus(ctx, m.ServiceID, status); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+		case anilist.DialogScore:
+			score, err := anilist.ParseScore(a.dialogState.ScoreInput.Value())
+			if err != nil {
+				// Show error but keep dialog open
+				a.err = fmt.Errorf("invalid score: %v", err)
+				return a, nil
+			}
+			a.closeBulkDialog()
+			return a, a.bulkAniListCmd(anilist.BulkScore, media, func(ctx context.Context, t tracker.Tracker, ids []int) error {
+				if editor, ok := t.(tracker.BulkEditor); ok {
+					return editor.BulkUpdateScore(ctx, ids, score)
+				}
+				for _, m := range media {
+					if err := t.UpdateScore(ctx, m.ServiceID, score); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+		case anilist.DialogCustomList:
+			lists := a.anilistComponent.CustomLists()
+			if a.dialogState.ListIndex >= len(lists) {
+				a.closeBulkDialog()
+				return a, nil
+			}
+			list := lists[a.dialogState.ListIndex]
+			a.closeBulkDialog()
+			return a, a.bulkAniListCmd(anilist.BulkCustomList, media, func(ctx context.Context, t tracker.Tracker, ids []int) error {
+				editor, ok := t.(tracker.BulkEditor)
+				if !ok {
+					return fmt.Errorf("custom lists are not supported by this tracker")
+				}
+				return editor.AddToCustomList(ctx, media, list)
+			})
+
+		case anilist.DialogDelete:
+			// Enter is not a confirmation for deletes (safe default)
+			a.closeBulkDialog()
+			return a, nil
+		}
+
+	case "y", "Y":
+		if a.dialogMode == anilist.DialogDelete {
+			a.closeBulkDialog()
+			return a, a.bulkAniListCmd(anilist.BulkDelete, media, func(ctx context.Context, t tracker.Tracker, ids []int) error {
+				if editor, ok := t.(tracker.BulkEditor); ok {
+					return editor.BulkDelete(ctx, ids)
+				}
+				for _, id := range ids {
+					if err := t.DeleteFromList(ctx, id); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+
+	case "n", "N":
+		if a.dialogMode == anilist.DialogDelete {
+			a.closeBulkDialog()
+			return a, nil
+		}
+	}
+
+	// Pass input to the score input
+	if a.dialogMode == anilist.DialogScore {
+		var cmd tea.Cmd
+		a.dialogState.ScoreInput, cmd = a.dialogState.ScoreInput.Update(msg)
+		return a, cmd
+	}
+	return a, nil
+}
+
+// closeBulkDialog closes the dialog without forgetting the marks
+func (a *App) closeBulkDialog() {
+	a.dialogMode = anilist.DialogNone
+	a.bulkMedia = nil
+}
+
+// bulkAniListCmd runs a bulk action against the AniList tracker
+func (a *App) bulkAniListCmd(action anilist.BulkAction, media []tracker.TrackedMedia, run func(ctx context.Context, t tracker.Tracker, entryIDs []int) error) tea.Cmd {
+	entryIDs := make([]int, 0, len(media))
+	for _, m := range media {
+		entryIDs = append(entryIDs, m.ListEntryID)
+	}
+
+	a.statusMsg = i18n.T("Updating %d entries on AniList...", len(media))
+	a.statusMsgTime = time.Now()
+
+	return func() tea.Msg {
+		mgr := a.trackerMgr
+		if mgr == nil || mgr.GetAniList() == nil {
+			return anilist.BulkUpdatedMsg{Action: action, Error: fmt.Errorf("AniList tracker not available")}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		err := run(ctx, mgr.GetAniList(), entryIDs)
+		return anilist.BulkUpdatedMsg{Action: action, Count: len(media), Error: err}
+	}
+}
+
+// handleBulkUpdatedMsg reports a bulk action and refreshes the library
+func (a *App) handleBulkUpdatedMsg(msg anilist.BulkUpdatedMsg) (*App, tea.Cmd) {
+	if msg.Error != nil {
+		a.err = fmt.Errorf("bulk update failed: %v", msg.Error)
+		a.state = errorView
+		return a, nil
+	}
+
+	switch msg.Action {
+	case anilist.BulkCustomList:
+		a.statusMsg = i18n.T("✓ Added %d entries", msg.Count)
+	case anilist.BulkDelete:
+		a.statusMsg = i18n.T("✓ Deleted %d entries", msg.Count)
+	default:
+		a.statusMsg = i18n.T("✓ Updated %d entries", msg.Count)
+	}
+	a.statusMsgTime = time.Now()
+
+	// The reload clears the marks
+	a.state = loadingView
+	a.loadingOp = loadingAniListLibrary
+	clearCmd := func() tea.Msg {
+		time.Sleep(2 * time.Second)
+		return clearStatusMsg{}
+	}
+	return a, tea.Batch(a.spinner.Tick, a.fetchAniListLibrary(), clearCmd)
+}
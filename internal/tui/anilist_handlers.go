@@ -12,30 +12,46 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tracker"
-	"github.com/justchokingaround/greg/internal/tracker/mapping"
 	"github.com/justchokingaround/greg/internal/tui/components/anilist"
 	"github.com/justchokingaround/greg/internal/tui/components/results"
 )
 
+func init() {
+	handle((*App).handleAniListSearchRequestedMsg)
+	handle((*App).handleAniListSearchResultMsg)
+	handle((*App).handleAniListAddToListDialogOpenMsg)
+	handle((*App).handleAniListAddToListMsg)
+	handle((*App).handleAniListDeleteConfirmationMsg)
+	handle((*App).handleAniListSearchSelectMsg)
+	handle((*App).handleAniListDeleteRequestedMsg)
+	handle((*App).handleAniListDeleteResultMsg)
+	handle((*App).handleSelectMediaMsg)
+	handle((*App).handleRandomPickMsg)
+	handle((*App).handleRefreshLibraryMsg)
+	handle((*App).handleOpenStatusUpdateMsg)
+	handle((*App).handleOpenScoreUpdateMsg)
+	handle((*App).handleOpenProgressUpdateMsg)
+	handle((*App).handleStatusUpdatedMsg)
+	handle((*App).handleScoreUpdatedMsg)
+	handle((*App).handleProgressUpdatedMsg)
+	handle((*App).handleProviderSearchResultMsg)
+	handle((*App).handleRemapRequestedMsg)
+}
+
 // fetchAniListLibrary fetches the user's AniList library
 func (a *App) fetchAniListLibrary() tea.Cmd {
-	return func() tea.Msg {
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		// Check if tracker manager is available
 		if a.trackerMgr == nil {
 			return anilist.LibraryLoadedMsg{
 				Error: fmt.Errorf("tracker manager not initialized"),
 			}
 		}
-
-		// Type assert to tracker.Manager
-		mgr, ok := a.trackerMgr.(*tracker.Manager)
-		if !ok {
-			return anilist.LibraryLoadedMsg{
-				Error: fmt.Errorf("failed to access tracker manager"),
-			}
-		}
+		mgr := a.trackerMgr
 
 		// Check if AniList is enabled and authenticated
 		if !mgr.IsAniListEnabled() {
@@ -51,7 +67,7 @@ func (a *App) fetchAniListLibrary() tea.Cmd {
 		}
 
 		// Fetch library from AniList
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(opCtx, 30*time.Second)
 		defer cancel()
 
 		// Determine media type based on current app state
@@ -67,17 +83,30 @@ func (a *App) fetchAniListLibrary() tea.Cmd {
 			}
 		}
 
+		// Local watch history feeds the smart lists; they still work without it
+		var lastWatched map[int]time.Time
+		if a.historyService != nil {
+			if lastWatched, err = a.historyService.LastWatchedByAniList(); err != nil {
+				a.logger.Warn("failed to load watch history for smart lists", "error", err)
+			}
+		}
+
 		return anilist.LibraryLoadedMsg{
-			Library: library,
-			Error:   nil,
+			Library:     library,
+			LastWatched: lastWatched,
+			Error:       nil,
 		}
-	}
+	})
 }
 
 // handleDialogInput handles keyboard input for AniList dialogs
 func (a *App) handleDialogInput(msg tea.KeyMsg) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if len(a.bulkMedia) > 0 {
+		return a.handleBulkDialogInput(msg)
+	}
+
 	switch msg.String() {
 	case "esc":
 		// Cancel dialog
@@ -274,13 +303,12 @@ func (a *App) handleDialogInput(msg tea.KeyMsg) (*App, tea.Cmd) {
 // updateAniListStatus updates the watch status of a media on AniList
 func (a *App) updateAniListStatus(media *tracker.TrackedMedia, newStatus string) tea.Cmd {
 	return func() tea.Msg {
-		// Type assert to tracker.Manager
-		mgr, ok := a.trackerMgr.(*tracker.Manager)
-		if !ok {
+		mgr := a.trackerMgr
+		if mgr == nil {
 			return anilist.StatusUpdatedMsg{
 				MediaID: media.ServiceID,
 				Status:  newStatus,
-				Error:   fmt.Errorf("failed to access tracker manager"),
+				Error:   fmt.Errorf("tracker manager not initialized"),
 			}
 		}
 
@@ -333,13 +361,12 @@ func (a *App) updateAniListStatus(media *tracker.TrackedMedia, newStatus string)
 // updateAniListScore updates the score of a media on AniList
 func (a *App) updateAniListScore(media *tracker.TrackedMedia, newScore float64) tea.Cmd {
 	return func() tea.Msg {
-		// Type assert to tracker.Manager
-		mgr, ok := a.trackerMgr.(*tracker.Manager)
-		if !ok {
+		mgr := a.trackerMgr
+		if mgr == nil {
 			return anilist.ScoreUpdatedMsg{
 				MediaID: media.ServiceID,
 				Score:   newScore,
-				Error:   fmt.Errorf("failed to access tracker manager"),
+				Error:   fmt.Errorf("tracker manager not initialized"),
 			}
 		}
 
@@ -369,14 +396,13 @@ func (a *App) updateAniListScore(media *tracker.TrackedMedia, newScore float64)
 // updateAniListProgress updates the episode progress of a media on AniList
 func (a *App) updateAniListProgress(media *tracker.TrackedMedia, newProgress int) tea.Cmd {
 	return func() tea.Msg {
-		// Type assert to tracker.Manager
-		mgr, ok := a.trackerMgr.(*tracker.Manager)
-		if !ok {
+		mgr := a.trackerMgr
+		if mgr == nil {
 			return anilist.ProgressUpdatedMsg{
 				MediaID:  media.ServiceID,
 				Episode:  newProgress,
 				Progress: 0,
-				Error:    fmt.Errorf("failed to access tracker manager"),
+				Error:    fmt.Errorf("tracker manager not initialized"),
 			}
 		}
 
@@ -408,7 +434,18 @@ func (a *App) updateAniListProgress(media *tracker.TrackedMedia, newProgress int
 
 // searchProvidersForAniList searches providers for an AniList anime
 func (a *App) searchProvidersForAniList(media *tracker.TrackedMedia) tea.Cmd {
-	return func() tea.Msg {
+	// Search the provider currently in use for this media type first
+	preferredType := providers.MediaTypeAnime
+	if media.Type == providers.MediaTypeManga {
+		preferredType = providers.MediaTypeManga
+	}
+	if mgr := a.mappingMgr; mgr != nil {
+		if p, ok := a.providers[preferredType]; ok && p != nil {
+			mgr.SetPreferredProvider(preferredType, p.Name())
+		}
+	}
+
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		a.debugLog("searchProvidersForAniList: Called for '%s' (ServiceID: %s)",
 			media.Title, media.ServiceID)
 
@@ -419,18 +456,7 @@ func (a *App) searchProvidersForAniList(media *tracker.TrackedMedia) tea.Cmd {
 				Error:     fmt.Errorf("mapping manager not initialized"),
 			}
 		}
-
-		mgr, ok := a.mappingMgr.(*mapping.Manager)
-		if !ok {
-			a.debugLog("ERROR: searchProvidersForAniList: Type assertion failed")
-			return anilist.ProviderSearchResultMsg{
-				AniListID: extractAniListID(media.ServiceID),
-				Error:     fmt.Errorf("invalid mapping manager type"),
-			}
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		mgr := a.mappingMgr
 
 		anilistID := extractAniListID(media.ServiceID)
 		a.debugLog("searchProvidersForAniList: Extracted AniList ID: %d", anilistID)
@@ -451,11 +477,13 @@ func (a *App) searchProvidersForAniList(media *tracker.TrackedMedia) tea.Cmd {
 			}
 		}
 		providerName := availProviders[0].Name()
-		a.debugLog("searchProvidersForAniList: Using provider: %s", providerName)
+
+		ctx, cancel := context.WithTimeout(opCtx, a.providerTimeout(providerName, config.TimeoutSearch))
+		defer cancel()
 
 		// Try to get or create mapping
 		a.debugLog("searchProvidersForAniList: Checking for existing mapping...")
-		providerMapping, searchResults, err := mgr.GetOrCreateMapping(
+		providerMapping, searchResults, foundBy, err := mgr.GetOrCreateMapping(
 			ctx,
 			anilistID,
 			media.Title,
@@ -470,6 +498,12 @@ func (a *App) searchProvidersForAniList(media *tracker.TrackedMedia) tea.Cmd {
 			}
 		}
 
+		// Use the provider the mapping or the results belong to, if it is still available
+		if _, err := providers.Get(foundBy); err == nil {
+			providerName = foundBy
+		}
+		a.debugLog("searchProvidersForAniList: Using provider: %s", providerName)
+
 		// If we got an existing mapping, return it
 		if providerMapping != nil {
 			a.debugLog("SUCCESS: searchProvidersForAniList: Found existing mapping (Provider: %s, MediaID: %s)",
@@ -495,7 +529,7 @@ func (a *App) searchProvidersForAniList(media *tracker.TrackedMedia) tea.Cmd {
 			ProviderName:  providerName,
 			SearchResults: results,
 		}
-	}
+	})
 }
 
 // extractAniListID extracts the numeric AniList ID from a ServiceID string
@@ -525,8 +559,8 @@ func (a *App) searchAniListForNewAnime(query string) tea.Cmd {
 			}
 		}
 
-		mgr, ok := a.trackerMgr.(*tracker.Manager)
-		if !ok || mgr.GetAniList() == nil {
+		mgr := a.trackerMgr
+		if mgr.GetAniList() == nil {
 			return anilist.AniListSearchResultMsg{
 				Query:   query,
 				Results: []tracker.TrackedMedia{},
@@ -569,8 +603,8 @@ func (a *App) deleteFromAniList(mediaListID int) tea.Cmd {
 			}
 		}
 
-		mgr, ok := a.trackerMgr.(*tracker.Manager)
-		if !ok || mgr.GetAniList() == nil {
+		mgr := a.trackerMgr
+		if mgr.GetAniList() == nil {
 			return anilist.AniListDeleteResultMsg{
 				Error: fmt.Errorf("AniList tracker not available"),
 			}
@@ -635,9 +669,8 @@ func (a *App) handleAniListAddToListMsg(msg anilist.AniListAddToListMsg) (*App,
 	// User selected an anime and status to add to AniList
 	if msg.Media != nil {
 		// Add to AniList with the selected status
-		if a.trackerMgr != nil {
-			trackerMgr, ok := a.trackerMgr.(*tracker.Manager)
-			if ok && trackerMgr.GetAniList() != nil {
+		if trackerMgr := a.trackerMgr; trackerMgr != nil {
+			if trackerMgr.GetAniList() != nil {
 				// Update status to the selected status
 				anilistID := extractAniListID(msg.Media.ServiceID)
 				ctx := context.Background()
@@ -699,9 +732,8 @@ func (a *App) handleAniListSearchSelectMsg(msg anilist.AniListSearchSelectMsg) (
 	// add to AniList with "CURRENT" status as default
 	if msg.Media != nil {
 		// Add to AniList with "CURRENT" status
-		if a.trackerMgr != nil {
-			trackerMgr, ok := a.trackerMgr.(*tracker.Manager)
-			if ok && trackerMgr.GetAniList() != nil {
+		if trackerMgr := a.trackerMgr; trackerMgr != nil {
+			if trackerMgr.GetAniList() != nil {
 				// Update status to CURRENT
 				anilistID := extractAniListID(msg.Media.ServiceID)
 				ctx := context.Background()
@@ -745,7 +777,7 @@ func (a *App) handleAniListDeleteResultMsg(msg anilist.AniListDeleteResultMsg) (
 	}
 
 	// Success: show confirmation and refresh the library
-	a.statusMsg = "✓ Successfully deleted from Anilist"
+	a.statusMsg = i18n.T("✓ Successfully deleted from Anilist")
 	a.statusMsgTime = time.Now()
 	a.state = loadingView
 	a.loadingOp = loadingAniListLibrary
@@ -774,6 +806,17 @@ func (a *App) handleSelectMediaMsg(msg anilist.SelectMediaMsg) (*App, tea.Cmd) {
 	return a, tea.Batch(cmds...)
 }
 
+// handleRandomPickMsg plays the entry the "watch something" dialog picked,
+// from its next episode like any library selection
+func (a *App) handleRandomPickMsg(msg anilist.RandomPickMsg) (*App, tea.Cmd) {
+	if msg.Media == nil {
+		return a, nil
+	}
+	toast := a.toast(severityInfo, i18n.T("Picked %s", msg.Media.Title))
+	updated, cmd := a.handleSelectMediaMsg(anilist.SelectMediaMsg{Media: msg.Media})
+	return updated, tea.Batch(toast, cmd)
+}
+
 // handleRefreshLibraryMsg handles library refresh request
 func (a *App) handleRefreshLibraryMsg(msg anilist.RefreshLibraryMsg) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -876,7 +919,7 @@ func (a *App) handleProgressUpdatedMsg(msg anilist.ProgressUpdatedMsg) (*App, te
 
 	// If we are reading manga, don't switch view, just show notification
 	if a.state == mangaReaderView {
-		a.statusMsg = fmt.Sprintf("✓ Progress updated: Chapter %d", msg.Episode)
+		a.statusMsg = i18n.T("✓ Progress updated: Chapter %d", msg.Episode)
 		a.mangaComponent.StatusMessage = a.statusMsg
 		a.statusMsgTime = time.Now()
 		return a, func() tea.Msg {
@@ -904,15 +947,17 @@ func (a *App) handleProviderSearchResultMsg(msg anilist.ProviderSearchResultMsg)
 
 	// Store the provider name
 	a.providerName = msg.ProviderName
+	a.setCourOffset(msg.AniListID, nil)
 
 	// If we got a direct mapping (existing), proceed to fetch episodes
 	if msg.Mapping != nil {
-		providerMapping, ok := msg.Mapping.(*mapping.ProviderMapping)
-		if !ok || providerMapping.Media == nil {
+		providerMapping := msg.Mapping
+		if providerMapping.Media == nil {
 			a.err = fmt.Errorf("invalid provider mapping")
 			a.state = errorView
 			return a, nil
 		}
+		a.setCourOffset(msg.AniListID, providerMapping)
 
 		// Set the selected media and provider
 		a.selectedMedia = *providerMapping.Media
@@ -959,20 +1004,18 @@ func (a *App) handleProviderSearchResultMsg(msg anilist.ProviderSearchResultMsg)
 			a.debugLog("Only 1 search result found for AniList content, using directly: %s (ID: %s)", testMedia.Title, testMedia.ID)
 
 			// Save the mapping automatically and proceed to get seasons/episodes
-			if a.mappingMgr != nil {
-				if mgr, ok := a.mappingMgr.(*mapping.Manager); ok {
-					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-					defer cancel()
-
-					a.debugLog("Auto-selecting mapping (AniList: %d → Provider: %s, Media: %s)",
-						a.currentAniListID, msg.ProviderName, testMedia.ID)
-
-					if err := mgr.SelectMapping(ctx, a.currentAniListID, msg.ProviderName, testMedia); err != nil {
-						a.debugLog("ERROR: Failed to save auto-selected mapping: %v", err)
-						// Continue anyway, just log the error
-					} else {
-						a.debugLog("SUCCESS: Auto-selected mapping saved successfully")
-					}
+			if mgr := a.mappingMgr; mgr != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+
+				a.debugLog("Auto-selecting mapping (AniList: %d → Provider: %s, Media: %s)",
+					a.currentAniListID, msg.ProviderName, testMedia.ID)
+
+				if err := mgr.SelectMapping(ctx, a.currentAniListID, msg.ProviderName, testMedia); err != nil {
+					a.debugLog("ERROR: Failed to save auto-selected mapping: %v", err)
+					// Continue anyway, just log the error
+				} else {
+					a.debugLog("SUCCESS: Auto-selected mapping saved successfully")
 				}
 			}
 
@@ -2,11 +2,13 @@ package tui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/justchokingaround/greg/internal/instance"
 	"github.com/justchokingaround/greg/internal/providers"
 	"gorm.io/gorm"
 )
@@ -26,13 +28,22 @@ type DebugInfo struct {
 }
 
 // Start is the entry point for the TUI.
+// initialLink, if set, is an AniList/MAL/provider URL opened right after startup.
+// inst, if set, receives commands forwarded from other greg invocations.
 // Returns debug information if in debug mode, otherwise nil.
-func Start(providers map[providers.MediaType]providers.Provider, trackerMgr interface{}, db *gorm.DB, cfg interface{}, logger *slog.Logger, audioPreference string) *DebugInfo {
+func Start(providers map[providers.MediaType]providers.Provider, trackerMgr ProgressTracker, db *gorm.DB, cfg ConfigProvider, logger *slog.Logger, audioPreference string, initialLink string, inst *instance.Instance) *DebugInfo {
 	m := NewApp(providers, db, cfg, logger, audioPreference)
 	m.trackerMgr = trackerMgr
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	m.initialLink = initialLink
+	p := tea.NewProgram(m, programOptions(m.config())...)
+	if inst != nil {
+		go serveRemote(inst, p)
+	}
 
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	m.shutdown()
+	// SIGINT ends the program like a normal quit
+	if err != nil && !errors.Is(err, tea.ErrInterrupted) {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
@@ -43,13 +54,16 @@ func Start(providers map[providers.MediaType]providers.Provider, trackerMgr inte
 
 // StartDebugLinks is the entry point for the TUI in debug links mode.
 // Returns debug information that should be printed after TUI exit.
-func StartDebugLinks(providers map[providers.MediaType]providers.Provider, trackerMgr interface{}, db *gorm.DB, cfg interface{}, logger *slog.Logger, audioPreference string) *DebugInfo {
+func StartDebugLinks(providers map[providers.MediaType]providers.Provider, trackerMgr ProgressTracker, db *gorm.DB, cfg ConfigProvider, logger *slog.Logger, audioPreference string) *DebugInfo {
 	m := NewApp(providers, db, cfg, logger, audioPreference)
 	m.trackerMgr = trackerMgr
 	m.inDebugLinksMode = true
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(m, programOptions(m.config())...)
 
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	m.shutdown()
+	// SIGINT ends the program like a normal quit
+	if err != nil && !errors.Is(err, tea.ErrInterrupted) {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
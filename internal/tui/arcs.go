@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/metadata/arcs"
+	"github.com/justchokingaround/greg/internal/metadata/tmdb"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	trackeranilist "github.com/justchokingaround/greg/internal/tracker/anilist"
+)
+
+// longRunningEpisodes is the shortest episode list worth grouping into TMDB
+// story arcs
+const longRunningEpisodes = 100
+
+// maxCours is the most entries of a chain of sequels grouped into cours
+const maxCours = 8
+
+// sequelChainLookup is implemented by tracker clients that can follow an
+// anime's sequels
+type sequelChainLookup interface {
+	GetSequelChain(ctx context.Context, id, limit int) ([]trackeranilist.Sequel, error)
+}
+
+// fillArcs groups episodes into story arcs or cours from the first source
+// that knows them: the provider, an arc dataset in metadata.arcs_dir, TMDB's
+// story arc episode groups for lists of 100+ episodes, or the AniList chain
+// of sequels of an anime the provider lists past the end of its AniList
+// entry. tracked is the AniList entry being watched, nil when unknown.
+func (a *App) fillArcs(media providers.Media, season int, tracked *tracker.TrackedMedia, episodes []providers.Episode) {
+	cfg := a.config()
+	if cfg == nil || len(episodes) == 0 || hasArcs(episodes) {
+		return
+	}
+
+	anilistID := 0
+	if tracked != nil {
+		anilistID = extractAniListID(tracked.ServiceID)
+	}
+	dataset, err := arcs.Load(cfg.Metadata.ArcsPath())
+	if err != nil {
+		a.debugLog("Arc datasets: %v", err)
+	} else if found, ok := dataset.Lookup(anilistID, media.Title); ok {
+		arcs.Apply(found, episodes)
+		return
+	}
+
+	if cfg.Metadata.TMDBAPIKey != "" && len(episodes) >= longRunningEpisodes {
+		showID := 0
+		if translator, ok := a.episodeNumbering(anilistID); ok {
+			showID = translator.TMDBID
+		}
+		a.fillTMDBArcs(cfg.Metadata.TMDBAPIKey, media, season, showID, episodes)
+		if hasArcs(episodes) {
+			return
+		}
+	}
+
+	if media.Type == providers.MediaTypeAnime && anilistID > 0 && tracked.TotalEpisodes > 0 && len(episodes) > tracked.TotalEpisodes {
+		a.fillCours(anilistID, episodes)
+	}
+}
+
+// hasArcs reports whether any episode has a story arc
+func hasArcs(episodes []providers.Episode) bool {
+	for _, ep := range episodes {
+		if ep.Arc != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fillTMDBArcs sets the story arcs of episodes from TMDB's story arc episode
+// group, matching anime by absolute episode number and other shows by
+// season and episode. showID is the TMDB show, 0 to search for it by title.
+func (a *App) fillTMDBArcs(apiKey string, media providers.Media, season, showID int, episodes []providers.Episode) {
+	if season <= 0 {
+		season = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := tmdb.NewClient(apiKey)
+	if showID == 0 {
+		var err error
+		showID, err = client.SearchShow(ctx, media.Title, media.Year)
+		if err != nil {
+			a.debugLog("TMDB: show lookup failed for %s: %v", media.Title, err)
+			return
+		}
+	}
+	storyArcs, err := client.GetStoryArcs(ctx, showID)
+	if err != nil {
+		a.debugLog("TMDB: story arcs lookup failed for %s: %v", media.Title, err)
+		return
+	}
+
+	absolute := make(map[int]string)
+	bySeason := make(map[[2]int]string)
+	for _, arc := range storyArcs {
+		for _, ep := range arc.Episodes {
+			if ep.Absolute > 0 {
+				absolute[ep.Absolute] = arc.Name
+			}
+			bySeason[[2]int{ep.Season, ep.Number}] = arc.Name
+		}
+	}
+
+	for i := range episodes {
+		if media.Type == providers.MediaTypeAnime {
+			episodes[i].Arc = absolute[episodes[i].Number]
+		} else {
+			episodes[i].Arc = bySeason[[2]int{season, episodes[i].Number}]
+		}
+	}
+}
+
+// fillCours splits the episodes of an anime a provider lists as one series
+// into the cours of its AniList chain of sequels
+func (a *App) fillCours(anilistID int, episodes []providers.Episode) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var lookup sequelChainLookup
+	if mgr := a.trackerMgr; mgr != nil {
+		lookup, _ = mgr.GetAniList().(sequelChainLookup)
+	}
+	if lookup == nil {
+		lookup = trackeranilist.NewClient(trackeranilist.Config{})
+	}
+
+	chain, err := lookup.GetSequelChain(ctx, anilistID, maxCours)
+	if err != nil {
+		a.debugLog("AniList: sequel chain lookup failed for %d: %v", anilistID, err)
+	}
+	if len(chain) < 2 {
+		return
+	}
+
+	cours := make([]arcs.Cour, 0, len(chain))
+	for _, entry := range chain {
+		cours = append(cours, arcs.Cour{Title: entry.Title, Episodes: entry.Episodes})
+	}
+	arcs.Apply(arcs.FromCours(cours), episodes)
+}
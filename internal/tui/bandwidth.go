@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+const (
+	bandwidthFlushInterval = time.Minute // How often streamed data is recorded
+	statsMonths            = 6           // Months shown in the stats view
+)
+
+// bandwidthMeter adds up the data mpv reads while streaming
+type bandwidthMeter struct {
+	pending   int64     // Streamed bytes not recorded yet
+	lastAt    time.Time // Last progress update, zero at playback start
+	flushedAt time.Time
+}
+
+// statsView is the overlay opened with ctrl+t
+type statsView struct {
+	open  bool
+	usage []database.BandwidthUsage // Newest month first
+	err   error
+}
+
+// monthlyCap returns network.monthly_cap in bytes, 0 when there is none
+func (a *App) monthlyCap() int64 {
+	if cfg := a.config(); cfg != nil && cfg.Network.MonthlyCap > 0 {
+		return int64(cfg.Network.MonthlyCap * 1e9)
+	}
+	return 0
+}
+
+// meterBandwidth counts the data streamed since the last progress update and
+// records it every bandwidthFlushInterval
+func (a *App) meterBandwidth(progress *player.PlaybackProgress) {
+	if progress == nil {
+		return
+	}
+	now := time.Now()
+	if !a.bandwidth.lastAt.IsZero() {
+		// cache-speed is a one second average; don't stretch it over gaps
+		elapsed := min(now.Sub(a.bandwidth.lastAt), 5*time.Second)
+		a.bandwidth.pending += int64(float64(progress.CacheSpeed) * elapsed.Seconds())
+	} else if a.bandwidth.flushedAt.IsZero() {
+		a.bandwidth.flushedAt = now
+	}
+	a.bandwidth.lastAt = now
+
+	if now.Sub(a.bandwidth.flushedAt) >= bandwidthFlushInterval {
+		a.flushBandwidth()
+	}
+}
+
+// flushBandwidth records the data streamed from the current provider in the
+// background, warning when it takes the month past the data cap
+func (a *App) flushBandwidth() {
+	bytes, provider := a.bandwidth.pending, a.currentPlaybackProvider
+	a.bandwidth.pending = 0
+	a.bandwidth.flushedAt = time.Now()
+	if a.db == nil || bytes <= 0 || provider == "" {
+		return
+	}
+
+	db, capBytes := a.db, a.monthlyCap()
+	a.goBackground(func() {
+		total, err := database.AddBandwidth(db, provider, database.BandwidthStream, bytes, time.Now())
+		if err != nil {
+			a.logger.Warn("failed to record bandwidth", "provider", provider, "error", err)
+			return
+		}
+		if warning := bandwidthWarning(total-bytes, total, capBytes); warning != "" {
+			a.notifyAsync(severityWarning, warning)
+		}
+	})
+}
+
+// endBandwidthMeter records what is left of the stream when playback ends
+func (a *App) endBandwidthMeter() {
+	a.flushBandwidth()
+	a.bandwidth = bandwidthMeter{}
+}
+
+// checkDownloadBandwidth warns when a finished download took the month past
+// the data cap; the downloader already recorded it
+func (a *App) checkDownloadBandwidth(bytes int64) {
+	capBytes := a.monthlyCap()
+	if a.db == nil || capBytes == 0 || bytes <= 0 {
+		return
+	}
+	total, err := database.BandwidthTotal(a.db, database.BandwidthMonth(time.Now()))
+	if err != nil {
+		a.logger.Warn("failed to read bandwidth usage", "error", err)
+		return
+	}
+	if warning := bandwidthWarning(total-bytes, total, capBytes); warning != "" {
+		a.notifyAsync(severityWarning, warning)
+	}
+}
+
+// bandwidthWarning returns the warning for usage going from before to after
+// bytes this month, empty unless it crossed 80% or 100% of capBytes
+func bandwidthWarning(before, after, capBytes int64) string {
+	if capBytes <= 0 {
+		return ""
+	}
+	switch {
+	case before < capBytes && after >= capBytes:
+		return i18n.T("Monthly data cap reached: %s used of %s", humanize.Bytes(uint64(after)), humanize.Bytes(uint64(capBytes)))
+	case before < capBytes*8/10 && after >= capBytes*8/10 && after < capBytes:
+		return i18n.T("80%% of the monthly data cap used: %s of %s", humanize.Bytes(uint64(after)), humanize.Bytes(uint64(capBytes)))
+	}
+	return ""
+}
+
+// openStats loads the bandwidth usage and opens the stats view
+func (a *App) openStats() {
+	a.stats = statsView{open: true}
+	if a.db != nil {
+		a.stats.usage, a.stats.err = database.ListBandwidth(a.db, statsMonths)
+	}
+}
+
+// handleStatsKeys handles keys while the stats view is open
+func (a *App) handleStatsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "ctrl+t":
+		a.stats.open = false
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// renderStats renders the data streamed and downloaded per provider, this
+// month first
+func (a *App) renderStats() string {
+	width := 64
+	if a.width > 0 && a.width-4 < width {
+		width = a.width - 4
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.TitleStyle.Render("  " + i18n.T("STATS") + "  "))
+	b.WriteString("\n\n")
+
+	thisMonth := database.BandwidthMonth(time.Now())
+	capBytes := a.monthlyCap()
+	switch {
+	case a.stats.err != nil:
+		b.WriteString(styles.AniListMetadataStyle.Render(i18n.T("Couldn't load bandwidth usage: %v", a.stats.err)))
+		b.WriteString("\n")
+	case len(a.stats.usage) == 0:
+		b.WriteString(styles.AniListMetadataStyle.Render(i18n.T("Nothing streamed or downloaded yet")))
+		b.WriteString("\n")
+	}
+
+	header := lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Bold(true)
+	for i := 0; i < len(a.stats.usage); {
+		month := a.stats.usage[i].Month
+		type row struct{ stream, download int64 }
+		var order []string
+		rows := make(map[string]*row)
+		var total int64
+		for ; i < len(a.stats.usage) && a.stats.usage[i].Month == month; i++ {
+			usage := a.stats.usage[i]
+			r, ok := rows[usage.Provider]
+			if !ok {
+				r = &row{}
+				rows[usage.Provider] = r
+				order = append(order, usage.Provider)
+			}
+			if usage.Kind == database.BandwidthDownload {
+				r.download += usage.Bytes
+			} else {
+				r.stream += usage.Bytes
+			}
+			total += usage.Bytes
+		}
+
+		title := month
+		if month == thisMonth && capBytes > 0 {
+			title += "  " + i18n.T("%s of %s cap", humanize.Bytes(uint64(total)), humanize.Bytes(uint64(capBytes)))
+		} else {
+			title += "  " + humanize.Bytes(uint64(total))
+		}
+		b.WriteString(header.Render(title))
+		b.WriteString("\n")
+		for _, provider := range order {
+			r := rows[provider]
+			b.WriteString(fmt.Sprintf("  %-16s %s  %s\n", provider,
+				styles.AniListMetadataStyle.Render(fmt.Sprintf("%-22s", i18n.T("%s streamed", humanize.Bytes(uint64(r.stream))))),
+				styles.AniListMetadataStyle.Render(i18n.T("%s downloaded", humanize.Bytes(uint64(r.download))))))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(styles.AniListHelpStyle.Render(i18n.T("esc close")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonPurple).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+}
@@ -8,8 +8,16 @@ import (
 
 	"github.com/justchokingaround/greg/internal/clipboard"
 	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tui/common"
 )
 
+func init() {
+	handle(func(a *App, msg common.CopyToClipboardMsg) (tea.Model, tea.Cmd) {
+		return a, a.copyToClipboardWithNotification(msg.Text, msg.Name)
+	})
+}
+
 // ReadFromClipboard reads content from the system clipboard
 func ReadFromClipboard(svc clipboard.Service, cfg *config.Config) (string, error) {
 	return svc.Read(context.Background(), cfg)
@@ -17,8 +25,8 @@ func ReadFromClipboard(svc clipboard.Service, cfg *config.Config) (string, error
 
 // copyToClipboard copies text to the system clipboard
 func (a *App) copyToClipboard(text string) tea.Cmd {
-	cfg, ok := a.cfg.(*config.Config)
-	if !ok {
+	cfg := a.config()
+	if cfg == nil {
 		// If config is not available, just return no-op
 		return func() tea.Msg { return nil }
 	}
@@ -29,7 +37,7 @@ func (a *App) copyToClipboard(text string) tea.Cmd {
 // copyToClipboardWithNotification copies text to clipboard and shows a notification
 func (a *App) copyToClipboardWithNotification(text, itemName string) tea.Cmd {
 	cmd := a.copyToClipboard(text)
-	a.statusMsg = "📋 " + itemName + " copied to clipboard"
+	a.statusMsg = i18n.T("📋 %s copied to clipboard", itemName)
 	a.statusMsgTime = time.Now()
 
 	return tea.Batch(cmd, func() tea.Msg {
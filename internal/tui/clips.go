@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+func init() {
+	handleCmd((*App).handleClipQueuedMsg)
+}
+
+// clipQueuedMsg reports a clip handed to the download queue
+type clipQueuedMsg struct {
+	err error
+}
+
+// markClip marks the start of a clip at the playback position, or its end
+// when the start is marked, queueing the clip for export
+func (a *App) markClip() tea.Cmd {
+	if a.lastProgress == nil {
+		return a.toast(severityWarning, i18n.T("Playback position not known yet"))
+	}
+	position := a.lastProgress.CurrentTime
+
+	if a.clipStart == nil {
+		a.clipStart = &position
+		return a.toast(severityInfo, i18n.T("Clip starts at %s, press 'c' again where it ends", utils.FormatPosition(position)))
+	}
+
+	start := *a.clipStart
+	a.clipStart = nil
+	if a.downloadMgr == nil || a.playingStream == nil {
+		return a.toast(severityError, i18n.T("Clips can't be exported from this playback"))
+	}
+
+	var settings config.ClipsConfig
+	if cfg := a.config(); cfg != nil {
+		settings = cfg.Downloads.Clips
+	}
+	clip := downloader.Clip{
+		Start:   start,
+		End:     position,
+		Format:  downloader.ClipFormat(settings.Format),
+		Width:   settings.Width,
+		FPS:     settings.FPS,
+		Hardsub: settings.Hardsub,
+	}
+	if clip.Format == "" {
+		clip.Format = downloader.ClipGIF
+	}
+	if err := clip.Validate(settings.MaxDuration); err != nil {
+		return a.toast(severityWarning, i18n.T("Clip not exported: %v", err))
+	}
+
+	mediaID, title, _ := a.playbackMedia(a.playingAniListID(), a.currentEpisodeNumber)
+	stream := a.playingStream
+	task := downloader.DownloadTask{
+		MediaID:    mediaID,
+		MediaTitle: title,
+		MediaType:  a.selectedMedia.Type,
+		Episode:    a.currentEpisodeNumber,
+		Season:     a.currentSeasonNumber,
+		Provider:   a.currentPlaybackProvider,
+		StreamURL:  stream.URL,
+		StreamType: stream.Type,
+		Headers:    stream.Headers,
+		Referer:    stream.Referer,
+		Subtitles:  stream.Subtitles,
+		Clip:       &clip,
+	}
+	mgr := a.downloadMgr
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return clipQueuedMsg{err: mgr.AddClip(ctx, task)}
+	}
+}
+
+// dropClipMark forgets a marked clip start
+func (a *App) dropClipMark() tea.Cmd {
+	if a.clipStart == nil {
+		return nil
+	}
+	a.clipStart = nil
+	return a.toast(severityInfo, i18n.T("Clip mark dropped"))
+}
+
+// handleClipQueuedMsg reports whether the clip was queued
+func (a *App) handleClipQueuedMsg(msg clipQueuedMsg) tea.Cmd {
+	if errors.Is(msg.err, downloader.ErrFFmpegRequired) {
+		return a.toast(severityError, i18n.T("Install ffmpeg to export clips"))
+	}
+	if msg.err != nil {
+		a.logger.Warn("failed to queue clip", "error", msg.err)
+		return a.toast(severityError, i18n.T("Clip not exported: %v", msg.err))
+	}
+	dir := "clips"
+	if cfg := a.config(); cfg != nil {
+		dir = filepath.Join(cfg.Downloads.Path, "clips")
+	}
+	return a.toast(severitySuccess, i18n.T("Clip queued, it will be saved to %s", dir))
+}
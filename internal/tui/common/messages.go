@@ -40,6 +40,16 @@ type MediaSelectedMsg struct {
 	Type    string `json:"type"`
 }
 
+// OpenLinkMsg requests opening an AniList, MyAnimeList or provider URL
+type OpenLinkMsg struct {
+	URL string
+}
+
+// PlayURLMsg requests playback of a pasted stream URL
+type PlayURLMsg struct {
+	URL string
+}
+
 // MediaDownloadMsg is a message when a download is requested for a media item (e.g. movie)
 type MediaDownloadMsg struct {
 	MediaID string
@@ -49,9 +59,11 @@ type MediaDownloadMsg struct {
 
 // EpisodeSelectedMsg is a message when an episode is selected.
 type EpisodeSelectedMsg struct {
-	EpisodeID string `json:"episode_id"`
-	Number    int    `json:"number"`
-	Title     string `json:"title"`
+	EpisodeID    string `json:"episode_id"`
+	Number       int    `json:"number"`
+	Title        string `json:"title"`
+	ChooseSource bool   `json:"choose_source,omitempty"` // Pick the server/mirror before playback
+	Download     bool   `json:"download,omitempty"`      // Download the episode and play it while it downloads
 }
 
 // EpisodeDownloadMsg is a message when a download is requested for an episode.
@@ -64,6 +76,7 @@ type EpisodeDownloadMsg struct {
 // BatchDownloadMsg is a message when batch download is requested for multiple episodes/chapters.
 type BatchDownloadMsg struct {
 	Episodes []EpisodeInfo
+	Quality  providers.Quality // Video quality to download, empty for the default
 }
 
 // MangaChapterDownloadMsg is a message when a manga chapter download is requested.
@@ -111,9 +124,51 @@ type SeasonSelectedMsg struct {
 
 // EpisodeInfo holds basic episode information for messaging
 type EpisodeInfo struct {
-	EpisodeID string
-	Number    int
-	Title     string
+	EpisodeID    string
+	Number       int
+	Title        string
+	Season       int
+	Volume       int
+	ScanGroup    string
+	Arc          string
+	Synopsis     string
+	ThumbnailURL string
+	Duration     time.Duration
+	ReleaseDate  time.Time
+}
+
+// NewEpisodeInfo converts a provider episode for messaging
+func NewEpisodeInfo(ep providers.Episode) EpisodeInfo {
+	return EpisodeInfo{
+		EpisodeID:    ep.ID,
+		Number:       ep.Number,
+		Title:        ep.Title,
+		Season:       ep.Season,
+		Volume:       ep.Volume,
+		ScanGroup:    ep.ScanGroup,
+		Arc:          ep.Arc,
+		Synopsis:     ep.Synopsis,
+		ThumbnailURL: ep.ThumbnailURL,
+		Duration:     ep.Duration,
+		ReleaseDate:  ep.ReleaseDate,
+	}
+}
+
+// Episode converts the info back to a provider episode
+func (e EpisodeInfo) Episode() providers.Episode {
+	return providers.Episode{
+		ID:           e.EpisodeID,
+		Number:       e.Number,
+		Title:        e.Title,
+		Season:       e.Season,
+		Volume:       e.Volume,
+		ScanGroup:    e.ScanGroup,
+		Arc:          e.Arc,
+		Synopsis:     e.Synopsis,
+		ThumbnailURL: e.ThumbnailURL,
+		Duration:     e.Duration,
+		ReleaseDate:  e.ReleaseDate,
+	}
 }
 
 // EpisodesLoadedMsg is a message when episodes are loaded successfully.
@@ -127,22 +182,36 @@ type PlaybackStartingMsg struct {
 	EpisodeID     string
 	EpisodeNumber int
 	EpisodeTitle  string
+	ChooseSource  bool
+	Download      bool // Play the episode while it downloads
 }
 
 // PlayerLaunchingMsg is a message when player is being launched
-type PlayerLaunchingMsg struct{}
+type PlayerLaunchingMsg struct {
+	Stream *providers.StreamURL // Stream being played, for clip export
+}
 
 // PlayerLaunchTimeoutCheckMsg is a tick message to check player launch status
 type PlayerLaunchTimeoutCheckMsg struct{}
 
 // PlaybackStartedMsg is a message when playback has successfully started
-type PlaybackStartedMsg struct{}
+type PlaybackStartedMsg struct {
+	Stream *providers.StreamURL // Stream being played, for clip export
+}
 
 // PlaybackEndedMsg is a message when playback has ended
 type PlaybackEndedMsg struct {
 	WatchedPercentage float64
 	WatchedDuration   string
 	TotalDuration     string
+	Completed         bool // Watched far enough to count, by the completion rule
+}
+
+// PlayerExitedMsg is sent when the player process of a playback exits, for
+// whatever reason. Session identifies the playback it belongs to.
+type PlayerExitedMsg struct {
+	Session int
+	Err     error // Exit error, nil for a clean exit
 }
 
 // PlaybackErrorMsg is a message when playback encounters an error
@@ -196,6 +265,12 @@ type DownloadErrorMsg struct {
 	Error  error
 }
 
+// RetryFromOtherProviderMsg asks to download a failed episode again from
+// another provider
+type RetryFromOtherProviderMsg struct {
+	TaskID string
+}
+
 // DownloadAddedMsg is a message when a download is added to queue
 type DownloadAddedMsg struct {
 	Title    string
@@ -271,6 +346,12 @@ type DebugSourcesInfo struct {
 	Subtitles     []DebugSubtitle
 	ProviderName  string
 	SelectedIndex int
+
+	// Cross-provider comparison of the episode, on demand
+	MediaTitle  string
+	Season      int
+	Comparing   bool
+	Comparisons []providers.Comparison
 }
 
 // DebugSourcesLoadedMsg is a message when debug sources are loaded
@@ -293,17 +374,42 @@ type MangaInfoResultMsg struct {
 	Err  error
 }
 
-// RequestDetailsMsg is a message to request details for a media item
+// DetailsRequest is a result row that needs details. Priority is the row's
+// distance from the cursor, lower is fetched first.
+type DetailsRequest struct {
+	MediaID  string
+	Index    int
+	Priority int
+}
+
+// RequestDetailsMsg lists the result rows on or near screen that need details.
+// It replaces any earlier request, so rows no longer listed are cancelled.
 type RequestDetailsMsg struct {
-	MediaID string
-	Index   int
+	Items []DetailsRequest
 }
 
 // DetailsLoadedMsg is a message when details for a media item are loaded
 type DetailsLoadedMsg struct {
-	Media providers.Media
-	Index int
-	Err   error
+	MediaID string
+	Media   providers.Media
+	Index   int
+	Err     error
+}
+
+// RequestEpisodeDetailMsg asks for the details of the episode shown in the
+// episode detail pane, with its thumbnail rendered to fit ThumbWidth x
+// ThumbHeight cells
+type RequestEpisodeDetailMsg struct {
+	Episode     providers.Episode
+	ThumbWidth  int
+	ThumbHeight int
+}
+
+// EpisodeDetailLoadedMsg carries an episode with the fields its provider left
+// empty filled in, and its thumbnail rendered as text ("" when there is none)
+type EpisodeDetailLoadedMsg struct {
+	Episode   providers.Episode
+	Thumbnail string
 }
 
 // SearchProviderMsg is a message to search a specific provider
@@ -322,6 +428,31 @@ type ProviderStatusesMsg []*providers.ProviderStatus
 // GoToProviderStatusMsg is a message to switch to the provider status view.
 type GoToProviderStatusMsg struct{}
 
+// ToggleFavoriteMsg is a message to star or unstar a title. An empty
+// ProviderName means the current provider.
+type ToggleFavoriteMsg struct {
+	MediaID      string
+	Title        string
+	Type         string
+	ProviderName string
+}
+
+// FavoriteToggledMsg is sent once a title is starred or unstarred.
+type FavoriteToggledMsg struct {
+	MediaID      string
+	Title        string
+	ProviderName string
+	Starred      bool
+	Err          error
+}
+
+// ToggleIncognitoMsg is a message to turn incognito mode on or off.
+type ToggleIncognitoMsg struct{}
+
+// CycleCoWatchMsg is a message to switch between watching solo and the
+// co-watch profiles.
+type CycleCoWatchMsg struct{}
+
 // ErrMsg is a message that contains an error.
 type ErrMsg struct{ Err error }
 
@@ -341,6 +472,36 @@ type ShowAudioSelectorMsg struct {
 	EpisodeTitle string
 }
 
+// TrashedMsg reports that a delete moved items to the trash, so the app can
+// offer to undo it from the status bar
+type TrashedMsg struct {
+	ID    uint   // Trash item ID (0 when nothing was deleted)
+	Label string // What was deleted, e.g. "Frieren - Episode 5"
+	Err   error
+}
+
+// CopyToClipboardMsg asks the app to copy text to the clipboard and say so in
+// the status bar
+type CopyToClipboardMsg struct {
+	Text string
+	Name string // What was copied, e.g. "Reproduction command"
+}
+
+// ShowResumePromptMsg asks whether to resume an unfinished episode or start over
+type ShowResumePromptMsg struct {
+	Stream   *providers.StreamURL
+	Options  player.PlayOptions // Options for playback from the start
+	Position time.Duration      // Saved position to resume from
+}
+
+// ShowSourceSelectorMsg is a message to let the user pick among an episode's sources
+type ShowSourceSelectorMsg struct {
+	Sources      []providers.StreamSource
+	EpisodeID    string
+	EpisodeNum   int
+	EpisodeTitle string
+}
+
 // PlaybackTickMsg is sent periodically to trigger playback status polling
 type PlaybackTickMsg struct{}
 
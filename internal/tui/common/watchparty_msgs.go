@@ -18,6 +18,11 @@ type WatchPartyInfo struct {
 	NextEpisodeTitle  string
 	NextEpisodeNumber int
 	ProviderName      string
+	MediaID           string
+	EpisodeID         string
+	ProxyURL          string            // Proxy used for ProxiedURL, reused for the next episode
+	Origin            string            // Origin the proxy sends upstream, reused for the next episode
+	Quality           providers.Quality // Quality of the shared stream
 }
 
 // WatchPartyMsg is sent when a WatchParty URL is generated
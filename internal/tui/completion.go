@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/metadata/aniskip"
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+func init() {
+	handleCmd(func(a *App, msg endingFoundMsg) tea.Cmd {
+		a.handleEndingFoundMsg(msg)
+		return nil
+	})
+}
+
+// endingLookup is where the ending of the episode playing starts, looked
+// up on AniSkip so the remaining rule leaves it out
+type endingLookup struct {
+	session int           // Player session it was looked up for
+	ending  time.Duration // 0 until found
+}
+
+// endingFoundMsg carries the ending of an episode found on AniSkip
+type endingFoundMsg struct {
+	session int
+	ending  time.Duration
+	err     error
+}
+
+// completionRule returns the rule deciding whether the playback counts as
+// watched
+func (a *App) completionRule() player.CompletionRule {
+	rule := player.CompletionRule{Rule: player.CompletionPercent, Percent: player.DefaultCompletionPercent}
+	cfg := a.playerConfig()
+	if cfg == nil {
+		return rule
+	}
+	rule.Rule = cfg.Completion.Rule
+	rule.Percent = cfg.Completion.Percent
+	rule.Remaining = cfg.Completion.Remaining
+	if a.ending.session == a.playerSession {
+		rule.Ending = a.ending.ending
+	}
+	return rule
+}
+
+// lookUpEnding fetches where the episode's ending starts once its duration
+// is known, once per playback. Only the remaining rule needs it, and only
+// anime watched from AniList have the MyAnimeList ID AniSkip goes by.
+func (a *App) lookUpEnding(progress *player.PlaybackProgress) tea.Cmd {
+	cfg := a.playerConfig()
+	if cfg == nil || cfg.Completion.Rule != player.CompletionRemaining || !cfg.Completion.AniSkip {
+		return nil
+	}
+	if progress == nil || progress.Duration <= 0 || a.ending.session == a.playerSession {
+		return nil
+	}
+	if !a.watchingFromAniList || a.currentAniListMedia == nil || a.currentAniListMedia.MALID == 0 || a.currentEpisodeNumber == 0 {
+		return nil
+	}
+
+	a.ending = endingLookup{session: a.playerSession}
+	session, malID, episode, length := a.playerSession, a.currentAniListMedia.MALID, a.currentEpisodeNumber, progress.Duration
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		interval, err := aniskip.NewClient().Ending(ctx, malID, episode, length)
+		if err != nil || interval == nil {
+			return endingFoundMsg{session: session, err: err}
+		}
+		return endingFoundMsg{session: session, ending: interval.Start}
+	}
+}
+
+// handleEndingFoundMsg keeps the ending for the playback it was looked up for
+func (a *App) handleEndingFoundMsg(msg endingFoundMsg) {
+	if msg.session != a.ending.session {
+		return
+	}
+	if msg.err != nil {
+		a.logger.Debug("failed to look up ending on aniskip", "error", msg.err)
+		return
+	}
+	a.ending.ending = msg.ending
+}
@@ -0,0 +1,157 @@
+package anilist
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+// highlightedMedia returns the media under the cursor, honoring the fuzzy filter
+func (m Model) highlightedMedia() *tracker.TrackedMedia {
+	filtered := m.GetFilteredLibrary()
+	if !m.fuzzySearch.IsActive() {
+		if m.currentIndex < 0 || m.currentIndex >= len(filtered) {
+			return nil
+		}
+		return &filtered[m.currentIndex]
+	}
+
+	indices := m.getFilteredLibraryIndices()
+	if m.currentIndex < 0 || m.currentIndex >= len(indices) || indices[m.currentIndex] >= len(filtered) {
+		return nil
+	}
+	return &filtered[indices[m.currentIndex]]
+}
+
+// visibleMedia returns the media shown in the list, honoring the fuzzy filter
+func (m Model) visibleMedia() []tracker.TrackedMedia {
+	filtered := m.GetFilteredLibrary()
+	if !m.fuzzySearch.IsActive() {
+		return filtered
+	}
+
+	var visible []tracker.TrackedMedia
+	for _, i := range m.getFilteredLibraryIndices() {
+		if i < len(filtered) {
+			visible = append(visible, filtered[i])
+		}
+	}
+	return visible
+}
+
+// MarkedMedia returns the entries marked for bulk actions, in library order
+func (m Model) MarkedMedia() []tracker.TrackedMedia {
+	var marked []tracker.TrackedMedia
+	for _, media := range m.library {
+		if m.marked[media.ServiceID] {
+			marked = append(marked, media)
+		}
+	}
+	return marked
+}
+
+// ClearMarks unmarks all entries
+func (m *Model) ClearMarks() {
+	m.marked = nil
+}
+
+// CustomLists returns the names of the user's custom lists
+func (m Model) CustomLists() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, media := range m.library {
+		for name := range media.CustomLists {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toggleMark marks or unmarks the highlighted entry and moves to the next one
+func (m *Model) toggleMark() {
+	media := m.highlightedMedia()
+	if media == nil {
+		return
+	}
+
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	if m.marked[media.ServiceID] {
+		delete(m.marked, media.ServiceID)
+	} else {
+		m.marked[media.ServiceID] = true
+	}
+
+	if m.currentIndex < len(m.visibleMedia())-1 {
+		m.currentIndex++
+	}
+}
+
+// toggleMarkAll marks every visible entry, or unmarks them if all are marked
+func (m *Model) toggleMarkAll() {
+	visible := m.visibleMedia()
+	allMarked := len(visible) > 0
+	for _, media := range visible {
+		if !m.marked[media.ServiceID] {
+			allMarked = false
+			break
+		}
+	}
+
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	for _, media := range visible {
+		if allMarked {
+			delete(m.marked, media.ServiceID)
+		} else {
+			m.marked[media.ServiceID] = true
+		}
+	}
+}
+
+// handleMarkKeys handles marking and bulk action keys. It reports whether
+// the key was consumed.
+func (m Model) handleMarkKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case " ":
+		m.toggleMark()
+		return m, nil, true
+	case "ctrl+a":
+		m.toggleMarkAll()
+		return m, nil, true
+	}
+
+	if len(m.marked) == 0 {
+		return m, nil, false
+	}
+
+	var action BulkAction
+	switch msg.String() {
+	case "esc":
+		m.ClearMarks()
+		return m, nil, true
+	case "s":
+		action = BulkStatus
+	case "r":
+		action = BulkScore
+	case "L":
+		action = BulkCustomList
+	case "d":
+		action = BulkDelete
+	default:
+		return m, nil, false
+	}
+
+	media := m.MarkedMedia()
+	return m, func() tea.Msg {
+		return BulkActionMsg{Action: action, Media: media}
+	}, true
+}
@@ -0,0 +1,51 @@
+package anilist
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+func TestMarkAndBulkAction(t *testing.T) {
+	m := New()
+	m.FilterByStatus("")
+	m.SetLibrary([]tracker.TrackedMedia{
+		{ServiceID: "1", Title: "A", ListEntryID: 11},
+		{ServiceID: "2", Title: "B", ListEntryID: 12},
+		{ServiceID: "3", Title: "C", ListEntryID: 13},
+	})
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	m, _ = m.handleLibraryViewKeyPress(space)
+	m, _ = m.handleLibraryViewKeyPress(space)
+	assert.Len(t, m.MarkedMedia(), 2)
+	assert.Equal(t, 2, m.currentIndex)
+
+	m, cmd := m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(BulkActionMsg)
+	require.True(t, ok)
+	assert.Equal(t, BulkDelete, msg.Action)
+	assert.Len(t, msg.Media, 2)
+
+	// esc clears the marks before leaving the view
+	m, cmd = m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd)
+	assert.Empty(t, m.MarkedMedia())
+
+	m, _ = m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyCtrlA})
+	assert.Len(t, m.MarkedMedia(), 3)
+}
+
+func TestCustomListsFromLibrary(t *testing.T) {
+	m := New()
+	m.SetLibrary([]tracker.TrackedMedia{
+		{ServiceID: "1", CustomLists: map[string]bool{"Rewatch": false, "Favourites": true}},
+		{ServiceID: "2", CustomLists: map[string]bool{"Rewatch": true}},
+	})
+	assert.Equal(t, []string{"Favourites", "Rewatch"}, m.CustomLists())
+}
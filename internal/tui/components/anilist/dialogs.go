@@ -20,6 +20,7 @@ const (
 	DialogProgress
 	DialogAddToList
 	DialogDelete
+	DialogCustomList
 )
 
 // StatusOption represents a status choice
@@ -41,6 +42,7 @@ var statusOptions = []StatusOption{
 type DialogState struct {
 	Mode          DialogMode
 	StatusIndex   int
+	ListIndex     int
 	ScoreInput    textinput.Model
 	ProgressInput textinput.Model
 }
@@ -294,3 +296,30 @@ func RenderDeleteConfirmationDialog(mediaTitle string) string {
 
 	return boxStyle.Render(output)
 }
+
+// RenderCustomListDialog renders the custom list selection dialog for bulk adds
+func RenderCustomListDialog(lists []string, selectedIndex int, count int) string {
+	var output string
+
+	// Title
+	title := styles.AniListHeaderStyle.Render("Add to Custom List")
+	output += title + "\n\n"
+	output += styles.AniListMetadataStyle.Render(fmt.Sprintf("%d marked entries", count)) + "\n\n"
+
+	// Options
+	for i, name := range lists {
+		if i == selectedIndex {
+			prefix := lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Render("▸ ")
+			output += prefix + lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Bold(true).Render(name) + "\n"
+		} else {
+			output += "  " + name + "\n"
+		}
+	}
+
+	output += "\n" + styles.AniListHelpStyle.Render("↑/↓ navigate • enter confirm • esc cancel")
+
+	// Box it
+	boxStyle := styles.PopupStyle.Width(40)
+
+	return boxStyle.Render(output)
+}
@@ -1,6 +1,12 @@
 package anilist
 
-import "github.com/justchokingaround/greg/internal/tracker"
+import (
+	"time"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
 
 // Messages for communication with parent TUI
 
@@ -9,6 +15,17 @@ type SelectMediaMsg struct {
 	Media *tracker.TrackedMedia
 }
 
+// RandomPickMsg is sent when the "watch something" dialog picks an entry to play
+type RandomPickMsg struct {
+	Media *tracker.TrackedMedia
+}
+
+// DownloadPlanningMsg requests queuing every episode of the Planning list
+type DownloadPlanningMsg struct {
+	Media   []tracker.TrackedMedia
+	Quality providers.Quality
+}
+
 // OpenStatusUpdateMsg requests opening status update dialog
 type OpenStatusUpdateMsg struct {
 	Media *tracker.TrackedMedia
@@ -32,8 +49,9 @@ type BackMsg struct{}
 
 // LibraryLoadedMsg is sent when library data is loaded
 type LibraryLoadedMsg struct {
-	Library []tracker.TrackedMedia
-	Error   error
+	Library     []tracker.TrackedMedia
+	LastWatched map[int]time.Time // Last local watch per AniList ID
+	Error       error
 }
 
 // StatusUpdatedMsg is sent when status is updated
@@ -67,9 +85,9 @@ type ProviderSearchingMsg struct {
 // ProviderSearchResultMsg is sent when provider search completes
 type ProviderSearchResultMsg struct {
 	AniListID     int
-	ProviderName  string        // Name of the provider searched
-	Mapping       interface{}   // *mapping.ProviderMapping (if existing mapping found)
-	SearchResults []interface{} // []providers.Media (if no mapping, show results to user)
+	ProviderName  string                   // Name of the provider searched
+	Mapping       *mapping.ProviderMapping // Existing mapping, if one was found
+	SearchResults []interface{}            // []providers.Media (if no mapping, show results to user)
 	Error         error
 }
 
@@ -136,3 +154,26 @@ type AniListDeleteRequestedMsg struct {
 type AniListDeleteResultMsg struct {
 	Error error
 }
+
+// BulkAction identifies an action applied to all marked entries
+type BulkAction int
+
+const (
+	BulkStatus BulkAction = iota
+	BulkScore
+	BulkCustomList
+	BulkDelete
+)
+
+// BulkActionMsg requests an action on the marked entries
+type BulkActionMsg struct {
+	Action BulkAction
+	Media  []tracker.TrackedMedia
+}
+
+// BulkUpdatedMsg is sent when a bulk action completes
+type BulkUpdatedMsg struct {
+	Action BulkAction
+	Count  int
+	Error  error
+}
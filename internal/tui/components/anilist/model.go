@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tracker"
 	"github.com/justchokingaround/greg/internal/tui/common"
@@ -44,6 +45,14 @@ type Model struct {
 	// Filter
 	statusFilter string // empty = all, or specific status
 
+	// Smart lists (saved filters shown as tabs)
+	smartLists  []config.SmartList
+	smartList   int               // Index of the active smart list, -1 = status filter
+	lastWatched map[int]time.Time // Last local watch per AniList ID
+
+	// Entries marked for bulk actions, by ServiceID
+	marked map[string]bool
+
 	// For search within AniList
 	searchInput        textinput.Model
 	searchQuery        string
@@ -62,6 +71,12 @@ type Model struct {
 	showInfoDialog bool
 	dialogScroll   int
 
+	// "Watch something" random pick dialog
+	random randomPick
+
+	// "Download my Planning list" dialog
+	planning planningDownload
+
 	// Keybindings
 	keys KeyMap
 
@@ -80,6 +95,8 @@ type KeyMap struct {
 	UpdateProgress key.Binding
 	FilterWatching key.Binding
 	FilterAll      key.Binding
+	NextTab        key.Binding
+	PrevTab        key.Binding
 	Play           key.Binding
 	Refresh        key.Binding
 	Remap          key.Binding
@@ -126,6 +143,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("a"),
 			key.WithHelp("a", "all anime"),
 		),
+		NextTab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next list"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous list"),
+		),
 		Play: key.NewBinding(
 			key.WithKeys("enter", "space"),
 			key.WithHelp("enter/space", "play"),
@@ -168,6 +193,7 @@ func New() Model {
 		currentIndex:       0,
 		viewMode:           ViewLibrary,
 		statusFilter:       "CURRENT", // Default to watching
+		smartList:          -1,
 		dialog:             InitDialogState(),
 		keys:               DefaultKeyMap(),
 		searchInput:        ti,
@@ -193,6 +219,7 @@ func (m *Model) SetSize(width, height int) {
 func (m *Model) SetLibrary(library []tracker.TrackedMedia) {
 	m.library = library
 	m.currentIndex = 0
+	m.marked = nil
 }
 
 // GetSelectedMedia returns the currently selected media
@@ -207,11 +234,25 @@ func (m *Model) GetSelectedMedia() *tracker.TrackedMedia {
 // FilterByStatus filters the library by watch status
 func (m *Model) FilterByStatus(status string) {
 	m.statusFilter = status
+	m.smartList = -1
 	m.currentIndex = 0
 }
 
-// GetFilteredLibrary returns the library filtered by current status
+// GetFilteredLibrary returns the library filtered by current status or smart list
 func (m *Model) GetFilteredLibrary() []tracker.TrackedMedia {
+	if list := m.activeSmartList(); list != nil {
+		filtered := []tracker.TrackedMedia{}
+		for _, media := range m.library {
+			if m.matchesSmartList(*list, media) {
+				filtered = append(filtered, media)
+			}
+		}
+		sort.Slice(filtered, func(i, j int) bool {
+			return filtered[i].UpdatedAt.After(filtered[j].UpdatedAt)
+		})
+		return filtered
+	}
+
 	if m.statusFilter == "" {
 		// Copy library to avoid modifying original
 		sorted := make([]tracker.TrackedMedia, len(m.library))
@@ -247,28 +288,37 @@ func (m Model) RenderLibraryView() string {
 		return "Loading..."
 	}
 
+	watchingName := "Watching"
+	if len(m.library) > 0 && m.library[0].Type == providers.MediaTypeManga {
+		watchingName = "Reading"
+	}
+
 	filtered := m.GetFilteredLibrary()
 	if len(filtered) == 0 {
-		return styles.AniListMetadataStyle.Render("No anime found. Press 'a' to view all, or 'ctrl+r' to refresh.")
+		empty := styles.AniListMetadataStyle.Render("No anime found. Press 'a' to view all, or 'ctrl+r' to refresh.")
+		if len(m.smartLists) > 0 {
+			return m.renderTabs(watchingName) + "\n\n" + empty
+		}
+		return empty
 	}
 
 	var output string
 
-	// Header with count
-	filterName := m.statusFilter
-	switch filterName {
-	case "":
-		filterName = "All Items"
-	case "CURRENT":
-		if len(m.library) > 0 && m.library[0].Type == providers.MediaTypeManga {
-			filterName = "Reading"
-		} else {
-			filterName = "Watching"
+	// Header with count, or tabs when smart lists are configured
+	if len(m.smartLists) > 0 {
+		output += m.renderTabs(watchingName) + "\n"
+	} else {
+		filterName := m.statusFilter
+		switch filterName {
+		case "":
+			filterName = "All Items"
+		case "CURRENT":
+			filterName = watchingName
 		}
-	}
 
-	header := styles.AniListHeaderStyle.Render(fmt.Sprintf("  %s  ", filterName))
-	output += header + "\n"
+		header := styles.AniListHeaderStyle.Render(fmt.Sprintf("  %s  ", filterName))
+		output += header + "\n"
+	}
 
 	// Get fuzzy filtered indices
 	filteredIndices := m.getFilteredLibraryIndices()
@@ -287,6 +337,9 @@ func (m Model) RenderLibraryView() string {
 	if displayCount > 0 {
 		countInfo += fmt.Sprintf(" • Viewing %d of %d", m.currentIndex+1, displayCount)
 	}
+	if len(m.marked) > 0 {
+		countInfo += fmt.Sprintf(" • %d marked", len(m.marked))
+	}
 	output += styles.AniListMetadataStyle.Render(countInfo) + "\n"
 
 	// Show fuzzy search input if active
@@ -344,7 +397,7 @@ func (m Model) RenderLibraryView() string {
 	// Help text
 	if m.fuzzySearch.IsActive() {
 		if m.fuzzySearch.IsLocked() {
-			output += "\n" + styles.AniListHelpStyle.Render("↑/↓ nav • enter play • i info • s status • r rate • d del • / edit • esc clear")
+			output += "\n" + styles.AniListHelpStyle.Render("↑/↓ nav • enter play • i info • s status • r rate • d del • space mark • / edit • esc clear")
 		} else {
 			output += "\n" + styles.AniListHelpStyle.Render("Type to filter • ↑/↓ nav • esc lock")
 		}
@@ -370,7 +423,11 @@ func (m Model) renderMediaItem(media tracker.TrackedMedia, selected bool) string
 	var lines []string
 
 	// Line 1: Title
-	lines = append(lines, titleStyle.Render(media.Title))
+	title := media.Title
+	if m.marked[media.ServiceID] {
+		title = "✓ " + title
+	}
+	lines = append(lines, titleStyle.Render(utils.Truncate(title, utils.ItemWidth(m.width))))
 
 	// Line 2: Type • Progress • Score • Status
 	var metaParts []string
@@ -452,15 +509,24 @@ func (m Model) renderHelp() string {
 		"r rate",
 		"p progress",
 		"d delete",
+		"space mark",
+		"L custom list",
 		"/ filter",
+		"R random",
+		"D download planning",
 		"w watching",
 		"a all",
+	}
+	if len(m.smartLists) > 0 {
+		helps = append(helps, "tab lists")
+	}
+	helps = append(helps,
 		"n new",
 		"P provider",
 		"m manga info",
 		"? help",
 		"q back",
-	}
+	)
 
 	helpStr := ""
 	for i, h := range helps {
@@ -526,6 +592,22 @@ func (m Model) View() string {
 		baseView = m.RenderLibraryView()
 	}
 
+	if m.random.open {
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.renderRandomDialog(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#161616")))
+	}
+
+	if m.planning.open {
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.renderPlanningDialog(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#161616")))
+	}
+
 	// Overlay info dialog if shown
 	if m.showInfoDialog {
 		filtered := m.GetFilteredLibrary()
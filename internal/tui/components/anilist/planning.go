@@ -0,0 +1,116 @@
+package anilist
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// planningQualities are the qualities offered for the Planning list download
+var planningQualities = []providers.Quality{
+	providers.Quality480p,
+	providers.Quality720p,
+	providers.Quality1080p,
+	providers.Quality1440p,
+	providers.Quality4K,
+}
+
+// planningRuntime is the episode length size estimates assume
+const planningRuntime = 24 * time.Minute
+
+// planningDownload is the state of the "download my Planning list" dialog
+type planningDownload struct {
+	open    bool
+	quality int // Index into planningQualities
+}
+
+// planningEntries returns the anime on the Planning list, in library order
+func (m Model) planningEntries() []tracker.TrackedMedia {
+	var entries []tracker.TrackedMedia
+	for _, media := range m.library {
+		if media.Status == tracker.StatusPlanToWatch && media.Type != providers.MediaTypeManga {
+			entries = append(entries, media)
+		}
+	}
+	return entries
+}
+
+// openPlanningDownload opens the dialog at 1080p
+func (m *Model) openPlanningDownload() {
+	m.planning.open = true
+	m.planning.quality = 0
+	for i, q := range planningQualities {
+		if q == providers.Quality1080p {
+			m.planning.quality = i
+		}
+	}
+}
+
+// handlePlanningKeys handles the "download my Planning list" dialog
+func (m Model) handlePlanningKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "n":
+		m.planning.open = false
+	case "left", "h":
+		m.planning.quality = (m.planning.quality - 1 + len(planningQualities)) % len(planningQualities)
+	case "right", "l":
+		m.planning.quality = (m.planning.quality + 1) % len(planningQualities)
+	case "enter", "y":
+		entries := m.planningEntries()
+		if len(entries) == 0 {
+			return m, nil
+		}
+		quality := planningQualities[m.planning.quality]
+		m.planning.open = false
+		return m, func() tea.Msg {
+			return DownloadPlanningMsg{Media: entries, Quality: quality}
+		}
+	}
+	return m, nil
+}
+
+// renderPlanningDialog renders the "download my Planning list" dialog
+func (m Model) renderPlanningDialog() string {
+	entries := m.planningEntries()
+	quality := planningQualities[min(m.planning.quality, len(planningQualities)-1)]
+
+	episodes, unknown := 0, 0
+	for _, media := range entries {
+		if aired := media.AiredEpisodes(); aired > 0 {
+			episodes += aired
+		} else {
+			unknown++
+		}
+	}
+
+	output := styles.AniListHeaderStyle.Render("Download Planning List") + "\n\n"
+	switch len(entries) {
+	case 0:
+		output += styles.AniListMetadataStyle.Render("Nothing planned") + "\n"
+	case 1:
+		output += styles.AniListMetadataStyle.Render(fmt.Sprintf("1 entry, %d episodes", episodes)) + "\n"
+	default:
+		output += styles.AniListMetadataStyle.Render(fmt.Sprintf("%d entries, %d episodes", len(entries), episodes)) + "\n"
+	}
+	if unknown > 0 {
+		output += styles.AniListMetadataStyle.Render(fmt.Sprintf("+ %d with an unknown episode count", unknown)) + "\n"
+	}
+
+	line := fmt.Sprintf("Quality ‹ %s ›", quality)
+	output += "\n" + lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Bold(true).Render(line) + "\n"
+	size := downloader.EstimateSize(quality, planningRuntime) * int64(episodes)
+	output += styles.AniListMetadataStyle.Render("Estimated size: ~"+humanize.Bytes(uint64(size))) + "\n"
+	output += "\n" + styles.AniListMetadataStyle.Render("Ambiguous matches are asked about, the rest is queued") + "\n"
+
+	output += "\n" + styles.AniListHelpStyle.Render("←/→ quality • enter queue • esc cancel")
+
+	return styles.PopupStyle.Width(min(56, max(m.width-4, 30))).Render(output)
+}
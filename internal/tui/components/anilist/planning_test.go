@@ -0,0 +1,47 @@
+package anilist
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+func TestPlanningDownload(t *testing.T) {
+	m := New()
+	m.FilterByStatus("")
+	m.SetLibrary(append(randomLibrary(),
+		tracker.TrackedMedia{ServiceID: "6", Title: "Planned Manga", Type: providers.MediaTypeManga, Status: tracker.StatusPlanToWatch}))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	key := func(k string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)} }
+
+	m, _ = m.handleLibraryViewKeyPress(key("D"))
+	require.True(t, m.planning.open)
+	view := m.View()
+	assert.Contains(t, view, "3 entries, 13 episodes")
+	assert.Contains(t, view, "1 with an unknown episode count")
+	assert.Contains(t, view, "1080p")
+
+	m, _ = m.handleLibraryViewKeyPress(key("h"))
+	m, cmd := m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.False(t, m.planning.open)
+	msg, ok := cmd().(DownloadPlanningMsg)
+	require.True(t, ok)
+	assert.Equal(t, providers.Quality720p, msg.Quality)
+	assert.Equal(t, []string{"Planned Action", "Planned Movie", "Planned Unknown"}, titles(msg.Media), "anime on the Planning list only")
+
+	// Nothing planned keeps the dialog open
+	m.SetLibrary(nil)
+	m, _ = m.handleLibraryViewKeyPress(key("D"))
+	m, cmd = m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.True(t, m.planning.open)
+	m, _ = m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.planning.open)
+}
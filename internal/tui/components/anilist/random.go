@@ -0,0 +1,160 @@
+package anilist
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// randomLengths are the length filters of the random pick, by episode count
+var randomLengths = []struct {
+	label    string
+	min, max int // 0 max = no upper bound
+}{
+	{"Any length", 0, 0},
+	{"Single episode", 1, 1},
+	{"Short (2-13 eps)", 2, 13},
+	{"Standard (14-26 eps)", 14, 26},
+	{"Long (27+ eps)", 27, 0},
+}
+
+// randomPick is the state of the "watch something" dialog
+type randomPick struct {
+	open   bool
+	field  int // 0 = genre, 1 = length
+	genre  int // Index into randomGenres, 0 = any
+	length int // Index into randomLengths
+}
+
+// isRandomCandidate reports whether media is on the Planning or Paused list
+func isRandomCandidate(media tracker.TrackedMedia) bool {
+	return media.Status == tracker.StatusPlanToWatch || media.Status == tracker.StatusOnHold
+}
+
+// randomGenres returns "Any genre" and the genres of the candidates, sorted
+func (m Model) randomGenres() []string {
+	seen := make(map[string]bool)
+	var genres []string
+	for _, media := range m.library {
+		if !isRandomCandidate(media) {
+			continue
+		}
+		for _, genre := range media.Genres {
+			if !seen[genre] {
+				seen[genre] = true
+				genres = append(genres, genre)
+			}
+		}
+	}
+	sort.Strings(genres)
+	return append([]string{"Any genre"}, genres...)
+}
+
+// randomCandidates returns the Planning and Paused entries passing the
+// dialog's filters. Entries with an unknown episode count only pass "Any
+// length".
+func (m Model) randomCandidates() []tracker.TrackedMedia {
+	genres := m.randomGenres()
+	genre := ""
+	if m.random.genre > 0 && m.random.genre < len(genres) {
+		genre = genres[m.random.genre]
+	}
+	length := randomLengths[0]
+	if m.random.length < len(randomLengths) {
+		length = randomLengths[m.random.length]
+	}
+
+	var candidates []tracker.TrackedMedia
+	for _, media := range m.library {
+		if !isRandomCandidate(media) {
+			continue
+		}
+		if genre != "" && !hasGenre(media, genre) {
+			continue
+		}
+		if length.min > 0 && (media.TotalEpisodes < length.min || (length.max > 0 && media.TotalEpisodes > length.max)) {
+			continue
+		}
+		candidates = append(candidates, media)
+	}
+	return candidates
+}
+
+func hasGenre(media tracker.TrackedMedia, genre string) bool {
+	for _, g := range media.Genres {
+		if g == genre {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRandomKeys handles the "watch something" dialog
+func (m Model) handleRandomKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	options := []int{len(m.randomGenres()), len(randomLengths)}
+	selected := []*int{&m.random.genre, &m.random.length}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.random.open = false
+	case "up", "k", "down", "j", "tab", "shift+tab":
+		// Two fields, so up and down both switch
+		m.random.field = (m.random.field + 1) % 2
+	case "left", "h":
+		*selected[m.random.field] = (*selected[m.random.field] - 1 + options[m.random.field]) % options[m.random.field]
+	case "right", "l":
+		*selected[m.random.field] = (*selected[m.random.field] + 1) % options[m.random.field]
+	case "enter", "R":
+		candidates := m.randomCandidates()
+		if len(candidates) == 0 {
+			return m, nil
+		}
+		picked := candidates[rand.IntN(len(candidates))]
+		m.random.open = false
+		return m, func() tea.Msg {
+			return RandomPickMsg{Media: &picked}
+		}
+	}
+	return m, nil
+}
+
+// renderRandomDialog renders the "watch something" dialog
+func (m Model) renderRandomDialog() string {
+	genres := m.randomGenres()
+	genre := genres[min(m.random.genre, len(genres)-1)]
+	length := randomLengths[min(m.random.length, len(randomLengths)-1)].label
+
+	output := styles.AniListHeaderStyle.Render("Watch Something") + "\n\n"
+	output += styles.AniListMetadataStyle.Render("A random pick from Planning and Paused") + "\n\n"
+
+	for i, field := range []struct{ name, value string }{{"Genre", genre}, {"Length", length}} {
+		line := fmt.Sprintf("%-7s ‹ %s ›", field.name, field.value)
+		if i == m.random.field {
+			prefix := lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Render("▸ ")
+			output += prefix + lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Bold(true).Render(line) + "\n"
+		} else {
+			output += "  " + line + "\n"
+		}
+	}
+
+	count := len(m.randomCandidates())
+	output += "\n"
+	switch count {
+	case 0:
+		output += styles.AniListMetadataStyle.Render("Nothing matches") + "\n"
+	case 1:
+		output += styles.AniListMetadataStyle.Render("1 match") + "\n"
+	default:
+		output += styles.AniListMetadataStyle.Render(fmt.Sprintf("%d matches", count)) + "\n"
+	}
+
+	output += "\n" + styles.AniListHelpStyle.Render("←/→ change • ↑/↓ field • enter pick & play • esc cancel")
+
+	return styles.PopupStyle.Width(min(50, max(m.width-4, 30))).Render(output)
+}
@@ -0,0 +1,79 @@
+package anilist
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+func randomLibrary() []tracker.TrackedMedia {
+	return []tracker.TrackedMedia{
+		{ServiceID: "1", Title: "Watching", Status: tracker.StatusWatching, TotalEpisodes: 12, Genres: []string{"Action"}},
+		{ServiceID: "2", Title: "Planned Action", Status: tracker.StatusPlanToWatch, TotalEpisodes: 12, Genres: []string{"Action", "Drama"}},
+		{ServiceID: "3", Title: "Paused Long", Status: tracker.StatusOnHold, TotalEpisodes: 64, Genres: []string{"Action"}},
+		{ServiceID: "4", Title: "Planned Movie", Status: tracker.StatusPlanToWatch, TotalEpisodes: 1, Genres: []string{"Romance"}},
+		{ServiceID: "5", Title: "Planned Unknown", Status: tracker.StatusPlanToWatch},
+	}
+}
+
+func titles(media []tracker.TrackedMedia) []string {
+	var names []string
+	for _, m := range media {
+		names = append(names, m.Title)
+	}
+	return names
+}
+
+func TestRandomCandidates(t *testing.T) {
+	m := New()
+	m.SetLibrary(randomLibrary())
+
+	assert.Equal(t, []string{"Any genre", "Action", "Drama", "Romance"}, m.randomGenres(), "genres of Planning and Paused only")
+	assert.Equal(t, []string{"Planned Action", "Paused Long", "Planned Movie", "Planned Unknown"}, titles(m.randomCandidates()))
+
+	m.random.genre = 1 // Action
+	assert.Equal(t, []string{"Planned Action", "Paused Long"}, titles(m.randomCandidates()))
+
+	m.random.length = 4 // Long
+	assert.Equal(t, []string{"Paused Long"}, titles(m.randomCandidates()))
+
+	m.random.genre = 0
+	m.random.length = 1 // Single episode
+	assert.Equal(t, []string{"Planned Movie"}, titles(m.randomCandidates()), "unknown lengths only pass any length")
+}
+
+func TestRandomPickPlays(t *testing.T) {
+	m := New()
+	m.FilterByStatus("")
+	m.SetLibrary(randomLibrary())
+	key := func(k string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)} }
+
+	m, _ = m.handleLibraryViewKeyPress(key("R"))
+	require.True(t, m.random.open)
+	assert.Contains(t, m.View(), "Watch Something")
+
+	// Length: Long leaves one entry to pick
+	m, _ = m.handleLibraryViewKeyPress(key("j"))
+	for range 4 {
+		m, _ = m.handleLibraryViewKeyPress(key("l"))
+	}
+	m, cmd := m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.False(t, m.random.open)
+	msg, ok := cmd().(RandomPickMsg)
+	require.True(t, ok)
+	assert.Equal(t, "Paused Long", msg.Media.Title)
+
+	// Nothing to pick keeps the dialog open
+	m.SetLibrary(nil)
+	m, _ = m.handleLibraryViewKeyPress(key("R"))
+	m, cmd = m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.True(t, m.random.open)
+	m, _ = m.handleLibraryViewKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.random.open)
+}
@@ -0,0 +1,138 @@
+package anilist
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// SetSmartLists sets the saved filters shown as library tabs
+func (m *Model) SetSmartLists(lists []config.SmartList) {
+	m.smartLists = lists
+	if m.smartList >= len(lists) {
+		m.smartList = -1
+	}
+}
+
+// SetLastWatched sets when each AniList media was last watched locally
+func (m *Model) SetLastWatched(lastWatched map[int]time.Time) {
+	m.lastWatched = lastWatched
+}
+
+// activeSmartList returns the selected smart list, or nil for a status filter
+func (m Model) activeSmartList() *config.SmartList {
+	if m.smartList < 0 || m.smartList >= len(m.smartLists) {
+		return nil
+	}
+	return &m.smartLists[m.smartList]
+}
+
+// matchesSmartList reports whether media passes every criterion of a smart list
+func (m Model) matchesSmartList(list config.SmartList, media tracker.TrackedMedia) bool {
+	if len(list.Status) > 0 {
+		matched := false
+		for _, status := range list.Status {
+			if parsed, err := tracker.ParseWatchStatus(strings.ToLower(status)); err == nil && parsed == media.Status {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if list.Airing && !media.IsAiring() {
+		return false
+	}
+	if list.Behind {
+		aired := media.AiredEpisodes()
+		if aired == 0 || media.Progress >= aired {
+			return false
+		}
+	}
+	if list.MinScore > 0 && media.AverageScore < list.MinScore {
+		return false
+	}
+	if list.MinUserScore > 0 && media.Score < list.MinUserScore {
+		return false
+	}
+
+	if list.WatchedWithin > 0 || list.UnwatchedFor > 0 {
+		var watchedAt time.Time
+		if id, err := strconv.Atoi(media.ServiceID); err == nil {
+			watchedAt = m.lastWatched[id]
+		}
+		since := time.Since(watchedAt)
+		if list.WatchedWithin > 0 && (watchedAt.IsZero() || since > list.WatchedWithin) {
+			return false
+		}
+		if list.UnwatchedFor > 0 && !watchedAt.IsZero() && since < list.UnwatchedFor {
+			return false
+		}
+	}
+
+	return true
+}
+
+// activeTab returns the index of the selected tab: 0 for Watching, 1 for All,
+// then the smart lists. Other status filters have no tab and return -1.
+func (m Model) activeTab() int {
+	if m.activeSmartList() != nil {
+		return m.smartList + 2
+	}
+	switch m.statusFilter {
+	case "CURRENT":
+		return 0
+	case "":
+		return 1
+	}
+	return -1
+}
+
+// cycleTab moves between the Watching, All and smart list tabs
+func (m *Model) cycleTab(delta int) {
+	count := len(m.smartLists) + 2
+	current := m.activeTab()
+	if current < 0 {
+		current = 0
+	}
+
+	next := ((current+delta)%count + count) % count
+	switch next {
+	case 0:
+		m.statusFilter = "CURRENT"
+		m.smartList = -1
+	case 1:
+		m.statusFilter = ""
+		m.smartList = -1
+	default:
+		m.smartList = next - 2
+	}
+	m.currentIndex = 0
+}
+
+// renderTabs renders the library tabs, highlighting the active one
+func (m Model) renderTabs(watchingName string) string {
+	names := []string{watchingName, "All"}
+	for _, list := range m.smartLists {
+		names = append(names, list.Name)
+	}
+
+	active := m.activeTab()
+	tabs := make([]string, len(names))
+	for i, name := range names {
+		if i == active {
+			tabs[i] = styles.AniListHeaderStyle.Render(" " + name + " ")
+		} else {
+			tabs[i] = styles.AniListMetadataStyle.Render(" " + name + " ")
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
@@ -0,0 +1,54 @@
+package anilist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+func TestSmartListAiringBehind(t *testing.T) {
+	m := New()
+	m.SetLibrary([]tracker.TrackedMedia{
+		{ServiceID: "1", Title: "Behind", Status: tracker.StatusWatching, Progress: 3, AiringStatus: "RELEASING", NextEpisode: 6, AverageScore: 8.4},
+		{ServiceID: "2", Title: "Caught up", Status: tracker.StatusWatching, Progress: 5, AiringStatus: "RELEASING", NextEpisode: 6, AverageScore: 8.9},
+		{ServiceID: "3", Title: "Low score", Status: tracker.StatusWatching, Progress: 1, AiringStatus: "RELEASING", NextEpisode: 6, AverageScore: 6.5},
+		{ServiceID: "4", Title: "Finished", Status: tracker.StatusWatching, Progress: 1, AiringStatus: "FINISHED", TotalEpisodes: 12, AverageScore: 9.0},
+	})
+	m.SetSmartLists([]config.SmartList{
+		{Name: "Airing & behind", Status: []string{"watching"}, Airing: true, Behind: true, MinScore: 8.0},
+	})
+
+	m.cycleTab(-1)
+	filtered := m.GetFilteredLibrary()
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "Behind", filtered[0].Title)
+	}
+
+	// Cycling past the last smart list wraps to Watching
+	m.cycleTab(1)
+	assert.Equal(t, 0, m.activeTab())
+	assert.Len(t, m.GetFilteredLibrary(), 4)
+}
+
+func TestSmartListLocalHistory(t *testing.T) {
+	m := New()
+	m.SetLastWatched(map[int]time.Time{
+		1: time.Now().Add(-2 * time.Hour),
+		2: time.Now().Add(-60 * 24 * time.Hour),
+	})
+
+	recent := config.SmartList{Name: "Recent", WatchedWithin: 24 * time.Hour}
+	stalled := config.SmartList{Name: "Stalled", UnwatchedFor: 30 * 24 * time.Hour}
+
+	assert.True(t, m.matchesSmartList(recent, tracker.TrackedMedia{ServiceID: "1"}))
+	assert.False(t, m.matchesSmartList(recent, tracker.TrackedMedia{ServiceID: "2"}))
+	assert.False(t, m.matchesSmartList(recent, tracker.TrackedMedia{ServiceID: "3"}))
+
+	assert.False(t, m.matchesSmartList(stalled, tracker.TrackedMedia{ServiceID: "1"}))
+	assert.True(t, m.matchesSmartList(stalled, tracker.TrackedMedia{ServiceID: "2"}))
+	assert.True(t, m.matchesSmartList(stalled, tracker.TrackedMedia{ServiceID: "3"}))
+}
@@ -84,6 +84,20 @@ func (m Model) handleLibraryViewKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.random.open {
+		return m.handleRandomKeys(msg)
+	}
+	if m.planning.open {
+		return m.handlePlanningKeys(msg)
+	}
+
+	// Marking and bulk actions, unless the fuzzy filter is being edited
+	if !m.fuzzySearch.IsActive() || m.fuzzySearch.IsLocked() {
+		if updated, cmd, handled := m.handleMarkKeys(msg); handled {
+			return updated, cmd
+		}
+	}
+
 	// If fuzzy search is active, handle it based on locked state
 	if m.fuzzySearch.IsActive() {
 		if m.fuzzySearch.IsLocked() {
@@ -199,15 +213,26 @@ func (m Model) handleLibraryViewKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 			case "w":
 				// Switch to watching filter
 				m.statusFilter = "CURRENT"
+				m.smartList = -1
 				m.currentIndex = 0
 				m.fuzzySearch.Deactivate()
 				return m, nil
 			case "a":
 				// Switch to all anime
 				m.statusFilter = ""
+				m.smartList = -1
 				m.currentIndex = 0
 				m.fuzzySearch.Deactivate()
 				return m, nil
+			case "tab", "shift+tab":
+				// Switch tabs
+				if msg.String() == "tab" {
+					m.cycleTab(1)
+				} else {
+					m.cycleTab(-1)
+				}
+				m.fuzzySearch.Deactivate()
+				return m, nil
 			case "n":
 				// Search new anime
 				m.fuzzySearch.Deactivate()
@@ -255,6 +280,17 @@ func (m Model) handleLibraryViewKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case msg.String() == "R":
+		// Pick something to watch from Planning and Paused
+		m.random.open = true
+		m.random.field = 0
+		return m, nil
+
+	case msg.String() == "D":
+		// Queue every episode of the Planning list
+		m.openPlanningDownload()
+		return m, nil
+
 	case msg.String() == "/":
 		// Activate fuzzy search
 		cmd := m.fuzzySearch.Activate()
@@ -273,12 +309,20 @@ func (m Model) handleLibraryViewKeyPress(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keys.FilterWatching):
 		m.statusFilter = "CURRENT"
+		m.smartList = -1
 		m.currentIndex = 0
 
 	case key.Matches(msg, m.keys.FilterAll):
 		m.statusFilter = ""
+		m.smartList = -1
 		m.currentIndex = 0
 
+	case key.Matches(msg, m.keys.NextTab):
+		m.cycleTab(1)
+
+	case key.Matches(msg, m.keys.PrevTab):
+		m.cycleTab(-1)
+
 	case key.Matches(msg, m.keys.Select):
 		// Will be handled by parent to initiate playback
 		return m, func() tea.Msg {
@@ -429,6 +473,12 @@ func (m Model) handleSearchResultsViewKeyPress(msg tea.KeyMsg) (Model, tea.Cmd)
 
 	// Normal mode (fuzzy search not active)
 	switch {
+	case msg.String() == "R":
+		// Pick something to watch from Planning and Paused
+		m.random.open = true
+		m.random.field = 0
+		return m, nil
+
 	case msg.String() == "/":
 		// Activate fuzzy search
 		cmd := m.fuzzySearch.Activate()
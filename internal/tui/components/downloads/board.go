@@ -0,0 +1,347 @@
+package downloads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// boardColumn is a column of the board layout
+type boardColumn int
+
+const (
+	columnQueued boardColumn = iota
+	columnActive
+	columnCompleted
+	columnFailed
+	boardColumns
+)
+
+var boardTitles = [boardColumns]string{"QUEUED", "ACTIVE", "COMPLETED", "FAILED"}
+
+// cardHeight is the number of lines a card takes: two of text and a border
+const cardHeight = 4
+
+// columnOf returns the board column of a download status
+func columnOf(status downloader.DownloadStatus) boardColumn {
+	switch status {
+	case downloader.StatusDownloading, downloader.StatusProcessing, downloader.StatusUploading:
+		return columnActive
+	case downloader.StatusCompleted:
+		return columnCompleted
+	case downloader.StatusFailed, downloader.StatusCancelled:
+		return columnFailed
+	default:
+		return columnQueued
+	}
+}
+
+// boardMove returns the action moving a card one column left (dir -1) or
+// right (dir 1) stands for, or "" when the card can't move that way:
+//
+//	Queued → Active     resume a paused download, or start a queued one next
+//	Active → Queued     pause
+//	Active → Failed     cancel (completed is skipped, it can't be moved into)
+//	Failed → Active     retry
+//
+// Completed cards stay where they are.
+func boardMove(task downloader.DownloadTask, dir int) string {
+	switch columnOf(task.Status) {
+	case columnQueued:
+		if dir > 0 && task.Status == downloader.StatusPaused {
+			return "r"
+		}
+		if dir > 0 && task.Status == downloader.StatusQueued {
+			return "T"
+		}
+	case columnActive:
+		if dir < 0 && task.Status == downloader.StatusDownloading {
+			return "p"
+		}
+		if dir > 0 {
+			return "c"
+		}
+	case columnFailed:
+		if dir < 0 {
+			return "retry"
+		}
+	}
+	return ""
+}
+
+// boardTasks returns the indices into m.downloads of each column's cards.
+// Queued cards are in the order workers pick them up, paused ones last.
+func (m Model) boardTasks() [boardColumns][]int {
+	var columns [boardColumns][]int
+	for i, task := range m.downloads {
+		column := columnOf(task.Status)
+		columns[column] = append(columns[column], i)
+	}
+
+	queued := columns[columnQueued]
+	sort.SliceStable(queued, func(i, j int) bool {
+		pi, iQueued := m.queuePositions[m.downloads[queued[i]].ID]
+		pj, jQueued := m.queuePositions[m.downloads[queued[j]].ID]
+		if iQueued != jQueued {
+			return iQueued
+		}
+		return pi < pj
+	})
+	return columns
+}
+
+// selectedCard returns the download under the board cursor
+func (m Model) selectedCard() (downloader.DownloadTask, bool) {
+	cards := m.boardTasks()[m.boardColumn]
+	row := m.boardRows[m.boardColumn]
+	if row < 0 || row >= len(cards) {
+		return downloader.DownloadTask{}, false
+	}
+	return m.downloads[cards[row]], true
+}
+
+// syncBoardCursor keeps the board cursor on a card after the downloads
+// change: on the card last moved, which may have changed column, or within
+// each column's cards
+func (m *Model) syncBoardCursor() {
+	columns := m.boardTasks()
+	if m.boardFollow != "" {
+		for column, cards := range columns {
+			for row, i := range cards {
+				if m.downloads[i].ID == m.boardFollow {
+					m.boardColumn = boardColumn(column)
+					m.boardRows[column] = row
+				}
+			}
+		}
+	}
+	for column, cards := range columns {
+		m.boardRows[column] = max(min(m.boardRows[column], len(cards)-1), 0)
+	}
+}
+
+// handleBoardKeys handles keys while the board layout is shown
+func (m Model) handleBoardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "left", "h", "right", "l":
+		m.boardFollow = ""
+		if key == "left" || key == "h" {
+			m.boardColumn = max(m.boardColumn-1, 0)
+		} else {
+			m.boardColumn = min(m.boardColumn+1, boardColumns-1)
+		}
+	case "up", "k", "down", "j":
+		m.boardFollow = ""
+		count := len(m.boardTasks()[m.boardColumn])
+		row := &m.boardRows[m.boardColumn]
+		if key == "up" || key == "k" {
+			*row = max(*row-1, 0)
+		} else {
+			*row = max(min(*row+1, count-1), 0)
+		}
+	case "H", "shift+left", "L", "shift+right":
+		task, ok := m.selectedCard()
+		if !ok {
+			return m, nil
+		}
+		dir := 1
+		if key == "H" || key == "shift+left" {
+			dir = -1
+		}
+		action := boardMove(task, dir)
+		if action == "" {
+			return m, nil
+		}
+		// Follow the card into the column it lands in
+		m.boardFollow = task.ID
+		return m.taskAction(task, action)
+	case "enter", " ", "p", "r", "c", "D", "delete", "K", "J", "T", "R", "i", "A":
+		task, ok := m.selectedCard()
+		if !ok {
+			return m, nil
+		}
+		if key == "R" {
+			key = "retry"
+		}
+		m.boardFollow = task.ID
+		return m.taskAction(task, key)
+	case "x":
+		return m, m.clearCompleted()
+	case "ctrl+r":
+		return m, m.fetchDownloads()
+	case "b", "esc":
+		m.boardView = false
+		m.boardFollow = ""
+	case "q":
+		m.tickerRunning = false
+		return m, func() tea.Msg {
+			return common.GoToHomeMsg{}
+		}
+	}
+	return m, nil
+}
+
+// renderBoard renders the downloads as Queued / Active / Completed / Failed
+// columns of cards. Narrow terminals show the focused column only, with the
+// others as tabs above it.
+func (m Model) renderBoard() string {
+	columns := m.boardTasks()
+
+	width := m.width
+	if width <= 0 {
+		width = 100
+	}
+	width -= 2 // Left margin
+	visible := []boardColumn{columnQueued, columnActive, columnCompleted, columnFailed}
+	var tabs string
+	if utils.Compact(m.width) {
+		visible = []boardColumn{m.boardColumn}
+		var parts []string
+		for column := range boardColumns {
+			label := fmt.Sprintf("%s %d", boardTitles[column], len(columns[column]))
+			if column == m.boardColumn {
+				label = styles.TitleStyle.Render(label)
+			} else {
+				label = styles.AniListMetadataStyle.Render(label)
+			}
+			parts = append(parts, label)
+		}
+		tabs = "  " + utils.Truncate(strings.Join(parts, " "), width) + "\n"
+	}
+
+	const gap = 1
+	columnWidth := (width - gap*(len(visible)-1)) / len(visible)
+
+	// Room for cards below the header, count, speed graph, column titles and
+	// help
+	maxCards := 4
+	if m.height > 0 {
+		chrome := 9
+		if tabs != "" {
+			chrome++
+		}
+		if m.showTotalSpeed() {
+			chrome++
+		}
+		maxCards = max((m.height-chrome)/cardHeight, 1)
+	}
+
+	rendered := make([]string, 0, len(visible))
+	for _, column := range visible {
+		rendered = append(rendered, m.renderBoardColumn(column, columns[column], columnWidth, maxCards))
+	}
+	return tabs + lipgloss.NewStyle().MarginLeft(2).Render(joinColumns(rendered, gap))
+}
+
+// joinColumns lays columns side by side, gap cells apart
+func joinColumns(columns []string, gap int) string {
+	parts := make([]string, 0, len(columns)*2)
+	for i, column := range columns {
+		if i > 0 {
+			parts = append(parts, strings.Repeat(" ", gap))
+		}
+		parts = append(parts, column)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+}
+
+// renderBoardColumn renders a column title and the cards around the
+// column's cursor
+func (m Model) renderBoardColumn(column boardColumn, cards []int, width, maxCards int) string {
+	focused := column == m.boardColumn
+
+	title := fmt.Sprintf("%s (%d)", boardTitles[column], len(cards))
+	titleStyle := styles.SubtitleStyle
+	if focused {
+		titleStyle = styles.TitleStyle
+	}
+	lines := []string{titleStyle.Render(utils.Truncate(title, width-2))}
+
+	// Scroll to keep the cursor in view
+	row := m.boardRows[column]
+	start := 0
+	if row >= maxCards {
+		start = row - maxCards + 1
+	}
+	end := min(start+maxCards, len(cards))
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderCard(m.downloads[cards[i]], focused && i == row, width))
+	}
+	if len(cards) == 0 {
+		lines = append(lines, styles.AniListMetadataStyle.Render("  empty"))
+	} else if end < len(cards) {
+		lines = append(lines, styles.AniListMetadataStyle.Render(fmt.Sprintf("  +%d more", len(cards)-end)))
+	}
+
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// renderCard renders a download as a card width cells wide
+func (m Model) renderCard(task downloader.DownloadTask, selected bool, width int) string {
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonBase02).
+		Padding(0, 1)
+	titleStyle := styles.AniListTitleStyle
+	metaStyle := styles.AniListMetadataStyle
+	if selected {
+		border = border.Border(lipgloss.ThickBorder()).BorderForeground(styles.OxocarbonPurple)
+		titleStyle = titleStyle.Foreground(styles.OxocarbonPurple)
+		metaStyle = metaStyle.Foreground(styles.OxocarbonMauve)
+	}
+	inner := max(width-border.GetHorizontalFrameSize(), 1)
+
+	title := task.MediaTitle
+	switch {
+	case task.MediaType == providers.MediaTypeManga && task.Episode > 0:
+		title = fmt.Sprintf("%s - Ch %d", title, task.Episode)
+	case task.Season > 0 && task.Episode > 0:
+		title = fmt.Sprintf("%s - S%02dE%02d", title, task.Season, task.Episode)
+	case task.Episode > 0:
+		title = fmt.Sprintf("%s - Ep %d", title, task.Episode)
+	}
+
+	var meta []string
+	switch task.Status {
+	case downloader.StatusDownloading, downloader.StatusProcessing, downloader.StatusUploading:
+		meta = append(meta, fmt.Sprintf("%s %.0f%%", getStatusIcon(task.Status), task.Progress))
+		if task.Speed > 0 {
+			meta = append(meta, humanize.Bytes(uint64(task.Speed))+"/s")
+		}
+		if task.ETA > 0 {
+			meta = append(meta, formatDuration(task.ETA))
+		}
+	case downloader.StatusCompleted:
+		meta = append(meta, getStatusIcon(task.Status))
+		if task.TotalBytes > 0 {
+			meta = append(meta, humanize.Bytes(uint64(task.TotalBytes)))
+		}
+	case downloader.StatusFailed, downloader.StatusCancelled:
+		meta = append(meta, fmt.Sprintf("%s %s", getStatusIcon(task.Status), task.Status))
+		if task.Error != "" {
+			meta = append(meta, task.Error)
+		}
+	case downloader.StatusPaused:
+		meta = append(meta, fmt.Sprintf("%s %.0f%%", getStatusIcon(task.Status), task.Progress))
+	default:
+		meta = append(meta, getStatusIcon(task.Status))
+		if pos, ok := m.queuePositions[task.ID]; ok {
+			meta = append(meta, fmt.Sprintf("#%d", pos))
+		}
+	}
+
+	content := titleStyle.Render(utils.PadRight(title, inner)) + "\n" +
+		metaStyle.Render(utils.PadRight(strings.Join(meta, " • "), inner))
+	return border.Render(content)
+}
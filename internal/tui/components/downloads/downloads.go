@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
 )
 
 // SortMode represents how to sort downloads
@@ -42,10 +44,32 @@ type Model struct {
 	height           int
 	autoRefresh      bool
 	tickerRunning    bool // Track if auto-refresh ticker is running
+	refreshInterval  time.Duration
 	fuzzySearch      *common.FuzzySearch
 	progressBar      progress.Model // Beautiful gradient progress bar
 	groupedView      bool           // Toggle between grouped and flat view
 	sortMode         SortMode       // Current sort mode
+	queuePositions   map[string]int // Task ID -> 1-based position among queued tasks
+	diskSpace        *downloader.DiskSpace
+
+	// Throughput over the last minute, one sample a second (see speedgraph.go)
+	speeds     map[string][]int64 // Task ID -> speed, for tasks that downloaded anything
+	totalSpeed []int64            // All downloads together
+
+	concurrency int // Downloads run at once, 0 when unknown (see totals.go)
+
+	// Board layout (see board.go)
+	boardView   bool
+	boardColumn boardColumn
+	boardRows   [boardColumns]int // Cursor row of each column
+	boardFollow string            // ID of the card last acted on, kept under the cursor
+
+	// Duplicate downloads dialog (see duplicates.go)
+	showDuplicates bool
+	duplicates     []downloader.DuplicateSet
+
+	// Failure details dialog (see failure.go)
+	failure *downloader.DownloadTask
 
 	// Delete confirmation dialog
 	showDeleteDialog bool
@@ -61,6 +85,7 @@ type DownloadGroup struct {
 	ActiveCount    int     // Number of active downloads
 	CompletedCount int     // Number of completed downloads
 	FailedCount    int     // Number of failed downloads
+	DiskUsage      int64   // Bytes on disk, including partial downloads
 }
 
 // displayItem represents an item in the display list (either a group header or an episode)
@@ -87,6 +112,8 @@ func getStatusIcon(status downloader.DownloadStatus) string {
 		return "⦸"
 	case downloader.StatusProcessing:
 		return "⚙"
+	case downloader.StatusUploading:
+		return "⇪"
 	default:
 		return "?"
 	}
@@ -168,26 +195,43 @@ func (m Model) renderDownloadItem(task downloader.DownloadTask, selected bool) s
 		metaStyle = metaStyle.Foreground(styles.OxocarbonMauve)
 	}
 
-	// Build title
+	// Episodes are indented under their show in the grouped view
+	width := m.width
+	if m.groupedView {
+		width -= 2
+	}
+	itemWidth := utils.ItemWidth(width)
+	compact := utils.Compact(m.width)
+
+	// Build title, abbreviated in the compact layout
 	title := task.MediaTitle
 	if task.MediaType == providers.MediaTypeManga {
 		// For manga, show chapter instead of episode
-		if task.Episode > 0 {
+		if task.Episode > 0 && compact {
+			title = fmt.Sprintf("%s - Ch %d", title, task.Episode)
+		} else if task.Episode > 0 {
 			title = fmt.Sprintf("%s - Chapter %d", title, task.Episode)
 		}
 	} else {
 		// For video content
-		if task.Episode > 0 {
-			title = fmt.Sprintf("%s - Episode %d", title, task.Episode)
-		}
-		if task.Season > 0 {
-			title = fmt.Sprintf("%s (S%02d)", title, task.Season)
+		switch {
+		case compact && task.Season > 0 && task.Episode > 0:
+			title = fmt.Sprintf("%s - S%02dE%02d", title, task.Season, task.Episode)
+		case compact && task.Episode > 0:
+			title = fmt.Sprintf("%s - Ep %d", title, task.Episode)
+		default:
+			if task.Episode > 0 {
+				title = fmt.Sprintf("%s - Episode %d", title, task.Episode)
+			}
+			if task.Season > 0 {
+				title = fmt.Sprintf("%s (S%02d)", title, task.Season)
+			}
 		}
-		if task.Quality != "" {
+		if task.Quality != "" && !compact {
 			title = fmt.Sprintf("%s [%s]", title, task.Quality)
 		}
 	}
-	titleStr := titleStyle.Render(title)
+	titleStr := titleStyle.Render(utils.Truncate(title, itemWidth))
 
 	// Build status metadata
 	statusIcon := getStatusIcon(task.Status)
@@ -196,7 +240,7 @@ func (m Model) renderDownloadItem(task downloader.DownloadTask, selected bool) s
 	// Status badge with color
 	statusColor := styles.OxocarbonBase04
 	switch task.Status {
-	case downloader.StatusDownloading:
+	case downloader.StatusDownloading, downloader.StatusUploading:
 		statusColor = styles.OxocarbonGreen
 	case downloader.StatusCompleted:
 		statusColor = styles.OxocarbonBlue
@@ -207,11 +251,15 @@ func (m Model) renderDownloadItem(task downloader.DownloadTask, selected bool) s
 	case downloader.StatusQueued:
 		statusColor = styles.OxocarbonPurple
 	}
-	statusBadge := styles.StatusBadgeStyle.Foreground(statusColor).Render(fmt.Sprintf("%s %s", statusIcon, task.Status))
+	statusText := fmt.Sprintf("%s %s", statusIcon, task.Status)
+	if compact {
+		statusText = statusIcon
+	}
+	statusBadge := styles.StatusBadgeStyle.Foreground(statusColor).Render(statusText)
 	metaParts = append(metaParts, statusBadge)
 
 	// Progress and additional info based on status
-	if task.Status == downloader.StatusDownloading || task.Status == downloader.StatusProcessing {
+	if task.Status.IsActive() {
 		progressBar := m.renderProgressBar(task.Progress)
 		// Don't use fmt.Sprintf with styled string - append directly
 		metaParts = append(metaParts, progressBar)
@@ -219,7 +267,9 @@ func (m Model) renderDownloadItem(task downloader.DownloadTask, selected bool) s
 		if task.Speed > 0 {
 			metaParts = append(metaParts, humanize.Bytes(uint64(task.Speed))+"/s")
 		}
-		if task.ETA > 0 {
+		if task.ETA > 0 && compact {
+			metaParts = append(metaParts, formatDuration(task.ETA))
+		} else if task.ETA > 0 {
 			metaParts = append(metaParts, "ETA: "+formatDuration(task.ETA))
 		}
 	} else if task.Status == downloader.StatusCompleted {
@@ -236,21 +286,32 @@ func (m Model) renderDownloadItem(task downloader.DownloadTask, selected bool) s
 				metaParts = append(metaParts, humanize.Bytes(uint64(task.TotalBytes)))
 			}
 		}
+		if task.RemotePath != "" {
+			metaParts = append(metaParts, "☁ "+utils.Truncate(task.RemotePath, 30))
+		}
 		if task.CompletedAt != nil {
 			metaParts = append(metaParts, humanize.Time(*task.CompletedAt))
 		}
 	} else if task.Status == downloader.StatusFailed && task.Error != "" {
-		errMsg := task.Error
-		if len(errMsg) > 40 {
-			errMsg = errMsg[:37] + "..."
+		metaParts = append(metaParts, utils.Truncate(task.Error, 40))
+	} else if task.Status == downloader.StatusPaused && (task.Progress > 0 || task.Error != "") {
+		if task.Progress > 0 {
+			metaParts = append(metaParts, fmt.Sprintf("%.1f%%", task.Progress))
 		}
-		metaParts = append(metaParts, errMsg)
-	} else if task.Status == downloader.StatusPaused && task.Progress > 0 {
-		metaParts = append(metaParts, fmt.Sprintf("%.1f%%", task.Progress))
+		if task.Error != "" {
+			metaParts = append(metaParts, utils.Truncate(task.Error, 40))
+		}
+	} else if pos, ok := m.queuePositions[task.ID]; ok {
+		metaParts = append(metaParts, fmt.Sprintf("#%d in queue", pos))
 	}
 
-	metaStr := metaStyle.Render(strings.Join(metaParts, " • "))
+	metaStr := metaStyle.Render(utils.Truncate(strings.Join(metaParts, " • "), itemWidth))
 	content := titleStr + "\n" + metaStr
+	if selected {
+		if graph := m.speedGraph(task.ID, itemWidth); graph != "" {
+			content += "\n" + metaStyle.Render(utils.Truncate(graph, itemWidth))
+		}
+	}
 
 	return boxStyle.Render(content)
 }
@@ -272,13 +333,17 @@ func (m Model) renderGroupItem(group *DownloadGroup, selected bool) string {
 	if m.expandedGroups[group.MediaTitle] {
 		expandIndicator = "▼"
 	}
-	titleStr := titleStyle.Render(fmt.Sprintf("%s %s", expandIndicator, group.MediaTitle))
+	titleText := fmt.Sprintf("%s %s", expandIndicator, group.MediaTitle)
 
 	// Build statistics - more compact
 	var metaParts []string
 
 	// Episode count
+	compact := utils.Compact(m.width)
 	metaParts = append(metaParts, fmt.Sprintf("%d eps", len(group.Tasks)))
+	if group.DiskUsage > 0 && !compact {
+		metaParts = append(metaParts, humanize.IBytes(uint64(group.DiskUsage)))
+	}
 
 	// Status badges - compact
 	if group.ActiveCount > 0 {
@@ -301,15 +366,26 @@ func (m Model) renderGroupItem(group *DownloadGroup, selected bool) string {
 		if m.width > 80 {
 			progressWidth = 20
 		}
-		oldWidth := m.progressBar.Width
-		m.progressBar.Width = progressWidth
-		progressBar := m.renderProgressBar(group.TotalProgress)
-		m.progressBar.Width = oldWidth
-		metaParts = append(metaParts, progressBar)
+		if !compact {
+			oldWidth := m.progressBar.Width
+			m.progressBar.Width = progressWidth
+			progressBar := m.renderProgressBar(group.TotalProgress)
+			m.progressBar.Width = oldWidth
+			metaParts = append(metaParts, progressBar)
+		}
 		metaParts = append(metaParts, fmt.Sprintf("%.0f%%", group.TotalProgress))
 	}
 
 	metaStr := metaStyle.Render(strings.Join(metaParts, " "))
+
+	// Title and stats share one line; keep the stats and shorten the title
+	titleWidth := utils.ItemWidth(m.width)
+	if titleWidth > 0 {
+		minTitle := utils.Width(expandIndicator) + 4
+		metaStr = utils.Truncate(metaStr, titleWidth-minTitle-1)
+		titleWidth = max(titleWidth-utils.Width(metaStr)-1, minTitle)
+	}
+	titleStr := titleStyle.Render(utils.Truncate(titleText, titleWidth))
 	content := titleStr + " " + metaStr
 
 	return boxStyle.Render(content)
@@ -332,7 +408,7 @@ func (m Model) getFilteredIndices() []int {
 func (m Model) getVisibleRange(total int) (int, int) {
 	maxVisible := 8
 	if m.height > 0 {
-		itemsSpace := m.height - 8
+		itemsSpace := m.height - 8 - m.graphLines()
 		if itemsSpace > 0 {
 			maxVisible = itemsSpace / 3
 		}
@@ -415,8 +491,11 @@ func (m Model) handleAction(action string) (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	task := m.downloads[item.taskIndex]
+	return m.taskAction(m.downloads[item.taskIndex], action)
+}
 
+// taskAction performs an action on a single download
+func (m Model) taskAction(task downloader.DownloadTask, action string) (Model, tea.Cmd) {
 	switch action {
 	case "enter", " ":
 		// Open the downloaded file if completed
@@ -436,10 +515,32 @@ func (m Model) handleAction(action string) (Model, tea.Cmd) {
 		if task.Status == downloader.StatusFailed || task.Status == downloader.StatusCancelled {
 			return m, m.retryDownload(task.ID)
 		}
+	case "A":
+		// Download failed or cancelled downloads again from another provider
+		if task.Status == downloader.StatusFailed || task.Status == downloader.StatusCancelled {
+			return m, func() tea.Msg {
+				return common.RetryFromOtherProviderMsg{TaskID: task.ID}
+			}
+		}
 	case "c":
 		if !task.Status.IsComplete() {
 			return m, m.cancelDownload(task.ID)
 		}
+	case "K", "J", "T":
+		// Reorder queued downloads
+		if task.Status == downloader.StatusQueued {
+			offset := 1
+			switch action {
+			case "K":
+				offset = -1
+			case "T":
+				offset = -len(m.downloads)
+			}
+			return m, m.moveDownload(task.ID, offset)
+		}
+	case "i":
+		// Show why a download failed
+		return m.showFailureDetails(task)
 	case "D", "delete":
 		// Show delete confirmation
 		m.showDeleteDialog = true
@@ -454,6 +555,11 @@ func (m Model) handleAction(action string) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// IsInputActive returns true if the fuzzy search input is active and not locked
+func (m Model) IsInputActive() bool {
+	return m.fuzzySearch.IsActive() && !m.fuzzySearch.IsLocked()
+}
+
 // Refresh fetches the latest downloads from the manager and starts auto-refresh
 func (m Model) Refresh() tea.Cmd {
 	// Always fetch downloads, but only start ticker if not already running
@@ -466,10 +572,19 @@ func (m Model) Refresh() tea.Cmd {
 	)
 }
 
+// SetRefreshInterval sets how often progress is refreshed
+func (m *Model) SetRefreshInterval(interval time.Duration) {
+	m.refreshInterval = interval
+}
+
 // startRefreshTicker starts the periodic refresh ticker
 func (m Model) startRefreshTicker() tea.Cmd {
+	interval := m.refreshInterval
+	if interval <= 0 {
+		interval = 300 * time.Millisecond // Refresh every 300ms for snappy updates
+	}
 	return func() tea.Msg {
-		time.Sleep(300 * time.Millisecond) // Refresh every 300ms for snappy updates
+		time.Sleep(interval)
 		return common.DownloadsTickMsg{}
 	}
 }
@@ -486,6 +601,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Update progress bar width based on terminal size
 		progressWidth := 20
+		if utils.Compact(msg.Width) {
+			progressWidth = 10
+		}
 		if msg.Width > 80 {
 			progressWidth = 30
 		}
@@ -506,7 +624,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					cmd = m.deleteShowFolder(m.deleteTaskTitle)
 				} else {
 					// Delete single file
-					cmd = m.deleteFile(m.deleteTaskID)
+					cmd = m.deleteFile(m.deleteTaskID, m.deleteTaskTitle)
 				}
 				m.showDeleteDialog = false
 				m.deleteTaskID = ""
@@ -522,6 +640,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.showDuplicates {
+			return m.handleDuplicatesKeys(msg)
+		}
+
+		if m.failure != nil {
+			return m.handleFailureKeys(msg)
+		}
+
 		// Fuzzy search mode
 		if m.fuzzySearch.IsActive() {
 			switch msg.String() {
@@ -540,7 +666,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.currentIndex++
 				}
 				return m, nil
-			case "p", "r", "c", "D", "delete":
+			case "p", "r", "c", "D", "delete", "i", "A":
 				return m.handleAction(msg.String())
 			default:
 				// Pass other keys to fuzzy search input
@@ -550,6 +676,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		switch msg.String() {
+		case "+", "=":
+			return m.adjustConcurrency(1)
+		case "-":
+			return m.adjustConcurrency(-1)
+		}
+
+		if m.boardView {
+			return m.handleBoardKeys(msg)
+		}
+
 		// Normal mode (fuzzy search not active)
 		maxIndex := len(m.displayItems) - 1
 		if maxIndex < 0 {
@@ -586,7 +723,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg {
 				return common.GoToHomeMsg{}
 			}
-		case "p", "r", "c", "D", "delete":
+		case "p", "r", "c", "D", "delete", "K", "J", "T", "i", "A":
 			return m.handleAction(msg.String())
 		case "R":
 			// Retry failed/cancelled download
@@ -594,9 +731,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "x":
 			// Clear completed downloads
 			return m, m.clearCompleted()
+		case "F":
+			// Find episodes downloaded more than once
+			return m, m.findDuplicates()
 		case "ctrl+r":
 			// Refresh list
 			return m, m.fetchDownloads()
+		case "b":
+			// Switch to the board layout
+			m.boardView = true
+			m.syncBoardCursor()
+			return m, nil
 		case "s":
 			// Cycle through sort modes
 			m.sortMode = (m.sortMode + 1) % 3
@@ -625,13 +770,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case duplicatesFoundMsg:
+		if msg.err != nil {
+			return m, nil
+		}
+		m.duplicates = msg.sets
+		m.showDuplicates = true
+
 	case common.DownloadCompleteMsg, common.DownloadErrorMsg:
 		// Refresh the downloads list
 		return m, m.fetchDownloads()
 
 	case downloadsRefreshMsg:
 		m.downloads = msg.downloads
+		m.speeds, m.totalSpeed = msg.speeds, msg.totalSpeed
+		if msg.concurrency > 0 {
+			m.concurrency = msg.concurrency
+		}
+		if msg.diskSpace != nil {
+			m.diskSpace = msg.diskSpace
+		}
 		m.buildGroupedView()
+		m.syncBoardCursor()
 		// Keep currentIndex valid
 		if m.currentIndex >= len(m.displayItems) {
 			m.currentIndex = len(m.displayItems) - 1
@@ -687,19 +847,45 @@ func (m Model) View() string {
 		sortModeStr = "by Progress"
 	}
 	header := styles.TitleStyle.Render(fmt.Sprintf("  DOWNLOADS (%s • %s)  ", viewMode, sortModeStr))
-	output += header + "\n"
+	if utils.Compact(m.width) {
+		header = styles.TitleStyle.Render(fmt.Sprintf("DOWNLOADS · %s · %s", viewMode, strings.TrimPrefix(sortModeStr, "by ")))
+	}
+	if m.boardView {
+		// The board has its own order, the sort mode doesn't apply
+		header = styles.TitleStyle.Render("  DOWNLOADS (BOARD)  ")
+		if utils.Compact(m.width) {
+			header = styles.TitleStyle.Render("DOWNLOADS · BOARD")
+		}
+	}
+	output += utils.Truncate(header, m.width) + "\n"
 
 	// Count
 	displayCount := len(m.displayItems)
-	if !m.groupedView {
+	if !m.groupedView || m.boardView {
 		displayCount = len(m.downloads)
 	}
 
 	count := styles.SubtitleStyle.Render(fmt.Sprintf("  %d items", displayCount))
-	if m.groupedView {
+	if m.groupedView && !m.boardView {
 		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" • %d shows", len(m.groupedDownloads)))
 	}
-	output += count + "\n"
+	count += styles.AniListMetadataStyle.Render(m.renderQueueTotals())
+	if m.diskSpace != nil && !m.diskSpace.Low() {
+		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" • %s free", humanize.IBytes(m.diskSpace.Free)))
+	}
+	output += utils.Truncate(count, m.width) + "\n"
+	if m.showTotalSpeed() {
+		output += m.renderTotalSpeed() + "\n"
+	}
+	if m.diskSpace != nil && m.diskSpace.Low() {
+		warning := fmt.Sprintf("  ⚠ Low disk space: %s free, downloads need %s (downloads.min_free_space)",
+			humanize.IBytes(m.diskSpace.Free), humanize.IBytes(m.diskSpace.Minimum))
+		if utils.Compact(m.width) {
+			warning = fmt.Sprintf("  ⚠ Low disk space: %s free, %s needed",
+				humanize.IBytes(m.diskSpace.Free), humanize.IBytes(m.diskSpace.Minimum))
+		}
+		output += styles.AniListMetadataStyle.Foreground(styles.OxocarbonRed).Render(utils.Truncate(warning, m.width)) + "\n"
+	}
 
 	// Fuzzy search
 	if m.fuzzySearch.IsActive() {
@@ -709,46 +895,60 @@ func (m Model) View() string {
 	}
 
 	// Render items
-	visibleStart, visibleEnd := m.getVisibleRange(len(m.displayItems))
-
-	for i := visibleStart; i < visibleEnd; i++ {
-		if i >= len(m.displayItems) {
-			break
-		}
-		item := m.displayItems[i]
-
-		if item.isGroup {
-			// Render group header
-			group := m.groupedDownloads[item.groupTitle]
-			output += m.renderGroupItem(group, i == m.currentIndex) + "\n"
-		} else {
-			// Render episode
-			if item.taskIndex < len(m.downloads) {
-				task := m.downloads[item.taskIndex]
-				// Add indentation for episodes under groups in grouped view
-				itemStr := m.renderDownloadItem(task, i == m.currentIndex)
-				if m.groupedView {
-					itemStr = "  " + itemStr
-				}
-				output += itemStr + "\n"
-			}
-		}
+	if m.boardView {
+		output += m.renderBoard() + "\n"
+	} else {
+		output += m.renderList()
 	}
 
 	// Help text - ultra compact to fit on screen
-	helpText := "  ↑/↓ • ⏎ expand/open • s sort • p/r pause/resume • R retry • c cancel • D del • x clear • esc back • q quit"
+	helpText := "  ↑/↓ • ⏎ expand/open • s sort • p/r pause/resume • K/J/T reorder • R/A retry here/elsewhere • i why failed • c cancel • D del • x clear • F dupes • +/- at once • esc back • q quit"
 	if !m.groupedView {
 		// In flat view, show that esc goes back to grouped
-		helpText = "  ↑/↓ • ⏎ open • s sort • p/r • R retry • c cancel • D del • x clear • esc grouped • q quit"
+		helpText = "  ↑/↓ • ⏎ open • s sort • p/r • K/J/T reorder • R/A retry here/elsewhere • i why failed • c cancel • D del • x clear • F dupes • +/- at once • esc grouped • q quit"
 	}
 	if m.fuzzySearch.IsActive() {
 		if m.fuzzySearch.IsLocked() {
-			helpText = "  ↑/↓ • p/r • R retry • c cancel • D del • / edit • esc clear • q quit"
+			helpText = "  ↑/↓ • p/r • R/A retry • i why failed • c cancel • D del • / edit • esc clear • q quit"
 		} else {
 			helpText = "  Type to filter • ↑/↓ • esc lock • q quit"
 		}
 	}
-	output += "\n" + styles.AniListHelpStyle.Render(helpText)
+	if m.boardView {
+		helpText = "  ←/→ column • ↑/↓ card • H/L move • ⏎ open • p/r • R/A retry • i why failed • c cancel • D del • x clear • +/- at once • b list • q quit"
+	}
+	// Narrow terminals get the essentials, the rest is in the ? help
+	shortHelp := "  ↑/↓ • ⏎ open • p/r • R retry • D del • esc back"
+	if m.boardView {
+		shortHelp = "  ←/→ • ↑/↓ • H/L move • ⏎ open • b list"
+	}
+	if !m.groupedView {
+		shortHelp = "  ↑/↓ • ⏎ open • p/r • R retry • D del • esc grouped"
+	}
+	if m.fuzzySearch.IsActive() && m.fuzzySearch.IsLocked() {
+		shortHelp = "  ↑/↓ • p/r • R retry • D del • esc clear"
+	}
+	output += "\n" + styles.AniListHelpStyle.Render(utils.FitHelp(m.width, helpText, shortHelp))
+
+	if m.showDuplicates {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.renderDuplicatesDialog(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#161616")),
+		)
+	}
+
+	if m.failure != nil {
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.renderFailureDialog(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#161616")),
+		)
+	}
 
 	// Render delete confirmation dialog
 	if m.showDeleteDialog {
@@ -759,7 +959,7 @@ func (m Model) View() string {
 			Render(fmt.Sprintf(
 				"%s\n\nAre you sure you want to delete this file?\n%s\n\n%s",
 				styles.TitleStyle.Foreground(styles.OxocarbonRed).Render("DELETE FILE"),
-				styles.AniListTitleStyle.Render(m.deleteTaskTitle),
+				styles.AniListTitleStyle.Render(utils.Truncate(m.deleteTaskTitle, m.width-8)),
 				styles.AniListHelpStyle.Render("(y) Confirm • (n/esc) Cancel"),
 			))
 
@@ -775,10 +975,42 @@ func (m Model) View() string {
 	return output
 }
 
+// renderList renders the visible groups and downloads of the list layout
+func (m Model) renderList() string {
+	var output string
+	visibleStart, visibleEnd := m.getVisibleRange(len(m.displayItems))
+
+	for i := visibleStart; i < visibleEnd; i++ {
+		if i >= len(m.displayItems) {
+			break
+		}
+		item := m.displayItems[i]
+
+		if item.isGroup {
+			// Render group header
+			group := m.groupedDownloads[item.groupTitle]
+			output += m.renderGroupItem(group, i == m.currentIndex) + "\n"
+		} else {
+			// Render episode
+			if item.taskIndex < len(m.downloads) {
+				task := m.downloads[item.taskIndex]
+				// Add indentation for episodes under groups in grouped view
+				itemStr := m.renderDownloadItem(task, i == m.currentIndex)
+				if m.groupedView {
+					itemStr = lipgloss.NewStyle().PaddingLeft(2).Render(itemStr)
+				}
+				output += itemStr + "\n"
+			}
+		}
+	}
+	return output
+}
+
 // SetDownloads updates the downloads list
 func (m *Model) SetDownloads(downloads []downloader.DownloadTask) {
 	m.downloads = downloads
 	m.buildGroupedView()
+	m.syncBoardCursor()
 
 	// Keep currentIndex valid
 	maxIndex := len(m.displayItems)
@@ -790,9 +1022,32 @@ func (m *Model) SetDownloads(downloads []downloader.DownloadTask) {
 	}
 }
 
+// buildQueuePositions numbers queued tasks in the order workers will pick
+// them up: highest priority first, then oldest first
+func (m *Model) buildQueuePositions() {
+	var queued []downloader.DownloadTask
+	for _, task := range m.downloads {
+		if task.Status == downloader.StatusQueued {
+			queued = append(queued, task)
+		}
+	}
+	sort.SliceStable(queued, func(i, j int) bool {
+		if queued[i].Priority != queued[j].Priority {
+			return queued[i].Priority > queued[j].Priority
+		}
+		return queued[i].CreatedAt.Before(queued[j].CreatedAt)
+	})
+
+	m.queuePositions = make(map[string]int, len(queued))
+	for i, task := range queued {
+		m.queuePositions[task.ID] = i + 1
+	}
+}
+
 // buildGroupedView organizes downloads by show
 func (m *Model) buildGroupedView() {
 	m.groupedDownloads = make(map[string]*DownloadGroup)
+	m.buildQueuePositions()
 
 	// Group downloads by MediaTitle
 	for _, task := range m.downloads {
@@ -832,9 +1087,15 @@ func (m *Model) buildGroupedView() {
 				totalProgress += task.Progress
 			}
 
+			if task.Status == downloader.StatusCompleted {
+				group.DiskUsage += task.TotalBytes
+			} else {
+				group.DiskUsage += task.BytesDownloaded
+			}
+
 			// Track status counts
 			switch task.Status {
-			case downloader.StatusDownloading, downloader.StatusProcessing, downloader.StatusQueued:
+			case downloader.StatusDownloading, downloader.StatusProcessing, downloader.StatusUploading, downloader.StatusQueued:
 				group.ActiveCount++
 			case downloader.StatusCompleted:
 				group.CompletedCount++
@@ -983,7 +1244,7 @@ func (m *Model) statusPriority(status downloader.DownloadStatus) int {
 		return 1
 	case downloader.StatusDownloading:
 		return 2
-	case downloader.StatusProcessing:
+	case downloader.StatusProcessing, downloader.StatusUploading:
 		return 3
 	case downloader.StatusQueued:
 		return 4
@@ -1008,7 +1269,18 @@ func (m Model) fetchDownloads() tea.Cmd {
 			return downloadsRefreshMsg{downloads: []downloader.DownloadTask{}}
 		}
 
-		return downloadsRefreshMsg{downloads: downloads}
+		msg := downloadsRefreshMsg{downloads: downloads, speeds: make(map[string][]int64)}
+		for _, task := range downloads {
+			if history := m.manager.SpeedHistory(task.ID); hasThroughput(history) {
+				msg.speeds[task.ID] = history
+			}
+		}
+		msg.totalSpeed = m.manager.TotalSpeedHistory()
+		msg.concurrency = m.manager.Concurrency()
+		if space, err := m.manager.DiskSpace(); err == nil {
+			msg.diskSpace = &space
+		}
+		return msg
 	}
 }
 
@@ -1029,6 +1301,23 @@ func (m Model) pauseDownload(id string) tea.Cmd {
 	}
 }
 
+// moveDownload moves a queued download through the queue
+func (m Model) moveDownload(id string, offset int) tea.Cmd {
+	return func() tea.Msg {
+		if m.manager == nil {
+			return nil
+		}
+		ctx := context.Background()
+		_ = m.manager.MoveInQueue(ctx, id, offset)
+		// Fetch updated downloads
+		downloads, err := m.manager.GetQueue(ctx)
+		if err != nil {
+			return downloadsRefreshMsg{downloads: []downloader.DownloadTask{}}
+		}
+		return downloadsRefreshMsg{downloads: downloads}
+	}
+}
+
 // resumeDownload resumes a download
 func (m Model) resumeDownload(id string) tea.Cmd {
 	return func() tea.Msg {
@@ -1100,24 +1389,17 @@ func (m Model) clearCompleted() tea.Cmd {
 	}
 }
 
-// deleteFile deletes a file and its task
-func (m Model) deleteFile(id string) tea.Cmd {
+// deleteFile moves a task and its file to the trash
+func (m Model) deleteFile(id, title string) tea.Cmd {
 	return func() tea.Msg {
 		if m.manager == nil {
 			return nil
 		}
-		ctx := context.Background()
-		_ = m.manager.DeleteTaskAndFile(ctx, id)
-		// Fetch updated downloads
-		downloads, err := m.manager.GetQueue(ctx)
-		if err != nil {
-			return downloadsRefreshMsg{downloads: []downloader.DownloadTask{}}
-		}
-		return downloadsRefreshMsg{downloads: downloads}
+		return trashTasks(m.manager, title, id)
 	}
 }
 
-// deleteShowFolder deletes the entire folder for a show (all episodes)
+// deleteShowFolder moves every episode of a show to the trash
 func (m Model) deleteShowFolder(showTitle string) tea.Cmd {
 	return func() tea.Msg {
 		if m.manager == nil {
@@ -1135,41 +1417,45 @@ func (m Model) deleteShowFolder(showTitle string) tea.Cmd {
 		ctx := context.Background()
 		downloads, err := m.manager.GetQueue(ctx)
 		if err != nil {
-			return downloadsRefreshMsg{downloads: []downloader.DownloadTask{}}
+			return common.TrashedMsg{Err: err}
 		}
 
-		// Collect show folder path for cleanup
+		var ids []string
 		var showFolder string
-
-		// Delete each task and file
 		for _, task := range downloads {
 			if task.MediaTitle == actualTitle {
-				// Get folder path before deleting
 				if showFolder == "" && task.OutputPath != "" {
 					showFolder = filepath.Dir(task.OutputPath)
 				}
-				// Delete task and file
-				_ = m.manager.DeleteTaskAndFile(ctx, task.ID)
+				ids = append(ids, task.ID)
 			}
 		}
-
-		// Try to remove the show folder (will only succeed if empty)
-		if showFolder != "" {
-			_ = os.RemoveAll(showFolder) // Use RemoveAll to recursively delete
+		if len(ids) == 0 {
+			return nil
 		}
 
-		// Give database a moment to commit changes
-		time.Sleep(100 * time.Millisecond)
+		msg := trashTasks(m.manager, actualTitle, ids...)
 
-		// Fetch updated downloads
-		downloads, err = m.manager.GetQueue(ctx)
-		if err != nil {
-			return downloadsRefreshMsg{downloads: []downloader.DownloadTask{}}
+		// Remove the show folder once its episodes are gone (only succeeds if empty)
+		if showFolder != "" {
+			_ = os.Remove(showFolder)
 		}
-		return downloadsRefreshMsg{downloads: downloads}
+		return msg
 	}
 }
 
+// trashTasks moves tasks to the trash and reports the result
+func trashTasks(manager *downloader.Manager, label string, ids ...string) tea.Msg {
+	item, err := manager.TrashTasks(context.Background(), label, ids...)
+	if err != nil {
+		return common.TrashedMsg{Err: err}
+	}
+	if item == nil {
+		return nil
+	}
+	return common.TrashedMsg{ID: item.ID, Label: item.Label}
+}
+
 // openFile opens a file with the system default application
 func openFile(path string) tea.Cmd {
 	return func() tea.Msg {
@@ -1193,4 +1479,9 @@ func openFile(path string) tea.Cmd {
 // downloadsRefreshMsg is an internal message for refreshing downloads
 type downloadsRefreshMsg struct {
 	downloads []downloader.DownloadTask
+	diskSpace *downloader.DiskSpace // nil keeps the last known value
+
+	speeds      map[string][]int64
+	totalSpeed  []int64
+	concurrency int
 }
@@ -0,0 +1,203 @@
+package downloads
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/tuitest"
+)
+
+var sizes = []struct{ width, height int }{{50, 15}, {60, 20}, {69, 24}, {80, 24}, {120, 40}}
+
+func sampleDownloads() []downloader.DownloadTask {
+	completed := time.Now().Add(-3 * time.Hour)
+	return []downloader.DownloadTask{
+		{ID: "a", MediaTitle: "Sousou no Frieren: The Journey's End", MediaType: providers.MediaTypeAnime, Episode: 1, Season: 1, Quality: providers.Quality1080p, Status: downloader.StatusDownloading, Progress: 42.5, Speed: 3 << 20, ETA: 95 * time.Second},
+		{ID: "b", MediaTitle: "Sousou no Frieren: The Journey's End", MediaType: providers.MediaTypeAnime, Episode: 2, Season: 1, Quality: providers.Quality1080p, Status: downloader.StatusCompleted, TotalBytes: 350 << 20, CompletedAt: &completed},
+		{ID: "c", MediaTitle: "Sousou no Frieren: The Journey's End", MediaType: providers.MediaTypeAnime, Episode: 3, Season: 1, Status: downloader.StatusFailed, Error: "failed to fetch segment 112: unexpected status 403 Forbidden"},
+		{ID: "d", MediaTitle: "進撃の巨人 The Final Season", MediaType: providers.MediaTypeAnime, Episode: 5, Status: downloader.StatusQueued},
+	}
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	for _, grouped := range []bool{true, false} {
+		for _, size := range sizes {
+			t.Run(fmt.Sprintf("grouped=%t/%dx%d", grouped, size.width, size.height), func(t *testing.T) {
+				m := New(nil)
+				updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+				m = updated.(Model)
+				m.groupedView = grouped
+				m.expandedGroups["Sousou no Frieren: The Journey's End"] = true
+				m.diskSpace = &downloader.DiskSpace{Free: 1 << 30, Minimum: 5 << 30}
+				m.SetDownloads(sampleDownloads())
+
+				tuitest.AssertFits(t, m.View(), size.width, size.height)
+			})
+		}
+	}
+}
+
+func TestBoardFitsTerminal(t *testing.T) {
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			m := New(nil)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(Model)
+			m.boardView = true
+			m.SetDownloads(sampleDownloads())
+
+			tuitest.AssertFits(t, m.View(), size.width, size.height)
+		})
+	}
+}
+
+func TestBoardMove(t *testing.T) {
+	tests := []struct {
+		status downloader.DownloadStatus
+		dir    int
+		want   string
+	}{
+		{downloader.StatusQueued, 1, "T"},
+		{downloader.StatusQueued, -1, ""},
+		{downloader.StatusPaused, 1, "r"},
+		{downloader.StatusDownloading, -1, "p"},
+		{downloader.StatusDownloading, 1, "c"},
+		{downloader.StatusProcessing, -1, ""},
+		{downloader.StatusCompleted, -1, ""},
+		{downloader.StatusCompleted, 1, ""},
+		{downloader.StatusFailed, -1, "retry"},
+		{downloader.StatusCancelled, -1, "retry"},
+		{downloader.StatusFailed, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.status, tt.dir), func(t *testing.T) {
+			assert.Equal(t, tt.want, boardMove(downloader.DownloadTask{Status: tt.status}, tt.dir))
+		})
+	}
+}
+
+func TestBoardTasksOrderQueue(t *testing.T) {
+	m := New(nil)
+	m.downloads = []downloader.DownloadTask{
+		{ID: "paused", Status: downloader.StatusPaused},
+		{ID: "second", Status: downloader.StatusQueued},
+		{ID: "first", Status: downloader.StatusQueued},
+		{ID: "done", Status: downloader.StatusCompleted},
+	}
+	m.queuePositions = map[string]int{"first": 1, "second": 2}
+
+	columns := m.boardTasks()
+	var queued []string
+	for _, i := range columns[columnQueued] {
+		queued = append(queued, m.downloads[i].ID)
+	}
+	assert.Equal(t, []string{"first", "second", "paused"}, queued)
+	assert.Equal(t, []int{3}, columns[columnCompleted])
+	assert.Empty(t, columns[columnActive])
+}
+
+func TestSpeedGraphsFitTerminal(t *testing.T) {
+	history := make([]int64, downloader.SpeedWindow/time.Second)
+	for i := range history {
+		history[i] = int64(i%7+1) << 20
+	}
+	for _, board := range []bool{false, true} {
+		for _, size := range sizes {
+			t.Run(fmt.Sprintf("board=%t/%dx%d", board, size.width, size.height), func(t *testing.T) {
+				m := New(nil)
+				updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+				m = updated.(Model)
+				m.boardView = board
+				m.groupedView = false
+				m.SetDownloads(sampleDownloads())
+				m.speeds = map[string][]int64{"a": history}
+				m.totalSpeed = history
+
+				view := m.View()
+				tuitest.AssertFits(t, view, size.width, size.height)
+				assert.Contains(t, view, "↓ 3.1 MB/s", "the header shows the current total speed")
+				if !board {
+					assert.Contains(t, view, "peak 7.3 MB/s", "the selected download shows its graph")
+				}
+			})
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, " ▁▄█", sparkline([]int64{0, 1, 50, 100}, 4))
+	assert.Equal(t, "▂█", sparkline([]int64{10, 20, 50, 70}, 2), "bars average the samples they cover")
+	assert.Equal(t, "  ", sparkline([]int64{0, 0}, 10), "never wider than the samples")
+	assert.Equal(t, "", sparkline(nil, 10))
+}
+
+func TestQueueTotals(t *testing.T) {
+	m := New(nil)
+	m.concurrency = 3
+	tasks := sampleDownloads()
+	tasks = append(tasks,
+		downloader.DownloadTask{ID: "e", MediaTitle: "Show", Episode: 6, Status: downloader.StatusQueued, ExpectedSize: 400e6},
+		downloader.DownloadTask{ID: "f", MediaTitle: "Show", Episode: 7, Status: downloader.StatusQueued, TotalBytes: 500e6, BytesDownloaded: 100e6},
+		downloader.DownloadTask{ID: "g", MediaTitle: "Show", Status: downloader.StatusUploading, Speed: 2e6},
+	)
+	m.SetDownloads(tasks)
+
+	count, size, unknown := m.queuedSize()
+	assert.Equal(t, 3, count)
+	assert.Equal(t, int64(800e6), size, "what is left of the tasks with a known size")
+	assert.True(t, unknown)
+	assert.Equal(t, " • 3 queued (≥800 MB) • 3 at once", m.renderQueueTotals())
+	assert.Equal(t, "↓ 3.1 MB/s ↑ 2.0 MB/s", m.speedLabel())
+}
+
+func TestFailureDetails(t *testing.T) {
+	failed := sampleDownloads()[2]
+	failed.Error = "ffmpeg exited with error: exit status 8\nOutput: [https @ 0x5581] HTTP error 403 Forbidden\nsegment-112.ts: Server returned 403 Forbidden (access denied)"
+	failed.OutputPath = "/downloads/Frieren - 003.mkv"
+	failed.Diagnostics = &downloader.Diagnostics{
+		Tool:     downloader.ToolFFmpeg,
+		URL:      "https://cdn.example/hls/frieren/3/playlist.m3u8?token=d41d8cd98f00b204e9800998ecf8427e",
+		Headers:  map[string]string{"Referer": "https://provider.example/", "User-Agent": "Mozilla/5.0"},
+		Status:   403,
+		Response: "token expired",
+		At:       time.Now(),
+	}
+
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			m := New(nil)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(Model)
+			m.SetDownloads(sampleDownloads())
+			m, _ = m.taskAction(failed, "i")
+
+			view := m.View()
+			tuitest.AssertFits(t, view, size.width, size.height)
+			assert.Contains(t, view, "DOWNLOAD FAILED")
+		})
+	}
+
+	m := New(nil)
+	m, _ = m.taskAction(failed, "i")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(Model)
+	assert.NotNil(t, m.failure, "copying keeps the details open")
+	copied, ok := cmd().(common.CopyToClipboardMsg)
+	assert.True(t, ok)
+	assert.Equal(t, failed.ReproCommand(), copied.Text)
+	assert.Contains(t, copied.Text, "ffmpeg -headers 'Referer: https://provider.example/'")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	assert.Nil(t, m.failure)
+
+	m, _ = m.taskAction(sampleDownloads()[0], "i")
+	assert.Nil(t, m.failure, "only failed downloads have details")
+}
@@ -0,0 +1,120 @@
+package downloads
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// duplicatesFoundMsg carries the result of a duplicate search
+type duplicatesFoundMsg struct {
+	sets []downloader.DuplicateSet
+	err  error
+}
+
+// findDuplicates looks for episodes downloaded more than once
+func (m Model) findDuplicates() tea.Cmd {
+	return func() tea.Msg {
+		if m.manager == nil {
+			return nil
+		}
+		sets, err := m.manager.FindDuplicates(context.Background())
+		return duplicatesFoundMsg{sets: sets, err: err}
+	}
+}
+
+// duplicateExtras returns the IDs and total size of the copies that would
+// be removed, keeping the first copy of every episode
+func duplicateExtras(sets []downloader.DuplicateSet) ([]string, int64) {
+	var ids []string
+	var size int64
+	for _, set := range sets {
+		for _, extra := range set.Extras {
+			ids = append(ids, extra.ID)
+			size += extra.TotalBytes
+		}
+	}
+	return ids, size
+}
+
+// handleDuplicatesKeys handles the dialog listing duplicate downloads
+func (m Model) handleDuplicatesKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		ids, _ := duplicateExtras(m.duplicates)
+		m.duplicates = nil
+		m.showDuplicates = false
+		if len(ids) == 0 || m.manager == nil {
+			return m, nil
+		}
+		manager := m.manager
+		return m, func() tea.Msg {
+			return trashTasks(manager, fmt.Sprintf("%d duplicate downloads", len(ids)), ids...)
+		}
+	case "n", "N", "esc", "q":
+		m.duplicates = nil
+		m.showDuplicates = false
+	}
+	return m, nil
+}
+
+// renderDuplicatesDialog renders the duplicates found, with the copies that
+// would go to the trash
+func (m Model) renderDuplicatesDialog() string {
+	title := styles.TitleStyle.Foreground(styles.OxocarbonRed).Render("DUPLICATE DOWNLOADS")
+	width := min(max(m.width-12, 20), 70)
+
+	if len(m.duplicates) == 0 {
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(styles.OxocarbonBlue).
+			Padding(1, 2).
+			Width(width + 4).
+			Render(fmt.Sprintf("%s\n\nNo episode is downloaded more than once.\n\n%s",
+				styles.TitleStyle.Render("DUPLICATE DOWNLOADS"),
+				styles.AniListHelpStyle.Render("(esc) Close")))
+	}
+
+	ids, size := duplicateExtras(m.duplicates)
+	summary := fmt.Sprintf("%d extra copies of %d episodes", len(ids), len(m.duplicates))
+	if size > 0 {
+		summary += fmt.Sprintf(" (%s)", humanize.IBytes(uint64(size)))
+	}
+
+	// List as many episodes as fit, the dialog has a border and padding
+	maxLines := max(m.height-14, 1)
+	var lines string
+	for i, set := range m.duplicates {
+		if i == maxLines {
+			lines += styles.AniListMetadataStyle.Render(fmt.Sprintf("… and %d more", len(m.duplicates)-i)) + "\n"
+			break
+		}
+		name := set.Keep.MediaTitle
+		if set.Keep.Episode > 0 {
+			name = fmt.Sprintf("%s - Episode %d", name, set.Keep.Episode)
+		}
+		if set.Keep.Quality != "" {
+			name = fmt.Sprintf("%s [%s]", name, set.Keep.Quality)
+		}
+		lines += styles.AniListTitleStyle.Render(utils.Truncate(fmt.Sprintf("%s ×%d", name, len(set.Extras)+1), width)) + "\n"
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonRed).
+		Padding(1, 2).
+		Width(width + 4).
+		Render(fmt.Sprintf("%s\n\n%s\n\n%s\nMove the extra copies to the trash? The first copy of each is kept.\n\n%s",
+			title,
+			utils.Truncate(summary, width),
+			lines,
+			styles.AniListHelpStyle.Render("(y) Trash extras • (n/esc) Cancel"),
+		))
+}
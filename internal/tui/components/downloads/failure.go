@@ -0,0 +1,158 @@
+package downloads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// showFailureDetails opens the details of a failed download
+func (m Model) showFailureDetails(task downloader.DownloadTask) (Model, tea.Cmd) {
+	if task.Status != downloader.StatusFailed && task.Diagnostics == nil {
+		return m, nil
+	}
+	m.failure = &task
+	return m, nil
+}
+
+// handleFailureKeys handles the failure details dialog
+func (m Model) handleFailureKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "c", "y":
+		command := m.failure.ReproCommand()
+		if command == "" {
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return common.CopyToClipboardMsg{Text: command, Name: "Reproduction command"}
+		}
+	case "R", "A":
+		task := *m.failure
+		m.failure = nil
+		action := msg.String()
+		if action == "R" {
+			action = "retry"
+		}
+		return m.taskAction(task, action)
+	case "esc", "q", "i", "enter":
+		m.failure = nil
+	}
+	return m, nil
+}
+
+// wrapLines wraps every line of text to width, keeping its line breaks
+func wrapLines(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if wrapped := utils.WrapText(line, width); len(wrapped) > 0 {
+			lines = append(lines, wrapped...)
+		}
+	}
+	return lines
+}
+
+// renderFailureDialog renders why a download failed and the request it
+// failed on
+func (m Model) renderFailureDialog() string {
+	task := m.failure
+	width := min(max(m.width-12, 20), 90)
+	label := styles.AniListMetadataStyle.Foreground(styles.OxocarbonPurple)
+
+	name := task.MediaTitle
+	if task.Episode > 0 {
+		name = fmt.Sprintf("%s - Episode %d", name, task.Episode)
+	}
+	if task.Quality != "" {
+		name = fmt.Sprintf("%s [%s]", name, task.Quality)
+	}
+
+	// Lines between the name and the help, the dialog has a border, padding
+	// and four lines of its own
+	budget := max(m.height-10, 3)
+	var body []string
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if len(body) > 0 {
+			body = append(body, "")
+		}
+		body = append(body, label.Render(title))
+		body = append(body, lines...)
+	}
+
+	// The error output of tools like ffmpeg is long; keep the end, where the
+	// reason usually is
+	errLines := wrapLines(task.Error, width)
+	if len(errLines) == 0 {
+		errLines = []string{"No error recorded"}
+	}
+	if maxError := max(budget/3, 1); len(errLines) > maxError {
+		errLines = errLines[len(errLines)-maxError:]
+		errLines[0] = utils.Truncate(utils.Ellipsis+" "+errLines[0], width)
+	}
+	section("Error", errLines)
+
+	help := "(esc) Close • (R) Retry • (A) Other provider"
+	if d := task.Diagnostics; d != nil {
+		var facts []string
+		if d.Tool != "" {
+			facts = append(facts, "via "+d.Tool)
+		}
+		if d.Status != 0 {
+			facts = append(facts, fmt.Sprintf("HTTP %d", d.Status))
+		}
+		if !d.At.IsZero() {
+			facts = append(facts, d.At.Format("Jan 2 15:04:05"))
+		}
+		if len(facts) > 0 {
+			body = append(body, styles.AniListMetadataStyle.Render(utils.Truncate(strings.Join(facts, " • "), width)))
+		}
+
+		if d.Response != "" {
+			section("Response", strings.Split(utils.TruncateToLines(d.Response, 2, width), "\n"))
+		}
+		section("URL", wrapLines(d.URL, width))
+
+		keys := make([]string, 0, len(d.Headers))
+		for key := range d.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var headers []string
+		for _, key := range keys {
+			headers = append(headers, utils.Truncate(key+": "+d.Headers[key], width))
+		}
+		section("Headers", headers)
+
+		if task.ReproCommand() != "" {
+			help = "(c) Copy reproduction command • " + help
+		}
+	}
+
+	// Small terminals lose the end of the details, the reproduction command
+	// has all of them anyway
+	if len(body) > budget {
+		body = append(body[:budget-1], styles.AniListMetadataStyle.Render(utils.Ellipsis))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonRed).
+		Padding(1, 2).
+		Width(width + 4).
+		Render(fmt.Sprintf("%s\n%s\n\n%s\n\n%s",
+			styles.TitleStyle.Foreground(styles.OxocarbonRed).Render("DOWNLOAD FAILED"),
+			styles.AniListTitleStyle.Render(utils.Truncate(name, width)),
+			strings.Join(body, "\n"),
+			styles.AniListHelpStyle.Render(utils.Truncate(help, width)),
+		))
+}
@@ -0,0 +1,101 @@
+package downloads
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// sparkLevels are the bars of a sparkline, from no throughput to the peak
+var sparkLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+// maxGraphWidth keeps graphs readable on wide terminals: a minute of samples
+const maxGraphWidth = 60
+
+// sparkline draws samples, oldest first, as width bars scaled to their peak.
+// With fewer bars than samples, each bar averages the samples it covers.
+func sparkline(samples []int64, width int) string {
+	if width <= 0 || len(samples) == 0 {
+		return ""
+	}
+	width = min(width, len(samples))
+	bars := make([]float64, width)
+	var peak float64
+	for i := range bars {
+		from, to := i*len(samples)/width, (i+1)*len(samples)/width
+		var sum int64
+		for _, s := range samples[from:to] {
+			sum += s
+		}
+		bars[i] = float64(sum) / float64(to-from)
+		peak = max(peak, bars[i])
+	}
+
+	var b strings.Builder
+	top := len(sparkLevels) - 1
+	for _, bar := range bars {
+		level := 0
+		if peak > 0 && bar > 0 {
+			// Any throughput shows at least the lowest bar
+			level = max(1, int(bar/peak*float64(top)+0.5))
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// hasThroughput reports whether anything was downloaded in samples
+func hasThroughput(samples []int64) bool {
+	for _, s := range samples {
+		if s > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// showTotalSpeed reports whether the header shows the speed of all
+// transfers: while any runs, and for a minute after
+func (m Model) showTotalSpeed() bool {
+	down, up := m.transferSpeeds()
+	return down > 0 || up > 0 || hasThroughput(m.totalSpeed)
+}
+
+// renderTotalSpeed renders the current speed of all transfers together and
+// the graph of all downloads over the last minute, for the header
+func (m Model) renderTotalSpeed() string {
+	label := "  " + m.speedLabel() + " "
+	graph := sparkline(m.totalSpeed, min(maxGraphWidth, m.width-len([]rune(label))-2))
+	return utils.Truncate(styles.AniListMetadataStyle.Render(label)+styles.AniListMetadataStyle.Foreground(styles.OxocarbonGreen).Render(graph), m.width)
+}
+
+// speedGraph returns the graph of a download's speed over the last minute
+// with its peak, fitting width, or "" when it downloaded nothing
+func (m Model) speedGraph(id string, width int) string {
+	samples := m.speeds[id]
+	if !hasThroughput(samples) {
+		return ""
+	}
+	var peak int64
+	for _, s := range samples {
+		peak = max(peak, s)
+	}
+	label := fmt.Sprintf(" peak %s/s", humanize.Bytes(uint64(peak)))
+	return sparkline(samples, min(maxGraphWidth, width-len([]rune(label)))) + label
+}
+
+// graphLines is how many lines the speed graphs take besides the list
+func (m Model) graphLines() int {
+	lines := 0
+	if m.showTotalSpeed() {
+		lines++
+	}
+	if len(m.speeds) > 0 {
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,86 @@
+package downloads
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+)
+
+// transferSpeeds returns how fast the active tasks download and upload
+// together right now
+func (m Model) transferSpeeds() (down, up int64) {
+	for _, task := range m.downloads {
+		switch task.Status {
+		case downloader.StatusDownloading:
+			down += task.Speed
+		case downloader.StatusUploading:
+			up += task.Speed
+		}
+	}
+	return down, up
+}
+
+// queuedSize returns how many tasks wait in the queue and how much is left
+// to download of those whose size is known
+func (m Model) queuedSize() (count int, size int64, unknown bool) {
+	for _, task := range m.downloads {
+		if task.Status != downloader.StatusQueued {
+			continue
+		}
+		count++
+		total := task.TotalBytes
+		if total == 0 {
+			total = task.ExpectedSize
+		}
+		if total == 0 {
+			unknown = true
+			continue
+		}
+		size += max(total-task.BytesDownloaded, 0)
+	}
+	return count, size, unknown
+}
+
+// renderQueueTotals renders the size of the queue and how many downloads
+// run at once, for the count line under the header
+func (m Model) renderQueueTotals() string {
+	var out string
+	if count, size, unknown := m.queuedSize(); count > 0 {
+		out += fmt.Sprintf(" • %d queued", count)
+		if size > 0 {
+			approx := ""
+			if unknown {
+				approx = "≥"
+			}
+			out += fmt.Sprintf(" (%s%s)", approx, humanize.Bytes(uint64(size)))
+		}
+	}
+	if m.concurrency > 0 {
+		out += fmt.Sprintf(" • %d at once", m.concurrency)
+	}
+	return out
+}
+
+// speedLabel renders the current download and upload speeds
+func (m Model) speedLabel() string {
+	down, up := m.transferSpeeds()
+	label := fmt.Sprintf("↓ %s/s", humanize.Bytes(uint64(down)))
+	if up > 0 {
+		label += fmt.Sprintf(" ↑ %s/s", humanize.Bytes(uint64(up)))
+	}
+	return label
+}
+
+// adjustConcurrency runs one download more or less at once, right away and
+// for this session only
+func (m Model) adjustConcurrency(delta int) (tea.Model, tea.Cmd) {
+	if m.manager == nil {
+		return m, nil
+	}
+	m.manager.SetConcurrency(m.manager.Concurrency() + delta)
+	m.concurrency = m.manager.Concurrency()
+	return m, nil
+}
@@ -0,0 +1,185 @@
+package episodes
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// arcGroup is a run of consecutive episodes in the same arc
+type arcGroup struct {
+	name  string
+	first int // Index of the first episode in m.episodes
+	last  int // Index of the last episode in m.episodes
+}
+
+// arcGroups returns the runs of episodes sharing an arc, in list order
+func (m MangalModel) arcGroups() []arcGroup {
+	var groups []arcGroup
+	for i, episode := range m.episodes {
+		if len(groups) > 0 && groups[len(groups)-1].name == episode.Arc {
+			groups[len(groups)-1].last = i
+			continue
+		}
+		groups = append(groups, arcGroup{name: episode.Arc, first: i, last: i})
+	}
+	return groups
+}
+
+// openArcs collapses the list into its arcs, with the cursor on the arc of
+// the highlighted episode
+func (m *MangalModel) openArcs() {
+	if !m.hasArcs() {
+		return
+	}
+	if m.visualMode {
+		m.endVisual(false)
+	}
+	m.arcView = true
+	m.arcIndex = 0
+	for i, group := range m.arcGroups() {
+		if m.currentIndex >= group.first && m.currentIndex <= group.last {
+			m.arcIndex = i
+		}
+	}
+}
+
+// closeArcs expands the list again, on the first episode of the arc under
+// the cursor unless the highlighted episode is already in it
+func (m *MangalModel) closeArcs() {
+	m.arcView = false
+	groups := m.arcGroups()
+	if m.arcIndex >= len(groups) {
+		return
+	}
+	group := groups[m.arcIndex]
+	if m.currentIndex < group.first || m.currentIndex > group.last {
+		m.currentIndex = group.first
+	}
+}
+
+// markArc marks every episode of an arc, or unmarks them when they all are
+func (m *MangalModel) markArc(group arcGroup) {
+	all := true
+	for i := group.first; i <= group.last; i++ {
+		all = all && m.selectedItems[i]
+	}
+	for i := group.first; i <= group.last; i++ {
+		if all {
+			delete(m.selectedItems, i)
+		} else {
+			m.selectedItems[i] = true
+		}
+	}
+	m.selectionMode = len(m.selectedItems) > 0
+}
+
+// handleArcKeys handles the collapsed arc list
+func (m MangalModel) handleArcKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	groups := m.arcGroups()
+	if len(groups) == 0 {
+		m.arcView = false
+		return m, nil
+	}
+	m.arcIndex = max(min(m.arcIndex, len(groups)-1), 0)
+
+	switch msg.String() {
+	case "up", "k":
+		m.arcIndex = max(m.arcIndex-1, 0)
+	case "down", "j":
+		m.arcIndex = min(m.arcIndex+1, len(groups)-1)
+	case "enter":
+		// Expand into the arc
+		m.arcView = false
+		m.currentIndex = groups[m.arcIndex].first
+	case " ":
+		m.markArc(groups[m.arcIndex])
+		m.arcIndex = min(m.arcIndex+1, len(groups)-1)
+	case "d":
+		// Download the marked episodes, or the whole arc under the cursor
+		if len(m.selectedItems) == 0 {
+			m.markArc(groups[m.arcIndex])
+		}
+		m.confirmBatch = true
+	case "c", "C":
+		m.selectedItems = make(map[int]bool)
+		m.selectionMode = false
+	case "z", "esc":
+		m.closeArcs()
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderArcs renders the list collapsed into its arcs
+func (m MangalModel) renderArcs() string {
+	groups := m.arcGroups()
+
+	output := styles.TitleStyle.Render("ARCS") + "\n"
+	count := styles.SubtitleStyle.Render(fmt.Sprintf("%d arcs • %d episodes", len(groups), len(m.episodes)))
+	if len(m.selectedItems) > 0 {
+		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" • %d selected", len(m.selectedItems)))
+	}
+	output += utils.Truncate(count, m.width) + "\n\n"
+
+	window := m
+	window.currentIndex = m.arcIndex
+	visibleStart, visibleEnd := window.getVisibleRange(len(groups))
+	for i := visibleStart; i < visibleEnd; i++ {
+		output += m.renderArcItem(groups[i], i == m.arcIndex) + "\n"
+		if !utils.Short(m.height) {
+			output += "\n"
+		}
+	}
+
+	helpText := "  ↑/↓ nav • enter open • space mark arc • d dl arc • c clear • z episodes • esc back"
+	shortHelp := "  ↑/↓ • enter open • d dl arc • z episodes"
+	output += "\n" + styles.AniListHelpStyle.Render(utils.FitHelp(m.width, helpText, shortHelp))
+	return output
+}
+
+// renderArcItem renders an arc row: its episode range and how many of its
+// episodes are marked, then its name
+func (m MangalModel) renderArcItem(group arcGroup, selected bool) string {
+	boxStyle := styles.AniListItemStyle
+	titleStyle := styles.AniListTitleStyle
+	metaStyle := styles.AniListMetadataStyle
+	if selected {
+		boxStyle = styles.AniListItemSelectedStyle
+		titleStyle = titleStyle.Foreground(styles.OxocarbonPurple)
+		metaStyle = metaStyle.Foreground(styles.OxocarbonMauve)
+	}
+
+	prefix := "Ep"
+	if m.mediaType == providers.MediaTypeManga {
+		prefix = "Ch"
+	}
+	first, last := m.episodes[group.first].Number, m.episodes[group.last].Number
+	episodes := group.last - group.first + 1
+	marked := 0
+	for i := group.first; i <= group.last; i++ {
+		if m.selectedItems[i] {
+			marked++
+		}
+	}
+
+	indicator := "  "
+	if marked == episodes {
+		indicator = "✓ "
+	}
+	meta := fmt.Sprintf("%s%s %d–%d · %d", indicator, prefix, first, last, episodes)
+	if marked > 0 && marked < episodes {
+		meta += fmt.Sprintf(" · %d marked", marked)
+	}
+
+	name := group.name
+	if name == "" {
+		name = "Other episodes"
+	}
+	width := utils.ItemWidth(m.width)
+	return boxStyle.Render(metaStyle.Render(utils.Truncate(meta, width)) + "\n" + titleStyle.Render(utils.Truncate(name, width)))
+}
@@ -0,0 +1,260 @@
+package episodes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// batchQualities are the qualities offered in the batch download summary
+var batchQualities = []providers.Quality{
+	providers.Quality480p,
+	providers.Quality720p,
+	providers.Quality1080p,
+	providers.Quality1440p,
+	providers.Quality4K,
+}
+
+// Fallback runtimes for size estimates when the provider has none
+const (
+	defaultAnimeRuntime = 24 * time.Minute
+	defaultVideoRuntime = 45 * time.Minute
+)
+
+func newRangeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "1-12,14"
+	ti.Prompt = "Range: "
+	ti.CharLimit = 64
+	ti.Width = 30
+	return ti
+}
+
+// startVisual starts marking a range from the highlighted episode
+func (m *MangalModel) startVisual() {
+	m.visualMode = true
+	m.visualAnchor = m.currentIndex
+	m.visualBase = make(map[int]bool, len(m.selectedItems))
+	for idx := range m.selectedItems {
+		m.visualBase[idx] = true
+	}
+	m.applyVisual()
+}
+
+// applyVisual marks every episode between the anchor and the cursor on top
+// of the selection that existed when visual mode started
+func (m *MangalModel) applyVisual() {
+	selected := make(map[int]bool, len(m.visualBase))
+	for idx := range m.visualBase {
+		selected[idx] = true
+	}
+	lo, hi := min(m.visualAnchor, m.currentIndex), max(m.visualAnchor, m.currentIndex)
+	for i := lo; i <= hi && i < len(m.episodes); i++ {
+		selected[i] = true
+	}
+	m.selectedItems = selected
+	m.selectionMode = len(selected) > 0
+}
+
+// endVisual leaves visual mode, keeping the marked range unless cancel is set
+func (m *MangalModel) endVisual(cancel bool) {
+	if cancel {
+		m.selectedItems = m.visualBase
+		m.selectionMode = len(m.selectedItems) > 0
+	}
+	m.visualMode = false
+	m.visualBase = nil
+}
+
+// handleRangeKeys handles the "1-12,14" range prompt
+func (m MangalModel) handleRangeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.rangeActive = false
+		m.rangeErr = ""
+		m.rangeInput.Blur()
+		return m, nil
+	case "enter":
+		spec := strings.TrimSpace(m.rangeInput.Value())
+		if spec == "" {
+			m.rangeErr = "Enter episode numbers, e.g. 1-12,14"
+			return m, nil
+		}
+		matched, err := providers.ParseEpisodeRange(m.episodes, spec)
+		if err != nil {
+			m.rangeErr = err.Error()
+			return m, nil
+		}
+		if len(matched) == 0 {
+			m.rangeErr = "No episodes match " + spec
+			return m, nil
+		}
+		wanted := make(map[int]bool, len(matched))
+		for _, ep := range matched {
+			wanted[ep.Number] = true
+		}
+		for i, ep := range m.episodes {
+			if wanted[ep.Number] {
+				m.selectedItems[i] = true
+			}
+		}
+		m.selectionMode = true
+		m.rangeActive = false
+		m.rangeErr = ""
+		m.rangeInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.rangeInput, cmd = m.rangeInput.Update(msg)
+	return m, cmd
+}
+
+// handleConfirmKeys handles the batch download summary
+func (m MangalModel) handleConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		selected := m.selectedEpisodes()
+		quality := m.batchQuality
+		m.confirmBatch = false
+		m.selectedItems = make(map[int]bool)
+		m.selectionMode = false
+		episodes := make([]common.EpisodeInfo, 0, len(selected))
+		for _, ep := range selected {
+			episodes = append(episodes, common.NewEpisodeInfo(ep))
+		}
+		return m, func() tea.Msg {
+			return common.BatchDownloadMsg{Episodes: episodes, Quality: quality}
+		}
+	case "left", "h":
+		if m.mediaType != providers.MediaTypeManga {
+			m.batchQuality = cycleQuality(m.batchQuality, -1)
+		}
+	case "right", "l":
+		if m.mediaType != providers.MediaTypeManga {
+			m.batchQuality = cycleQuality(m.batchQuality, 1)
+		}
+	case "n", "N", "esc", "q":
+		m.confirmBatch = false
+	}
+	return m, nil
+}
+
+// selectedEpisodes returns the marked episodes in list order
+func (m MangalModel) selectedEpisodes() []providers.Episode {
+	selected := make([]providers.Episode, 0, len(m.selectedItems))
+	for idx, ep := range m.episodes {
+		if m.selectedItems[idx] {
+			selected = append(selected, ep)
+		}
+	}
+	return selected
+}
+
+func cycleQuality(current providers.Quality, step int) providers.Quality {
+	for i, q := range batchQualities {
+		if q == current {
+			return batchQualities[(i+step+len(batchQualities))%len(batchQualities)]
+		}
+	}
+	return providers.Quality1080p
+}
+
+// estimateBatchSize returns the estimated total size of the episodes and
+// whether any runtime had to be guessed
+func (m MangalModel) estimateBatchSize(episodes []providers.Episode) (int64, bool) {
+	fallback := defaultVideoRuntime
+	if m.mediaType == providers.MediaTypeAnime {
+		fallback = defaultAnimeRuntime
+	}
+
+	var total int64
+	guessed := false
+	for _, ep := range episodes {
+		runtime := ep.Duration
+		if runtime <= 0 {
+			runtime = fallback
+			guessed = true
+		}
+		total += downloader.EstimateSize(m.batchQuality, runtime)
+	}
+	return total, guessed
+}
+
+// formatNumbers collapses sorted episode numbers into ranges ("1-12, 14")
+func formatNumbers(episodes []providers.Episode) string {
+	var parts []string
+	for i := 0; i < len(episodes); {
+		j := i
+		for j+1 < len(episodes) && episodes[j+1].Number == episodes[j].Number+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", episodes[i].Number, episodes[j].Number))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d", episodes[i].Number))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderBatchConfirm renders the batch download summary dialog
+func (m MangalModel) renderBatchConfirm() string {
+	selected := m.selectedEpisodes()
+
+	noun := "episodes"
+	if m.mediaType == providers.MediaTypeManga {
+		noun = "chapters"
+	}
+
+	// Room inside the dialog's border and padding
+	width := 60
+	if m.width > 0 {
+		width = min(width, m.width-6)
+	}
+
+	var lines []string
+	lines = append(lines, styles.TitleStyle.Render(fmt.Sprintf("DOWNLOAD %d %s", len(selected), strings.ToUpper(noun))))
+	lines = append(lines, "")
+	lines = append(lines, styles.AniListTitleStyle.Render(utils.Truncate(formatNumbers(selected), width)))
+
+	help := []string{"(y/enter) Queue • (n/esc) Cancel"}
+	if m.mediaType != providers.MediaTypeManga {
+		size, guessed := m.estimateBatchSize(selected)
+		sizeText := "~" + humanize.Bytes(uint64(size))
+		if guessed {
+			sizeText += " (runtime guessed)"
+		}
+		lines = append(lines, "")
+		lines = append(lines, styles.AniListMetadataStyle.Render(fmt.Sprintf("Quality: ‹ %s ›", m.batchQuality)))
+		lines = append(lines, styles.AniListMetadataStyle.Render("Estimated size: "+sizeText))
+		help = []string{"(y/enter) Queue • (←/→) Quality • (n/esc) Cancel", "(y) Queue • (←/→) Quality • (esc) Cancel"}
+	}
+	lines = append(lines, "")
+	lines = append(lines, styles.AniListHelpStyle.Render(utils.FitHelp(width, help...)))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonPurple).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		dialog,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("#161616")),
+	)
+}
@@ -0,0 +1,203 @@
+package episodes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// detailSplitWidth is the narrowest view that shows the detail pane next to
+// the list. Narrower views show the pane in place of the list.
+const detailSplitWidth = 90
+
+// episodeDetail is the detail pane state of one episode
+type episodeDetail struct {
+	loaded    bool   // The request came back, with or without details
+	thumbnail string // Rendered thumbnail, "" when there is none
+}
+
+// selectedEpisode returns the episode under the cursor, through the filter
+// when one is active
+func (m MangalModel) selectedEpisode() (providers.Episode, bool) {
+	index := m.currentIndex
+	if m.fuzzySearch.IsActive() {
+		filtered := m.getFilteredIndices()
+		if index < 0 || index >= len(filtered) {
+			return providers.Episode{}, false
+		}
+		index = filtered[index]
+	}
+	if index < 0 || index >= len(m.episodes) {
+		return providers.Episode{}, false
+	}
+	return m.episodes[index], true
+}
+
+// requestDetail asks for the details of the episode under the cursor the
+// first time the pane shows it
+func (m MangalModel) requestDetail() tea.Cmd {
+	episode, ok := m.selectedEpisode()
+	if !ok {
+		return nil
+	}
+	if _, requested := m.details[episode.ID]; requested {
+		return nil
+	}
+	m.details[episode.ID] = episodeDetail{}
+
+	width, height := m.thumbnailSize()
+	return func() tea.Msg {
+		return common.RequestEpisodeDetailMsg{Episode: episode, ThumbWidth: width, ThumbHeight: height}
+	}
+}
+
+// SetEpisodeDetail replaces an episode with its filled-in details and keeps
+// its rendered thumbnail for the detail pane
+func (m *MangalModel) SetEpisodeDetail(episode providers.Episode, thumbnail string) {
+	for i := range m.episodes {
+		if m.episodes[i].ID == episode.ID {
+			m.episodes[i] = episode
+			break
+		}
+	}
+	m.details[episode.ID] = episodeDetail{loaded: true, thumbnail: thumbnail}
+}
+
+// splitDetail reports whether the detail pane is shown next to the list
+// rather than in place of it
+func (m MangalModel) splitDetail() bool {
+	return m.width == 0 || m.width >= detailSplitWidth
+}
+
+// detailWidths returns the width of the list and of the detail pane
+func (m MangalModel) detailWidths() (int, int) {
+	width := m.width
+	if width == 0 {
+		width = 120
+	}
+	if !m.splitDetail() {
+		return 0, width
+	}
+	list := width * 11 / 20
+	return list, width - list - 1
+}
+
+// thumbnailSize returns the size in cells thumbnails are rendered at, a 16:9
+// image in cells about twice as tall as wide, or 0x0 when the pane is too
+// small for one
+func (m MangalModel) thumbnailSize() (int, int) {
+	_, pane := m.detailWidths()
+	width := pane - 4 // Border and padding
+	height := width * 9 / 32
+	if m.height > 0 {
+		height = min(height, (m.height-4)/3)
+	}
+	if width < 10 || height < 3 {
+		return 0, 0
+	}
+	return width, height
+}
+
+// renderWithDetail renders the list with the detail pane beside it, or the
+// pane alone on narrow terminals
+func (m MangalModel) renderWithDetail() string {
+	listWidth, paneWidth := m.detailWidths()
+	if listWidth == 0 {
+		action := "play"
+		if m.mediaType == providers.MediaTypeManga {
+			action = "read"
+		}
+		helpText := fmt.Sprintf("  ↑/↓ episode • enter %s • I list • esc close", action)
+		return m.renderDetailPane(paneWidth, m.height-3) + "\n" +
+			styles.AniListHelpStyle.Render(utils.FitHelp(m.width, helpText, "  ↑/↓ • I list • esc close"))
+	}
+
+	list := m
+	list.width = listWidth
+	height := 0
+	if m.height > 0 {
+		height = m.height - 1
+	}
+	left := lipgloss.NewStyle().Width(listWidth).MaxWidth(listWidth).Render(list.renderList())
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, " ", m.renderDetailPane(paneWidth, height))
+}
+
+// renderDetailPane renders the thumbnail, number, title, air date, runtime and
+// synopsis of the episode under the cursor, width cells wide and at most
+// height rows tall (unbounded when height is 0)
+func (m MangalModel) renderDetailPane(width, height int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonBase02).
+		Padding(0, 1)
+	inner := max(width-box.GetHorizontalFrameSize(), 1)
+
+	episode, ok := m.selectedEpisode()
+	if !ok {
+		return box.Width(width - box.GetHorizontalBorderSize()).Render(styles.AniListMetadataStyle.Render("No episode selected"))
+	}
+	detail, requested := m.details[episode.ID]
+
+	var lines []string
+	if detail.thumbnail != "" {
+		for _, line := range strings.Split(detail.thumbnail, "\n") {
+			lines = append(lines, ansi.Truncate(line, inner, ""))
+		}
+		lines = append(lines, "")
+	}
+
+	prefix := "Episode"
+	if m.mediaType == providers.MediaTypeManga {
+		prefix = "Chapter"
+	}
+	lines = append(lines, styles.AniListMetadataStyle.Render(fmt.Sprintf("%s %d", prefix, episode.Number)))
+	if title := cleanTitle(episode.Title); title != "" && title != fmt.Sprintf("%s %d", prefix, episode.Number) {
+		for _, line := range utils.WrapText(title, inner) {
+			lines = append(lines, styles.AniListTitleStyle.Render(line))
+		}
+	}
+
+	var meta []string
+	if !episode.ReleaseDate.IsZero() {
+		if episode.ReleaseDate.After(time.Now()) {
+			meta = append(meta, "airs "+episode.ReleaseDate.Format("Jan 2, 2006"))
+		} else {
+			meta = append(meta, episode.ReleaseDate.Format("Jan 2, 2006"))
+		}
+	}
+	if episode.Duration > 0 {
+		meta = append(meta, fmt.Sprintf("%dm", int(episode.Duration.Minutes())))
+	}
+	if len(meta) > 0 {
+		lines = append(lines, styles.AniListMetadataStyle.Render(utils.Truncate(strings.Join(meta, " · "), inner)))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case episode.Synopsis != "":
+		for _, line := range utils.WrapText(episode.Synopsis, inner) {
+			lines = append(lines, styles.SubtitleStyle.Render(line))
+		}
+	case requested && !detail.loaded:
+		lines = append(lines, styles.AniListMetadataStyle.Render("Loading details…"))
+	default:
+		lines = append(lines, styles.AniListMetadataStyle.Render("No synopsis available"))
+	}
+
+	// Cut the synopsis to the pane, the border takes two rows
+	if height > 0 {
+		if maxLines := max(height-box.GetVerticalFrameSize(), 1); len(lines) > maxLines {
+			lines = lines[:maxLines]
+			lines[maxLines-1] = utils.Truncate(lines[maxLines-1]+"…", inner)
+		}
+	}
+	return box.Width(width - box.GetHorizontalBorderSize()).Render(strings.Join(lines, "\n"))
+}
@@ -1,6 +1,8 @@
 package episodes
 
 import (
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/justchokingaround/greg/internal/providers"
 )
@@ -25,19 +27,48 @@ func (m *Model) SetEpisodes(episodes []providers.Episode) {
 	currentMediaType := m.mangal.mediaType
 	width := m.mangal.width
 	height := m.mangal.height
+	nextAiring, nextAiringAt := m.mangal.nextAiring, m.mangal.nextAiringAt
+	specials := m.mangal.specials
+	singleSource := m.mangal.singleSource
 
 	m.mangal = NewMangal()
 	m.mangal.SetMediaType(currentMediaType)
+	m.mangal.singleSource = singleSource
+	m.mangal.SetAiring(nextAiring, nextAiringAt)
+	m.mangal.SetSpecials(specials)
 	m.mangal.width = width
 	m.mangal.height = height
 	m.mangal.SetEpisodes(episodes)
 }
 
+// SetAiring sets the first episode that hasn't aired yet (0 if unknown) and
+// when it airs. It is kept when the episodes are replaced.
+func (m *Model) SetAiring(nextEpisode int, at time.Time) {
+	m.mangal.SetAiring(nextEpisode, at)
+}
+
+// SetSpecials sets the OVAs, specials and movies attached to the series,
+// listed with 'o'. They are kept when the episodes are replaced.
+func (m *Model) SetSpecials(specials []Special) {
+	m.mangal.SetSpecials(specials)
+}
+
+// SetCapabilities sets what the provider supports. It is kept when the
+// episodes are replaced.
+func (m *Model) SetCapabilities(caps providers.Capabilities) {
+	m.mangal.SetCapabilities(caps)
+}
+
 // SetCursorToEpisode sets the cursor to the episode with the given episode number
 func (m *Model) SetCursorToEpisode(episodeNumber int) {
 	m.mangal.SetCursorToEpisode(episodeNumber)
 }
 
+// SetEpisodeDetail fills in an episode's details for the detail pane
+func (m *Model) SetEpisodeDetail(episode providers.Episode, thumbnail string) {
+	m.mangal.SetEpisodeDetail(episode, thumbnail)
+}
+
 // GetCurrentIndex returns the current selected index in the episodes list
 func (m Model) GetCurrentIndex() int {
 	return m.mangal.currentIndex
@@ -48,8 +79,12 @@ func (m Model) GetEpisodes() []providers.Episode {
 	return m.mangal.episodes
 }
 
-// IsInputActive returns true if the fuzzy search input is active and not locked
+// IsInputActive returns true if the fuzzy search input is active and not locked,
+// or the range or jump prompt or batch download summary is open
 func (m Model) IsInputActive() bool {
+	if m.mangal.rangeActive || m.mangal.jumpActive || m.mangal.confirmBatch {
+		return true
+	}
 	return m.mangal.fuzzySearch.IsActive() && !m.mangal.fuzzySearch.IsLocked()
 }
 
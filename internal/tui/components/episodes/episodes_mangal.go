@@ -5,14 +5,28 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
 )
 
+// synopsisLines is the most synopsis lines shown for an expanded episode
+const synopsisLines = 4
+
+// episodePrefix matches provider title prefixes like "Eps 3:"
+var episodePrefix = regexp.MustCompile(`^Eps \d+[:\-\s]*`)
+
+// CourOffsetRequestedMsg is sent when the episode offset of the AniList
+// entry's provider listing is to be set
+type CourOffsetRequestedMsg struct{}
+
 // MangalModel is a mangal-style episodes view
 type MangalModel struct {
 	episodes      []providers.Episode
@@ -23,6 +37,52 @@ type MangalModel struct {
 	fuzzySearch   *common.FuzzySearch
 	selectedItems map[int]bool // For batch selection
 	selectionMode bool         // Whether in selection mode
+	expanded      bool         // Show the synopsis of the highlighted episode
+
+	// Episode detail pane (see detail.go)
+	detailPane bool
+	details    map[string]episodeDetail // By episode ID, present once requested
+
+	// Range selection
+	visualMode   bool         // Marking everything between visualAnchor and the cursor
+	visualAnchor int          // Index where visual mode started
+	visualBase   map[int]bool // Selection before visual mode, restored on cancel
+	rangeInput   textinput.Model
+	rangeActive  bool   // Whether the "1-12,14" prompt is open
+	rangeErr     string // Parse error shown under the prompt
+
+	// ":500" jump prompt (see jump.go)
+	jumpInput  textinput.Model
+	jumpActive bool
+	jumpErr    string
+
+	// Arc outline, the list collapsed into its arcs (see arcs.go)
+	arcView  bool
+	arcIndex int
+
+	// OVAs, specials and movies of the series (see specials.go)
+	specials     []Special
+	specialsView bool
+	specialIndex int
+
+	// Batch download summary
+	confirmBatch bool
+	batchQuality providers.Quality
+
+	// Airing schedule (see SetAiring)
+	nextAiring   int       // First episode not aired yet, 0 if unknown
+	nextAiringAt time.Time // When nextAiring airs, zero if unknown
+
+	singleSource bool // The provider offers one stream per episode, so there is no source to pick
+}
+
+// Unaired reports whether an episode hasn't aired yet, from its release date
+// or from nextAiring, the first episode not aired yet (0 if unknown)
+func Unaired(episode providers.Episode, nextAiring int, now time.Time) bool {
+	if episode.ReleaseDate.After(now) {
+		return true
+	}
+	return nextAiring > 0 && episode.Number >= nextAiring
 }
 
 func NewMangal() MangalModel {
@@ -33,6 +93,10 @@ func NewMangal() MangalModel {
 		fuzzySearch:   common.NewFuzzySearch(),
 		selectedItems: make(map[int]bool),
 		selectionMode: false,
+		rangeInput:    newRangeInput(),
+		jumpInput:     newJumpInput(),
+		batchQuality:  providers.Quality1080p,
+		details:       make(map[string]episodeDetail),
 	}
 }
 
@@ -46,10 +110,22 @@ func (m *MangalModel) SetEpisodes(episodes []providers.Episode) {
 	m.selectedItems = make(map[int]bool) // Clear selections when episodes change
 }
 
+// SetAiring sets the first episode that hasn't aired yet and when it airs
+func (m *MangalModel) SetAiring(nextEpisode int, at time.Time) {
+	m.nextAiring = nextEpisode
+	m.nextAiringAt = at
+}
+
 func (m *MangalModel) SetMediaType(mediaType providers.MediaType) {
 	m.mediaType = mediaType
 }
 
+// SetCapabilities sets what the provider supports, leaving out the actions
+// it doesn't
+func (m *MangalModel) SetCapabilities(caps providers.Capabilities) {
+	m.singleSource = !caps.CanPickSource()
+}
+
 // SetCursorToEpisode sets the cursor to the episode with the given episode number
 func (m *MangalModel) SetCursorToEpisode(episodeNumber int) {
 	for i, ep := range m.episodes {
@@ -66,6 +142,16 @@ func (m MangalModel) Init() tea.Cmd {
 }
 
 func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	m = updated.(MangalModel)
+	if m.detailPane {
+		// Fetch the details of the episode the cursor landed on
+		return m, tea.Batch(cmd, m.requestDetail())
+	}
+	return m, cmd
+}
+
+func (m MangalModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -74,6 +160,22 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.confirmBatch {
+			return m.handleConfirmKeys(msg)
+		}
+		if m.arcView {
+			return m.handleArcKeys(msg)
+		}
+		if m.specialsView {
+			return m.handleSpecialsKeys(msg)
+		}
+		if m.rangeActive {
+			return m.handleRangeKeys(msg)
+		}
+		if m.jumpActive {
+			return m.handleJumpKeys(msg)
+		}
+
 		// If fuzzy search is active, handle it first
 		if m.fuzzySearch.IsActive() {
 			if m.fuzzySearch.IsLocked() {
@@ -178,9 +280,64 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Normal mode (fuzzy search not active)
 		maxIndex := len(m.episodes) - 1
 
+		if m.visualMode {
+			switch msg.String() {
+			case "v", "V":
+				m.endVisual(false)
+				return m, nil
+			case "esc":
+				m.endVisual(true)
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
+		case "v", "V":
+			// Mark a range by moving the cursor
+			if len(m.episodes) > 0 {
+				m.startVisual()
+			}
+		case "r":
+			// Select episodes by number ("1-12,14")
+			if len(m.episodes) > 0 {
+				m.rangeActive = true
+				m.rangeErr = ""
+				m.rangeInput.SetValue("")
+				return m, m.rangeInput.Focus()
+			}
+		case ":":
+			// Jump to an episode by number
+			if len(m.episodes) > 0 {
+				m.jumpActive = true
+				m.jumpErr = ""
+				m.jumpInput.SetValue("")
+				return m, m.jumpInput.Focus()
+			}
+		case "z":
+			// Collapse the list into its arcs
+			m.openArcs()
+		case "o":
+			// List the OVAs, specials and movies of the series
+			if len(m.specials) > 0 {
+				if m.visualMode {
+					m.endVisual(false)
+				}
+				m.specialsView = true
+			}
+		case "O":
+			// Set which provider episode the AniList entry starts at
+			if len(m.episodes) > 0 {
+				return m, func() tea.Msg { return CourOffsetRequestedMsg{} }
+			}
+		case "[":
+			m.jumpArc(-1)
+		case "]":
+			m.jumpArc(1)
 		case "/":
 			// Activate fuzzy search
+			if m.visualMode {
+				m.endVisual(false)
+			}
 			cmd := m.fuzzySearch.Activate()
 			m.currentIndex = 0
 			return m, cmd
@@ -231,6 +388,9 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.currentIndex < 0 {
 				m.currentIndex = 0
 			}
+			if m.visualMode {
+				m.applyVisual()
+			}
 		case "down", "j":
 			if m.currentIndex < maxIndex {
 				m.currentIndex++
@@ -239,6 +399,9 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.currentIndex > maxIndex {
 				m.currentIndex = maxIndex
 			}
+			if m.visualMode {
+				m.applyVisual()
+			}
 		case "enter":
 			if len(m.episodes) > 0 {
 				selected := m.episodes[m.currentIndex]
@@ -250,24 +413,28 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+		case "S":
+			// Pick the server/mirror before playing
+			if len(m.episodes) > 0 && m.mediaType != providers.MediaTypeManga && !m.singleSource {
+				selected := m.episodes[m.currentIndex]
+				return m, func() tea.Msg {
+					return common.EpisodeSelectedMsg{
+						EpisodeID:    selected.ID,
+						Number:       selected.Number,
+						Title:        selected.Title,
+						ChooseSource: true,
+					}
+				}
+			}
 		case "d":
 			// Download selected episode(s)
 			if len(m.selectedItems) > 0 {
-				// Batch download - collect all selected episodes in order
-				selectedEpisodes := make([]common.EpisodeInfo, 0, len(m.selectedItems))
-				// Iterate through episodes in order, not map keys
-				for idx, ep := range m.episodes {
-					if m.selectedItems[idx] {
-						selectedEpisodes = append(selectedEpisodes, common.EpisodeInfo{
-							EpisodeID: ep.ID,
-							Number:    ep.Number,
-							Title:     ep.Title,
-						})
-					}
-				}
-				return m, func() tea.Msg {
-					return common.BatchDownloadMsg{Episodes: selectedEpisodes}
+				// Batch download - show the summary before queueing
+				if m.visualMode {
+					m.endVisual(false)
 				}
+				m.confirmBatch = true
+				return m, nil
 			} else if len(m.episodes) > 0 {
 				// Single download
 				selected := m.episodes[m.currentIndex]
@@ -279,6 +446,19 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+		case "D":
+			// Download the selected episode and watch it while it downloads
+			if len(m.episodes) > 0 && m.mediaType != providers.MediaTypeManga {
+				selected := m.episodes[m.currentIndex]
+				return m, func() tea.Msg {
+					return common.EpisodeSelectedMsg{
+						EpisodeID: selected.ID,
+						Number:    selected.Number,
+						Title:     selected.Title,
+						Download:  true,
+					}
+				}
+			}
 		case "w":
 			// Share selected episode via WatchParty
 			if len(m.episodes) > 0 {
@@ -303,7 +483,21 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+		case "i":
+			// Expand the synopsis of the highlighted episode
+			m.expanded = !m.expanded
+		case "I":
+			// Show the selected episode's details beside the list
+			m.detailPane = !m.detailPane
 		case "esc":
+			if m.detailPane {
+				m.detailPane = false
+				return m, nil
+			}
+			if m.expanded {
+				m.expanded = false
+				return m, nil
+			}
 			return m, func() tea.Msg {
 				return common.BackMsg{}
 			}
@@ -324,6 +518,23 @@ func (m MangalModel) View() string {
 		return styles.SubtitleStyle.Render(msg)
 	}
 
+	if m.confirmBatch {
+		return m.renderBatchConfirm()
+	}
+	if m.arcView {
+		return m.renderArcs()
+	}
+	if m.specialsView {
+		return m.renderSpecials()
+	}
+	if m.detailPane {
+		return m.renderWithDetail()
+	}
+	return m.renderList()
+}
+
+// renderList renders the header, episode rows and help
+func (m MangalModel) renderList() string {
 	var output string
 
 	// Static header - always at top
@@ -344,7 +555,29 @@ func (m MangalModel) View() string {
 	if len(m.selectedItems) > 0 {
 		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" • %d selected", len(m.selectedItems)))
 	}
-	output += count + "\n"
+	if m.visualMode {
+		count += styles.StatusBadgeStyle.Foreground(styles.OxocarbonPurple).Render(" VISUAL")
+	}
+	if episode, ok := m.selectedEpisode(); ok && episode.Arc != "" {
+		count += styles.AniListMetadataStyle.Render(" • " + episode.Arc)
+	}
+	if len(m.specials) > 0 {
+		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" • %d specials (o)", len(m.specials)))
+	}
+	output += utils.Truncate(count, m.width) + "\n"
+
+	if m.rangeActive {
+		output += "\n" + m.rangeInput.View() + "\n"
+		if m.rangeErr != "" {
+			output += styles.AniListMetadataStyle.Foreground(styles.OxocarbonRed).Render(m.rangeErr) + "\n"
+		}
+	}
+	if m.jumpActive {
+		output += "\n" + m.jumpInput.View() + "\n"
+		if m.jumpErr != "" {
+			output += styles.AniListMetadataStyle.Foreground(styles.OxocarbonRed).Render(m.jumpErr) + "\n"
+		}
+	}
 
 	// Extra spacing after header
 	output += "\n"
@@ -377,7 +610,11 @@ func (m MangalModel) View() string {
 		episode := m.episodes[actualIndex]
 		isSelected := i == m.currentIndex
 		isMarked := m.selectedItems[actualIndex]
-		output += m.renderEpisodeItem(episode, isSelected, isMarked) + "\n\n"
+		output += m.renderEpisodeItem(episode, isSelected, isMarked) + "\n"
+		if !utils.Short(m.height) {
+			// Arcs start in the blank line between rows
+			output += m.arcSeparator(filteredIndices, i, visibleEnd) + "\n"
+		}
 	}
 
 	// Help text at bottom
@@ -385,7 +622,7 @@ func (m MangalModel) View() string {
 	if m.mediaType == providers.MediaTypeManga {
 		action = "read"
 	}
-	helpText := fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • space sel • a all • c clear • / filter • esc back", action)
+	helpText := fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • space sel • v/r range • a all • c clear • / filter • : jump • esc back", action)
 	if m.fuzzySearch.IsActive() {
 		helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • esc clear", action)
 	}
@@ -394,14 +631,34 @@ func (m MangalModel) View() string {
 		if m.fuzzySearch.IsActive() {
 			helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • s src • esc clear", action)
 		} else {
+			pick := "S pick src • "
+			if m.singleSource {
+				pick = ""
+			}
 			if m.mediaType == providers.MediaTypeAnime {
-				helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • s src • m manga • / filter • esc back", action)
+				helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • %sd dl • v/r range • s src • m manga • / filter • : jump • esc back", action, pick)
 			} else {
-				helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • s src • / filter • esc back", action)
+				helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • i synopsis • %sd dl • v/r range • s src • / filter • : jump • esc back", action, pick)
 			}
 		}
 	}
-	output += "\n" + styles.AniListHelpStyle.Render(helpText)
+	// Narrow terminals get the essentials, the rest is in the ? help
+	shortHelp := fmt.Sprintf("  ↑/↓ • enter %s • d dl • / filter • esc back", action)
+	if m.fuzzySearch.IsActive() {
+		shortHelp = fmt.Sprintf("  ↑/↓ • enter %s • d dl • esc clear", action)
+	}
+	if m.detailPane && !m.fuzzySearch.IsActive() {
+		helpText = fmt.Sprintf("  ↑/↓ nav • enter %s • d dl • I hide details • esc close", action)
+		shortHelp = fmt.Sprintf("  ↑/↓ • enter %s • I hide • esc close", action)
+	}
+	if m.rangeActive {
+		helpText = "  enter select • esc cancel"
+	} else if m.jumpActive {
+		helpText = "  enter jump • esc cancel"
+	} else if m.visualMode {
+		helpText = "  ↑/↓ extend • v done • d dl • esc cancel"
+	}
+	output += "\n" + styles.AniListHelpStyle.Render(utils.FitHelp(m.width, helpText, shortHelp))
 
 	return output
 }
@@ -411,22 +668,31 @@ func (m MangalModel) renderEpisodeItem(episode providers.Episode, selected bool,
 	titleStyle := styles.AniListTitleStyle
 	metaStyle := styles.AniListMetadataStyle
 
+	unaired := Unaired(episode, m.nextAiring, time.Now())
 	if selected {
 		boxStyle = styles.AniListItemSelectedStyle
 		titleStyle = titleStyle.Foreground(styles.OxocarbonPurple)
 		metaStyle = metaStyle.Foreground(styles.OxocarbonMauve)
 	}
+	if unaired {
+		// Grey out episodes that can't be played yet
+		titleStyle = titleStyle.Foreground(styles.OxocarbonBase03)
+		metaStyle = metaStyle.Foreground(styles.OxocarbonBase03)
+	}
 
-	// Clean title
-	cleanedTitle := episode.Title
-	re := regexp.MustCompile(`^Eps \d+[:\-\s]*`)
-	cleanedTitle = re.ReplaceAllString(cleanedTitle, "")
-	cleanedTitle = strings.TrimSpace(cleanedTitle)
+	cleanedTitle := cleanTitle(episode.Title)
 
 	prefix := "Episode"
 	if m.mediaType == providers.MediaTypeManga {
 		prefix = "Chapter"
 	}
+	// Compact rows abbreviate the number and leave out the volume, scan
+	// group and runtime
+	compact := utils.Compact(m.width)
+	numPrefix := prefix
+	if compact {
+		numPrefix = prefix[:2]
+	}
 
 	var content string
 	// Show selection indicator
@@ -436,21 +702,79 @@ func (m MangalModel) renderEpisodeItem(episode providers.Episode, selected bool,
 	}
 
 	// Always show episode number
-	episodeNum := metaStyle.Render(fmt.Sprintf("%s%s %d", selIndicator, prefix, episode.Number))
+	numText := fmt.Sprintf("%s%s %d", selIndicator, numPrefix, episode.Number)
+	if episode.Volume > 0 && !compact {
+		numText += fmt.Sprintf(" · Vol. %d", episode.Volume)
+	}
+	if episode.ScanGroup != "" && !compact {
+		numText += fmt.Sprintf(" · %s", episode.ScanGroup)
+	}
+	if unaired && episode.Number == m.nextAiring && !m.nextAiringAt.IsZero() {
+		numText += " · " + i18n.T("airs in %s", utils.FormatCountdown(time.Until(m.nextAiringAt)))
+	} else if unaired && episode.ReleaseDate.IsZero() {
+		numText += " · " + i18n.T("not yet aired")
+	} else if !episode.ReleaseDate.IsZero() {
+		if episode.ReleaseDate.After(time.Now()) {
+			numText += fmt.Sprintf(" · airs %s", episode.ReleaseDate.Format("Jan 2, 2006"))
+		} else {
+			numText += fmt.Sprintf(" · %s", episode.ReleaseDate.Format("Jan 2, 2006"))
+		}
+	}
+	if episode.Duration > 0 && !compact {
+		numText += fmt.Sprintf(" · %dm", int(episode.Duration.Minutes()))
+	}
+	episodeNum := metaStyle.Render(utils.Truncate(numText, utils.ItemWidth(m.width)))
 
 	// Show title if available, otherwise empty line to maintain height
 	var title string
 	if cleanedTitle != "" && cleanedTitle != fmt.Sprintf("%s %d", prefix, episode.Number) {
-		title = titleStyle.Render(cleanedTitle)
+		title = titleStyle.Render(utils.Truncate(cleanedTitle, utils.ItemWidth(m.width)))
 	} else {
 		title = " " // Empty line with space to maintain height
 	}
 
 	content = episodeNum + "\n" + title
 
+	if selected && m.expanded {
+		content += "\n" + m.renderSynopsis(episode.Synopsis)
+	}
+
 	return boxStyle.Render(content)
 }
 
+// cleanTitle strips the episode number prefix some providers put in titles
+func cleanTitle(title string) string {
+	return strings.TrimSpace(episodePrefix.ReplaceAllString(title, ""))
+}
+
+// renderSynopsis renders an episode synopsis wrapped to the view width and
+// cut to synopsisLines lines, fewer on short terminals
+func (m MangalModel) renderSynopsis(synopsis string) string {
+	if synopsis == "" {
+		return styles.AniListMetadataStyle.Render("No synopsis available")
+	}
+
+	width := utils.ItemWidth(m.width)
+	if width == 0 {
+		width = 60
+	}
+	wrapped := lipgloss.NewStyle().Width(width).Render(synopsis)
+	lines := strings.Split(wrapped, "\n")
+	if n := m.synopsisLines(); len(lines) > n {
+		lines = lines[:n]
+		lines[n-1] = utils.Truncate(strings.TrimRight(lines[n-1], " ")+"…", width)
+	}
+	return styles.SubtitleStyle.Render(strings.Join(lines, "\n"))
+}
+
+// synopsisLines returns how many lines an expanded synopsis takes
+func (m MangalModel) synopsisLines() int {
+	if utils.Short(m.height) {
+		return 2
+	}
+	return synopsisLines
+}
+
 // getFilteredIndices returns the indices of episodes that match the fuzzy search
 func (m MangalModel) getFilteredIndices() []int {
 	searchStrings := make([]string, len(m.episodes))
@@ -459,7 +783,7 @@ func (m MangalModel) getFilteredIndices() []int {
 		prefix = "Chapter"
 	}
 	for i, episode := range m.episodes {
-		searchStrings[i] = fmt.Sprintf("%s %d %s", prefix, episode.Number, episode.Title)
+		searchStrings[i] = fmt.Sprintf("%s %d %s %s", prefix, episode.Number, episode.Title, episode.Arc)
 	}
 	return m.fuzzySearch.Filter(searchStrings)
 }
@@ -473,9 +797,23 @@ func (m MangalModel) getVisibleRange(total int) (int, int) {
 		// Each item takes ~3 lines (title + metadata + margin)
 		// Overhead: header (2) + count (1) + spacing (1) + help (2) = 6 lines
 		// We use a larger safety margin (10) to ensure no scrolling
-		itemsSpace := m.height - 10
+		itemsSpace, linesPerItem := m.height-10, 3
+		if utils.Short(m.height) {
+			// No blank line between items, and only the header, count and
+			// help around them
+			itemsSpace, linesPerItem = m.height-6, 2
+			if m.fuzzySearch.IsActive() {
+				itemsSpace -= 5
+			}
+			if m.rangeActive || m.jumpActive {
+				itemsSpace -= 3
+			}
+		}
+		if m.expanded {
+			itemsSpace -= m.synopsisLines()
+		}
 		if itemsSpace > 0 {
-			maxVisible = itemsSpace / 3 // 3 lines per item
+			maxVisible = itemsSpace / linesPerItem
 		}
 
 		// Ensure at least 1 item visible
@@ -0,0 +1,377 @@
+package episodes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/tuitest"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+var sizes = []struct{ width, height int }{{50, 15}, {60, 20}, {69, 24}, {80, 24}, {120, 40}}
+
+func sampleEpisodes() []providers.Episode {
+	episodes := make([]providers.Episode, 0, 12)
+	for i := 1; i <= 12; i++ {
+		episodes = append(episodes, providers.Episode{
+			ID:          fmt.Sprintf("ep-%d", i),
+			Number:      i,
+			Title:       fmt.Sprintf("Eps %d: The Journey's End and the Long Road Back to the Village", i),
+			ReleaseDate: time.Date(2023, 9, 29, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 7*i),
+			Duration:    24 * time.Minute,
+			Synopsis:    "The party returns to the capital fifty years later to watch the meteor shower together one last time.",
+		})
+	}
+	return episodes
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	for _, mediaType := range []providers.MediaType{providers.MediaTypeAnime, providers.MediaTypeMovieTV} {
+		for _, size := range sizes {
+			t.Run(fmt.Sprintf("%s/%dx%d", mediaType, size.width, size.height), func(t *testing.T) {
+				m := New()
+				m.SetMediaType(mediaType)
+				m.SetEpisodes(sampleEpisodes())
+				m.SetAiring(12, time.Now().Add(52*time.Hour))
+				updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+				m = updated.(Model)
+				m.mangal.expanded = true
+				m.mangal.selectedItems[1] = true
+
+				tuitest.AssertFits(t, m.View(), size.width, size.height)
+			})
+		}
+	}
+}
+
+func TestBatchConfirmFitsTerminal(t *testing.T) {
+	m := New()
+	m.SetMediaType(providers.MediaTypeAnime)
+	m.SetEpisodes(sampleEpisodes())
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 50, Height: 15})
+	m = updated.(Model)
+	for i := range m.mangal.episodes {
+		m.mangal.selectedItems[i] = true
+	}
+	m.mangal.confirmBatch = true
+	m.mangal.batchQuality = providers.Quality1080p
+
+	tuitest.AssertFits(t, m.View(), 50, 15)
+}
+
+// thumbnail is a stand-in for a rendered thumbnail w cells wide and h tall
+func thumbnail(w, h int) string {
+	return strings.TrimSuffix(strings.Repeat(strings.Repeat("▀", w)+"\n", h), "\n")
+}
+
+func TestDetailPaneFitsTerminal(t *testing.T) {
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			m := New()
+			m.SetMediaType(providers.MediaTypeAnime)
+			m.SetEpisodes(sampleEpisodes())
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(Model)
+			updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("I")})
+			m = updated.(Model)
+
+			width, height := m.mangal.thumbnailSize()
+			episode := m.GetEpisodes()[0]
+			m.SetEpisodeDetail(episode, thumbnail(width, height))
+
+			tuitest.AssertFits(t, m.View(), size.width, size.height)
+		})
+	}
+}
+
+func TestDetailPaneRequestsEachEpisodeOnce(t *testing.T) {
+	episodes := sampleEpisodes()
+	for i := range episodes {
+		episodes[i].Synopsis = ""
+	}
+	m := New()
+	m.SetEpisodes(episodes)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	press := func(key string) tea.Msg {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+		if cmd == nil {
+			return nil
+		}
+		return cmd()
+	}
+
+	msg := press("I")
+	require.IsType(t, common.RequestEpisodeDetailMsg{}, msg)
+	request := msg.(common.RequestEpisodeDetailMsg)
+	assert.Equal(t, "ep-1", request.Episode.ID)
+	assert.Positive(t, request.ThumbWidth)
+	assert.Positive(t, request.ThumbHeight)
+	assert.Contains(t, m.View(), "Loading details…")
+
+	msg = press("j")
+	require.IsType(t, common.RequestEpisodeDetailMsg{}, msg)
+	assert.Equal(t, "ep-2", msg.(common.RequestEpisodeDetailMsg).Episode.ID)
+
+	// Coming back to an episode doesn't fetch it again
+	assert.Nil(t, press("k"))
+
+	episode := m.GetEpisodes()[0]
+	episode.Synopsis = "Frieren sets out for the northern lands."
+	m.SetEpisodeDetail(episode, "")
+	assert.Contains(t, m.View(), "Frieren sets out")
+
+	// Closing the pane stops fetching
+	press("I")
+	assert.Nil(t, press("j"))
+}
+
+// longRunning returns count episodes split into arcs of arcLength episodes
+func longRunning(count, arcLength int) []providers.Episode {
+	episodes := make([]providers.Episode, 0, count)
+	for i := 1; i <= count; i++ {
+		episodes = append(episodes, providers.Episode{
+			ID:     fmt.Sprintf("ep-%d", i),
+			Number: i,
+			Title:  fmt.Sprintf("Episode %d", i),
+			Arc:    fmt.Sprintf("Arc %d", (i-1)/arcLength+1),
+		})
+	}
+	return episodes
+}
+
+// typeKeys sends each rune of keys, then the given special keys
+func typeKeys(m Model, keys string, special ...tea.KeyType) Model {
+	for _, r := range keys {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	for _, key := range special {
+		updated, _ := m.Update(tea.KeyMsg{Type: key})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestJumpToEpisode(t *testing.T) {
+	episodes := longRunning(1100, 50)
+	// A gap in the numbering, as after a recap the provider left out
+	episodes = append(episodes[:499], episodes[502:]...)
+
+	m := New()
+	m.SetEpisodes(episodes)
+
+	m = typeKeys(m, ":500", tea.KeyEnter)
+	assert.False(t, m.IsInputActive())
+	assert.Equal(t, 503, m.GetEpisodes()[m.GetCurrentIndex()].Number, "missing episodes jump to the next one")
+
+	m = typeKeys(m, ":42", tea.KeyEnter)
+	assert.Equal(t, 42, m.GetEpisodes()[m.GetCurrentIndex()].Number)
+
+	m = typeKeys(m, ":99999", tea.KeyEnter)
+	assert.Equal(t, 1100, m.GetEpisodes()[m.GetCurrentIndex()].Number)
+
+	m = typeKeys(m, ":abc", tea.KeyEnter)
+	assert.True(t, m.IsInputActive(), "invalid numbers keep the prompt open")
+	assert.NotEmpty(t, m.mangal.jumpErr)
+	m = typeKeys(m, "", tea.KeyEsc)
+	assert.False(t, m.IsInputActive())
+	assert.Equal(t, 1100, m.GetEpisodes()[m.GetCurrentIndex()].Number)
+}
+
+func TestArcNavigation(t *testing.T) {
+	m := New()
+	m.SetEpisodes(longRunning(200, 50))
+	number := func() int { return m.GetEpisodes()[m.GetCurrentIndex()].Number }
+
+	m = typeKeys(m, "]")
+	assert.Equal(t, 51, number())
+	m = typeKeys(m, "]]")
+	assert.Equal(t, 151, number())
+	m = typeKeys(m, "]")
+	assert.Equal(t, 151, number(), "the last arc has no next one")
+
+	m = typeKeys(m, "jj[")
+	assert.Equal(t, 151, number(), "back to the start of the current arc")
+	m = typeKeys(m, "[")
+	assert.Equal(t, 101, number())
+}
+
+func TestFilterMatchesArcs(t *testing.T) {
+	episodes := longRunning(120, 50)
+	episodes[60].Arc = "Wano Country"
+	episodes[61].Arc = "Wano Country"
+
+	m := New()
+	m.SetEpisodes(episodes)
+	m = typeKeys(m, "/wano")
+
+	assert.ElementsMatch(t, []int{60, 61}, m.mangal.getFilteredIndices())
+}
+
+func TestArcsFitTerminal(t *testing.T) {
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			episodes := longRunning(200, 3)
+			episodes[3].Arc = "The Extraordinarily Long Name of an Arc That Goes On and On Forever"
+			m := New()
+			m.SetMediaType(providers.MediaTypeAnime)
+			m.SetEpisodes(episodes)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(Model)
+			m = typeKeys(m, ":")
+
+			view := m.View()
+			tuitest.AssertFits(t, view, size.width, size.height)
+			if !utils.Short(size.height) {
+				assert.Contains(t, view, "── The Extraordinarily Long Name")
+			}
+		})
+	}
+}
+
+func TestArcOutline(t *testing.T) {
+	m := New()
+	m.SetEpisodes(longRunning(200, 50))
+	number := func() int { return m.GetEpisodes()[m.GetCurrentIndex()].Number }
+
+	m = typeKeys(m, "z")
+	require.True(t, m.mangal.arcView)
+	assert.Contains(t, m.View(), "4 arcs")
+
+	m = typeKeys(m, "jj", tea.KeyEnter)
+	assert.False(t, m.mangal.arcView)
+	assert.Equal(t, 101, number(), "enter opens the arc at its first episode")
+
+	// Download a whole arc from the outline
+	m = typeKeys(m, "zjd")
+	require.True(t, m.mangal.confirmBatch)
+	assert.Len(t, m.mangal.selectedItems, 50)
+	assert.True(t, m.mangal.selectedItems[150])
+	assert.False(t, m.mangal.selectedItems[149])
+}
+
+func TestArcOutlineMarksArcs(t *testing.T) {
+	m := New()
+	m.SetEpisodes(longRunning(100, 25))
+
+	m = typeKeys(m, "z  ")
+	assert.Len(t, m.mangal.selectedItems, 50, "space marks an arc and moves to the next")
+	m = typeKeys(m, "k ")
+	assert.Len(t, m.mangal.selectedItems, 25, "space on a marked arc unmarks it")
+
+	m = typeKeys(m, "z")
+	assert.False(t, m.mangal.arcView)
+	assert.Equal(t, 50, m.GetCurrentIndex(), "the list opens on the arc the outline was on")
+}
+
+func TestArcOutlineNeedsArcs(t *testing.T) {
+	m := New()
+	m.SetEpisodes(sampleEpisodes())
+	m = typeKeys(m, "z")
+	assert.False(t, m.mangal.arcView)
+}
+
+func TestArcOutlineFitsTerminal(t *testing.T) {
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			episodes := longRunning(400, 10)
+			episodes[10].Arc = "The Extraordinarily Long Name of an Arc That Goes On and On Forever"
+			m := New()
+			m.SetMediaType(providers.MediaTypeAnime)
+			m.SetEpisodes(episodes)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(Model)
+			m = typeKeys(m, "z  j")
+
+			tuitest.AssertFits(t, m.View(), size.width, size.height)
+		})
+	}
+}
+
+func sampleSpecials() []Special {
+	return []Special{
+		{AniListID: 4, Title: "Frieren: Beyond Journey's End Mini Anime — Frieren and the Unusually Long Special Title", Format: "ONA", Year: 2023, Episodes: 10, Watched: 3},
+		{AniListID: 3, Title: "Frieren: The Movie", Format: "MOVIE", Year: 2025, Episodes: 1, Watched: 1, Completed: true},
+	}
+}
+
+func TestSpecials(t *testing.T) {
+	m := New()
+	m.SetMediaType(providers.MediaTypeAnime)
+	m.SetEpisodes(sampleEpisodes())
+
+	m = typeKeys(m, "o")
+	assert.False(t, m.mangal.specialsView, "nothing to list without specials")
+
+	m.SetSpecials(sampleSpecials())
+	m.SetEpisodes(sampleEpisodes())
+	assert.Contains(t, m.View(), "2 specials (o)", "specials are kept when the episodes are replaced")
+
+	m = typeKeys(m, "o")
+	require.True(t, m.mangal.specialsView)
+	view := m.View()
+	assert.Contains(t, view, "3/10 watched")
+	assert.Contains(t, view, "✓ Movie · 2025")
+
+	m = typeKeys(m, "j")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.Equal(t, SpecialSelectedMsg{Special: sampleSpecials()[1]}, cmd())
+
+	m = typeKeys(m, "", tea.KeyEsc)
+	assert.False(t, m.mangal.specialsView)
+}
+
+func TestCourOffsetRequested(t *testing.T) {
+	m := New()
+	m.SetMediaType(providers.MediaTypeAnime)
+	m.SetEpisodes(sampleEpisodes())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, CourOffsetRequestedMsg{}, cmd())
+}
+
+func TestPickSourceNeedsSeveralStreams(t *testing.T) {
+	m := New()
+	m.SetMediaType(providers.MediaTypeAnime)
+	m.SetCapabilities(providers.Capabilities{Streams: true})
+	m.SetEpisodes(sampleEpisodes())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	assert.Nil(t, cmd, "a provider with one stream per episode has no source to pick")
+	assert.NotContains(t, m.View(), "S pick src")
+
+	m.SetCapabilities(providers.Capabilities{Streams: true, Sources: true})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	require.NotNil(t, cmd)
+	assert.True(t, cmd().(common.EpisodeSelectedMsg).ChooseSource)
+}
+
+func TestSpecialsFitTerminal(t *testing.T) {
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			m := New()
+			m.SetMediaType(providers.MediaTypeAnime)
+			m.SetEpisodes(sampleEpisodes())
+			m.SetSpecials(sampleSpecials())
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(Model)
+
+			tuitest.AssertFits(t, m.View(), size.width, size.height)
+			m = typeKeys(m, "o")
+			tuitest.AssertFits(t, m.View(), size.width, size.height)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package episodes
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+func newJumpInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "500"
+	ti.Prompt = ":"
+	ti.CharLimit = 6
+	ti.Width = 10
+	return ti
+}
+
+// handleJumpKeys handles the ":500" jump prompt
+func (m MangalModel) handleJumpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.jumpActive = false
+		m.jumpErr = ""
+		m.jumpInput.Blur()
+		return m, nil
+	case "enter":
+		number, err := strconv.Atoi(strings.TrimSpace(m.jumpInput.Value()))
+		if err != nil || number < 0 {
+			m.jumpErr = "Enter an episode number, e.g. 500"
+			return m, nil
+		}
+		m.jumpTo(number)
+		m.jumpActive = false
+		m.jumpErr = ""
+		m.jumpInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.jumpInput, cmd = m.jumpInput.Update(msg)
+	return m, cmd
+}
+
+// jumpTo moves the cursor to the episode numbered number, or the first one
+// after it when there is no such episode, or the last episode
+func (m *MangalModel) jumpTo(number int) {
+	if len(m.episodes) == 0 {
+		return
+	}
+	m.currentIndex = len(m.episodes) - 1
+	for i, episode := range m.episodes {
+		if episode.Number >= number {
+			m.currentIndex = i
+			break
+		}
+	}
+	if m.visualMode {
+		m.applyVisual()
+	}
+}
+
+// hasArcs reports whether any episode has a story arc
+func (m MangalModel) hasArcs() bool {
+	for _, episode := range m.episodes {
+		if episode.Arc != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// jumpArc moves the cursor to the first episode of the next arc (dir 1), or
+// of the current arc and then the previous one (dir -1)
+func (m *MangalModel) jumpArc(dir int) {
+	i := m.currentIndex
+	if i < 0 || i >= len(m.episodes) {
+		return
+	}
+	if dir > 0 {
+		for i++; i < len(m.episodes); i++ {
+			if m.episodes[i].Arc != m.episodes[i-1].Arc {
+				m.currentIndex = i
+				break
+			}
+		}
+	} else {
+		if i > 0 && m.episodes[i-1].Arc != m.episodes[i].Arc {
+			i--
+		}
+		for i > 0 && m.episodes[i-1].Arc == m.episodes[i].Arc {
+			i--
+		}
+		m.currentIndex = i
+	}
+	if m.visualMode {
+		m.applyVisual()
+	}
+}
+
+// arcSeparator returns the line between the visible rows i and i+1 of the
+// filtered list: the name of the arc row i+1 starts, or "" when it's in the
+// same arc
+func (m MangalModel) arcSeparator(filteredIndices []int, i, visibleEnd int) string {
+	if i+1 >= visibleEnd || i+1 >= len(filteredIndices) {
+		return ""
+	}
+	current, next := m.episodes[filteredIndices[i]], m.episodes[filteredIndices[i+1]]
+	if next.Arc == "" || next.Arc == current.Arc {
+		return ""
+	}
+	return styles.AniListMetadataStyle.Render(utils.Truncate("   ── "+next.Arc+" ──", m.width))
+}
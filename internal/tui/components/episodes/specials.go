@@ -0,0 +1,136 @@
+package episodes
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// Special is an OVA, special or movie attached to the series, listed apart
+// from its episodes since providers rarely number them along with them
+type Special struct {
+	AniListID int
+	MALID     int // 0 if unknown
+	Title     string
+	Format    string // OVA, SPECIAL, MOVIE or ONA
+	Year      int    // 0 if unknown
+	Episodes  int    // 0 when unknown
+	Watched   int    // Episodes watched
+	Completed bool
+}
+
+// SpecialSelectedMsg is sent when a special is picked to watch
+type SpecialSelectedMsg struct {
+	Special Special
+}
+
+// formatNames are the labels of AniList formats
+var formatNames = map[string]string{
+	"OVA":     "OVA",
+	"SPECIAL": "Special",
+	"MOVIE":   "Movie",
+	"ONA":     "ONA",
+}
+
+// SetSpecials sets the specials attached to the series
+func (m *MangalModel) SetSpecials(specials []Special) {
+	m.specials = specials
+	m.specialIndex = min(m.specialIndex, max(len(specials)-1, 0))
+	if len(specials) == 0 {
+		m.specialsView = false
+	}
+}
+
+// handleSpecialsKeys handles the list of specials
+func (m MangalModel) handleSpecialsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if len(m.specials) == 0 {
+		m.specialsView = false
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.specialIndex = max(m.specialIndex-1, 0)
+	case "down", "j":
+		m.specialIndex = min(m.specialIndex+1, len(m.specials)-1)
+	case "enter":
+		selected := m.specials[m.specialIndex]
+		return m, func() tea.Msg {
+			return SpecialSelectedMsg{Special: selected}
+		}
+	case "o", "esc":
+		m.specialsView = false
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderSpecials renders the specials attached to the series
+func (m MangalModel) renderSpecials() string {
+	watched := 0
+	for _, special := range m.specials {
+		if special.Completed {
+			watched++
+		}
+	}
+
+	output := styles.TitleStyle.Render("SPECIALS") + "\n"
+	count := styles.SubtitleStyle.Render(fmt.Sprintf("%d OVAs, specials and movies", len(m.specials)))
+	if watched > 0 {
+		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" • %d watched", watched))
+	}
+	output += utils.Truncate(count, m.width) + "\n\n"
+
+	window := m
+	window.currentIndex = m.specialIndex
+	visibleStart, visibleEnd := window.getVisibleRange(len(m.specials))
+	for i := visibleStart; i < visibleEnd; i++ {
+		output += m.renderSpecialItem(m.specials[i], i == m.specialIndex) + "\n"
+		if !utils.Short(m.height) {
+			output += "\n"
+		}
+	}
+
+	helpText := "  ↑/↓ nav • enter watch • o episodes • esc back"
+	shortHelp := "  ↑/↓ • enter watch • esc back"
+	output += "\n" + styles.AniListHelpStyle.Render(utils.FitHelp(m.width, helpText, shortHelp))
+	return output
+}
+
+// renderSpecialItem renders a special's row: its format, year and how much
+// of it was watched, then its title
+func (m MangalModel) renderSpecialItem(special Special, selected bool) string {
+	boxStyle := styles.AniListItemStyle
+	titleStyle := styles.AniListTitleStyle
+	metaStyle := styles.AniListMetadataStyle
+	if selected {
+		boxStyle = styles.AniListItemSelectedStyle
+		titleStyle = titleStyle.Foreground(styles.OxocarbonPurple)
+		metaStyle = metaStyle.Foreground(styles.OxocarbonMauve)
+	}
+
+	indicator := "  "
+	if special.Completed {
+		indicator = "✓ "
+	}
+	format := formatNames[special.Format]
+	if format == "" {
+		format = special.Format
+	}
+	meta := indicator + format
+	if special.Year > 0 {
+		meta += fmt.Sprintf(" · %d", special.Year)
+	}
+	switch {
+	case special.Episodes > 1:
+		meta += fmt.Sprintf(" · %d/%d watched", special.Watched, special.Episodes)
+	case special.Episodes == 0 && special.Watched > 0:
+		meta += fmt.Sprintf(" · %d watched", special.Watched)
+	}
+
+	width := utils.ItemWidth(m.width)
+	return boxStyle.Render(metaStyle.Render(utils.Truncate(meta, width)) + "\n" + titleStyle.Render(utils.Truncate(special.Title, width)))
+}
@@ -6,6 +6,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/styles"
 )
 
@@ -31,8 +33,15 @@ type Shortcut struct {
 	Key         string
 	Description string
 	Context     []HelpContext
+	Requires    func(providers.Capabilities) bool // nil when every provider supports it
 }
 
+// Requirements of shortcuts that not every provider supports
+var (
+	needsStreams    = func(c providers.Capabilities) bool { return c.Streams }
+	needsSourcePick = providers.Capabilities.CanPickSource
+)
+
 // Model represents the help panel state
 type Model struct {
 	context      HelpContext
@@ -40,7 +49,8 @@ type Model struct {
 	height       int
 	visible      bool
 	providerName string
-	scrollOffset int // Scroll position for help content
+	capabilities *providers.Capabilities // Of the current provider, nil when unknown
+	scrollOffset int                     // Scroll position for help content
 }
 
 // all shortcuts organized by context
@@ -50,9 +60,15 @@ var allShortcuts = []Shortcut{
 	{Key: "enter", Description: "Select item", Context: []HelpContext{GlobalContext}},
 	{Key: "esc", Description: "Go back / Cancel", Context: []HelpContext{GlobalContext}},
 	{Key: "ctrl+h", Description: "Return to home", Context: []HelpContext{GlobalContext}},
+	{Key: "alt+←/→", Description: "Back/forward through visited pages", Context: []HelpContext{GlobalContext}},
 	{Key: "q", Description: "Quit application", Context: []HelpContext{GlobalContext}},
 	{Key: "d", Description: "Go to downloads (from home)", Context: []HelpContext{GlobalContext}},
 	{Key: "?", Description: "Show/hide this help", Context: []HelpContext{GlobalContext}},
+	{Key: "ctrl+n", Description: "Show notifications", Context: []HelpContext{GlobalContext}},
+	{Key: "ctrl+t", Description: "Show bandwidth stats", Context: []HelpContext{GlobalContext}},
+	{Key: "ctrl+o", Description: "Play/pause ambient radio", Context: []HelpContext{GlobalContext}},
+	{Key: "alt+o", Description: "Next ambient station", Context: []HelpContext{GlobalContext}},
+	{Key: "alt+O", Description: "Stop ambient radio", Context: []HelpContext{GlobalContext}},
 
 	// Home context
 	{Key: "s", Description: "Open search", Context: []HelpContext{HomeContext}},
@@ -60,12 +76,15 @@ var allShortcuts = []Shortcut{
 	{Key: "d", Description: "View downloads", Context: []HelpContext{HomeContext}},
 	{Key: "h", Description: "View watch history", Context: []HelpContext{HomeContext}},
 	{Key: "P", Description: "View provider health status", Context: []HelpContext{HomeContext}},
+	{Key: "I", Description: "Toggle incognito (no history or AniList sync)", Context: []HelpContext{HomeContext}},
+	{Key: "W", Description: "Cycle co-watch profiles (separate progress)", Context: []HelpContext{HomeContext}},
 	{Key: "p", Description: "Switch provider", Context: []HelpContext{HomeContext}},
-	{Key: "tab", Description: "Toggle anime/movies/manga", Context: []HelpContext{HomeContext}},
+	{Key: "tab", Description: "Toggle anime/movies/manga", Context: []HelpContext{HomeContext, SearchContext, ResultsContext}},
 	{Key: "1", Description: "Switch to movies/TV", Context: []HelpContext{HomeContext}},
 	{Key: "2", Description: "Switch to anime", Context: []HelpContext{HomeContext}},
 	{Key: "3", Description: "Switch to manga", Context: []HelpContext{HomeContext}},
 	{Key: "w", Description: "Share recent item via WatchParty", Context: []HelpContext{HomeContext}},
+	{Key: "o", Description: "Play a stream URL", Context: []HelpContext{HomeContext}},
 
 	// Search context (when not typing)
 	{Key: "p", Description: "Switch provider", Context: []HelpContext{SearchContext}},
@@ -77,10 +96,24 @@ var allShortcuts = []Shortcut{
 	{Key: "/", Description: "Filter results", Context: []HelpContext{ResultsContext, EpisodesContext, SeasonsContext}},
 	{Key: "p", Description: "Switch provider", Context: []HelpContext{ResultsContext, EpisodesContext, SeasonsContext}},
 	{Key: "d", Description: "Download episode", Context: []HelpContext{ResultsContext, EpisodesContext}},
-	{Key: "i", Description: "Show info", Context: []HelpContext{ResultsContext, EpisodesContext}},
-	{Key: "s", Description: "Show sources", Context: []HelpContext{ResultsContext, EpisodesContext}},
-	{Key: "w", Description: "Share via WatchParty", Context: []HelpContext{ResultsContext, EpisodesContext, SeasonsContext}},
+	{Key: "D", Description: "Download and watch while it downloads", Context: []HelpContext{EpisodesContext}, Requires: needsStreams},
+	{Key: "A", Description: "Download a failed episode again from another provider", Context: []HelpContext{DownloadsContext}},
+	{Key: "i", Description: "Show info", Context: []HelpContext{ResultsContext}},
+	{Key: "f", Description: "Star/unstar as favorite", Context: []HelpContext{HomeContext, ResultsContext, HistoryContext}},
+	{Key: "i", Description: "Expand episode synopsis", Context: []HelpContext{EpisodesContext}},
+	{Key: "I", Description: "Toggle episode detail pane", Context: []HelpContext{EpisodesContext}},
+	{Key: "s", Description: "Show sources", Context: []HelpContext{ResultsContext, EpisodesContext}, Requires: needsStreams},
+	{Key: "S", Description: "Pick source and play", Context: []HelpContext{EpisodesContext}, Requires: needsSourcePick},
+	{Key: "w", Description: "Share via WatchParty", Context: []HelpContext{ResultsContext, EpisodesContext, SeasonsContext}, Requires: needsStreams},
 	{Key: "m", Description: "Manga info", Context: []HelpContext{EpisodesContext, SeasonsContext}},
+	{Key: "space", Description: "Mark for batch download", Context: []HelpContext{EpisodesContext}},
+	{Key: "v", Description: "Mark a range with the cursor", Context: []HelpContext{EpisodesContext}},
+	{Key: "r", Description: "Mark episodes by number (1-12,14)", Context: []HelpContext{EpisodesContext}},
+	{Key: ":", Description: "Jump to episode number", Context: []HelpContext{EpisodesContext}},
+	{Key: "[/]", Description: "Previous/next story arc", Context: []HelpContext{EpisodesContext}},
+	{Key: "z", Description: "Collapse list into story arcs (space marks, d downloads an arc)", Context: []HelpContext{EpisodesContext}},
+	{Key: "o", Description: "OVAs, specials and movies of the series (from AniList)", Context: []HelpContext{EpisodesContext}},
+	{Key: "O", Description: "Set the episode the AniList season starts at in the provider's list", Context: []HelpContext{EpisodesContext}},
 
 	// AniList context
 	{Key: "enter/→", Description: "Play from library", Context: []HelpContext{AniListContext}},
@@ -91,7 +124,13 @@ var allShortcuts = []Shortcut{
 	{Key: "del", Description: "Delete from library", Context: []HelpContext{AniListContext}},
 	{Key: "w", Description: "Filter: watching", Context: []HelpContext{AniListContext}},
 	{Key: "a", Description: "Filter: all", Context: []HelpContext{AniListContext}},
+	{Key: "tab", Description: "Next smart list tab", Context: []HelpContext{AniListContext}},
+	{Key: "space", Description: "Mark for bulk actions", Context: []HelpContext{AniListContext}},
+	{Key: "ctrl+a", Description: "Mark all visible", Context: []HelpContext{AniListContext}},
+	{Key: "L", Description: "Add marked to custom list", Context: []HelpContext{AniListContext}},
 	{Key: "/", Description: "Fuzzy search", Context: []HelpContext{AniListContext}},
+	{Key: "R", Description: "Watch something: random pick from Planning/Paused", Context: []HelpContext{AniListContext}},
+	{Key: "D", Description: "Download every aired episode of the Planning list", Context: []HelpContext{AniListContext}, Requires: needsStreams},
 
 	// History context
 	{Key: "/", Description: "Search history", Context: []HelpContext{HistoryContext}},
@@ -104,6 +143,7 @@ var allShortcuts = []Shortcut{
 	{Key: "p", Description: "Sort by progress", Context: []HelpContext{HistoryContext}},
 	{Key: "x", Description: "Delete selected item", Context: []HelpContext{HistoryContext}},
 	{Key: "X", Description: "Delete all history", Context: []HelpContext{HistoryContext}},
+	{Key: "u", Description: "Undo last delete", Context: []HelpContext{HistoryContext}},
 	{Key: "w", Description: "Share via WatchParty", Context: []HelpContext{HistoryContext}},
 	{Key: "enter", Description: "Play selected item", Context: []HelpContext{HistoryContext}},
 
@@ -111,9 +151,17 @@ var allShortcuts = []Shortcut{
 	{Key: "p", Description: "Pause download", Context: []HelpContext{DownloadsContext}},
 	{Key: "r", Description: "Resume download", Context: []HelpContext{DownloadsContext}},
 	{Key: "c", Description: "Cancel download", Context: []HelpContext{DownloadsContext}},
+	{Key: "i", Description: "Why a download failed, with a command to reproduce it", Context: []HelpContext{DownloadsContext}},
+	{Key: "K/J", Description: "Move queued download up/down", Context: []HelpContext{DownloadsContext}},
+	{Key: "T", Description: "Move queued download to the front", Context: []HelpContext{DownloadsContext}},
 	{Key: "x", Description: "Clear completed", Context: []HelpContext{DownloadsContext}},
+	{Key: "F", Description: "Find duplicate downloads", Context: []HelpContext{DownloadsContext}},
+	{Key: "+/-", Description: "Run more/fewer downloads at once (this session)", Context: []HelpContext{DownloadsContext}},
+	{Key: "u", Description: "Undo last delete", Context: []HelpContext{DownloadsContext}},
 	{Key: "ctrl+r", Description: "Refresh list", Context: []HelpContext{DownloadsContext}},
 	{Key: "/", Description: "Filter downloads", Context: []HelpContext{DownloadsContext}},
+	{Key: "b", Description: "Toggle board layout", Context: []HelpContext{DownloadsContext}},
+	{Key: "H/L", Description: "Move card between board columns", Context: []HelpContext{DownloadsContext}},
 
 	// Settings context (for future use)
 	{Key: "s", Description: "Save settings", Context: []HelpContext{SettingsContext}},
@@ -134,6 +182,17 @@ func (m *Model) SetProviderName(name string) {
 	m.providerName = name
 }
 
+// SetCapabilities sets what the current provider supports. Shortcuts it
+// doesn't are greyed out.
+func (m *Model) SetCapabilities(caps providers.Capabilities) {
+	m.capabilities = &caps
+}
+
+// supported reports whether the current provider supports a shortcut
+func (m Model) supported(sc Shortcut) bool {
+	return sc.Requires == nil || m.capabilities == nil || sc.Requires(*m.capabilities)
+}
+
 // Init initializes the help model
 func (m Model) Init() tea.Cmd {
 	return nil
@@ -199,7 +258,7 @@ func (m Model) View() string {
 
 	// Provider info section (if available)
 	if m.providerName != "" && m.providerName != "Unknown" {
-		providerInfo := fmt.Sprintf("Current Provider: %s", m.providerName)
+		providerInfo := i18n.T("Current Provider: %s", m.providerName)
 		providerStyle := lipgloss.NewStyle().
 			Foreground(styles.OxocarbonPurple).
 			Bold(true).
@@ -210,7 +269,7 @@ func (m Model) View() string {
 	}
 
 	// Navigation instructions at the TOP (always visible)
-	navInstructions := styles.AniListHelpStyle.Render("↑/↓ j/k scroll • d/u half page • space/b page • g/G top/bottom • esc/? close")
+	navInstructions := styles.AniListHelpStyle.Render(i18n.T("↑/↓ j/k scroll • d/u half page • space/b page • g/G top/bottom • esc/? close"))
 	content.WriteString(lipgloss.NewStyle().Width(60).Align(lipgloss.Center).Render(navInstructions))
 	content.WriteString("\n")
 
@@ -220,7 +279,7 @@ func (m Model) View() string {
 
 	// Render global shortcuts
 	if len(globalShortcuts) > 0 {
-		header := styles.AniListHeaderStyle.Render("Navigation & General")
+		header := styles.AniListHeaderStyle.Render(i18n.T("Navigation & General"))
 		content.WriteString(header)
 		content.WriteString("\n")
 		for _, sc := range globalShortcuts {
@@ -235,7 +294,7 @@ func (m Model) View() string {
 		contextName := m.getContextName()
 		if contextName != "" {
 			content.WriteString("\n")
-			header := styles.AniListHeaderStyle.Render(contextName + " Actions")
+			header := styles.AniListHeaderStyle.Render(i18n.T("%s Actions", i18n.T(contextName)))
 			content.WriteString(header)
 			content.WriteString("\n")
 			for _, sc := range contextShortcuts {
@@ -293,7 +352,7 @@ func (m Model) View() string {
 	}
 
 	// Wrap in box with scroll indicator in title
-	boxTitle := "KEYBOARD SHORTCUTS" + scrollInfo
+	boxTitle := i18n.T("KEYBOARD SHORTCUTS") + scrollInfo
 	titleBar := lipgloss.NewStyle().
 		Foreground(styles.OxocarbonWhite).
 		Background(styles.OxocarbonPurple).
@@ -377,7 +436,12 @@ func (m Model) renderShortcutLine(sc Shortcut) string {
 	descStyle := lipgloss.NewStyle().
 		Foreground(styles.OxocarbonBase05)
 
-	return "  " + keyStyle.Render(sc.Key) + descStyle.Render(sc.Description)
+	if !m.supported(sc) {
+		keyStyle = keyStyle.Foreground(styles.OxocarbonBase03).Bold(false)
+		descStyle = descStyle.Foreground(styles.OxocarbonBase03)
+		return "  " + keyStyle.Render(sc.Key) + descStyle.Render(i18n.T("%s (not on %s)", i18n.T(sc.Description), m.providerName))
+	}
+	return "  " + keyStyle.Render(sc.Key) + descStyle.Render(i18n.T(sc.Description))
 }
 
 // getContextName returns a human-readable name for the current context
@@ -5,6 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/tuitest"
 )
 
@@ -24,3 +27,17 @@ func TestHelpView(t *testing.T) {
 
 	tuitest.AssertSnapshot(t, view)
 }
+
+func TestUnsupportedShortcutsGreyedOut(t *testing.T) {
+	model := New()
+	model.SetProviderName("reader")
+	pick := Shortcut{Key: "S", Description: "Pick source and play", Requires: needsSourcePick}
+
+	assert.True(t, model.supported(pick), "everything is shown before the provider is known")
+	assert.NotContains(t, model.renderShortcutLine(pick), "not on")
+
+	model.SetCapabilities(providers.Capabilities{Streams: true})
+	assert.False(t, model.supported(pick))
+	assert.Contains(t, model.renderShortcutLine(pick), "Pick source and play (not on reader)")
+	assert.True(t, model.supported(Shortcut{Key: "q", Description: "Quit application"}))
+}
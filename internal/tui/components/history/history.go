@@ -16,6 +16,7 @@ import (
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
 )
 
 // Model represents the history TUI component
@@ -333,8 +334,7 @@ func (m *Model) DeleteHistoryItem(db *gorm.DB, id uint) error {
 		return fmt.Errorf("database is nil")
 	}
 
-	err := dbToUse.Delete(&database.History{}, id).Error
-	if err != nil {
+	if _, err := database.TrashHistoryEntries(dbToUse, fmt.Sprintf("history entry #%d", id), id); err != nil {
 		return err
 	}
 	// Reload history
@@ -352,8 +352,7 @@ func (m *Model) DeleteAllHistory(db *gorm.DB) error {
 		return fmt.Errorf("database is nil")
 	}
 
-	err := dbToUse.Where("1 = 1").Delete(&database.History{}).Error
-	if err != nil {
+	if _, err := database.TrashAllHistory(dbToUse); err != nil {
 		return err
 	}
 	m.SetHistory([]database.History{})
@@ -430,7 +429,7 @@ func (m Model) View() string {
 		content.WriteString(m.renderHistoryItem(item, i == m.currentIndex) + "\n\n")
 	}
 
-	helpText := "  ↑/↓ nav • enter play • / search • 1-4 filter • r/t/p sort • x del • q back"
+	helpText := "  ↑/↓ nav • enter play • / search • 1-4 filter • r/t/p sort • f fav • x del • q back"
 	if m.fuzzySearch.IsActive() {
 		if m.fuzzySearch.IsLocked() {
 			helpText = "  ↑/↓ nav • enter play • / edit • q back"
@@ -536,7 +535,7 @@ func (m Model) renderHistoryItem(item database.History, selected bool) string {
 			title = fmt.Sprintf("%s - %s %d", item.MediaTitle, prefix, item.Episode)
 		}
 	}
-	lines = append(lines, titleStyle.Render(title))
+	lines = append(lines, titleStyle.Render(utils.Truncate(title, utils.ItemWidth(m.width))))
 
 	// Line 2: Progress, Provider, Date
 	var metaParts []string
@@ -744,17 +743,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case DeleteHistoryItemMsg:
-		if m.db != nil {
-			m.db.Delete(&database.History{}, msg.ID)
+		if m.db == nil {
+			return m, nil
 		}
-		return m, m.Refresh()
+		return m, tea.Sequence(trashHistoryCmd(func() (*database.TrashItem, error) {
+			return database.TrashHistoryEntries(m.db, msg.Label, msg.ID)
+		}), m.Refresh())
 
 	case DeleteAllHistoryMsg:
-		if m.db != nil {
-			m.db.Where("1 = 1").Delete(&database.History{})
+		if m.db == nil {
+			return m, nil
 		}
 		m.history = []database.History{}
-		return m, nil
+		return m, trashHistoryCmd(func() (*database.TrashItem, error) {
+			return database.TrashAllHistory(m.db)
+		})
 
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
@@ -809,14 +812,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "x":
 			selected := m.GetSelectedHistory()
 			if selected != nil {
+				label := selected.MediaTitle
+				if selected.Episode > 0 {
+					label = fmt.Sprintf("%s - Episode %d", selected.MediaTitle, selected.Episode)
+				}
 				return m, func() tea.Msg {
-					return DeleteHistoryItemMsg{ID: selected.ID}
+					return DeleteHistoryItemMsg{ID: selected.ID, Label: label}
 				}
 			}
 		case "X":
 			return m, func() tea.Msg {
 				return DeleteAllHistoryMsg{}
 			}
+		case "f":
+			selected := m.GetSelectedHistory()
+			if selected != nil {
+				return m, func() tea.Msg {
+					return common.ToggleFavoriteMsg{
+						MediaID:      selected.MediaID,
+						Title:        selected.MediaTitle,
+						Type:         selected.MediaType,
+						ProviderName: selected.ProviderName,
+					}
+				}
+			}
 		case "w":
 			selected := m.GetSelectedHistory()
 			if selected != nil {
@@ -838,7 +857,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // Messages for history operations
 type DeleteHistoryItemMsg struct {
-	ID uint
+	ID    uint
+	Label string
 }
 
 type DeleteAllHistoryMsg struct{}
+
+// trashHistoryCmd runs a history trash operation and reports it for undo
+func trashHistoryCmd(trash func() (*database.TrashItem, error)) tea.Cmd {
+	return func() tea.Msg {
+		item, err := trash()
+		if err != nil {
+			return common.TrashedMsg{Err: err}
+		}
+		if item == nil {
+			return nil
+		}
+		return common.TrashedMsg{ID: item.ID, Label: item.Label}
+	}
+}
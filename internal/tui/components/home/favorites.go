@@ -0,0 +1,198 @@
+package home
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// FavoriteItem is a starred title and where to pick it up again
+type FavoriteItem struct {
+	MediaID      string
+	MediaTitle   string
+	MediaType    string
+	ProviderName string
+	Resume       *RecentItem // Latest unfinished episode, nil to open the episode list
+}
+
+// FavoritesLoadedMsg is sent when favorites are loaded
+type FavoritesLoadedMsg struct {
+	MediaType providers.MediaType
+	Items     []FavoriteItem
+	Error     error
+}
+
+// FetchFavorites returns the favorites of a provider for a media type, with
+// the unfinished episode to resume for each
+func FetchFavorites(db *gorm.DB, mediaType string, providerName string) ([]FavoriteItem, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is nil")
+	}
+
+	var types []string
+	switch providers.MediaType(mediaType) {
+	case providers.MediaTypeAnime:
+		types = []string{"anime"}
+	case providers.MediaTypeManga:
+		types = []string{"manga"}
+	case providers.MediaTypeMovieTV:
+		types = []string{"movie", "tv"}
+	}
+	favs, err := database.ListFavorites(db, providerName, types...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch favorites: %w", err)
+	}
+
+	items := make([]FavoriteItem, 0, len(favs))
+	for _, fav := range favs {
+		item := FavoriteItem{
+			MediaID:      fav.MediaID,
+			MediaTitle:   fav.MediaTitle,
+			MediaType:    fav.MediaType,
+			ProviderName: fav.ProviderName,
+		}
+		if h, err := database.LatestUnfinished(db, fav.ProviderName, fav.MediaID); err == nil && h != nil {
+			item.Resume = &RecentItem{
+				ID:              h.ID,
+				MediaID:         h.MediaID,
+				MediaTitle:      h.MediaTitle,
+				MediaType:       h.MediaType,
+				Episode:         h.Episode,
+				Season:          h.Season,
+				Page:            h.Page,
+				TotalPages:      h.TotalPages,
+				ProgressPercent: h.ProgressPercent,
+				ProgressSeconds: h.ProgressSeconds,
+				TotalSeconds:    h.TotalSeconds,
+				WatchedAt:       h.WatchedAt,
+				ProviderName:    h.ProviderName,
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// loadFavorites loads the favorites of the current provider
+func (m *Model) loadFavorites() tea.Cmd {
+	if m.db == nil || m.providerName == "" {
+		return nil
+	}
+	db, mediaType, providerName := m.db, m.CurrentMediaType, m.providerName
+	return func() tea.Msg {
+		items, err := FetchFavorites(db, string(mediaType), providerName)
+		return FavoritesLoadedMsg{MediaType: mediaType, Items: items, Error: err}
+	}
+}
+
+// ReloadFavorites reloads the favorites section
+func (m *Model) ReloadFavorites() tea.Cmd {
+	return m.loadFavorites()
+}
+
+// favoritesDisplayCount returns how many favorites should be displayed
+func (m Model) favoritesDisplayCount() int {
+	count := len(m.favorites)
+	if count > 3 {
+		count = 3
+	}
+	if m.height > 0 && m.height < 30 && count > 1 {
+		count = 1
+	}
+	return count
+}
+
+// selectableCount returns the number of recent items and favorites on screen
+func (m Model) selectableCount() int {
+	return m.displayCount + m.favoritesDisplayCount()
+}
+
+// selectedRecent returns the selected recent item
+func (m Model) selectedRecent() (RecentItem, bool) {
+	if !m.focusOnRecent || m.selectedIndex >= m.displayCount || m.selectedIndex >= len(m.recentItems) {
+		return RecentItem{}, false
+	}
+	return m.recentItems[m.selectedIndex], true
+}
+
+// selectedFavorite returns the selected favorite
+func (m Model) selectedFavorite() (FavoriteItem, bool) {
+	i := m.selectedIndex - m.displayCount
+	if !m.focusOnRecent || i < 0 || i >= m.favoritesDisplayCount() {
+		return FavoriteItem{}, false
+	}
+	return m.favorites[i], true
+}
+
+// openFavorite resumes the unfinished episode of a favorite, or opens its
+// episode list
+func openFavorite(item FavoriteItem) tea.Cmd {
+	if r := item.Resume; r != nil {
+		return func() tea.Msg {
+			return common.ResumePlaybackMsg{
+				MediaID:         r.MediaID,
+				MediaTitle:      r.MediaTitle,
+				MediaType:       r.MediaType,
+				Episode:         r.Episode,
+				Season:          r.Season,
+				ProgressSeconds: r.ProgressSeconds,
+				ProviderName:    r.ProviderName,
+			}
+		}
+	}
+	return func() tea.Msg {
+		return common.MediaSelectedMsg{
+			MediaID: item.MediaID,
+			Title:   item.MediaTitle,
+			Type:    item.MediaType,
+		}
+	}
+}
+
+// toggleFavorite asks the app to star or unstar a title
+func toggleFavorite(mediaID, title, mediaType, providerName string) tea.Cmd {
+	return func() tea.Msg {
+		return common.ToggleFavoriteMsg{
+			MediaID:      mediaID,
+			Title:        title,
+			Type:         mediaType,
+			ProviderName: providerName,
+		}
+	}
+}
+
+// renderFavoriteItem renders a single favorite
+func (m Model) renderFavoriteItem(item FavoriteItem, selected bool) string {
+	info := i18n.T("Not started")
+	if r := item.Resume; r != nil {
+		switch {
+		case r.MediaType == "manga":
+			info = i18n.T("Resume chapter %d • %s", r.Episode, FormatTimeAgo(r.WatchedAt))
+		case r.Episode > 0:
+			info = i18n.T("Resume episode %d • %s", r.Episode, FormatTimeAgo(r.WatchedAt))
+		default:
+			info = i18n.T("Resume at %.0f%% • %s", r.ProgressPercent, FormatTimeAgo(r.WatchedAt))
+		}
+	}
+
+	itemStyle := styles.AniListItemStyle
+	titleStyle := styles.AniListTitleStyle
+	if selected {
+		itemStyle = styles.AniListItemSelectedStyle
+		titleStyle = titleStyle.Foreground(styles.OxocarbonPurple)
+	}
+
+	star := lipgloss.NewStyle().Foreground(styles.OxocarbonPink).Render("★ ")
+	content := star + titleStyle.Render(utils.Truncate(item.MediaTitle, utils.ItemWidth(m.width)-2)) + "\n" +
+		styles.AniListMetadataStyle.Render(utils.Truncate(info, utils.ItemWidth(m.width)))
+	return itemStyle.Render(content)
+}
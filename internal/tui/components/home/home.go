@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"gorm.io/gorm"
 
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
 )
 
 type Model struct {
@@ -24,12 +27,21 @@ type Model struct {
 	focusOnRecent    bool // Whether focus is on recent items section
 	recentLoaded     bool // Whether recent items have been loaded
 	displayCount     int  // Number of items currently displayed
+	favorites        []FavoriteItem
+	modes            map[providers.MediaType]modeState // Lists and cursor of the other media types
+	keepSelection    bool                              // Keep the restored cursor on the next reload
+	pendingSyncs     int64
+	incognito        bool
+	coWatch          string // Name of the co-watch profile, empty when solo
+	urlInput         textinput.Model
+	urlActive        bool // Whether the "play a URL" prompt is open
 }
 
 // RecentHistoryLoadedMsg is sent when recent history is loaded
 type RecentHistoryLoadedMsg struct {
-	Items []RecentItem
-	Error error
+	MediaType providers.MediaType
+	Items     []RecentItem
+	Error     error
 }
 
 func New(db *gorm.DB) Model {
@@ -40,17 +52,71 @@ func New(db *gorm.DB) Model {
 		selectedIndex:    0,
 		focusOnRecent:    false,
 		recentLoaded:     false,
+		urlInput:         newURLInput(),
 	}
 }
 
+func newURLInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "https://example.com/video.m3u8"
+	ti.Prompt = "URL: "
+	ti.CharLimit = 2048
+	ti.Width = 50
+	return ti
+}
+
+// IsInputActive returns true while the "play a URL" prompt takes input
+func (m *Model) IsInputActive() bool {
+	return m.urlActive
+}
+
+// handleURLKeys handles the "play a URL" prompt
+func (m *Model) handleURLKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.urlActive = false
+		m.urlInput.Blur()
+		return m, nil
+	case "enter":
+		url := strings.TrimSpace(m.urlInput.Value())
+		if url == "" {
+			return m, nil
+		}
+		m.urlActive = false
+		m.urlInput.Blur()
+		return m, func() tea.Msg {
+			return common.PlayURLMsg{URL: url}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.urlInput, cmd = m.urlInput.Update(msg)
+	return m, cmd
+}
+
 // SetProvider updates the current provider name for filtering history
 func (m *Model) SetProvider(providerName string) {
 	m.providerName = providerName
 }
 
+// SetIncognito shows or hides the incognito badge
+func (m *Model) SetIncognito(on bool) {
+	m.incognito = on
+}
+
+// SetCoWatch shows the co-watch profile badge, or hides it when name is empty
+func (m *Model) SetCoWatch(name string) {
+	m.coWatch = name
+}
+
+// SetPendingSyncs sets the number of tracker updates waiting for a retry
+func (m *Model) SetPendingSyncs(n int64) {
+	m.pendingSyncs = n
+}
+
 func (m *Model) Init() tea.Cmd {
-	// Load recent history on init
-	return m.loadRecent()
+	// Load recent history and favorites on init
+	return tea.Batch(m.loadRecent(), m.loadFavorites())
 }
 
 // loadRecent loads recent history from the database
@@ -59,10 +125,11 @@ func (m *Model) loadRecent() tea.Cmd {
 		return nil
 	}
 
+	current := m.CurrentMediaType
 	return func() tea.Msg {
 		// Fetch recent history for current media type
 		var mediaType string
-		switch m.CurrentMediaType {
+		switch current {
 		case providers.MediaTypeAnime:
 			mediaType = "anime"
 		case providers.MediaTypeManga:
@@ -75,8 +142,9 @@ func (m *Model) loadRecent() tea.Cmd {
 		// If providerName is empty, it means we haven't initialized properly yet
 		if m.providerName == "" {
 			return RecentHistoryLoadedMsg{
-				Items: []RecentItem{},
-				Error: nil,
+				MediaType: current,
+				Items:     []RecentItem{},
+				Error:     nil,
 			}
 		}
 
@@ -85,8 +153,9 @@ func (m *Model) loadRecent() tea.Cmd {
 
 		items, err := FetchRecentHistory(m.db, mediaType, m.providerName, 5)
 		return RecentHistoryLoadedMsg{
-			Items: items,
-			Error: err,
+			MediaType: current,
+			Items:     items,
+			Error:     err,
 		}
 	}
 }
@@ -95,9 +164,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case common.RefreshHistoryMsg:
 		// Reload recent history when explicitly requested
-		return m, m.loadRecent()
+		return m, tea.Batch(m.loadRecent(), m.loadFavorites())
 
 	case RecentHistoryLoadedMsg:
+		// Drop lists loaded for a media type that is no longer shown
+		if msg.MediaType != m.CurrentMediaType {
+			return m, nil
+		}
 		m.recentLoaded = true
 		if msg.Error == nil {
 			m.recentItems = msg.Items
@@ -106,7 +179,28 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// If we have recent items, focus on them by default
 			if len(m.recentItems) > 0 {
 				m.focusOnRecent = true
-				m.selectedIndex = 0
+				if !m.keepSelection {
+					m.selectedIndex = 0
+				}
+			}
+			if n := m.selectableCount(); m.selectedIndex >= n && n > 0 {
+				m.selectedIndex = n - 1
+			}
+		}
+		m.keepSelection = false
+		return m, nil
+
+	case FavoritesLoadedMsg:
+		if msg.MediaType != m.CurrentMediaType {
+			return m, nil
+		}
+		if msg.Error == nil {
+			m.favorites = msg.Items
+			if len(m.favorites) > 0 {
+				m.focusOnRecent = true
+			}
+			if n := m.selectableCount(); m.selectedIndex >= n && n > 0 {
+				m.selectedIndex = n - 1
 			}
 		}
 		return m, nil
@@ -114,17 +208,24 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if msg.Width > 0 {
+			m.urlInput.Width = min(50, max(msg.Width-10, 10))
+		}
 		// Recalculate display count based on new dimensions
 		m.displayCount = m.calculateDisplayCount()
 		// Ensure selectedIndex is within bounds
-		if m.displayCount > 0 && m.selectedIndex >= m.displayCount {
-			m.selectedIndex = m.displayCount - 1
+		if n := m.selectableCount(); n > 0 && m.selectedIndex >= n {
+			m.selectedIndex = n - 1
 		}
 		return m, nil
 
 	case tea.KeyMsg:
-		// Navigation keys for recent items
-		if m.focusOnRecent && len(m.recentItems) > 0 && m.displayCount > 0 {
+		if m.urlActive {
+			return m.handleURLKeys(msg)
+		}
+
+		// Navigation keys for recent items and favorites
+		if m.focusOnRecent && m.selectableCount() > 0 {
 			switch msg.String() {
 			case "up", "k":
 				// Cycle within visible items
@@ -132,12 +233,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedIndex--
 				} else {
 					// Wrap around to last visible item
-					m.selectedIndex = m.displayCount - 1
+					m.selectedIndex = m.selectableCount() - 1
 				}
 				return m, nil
 			case "down", "j":
 				// Cycle within visible items
-				if m.selectedIndex < m.displayCount-1 {
+				if m.selectedIndex < m.selectableCount()-1 {
 					m.selectedIndex++
 				} else {
 					// Wrap around to first visible item
@@ -145,9 +246,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "enter":
+				// Resume the selected favorite
+				if fav, ok := m.selectedFavorite(); ok {
+					return m, openFavorite(fav)
+				}
 				// Resume playback of selected recent item
-				if m.selectedIndex < len(m.recentItems) {
-					item := m.recentItems[m.selectedIndex]
+				if item, ok := m.selectedRecent(); ok {
 					return m, func() tea.Msg {
 						return common.ResumePlaybackMsg{
 							MediaID:         item.MediaID,
@@ -164,10 +268,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Remove from recent (mark as completed in database)
 				// TODO: Remove from recent - not implemented in beta
 				return m, nil
+			case "f":
+				// Star or unstar the selected item
+				if fav, ok := m.selectedFavorite(); ok {
+					return m, toggleFavorite(fav.MediaID, fav.MediaTitle, fav.MediaType, fav.ProviderName)
+				}
+				if item, ok := m.selectedRecent(); ok {
+					return m, toggleFavorite(item.MediaID, item.MediaTitle, item.MediaType, item.ProviderName)
+				}
+				return m, nil
 			case "w":
 				// Share selected recent item via WatchParty
-				if m.selectedIndex < len(m.recentItems) {
-					item := m.recentItems[m.selectedIndex]
+				if item, ok := m.selectedRecent(); ok {
 					return m, func() tea.Msg {
 						return common.ShareRecentViaWatchPartyMsg{
 							MediaID:      item.MediaID,
@@ -181,8 +293,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "m":
 				// Show manga info for selected recent item
-				if m.selectedIndex < len(m.recentItems) {
-					item := m.recentItems[m.selectedIndex]
+				if item, ok := m.selectedRecent(); ok {
 					// Only for anime
 					if item.MediaType == "anime" {
 						return m, func() tea.Msg {
@@ -212,6 +323,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg {
 				return common.GoToProviderStatusMsg{}
 			}
+		case "I":
+			// Toggle incognito mode (capital I)
+			return m, func() tea.Msg {
+				return common.ToggleIncognitoMsg{}
+			}
+		case "W":
+			// Cycle co-watch profiles (capital W)
+			return m, func() tea.Msg {
+				return common.CycleCoWatchMsg{}
+			}
 		case "tab":
 			// Toggle provider and reload recent history
 			return m, func() tea.Msg {
@@ -230,6 +351,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg {
 				return common.GoToDownloadsMsg{}
 			}
+		case "o":
+			// Play a pasted stream URL
+			m.urlActive = true
+			m.urlInput.SetValue("")
+			return m, m.urlInput.Focus()
 		}
 	}
 	return m, nil
@@ -237,18 +363,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) View() string {
 	var output strings.Builder
+	compact := utils.Compact(m.width)
+	short := utils.Short(m.height)
 
 	// Header with mode and provider - ALWAYS render this
 	header := styles.TitleStyle.Render("  greg  ")
 
-	mode := "ANIME"
+	mode := i18n.T("ANIME")
 	modeColor := styles.OxocarbonPurple
 	switch m.CurrentMediaType {
 	case providers.MediaTypeMovieTV:
-		mode = "MOVIES/TV"
+		mode = i18n.T("MOVIES/TV")
 		modeColor = styles.OxocarbonBlue
 	case providers.MediaTypeManga:
-		mode = "MANGA"
+		mode = i18n.T("MANGA")
 		modeColor = styles.OxocarbonPink
 	}
 
@@ -261,7 +389,7 @@ func (m *Model) View() string {
 
 	providerName := m.providerName
 	if providerName == "" {
-		providerName = "loading..."
+		providerName = i18n.T("loading...")
 	}
 	providerBadge := lipgloss.NewStyle().
 		Foreground(styles.OxocarbonBase05).
@@ -269,16 +397,64 @@ func (m *Model) View() string {
 		Padding(0, 1).
 		Render(providerName)
 
-	// Always write header
+	// Always write header; the compact one drops the app name and shortens
+	// the badges
 	headerLine := lipgloss.JoinHorizontal(lipgloss.Center, header, "  ", modeBadge, " ", providerBadge)
-	output.WriteString(headerLine)
+	if compact {
+		headerLine = lipgloss.JoinHorizontal(lipgloss.Center, modeBadge, " ", providerBadge)
+	}
+	if m.pendingSyncs > 0 {
+		syncText := i18n.T("⟳ %d pending sync", m.pendingSyncs)
+		if compact {
+			syncText = fmt.Sprintf("⟳ %d", m.pendingSyncs)
+		}
+		syncBadge := lipgloss.NewStyle().
+			Foreground(styles.OxocarbonBase00).
+			Background(styles.OxocarbonTeal).
+			Padding(0, 1).
+			Render(syncText)
+		headerLine = lipgloss.JoinHorizontal(lipgloss.Center, headerLine, " ", syncBadge)
+	}
+	if m.incognito {
+		incognitoText := i18n.T("INCOGNITO")
+		if compact {
+			incognitoText = i18n.T("INCOG")
+		}
+		incognitoBadge := lipgloss.NewStyle().
+			Foreground(styles.OxocarbonBase00).
+			Background(styles.OxocarbonMagenta).
+			Padding(0, 1).
+			Bold(true).
+			Render(incognitoText)
+		headerLine = lipgloss.JoinHorizontal(lipgloss.Center, headerLine, " ", incognitoBadge)
+	}
+	if m.coWatch != "" {
+		coWatchText := i18n.T("WITH %s", strings.ToUpper(m.coWatch))
+		if compact {
+			coWatchText = "+" + strings.ToUpper(m.coWatch)
+		}
+		coWatchBadge := lipgloss.NewStyle().
+			Foreground(styles.OxocarbonBase00).
+			Background(styles.OxocarbonGreen).
+			Padding(0, 1).
+			Bold(true).
+			Render(coWatchText)
+		headerLine = lipgloss.JoinHorizontal(lipgloss.Center, headerLine, " ", coWatchBadge)
+	}
+	output.WriteString(utils.Truncate(headerLine, m.width))
 	output.WriteString("\n\n")
 
+	// Short terminals separate sections with a blank line instead of a rule
+	sectionEnd := "\n" + styles.HomeSeparatorStyle.Render(strings.Repeat("─", m.calculateSeparatorWidth())) + "\n\n"
+	if short {
+		sectionEnd = "\n\n"
+	}
+
 	// Continue Watching section
 	if len(m.recentItems) > 0 {
-		headerText := "Continue Watching"
+		headerText := i18n.T("Continue Watching")
 		if m.CurrentMediaType == providers.MediaTypeManga {
-			headerText = "Continue Reading"
+			headerText = i18n.T("Continue Reading")
 		}
 		continueHeader := styles.SubtitleStyle.Render(headerText)
 		output.WriteString(continueHeader)
@@ -300,44 +476,72 @@ func (m *Model) View() string {
 		}
 
 		// Add separator after recent items
-		sepWidth := m.calculateSeparatorWidth()
-		separator := strings.Repeat("─", sepWidth)
+		output.WriteString(sectionEnd)
+	}
+
+	// Favorites section
+	if count := m.favoritesDisplayCount(); count > 0 {
+		output.WriteString(styles.SubtitleStyle.Render(i18n.T("Favorites")))
+		output.WriteString("\n")
+		for i := 0; i < count; i++ {
+			selected := m.focusOnRecent && m.selectedIndex == m.displayCount+i
+			output.WriteString(m.renderFavoriteItem(m.favorites[i], selected))
+			if i < count-1 {
+				output.WriteString("\n")
+			}
+		}
+
+		output.WriteString(sectionEnd)
+	}
+
+	// Short terminals get the actions as a grid of keys, without the modes
+	// section; tab is in the footer
+	if short {
+		output.WriteString(m.renderActionGrid())
 		output.WriteString("\n")
-		output.WriteString(styles.HomeSeparatorStyle.Render(separator))
-		output.WriteString("\n\n")
+		output.WriteString(styles.AniListHelpStyle.Render(m.footerHelp(true)))
+		return output.String()
 	}
 
 	// Quick Actions section
-	output.WriteString(styles.SubtitleStyle.Render("Quick Actions"))
+	output.WriteString(styles.SubtitleStyle.Render(i18n.T("Quick Actions")))
 	output.WriteString("\n")
 
 	// Core navigation actions with descriptions
-	searchDesc := "Find and browse content"
+	searchDesc := i18n.T("Find and browse content")
 	switch m.CurrentMediaType {
 	case providers.MediaTypeAnime:
-		searchDesc = "Search for anime series"
+		searchDesc = i18n.T("Search for anime series")
 	case providers.MediaTypeManga:
-		searchDesc = "Search for manga titles"
+		searchDesc = i18n.T("Search for manga titles")
 	}
 
-	output.WriteString(m.renderAction("s", "Search", searchDesc))
+	output.WriteString(m.renderAction("s", i18n.T("Search"), searchDesc))
 	output.WriteString("\n")
 
-	output.WriteString(m.renderAction("h", "History", "View your watch history"))
+	output.WriteString(m.renderAction("h", i18n.T("History"), i18n.T("View your watch history")))
 	output.WriteString("\n")
 
 	// AniList for anime/manga
 	if m.CurrentMediaType != providers.MediaTypeMovieTV {
-		output.WriteString(m.renderAction("l", "AniList", "Sync and manage your library"))
+		output.WriteString(m.renderAction("l", "AniList", i18n.T("Sync and manage your library")))
 		output.WriteString("\n")
 	}
 
-	output.WriteString(m.renderAction("p", "Switch Providers", "Browse available providers"))
+	output.WriteString(m.renderAction("p", i18n.T("Switch Providers"), i18n.T("Browse available providers")))
 	output.WriteString("\n")
 
-	output.WriteString(m.renderAction("d", "Downloads", "Manage your downloads"))
+	output.WriteString(m.renderAction("d", i18n.T("Downloads"), i18n.T("Manage your downloads")))
 	output.WriteString("\n")
 
+	output.WriteString(m.renderAction("o", i18n.T("Play URL"), i18n.T("Play an m3u8 or video link directly")))
+	output.WriteString("\n")
+	if m.urlActive {
+		output.WriteString("  " + m.urlInput.View() + "\n")
+		output.WriteString(styles.AniListHelpStyle.Render("  " + i18n.T("enter play  •  esc cancel")))
+		output.WriteString("\n")
+	}
+
 	// Separator between sections
 	sepWidth := m.calculateSeparatorWidth()
 	separator := strings.Repeat("─", sepWidth)
@@ -346,34 +550,49 @@ func (m *Model) View() string {
 	output.WriteString("\n")
 
 	// Mode switching section
-	output.WriteString(styles.SubtitleStyle.Render("Modes"))
+	output.WriteString(styles.SubtitleStyle.Render(i18n.T("Modes")))
 	output.WriteString("\n")
 
-	output.WriteString(m.renderAction("tab", "Switch Mode", "Cycle: Movies/TV, Anime, Manga"))
+	output.WriteString(m.renderAction("tab", i18n.T("Switch Mode"), i18n.T("Cycle: Movies/TV, Anime, Manga")))
 	output.WriteString("\n")
 
-	output.WriteString(m.renderAction("1 / 2 / 3", "Quick Switch", "Jump to specific mode"))
+	output.WriteString(m.renderAction("1 / 2 / 3", i18n.T("Quick Switch"), i18n.T("Jump to specific mode")))
 	output.WriteString("\n")
 
 	// Footer
 	output.WriteString("\n")
 	output.WriteString(styles.HomeSeparatorStyle.Render(separator))
 	output.WriteString("\n")
+	output.WriteString(styles.AniListHelpStyle.Render(m.footerHelp(false)))
 
-	if len(m.recentItems) > 0 {
+	return output.String()
+}
+
+// footerHelp returns the key hints of the footer that fit the width.
+// withModes adds the mode switch, for layouts without the modes section.
+func (m Model) footerHelp(withModes bool) string {
+	var full, short string
+	if m.selectableCount() > 0 {
 		// Show different hints based on how many items are displayed vs total
-		if m.displayCount > 1 {
-			output.WriteString(styles.AniListHelpStyle.Render("↑/↓ navigate  •  enter resume  •  h more history  •  ? help  •  q quit"))
+		if m.selectableCount() > 1 {
+			full = i18n.T("↑/↓ navigate  •  enter resume  •  h more history  •  ? help  •  q quit")
+			short = i18n.T("↑/↓ • enter resume • ? help • q quit")
 		} else if len(m.recentItems) > 1 {
-			output.WriteString(styles.AniListHelpStyle.Render("enter resume  •  h view all history  •  ? help  •  q quit"))
+			full = i18n.T("enter resume  •  h view all history  •  ? help  •  q quit")
+			short = i18n.T("enter resume • ? help • q quit")
 		} else {
-			output.WriteString(styles.AniListHelpStyle.Render("enter resume  •  ? help  •  q quit"))
+			full = i18n.T("enter resume  •  ? help  •  q quit")
+			short = i18n.T("enter resume • ? help • q quit")
 		}
 	} else {
-		output.WriteString(styles.AniListHelpStyle.Render("? help  •  q quit"))
+		full = i18n.T("? help  •  q quit")
+		short = full
 	}
-
-	return output.String()
+	if withModes {
+		full = i18n.T("tab mode") + "  •  " + full
+		short = i18n.T("tab mode") + " • " + short
+	}
+	return utils.FitHelp(m.width, full, short)
 }
 
 // calculateSeparatorWidth returns the appropriate separator width
@@ -406,6 +625,42 @@ func (m Model) calculateDisplayCount() int {
 	return displayCount
 }
 
+// renderActionGrid renders the quick actions as keys and titles, two to a
+// row, for short terminals
+func (m Model) renderActionGrid() string {
+	type action struct{ key, title string }
+	actions := []action{{"s", i18n.T("Search")}, {"h", i18n.T("History")}}
+	if m.CurrentMediaType != providers.MediaTypeMovieTV {
+		actions = append(actions, action{"l", "AniList"})
+	}
+	actions = append(actions,
+		action{"p", i18n.T("Providers")},
+		action{"d", i18n.T("Downloads")},
+		action{"o", i18n.T("Play URL")},
+	)
+
+	keyStyle := lipgloss.NewStyle().Foreground(styles.OxocarbonCyan).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.OxocarbonBase05).Bold(true)
+	cellWidth := 24
+	if m.width > 0 {
+		cellWidth = min(cellWidth, m.width/2)
+	}
+
+	var rows []string
+	for i := 0; i < len(actions); i += 2 {
+		var row string
+		for _, a := range actions[i:min(i+2, len(actions))] {
+			cell := keyStyle.Render("["+a.key+"]") + " " + titleStyle.Render(a.title)
+			row += utils.PadRight(cell, cellWidth)
+		}
+		rows = append(rows, strings.TrimRight(row, " "))
+	}
+	if m.urlActive {
+		rows = append(rows, "  "+m.urlInput.View())
+	}
+	return strings.Join(rows, "\n")
+}
+
 // renderAction renders a menu action with key, title, and description in a clean format
 func (m Model) renderAction(key, title, description string) string {
 	// Fixed width for key column (includes brackets)
@@ -427,6 +682,12 @@ func (m Model) renderAction(key, title, description string) string {
 	descStyle := lipgloss.NewStyle().
 		Foreground(styles.OxocarbonBase03)
 
+	// Compact rows leave the description out
+	if utils.Compact(m.width) {
+		keyStyle = keyStyle.Width(12)
+		return keyStyle.Render(keyText) + titleStyle.Render(title)
+	}
+
 	keyPart := keyStyle.Render(keyText)
 	titlePart := titleStyle.Render(title)
 	descPart := descStyle.Render(description)
@@ -439,23 +700,25 @@ func (m Model) renderRecentItem(item RecentItem, selected bool) string {
 	// Title with episode info
 	title := FormatEpisodeTitle(item)
 
-	// Progress info
+	// Progress info, collapsed to the percentage and when in compact rows
 	var progressInfo string
-	if item.MediaType == "manga" {
+	if utils.Compact(m.width) {
+		progressInfo = i18n.T("%.0f%% • %s", item.ProgressPercent, FormatTimeAgo(item.WatchedAt))
+	} else if item.MediaType == "manga" {
 		if item.TotalPages > 0 {
-			progressInfo = fmt.Sprintf("Progress: %.0f%% • Page %d/%d • %s",
+			progressInfo = i18n.T("Progress: %.0f%% • Page %d/%d • %s",
 				item.ProgressPercent,
 				item.Page,
 				item.TotalPages,
 				FormatTimeAgo(item.WatchedAt))
 		} else {
-			progressInfo = fmt.Sprintf("Progress: %.0f%% • Page %d • %s",
+			progressInfo = i18n.T("Progress: %.0f%% • Page %d • %s",
 				item.ProgressPercent,
 				item.Page,
 				FormatTimeAgo(item.WatchedAt))
 		}
 	} else {
-		progressInfo = fmt.Sprintf("Progress: %.0f%% • %s / %s • %s",
+		progressInfo = i18n.T("Progress: %.0f%% • %s / %s • %s",
 			item.ProgressPercent,
 			FormatDuration(item.ProgressSeconds),
 			FormatDuration(item.TotalSeconds),
@@ -477,8 +740,8 @@ func (m Model) renderRecentItem(item RecentItem, selected bool) string {
 	}
 
 	// Render
-	content := titleStyle.Render(title) + "\n" +
-		styles.AniListMetadataStyle.Render(progressInfo)
+	content := titleStyle.Render(utils.Truncate(title, utils.ItemWidth(m.width))) + "\n" +
+		styles.AniListMetadataStyle.Render(utils.Truncate(progressInfo, utils.ItemWidth(m.width)))
 
 	return itemStyle.Render(content)
 }
@@ -0,0 +1,52 @@
+package home
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/tuitest"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+var sizes = []struct{ width, height int }{{50, 15}, {60, 20}, {69, 24}, {80, 24}, {120, 40}}
+
+func TestViewFitsTerminal(t *testing.T) {
+	for _, mediaType := range []providers.MediaType{providers.MediaTypeAnime, providers.MediaTypeMovieTV} {
+		for _, size := range sizes {
+			t.Run(fmt.Sprintf("%s/%dx%d", mediaType, size.width, size.height), func(t *testing.T) {
+				m := New(nil)
+				m.CurrentMediaType = mediaType
+				m.SetProvider("allanime")
+				m.SetPendingSyncs(3)
+				m.SetIncognito(true)
+				m.SetCoWatch("alice")
+				m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+				m.Update(RecentHistoryLoadedMsg{MediaType: mediaType, Items: []RecentItem{{
+					MediaTitle:      "Sousou no Frieren: The Journey's End and Everything After",
+					MediaType:       "anime",
+					Episode:         7,
+					ProgressPercent: 42,
+					ProgressSeconds: 610,
+					TotalSeconds:    1440,
+					WatchedAt:       time.Now().Add(-3 * time.Hour),
+				}}})
+				m.Update(FavoritesLoadedMsg{MediaType: mediaType, Items: []FavoriteItem{{
+					MediaTitle: "Bocchi the Rock!",
+					MediaType:  "anime",
+				}}})
+
+				// The full layout is cut to the terminal by the app, the short
+				// one must fit on its own
+				height := 0
+				if utils.Short(size.height) {
+					height = size.height
+				}
+				tuitest.AssertFits(t, m.View(), size.width, height)
+			})
+		}
+	}
+}
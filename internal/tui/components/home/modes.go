@@ -0,0 +1,41 @@
+package home
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// modeState is what the home screen keeps of a media type while another one
+// is shown
+type modeState struct {
+	recentItems   []RecentItem
+	favorites     []FavoriteItem
+	selectedIndex int
+	focusOnRecent bool
+}
+
+// SwitchMediaType shows another media type, restoring its lists and cursor
+// from the last visit while they reload
+func (m *Model) SwitchMediaType(mediaType providers.MediaType) tea.Cmd {
+	if m.modes == nil {
+		m.modes = make(map[providers.MediaType]modeState)
+	}
+	m.modes[m.CurrentMediaType] = modeState{
+		recentItems:   m.recentItems,
+		favorites:     m.favorites,
+		selectedIndex: m.selectedIndex,
+		focusOnRecent: m.focusOnRecent,
+	}
+
+	saved, ok := m.modes[mediaType]
+	m.CurrentMediaType = mediaType
+	m.recentItems = saved.recentItems
+	m.favorites = saved.favorites
+	m.selectedIndex = saved.selectedIndex
+	m.focusOnRecent = saved.focusOnRecent
+	m.recentLoaded = ok
+	m.keepSelection = ok
+	m.displayCount = m.calculateDisplayCount()
+	return m.Init()
+}
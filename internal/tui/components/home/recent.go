@@ -7,6 +7,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 )
 
@@ -109,51 +110,51 @@ func FormatTimeAgo(t time.Time) string {
 
 	// Less than a minute
 	if duration < time.Minute {
-		return "just now"
+		return i18n.T("just now")
 	}
 
 	// Less than an hour
 	if duration < time.Hour {
 		minutes := int(duration.Minutes())
 		if minutes == 1 {
-			return "1 minute ago"
+			return i18n.T("1 minute ago")
 		}
-		return fmt.Sprintf("%d minutes ago", minutes)
+		return i18n.T("%d minutes ago", minutes)
 	}
 
 	// Less than a day
 	if duration < 24*time.Hour {
 		hours := int(duration.Hours())
 		if hours == 1 {
-			return "1 hour ago"
+			return i18n.T("1 hour ago")
 		}
-		return fmt.Sprintf("%d hours ago", hours)
+		return i18n.T("%d hours ago", hours)
 	}
 
 	// Less than a week
 	if duration < 7*24*time.Hour {
 		days := int(duration.Hours() / 24)
 		if days == 1 {
-			return "1 day ago"
+			return i18n.T("1 day ago")
 		}
-		return fmt.Sprintf("%d days ago", days)
+		return i18n.T("%d days ago", days)
 	}
 
 	// Less than a month
 	if duration < 30*24*time.Hour {
 		weeks := int(duration.Hours() / 24 / 7)
 		if weeks == 1 {
-			return "1 week ago"
+			return i18n.T("1 week ago")
 		}
-		return fmt.Sprintf("%d weeks ago", weeks)
+		return i18n.T("%d weeks ago", weeks)
 	}
 
 	// More than a month
 	months := int(duration.Hours() / 24 / 30)
 	if months == 1 {
-		return "1 month ago"
+		return i18n.T("1 month ago")
 	}
-	return fmt.Sprintf("%d months ago", months)
+	return i18n.T("%d months ago", months)
 }
 
 // FormatDuration formats seconds as HH:MM:SS or MM:SS
@@ -177,12 +178,12 @@ func FormatEpisodeTitle(item RecentItem) string {
 
 	// For Manga
 	if item.MediaType == "manga" {
-		return fmt.Sprintf("%s - Chapter %d", item.MediaTitle, item.Episode)
+		return i18n.T("%s - Chapter %d", item.MediaTitle, item.Episode)
 	}
 
 	// For TV/Anime with episodes
 	if item.Season > 0 {
 		return fmt.Sprintf("%s - S%d E%d", item.MediaTitle, item.Season, item.Episode)
 	}
-	return fmt.Sprintf("%s - Episode %d", item.MediaTitle, item.Episode)
+	return i18n.T("%s - Episode %d", item.MediaTitle, item.Episode)
 }
@@ -1,6 +1,7 @@
 package manga
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,12 +18,14 @@ import (
 
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/imagecache"
 	"github.com/justchokingaround/greg/internal/tui/common"
 )
 
 type Model struct {
 	Config                *config.Config
 	DB                    *gorm.DB
+	Images                *imagecache.Cache // Shared image cache, nil to download pages every time
 	Pages                 []string
 	Title                 string
 	Chapter               string
@@ -44,8 +47,16 @@ type Model struct {
 	ProviderName  string
 	AniListID     *int
 	StatusMessage string
+	Incognito     bool // Don't save reading progress
+
+	// Pages seen in this chapter, for the completion threshold
+	viewed         map[int]bool
+	completionSent bool
 }
 
+// prefetchPages is how many pages ahead are fetched in the background
+const prefetchPages = 2
+
 type PageRenderedMsg struct {
 	Content string
 	Err     error
@@ -105,9 +116,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Check if completed
 				if m.CurrentPage >= len(m.Pages)-1 {
-					cmds = append(cmds, func() tea.Msg {
-						return common.ChapterCompletedMsg{MediaID: m.MediaID, Chapter: m.EpisodeNumber}
-					})
+					cmds = append(cmds, m.completeChapter())
 				}
 				// Hide cursor when dismissing prompt
 				cmds = append(cmds, tea.HideCursor, hideCursorPeriodically())
@@ -132,7 +141,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Hide cursor when dismissing prompt
 				return m, tea.Batch(
 					func() tea.Msg { return common.NextChapterMsg{} },
-					func() tea.Msg { return common.ChapterCompletedMsg{MediaID: m.MediaID, Chapter: m.EpisodeNumber} },
+					m.completeChapter(),
 					tea.HideCursor,
 					hideCursorPeriodically(),
 				)
@@ -164,7 +173,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.InputBuffer = ""
 						m.updateHistory()
 						// Hide cursor when exiting input mode
-						return m, tea.Batch(m.renderPage(), tea.HideCursor, hideCursorPeriodically())
+						return m, tea.Batch(m.renderPage(), m.markViewed(), tea.HideCursor, hideCursorPeriodically())
 					}
 				}
 				// Invalid input or page number, just exit input mode
@@ -209,7 +218,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.CurrentPage++
 				m.Loading = true
 				m.updateHistory() // Save progress on page turn
-				return m, tea.Batch(m.renderPage(), hideCursorPeriodically())
+				return m, tea.Batch(m.renderPage(), m.markViewed(), hideCursorPeriodically())
 			} else if len(m.Pages) > 0 {
 				// End of chapter
 				m.updateHistory() // Ensure completed status is saved
@@ -475,6 +484,8 @@ func (m *Model) SetContent(pages []string, title, chapter string, mediaID string
 	m.Loading = true
 	m.ShowNextChapterPrompt = false
 	m.ShowQuitPrompt = false
+	m.viewed = make(map[int]bool)
+	m.completionSent = false
 
 	// Check history to resume
 	if m.DB != nil && m.MediaID != "" {
@@ -487,12 +498,17 @@ func (m *Model) SetContent(pages []string, title, chapter string, mediaID string
 		}
 	}
 
+	// Pages before the resume point were read in an earlier session
+	for i := 0; i <= m.CurrentPage && i < len(m.Pages); i++ {
+		m.viewed[i] = true
+	}
+
 	// Initial history update - REMOVED to avoid auto-save on load
 	// m.updateHistory()
 }
 
 func (m *Model) updateHistory() {
-	if m.DB == nil || m.MediaID == "" {
+	if m.DB == nil || m.MediaID == "" || m.Incognito {
 		return
 	}
 
@@ -537,6 +553,48 @@ func (m *Model) updateHistory() {
 	}
 }
 
+// completionThreshold returns the share of pages that must be viewed for a
+// chapter to count as read
+func (m *Model) completionThreshold() float64 {
+	if m.Config == nil {
+		return 1.0
+	}
+	threshold := m.Config.Tracker.AniList.ChapterThreshold
+	if threshold <= 0 || threshold > 1 {
+		return 1.0
+	}
+	return threshold
+}
+
+// markViewed records the current page as viewed and completes the chapter
+// once enough pages were seen
+func (m *Model) markViewed() tea.Cmd {
+	if len(m.Pages) == 0 {
+		return nil
+	}
+	if m.viewed == nil {
+		m.viewed = make(map[int]bool)
+	}
+	m.viewed[m.CurrentPage] = true
+
+	if m.completionSent || float64(len(m.viewed))/float64(len(m.Pages)) < m.completionThreshold() {
+		return nil
+	}
+	return m.completeChapter()
+}
+
+// completeChapter reports the chapter as read, at most once per chapter
+func (m *Model) completeChapter() tea.Cmd {
+	if m.completionSent {
+		return nil
+	}
+	m.completionSent = true
+	mediaID, chapter := m.MediaID, m.EpisodeNumber
+	return func() tea.Msg {
+		return common.ChapterCompletedMsg{MediaID: mediaID, Chapter: chapter}
+	}
+}
+
 func (m *Model) RenderCurrentPage() tea.Cmd {
 	return m.renderPage()
 }
@@ -578,26 +636,19 @@ func (m *Model) renderPage() tea.Cmd {
 		}
 	}
 
-	return func() tea.Msg {
-		// Create a temp file
-		tmpFile, err := os.CreateTemp("", "greg-manga-*.jpg")
-		if err != nil {
-			return PageRenderedMsg{Err: fmt.Errorf("failed to create temp file: %w", err)}
-		}
-		defer func() { _ = os.Remove(tmpFile.Name()) }()
-
-		// Download the image
-		resp, err := http.Get(url)
-		if err != nil {
-			return PageRenderedMsg{Err: fmt.Errorf("failed to download image: %w", err)}
-		}
-		defer func() { _ = resp.Body.Close() }()
+	// Warm the cache with the next pages while this one renders
+	images := m.Images
+	if images != nil {
+		end := min(m.CurrentPage+1+prefetchPages, len(m.Pages))
+		images.Prefetch(m.Pages[m.CurrentPage+1 : end]...)
+	}
 
-		_, err = io.Copy(tmpFile, resp.Body)
+	return func() tea.Msg {
+		imagePath, cleanup, err := fetchPage(images, url)
 		if err != nil {
-			return PageRenderedMsg{Err: fmt.Errorf("failed to save image: %w", err)}
+			return PageRenderedMsg{Err: err}
 		}
-		_ = tmpFile.Close()
+		defer cleanup()
 
 		// Run chafa
 		// We set --size to the available area
@@ -621,7 +672,7 @@ func (m *Model) renderPage() tea.Cmd {
 		// "symbols" or others will use default (no -f flag or specific one if needed, but chafa defaults to symbols)
 
 		// Add file path
-		args = append(args, tmpFile.Name())
+		args = append(args, imagePath)
 
 		cmd := exec.Command("chafa", args...)
 		output, err := cmd.Output()
@@ -632,3 +683,38 @@ func (m *Model) renderPage() tea.Cmd {
 		return PageRenderedMsg{Content: strings.Trim(string(output), "\n\r\t ")}
 	}
 }
+
+// fetchPage returns a local path for the page image, from the image cache
+// when there is one or a temp file otherwise. cleanup removes the temp file.
+func fetchPage(images *imagecache.Cache, url string) (string, func(), error) {
+	if images != nil {
+		path, err := images.Get(context.Background(), url)
+		if err != nil {
+			return "", nil, err
+		}
+		return path, func() {}, nil
+	}
+
+	// Create a temp file
+	tmpFile, err := os.CreateTemp("", "greg-manga-*.jpg")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cleanup := func() { _ = os.Remove(tmpFile.Name()) }
+
+	// Download the image
+	resp, err := http.Get(url)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, err = io.Copy(tmpFile, resp.Body)
+	_ = tmpFile.Close()
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to save image: %w", err)
+	}
+	return tmpFile.Name(), cleanup, nil
+}
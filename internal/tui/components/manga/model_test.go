@@ -0,0 +1,40 @@
+package manga
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func TestChapterCompletesAtPageThreshold(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Tracker.AniList.ChapterThreshold = 0.5
+
+	m := New(cfg, nil)
+	m.SetContent([]string{"1", "2", "3", "4"}, "Title", "Chapter 7", "media", 7, "comix", nil)
+
+	// Two of four pages viewed
+	m.CurrentPage = 1
+	cmd := m.markViewed()
+	require.NotNil(t, cmd)
+	assert.Equal(t, common.ChapterCompletedMsg{MediaID: "media", Chapter: 7}, cmd())
+
+	// Completion is only reported once per chapter
+	m.CurrentPage = 3
+	assert.Nil(t, m.markViewed())
+	assert.Nil(t, m.completeChapter())
+}
+
+func TestChapterThresholdDefaultsToAllPages(t *testing.T) {
+	m := New(nil, nil)
+	m.SetContent([]string{"1", "2", "3"}, "Title", "Chapter 1", "media", 1, "comix", nil)
+
+	m.CurrentPage = 1
+	assert.Nil(t, m.markViewed())
+	m.CurrentPage = 2
+	assert.NotNil(t, m.markViewed())
+}
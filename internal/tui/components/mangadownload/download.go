@@ -60,6 +60,11 @@ func New() Model {
 	}
 }
 
+// SetSpinner replaces the spinner animation
+func (m *Model) SetSpinner(s spinner.Spinner) {
+	m.spinner.Spinner = s
+}
+
 func (m *Model) SetTotal(total int) {
 	m.totalChapters = total
 }
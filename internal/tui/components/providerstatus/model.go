@@ -25,6 +25,9 @@ type item struct {
 }
 
 func (i item) Title() string {
+	if i.status.LikelyGeoBlocked {
+		return fmt.Sprintf("%s 🌐 likely geo-blocked", i.status.ProviderName)
+	}
 	return i.status.ProviderName
 }
 
@@ -111,6 +114,9 @@ func (m Model) renderDetail() string {
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("Provider: %s\n\n", m.selectedItem.status.ProviderName))
 	b.WriteString(fmt.Sprintf("Status: %s\n", m.selectedItem.status.Status))
+	if m.selectedItem.status.LikelyGeoBlocked {
+		b.WriteString("Likely geo-blocked: fails consistently from your region\n")
+	}
 	b.WriteString(fmt.Sprintf("Duration: %s\n\n", r.Duration))
 	b.WriteString("Curl Command:\n")
 	b.WriteString(r.CurlCommand)
@@ -20,9 +20,11 @@ func (m *Model) SetMediaResults(results []providers.Media) {
 	// But preserve width and height from previous model
 	oldWidth := m.mangal.width
 	oldHeight := m.mangal.height
+	favorites := m.mangal.favorites
 	m.mangal = NewMangal()
 	m.mangal.width = oldWidth
 	m.mangal.height = oldHeight
+	m.mangal.favorites = favorites
 	m.mangal.SetMediaResults(results)
 }
 
@@ -37,6 +39,11 @@ func (m *Model) SetEpisodeResults(results []providers.Episode) {
 	m.mangal.SetEpisodeResults(results)
 }
 
+// RequestVisibleDetails requests details for the rows on or near screen
+func (m *Model) RequestVisibleDetails() tea.Cmd {
+	return m.mangal.checkDetailsNeeded()
+}
+
 func (m *Model) UpdateMediaItem(index int, media providers.Media) {
 	m.mangal.UpdateMediaItem(index, media)
 }
@@ -65,6 +72,19 @@ func (m *Model) SetProviderName(name string) {
 	m.mangal.providerName = name
 }
 
+// SetFavorites sets the starred media IDs
+func (m *Model) SetFavorites(ids map[string]bool) {
+	m.mangal.favorites = ids
+}
+
+// SetFavorite stars or unstars a media ID
+func (m *Model) SetFavorite(id string, starred bool) {
+	if m.mangal.favorites == nil {
+		m.mangal.favorites = make(map[string]bool)
+	}
+	m.mangal.favorites[id] = starred
+}
+
 func (m *Model) SetIsProviderSelection(isProviderSelection bool) {
 	m.mangal.isProviderSelection = isProviderSelection
 }
@@ -33,7 +33,8 @@ type MangalModel struct {
 	dialogScroll        int // Scroll offset for info dialog
 	showMangaInfo       bool
 	providerName        string
-	isProviderSelection bool // True when showing provider selection
+	isProviderSelection bool            // True when showing provider selection
+	favorites           map[string]bool // Starred media IDs
 }
 
 func NewMangal() MangalModel {
@@ -128,7 +129,11 @@ func (m MangalModel) Init() tea.Cmd {
 	return nil
 }
 
-// checkDetailsNeeded checks if visible items need details fetched
+// detailsLookahead is how many rows beyond the viewport get details fetched
+const detailsLookahead = 2
+
+// checkDetailsNeeded requests details for rows on or near screen, nearest
+// the cursor first
 func (m *MangalModel) checkDetailsNeeded() tea.Cmd {
 	if m.itemType != mediaType || len(m.results) == 0 {
 		return nil
@@ -137,20 +142,14 @@ func (m *MangalModel) checkDetailsNeeded() tea.Cmd {
 	// Get filtered indices
 	filteredIndices := m.getFilteredIndices()
 	displayCount := len(filteredIndices)
-	if displayCount == 0 {
-		return nil
-	}
 
-	// Get visible range
+	// Get visible range, widened by the lookahead
 	start, end := m.getVisibleRange(displayCount)
+	start = max(start-detailsLookahead, 0)
+	end = min(end+detailsLookahead, displayCount)
 
-	var cmds []tea.Cmd
-
-	// Check all visible items
+	var items []common.DetailsRequest
 	for i := start; i < end; i++ {
-		if i >= len(filteredIndices) {
-			break
-		}
 		actualIndex := filteredIndices[i]
 		if actualIndex >= len(m.results) {
 			continue
@@ -158,23 +157,22 @@ func (m *MangalModel) checkDetailsNeeded() tea.Cmd {
 
 		media := m.results[actualIndex]
 		if media.Synopsis == "" {
-			// Capture index for closure
-			idx := actualIndex
-			mid := media.ID
-			cmds = append(cmds, func() tea.Msg {
-				return common.RequestDetailsMsg{
-					MediaID: mid,
-					Index:   idx,
-				}
+			priority := i - m.currentIndex
+			if priority < 0 {
+				priority = -priority
+			}
+			items = append(items, common.DetailsRequest{
+				MediaID:  media.ID,
+				Index:    actualIndex,
+				Priority: priority,
 			})
 		}
 	}
 
-	if len(cmds) > 0 {
-		return tea.Batch(cmds...)
+	// Sent even when empty so requests for rows scrolled away are cancelled
+	return func() tea.Msg {
+		return common.RequestDetailsMsg{Items: items}
 	}
-
-	return nil
 }
 
 func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -356,7 +354,7 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmd := m.fuzzySearch.Update(msg)
 				// Reset currentIndex when query changes
 				m.currentIndex = 0
-				return m, cmd
+				return m, tea.Batch(cmd, m.checkDetailsNeeded())
 			}
 		}
 
@@ -463,6 +461,25 @@ func (m MangalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+		case "f":
+			// Star or unstar the selected media
+			if m.itemType == mediaType && len(m.results) > 0 && !m.isProviderSelection {
+				selected := m.results[m.currentIndex]
+				return m, func() tea.Msg {
+					return common.ToggleFavoriteMsg{
+						MediaID: selected.ID,
+						Title:   selected.Title,
+						Type:    string(selected.Type),
+					}
+				}
+			}
+		case "tab":
+			// Switch media type, keeping these results for when we come back
+			if m.itemType == mediaType && !m.isProviderSelection {
+				return m, func() tea.Msg {
+					return common.ToggleProviderMsg{}
+				}
+			}
 		case "s":
 			// Show debug info (source links)
 			if m.itemType == mediaType && len(m.results) > 0 {
@@ -532,10 +549,17 @@ func (m MangalModel) renderMediaResults() string {
 	var content strings.Builder
 
 	// Top padding
-	content.WriteString("\n\n")
+	if utils.Short(m.height) {
+		content.WriteString("\n")
+	} else {
+		content.WriteString("\n\n")
+	}
 
 	// Header with count - clean and readable
 	header := styles.TitleStyle.Render("  RESULTS  ")
+	if m.compact() {
+		header = styles.TitleStyle.Render("RESULTS")
+	}
 
 	// Add provider badge if available
 	if m.providerName != "" {
@@ -547,7 +571,7 @@ func (m MangalModel) renderMediaResults() string {
 		header = lipgloss.JoinHorizontal(lipgloss.Center, header, " ", providerBadge)
 	}
 
-	content.WriteString(header + "\n")
+	content.WriteString(utils.Truncate(header, m.width) + "\n")
 
 	// Get filtered indices if fuzzy search is active
 	filteredIndices := m.getFilteredIndices()
@@ -557,7 +581,7 @@ func (m MangalModel) renderMediaResults() string {
 	if m.fuzzySearch.IsActive() && m.fuzzySearch.Query() != "" {
 		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" (filtered from %d)", len(m.results)))
 	}
-	content.WriteString(count + "\n")
+	content.WriteString(utils.Truncate(count, m.width) + "\n")
 
 	// Show fuzzy search input if active
 	if m.fuzzySearch.IsActive() {
@@ -587,7 +611,7 @@ func (m MangalModel) renderMediaResults() string {
 			break
 		}
 		media := m.results[actualIndex]
-		content.WriteString(m.renderMediaItem(media, i == m.currentIndex) + "\n\n")
+		content.WriteString(m.renderMediaItem(media, i == m.currentIndex) + m.rowGap())
 	}
 
 	// Build help text (will be positioned at bottom) - concise version
@@ -602,7 +626,7 @@ func (m MangalModel) renderMediaResults() string {
 		}
 	} else {
 		// Normal results view
-		helpText = "  ↑/↓ nav • enter select • s src • i info • / filter • esc back"
+		helpText = "  ↑/↓ nav • enter select • s src • i info • f fav • tab mode • / filter • esc back"
 
 		// Add manga info if enabled and it's anime
 		isAnime := false
@@ -611,7 +635,7 @@ func (m MangalModel) renderMediaResults() string {
 		}
 
 		if m.showMangaInfo && isAnime {
-			helpText = "  ↑/↓ nav • enter select • s src • i info • m manga • / filter • esc back"
+			helpText = "  ↑/↓ nav • enter select • s src • i info • f fav • m manga • tab mode • / filter • esc back"
 		}
 
 		if m.fuzzySearch.IsActive() {
@@ -658,7 +682,12 @@ func (m MangalModel) renderMediaResults() string {
 		}
 	}
 
-	styledHelpText := styles.AniListHelpStyle.Render(helpText)
+	// Narrow terminals get the essentials, the rest is in the ? help
+	shortHelp := []string{"  ↑/↓ nav • enter select • i info • / filter • esc back", "  ↑/↓ • enter • i info • / filter • esc back"}
+	if m.fuzzySearch.IsActive() && m.fuzzySearch.IsLocked() {
+		shortHelp = []string{"  ↑/↓ nav • enter select • / edit • esc clear"}
+	}
+	styledHelpText := styles.AniListHelpStyle.Render(utils.FitHelp(m.width, append([]string{helpText}, shortHelp...)...))
 
 	// If height is available, use fixed layout with help at bottom
 	if m.height > 0 {
@@ -704,7 +733,7 @@ func (m MangalModel) renderEpisodeResults() string {
 		header = lipgloss.JoinHorizontal(lipgloss.Center, header, " ", providerBadge)
 	}
 
-	content.WriteString(header + "\n")
+	content.WriteString(utils.Truncate(header, m.width) + "\n")
 
 	// Get filtered indices if fuzzy search is active
 	filteredIndices := m.getFilteredIndices()
@@ -714,7 +743,7 @@ func (m MangalModel) renderEpisodeResults() string {
 	if m.fuzzySearch.IsActive() && m.fuzzySearch.Query() != "" {
 		count += styles.AniListMetadataStyle.Render(fmt.Sprintf(" (filtered from %d)", len(m.episodes)))
 	}
-	content.WriteString(count + "\n")
+	content.WriteString(utils.Truncate(count, m.width) + "\n")
 
 	// Show fuzzy search input if active
 	if m.fuzzySearch.IsActive() {
@@ -735,7 +764,7 @@ func (m MangalModel) renderEpisodeResults() string {
 			break
 		}
 		episode := m.episodes[actualIndex]
-		content.WriteString(m.renderEpisodeItem(episode, i == m.currentIndex) + "\n\n")
+		content.WriteString(m.renderEpisodeItem(episode, i == m.currentIndex) + m.rowGap())
 	}
 
 	// Build help text (will be positioned at bottom)
@@ -761,7 +790,11 @@ func (m MangalModel) renderEpisodeResults() string {
 			helpText = "  Type to filter • ↑/↓ navigate • w share • ? help • esc lock filter"
 		}
 	}
-	styledHelpText := styles.AniListHelpStyle.Render(helpText)
+	shortHelp := "  ↑/↓ nav • enter play • / filter • ? help • esc back"
+	if m.fuzzySearch.IsActive() && !m.fuzzySearch.IsLocked() {
+		shortHelp = "  Type to filter • ↑/↓ nav • esc lock"
+	}
+	styledHelpText := styles.AniListHelpStyle.Render(utils.FitHelp(m.width, helpText, shortHelp))
 
 	// If height is available, use fixed layout with help at bottom
 	if m.height > 0 {
@@ -797,26 +830,38 @@ func (m MangalModel) renderMediaItem(media providers.Media, selected bool) strin
 
 	var lines []string
 
-	// Line 1: Title (always present)
-	lines = append(lines, titleStyle.Render(media.Title))
+	// Line 1: Title (always present), starred favorites first
+	if m.favorites[media.ID] {
+		star := lipgloss.NewStyle().Foreground(styles.OxocarbonPink).Render("★ ")
+		lines = append(lines, star+titleStyle.Render(utils.Truncate(media.Title, utils.ItemWidth(m.width)-2)))
+	} else {
+		lines = append(lines, titleStyle.Render(utils.Truncate(media.Title, utils.ItemWidth(m.width))))
+	}
 
-	// Line 2: Metadata (Year • Type • Rating • Status • Episodes)
+	// Line 2: Metadata (Year • Type • Rating • Status • Episodes), collapsed
+	// to year, rating and episode count in the compact layout
+	compact := m.compact()
 	var metaParts []string
 	if media.Year > 0 {
 		metaParts = append(metaParts, fmt.Sprintf("%d", media.Year))
 	}
-	if media.Type != "" {
+	if media.Type != "" && !compact {
 		metaParts = append(metaParts, string(media.Type))
 	}
 	if media.Rating > 0 {
 		metaParts = append(metaParts, fmt.Sprintf("★ %.1f", media.Rating))
 	}
-	if media.Status != "" && media.Status != "Unknown" {
+	if media.Status != "" && media.Status != "Unknown" && !compact {
 		metaParts = append(metaParts, media.Status)
 	}
 	if media.TotalEpisodes > 0 {
 		label := "episodes"
-		if media.Type == providers.MediaTypeManga {
+		switch {
+		case compact && media.Type == providers.MediaTypeManga:
+			label = "ch"
+		case compact:
+			label = "eps"
+		case media.Type == providers.MediaTypeManga:
 			label = "chapters"
 		}
 		metaParts = append(metaParts, fmt.Sprintf("%d %s", media.TotalEpisodes, label))
@@ -824,7 +869,15 @@ func (m MangalModel) renderMediaItem(media providers.Media, selected bool) strin
 
 	if len(metaParts) > 0 {
 		meta := strings.Join(metaParts, " • ")
-		lines = append(lines, metaStyle.Render(meta))
+		lines = append(lines, metaStyle.Render(utils.Truncate(meta, utils.ItemWidth(m.width))))
+	}
+
+	// Compact rows stop at the title and metadata; 'i' shows the rest
+	if compact {
+		if len(metaParts) == 0 {
+			lines = append(lines, " ")
+		}
+		return boxStyle.Render(strings.Join(lines, "\n"))
 	}
 
 	// Lines 3-4: Synopsis (max 2 lines) - always present for consistency
@@ -835,6 +888,9 @@ func (m MangalModel) renderMediaItem(media providers.Media, selected bool) strin
 	if availableWidth > 100 {
 		availableWidth = 100
 	}
+	if itemWidth := utils.ItemWidth(m.width); itemWidth > 0 && availableWidth > itemWidth {
+		availableWidth = itemWidth
+	}
 
 	synopsis := media.Synopsis
 	if synopsis == "" {
@@ -898,12 +954,27 @@ func (m MangalModel) renderEpisodeItem(episode providers.Episode, selected bool)
 		}
 	}
 
-	title := titleStyle.Render(titleText)
+	title := titleStyle.Render(utils.Truncate(titleText, utils.ItemWidth(m.width)))
 	content := episodeNum + "\n" + title
 
 	return boxStyle.Render(content)
 }
 
+// compact reports whether results are shown as compact rows: title and
+// metadata only, for narrow or short terminals
+func (m MangalModel) compact() bool {
+	return utils.Compact(m.width) || utils.Short(m.height)
+}
+
+// rowGap separates rendered items, with a blank line unless the terminal is
+// short
+func (m MangalModel) rowGap() string {
+	if utils.Short(m.height) {
+		return "\n"
+	}
+	return "\n\n"
+}
+
 // getFilteredIndices returns the indices of items that match the fuzzy search
 func (m MangalModel) getFilteredIndices() []int {
 	var searchStrings []string
@@ -940,6 +1011,9 @@ func (m MangalModel) getVisibleRange(total int) (int, int) {
 		if m.fuzzySearch.IsActive() {
 			overhead = 10 // Fuzzy search takes more space
 		}
+		if utils.Short(m.height) {
+			overhead-- // Single line of top padding
+		}
 
 		itemsSpace := m.height - overhead
 		if itemsSpace > 0 {
@@ -947,6 +1021,13 @@ func (m MangalModel) getVisibleRange(total int) (int, int) {
 			// Each item typically takes: title (1) + metadata (1) + synopsis (2) + genres (1) + borders/spacing (1) = ~6 lines
 			// Use a conservative estimate to maximize space usage
 			linesPerItem := 6
+			if m.itemType == episodeType || m.compact() {
+				// Title and metadata, plus the gap
+				linesPerItem = 3
+				if utils.Short(m.height) {
+					linesPerItem = 2
+				}
+			}
 			// Allow more items if space permits (user requested more than 3)
 			maxVisible = itemsSpace / linesPerItem
 		}
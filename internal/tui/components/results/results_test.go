@@ -0,0 +1,58 @@
+package results
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/tuitest"
+)
+
+var sizes = []struct{ width, height int }{{50, 15}, {60, 20}, {69, 24}, {80, 24}, {120, 40}}
+
+func sampleResults() []providers.Media {
+	return []providers.Media{
+		{
+			ID:            "1",
+			Title:         "Sousou no Frieren: The Journey's End and Everything After It",
+			Type:          providers.MediaTypeAnime,
+			Year:          2023,
+			Rating:        9.1,
+			Status:        "Finished Airing",
+			TotalEpisodes: 28,
+			Synopsis:      "The adventure is over but life goes on for an elf mage just beginning to learn what living is all about, decades after her party defeated the Demon King.",
+			Genres:        []string{"Adventure", "Drama", "Fantasy", "Shounen", "Slice of Life"},
+		},
+		{ID: "2", Title: "進撃の巨人 The Final Season Part 2", Type: providers.MediaTypeAnime, Year: 2022, TotalEpisodes: 12},
+		{ID: "3", Title: "Bocchi the Rock!", Type: providers.MediaTypeAnime, Genres: []string{"Comedy", "Music"}},
+	}
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			m := NewMangal()
+			m.providerName = "allanime"
+			m.SetMediaResults(sampleResults())
+			m.favorites = map[string]bool{"1": true}
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+			m = updated.(MangalModel)
+
+			tuitest.AssertFits(t, m.View(), size.width, size.height)
+		})
+	}
+}
+
+func TestCompactRowsShowMoreResults(t *testing.T) {
+	m := NewMangal()
+	m.SetMediaResults(sampleResults())
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	m = updated.(MangalModel)
+
+	start, end := m.getVisibleRange(3)
+	if end-start < 3 {
+		t.Errorf("compact layout shows %d of 3 results on a 60x20 terminal", end-start)
+	}
+}
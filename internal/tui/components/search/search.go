@@ -4,12 +4,15 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
 )
 
 type Model struct {
 	textInput textinput.Model
+	mediaType providers.MediaType // Mode searched, shown next to the header
 	width     int
 	height    int
 }
@@ -59,6 +62,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg {
 				return common.GoToHomeMsg{}
 			}
+		case "tab":
+			return m, func() tea.Msg {
+				return common.ToggleProviderMsg{}
+			}
 		}
 	}
 
@@ -72,6 +79,9 @@ func (m Model) View() string {
 
 	// Header
 	header := styles.TitleStyle.Render("  SEARCH  ")
+	if badge := m.modeBadge(); badge != "" {
+		header += " " + badge
+	}
 	output += header + "\n"
 
 	// Subtitle
@@ -85,12 +95,41 @@ func (m Model) View() string {
 
 	// Help text - never show p/1/2/3 in search mode as user needs to type freely
 	// User can use these keys before entering search (from home view)
-	helpText := "  enter search • esc back"
+	helpText := "  enter search • tab mode • esc back"
 	output += "\n" + styles.AniListHelpStyle.Render(helpText)
 
 	return output
 }
 
+// SetMediaType sets the mode shown next to the header
+func (m *Model) SetMediaType(mediaType providers.MediaType) {
+	m.mediaType = mediaType
+}
+
+// modeBadge renders the current mode
+func (m Model) modeBadge() string {
+	var mode string
+	color := styles.OxocarbonPurple
+	switch m.mediaType {
+	case providers.MediaTypeAnime:
+		mode = i18n.T("ANIME")
+	case providers.MediaTypeMovieTV:
+		mode = i18n.T("MOVIES/TV")
+		color = styles.OxocarbonBlue
+	case providers.MediaTypeManga:
+		mode = i18n.T("MANGA")
+		color = styles.OxocarbonPink
+	default:
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(styles.OxocarbonBase00).
+		Background(color).
+		Padding(0, 1).
+		Bold(true).
+		Render(mode)
+}
+
 // SetValue sets the value of the search input
 func (m *Model) SetValue(value string) {
 	m.textInput.SetValue(value)
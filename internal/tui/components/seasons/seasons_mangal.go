@@ -8,6 +8,7 @@ import (
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
 )
 
 // MangalModel is a mangal-style seasons view
@@ -261,7 +262,7 @@ func (m MangalModel) renderSeasonItem(season providers.Season, selected bool) st
 	}
 
 	// Season title with high contrast (already contains "Season N")
-	title := titleStyle.Render(season.Title)
+	title := titleStyle.Render(utils.Truncate(season.Title, utils.ItemWidth(m.width)))
 
 	return boxStyle.Render(title)
 }
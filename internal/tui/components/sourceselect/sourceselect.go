@@ -0,0 +1,177 @@
+package sourceselect
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// SelectionMsg sent when user selects a source
+type SelectionMsg struct {
+	Source providers.StreamSource
+}
+
+// CancelMsg sent when user cancels selection
+type CancelMsg struct{}
+
+type Model struct {
+	sources     []providers.StreamSource
+	title       string
+	fuzzySearch *common.FuzzySearch
+	selected    int // Current selection index in filtered results
+	width       int
+	height      int
+}
+
+func New(sources []providers.StreamSource, title string) Model {
+	fuzzySearch := common.NewFuzzySearch()
+	// Start locked so j/k navigate right away; '/' starts filtering
+	fuzzySearch.Activate()
+	fuzzySearch.Lock()
+
+	return Model{
+		sources:     sources,
+		title:       title,
+		fuzzySearch: fuzzySearch,
+		selected:    0,
+		width:       80,
+		height:      20,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Unlock fuzzy search on '/'
+		if msg.String() == "/" && m.fuzzySearch.IsLocked() {
+			return m, m.fuzzySearch.Unlock()
+		}
+
+		// Handle input when fuzzy search unlocked
+		if !m.fuzzySearch.IsLocked() {
+			switch msg.String() {
+			case "esc":
+				m.fuzzySearch.Lock()
+				return m, nil
+			case "enter":
+				m.fuzzySearch.Lock()
+				m.selected = 0
+				return m, nil
+			default:
+				return m, m.fuzzySearch.Update(msg)
+			}
+		}
+
+		switch msg.String() {
+		case "j", "down":
+			filtered := m.getFilteredIndices()
+			if m.selected < len(filtered)-1 {
+				m.selected++
+			}
+		case "k", "up":
+			if m.selected > 0 {
+				m.selected--
+			}
+		case "enter":
+			filtered := m.getFilteredIndices()
+			if len(filtered) > 0 && m.selected < len(filtered) {
+				source := m.sources[filtered[m.selected]]
+				return m, func() tea.Msg {
+					return SelectionMsg{Source: source}
+				}
+			}
+		case "esc", "q":
+			return m, func() tea.Msg { return CancelMsg{} }
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.fuzzySearch.SetWidth(msg.Width)
+	}
+
+	return m, nil
+}
+
+func (m Model) View() string {
+	var s strings.Builder
+
+	s.WriteString(styles.AniListTitleStyle.Render("Select Source") + "\n")
+	if m.title != "" {
+		s.WriteString(styles.AniListMetadataStyle.Render(m.title) + "\n")
+	}
+	s.WriteString("\n")
+
+	filtered := m.getFilteredIndices()
+	if len(filtered) == 0 {
+		s.WriteString(styles.AniListMetadataStyle.Render("\nNo sources match filter.\n\n"))
+	} else {
+		for i, idx := range filtered {
+			label := Label(m.sources[idx])
+			if i == m.selected {
+				selectedStyle := styles.AniListTitleStyle.Foreground(styles.OxocarbonPurple)
+				s.WriteString(selectedStyle.Render("> "+label) + "\n")
+			} else {
+				s.WriteString(styles.AniListMetadataStyle.Render("  "+label) + "\n")
+			}
+		}
+	}
+
+	s.WriteString("\n")
+	fuzzyView := m.fuzzySearch.View()
+	if !m.fuzzySearch.IsLocked() {
+		borderStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(styles.OxocarbonPurple)
+		fuzzyView = borderStyle.Render(fuzzyView)
+	}
+	s.WriteString(fuzzyView + "\n")
+
+	if m.fuzzySearch.IsLocked() {
+		s.WriteString("\n" + styles.HelpStyle.Render("j/k: navigate • /: filter • enter: play • esc/q: cancel"))
+	} else {
+		s.WriteString("\n" + styles.HelpStyle.Render("type to filter • enter: confirm • esc: stop filtering"))
+	}
+
+	return s.String()
+}
+
+// Label describes a source as "server • quality • audio • type"
+func Label(source providers.StreamSource) string {
+	parts := []string{source.Server}
+	if source.Stream != nil {
+		quality := string(source.Stream.Quality)
+		if quality == "" {
+			quality = string(providers.QualityAuto)
+		}
+		parts = append(parts, quality)
+	}
+	if source.Audio != "" {
+		parts = append(parts, source.Audio)
+	}
+	if source.Stream != nil && source.Stream.Type != "" {
+		parts = append(parts, string(source.Stream.Type))
+	}
+	if source.Stream != nil && len(source.Stream.Subtitles) > 0 {
+		parts = append(parts, fmt.Sprintf("%d subs", len(source.Stream.Subtitles)))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// getFilteredIndices returns source indices matching fuzzy search
+func (m Model) getFilteredIndices() []int {
+	searchStrings := make([]string, len(m.sources))
+	for i, source := range m.sources {
+		searchStrings[i] = Label(source)
+	}
+	return m.fuzzySearch.Filter(searchStrings)
+}
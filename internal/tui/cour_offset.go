@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	trackeranilist "github.com/justchokingaround/greg/internal/tracker/anilist"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+	"github.com/justchokingaround/greg/internal/tui/components/episodes"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+func init() {
+	handle(func(a *App, _ episodes.CourOffsetRequestedMsg) (tea.Model, tea.Cmd) {
+		return a.handleCourOffsetRequestedMsg()
+	})
+	handleCmd(func(a *App, msg prequelEpisodesMsg) tea.Cmd {
+		a.handlePrequelEpisodesMsg(msg)
+		return nil
+	})
+}
+
+// courOffset is the episode offset of the provider mapping of the AniList
+// entry being watched. Providers often list a show AniList splits into cours
+// as one, so with an offset of 25 provider episode 27 is episode 2 of the
+// AniList entry.
+type courOffset struct {
+	anilistID int
+	offset    int
+	verified  bool // Set or confirmed by the user
+
+	// The prompt setting it (courOffsetView)
+	input     textinput.Model
+	suggested int                 // From the AniList prequels, -1 until looked up
+	episodes  []providers.Episode // Provider episodes, for auto-play once confirmed
+	autoPlay  bool                // Continue with auto-play rather than the episode list
+}
+
+// prequelEpisodesMsg carries how many episodes the prequels of an AniList
+// entry have
+type prequelEpisodesMsg struct {
+	anilistID int
+	episodes  int
+	err       error
+}
+
+// prequelChainLookup is implemented by tracker clients that can follow an
+// anime's prequels
+type prequelChainLookup interface {
+	GetPrequelChain(ctx context.Context, id, limit int) ([]trackeranilist.Sequel, error)
+}
+
+// setCourOffset remembers the episode offset of the mapping found for an
+// AniList entry
+func (a *App) setCourOffset(anilistID int, providerMapping *mapping.ProviderMapping) {
+	a.cour = courOffset{anilistID: anilistID}
+	if providerMapping != nil {
+		a.cour.offset = providerMapping.EpisodeOffset
+		a.cour.verified = providerMapping.OffsetVerified
+	}
+}
+
+// episodeOffset returns how many provider episodes come before the first
+// episode of the AniList entry being watched
+func (a *App) episodeOffset() int {
+	if !a.watchingFromAniList || a.cour.anilistID != a.currentAniListID {
+		return 0
+	}
+	return a.cour.offset
+}
+
+// anilistEpisode returns the AniList number of a provider episode
+func (a *App) anilistEpisode(episode int) int {
+	return episode - a.episodeOffset()
+}
+
+// providerEpisode returns the provider's number of an AniList episode
+func (a *App) providerEpisode(episode int) int {
+	return episode + a.episodeOffset()
+}
+
+// checkCourOffset asks for the episode offset the first time an AniList
+// entry's mapping lists more episodes than the entry has, which is how a
+// provider listing several cours as one show looks. Entries the cross-ID
+// mapping datasets place in their show start at their absolute number
+// without asking. Returns false when there is nothing to ask.
+func (a *App) checkCourOffset(episodes []providers.Episode) (tea.Cmd, bool) {
+	media := a.currentAniListMedia
+	if !a.watchingFromAniList || media == nil || media.Type != providers.MediaTypeAnime {
+		return nil, false
+	}
+	if a.cour.anilistID != a.currentAniListID || a.cour.verified || !listsPastEntry(media, episodes) {
+		return nil, false
+	}
+	if offset, ok := a.absoluteStart(a.currentAniListID); ok {
+		a.debugLog("ID mappings: %s starts after episode %d", media.Title, offset)
+		a.cour.offset = offset
+		a.cour.verified = true
+		a.episodesComponent.SetAiring(a.airingSchedule())
+		return nil, false
+	}
+	return a.openCourOffset(episodes, true), true
+}
+
+// openCourOffset opens the prompt setting the episode offset and looks up
+// the episodes of the entry's prequels to suggest one
+func (a *App) openCourOffset(episodes []providers.Episode, autoPlay bool) tea.Cmd {
+	input := textinput.New()
+	input.Placeholder = "0"
+	input.CharLimit = 5
+	input.Width = 8
+	input.SetValue(strconv.Itoa(a.cour.offset))
+	input.CursorEnd()
+	input.Focus()
+
+	a.cour.input = input
+	a.cour.suggested = -1
+	a.cour.episodes = episodes
+	a.cour.autoPlay = autoPlay
+	a.state = courOffsetView
+
+	var lookup prequelChainLookup
+	if mgr := a.trackerMgr; mgr != nil {
+		lookup, _ = mgr.GetAniList().(prequelChainLookup)
+	}
+	if lookup == nil {
+		lookup = trackeranilist.NewClient(trackeranilist.Config{})
+	}
+	id := a.currentAniListID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		chain, err := lookup.GetPrequelChain(ctx, id, maxCours)
+		episodes := 0
+		for _, entry := range chain[min(1, len(chain)):] {
+			episodes += entry.Episodes
+		}
+		return prequelEpisodesMsg{anilistID: id, episodes: episodes, err: err}
+	}
+}
+
+// handlePrequelEpisodesMsg suggests the prequels' episodes as the offset,
+// filling it in when the user hasn't set one
+func (a *App) handlePrequelEpisodesMsg(msg prequelEpisodesMsg) {
+	if msg.anilistID != a.currentAniListID || a.state != courOffsetView {
+		return
+	}
+	if msg.err != nil {
+		a.debugLog("AniList: prequel chain lookup failed for %d: %v", msg.anilistID, msg.err)
+	}
+	a.cour.suggested = msg.episodes
+	if msg.episodes > 0 && !a.cour.verified && strings.TrimSpace(a.cour.input.Value()) == "0" {
+		a.cour.input.SetValue(strconv.Itoa(msg.episodes))
+		a.cour.input.CursorEnd()
+	}
+}
+
+// handleCourOffsetKeys handles keyboard input in the episode offset prompt
+func (a *App) handleCourOffsetKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "enter":
+		offset, err := strconv.Atoi(strings.TrimSpace(a.cour.input.Value()))
+		if err != nil || offset < 0 {
+			a.statusMsg = i18n.T("⚠ The offset is a number of episodes, 0 or more")
+			a.statusMsgTime = time.Now()
+			return a, nil
+		}
+		if mgr := a.mappingMgr; mgr != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err = mgr.SetEpisodeOffset(ctx, a.currentAniListID, offset)
+			cancel()
+		}
+		if err != nil {
+			a.logger.Warn("failed to save episode offset", "error", err)
+			a.statusMsg = i18n.T("⚠ Failed to save episode offset: %v", err)
+		} else {
+			a.statusMsg = i18n.T("✓ Episode 1 of %s is provider episode %d", a.currentAniListMedia.Title, offset+1)
+		}
+		a.statusMsgTime = time.Now()
+		a.cour.offset = offset
+		a.cour.verified = true
+		return a, a.closeCourOffset()
+	case "esc":
+		// Keep the offset for now and ask again next time
+		return a, a.closeCourOffset()
+	}
+
+	var cmd tea.Cmd
+	a.cour.input, cmd = a.cour.input.Update(msg)
+	return a, cmd
+}
+
+// closeCourOffset leaves the offset prompt for auto-play or the episode list
+func (a *App) closeCourOffset() tea.Cmd {
+	episodes := a.cour.episodes
+	a.cour.episodes = nil
+	a.episodesComponent.SetAiring(a.airingSchedule())
+	if a.cour.autoPlay {
+		if cmd, ok := a.autoPlayAniList(episodes); ok {
+			return cmd
+		}
+	}
+	a.episodesComponent.SetMediaType(a.selectedMedia.Type)
+	a.episodesComponent.SetEpisodes(a.episodes)
+	a.episodesComponent.SetCursorToEpisode(a.providerEpisode(a.currentAniListMedia.Progress + 1))
+	a.state = episodeView
+	return nil
+}
+
+// renderCourOffset renders the episode offset prompt
+func (a *App) renderCourOffset() string {
+	media := a.currentAniListMedia
+	if media == nil {
+		return ""
+	}
+
+	last := 0
+	for _, ep := range a.cour.episodes {
+		last = max(last, ep.Number)
+	}
+	text := i18n.T("%s\n\n%s lists %d episodes, the AniList entry has %d.\nIf it lists earlier seasons too, how many of its\nepisodes come before this entry's first one?",
+		media.Title, a.providerName, last, media.TotalEpisodes)
+	text += "\n\n" + a.cour.input.View()
+	switch {
+	case a.cour.suggested < 0:
+		text += "\n\n" + i18n.T("Looking up earlier seasons on AniList…")
+	case a.cour.suggested > 0:
+		text += "\n\n" + i18n.T("The earlier seasons on AniList have %d episodes", a.cour.suggested)
+	}
+	text += "\n\n" + i18n.T("[enter] Save  [esc] Not now")
+
+	return lipgloss.Place(
+		a.width,
+		a.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		styles.PopupStyle.Render(text),
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
+
+// handleCourOffsetRequestedMsg opens the episode offset prompt of the AniList
+// entry being watched
+func (a *App) handleCourOffsetRequestedMsg() (tea.Model, tea.Cmd) {
+	if !a.watchingFromAniList || a.cour.anilistID != a.currentAniListID || a.currentAniListMedia == nil {
+		a.statusMsg = i18n.T("⚠ The episode offset is set for anime watched from AniList")
+		a.statusMsgTime = time.Now()
+		return a, nil
+	}
+	return a, a.openCourOffset(a.episodes, false)
+}
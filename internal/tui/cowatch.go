@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle(func(a *App, _ common.CycleCoWatchMsg) (tea.Model, tea.Cmd) { return a.handleCycleCoWatchMsg() })
+}
+
+// CoWatch starts the TUI watching with a co-watch profile, set by main (--with)
+var CoWatch string
+
+// setCoWatch starts watching with a co-watch profile, or solo when nil:
+// while set, progress goes to the profile instead of history and AniList
+func (a *App) setCoWatch(profile *database.CoWatchProfile) {
+	a.coWatch = profile
+	name := ""
+	if profile != nil {
+		name = profile.Name
+	}
+	a.home.SetCoWatch(name)
+}
+
+// playingAniListID returns the AniList ID of the playing media, or nil
+func (a *App) playingAniListID() *int {
+	if a.watchingFromAniList && a.currentAniListID > 0 {
+		id := a.currentAniListID
+		return &id
+	}
+	return nil
+}
+
+// saveCoWatchProgress saves playback progress to the co-watch profile
+func (a *App) saveCoWatchProgress(progress *player.PlaybackProgress) {
+	if a.db == nil || a.currentEpisodeID == "" || a.currentPlaybackProvider == "" {
+		return
+	}
+
+	anilistID := a.playingAniListID()
+	mediaID, mediaTitle, mediaType := a.playbackMedia(anilistID, a.currentEpisodeNumber)
+	entry := database.CoWatchProgress{
+		ProfileID:       a.coWatch.ID,
+		MediaID:         mediaID,
+		MediaTitle:      mediaTitle,
+		MediaType:       mediaType,
+		AniListID:       anilistID,
+		ProviderName:    a.currentPlaybackProvider,
+		Season:          a.currentSeasonNumber,
+		Episode:         a.currentEpisodeNumber,
+		ProgressSeconds: int(progress.CurrentTime.Seconds()),
+		TotalSeconds:    int(progress.Duration.Seconds()),
+		ProgressPercent: progress.Percentage,
+		Completed:       a.completionRule().Completed(*progress),
+		WatchedAt:       time.Now(),
+	}
+
+	a.debugLog("saveCoWatchProgress: profile=%s, mediaID=%s, episode=%d, completed=%v",
+		a.coWatch.Name, mediaID, entry.Episode, entry.Completed)
+	if err := database.SaveCoWatchProgress(a.db, entry); err != nil {
+		a.logger.Error("co-watch save failed", "profile", a.coWatch.Name, "error", err)
+		a.err = fmt.Errorf("failed to save co-watch progress: %v", err)
+	}
+}
+
+// coWatchResume returns the position to resume an episode at with the
+// co-watch profile
+func (a *App) coWatchResume(episode int) (int, error) {
+	mediaID, _, _ := a.playbackMedia(a.playingAniListID(), episode)
+	entry, err := database.CoWatchResume(a.db, a.coWatch.ID, mediaID, a.currentSeasonNumber, episode)
+	if err != nil || entry == nil || entry.Completed {
+		return 0, err
+	}
+	return entry.ProgressSeconds, nil
+}
+
+// coWatchNextEpisode returns the episode to play next with the co-watch
+// profile, starting at the first one
+func (a *App) coWatchNextEpisode() int {
+	mediaID, _, _ := a.playbackMedia(a.playingAniListID(), 0)
+	next, err := database.CoWatchNextEpisode(a.db, a.coWatch.ID, mediaID)
+	if err != nil {
+		a.logger.Warn("failed to load co-watch progress", "profile", a.coWatch.Name, "error", err)
+	}
+	return max(next, 1)
+}
+
+// handleCycleCoWatchMsg switches between watching solo and each co-watch
+// profile in turn
+func (a *App) handleCycleCoWatchMsg() (tea.Model, tea.Cmd) {
+	if a.db == nil {
+		return a, nil
+	}
+	profiles, err := database.ListCoWatchProfiles(a.db)
+	if err != nil {
+		a.logger.Warn("failed to list co-watch profiles", "error", err)
+	}
+
+	switch {
+	case len(profiles) == 0:
+		a.statusMsg = i18n.T("No co-watch profiles (add one with 'greg cowatch add <name>')")
+	default:
+		var next *database.CoWatchProfile
+		if a.coWatch == nil {
+			next = &profiles[0]
+		} else {
+			for i, p := range profiles {
+				if p.ID == a.coWatch.ID && i+1 < len(profiles) {
+					next = &profiles[i+1]
+					break
+				}
+			}
+		}
+		a.setCoWatch(next)
+		if next == nil {
+			a.statusMsg = i18n.T("Watching solo")
+		} else {
+			a.statusMsg = i18n.T("Watching with %s: progress is kept apart from your history and AniList", next.Name)
+		}
+	}
+	a.statusMsgTime = time.Now()
+	return a, func() tea.Msg {
+		time.Sleep(3 * time.Second)
+		return clearStatusMsg{}
+	}
+}
+
+// resolveCoWatch looks up the co-watch profile given on the command line
+func (a *App) resolveCoWatch(name string) {
+	if a.db == nil || strings.TrimSpace(name) == "" {
+		return
+	}
+	profile, err := database.FindCoWatchProfile(a.db, name)
+	if err != nil || profile == nil {
+		a.logger.Warn("unknown co-watch profile", "name", name, "error", err)
+		return
+	}
+	a.setCoWatch(profile)
+}
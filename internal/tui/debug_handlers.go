@@ -13,19 +13,21 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
-	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
 )
 
+func init() {
+	handle((*App).handleGenerateDebugInfoMsg)
+	handle((*App).handleDebugSourcesLoadedMsg)
+	handle((*App).handleDebugComparisonMsg)
+}
+
 // isDebugMode checks if debug mode is enabled in the config
 func (a *App) isDebugMode() bool {
-	if a.cfg == nil {
-		return false
-	}
-	cfg, ok := a.cfg.(*config.Config)
-	if !ok {
+	cfg := a.config()
+	if cfg == nil {
 		return false
 	}
 	return cfg.Advanced.Debug || a.inDebugLinksMode
@@ -77,45 +79,10 @@ func (a *App) generateMediaDebugInfo(mediaID string, title string, mediaType str
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		// Try to get episode ID for movie
-		var episodeID string
-
-		// Check if provider supports direct movie ID
-		type movieEpisodeIDGetter interface {
-			GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-		}
-
-		if getter, ok := provider.(movieEpisodeIDGetter); ok {
-			id, err := getter.GetMovieEpisodeID(ctx, mediaID)
-			if err == nil {
-				episodeID = id
-			}
-		}
-
-		// If not found, try getting seasons/episodes
-		if episodeID == "" {
-			seasons, err := provider.GetSeasons(ctx, mediaID)
-			if err == nil && len(seasons) > 0 {
-				episodes, err := provider.GetEpisodes(ctx, seasons[0].ID)
-				if err == nil && len(episodes) > 0 {
-					episodeID = episodes[0].ID
-				}
-			} else {
-				// Try getting media details directly (some providers return episodes in details)
-				details, err := provider.GetMediaDetails(ctx, mediaID)
-				if err == nil && len(details.Seasons) > 0 {
-					// Try first season from details
-					episodes, err := provider.GetEpisodes(ctx, details.Seasons[0].ID)
-					if err == nil && len(episodes) > 0 {
-						episodeID = episodes[0].ID
-					}
-				}
-			}
-		}
-
-		if episodeID == "" {
+		episodeID, err := providers.MovieEpisodeID(ctx, provider, mediaID)
+		if err != nil {
 			return common.DebugSourcesLoadedMsg{
-				Error: fmt.Errorf("could not determine episode ID for media"),
+				Error: fmt.Errorf("could not determine episode ID for media: %w", err),
 			}
 		}
 
@@ -125,7 +92,11 @@ func (a *App) generateMediaDebugInfo(mediaID string, title string, mediaType str
 		// Let's duplicate the logic for now to avoid complex refactoring, or extract a helper.
 		// Extracting helper is better.
 
-		return a.fetchDebugInfo(ctx, provider, episodeID, 0, title)
+		msg := a.fetchDebugInfo(ctx, provider, episodeID, 0, title)
+		if msg.Info != nil {
+			msg.Info.MediaTitle = title
+		}
+		return msg
 	}
 }
 
@@ -182,6 +153,8 @@ func (a *App) fetchDebugInfo(ctx context.Context, provider providers.Provider, e
 
 // generateDebugInfo fetches all source links for an episode
 func (a *App) generateDebugInfo(episodeID string, episodeNumber int, episodeTitle string) tea.Cmd {
+	mediaTitle := a.selectedMedia.Title
+	season := a.currentSeasonNumber
 	return func() tea.Msg {
 		provider, ok := a.providers[a.currentMediaType]
 		if !ok {
@@ -193,8 +166,49 @@ func (a *App) generateDebugInfo(episodeID string, episodeNumber int, episodeTitl
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		return a.fetchDebugInfo(ctx, provider, episodeID, episodeNumber, episodeTitle)
+		msg := a.fetchDebugInfo(ctx, provider, episodeID, episodeNumber, episodeTitle)
+		if msg.Info != nil {
+			msg.Info.MediaTitle = mediaTitle
+			msg.Info.Season = season
+		}
+		return msg
+	}
+}
+
+// debugComparisonMsg carries the cross-provider comparison of the episode
+// in the debug popup
+type debugComparisonMsg struct {
+	comparisons []providers.Comparison
+}
+
+// compareDebugProviders resolves the episode of the debug popup on every
+// provider of the same type
+func (a *App) compareDebugProviders(info *common.DebugSourcesInfo) tea.Cmd {
+	mediaType := a.currentMediaType
+	if p, err := providers.Get(info.ProviderName); err == nil {
+		mediaType = p.Type()
+	}
+	title, season, episode := info.MediaTitle, info.Season, info.EpisodeNumber
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		comparisons := providers.CompareProviders(ctx, providers.GetByType(mediaType), title, season, episode)
+		providers.RankComparisons(comparisons)
+		return debugComparisonMsg{comparisons: comparisons}
+	}
+}
+
+// handleDebugComparisonMsg shows a finished comparison, unless the popup
+// was closed meanwhile
+func (a *App) handleDebugComparisonMsg(msg debugComparisonMsg) (tea.Model, tea.Cmd) {
+	if a.debugSourcesInfo == nil || !a.debugSourcesInfo.Comparing {
+		return a, nil
 	}
+	a.debugSourcesInfo.Comparing = false
+	a.debugSourcesInfo.Comparisons = msg.comparisons
+	return a, nil
 }
 
 // handleDebugPopupInput handles key input when the Debug popup is visible
@@ -299,6 +313,13 @@ func (a *App) handleDebugPopupInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 			return a, a.copyToClipboardWithNotification(jsonBuilder.String(), "Debug info JSON")
 		}
+	case "p":
+		// Compare the episode across providers
+		if a.debugSourcesInfo != nil && !a.debugSourcesInfo.Comparing && a.debugSourcesInfo.MediaTitle != "" {
+			a.debugSourcesInfo.Comparing = true
+			a.debugSourcesInfo.Comparisons = nil
+			return a, a.compareDebugProviders(a.debugSourcesInfo)
+		}
 	case "c":
 		// Copy all sources to clipboard (legacy/text format)
 		if a.debugSourcesInfo != nil {
@@ -396,11 +417,19 @@ func (a *App) renderDebugPopup() string {
 		}
 	}
 
+	if a.debugSourcesInfo.Comparing {
+		content = append(content, "", "Comparing providers...")
+	} else if len(a.debugSourcesInfo.Comparisons) > 0 {
+		content = append(content, "", "Provider comparison:")
+		content = append(content, renderComparisons(a.debugSourcesInfo.Comparisons)...)
+	}
+
 	content = append(content, "", "Keybinds:",
 		"  enter/u - Copy selected URL",
 		"  m - Copy MPV command",
 		"  J - Copy JSON",
 		"  c - Copy all text",
+		"  p - Compare providers",
 		"  q - Close popup")
 
 	contentStr := strings.Join(content, "\n")
@@ -418,3 +447,26 @@ func (a *App) renderDebugPopup() string {
 
 	return popupContent
 }
+
+// renderComparisons renders a provider comparison as table rows
+func renderComparisons(comparisons []providers.Comparison) []string {
+	rows := []string{fmt.Sprintf("  %-12s %-6s %-8s %-7s %-5s %s", "Provider", "Best", "Latency", "Sources", "Subs", "Audio")}
+	for _, c := range comparisons {
+		latency := c.Latency.Round(10 * time.Millisecond).String()
+		if c.Error != "" {
+			rows = append(rows, lipgloss.NewStyle().Foreground(styles.OxocarbonRed).Render(
+				fmt.Sprintf("  %-12s %s", c.Provider, c.Error)))
+			continue
+		}
+		best := string(c.Best())
+		if best == "" {
+			best = "?"
+		}
+		audio := strings.Join(c.Audio, ", ")
+		if audio == "" {
+			audio = "-"
+		}
+		rows = append(rows, fmt.Sprintf("  %-12s %-6s %-8s %-7d %-5d %s", c.Provider, best, latency, c.Sources, len(c.Subtitles), audio))
+	}
+	return rows
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+// ConfigProvider supplies the configuration the TUI reads. *config.Config is
+// one.
+type ConfigProvider interface {
+	Config() *config.Config
+}
+
+// ProgressTracker is what the TUI needs of the tracker manager: the AniList
+// client, progress updates and the offline sync queue. *tracker.Manager is
+// one.
+type ProgressTracker interface {
+	GetAniList() tracker.Tracker
+	IsAniListEnabled() bool
+	IsAniListAuthenticated() bool
+	UpdateProgress(ctx context.Context, mediaID string, episode int, progress float64) error
+	ProcessSyncQueue(ctx context.Context, force bool) (synced, failed int, err error)
+	PendingSyncs() (int64, error)
+	SearchMedia(ctx context.Context, query string, mediaType providers.MediaType) ([]tracker.TrackedMedia, error)
+	GetUserLibrary(ctx context.Context, mediaType providers.MediaType) ([]tracker.TrackedMedia, error)
+}
+
+// MappingStore keeps which provider media each AniList entry plays from.
+// *mapping.Manager is one.
+type MappingStore interface {
+	GetMapping(ctx context.Context, anilistID int) (*mapping.ProviderMapping, error)
+	GetOrCreateMapping(ctx context.Context, anilistID int, title string, mediaType providers.MediaType) (*mapping.ProviderMapping, []providers.Media, string, error)
+	SelectMapping(ctx context.Context, anilistID int, providerName string, selected providers.Media) error
+	SetEpisodeOffset(ctx context.Context, anilistID, offset int) error
+	SetPreferredProvider(mediaType providers.MediaType, name string)
+	TrashMapping(ctx context.Context, anilistID int) (*database.TrashItem, error)
+}
+
+var (
+	_ ConfigProvider  = (*config.Config)(nil)
+	_ ProgressTracker = (*tracker.Manager)(nil)
+	_ MappingStore    = (*mapping.Manager)(nil)
+)
+
+// config returns the configuration, nil when the TUI runs without one
+func (a *App) config() *config.Config {
+	if a.cfg == nil {
+		return nil
+	}
+	return a.cfg.Config()
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+// detailsFetcher queues result detail requests by priority. Each queued
+// request gets one fetch command that, once it holds a semaphore slot, takes
+// whichever request is most urgent at that moment, so rows near the cursor
+// go first even when they were requested last.
+type detailsFetcher struct {
+	mu      sync.Mutex
+	pending map[string]common.DetailsRequest // Waiting for a semaphore slot
+	running map[string]*runningFetch
+	done    map[string]bool // Fetched since the last reset
+}
+
+// runningFetch is a details request holding a semaphore slot
+type runningFetch struct {
+	cancel context.CancelFunc
+}
+
+func newDetailsFetcher() *detailsFetcher {
+	return &detailsFetcher{
+		pending: make(map[string]common.DetailsRequest),
+		running: make(map[string]*runningFetch),
+		done:    make(map[string]bool),
+	}
+}
+
+// reset cancels everything in flight and forgets fetched media, for a new
+// set of results
+func (f *detailsFetcher) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, run := range f.running {
+		run.cancel()
+	}
+	f.pending = make(map[string]common.DetailsRequest)
+	f.running = make(map[string]*runningFetch)
+	f.done = make(map[string]bool)
+}
+
+// want replaces the wanted set with items. Requests no longer wanted are
+// dropped or cancelled. Returns how many requests were newly queued.
+func (f *detailsFetcher) want(items []common.DetailsRequest) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	wanted := make(map[string]bool, len(items))
+	for _, item := range items {
+		wanted[item.MediaID] = true
+	}
+	for id := range f.pending {
+		if !wanted[id] {
+			delete(f.pending, id)
+		}
+	}
+	for id, run := range f.running {
+		if !wanted[id] {
+			run.cancel()
+			delete(f.running, id)
+		}
+	}
+
+	queued := 0
+	for _, item := range items {
+		if f.done[item.MediaID] || f.running[item.MediaID] != nil {
+			continue
+		}
+		if _, ok := f.pending[item.MediaID]; !ok {
+			queued++
+		}
+		// Refresh priority and index for requests already waiting
+		f.pending[item.MediaID] = item
+	}
+	return queued
+}
+
+// next takes the most urgent pending request and marks it running, bounded
+// by timeout
+func (f *detailsFetcher) next(timeout time.Duration) (common.DetailsRequest, context.Context, *runningFetch, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var best common.DetailsRequest
+	found := false
+	for _, item := range f.pending {
+		if !found || item.Priority < best.Priority || (item.Priority == best.Priority && item.Index < best.Index) {
+			best = item
+			found = true
+		}
+	}
+	if !found {
+		return best, nil, nil, false
+	}
+
+	delete(f.pending, best.MediaID)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	run := &runningFetch{cancel: cancel}
+	f.running[best.MediaID] = run
+	return best, ctx, run, true
+}
+
+// finish marks a request as no longer running. Cancelled requests are not
+// marked done so they are fetched again if their row comes back into view.
+func (f *detailsFetcher) finish(mediaID string, run *runningFetch, cancelled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	run.cancel()
+	// A newer fetch of the same media may have started after this one was cancelled
+	if f.running[mediaID] == run {
+		delete(f.running, mediaID)
+	}
+	if !cancelled {
+		f.done[mediaID] = true
+	}
+}
+
+// fetchNextDetails fetches the most urgent pending details request once a
+// semaphore slot is free
+func (a *App) fetchNextDetails() tea.Cmd {
+	return func() tea.Msg {
+		// Acquire semaphore to limit concurrent fetches
+		a.detailsSem <- struct{}{}
+		defer func() { <-a.detailsSem }()
+
+		provider, hasProvider := a.providers[a.currentMediaType]
+		var providerName string
+		if hasProvider {
+			providerName = provider.Name()
+		}
+
+		item, ctx, run, ok := a.details.next(a.providerTimeout(providerName, config.TimeoutDetails))
+		if !ok {
+			// The request this fetch was queued for has been cancelled
+			return nil
+		}
+
+		if !hasProvider {
+			a.details.finish(item.MediaID, run, false)
+			return common.DetailsLoadedMsg{MediaID: item.MediaID, Err: fmt.Errorf("no provider available"), Index: item.Index}
+		}
+
+		details, err := provider.GetMediaDetails(ctx, item.MediaID)
+		cancelled := errors.Is(ctx.Err(), context.Canceled)
+		a.details.finish(item.MediaID, run, cancelled)
+		if cancelled {
+			return nil
+		}
+		if err != nil {
+			return common.DetailsLoadedMsg{MediaID: item.MediaID, Err: err, Index: item.Index}
+		}
+
+		return common.DetailsLoadedMsg{
+			MediaID: item.MediaID,
+			Media:   details.Media,
+			Index:   item.Index,
+		}
+	}
+}
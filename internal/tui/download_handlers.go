@@ -5,6 +5,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -12,15 +13,25 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
 
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/components/mangadownload"
 	"github.com/justchokingaround/greg/internal/tui/styles"
 )
 
+func init() {
+	handle((*App).handleMediaDownloadMsg)
+	handle((*App).handleEpisodeDownloadMsg)
+	handle((*App).handleBatchDownloadMsg)
+	handle((*App).handleDownloadAddedMsg)
+	handle((*App).handleDownloadRefusedMsg)
+}
+
 // resolveAndDownloadMovie resolves a movie's episode ID and starts the download
 func (a *App) resolveAndDownloadMovie(mediaID string, title string) tea.Cmd {
 	return func() tea.Msg {
@@ -29,41 +40,9 @@ func (a *App) resolveAndDownloadMovie(mediaID string, title string) tea.Cmd {
 			return nil // Should log error
 		}
 
-		// Try to get episode ID using GetMovieEpisodeID interface
-		type movieEpisodeIDGetter interface {
-			GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-		}
-
-		var episodeID string
-		if episodeIDGetter, ok := provider.(movieEpisodeIDGetter); ok {
-			id, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), mediaID)
-			if err == nil {
-				episodeID = id
-			}
-		}
-
-		// If not found via interface, try getting seasons/episodes as fallback
-		if episodeID == "" {
-			// Try getting seasons
-			seasons, err := provider.GetSeasons(context.Background(), mediaID)
-			if err == nil && len(seasons) > 0 {
-				// Get episodes for first season
-				episodes, err := provider.GetEpisodes(context.Background(), seasons[0].ID)
-				if err == nil && len(episodes) > 0 {
-					episodeID = episodes[0].ID
-				}
-			}
-		}
-
-		// If still not found, try GetMediaDetails
-		if episodeID == "" {
-			details, err := provider.GetMediaDetails(context.Background(), mediaID)
-			if err == nil && len(details.Seasons) > 0 {
-				episodes, err := provider.GetEpisodes(context.Background(), details.Seasons[0].ID)
-				if err == nil && len(episodes) > 0 {
-					episodeID = episodes[0].ID
-				}
-			}
+		episodeID, err := providers.MovieEpisodeID(context.Background(), provider, mediaID)
+		if err != nil {
+			a.debugLog("Could not resolve movie %s: %v", mediaID, err)
 		}
 
 		if episodeID == "" {
@@ -85,7 +64,7 @@ func (a *App) resolveAndDownloadMovie(mediaID string, title string) tea.Cmd {
 		// We can just execute the logic here directly.
 
 		// Get stream URL
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
 		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
@@ -112,6 +91,12 @@ func (a *App) resolveAndDownloadMovie(mediaID string, title string) tea.Cmd {
 
 		// Add to download queue
 		if err := a.downloadMgr.AddToQueue(ctx, task); err != nil {
+			if errors.Is(err, downloader.ErrInsufficientSpace) {
+				return downloadRefusedMsg{err: err}
+			}
+			if dup, ok := asDuplicate(err); ok {
+				return downloadDuplicateMsg{dups: []*downloader.DuplicateError{dup}}
+			}
 			return nil
 		}
 
@@ -154,7 +139,7 @@ func (a *App) startDownload(episodeID string, episodeNumber int, episodeTitle st
 		}
 
 		// Get stream URL for the episode/movie
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
 		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
@@ -189,6 +174,12 @@ func (a *App) startDownload(episodeID string, episodeNumber int, episodeTitle st
 		// Add to download queue
 		if err := a.downloadMgr.AddToQueue(ctx, task); err != nil {
 			a.logger.Error("failed to add download to queue", "error", err)
+			if errors.Is(err, downloader.ErrInsufficientSpace) {
+				return downloadRefusedMsg{err: err}
+			}
+			if dup, ok := asDuplicate(err); ok {
+				return downloadDuplicateMsg{dups: []*downloader.DuplicateError{dup}}
+			}
 			return nil
 		}
 
@@ -218,6 +209,88 @@ func (a *App) startDownload(episodeID string, episodeNumber int, episodeTitle st
 	}
 }
 
+// downloadForWatching queues an episode for download, moved to the front of
+// the queue, and waits until enough of it is on disk to play. It returns the
+// stream to play instead: the growing file, the finished one, or the
+// provider's stream itself for downloads that can't be played before they
+// finish.
+func (a *App) downloadForWatching(ctx context.Context, providerName string, stream *providers.StreamURL, episodeNumber int) (*providers.StreamURL, error) {
+	if a.downloadMgr == nil {
+		return nil, fmt.Errorf("downloads are not available")
+	}
+
+	queue, err := a.downloadMgr.GetQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch a download of the episode that is already under way
+	var id string
+	for _, t := range queue {
+		if t.MediaID == a.selectedMedia.ID && t.Episode == episodeNumber && !t.Status.IsComplete() {
+			id = t.ID
+			if t.Status == downloader.StatusPaused {
+				_ = a.downloadMgr.Resume(ctx, id)
+			}
+			break
+		}
+	}
+
+	if id == "" {
+		task := downloader.DownloadTask{
+			ID:         uuid.New().String(),
+			MediaID:    a.selectedMedia.ID,
+			MediaTitle: a.selectedMedia.Title,
+			MediaType:  a.selectedMedia.Type,
+			Episode:    episodeNumber,
+			Quality:    providers.Quality1080p,
+			Provider:   providerName,
+			StreamURL:  stream.URL,
+			StreamType: stream.Type,
+			Headers:    stream.Headers,
+			Referer:    stream.Referer,
+			Subtitles:  stream.Subtitles,
+			EmbedSubs:  true,
+		}
+		if err := a.downloadMgr.AddToQueue(ctx, task); err != nil {
+			if dup, ok := asDuplicate(err); ok && dup.Path != "" {
+				// Already downloaded, play that copy
+				return localStream(stream, dup.Path), nil
+			}
+			return nil, fmt.Errorf("failed to queue download: %w", err)
+		}
+		id = task.ID
+	}
+	_ = a.downloadMgr.MoveInQueue(ctx, id, -len(queue))
+
+	path, growing, err := a.downloadMgr.WaitPlayable(ctx, id)
+	switch {
+	case errors.Is(err, downloader.ErrNotStreamable):
+		a.logger.Info("download can't be played before it finishes, streaming meanwhile", "episode", episodeNumber)
+		return stream, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to download episode: %w", err)
+	case growing:
+		// mpv keeps reading a file that is still being appended to
+		return localStream(stream, "appending://"+path), nil
+	default:
+		return localStream(stream, path), nil
+	}
+}
+
+// localStream returns a copy of stream that plays a downloaded file
+func localStream(stream *providers.StreamURL, path string) *providers.StreamURL {
+	local := *stream
+	local.URL = path
+	local.Headers = nil
+	local.Referer = ""
+	local.Type = providers.StreamTypeMP4
+	if strings.HasSuffix(path, ".mkv") {
+		local.Type = providers.StreamTypeMKV
+	}
+	return &local
+}
+
 // downloadMangaChapters downloads manga chapters and adds them to the download queue
 func (a *App) downloadMangaChapters(provider providers.MangaProvider, episodes []common.EpisodeInfo) {
 	ctx := context.Background()
@@ -318,10 +391,8 @@ func sanitizeFilename(name string) string {
 
 // getDownloadPath returns the download path from config or default
 func (a *App) getDownloadPath() string {
-	if a.cfg != nil {
-		if appCfg, ok := a.cfg.(*config.Config); ok {
-			return appCfg.Downloads.Path
-		}
+	if appCfg := a.config(); appCfg != nil {
+		return appCfg.Downloads.Path
 	}
 	// Default to current directory
 	return "."
@@ -385,6 +456,10 @@ func (a *App) handleMediaDownloadMsg(msg common.MediaDownloadMsg) (*App, tea.Cmd
 
 // handleEpisodeDownloadMsg handles download request for an episode
 func (a *App) handleEpisodeDownloadMsg(msg common.EpisodeDownloadMsg) (*App, tea.Cmd) {
+	if cmd, unaired := a.refuseUnaired(msg.Number); unaired {
+		return a, cmd
+	}
+
 	var cmds []tea.Cmd
 	// Handle download request
 	if a.downloadMgr != nil {
@@ -397,6 +472,7 @@ func (a *App) handleEpisodeDownloadMsg(msg common.EpisodeDownloadMsg) (*App, tea
 // handleBatchDownloadMsg handles batch download request
 func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
+	msg.Episodes = a.airedOnly(msg.Episodes)
 	// Handle batch download request
 	if a.currentMediaType == providers.MediaTypeManga && len(msg.Episodes) > 0 {
 		// Manga batch download - show progress UI
@@ -414,12 +490,18 @@ func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd
 
 		return a, a.mangaDownloadComponent.Init()
 	} else if a.downloadMgr != nil {
+		quality := msg.Quality
+		if quality == "" {
+			quality = providers.Quality1080p
+		}
+
 		// Video downloads - queue them asynchronously
 		// Don't create individual commands - they timeout when batched
 		go func() {
 			episodeList := msg.Episodes
 			successCount := 0
-			a.logger.Info("batch download started", "count", len(episodeList))
+			var duplicates []*downloader.DuplicateError
+			a.logger.Info("batch download started", "count", len(episodeList), "quality", quality)
 
 			for i, ep := range episodeList {
 				a.logger.Info("processing episode for download", "index", i+1, "episode", ep.Number, "id", ep.EpisodeID)
@@ -435,8 +517,8 @@ func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd
 				var stream *providers.StreamURL
 				var err error
 				for attempt := 1; attempt <= 2; attempt++ {
-					ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
-					stream, err = provider.GetStreamURL(ctx, ep.EpisodeID, providers.Quality1080p)
+					ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutStream))
+					stream, err = provider.GetStreamURL(ctx, ep.EpisodeID, quality)
 					cancel()
 
 					if err == nil {
@@ -464,7 +546,7 @@ func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd
 					MediaType:  a.selectedMedia.Type,
 					Episode:    ep.Number,
 					Season:     0,
-					Quality:    providers.Quality1080p,
+					Quality:    quality,
 					Provider:   provider.Name(),
 					StreamURL:  stream.URL,
 					StreamType: stream.Type,
@@ -477,8 +559,14 @@ func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd
 				// Add to queue
 				if err := a.downloadMgr.AddToQueue(context.Background(), task); err != nil {
 					a.logger.Error("failed to add to download queue", "episode", ep.Number, "error", err)
+					if errors.Is(err, downloader.ErrInsufficientSpace) {
+						// The rest won't fit either; the downloads view shows the warning
+						break
+					}
 					// Check if it's a duplicate
-					if strings.Contains(err.Error(), "already in queue") {
+					if dup, ok := asDuplicate(err); ok {
+						duplicates = append(duplicates, dup)
+					} else if strings.Contains(err.Error(), "already in queue") {
 						a.logger.Info("skipping duplicate episode", "episode", ep.Number)
 					}
 				} else {
@@ -493,6 +581,11 @@ func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd
 			}
 
 			a.logger.Info("batch download queuing complete", "total_attempted", len(episodeList), "total_added", successCount, "total_skipped", len(episodeList)-successCount)
+
+			// Ask once about every already downloaded episode of the batch
+			if len(duplicates) > 0 {
+				a.msgChan <- downloadDuplicateMsg{dups: duplicates}
+			}
 		}()
 
 		// Switch to downloads view immediately
@@ -503,6 +596,22 @@ func (a *App) handleBatchDownloadMsg(msg common.BatchDownloadMsg) (*App, tea.Cmd
 	return a, tea.Batch(cmds...)
 }
 
+// downloadRefusedMsg reports a download that was not queued because the
+// disk is too full
+type downloadRefusedMsg struct {
+	err error
+}
+
+// handleDownloadRefusedMsg shows why a download was not queued
+func (a *App) handleDownloadRefusedMsg(msg downloadRefusedMsg) (*App, tea.Cmd) {
+	a.statusMsg = i18n.T("✗ Download not queued: %v", msg.err)
+	a.statusMsgTime = time.Now()
+	return a, func() tea.Msg {
+		time.Sleep(5 * time.Second)
+		return clearStatusMsg{}
+	}
+}
+
 // handleDownloadAddedMsg handles download added notification
 func (a *App) handleDownloadAddedMsg(msg common.DownloadAddedMsg) (*App, tea.Cmd) {
 	// Silently add download - no notification needed
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/resolve"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle((*App).handleRetryFromOtherProviderMsg)
+	handle((*App).handleDownloadRequeuedMsg)
+}
+
+// downloadRequeuedMsg reports a failed download queued again from another
+// provider
+type downloadRequeuedMsg struct {
+	label    string
+	provider string
+	err      error
+}
+
+// handleRetryFromOtherProviderMsg looks for the episode of a failed
+// download on the other providers in the background
+func (a *App) handleRetryFromOtherProviderMsg(msg common.RetryFromOtherProviderMsg) (tea.Model, tea.Cmd) {
+	if a.downloadMgr == nil {
+		return a, nil
+	}
+	manager := a.downloadMgr
+	// Searching the other providers, then resolving the episode on one
+	timeout := a.providerTimeout("", config.TimeoutSearch, config.TimeoutSearch, config.TimeoutDetails, config.TimeoutStream)
+
+	cmd := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		queue, err := manager.GetQueue(ctx)
+		if err != nil {
+			return downloadRequeuedMsg{label: "download", err: err}
+		}
+		var task *downloader.DownloadTask
+		for i := range queue {
+			if queue[i].ID == msg.TaskID {
+				task = &queue[i]
+				break
+			}
+		}
+		if task == nil {
+			return downloadRequeuedMsg{label: "download", err: fmt.Errorf("download not found")}
+		}
+
+		label := downloadLabel(*task)
+		source, err := otherProviderSource(ctx, *task)
+		if err == nil {
+			err = manager.Requeue(ctx, task.ID, *source)
+		}
+		if err != nil {
+			return downloadRequeuedMsg{label: label, err: err}
+		}
+		return downloadRequeuedMsg{label: label, provider: source.Provider}
+	}
+	return a, tea.Batch(cmd, a.toast(severityInfo, i18n.T("Looking for the episode on other providers...")))
+}
+
+// handleDownloadRequeuedMsg says where a failed download was queued from
+func (a *App) handleDownloadRequeuedMsg(msg downloadRequeuedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.logger.Warn("failed to requeue download from another provider", "download", msg.label, "error", msg.err)
+		return a, a.toast(severityError, i18n.T("Couldn't requeue %s: %v", msg.label, msg.err))
+	}
+	cmds := []tea.Cmd{a.toast(severitySuccess, i18n.T("Requeued %s from %s", msg.label, msg.provider))}
+	if a.state == downloadsView {
+		cmds = append(cmds, a.downloadsComponent.Refresh())
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// otherProviderSource resolves the episode of a failed download on the first
+// other provider of its type that has the same title, and returns the
+// download task it would be fetched from
+func otherProviderSource(ctx context.Context, task downloader.DownloadTask) (*downloader.DownloadTask, error) {
+	mediaType := task.MediaType
+	if current, err := providers.Get(task.Provider); err == nil {
+		mediaType = current.Type()
+	} else if mediaType == providers.MediaTypeMovie || mediaType == providers.MediaTypeTV {
+		mediaType = providers.MediaTypeMovieTV
+	}
+
+	lastErr := fmt.Errorf("no other provider has %s", task.MediaTitle)
+	for _, p := range providers.GetByType(mediaType) {
+		if p.Name() == task.Provider {
+			continue
+		}
+		media, err := providers.FindMedia(ctx, p, task.MediaTitle)
+		if err != nil {
+			continue
+		}
+
+		res, err := resolve.EpisodeStream(ctx, p,
+			resolve.MediaRef{ID: media.ID, Title: media.Title, Type: task.MediaType},
+			resolve.EpisodeSelector{Season: task.Season, Episode: task.Episode},
+			resolve.Prefs{Quality: task.Quality})
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", p.Name(), err)
+			continue
+		}
+		return &downloader.DownloadTask{
+			MediaID:    media.ID,
+			Provider:   p.Name(),
+			StreamURL:  res.Stream.URL,
+			StreamType: res.Stream.Type,
+			Headers:    res.Stream.Headers,
+			Referer:    res.Stream.Referer,
+			Subtitles:  res.Stream.Subtitles,
+			EmbedSubs:  true,
+		}, nil
+	}
+	return nil, lastErr
+}
+
+// downloadLabel names a download's episode for messages
+func downloadLabel(task downloader.DownloadTask) string {
+	if task.Episode > 0 {
+		return fmt.Sprintf("%s - Episode %d", task.MediaTitle, task.Episode)
+	}
+	return task.MediaTitle
+}
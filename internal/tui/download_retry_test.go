@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	fakeprovider "github.com/justchokingaround/greg/internal/providers/fake"
+)
+
+func TestOtherProviderSource(t *testing.T) {
+	for _, p := range []providers.Provider{
+		fakeprovider.New("retry-failed", providers.MediaTypeAnime, frieren),
+		fakeprovider.New("retry-other", providers.MediaTypeAnime, frieren),
+	} {
+		require.NoError(t, providers.Register(p))
+		t.Cleanup(func() { _ = providers.Unregister(p.Name()) })
+	}
+
+	task := downloader.DownloadTask{
+		MediaID:    "frieren",
+		MediaTitle: "Frieren",
+		MediaType:  providers.MediaTypeAnime,
+		Provider:   "retry-failed",
+		Season:     1,
+		Episode:    3,
+		Quality:    providers.Quality1080p,
+	}
+	source, err := otherProviderSource(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "retry-other", source.Provider)
+	assert.Equal(t, "frieren", source.MediaID)
+	assert.Equal(t, fakeprovider.StreamURL(fakeprovider.EpisodeID("frieren", 1, 3), providers.Quality1080p), source.StreamURL)
+
+	// The failed provider is never the way out
+	_ = providers.Unregister("retry-other")
+	_, err = otherProviderSource(context.Background(), task)
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+func init() {
+	handle((*App).handleDownloadDuplicateMsg)
+}
+
+// downloadDuplicateMsg reports episodes that weren't queued because they
+// were already downloaded
+type downloadDuplicateMsg struct {
+	dups []*downloader.DuplicateError
+}
+
+// asDuplicate returns the duplicate an AddToQueue error reports, if any
+func asDuplicate(err error) (*downloader.DuplicateError, bool) {
+	var dup *downloader.DuplicateError
+	return dup, errors.As(err, &dup)
+}
+
+// handleDownloadDuplicateMsg asks what to do with already downloaded
+// episodes; more arriving while the prompt is open join it
+func (a *App) handleDownloadDuplicateMsg(msg downloadDuplicateMsg) (*App, tea.Cmd) {
+	a.pendingDuplicates = append(a.pendingDuplicates, msg.dups...)
+	return a, nil
+}
+
+// handleDuplicatePromptKeys handles the skip/overwrite/keep both prompt; the
+// choice applies to every episode in it
+func (a *App) handleDuplicatePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var action downloader.DuplicateAction
+	switch msg.String() {
+	case "s", "S", "n", "esc":
+		a.pendingDuplicates = nil
+		return a, nil
+	case "o", "O":
+		action = downloader.DuplicateOverwrite
+	case "k", "K":
+		action = downloader.DuplicateKeepBoth
+	case "ctrl+c":
+		return a, tea.Quit
+	default:
+		return a, nil
+	}
+
+	dups := a.pendingDuplicates
+	a.pendingDuplicates = nil
+	return a, a.requeueDuplicates(dups, action)
+}
+
+// requeueDuplicates queues the episodes of the prompt again with the user's
+// choice
+func (a *App) requeueDuplicates(dups []*downloader.DuplicateError, action downloader.DuplicateAction) tea.Cmd {
+	if a.downloadMgr == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		queued := 0
+		for _, dup := range dups {
+			task := dup.Task
+			task.OnDuplicate = action
+			if err := a.downloadMgr.AddToQueue(context.Background(), task); err != nil {
+				a.logger.Error("failed to queue duplicate download", "title", task.MediaTitle, "episode", task.Episode, "error", err)
+				if errors.Is(err, downloader.ErrInsufficientSpace) {
+					return downloadRefusedMsg{err: err}
+				}
+				continue
+			}
+			queued++
+		}
+		a.logger.Info("queued already downloaded episodes", "action", action, "queued", queued)
+		return nil
+	}
+}
+
+// renderDuplicatePrompt renders the prompt for already downloaded episodes
+func (a *App) renderDuplicatePrompt() string {
+	dups := a.pendingDuplicates
+	first := dups[0]
+
+	var body string
+	if len(dups) == 1 {
+		body = i18n.T("%s is already downloaded:\n%s",
+			first.Task.MediaTitle+episodeSuffix(first.Task.Episode), filepath.Base(first.Path))
+	} else {
+		body = i18n.T("%d episodes of %s are already downloaded",
+			len(dups), first.Task.MediaTitle)
+	}
+	return styles.PopupStyle.Render(body + "\n\n" +
+		i18n.T("[s] Skip\n[o] Overwrite (the old copy goes to the trash)\n[k] Keep both"))
+}
+
+// episodeSuffix is " - Episode N", or nothing for movies
+func episodeSuffix(episode int) string {
+	if episode <= 0 {
+		return ""
+	}
+	return i18n.T(" - Episode %d", episode)
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/metadata/tmdb"
+	"github.com/justchokingaround/greg/internal/providers"
+	trackeranilist "github.com/justchokingaround/greg/internal/tracker/anilist"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle((*App).handleRequestEpisodeDetailMsg)
+	handle((*App).handleEpisodeDetailLoadedMsg)
+}
+
+// streamingEpisodesLookup is implemented by tracker clients that list an
+// anime's episodes from official streaming sites
+type streamingEpisodesLookup interface {
+	GetStreamingEpisodes(ctx context.Context, id int) ([]trackeranilist.StreamingEpisode, error)
+}
+
+// episodeMetadata remembers the TMDB seasons and AniList episode lists looked
+// up for the detail pane, so moving the cursor fetches each once
+type episodeMetadata struct {
+	mu      sync.Mutex
+	seasons map[string][]tmdb.Episode                 // By title, year and season
+	anilist map[int][]trackeranilist.StreamingEpisode // By AniList ID
+}
+
+func newEpisodeMetadata() *episodeMetadata {
+	return &episodeMetadata{
+		seasons: make(map[string][]tmdb.Episode),
+		anilist: make(map[int][]trackeranilist.StreamingEpisode),
+	}
+}
+
+// handleRequestEpisodeDetailMsg fetches the details of the episode shown in
+// the detail pane
+func (a *App) handleRequestEpisodeDetailMsg(msg common.RequestEpisodeDetailMsg) (tea.Model, tea.Cmd) {
+	provider := a.providers[a.currentMediaType]
+	media := a.selectedMedia
+	season := a.currentSeasonNumber
+	anilistID := a.currentAniListID
+	cfg := a.config()
+
+	return a, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		episode := a.fetchEpisodeDetail(ctx, cfg, provider, media, season, anilistID, msg.Episode)
+
+		var thumbnail string
+		if cfg != nil && cfg.UI.PreviewImages && episode.ThumbnailURL != "" && msg.ThumbWidth > 0 {
+			rendered, err := a.renderThumbnail(ctx, episode.ThumbnailURL, msg.ThumbWidth, msg.ThumbHeight)
+			if err != nil {
+				a.debugLog("Failed to render thumbnail of episode %d: %v", episode.Number, err)
+			}
+			thumbnail = rendered
+		}
+		return common.EpisodeDetailLoadedMsg{Episode: episode, Thumbnail: thumbnail}
+	}
+}
+
+// fetchEpisodeDetail fills in the fields of an episode its provider's episode
+// list left empty, asking the provider first, then TMDB for shows, then
+// AniList for anime
+func (a *App) fetchEpisodeDetail(ctx context.Context, cfg *config.Config, provider providers.Provider, media providers.Media, season, anilistID int, episode providers.Episode) providers.Episode {
+	if detailer, ok := providers.As[providers.EpisodeDetailer](provider); ok && !episodeComplete(episode) {
+		detail, err := detailer.GetEpisodeDetail(ctx, media.ID, episode.ID)
+		if err != nil {
+			a.debugLog("Provider episode details failed for %s: %v", episode.ID, err)
+		} else if detail != nil {
+			fillEpisode(&episode, *detail)
+		}
+	}
+
+	if media.Type == providers.MediaTypeTV && cfg != nil && cfg.Metadata.TMDBAPIKey != "" && !episodeComplete(episode) {
+		seasonEpisodes, err := a.episodeMeta.tmdbSeason(ctx, cfg.Metadata.TMDBAPIKey, media, season)
+		if err != nil {
+			a.debugLog("TMDB: season %d lookup failed for %s: %v", season, media.Title, err)
+		}
+		for _, ep := range seasonEpisodes {
+			if ep.Number == episode.Number {
+				fillEpisode(&episode, providers.Episode{
+					Title:        ep.Title,
+					Synopsis:     ep.Overview,
+					ReleaseDate:  ep.AirDate,
+					Duration:     ep.Runtime,
+					ThumbnailURL: ep.Still,
+				})
+			}
+		}
+	}
+
+	if media.Type == providers.MediaTypeAnime && anilistID > 0 && episode.ThumbnailURL == "" {
+		var lookup streamingEpisodesLookup
+		if mgr := a.trackerMgr; mgr != nil {
+			lookup, _ = mgr.GetAniList().(streamingEpisodesLookup)
+		}
+		if lookup == nil {
+			lookup = trackeranilist.NewClient(trackeranilist.Config{})
+		}
+		streaming, err := a.episodeMeta.streamingEpisodes(ctx, lookup, anilistID)
+		if err != nil {
+			a.debugLog("AniList: streaming episodes lookup failed for %d: %v", anilistID, err)
+		}
+		for _, ep := range streaming {
+			if ep.Number == episode.Number {
+				fillEpisode(&episode, providers.Episode{Title: ep.Title, ThumbnailURL: ep.Thumbnail})
+				break
+			}
+		}
+	}
+	return episode
+}
+
+// episodeComplete reports whether an episode has everything the detail pane shows
+func episodeComplete(episode providers.Episode) bool {
+	return episode.Synopsis != "" && !episode.ReleaseDate.IsZero() && episode.Duration > 0 && episode.ThumbnailURL != ""
+}
+
+// fillEpisode copies the fields of from that episode has empty, and from's
+// title over a placeholder one
+func fillEpisode(episode *providers.Episode, from providers.Episode) {
+	if isPlaceholderTitle(*episode) && from.Title != "" {
+		episode.Title = from.Title
+	}
+	if episode.Synopsis == "" {
+		episode.Synopsis = from.Synopsis
+	}
+	if episode.ReleaseDate.IsZero() {
+		episode.ReleaseDate = from.ReleaseDate
+	}
+	if episode.Duration == 0 {
+		episode.Duration = from.Duration
+	}
+	if episode.ThumbnailURL == "" {
+		episode.ThumbnailURL = from.ThumbnailURL
+	}
+}
+
+// tmdbSeason returns the TMDB episodes of a show's season, looked up once
+func (m *episodeMetadata) tmdbSeason(ctx context.Context, apiKey string, media providers.Media, season int) ([]tmdb.Episode, error) {
+	if season <= 0 {
+		season = 1
+	}
+	key := media.Title + "|" + strconv.Itoa(media.Year) + "|" + strconv.Itoa(season)
+
+	m.mu.Lock()
+	episodes, ok := m.seasons[key]
+	m.mu.Unlock()
+	if ok {
+		return episodes, nil
+	}
+
+	client := tmdb.NewClient(apiKey)
+	showID, err := client.SearchShow(ctx, media.Title, media.Year)
+	if err != nil {
+		return nil, err
+	}
+	episodes, err = client.GetSeason(ctx, showID, season)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.seasons[key] = episodes
+	m.mu.Unlock()
+	return episodes, nil
+}
+
+// streamingEpisodes returns the AniList streaming episodes of an anime,
+// looked up once
+func (m *episodeMetadata) streamingEpisodes(ctx context.Context, lookup streamingEpisodesLookup, id int) ([]trackeranilist.StreamingEpisode, error) {
+	m.mu.Lock()
+	episodes, ok := m.anilist[id]
+	m.mu.Unlock()
+	if ok {
+		return episodes, nil
+	}
+
+	episodes, err := lookup.GetStreamingEpisodes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.anilist[id] = episodes
+	m.mu.Unlock()
+	return episodes, nil
+}
+
+// renderThumbnail renders an image as text with chafa, width x height cells.
+// Symbols are used whatever ui.manga_method says, as sixel and kitty images
+// can't be laid out next to other text.
+func (a *App) renderThumbnail(ctx context.Context, url string, width, height int) (string, error) {
+	if _, err := exec.LookPath("chafa"); err != nil {
+		return "", nil
+	}
+
+	path, cleanup, err := a.fetchImage(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	cmd := exec.CommandContext(ctx, "chafa",
+		"-f", "symbols",
+		"-s", fmt.Sprintf("%dx%d", width, height),
+		"--animate", "off",
+		path,
+	)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("chafa failed: %w", err)
+	}
+	return strings.Trim(string(output), "\n"), nil
+}
+
+// fetchImage returns a local path for an image, from the image cache when
+// there is one or a temp file otherwise. cleanup removes the temp file.
+func (a *App) fetchImage(ctx context.Context, url string) (string, func(), error) {
+	if a.images != nil {
+		path, err := a.images.Get(ctx, url)
+		if err != nil {
+			return "", nil, err
+		}
+		return path, func() {}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	tmpFile, err := os.CreateTemp("", "greg-thumb-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	cleanup := func() { _ = os.Remove(tmpFile.Name()) }
+	_, err = io.Copy(tmpFile, resp.Body)
+	_ = tmpFile.Close()
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to save image: %w", err)
+	}
+	return tmpFile.Name(), cleanup, nil
+}
+
+// handleEpisodeDetailLoadedMsg shows fetched episode details in the detail pane
+func (a *App) handleEpisodeDetailLoadedMsg(msg common.EpisodeDetailLoadedMsg) (tea.Model, tea.Cmd) {
+	// Keep the filled-in fields for playback and downloads too
+	for i := range a.episodes {
+		if a.episodes[i].ID == msg.Episode.ID {
+			a.episodes[i] = msg.Episode
+			break
+		}
+	}
+	a.episodesComponent.SetEpisodeDetail(msg.Episode, msg.Thumbnail)
+	return a, nil
+}
@@ -0,0 +1,197 @@
+package tui
+
+// This file contains the error view: guidance and next actions for
+// classified provider errors. All methods remain on the App struct.
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/browser"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+func init() {
+	handle((*App).handleErrorRetryMsg)
+}
+
+const (
+	// defaultRateLimitRetry is how long to wait when a rate-limited site
+	// doesn't say
+	defaultRateLimitRetry = 30 * time.Second
+	// maxAutoRetryDelay is the longest wait retried automatically
+	maxAutoRetryDelay = 5 * time.Minute
+)
+
+// errorRetryMsg retries the failed operation of the error shown as seq
+type errorRetryMsg struct {
+	seq int
+}
+
+// providerError returns the classified provider error shown, if any
+func (a *App) providerError() *providers.Error {
+	var providerErr *providers.Error
+	if a.err != nil && errors.As(a.err, &providerErr) {
+		return providerErr
+	}
+	return nil
+}
+
+// errorShown is called when the error view opens. Errors of the loading
+// operation can be retried; rate-limited ones are retried automatically.
+func (a *App) errorShown(fromOperation bool) tea.Cmd {
+	a.errorSeq++
+	a.retryAt = time.Time{}
+	if !fromOperation {
+		a.retryOp = nil
+	}
+
+	providerErr := a.providerError()
+	if providerErr != nil && providerErr.Kind == providers.ErrorGeoBlocked {
+		a.recordGeoBlocked(providerErr.Provider)
+	}
+	if a.retryOp == nil || providerErr == nil || providerErr.Kind != providers.ErrorRateLimited {
+		return nil
+	}
+
+	delay := providerErr.RetryAfter
+	if delay <= 0 {
+		delay = defaultRateLimitRetry
+	}
+	if delay > maxAutoRetryDelay {
+		return nil
+	}
+	a.retryAt = time.Now().Add(delay)
+	seq := a.errorSeq
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return errorRetryMsg{seq: seq}
+	})
+}
+
+// recordGeoBlocked counts a geo-blocked error towards flagging the provider
+// as likely geo-blocked
+func (a *App) recordGeoBlocked(provider string) {
+	if a.db == nil || provider == "" {
+		return
+	}
+	a.goBackground(func() {
+		availability, err := database.RecordProviderFailure(a.db, provider, true)
+		if err != nil {
+			a.logger.Warn("failed to record provider availability", "provider", provider, "error", err)
+			return
+		}
+		providers.SetLikelyGeoBlocked(provider, availability.LikelyGeoBlocked())
+	})
+}
+
+// handleErrorRetryMsg retries a rate-limited operation if its error is still shown
+func (a *App) handleErrorRetryMsg(msg errorRetryMsg) (tea.Model, tea.Cmd) {
+	if a.state != errorView || msg.seq != a.errorSeq || a.retryOp == nil {
+		return a, nil
+	}
+	return a.retryOperation()
+}
+
+// retryOperation runs the failed loading operation again
+func (a *App) retryOperation() (tea.Model, tea.Cmd) {
+	run := a.retryOp
+	a.err = nil
+	a.retryAt = time.Time{}
+	a.state = loadingView
+	a.loadingOp = a.retryLoadingOp
+	return a, tea.Batch(a.spinner.Tick, a.withOperation(run))
+}
+
+// handleErrorViewKeys runs the next actions offered for the error shown.
+// Unhandled keys (esc, quit) fall through to the usual handling.
+func (a *App) handleErrorViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	providerErr := a.providerError()
+
+	switch msg.String() {
+	case "r":
+		if a.retryOp != nil {
+			model, cmd := a.retryOperation()
+			return model, cmd, true
+		}
+	case "p":
+		if providerErr != nil {
+			a.err = nil
+			if a.watchingFromAniList {
+				a.state = anilistView
+			} else {
+				a.state = searchView
+			}
+			model, cmd := a.handleProviderSwitch()
+			return model, cmd, true
+		}
+	case "o":
+		if providerErr != nil && providerErr.Kind == providers.ErrorCaptchaRequired && providerErr.URL != "" {
+			url := providerErr.URL
+			a.statusMsg = i18n.T("Opening %s in the browser...", url)
+			a.statusMsgTime = time.Now()
+			return a, func() tea.Msg {
+				if err := browser.OpenURL(url); err != nil {
+					a.logger.Warn("failed to open browser", "url", url, "error", err)
+				}
+				return nil
+			}, true
+		}
+	}
+	return a, nil, false
+}
+
+// errorGuidance explains a provider error and what can be done about it
+func errorGuidance(providerErr *providers.Error) string {
+	switch providerErr.Kind {
+	case providers.ErrorNotFound:
+		return i18n.T("%s doesn't have this. Another provider might.", providerErr.Provider)
+	case providers.ErrorGeoBlocked:
+		return i18n.T("%s is blocked in your region. Set network.proxy to a proxy in another country, or switch provider.", providerErr.Provider)
+	case providers.ErrorCaptchaRequired:
+		return i18n.T("%s is asking for a captcha. Pass it in the browser, then retry.", providerErr.Provider)
+	case providers.ErrorRateLimited:
+		return i18n.T("%s is rate limiting requests. Wait a bit before retrying.", providerErr.Provider)
+	case providers.ErrorParserBroken:
+		return i18n.T("%s changed its site and greg can't read it anymore. Switch provider and check for a greg update.", providerErr.Provider)
+	default:
+		return ""
+	}
+}
+
+// renderErrorView shows the error with guidance and the available actions
+func (a *App) renderErrorView() string {
+	var b strings.Builder
+	b.WriteString(i18n.T("An error occurred:") + "\n\n")
+	b.WriteString(a.err.Error())
+	b.WriteString("\n\n")
+
+	providerErr := a.providerError()
+	if providerErr != nil {
+		if guidance := errorGuidance(providerErr); guidance != "" {
+			b.WriteString(guidance + "\n\n")
+		}
+	}
+	if !a.retryAt.IsZero() {
+		b.WriteString(i18n.T("Retrying automatically at %s", a.retryAt.Format("15:04:05")) + "\n\n")
+	}
+
+	var actions []string
+	if a.retryOp != nil {
+		actions = append(actions, i18n.T("r retry"))
+	}
+	if providerErr != nil {
+		if providerErr.Kind == providers.ErrorCaptchaRequired && providerErr.URL != "" {
+			actions = append(actions, i18n.T("o open in browser"))
+		}
+		actions = append(actions, i18n.T("p switch provider"))
+	}
+	actions = append(actions, i18n.T("esc back"))
+	b.WriteString(styles.HelpStyle.Render(strings.Join(actions, " • ")))
+	return styles.AppStyle.Render(b.String())
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle((*App).handleToggleFavoriteMsg)
+	handle((*App).handleFavoriteToggledMsg)
+}
+
+// favoriteMediaType maps a media type to the one stored with favorites,
+// matching history entries
+func favoriteMediaType(mediaType string) string {
+	switch providers.MediaType(mediaType) {
+	case providers.MediaTypeAnime, providers.MediaTypeManga, providers.MediaTypeTV:
+		return mediaType
+	default:
+		return string(providers.MediaTypeMovie)
+	}
+}
+
+// favoriteIDs returns the media IDs starred on the current provider
+func (a *App) favoriteIDs() map[string]bool {
+	if a.db == nil {
+		return nil
+	}
+	ids, err := database.FavoriteIDs(a.db, a.providerName)
+	if err != nil {
+		a.logger.Warn("failed to load favorites", "error", err)
+		return nil
+	}
+	return ids
+}
+
+// handleToggleFavoriteMsg stars or unstars a title
+func (a *App) handleToggleFavoriteMsg(msg common.ToggleFavoriteMsg) (tea.Model, tea.Cmd) {
+	if a.db == nil {
+		return a, nil
+	}
+	fav := database.Favorite{
+		MediaID:      msg.MediaID,
+		ProviderName: msg.ProviderName,
+		MediaTitle:   msg.Title,
+		MediaType:    favoriteMediaType(msg.Type),
+	}
+	if fav.ProviderName == "" {
+		fav.ProviderName = a.providerName
+	}
+
+	db := a.db
+	return a, func() tea.Msg {
+		starred, err := database.ToggleFavorite(db, fav)
+		return common.FavoriteToggledMsg{
+			MediaID:      fav.MediaID,
+			Title:        fav.MediaTitle,
+			ProviderName: fav.ProviderName,
+			Starred:      starred,
+			Err:          err,
+		}
+	}
+}
+
+// handleFavoriteToggledMsg reports a starred or unstarred title and
+// refreshes the views showing favorites
+func (a *App) handleFavoriteToggledMsg(msg common.FavoriteToggledMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	switch {
+	case msg.Err != nil:
+		a.logger.Warn("failed to update favorites", "media", msg.Title, "error", msg.Err)
+		a.statusMsg = i18n.T("⚠ Failed to update favorites: %v", msg.Err)
+	case msg.Starred:
+		a.statusMsg = i18n.T("★ Added %s to favorites", msg.Title)
+	default:
+		a.statusMsg = i18n.T("Removed %s from favorites", msg.Title)
+	}
+	a.statusMsgTime = time.Now()
+
+	if msg.Err == nil {
+		if msg.ProviderName == a.providerName {
+			a.results.SetFavorite(msg.MediaID, msg.Starred)
+		}
+		if a.state == homeView {
+			cmds = append(cmds, a.home.ReloadFavorites())
+		}
+	}
+	cmds = append(cmds, func() tea.Msg {
+		time.Sleep(3 * time.Second)
+		return clearStatusMsg{}
+	})
+	return a, tea.Batch(cmds...)
+}
@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// HeadlessOptions set up a Headless app
+type HeadlessOptions struct {
+	Providers  map[providers.MediaType]providers.Provider
+	TrackerMgr ProgressTracker // Optional; AniList views fail to load without one
+	DB         *gorm.DB        // An in-memory database works, migrated like a real one
+	Config     ConfigProvider
+	Player     player.Player // Replaces mpv; playback fails to start without one
+	Logger     *slog.Logger  // Discards when nil
+
+	Width, Height int           // 120x40 when 0
+	Timeout       time.Duration // How long WaitFor waits, 5s when 0
+}
+
+// Headless runs the TUI without a terminal, for integration tests and
+// scripted demos: keys and messages go through Update in the caller's
+// goroutine, the commands they return run in the background like in a
+// program, and the view is read back without ANSI styling.
+//
+// The providers are registered in the global provider registry until Close,
+// as the mapping manager looks providers up there.
+type Headless struct {
+	app        *App
+	msgs       chan tea.Msg
+	done       chan struct{}
+	registered []string
+	timeout    time.Duration
+	quit       bool
+}
+
+// NewHeadless creates the app, sends it the window size and starts it
+func NewHeadless(opts HeadlessOptions) *Headless {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	h := &Headless{
+		msgs:    make(chan tea.Msg, 64),
+		done:    make(chan struct{}),
+		timeout: opts.Timeout,
+	}
+	if h.timeout == 0 {
+		h.timeout = 5 * time.Second
+	}
+	for _, p := range opts.Providers {
+		if _, err := providers.Get(p.Name()); err != nil && providers.Register(p) == nil {
+			h.registered = append(h.registered, p.Name())
+		}
+	}
+
+	h.app = NewApp(opts.Providers, opts.DB, opts.Config, logger, "")
+	h.app.trackerMgr = opts.TrackerMgr
+	h.app.player = opts.Player
+
+	width, height := opts.Width, opts.Height
+	if width == 0 || height == 0 {
+		width, height = 120, 40
+	}
+	h.run(h.app.Init())
+	h.Send(tea.WindowSizeMsg{Width: width, Height: height})
+	return h
+}
+
+// App returns the app being driven
+func (h *Headless) App() *App {
+	return h.app
+}
+
+// Send passes msg to Update and starts the command it returns
+func (h *Headless) Send(msg tea.Msg) {
+	if h.quit {
+		return
+	}
+	if _, ok := msg.(tea.QuitMsg); ok {
+		h.quit = true
+		return
+	}
+	_, cmd := h.app.Update(msg)
+	h.run(cmd)
+}
+
+// Press sends keys by name, as tea.KeyMsg.String() spells them: "enter",
+// "esc", "down", "space", "ctrl+c", "alt+x" or a single character
+func (h *Headless) Press(keys ...string) {
+	for _, key := range keys {
+		h.Send(keyMsg(key))
+	}
+}
+
+// Type sends text one character at a time, as typing it would
+func (h *Headless) Type(text string) {
+	for _, r := range text {
+		h.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// View returns the rendered view without ANSI styling
+func (h *Headless) View() string {
+	return ansi.Strip(h.app.View())
+}
+
+// Quitting reports whether the app asked to quit
+func (h *Headless) Quitting() bool {
+	return h.quit
+}
+
+// WaitFor handles the results of commands until the view satisfies cond,
+// failing with the last view when the timeout passes first
+func (h *Headless) WaitFor(cond func(view string) bool) error {
+	deadline := time.After(h.timeout)
+	for {
+		if view := h.View(); cond(view) {
+			return nil
+		}
+		select {
+		case msg := <-h.msgs:
+			h.Send(msg)
+		case <-deadline:
+			return fmt.Errorf("timed out after %v waiting for the view, which is:\n%s", h.timeout, h.View())
+		}
+	}
+}
+
+// WaitForText waits until the view contains text
+func (h *Headless) WaitForText(text string) error {
+	if err := h.WaitFor(func(view string) bool { return strings.Contains(view, text) }); err != nil {
+		return fmt.Errorf("%q not shown: %w", text, err)
+	}
+	return nil
+}
+
+// Settle handles the results of commands until none arrives for idle, for
+// flows that end without anything to wait for in the view
+func (h *Headless) Settle(idle time.Duration) {
+	for {
+		select {
+		case msg := <-h.msgs:
+			h.Send(msg)
+		case <-time.After(idle):
+			return
+		}
+	}
+}
+
+// Close shuts the app down like quitting would and unregisters the
+// providers NewHeadless registered. Commands still running are abandoned.
+func (h *Headless) Close() {
+	close(h.done)
+	h.app.shutdown()
+	for _, name := range h.registered {
+		_ = providers.Unregister(name)
+	}
+}
+
+// run starts cmd in the background, unwrapping batches and sequences the
+// way a program would
+func (h *Headless) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		h.deliver(cmd())
+	}()
+}
+
+func (h *Headless) deliver(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil:
+		return
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			h.run(cmd)
+		}
+		return
+	}
+	// tea.Sequence's message is unexported: a slice of commands to run in
+	// order
+	if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == reflect.TypeFor[tea.Cmd]() {
+		for i := range v.Len() {
+			if cmd := v.Index(i).Interface().(tea.Cmd); cmd != nil {
+				h.deliver(cmd())
+			}
+		}
+		return
+	}
+	select {
+	case h.msgs <- msg:
+	case <-h.done:
+	}
+}
+
+// keyTypes are the key types by name, the reverse of tea.KeyType.String
+var keyTypes = func() map[string]tea.KeyType {
+	types := make(map[string]tea.KeyType)
+	for k := tea.KeyType(-100); k <= tea.KeyDelete; k++ {
+		if name := k.String(); name != "" && k != tea.KeyRunes {
+			if _, ok := types[name]; !ok {
+				types[name] = k
+			}
+		}
+	}
+	return types
+}()
+
+// keyMsg returns the key message a key name stands for
+func keyMsg(key string) tea.KeyMsg {
+	alt := false
+	if rest, ok := strings.CutPrefix(key, "alt+"); ok && rest != "" {
+		alt, key = true, rest
+	}
+	if key == "space" || key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}
+	}
+	if k, ok := keyTypes[key]; ok {
+		return tea.KeyMsg{Type: k, Alt: alt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: alt}
+}
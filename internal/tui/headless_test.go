@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/player"
+	fakeplayer "github.com/justchokingaround/greg/internal/player/fake"
+	"github.com/justchokingaround/greg/internal/providers"
+	fakeprovider "github.com/justchokingaround/greg/internal/providers/fake"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+// frieren is the one show of the provider the flows run on
+var frieren = fakeprovider.Title{
+	Media:   providers.Media{ID: "frieren", Title: "Frieren", Type: providers.MediaTypeAnime, TotalEpisodes: 4},
+	Seasons: []int{4},
+}
+
+// libraryTracker serves an AniList library
+type libraryTracker struct {
+	library []tracker.TrackedMedia
+}
+
+func (t *libraryTracker) GetAniList() tracker.Tracker  { return nil }
+func (t *libraryTracker) IsAniListEnabled() bool       { return true }
+func (t *libraryTracker) IsAniListAuthenticated() bool { return true }
+func (t *libraryTracker) PendingSyncs() (int64, error) { return 0, nil }
+func (t *libraryTracker) UpdateProgress(context.Context, string, int, float64) error {
+	return nil
+}
+func (t *libraryTracker) ProcessSyncQueue(context.Context, bool) (int, int, error) {
+	return 0, 0, nil
+}
+func (t *libraryTracker) SearchMedia(context.Context, string, providers.MediaType) ([]tracker.TrackedMedia, error) {
+	return t.library, nil
+}
+func (t *libraryTracker) GetUserLibrary(context.Context, providers.MediaType) ([]tracker.TrackedMedia, error) {
+	return t.library, nil
+}
+
+// newHeadless drives an app on a provider serving frieren, an in-memory database and
+// defaults configured in a temporary directory
+func newHeadless(t *testing.T, db *gorm.DB, trackerMgr ProgressTracker, p player.Player) *Headless {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("GREG_CONFIG_HOME", dir)
+
+	cfg, _, err := config.Load("")
+	require.NoError(t, err)
+	cfg.UI.DefaultMediaType = "anime"
+	cfg.Downloads.Path = dir
+
+	h := NewHeadless(HeadlessOptions{
+		Providers:  map[providers.MediaType]providers.Provider{providers.MediaTypeAnime: fakeprovider.New("headless", providers.MediaTypeAnime, frieren)},
+		TrackerMgr: trackerMgr,
+		DB:         db,
+		Config:     cfg,
+		Player:     p,
+	})
+	t.Cleanup(h.Close)
+	return h
+}
+
+func newHeadlessDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: is a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, database.Migrate(db))
+	return db
+}
+
+func TestHeadlessSearchAndPlay(t *testing.T) {
+	p := &fakeplayer.Player{}
+	h := newHeadless(t, newHeadlessDB(t), nil, p)
+
+	require.NoError(t, h.WaitForText("Quick Actions"))
+	h.Press("s")
+	require.NoError(t, h.WaitForText("Find your next watch"))
+	h.Type("frieren")
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("1 found"))
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("4 available"))
+	h.Press("down", "enter")
+	require.NoError(t, h.WaitForText("Playing: Frieren - Episode 2"))
+
+	play, ok := p.LastPlay()
+	require.True(t, ok)
+	assert.Contains(t, play.URL, "/frieren-s1-e2/")
+	assert.Equal(t, 2, play.Options.Episode)
+}
+
+func TestHeadlessResumeFromHistory(t *testing.T) {
+	db := newHeadlessDB(t)
+	require.NoError(t, db.Create(&database.History{
+		MediaID: "frieren", MediaTitle: "Frieren", MediaType: "anime", Episode: 3, Season: 1,
+		ProgressSeconds: 600, TotalSeconds: 1440, ProgressPercent: 41.7, ProviderName: "headless",
+	}).Error)
+	p := &fakeplayer.Player{}
+	h := newHeadless(t, db, nil, p)
+
+	require.NoError(t, h.WaitForText("Frieren - S1 E3"))
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("Playing: Frieren - Episode 3"))
+
+	play, ok := p.LastPlay()
+	require.True(t, ok)
+	assert.Contains(t, play.URL, "/frieren-s1-e3/")
+	assert.Equal(t, 10*time.Minute, play.Options.StartTime)
+}
+
+func TestHeadlessAniListAutoPlay(t *testing.T) {
+	db := newHeadlessDB(t)
+	require.NoError(t, mapping.NewManager(db, "headless", slog.New(slog.DiscardHandler)).SelectMapping(context.Background(), 154587, "headless",
+		providers.Media{ID: "frieren", Title: "Frieren", Type: providers.MediaTypeAnime}))
+	library := &libraryTracker{library: []tracker.TrackedMedia{{
+		ServiceID: "anilist:154587", Title: "Frieren", Type: providers.MediaTypeAnime,
+		Progress: 1, TotalEpisodes: 4, Status: tracker.StatusWatching,
+	}}}
+	p := &fakeplayer.Player{}
+	h := newHeadless(t, db, library, p)
+
+	require.NoError(t, h.WaitForText("Quick Actions"))
+	h.Press("l")
+	require.NoError(t, h.WaitForText("1/4 episodes"))
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("Playing: Frieren - Episode 2"), "the episode after the AniList progress plays")
+
+	play, ok := p.LastPlay()
+	require.True(t, ok)
+	assert.Contains(t, play.URL, "/frieren-s1-e2/")
+}
+
+func TestKeyMsg(t *testing.T) {
+	for _, key := range []string{"enter", "esc", "down", "tab", "ctrl+c", "j", "G", "alt+x", " "} {
+		assert.Equal(t, key, keyMsg(key).String())
+	}
+	assert.Equal(t, " ", keyMsg("space").String())
+}
+
+func TestHeadlessPlaybackEnds(t *testing.T) {
+	db := newHeadlessDB(t)
+	p := fakeplayer.New(time.Minute, 120) // Ends in half a second
+	h := newHeadless(t, db, nil, p)
+
+	require.NoError(t, h.WaitForText("Quick Actions"))
+	h.Press("s")
+	require.NoError(t, h.WaitForText("Find your next watch"))
+	h.Type("frieren")
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("1 found"))
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("4 available"))
+	h.Press("enter")
+	require.NoError(t, h.WaitForText("Playing: Frieren - Episode 1"))
+	require.NoError(t, h.WaitFor(func(view string) bool { return !strings.Contains(view, "Playing:") }))
+	h.Settle(200 * time.Millisecond)
+
+	var history database.History
+	require.NoError(t, db.Where("media_id = ?", "frieren").First(&history).Error)
+	assert.Equal(t, 1, history.Episode)
+	assert.True(t, history.Completed, "played to the end")
+}
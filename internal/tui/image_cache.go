@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"log/slog"
+	"path/filepath"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/imagecache"
+)
+
+// newImageCache opens the shared image cache under the cache directory, or
+// returns nil when caching is disabled
+func newImageCache(cfg *config.Config, logger *slog.Logger) *imagecache.Cache {
+	if !cfg.Cache.Enabled || cfg.Cache.Path == "" {
+		return nil
+	}
+
+	cache, err := imagecache.New(
+		filepath.Join(cfg.Cache.Path, "images"),
+		int64(cfg.Cache.MaxSize)*1024*1024,
+		cfg.Cache.TTL.Images,
+	)
+	if err != nil {
+		logger.Warn("image cache disabled", "error", err)
+		return nil
+	}
+
+	// Drop expired and over-cap images left from previous runs
+	go func() {
+		if err := cache.Prune(); err != nil {
+			logger.Debug("failed to prune image cache", "error", err)
+		}
+	}()
+	return cache
+}
+
+// closeImageCache clears the image cache on exit when configured to
+func (a *App) closeImageCache() {
+	cfg := a.config()
+	if a.images == nil || cfg == nil || !cfg.Cache.CleanupOnExit {
+		return
+	}
+	if err := a.images.Clear(); err != nil {
+		a.logger.Warn("failed to clear image cache", "error", err)
+	}
+}
+
+// prefetchPosters queues poster images of search results for the cache
+func (a *App) prefetchPosters(urls []string) {
+	cfg := a.config()
+	if a.images == nil || cfg == nil || !cfg.UI.PreviewImages {
+		return
+	}
+	a.images.Prefetch(urls...)
+}
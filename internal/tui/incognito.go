@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle(func(a *App, _ common.ToggleIncognitoMsg) (tea.Model, tea.Cmd) { return a.handleToggleIncognitoMsg() })
+}
+
+// Incognito starts the TUI in incognito mode, set by main (--incognito)
+var Incognito bool
+
+// setIncognito turns incognito mode on or off: while on, playback and
+// reading aren't saved to history or synced to AniList
+func (a *App) setIncognito(on bool) {
+	a.incognito = on
+	a.home.SetIncognito(on)
+	a.mangaComponent.Incognito = on
+}
+
+// handleToggleIncognitoMsg toggles incognito mode for the rest of the session
+func (a *App) handleToggleIncognitoMsg() (tea.Model, tea.Cmd) {
+	a.setIncognito(!a.incognito)
+	if a.incognito {
+		a.statusMsg = i18n.T("Incognito on: history and AniList sync are paused")
+	} else {
+		a.statusMsg = i18n.T("Incognito off")
+	}
+	a.statusMsgTime = time.Now()
+	return a, func() tea.Msg {
+		time.Sleep(3 * time.Second)
+		return clearStatusMsg{}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/components/anilist"
@@ -22,8 +23,19 @@ import (
 	"github.com/justchokingaround/greg/internal/tui/components/seasons"
 )
 
+func init() {
+	handle((*App).handleKeyMsg)
+}
+
 // handleKeyMsg processes all keyboard input and routes to appropriate handlers
 func (a *App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The home view's URL prompt takes every key, '?' included (URLs have query strings)
+	if a.state == homeView && a.home.IsInputActive() && msg.Type != tea.KeyCtrlC {
+		homeModel, cmd := a.home.Update(msg)
+		a.home = homeModel.(*home.Model)
+		return a, cmd
+	}
+
 	// Handle '?' to toggle help (global keybinding, works in all views)
 	if msg.String() == "?" {
 		// If help is visible and user presses ?, hide it
@@ -62,6 +74,26 @@ func (a *App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, helpCmd
 	}
 
+	// Notification center (global, takes every key while open)
+	if a.notes.open {
+		return a.handleNotificationCenterKeys(msg)
+	}
+	if msg.String() == "ctrl+n" {
+		a.notes.open = true
+		a.notes.offset = 0
+		return a, nil
+	}
+	if a.stats.open {
+		return a.handleStatsKeys(msg)
+	}
+	if msg.String() == "ctrl+t" {
+		a.openStats()
+		return a, nil
+	}
+	if cmd, ok := a.handleAmbientKeys(msg.String()); ok {
+		return a, cmd
+	}
+
 	// Handle download notification dismissal
 	if a.showDownloadNotification {
 		// 'd' key goes to downloads instead of dismissing
@@ -85,6 +117,16 @@ func (a *App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Already downloaded prompt
+	if len(a.pendingDuplicates) > 0 {
+		return a.handleDuplicatePromptKeys(msg)
+	}
+
+	// Which search result a Planning entry is
+	if a.planning != nil && a.planning.prompt != nil {
+		return a.handlePlanningPromptKeys(msg)
+	}
+
 	// Handle WatchParty popup keys first if popup is visible
 	if a.showWatchPartyPopup {
 		return a.handleWatchPartyPopupInput(msg)
@@ -100,6 +142,18 @@ func (a *App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleDialogInput(msg)
 	}
 
+	// Next actions offered for a provider error
+	if a.state == errorView {
+		if model, cmd, ok := a.handleErrorViewKeys(msg); ok {
+			return model, cmd
+		}
+	}
+
+	// Undo the last delete while the status bar offers it
+	if a.isUndoKey(msg) {
+		return a, a.undoTrash()
+	}
+
 	// Handle playback completion view (special case - needs early handling)
 	if a.state == playbackCompletedView {
 		return a.handlePlaybackCompletedKeys(msg)
@@ -110,6 +164,26 @@ func (a *App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleLaunchingPlayerKeys(msg)
 	}
 
+	// Resume/start over prompt (special case - needs early handling)
+	if a.state == resumePromptView {
+		return a.handleResumePromptKeys(msg)
+	}
+
+	// Local vs AniList progress prompt (special case - needs early handling)
+	if a.state == progressConflictView {
+		return a.handleProgressConflictKeys(msg)
+	}
+
+	// Episode offset prompt (special case - needs early handling)
+	if a.state == courOffsetView {
+		return a.handleCourOffsetKeys(msg)
+	}
+
+	// Release notes after an update (special case - needs early handling)
+	if a.state == whatsNewView {
+		return a.handleWhatsNewKeys(msg)
+	}
+
 	// Block all navigation when playing (except quit) (special case - needs early handling)
 	if a.state == playingView {
 		return a.handlePlayingViewKeys(msg)
@@ -124,6 +198,16 @@ func (a *App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, cmd
 	}
 
+	// Back/forward through the pages visited
+	if !a.inputModeActive() {
+		switch msg.String() {
+		case "alt+left":
+			return a.navigateBack()
+		case "alt+right":
+			return a.navigateForward()
+		}
+	}
+
 	// Clear quit request if user presses any key other than 'q' or 'esc'
 	if a.quitRequested && msg.String() != "q" && msg.String() != "esc" {
 		a.quitRequested = false
@@ -148,6 +232,11 @@ func (a *App) handleEscapeKey() (tea.Model, tea.Cmd) {
 		return a, nil
 	}
 
+	// Back out of a loading operation, aborting its provider calls
+	if a.state == loadingView {
+		return a.abortOperation()
+	}
+
 	// Handle provider selection view
 	if a.state == providerSelectionView {
 		// Return to previous state
@@ -169,7 +258,7 @@ func (a *App) handleEscapeKey() (tea.Model, tea.Cmd) {
 		if a.downloadMgr != nil && a.downloadMgr.HasActiveDownloads() {
 			if !a.quitRequested {
 				a.quitRequested = true
-				a.statusMsg = "⚠ Downloads in progress! Press 'esc' again to force quit, 'd' to view downloads, or any other key to cancel"
+				a.statusMsg = i18n.T("⚠ Downloads in progress! Press 'esc' again to force quit, 'd' to view downloads, or any other key to cancel")
 				a.statusMsgTime = time.Now()
 				return a, func() tea.Msg {
 					time.Sleep(3 * time.Second)
@@ -204,8 +293,8 @@ func (a *App) handleEscapeKey() (tea.Model, tea.Cmd) {
 // handlePlaybackCompletedKeys handles keyboard input in playback completed view
 func (a *App) handlePlaybackCompletedKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
-	// If watching from AniList, episode was completed (>= 85%), and not last episode, handle continue watching prompt
-	if a.watchingFromAniList && !a.isLastEpisode && a.episodeCompleted {
+	// If watching from AniList, episode was completed, and not last episode, handle continue watching prompt
+	if a.watchingFromAniList && !a.isLastEpisode && a.episodeCompleted && !a.sleep.fired {
 		switch msg.String() {
 		case "y", "Y", "enter":
 			// User wants to continue - play next episode
@@ -214,7 +303,7 @@ func (a *App) handlePlaybackCompletedKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 			// Find and play the next episode (current + 1)
 			targetEpisode := a.currentEpisodeNumber + 1
-			if targetEpisode > a.currentAniListMedia.TotalEpisodes {
+			if a.anilistEpisode(targetEpisode) > a.currentAniListMedia.TotalEpisodes {
 				// No more episodes
 				a.watchingFromAniList = false
 				a.previousState = -1
@@ -450,6 +539,38 @@ func (a *App) handleLaunchingPlayerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// handleResumePromptKeys handles keyboard input in the resume/start over prompt
+func (a *App) handleResumePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return a, tea.Quit
+	}
+
+	prompt := a.pendingResume
+	switch msg.String() {
+	case "r", "R", "enter":
+		a.pendingResume = nil
+		a.state = loadingView
+		options := prompt.Options
+		options.StartTime = prompt.Position
+		return a, a.launchPlayer(prompt.Stream, options)
+	case "s", "S":
+		a.pendingResume = nil
+		a.state = loadingView
+		return a, a.launchPlayer(prompt.Stream, prompt.Options)
+	case "esc", "q":
+		// Cancel playback and return to the episode list
+		a.pendingResume = nil
+		if a.currentEpisodeNumber > 0 && len(a.episodes) > 0 {
+			a.state = episodeView
+		} else {
+			a.state = homeView
+		}
+		return a, nil
+	default:
+		return a, nil
+	}
+}
+
 // handlePlayingViewKeys handles keyboard input during video playback
 func (a *App) handlePlayingViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle Ctrl+C - quit the TUI application immediately
@@ -472,6 +593,16 @@ func (a *App) handlePlayingViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.completionDialogMsg = "Did you finish watching this video?\n\n[y] Yes - Episode completed\n[n] No - Not completed"
 		a.episodeCompleted = false // User-initiated quit, default to not completed
 		return a, nil
+	case "z":
+		return a, a.cycleSleepTimer()
+	case "m":
+		return a, a.saveEpisodeThemes()
+	case "n":
+		return a, a.cycleNormalization()
+	case "c":
+		return a, a.markClip()
+	case "C":
+		return a, a.dropClipMark()
 	default:
 		// Ignore all other keys during playback
 		return a, nil
@@ -576,6 +707,13 @@ func (a *App) handleSpecificKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		historyModel, cmd = a.historyComponent.Update(msg)
 		a.historyComponent = historyModel.(history.Model)
 		return a, cmd
+	case sourceSelectView:
+		// Source selector handles navigation, filtering and cancel internally
+		if a.sourceSelectorModel != nil {
+			updatedModel, cmd := a.sourceSelectorModel.Update(msg)
+			a.sourceSelectorModel = &updatedModel
+			return a, cmd
+		}
 	case providerSelectionView:
 		isAniListSelection := a.watchingFromAniList && len(a.providerSearchResults) > 0
 
@@ -654,6 +792,7 @@ func (a *App) handleSpecificKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+h":
 		// Global keybind to return to home view from anywhere
 		if a.state != homeView {
+			a.cancelOperation()
 			a.statusMsg = ""
 			a.state = homeView
 			a.cameFromHistory = false
@@ -672,7 +811,7 @@ func (a *App) handleSpecificKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if !a.quitRequested {
 					// First 'q' press - show warning
 					a.quitRequested = true
-					a.statusMsg = "⚠ Downloads in progress! Press 'q' again to force quit, 'alt+d' to view downloads, or any other key to cancel"
+					a.statusMsg = i18n.T("⚠ Downloads in progress! Press 'q' again to force quit, 'alt+d' to view downloads, or any other key to cancel")
 					a.statusMsgTime = time.Now()
 					// Clear status message after 2 seconds
 					return a, func() tea.Msg {
@@ -766,9 +905,13 @@ func (a *App) handleProviderSwitch() (tea.Model, tea.Cmd) {
 		// Create a list of "media" items representing providers
 		var providerItems []providers.Media
 		for _, p := range providersList {
+			title := p.Name()
+			if providers.LikelyGeoBlocked(p.Name()) {
+				title = i18n.T("%s (likely geo-blocked)", p.Name())
+			}
 			providerItems = append(providerItems, providers.Media{
 				ID:    p.Name(),
-				Title: p.Name(),
+				Title: title,
 				Type:  a.currentMediaType,
 			})
 		}
@@ -789,37 +932,3 @@ func (a *App) handleProviderSwitch() (tea.Model, tea.Cmd) {
 
 	return a, nil
 }
-
-// switchToMediaType switches the current media type and updates related state
-func (a *App) switchToMediaType(mediaType providers.MediaType) (tea.Model, tea.Cmd) {
-	if a.currentMediaType != mediaType {
-		// Save current query
-		if a.searchQueries == nil {
-			a.searchQueries = make(map[providers.MediaType]string)
-		}
-		a.searchQueries[a.currentMediaType] = a.search.GetValue()
-
-		a.currentMediaType = mediaType
-		a.home.CurrentMediaType = a.currentMediaType
-
-		// Update provider name
-		if provider, ok := a.providers[a.currentMediaType]; ok {
-			a.home.SetProvider(provider.Name())
-			a.helpComponent.SetProviderName(provider.Name())
-		}
-
-		// Restore query for new media type
-		if query, ok := a.searchQueries[a.currentMediaType]; ok {
-			a.search.SetValue(query)
-		} else {
-			a.search.SetValue("")
-		}
-
-		// Reload recent history if on home view
-		if a.state == homeView {
-			return a, a.home.Init()
-		}
-	}
-
-	return a, nil
-}
@@ -0,0 +1,188 @@
+package tui
+
+// This file contains link-opening and clipboard-watching methods.
+// All methods remain on the App struct.
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/links"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	trackeranilist "github.com/justchokingaround/greg/internal/tracker/anilist"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/components/anilist"
+)
+
+func init() {
+	handle((*App).handleOpenLinkMsg)
+	handle((*App).handleLinkResolvedMsg)
+	handle((*App).handleClipboardCheckMsg)
+}
+
+// clipboardWatchInterval is how often the clipboard is polled when ui.clipboard_watch is enabled
+const clipboardWatchInterval = time.Second
+
+// clipboardCheckMsg carries the clipboard content read by the watcher
+type clipboardCheckMsg struct {
+	text string
+}
+
+// linkResolvedMsg is sent when a link has been resolved to media
+type linkResolvedMsg struct {
+	link     *links.Link
+	provider providers.Provider    // Set for provider links
+	media    *providers.Media      // Set for provider links
+	tracked  *tracker.TrackedMedia // Set for AniList/MAL links
+	err      error
+}
+
+// mediaLookup is implemented by tracker clients that can look up media by ID
+type mediaLookup interface {
+	GetMediaByID(ctx context.Context, id int, mal bool, mediaType providers.MediaType) (*tracker.TrackedMedia, error)
+}
+
+// handleOpenLinkMsg parses a link and starts resolving it
+func (a *App) handleOpenLinkMsg(msg common.OpenLinkMsg) (tea.Model, tea.Cmd) {
+	link, ok := links.Parse(msg.URL)
+	if !ok {
+		a.statusMsg = i18n.T("✗ Not a supported AniList, MyAnimeList or provider link")
+		a.statusMsgTime = time.Now()
+		return a, func() tea.Msg {
+			time.Sleep(3 * time.Second)
+			return clearStatusMsg{}
+		}
+	}
+
+	a.state = loadingView
+	a.loadingOp = loadingProviderSearch
+	return a, tea.Batch(a.spinner.Tick, a.resolveLink(link))
+}
+
+// resolveLink looks up the media a link points to
+func (a *App) resolveLink(link *links.Link) tea.Cmd {
+	return func() tea.Msg {
+		if link.Source == links.SourceProvider {
+			provider, err := providers.Get(link.Provider)
+			if err != nil {
+				return linkResolvedMsg{link: link, err: fmt.Errorf("provider %s not available: %w", link.Provider, err)}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutDetails))
+			defer cancel()
+
+			media := &providers.Media{ID: link.MediaID, Type: link.Type}
+			if details, err := provider.GetMediaDetails(ctx, link.MediaID); err == nil && details != nil {
+				media.Title = details.Title
+			}
+			if media.Title == "" {
+				media.Title = link.MediaID
+			}
+			return linkResolvedMsg{link: link, provider: provider, media: media}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		// AniList/MAL links: resolve through AniList, which also maps MAL IDs
+		var lookup mediaLookup
+		if mgr := a.trackerMgr; mgr != nil {
+			lookup, _ = mgr.GetAniList().(mediaLookup)
+		}
+		if lookup == nil {
+			lookup = trackeranilist.NewClient(trackeranilist.Config{})
+		}
+
+		tracked, err := lookup.GetMediaByID(ctx, link.TrackerID, link.Source == links.SourceMAL, link.Type)
+		if err != nil {
+			return linkResolvedMsg{link: link, err: fmt.Errorf("failed to look up %s: %w", link.URL, err)}
+		}
+		return linkResolvedMsg{link: link, tracked: tracked}
+	}
+}
+
+// handleLinkResolvedMsg jumps to the episode list of a resolved link
+func (a *App) handleLinkResolvedMsg(msg linkResolvedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.err = msg.err
+		a.state = errorView
+		return a, nil
+	}
+
+	// Tracker links go through the same provider search/mapping flow as the AniList library
+	if msg.tracked != nil {
+		return a.handleSelectMediaMsg(anilist.SelectMediaMsg{Media: msg.tracked})
+	}
+
+	a.watchingFromAniList = false
+	a.currentAniListID = 0
+	a.updateProvider(msg.provider)
+	a.currentMediaType = msg.media.Type
+
+	return a.handleMediaSelectedMsg(common.MediaSelectedMsg{
+		MediaID: msg.media.ID,
+		Title:   msg.media.Title,
+		Type:    string(msg.media.Type),
+	})
+}
+
+// clipboardWatchEnabled reports whether ui.clipboard_watch is on
+func (a *App) clipboardWatchEnabled() bool {
+	cfg := a.config()
+	return cfg != nil && cfg.UI.ClipboardWatch && a.clipboardSvc != nil
+}
+
+// watchClipboard reads the clipboard after clipboardWatchInterval
+func (a *App) watchClipboard() tea.Cmd {
+	cfg := a.config()
+	svc, last := a.clipboardSvc, a.lastClipboard
+	return tea.Tick(clipboardWatchInterval, func(time.Time) tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		text, err := svc.Read(ctx, cfg)
+		if err != nil {
+			return clipboardCheckMsg{text: last}
+		}
+		return clipboardCheckMsg{text: text}
+	})
+}
+
+// handleClipboardCheckMsg opens newly copied links while greg is idle
+func (a *App) handleClipboardCheckMsg(msg clipboardCheckMsg) (tea.Model, tea.Cmd) {
+	next := a.watchClipboard()
+
+	// The first read only records what was already copied before greg started
+	if !a.clipboardPrimed {
+		a.clipboardPrimed = true
+		a.lastClipboard = msg.text
+		return a, next
+	}
+
+	if msg.text == a.lastClipboard {
+		return a, next
+	}
+	a.lastClipboard = msg.text
+
+	if _, ok := links.Parse(msg.text); !ok || !a.isIdleForLink() {
+		return a, next
+	}
+
+	model, cmd := a.handleOpenLinkMsg(common.OpenLinkMsg{URL: msg.text})
+	return model, tea.Batch(cmd, next)
+}
+
+// isIdleForLink reports whether opening a link would not interrupt the user
+func (a *App) isIdleForLink() bool {
+	switch a.state {
+	case homeView, resultsView, seasonView, episodeView, anilistView, historyView, errorView:
+		return true
+	}
+	return false
+}
@@ -8,12 +8,19 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/scraper"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/components/mangainfo"
 )
 
+func init() {
+	handle((*App).handleMangaInfoMsg)
+	handle((*App).handleMangaInfoResultMsg)
+	handle((*App).handleNextChapterMsg)
+}
+
 func (a *App) handleMangaInfoMsg(msg common.MangaInfoMsg) (tea.Model, tea.Cmd) {
 	a.previousState = a.state
 	a.state = loadingView
@@ -65,7 +72,7 @@ func (a *App) handleNextChapterMsg(msg common.NextChapterMsg) (tea.Model, tea.Cm
 	}
 
 	// No more chapters
-	a.statusMsg = "No more chapters available."
+	a.statusMsg = i18n.T("No more chapters available.")
 	a.statusMsgTime = time.Now()
 	return a, func() tea.Msg {
 		time.Sleep(3 * time.Second)
@@ -0,0 +1,58 @@
+package tui
+
+// This file contains playback of pasted stream URLs.
+// All methods remain on the App struct.
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/providers/manual"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle((*App).handlePlayURLMsg)
+}
+
+// handlePlayURLMsg plays a stream URL pasted on the home view
+func (a *App) handlePlayURLMsg(msg common.PlayURLMsg) (tea.Model, tea.Cmd) {
+	stream, err := manual.Stream(msg.URL, "", nil)
+	if err != nil {
+		a.statusMsg = i18n.T("✗ Can't play URL: %v", err)
+		a.statusMsgTime = time.Now()
+		return a, func() tea.Msg {
+			time.Sleep(3 * time.Second)
+			return clearStatusMsg{}
+		}
+	}
+	return a, a.playManualStream(stream, manual.Title(stream.URL))
+}
+
+// playManualStream plays a stream outside of any provider. It is recorded in
+// the history under the manual provider with the URL as media ID.
+func (a *App) playManualStream(stream *providers.StreamURL, title string) tea.Cmd {
+	a.selectedMedia = providers.Media{
+		ID:    stream.URL,
+		Title: title,
+		Type:  providers.MediaTypeMovie,
+	}
+	a.episodes = nil
+	a.watchingFromAniList = false
+	a.currentAniListID = 0
+	a.currentAudioPreference = ""
+	a.currentPlaybackProvider = manual.ProviderName
+	a.currentEpisodeID = stream.URL
+	a.currentEpisodeNumber = 0
+	a.currentSeasonNumber = 0
+	a.currentEpisodeTitle = title
+
+	a.state = loadingView
+	a.loadingOp = loadingStream
+	return tea.Batch(a.spinner.Tick, func() tea.Msg {
+		return a.playStream(stream, stream.URL, 0, title)
+	})
+}
@@ -8,12 +8,25 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/justchokingaround/greg/internal/config"
-	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
-	"github.com/justchokingaround/greg/internal/tracker/mapping"
+	"github.com/justchokingaround/greg/internal/tracker"
 	"github.com/justchokingaround/greg/internal/tui/common"
 )
 
+func init() {
+	handle((*App).handlePerformSearchMsg)
+	handle((*App).handleSearchProviderMsg)
+	handle((*App).handleSearchResultsMsg)
+	handle((*App).handleGenerateMediaDebugInfoMsg)
+	handle((*App).handleRequestDetailsMsg)
+	handle((*App).handleDetailsLoadedMsg)
+	handle((*App).handleMediaSelectedMsg)
+	handle((*App).handleSeasonsLoadedMsg)
+	handle((*App).handleSeasonSelectedMsg)
+	handle((*App).handleEpisodesLoadedMsg)
+}
+
 // handlePerformSearchMsg handles search requests
 func (a *App) handlePerformSearchMsg(msg common.PerformSearchMsg) (*App, tea.Cmd) {
 	a.state = loadingView
@@ -35,29 +48,32 @@ func (a *App) handleSearchProviderMsg(msg common.SearchProviderMsg) (*App, tea.C
 		a.updateProvider(p)
 	} else {
 		a.debugLog("SearchProviderMsg: Failed to get provider %s: %v", msg.ProviderName, err)
-		a.statusMsg = fmt.Sprintf("⚠ Failed to get provider %s: %v", msg.ProviderName, err)
+		a.statusMsg = i18n.T("⚠ Failed to get provider %s: %v", msg.ProviderName, err)
 		a.statusMsgTime = time.Now()
 	}
 
 	// If SaveMapping is true and we are in a global switch context (no specific media),
 	// update the default provider in config
 	if msg.SaveMapping && msg.Query == "Global Default" {
-		if cfg, ok := a.cfg.(*config.Config); ok {
-			if a.currentMediaType == providers.MediaTypeAnime {
+		if cfg := a.config(); cfg != nil {
+			switch a.currentMediaType {
+			case providers.MediaTypeAnime:
 				cfg.Providers.Default.Anime = msg.ProviderName
-			} else {
+			case providers.MediaTypeManga:
+				cfg.Providers.Default.Manga = msg.ProviderName
+			default:
 				cfg.Providers.Default.MoviesAndTV = msg.ProviderName
 			}
 			if err := cfg.Save(); err != nil {
-				a.statusMsg = fmt.Sprintf("⚠ Failed to save config: %v", err)
+				a.statusMsg = i18n.T("⚠ Failed to save config: %v", err)
 			} else {
-				a.statusMsg = fmt.Sprintf("✓ Default provider set to %s", msg.ProviderName)
+				a.statusMsg = i18n.T("✓ Default provider set to %s", msg.ProviderName)
 			}
 			a.statusMsgTime = time.Now()
 		}
 	} else if msg.Query == "Global Default" {
 		// Just Once case
-		a.statusMsg = fmt.Sprintf("✓ Switched to %s (temporary)", msg.ProviderName)
+		a.statusMsg = i18n.T("✓ Switched to %s (temporary)", msg.ProviderName)
 		a.statusMsgTime = time.Now()
 	}
 
@@ -102,31 +118,20 @@ func (a *App) handleSearchResultsMsg(msg common.SearchResultsMsg) (*App, tea.Cmd
 	}
 
 	a.results.SetMediaResults(mediaResults)
+	a.results.SetFavorites(a.favoriteIDs())
+	posters := make([]string, 0, len(mediaResults))
+	for _, media := range mediaResults {
+		posters = append(posters, media.PosterURL)
+	}
+	a.prefetchPosters(posters)
 	// Enable manga info only for anime
 	a.results.SetShowMangaInfo(a.currentMediaType == providers.MediaTypeAnime)
 	a.results.SetProviderName(a.providerName)
 	a.state = resultsView
 
-	// Trigger fetch for the first few items if needed
-	if len(mediaResults) > 0 {
-		limit := 5
-		if len(mediaResults) < limit {
-			limit = len(mediaResults)
-		}
-
-		for i := 0; i < limit; i++ {
-			if mediaResults[i].Synopsis == "" {
-				idx := i
-				mid := mediaResults[i].ID
-				cmds = append(cmds, func() tea.Msg {
-					return common.RequestDetailsMsg{
-						MediaID: mid,
-						Index:   idx,
-					}
-				})
-			}
-		}
-	}
+	// Fetch details for the rows on screen, replacing the previous search's queue
+	a.details.reset()
+	cmds = append(cmds, a.results.RequestVisibleDetails())
 
 	return a, tea.Batch(cmds...)
 }
@@ -161,30 +166,28 @@ func (a *App) handleMediaSelectedMsg(msg common.MediaSelectedMsg) (*App, tea.Cmd
 
 		if selectedMedia != nil {
 			// Save mapping
-			if a.mappingMgr != nil {
-				if mgr, ok := a.mappingMgr.(*mapping.Manager); ok {
-					// We need an ID to map TO.
-					// If we are in AniList mode, we have a.currentAniListID.
-					if a.currentAniListID > 0 {
-						ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-						err := mgr.SelectMapping(ctx, a.currentAniListID, a.providerName, *selectedMedia)
-						cancel()
-
-						if err != nil {
-							a.statusMsg = fmt.Sprintf("⚠ Failed to save mapping: %v", err)
-						} else {
-							a.statusMsg = "✓ Mapping saved successfully"
-						}
-						a.statusMsgTime = time.Now()
-
-						// Clear the flag
-						a.remapShouldSave = false
+			if mgr := a.mappingMgr; mgr != nil {
+				// We need an ID to map TO.
+				// If we are in AniList mode, we have a.currentAniListID.
+				if a.currentAniListID > 0 {
+					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+					err := mgr.SelectMapping(ctx, a.currentAniListID, a.providerName, *selectedMedia)
+					cancel()
+
+					if err != nil {
+						a.statusMsg = i18n.T("⚠ Failed to save mapping: %v", err)
 					} else {
-						// Not in AniList mode.
-						a.statusMsg = "⚠ Cannot save preference without AniList context"
-						a.statusMsgTime = time.Now()
-						a.remapShouldSave = false
+						a.statusMsg = i18n.T("✓ Mapping saved successfully")
 					}
+					a.statusMsgTime = time.Now()
+
+					// Clear the flag
+					a.remapShouldSave = false
+				} else {
+					// Not in AniList mode.
+					a.statusMsg = i18n.T("⚠ Cannot save preference without AniList context")
+					a.statusMsgTime = time.Now()
+					a.remapShouldSave = false
 				}
 			}
 		}
@@ -215,27 +218,23 @@ func (a *App) handleMediaSelectedMsg(msg common.MediaSelectedMsg) (*App, tea.Cmd
 			selectedMedia.Title, selectedMedia.ID)
 
 		// Save the mapping
-		if a.mappingMgr == nil {
+		if mgr := a.mappingMgr; mgr == nil {
 			a.debugLog("ERROR: MediaSelectedMsg: mappingMgr is nil, cannot save mapping")
 		} else {
-			if mgr, ok := a.mappingMgr.(*mapping.Manager); ok {
-				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
 
-				a.debugLog("MediaSelectedMsg: Saving mapping (AniList: %d → Provider: %s, Media: %s)",
-					a.currentAniListID, a.providerName, selectedMedia.ID)
-
-				if err := mgr.SelectMapping(ctx, a.currentAniListID, a.providerName, *selectedMedia); err != nil {
-					a.debugLog("ERROR: MediaSelectedMsg: Failed to save mapping: %v", err)
-					a.err = fmt.Errorf("failed to save mapping: %v", err)
-					a.state = errorView
-					return a, nil
-				}
+			a.debugLog("MediaSelectedMsg: Saving mapping (AniList: %d → Provider: %s, Media: %s)",
+				a.currentAniListID, a.providerName, selectedMedia.ID)
 
-				a.debugLog("SUCCESS: MediaSelectedMsg: Mapping saved successfully")
-			} else {
-				a.debugLog("ERROR: MediaSelectedMsg: Type assertion to *mapping.Manager failed")
+			if err := mgr.SelectMapping(ctx, a.currentAniListID, a.providerName, *selectedMedia); err != nil {
+				a.debugLog("ERROR: MediaSelectedMsg: Failed to save mapping: %v", err)
+				a.err = fmt.Errorf("failed to save mapping: %v", err)
+				a.state = errorView
+				return a, nil
 			}
+
+			a.debugLog("SUCCESS: MediaSelectedMsg: Mapping saved successfully")
 		}
 
 		// Set selected media and proceed to fetch seasons
@@ -261,30 +260,28 @@ func (a *App) handleMediaSelectedMsg(msg common.MediaSelectedMsg) (*App, tea.Cmd
 		}
 
 		// Save the mapping for future use
-		if a.mappingMgr != nil {
-			if mgr, ok := a.mappingMgr.(*mapping.Manager); ok {
-				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
-
-				// Get provider name from current provider
-				providerType := providers.MediaTypeAnime
-				if selectedMedia.Type == providers.MediaTypeManga {
-					providerType = providers.MediaTypeManga
-				}
+		if mgr := a.mappingMgr; mgr != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			// Get provider name from current provider
+			providerType := providers.MediaTypeAnime
+			if selectedMedia.Type == providers.MediaTypeManga {
+				providerType = providers.MediaTypeManga
+			}
 
-				if provider := a.providers[providerType]; provider != nil {
-					a.updateProvider(provider)
-				}
+			if provider := a.providers[providerType]; provider != nil {
+				a.updateProvider(provider)
+			}
 
-				a.debugLog("MediaSelectedMsg: Saving mapping (AniList: %d → Provider: %s, Media: %s)",
-					a.currentAniListID, a.providerName, selectedMedia.ID)
+			a.debugLog("MediaSelectedMsg: Saving mapping (AniList: %d → Provider: %s, Media: %s)",
+				a.currentAniListID, a.providerName, selectedMedia.ID)
 
-				if err := mgr.SelectMapping(ctx, a.currentAniListID, a.providerName, selectedMedia); err != nil {
-					a.debugLog("ERROR: MediaSelectedMsg: Failed to save mapping: %v", err)
-					// Don't fail the whole flow, just log the error
-				} else {
-					a.debugLog("SUCCESS: MediaSelectedMsg: Mapping saved successfully")
-				}
+			if err := mgr.SelectMapping(ctx, a.currentAniListID, a.providerName, selectedMedia); err != nil {
+				a.debugLog("ERROR: MediaSelectedMsg: Failed to save mapping: %v", err)
+				// Don't fail the whole flow, just log the error
+			} else {
+				a.debugLog("SUCCESS: MediaSelectedMsg: Mapping saved successfully")
 			}
 		}
 
@@ -321,7 +318,8 @@ func (a *App) handleMediaSelectedMsg(msg common.MediaSelectedMsg) (*App, tea.Cmd
 	}
 
 	// Check if provider exists
-	if _, ok := a.providers[providerType]; !ok {
+	provider, ok := a.providers[providerType]
+	if !ok {
 		a.err = fmt.Errorf("no provider available for %s", providerType)
 		a.state = errorView
 		return a, nil
@@ -330,8 +328,9 @@ func (a *App) handleMediaSelectedMsg(msg common.MediaSelectedMsg) (*App, tea.Cmd
 	// Update currentMediaType to match the provider we'll use
 	a.currentMediaType = providerType
 
-	// For movies, skip season fetching and go straight to playback
-	if selectedType == providers.MediaTypeMovie {
+	// For movies, skip season fetching and go straight to playback when the
+	// provider resolves movies directly; others list them as a season
+	if selectedType == providers.MediaTypeMovie && provider.Capabilities().Movies {
 		a.debugLog("Detected as Movie, calling playMovieDirectly (using provider type: %s)", providerType)
 		// For movies, set previousState to homeView so we return there after playback
 		a.previousState = homeView
@@ -361,6 +360,11 @@ func (a *App) handleSeasonsLoadedMsg(msg common.SeasonsLoadedMsg) (*App, tea.Cmd
 
 	if len(msg.Seasons) == 0 && a.selectedMedia.Type == providers.MediaTypeMovie {
 		// It's a movie, play directly
+		if provider, ok := a.providers[a.currentMediaType]; ok && !provider.Capabilities().Movies {
+			a.err = fmt.Errorf("%s lists no episode for this movie", provider.Name())
+			a.state = errorView
+			return a, nil
+		}
 		a.state = loadingView
 		a.loadingOp = loadingStream
 		cmds = append(cmds, a.spinner.Tick, a.playMovieDirectly(a.selectedMedia.ID))
@@ -555,52 +559,13 @@ func (a *App) handleSeasonsLoadedMsg(msg common.SeasonsLoadedMsg) (*App, tea.Cmd
 							len(episodes), seasons[0].Title, a.selectedMedia.Title)
 
 						// Set episodes and proceed to episode view
-						var epList []providers.Episode
-						for _, epInfo := range episodes {
-							epList = append(epList, providers.Episode{
-								ID:     epInfo.ID,
-								Number: epInfo.Number,
-								Title:  epInfo.Title,
-							})
-						}
-						a.episodes = epList
+						a.episodes = episodes
+						a.episodesComponent.SetAiring(a.airingSchedule())
 
-						// If watching from AniList, auto-play the first episode
+						// If watching from AniList, auto-play the next episode
 						if a.watchingFromAniList && a.currentAniListMedia != nil {
-							// Get the episode to play based on AniList progress (1-indexed)
-							targetEpisode := a.currentAniListMedia.Progress + 1
-
-							// If all episodes are complete, play the last episode
-							if targetEpisode > a.currentAniListMedia.TotalEpisodes {
-								targetEpisode = a.currentAniListMedia.TotalEpisodes
-							}
-
-							// Find the episode
-							var episodeToPlay *providers.Episode
-							for i := range epList {
-								if epList[i].Number == targetEpisode {
-									episodeToPlay = &epList[i]
-									break
-								}
-							}
-
-							if episodeToPlay == nil && len(epList) > 0 {
-								// Fallback: play the first episode if exact match not found
-								episodeToPlay = &epList[0]
-							}
-
-							if episodeToPlay != nil {
-								// Clear any status message
-								a.statusMsg = ""
-								// Set previous state to anilist so user returns there after playback
-								a.previousState = anilistView
-								return a, func() tea.Msg {
-									return common.EpisodeSelectedMsg{
-										EpisodeID: episodeToPlay.ID,
-										Number:    episodeToPlay.Number,
-										Title:     episodeToPlay.Title,
-									}
-								}
+							if cmd, ok := a.autoPlayAniList(episodes); ok {
+								return a, cmd
 							}
 						}
 
@@ -622,14 +587,17 @@ func (a *App) handleSeasonsLoadedMsg(msg common.SeasonsLoadedMsg) (*App, tea.Cmd
 			fmt.Printf("DEBUG: No episodes found directly either, treating as bad mapping for '%s'\n", a.selectedMedia.Title)
 			if a.currentAniListMedia != nil && a.currentAniListID != 0 {
 				// Delete the bad mapping to avoid reusing it
-				if a.mappingMgr != nil {
-					if mgr, ok := a.mappingMgr.(*mapping.Manager); ok {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						if err := mgr.DeleteMapping(ctx, a.currentAniListID); err != nil {
-							fmt.Printf("DEBUG: Failed to delete bad mapping: %v\n", err)
-						} else {
-							fmt.Printf("DEBUG: Successfully deleted bad mapping for AniList ID: %d\n", a.currentAniListID)
+				if mgr := a.mappingMgr; mgr != nil {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					if item, err := mgr.TrashMapping(ctx, a.currentAniListID); err != nil {
+						fmt.Printf("DEBUG: Failed to delete bad mapping: %v\n", err)
+					} else {
+						fmt.Printf("DEBUG: Successfully deleted bad mapping for AniList ID: %d\n", a.currentAniListID)
+						if item != nil {
+							// Keep the mapping recoverable in case it was right after all
+							_, undoCmd := a.handleTrashedMsg(common.TrashedMsg{ID: item.ID, Label: item.Label})
+							cmds = append(cmds, undoCmd)
 						}
 					}
 				}
@@ -649,7 +617,7 @@ func (a *App) handleSeasonsLoadedMsg(msg common.SeasonsLoadedMsg) (*App, tea.Cmd
 						a.selectedMedia.Title,
 						strings.ToLower(strings.ReplaceAll(a.selectedMedia.Title, ":", "")))
 					a.state = errorView
-					return a, nil
+					return a, tea.Batch(cmds...)
 				}
 			} else {
 				// Fallback error if we don't have AniList media info
@@ -726,13 +694,19 @@ func (a *App) handleEpisodesLoadedMsg(msg common.EpisodesLoadedMsg) (*App, tea.C
 
 	var episodes []providers.Episode
 	for _, epInfo := range msg.Episodes {
-		episodes = append(episodes, providers.Episode{
-			ID:     epInfo.EpisodeID,
-			Number: epInfo.Number,
-			Title:  epInfo.Title,
-		})
+		episodes = append(episodes, epInfo.Episode())
 	}
 	a.episodes = episodes
+	a.episodesComponent.SetAiring(a.airingSchedule())
+	if provider, ok := a.providers[a.currentMediaType]; ok {
+		a.applyCapabilities(provider)
+	}
+	specialsCmd := a.loadSpecials()
+
+	// Providers listing the entry's earlier cours too need an offset first
+	if cmd, ok := a.checkCourOffset(episodes); ok {
+		return a, tea.Batch(cmd, specialsCmd)
+	}
 
 	// If watching from AniList, auto-play the current episode
 	if a.watchingFromAniList && a.currentAniListMedia != nil {
@@ -755,83 +729,8 @@ func (a *App) handleEpisodesLoadedMsg(msg common.EpisodesLoadedMsg) (*App, tea.C
 			}
 		}
 
-		// Get the episode to play based on AniList progress
-		// Progress is the number of episodes completed, so play Progress + 1
-		targetEpisode := a.currentAniListMedia.Progress + 1
-
-		a.debugLog("AniList Auto-Play: Progress=%d, Target=%d, TotalEpisodes=%d",
-			a.currentAniListMedia.Progress, targetEpisode, a.currentAniListMedia.TotalEpisodes)
-
-		// Check local history for latest progress to see if we're ahead of AniList
-		// This handles cases where AniList sync might be delayed or failed
-		if a.db != nil {
-			var latestHistory database.History
-			// Find the latest watched episode for this media
-			err := a.db.Where("media_id = ?", a.selectedMedia.ID).
-				Order("episode DESC").
-				First(&latestHistory).Error
-
-			if err == nil {
-				// If we have local history
-				if latestHistory.Episode > targetEpisode {
-					// Local history is ahead, use it
-					targetEpisode = latestHistory.Episode
-					// If the latest episode is completed, move to the next one
-					if latestHistory.Completed {
-						targetEpisode++
-					}
-					a.debugLog("AniList Auto-Play: Local history override -> Target=%d", targetEpisode)
-				} else if latestHistory.Episode == targetEpisode && latestHistory.Completed {
-					// If the current target is completed locally, move to next
-					targetEpisode++
-					a.debugLog("AniList Auto-Play: Local history completed override -> Target=%d", targetEpisode)
-				}
-			}
-		}
-
-		// If all episodes are complete, play the last episode
-		if targetEpisode > a.currentAniListMedia.TotalEpisodes && a.currentAniListMedia.TotalEpisodes > 0 {
-			targetEpisode = a.currentAniListMedia.TotalEpisodes
-		}
-
-		// Find the episode
-		var episodeToPlay *providers.Episode
-		for i := range episodes {
-			if episodes[i].Number == targetEpisode {
-				episodeToPlay = &episodes[i]
-				break
-			}
-		}
-
-		if episodeToPlay == nil {
-			a.debugLog("AniList Auto-Play: Target episode %d not found in %d episodes", targetEpisode, len(episodes))
-			// Log first few episodes to see what we have
-			for i := 0; i < 5 && i < len(episodes); i++ {
-				a.debugLog("  Ep %d: Number=%d, Title=%s", i, episodes[i].Number, episodes[i].Title)
-			}
-		} else {
-			a.debugLog("AniList Auto-Play: Found episode %d (ID: %s)", episodeToPlay.Number, episodeToPlay.ID)
-		}
-
-		if episodeToPlay == nil && len(episodes) > 0 {
-			// Fallback: play the first episode if exact match not found
-			episodeToPlay = &episodes[0]
-			a.debugLog("AniList Auto-Play: Falling back to first episode %d", episodeToPlay.Number)
-		}
-
-		if episodeToPlay != nil {
-			// Clear any status message (e.g., "Switched to manual search")
-			a.statusMsg = ""
-
-			// Set previous state to anilist so user returns there after playback
-			a.previousState = anilistView
-			return a, func() tea.Msg {
-				return common.EpisodeSelectedMsg{
-					EpisodeID: episodeToPlay.ID,
-					Number:    episodeToPlay.Number,
-					Title:     episodeToPlay.Title,
-				}
-			}
+		if cmd, ok := a.autoPlayAniList(episodes); ok {
+			return a, tea.Batch(cmd, specialsCmd)
 		}
 	}
 
@@ -839,38 +738,32 @@ func (a *App) handleEpisodesLoadedMsg(msg common.EpisodesLoadedMsg) (*App, tea.C
 	if len(episodes) == 1 {
 		// Set previous state to search so user can go back after playback
 		a.previousState = searchView
-		return a, func() tea.Msg {
+		return a, tea.Batch(specialsCmd, func() tea.Msg {
 			return common.EpisodeSelectedMsg{
 				EpisodeID: episodes[0].ID,
 				Number:    episodes[0].Number,
 				Title:     episodes[0].Title,
 			}
-		}
+		})
 	}
 
 	// Multiple episodes - show selection screen
 	a.episodesComponent.SetMediaType(a.selectedMedia.Type)
 	a.episodesComponent.SetEpisodes(a.episodes)
 	a.state = episodeView
-	return a, nil
+	return a, specialsCmd
 }
 
 // performSearch performs a search with the current provider
 func (a *App) performSearch(query string) tea.Cmd {
-	// Save query for this media type
-	if a.searchQueries == nil {
-		a.searchQueries = make(map[providers.MediaType]string)
-	}
-	a.searchQueries[a.currentMediaType] = query
-
-	return func() tea.Msg {
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		provider, ok := a.providers[a.currentMediaType]
 		if !ok {
 			return common.SearchResultsMsg{Err: fmt.Errorf("no provider available for %s", a.currentMediaType)}
 		}
 
 		// Add timeout to prevent hanging indefinitely
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(opCtx, a.providerTimeout(provider.Name(), config.TimeoutSearch))
 		defer cancel()
 
 		results, err := provider.Search(ctx, query)
@@ -911,18 +804,20 @@ func (a *App) performSearch(query string) tea.Cmd {
 		// Instead, details are fetched on-demand as the user scrolls (lazy loading).
 		// This makes the initial search much faster and prevents "thundering herd" issues.
 
+		a.emitSearchEvent(query, provider.Name(), len(results))
+
 		// TODO: This is a temporary hack to convert the results
 		var interfaceResults []interface{}
 		for _, r := range results {
 			interfaceResults = append(interfaceResults, r)
 		}
 		return common.SearchResultsMsg{Results: interfaceResults, Err: nil}
-	}
+	})
 }
 
 // searchSpecificProvider searches using a specific provider
 func (a *App) searchSpecificProvider(providerName string, query string) tea.Cmd {
-	return func() tea.Msg {
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		a.debugLog("searchSpecificProvider: provider=%s, query=%s, currentMediaType=%s", providerName, query, a.currentMediaType)
 
 		provider, err := providers.Get(providerName)
@@ -931,7 +826,7 @@ func (a *App) searchSpecificProvider(providerName string, query string) tea.Cmd
 			return common.SearchResultsMsg{Err: fmt.Errorf("provider not found: %s", providerName)}
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(opCtx, a.providerTimeout(provider.Name(), config.TimeoutSearch))
 		defer cancel()
 
 		results, err := provider.Search(ctx, query)
@@ -970,6 +865,7 @@ func (a *App) searchSpecificProvider(providerName string, query string) tea.Cmd
 		results = filteredResults
 
 		a.debugLog("searchSpecificProvider: After filtering, %d results for type %s", len(results), a.currentMediaType)
+		a.emitSearchEvent(query, provider.Name(), len(results))
 
 		var interfaceResults []interface{}
 		for _, r := range results {
@@ -977,41 +873,14 @@ func (a *App) searchSpecificProvider(providerName string, query string) tea.Cmd
 		}
 
 		return common.SearchResultsMsg{Results: interfaceResults}
-	}
-}
-
-// fetchMediaDetails fetches detailed media information
-func (a *App) fetchMediaDetails(mediaID string, index int) tea.Cmd {
-	return func() tea.Msg {
-		// Acquire semaphore to limit concurrent fetches
-		a.detailsSem <- struct{}{}
-		defer func() { <-a.detailsSem }()
-
-		provider, ok := a.providers[a.currentMediaType]
-		if !ok {
-			return common.DetailsLoadedMsg{Err: fmt.Errorf("no provider available"), Index: index}
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		details, err := provider.GetMediaDetails(ctx, mediaID)
-		if err != nil {
-			return common.DetailsLoadedMsg{Err: err, Index: index, Media: providers.Media{ID: mediaID}}
-		}
-
-		return common.DetailsLoadedMsg{
-			Media: details.Media,
-			Index: index,
-		}
-	}
+	})
 }
 
 // getSeasons retrieves seasons for the given media ID
 func (a *App) getSeasons(mediaID string) tea.Cmd {
-	return func() tea.Msg {
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		provider := a.providers[a.currentMediaType]
-		seasons, err := provider.GetSeasons(context.Background(), mediaID)
+		seasons, err := provider.GetSeasons(opCtx, mediaID)
 		if err != nil {
 			return common.SeasonsLoadedMsg{Error: err}
 		}
@@ -1025,37 +894,59 @@ func (a *App) getSeasons(mediaID string) tea.Cmd {
 			})
 		}
 		return common.SeasonsLoadedMsg{Seasons: seasonInfos}
-	}
+	})
 }
 
 // getEpisodes retrieves episodes for the given season ID
 func (a *App) getEpisodes(seasonID string) tea.Cmd {
-	return func() tea.Msg {
+	media := a.selectedMedia
+	season := a.currentSeasonNumber
+	var tracked *tracker.TrackedMedia
+	if a.watchingFromAniList {
+		tracked = a.currentAniListMedia
+	}
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		provider := a.providers[a.currentMediaType]
-		episodes, err := provider.GetEpisodes(context.Background(), seasonID)
+		episodes, err := provider.GetEpisodes(opCtx, seasonID)
 		if err != nil {
 			return common.EpisodesLoadedMsg{Error: err}
 		}
 
+		if media.Type == providers.MediaTypeTV {
+			a.fillSeasonMetadata(media, season, episodes)
+		}
+		if media.Type == providers.MediaTypeAnime {
+			a.fillAnimeSeasonMetadata(tracked, episodes)
+		}
+		if media.Type == providers.MediaTypeTV || media.Type == providers.MediaTypeAnime {
+			a.fillArcs(media, season, tracked, episodes)
+		}
+
 		var episodeInfos []common.EpisodeInfo
 		for _, ep := range episodes {
-			episodeInfos = append(episodeInfos, common.EpisodeInfo{
-				EpisodeID: ep.ID,
-				Number:    ep.Number,
-				Title:     ep.Title,
-			})
+			episodeInfos = append(episodeInfos, common.NewEpisodeInfo(ep))
 		}
 		return common.EpisodesLoadedMsg{Episodes: episodeInfos}
-	}
+	})
 }
 
 func (a *App) handleRequestDetailsMsg(msg common.RequestDetailsMsg) (tea.Model, tea.Cmd) {
-	return a, a.fetchMediaDetails(msg.MediaID, msg.Index)
+	queued := a.details.want(msg.Items)
+	cmds := make([]tea.Cmd, 0, queued)
+	for i := 0; i < queued; i++ {
+		cmds = append(cmds, a.fetchNextDetails())
+	}
+	return a, tea.Batch(cmds...)
 }
 
 func (a *App) handleDetailsLoadedMsg(msg common.DetailsLoadedMsg) (tea.Model, tea.Cmd) {
 	if msg.Err != nil {
-		a.debugLog("Failed to fetch details for %s: %v", msg.Media.ID, msg.Err)
+		a.debugLog("Failed to fetch details for %s: %v", msg.MediaID, msg.Err)
+		return a, nil
+	}
+	// Drop details that arrive after the results were replaced
+	results := a.results.GetMediaResults()
+	if msg.Index >= len(results) || results[msg.Index].ID != msg.MediaID {
 		return a, nil
 	}
 	a.results.UpdateMediaItem(msg.Index, msg.Media)
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/components/results"
+)
+
+// modeState is the search state kept per media type, so switching modes
+// returns to the same query, results and selection
+type modeState struct {
+	query         string
+	results       results.Model
+	selectedMedia providers.Media
+}
+
+// nextMediaType returns the media type after the current one in the
+// Movies/TV, Anime, Manga cycle
+func (a *App) nextMediaType() providers.MediaType {
+	switch a.currentMediaType {
+	case providers.MediaTypeMovieTV:
+		return providers.MediaTypeAnime
+	case providers.MediaTypeAnime:
+		return providers.MediaTypeManga
+	default:
+		return providers.MediaTypeMovieTV
+	}
+}
+
+// switchToMediaType switches the current media type, saving the state of
+// the current one and restoring the state of the new one
+func (a *App) switchToMediaType(mediaType providers.MediaType) (tea.Model, tea.Cmd) {
+	if a.currentMediaType == mediaType {
+		return a, nil
+	}
+
+	if a.modes == nil {
+		a.modes = make(map[providers.MediaType]modeState)
+	}
+	a.modes[a.currentMediaType] = modeState{
+		query:         a.search.GetValue(),
+		results:       a.results,
+		selectedMedia: a.selectedMedia,
+	}
+
+	saved, ok := a.modes[mediaType]
+	hasResults := ok && len(saved.results.GetMediaResults()) > 0
+	a.currentMediaType = mediaType
+	a.search.SetMediaType(mediaType)
+	a.search.SetValue(saved.query)
+	a.selectedMedia = saved.selectedMedia
+	if hasResults {
+		a.results = saved.results
+	} else {
+		a.results.SetMediaResults(nil)
+	}
+	// Queued detail requests refer to the results being replaced
+	a.details.reset()
+
+	if provider, ok := a.providers[a.currentMediaType]; ok {
+		a.providerName = provider.Name()
+		a.home.SetProvider(provider.Name())
+		a.helpComponent.SetProviderName(provider.Name())
+		a.applyCapabilities(provider)
+	}
+
+	cmds := []tea.Cmd{a.home.SwitchMediaType(mediaType)}
+	if hasResults {
+		a.results.SetFavorites(a.favoriteIDs())
+		cmds = append(cmds, a.results.RequestVisibleDetails())
+	} else if a.state == resultsView {
+		a.state = searchView
+	}
+	return a, tea.Batch(cmds...)
+}
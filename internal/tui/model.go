@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -12,14 +13,21 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"gorm.io/gorm"
 
+	"github.com/justchokingaround/greg/internal/ambient"
+	"github.com/justchokingaround/greg/internal/changelog"
 	"github.com/justchokingaround/greg/internal/clipboard"
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
 	"github.com/justchokingaround/greg/internal/downloader"
 	historyservice "github.com/justchokingaround/greg/internal/history"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/imagecache"
+	"github.com/justchokingaround/greg/internal/incoming"
 	"github.com/justchokingaround/greg/internal/player"
 	"github.com/justchokingaround/greg/internal/player/mpv"
 	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/scripting"
+	"github.com/justchokingaround/greg/internal/torrentclient"
 	"github.com/justchokingaround/greg/internal/tracker"
 	"github.com/justchokingaround/greg/internal/tracker/mapping"
 	"github.com/justchokingaround/greg/internal/tui/common"
@@ -38,7 +46,9 @@ import (
 	"github.com/justchokingaround/greg/internal/tui/components/results"
 	"github.com/justchokingaround/greg/internal/tui/components/search"
 	"github.com/justchokingaround/greg/internal/tui/components/seasons"
+	"github.com/justchokingaround/greg/internal/tui/components/sourceselect"
 	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
 )
 
 type sessionState int
@@ -61,6 +71,9 @@ const (
 	playingView
 	playbackCompletedView
 	audioSelectView
+	sourceSelectView
+	resumePromptView
+	progressConflictView
 	anilistView
 	providerSelectionView
 	downloadsView
@@ -69,6 +82,8 @@ const (
 	mangaInfoView
 	providerStatusView
 	mangaDownloadProgressView
+	whatsNewView
+	courOffsetView
 )
 
 type loadingOperation int
@@ -87,6 +102,8 @@ const (
 type App struct {
 	state                   sessionState
 	loadingOp               loadingOperation
+	opCancel                context.CancelFunc // Cancels the loading operation in flight
+	background              sync.WaitGroup     // Background writes that shutdown waits for
 	width                   int
 	height                  int
 	providers               map[providers.MediaType]providers.Provider
@@ -98,6 +115,7 @@ type App struct {
 	episodeListModel        results.Model
 	episodesComponent       episodes.Model
 	audioSelectorModel      *audioselect.Model
+	sourceSelectorModel     *sourceselect.Model
 	anilistComponent        anilist.Model
 	downloadsComponent      downloads.Model
 	historyComponent        history.Model
@@ -106,6 +124,7 @@ type App struct {
 	mangaDownloadComponent  mangadownload.Model
 	providerStatusComponent providerstatus.Model
 	historyService          *historyservice.Service
+	images                  *imagecache.Cache // Shared poster and page image cache, nil when disabled
 	helpComponent           help.Model
 	spinner                 spinner.Model
 	err                     error
@@ -128,10 +147,12 @@ type App struct {
 	currentEpisodeTitle     string
 	currentPlaybackProvider string                   // Provider used for current playback session
 	previousState           sessionState             // To return to after playback
+	nav                     navHistory               // Pages for alt+left/alt+right (see navigation.go)
 	lastProgress            *player.PlaybackProgress // Store last known progress
 	playbackCompletionMsg   string                   // Message to show after playback ends
-	episodeCompleted        bool                     // Whether the last episode was completed (>= 85%)
+	episodeCompleted        bool                     // Whether the last episode was completed (see completionRule)
 	launchStartTime         time.Time                // When player launch started (for timeout)
+	playerSession           int                      // Incremented for every launched playback
 	lastPlayedEpisodeNumber int                      // Episode number to position cursor on after playback
 
 	// Completion confirmation modal
@@ -139,8 +160,24 @@ type App struct {
 	completionDialogMsg  string // Formatted modal content
 	pendingCompletion    bool   // Should mark episode complete on 'yes'
 
-	// Search queries per media type
-	searchQueries map[providers.MediaType]string
+	// Resume/start over prompt
+	pendingResume *common.ShowResumePromptMsg // Playback waiting for the user's choice
+
+	// Local vs AniList progress prompt
+	pendingConflict *progressConflict
+
+	// Skip/overwrite/keep both prompt for already downloaded episodes
+	pendingDuplicates []*downloader.DuplicateError
+
+	// Planning list being queued, nil when none (see planning_download.go)
+	planning *planningRun
+
+	// Undo for the last delete (see trash_handlers.go)
+	undoTrashID    uint   // Trash item restored by undo (0 = nothing to undo)
+	undoTrashLabel string // What was deleted
+
+	// Search state per media type (see mode_state.go)
+	modes map[providers.MediaType]modeState
 
 	// For debug links mode
 	inDebugLinksMode bool
@@ -151,13 +188,14 @@ type App struct {
 	// Status message (shown briefly at bottom)
 	statusMsg     string
 	statusMsgTime time.Time
+	notes         notificationCenter // Toasts and notification history (see notifications.go)
 
 	// Download notification popup
 	showDownloadNotification bool
 	downloadNotificationMsg  string
 
 	// Tracker integration
-	trackerMgr interface{} // *tracker.Manager
+	trackerMgr ProgressTracker
 
 	// AniList dialog state
 	dialogMode  anilist.DialogMode
@@ -165,18 +203,41 @@ type App struct {
 
 	// Database and mapping
 	db         *gorm.DB
-	mappingMgr interface{} // *mapping.Manager
+	mappingMgr MappingStore
 
 	// Download manager
 	downloadMgr  *downloader.Manager
-	cfg          interface{} // *config.Config
+	cfg          ConfigProvider
 	logger       *slog.Logger
 	clipboardSvc clipboard.Service
 
 	// AniList playback context
-	watchingFromAniList     bool
-	currentAniListID        int
-	currentAniListMedia     *tracker.TrackedMedia
+	watchingFromAniList bool
+	currentAniListID    int
+	currentAniListMedia *tracker.TrackedMedia
+	bulkMedia           []tracker.TrackedMedia // Marked entries the open dialog applies to
+
+	// Tracker sync queue
+	pendingSyncs            int64
+	syncRetryScheduled      bool
+	incognito               bool                     // No history, AniList sync or subscriptions
+	coWatch                 *database.CoWatchProfile // Progress goes to this profile instead of history
+	sleep                   sleepTimer               // Stops playback after an episode or at a time
+	watched                 watchTime                // Watch time today, for the daily limit
+	radio                   *ambient.Radio           // Background radio, nil until first used
+	radioHeld               bool                     // Radio paused for a video, resumed when it ends
+	playingStream           *providers.StreamURL     // Stream of the current playback, for clips
+	clipStart               *time.Duration           // Marked start of a clip, nil when none
+	subtitleDelay           time.Duration            // Subtitle delay remembered for the series playing
+	normalization           string                   // Loudness normalization of the playback
+	health                  streamHealth             // Stalls of the stream playing, for switching sources
+	preload                 preloadedStream          // Next episode's stream, resolved during playback
+	ending                  endingLookup             // Where the episode playing's ending starts, from AniSkip
+	specials                seriesSpecials           // OVAs, specials and movies of the AniList entry being watched
+	cour                    courOffset               // Episode offset of the AniList entry's provider mapping
+	bandwidth               bandwidthMeter           // Data streamed and not recorded yet
+	stats                   statsView                // Bandwidth usage overlay (ctrl+t)
+	scripts                 *scripting.Engine        // Lua automations, nil when there are none
 	providerSearchResults   []providers.Media
 	providerName            string        // Name of provider being used
 	providerSelectionResult results.Model // Reuse results component for selection
@@ -199,42 +260,71 @@ type App struct {
 
 	// Semaphore for limiting concurrent detail fetches
 	detailsSem chan struct{}
+	details    *detailsFetcher // Priority queue of result detail requests
+
+	episodeMeta *episodeMetadata // TMDB and AniList lookups of the episode detail pane
 
 	// Audio preference from CLI flag or config
-	audioPreference    string               // "dub", "sub", or "" (use DB/config)
-	selectedAudioTrack *int                 // User-selected audio track index from selector (nil if not set)
-	pendingStream      *providers.StreamURL // Stream waiting for audio selection
+	audioPreference        string               // "dub", "sub", language code, or "" (use DB/config)
+	currentAudioPreference string               // Preference resolved for the current playback
+	selectedAudioTrack     *int                 // User-selected audio track index from selector (nil if not set)
+	pendingStream          *providers.StreamURL // Stream waiting for audio selection
+
+	// Link opening (greg open <url> and ui.clipboard_watch)
+	initialLink     string // Link to open on startup
+	lastClipboard   string // Last clipboard content seen by the watcher
+	clipboardPrimed bool   // Whether the watcher has recorded the initial clipboard
+
+	// Release notes shown after an update
+	whatsNew       *changelog.Release
+	whatsNewOffset int // First visible line
+
+	// Retrying the loading operation a provider error came from
+	retryOp        func(opCtx context.Context) tea.Msg // Last loading operation, offered as retry when it fails
+	retryLoadingOp loadingOperation
+	retryAt        time.Time // When a rate-limited operation is retried automatically
+	errorSeq       int       // Invalidates automatic retries of errors no longer shown
 }
 
-func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB, cfg interface{}, logger *slog.Logger, audioPreference string) *App {
+// Player plays instead of mpv when set, by main (--demo)
+var Player player.Player
+
+func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB, cfg ConfigProvider, logger *slog.Logger, audioPreference string) *App {
 	// Use default logger if none provided
 	if logger == nil {
 		logger = slog.Default()
 	}
 
+	var appConfig *config.Config
+	if cfg != nil {
+		appConfig = cfg.Config()
+	}
+
+	if appConfig != nil {
+		if err := i18n.Init(appConfig.UI.Locale, config.GetConfigDir()); err != nil {
+			logger.Warn("failed to load translations", "error", err)
+		}
+	}
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
+	if lowRefreshEnabled(appConfig) {
+		s.Spinner = staticSpinner
+	}
 	s.Style = styles.SelectedItemStyle
 
 	// Check if debug mode is enabled
-	debugMode := false
-	if cfg != nil {
-		if configPtr, ok := cfg.(*config.Config); ok {
-			debugMode = configPtr.Advanced.Debug
-		}
-	}
+	debugMode := appConfig != nil && appConfig.Advanced.Debug
 
 	// Initialize MPV player with configuration
-	var mpvPlayer player.Player
-	if cfg != nil {
-		if appCfg, ok := cfg.(*config.Config); ok {
-			mpvPlayerWithConfig, err := mpv.NewMPVPlayerWithConfig(appCfg, debugMode)
-			if err != nil {
-				// Log the error but continue initializing the app
-				logger.Warn("failed to initialize MPV player with config", "error", err)
-			} else {
-				mpvPlayer = mpvPlayerWithConfig
-			}
+	mpvPlayer := Player
+	if mpvPlayer == nil && appConfig != nil {
+		mpvPlayerWithConfig, err := mpv.NewMPVPlayerWithConfig(appConfig, debugMode)
+		if err != nil {
+			// Log the error but continue initializing the app
+			logger.Warn("failed to initialize MPV player with config", "error", err)
+		} else {
+			mpvPlayer = mpvPlayerWithConfig
 		}
 	}
 
@@ -286,21 +376,34 @@ func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB,
 	// Initialize download manager if config and database are available
 	var downloadMgr *downloader.Manager
 	var downloadsComp downloads.Model
-	if cfg != nil && db != nil {
-		if appCfg, ok := cfg.(*config.Config); ok {
-			// Create download manager
-			dlMgr, err := downloader.NewManager(db, &appCfg.Downloads, logger)
-			if err != nil {
-				logger.Warn("failed to initialize download manager", "error", err)
-			} else {
-				downloadMgr = dlMgr
-				downloadsComp = downloads.New(dlMgr)
-
-				// Start the download manager
-				if err := dlMgr.Start(context.Background()); err != nil {
-					logger.Warn("failed to start download manager", "error", err)
-				} else if debugMode {
-					logger.Debug("download manager initialized successfully")
+	if appConfig != nil && db != nil {
+		// Create download manager
+		dlMgr, err := downloader.NewManager(db, &appConfig.Downloads, logger)
+		if err != nil {
+			logger.Warn("failed to initialize download manager", "error", err)
+		} else {
+			downloadMgr = dlMgr
+			downloadsComp = downloads.New(dlMgr)
+			downloadsComp.SetRefreshInterval(downloadsRefreshInterval(appConfig))
+
+			// Start the download manager
+			if err := dlMgr.Start(context.Background()); err != nil {
+				logger.Warn("failed to start download manager", "error", err)
+			} else if debugMode {
+				logger.Debug("download manager initialized successfully")
+			}
+
+			// Import what other programs drop into the incoming folder,
+			// and torrents handed to a torrent client once they finish
+			watcher := incoming.New(appConfig.Downloads.Incoming.Path, dlMgr, mappingMgr, logger)
+			if appConfig.Downloads.Incoming.Path != "" {
+				go watcher.Run(context.Background(), appConfig.Downloads.Incoming.Interval)
+			}
+			if appConfig.Downloads.Torrent.Client != "" {
+				if client, err := torrentclient.New(appConfig.Downloads.Torrent); err != nil {
+					logger.Warn("failed to set up torrent client", "error", err)
+				} else {
+					go watcher.TrackTorrents(context.Background(), db, client, appConfig.Downloads.Torrent.Interval)
 				}
 			}
 		}
@@ -313,13 +416,6 @@ func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB,
 		historyService = historyservice.NewService(db)
 	}
 
-	var appConfig *config.Config
-	if cfg != nil {
-		if c, ok := cfg.(*config.Config); ok {
-			appConfig = c
-		}
-	}
-
 	// Initialize clipboard service
 	clipboardSvc := clipboard.NewService(logger)
 
@@ -359,12 +455,13 @@ func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB,
 		spinner:                 s,
 		player:                  mpvPlayer,
 		detailsSem:              make(chan struct{}, 5), // Limit to 5 concurrent fetches
-		searchQueries:           make(map[providers.MediaType]string),
+		details:                 newDetailsFetcher(),
+		episodeMeta:             newEpisodeMetadata(),
+		modes:                   make(map[providers.MediaType]modeState),
 		inDebugLinksMode:        false,
 		dialogMode:              anilist.DialogNone,
 		dialogState:             anilist.InitDialogState(),
 		db:                      db,
-		mappingMgr:              mappingMgr,
 		downloadMgr:             downloadMgr,
 		cfg:                     cfg,
 		logger:                  logger,
@@ -375,12 +472,33 @@ func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB,
 
 	// Set parent for manga info component
 	app.mangaInfoComponent.SetParent(app)
+	app.setIncognito(Incognito)
+	app.resolveCoWatch(CoWatch)
+	if SleepTimer != "" {
+		if timer, err := parseSleepTimer(SleepTimer, time.Now()); err == nil {
+			app.sleep = timer
+		}
+	}
+
+	if appConfig != nil {
+		app.anilistComponent.SetSmartLists(appConfig.Tracker.AniList.SmartLists)
+		app.images = newImageCache(appConfig, logger)
+		app.mangaComponent.Images = app.images
+	}
+	if mappingMgr != nil {
+		app.mappingMgr = mappingMgr
+	}
+	if lowRefreshEnabled(appConfig) {
+		app.mangaDownloadComponent.SetSpinner(staticSpinner)
+	}
 
 	// Set initial provider name and media type for home component filtering
 	app.home.CurrentMediaType = app.currentMediaType
+	app.search.SetMediaType(app.currentMediaType)
 	if provider, ok := providerMap[app.currentMediaType]; ok {
 		app.home.SetProvider(provider.Name())
 		app.helpComponent.SetProviderName(provider.Name())
+		app.applyCapabilities(provider)
 	}
 
 	// Set up download manager callbacks if available
@@ -395,14 +513,29 @@ func NewApp(providerMap map[providers.MediaType]providers.Provider, db *gorm.DB,
 		// Callback for download completion
 		app.downloadMgr.OnDownloadComplete(func(task downloader.DownloadTask) {
 			app.logger.Info("download completed", "media_title", task.MediaTitle)
+			app.notifyAsync(severitySuccess, i18n.T("Downloaded %s", task.MediaTitle))
+			app.emitScriptEvent(scripting.EventDownloadCompleted, downloadEvent(task))
+			if task.Clip == nil {
+				bytes := task.BytesDownloaded
+				if bytes <= 0 {
+					bytes = task.TotalBytes
+				}
+				app.checkDownloadBandwidth(bytes)
+			}
 		})
 
 		// Callback for download errors
 		app.downloadMgr.OnDownloadError(func(task downloader.DownloadTask, err error) {
 			app.logger.Error("download failed", "media_title", task.MediaTitle, "error", err)
+			app.notifyAsync(severityError, i18n.T("Download of %s failed: %v", task.MediaTitle, err))
+			event := downloadEvent(task)
+			event["error"] = err.Error()
+			app.emitScriptEvent(scripting.EventDownloadFailed, event)
 		})
 	}
 
+	app.loadScripts()
+
 	return app
 }
 
@@ -444,15 +577,33 @@ func (a *App) updateProvider(p providers.Provider) {
 	a.results.SetProviderName(p.Name())
 	a.episodeListModel.SetProviderName(p.Name())
 	a.helpComponent.SetProviderName(p.Name())
+	a.applyCapabilities(p)
 
 	a.debugLog("updateProvider: UI components updated with %s", p.Name())
 }
 
+// applyCapabilities leaves the actions a provider doesn't support out of the
+// episode list and greys them out in the help
+func (a *App) applyCapabilities(p providers.Provider) {
+	caps := p.Capabilities()
+	a.helpComponent.SetCapabilities(caps)
+	a.episodesComponent.SetCapabilities(caps)
+}
+
 func (a *App) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		a.home.Init(),
 		a.listenForMessages(),
-	)
+	}
+	if a.initialLink != "" {
+		link := a.initialLink
+		cmds = append(cmds, func() tea.Msg { return common.OpenLinkMsg{URL: link} })
+	}
+	if a.clipboardWatchEnabled() {
+		cmds = append(cmds, a.watchClipboard())
+	}
+	cmds = append(cmds, a.retrySyncQueue(), a.checkWhatsNew(), checkProviderHealth())
+	return tea.Batch(cmds...)
 }
 
 // listenForMessages listens for messages from background goroutines
@@ -462,335 +613,30 @@ func (a *App) listenForMessages() tea.Cmd {
 	}
 }
 
+// Update handles msg and sets up the next actions when the error view opens
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
-
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		a.width = msg.Width
-		a.height = msg.Height
-		// Pass window size messages to all components
-		// IMPORTANT: Must assign updated models back to preserve width/height
-		var homeCmd, searchCmd, resultsCmd, seasonsCmd, episodesCmd, anilistCmd, downloadsCmd, mangaCmd tea.Cmd
-		var homeModel, searchModel, resultsModel, seasonsModel, episodesModel, mangaModel tea.Model
-
-		homeModel, homeCmd = a.home.Update(msg)
-		a.home = homeModel.(*home.Model)
-
-		searchModel, searchCmd = a.search.Update(msg)
-		a.search = searchModel.(search.Model)
-
-		resultsModel, resultsCmd = a.results.Update(msg)
-		a.results = resultsModel.(results.Model)
-
-		seasonsModel, seasonsCmd = a.seasons.Update(msg)
-		a.seasons = seasonsModel.(seasons.Model)
-
-		episodesModel, episodesCmd = a.episodesComponent.Update(msg)
-		a.episodesComponent = episodesModel.(episodes.Model)
-
-		mangaModel, mangaCmd = a.mangaComponent.Update(msg)
-		a.mangaComponent = mangaModel.(manga.Model)
-
-		var anilistModel tea.Model
-		anilistModel, anilistCmd = a.anilistComponent.Update(msg)
-		a.anilistComponent = anilistModel.(anilist.Model)
-
-		var downloadsModel tea.Model
-		downloadsModel, downloadsCmd = a.downloadsComponent.Update(msg)
-		a.downloadsComponent = downloadsModel.(downloads.Model)
-
-		var historyModel tea.Model
-		var historyCmd tea.Cmd
-		historyModel, historyCmd = a.historyComponent.Update(msg)
-		a.historyComponent = historyModel.(history.Model)
-
-		var providerStatusModel tea.Model
-		var providerStatusCmd tea.Cmd
-		providerStatusModel, providerStatusCmd = a.providerStatusComponent.Update(msg)
-		a.providerStatusComponent = providerStatusModel.(providerstatus.Model)
-
-		// Update help component with window size
-		var helpCmd tea.Cmd
-		a.helpComponent, helpCmd = a.helpComponent.Update(msg)
-
-		// Update provider name in help
-		if p, ok := a.providers[a.currentMediaType]; ok {
-			a.helpComponent.SetProviderName(p.Name())
-		}
-
-		// Also update episodeListModel which is used for episode selection view
-		episodeListModel, _ := a.episodeListModel.Update(msg)
-		a.episodeListModel = episodeListModel.(results.Model)
-		a.episodeListModel.SetProviderName(a.providerName)
-
-		// Also update providerSelectionResult if active
-		if a.state == providerSelectionView {
-			var providerSelectionModel tea.Model
-			var providerSelectionCmd tea.Cmd
-			providerSelectionModel, providerSelectionCmd = a.providerSelectionResult.Update(msg)
-			a.providerSelectionResult = providerSelectionModel.(results.Model)
-			providerStatusCmd = tea.Batch(providerStatusCmd, providerSelectionCmd)
-		}
-
-		var mangaInfoModel tea.Model
-		var mangaInfoCmd tea.Cmd
-		mangaInfoModel, mangaInfoCmd = a.mangaInfoComponent.Update(msg)
-		a.mangaInfoComponent = mangaInfoModel.(*mangainfo.Model)
-
-		return a, tea.Batch(homeCmd, searchCmd, resultsCmd, seasonsCmd, episodesCmd, anilistCmd, downloadsCmd, helpCmd, mangaCmd, historyCmd, mangaInfoCmd, providerStatusCmd)
-	case tea.KeyMsg:
-		return a.handleKeyMsg(msg)
-	// Custom messages
-	case common.MangaInfoMsg:
-		return a.handleMangaInfoMsg(msg)
-
-	case common.MangaInfoResultMsg:
-		return a.handleMangaInfoResultMsg(msg)
-
-	case common.GoToSearchMsg:
-		return a.handleGoToSearchMsg()
-	case common.GoToHomeMsg:
-		return a.handleGoToHomeMsg()
-	case common.BackMsg:
-		return a.handleBackMsg()
-	case common.ToggleProviderMsg:
-		return a.handleToggleProviderMsg()
-	case common.GoToAniListMsg:
-		return a.handleGoToAniListMsg()
-	case common.GoToDownloadsMsg:
-		return a.handleGoToDownloadsMsg()
-	case common.GoToProviderStatusMsg:
-		return a.handleGoToProviderStatusMsg()
-	case common.DownloadsTickMsg:
-		return a.handleDownloadsTickMsg(msg)
-	case common.GoToHistoryMsg:
-		return a.handleGoToHistoryMsg(msg)
-	case anilist.LibraryLoadedMsg:
-		return a.handleLibraryLoadedMsg(msg)
-	case anilist.BackMsg:
-		return a.handleAnilistBackMsg()
-	case common.RefreshHistoryMsg:
-		return a.handleRefreshHistoryMsg(msg)
-	case anilist.SearchNewAnimeMsg:
-		return a.handleSearchNewAnimeMsg(msg)
-
-	case anilist.AniListSearchRequestedMsg:
-		return a.handleAniListSearchRequestedMsg(msg)
-
-	case anilist.AniListSearchResultMsg:
-		return a.handleAniListSearchResultMsg(msg)
-
-	case anilist.AniListAddToListDialogOpenMsg:
-		return a.handleAniListAddToListDialogOpenMsg(msg)
-
-	case anilist.AniListAddToListMsg:
-		return a.handleAniListAddToListMsg(msg)
-
-	case anilist.AniListDeleteConfirmationMsg:
-		return a.handleAniListDeleteConfirmationMsg(msg)
-
-	case anilist.AniListSearchSelectMsg:
-		return a.handleAniListSearchSelectMsg(msg)
-
-	case anilist.AniListDeleteRequestedMsg:
-		return a.handleAniListDeleteRequestedMsg(msg)
-
-	case anilist.AniListDeleteResultMsg:
-		return a.handleAniListDeleteResultMsg(msg)
-
-	case anilist.SelectMediaMsg:
-		return a.handleSelectMediaMsg(msg)
-
-	case anilist.RefreshLibraryMsg:
-		return a.handleRefreshLibraryMsg(msg)
-
-	case anilist.OpenStatusUpdateMsg:
-		return a.handleOpenStatusUpdateMsg(msg)
-
-	case anilist.OpenScoreUpdateMsg:
-		return a.handleOpenScoreUpdateMsg(msg)
-
-	case anilist.OpenProgressUpdateMsg:
-		return a.handleOpenProgressUpdateMsg(msg)
-
-	case anilist.StatusUpdatedMsg:
-		return a.handleStatusUpdatedMsg(msg)
-
-	case anilist.ScoreUpdatedMsg:
-		return a.handleScoreUpdatedMsg(msg)
-
-	case anilist.ProgressUpdatedMsg:
-		return a.handleProgressUpdatedMsg(msg)
-
-	case anilist.ProviderSearchResultMsg:
-		return a.handleProviderSearchResultMsg(msg)
-
-	case anilist.RemapRequestedMsg:
-		return a.handleRemapRequestedMsg(msg)
-
-	case common.PerformSearchMsg:
-		return a.handlePerformSearchMsg(msg)
-
-	case common.SearchProviderMsg:
-		return a.handleSearchProviderMsg(msg)
-
-	case common.SearchResultsMsg:
-		return a.handleSearchResultsMsg(msg)
-
-	case common.GenerateDebugInfoMsg:
-		return a.handleGenerateDebugInfoMsg(msg)
-
-	case common.GenerateMediaDebugInfoMsg:
-		return a.handleGenerateMediaDebugInfoMsg(msg)
-
-	case common.DebugSourcesLoadedMsg:
-		return a.handleDebugSourcesLoadedMsg(msg)
-
-	case common.RequestDetailsMsg:
-		return a.handleRequestDetailsMsg(msg)
-
-	case common.DetailsLoadedMsg:
-		return a.handleDetailsLoadedMsg(msg)
-
-	case common.MediaSelectedMsg:
-		return a.handleMediaSelectedMsg(msg)
-
-	case common.SeasonsLoadedMsg:
-		return a.handleSeasonsLoadedMsg(msg)
-
-	case common.SeasonSelectedMsg:
-		return a.handleSeasonSelectedMsg(msg)
-
-	case common.EpisodesLoadedMsg:
-		return a.handleEpisodesLoadedMsg(msg)
-
-	case common.MediaDownloadMsg:
-		return a.handleMediaDownloadMsg(msg)
-
-	case common.EpisodeDownloadMsg:
-		return a.handleEpisodeDownloadMsg(msg)
-
-	case common.BatchDownloadMsg:
-		return a.handleBatchDownloadMsg(msg)
-
-	case common.DownloadAddedMsg:
-		return a.handleDownloadAddedMsg(msg)
-
-	case clearStatusMsg:
-		return a.handleClearStatusMsg(msg)
-
-	case dismissDownloadNotificationMsg:
-		return a.handleDismissDownloadNotificationMsg(msg)
-
-	case common.WatchPartyMsg:
-		return a.handleWatchPartyMsg(msg)
-
-	case common.SetWatchPartyProxyMsg:
-		return a.handleSetWatchPartyProxyMsg(msg)
-
-	case common.OpenWatchPartyMsg:
-		return a.handleOpenWatchPartyMsg(msg)
-
-	case common.GenerateWatchPartyMsg:
-		return a.handleGenerateWatchPartyMsg(msg)
-
-	case common.ShareViaWatchPartyMsg:
-		return a.handleShareViaWatchPartyMsg(msg)
-
-	case common.ShareHistoryViaWatchPartyMsg:
-		return a.handleShareHistoryViaWatchPartyMsg(msg)
-
-	case common.ShareRecentViaWatchPartyMsg:
-		return a.handleShareRecentViaWatchPartyMsg(msg)
-
-	case common.ShareMediaViaWatchPartyMsg:
-		return a.handleShareMediaViaWatchPartyMsg(msg)
-
-	case common.NextChapterMsg:
-		return a.handleNextChapterMsg(msg)
-
-	case common.EpisodeSelectedMsg:
-		return a.handleEpisodeSelectedMsg(msg)
-
-	case common.MangaPagesLoadedMsg:
-		return a.handleMangaPagesLoadedMsg(msg)
-
-	case common.ChapterCompletedMsg:
-		return a.handleChapterCompletedMsg(msg)
-
-	case common.MangaQuitMsg:
-		return a.handleMangaQuitMsg(msg)
-
-	case common.ResumePlaybackMsg:
-		return a.handleResumePlaybackMsg(msg)
-
-	case common.PlaybackStartingMsg:
-		return a.handlePlaybackStartingMsg(msg)
-
-	case common.PlayerLaunchingMsg:
-		return a.handlePlayerLaunchingMsg(msg)
-
-	case common.PlayerLaunchTimeoutCheckMsg:
-		return a.handlePlayerLaunchTimeoutCheckMsg(msg)
-
-	case common.PlaybackStartedMsg:
-		return a.handlePlaybackStartedMsg(msg)
-
-	case common.PlaybackEndedMsg:
-		return a.handlePlaybackEndedMsg(msg)
-
-	case common.PlaybackTickMsg:
-		return a, a.handlePlaybackTickMsg()
-
-	case common.PlaybackProgressMsg:
-		return a, a.handlePlaybackProgressMsg(msg)
-
-	case common.PlaybackErrorMsg:
-		return a.handlePlaybackErrorMsg(msg)
-
-	case common.PlaybackAutoReturnMsg:
-		return a.handlePlaybackAutoReturnMsg(msg)
-
-	case common.ShowAudioSelectorMsg:
-		// Show audio selector when no matching track found
-		selector := audioselect.New(msg.Tracks, msg.AniListID)
-		a.audioSelectorModel = &selector
-		a.pendingStream = msg.Stream
-		// Store episode context for playback resumption
-		a.currentEpisodeID = msg.EpisodeID
-		a.currentEpisodeNumber = msg.EpisodeNum
-		a.currentEpisodeTitle = msg.EpisodeTitle
-		a.previousState = a.state
-		a.state = audioSelectView
-		return a, nil
-
-	case audioselect.SelectionMsg:
-		// User selected an audio track
-		// Save preference to database
-		if msg.AniListID > 0 {
-			trackIndexPtr := &msg.Track.Index
-			err := database.SaveAudioPreference(a.db, msg.AniListID, msg.Track.Type, trackIndexPtr)
-			if err != nil {
-				a.logger.Error("failed to save audio preference", "error", err, "anilist_id", msg.AniListID, "type", msg.Track.Type)
-				// Non-blocking - playback continues even if save fails
-			} else {
-				a.logger.Debug("saved audio preference", "anilist_id", msg.AniListID, "type", msg.Track.Type)
-			}
-		}
-		// Resume playback with selected track
-		a.state = launchingPlayerView
-		return a, a.continuePlaybackWithAudioTrack(msg.Track.Index)
+	prevState := a.state
+	model, cmd := a.update(msg)
+	a.recordStatus()
+	if a.state == errorView && prevState != errorView {
+		return model, tea.Batch(cmd, a.errorShown(prevState == loadingView))
+	}
+	if a.state != errorView && a.state != loadingView {
+		a.retryOp = nil
+	}
+	a.nav.visit(a.state)
+	return model, cmd
+}
 
-	case audioselect.CancelMsg:
-		// User canceled audio selection - return to previous view
-		a.state = a.previousState
-		a.selectedAudioTrack = nil
-		a.pendingStream = nil
-		return a, nil
+func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Messages with a handler subscribed in router.go's routes
+	if model, cmd, ok := a.dispatch(msg); ok {
+		return model, cmd
 	}
 
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
 	// Component-specific updates
 	switch a.state {
 	case providerStatusView:
@@ -881,6 +727,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.audioSelectorModel = &updatedModel
 			cmds = append(cmds, cmd)
 		}
+	case sourceSelectView:
+		if a.sourceSelectorModel != nil {
+			updatedModel, cmd := a.sourceSelectorModel.Update(msg)
+			a.sourceSelectorModel = &updatedModel
+			cmds = append(cmds, cmd)
+		}
 	case loadingView, launchingPlayerView:
 		a.spinner, cmd = a.spinner.Update(msg)
 		cmds = append(cmds, cmd)
@@ -930,6 +782,32 @@ func (a *App) View() string {
 		)
 	}
 
+	// Render the already downloaded prompt if episodes are waiting for it
+	if len(a.pendingDuplicates) > 0 {
+		finalView = lipgloss.Place(
+			lipgloss.Width(finalView),
+			lipgloss.Height(finalView),
+			lipgloss.Center,
+			lipgloss.Center,
+			a.renderDuplicatePrompt(),
+			lipgloss.WithWhitespaceBackground(styles.OxocarbonBlack),
+			lipgloss.WithWhitespaceForeground(styles.OxocarbonBlack),
+		)
+	}
+
+	// Render the pick among search results of a Planning entry
+	if a.planning != nil && a.planning.prompt != nil {
+		finalView = lipgloss.Place(
+			lipgloss.Width(finalView),
+			lipgloss.Height(finalView),
+			lipgloss.Center,
+			lipgloss.Center,
+			a.renderPlanningPrompt(),
+			lipgloss.WithWhitespaceBackground(styles.OxocarbonBlack),
+			lipgloss.WithWhitespaceForeground(styles.OxocarbonBlack),
+		)
+	}
+
 	// Render download notification popup if visible
 	if a.showDownloadNotification {
 		notificationView := a.renderDownloadNotification()
@@ -946,19 +824,7 @@ func (a *App) View() string {
 	}
 
 	// Add INPUT MODE indicator to status bar if any component has active input
-	inputModeActive := false
-	switch a.state {
-	case resultsView:
-		inputModeActive = a.results.IsInputActive()
-	case episodeView:
-		inputModeActive = a.episodesComponent.IsInputActive()
-	case seasonView:
-		inputModeActive = a.seasons.IsInputActive()
-	case historyView:
-		inputModeActive = a.historyComponent.IsInputActive()
-	}
-
-	if inputModeActive {
+	if a.inputModeActive() {
 		// Override status message with input mode indicator
 		width := a.width
 		if width == 0 {
@@ -1068,6 +934,44 @@ func (a *App) View() string {
 		}
 
 		finalView += "\n" + statusStyle.Render(fmt.Sprintf("%s %s", icon, cleanMsg))
+	} else if a.state != mangaReaderView {
+		width := a.width
+		if width == 0 {
+			width = 80
+		}
+		if miniPlayer := a.renderMiniPlayer(width); miniPlayer != "" {
+			// Replace the last line, like the status message
+			finalView = strings.TrimRight(finalView, "\n")
+			if lines := strings.Split(finalView, "\n"); a.height > 1 && len(lines) >= a.height {
+				finalView = strings.Join(lines[:a.height-1], "\n")
+			}
+			finalView += "\n" + miniPlayer
+		}
+	}
+
+	finalView = a.renderToasts(finalView)
+
+	if a.notes.open {
+		return lipgloss.Place(
+			a.width,
+			a.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.renderNotificationCenter(),
+			lipgloss.WithWhitespaceBackground(styles.OxocarbonBlack),
+			lipgloss.WithWhitespaceForeground(styles.OxocarbonBlack),
+		)
+	}
+	if a.stats.open {
+		return lipgloss.Place(
+			a.width,
+			a.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			a.renderStats(),
+			lipgloss.WithWhitespaceBackground(styles.OxocarbonBlack),
+			lipgloss.WithWhitespaceForeground(styles.OxocarbonBlack),
+		)
 	}
 
 	// Render help overlay on top if visible (render AFTER status so it appears above everything)
@@ -1126,40 +1030,36 @@ func (a *App) renderHeader() string {
 func (a *App) renderView() string {
 	switch a.state {
 	case errorView:
-		errorMsg := "An error occurred:\n\n"
-		errorMsg += a.err.Error()
-		errorMsg += "\n\n"
-		errorMsg += styles.HelpStyle.Render("Press 'esc' to return.")
-		return styles.AppStyle.Render(errorMsg)
+		return a.renderErrorView()
 	case loadingView:
 		var loadingMsg string
 		switch a.loadingOp {
 		case loadingSearch:
-			loadingMsg = "Searching..."
+			loadingMsg = i18n.T("Searching...")
 		case loadingSeasons:
 			if a.selectedMedia.Type == providers.MediaTypeManga {
-				loadingMsg = "Loading volumes..."
+				loadingMsg = i18n.T("Loading volumes...")
 			} else {
-				loadingMsg = "Loading seasons..."
+				loadingMsg = i18n.T("Loading seasons...")
 			}
 		case loadingEpisodes:
 			if a.selectedMedia.Type == providers.MediaTypeManga || a.currentMediaType == providers.MediaTypeManga {
-				loadingMsg = "Loading chapters..."
+				loadingMsg = i18n.T("Loading chapters...")
 			} else {
-				loadingMsg = "Loading episodes..."
+				loadingMsg = i18n.T("Loading episodes...")
 			}
 		case loadingStream:
-			loadingMsg = "Loading media..."
+			loadingMsg = i18n.T("Loading media...")
 		case loadingAniListLibrary:
-			loadingMsg = "Fetching your Anilist library..."
+			loadingMsg = i18n.T("Fetching your Anilist library...")
 		case loadingProviderSearch:
-			loadingMsg = "Searching providers for anime..."
+			loadingMsg = i18n.T("Searching providers for anime...")
 		case loadingMangaPages:
-			loadingMsg = "Loading manga pages..."
+			loadingMsg = i18n.T("Loading manga pages...")
 		default:
-			loadingMsg = "Loading..."
+			loadingMsg = i18n.T("Loading...")
 		}
-		return fmt.Sprintf("\n\n   %s %s\n\n", a.spinner.View(), loadingMsg)
+		return fmt.Sprintf("\n\n   %s %s\n\n   %s\n", a.spinner.View(), loadingMsg, styles.HelpStyle.Render(i18n.T("esc cancel")))
 	case launchingPlayerView:
 		// Show launching state with spinner and timeout info
 		elapsed := time.Since(a.launchStartTime)
@@ -1167,24 +1067,24 @@ func (a *App) renderView() string {
 
 		var launchMsg string
 		if a.currentEpisodeNumber == 0 { // For movies
-			launchMsg += fmt.Sprintf("Launching player: %s\n\n", a.selectedMedia.Title)
+			launchMsg += i18n.T("Launching player: %s", a.selectedMedia.Title) + "\n\n"
 		} else { // For episodes
-			launchMsg += fmt.Sprintf("Launching player: %s - Episode %d\n\n",
-				a.selectedMedia.Title, a.currentEpisodeNumber)
+			launchMsg += i18n.T("Launching player: %s - Episode %d",
+				a.selectedMedia.Title, a.currentEpisodeNumber) + "\n\n"
 		}
 
-		launchMsg += fmt.Sprintf("%s Starting mpv player...\n\n", a.spinner.View())
-		launchMsg += fmt.Sprintf("Timeout in: %.1fs\n\n", remaining.Seconds())
-		launchMsg += styles.HelpStyle.Render("Press 'esc' to cancel, 'q' or Ctrl+C to quit application")
+		launchMsg += a.spinner.View() + " " + i18n.T("Starting mpv player...") + "\n\n"
+		launchMsg += i18n.T("Timeout in: %.1fs", remaining.Seconds()) + "\n\n"
+		launchMsg += styles.HelpStyle.Render(i18n.T("Press 'esc' to cancel, 'q' or Ctrl+C to quit application"))
 
 		return styles.AppStyle.Render(launchMsg)
 	case playingView:
 		// Show background indicator that playback is active
 		var playingMsg string
 		if a.currentEpisodeNumber == 0 { // For movies and single-episode content
-			playingMsg += fmt.Sprintf("▶ Playing: %s", a.selectedMedia.Title)
+			playingMsg += "▶ " + i18n.T("Playing: %s", a.selectedMedia.Title)
 		} else { // For multi-episode series
-			playingMsg += fmt.Sprintf("▶ Playing: %s - Episode %d", a.selectedMedia.Title, a.currentEpisodeNumber)
+			playingMsg += "▶ " + i18n.T("Playing: %s - Episode %d", a.selectedMedia.Title, a.currentEpisodeNumber)
 			// Only add episode title if it's different from media title and not generic
 			if a.currentEpisodeTitle != "" &&
 				a.currentEpisodeTitle != "Movie" &&
@@ -1193,9 +1093,21 @@ func (a *App) renderView() string {
 			}
 		}
 		playingMsg += "\n\n"
-		playingMsg += "Playback is running in mpv player.\n"
-		playingMsg += "UI will return automatically when playback ends.\n\n"
-		playingMsg += "Press 'q' or Ctrl+C to quit application."
+		if a.sleep.active() {
+			playingMsg += "☾ " + i18n.T("Sleep timer: stopping %s", a.sleep.label()) + "\n\n"
+		}
+		if a.lastProgress != nil && a.lastProgress.SubtitleDelay != 0 && !a.incognito {
+			playingMsg += i18n.T("Subtitle delay %+.1fs, remembered for the next episode", a.lastProgress.SubtitleDelay.Seconds()) + "\n\n"
+		}
+		if a.clipStart != nil {
+			playingMsg += "✂ " + i18n.T("Clip from %s, press 'c' where it ends or 'C' to drop it", utils.FormatPosition(*a.clipStart)) + "\n\n"
+		}
+		playingMsg += i18n.T("Playback is running in mpv player.") + "\n"
+		playingMsg += i18n.T("UI will return automatically when playback ends.") + "\n\n"
+		playingMsg += i18n.T("Press 'm' to save this episode's opening and ending to your playlist.") + "\n"
+		playingMsg += i18n.T("Press 'c' to mark the start and end of a clip to export.") + "\n"
+		playingMsg += i18n.T("Press 'n' to cycle loudness normalization (now: %s).", a.normalization) + "\n"
+		playingMsg += i18n.T("Press 'z' to set a sleep timer, 'q' or Ctrl+C to quit application.")
 		return styles.AppStyle.Render(playingMsg)
 	case playbackCompletedView:
 		// Show playback completion message
@@ -1234,12 +1146,12 @@ func (a *App) renderView() string {
 		} else {
 			// If no specific title, we are switching global default
 			isGlobalSwitch = true
-			title = "Global Default"
+			title = i18n.T("Global Default")
 		}
 
-		headerText := "\nSelect provider for: " + title + "\n"
+		headerText := "\n" + i18n.T("Select provider for: %s", title) + "\n"
 		if isGlobalSwitch {
-			headerText = "\nSelect Default Provider\n"
+			headerText = "\n" + i18n.T("Select Default Provider") + "\n"
 		}
 
 		// Add current provider info
@@ -1247,7 +1159,7 @@ func (a *App) renderView() string {
 		if p, ok := a.providers[a.currentMediaType]; ok {
 			currentProvider = p.Name()
 		}
-		headerText += fmt.Sprintf("Current: %s\n\n", currentProvider)
+		headerText += i18n.T("Current: %s", currentProvider) + "\n\n"
 
 		header := lipgloss.NewStyle().
 			Bold(true).
@@ -1261,7 +1173,7 @@ func (a *App) renderView() string {
 		} else {
 			baseView = header + a.providerSelectionResult.View()
 			// Add hint for provider selection actions
-			baseView += "\n" + styles.AniListHelpStyle.Render("  Enter: Just Once • Ctrl+S: Set Default")
+			baseView += "\n" + styles.AniListHelpStyle.Render("  "+i18n.T("Enter: Just Once • Ctrl+S: Set Default"))
 		}
 
 		return baseView
@@ -1278,12 +1190,16 @@ func (a *App) renderView() string {
 			switch a.dialogMode {
 			case anilist.DialogStatus:
 				selectedMedia := a.anilistComponent.GetSelectedMedia()
-				if selectedMedia != nil {
+				if len(a.bulkMedia) > 0 {
+					dialogView = anilist.RenderStatusDialog("", a.dialogState.StatusIndex, string(a.bulkMedia[0].Type))
+				} else if selectedMedia != nil {
 					dialogView = anilist.RenderStatusDialog(string(selectedMedia.Status), a.dialogState.StatusIndex, string(selectedMedia.Type))
 				}
 			case anilist.DialogScore:
 				selectedMedia := a.anilistComponent.GetSelectedMedia()
-				if selectedMedia != nil {
+				if len(a.bulkMedia) > 0 {
+					dialogView = a.anilistComponent.RenderScoreDialog(0, a.dialogState.ScoreInput)
+				} else if selectedMedia != nil {
 					dialogView = a.anilistComponent.RenderScoreDialog(selectedMedia.Score, a.dialogState.ScoreInput)
 				}
 			case anilist.DialogProgress:
@@ -1297,8 +1213,12 @@ func (a *App) renderView() string {
 					statusOptions := []string{"CURRENT", "COMPLETED", "PAUSED", "DROPPED", "PLANNING", "REPEATING"}
 					dialogView = anilist.RenderAddToListDialog(a.currentAniListMedia.Title, statusOptions, a.dialogState.StatusIndex, string(a.currentAniListMedia.Type))
 				}
+			case anilist.DialogCustomList:
+				dialogView = anilist.RenderCustomListDialog(a.anilistComponent.CustomLists(), a.dialogState.ListIndex, len(a.bulkMedia))
 			case anilist.DialogDelete:
-				if a.currentAniListMedia != nil {
+				if len(a.bulkMedia) > 0 {
+					dialogView = anilist.RenderDeleteConfirmationDialog(fmt.Sprintf("%d marked entries", len(a.bulkMedia)))
+				} else if a.currentAniListMedia != nil {
 					dialogView = anilist.RenderDeleteConfirmationDialog(a.currentAniListMedia.Title)
 				}
 			}
@@ -1335,6 +1255,32 @@ func (a *App) renderView() string {
 			return a.audioSelectorModel.View()
 		}
 		return "Audio selector not initialized"
+	case progressConflictView:
+		return a.renderProgressConflict()
+	case whatsNewView:
+		return a.renderWhatsNew()
+	case courOffsetView:
+		return a.renderCourOffset()
+	case resumePromptView:
+		if a.pendingResume == nil {
+			return ""
+		}
+		dialogView := styles.PopupStyle.Render(i18n.T("%s\n\n[r] Resume from %s\n[s] Start over\n[esc] Cancel",
+			a.pendingResume.Options.Title, home.FormatDuration(int(a.pendingResume.Position.Seconds()))))
+		return lipgloss.Place(
+			a.width,
+			a.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			dialogView,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+		)
+	case sourceSelectView:
+		if a.sourceSelectorModel != nil {
+			return a.sourceSelectorModel.View()
+		}
+		return "Source selector not initialized"
 	default:
 		return a.home.View()
 	}
@@ -1383,7 +1329,7 @@ func (a *App) getMangaPages(chapterID string) tea.Cmd {
 			}
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
 		pages, err := mangaProvider.GetMangaPages(ctx, chapterID)
@@ -1410,23 +1356,25 @@ func (a *App) switchProvider(saveAsDefault bool) (tea.Model, tea.Cmd) {
 
 	// Save to config if requested
 	if saveAsDefault {
-		if cfg, ok := a.cfg.(*config.Config); ok {
+		if cfg := a.config(); cfg != nil {
 			switch a.currentMediaType {
 			case providers.MediaTypeAnime:
 				cfg.Providers.Default.Anime = a.providerName
 			case providers.MediaTypeMovie, providers.MediaTypeTV, providers.MediaTypeMovieTV:
 				cfg.Providers.Default.MoviesAndTV = a.providerName
+			case providers.MediaTypeManga:
+				cfg.Providers.Default.Manga = a.providerName
 			}
 
 			if err := cfg.Save(); err != nil {
-				a.statusMsg = fmt.Sprintf("⚠ Failed to save config: %v", err)
+				a.statusMsg = i18n.T("⚠ Failed to save config: %v", err)
 			} else {
-				a.statusMsg = fmt.Sprintf("✓ Default provider set to %s", a.providerName)
+				a.statusMsg = i18n.T("✓ Default provider set to %s", a.providerName)
 			}
 			a.statusMsgTime = time.Now()
 		}
 	} else {
-		a.statusMsg = fmt.Sprintf("✓ Switched to %s (temporary)", a.providerName)
+		a.statusMsg = i18n.T("✓ Switched to %s (temporary)", a.providerName)
 		a.statusMsgTime = time.Now()
 	}
 
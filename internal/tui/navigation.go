@@ -0,0 +1,103 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxNavHistory caps how many pages back/forward remember
+const maxNavHistory = 50
+
+// navHistory is the back/forward history of the pages visited. Transient
+// views (loading, errors, playback, prompts) are not recorded, so going back
+// skips them.
+type navHistory struct {
+	current sessionState // Last page shown
+	back    []sessionState
+	forward []sessionState
+}
+
+// isPage reports whether a view is recorded in the navigation history
+func isPage(state sessionState) bool {
+	switch state {
+	case homeView, searchView, resultsView, seasonView, episodeView, anilistView,
+		downloadsView, historyView, mangaInfoView, providerStatusView:
+		return true
+	}
+	return false
+}
+
+// visit records the page shown after an update; pages opened any other way
+// than back/forward drop the forward history
+func (n *navHistory) visit(state sessionState) {
+	if !isPage(state) || state == n.current {
+		return
+	}
+	n.back = append(n.back, n.current)
+	if len(n.back) > maxNavHistory {
+		n.back = n.back[1:]
+	}
+	n.forward = nil
+	n.current = state
+}
+
+// canShow reports whether the data a page shows is still loaded
+func (a *App) canShow(state sessionState) bool {
+	switch state {
+	case resultsView:
+		return len(a.results.GetMediaResults()) > 0
+	case seasonView:
+		return len(a.seasonsList) > 0
+	case episodeView:
+		return len(a.episodes) > 0
+	case mangaInfoView:
+		return a.mangaInfoComponent != nil
+	}
+	return true
+}
+
+// navigate moves one page through the history: from pops the page to show
+// and to receives the page being left. Pages that can't be shown anymore
+// are skipped.
+func (a *App) navigate(from, to *[]sessionState) (tea.Model, tea.Cmd) {
+	for len(*from) > 0 {
+		state := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		if state == a.nav.current || !a.canShow(state) {
+			continue
+		}
+		*to = append(*to, a.nav.current)
+		a.nav.current = state
+		a.cancelOperation()
+		a.statusMsg = ""
+		a.quitRequested = false
+		a.state = state
+		return a, a.refreshPage(state)
+	}
+	return a, nil
+}
+
+// navigateBack shows the previous page (alt+left)
+func (a *App) navigateBack() (tea.Model, tea.Cmd) {
+	return a.navigate(&a.nav.back, &a.nav.forward)
+}
+
+// navigateForward shows the page left with navigateBack (alt+right)
+func (a *App) navigateForward() (tea.Model, tea.Cmd) {
+	return a.navigate(&a.nav.forward, &a.nav.back)
+}
+
+// refreshPage reloads the pages that show data which may have changed
+// since they were left
+func (a *App) refreshPage(state sessionState) tea.Cmd {
+	switch state {
+	case homeView:
+		return a.home.Init()
+	case historyView:
+		return a.historyComponent.Init()
+	case downloadsView:
+		return a.downloadsComponent.Refresh()
+	case providerStatusView:
+		return a.providerStatusComponent.Init()
+	}
+	return nil
+}
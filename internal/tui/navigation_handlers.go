@@ -3,12 +3,27 @@ package tui
 import (
 	tea "github.com/charmbracelet/bubbletea"
 
-	"github.com/justchokingaround/greg/internal/providers"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/components/anilist"
 	"github.com/justchokingaround/greg/internal/tui/components/downloads"
 )
 
+func init() {
+	handle(func(a *App, _ common.GoToSearchMsg) (tea.Model, tea.Cmd) { return a.handleGoToSearchMsg() })
+	handle(func(a *App, _ common.GoToHomeMsg) (tea.Model, tea.Cmd) { return a.handleGoToHomeMsg() })
+	handle(func(a *App, _ common.BackMsg) (tea.Model, tea.Cmd) { return a.handleBackMsg() })
+	handle(func(a *App, _ common.ToggleProviderMsg) (tea.Model, tea.Cmd) { return a.handleToggleProviderMsg() })
+	handle(func(a *App, _ common.GoToAniListMsg) (tea.Model, tea.Cmd) { return a.handleGoToAniListMsg() })
+	handle(func(a *App, _ common.GoToDownloadsMsg) (tea.Model, tea.Cmd) { return a.handleGoToDownloadsMsg() })
+	handle(func(a *App, _ common.GoToProviderStatusMsg) (tea.Model, tea.Cmd) {
+		return a.handleGoToProviderStatusMsg()
+	})
+	handle((*App).handleDownloadsTickMsg)
+	handle((*App).handleGoToHistoryMsg)
+	handle((*App).handleLibraryLoadedMsg)
+	handle(func(a *App, _ anilist.BackMsg) (tea.Model, tea.Cmd) { return a.handleAnilistBackMsg() })
+}
+
 func (a *App) handleGoToSearchMsg() (tea.Model, tea.Cmd) {
 	a.statusMsg = ""
 	a.state = searchView
@@ -81,32 +96,7 @@ func (a *App) handleBackMsg() (tea.Model, tea.Cmd) {
 }
 
 func (a *App) handleToggleProviderMsg() (tea.Model, tea.Cmd) {
-	if a.searchQueries == nil {
-		a.searchQueries = make(map[providers.MediaType]string)
-	}
-	a.searchQueries[a.currentMediaType] = a.search.GetValue()
-
-	switch a.currentMediaType {
-	case providers.MediaTypeMovieTV:
-		a.currentMediaType = providers.MediaTypeAnime
-	case providers.MediaTypeAnime:
-		a.currentMediaType = providers.MediaTypeManga
-	default:
-		a.currentMediaType = providers.MediaTypeMovieTV
-	}
-	a.home.CurrentMediaType = a.currentMediaType
-	if provider, ok := a.providers[a.currentMediaType]; ok {
-		a.home.SetProvider(provider.Name())
-		a.helpComponent.SetProviderName(provider.Name())
-	}
-
-	if query, ok := a.searchQueries[a.currentMediaType]; ok {
-		a.search.SetValue(query)
-	} else {
-		a.search.SetValue("")
-	}
-
-	return a, a.home.Init()
+	return a.switchToMediaType(a.nextMediaType())
 }
 
 func (a *App) handleGoToAniListMsg() (tea.Model, tea.Cmd) {
@@ -164,6 +154,7 @@ func (a *App) handleLibraryLoadedMsg(msg anilist.LibraryLoadedMsg) (tea.Model, t
 	}
 
 	a.anilistComponent.SetLibrary(msg.Library)
+	a.anilistComponent.SetLastWatched(msg.LastWatched)
 	a.state = anilistView
 	return a, tea.ClearScreen
 }
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+// audioNormalizer is a player whose loudness normalization can be switched
+// during playback
+type audioNormalizer interface {
+	SetAudioNormalization(ctx context.Context, mode string) error
+}
+
+// configuredNormalization returns player.audio_normalization, "off" when unset
+func (a *App) configuredNormalization() string {
+	if cfg := a.playerConfig(); cfg != nil && slices.Contains(player.NormalizationModes, cfg.AudioNormalization) {
+		return cfg.AudioNormalization
+	}
+	return player.NormalizationOff
+}
+
+// applyAudioNormalization sets the loudness options for the show about to
+// play: its own override, else the configured mode
+func (a *App) applyAudioNormalization(options *player.PlayOptions) {
+	mode := a.configuredNormalization()
+	if cfg := a.playerConfig(); cfg != nil {
+		options.ReplayGain = cfg.ReplayGain
+	}
+
+	if a.db != nil {
+		mediaID, _, _ := a.playbackMedia(a.playingAniListID(), a.currentEpisodeNumber)
+		if mediaID != "" {
+			override, err := database.GetAudioNormalization(a.db, mediaID)
+			if err != nil {
+				a.logger.Warn("failed to load audio normalization", "error", err)
+			} else if slices.Contains(player.NormalizationModes, override) {
+				mode = override
+			}
+		}
+	}
+
+	a.normalization = mode
+	options.AudioNormalization = mode
+}
+
+// cycleNormalization switches the playback to the next normalization mode
+// and remembers it for the show
+func (a *App) cycleNormalization() tea.Cmd {
+	normalizer, ok := a.player.(audioNormalizer)
+	if !ok {
+		return nil
+	}
+
+	i := slices.Index(player.NormalizationModes, a.normalization)
+	next := player.NormalizationModes[(i+1)%len(player.NormalizationModes)]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := normalizer.SetAudioNormalization(ctx, next); err != nil {
+		a.logger.Warn("failed to switch audio normalization", "error", err)
+		return a.toast(severityError, i18n.T("Couldn't change normalization: %v", err))
+	}
+	a.normalization = next
+
+	if a.db == nil || a.incognito {
+		return a.toast(severityInfo, i18n.T("Normalization: %s", next))
+	}
+	mediaID, _, _ := a.playbackMedia(a.playingAniListID(), a.currentEpisodeNumber)
+	if mediaID == "" {
+		return a.toast(severityInfo, i18n.T("Normalization: %s", next))
+	}
+	// Back at the configured mode, the show follows the config again
+	override := next
+	if next == a.configuredNormalization() {
+		override = ""
+	}
+	if err := database.SaveAudioNormalization(a.db, mediaID, override); err != nil {
+		a.logger.Warn("failed to save audio normalization", "error", err)
+	}
+	return a.toast(severityInfo, i18n.T("Normalization: %s, remembered for this show", next))
+}
@@ -0,0 +1,297 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+func init() {
+	handle((*App).handleNotificationMsg)
+	handle((*App).handleToastExpiredMsg)
+	handle(func(a *App, _ providerHealthTickMsg) (tea.Model, tea.Cmd) { return a.handleProviderHealthTickMsg() })
+}
+
+const (
+	toastDuration       = 4 * time.Second
+	maxToasts           = 3
+	maxNotifications    = 100
+	toastWidth          = 44
+	providerHealthCheck = 30 * time.Second // How often provider health is looked at
+)
+
+// severity is how important a notification is
+type severity int
+
+const (
+	severityInfo severity = iota
+	severitySuccess
+	severityWarning
+	severityError
+)
+
+// icon returns the icon status messages of the severity start with
+func (s severity) icon() string {
+	switch s {
+	case severitySuccess:
+		return "✓"
+	case severityWarning:
+		return "⚠"
+	case severityError:
+		return "✗"
+	default:
+		return "ℹ"
+	}
+}
+
+// color returns the color the status bar uses for the severity
+func (s severity) color() lipgloss.Color {
+	switch s {
+	case severitySuccess:
+		return styles.OxocarbonGreen
+	case severityWarning:
+		return styles.OxocarbonTeal
+	case severityError:
+		return styles.OxocarbonPink
+	default:
+		return styles.OxocarbonCyan
+	}
+}
+
+// severityOf returns the severity of a status message from its icon, and
+// the message without it
+func severityOf(text string) (severity, string) {
+	for _, s := range []severity{severitySuccess, severityWarning, severityError, severityInfo} {
+		if rest, ok := strings.CutPrefix(text, s.icon()); ok {
+			return s, strings.TrimSpace(rest)
+		}
+	}
+	return severityInfo, strings.TrimSpace(text)
+}
+
+// notification is an entry of the notification center
+type notification struct {
+	id    int
+	level severity
+	text  string
+	at    time.Time
+}
+
+// notificationCenter keeps the notifications of the session: toasts shown
+// in the top-right corner and the history opened with ctrl+n
+type notificationCenter struct {
+	history    []notification // Oldest first
+	toasts     []notification
+	nextID     int
+	lastStatus string          // Status bar message already in the history
+	unhealthy  map[string]bool // Providers already reported as not responding
+	open       bool
+	offset     int // First visible history entry
+}
+
+// notificationMsg carries a notification from a background goroutine
+type notificationMsg struct {
+	level severity
+	text  string
+}
+
+// toastExpiredMsg hides a toast
+type toastExpiredMsg struct {
+	id int
+}
+
+// providerHealthTickMsg looks for providers that stopped responding
+type providerHealthTickMsg struct{}
+
+// add appends a notification to the history
+func (n *notificationCenter) add(level severity, text string) notification {
+	n.nextID++
+	note := notification{id: n.nextID, level: level, text: text, at: time.Now()}
+	n.history = append(n.history, note)
+	if len(n.history) > maxNotifications {
+		n.history = n.history[len(n.history)-maxNotifications:]
+	}
+	return note
+}
+
+// toast shows a notification in the top-right corner without blocking input
+// and keeps it in the history
+func (a *App) toast(level severity, text string) tea.Cmd {
+	note := a.notes.add(level, text)
+	a.notes.toasts = append(a.notes.toasts, note)
+	if len(a.notes.toasts) > maxToasts {
+		a.notes.toasts = a.notes.toasts[len(a.notes.toasts)-maxToasts:]
+	}
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: note.id}
+	})
+}
+
+// notifyAsync sends a toast from a background goroutine. It never blocks:
+// the notification is dropped when the message queue is full.
+func (a *App) notifyAsync(level severity, text string) {
+	select {
+	case a.msgChan <- notificationMsg{level: level, text: text}:
+	default:
+		a.logger.Warn("dropped notification", "text", text)
+	}
+}
+
+// recordStatus keeps status bar messages in the notification history
+func (a *App) recordStatus() {
+	if a.statusMsg == a.notes.lastStatus {
+		return
+	}
+	a.notes.lastStatus = a.statusMsg
+	if a.statusMsg != "" {
+		level, text := severityOf(a.statusMsg)
+		a.notes.add(level, text)
+	}
+}
+
+func (a *App) handleNotificationMsg(msg notificationMsg) (tea.Model, tea.Cmd) {
+	return a, tea.Batch(a.toast(msg.level, msg.text), a.listenForMessages())
+}
+
+func (a *App) handleToastExpiredMsg(msg toastExpiredMsg) (tea.Model, tea.Cmd) {
+	for i, note := range a.notes.toasts {
+		if note.id == msg.id {
+			a.notes.toasts = append(a.notes.toasts[:i], a.notes.toasts[i+1:]...)
+			break
+		}
+	}
+	return a, nil
+}
+
+// checkProviderHealth schedules the next look at provider health
+func checkProviderHealth() tea.Cmd {
+	return tea.Tick(providerHealthCheck, func(time.Time) tea.Msg {
+		return providerHealthTickMsg{}
+	})
+}
+
+// handleProviderHealthTickMsg reports providers in use whose last health
+// check failed, once until they recover
+func (a *App) handleProviderHealthTickMsg() (tea.Model, tea.Cmd) {
+	inUse := make(map[string]bool)
+	for _, p := range a.providers {
+		inUse[p.Name()] = true
+	}
+	if a.notes.unhealthy == nil {
+		a.notes.unhealthy = make(map[string]bool)
+	}
+
+	cmds := []tea.Cmd{checkProviderHealth()}
+	for _, status := range providers.GetProviderStatuses() {
+		if !inUse[status.ProviderName] || status.LastResult == nil {
+			continue
+		}
+		if status.Healthy {
+			delete(a.notes.unhealthy, status.ProviderName)
+			continue
+		}
+		if !a.notes.unhealthy[status.ProviderName] {
+			a.notes.unhealthy[status.ProviderName] = true
+			cmds = append(cmds, a.toast(severityWarning, i18n.T("Provider %s is not responding", status.ProviderName)))
+		}
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// handleNotificationCenterKeys handles keys while the notification center
+// is open; it takes every key like the help overlay
+func (a *App) handleNotificationCenterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "ctrl+n":
+		a.notes.open = false
+	case "up", "k":
+		if a.notes.offset > 0 {
+			a.notes.offset--
+		}
+	case "down", "j":
+		if a.notes.offset < len(a.notes.history)-1 {
+			a.notes.offset++
+		}
+	case "c":
+		a.notes.history = nil
+		a.notes.offset = 0
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// renderToasts draws the visible toasts over the top-right corner of view
+func (a *App) renderToasts(view string) string {
+	if len(a.notes.toasts) == 0 || a.width == 0 {
+		return view
+	}
+	width := toastWidth
+	if width > a.width {
+		width = a.width
+	}
+
+	var toasts []string
+	for _, note := range a.notes.toasts {
+		toast := lipgloss.NewStyle().
+			Width(width).
+			Background(note.level.color()).
+			Foreground(styles.OxocarbonBase00).
+			Bold(true).
+			Padding(0, 1).
+			Render(utils.Truncate(note.level.icon()+" "+note.text, width-2))
+		toasts = append(toasts, toast)
+	}
+	return utils.OverlayTopRight(view, strings.Join(toasts, "\n"), a.width)
+}
+
+// renderNotificationCenter renders the notification history, newest first
+func (a *App) renderNotificationCenter() string {
+	width := 70
+	if a.width > 0 && a.width-4 < width {
+		width = a.width - 4
+	}
+	visible := 15
+	if a.height > 0 && a.height-10 < visible {
+		visible = a.height - 10
+	}
+	if visible < 3 {
+		visible = 3
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.TitleStyle.Render("  " + i18n.T("NOTIFICATIONS") + "  "))
+	b.WriteString("\n\n")
+
+	if len(a.notes.history) == 0 {
+		b.WriteString(styles.AniListMetadataStyle.Render(i18n.T("No notifications yet")))
+		b.WriteString("\n")
+	}
+	shown := 0
+	for i := len(a.notes.history) - 1 - a.notes.offset; i >= 0 && shown < visible; i-- {
+		note := a.notes.history[i]
+		icon := lipgloss.NewStyle().Foreground(note.level.color()).Bold(true).Render(note.level.icon())
+		when := styles.AniListMetadataStyle.Render(note.at.Format("15:04:05"))
+		text := utils.Truncate(note.text, width-16)
+		b.WriteString(fmt.Sprintf("%s %s  %s\n", when, icon, text))
+		shown++
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.AniListHelpStyle.Render(i18n.T("↑/↓ scroll • c clear • esc close")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.OxocarbonPurple).
+		Padding(1, 2).
+		Width(width).
+		Render(b.String())
+}
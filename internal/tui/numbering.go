@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/metadata/numbering"
+	"github.com/justchokingaround/greg/internal/metadata/tmdb"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+)
+
+// episodeNumbering returns the numbering of the TMDB show an AniList entry
+// is part of, from the cross-ID mapping datasets in metadata.id_mappings_dir
+func (a *App) episodeNumbering(anilistID int) (*numbering.Translator, bool) {
+	cfg := a.config()
+	if cfg == nil || anilistID <= 0 {
+		return nil, false
+	}
+	dataset, err := numbering.Load(cfg.Metadata.IDMappingsPath())
+	if err != nil {
+		a.debugLog("ID mapping datasets: %v", err)
+		return nil, false
+	}
+	return dataset.Translator(anilistID)
+}
+
+// absoluteStart returns how many episodes of its show come before an AniList
+// entry's first, when the cross-ID mapping datasets know it
+func (a *App) absoluteStart(anilistID int) (int, bool) {
+	translator, ok := a.episodeNumbering(anilistID)
+	if !ok {
+		return 0, false
+	}
+	start, ok := translator.Start(anilistID)
+	return start - 1, ok
+}
+
+// listsPastEntry reports whether a provider numbers an AniList entry's
+// episodes past its end, which is how it lists a long series absolutely
+func listsPastEntry(tracked *tracker.TrackedMedia, episodes []providers.Episode) bool {
+	if tracked == nil || tracked.TotalEpisodes == 0 {
+		return false
+	}
+	for _, ep := range episodes {
+		if ep.Number > tracked.TotalEpisodes {
+			return true
+		}
+	}
+	return false
+}
+
+// fillAnimeSeasonMetadata fills episode titles, synopses, air dates, runtimes
+// and thumbnails of an anime the cross-ID mapping datasets place in a TMDB
+// show, translating the provider's numbers into TMDB seasons. It does
+// nothing without a TMDB API key.
+func (a *App) fillAnimeSeasonMetadata(tracked *tracker.TrackedMedia, episodes []providers.Episode) {
+	cfg := a.config()
+	if cfg == nil || cfg.Metadata.TMDBAPIKey == "" || tracked == nil || len(episodes) == 0 {
+		return
+	}
+	anilistID := extractAniListID(tracked.ServiceID)
+	translator, ok := a.episodeNumbering(anilistID)
+	if !ok {
+		return
+	}
+	start, ok := translator.Start(anilistID)
+	if !ok {
+		return
+	}
+	// Providers listing past the entry number from the start of the show
+	base := start - 1
+	if listsPastEntry(tracked, episodes) {
+		base = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	client := tmdb.NewClient(cfg.Metadata.TMDBAPIKey)
+	seasons := make(map[int]map[int]tmdb.Episode)
+	for i := range episodes {
+		season, number, ok := translator.Seasonal(base + episodes[i].Number)
+		if !ok {
+			continue
+		}
+		byNumber, fetched := seasons[season]
+		if !fetched {
+			seasonEpisodes, err := client.GetSeason(ctx, translator.TMDBID, season)
+			if err != nil {
+				a.debugLog("TMDB: season %d lookup failed for show %d: %v", season, translator.TMDBID, err)
+			}
+			byNumber = make(map[int]tmdb.Episode, len(seasonEpisodes))
+			for _, ep := range seasonEpisodes {
+				byNumber[ep.Number] = ep
+			}
+			seasons[season] = byNumber
+		}
+		if ep, found := byNumber[number]; found {
+			fillEpisode(&episodes[i], providers.Episode{
+				Title:        ep.Title,
+				Synopsis:     ep.Overview,
+				ReleaseDate:  ep.AirDate,
+				Duration:     ep.Runtime,
+				ThumbnailURL: ep.Still,
+			})
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/i18n"
+)
+
+func init() {
+	handle(func(a *App, _ operationCancelledMsg) (tea.Model, tea.Cmd) {
+		// Result of an operation the user backed out of
+		return a, nil
+	})
+}
+
+// operationCancelledMsg replaces the result of an operation the user backed
+// out of, so a late result doesn't yank them to another view
+type operationCancelledMsg struct{}
+
+// beginOperation cancels the loading operation in flight and returns the
+// context for a new one. Must be called from Update, not from a tea.Cmd.
+func (a *App) beginOperation() context.Context {
+	a.cancelOperation()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.opCancel = cancel
+	return ctx
+}
+
+// cancelOperation aborts the loading operation in flight, if any
+func (a *App) cancelOperation() {
+	if a.opCancel != nil {
+		a.opCancel()
+		a.opCancel = nil
+	}
+}
+
+// withOperation runs run as the current loading operation. Its context is
+// cancelled when the user backs out or another operation starts, and the
+// result of a cancelled run is dropped.
+func (a *App) withOperation(run func(opCtx context.Context) tea.Msg) tea.Cmd {
+	opCtx := a.beginOperation()
+	a.retryOp, a.retryLoadingOp = run, a.loadingOp
+	return func() tea.Msg {
+		msg := run(opCtx)
+		if opCtx.Err() != nil {
+			return operationCancelledMsg{}
+		}
+		return msg
+	}
+}
+
+// providerTimeout returns the time allowed for a sequence of calls to the
+// named provider, as configured under network.timeouts
+func (a *App) providerTimeout(provider string, kinds ...config.TimeoutKind) time.Duration {
+	var timeouts config.TimeoutsConfig
+	if appCfg := a.config(); appCfg != nil {
+		timeouts = appCfg.Network.Timeouts
+	}
+	return timeouts.Budget(provider, kinds...)
+}
+
+// abortOperation cancels the loading operation and returns to the view it
+// was started from
+func (a *App) abortOperation() (tea.Model, tea.Cmd) {
+	a.cancelOperation()
+
+	switch a.loadingOp {
+	case loadingSearch:
+		a.state = searchView
+	case loadingAniListLibrary:
+		a.state = homeView
+	case loadingProviderSearch:
+		a.state = anilistView
+	case loadingEpisodes:
+		if len(a.seasonsList) > 1 {
+			a.state = seasonView
+		} else {
+			a.state = resultsView
+		}
+	case loadingStream, loadingMangaPages:
+		if len(a.episodes) > 0 {
+			a.state = episodeView
+		} else {
+			a.state = resultsView
+		}
+	default:
+		a.state = resultsView
+	}
+	if a.watchingFromAniList && a.state != homeView {
+		a.state = anilistView
+	}
+	a.loadingOp = 0
+
+	a.statusMsg = i18n.T("Cancelled")
+	a.statusMsgTime = time.Now()
+	return a, func() tea.Msg {
+		time.Sleep(2 * time.Second)
+		return clearStatusMsg{}
+	}
+}
@@ -0,0 +1,391 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+	"github.com/justchokingaround/greg/internal/tui/components/anilist"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+func init() {
+	handle((*App).handleDownloadPlanningMsg)
+	handle((*App).handlePlanningEntryMsg)
+}
+
+// planningRun queues the episodes of the Planning list one entry at a time.
+// What it queues is kept by the download queue across restarts, and a new
+// run skips the episodes already queued or downloaded without resolving
+// them, so an interrupted run picks up where it stopped.
+type planningRun struct {
+	queuer  planningQueuer
+	entries []tracker.TrackedMedia // Entries left, the first one is being queued
+	total   int                    // Entries at the start
+	queued  int                    // Episodes queued
+	skipped int                    // Episodes already queued or downloaded
+	passed  int                    // Entries skipped at the prompt
+	failed  []string               // Entries not or only partly queued
+	prompt  *planningPrompt        // Search results waiting for the user's pick
+}
+
+// planningPrompt asks which of a provider's search results an entry is
+type planningPrompt struct {
+	provider string
+	results  []providers.Media
+	cursor   int
+}
+
+// planningEntryMsg reports the first entry of the run queued, or the pick
+// it waits for
+type planningEntryMsg struct {
+	queued  int
+	skipped int
+	prompt  *planningPrompt
+	err     error
+	stop    bool // Nothing else fits either, the disk is full
+}
+
+// planningQueuer maps Planning entries to providers and queues their
+// episodes, in the background
+type planningQueuer struct {
+	mappings MappingStore
+	manager  *downloader.Manager
+	timeouts config.TimeoutsConfig
+	quality  providers.Quality
+}
+
+// handleDownloadPlanningMsg starts queuing the Planning list
+func (a *App) handleDownloadPlanningMsg(msg anilist.DownloadPlanningMsg) (*App, tea.Cmd) {
+	if a.downloadMgr == nil || a.mappingMgr == nil {
+		return a, a.toast(severityError, i18n.T("Downloads are not available"))
+	}
+	if a.planning != nil {
+		return a, a.toast(severityWarning, i18n.T("The Planning list is already being queued"))
+	}
+	if len(msg.Media) == 0 {
+		return a, nil
+	}
+
+	// Search the provider in use first, like playback from AniList does
+	if p, ok := a.providers[providers.MediaTypeAnime]; ok && p != nil {
+		a.mappingMgr.SetPreferredProvider(providers.MediaTypeAnime, p.Name())
+	}
+	var timeouts config.TimeoutsConfig
+	if cfg := a.config(); cfg != nil {
+		timeouts = cfg.Network.Timeouts
+	}
+
+	a.planning = &planningRun{
+		queuer: planningQueuer{
+			mappings: a.mappingMgr,
+			manager:  a.downloadMgr,
+			timeouts: timeouts,
+			quality:  msg.Quality,
+		},
+		entries: msg.Media,
+		total:   len(msg.Media),
+	}
+	return a, tea.Batch(
+		a.toast(severityInfo, i18n.T("Queuing %d Planning entries at %s...", len(msg.Media), msg.Quality)),
+		a.queuePlanningEntry("", nil),
+	)
+}
+
+// queuePlanningEntry queues the next entry of the run, with the user's pick
+// among provider's results when it was asked for, or finishes the run
+func (a *App) queuePlanningEntry(provider string, pick *providers.Media) tea.Cmd {
+	run := a.planning
+	if len(run.entries) == 0 {
+		return a.finishPlanning()
+	}
+
+	a.statusMsg = i18n.T("Queuing the Planning list: %d/%d entries, %d episodes queued",
+		run.total-len(run.entries)+1, run.total, run.queued)
+	a.statusMsgTime = time.Now()
+
+	queuer, media := run.queuer, run.entries[0]
+	return func() tea.Msg {
+		return queuer.queue(context.Background(), media, provider, pick)
+	}
+}
+
+// handlePlanningEntryMsg counts an entry of the run and moves to the next
+func (a *App) handlePlanningEntryMsg(msg planningEntryMsg) (*App, tea.Cmd) {
+	run := a.planning
+	if run == nil || len(run.entries) == 0 {
+		return a, nil
+	}
+	if msg.prompt != nil {
+		run.prompt = msg.prompt
+		return a, nil
+	}
+
+	media := run.entries[0]
+	run.entries = run.entries[1:]
+	run.queued += msg.queued
+	run.skipped += msg.skipped
+	if msg.err != nil {
+		a.logger.Warn("failed to queue planning entry", "title", media.Title, "error", msg.err)
+		run.failed = append(run.failed, media.Title)
+	}
+	if msg.stop {
+		run.entries = nil
+		return a, tea.Batch(a.finishPlanning(), a.toast(severityError, i18n.T("✗ Download not queued: %v", msg.err)))
+	}
+
+	cmds := []tea.Cmd{a.queuePlanningEntry("", nil)}
+	if a.state == downloadsView {
+		cmds = append(cmds, a.downloadsComponent.Refresh())
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// finishPlanning ends the run and sums it up
+func (a *App) finishPlanning() tea.Cmd {
+	run := a.planning
+	a.planning = nil
+	a.statusMsg = ""
+
+	text := i18n.T("Queued %d episodes from the Planning list", run.queued)
+	if run.skipped > 0 {
+		text += i18n.T(", %d were already queued or downloaded", run.skipped)
+	}
+	if run.passed > 0 {
+		text += i18n.T(", skipped %d entries", run.passed)
+	}
+	cmds := []tea.Cmd{a.toast(severitySuccess, text)}
+	if len(run.failed) > 0 {
+		cmds = append(cmds, a.toast(severityWarning, i18n.T("Not everything of %s could be queued, see the log", strings.Join(run.failed, ", "))))
+	}
+	if a.state == downloadsView {
+		cmds = append(cmds, a.downloadsComponent.Refresh())
+	}
+	return tea.Batch(cmds...)
+}
+
+// handlePlanningPromptKeys handles the pick among an entry's search results
+func (a *App) handlePlanningPromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	run := a.planning
+	prompt := run.prompt
+	switch msg.String() {
+	case "up", "k":
+		if prompt.cursor > 0 {
+			prompt.cursor--
+		}
+	case "down", "j":
+		if prompt.cursor < len(prompt.results)-1 {
+			prompt.cursor++
+		}
+	case "enter":
+		pick := prompt.results[prompt.cursor]
+		run.prompt = nil
+		return a, a.queuePlanningEntry(prompt.provider, &pick)
+	case "s", "S":
+		run.prompt = nil
+		run.entries = run.entries[1:]
+		run.passed++
+		return a, a.queuePlanningEntry("", nil)
+	case "esc":
+		// What was queued stays queued
+		run.prompt = nil
+		run.entries = nil
+		return a, a.finishPlanning()
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// renderPlanningPrompt renders the pick among an entry's search results
+func (a *App) renderPlanningPrompt() string {
+	run := a.planning
+	prompt := run.prompt
+
+	text := i18n.T("Which one is %s?", run.entries[0].Title) + "\n"
+	text += styles.AniListMetadataStyle.Render(i18n.T("Planning entry %d of %d, results from %s",
+		run.total-len(run.entries)+1, run.total, prompt.provider)) + "\n\n"
+
+	// A window of results around the cursor
+	const shown = 10
+	start := max(0, min(prompt.cursor-shown/2, len(prompt.results)-shown))
+	for i := start; i < len(prompt.results) && i < start+shown; i++ {
+		result := prompt.results[i]
+		line := result.Title
+		if result.Year > 0 {
+			line += fmt.Sprintf(" (%d)", result.Year)
+		}
+		if result.TotalEpisodes > 0 {
+			line += i18n.T(" • %d eps", result.TotalEpisodes)
+		}
+		if i == prompt.cursor {
+			text += lipgloss.NewStyle().Foreground(styles.OxocarbonPurple).Bold(true).Render("▸ "+line) + "\n"
+		} else {
+			text += "  " + line + "\n"
+		}
+	}
+
+	return styles.PopupStyle.Render(text + "\n" + i18n.T("[enter] Pick  [s] Skip this entry  [esc] Stop"))
+}
+
+// queue maps an entry to a provider and queues its aired episodes. pick is
+// the user's choice among provider's search results, when they were asked.
+func (q planningQueuer) queue(ctx context.Context, media tracker.TrackedMedia, provider string, pick *providers.Media) planningEntryMsg {
+	anilistID := extractAniListID(media.ServiceID)
+	if pick != nil {
+		if err := q.mappings.SelectMapping(ctx, anilistID, provider, *pick); err != nil {
+			return planningEntryMsg{err: fmt.Errorf("failed to save mapping: %w", err)}
+		}
+	}
+
+	searchCtx, cancel := context.WithTimeout(ctx, q.timeouts.Budget("", config.TimeoutSearch))
+	mapped, results, foundBy, err := q.mappings.GetOrCreateMapping(searchCtx, anilistID, media.Title, providers.MediaTypeAnime)
+	cancel()
+	if err != nil {
+		return planningEntryMsg{err: err}
+	}
+
+	if mapped == nil {
+		var named []providers.Media
+		for _, result := range results {
+			if strings.TrimSpace(result.Title) != "" {
+				named = append(named, result)
+			}
+		}
+		match := unambiguousMatch(media.Title, named)
+		if match == nil {
+			if len(named) == 0 {
+				return planningEntryMsg{err: fmt.Errorf("%q not found on any provider", media.Title)}
+			}
+			return planningEntryMsg{prompt: &planningPrompt{provider: foundBy, results: named}}
+		}
+		if err := q.mappings.SelectMapping(ctx, anilistID, foundBy, *match); err != nil {
+			return planningEntryMsg{err: fmt.Errorf("failed to save mapping: %w", err)}
+		}
+		mapped = &mapping.ProviderMapping{AniListID: anilistID, ProviderName: foundBy, ProviderMediaID: match.ID}
+	}
+	return q.queueEpisodes(ctx, media, mapped)
+}
+
+// unambiguousMatch returns the search result that is clearly the entry: the
+// only one, or the only one with the same title
+func unambiguousMatch(title string, results []providers.Media) *providers.Media {
+	if len(results) == 1 {
+		return &results[0]
+	}
+	var match *providers.Media
+	for i := range results {
+		if providers.SameTitle(results[i].Title, title) {
+			if match != nil {
+				return nil
+			}
+			match = &results[i]
+		}
+	}
+	return match
+}
+
+// queueEpisodes queues the episodes of the provider's media that belong to
+// the entry and have aired
+func (q planningQueuer) queueEpisodes(ctx context.Context, media tracker.TrackedMedia, mapped *mapping.ProviderMapping) planningEntryMsg {
+	p, err := providers.Get(mapped.ProviderName)
+	if err != nil {
+		return planningEntryMsg{err: err}
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, q.timeouts.Budget(p.Name(), config.TimeoutDetails, config.TimeoutDetails))
+	episodes, err := listEpisodes(listCtx, p, mapped.ProviderMediaID)
+	cancel()
+	if err != nil {
+		return planningEntryMsg{err: err}
+	}
+
+	// Episodes queued or downloaded before aren't resolved again
+	tasks, err := q.manager.GetQueue(ctx)
+	if err != nil {
+		return planningEntryMsg{err: err}
+	}
+	have := make(map[int]bool)
+	for _, task := range tasks {
+		if task.MediaID == mapped.ProviderMediaID && task.Status != downloader.StatusFailed && task.Status != downloader.StatusCancelled {
+			have[task.Episode] = true
+		}
+	}
+
+	var msg planningEntryMsg
+	aired := media.AiredEpisodes()
+	for _, ep := range episodes {
+		// Episodes of another cour, or not out yet
+		if number := mapped.AniListEpisode(ep.Number); number <= 0 || (aired > 0 && number > aired) {
+			continue
+		}
+		if have[ep.Number] {
+			msg.skipped++
+			continue
+		}
+
+		streamCtx, cancel := context.WithTimeout(ctx, q.timeouts.Budget(p.Name(), config.TimeoutStream))
+		stream, err := p.GetStreamURL(streamCtx, ep.ID, q.quality)
+		cancel()
+		if err != nil {
+			msg.err = fmt.Errorf("episode %d: %w", ep.Number, err)
+			continue
+		}
+
+		err = q.manager.AddToQueue(ctx, downloader.DownloadTask{
+			MediaID:    mapped.ProviderMediaID,
+			MediaTitle: media.Title,
+			MediaType:  providers.MediaTypeAnime,
+			Episode:    ep.Number,
+			Quality:    q.quality,
+			Provider:   p.Name(),
+			StreamURL:  stream.URL,
+			StreamType: stream.Type,
+			Headers:    stream.Headers,
+			Referer:    stream.Referer,
+			Subtitles:  stream.Subtitles,
+			EmbedSubs:  true,
+		})
+		switch {
+		case err == nil:
+			msg.queued++
+		case errors.Is(err, downloader.ErrInsufficientSpace):
+			msg.err, msg.stop = err, true
+			return msg
+		default:
+			if _, dup := asDuplicate(err); dup || strings.Contains(err.Error(), "already in queue") {
+				msg.skipped++
+			} else {
+				msg.err = fmt.Errorf("episode %d: %w", ep.Number, err)
+			}
+		}
+	}
+	return msg
+}
+
+// listEpisodes returns the episodes of the first season of mediaID on p
+func listEpisodes(ctx context.Context, p providers.Provider, mediaID string) ([]providers.Episode, error) {
+	details, err := p.GetMediaDetails(ctx, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get details from %s: %w", p.Name(), err)
+	}
+	if details == nil || len(details.Seasons) == 0 {
+		return nil, fmt.Errorf("no episodes found on %s", p.Name())
+	}
+	episodes, err := p.GetEpisodes(ctx, details.Seasons[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get episodes from %s: %w", p.Name(), err)
+	}
+	return episodes, nil
+}
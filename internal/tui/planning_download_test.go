@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	fakeprovider "github.com/justchokingaround/greg/internal/providers/fake"
+	"github.com/justchokingaround/greg/internal/tracker"
+	"github.com/justchokingaround/greg/internal/tracker/mapping"
+)
+
+func TestPlanningQueuer(t *testing.T) {
+	p := fakeprovider.New("planning", providers.MediaTypeAnime, frieren,
+		fakeprovider.Title{Media: providers.Media{ID: "meshi", Title: "Dungeon Meshi", Type: providers.MediaTypeAnime}, Seasons: []int{2}},
+		fakeprovider.Title{Media: providers.Media{ID: "meshi-ova", Title: "Dungeon Meshi OVA", Type: providers.MediaTypeAnime}, Seasons: []int{1}},
+	)
+	require.NoError(t, providers.Register(p))
+	t.Cleanup(func() { _ = providers.Unregister(p.Name()) })
+
+	db := newHeadlessDB(t)
+	// Not started, so what is queued stays queued
+	manager, err := downloader.NewManager(db, &config.DownloadsConfig{
+		Path: t.TempDir(), Concurrent: 1, AnimeFilenameTemplate: "{title} - {episode:03d} [{quality}]",
+	}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	q := planningQueuer{
+		mappings: mapping.NewManager(db, "planning", slog.New(slog.DiscardHandler)),
+		manager:  manager,
+		quality:  providers.Quality720p,
+	}
+	ctx := context.Background()
+
+	// Aired episodes only
+	planned := tracker.TrackedMedia{ServiceID: "anilist:154587", Title: "Frieren", Type: providers.MediaTypeAnime,
+		Status: tracker.StatusPlanToWatch, TotalEpisodes: 4, NextEpisode: 4, AiringStatus: "RELEASING"}
+	msg := q.queue(ctx, planned, "", nil)
+	require.NoError(t, msg.err)
+	assert.Equal(t, 3, msg.queued)
+
+	tasks, err := manager.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "planning", tasks[0].Provider)
+	assert.Equal(t, providers.Quality720p, tasks[0].Quality)
+	assert.Equal(t, []int{1, 2, 3}, []int{tasks[0].Episode, tasks[1].Episode, tasks[2].Episode})
+
+	// Running again picks up where the last run stopped
+	planned.NextEpisode = 0
+	planned.AiringStatus = "FINISHED"
+	msg = q.queue(ctx, planned, "", nil)
+	require.NoError(t, msg.err)
+	assert.Equal(t, 1, msg.queued)
+	assert.Equal(t, 3, msg.skipped)
+
+	// Two results without the entry's title are asked about
+	meshi := tracker.TrackedMedia{ServiceID: "anilist:153518", Title: "Dungeon", Type: providers.MediaTypeAnime, Status: tracker.StatusPlanToWatch}
+	msg = q.queue(ctx, meshi, "", nil)
+	require.NotNil(t, msg.prompt)
+	require.Len(t, msg.prompt.results, 2)
+	assert.Equal(t, "planning", msg.prompt.provider)
+
+	picked := msg.prompt.results[0]
+	msg = q.queue(ctx, meshi, msg.prompt.provider, &picked)
+	require.NoError(t, msg.err)
+	assert.Equal(t, map[string]int{"meshi": 2, "meshi-ova": 1}[picked.ID], msg.queued)
+	mapped, err := q.mappings.GetMapping(ctx, 153518)
+	require.NoError(t, err)
+	require.NotNil(t, mapped)
+	assert.Equal(t, picked.ID, mapped.ProviderMediaID, "the pick is remembered")
+}
+
+func TestUnambiguousMatch(t *testing.T) {
+	one := []providers.Media{{ID: "a", Title: "Something Else"}}
+	assert.Equal(t, "a", unambiguousMatch("Frieren", one).ID, "a single result is used")
+
+	results := []providers.Media{{ID: "a", Title: "Frieren: Beyond Journey's End"}, {ID: "b", Title: "Frieren"}}
+	assert.Equal(t, "b", unambiguousMatch("frieren", results).ID)
+	assert.Nil(t, unambiguousMatch("Sousou no Frieren", results))
+	assert.Nil(t, unambiguousMatch("Frieren", append(results, providers.Media{ID: "c", Title: "FRIEREN"})))
+}
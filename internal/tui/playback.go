@@ -5,6 +5,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"runtime"
@@ -16,20 +17,50 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/justchokingaround/greg/internal/audio"
+	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
 	"github.com/justchokingaround/greg/internal/player"
 	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/providers/manual"
+	"github.com/justchokingaround/greg/internal/resolve"
+	"github.com/justchokingaround/greg/internal/scripting"
 	"github.com/justchokingaround/greg/internal/tracker"
-	"github.com/justchokingaround/greg/internal/tracker/mapping"
 	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/components/audioselect"
+	"github.com/justchokingaround/greg/internal/tui/components/sourceselect"
 	"github.com/justchokingaround/greg/internal/tui/styles"
 )
 
+func init() {
+	handle((*App).handleEpisodeSelectedMsg)
+	handle((*App).handleMangaPagesLoadedMsg)
+	handle((*App).handleChapterCompletedMsg)
+	handle((*App).handleMangaQuitMsg)
+	handle((*App).handleResumePlaybackMsg)
+	handle((*App).handlePlaybackStartingMsg)
+	handle((*App).handlePlayerLaunchingMsg)
+	handle((*App).handlePlayerLaunchTimeoutCheckMsg)
+	handle((*App).handlePlaybackStartedMsg)
+	handle((*App).handlePlayerExitedMsg)
+	handle((*App).handlePlaybackEndedMsg)
+	handleCmd(func(a *App, _ common.PlaybackTickMsg) tea.Cmd { return a.handlePlaybackTickMsg() })
+	handleCmd((*App).handlePlaybackProgressMsg)
+	handle((*App).handlePlaybackErrorMsg)
+	handle((*App).handlePlaybackAutoReturnMsg)
+	handle((*App).handleShowAudioSelectorMsg)
+	handle((*App).handleAudioSelectionMsg)
+	handle(func(a *App, _ audioselect.CancelMsg) (tea.Model, tea.Cmd) { return a.handleAudioSelectCancelMsg() })
+	handle((*App).handleShowResumePromptMsg)
+	handle((*App).handleShowSourceSelectorMsg)
+	handle((*App).handleSourceSelectionMsg)
+	handle(func(a *App, _ sourceselect.CancelMsg) (tea.Model, tea.Cmd) { return a.handleSourceSelectCancelMsg() })
+}
+
 // monitorPlayback schedules the first playback status check
 func (a *App) monitorPlayback() tea.Cmd {
 	// Schedule tick AND start async progress check
 	return tea.Batch(
-		tea.Tick(1*time.Second, func(time.Time) tea.Msg { // Reduced from 2s to 1s for faster detection
+		tea.Tick(playbackRefreshInterval(a.config()), func(time.Time) tea.Msg {
 			return common.PlaybackTickMsg{}
 		}),
 		a.checkPlaybackProgress(),
@@ -67,14 +98,14 @@ func (a *App) handlePlaybackTickMsg() tea.Cmd {
 		a.debugLog("handlePlaybackTickMsg: player is nil, ending playback")
 		a.syncProgressOnEnd(a.lastProgress)
 		return func() tea.Msg {
-			return createPlaybackEndedMsg(a.lastProgress)
+			return a.createPlaybackEndedMsg(a.lastProgress)
 		}
 	}
 
 	// Schedule next tick AND start async progress check
 	// The progress check runs in a goroutine, doesn't block
 	return tea.Batch(
-		tea.Tick(1*time.Second, func(time.Time) tea.Msg { // Reduced from 2s to 1s
+		tea.Tick(playbackRefreshInterval(a.config()), func(time.Time) tea.Msg {
 			return common.PlaybackTickMsg{}
 		}),
 		a.checkPlaybackProgress(),
@@ -116,18 +147,18 @@ func (a *App) handlePlaybackProgressMsg(msg common.PlaybackProgressMsg) tea.Cmd
 				a.debugLog("handlePlaybackProgressMsg[Windows]: IPC connection lost")
 				a.syncProgressOnEnd(a.lastProgress)
 				return func() tea.Msg {
-					return createPlaybackEndedMsg(a.lastProgress)
+					return a.createPlaybackEndedMsg(a.lastProgress)
 				}
 			}
 		}
 
-		// Linux errors indicating mpv closed
+		// Linux errors indicating mpv closed (PlayerExitedMsg usually gets there first)
 		if strings.Contains(errMsg, "broken pipe") ||
 			strings.Contains(errMsg, "connection refused") ||
 			strings.Contains(errMsg, "no such file") {
 			a.syncProgressOnEnd(a.lastProgress)
 			return func() tea.Msg {
-				return createPlaybackEndedMsg(a.lastProgress)
+				return a.createPlaybackEndedMsg(a.lastProgress)
 			}
 		}
 
@@ -137,18 +168,20 @@ func (a *App) handlePlaybackProgressMsg(msg common.PlaybackProgressMsg) tea.Cmd
 
 	// Store progress
 	a.lastProgress = msg.Progress
+	watchCmd := a.trackWatchTime(msg.Progress)
+	a.meterBandwidth(msg.Progress)
 
 	// Check if playback has ended
 	if msg.Progress.EOF {
 		a.debugLog("handlePlaybackProgressMsg: EOF reached, ending playback")
 		a.syncProgressOnEnd(a.lastProgress)
-		return func() tea.Msg {
-			return createPlaybackEndedMsg(a.lastProgress)
-		}
+		return tea.Batch(watchCmd, func() tea.Msg {
+			return a.createPlaybackEndedMsg(a.lastProgress)
+		})
 	}
 
 	// Progress updated, tick will handle next check
-	return nil
+	return tea.Batch(watchCmd, a.checkSleepTimer(), a.checkStreamHealth(msg.Progress), a.preloadNextEpisode(msg.Progress), a.lookUpEnding(msg.Progress))
 }
 
 // autoReturnAfterDelay returns a command that sends PlaybackAutoReturnMsg after a delay
@@ -180,24 +213,65 @@ func selectBestSubtitle(subtitles []providers.Subtitle) *providers.Subtitle {
 	return &subtitles[0]
 }
 
+// positionSaver is a player that remembers where it was quit with
+// quit-watch-later
+type positionSaver interface {
+	SavedPosition() (time.Duration, bool)
+}
+
+// applySavedPosition moves progress to the position the player saved when
+// quit with quit-watch-later, where the user actually stopped rather than
+// where it was last polled
+func (a *App) applySavedPosition(progress *player.PlaybackProgress) {
+	saver, ok := a.player.(positionSaver)
+	if !ok {
+		return
+	}
+	position, ok := saver.SavedPosition()
+	if !ok || progress.Duration <= 0 {
+		return
+	}
+	a.debugLog("applySavedPosition: watch-later position %s (last polled %s)", position, progress.CurrentTime)
+	progress.CurrentTime = min(position, progress.Duration)
+	progress.Percentage = float64(progress.CurrentTime) / float64(progress.Duration) * 100
+}
+
 // syncProgressOnEnd syncs playback progress to AniList when playback ends
 func (a *App) syncProgressOnEnd(progress *player.PlaybackProgress) {
 	a.debugLog("syncProgressOnEnd: Called with progress=%v", progress != nil)
+	a.saveWatchTime()
+	a.endBandwidthMeter()
 
 	if progress == nil {
 		a.debugLog("syncProgressOnEnd: progress is nil, returning")
 		return
 	}
+	a.applySavedPosition(progress)
+	a.saveSubtitleDelay(progress)
+	a.emitPlaybackEnded(progress)
 
 	a.debugLog("syncProgressOnEnd: watchingFromAniList=%v, currentAniListID=%d, percentage=%.1f%%",
 		a.watchingFromAniList, a.currentAniListID, progress.Percentage)
 
+	if a.incognito {
+		a.debugLog("syncProgressOnEnd: Incognito, not saving history or syncing")
+		a.currentPlaybackProvider = ""
+		return
+	}
+
+	if a.coWatch != nil {
+		// Kept apart from history and AniList until synced with greg cowatch sync
+		a.saveCoWatchProgress(progress)
+		a.currentPlaybackProvider = ""
+		return
+	}
+
 	if a.currentEpisodeID != "" && a.currentPlaybackProvider != "" {
 		providerName := a.currentPlaybackProvider
 
 		totalSeconds := int(progress.Duration.Seconds())
 		currentSeconds := int(progress.CurrentTime.Seconds())
-		completed := progress.Percentage >= 85.0
+		completed := a.completionRule().Completed(*progress)
 
 		episodeNumber := a.currentEpisodeNumber
 		seasonNumber := a.currentSeasonNumber
@@ -237,9 +311,9 @@ func (a *App) syncProgressOnEnd(progress *player.PlaybackProgress) {
 		a.currentPlaybackProvider = ""
 	}
 
-	// Only sync to AniList if progress >= 85%
-	if progress.Percentage < 85.0 {
-		a.debugLog("syncProgressOnEnd: Progress < 85%%, not syncing to AniList")
+	// Only sync to AniList once the episode counts as watched
+	if !a.completionRule().Completed(*progress) {
+		a.debugLog("syncProgressOnEnd: Episode not completed, not syncing to AniList")
 		return
 	}
 
@@ -252,18 +326,12 @@ func (a *App) syncProgressOnEnd(progress *player.PlaybackProgress) {
 		a.logger.Error("syncProgressOnEnd: currentAniListID is 0, skipping sync\n")
 		return
 	}
-	if a.trackerMgr == nil {
+	mgr := a.trackerMgr
+	if mgr == nil {
 		a.logger.Error("syncProgressOnEnd: trackerMgr is nil, skipping sync\n")
 		return
 	}
 
-	// Type assert to *tracker.Manager
-	mgr, ok := a.trackerMgr.(*tracker.Manager)
-	if !ok {
-		a.logger.Error("syncProgressOnEnd: Type assertion to *tracker.Manager failed (type: %T)\n", a.trackerMgr)
-		return
-	}
-
 	if !mgr.IsAniListEnabled() {
 		a.debugLog("syncProgressOnEnd: AniList is not enabled")
 		return
@@ -276,8 +344,14 @@ func (a *App) syncProgressOnEnd(progress *player.PlaybackProgress) {
 
 	a.debugLog("syncProgressOnEnd: All checks passed, starting AniList sync...")
 
+	// The provider may number this entry's episodes after its earlier cours
+	episode := a.anilistEpisode(a.currentEpisodeNumber)
+	if episode < 1 {
+		return
+	}
+
 	// Sync to AniList in the background
-	go func() {
+	a.goBackground(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
@@ -285,13 +359,18 @@ func (a *App) syncProgressOnEnd(progress *player.PlaybackProgress) {
 		mediaID := fmt.Sprintf("%d", a.currentAniListID)
 
 		a.debugLog("AniList Sync: Calling UpdateProgress(mediaID=%s, episode=%d, progress=1.0)",
-			mediaID, a.currentEpisodeNumber)
+			mediaID, episode)
 
 		// Update progress (100% = episode completed)
-		if err := mgr.UpdateProgress(ctx, mediaID, a.currentEpisodeNumber, 1.0); err != nil {
-			// Set error for display
+		if err := mgr.UpdateProgress(ctx, mediaID, episode, 1.0); err != nil {
 			a.logger.Error("AniList sync failed", "error", err)
-			a.err = fmt.Errorf("failed to sync to anilist: %v", err)
+			if errors.Is(err, tracker.ErrSyncQueued) {
+				// Retried later; the home badge shows it is pending
+				a.notifySyncQueued(mgr)
+			} else {
+				// Set error for display
+				a.err = fmt.Errorf("failed to sync to anilist: %v", err)
+			}
 		} else {
 			a.logger.Info("AniList sync completed successfully")
 		}
@@ -302,11 +381,11 @@ func (a *App) syncProgressOnEnd(progress *player.PlaybackProgress) {
 			// This will be implemented in the score dialog component
 			_ = a.isLastEpisode // acknowledge
 		}
-	}()
+	})
 }
 
 // createPlaybackEndedMsg creates a PlaybackEndedMsg with progress info
-func createPlaybackEndedMsg(progress *player.PlaybackProgress) common.PlaybackEndedMsg {
+func (a *App) createPlaybackEndedMsg(progress *player.PlaybackProgress) common.PlaybackEndedMsg {
 	if progress == nil {
 		return common.PlaybackEndedMsg{}
 	}
@@ -324,6 +403,7 @@ func createPlaybackEndedMsg(progress *player.PlaybackProgress) common.PlaybackEn
 		WatchedPercentage: progress.Percentage,
 		WatchedDuration:   fmt.Sprintf("%d:%02d:%02d", watchedHours, watchedMins, watchedSecs),
 		TotalDuration:     fmt.Sprintf("%d:%02d:%02d", totalHours, totalMins, totalSecs),
+		Completed:         a.completionRule().Completed(*progress),
 	}
 }
 
@@ -336,21 +416,38 @@ func (a *App) checkPlayerLaunchStatus() tea.Cmd {
 	}
 }
 
-// checkResumePosition checks if there's a saved progress for this anime/episode
-func (a *App) checkResumePosition(anilistID int, episode int) (int, error) {
-	a.debugLog("checkResumePosition: anilistID=%d, episode=%d", anilistID, episode)
+// checkResumePosition checks if there's a saved progress for this episode.
+// AniList content is looked up by AniList ID, everything else by provider
+// media ID and season.
+func (a *App) checkResumePosition(episode int) (int, error) {
+	a.debugLog("checkResumePosition: anilistID=%d, mediaID=%s, season=%d, episode=%d",
+		a.currentAniListID, a.selectedMedia.ID, a.currentSeasonNumber, episode)
 
 	if a.db == nil {
 		a.logger.Error("checkResumePosition: database is nil\n")
 		return 0, fmt.Errorf("database is nil")
 	}
 
-	// Find the most recent history entry for this anime and episode
-	var history database.History
-	err := a.db.Where("anilist_id = ? AND episode = ? AND completed = false", anilistID, episode).
-		Order("watched_at DESC").
-		First(&history).Error
+	if cfg := a.config(); cfg != nil && !cfg.Player.Resume {
+		return 0, nil
+	}
+	if a.coWatch != nil {
+		return a.coWatchResume(episode)
+	}
+
+	// Find the most recent history entry for this media and episode
+	query := a.db.Where("episode = ? AND completed = false", episode)
+	switch {
+	case a.watchingFromAniList && a.currentAniListID > 0:
+		query = query.Where("anilist_id = ?", a.currentAniListID)
+	case a.selectedMedia.ID != "":
+		query = query.Where("media_id = ? AND season = ?", a.selectedMedia.ID, a.currentSeasonNumber)
+	default:
+		return 0, nil
+	}
 
+	var history database.History
+	err := query.Order("watched_at DESC").First(&history).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			a.debugLog("checkResumePosition: No history record found")
@@ -363,9 +460,9 @@ func (a *App) checkResumePosition(anilistID int, episode int) (int, error) {
 	a.debugLog("checkResumePosition: Found history - Progress: %d/%d seconds (%.1f%%), Completed: %v",
 		history.ProgressSeconds, history.TotalSeconds, history.ProgressPercent, history.Completed)
 
-	// Only resume if progress is less than 85%
-	if history.ProgressPercent >= 85.0 {
-		a.debugLog("checkResumePosition: Progress >= 85%%, not resuming")
+	// Only resume episodes that weren't finished
+	if history.Completed {
+		a.debugLog("checkResumePosition: Episode completed, not resuming")
 		return 0, nil
 	}
 
@@ -373,17 +470,40 @@ func (a *App) checkResumePosition(anilistID int, episode int) (int, error) {
 	return history.ProgressSeconds, nil
 }
 
+// resumePrompt returns a prompt to resume or start over when the episode
+// has a saved position, or nil when it should play from the start
+func (a *App) resumePrompt(stream *providers.StreamURL, options player.PlayOptions, episode int) *common.ShowResumePromptMsg {
+	resumeSeconds, err := a.checkResumePosition(episode)
+	if err != nil || resumeSeconds <= 0 {
+		return nil
+	}
+	return &common.ShowResumePromptMsg{
+		Stream:   stream,
+		Options:  options,
+		Position: time.Duration(resumeSeconds) * time.Second,
+	}
+}
+
+// launchPlayer starts playback of a stream with the given options
+func (a *App) launchPlayer(stream *providers.StreamURL, options player.PlayOptions) tea.Cmd {
+	return func() tea.Msg {
+		if a.player == nil {
+			return common.PlaybackErrorMsg{Error: fmt.Errorf("player not initialized")}
+		}
+		if err := a.player.Play(context.Background(), stream.URL, options); err != nil {
+			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start playback: %w", err)}
+		}
+		return common.PlayerLaunchingMsg{Stream: stream}
+	}
+}
+
 // savePlaybackProgress saves progress to the database
 // Supports both AniList content (with anilistID) and direct provider content (anilistID = nil)
 func (a *App) savePlaybackProgress(anilistIDPtr *int, providerName string, episode int, progressSeconds int, totalSeconds int, completed bool) error {
 	// Handle nil AniList ID for non-AniList content
-	var anilistID int
-	hasAniListID := false
 	if anilistIDPtr != nil {
-		anilistID = *anilistIDPtr
-		hasAniListID = true
 		a.debugLog("savePlaybackProgress: anilistID=%d, episode=%d, progress=%d/%d, completed=%v",
-			anilistID, episode, progressSeconds, totalSeconds, completed)
+			*anilistIDPtr, episode, progressSeconds, totalSeconds, completed)
 	} else {
 		a.debugLog("savePlaybackProgress: Direct provider content, episode=%d, progress=%d/%d, completed=%v",
 			episode, progressSeconds, totalSeconds, completed)
@@ -399,48 +519,22 @@ func (a *App) savePlaybackProgress(anilistIDPtr *int, providerName string, episo
 		progressPercent = (float64(progressSeconds) / float64(totalSeconds)) * 100.0
 	}
 
-	// Determine MediaID, MediaTitle, MediaType based on content source
-	var mediaID string
-	var mediaTitle string
-	var mediaType string
-
-	if hasAniListID {
-		mediaID = fmt.Sprintf("anilist:%d", anilistID)
-		mediaTitle = a.currentAniListMedia.Title
-		mediaType = "anime"
-	} else {
-		mediaID = a.selectedMedia.ID
-		mediaTitle = a.selectedMedia.Title
-
-		switch a.selectedMedia.Type {
-		case providers.MediaTypeAnime:
-			mediaType = "anime"
-		case providers.MediaTypeMovie:
-			mediaType = "movie"
-		case providers.MediaTypeTV:
-			mediaType = "tv"
-		case providers.MediaTypeManga:
-			mediaType = "manga"
-		case providers.MediaTypeMovieTV:
-			if episode > 1 || a.currentSeasonNumber > 0 {
-				mediaType = "tv"
-			} else {
-				mediaType = "movie"
-			}
-		default:
-			mediaType = "movie"
-		}
-	}
+	mediaID, mediaTitle, mediaType := a.playbackMedia(anilistIDPtr, episode)
 
 	// If this is a completed watch, delete any previous incomplete records for this media/episode
 	if completed {
 		a.debugLog("savePlaybackProgress: Deleting old incomplete records for media_id=%s, episode=%d", mediaID, episode)
-		result := a.db.Where("media_id = ? AND episode = ? AND completed = false", mediaID, episode).
-			Delete(&database.History{})
-		if result.Error != nil {
-			a.logger.Warn("failed to delete old playback records", "error", result.Error)
-		} else if result.RowsAffected > 0 {
-			a.debugLog("savePlaybackProgress: Deleted %d old incomplete record(s)", result.RowsAffected)
+		var deleted int64
+		err := database.Write(a.db, func(tx *gorm.DB) error {
+			result := tx.Where("media_id = ? AND episode = ? AND completed = false", mediaID, episode).
+				Delete(&database.History{})
+			deleted = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			a.logger.Warn("failed to delete old playback records", "error", err)
+		} else if deleted > 0 {
+			a.debugLog("savePlaybackProgress: Deleted %d old incomplete record(s)", deleted)
 		}
 	} else {
 		// For incomplete watches, update existing incomplete record if it exists
@@ -458,7 +552,7 @@ func (a *App) savePlaybackProgress(anilistIDPtr *int, providerName string, episo
 			existing.WatchedAt = time.Now()
 			existing.ProviderName = providerName
 
-			if err := a.db.Save(&existing).Error; err != nil {
+			if err := database.Write(a.db, func(tx *gorm.DB) error { return tx.Save(&existing).Error }); err != nil {
 				a.debugLog("ERROR: savePlaybackProgress: Failed to update history: %v", err)
 				return fmt.Errorf("failed to update history record: %w", err)
 			}
@@ -485,7 +579,7 @@ func (a *App) savePlaybackProgress(anilistIDPtr *int, providerName string, episo
 	}
 
 	a.debugLog("savePlaybackProgress: Creating new history record (mediaID=%s, mediaType=%s)...", mediaID, mediaType)
-	if err := a.db.Create(&history).Error; err != nil {
+	if err := database.Write(a.db, func(tx *gorm.DB) error { return tx.Create(&history).Error }); err != nil {
 		a.debugLog("ERROR: savePlaybackProgress: Failed to create history: %v", err)
 		return fmt.Errorf("failed to create history record: %w", err)
 	}
@@ -494,6 +588,35 @@ func (a *App) savePlaybackProgress(anilistIDPtr *int, providerName string, episo
 	return nil
 }
 
+// playbackMedia returns the media ID, title and type progress of the playing
+// episode is saved under
+func (a *App) playbackMedia(anilistID *int, episode int) (string, string, string) {
+	if anilistID != nil {
+		return fmt.Sprintf("anilist:%d", *anilistID), a.currentAniListMedia.Title, "anime"
+	}
+
+	var mediaType string
+	switch a.selectedMedia.Type {
+	case providers.MediaTypeAnime:
+		mediaType = "anime"
+	case providers.MediaTypeMovie:
+		mediaType = "movie"
+	case providers.MediaTypeTV:
+		mediaType = "tv"
+	case providers.MediaTypeManga:
+		mediaType = "manga"
+	case providers.MediaTypeMovieTV:
+		if episode > 1 || a.currentSeasonNumber > 0 {
+			mediaType = "tv"
+		} else {
+			mediaType = "movie"
+		}
+	default:
+		mediaType = "movie"
+	}
+	return a.selectedMedia.ID, a.selectedMedia.Title, mediaType
+}
+
 func (a *App) findNextEpisode(currentEpisode int, currentSeason int) *providers.Episode {
 	if len(a.episodes) == 0 {
 		return nil
@@ -551,7 +674,7 @@ func (a *App) createNextEpisodePlaceholder(nextEpisode *providers.Episode, seaso
 	}
 
 	a.debugLog("createNextEpisodePlaceholder: Creating placeholder for next episode (episode=%d, season=%d)...", nextEpisode.Number, seasonNumber)
-	if err := a.db.Create(&history).Error; err != nil {
+	if err := database.Write(a.db, func(tx *gorm.DB) error { return tx.Create(&history).Error }); err != nil {
 		a.debugLog("ERROR: createNextEpisodePlaceholder: Failed to create placeholder: %v", err)
 		return fmt.Errorf("failed to create next episode placeholder: %w", err)
 	}
@@ -579,6 +702,10 @@ func cleanEpisodeTitle(title string, mediaTitle string) string {
 
 // handleEpisodeSelectedMsg handles episode selection
 func (a *App) handleEpisodeSelectedMsg(msg common.EpisodeSelectedMsg) (*App, tea.Cmd) {
+	if cmd, unaired := a.refuseUnaired(msg.Number); unaired {
+		return a, cmd
+	}
+
 	var cmds []tea.Cmd
 
 	// Only set previousState if it wasn't already set (e.g., by auto-play)
@@ -596,7 +723,7 @@ func (a *App) handleEpisodeSelectedMsg(msg common.EpisodeSelectedMsg) (*App, tea
 
 	// Check if this is the last episode (for AniList tracking)
 	if a.watchingFromAniList && a.currentAniListMedia != nil {
-		a.isLastEpisode = (episodeNumber >= a.currentAniListMedia.TotalEpisodes)
+		a.isLastEpisode = (a.anilistEpisode(episodeNumber) >= a.currentAniListMedia.TotalEpisodes)
 	} else {
 		a.isLastEpisode = false
 	}
@@ -626,6 +753,8 @@ func (a *App) handleEpisodeSelectedMsg(msg common.EpisodeSelectedMsg) (*App, tea
 			EpisodeID:     msg.EpisodeID,
 			EpisodeNumber: episodeNumber,
 			EpisodeTitle:  cleanedTitle,
+			ChooseSource:  msg.ChooseSource,
+			Download:      msg.Download,
 		}
 	}
 }
@@ -665,15 +794,63 @@ func (a *App) handleMangaPagesLoadedMsg(msg common.MangaPagesLoadedMsg) (*App, t
 // handleChapterCompletedMsg handles chapter completion
 func (a *App) handleChapterCompletedMsg(msg common.ChapterCompletedMsg) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
-	if a.watchingFromAniList {
+	if a.watchingFromAniList && !a.incognito {
 		// Update AniList progress
 		if a.currentAniListMedia != nil {
 			cmds = append(cmds, a.updateAniListProgress(a.currentAniListMedia, msg.Chapter))
+			if volumes := volumesRead(a.episodes, msg.Chapter); volumes > 0 {
+				cmds = append(cmds, a.updateAniListVolumes(a.currentAniListMedia, volumes))
+			}
 		}
 	}
 	return a, tea.Batch(cmds...)
 }
 
+// volumesRead returns how many volumes are fully read once chapter is done,
+// or 0 when the provider has no volume information
+func volumesRead(chapters []providers.Episode, chapter int) int {
+	volume := 0
+	for _, ch := range chapters {
+		if ch.Number == chapter {
+			volume = ch.Volume
+			break
+		}
+	}
+	if volume <= 0 {
+		return 0
+	}
+
+	// The volume only counts once its last chapter is read
+	for _, ch := range chapters {
+		if ch.Volume == volume && ch.Number > chapter {
+			return volume - 1
+		}
+	}
+	return volume
+}
+
+// updateAniListVolumes updates the read volume count on trackers that support it
+func (a *App) updateAniListVolumes(media *tracker.TrackedMedia, volumes int) tea.Cmd {
+	return func() tea.Msg {
+		mgr := a.trackerMgr
+		if mgr == nil {
+			return nil
+		}
+		updater, ok := mgr.GetAniList().(tracker.VolumeUpdater)
+		if !ok {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := updater.UpdateVolumeProgress(ctx, media.ServiceID, volumes); err != nil {
+			a.logger.Warn("failed to update AniList volume progress", "media", media.Title, "volumes", volumes, "error", err)
+		}
+		return nil
+	}
+}
+
 // handleMangaQuitMsg handles manga reader quit
 func (a *App) handleMangaQuitMsg(msg common.MangaQuitMsg) (*App, tea.Cmd) {
 	a.state = episodeView
@@ -691,6 +868,15 @@ func (a *App) handleMangaQuitMsg(msg common.MangaQuitMsg) (*App, tea.Cmd) {
 // handleResumePlaybackMsg handles resuming playback from history
 func (a *App) handleResumePlaybackMsg(msg common.ResumePlaybackMsg) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
+	// Pasted URLs are stored with the URL as media ID
+	if msg.ProviderName == manual.ProviderName {
+		stream, err := manual.Stream(msg.MediaID, "", nil)
+		if err != nil {
+			return a, func() tea.Msg { return common.PlaybackErrorMsg{Error: err} }
+		}
+		return a, a.playManualStream(stream, msg.MediaTitle)
+	}
+
 	// Resume playback from continue watching
 	// We need to fetch the media and episodes first, then play
 	a.selectedMedia = providers.Media{
@@ -720,7 +906,7 @@ func (a *App) handlePlaybackStartingMsg(msg common.PlaybackStartingMsg) (*App, t
 	// Show loading state while fetching stream URL
 	a.state = loadingView
 	a.loadingOp = loadingStream
-	cmds = append(cmds, a.spinner.Tick, a.startPlayback(msg.EpisodeID, msg.EpisodeNumber, msg.EpisodeTitle))
+	cmds = append(cmds, a.spinner.Tick, a.startPlayback(msg.EpisodeID, msg.EpisodeNumber, msg.EpisodeTitle, msg.ChooseSource, msg.Download))
 	return a, tea.Batch(cmds...)
 }
 
@@ -731,11 +917,59 @@ func (a *App) handlePlayerLaunchingMsg(msg common.PlayerLaunchingMsg) (*App, tea
 	a.previousState = a.state // Save current state to return to on cancel
 	a.state = launchingPlayerView
 	a.launchStartTime = time.Now()
+	a.playingStream = msg.Stream
+	a.clipStart = nil
+	a.resetStreamHealth(msg.Stream)
+	a.bandwidth = bandwidthMeter{}
+	a.holdAmbient()
+	if a.sleep.fired {
+		// Playing again after the sleep timer went off
+		a.sleep = sleepTimer{}
+	}
 	// Start ticker to check for launch completion or timeout
-	cmds = append(cmds, a.spinner.Tick, a.checkPlayerLaunchStatus())
+	cmds = append(cmds, a.spinner.Tick, a.checkPlayerLaunchStatus(), a.waitForPlayerExit())
 	return a, tea.Batch(cmds...)
 }
 
+// waitForPlayerExit returns a command that sends PlayerExitedMsg once the
+// player process of the playback just launched exits
+func (a *App) waitForPlayerExit() tea.Cmd {
+	a.playerSession++
+	session, p := a.playerSession, a.player
+	if p == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		err := p.Wait(context.Background())
+		return common.PlayerExitedMsg{Session: session, Err: err}
+	}
+}
+
+// handlePlayerExitedMsg ends playback as soon as the player process exits,
+// whether mpv was closed or killed from outside greg
+func (a *App) handlePlayerExitedMsg(msg common.PlayerExitedMsg) (*App, tea.Cmd) {
+	if msg.Session != a.playerSession {
+		return a, nil // Exit of an earlier playback
+	}
+	a.releaseAmbient()
+
+	switch a.state {
+	case launchingPlayerView:
+		a.debugLog("handlePlayerExitedMsg: player exited during launch: %v", msg.Err)
+		if msg.Err != nil {
+			a.err = fmt.Errorf("mpv exited before playback started: %w", msg.Err)
+			a.state = errorView
+			return a, nil
+		}
+		return a.handlePlaybackEndedMsg(a.createPlaybackEndedMsg(nil))
+	case playingView:
+		a.debugLog("handlePlayerExitedMsg: player exited: %v", msg.Err)
+		a.syncProgressOnEnd(a.lastProgress)
+		return a.handlePlaybackEndedMsg(a.createPlaybackEndedMsg(a.lastProgress))
+	}
+	return a, nil
+}
+
 // handlePlayerLaunchTimeoutCheckMsg handles player launch timeout checks
 func (a *App) handlePlayerLaunchTimeoutCheckMsg(msg common.PlayerLaunchTimeoutCheckMsg) (*App, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -780,10 +1014,19 @@ func (a *App) handlePlaybackStartedMsg(msg common.PlaybackStartedMsg) (*App, tea
 	var cmds []tea.Cmd
 	// Playback started successfully, transition to playing view
 	a.state = playingView
+	if msg.Stream != nil {
+		a.playingStream = msg.Stream
+		a.clipStart = nil
+		a.resetStreamHealth(msg.Stream)
+	}
+	if a.sleep.fired {
+		a.sleep = sleepTimer{}
+	}
 	// Record when playback started (for IPC initialization grace period)
 	a.launchStartTime = time.Now()
-	// Start monitoring playback
-	cmds = append(cmds, a.monitorPlayback())
+	a.emitScriptEvent(scripting.EventPlaybackStarted, a.playbackEvent())
+	// Start monitoring playback and the player process
+	cmds = append(cmds, a.monitorPlayback(), a.waitForPlayerExit())
 	return a, tea.Batch(cmds...)
 }
 
@@ -814,19 +1057,24 @@ func (a *App) handlePlaybackEndedMsg(msg common.PlaybackEndedMsg) (*App, tea.Cmd
 		// Show AniList sync status if watching from AniList
 		if a.watchingFromAniList && a.currentAniListID > 0 {
 			lines = append(lines, "")
-			if msg.WatchedPercentage >= 85.0 {
+			if msg.Completed {
 				lines = append(lines, "✓ Syncing to AniList...")
 			} else {
-				lines = append(lines, "→ Saved locally (watch ≥85% to sync)")
+				lines = append(lines, "→ Saved locally (finish the episode to sync)")
 			}
 		}
 	}
 
-	// Help text - only offer to continue if episode was completed (>= 85%)
+	// Help text - only offer to continue if episode was completed
 	lines = append(lines, "")
-	episodeCompleted := msg.WatchedPercentage >= 85.0
+	episodeCompleted := msg.Completed
+	sleeping := a.sleepOnPlaybackEnd()
 	var autoReturn bool // Flag to determine if we should auto-return
-	if a.watchingFromAniList && !a.isLastEpisode && episodeCompleted {
+	if sleeping {
+		// Sleep timer went off - don't offer the next episode
+		lines = append(lines, styles.HelpStyle.Render("Sleep timer: stopping here. Good night!"))
+		autoReturn = true
+	} else if a.watchingFromAniList && !a.isLastEpisode && episodeCompleted {
 		// Episode completed and not the last episode - offer to continue
 		lines = append(lines, styles.HelpStyle.Render("Continue watching next episode?"))
 		lines = append(lines, styles.HelpStyle.Render("[y/Enter] Yes  [n/Esc] No, return to library"))
@@ -964,7 +1212,7 @@ func (a *App) playMovieDirectly(mediaID string) tea.Cmd {
 	providerType := a.currentMediaType
 	provider := a.providers[providerType]
 
-	return func() tea.Msg {
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		a.debugLog("playMovieDirectly goroutine executing with mediaID=%s", mediaID)
 		a.debugLog("providerType=%s", providerType)
 
@@ -977,29 +1225,19 @@ func (a *App) playMovieDirectly(mediaID string) tea.Cmd {
 		// Track provider for this playback session
 		a.currentPlaybackProvider = provider.Name()
 
-		// This is a temporary solution to call the provider-specific method.
-		// A better solution would be to have a more generic way to handle movies.
-		type movieEpisodeIDGetter interface {
-			GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-		}
-
-		episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-		if !ok {
-			a.debugLog("ERROR: Provider %s does not implement GetMovieEpisodeID", provider.Name())
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("provider does not support direct movie playback")}
-		}
-
-		a.debugLog("Calling GetMovieEpisodeID...")
-		episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), mediaID)
+		a.debugLog("Resolving movie episode ID...")
+		episodeID, err := providers.MovieEpisodeID(opCtx, provider, mediaID)
 		if err != nil {
-			a.debugLog("ERROR: GetMovieEpisodeID failed: %v", err)
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get movie episode ID: %w", err)}
+			a.debugLog("ERROR: resolving movie episode ID failed: %v", err)
+			return common.PlaybackErrorMsg{Error: err}
 		}
 		a.debugLog("Got episodeID=%s", episodeID)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(opCtx, a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
+		preference := a.resolveAudioPreference(provider, a.currentAniListID)
+
 		a.debugLog("Calling GetStreamURL for episodeID=%s", episodeID)
 		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
 		if err != nil {
@@ -1034,15 +1272,6 @@ func (a *App) playMovieDirectly(mediaID string) tea.Cmd {
 		// Audio track selection for movies
 		audioTrackIndex := 0 // Default to first track
 		if len(stream.AudioTracks) > 0 {
-			// Determine effective audio preference
-			preference := a.audioPreference // From CLI flag or empty
-			if preference == "" && a.currentAniListID != 0 {
-				// Check database for per-show memory
-				if dbPref, err := database.GetAudioPreference(a.db, a.currentAniListID); err == nil && dbPref != "" {
-					preference = dbPref
-				}
-			}
-
 			// Try to find matching track
 			if selectedTrack := audio.SelectAudioTrack(stream.AudioTracks, preference); selectedTrack != nil {
 				audioTrackIndex = selectedTrack.Index
@@ -1055,6 +1284,7 @@ func (a *App) playMovieDirectly(mediaID string) tea.Cmd {
 			Headers:    stream.Headers,
 			Referer:    stream.Referer,
 			AudioTrack: audioTrackIndex,
+			YTDL:       stream.Type == providers.StreamTypeYTDLP,
 		}
 
 		// Add subtitle if available, preferring English
@@ -1062,22 +1292,38 @@ func (a *App) playMovieDirectly(mediaID string) tea.Cmd {
 			options.SubtitleURL = subtitle.URL
 			options.SubtitleLang = "en,eng,english"
 		}
-
-		if err := a.player.Play(context.Background(), stream.URL, options); err != nil {
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start playback: %w", err)}
-		}
+		options.SubtitleDelay = a.rememberedSubtitleDelay()
+		a.applyAudioNormalization(&options)
 
 		a.currentEpisodeID = episodeID
 		a.currentEpisodeNumber = 0
 		a.currentSeasonNumber = 0 // Movies don't have seasons
 		a.currentEpisodeTitle = a.selectedMedia.Title
 
-		return common.PlaybackStartedMsg{}
-	}
+		// Ask whether to resume when the movie was left unfinished
+		if prompt := a.resumePrompt(stream, options, 0); prompt != nil {
+			return *prompt
+		}
+
+		if opCtx.Err() != nil {
+			return operationCancelledMsg{}
+		}
+		if err := a.player.Play(context.Background(), stream.URL, options); err != nil {
+			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start playback: %w", err)}
+		}
+
+		return common.PlaybackStartedMsg{Stream: stream}
+	})
 }
 
-func (a *App) startPlayback(episodeID string, episodeNumber int, episodeTitle string) tea.Cmd {
-	return func() tea.Msg {
+func (a *App) startPlayback(episodeID string, episodeNumber int, episodeTitle string, chooseSource, download bool) tea.Cmd {
+	// Stream of the episode resolved while the previous one played
+	var preloaded *providers.StreamURL
+	if p, ok := a.providers[a.currentMediaType]; ok && !chooseSource {
+		preloaded = a.takePreloaded(p.Name(), episodeID)
+	}
+
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		// Get the provider for the current media type
 		provider, ok := a.providers[a.currentMediaType]
 		if !ok {
@@ -1095,104 +1341,179 @@ func (a *App) startPlayback(episodeID string, episodeNumber int, episodeTitle st
 		a.currentPlaybackProvider = provider.Name()
 
 		// Get stream URL for the episode/movie
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(opCtx, a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
-		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
-		if err != nil {
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get stream URL: %w", err)}
-		}
-
-		// Check if debug mode is enabled
-		if a.isDebugMode() {
-			a.debugInfo = &DebugInfo{
-				MediaTitle:    a.selectedMedia.Title,
-				EpisodeTitle:  episodeTitle,
-				EpisodeNumber: episodeNumber,
-				StreamURL:     stream.URL,
-				Quality:       stream.Quality,
-				Type:          stream.Type,
-				Referer:       stream.Referer,
-				Headers:       stream.Headers,
-				Subtitles:     stream.Subtitles,
-				Error:         nil,
-			}
-			a.forceQuit = true
-			return nil
-		}
-
-		// Check if player is initialized
-		if a.player == nil {
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("player not initialized")}
-		}
+		a.resolveAudioPreference(provider, a.currentAniListID)
 
-		// Prepare playback options
-		var title string
-		if episodeNumber == 0 { // For movies played directly
-			title = a.selectedMedia.Title
-		} else { // For episodes and other content
-			title = fmt.Sprintf("%s - Episode %d", a.selectedMedia.Title, episodeNumber)
-		}
-
-		// Audio track selection (CLI > DB > config hierarchy)
-		audioTrackIndex := 0 // Default to first track
-		if len(stream.AudioTracks) > 0 {
-			// Determine effective audio preference
-			preference := a.audioPreference // From CLI flag or empty
-			if preference == "" && a.currentAniListID != 0 {
-				// Check database for per-show memory
-				if dbPref, err := database.GetAudioPreference(a.db, a.currentAniListID); err == nil && dbPref != "" {
-					preference = dbPref
-				}
+		// Let the user pick the server/mirror when asked to or configured
+		if !download && (chooseSource || a.sourceSelectorEnabled()) {
+			sources, err := providers.ListStreamSources(ctx, provider, episodeID)
+			if err != nil {
+				return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get sources: %w", err)}
 			}
-			// If still empty, will use config default (already in a.audioPreference from CLI init)
-
-			// Try to find matching track
-			if selectedTrack := audio.SelectAudioTrack(stream.AudioTracks, preference); selectedTrack != nil {
-				audioTrackIndex = selectedTrack.Index
-			} else {
-				// No matching track found - show audio selector TUI
-				return common.ShowAudioSelectorMsg{
-					Tracks:       stream.AudioTracks,
-					Stream:       stream,
-					AniListID:    a.currentAniListID,
+			if len(sources) > 1 {
+				return common.ShowSourceSelectorMsg{
+					Sources:      sources,
 					EpisodeID:    episodeID,
 					EpisodeNum:   episodeNumber,
 					EpisodeTitle: episodeTitle,
 				}
 			}
+			if opCtx.Err() != nil {
+				return operationCancelledMsg{}
+			}
+			return a.playStream(sources[0].Stream, episodeID, episodeNumber, episodeTitle)
 		}
 
-		options := player.PlayOptions{
-			Title:      title,
-			Episode:    episodeNumber,
-			Headers:    stream.Headers,
-			Referer:    stream.Referer,
-			AudioTrack: audioTrackIndex,
+		stream := preloaded
+		var err error
+		if stream == nil {
+			stream, err = provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
+			if err != nil {
+				return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get stream URL: %w", err)}
+			}
 		}
 
-		// Check for resume position if watching from AniList
-		if a.watchingFromAniList && a.currentAniListID > 0 {
-			if resumeSeconds, err := a.checkResumePosition(a.currentAniListID, episodeNumber); err == nil && resumeSeconds > 0 {
-				options.StartTime = time.Duration(resumeSeconds) * time.Second
-				// Note: User will see resume position when playback starts
+		if download {
+			stream, err = a.downloadForWatching(opCtx, provider.Name(), stream, episodeNumber)
+			if err != nil {
+				return common.PlaybackErrorMsg{Error: err}
 			}
 		}
 
-		// Add subtitle if available, preferring English
-		if subtitle := selectBestSubtitle(stream.Subtitles); subtitle != nil {
-			options.SubtitleURL = subtitle.URL
-			options.SubtitleLang = "en,eng,english"
+		if opCtx.Err() != nil {
+			return operationCancelledMsg{}
 		}
+		return a.playStream(stream, episodeID, episodeNumber, episodeTitle)
+	})
+}
 
-		// Play the stream with MPV (now async - returns immediately)
-		if err := a.player.Play(context.Background(), stream.URL, options); err != nil {
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start playback: %w", err)}
+// playSelectedSource starts playback of a source picked in the source selector
+func (a *App) playSelectedSource(stream *providers.StreamURL) tea.Cmd {
+	episodeID, episodeNumber, episodeTitle := a.currentEpisodeID, a.currentEpisodeNumber, a.currentEpisodeTitle
+	return func() tea.Msg {
+		return a.playStream(stream, episodeID, episodeNumber, episodeTitle)
+	}
+}
+
+// sourceSelectorEnabled reports whether the source selector is shown before every playback
+func (a *App) sourceSelectorEnabled() bool {
+	cfg := a.config()
+	return cfg != nil && cfg.Player.SourceSelector
+}
+
+// resolveAudioPreference returns the audio preference for the current show:
+// CLI flag > per-show memory (by AniList ID, then provider media ID) > config.
+// Providers serving several audio versions are told the preference.
+func (a *App) resolveAudioPreference(provider providers.Provider, anilistID int) string {
+	preference := a.audioPreference // From CLI flag or empty
+	if preference == "" && anilistID != 0 {
+		if dbPref, err := database.GetAudioPreference(a.db, anilistID); err == nil {
+			preference = dbPref
+		}
+	}
+	if preference == "" && a.selectedMedia.ID != "" {
+		if dbPref, err := database.GetMediaAudioPreference(a.db, provider.Name(), a.selectedMedia.ID); err == nil {
+			preference = dbPref
 		}
+	}
+	if preference == "" {
+		if cfg := a.config(); cfg != nil {
+			preference = cfg.Player.AudioPreference
+		}
+	}
 
-		// Player launch initiated, transition to launching state
-		return common.PlayerLaunchingMsg{}
+	if setter, ok := providers.As[providers.AudioPreferenceSetter](provider); ok {
+		setter.SetAudioPreference(preference)
+	}
+	a.currentAudioPreference = preference
+	return preference
+}
+
+// playStream resolves the audio track and launches the player for a stream
+func (a *App) playStream(stream *providers.StreamURL, episodeID string, episodeNumber int, episodeTitle string) tea.Msg {
+	// Check if debug mode is enabled
+	if a.isDebugMode() {
+		a.debugInfo = &DebugInfo{
+			MediaTitle:    a.selectedMedia.Title,
+			EpisodeTitle:  episodeTitle,
+			EpisodeNumber: episodeNumber,
+			StreamURL:     stream.URL,
+			Quality:       stream.Quality,
+			Type:          stream.Type,
+			Referer:       stream.Referer,
+			Headers:       stream.Headers,
+			Subtitles:     stream.Subtitles,
+			Error:         nil,
+		}
+		a.forceQuit = true
+		return nil
+	}
+
+	// Check if player is initialized
+	if a.player == nil {
+		return common.PlaybackErrorMsg{Error: fmt.Errorf("player not initialized")}
+	}
+
+	// Prepare playback options
+	var title string
+	if episodeNumber == 0 { // For movies played directly
+		title = a.selectedMedia.Title
+	} else { // For episodes and other content
+		title = fmt.Sprintf("%s - Episode %d", a.selectedMedia.Title, episodeNumber)
+	}
+
+	// Audio track selection (CLI > DB > config hierarchy)
+	audioTrackIndex := 0 // Default to first track
+	if len(stream.AudioTracks) > 0 {
+		preference := a.currentAudioPreference
+
+		// Try to find matching track
+		if selectedTrack := audio.SelectAudioTrack(stream.AudioTracks, preference); selectedTrack != nil {
+			audioTrackIndex = selectedTrack.Index
+		} else {
+			// No matching track found - show audio selector TUI
+			return common.ShowAudioSelectorMsg{
+				Tracks:       stream.AudioTracks,
+				Stream:       stream,
+				AniListID:    a.currentAniListID,
+				EpisodeID:    episodeID,
+				EpisodeNum:   episodeNumber,
+				EpisodeTitle: episodeTitle,
+			}
+		}
+	}
+
+	options := player.PlayOptions{
+		Title:      title,
+		Episode:    episodeNumber,
+		Headers:    stream.Headers,
+		Referer:    stream.Referer,
+		AudioTrack: audioTrackIndex,
+		YTDL:       stream.Type == providers.StreamTypeYTDLP,
+	}
+
+	// Add subtitle if available, preferring English
+	if subtitle := selectBestSubtitle(stream.Subtitles); subtitle != nil {
+		options.SubtitleURL = subtitle.URL
+		options.SubtitleLang = "en,eng,english"
+	}
+	options.SubtitleDelay = a.rememberedSubtitleDelay()
+	a.applyAudioNormalization(&options)
+
+	// Ask whether to resume when the episode was left unfinished
+	if prompt := a.resumePrompt(stream, options, episodeNumber); prompt != nil {
+		return *prompt
+	}
+
+	// Play the stream with MPV (now async - returns immediately)
+	if err := a.player.Play(context.Background(), stream.URL, options); err != nil {
+		return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start playback: %w", err)}
 	}
+
+	// Player launch initiated, transition to launching state
+	return common.PlayerLaunchingMsg{Stream: stream}
 }
 
 // continuePlaybackWithAudioTrack continues playback after audio track selection
@@ -1225,13 +1546,7 @@ func (a *App) continuePlaybackWithAudioTrack(audioTrackIndex int) tea.Cmd {
 			Headers:    stream.Headers,
 			Referer:    stream.Referer,
 			AudioTrack: audioTrackIndex,
-		}
-
-		// Check for resume position if watching from AniList
-		if a.watchingFromAniList && a.currentAniListID > 0 {
-			if resumeSeconds, err := a.checkResumePosition(a.currentAniListID, a.currentEpisodeNumber); err == nil && resumeSeconds > 0 {
-				options.StartTime = time.Duration(resumeSeconds) * time.Second
-			}
+			YTDL:       stream.Type == providers.StreamTypeYTDLP,
 		}
 
 		// Add subtitle if available, preferring English
@@ -1239,6 +1554,13 @@ func (a *App) continuePlaybackWithAudioTrack(audioTrackIndex int) tea.Cmd {
 			options.SubtitleURL = subtitle.URL
 			options.SubtitleLang = "en,eng,english"
 		}
+		options.SubtitleDelay = a.rememberedSubtitleDelay()
+		a.applyAudioNormalization(&options)
+
+		// Ask whether to resume when the episode was left unfinished
+		if prompt := a.resumePrompt(stream, options, a.currentEpisodeNumber); prompt != nil {
+			return *prompt
+		}
 
 		// Play the stream with MPV (now async - returns immediately)
 		if err := a.player.Play(context.Background(), stream.URL, options); err != nil {
@@ -1246,13 +1568,13 @@ func (a *App) continuePlaybackWithAudioTrack(audioTrackIndex int) tea.Cmd {
 		}
 
 		// Player launch initiated, transition to launching state
-		return common.PlayerLaunchingMsg{}
+		return common.PlayerLaunchingMsg{Stream: stream}
 	}
 }
 
 // resumePlaybackFromHistory resumes playback from history with stored progress
 func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
-	return func() tea.Msg {
+	return a.withOperation(func(opCtx context.Context) tea.Msg {
 		// Update current media type from message if available
 		if msg.MediaType != "" {
 			switch msg.MediaType {
@@ -1313,7 +1635,7 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 		// Track provider for this playback session
 		a.currentPlaybackProvider = provider.Name()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(opCtx, a.providerTimeout(provider.Name(), config.TimeoutDetails, config.TimeoutStream))
 		defer cancel()
 
 		// Check if this is an AniList media ID that needs mapping
@@ -1334,38 +1656,34 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 
 			// Try to look up the provider mapping to get the media ID
 			var mappingFound bool
-			if a.mappingMgr != nil {
-				if mgr, ok := a.mappingMgr.(*mapping.Manager); ok {
-					providerMapping, err := mgr.GetMapping(ctx, anilistID)
-					if err == nil && providerMapping != nil {
-						// Mapping exists
-						mappingFound = true
-
-						// If we have a provider from history, verify it matches
-						// If not, we'll need to search with the history provider
-						if msg.ProviderName != "" && providerMapping.ProviderName != msg.ProviderName {
-							// Provider mismatch - the user watched with a different provider than mapped
-							// We'll search for the anime with the provider from history
-							a.logger.Debug("Provider mismatch: history=%s, mapping=%s. Searching with history provider...\n",
-								msg.ProviderName, providerMapping.ProviderName)
-
-							// Search for the anime using the provider from history
-							searchResults, err := provider.Search(ctx, msg.MediaTitle)
-							if err != nil || len(searchResults) == 0 {
-								return common.PlaybackErrorMsg{Error: fmt.Errorf("could not find '%s' using %s provider. Please play from your AniList library to update the mapping", msg.MediaTitle, provider.Name())}
-							}
-
-							// Use the first result (best match)
-							actualMediaID = searchResults[0].ID
-							a.selectedMedia = searchResults[0]
-						} else {
-							// Provider matches or no provider in history - use the mapping
-							actualMediaID = providerMapping.ProviderMediaID
-							a.selectedMedia = providers.Media{
-								ID:    actualMediaID,
-								Title: msg.MediaTitle,
-								Type:  provider.Type(),
-							}
+			if mgr := a.mappingMgr; mgr != nil {
+				providerMapping, err := mgr.GetMapping(ctx, anilistID)
+				if err == nil && providerMapping != nil {
+					// Mapping exists
+					mappingFound = true
+
+					// If we have a provider from history, verify it matches
+					// If not, we'll need to search with the history provider
+					if msg.ProviderName != "" && providerMapping.ProviderName != msg.ProviderName {
+						// Provider mismatch - the user watched with a different provider than mapped
+						// We'll search for the anime with the provider from history
+						a.logger.Debug("Provider mismatch: history=%s, mapping=%s. Searching with history provider...\n",
+							msg.ProviderName, providerMapping.ProviderName)
+
+						// Search for the anime using the provider from history
+						found, err := resolve.Search(ctx, provider, msg.MediaTitle)
+						if err != nil {
+							return common.PlaybackErrorMsg{Error: fmt.Errorf("could not find '%s' using %s provider. Please play from your AniList library to update the mapping", msg.MediaTitle, provider.Name())}
+						}
+						actualMediaID = found.ID
+						a.selectedMedia = *found
+					} else {
+						// Provider matches or no provider in history - use the mapping
+						actualMediaID = providerMapping.ProviderMediaID
+						a.selectedMedia = providers.Media{
+							ID:    actualMediaID,
+							Title: msg.MediaTitle,
+							Type:  provider.Type(),
 						}
 					}
 				}
@@ -1375,14 +1693,12 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 			if !mappingFound {
 				a.logger.Debug("no mapping found, searching with provider", "anilist_id", anilistID, "provider", provider.Name())
 
-				searchResults, err := provider.Search(ctx, msg.MediaTitle)
-				if err != nil || len(searchResults) == 0 {
+				found, err := resolve.Search(ctx, provider, msg.MediaTitle)
+				if err != nil {
 					return common.PlaybackErrorMsg{Error: fmt.Errorf("no provider mapping found for '%s'. Please play it from your AniList library to create a mapping with %s", msg.MediaTitle, provider.Name())}
 				}
-
-				// Use the first result (best match)
-				actualMediaID = searchResults[0].ID
-				a.selectedMedia = searchResults[0]
+				actualMediaID = found.ID
+				a.selectedMedia = *found
 			}
 		} else {
 			// Non-AniList content - verify the media ID is still valid by trying a quick lookup
@@ -1394,69 +1710,30 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 			}
 		}
 
-		// For movies (episode 0), we need to get the movie episode ID first
+		// Movies (episode 0) resolve to their movie episode, shows to the
+		// episode in the season history recorded
+		ref := resolve.MediaRef{ID: actualMediaID, Title: msg.MediaTitle}
 		if msg.Episode == 0 {
-			// Check if provider supports GetMovieEpisodeID interface
-			type movieEpisodeIDGetter interface {
-				GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-			}
-
-			episodeIDGetter, hasMovieMethod := provider.(movieEpisodeIDGetter)
-			var movieEpisodeID string
-
-			if hasMovieMethod {
-				// Provider supports GetMovieEpisodeID - use it
-				a.logger.Debug("Getting movie episode ID", "media_id", actualMediaID)
-				var err error
-				movieEpisodeID, err = episodeIDGetter.GetMovieEpisodeID(ctx, actualMediaID)
-				if err != nil {
-					// If getting movie episode ID fails, try to search for the media by title as fallback
-					a.logger.Debug("Failed to get movie episode ID, searching by title", "media_id", actualMediaID, "error", err)
-
-					searchResults, searchErr := provider.Search(ctx, msg.MediaTitle)
-					if searchErr == nil && len(searchResults) > 0 {
-						// Update to use the first search result
-						actualMediaID = searchResults[0].ID
-						a.selectedMedia = searchResults[0]
-
-						// Try again with the new media ID
-						movieEpisodeID, err = episodeIDGetter.GetMovieEpisodeID(ctx, actualMediaID)
-					}
-
-					if err != nil {
-						return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get movie episode ID: %w", err)}
-					}
-				}
-			} else {
-				// Provider doesn't have GetMovieEpisodeID - use media ID directly
-				a.logger.Debug("Provider doesn't support GetMovieEpisodeID", "media_id", actualMediaID)
-				movieEpisodeID = actualMediaID
-			}
+			ref.Type = providers.MediaTypeMovie
+		}
+		res, err := resolve.Episode(ctx, provider, ref, resolve.EpisodeSelector{Season: msg.Season, Episode: msg.Episode})
+		if err != nil {
+			return common.PlaybackErrorMsg{Error: err}
+		}
+		if res.Media.ID != actualMediaID {
+			// The stored media ID was stale, so the title was searched
+			a.logger.Debug("media ID no longer resolves, found by title", "media_id", actualMediaID, "found", res.Media.ID)
+			actualMediaID = res.Media.ID
+			a.selectedMedia = res.Media
+		}
+		episodeID := res.Episode.ID
 
+		if msg.Episode == 0 {
 			// Now get the stream URL using the episode ID
-			stream, err := provider.GetStreamURL(ctx, movieEpisodeID, providers.Quality1080p)
+			preference := a.resolveAudioPreference(provider, anilistID)
+			stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
 			if err != nil {
-				// If getting stream fails and we haven't tried searching yet, try that
-				if hasMovieMethod && movieEpisodeID == actualMediaID {
-					a.logger.Debug("Failed to get stream for media ID %s: %v. Attempting to search by title...", actualMediaID, err)
-
-					searchResults, searchErr := provider.Search(ctx, msg.MediaTitle)
-					if searchErr == nil && len(searchResults) > 0 {
-						// Update to use the first search result
-						actualMediaID = searchResults[0].ID
-						a.selectedMedia = searchResults[0]
-
-						// Try to get episode ID and stream with new media ID
-						movieEpisodeID, err = episodeIDGetter.GetMovieEpisodeID(ctx, actualMediaID)
-						if err == nil {
-							stream, err = provider.GetStreamURL(ctx, movieEpisodeID, providers.Quality1080p)
-						}
-					}
-				}
-
-				if err != nil {
-					return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get stream URL: %w", err)}
-				}
+				return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get stream URL: %w", err)}
 			}
 
 			// If this is AniList content, fetch the full media details for proper tracking
@@ -1477,12 +1754,6 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 			// Audio track selection for history movie playback
 			audioTrackIndex := 0
 			if len(stream.AudioTracks) > 0 {
-				preference := a.audioPreference
-				if preference == "" && anilistID != 0 {
-					if dbPref, err := database.GetAudioPreference(a.db, anilistID); err == nil && dbPref != "" {
-						preference = dbPref
-					}
-				}
 				if selectedTrack := audio.SelectAudioTrack(stream.AudioTracks, preference); selectedTrack != nil {
 					audioTrackIndex = selectedTrack.Index
 				}
@@ -1496,6 +1767,7 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 				Headers:    stream.Headers,
 				Referer:    stream.Referer,
 				AudioTrack: audioTrackIndex,
+				YTDL:       stream.Type == providers.StreamTypeYTDLP,
 			}
 
 			// Add subtitle if available, preferring English
@@ -1503,94 +1775,21 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 				playOpts.SubtitleURL = subtitle.URL
 				playOpts.SubtitleLang = "en,eng,english"
 			}
+			playOpts.SubtitleDelay = a.rememberedSubtitleDelay()
+			a.applyAudioNormalization(&playOpts)
 
+			if opCtx.Err() != nil {
+				return operationCancelledMsg{}
+			}
 			if err := a.player.Play(context.Background(), stream.URL, playOpts); err != nil {
 				return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start player: %w", err)}
 			}
 
-			return common.PlayerLaunchingMsg{}
-		}
-
-		// For TV/Anime, we need to get episodes
-		// Try to determine if this is single-season or multi-season content
-		var episodeID string
-		var episodes []providers.Episode
-		var err error
-
-		// Try to get seasons first
-		seasons, seasonsErr := provider.GetSeasons(ctx, actualMediaID)
-
-		// If getting seasons fails, try searching by title as fallback
-		if seasonsErr != nil {
-			a.logger.Debug("Failed to get seasons for media ID %s: %v. Attempting to search by title...", actualMediaID, seasonsErr)
-
-			searchResults, searchErr := provider.Search(ctx, msg.MediaTitle)
-			if searchErr == nil && len(searchResults) > 0 {
-				// Update to use the first search result
-				actualMediaID = searchResults[0].ID
-				a.selectedMedia = searchResults[0]
-
-				// Try again with the new media ID
-				seasons, seasonsErr = provider.GetSeasons(ctx, actualMediaID)
-			}
-		}
-
-		// If getting seasons fails or returns empty/single season, treat as single-season content
-		if seasonsErr != nil || len(seasons) == 0 || (len(seasons) == 1 && seasons[0].ID == actualMediaID) {
-			// Single season content - episodes directly from media ID
-			episodes, err = provider.GetEpisodes(ctx, actualMediaID)
-			if err != nil {
-				return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get episodes: %w", err)}
-			}
-		} else {
-			// Multi-season content
-			var seasonID string
-
-			// If we have a specific season number from history, try to find it
-			if msg.Season > 0 {
-				for _, season := range seasons {
-					if season.Number == msg.Season {
-						seasonID = season.ID
-						break
-					}
-				}
-			}
-
-			// If we didn't find the season by number, try to use the first season
-			// or if only one season exists
-			if seasonID == "" {
-				if len(seasons) == 1 {
-					seasonID = seasons[0].ID
-				} else {
-					return common.PlaybackErrorMsg{Error: fmt.Errorf("could not determine correct season (requested season %d, found %d seasons)", msg.Season, len(seasons))}
-				}
-			}
-
-			// Get episodes for the season
-			episodes, err = provider.GetEpisodes(ctx, seasonID)
-			if err != nil {
-				// If getting episodes by season ID fails, fall back to direct media ID
-				episodes, err = provider.GetEpisodes(ctx, actualMediaID)
-				if err != nil {
-					return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get episodes: %w", err)}
-				}
-			}
+			return common.PlayerLaunchingMsg{Stream: stream}
 		}
 
 		// Store episodes so we can return to the episode list after playback
-		a.episodes = episodes
-
-		// Find the episode by number
-		for _, ep := range episodes {
-			if ep.Number == msg.Episode {
-				episodeID = ep.ID
-				break
-			}
-		}
-
-		if episodeID == "" {
-			return common.PlaybackErrorMsg{Error: fmt.Errorf("episode %d not found", msg.Episode)}
-		}
+		a.episodes = res.Episodes
 
 		// Handle Manga
 		if msg.MediaType == "manga" {
@@ -1616,6 +1815,7 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 		}
 
 		// Get stream URL
+		preference := a.resolveAudioPreference(provider, anilistID)
 		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
 		if err != nil {
 			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to get stream URL: %w", err)}
@@ -1639,12 +1839,6 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 		// Audio track selection for history episode playback
 		audioTrackIndex := 0
 		if len(stream.AudioTracks) > 0 {
-			preference := a.audioPreference
-			if preference == "" && anilistID != 0 {
-				if dbPref, err := database.GetAudioPreference(a.db, anilistID); err == nil && dbPref != "" {
-					preference = dbPref
-				}
-			}
 			if selectedTrack := audio.SelectAudioTrack(stream.AudioTracks, preference); selectedTrack != nil {
 				audioTrackIndex = selectedTrack.Index
 			}
@@ -1660,6 +1854,7 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 			Headers:    stream.Headers,
 			Referer:    stream.Referer,
 			AudioTrack: audioTrackIndex,
+			YTDL:       stream.Type == providers.StreamTypeYTDLP,
 		}
 
 		// Add subtitle if available, preferring English
@@ -1667,11 +1862,113 @@ func (a *App) resumePlaybackFromHistory(msg common.ResumePlaybackMsg) tea.Cmd {
 			playOpts.SubtitleURL = subtitle.URL
 			playOpts.SubtitleLang = "en,eng,english"
 		}
+		playOpts.SubtitleDelay = a.rememberedSubtitleDelay()
+		a.applyAudioNormalization(&playOpts)
 
+		if opCtx.Err() != nil {
+			return operationCancelledMsg{}
+		}
 		if err := a.player.Play(context.Background(), stream.URL, playOpts); err != nil {
 			return common.PlaybackErrorMsg{Error: fmt.Errorf("failed to start player: %w", err)}
 		}
 
-		return common.PlayerLaunchingMsg{}
+		return common.PlayerLaunchingMsg{Stream: stream}
+	})
+}
+
+// handleShowAudioSelectorMsg shows the audio selector when no track matches
+// the preference
+func (a *App) handleShowAudioSelectorMsg(msg common.ShowAudioSelectorMsg) (tea.Model, tea.Cmd) {
+	// Show audio selector when no matching track found
+	selector := audioselect.New(msg.Tracks, msg.AniListID)
+	a.audioSelectorModel = &selector
+	a.pendingStream = msg.Stream
+	// Store episode context for playback resumption
+	a.currentEpisodeID = msg.EpisodeID
+	a.currentEpisodeNumber = msg.EpisodeNum
+	a.currentEpisodeTitle = msg.EpisodeTitle
+	a.previousState = a.state
+	a.state = audioSelectView
+	return a, nil
+}
+
+// handleAudioSelectionMsg remembers the audio track the user picked and
+// resumes playback with it
+func (a *App) handleAudioSelectionMsg(msg audioselect.SelectionMsg) (tea.Model, tea.Cmd) {
+	// User selected an audio track
+	// Save preference to database
+	// (by AniList ID, or provider media ID for other content)
+	trackIndexPtr := &msg.Track.Index
+	preference := audio.TrackPreference(msg.Track)
+	var err error
+	switch {
+	case preference == "":
+		// Unknown track type - nothing to remember
+	case msg.AniListID > 0:
+		err = database.SaveAudioPreference(a.db, msg.AniListID, preference, trackIndexPtr)
+	case a.selectedMedia.ID != "" && a.currentPlaybackProvider != "":
+		err = database.SaveMediaAudioPreference(a.db, a.currentPlaybackProvider, a.selectedMedia.ID, preference, trackIndexPtr)
+	}
+	if err != nil {
+		a.logger.Error("failed to save audio preference", "error", err, "anilist_id", msg.AniListID, "media_id", a.selectedMedia.ID, "preference", preference)
+		// Non-blocking - playback continues even if save fails
+	} else if preference != "" {
+		a.logger.Debug("saved audio preference", "anilist_id", msg.AniListID, "media_id", a.selectedMedia.ID, "preference", preference)
+	}
+	// Resume playback with selected track
+	a.state = launchingPlayerView
+	return a, a.continuePlaybackWithAudioTrack(msg.Track.Index)
+}
+
+// handleAudioSelectCancelMsg returns to the previous view when audio
+// selection is canceled
+func (a *App) handleAudioSelectCancelMsg() (tea.Model, tea.Cmd) {
+	// User canceled audio selection - return to previous view
+	a.state = a.previousState
+	a.selectedAudioTrack = nil
+	a.pendingStream = nil
+	return a, nil
+}
+
+// handleShowResumePromptMsg asks whether to resume or start over before
+// launching the player
+func (a *App) handleShowResumePromptMsg(msg common.ShowResumePromptMsg) (tea.Model, tea.Cmd) {
+	// Ask whether to resume or start over before launching the player
+	a.pendingResume = &msg
+	a.state = resumePromptView
+	return a, nil
+}
+
+// handleShowSourceSelectorMsg lets the user pick the server or mirror of an
+// episode
+func (a *App) handleShowSourceSelectorMsg(msg common.ShowSourceSelectorMsg) (tea.Model, tea.Cmd) {
+	// Let the user pick the server/mirror for this episode
+	title := msg.EpisodeTitle
+	if msg.EpisodeNum > 0 {
+		title = fmt.Sprintf("%s - Episode %d", a.selectedMedia.Title, msg.EpisodeNum)
 	}
+	selector := sourceselect.New(msg.Sources, title)
+	a.sourceSelectorModel = &selector
+	a.currentEpisodeID = msg.EpisodeID
+	a.currentEpisodeNumber = msg.EpisodeNum
+	a.currentEpisodeTitle = msg.EpisodeTitle
+	a.state = sourceSelectView
+	return a, nil
+}
+
+// handleSourceSelectionMsg plays the source the user picked
+func (a *App) handleSourceSelectionMsg(msg sourceselect.SelectionMsg) (tea.Model, tea.Cmd) {
+	a.sourceSelectorModel = nil
+	a.state = loadingView
+	a.loadingOp = loadingStream
+	return a, tea.Batch(a.spinner.Tick, a.playSelectedSource(msg.Source.Stream))
+}
+
+// handleSourceSelectCancelMsg returns to the previous view when source
+// selection is canceled
+func (a *App) handleSourceSelectCancelMsg() (tea.Model, tea.Cmd) {
+	// User canceled source selection - return to previous view
+	a.sourceSelectorModel = nil
+	a.state = a.previousState
+	return a, nil
 }
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+func init() {
+	handleCmd(func(a *App, msg nextEpisodePreloadedMsg) tea.Cmd {
+		a.handleNextEpisodePreloadedMsg(msg)
+		return nil
+	})
+}
+
+const (
+	// preloadAfter is how far into an episode, in percent, the next one is
+	// resolved
+	preloadAfter = 50.0
+	// preloadTTL is how long a preloaded stream is used; providers' stream
+	// links expire
+	preloadTTL = 30 * time.Minute
+)
+
+// preloadedStream is the next episode's stream, resolved while the current
+// one plays so continuing to it doesn't wait on the provider
+type preloadedStream struct {
+	session   int    // Player session it was resolved during
+	provider  string // Provider the stream comes from
+	episodeID string
+	stream    *providers.StreamURL // Nil until resolved
+	at        time.Time
+}
+
+// nextEpisodePreloadedMsg carries the next episode's resolved stream
+type nextEpisodePreloadedMsg struct {
+	session   int
+	episodeID string
+	stream    *providers.StreamURL
+	err       error
+}
+
+// preloadNextEpisode resolves the next episode's stream once playback is
+// past preloadAfter, once per playback
+func (a *App) preloadNextEpisode(progress *player.PlaybackProgress) tea.Cmd {
+	cfg := a.playerConfig()
+	if cfg == nil || !cfg.PreloadNext || cfg.SourceSelector || progress == nil || progress.Percentage < preloadAfter {
+		return nil
+	}
+	if a.preload.session == a.playerSession || a.currentEpisodeNumber == 0 {
+		return nil
+	}
+	next := a.findNextEpisode(a.currentEpisodeNumber, a.currentSeasonNumber)
+	provider, ok := a.providers[a.currentMediaType]
+	if next == nil || !ok {
+		return nil
+	}
+
+	a.preload = preloadedStream{session: a.playerSession, provider: provider.Name(), episodeID: next.ID}
+	session, episodeID := a.playerSession, next.ID
+	timeout := a.providerTimeout(provider.Name(), config.TimeoutStream)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
+		return nextEpisodePreloadedMsg{session: session, episodeID: episodeID, stream: stream, err: err}
+	}
+}
+
+// handleNextEpisodePreloadedMsg keeps the preloaded stream for when the
+// next episode is played
+func (a *App) handleNextEpisodePreloadedMsg(msg nextEpisodePreloadedMsg) {
+	if msg.session != a.preload.session || msg.episodeID != a.preload.episodeID {
+		return
+	}
+	if msg.err != nil {
+		a.logger.Debug("failed to preload next episode", "episode_id", msg.episodeID, "error", msg.err)
+		return
+	}
+	a.preload.stream = msg.stream
+	a.preload.at = time.Now()
+}
+
+// takePreloaded returns the preloaded stream of an episode on provider, nil
+// when there is none or it is too old. A stream is only used once.
+func (a *App) takePreloaded(provider, episodeID string) *providers.StreamURL {
+	p := a.preload
+	if p.stream == nil || p.provider != provider || p.episodeID != episodeID || time.Since(p.at) > preloadTTL {
+		return nil
+	}
+	a.preload.stream = nil
+	return p.stream
+}
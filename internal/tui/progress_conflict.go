@@ -0,0 +1,188 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// progressConflict is an auto-play target that differs between local
+// history and AniList, waiting for the user's choice
+type progressConflict struct {
+	title    string
+	local    int // Next episode according to local history
+	anilist  int // Next episode according to AniList progress
+	episodes []providers.Episode
+}
+
+// localNextEpisode returns the next episode to play according to local
+// history, or 0 when the media has none
+func (a *App) localNextEpisode(mediaID string) int {
+	if a.db == nil {
+		return 0
+	}
+
+	var latest database.History
+	if err := a.db.Where("media_id = ?", mediaID).Order("episode DESC").First(&latest).Error; err != nil {
+		return 0
+	}
+	if latest.Completed {
+		return latest.Episode + 1
+	}
+	return latest.Episode
+}
+
+// trustMaxProgress reports whether progress conflicts resolve to the furthest
+// episode without asking
+func (a *App) trustMaxProgress() bool {
+	cfg := a.config()
+	return cfg != nil && cfg.Tracker.AniList.ProgressConflict == "max"
+}
+
+// autoPlayAniList plays the next episode of the current AniList entry. When
+// local history and AniList disagree it asks which one to follow. Returns
+// false if there was nothing to play.
+func (a *App) autoPlayAniList(episodes []providers.Episode) (tea.Cmd, bool) {
+	media := a.currentAniListMedia
+	if a.coWatch != nil {
+		// The co-watch profile has its own track; AniList is solo progress
+		cmd := a.playAniListEpisode(episodes, max(a.anilistEpisode(a.coWatchNextEpisode()), 1))
+		return cmd, cmd != nil
+	}
+
+	// Progress is the number of episodes completed, so play Progress + 1
+	anilistNext := media.Progress + 1
+	localNext := a.localNextEpisode(a.selectedMedia.ID)
+	if localNext > 0 {
+		// History counts provider episodes, which may include earlier cours
+		localNext = max(a.anilistEpisode(localNext), 1)
+	}
+
+	a.debugLog("AniList Auto-Play: Progress=%d, AniList target=%d, local target=%d, TotalEpisodes=%d",
+		media.Progress, anilistNext, localNext, media.TotalEpisodes)
+
+	target := anilistNext
+	if localNext > 0 && localNext != anilistNext {
+		if !a.trustMaxProgress() {
+			a.pendingConflict = &progressConflict{
+				title:    media.Title,
+				local:    localNext,
+				anilist:  anilistNext,
+				episodes: episodes,
+			}
+			a.state = progressConflictView
+			return nil, true
+		}
+		target = max(localNext, anilistNext)
+		a.debugLog("AniList Auto-Play: Progress conflict resolved to furthest -> Target=%d", target)
+	}
+
+	cmd := a.playAniListEpisode(episodes, target)
+	return cmd, cmd != nil
+}
+
+// playAniListEpisode plays the given episode of the current AniList entry,
+// clamped to the last episode
+func (a *App) playAniListEpisode(episodes []providers.Episode, target int) tea.Cmd {
+	// If all episodes are complete, play the last episode
+	if total := a.currentAniListMedia.TotalEpisodes; total > 0 && target > total {
+		target = total
+	}
+	target = a.providerEpisode(target)
+
+	var episodeToPlay *providers.Episode
+	for i := range episodes {
+		if episodes[i].Number == target {
+			episodeToPlay = &episodes[i]
+			break
+		}
+	}
+
+	if episodeToPlay == nil {
+		a.debugLog("AniList Auto-Play: Target episode %d not found in %d episodes", target, len(episodes))
+		if len(episodes) == 0 {
+			return nil
+		}
+		// Fallback: play the first episode if exact match not found
+		episodeToPlay = &episodes[0]
+		a.debugLog("AniList Auto-Play: Falling back to first episode %d", episodeToPlay.Number)
+	}
+
+	// Clear any status message (e.g., "Switched to manual search")
+	a.statusMsg = ""
+
+	// Set previous state to anilist so user returns there after playback
+	a.previousState = anilistView
+	ep := *episodeToPlay
+	return func() tea.Msg {
+		return common.EpisodeSelectedMsg{
+			EpisodeID: ep.ID,
+			Number:    ep.Number,
+			Title:     ep.Title,
+		}
+	}
+}
+
+// handleProgressConflictKeys handles keyboard input in the progress conflict prompt
+func (a *App) handleProgressConflictKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return a, tea.Quit
+	}
+
+	conflict := a.pendingConflict
+	if conflict == nil {
+		a.state = anilistView
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "l", "L":
+		a.pendingConflict = nil
+		a.state = loadingView
+		return a, a.playAniListEpisode(conflict.episodes, conflict.local)
+	case "a", "A":
+		a.pendingConflict = nil
+		a.state = loadingView
+		return a, a.playAniListEpisode(conflict.episodes, conflict.anilist)
+	case "m", "M", "enter":
+		a.pendingConflict = nil
+		a.state = loadingView
+		return a, a.playAniListEpisode(conflict.episodes, max(conflict.local, conflict.anilist))
+	case "esc", "q":
+		// Pick the episode by hand instead
+		a.pendingConflict = nil
+		a.episodesComponent.SetMediaType(a.selectedMedia.Type)
+		a.episodesComponent.SetEpisodes(conflict.episodes)
+		a.state = episodeView
+		return a, nil
+	default:
+		return a, nil
+	}
+}
+
+// renderProgressConflict renders the progress conflict prompt
+func (a *App) renderProgressConflict() string {
+	conflict := a.pendingConflict
+	if conflict == nil {
+		return ""
+	}
+
+	dialogView := styles.PopupStyle.Render(i18n.T(
+		"%s\n\nLocal says ep %d, AniList says ep %d — which to use?\n\n[l] Local (ep %d)\n[a] AniList (ep %d)\n[m] Furthest (ep %d)\n[esc] Pick an episode",
+		conflict.title, conflict.local, conflict.anilist,
+		conflict.local, conflict.anilist, max(conflict.local, conflict.anilist)))
+	return lipgloss.Place(
+		a.width,
+		a.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialogView,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+)
+
+const (
+	// lowRefreshFPS caps redraws per second in low-refresh mode
+	lowRefreshFPS = 10
+	// lowRefreshMinInterval is the shortest refresh tick in low-refresh mode
+	lowRefreshMinInterval = 2 * time.Second
+
+	defaultDownloadsRefresh = 300 * time.Millisecond
+	defaultPlaybackRefresh  = time.Second
+)
+
+// staticSpinner is drawn instead of an animated spinner in low-refresh mode.
+// Its frame never changes, so it ticks once an hour rather than not at all.
+var staticSpinner = spinner.Spinner{
+	Frames: []string{"…"},
+	FPS:    time.Hour,
+}
+
+// lowRefreshEnabled reports whether ui.low_refresh is on
+func lowRefreshEnabled(cfg *config.Config) bool {
+	return cfg != nil && cfg.UI.LowRefresh
+}
+
+// refreshInterval returns a configured tick interval, falling back to def
+// when unset and raised to lowRefreshMinInterval in low-refresh mode
+func refreshInterval(cfg *config.Config, pick func(config.RefreshConfig) time.Duration, def time.Duration) time.Duration {
+	interval := def
+	if cfg != nil {
+		if configured := pick(cfg.UI.Refresh); configured > 0 {
+			interval = configured
+		}
+	}
+	if lowRefreshEnabled(cfg) {
+		interval = max(interval, lowRefreshMinInterval)
+	}
+	return interval
+}
+
+// downloadsRefreshInterval is how often the downloads view refreshes
+func downloadsRefreshInterval(cfg *config.Config) time.Duration {
+	return refreshInterval(cfg, func(r config.RefreshConfig) time.Duration { return r.Downloads }, defaultDownloadsRefresh)
+}
+
+// playbackRefreshInterval is how often playback progress is checked
+func playbackRefreshInterval(cfg *config.Config) time.Duration {
+	return refreshInterval(cfg, func(r config.RefreshConfig) time.Duration { return r.Playback }, defaultPlaybackRefresh)
+}
+
+// programOptions returns the bubbletea options for the configured refresh mode
+func programOptions(cfg *config.Config) []tea.ProgramOption {
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if lowRefreshEnabled(cfg) {
+		opts = append(opts, tea.WithFPS(lowRefreshFPS))
+	}
+	return opts
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/instance"
+	"github.com/justchokingaround/greg/internal/links"
+	"github.com/justchokingaround/greg/internal/providers/manual"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle((*App).handleRemoteRequestMsg)
+}
+
+// remoteReplyTimeout bounds how long a forwarded command waits for the TUI
+const remoteReplyTimeout = 3 * time.Second
+
+// remoteRequestMsg is a command forwarded from another greg invocation
+type remoteRequestMsg struct {
+	req   instance.Request
+	reply chan error
+}
+
+// serveRemote hands commands forwarded to inst over to the running program
+func serveRemote(inst *instance.Instance, p *tea.Program) {
+	inst.Serve(func(req instance.Request) error {
+		reply := make(chan error, 1)
+		p.Send(remoteRequestMsg{req: req, reply: reply})
+
+		select {
+		case err := <-reply:
+			return err
+		case <-time.After(remoteReplyTimeout):
+			return fmt.Errorf("greg did not respond")
+		}
+	})
+}
+
+// handleRemoteRequestMsg runs a forwarded command and reports the outcome
+func (a *App) handleRemoteRequestMsg(msg remoteRequestMsg) (tea.Model, tea.Cmd) {
+	switch msg.req.Command {
+	case instance.CommandOpen:
+		if len(msg.req.Args) != 1 {
+			msg.reply <- fmt.Errorf("open takes exactly one link")
+			return a, nil
+		}
+		if _, ok := links.Parse(msg.req.Args[0]); !ok {
+			msg.reply <- fmt.Errorf("unsupported link: %s", msg.req.Args[0])
+			return a, nil
+		}
+		// Don't pull the user out of playback or a dialog
+		if !a.isIdleForLink() {
+			msg.reply <- fmt.Errorf("greg is busy, try again when it is idle")
+			return a, nil
+		}
+
+		msg.reply <- nil
+		return a.handleOpenLinkMsg(common.OpenLinkMsg{URL: msg.req.Args[0]})
+	case instance.CommandPlay:
+		if len(msg.req.Args) < 3 {
+			msg.reply <- fmt.Errorf("play takes a URL, a referer and a title")
+			return a, nil
+		}
+		headers, err := manual.ParseHeaders(msg.req.Args[3:])
+		if err != nil {
+			msg.reply <- err
+			return a, nil
+		}
+		stream, err := manual.Stream(msg.req.Args[0], msg.req.Args[1], headers)
+		if err != nil {
+			msg.reply <- err
+			return a, nil
+		}
+		if !a.isIdleForLink() {
+			msg.reply <- fmt.Errorf("greg is busy, try again when it is idle")
+			return a, nil
+		}
+
+		title := msg.req.Args[2]
+		if title == "" {
+			title = manual.Title(stream.URL)
+		}
+		msg.reply <- nil
+		return a, a.playManualStream(stream, title)
+	case instance.CommandDownload:
+		cmd, err := a.startRemoteDownload(msg.req.Args)
+		msg.reply <- err
+		if err != nil {
+			return a, nil
+		}
+		return a, tea.Batch(cmd, a.toast(severityInfo, i18n.T("Queuing downloads sent from another terminal...")))
+	default:
+		msg.reply <- fmt.Errorf("unknown command: %s", msg.req.Command)
+		return a, nil
+	}
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/resolve"
+)
+
+func init() {
+	handle((*App).handleRemoteDownloadMsg)
+}
+
+// remoteDownload is a 'greg download' forwarded from another invocation
+type remoteDownload struct {
+	provider     providers.Provider
+	mediaID      string
+	episodeRange string
+	quality      providers.Quality
+	onDuplicate  downloader.DuplicateAction
+	embedSubs    bool
+}
+
+// remoteDownloadMsg reports the episodes a forwarded download queued
+type remoteDownloadMsg struct {
+	title   string
+	queued  int
+	skipped int
+	err     error
+}
+
+// parseRemoteDownload reads the arguments of a forwarded download: provider,
+// media ID, episode range, quality and duplicate action
+func parseRemoteDownload(args []string) (remoteDownload, error) {
+	if len(args) != 5 {
+		return remoteDownload{}, fmt.Errorf("download takes 5 arguments, got %d", len(args))
+	}
+
+	p, err := providers.Get(args[0])
+	if err != nil {
+		return remoteDownload{}, fmt.Errorf("provider %s not found: %w", args[0], err)
+	}
+	if args[1] == "" {
+		return remoteDownload{}, fmt.Errorf("missing media ID")
+	}
+	quality := providers.Quality1080p
+	if args[3] != "" {
+		if quality, err = providers.ParseQuality(args[3]); err != nil {
+			return remoteDownload{}, fmt.Errorf("invalid quality: %w", err)
+		}
+	}
+
+	return remoteDownload{
+		provider:     p,
+		mediaID:      args[1],
+		episodeRange: args[2],
+		quality:      quality,
+		onDuplicate:  downloader.DuplicateAction(args[4]),
+	}, nil
+}
+
+// queue resolves the episodes of the download and adds them to manager's queue
+func (d remoteDownload) queue(ctx context.Context, manager *downloader.Manager, timeouts config.TimeoutsConfig) remoteDownloadMsg {
+	detailsCtx, cancel := context.WithTimeout(ctx, timeouts.Budget(d.provider.Name(), config.TimeoutDetails, config.TimeoutDetails))
+	defer cancel()
+
+	details, err := d.provider.GetMediaDetails(detailsCtx, d.mediaID)
+	if err != nil {
+		return remoteDownloadMsg{title: d.mediaID, err: fmt.Errorf("failed to get media details: %w", err)}
+	}
+
+	msg := remoteDownloadMsg{title: details.Title}
+	var episodes []providers.Episode
+	if len(details.Seasons) == 0 && details.Type == providers.MediaTypeMovie {
+		episodeID, err := providers.MovieEpisodeID(detailsCtx, d.provider, d.mediaID)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		episodes = []providers.Episode{{ID: episodeID, Number: 1}}
+	} else {
+		if episodes, err = resolve.Episodes(detailsCtx, d.provider, d.mediaID, 0); err != nil {
+			msg.err = err
+			return msg
+		}
+		if d.episodeRange != "" {
+			if episodes, err = providers.ParseEpisodeRange(episodes, d.episodeRange); err != nil {
+				msg.err = fmt.Errorf("failed to parse episode range: %w", err)
+				return msg
+			}
+		}
+	}
+
+	for _, ep := range episodes {
+		streamCtx, cancel := context.WithTimeout(ctx, timeouts.Budget(d.provider.Name(), config.TimeoutStream))
+		stream, err := d.provider.GetStreamURL(streamCtx, ep.ID, d.quality)
+		cancel()
+		if err != nil {
+			msg.err = fmt.Errorf("episode %d: %w", ep.Number, err)
+			continue
+		}
+
+		err = manager.AddToQueue(ctx, downloader.DownloadTask{
+			MediaID:     d.mediaID,
+			MediaTitle:  details.Title,
+			MediaType:   details.Type,
+			Episode:     ep.Number,
+			Quality:     d.quality,
+			Provider:    d.provider.Name(),
+			StreamURL:   stream.URL,
+			StreamType:  stream.Type,
+			Headers:     stream.Headers,
+			Referer:     stream.Referer,
+			Subtitles:   stream.Subtitles,
+			EmbedSubs:   d.embedSubs,
+			OnDuplicate: d.onDuplicate,
+		})
+		switch {
+		case err == nil:
+			msg.queued++
+		case errors.Is(err, downloader.ErrInsufficientSpace):
+			msg.err = err
+			return msg
+		default:
+			if _, dup := asDuplicate(err); dup || strings.Contains(err.Error(), "already in queue") {
+				msg.skipped++
+			} else {
+				msg.err = fmt.Errorf("episode %d: %w", ep.Number, err)
+			}
+		}
+	}
+	return msg
+}
+
+// startRemoteDownload queues a forwarded download in the background
+func (a *App) startRemoteDownload(args []string) (tea.Cmd, error) {
+	if a.downloadMgr == nil {
+		return nil, fmt.Errorf("downloads are not available")
+	}
+	download, err := parseRemoteDownload(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeouts config.TimeoutsConfig
+	if cfg := a.config(); cfg != nil {
+		timeouts = cfg.Network.Timeouts
+		download.embedSubs = cfg.Downloads.EmbedSubtitles
+	}
+	manager := a.downloadMgr
+	return func() tea.Msg {
+		return download.queue(context.Background(), manager, timeouts)
+	}, nil
+}
+
+// handleRemoteDownloadMsg reports what a forwarded download queued
+func (a *App) handleRemoteDownloadMsg(msg remoteDownloadMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if a.state == downloadsView {
+		cmds = append(cmds, a.downloadsComponent.Refresh())
+	}
+
+	if msg.err != nil {
+		a.logger.Warn("forwarded download not fully queued", "title", msg.title, "queued", msg.queued, "error", msg.err)
+		cmds = append(cmds, a.toast(severityError, i18n.T("Queued %d episodes of %s, the rest failed: %v", msg.queued, msg.title, msg.err)))
+		return a, tea.Batch(cmds...)
+	}
+
+	text := i18n.T("Queued %d episodes of %s", msg.queued, msg.title)
+	if msg.skipped > 0 {
+		text += i18n.T(", %d were already queued or downloaded", msg.skipped)
+	}
+	cmds = append(cmds, a.toast(severitySuccess, text))
+	return a, tea.Batch(cmds...)
+}
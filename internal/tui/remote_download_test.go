@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/providers"
+	fakeprovider "github.com/justchokingaround/greg/internal/providers/fake"
+)
+
+func TestRemoteDownload(t *testing.T) {
+	p := fakeprovider.New("remote", providers.MediaTypeAnime, frieren)
+	require.NoError(t, providers.Register(p))
+	t.Cleanup(func() { _ = providers.Unregister(p.Name()) })
+
+	_, err := parseRemoteDownload([]string{"remote", "frieren"})
+	assert.Error(t, err)
+	_, err = parseRemoteDownload([]string{"nope", "frieren", "", "", ""})
+	assert.Error(t, err)
+
+	download, err := parseRemoteDownload([]string{"remote", frieren.Media.ID, "2-3", "720p", ""})
+	require.NoError(t, err)
+	assert.Equal(t, providers.Quality720p, download.quality)
+
+	db := newHeadlessDB(t)
+	// Not started, so what is queued stays queued
+	manager, err := downloader.NewManager(db, &config.DownloadsConfig{
+		Path: t.TempDir(), Concurrent: 1, AnimeFilenameTemplate: "{title} - {episode:03d} [{quality}]",
+	}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	msg := download.queue(ctx, manager, config.TimeoutsConfig{})
+	require.NoError(t, msg.err)
+	assert.Equal(t, 2, msg.queued)
+
+	tasks, err := manager.GetQueue(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, []int{2, 3}, []int{tasks[0].Episode, tasks[1].Episode})
+	assert.Equal(t, "remote", tasks[0].Provider)
+
+	// Forwarding the same download again queues nothing new
+	msg = download.queue(ctx, manager, config.TimeoutsConfig{})
+	require.NoError(t, msg.err)
+	assert.Equal(t, 0, msg.queued)
+	assert.Equal(t, 2, msg.skipped)
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Messages reach the app through routes. Each file handling messages
+// subscribes its handlers to their message types from an init function, and
+// Update dispatches on the type of the message, so a new view registers its
+// messages next to its handlers. Messages without a route go to the
+// component of the current view.
+
+// route updates the app for one type of message
+type route func(a *App, msg tea.Msg) (tea.Model, tea.Cmd)
+
+// routes are the handlers subscribed to each message type
+var routes = make(map[reflect.Type]route)
+
+// handle subscribes fn to messages of type M. A message type has one handler.
+func handle[M tea.Msg, R tea.Model](fn func(a *App, msg M) (R, tea.Cmd)) {
+	subscribe[M](func(a *App, msg tea.Msg) (tea.Model, tea.Cmd) {
+		return fn(a, msg.(M))
+	})
+}
+
+// handleCmd subscribes fn, which only returns the next command, to messages
+// of type M
+func handleCmd[M tea.Msg](fn func(a *App, msg M) tea.Cmd) {
+	subscribe[M](func(a *App, msg tea.Msg) (tea.Model, tea.Cmd) {
+		return a, fn(a, msg.(M))
+	})
+}
+
+func subscribe[M tea.Msg](r route) {
+	t := reflect.TypeFor[M]()
+	if _, ok := routes[t]; ok {
+		panic(fmt.Sprintf("tui: %v is already handled", t))
+	}
+	routes[t] = r
+}
+
+// dispatch hands msg to the handler subscribed to its type, reporting false
+// when there is none
+func (a *App) dispatch(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
+	r, ok := routes[reflect.TypeOf(msg)]
+	if !ok {
+		return nil, nil, false
+	}
+	model, cmd := r(a, msg)
+	return model, cmd, true
+}
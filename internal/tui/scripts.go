@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/downloader"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/scripting"
+)
+
+// scriptHost carries out what the Lua scripts ask for
+type scriptHost struct {
+	app *App
+}
+
+// loadScripts starts the scripts of scripts.dir
+func (a *App) loadScripts() {
+	cfg := a.config()
+	if cfg == nil || !cfg.Scripts.Enabled {
+		return
+	}
+	engine, err := scripting.Load(cfg.Scripts.ScriptsDir(), scriptHost{app: a}, a.logger)
+	if err != nil {
+		a.logger.Warn("failed to load scripts", "error", err)
+		if engine == nil {
+			return
+		}
+		a.notifyAsync(severityWarning, i18n.T("Some scripts failed to load, see the log"))
+	}
+	a.scripts = engine
+}
+
+// emitScriptEvent hands an event to the scripts; safe from any goroutine
+func (a *App) emitScriptEvent(name string, event scripting.Event) {
+	a.scripts.Emit(name, event)
+}
+
+// emitSearchEvent reports a search to the scripts
+func (a *App) emitSearchEvent(query, provider string, results int) {
+	a.emitScriptEvent(scripting.EventSearch, scripting.Event{
+		"query":      query,
+		"provider":   provider,
+		"media_type": string(a.currentMediaType),
+		"results":    results,
+	})
+}
+
+// playbackEvent describes the episode playing for the scripts
+func (a *App) playbackEvent() scripting.Event {
+	anilistID := a.playingAniListID()
+	_, title, mediaType := a.playbackMedia(anilistID, a.currentEpisodeNumber)
+	event := scripting.Event{
+		"title":      title,
+		"media_id":   a.selectedMedia.ID,
+		"provider":   a.currentPlaybackProvider,
+		"media_type": mediaType,
+		"season":     a.currentSeasonNumber,
+		"episode":    a.currentEpisodeNumber,
+	}
+	if anilistID != nil {
+		event["anilist_id"] = *anilistID
+	}
+	return event
+}
+
+// emitPlaybackEnded reports the end of playback, and a completed episode
+func (a *App) emitPlaybackEnded(progress *player.PlaybackProgress) {
+	if a.scripts == nil {
+		return
+	}
+	event := a.playbackEvent()
+	completed := a.completionRule().Completed(*progress)
+	event["position"] = int(progress.CurrentTime.Seconds())
+	event["duration"] = int(progress.Duration.Seconds())
+	event["percentage"] = progress.Percentage
+	event["completed"] = completed
+	a.emitScriptEvent(scripting.EventPlaybackEnded, event)
+	if completed && a.currentEpisodeNumber > 0 {
+		a.emitScriptEvent(scripting.EventEpisodeCompleted, event)
+	}
+}
+
+// downloadEvent describes a download for the scripts
+func downloadEvent(task downloader.DownloadTask) scripting.Event {
+	return scripting.Event{
+		"title":    task.MediaTitle,
+		"media_id": task.MediaID,
+		"provider": task.Provider,
+		"season":   task.Season,
+		"episode":  task.Episode,
+		"quality":  string(task.Quality),
+		"path":     task.OutputPath,
+	}
+}
+
+// Notify shows a script's message as a toast
+func (h scriptHost) Notify(script, text string) {
+	h.app.notifyAsync(severityInfo, fmt.Sprintf("%s: %s", script, text))
+}
+
+// Download resolves the episode a script asked for and queues it in the
+// background
+func (h scriptHost) Download(req scripting.DownloadRequest) error {
+	a := h.app
+	if a.downloadMgr == nil {
+		return errors.New("downloads are not available")
+	}
+	p, err := providers.Get(req.Provider)
+	if err != nil {
+		return err
+	}
+	quality := providers.Quality1080p
+	if req.Quality != "" {
+		if quality, err = providers.ParseQuality(req.Quality); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		if err := a.queueScriptDownload(p, req, quality); err != nil {
+			a.logger.Warn("script download failed", "script", req.Script, "title", req.Title, "episode", req.Episode, "error", err)
+			a.notifyAsync(severityError, i18n.T("%s: couldn't download %s episode %d: %v", req.Script, req.Title, req.Episode, err))
+			return
+		}
+		a.notifyAsync(severityInfo, i18n.T("%s: queued %s episode %d", req.Script, req.Title, req.Episode))
+	}()
+	return nil
+}
+
+// queueScriptDownload looks up the stream of a script's download and adds
+// it to the queue
+func (a *App) queueScriptDownload(p providers.Provider, req scripting.DownloadRequest, quality providers.Quality) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(p.Name(), config.TimeoutSearch, config.TimeoutDetails, config.TimeoutStream))
+	defer cancel()
+
+	mediaID := req.MediaID
+	if mediaID == "" {
+		media, err := providers.FindMedia(ctx, p, req.Title)
+		if err != nil {
+			return err
+		}
+		mediaID = media.ID
+	}
+	stream, err := providers.EpisodeStream(ctx, p, mediaID, req.Season, req.Episode, quality)
+	if err != nil {
+		return err
+	}
+
+	mediaType := p.Type()
+	if mediaType == providers.MediaTypeMovieTV {
+		mediaType = providers.MediaTypeTV
+	}
+	return a.downloadMgr.AddToQueue(ctx, downloader.DownloadTask{
+		MediaID:    mediaID,
+		MediaTitle: req.Title,
+		MediaType:  mediaType,
+		Episode:    req.Episode,
+		Season:     req.Season,
+		Quality:    quality,
+		Provider:   p.Name(),
+		StreamURL:  stream.URL,
+		StreamType: stream.Type,
+		Headers:    stream.Headers,
+		Referer:    stream.Referer,
+		Subtitles:  stream.Subtitles,
+		EmbedSubs:  true,
+	})
+}
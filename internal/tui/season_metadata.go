@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/metadata/tmdb"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// episodeNumberPrefix matches provider title prefixes like "Eps 3:" or "Episode 3"
+var episodeNumberPrefix = regexp.MustCompile(`(?i)^(eps?|episode)\s*\d+[:\-\s]*`)
+
+// fillSeasonMetadata fills episode titles, synopses, air dates, runtimes and
+// thumbnails the provider left empty from TMDB. It does nothing without a
+// TMDB API key.
+func (a *App) fillSeasonMetadata(media providers.Media, season int, episodes []providers.Episode) {
+	cfg := a.config()
+	if cfg == nil || cfg.Metadata.TMDBAPIKey == "" || len(episodes) == 0 {
+		return
+	}
+	if season <= 0 {
+		season = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := tmdb.NewClient(cfg.Metadata.TMDBAPIKey)
+	showID, err := client.SearchShow(ctx, media.Title, media.Year)
+	if err != nil {
+		a.debugLog("TMDB: show lookup failed for %s: %v", media.Title, err)
+		return
+	}
+	seasonEpisodes, err := client.GetSeason(ctx, showID, season)
+	if err != nil {
+		a.debugLog("TMDB: season %d lookup failed for %s: %v", season, media.Title, err)
+		return
+	}
+
+	byNumber := make(map[int]tmdb.Episode, len(seasonEpisodes))
+	for _, ep := range seasonEpisodes {
+		byNumber[ep.Number] = ep
+	}
+
+	for i := range episodes {
+		ep, found := byNumber[episodes[i].Number]
+		if !found {
+			continue
+		}
+		if isPlaceholderTitle(episodes[i]) && ep.Title != "" {
+			episodes[i].Title = ep.Title
+		}
+		if episodes[i].Synopsis == "" {
+			episodes[i].Synopsis = ep.Overview
+		}
+		if episodes[i].ReleaseDate.IsZero() {
+			episodes[i].ReleaseDate = ep.AirDate
+		}
+		if episodes[i].Duration == 0 {
+			episodes[i].Duration = ep.Runtime
+		}
+		if episodes[i].ThumbnailURL == "" {
+			episodes[i].ThumbnailURL = ep.Still
+		}
+	}
+}
+
+// isPlaceholderTitle reports whether a provider episode title carries no
+// information beyond the episode number, e.g. "Episode 3" or "Eps 3:"
+func isPlaceholderTitle(ep providers.Episode) bool {
+	title := strings.TrimSpace(episodeNumberPrefix.ReplaceAllString(ep.Title, ""))
+	return title == "" || title == strconv.Itoa(ep.Number)
+}
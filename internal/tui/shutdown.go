@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"context"
+	"time"
+)
+
+// shutdownTimeout bounds each step of the shutdown sequence
+const shutdownTimeout = 5 * time.Second
+
+// shutdown stops every subsystem after the TUI exits: in-flight loads are
+// cancelled, playback progress is saved and mpv stopped, active downloads are
+// paused so they resume on the next run, and background writes are waited
+// for. The caller closes the database afterwards.
+func (a *App) shutdown() {
+	a.cancelOperation()
+	a.stopPlayerOnExit()
+	a.stopAmbient()
+
+	if a.downloadMgr != nil {
+		// Stop marks active downloads as paused, so they resume next time
+		if err := a.downloadMgr.Stop(); err != nil {
+			a.logger.Warn("failed to stop download manager", "error", err)
+		}
+	}
+
+	a.waitBackground()
+	a.scripts.Close()
+	a.closeImageCache()
+}
+
+// stopPlayerOnExit saves the progress of a playback still running and stops mpv
+func (a *App) stopPlayerOnExit() {
+	if a.player == nil || !a.player.IsPlaying() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if progress, err := a.player.GetProgress(ctx); err == nil {
+		a.syncProgressOnEnd(progress)
+	} else {
+		a.logger.Warn("failed to read playback progress on exit", "error", err)
+	}
+
+	if err := a.player.Stop(ctx); err != nil {
+		a.logger.Warn("failed to stop player", "error", err)
+	}
+}
+
+// goBackground runs fn in a goroutine that shutdown waits for
+func (a *App) goBackground(fn func()) {
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		fn()
+	}()
+}
+
+// waitBackground waits for background writes, giving up after shutdownTimeout
+func (a *App) waitBackground() {
+	done := make(chan struct{})
+	go func() {
+		a.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		a.logger.Warn("background writes still running at exit")
+	}
+}
@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+// SleepTimer starts the TUI with a sleep timer, set by main (--sleep)
+var SleepTimer string
+
+// sleepPresets are the timers 'z' cycles through while playing; 0 is
+// "after this episode"
+var sleepPresets = []time.Duration{0, 15 * time.Minute, 30 * time.Minute, time.Hour}
+
+// sleepTimer stops playback after the current episode or at a set time
+type sleepTimer struct {
+	afterEpisode bool
+	at           time.Time // Zero unless stopping at a time
+	preset       int       // Index in sleepPresets + 1, 0 when off or not a preset
+	fired        bool      // Went off; autoplay is held back until the next playback
+}
+
+// active reports whether the timer is set and hasn't gone off yet
+func (t sleepTimer) active() bool {
+	return !t.fired && (t.afterEpisode || !t.at.IsZero())
+}
+
+// label describes when the timer goes off
+func (t sleepTimer) label() string {
+	if t.afterEpisode {
+		return i18n.T("after this episode")
+	}
+	return i18n.T("at %s", t.at.Format("15:04"))
+}
+
+// parseSleepTimer reads "episode", a duration ("45m") or a clock time
+// ("23:30", the next one to come)
+func parseSleepTimer(value string, now time.Time) (sleepTimer, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "episode" || value == "ep" {
+		return sleepTimer{afterEpisode: true}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return sleepTimer{at: now.Add(d)}, nil
+	}
+	if clock, err := time.Parse("15:04", value); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return sleepTimer{at: at}, nil
+	}
+	return sleepTimer{}, fmt.Errorf("invalid sleep timer %q: use episode, a duration such as 45m or a time such as 23:30", value)
+}
+
+// CheckSleepTimer reports whether value is a valid --sleep timer
+func CheckSleepTimer(value string) error {
+	_, err := parseSleepTimer(value, time.Now())
+	return err
+}
+
+// playerConfig returns the player settings, or nil without a config
+func (a *App) playerConfig() *config.PlayerConfig {
+	if cfg := a.config(); cfg != nil {
+		return &cfg.Player
+	}
+	return nil
+}
+
+// cycleSleepTimer switches the sleep timer to the next preset, then off
+func (a *App) cycleSleepTimer() tea.Cmd {
+	next := a.sleep.preset
+	if !a.sleep.active() {
+		next = 0
+	}
+	if next >= len(sleepPresets) {
+		a.sleep = sleepTimer{}
+		return a.toast(severityInfo, i18n.T("Sleep timer off"))
+	}
+
+	if d := sleepPresets[next]; d == 0 {
+		a.sleep = sleepTimer{afterEpisode: true}
+	} else {
+		a.sleep = sleepTimer{at: time.Now().Add(d)}
+	}
+	a.sleep.preset = next + 1
+	return a.toast(severityInfo, i18n.T("Sleep timer: stopping %s", a.sleep.label()))
+}
+
+// checkSleepTimer pauses or stops mpv once the sleep timer's time comes
+func (a *App) checkSleepTimer() tea.Cmd {
+	if !a.sleep.active() || a.sleep.at.IsZero() || time.Now().Before(a.sleep.at) || a.player == nil {
+		return nil
+	}
+	a.sleep.fired = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if cfg := a.playerConfig(); cfg != nil && cfg.SleepAction == "stop" {
+		// The player exiting ends playback as usual
+		_ = a.player.Stop(ctx)
+		return a.toast(severityInfo, i18n.T("Sleep timer: stopped playback"))
+	}
+	if err := a.player.SetPaused(ctx, true); err != nil {
+		a.logger.Warn("sleep timer failed to pause mpv", "error", err)
+	}
+	return a.toast(severityInfo, i18n.T("Sleep timer: paused playback"))
+}
+
+// sleepOnPlaybackEnd fires an "after this episode" timer and reports
+// whether the next episode should be held back
+func (a *App) sleepOnPlaybackEnd() bool {
+	if a.sleep.active() && (a.sleep.afterEpisode || !time.Now().Before(a.sleep.at)) {
+		a.sleep.fired = true
+	}
+	cfg := a.playerConfig()
+	return a.sleep.fired && (cfg == nil || cfg.SleepStopsAutoplay)
+}
+
+// watchTime adds up the time spent watching today for the daily limit
+type watchTime struct {
+	day      string        // Date the total is for
+	total    time.Duration // Watched today, including earlier sessions
+	lastPos  time.Duration // Playback position at the last progress update
+	lastAt   time.Time     // When that update came, zero at playback start
+	reminded bool          // Crossing the limit was already reported
+}
+
+// watchTimeKey is the setting the watch time of a day is stored under
+func watchTimeKey(day string) string {
+	return "watch_time." + day
+}
+
+// trackWatchTime counts the playback time since the last progress update
+// and reminds once the daily limit is passed
+func (a *App) trackWatchTime(progress *player.PlaybackProgress) tea.Cmd {
+	cfg := a.playerConfig()
+	if cfg == nil || cfg.DailyLimit <= 0 || progress == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if day := now.Format(time.DateOnly); day != a.watched.day {
+		a.watched = watchTime{day: day, total: a.loadWatchTime(day), lastAt: a.watched.lastAt, lastPos: a.watched.lastPos}
+	}
+
+	starting := a.watched.lastAt.IsZero()
+	if !starting && !progress.Paused {
+		// Count wall time, not seeks or speed-ups, between nearby updates
+		moved := progress.CurrentTime - a.watched.lastPos
+		elapsed := now.Sub(a.watched.lastAt)
+		if moved > 0 && elapsed < time.Minute {
+			a.watched.total += min(moved, elapsed)
+		}
+	}
+	a.watched.lastPos, a.watched.lastAt = progress.CurrentTime, now
+
+	over := a.watched.total >= cfg.DailyLimit
+	if over && (starting || !a.watched.reminded) {
+		a.watched.reminded = true
+		return a.toast(severityWarning, i18n.T("You've watched %s today (daily limit %s)",
+			utils.FormatCountdown(a.watched.total), utils.FormatCountdown(cfg.DailyLimit)))
+	}
+	return nil
+}
+
+// loadWatchTime returns the watch time stored for a day
+func (a *App) loadWatchTime(day string) time.Duration {
+	if a.db == nil {
+		return 0
+	}
+	value, err := database.GetSetting(a.db, watchTimeKey(day))
+	if err != nil || value == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// saveWatchTime stores today's watch time when playback ends
+func (a *App) saveWatchTime() {
+	a.watched.lastAt = time.Time{}
+	if a.db == nil || a.incognito || a.watched.day == "" {
+		return
+	}
+	seconds := strconv.FormatInt(int64(a.watched.total/time.Second), 10)
+	if err := database.SetSetting(a.db, watchTimeKey(a.watched.day), seconds); err != nil {
+		a.logger.Warn("failed to save watch time", "error", err)
+	}
+}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tracker"
+	trackeranilist "github.com/justchokingaround/greg/internal/tracker/anilist"
+	"github.com/justchokingaround/greg/internal/tui/components/anilist"
+	"github.com/justchokingaround/greg/internal/tui/components/episodes"
+)
+
+func init() {
+	handle((*App).handleSpecialSelectedMsg)
+	handleCmd(func(a *App, msg specialsLoadedMsg) tea.Cmd {
+		a.handleSpecialsLoadedMsg(msg)
+		return nil
+	})
+}
+
+// specialsLookup is implemented by tracker clients that can list an anime's
+// OVAs, specials and movies
+type specialsLookup interface {
+	GetSpecials(ctx context.Context, id int) ([]trackeranilist.Special, error)
+}
+
+// seriesSpecials are the OVAs, specials and movies related to the AniList
+// entry whose episodes are listed
+type seriesSpecials struct {
+	anilistID int
+	specials  []trackeranilist.Special
+}
+
+// specialsLoadedMsg carries the specials related to an AniList entry
+type specialsLoadedMsg struct {
+	anilistID int
+	specials  []trackeranilist.Special
+	err       error
+}
+
+// loadSpecials lists the specials of the anime watched from AniList beside
+// its episodes, looking them up the first time its episodes are shown
+func (a *App) loadSpecials() tea.Cmd {
+	media := a.currentAniListMedia
+	if !a.watchingFromAniList || media == nil || media.Type != providers.MediaTypeAnime || a.currentAniListID == 0 {
+		a.specials = seriesSpecials{}
+		a.episodesComponent.SetSpecials(nil)
+		return nil
+	}
+	if a.specials.anilistID == a.currentAniListID {
+		// Refresh what was watched since
+		a.episodesComponent.SetSpecials(a.specialRows())
+		return nil
+	}
+
+	a.specials = seriesSpecials{anilistID: a.currentAniListID}
+	a.episodesComponent.SetSpecials(nil)
+
+	var lookup specialsLookup
+	if mgr := a.trackerMgr; mgr != nil {
+		lookup, _ = mgr.GetAniList().(specialsLookup)
+	}
+	if lookup == nil {
+		lookup = trackeranilist.NewClient(trackeranilist.Config{})
+	}
+	id := a.currentAniListID
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		specials, err := lookup.GetSpecials(ctx, id)
+		return specialsLoadedMsg{anilistID: id, specials: specials, err: err}
+	}
+}
+
+// handleSpecialsLoadedMsg lists the specials if their entry is still the one
+// being watched
+func (a *App) handleSpecialsLoadedMsg(msg specialsLoadedMsg) {
+	if msg.anilistID != a.specials.anilistID {
+		return
+	}
+	if msg.err != nil {
+		a.debugLog("AniList: specials lookup failed for %d: %v", msg.anilistID, msg.err)
+		return
+	}
+	a.specials.specials = msg.specials
+	a.episodesComponent.SetSpecials(a.specialRows())
+}
+
+// specialRows returns the specials as listed in the episodes view. Each is
+// tracked as its own AniList entry, so what was watched of it is the
+// furthest of its AniList progress and the episodes completed locally.
+func (a *App) specialRows() []episodes.Special {
+	if len(a.specials.specials) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(a.specials.specials))
+	for _, special := range a.specials.specials {
+		ids = append(ids, special.ID)
+	}
+	local := map[int]int{}
+	if a.db != nil {
+		counts, err := database.CompletedEpisodes(a.db, ids)
+		if err != nil {
+			a.logger.Warn("failed to count watched specials", "error", err)
+		} else {
+			local = counts
+		}
+	}
+
+	rows := make([]episodes.Special, 0, len(a.specials.specials))
+	for _, special := range a.specials.specials {
+		row := episodes.Special{
+			AniListID: special.ID,
+			MALID:     special.MALID,
+			Title:     special.Title,
+			Format:    special.Format,
+			Episodes:  special.Episodes,
+			Watched:   max(special.Progress, local[special.ID]),
+		}
+		if special.StartDate != nil {
+			row.Year = special.StartDate.Year()
+		}
+		row.Completed = special.Status == "COMPLETED" || (row.Episodes > 0 && row.Watched >= row.Episodes)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// handleSpecialSelectedMsg watches a special like an entry picked from the
+// AniList library: it is mapped to the provider through its own AniList ID
+// and its progress is tracked apart from the series
+func (a *App) handleSpecialSelectedMsg(msg episodes.SpecialSelectedMsg) (*App, tea.Cmd) {
+	media := &tracker.TrackedMedia{
+		ServiceID:     strconv.Itoa(msg.Special.AniListID),
+		Title:         msg.Special.Title,
+		Type:          providers.MediaTypeAnime,
+		TotalEpisodes: msg.Special.Episodes,
+		MALID:         msg.Special.MALID,
+		Status:        tracker.StatusPlanToWatch,
+	}
+	for _, special := range a.specials.specials {
+		if special.ID != msg.Special.AniListID {
+			continue
+		}
+		media.Progress = special.Progress
+		if status, err := tracker.ParseWatchStatus(strings.ToLower(special.Status)); err == nil {
+			media.Status = status
+		}
+	}
+	return a.handleSelectMediaMsg(anilist.SelectMediaMsg{Media: media})
+}
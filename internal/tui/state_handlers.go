@@ -7,9 +7,26 @@ import (
 
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/components/anilist"
+	"github.com/justchokingaround/greg/internal/tui/components/downloads"
+	"github.com/justchokingaround/greg/internal/tui/components/episodes"
+	"github.com/justchokingaround/greg/internal/tui/components/history"
 	"github.com/justchokingaround/greg/internal/tui/components/home"
+	"github.com/justchokingaround/greg/internal/tui/components/manga"
+	"github.com/justchokingaround/greg/internal/tui/components/mangainfo"
+	"github.com/justchokingaround/greg/internal/tui/components/providerstatus"
+	"github.com/justchokingaround/greg/internal/tui/components/results"
+	"github.com/justchokingaround/greg/internal/tui/components/search"
+	"github.com/justchokingaround/greg/internal/tui/components/seasons"
 )
 
+func init() {
+	handle((*App).handleWindowSizeMsg)
+	handle((*App).handleRefreshHistoryMsg)
+	handle((*App).handleSearchNewAnimeMsg)
+	handle((*App).handleClearStatusMsg)
+	handle((*App).handleDismissDownloadNotificationMsg)
+}
+
 func (a *App) handleRefreshHistoryMsg(msg common.RefreshHistoryMsg) (tea.Model, tea.Cmd) {
 	a.state = homeView
 	homeModel, cmd := a.home.Update(msg)
@@ -34,3 +51,80 @@ func (a *App) handleDismissDownloadNotificationMsg(msg dismissDownloadNotificati
 	a.downloadNotificationMsg = ""
 	return a, nil
 }
+
+// handleWindowSizeMsg handles a terminal resize, passing the new size to
+// every component
+func (a *App) handleWindowSizeMsg(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	a.width = msg.Width
+	a.height = msg.Height
+	// Pass window size messages to all components
+	// IMPORTANT: Must assign updated models back to preserve width/height
+	var homeCmd, searchCmd, resultsCmd, seasonsCmd, episodesCmd, anilistCmd, downloadsCmd, mangaCmd tea.Cmd
+	var homeModel, searchModel, resultsModel, seasonsModel, episodesModel, mangaModel tea.Model
+
+	homeModel, homeCmd = a.home.Update(msg)
+	a.home = homeModel.(*home.Model)
+
+	searchModel, searchCmd = a.search.Update(msg)
+	a.search = searchModel.(search.Model)
+
+	resultsModel, resultsCmd = a.results.Update(msg)
+	a.results = resultsModel.(results.Model)
+
+	seasonsModel, seasonsCmd = a.seasons.Update(msg)
+	a.seasons = seasonsModel.(seasons.Model)
+
+	episodesModel, episodesCmd = a.episodesComponent.Update(msg)
+	a.episodesComponent = episodesModel.(episodes.Model)
+
+	mangaModel, mangaCmd = a.mangaComponent.Update(msg)
+	a.mangaComponent = mangaModel.(manga.Model)
+
+	var anilistModel tea.Model
+	anilistModel, anilistCmd = a.anilistComponent.Update(msg)
+	a.anilistComponent = anilistModel.(anilist.Model)
+
+	var downloadsModel tea.Model
+	downloadsModel, downloadsCmd = a.downloadsComponent.Update(msg)
+	a.downloadsComponent = downloadsModel.(downloads.Model)
+
+	var historyModel tea.Model
+	var historyCmd tea.Cmd
+	historyModel, historyCmd = a.historyComponent.Update(msg)
+	a.historyComponent = historyModel.(history.Model)
+
+	var providerStatusModel tea.Model
+	var providerStatusCmd tea.Cmd
+	providerStatusModel, providerStatusCmd = a.providerStatusComponent.Update(msg)
+	a.providerStatusComponent = providerStatusModel.(providerstatus.Model)
+
+	// Update help component with window size
+	var helpCmd tea.Cmd
+	a.helpComponent, helpCmd = a.helpComponent.Update(msg)
+
+	// Update provider name in help
+	if p, ok := a.providers[a.currentMediaType]; ok {
+		a.helpComponent.SetProviderName(p.Name())
+	}
+
+	// Also update episodeListModel which is used for episode selection view
+	episodeListModel, _ := a.episodeListModel.Update(msg)
+	a.episodeListModel = episodeListModel.(results.Model)
+	a.episodeListModel.SetProviderName(a.providerName)
+
+	// Also update providerSelectionResult if active
+	if a.state == providerSelectionView {
+		var providerSelectionModel tea.Model
+		var providerSelectionCmd tea.Cmd
+		providerSelectionModel, providerSelectionCmd = a.providerSelectionResult.Update(msg)
+		a.providerSelectionResult = providerSelectionModel.(results.Model)
+		providerStatusCmd = tea.Batch(providerStatusCmd, providerSelectionCmd)
+	}
+
+	var mangaInfoModel tea.Model
+	var mangaInfoCmd tea.Cmd
+	mangaInfoModel, mangaInfoCmd = a.mangaInfoComponent.Update(msg)
+	a.mangaInfoComponent = mangaInfoModel.(*mangainfo.Model)
+
+	return a, tea.Batch(homeCmd, searchCmd, resultsCmd, seasonsCmd, episodesCmd, anilistCmd, downloadsCmd, helpCmd, mangaCmd, historyCmd, mangaInfoCmd, providerStatusCmd)
+}
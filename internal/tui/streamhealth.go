@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/audio"
+	"github.com/justchokingaround/greg/internal/config"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/utils"
+)
+
+func init() {
+	handleCmd((*App).handleAlternateStreamMsg)
+}
+
+// startupBuffering is how long after launch buffering is expected and not
+// counted as a stall
+const startupBuffering = 15 * time.Second
+
+// streamSwitcher is a player that can swap the stream of a running playback
+type streamSwitcher interface {
+	SwitchStream(ctx context.Context, url string, options player.PlayOptions) error
+}
+
+// streamHealth tracks the stalls of the stream playing
+type streamHealth struct {
+	stalls       []time.Time     // Starts of the stalls within the window
+	stalledSince time.Time       // Start of the ongoing stall, zero when playing
+	switching    bool            // Looking for another source
+	exhausted    bool            // No other source was found for this episode
+	tried        map[string]bool // Stream URLs played for this episode
+}
+
+// alternateStreamMsg reports the search for another source of the episode
+type alternateStreamMsg struct {
+	session int // Player session the search was for
+	stream  *providers.StreamURL
+	source  string // Server or provider of the stream
+	err     error
+}
+
+// streamHealthConfig returns player.stream_health, nil when not configured
+func (a *App) streamHealthConfig() *config.StreamHealthConfig {
+	if cfg := a.playerConfig(); cfg != nil {
+		return &cfg.StreamHealth
+	}
+	return nil
+}
+
+// resetStreamHealth starts tracking the stalls of a new episode's stream
+func (a *App) resetStreamHealth(stream *providers.StreamURL) {
+	a.health = streamHealth{tried: make(map[string]bool)}
+	if stream != nil {
+		a.health.tried[stream.URL] = true
+	}
+}
+
+// checkStreamHealth counts buffering stalls and switches to another source
+// once the stream has stalled too often or for too long
+func (a *App) checkStreamHealth(progress *player.PlaybackProgress) tea.Cmd {
+	cfg := a.streamHealthConfig()
+	if cfg == nil || !cfg.AutoSwitch || progress == nil || time.Since(a.launchStartTime) < startupBuffering {
+		return nil
+	}
+
+	now := time.Now()
+	if !progress.Buffering {
+		a.health.stalledSince = time.Time{}
+		return nil
+	}
+	if a.health.stalledSince.IsZero() {
+		a.health.stalledSince = now
+		a.health.stalls = append(a.health.stalls, now)
+	}
+	recent := a.health.stalls[:0]
+	for _, at := range a.health.stalls {
+		if now.Sub(at) <= cfg.Window {
+			recent = append(recent, at)
+		}
+	}
+	a.health.stalls = recent
+
+	stalledTooOften := cfg.Stalls > 0 && len(a.health.stalls) >= cfg.Stalls
+	stalledTooLong := cfg.StallTimeout > 0 && now.Sub(a.health.stalledSince) >= cfg.StallTimeout
+	if (!stalledTooOften && !stalledTooLong) || a.health.switching || a.health.exhausted {
+		return nil
+	}
+
+	a.health.switching = true
+	a.debugLog("checkStreamHealth: %d stalls, stalled for %s, switching source", len(a.health.stalls), now.Sub(a.health.stalledSince))
+	return tea.Batch(
+		a.toast(severityWarning, i18n.T("The stream keeps buffering, looking for another source...")),
+		a.findAlternateStream(cfg.OtherProviders),
+	)
+}
+
+// findAlternateStream resolves the episode playing from a source not tried
+// yet: first the provider's other servers, then other providers
+func (a *App) findAlternateStream(otherProviders bool) tea.Cmd {
+	if _, ok := a.player.(streamSwitcher); !ok {
+		return nil
+	}
+
+	session := a.playerSession
+	providerName := a.currentPlaybackProvider
+	episodeID := a.currentEpisodeID
+	episode, season := a.currentEpisodeNumber, a.currentSeasonNumber
+	_, title, _ := a.playbackMedia(a.playingAniListID(), episode)
+	audioPref := a.currentAudioPreference
+	tried := make(map[string]bool, len(a.health.tried))
+	for url := range a.health.tried {
+		tried[url] = true
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+		defer cancel()
+
+		stream, source, err := alternateStream(ctx, providerName, episodeID, title, season, episode, audioPref, tried, otherProviders)
+		return alternateStreamMsg{session: session, stream: stream, source: source, err: err}
+	}
+}
+
+// alternateStream returns a stream of the episode whose URL isn't in
+// tried, and the server or provider it comes from
+func alternateStream(ctx context.Context, providerName, episodeID, title string, season, episode int, audioPref string, tried map[string]bool, otherProviders bool) (*providers.StreamURL, string, error) {
+	current, err := providers.Get(providerName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if sources, err := providers.ListStreamSources(ctx, current, episodeID); err == nil {
+		// Keep the sub/dub version playing when the sources say which is which
+		want := providers.AnimeAudioType(audioPref)
+		for _, matchAudio := range []bool{true, false} {
+			for _, source := range sources {
+				if source.Stream == nil || tried[source.Stream.URL] {
+					continue
+				}
+				if matchAudio && want != "" && source.Audio != "" && source.Audio != want {
+					continue
+				}
+				return source.Stream, source.Server, nil
+			}
+		}
+	}
+
+	if !otherProviders {
+		return nil, "", fmt.Errorf("no other server on %s", providerName)
+	}
+	for _, p := range providers.GetByType(current.Type()) {
+		if p.Name() == providerName {
+			continue
+		}
+		stream, err := providers.FindEpisodeStream(ctx, p, title, season, episode)
+		if err != nil || tried[stream.URL] {
+			continue
+		}
+		return stream, p.Name(), nil
+	}
+	return nil, "", fmt.Errorf("no other server or provider has this episode")
+}
+
+// handleAlternateStreamMsg swaps the stream found in at the current position
+func (a *App) handleAlternateStreamMsg(msg alternateStreamMsg) tea.Cmd {
+	if msg.session != a.playerSession || a.state != playingView {
+		return nil // Playback ended or moved on meanwhile
+	}
+	a.health.switching = false
+	if msg.err != nil {
+		a.health.exhausted = true
+		a.logger.Warn("no alternate source found", "error", msg.err)
+		return a.toast(severityWarning, i18n.T("No other source found: %v", msg.err))
+	}
+
+	var position time.Duration
+	if a.lastProgress != nil {
+		position = a.lastProgress.CurrentTime
+	}
+	options := player.PlayOptions{
+		StartTime: position,
+		Headers:   msg.stream.Headers,
+		Referer:   msg.stream.Referer,
+		YTDL:      msg.stream.Type == providers.StreamTypeYTDLP,
+	}
+	if subtitle := selectBestSubtitle(msg.stream.Subtitles); subtitle != nil {
+		options.SubtitleURL = subtitle.URL
+	}
+	if track := audio.SelectAudioTrack(msg.stream.AudioTracks, a.currentAudioPreference); track != nil {
+		options.AudioTrack = track.Index
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if a.health.tried == nil {
+		a.health.tried = make(map[string]bool)
+	}
+	a.health.tried[msg.stream.URL] = true
+	if err := a.player.(streamSwitcher).SwitchStream(ctx, msg.stream.URL, options); err != nil {
+		a.logger.Warn("failed to switch stream", "error", err)
+		return a.toast(severityError, i18n.T("Couldn't switch source: %v", err))
+	}
+
+	a.health.stalls = nil
+	a.health.stalledSince = time.Time{}
+	a.launchStartTime = time.Now() // The new stream buffers at first too
+	a.playingStream = msg.stream
+	return a.toast(severitySuccess, i18n.T("Switched to %s at %s", msg.source, utils.FormatPosition(position)))
+}
@@ -28,166 +28,202 @@ var (
 	OxocarbonLightBlue = lipgloss.Color("#82cfff") // base0F
 	OxocarbonMauve     = lipgloss.Color("#d1aaff")
 
-	// Status colors using oxocarbon palette
-	StatusWatching  = OxocarbonGreen  // Green for current
-	StatusCompleted = OxocarbonBlue   // Blue for completed
-	StatusOnHold    = OxocarbonPink   // Pink for paused
-	StatusDropped   = OxocarbonPink   // Pink/red for dropped
-	StatusPlanning  = OxocarbonPurple // Purple for planning
+	// Status colors using the palette, set by buildStyles
+	StatusWatching, StatusCompleted, StatusOnHold, StatusDropped, StatusPlanning lipgloss.Color
 )
 
+// Styles built from the palette by buildStyles
 var (
+	AppStyle                 lipgloss.Style
+	TitleStyle               lipgloss.Style
+	SubtitleStyle            lipgloss.Style
+	HelpStyle                lipgloss.Style
+	NormalItemStyle          lipgloss.Style
+	SelectedItemStyle        lipgloss.Style
+	ActiveItemStyle          lipgloss.Style
+	AniListItemStyle         lipgloss.Style
+	AniListItemSelectedStyle lipgloss.Style
+	AniListTitleStyle        lipgloss.Style
+	AniListMetadataStyle     lipgloss.Style
+	AniListURLStyle          lipgloss.Style
+	AniListProgressStyle     lipgloss.Style
+	AniListScoreStyle        lipgloss.Style
+	AniListHeaderStyle       lipgloss.Style
+	AniListHelpStyle         lipgloss.Style
+	StatusBadgeStyle         lipgloss.Style
+	CategoryHeaderStyle      lipgloss.Style
+	GenreBadgeStyle          lipgloss.Style
+	GenreBadgeSelectedStyle  lipgloss.Style
+	SynopsisStyle            lipgloss.Style
+	HomeSeparatorStyle       lipgloss.Style
+	FooterStyle              lipgloss.Style
+	PopupStyle               lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+}
+
+// buildStyles derives the styles from the palette
+func buildStyles() {
+	// Status colors using oxocarbon palette
+	StatusWatching = OxocarbonGreen  // Green for current
+	StatusCompleted = OxocarbonBlue  // Blue for completed
+	StatusOnHold = OxocarbonPink     // Pink for paused
+	StatusDropped = OxocarbonPink    // Pink/red for dropped
+	StatusPlanning = OxocarbonPurple // Purple for planning
+
 	// App general style with a subtle border
 	AppStyle = lipgloss.NewStyle().
-			Padding(1, 2).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(OxocarbonBase01)
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(OxocarbonBase01)
 
 	// Title style
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonWhite).
-			Background(OxocarbonPurple).
-			Padding(0, 1).
-			Bold(true)
+		Foreground(OxocarbonWhite).
+		Background(OxocarbonPurple).
+		Padding(0, 1).
+		Bold(true)
 
 	// Subtitle style
 	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonMauve).
-			Bold(true)
+		Foreground(OxocarbonMauve).
+		Bold(true)
 
 	// Help style
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonBase03).
-			Italic(true)
+		Foreground(OxocarbonBase03).
+		Italic(true)
 
 	// List styles
 	NormalItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2).
-			Foreground(OxocarbonBase05)
+		PaddingLeft(2).
+		Foreground(OxocarbonBase05)
 
 	SelectedItemStyle = lipgloss.NewStyle().
-				PaddingLeft(2).
-				Foreground(OxocarbonPurple).
-				Bold(true)
+		PaddingLeft(2).
+		Foreground(OxocarbonPurple).
+		Bold(true)
 
 	ActiveItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2).
-			Foreground(OxocarbonGreen).
-			Bold(true)
+		PaddingLeft(2).
+		Foreground(OxocarbonGreen).
+		Bold(true)
 
 	// List item with oxocarbon border (mangal style)
 	AniListItemStyle = lipgloss.NewStyle().
-				BorderStyle(lipgloss.NormalBorder()).
-				BorderForeground(OxocarbonBase02).
-				BorderLeft(true).
-				BorderTop(false).
-				BorderRight(false).
-				BorderBottom(false).
-				PaddingLeft(2).
-				PaddingRight(2).
-				MarginLeft(3)
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(OxocarbonBase02).
+		BorderLeft(true).
+		BorderTop(false).
+		BorderRight(false).
+		BorderBottom(false).
+		PaddingLeft(2).
+		PaddingRight(2).
+		MarginLeft(3)
 
 	// Selected item with highlighted border
 	AniListItemSelectedStyle = lipgloss.NewStyle().
-					BorderStyle(lipgloss.ThickBorder()).
-					BorderForeground(OxocarbonPurple).
-					BorderLeft(true).
-					BorderTop(false).
-					BorderRight(false).
-					BorderBottom(false).
-					PaddingLeft(2).
-					PaddingRight(2).
-					MarginLeft(3)
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderForeground(OxocarbonPurple).
+		BorderLeft(true).
+		BorderTop(false).
+		BorderRight(false).
+		BorderBottom(false).
+		PaddingLeft(2).
+		PaddingRight(2).
+		MarginLeft(3)
 
 	// Title style with primary foreground
 	AniListTitleStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonBase05).
-				Bold(true)
+		Foreground(OxocarbonBase05).
+		Bold(true)
 
 	// Subtitle/metadata style - slightly muted but still readable
 	AniListMetadataStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonBase04)
+		Foreground(OxocarbonBase04)
 
 	// URL/link style
 	AniListURLStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonCyan).
-			Italic(true)
+		Foreground(OxocarbonCyan).
+		Italic(true)
 
 	// Progress bar style
 	AniListProgressStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonPurple)
+		Foreground(OxocarbonPurple)
 
 	// Score style
 	AniListScoreStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonPink).
-				Bold(true)
+		Foreground(OxocarbonPink).
+		Bold(true)
 
 	// Header style
 	AniListHeaderStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonPurple).
-				Bold(true).
-				Underline(true).
-				MarginBottom(1).
-				MarginTop(1)
+		Foreground(OxocarbonPurple).
+		Bold(true).
+		Underline(true).
+		MarginBottom(1).
+		MarginTop(1)
 
 	// Help text style - muted
 	AniListHelpStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonBase03).
-				MarginTop(1)
+		Foreground(OxocarbonBase03).
+		MarginTop(1)
 
 	// Status badge styles
 	StatusBadgeStyle = lipgloss.NewStyle().
-				Padding(0, 1).
-				Bold(true)
+		Padding(0, 1).
+		Bold(true)
 
 	// Category header (like "Mangas" in mangal)
 	CategoryHeaderStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonBase05).
-				Background(OxocarbonBase01).
-				Padding(0, 1).
-				Bold(true).
-				MarginTop(1).
-				MarginBottom(1)
+		Foreground(OxocarbonBase05).
+		Background(OxocarbonBase01).
+		Padding(0, 1).
+		Bold(true).
+		MarginTop(1).
+		MarginBottom(1)
 
 	// Genre badge - pill-shaped tags
 	GenreBadgeStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonBase05).
-			Background(OxocarbonBase01).
-			Padding(0, 1).
-			MarginRight(1)
+		Foreground(OxocarbonBase05).
+		Background(OxocarbonBase01).
+		Padding(0, 1).
+		MarginRight(1)
 
 	// Selected genre badge (purple accent for selected items)
 	GenreBadgeSelectedStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonPurple).
-				Background(OxocarbonBase01).
-				Padding(0, 1).
-				MarginRight(1)
+		Foreground(OxocarbonPurple).
+		Background(OxocarbonBase01).
+		Padding(0, 1).
+		MarginRight(1)
 
 	// Synopsis style - italic, muted for readability
 	SynopsisStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonBase04).
-			Italic(true)
+		Foreground(OxocarbonBase04).
+		Italic(true)
 
 	// Home separator for visual grouping
 	HomeSeparatorStyle = lipgloss.NewStyle().
-				Foreground(OxocarbonBase02).
-				MarginTop(1).
-				MarginBottom(1)
+		Foreground(OxocarbonBase02).
+		MarginTop(1).
+		MarginBottom(1)
 
 	// Footer style for status messages
 	FooterStyle = lipgloss.NewStyle().
-			Foreground(OxocarbonBase05).
-			Background(OxocarbonBase01).
-			Padding(0, 1)
+		Foreground(OxocarbonBase05).
+		Background(OxocarbonBase01).
+		Padding(0, 1)
 
 	// Popup style
 	PopupStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(OxocarbonPurple).
-			Padding(1, 2).
-			Background(OxocarbonBase00).
-			Foreground(OxocarbonBase05)
-)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(OxocarbonPurple).
+		Padding(1, 2).
+		Background(OxocarbonBase00).
+		Foreground(OxocarbonBase05)
+}
 
 // GetStatusColor returns the color for a given watch status
 func GetStatusColor(status string) lipgloss.Color {
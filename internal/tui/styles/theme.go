@@ -0,0 +1,88 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// palette maps the color names a theme can set to the palette
+var palette = map[string]*lipgloss.Color{
+	"black":      &OxocarbonBlack,
+	"base00":     &OxocarbonBase00,
+	"base01":     &OxocarbonBase01,
+	"base02":     &OxocarbonBase02,
+	"base03":     &OxocarbonBase03,
+	"base04":     &OxocarbonBase04,
+	"base05":     &OxocarbonBase05,
+	"base06":     &OxocarbonBase06,
+	"white":      &OxocarbonWhite,
+	"teal":       &OxocarbonTeal,
+	"blue":       &OxocarbonBlue,
+	"pink":       &OxocarbonPink,
+	"red":        &OxocarbonRed,
+	"cyan":       &OxocarbonCyan,
+	"magenta":    &OxocarbonMagenta,
+	"green":      &OxocarbonGreen,
+	"purple":     &OxocarbonPurple,
+	"light_blue": &OxocarbonLightBlue,
+	"mauve":      &OxocarbonMauve,
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[0-9]{1,3})$`)
+
+// Theme overrides palette colors, e.g. purple: "#bd93f9"
+type Theme struct {
+	Name   string            `yaml:"name"`
+	Colors map[string]string `yaml:"colors"`
+}
+
+// ParseTheme reads and validates a theme
+func ParseTheme(data []byte) (*Theme, error) {
+	var theme Theme
+	if err := yaml.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("failed to parse theme: %w", err)
+	}
+	for name, color := range theme.Colors {
+		if _, ok := palette[name]; !ok {
+			return nil, fmt.Errorf("unknown theme color %q (known: %s)", name, strings.Join(PaletteNames(), ", "))
+		}
+		if !colorPattern.MatchString(color) {
+			return nil, fmt.Errorf("invalid color %q for %s", color, name)
+		}
+	}
+	return &theme, nil
+}
+
+// LoadTheme reads a theme file
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme: %w", err)
+	}
+	return ParseTheme(data)
+}
+
+// ApplyTheme sets the theme's colors and rebuilds the styles. It must run
+// before the TUI is created.
+func ApplyTheme(theme *Theme) {
+	for name, color := range theme.Colors {
+		*palette[name] = lipgloss.Color(color)
+	}
+	buildStyles()
+}
+
+// PaletteNames returns the color names a theme can set
+func PaletteNames() []string {
+	names := make([]string, 0, len(palette))
+	for name := range palette {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
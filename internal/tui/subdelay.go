@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/player"
+)
+
+// rememberedSubtitleDelay returns the subtitle delay saved for the series
+// about to play on the current provider, to pass to mpv at launch
+func (a *App) rememberedSubtitleDelay() time.Duration {
+	a.subtitleDelay = 0
+	if a.db == nil || a.currentPlaybackProvider == "" {
+		return 0
+	}
+
+	mediaID, _, _ := a.playbackMedia(a.playingAniListID(), a.currentEpisodeNumber)
+	if mediaID == "" {
+		return 0
+	}
+	delay, err := database.GetSubtitleDelay(a.db, a.currentPlaybackProvider, mediaID)
+	if err != nil {
+		a.logger.Warn("failed to load subtitle delay", "error", err)
+		return 0
+	}
+	a.subtitleDelay = delay
+	return delay
+}
+
+// saveSubtitleDelay remembers the subtitle delay set in mpv during playback
+// for the series on the current provider, when it was changed
+func (a *App) saveSubtitleDelay(progress *player.PlaybackProgress) {
+	if a.db == nil || a.incognito || a.currentPlaybackProvider == "" {
+		return
+	}
+	if progress.SubtitleDelay == a.subtitleDelay {
+		return
+	}
+
+	mediaID, _, _ := a.playbackMedia(a.playingAniListID(), a.currentEpisodeNumber)
+	if mediaID == "" {
+		return
+	}
+	if err := database.SaveSubtitleDelay(a.db, a.currentPlaybackProvider, mediaID, progress.SubtitleDelay); err != nil {
+		a.logger.Warn("failed to save subtitle delay", "error", err)
+		return
+	}
+	a.debugLog("saveSubtitleDelay: %s for %s on %s", progress.SubtitleDelay, mediaID, a.currentPlaybackProvider)
+	a.subtitleDelay = progress.SubtitleDelay
+}
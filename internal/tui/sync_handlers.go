@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/i18n"
+)
+
+func init() {
+	handle((*App).handleSyncQueueMsg)
+	handle(func(a *App, _ syncRetryTickMsg) (tea.Model, tea.Cmd) { return a.handleSyncRetryTickMsg() })
+}
+
+// syncQueueMsg reports the state of the tracker sync queue
+type syncQueueMsg struct {
+	synced  int
+	pending int64
+	viaChan bool // Sent through msgChan, so the listener must be re-armed
+}
+
+// syncRetryTickMsg triggers a periodic retry of the sync queue
+type syncRetryTickMsg struct{}
+
+// retrySyncQueue retries queued tracker updates whose backoff has passed
+func (a *App) retrySyncQueue() tea.Cmd {
+	mgr := a.trackerMgr
+	if mgr == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		synced := 0
+		if mgr.IsAniListEnabled() && mgr.IsAniListAuthenticated() {
+			var err error
+			synced, _, err = mgr.ProcessSyncQueue(ctx, false)
+			if err != nil {
+				a.logger.Warn("failed to process sync queue", "error", err)
+			}
+		}
+
+		pending, err := mgr.PendingSyncs()
+		if err != nil {
+			a.logger.Warn("failed to count pending syncs", "error", err)
+		}
+		return syncQueueMsg{synced: synced, pending: pending}
+	}
+}
+
+// notifySyncQueued refreshes the pending sync badge from a background goroutine
+func (a *App) notifySyncQueued(mgr ProgressTracker) {
+	pending, err := mgr.PendingSyncs()
+	if err != nil {
+		a.logger.Warn("failed to count pending syncs", "error", err)
+		return
+	}
+	a.msgChan <- syncQueueMsg{pending: pending, viaChan: true}
+}
+
+// handleSyncQueueMsg updates the pending sync badge and schedules the next retry
+func (a *App) handleSyncQueueMsg(msg syncQueueMsg) (tea.Model, tea.Cmd) {
+	a.pendingSyncs = msg.pending
+	a.home.SetPendingSyncs(msg.pending)
+
+	var cmds []tea.Cmd
+	if msg.viaChan {
+		cmds = append(cmds, a.listenForMessages())
+	}
+	if msg.synced > 0 {
+		cmds = append(cmds, a.toast(severitySuccess, i18n.T("Synced %d queued AniList updates", msg.synced)))
+	}
+
+	if msg.pending > 0 && !a.syncRetryScheduled {
+		a.syncRetryScheduled = true
+		interval := 5 * time.Minute
+		if cfg := a.config(); cfg != nil && cfg.Tracker.AniList.SyncInterval > 0 {
+			interval = cfg.Tracker.AniList.SyncInterval
+		}
+		cmds = append(cmds, tea.Tick(interval, func(time.Time) tea.Msg {
+			return syncRetryTickMsg{}
+		}))
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// handleSyncRetryTickMsg retries the sync queue on the sync interval
+func (a *App) handleSyncRetryTickMsg() (tea.Model, tea.Cmd) {
+	a.syncRetryScheduled = false
+	return a, a.retrySyncQueue()
+}
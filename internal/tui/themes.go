@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/metadata/animethemes"
+)
+
+func init() {
+	handleCmd((*App).handleThemesSavedMsg)
+}
+
+// themesSavedMsg reports the songs saved to the playlist with 'm'
+type themesSavedMsg struct {
+	saved []animethemes.Theme // Newly added
+	known int                 // Already on the playlist
+	err   error
+}
+
+// saveEpisodeThemes looks up the openings and endings of the episode playing
+// and adds them to the playlist
+func (a *App) saveEpisodeThemes() tea.Cmd {
+	if a.db == nil {
+		return nil
+	}
+	if a.incognito {
+		return a.toast(severityInfo, i18n.T("Incognito: the playlist isn't saved"))
+	}
+
+	anilistID := a.playingAniListID()
+	_, title, mediaType := a.playbackMedia(anilistID, a.currentEpisodeNumber)
+	if mediaType != "anime" {
+		return a.toast(severityWarning, i18n.T("Openings and endings are only known for anime"))
+	}
+	episode := a.currentEpisodeNumber
+	db := a.db
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		client := animethemes.NewClient()
+		var themes []animethemes.Theme
+		var err error
+		if anilistID != nil {
+			themes, err = client.ByAniListID(ctx, *anilistID)
+		} else {
+			themes, err = client.Search(ctx, title)
+		}
+		if err != nil {
+			return themesSavedMsg{err: fmt.Errorf("failed to fetch themes: %w", err)}
+		}
+
+		var msg themesSavedMsg
+		for _, theme := range animethemes.ForEpisode(themes, episode) {
+			added, err := database.AddPlaylistTrack(db, database.PlaylistTrack{
+				MediaTitle: title,
+				AniListID:  anilistID,
+				Episode:    episode,
+				Theme:      theme.Slug,
+				Song:       theme.Song,
+				Artist:     theme.Artist(),
+			})
+			if err != nil {
+				return themesSavedMsg{err: err}
+			}
+			if added {
+				msg.saved = append(msg.saved, theme)
+			} else {
+				msg.known++
+			}
+		}
+		return msg
+	}
+}
+
+// handleThemesSavedMsg reports what 'm' added to the playlist
+func (a *App) handleThemesSavedMsg(msg themesSavedMsg) tea.Cmd {
+	switch {
+	case msg.err != nil:
+		a.logger.Warn("failed to save themes to playlist", "error", msg.err)
+		return a.toast(severityError, i18n.T("Couldn't save the songs: %v", msg.err))
+	case len(msg.saved) > 0:
+		var songs []string
+		for _, theme := range msg.saved {
+			song := fmt.Sprintf("%s %q", theme.Slug, theme.Song)
+			if artist := theme.Artist(); artist != "" {
+				song += " - " + artist
+			}
+			songs = append(songs, song)
+		}
+		return a.toast(severitySuccess, i18n.T("Saved to playlist: %s", strings.Join(songs, ", ")))
+	case msg.known > 0:
+		return a.toast(severityInfo, i18n.T("This episode's songs are already on the playlist"))
+	default:
+		return a.toast(severityWarning, i18n.T("No opening or ending found for this episode"))
+	}
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tui/common"
+)
+
+func init() {
+	handle((*App).handleTrashedMsg)
+	handle((*App).handleUndoExpiredMsg)
+	handle((*App).handleTrashRestoredMsg)
+}
+
+// undoWindow is how long the status bar offers to undo a delete
+const undoWindow = 8 * time.Second
+
+// undoExpiredMsg ends the undo window of a trash item
+type undoExpiredMsg struct {
+	id uint
+}
+
+// trashRestoredMsg reports the result of an undo
+type trashRestoredMsg struct {
+	label string
+	err   error
+}
+
+// inputModeActive reports whether the current view is taking text input
+func (a *App) inputModeActive() bool {
+	switch a.state {
+	case homeView:
+		return a.home.IsInputActive()
+	case resultsView:
+		return a.results.IsInputActive()
+	case episodeView:
+		return a.episodesComponent.IsInputActive()
+	case seasonView:
+		return a.seasons.IsInputActive()
+	case historyView:
+		return a.historyComponent.IsInputActive()
+	case downloadsView:
+		return a.downloadsComponent.IsInputActive()
+	}
+	return false
+}
+
+// handleTrashedMsg offers to undo a delete from the status bar
+func (a *App) handleTrashedMsg(msg common.TrashedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		a.statusMsg = i18n.T("✗ Delete failed: %v", msg.Err)
+		a.statusMsgTime = time.Now()
+		return a, func() tea.Msg {
+			time.Sleep(3 * time.Second)
+			return clearStatusMsg{}
+		}
+	}
+	if msg.ID == 0 {
+		return a, nil
+	}
+
+	a.trashed(msg.ID, msg.Label)
+	return a, func() tea.Msg {
+		time.Sleep(undoWindow)
+		return undoExpiredMsg{id: msg.ID}
+	}
+}
+
+// trashed records the last delete so it can be undone
+func (a *App) trashed(id uint, label string) {
+	a.undoTrashID = id
+	a.undoTrashLabel = label
+	a.statusMsg = i18n.T("🗑 Deleted %s • u to undo", label)
+	a.statusMsgTime = time.Now()
+}
+
+// handleUndoExpiredMsg closes the undo window unless a newer delete replaced it
+func (a *App) handleUndoExpiredMsg(msg undoExpiredMsg) (tea.Model, tea.Cmd) {
+	if a.undoTrashID != msg.id {
+		return a, nil
+	}
+	if a.statusMsg == fmt.Sprintf("🗑 Deleted %s • u to undo", a.undoTrashLabel) {
+		a.statusMsg = ""
+	}
+	a.undoTrashID = 0
+	a.undoTrashLabel = ""
+	return a, nil
+}
+
+// isUndoKey reports whether a key undoes the last delete. 'u' is left to
+// views that bind it themselves and to text input; ctrl+z always works.
+func (a *App) isUndoKey(msg tea.KeyMsg) bool {
+	if a.undoTrashID == 0 {
+		return false
+	}
+	switch msg.String() {
+	case "ctrl+z":
+		return true
+	case "u":
+		return a.state != anilistView && !a.inputModeActive()
+	}
+	return false
+}
+
+// undoTrash restores the last deleted items
+func (a *App) undoTrash() tea.Cmd {
+	id, label := a.undoTrashID, a.undoTrashLabel
+	a.undoTrashID = 0
+	a.undoTrashLabel = ""
+	return func() tea.Msg {
+		_, err := database.RestoreTrash(a.db, id)
+		return trashRestoredMsg{label: label, err: err}
+	}
+}
+
+// handleTrashRestoredMsg reports an undo and refreshes the affected view
+func (a *App) handleTrashRestoredMsg(msg trashRestoredMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		a.statusMsg = i18n.T("✗ Undo failed: %v", msg.err)
+	} else {
+		a.statusMsg = i18n.T("✓ Restored %s", msg.label)
+	}
+	a.statusMsgTime = time.Now()
+
+	cmds := []tea.Cmd{func() tea.Msg {
+		time.Sleep(3 * time.Second)
+		return clearStatusMsg{}
+	}}
+	switch a.state {
+	case historyView:
+		cmds = append(cmds, a.historyComponent.Refresh())
+	case downloadsView:
+		cmds = append(cmds, a.downloadsComponent.Refresh())
+	}
+	return a, tea.Batch(cmds...)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/require"
 )
 
@@ -34,3 +35,19 @@ func AssertSnapshot(t *testing.T, output string) {
 
 	require.Equal(t, string(snapshot), output, "snapshot does not match. run with -update to update it.")
 }
+
+// AssertFits checks that no line of a view is wider than width cells and,
+// when height is above zero, that it has at most height lines
+func AssertFits(t *testing.T, view string, width, height int) {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimRight(view, "\n"), "\n")
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > width {
+			t.Errorf("line %d is %d cells wide, more than %d: %q", i+1, w, width, ansi.Strip(line))
+		}
+	}
+	if height > 0 && len(lines) > height {
+		t.Errorf("view is %d lines tall, more than %d", len(lines), height)
+	}
+}
@@ -0,0 +1,39 @@
+package utils
+
+// Breakpoints of the responsive layouts. Below CompactWidth columns views
+// switch to compact rows, collapsed metadata and abbreviated headers, and
+// below CompactHeight rows they drop the spacing between rows. Compact
+// layouts fit terminals down to 50x15.
+const (
+	CompactWidth  = 70
+	CompactHeight = 24
+)
+
+// Compact reports whether a view width cells wide uses its compact layout.
+// A width of zero (no WindowSizeMsg received yet) is not compact.
+func Compact(width int) bool {
+	return width > 0 && width < CompactWidth
+}
+
+// Short reports whether a view height rows tall uses compact rows
+func Short(height int) bool {
+	return height > 0 && height < CompactHeight
+}
+
+// FitHelp returns the first help line that fits in width cells, from the
+// most to the least detailed, or the last one truncated. A width of zero
+// returns the first line.
+func FitHelp(width int, lines ...string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	if width <= 0 {
+		return lines[0]
+	}
+	for _, line := range lines {
+		if Width(line) <= width {
+			return line
+		}
+	}
+	return Truncate(lines[len(lines)-1], width)
+}
@@ -3,7 +3,7 @@ package utils
 import (
 	"strings"
 
-	"github.com/mattn/go-runewidth"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // TruncateToLines truncates text to a maximum number of lines with proper word wrapping.
@@ -19,28 +19,29 @@ func TruncateToLines(text string, maxLines int, maxWidth int) string {
 
 	// Truncate the last line
 	lastLine := lines[maxLines-1]
-	if runewidth.StringWidth(lastLine) > maxWidth-3 {
+	if Width(lastLine) > maxWidth-Width(Ellipsis) {
 		lastLine = TruncateWithWidth(lastLine, maxWidth)
 	} else {
-		lastLine += "..."
+		lastLine += Ellipsis
 	}
 
 	return result + "\n" + lastLine
 }
 
 // WrapText wraps text at word boundaries to fit within maxWidth.
-// Returns a slice of lines.
+// Words wider than maxWidth (e.g. CJK text without spaces) are broken
+// between grapheme clusters. Returns a slice of lines.
 func WrapText(text string, maxWidth int) []string {
 	// Clean text first
 	text = strings.TrimSpace(text)
-	words := strings.Fields(text)
+	words := splitLongWords(strings.Fields(text), maxWidth)
 
 	var lines []string
 	var currentLine strings.Builder
 	currentWidth := 0
 
 	for _, word := range words {
-		wordWidth := runewidth.StringWidth(word)
+		wordWidth := Width(word)
 		spaceWidth := 1
 
 		if currentWidth == 0 {
@@ -68,19 +69,25 @@ func WrapText(text string, maxWidth int) []string {
 	return lines
 }
 
-// TruncateWithWidth truncates text to fit within maxWidth, accounting for Unicode character widths.
-// Adds "..." if the text is truncated.
-func TruncateWithWidth(text string, maxWidth int) string {
-	if runewidth.StringWidth(text) <= maxWidth {
-		return text
+// splitLongWords hard-wraps words wider than maxWidth into maxWidth-sized
+// pieces so WrapText never emits an overlong line.
+func splitLongWords(words []string, maxWidth int) []string {
+	if maxWidth <= 0 {
+		return words
 	}
-
-	width := 0
-	for i, r := range text {
-		width += runewidth.RuneWidth(r)
-		if width > maxWidth-3 {
-			return text[:i] + "..."
+	out := make([]string, 0, len(words))
+	for _, word := range words {
+		if Width(word) <= maxWidth {
+			out = append(out, word)
+			continue
 		}
+		out = append(out, strings.Split(ansi.Hardwrap(word, maxWidth, false), "\n")...)
 	}
-	return text
+	return out
+}
+
+// TruncateWithWidth truncates text to fit within maxWidth, accounting for Unicode character widths.
+// Adds "..." if the text is truncated.
+func TruncateWithWidth(text string, maxWidth int) string {
+	return Truncate(text, maxWidth)
 }
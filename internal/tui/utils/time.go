@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatCountdown formats the time left until something happens with its
+// two largest units, e.g. "2d 4h", "4h 12m" or "12m"
+func FormatCountdown(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// FormatPosition formats a playback position as m:ss, or h:mm:ss past an
+// hour
+func FormatPosition(d time.Duration) string {
+	s := int(d.Round(time.Second) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
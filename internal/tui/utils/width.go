@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+// Ellipsis is appended to text cut by Truncate.
+const Ellipsis = "..."
+
+// Width returns the number of terminal cells text occupies. It measures
+// grapheme clusters, so double-width runes (CJK, emoji) count as two cells,
+// combining marks and ZWJ sequences count once, and ANSI escapes are ignored.
+// This is the same measure lipgloss uses when laying out boxes.
+func Width(text string) int {
+	return ansi.StringWidth(text)
+}
+
+// Truncate cuts text to at most maxWidth cells, ending it with Ellipsis when
+// anything was removed. Grapheme clusters and ANSI escapes are never split.
+// A maxWidth of zero or less means the width is not known yet (no
+// WindowSizeMsg received) and returns text unchanged.
+func Truncate(text string, maxWidth int) string {
+	if maxWidth <= 0 || Width(text) <= maxWidth {
+		return text
+	}
+	if maxWidth <= Width(Ellipsis) {
+		return ansi.Truncate(text, maxWidth, "")
+	}
+	return ansi.Truncate(text, maxWidth, Ellipsis)
+}
+
+// PadRight pads text with spaces to exactly width cells, truncating it first
+// if it is too wide.
+func PadRight(text string, width int) string {
+	text = Truncate(text, width)
+	if gap := width - Width(text); gap > 0 {
+		return text + strings.Repeat(" ", gap)
+	}
+	return text
+}
+
+// Center pads text on both sides to width cells, truncating it first if it
+// is too wide. Odd gaps put the extra space on the right.
+func Center(text string, width int) string {
+	text = Truncate(text, width)
+	gap := width - Width(text)
+	if gap <= 0 {
+		return text
+	}
+	left := gap / 2
+	return strings.Repeat(" ", left) + text + strings.Repeat(" ", gap-left)
+}
+
+// OverlayTopRight draws overlay over the top-right corner of base, a view
+// width cells wide, leaving the rest of base visible.
+func OverlayTopRight(base, overlay string, width int) string {
+	lines := strings.Split(base, "\n")
+	for i, line := range strings.Split(overlay, "\n") {
+		left := width - Width(line)
+		if left < 0 {
+			left = 0
+		}
+		if i >= len(lines) {
+			lines = append(lines, "")
+		}
+		// Reset styles cut open by the truncation before drawing the overlay
+		lines[i] = PadRight(ansi.Truncate(lines[i], left, "")+"\x1b[m", left) + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ItemWidth returns the width available for text inside a list item box
+// (styles.AniListItemStyle) when the list is totalWidth cells wide. It
+// returns 0 while totalWidth is unknown.
+func ItemWidth(totalWidth int) int {
+	if totalWidth <= 0 {
+		return 0
+	}
+	width := totalWidth - styles.AniListItemStyle.GetHorizontalFrameSize()
+	if width < 10 {
+		return 10
+	}
+	return width
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidth(t *testing.T) {
+	assert.Equal(t, 5, Width("hello"))
+	assert.Equal(t, 6, Width("進撃の"))
+	assert.Equal(t, 2, Width("👨‍👩‍👧"), "ZWJ sequence is one grapheme")
+	assert.Equal(t, 1, Width("é"), "combining mark adds no width")
+	assert.Equal(t, 5, Width("\x1b[1mhello\x1b[0m"), "ANSI escapes are ignored")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10))
+	assert.Equal(t, "unchanged", Truncate("unchanged", 0))
+
+	got := Truncate("進撃の巨人 The Final Season", 10)
+	assert.LessOrEqual(t, Width(got), 10)
+	assert.Equal(t, "進撃の...", got)
+
+	// Never splits a double-width rune to fill an odd cell
+	got = Truncate("進撃の巨人", 8)
+	assert.Equal(t, "進撃...", got)
+
+	got = Truncate("👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧", 5)
+	assert.Equal(t, "👨‍👩‍👧...", got)
+}
+
+func TestPadAndCenter(t *testing.T) {
+	assert.Equal(t, "進撃  ", PadRight("進撃", 6))
+	assert.Equal(t, 6, Width(PadRight("進撃の巨人", 6)))
+	assert.Equal(t, " 進撃  ", Center("進撃", 7))
+}
+
+func TestWrapTextBreaksUnspacedCJK(t *testing.T) {
+	lines := WrapText("進撃の巨人進撃の巨人", 6)
+	for _, line := range lines {
+		assert.LessOrEqual(t, Width(line), 6)
+	}
+	assert.Len(t, lines, 4)
+}
+
+func TestOverlayTopRight(t *testing.T) {
+	got := OverlayTopRight("hello world\nsecond line\nthird", "[ab]\n[cd]", 12)
+	assert.Equal(t, "hello wo\x1b[m[ab]\nsecond l\x1b[m[cd]\nthird", got)
+
+	// Overlays taller than the base add lines
+	got = OverlayTopRight("進撃の巨人", "[x]\n[y]", 8)
+	assert.Equal(t, "進撃\x1b[m [x]\n\x1b[m     [y]", got)
+}
+
+func TestFormatCountdown(t *testing.T) {
+	assert.Equal(t, "2d 4h", FormatCountdown(52*time.Hour+30*time.Minute))
+	assert.Equal(t, "4h 12m", FormatCountdown(4*time.Hour+12*time.Minute))
+	assert.Equal(t, "12m", FormatCountdown(12*time.Minute+40*time.Second))
+	assert.Equal(t, "<1m", FormatCountdown(20*time.Second))
+}
+
+func TestFitHelp(t *testing.T) {
+	full := "↑/↓ navigate • enter play • / filter • esc back"
+	short := "↑/↓ • enter • esc"
+	assert.Equal(t, full, FitHelp(0, full, short))
+	assert.Equal(t, full, FitHelp(80, full, short))
+	assert.Equal(t, short, FitHelp(20, full, short))
+	assert.Equal(t, "↑/↓ • e...", FitHelp(10, full, short))
+
+	assert.True(t, Compact(50))
+	assert.False(t, Compact(CompactWidth))
+	assert.False(t, Compact(0), "unknown width")
+	assert.True(t, Short(15))
+	assert.False(t, Short(0), "unknown height")
+}
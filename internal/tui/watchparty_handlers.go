@@ -15,12 +15,25 @@ import (
 
 	"github.com/justchokingaround/greg/internal/config"
 	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
 	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/resolve"
 	"github.com/justchokingaround/greg/internal/tui/common"
 	"github.com/justchokingaround/greg/internal/tui/styles"
 	"github.com/justchokingaround/greg/internal/watchparty"
 )
 
+func init() {
+	handle((*App).handleWatchPartyMsg)
+	handle((*App).handleSetWatchPartyProxyMsg)
+	handle((*App).handleOpenWatchPartyMsg)
+	handle((*App).handleGenerateWatchPartyMsg)
+	handle((*App).handleShareViaWatchPartyMsg)
+	handle((*App).handleShareHistoryViaWatchPartyMsg)
+	handle((*App).handleShareRecentViaWatchPartyMsg)
+	handle((*App).handleShareMediaViaWatchPartyMsg)
+}
+
 // generateWatchPartyURL creates a WatchParty URL for the current episode
 func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episodeTitle string) tea.Cmd {
 	return func() tea.Msg {
@@ -41,7 +54,7 @@ func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episode
 		}
 
 		// Get stream URL for the episode
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
 		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
@@ -57,7 +70,7 @@ func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episode
 
 		// If no WatchParty-specific proxy is configured, try other potential sources
 		if finalProxyURL == "" && a.cfg != nil {
-			if cfg, ok := a.cfg.(*config.Config); ok {
+			if cfg := a.config(); cfg != nil {
 				// Try WatchParty default proxy first (double check we didn't miss it)
 				if cfg.WatchParty.DefaultProxy != "" && finalProxyURL == "" {
 					finalProxyURL = cfg.WatchParty.DefaultProxy
@@ -96,7 +109,7 @@ func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episode
 		if finalProxyURL != "" {
 			videoURL, err = watchparty.GenerateProxiedURL(stream.URL, watchparty.ProxyConfig{
 				ProxyURL: finalProxyURL,
-				Origin:   "", // Will be derived from referer in GenerateProxiedURL
+				Origin:   a.tempOrigin, // Derived from the referer when empty
 				Referer:  stream.Referer,
 			})
 			if err != nil {
@@ -111,7 +124,13 @@ func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episode
 		}
 
 		// Generate WatchParty URL
-		watchPartyFinalURL := watchparty.GenerateWatchPartyURL(videoURL)
+		subtitleProxy := watchparty.ProxyConfig{
+			ProxyURL: finalProxyURL,
+			Referer:  stream.Referer,
+			Hosted:   watchparty.IsOwnProxy(finalProxyURL, config.GetStateDir()),
+		}
+		watchPartyFinalURL := watchparty.GenerateWatchPartyURL(videoURL,
+			watchparty.RoomSubtitleURLs(stream.Subtitles, a.subtitleLang(), subtitleProxy)...)
 
 		// Log debug information for troubleshooting proxy configuration
 		a.logger.Debug("generating WatchParty URL",
@@ -121,18 +140,24 @@ func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episode
 			"proxy_configured", finalProxyURL != "",
 			"proxy_url", finalProxyURL)
 
-		// Prepare WatchParty information for the popup
+		// Prepare WatchParty information for the popup; subtitles go through the proxy, if any
+		hostedSubtitles := watchparty.HostedSubtitles(stream.Subtitles, subtitleProxy)
 		wpInfo := &common.WatchPartyInfo{
 			URL:           stream.URL,         // Original stream URL
 			ProxiedURL:    videoURL,           // Proxied stream URL (same as original if no proxy)
 			WatchPartyURL: watchPartyFinalURL, // Complete WatchParty URL with video parameter
-			Subtitles:     stream.Subtitles,
+			Subtitles:     hostedSubtitles,
 			Referer:       stream.Referer,
 			Headers:       stream.Headers,
 			Title:         a.selectedMedia.Title,
 			EpisodeTitle:  episodeTitle,
 			EpisodeNumber: episodeNumber,
 			ProviderName:  provider.Name(),
+			MediaID:       a.selectedMedia.ID,
+			EpisodeID:     episodeID,
+			ProxyURL:      finalProxyURL,
+			Origin:        a.tempOrigin,
+			Quality:       sharedQuality(stream, providers.Quality1080p),
 		}
 
 		// Get next episode info for "keep watching" functionality
@@ -170,7 +195,7 @@ func (a *App) generateWatchPartyURL(episodeID string, episodeNumber int, episode
 func (a *App) generateWatchPartyURLWithProvider(provider providers.Provider, episodeID string, episodeNumber int, episodeTitle string) tea.Cmd {
 	return func() tea.Msg {
 		// Get stream URL for the episode using the specified provider
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutStream))
 		defer cancel()
 
 		stream, err := provider.GetStreamURL(ctx, episodeID, providers.Quality1080p)
@@ -186,7 +211,7 @@ func (a *App) generateWatchPartyURLWithProvider(provider providers.Provider, epi
 
 		// If no WatchParty-specific proxy is configured, try other potential sources
 		if finalProxyURL == "" && a.cfg != nil {
-			if cfg, ok := a.cfg.(*config.Config); ok {
+			if cfg := a.config(); cfg != nil {
 				// Try WatchParty default proxy first (double check we didn't miss it)
 				if cfg.WatchParty.DefaultProxy != "" && finalProxyURL == "" {
 					finalProxyURL = cfg.WatchParty.DefaultProxy
@@ -225,7 +250,7 @@ func (a *App) generateWatchPartyURLWithProvider(provider providers.Provider, epi
 		if finalProxyURL != "" {
 			videoURL, err = watchparty.GenerateProxiedURL(stream.URL, watchparty.ProxyConfig{
 				ProxyURL: finalProxyURL,
-				Origin:   "", // Will be derived from referer in GenerateProxiedURL
+				Origin:   a.tempOrigin, // Derived from the referer when empty
 				Referer:  stream.Referer,
 			})
 			if err != nil {
@@ -240,7 +265,13 @@ func (a *App) generateWatchPartyURLWithProvider(provider providers.Provider, epi
 		}
 
 		// Generate WatchParty URL
-		watchPartyFinalURL := watchparty.GenerateWatchPartyURL(videoURL)
+		subtitleProxy := watchparty.ProxyConfig{
+			ProxyURL: finalProxyURL,
+			Referer:  stream.Referer,
+			Hosted:   watchparty.IsOwnProxy(finalProxyURL, config.GetStateDir()),
+		}
+		watchPartyFinalURL := watchparty.GenerateWatchPartyURL(videoURL,
+			watchparty.RoomSubtitleURLs(stream.Subtitles, a.subtitleLang(), subtitleProxy)...)
 
 		// Log debug information for troubleshooting proxy configuration
 		a.logger.Debug("generating WatchParty URL",
@@ -250,18 +281,24 @@ func (a *App) generateWatchPartyURLWithProvider(provider providers.Provider, epi
 			"proxy_configured", finalProxyURL != "",
 			"proxy_url", finalProxyURL)
 
-		// Prepare WatchParty information for the popup
+		// Prepare WatchParty information for the popup; subtitles go through the proxy, if any
+		hostedSubtitles := watchparty.HostedSubtitles(stream.Subtitles, subtitleProxy)
 		wpInfo := &common.WatchPartyInfo{
 			URL:           stream.URL,         // Original stream URL
 			ProxiedURL:    videoURL,           // Proxied stream URL (same as original if no proxy)
 			WatchPartyURL: watchPartyFinalURL, // Complete WatchParty URL with video parameter
-			Subtitles:     stream.Subtitles,
+			Subtitles:     hostedSubtitles,
 			Referer:       stream.Referer,
 			Headers:       stream.Headers,
 			Title:         a.selectedMedia.Title,
 			EpisodeTitle:  episodeTitle,
 			EpisodeNumber: episodeNumber,
 			ProviderName:  provider.Name(),
+			MediaID:       a.selectedMedia.ID,
+			EpisodeID:     episodeID,
+			ProxyURL:      finalProxyURL,
+			Origin:        a.tempOrigin,
+			Quality:       sharedQuality(stream, providers.Quality1080p),
 		}
 
 		// Get next episode info for "keep watching" functionality
@@ -295,7 +332,7 @@ func (a *App) generateWatchPartyURLWithProvider(provider providers.Provider, epi
 	}
 }
 
-// getWatchPartyProxy gets the WatchParty proxy URL (checks temp override first, then config)
+// getWatchPartyProxy gets the WatchParty proxy URL (checks temp override first, then config, then greg's own proxy)
 func (a *App) getWatchPartyProxy() string {
 	// Check for temporary override first
 	if a.tempProxyURL != "" {
@@ -303,14 +340,24 @@ func (a *App) getWatchPartyProxy() string {
 	}
 
 	// Fall back to config
-	if a.cfg == nil {
+	cfg := a.config()
+	if cfg == nil {
 		return ""
 	}
-	cfg, ok := a.cfg.(*config.Config)
-	if !ok {
+	proxyURL, err := watchparty.ResolveProxyURL(cfg.WatchParty.DefaultProxy, cfg.WatchParty.ProxyPublicURL, config.GetStateDir())
+	if err != nil {
+		a.logger.Warn("no watchparty proxy", "error", err)
 		return ""
 	}
-	return cfg.WatchParty.DefaultProxy
+	return proxyURL
+}
+
+// subtitleLang returns the preferred subtitle language (defaults to English)
+func (a *App) subtitleLang() string {
+	if cfg := a.config(); cfg != nil && cfg.Player.SubtitleLang != "" {
+		return cfg.Player.SubtitleLang
+	}
+	return "en"
 }
 
 // setWatchPartyProxy sets temporary proxy configuration
@@ -400,7 +447,7 @@ func (a *App) handleWatchPartyPopupInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Open the WatchParty URL in the browser
 		if a.watchPartyInfo != nil && a.watchPartyInfo.WatchPartyURL != "" {
 			cmd := a.openWatchPartyInBrowser(a.watchPartyInfo.WatchPartyURL) // Use the complete WatchParty URL: watchparty.me/create?video=PROXIED_URL
-			a.statusMsg = "✓ Opening WatchParty in browser..."
+			a.statusMsg = i18n.T("✓ Opening WatchParty in browser...")
 			a.statusMsgTime = time.Now()
 			return a, tea.Batch(cmd, func() tea.Msg {
 				time.Sleep(3 * time.Second)
@@ -408,13 +455,22 @@ func (a *App) handleWatchPartyPopupInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			})
 		}
 
+	case "n":
+		// Share the next episode with the same proxy settings
+		if a.watchPartyInfo != nil && a.watchPartyInfo.NextEpisodeID != "" {
+			next := a.watchPartyInfo
+			a.statusMsg = i18n.T("Generating WatchParty URL for episode %d...", next.NextEpisodeNumber)
+			a.statusMsgTime = time.Now()
+			return a, a.generateWatchPartyURL(next.NextEpisodeID, next.NextEpisodeNumber, next.NextEpisodeTitle)
+		}
+
 	case "k":
 		// Keep watching next episode (if available)
 		if a.watchPartyInfo != nil && a.watchPartyInfo.NextEpisodeID != "" {
 			// Close the popup and start playing the next episode
 			a.showWatchPartyPopup = false
 			// Update history to mark current episode as watched (if needed)
-			if a.historyService != nil && a.selectedMedia.Title != "" {
+			if a.historyService != nil && a.selectedMedia.Title != "" && !a.incognito && a.coWatch == nil {
 				// Record the current episode as completed in history
 				historyRecord := database.History{
 					MediaID:         a.selectedMedia.ID,
@@ -476,6 +532,7 @@ func (a *App) renderWatchPartyPopup() string {
 		"  w - Copy WatchParty URL to clipboard",
 		"  o - Open in browser",
 		"  r - Copy referer to clipboard",
+		"  n - Share next episode via WatchParty",
 		"  k - Keep watching next episode",
 		"  q - Close popup",
 		"",
@@ -483,11 +540,7 @@ func (a *App) renderWatchPartyPopup() string {
 	}
 
 	// Get user's preferred subtitle language from config
-	preferredLang := "en" // Default to English
-	cfg, ok := a.cfg.(*config.Config)
-	if ok && cfg.Player.SubtitleLang != "" {
-		preferredLang = cfg.Player.SubtitleLang
-	}
+	preferredLang := a.subtitleLang()
 
 	// Filter subtitles to only show the preferred language
 	filteredSubtitles := []providers.Subtitle{}
@@ -534,7 +587,7 @@ func (a *App) renderWatchPartyPopup() string {
 
 func (a *App) handleWatchPartyMsg(msg common.WatchPartyMsg) (tea.Model, tea.Cmd) {
 	if msg.Err != nil {
-		a.statusMsg = fmt.Sprintf("✗ Failed to generate WatchParty URL: %v", msg.Err)
+		a.statusMsg = i18n.T("✗ Failed to generate WatchParty URL: %v", msg.Err)
 		a.statusMsgTime = time.Now()
 		return a, func() tea.Msg {
 			time.Sleep(5 * time.Second)
@@ -544,12 +597,47 @@ func (a *App) handleWatchPartyMsg(msg common.WatchPartyMsg) (tea.Model, tea.Cmd)
 
 	a.showWatchPartyPopup = true
 	a.watchPartyInfo = msg.WatchPartyInfo
+	a.saveWatchPartySession(msg.WatchPartyInfo)
 	return a, nil
 }
 
+// saveWatchPartySession remembers the shared episode so 'greg watchparty next'
+// can continue the session from the CLI
+func (a *App) saveWatchPartySession(info *common.WatchPartyInfo) {
+	if a.db == nil || info == nil || info.MediaID == "" {
+		return
+	}
+
+	session := &watchparty.Session{
+		Provider:      info.ProviderName,
+		MediaID:       info.MediaID,
+		MediaTitle:    info.Title,
+		SeasonNumber:  a.currentSeasonNumber,
+		EpisodeID:     info.EpisodeID,
+		EpisodeNumber: info.EpisodeNumber,
+		EpisodeTitle:  info.EpisodeTitle,
+		Quality:       string(info.Quality),
+		ProxyURL:      info.ProxyURL,
+		Origin:        info.Origin,
+		WatchPartyURL: info.WatchPartyURL,
+	}
+	if err := watchparty.SaveSession(a.db, session); err != nil {
+		a.logger.Warn("failed to save watchparty session", "error", err)
+	}
+}
+
+// sharedQuality returns the quality of a shared stream, the requested one when
+// the provider doesn't report it
+func sharedQuality(stream *providers.StreamURL, requested providers.Quality) providers.Quality {
+	if stream.Quality != "" {
+		return stream.Quality
+	}
+	return requested
+}
+
 func (a *App) handleSetWatchPartyProxyMsg(msg common.SetWatchPartyProxyMsg) (tea.Model, tea.Cmd) {
 	a.setWatchPartyProxy(msg.ProxyURL, msg.Origin)
-	a.statusMsg = "✓ WatchParty proxy updated"
+	a.statusMsg = i18n.T("✓ WatchParty proxy updated")
 	a.statusMsgTime = time.Now()
 	return a, func() tea.Msg {
 		time.Sleep(2 * time.Second)
@@ -569,7 +657,7 @@ func (a *App) handleOpenWatchPartyMsg(msg common.OpenWatchPartyMsg) (tea.Model,
 		}
 	}
 
-	a.statusMsg = "✓ WatchParty room opened in your browser!"
+	a.statusMsg = i18n.T("✓ WatchParty room opened in your browser!")
 	a.statusMsgTime = time.Now()
 	return a, func() tea.Msg {
 		time.Sleep(2 * time.Second)
@@ -620,20 +708,9 @@ func (a *App) handleShareHistoryViaWatchPartyMsg(msg common.ShareHistoryViaWatch
 
 	// For history items, we need to get the episode ID from the provider
 	if msg.Episode == 0 {
-		type movieEpisodeIDGetter interface {
-			GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-		}
-
-		episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-		if !ok {
-			a.err = fmt.Errorf("provider does not support direct movie playback")
-			a.state = errorView
-			return a, nil
-		}
-
-		episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), msg.MediaID)
+		episodeID, err := providers.MovieEpisodeID(context.Background(), provider, msg.MediaID)
 		if err != nil {
-			a.err = fmt.Errorf("failed to get movie episode ID: %w", err)
+			a.err = err
 			a.state = errorView
 			return a, nil
 		}
@@ -673,21 +750,9 @@ func (a *App) handleShareRecentViaWatchPartyMsg(msg common.ShareRecentViaWatchPa
 	// For recent items, we try to get the episode ID from the provider
 	// For movies (episode 0), we can try directly
 	if msg.Episode == 0 { // Likely a movie
-		// This is a temporary solution to call the provider-specific method.
-		type movieEpisodeIDGetter interface {
-			GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-		}
-
-		episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-		if !ok {
-			a.err = fmt.Errorf("provider does not support direct movie playback")
-			a.state = errorView
-			return a, nil
-		}
-
-		episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), msg.MediaID)
+		episodeID, err := providers.MovieEpisodeID(context.Background(), provider, msg.MediaID)
 		if err != nil {
-			a.err = fmt.Errorf("failed to get movie episode ID: %w", err)
+			a.err = err
 			a.state = errorView
 			return a, nil
 		}
@@ -769,188 +834,20 @@ func (a *App) handleShareMediaViaWatchPartyMsg(msg common.ShareMediaViaWatchPart
 		return a, nil
 	}
 
-	// For all media types, get seasons first to determine structure
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), a.providerTimeout(provider.Name(), config.TimeoutDetails, config.TimeoutStream))
 	defer cancel()
 
-	seasons, err := provider.GetSeasons(ctx, msg.MediaID)
-	if err != nil {
-		// If GetSeasons fails, this might be a movie that needs the direct method
-		// (Some providers handle movies as direct, others via seasons/episodes)
-		type movieEpisodeIDGetter interface {
-			GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-		}
-
-		episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-		if !ok {
-			// Provider doesn't support direct movie playback, which is needed in this case
-			// Since we couldn't get seasons and provider doesn't support direct method,
-			// we need to get media details to see if it has seasons/episodes info
-			mediaDetails, err := provider.GetMediaDetails(ctx, msg.MediaID)
-			if err != nil || mediaDetails == nil {
-				a.err = fmt.Errorf("provider does not support direct movie playback and seasons could not be retrieved")
-				a.state = errorView
-				return a, nil
-			}
-
-			// If media details have seasons, try to get episodes that way
-			if len(mediaDetails.Seasons) > 0 {
-				// Use the first season to get episodes
-				var episodes []providers.Episode
-				for _, seasonInfo := range mediaDetails.Seasons {
-					episodes, err = provider.GetEpisodes(ctx, seasonInfo.ID)
-					if err == nil && len(episodes) > 0 {
-						// Found episodes via media details, use the first one
-						episode := episodes[0]                                                            // For movies, typically just one episode
-						return a, a.generateWatchPartyURLWithProvider(provider, episode.ID, 0, msg.Title) // Episode 0 for movies
-					}
-				}
-			}
-
-			// If we still couldn't get episodes, try the direct movie method again
-			// or report an error
-			a.err = fmt.Errorf("could not determine how to get movie episode from provider")
-			a.state = errorView
-			return a, nil
-		}
-
-		episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), msg.MediaID)
-		if err != nil {
-			a.err = fmt.Errorf("failed to get movie episode ID: %w", err)
-			a.state = errorView
-			return a, nil
-		}
-
-		return a, a.generateWatchPartyURLWithProvider(provider, episodeID, 0, msg.Title) // Episode 0 for movies
+	// Share the movie itself, or the first episode of anything else
+	ref := resolve.MediaRef{ID: msg.MediaID, Title: msg.Title}
+	if mediaType == providers.MediaTypeMovie || strings.HasPrefix(msg.MediaID, "movie/") {
+		ref.Type = providers.MediaTypeMovie
 	}
-
-	// If we got seasons, try to get episodes
-	if len(seasons) > 0 {
-		// For movies, typically the first season (or only season) should have the movie episode
-		firstSeason := seasons[0]
-		episodes, err := provider.GetEpisodes(ctx, firstSeason.ID)
-		if err != nil || len(episodes) == 0 {
-			// If no episodes found via seasons, check if this might be a movie
-			mediaDetails, err := provider.GetMediaDetails(ctx, msg.MediaID)
-			if err != nil || mediaDetails == nil {
-				a.err = fmt.Errorf("provider implementation doesn't support getting episodes from seasons and failed to get media details: %w", err)
-				a.state = errorView
-				return a, nil
-			}
-
-			// Check if this is a movie by looking at metadata
-			isMovie := msg.Type == string(providers.MediaTypeMovie) ||
-				mediaDetails.TotalEpisodes == 1 ||
-				(len(mediaDetails.Seasons) == 0 && mediaDetails.Status == "Completed" && mediaDetails.Type == providers.MediaTypeAnime)
-
-			if isMovie {
-				// Try the direct movie method as a fallback
-				type movieEpisodeIDGetter interface {
-					GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-				}
-
-				episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-				if !ok {
-					// Provider doesn't implement direct movie interface
-					a.err = fmt.Errorf("provider implementation doesn't support movie playback directly and no episodes found in seasons")
-					a.state = errorView
-					return a, nil
-				}
-
-				episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), msg.MediaID)
-				if err != nil {
-					a.err = fmt.Errorf("failed to get movie episode ID: %w", err)
-					a.state = errorView
-					return a, nil
-				}
-
-				return a, a.generateWatchPartyURLWithProvider(provider, episodeID, 0, msg.Title) // Episode 0 for movies
-			} else {
-				// For non-movies, report that episodes weren't found
-				a.err = fmt.Errorf("no episodes found for media - seasons exist but no episodes available")
-				a.state = errorView
-				return a, nil
-			}
-		}
-
-		// If we found episodes, use the first one (for movies there's usually just one)
-		if len(episodes) > 0 {
-			episode := episodes[0]                                                            // For movies, typically just one episode
-			return a, a.generateWatchPartyURLWithProvider(provider, episode.ID, 0, msg.Title) // Episode 0 for movies
-		}
-	} else {
-		// No seasons found - this could be a movie or an anime movie
-		// Check if the media is likely a movie by getting its details and checking structure
-		mediaDetails, err := provider.GetMediaDetails(ctx, msg.MediaID)
-		if err != nil || mediaDetails == nil {
-			a.err = fmt.Errorf("failed to get media details to determine if it's a movie: %w", err)
-			a.state = errorView
-			return a, nil
-		}
-
-		// Check if this is a movie by looking at metadata
-		// Movies typically have TotalEpisodes == 1 or no seasons but have episodes
-		isMovie := msg.Type == string(providers.MediaTypeMovie) ||
-			mediaDetails.TotalEpisodes == 1 ||
-			(len(mediaDetails.Seasons) == 0 && mediaDetails.Status == "Completed" && mediaDetails.Type == providers.MediaTypeAnime)
-
-		if isMovie {
-			// Try the direct movie method
-			type movieEpisodeIDGetter interface {
-				GetMovieEpisodeID(ctx context.Context, mediaID string) (string, error)
-			}
-
-			episodeIDGetter, ok := provider.(movieEpisodeIDGetter)
-			if !ok {
-				// If provider doesn't implement direct movie interface, try to get from media details
-				// Some providers return episodes directly even without seasons
-				seasonsCheck, err := provider.GetSeasons(ctx, msg.MediaID)
-				if err == nil && len(seasonsCheck) > 0 {
-					// Try getting episodes for the first season
-					firstSeason := seasonsCheck[0]
-					episodes, err := provider.GetEpisodes(ctx, firstSeason.ID)
-					if err == nil && len(episodes) > 0 {
-						episode := episodes[0]                                                            // For movies, typically just one episode
-						return a, a.generateWatchPartyURLWithProvider(provider, episode.ID, 0, msg.Title) // Episode 0 for movies
-					}
-				}
-
-				// For API-based providers, we need to try alternative approaches if seasons didn't work
-				// Try to get episodes directly through the media details if possible
-				// This handles cases where the provider might provide movie content differently
-				// For example, anime movies might be structured as single episodes
-				if len(mediaDetails.Seasons) == 0 && mediaDetails.TotalEpisodes > 0 {
-					// This could be a provider that doesn't use seasons for single episodes
-					// We'll try to work with the provider implementation directly
-					a.err = fmt.Errorf("provider doesn't implement direct movie interface and no seasons found")
-					a.state = errorView
-					return a, nil
-				}
-
-				// If still no luck, we have to admit the provider doesn't support this
-				a.err = fmt.Errorf("provider doesn't implement required interfaces for movie playback")
-				a.state = errorView
-				return a, nil
-			}
-
-			episodeID, err := episodeIDGetter.GetMovieEpisodeID(context.Background(), msg.MediaID)
-			if err != nil {
-				a.err = fmt.Errorf("failed to get movie episode ID: %w", err)
-				a.state = errorView
-				return a, nil
-			}
-
-			return a, a.generateWatchPartyURLWithProvider(provider, episodeID, 0, msg.Title) // Episode 0 for movies
-		} else {
-			// For non-movies with no seasons, this might be an error
-			a.err = fmt.Errorf("no seasons found for non-movie media - structure not recognized. Media type: %s, TotalEpisodes: %d", mediaDetails.Type, mediaDetails.TotalEpisodes)
-			a.state = errorView
-			return a, nil
-		}
+	res, err := resolve.Episode(ctx, provider, ref, resolve.EpisodeSelector{})
+	if err != nil {
+		a.err = fmt.Errorf("failed to generate WatchParty URL: %w", err)
+		a.state = errorView
+		return a, nil
 	}
 
-	// Fallback if no path succeeded
-	a.err = fmt.Errorf("failed to generate WatchParty URL - no valid episodes found")
-	a.state = errorView
-	return a, nil
+	return a, a.generateWatchPartyURLWithProvider(provider, res.Episode.ID, 0, msg.Title) // Episode 0 for movies
 }
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/justchokingaround/greg/internal/tui/common"
+	"github.com/justchokingaround/greg/internal/watchparty"
+)
+
+func TestSaveWatchPartySession(t *testing.T) {
+	db := newHeadlessDB(t)
+	a := &App{db: db, logger: slog.New(slog.DiscardHandler)}
+
+	a.saveWatchPartySession(&common.WatchPartyInfo{
+		ProviderName:  "headless",
+		MediaID:       "frieren",
+		EpisodeID:     "frieren-1",
+		EpisodeNumber: 1,
+		ProxyURL:      "https://proxy.example/tok/",
+		Origin:        "https://videostr.net",
+		Quality:       providers.Quality720p,
+	})
+
+	session, err := watchparty.LoadSession(db)
+	require.NoError(t, err)
+	assert.Equal(t, "https://videostr.net", session.Origin, "'greg watchparty next' keeps sending the Origin")
+	assert.Equal(t, "720p", session.Quality)
+	assert.Equal(t, "https://proxy.example/tok/", session.ProxyURL)
+}
+
+func TestSharedQuality(t *testing.T) {
+	assert.Equal(t, providers.Quality720p, sharedQuality(&providers.StreamURL{Quality: providers.Quality720p}, providers.Quality1080p))
+	assert.Equal(t, providers.Quality1080p, sharedQuality(&providers.StreamURL{}, providers.Quality1080p))
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/justchokingaround/greg"
+	"github.com/justchokingaround/greg/internal/changelog"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/i18n"
+	"github.com/justchokingaround/greg/internal/tui/styles"
+)
+
+func init() {
+	handle((*App).handleWhatsNewMsg)
+}
+
+// Version is the running greg version, set by main
+var Version = "dev"
+
+// lastSeenVersionKey stores the version whose release notes were last shown
+const lastSeenVersionKey = "changelog_last_seen_version"
+
+// whatsNewMsg carries the release notes of a newly installed version
+type whatsNewMsg struct {
+	release changelog.Release
+}
+
+// checkWhatsNew looks for release notes the user hasn't seen yet. Fresh
+// installs only record the version.
+func (a *App) checkWhatsNew() tea.Cmd {
+	db, version := a.db, Version
+	if db == nil || !changelog.IsRelease(version) {
+		return nil
+	}
+
+	return func() tea.Msg {
+		lastSeen, err := database.GetSetting(db, lastSeenVersionKey)
+		if err != nil || changelog.Normalize(lastSeen) == changelog.Normalize(version) {
+			return nil
+		}
+		if lastSeen == "" {
+			_ = database.SetSetting(db, lastSeenVersionKey, version)
+			return nil
+		}
+
+		release, ok := changelog.Find(changelog.Parse(greg.Changelog), version)
+		if !ok {
+			_ = database.SetSetting(db, lastSeenVersionKey, version)
+			return nil
+		}
+		return whatsNewMsg{release: release}
+	}
+}
+
+// handleWhatsNewMsg shows the release notes if greg is still on the home screen
+func (a *App) handleWhatsNewMsg(msg whatsNewMsg) (tea.Model, tea.Cmd) {
+	// Don't cover a link being opened; the notes are shown next start
+	if a.state != homeView {
+		return a, nil
+	}
+	a.whatsNew = &msg.release
+	a.whatsNewOffset = 0
+	a.state = whatsNewView
+	return a, nil
+}
+
+// handleWhatsNewKeys scrolls and dismisses the release notes
+func (a *App) handleWhatsNewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "j", "down":
+		if a.whatsNewOffset < len(a.whatsNewLines())-1 {
+			a.whatsNewOffset++
+		}
+		return a, nil
+	case "k", "up":
+		if a.whatsNewOffset > 0 {
+			a.whatsNewOffset--
+		}
+		return a, nil
+	case "esc", "q", "enter":
+		a.whatsNew = nil
+		a.state = homeView
+		if a.db != nil {
+			_ = database.SetSetting(a.db, lastSeenVersionKey, Version)
+		}
+		return a, nil
+	default:
+		return a, nil
+	}
+}
+
+// whatsNewLines renders the release notes as plain terminal lines
+func (a *App) whatsNewLines() []string {
+	if a.whatsNew == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(a.whatsNew.Notes, "\n") {
+		switch {
+		case strings.HasPrefix(line, "#"):
+			lines = append(lines, styles.SubtitleStyle.Render(strings.TrimSpace(strings.TrimLeft(line, "#"))))
+		case strings.HasPrefix(line, "- "):
+			lines = append(lines, "• "+strings.TrimPrefix(line, "- "))
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// renderWhatsNew renders the release notes popup
+func (a *App) renderWhatsNew() string {
+	if a.whatsNew == nil {
+		return ""
+	}
+
+	lines := a.whatsNewLines()
+	// Leave room for the popup border, title and help line
+	visible := max(a.height-10, 5)
+	end := min(a.whatsNewOffset+visible, len(lines))
+	body := strings.Join(lines[a.whatsNewOffset:end], "\n")
+
+	title := styles.TitleStyle.Render(i18n.T("What's new in greg %s", a.whatsNew.Version))
+	help := styles.HelpStyle.Render(i18n.T("j/k scroll • enter close • greg changelog to read again"))
+	dialogView := styles.PopupStyle.Render(title + "\n\n" + body + "\n\n" + help)
+
+	return lipgloss.Place(
+		a.width,
+		a.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialogView,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
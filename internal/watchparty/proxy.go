@@ -0,0 +1,393 @@
+package watchparty
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// proxyUserAgent is sent upstream; some CDNs reject requests without a browser UA
+const proxyUserAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+// uriAttrPattern matches URI="..." attributes in HLS tags (#EXT-X-KEY, #EXT-X-MEDIA, ...)
+var uriAttrPattern = regexp.MustCompile(`URI="([^"]*)"`)
+
+// proxyTokenFile is the file in greg's state directory holding the proxy token
+const proxyTokenFile = "proxy_token"
+
+// ErrNoProxyURL is returned when greg's proxy has no public URL friends can reach
+var ErrNoProxyURL = errors.New("no proxy reachable by other viewers: set watchparty.default_proxy or watchparty.proxy_public_url")
+
+// errPrivateAddress is returned when the proxy is asked to reach a non-public address
+var errPrivateAddress = errors.New("destination is not a public address")
+
+// ProxyServer is a CORS-enabled m3u8 proxy compatible with the URLs produced by
+// GenerateProxiedURL. Playlists are rewritten so every segment, key and
+// variant is fetched through the proxy with the same Origin/Referer headers.
+// Only requests under /<token>/ are served, so the proxy is not an open relay
+// for anyone who finds the port, and upstream requests only go to public
+// addresses, so people in the room can't reach the host's own services or LAN.
+type ProxyServer struct {
+	publicURL string
+	token     string
+	client    *http.Client
+	server    *http.Server
+	listener  net.Listener
+}
+
+// NewProxyServer creates a proxy. publicURL is the base URL clients reach the
+// proxy at (e.g. a tunnel); when empty it is derived from each request's Host.
+// token is the path segment every proxied URL must carry.
+func NewProxyServer(publicURL, token string) *ProxyServer {
+	return &ProxyServer{
+		publicURL: publicURL,
+		token:     token,
+		client:    newPublicClient(),
+	}
+}
+
+// newPublicClient returns the proxy's upstream client. The address check runs
+// when each connection is dialed, so it also covers redirects and DNS
+// rebinding; environment proxies are ignored since they would dial on our behalf.
+func newPublicClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnly,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: 60 * time.Second, Transport: transport}
+}
+
+// publicOnly is a net.Dialer Control refusing loopback, private, link-local
+// and unspecified addresses
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("%w: %s", errPrivateAddress, host)
+	}
+	return nil
+}
+
+// Listen starts serving on addr (e.g. "127.0.0.1:8787") in the background
+func (p *ProxyServer) Listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	p.listener = listener
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = p.server.Serve(listener) }()
+
+	return nil
+}
+
+// Addr returns the address the proxy is listening on
+func (p *ProxyServer) Addr() string {
+	if p.listener == nil {
+		return ""
+	}
+	return p.listener.Addr().String()
+}
+
+// Close stops the proxy
+func (p *ProxyServer) Close() error {
+	if p.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return p.server.Shutdown(ctx)
+}
+
+// ServeHTTP implements http.Handler
+func (p *ProxyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Range, Content-Type")
+	w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range")
+
+	switch {
+	case r.Method == http.MethodOptions:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.URL.Path == "/healthz":
+		_, _ = io.WriteString(w, "ok")
+		return
+	}
+
+	rest, ok := strings.CutPrefix(r.URL.Path, "/"+p.token+"/")
+	switch {
+	case !ok:
+		http.NotFound(w, r)
+		return
+	case rest == subtitlePath:
+		p.serveSubtitle(w, r)
+		return
+	case r.Method != http.MethodGet && r.Method != http.MethodHead:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	target := query.Get("url")
+	if target == "" {
+		http.Error(w, "missing url parameter", http.StatusBadRequest)
+		return
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") {
+		http.Error(w, "invalid url parameter", http.StatusBadRequest)
+		return
+	}
+
+	origin, referer := query.Get("origin"), query.Get("referer")
+
+	resp, err := p.fetch(r.Context(), r.Method, targetURL, origin, referer, r.Header.Get("Range"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("upstream request failed: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if isPlaylist(targetURL, resp.Header.Get("Content-Type")) && resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read playlist: %v", err), http.StatusBadGateway)
+			return
+		}
+
+		rewritten := RewritePlaylist(body, targetURL, p.baseURL(r), origin, referer)
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Header().Set("Content-Length", fmt.Sprint(len(rewritten)))
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(rewritten)
+		return
+	}
+
+	for _, header := range []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "Cache-Control"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// serveSubtitle fetches a subtitle file and serves it as WebVTT
+func (p *ProxyServer) serveSubtitle(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	targetURL, err := url.Parse(query.Get("url"))
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") {
+		http.Error(w, "invalid url parameter", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := p.fetch(r.Context(), http.MethodGet, targetURL, query.Get("origin"), query.Get("referer"), "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("upstream request failed: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		http.Error(w, fmt.Sprintf("upstream returned %s", resp.Status), http.StatusBadGateway)
+		return
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read subtitle: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	vtt, err := ToWebVTT(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_, _ = w.Write(vtt)
+}
+
+// fetch requests target upstream with the Origin/Referer headers the stream host expects
+func (p *ProxyServer) fetch(ctx context.Context, method string, target *url.URL, origin, referer, rangeHeader string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", proxyUserAgent)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	} else if origin != "" {
+		req.Header.Set("Referer", origin+"/")
+	}
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	return p.client.Do(req)
+}
+
+// baseURL returns the URL clients should use to reach this proxy
+func (p *ProxyServer) baseURL(r *http.Request) string {
+	if p.publicURL != "" {
+		return ProxyBaseURL(p.publicURL, p.token)
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return ProxyBaseURL(fmt.Sprintf("%s://%s/", scheme, r.Host), p.token)
+}
+
+// isPlaylist reports whether a response is an HLS playlist
+func isPlaylist(u *url.URL, contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.Contains(contentType, "mpegurl") || strings.HasSuffix(strings.ToLower(u.Path), ".m3u8")
+}
+
+// RewritePlaylist rewrites every URI in an m3u8 playlist to go through the proxy at proxyBase
+func RewritePlaylist(playlist []byte, playlistURL *url.URL, proxyBase, origin, referer string) []byte {
+	rewrite := func(ref string) string {
+		resolved, err := playlistURL.Parse(strings.TrimSpace(ref))
+		if err != nil {
+			return ref
+		}
+		proxied, err := GenerateProxiedURL(resolved.String(), ProxyConfig{
+			ProxyURL: proxyBase,
+			Origin:   origin,
+			Referer:  referer,
+		})
+		if err != nil {
+			return ref
+		}
+		return proxied
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			// Keep blank lines as-is
+		case strings.HasPrefix(trimmed, "#"):
+			line = uriAttrPattern.ReplaceAllStringFunc(line, func(attr string) string {
+				match := uriAttrPattern.FindStringSubmatch(attr)
+				return fmt.Sprintf(`URI="%s"`, rewrite(match[1]))
+			})
+		default:
+			line = rewrite(trimmed)
+		}
+
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+
+	return out.Bytes()
+}
+
+// ProxyBaseURL returns the base of greg's proxy URLs for a proxy reachable at
+// publicURL and guarded by token
+func ProxyBaseURL(publicURL, token string) string {
+	return strings.TrimSuffix(publicURL, "/") + "/" + token + "/"
+}
+
+// LoadProxyToken returns the token guarding greg's proxy, generating and
+// storing it in dir on first use so 'greg proxy serve' and the URLs handed
+// to WatchParty agree on it
+func LoadProxyToken(dir string) (string, error) {
+	path := filepath.Join(dir, proxyTokenFile)
+	if data, err := os.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read proxy token: %w", err)
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate proxy token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to save proxy token: %w", err)
+	}
+	return token, nil
+}
+
+// IsOwnProxy reports whether proxyURL is greg's own proxy, i.e. ends in the
+// token stored in stateDir
+func IsOwnProxy(proxyURL, stateDir string) bool {
+	if proxyURL == "" || stateDir == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(stateDir, proxyTokenFile))
+	token := strings.TrimSpace(string(data))
+	if err != nil || token == "" {
+		return false
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSuffix(parsed.Path, "/"), "/"+token)
+}
+
+// ResolveProxyURL picks the proxy for WatchParty URLs: an explicit proxy,
+// then greg's own proxy at its configured public URL, guarded by the token
+// stored in stateDir. A proxy on loopback is never used since nobody else in
+// the room could reach it, so ErrNoProxyURL is returned when neither is set.
+func ResolveProxyURL(explicit, publicURL, stateDir string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if publicURL == "" {
+		return "", ErrNoProxyURL
+	}
+	token, err := LoadProxyToken(stateDir)
+	if err != nil {
+		return "", err
+	}
+	return ProxyBaseURL(publicURL, token), nil
+}
@@ -0,0 +1,186 @@
+package watchparty
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewritePlaylist(t *testing.T) {
+	playlist := []byte(`#EXTM3U
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin"
+#EXTINF:10.0,
+seg-1.ts
+#EXTINF:10.0,
+https://cdn.example.com/other/seg-2.ts
+`)
+	playlistURL, _ := url.Parse("https://cdn.example.com/hls/index.m3u8")
+
+	out := string(RewritePlaylist(playlist, playlistURL, "http://127.0.0.1:8787/", "https://videostr.net", ""))
+
+	assert.Contains(t, out, "#EXTM3U\n")
+	assert.Contains(t, out, `URI="http://127.0.0.1:8787/?origin=https%3A%2F%2Fvideostr.net&url=https%3A%2F%2Fcdn.example.com%2Fhls%2Fkey.bin"`)
+	assert.Contains(t, out, "http://127.0.0.1:8787/?origin=https%3A%2F%2Fvideostr.net&url=https%3A%2F%2Fcdn.example.com%2Fhls%2Fseg-1.ts\n")
+	assert.Contains(t, out, "url=https%3A%2F%2Fcdn.example.com%2Fother%2Fseg-2.ts")
+	assert.NotContains(t, out, "\nseg-1.ts")
+}
+
+func TestProxyServerInjectsHeaders(t *testing.T) {
+	var gotOrigin, gotReferer string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOrigin, gotReferer = r.Header.Get("Origin"), r.Header.Get("Referer")
+		switch r.URL.Path {
+		case "/index.m3u8":
+			w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+			_, _ = io.WriteString(w, "#EXTM3U\n#EXTINF:10.0,\nseg.ts\n")
+		default:
+			_, _ = io.WriteString(w, "segment-data")
+		}
+	}))
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(newTestProxy("tok"))
+	defer proxy.Close()
+
+	proxied, err := GenerateProxiedURL(upstream.URL+"/index.m3u8", ProxyConfig{
+		ProxyURL: proxy.URL + "/tok/",
+		Origin:   "https://videostr.net",
+		Referer:  "https://videostr.net/embed",
+	})
+	require.NoError(t, err)
+
+	resp, err := http.Get(proxied)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "https://videostr.net", gotOrigin)
+	assert.Equal(t, "https://videostr.net/embed", gotReferer)
+
+	// The segment line must point back at the proxy
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	segment := lines[len(lines)-1]
+	require.True(t, strings.HasPrefix(segment, proxy.URL+"/tok/?"), segment)
+
+	resp, err = http.Get(segment)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, "segment-data", string(body))
+	assert.Equal(t, "https://videostr.net/embed", gotReferer)
+}
+
+// newTestProxy returns a proxy allowed to reach the httptest upstreams on loopback
+func newTestProxy(token string) *ProxyServer {
+	proxy := NewProxyServer("", token)
+	proxy.client = &http.Client{Timeout: 10 * time.Second}
+	return proxy
+}
+
+func TestProxyServerRejectsBadURL(t *testing.T) {
+	proxy := httptest.NewServer(NewProxyServer("", "tok"))
+	defer proxy.Close()
+
+	resp, err := http.Get(proxy.URL + "/tok/?url=file%3A%2F%2F%2Fetc%2Fpasswd")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestProxyServerRequiresToken(t *testing.T) {
+	var hits int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(newTestProxy("tok"))
+	defer proxy.Close()
+
+	for _, path := range []string{"/", "/wrong/", "/tokx/", "/x/tok/", "/x/tok/" + subtitlePath, "/" + subtitlePath} {
+		resp, err := http.Get(proxy.URL + path + "?url=" + url.QueryEscape(upstream.URL))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+	assert.Zero(t, hits)
+}
+
+func TestProxyServerRefusesPrivateDestinations(t *testing.T) {
+	var hits int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(NewProxyServer("", "tok"))
+	defer proxy.Close()
+
+	for _, path := range []string{"/tok/", "/tok/" + subtitlePath} {
+		resp, err := http.Get(proxy.URL + path + "?url=" + url.QueryEscape(upstream.URL))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode, path)
+	}
+	assert.Zero(t, hits)
+}
+
+func TestPublicOnly(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "10.0.0.1:80", "192.168.1.1:80",
+		"172.16.5.4:80", "169.254.169.254:80", "[fe80::1]:80", "0.0.0.0:80", "[::]:80", "[::ffff:127.0.0.1]:80", "[fd00::1]:80"} {
+		assert.ErrorIs(t, publicOnly("tcp", address, nil), errPrivateAddress, address)
+	}
+	for _, address := range []string{"1.1.1.1:443", "[2606:4700::1111]:443"} {
+		assert.NoError(t, publicOnly("tcp", address, nil), address)
+	}
+}
+
+func TestResolveProxyURL(t *testing.T) {
+	dir := t.TempDir()
+
+	proxyURL, err := ResolveProxyURL("https://explicit/", "https://public/", dir)
+	require.NoError(t, err)
+	assert.Equal(t, "https://explicit/", proxyURL)
+
+	_, err = ResolveProxyURL("", "", dir)
+	assert.ErrorIs(t, err, ErrNoProxyURL)
+
+	token, err := LoadProxyToken(dir)
+	require.NoError(t, err)
+	proxyURL, err = ResolveProxyURL("", "https://public/", dir)
+	require.NoError(t, err)
+	assert.Equal(t, "https://public/"+token+"/", proxyURL)
+}
+
+func TestLoadProxyToken(t *testing.T) {
+	dir := t.TempDir()
+
+	token, err := LoadProxyToken(dir)
+	require.NoError(t, err)
+	assert.Len(t, token, 32)
+
+	again, err := LoadProxyToken(dir)
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+}
+
+func TestIsOwnProxy(t *testing.T) {
+	dir := t.TempDir()
+	assert.False(t, IsOwnProxy("https://public/anything/", dir))
+
+	token, err := LoadProxyToken(dir)
+	require.NoError(t, err)
+
+	assert.True(t, IsOwnProxy(ProxyBaseURL("https://public/", token), dir))
+	assert.False(t, IsOwnProxy("https://worker.example.com/", dir))
+	assert.False(t, IsOwnProxy("", dir))
+}
@@ -0,0 +1,151 @@
+package watchparty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// sessionSettingKey is the settings key the last WatchParty session is stored under
+const sessionSettingKey = "watchparty_session"
+
+var (
+	// ErrNoSession is returned when no WatchParty session has been saved yet
+	ErrNoSession = errors.New("no WatchParty session found")
+	// ErrNoNextEpisode is returned when the session is already at the last episode
+	ErrNoNextEpisode = errors.New("no next episode available")
+)
+
+// Session remembers the media behind the last WatchParty room so the next
+// episode can be shared with the same proxy settings without searching again
+type Session struct {
+	Provider      string    `json:"provider"`
+	MediaID       string    `json:"media_id"`
+	MediaTitle    string    `json:"media_title"`
+	SeasonID      string    `json:"season_id,omitempty"`
+	SeasonNumber  int       `json:"season_number,omitempty"`
+	EpisodeID     string    `json:"episode_id"`
+	EpisodeNumber int       `json:"episode_number"`
+	EpisodeTitle  string    `json:"episode_title,omitempty"`
+	Quality       string    `json:"quality,omitempty"`
+	ProxyURL      string    `json:"proxy_url,omitempty"`
+	Origin        string    `json:"origin,omitempty"`
+	WatchPartyURL string    `json:"watch_party_url,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SaveSession persists the session, replacing any previous one
+func SaveSession(db *gorm.DB, session *Session) error {
+	session.UpdatedAt = time.Now()
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode WatchParty session: %w", err)
+	}
+	if err := database.SetSetting(db, sessionSettingKey, string(data)); err != nil {
+		return fmt.Errorf("failed to save WatchParty session: %w", err)
+	}
+	return nil
+}
+
+// LoadSession returns the last saved session or ErrNoSession
+func LoadSession(db *gorm.DB) (*Session, error) {
+	data, err := database.GetSetting(db, sessionSettingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load WatchParty session: %w", err)
+	}
+	if data == "" {
+		return nil, ErrNoSession
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to decode WatchParty session: %w", err)
+	}
+	return &session, nil
+}
+
+// Next advances the session to the episode after the current one and
+// creates a WatchParty URL for it using the session's proxy settings.
+// The session is updated in place; callers are responsible for saving it.
+func (m *Manager) Next(ctx context.Context, provider providers.Provider, session *Session) (string, error) {
+	seasonID, err := resolveSessionSeason(ctx, provider, session)
+	if err != nil {
+		return "", err
+	}
+
+	episodes, err := provider.GetEpisodes(ctx, seasonID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get episodes: %w", err)
+	}
+
+	next, ok := NextEpisode(episodes, session.EpisodeNumber)
+	if !ok {
+		return "", ErrNoNextEpisode
+	}
+
+	quality := providers.Quality1080p
+	if session.Quality != "" {
+		if parsed, err := providers.ParseQuality(session.Quality); err == nil {
+			quality = parsed
+		}
+	}
+
+	watchPartyURL, err := m.CreateWatchParty(ctx, provider, session.MediaID, next.ID, quality, ProxyConfig{
+		ProxyURL: session.ProxyURL,
+		Origin:   session.Origin,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	session.SeasonID = seasonID
+	session.EpisodeID = next.ID
+	session.EpisodeNumber = next.Number
+	session.EpisodeTitle = next.Title
+	session.WatchPartyURL = watchPartyURL
+
+	return watchPartyURL, nil
+}
+
+// resolveSessionSeason returns the season the session is in, looking it up by
+// number when only that was recorded
+func resolveSessionSeason(ctx context.Context, provider providers.Provider, session *Session) (string, error) {
+	if session.SeasonID != "" {
+		return session.SeasonID, nil
+	}
+
+	seasons, err := provider.GetSeasons(ctx, session.MediaID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get seasons: %w", err)
+	}
+	if len(seasons) == 0 {
+		return "", ErrNoNextEpisode
+	}
+
+	for _, season := range seasons {
+		if season.Number == session.SeasonNumber {
+			return season.ID, nil
+		}
+	}
+	return seasons[0].ID, nil
+}
+
+// NextEpisode returns the episode with the lowest number greater than current
+func NextEpisode(episodes []providers.Episode, current int) (providers.Episode, bool) {
+	var next providers.Episode
+	found := false
+	for _, ep := range episodes {
+		if ep.Number > current && (!found || ep.Number < next.Number) {
+			next = ep
+			found = true
+		}
+	}
+	return next, found
+}
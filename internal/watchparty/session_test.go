@@ -0,0 +1,59 @@
+package watchparty
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/justchokingaround/greg/internal/database"
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(db))
+
+	_, err = LoadSession(db)
+	assert.ErrorIs(t, err, ErrNoSession)
+
+	session := &Session{
+		Provider:      "hianime",
+		MediaID:       "frieren-18542",
+		MediaTitle:    "Frieren",
+		SeasonID:      "frieren-18542",
+		EpisodeID:     "ep-3",
+		EpisodeNumber: 3,
+		ProxyURL:      "https://proxy.example.com/",
+	}
+	require.NoError(t, SaveSession(db, session))
+
+	loaded, err := LoadSession(db)
+	require.NoError(t, err)
+	assert.Equal(t, "ep-3", loaded.EpisodeID)
+	assert.Equal(t, 3, loaded.EpisodeNumber)
+	assert.Equal(t, "https://proxy.example.com/", loaded.ProxyURL)
+	assert.False(t, loaded.UpdatedAt.IsZero())
+}
+
+func TestNextEpisode(t *testing.T) {
+	episodes := []providers.Episode{
+		{ID: "ep-1", Number: 1},
+		{ID: "ep-3", Number: 3},
+		{ID: "ep-2", Number: 2},
+	}
+
+	next, ok := NextEpisode(episodes, 1)
+	require.True(t, ok)
+	assert.Equal(t, "ep-2", next.ID)
+
+	// Gaps in numbering are skipped over
+	next, ok = NextEpisode(episodes[:2], 1)
+	require.True(t, ok)
+	assert.Equal(t, "ep-3", next.ID)
+
+	_, ok = NextEpisode(episodes, 3)
+	assert.False(t, ok)
+}
@@ -0,0 +1,127 @@
+package watchparty
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/justchokingaround/greg/internal/providers"
+)
+
+// subtitlePath is the proxy endpoint that serves subtitles as WebVTT
+const subtitlePath = "subtitle.vtt"
+
+// srtTimestamp matches SRT timestamps, which use a comma before the milliseconds
+var srtTimestamp = regexp.MustCompile(`(\d{2}:\d{2}:\d{2}),(\d{3})`)
+
+// ErrUnsupportedSubtitle is returned for subtitle formats that cannot be converted to WebVTT
+var ErrUnsupportedSubtitle = errors.New("unsupported subtitle format")
+
+// ToWebVTT converts an SRT or WebVTT subtitle file to WebVTT
+func ToWebVTT(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	trimmed := strings.TrimSpace(text)
+
+	switch {
+	case strings.HasPrefix(trimmed, "WEBVTT"):
+		return []byte(text), nil
+	case strings.HasPrefix(trimmed, "[Script Info]"):
+		return nil, fmt.Errorf("%w: ASS/SSA", ErrUnsupportedSubtitle)
+	}
+
+	return []byte("WEBVTT\n\n" + srtTimestamp.ReplaceAllString(text, "$1.$2")), nil
+}
+
+// GenerateSubtitleURL creates a stable proxy URL for subtitleURL. greg's own
+// proxy serves it as WebVTT; other proxies get their usual proxied URL.
+func GenerateSubtitleURL(subtitleURL string, config ProxyConfig) (string, error) {
+	if config.ProxyURL == "" {
+		return subtitleURL, nil
+	}
+	if !config.Hosted {
+		return GenerateProxiedURL(subtitleURL, config)
+	}
+
+	proxyBase, err := url.Parse(config.ProxyURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	params := url.Values{}
+	params.Add("url", subtitleURL)
+	if config.Origin != "" {
+		params.Add("origin", config.Origin)
+	}
+	if config.Referer != "" {
+		params.Add("referer", config.Referer)
+	}
+
+	hosted := proxyBase.JoinPath(subtitlePath)
+	hosted.RawQuery = params.Encode()
+	return hosted.String(), nil
+}
+
+// SelectSubtitles returns the subtitles matching lang, falling back to English
+func SelectSubtitles(subtitles []providers.Subtitle, lang string) []providers.Subtitle {
+	if lang == "" {
+		lang = "en"
+	}
+	lang = strings.ToLower(lang)
+
+	var selected []providers.Subtitle
+	for _, sub := range subtitles {
+		language := strings.ToLower(sub.Language)
+		if strings.Contains(language, lang) || strings.Contains(language, "english") {
+			selected = append(selected, sub)
+		}
+	}
+	return selected
+}
+
+// HostedSubtitles returns subtitles with their URLs going through the proxy,
+// pointing at the WebVTT endpoint when it is greg's own. Subtitles are
+// returned unchanged when no proxy is set.
+func HostedSubtitles(subtitles []providers.Subtitle, config ProxyConfig) []providers.Subtitle {
+	if config.ProxyURL == "" {
+		return subtitles
+	}
+
+	hosted := make([]providers.Subtitle, 0, len(subtitles))
+	for _, sub := range subtitles {
+		if sub.Format == "ass" {
+			continue
+		}
+		hostedURL, err := GenerateSubtitleURL(sub.URL, config)
+		if err != nil {
+			continue
+		}
+		format := sub.Format
+		if config.Hosted {
+			format = "vtt"
+		}
+		hosted = append(hosted, providers.Subtitle{Language: sub.Language, URL: hostedURL, Format: format})
+	}
+	return hosted
+}
+
+// RoomSubtitleURLs returns the proxied URLs of the subtitles matching lang,
+// ready to be attached to a WatchParty room. Returns nil when no proxy is set.
+func RoomSubtitleURLs(subtitles []providers.Subtitle, lang string, config ProxyConfig) []string {
+	if config.ProxyURL == "" {
+		return nil
+	}
+	return subtitleURLs(HostedSubtitles(SelectSubtitles(subtitles, lang), config))
+}
+
+// subtitleURLs returns just the URLs of subtitles
+func subtitleURLs(subtitles []providers.Subtitle) []string {
+	urls := make([]string, 0, len(subtitles))
+	for _, sub := range subtitles {
+		urls = append(urls, sub.URL)
+	}
+	return urls
+}
@@ -0,0 +1,77 @@
+package watchparty
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/justchokingaround/greg/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleSRT = "\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,500\r\nHello\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nWorld\r\n"
+
+func TestToWebVTT(t *testing.T) {
+	vtt, err := ToWebVTT([]byte(sampleSRT))
+	require.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500\nHello\n\n2\n00:00:03.000 --> 00:00:04.000\nWorld\n", string(vtt))
+
+	// WebVTT passes through untouched
+	vtt, err = ToWebVTT([]byte("WEBVTT\n\n00:01.000 --> 00:02.000\nHi\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:01.000 --> 00:02.000\nHi\n", string(vtt))
+
+	_, err = ToWebVTT([]byte("[Script Info]\nTitle: test\n"))
+	assert.ErrorIs(t, err, ErrUnsupportedSubtitle)
+}
+
+func TestRoomSubtitleURLs(t *testing.T) {
+	subs := []providers.Subtitle{
+		{Language: "English", URL: "https://cdn.example.com/en.srt", Format: "srt"},
+		{Language: "Spanish", URL: "https://cdn.example.com/es.srt", Format: "srt"},
+	}
+
+	assert.Nil(t, RoomSubtitleURLs(subs, "en", ProxyConfig{}))
+
+	urls := RoomSubtitleURLs(subs, "en", ProxyConfig{ProxyURL: "https://proxy.example.com/tok/", Hosted: true})
+	require.Len(t, urls, 1)
+	assert.Equal(t, "https://proxy.example.com/tok/subtitle.vtt?url=https%3A%2F%2Fcdn.example.com%2Fen.srt", urls[0])
+
+	// Other proxies only know their own URL scheme
+	external := RoomSubtitleURLs(subs, "en", ProxyConfig{ProxyURL: "https://worker.example.com/"})
+	require.Len(t, external, 1)
+	assert.Equal(t, "https://worker.example.com/?origin=&url=https%3A%2F%2Fcdn.example.com%2Fen.srt", external[0])
+
+	room, err := url.Parse(GenerateWatchPartyURL("https://video", urls...))
+	require.NoError(t, err)
+	assert.Equal(t, urls, room.Query()["subtitle"])
+}
+
+func TestProxyServerServesSubtitles(t *testing.T) {
+	var gotReferer string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReferer = r.Header.Get("Referer")
+		_, _ = io.WriteString(w, sampleSRT)
+	}))
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(newTestProxy("tok"))
+	defer proxy.Close()
+
+	hosted, err := GenerateSubtitleURL(upstream.URL+"/en.srt", ProxyConfig{ProxyURL: ProxyBaseURL(proxy.URL, "tok"), Referer: "https://videostr.net/", Hosted: true})
+	require.NoError(t, err)
+
+	resp, err := http.Get(hosted)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/vtt; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "https://videostr.net/", gotReferer)
+	assert.Contains(t, string(body), "00:00:01.000 --> 00:00:02.500")
+}
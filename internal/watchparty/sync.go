@@ -0,0 +1,409 @@
+package watchparty
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/player"
+	"golang.org/x/net/websocket"
+)
+
+// SyncEventType identifies a message exchanged in a native sync session
+type SyncEventType string
+
+const (
+	SyncEventLoad  SyncEventType = "load"  // Host announces the media everyone should play
+	SyncEventPlay  SyncEventType = "play"  // Resume playback at Position
+	SyncEventPause SyncEventType = "pause" // Pause playback at Position
+	SyncEventSeek  SyncEventType = "seek"  // Jump to Position
+)
+
+// syncPath is the HTTP path prefix of the WebSocket endpoint; the room code follows it
+const syncPath = "/sync/"
+
+// syncWriteTimeout bounds each write to a peer, so a stalled peer is dropped
+// instead of holding up the rest of the room
+var syncWriteTimeout = 5 * time.Second
+
+// seekThreshold is how far playback may drift from the expected position before
+// it is treated as a user seek and mirrored to the other peers
+const seekThreshold = 2 * time.Second
+
+// SyncMedia describes what the room is watching. Peers resolve the stream
+// through their own provider when possible and fall back to StreamURL.
+type SyncMedia struct {
+	Provider  string            `json:"provider"`
+	MediaID   string            `json:"media_id"`
+	EpisodeID string            `json:"episode_id"`
+	Title     string            `json:"title"`
+	Episode   int               `json:"episode,omitempty"`
+	StreamURL string            `json:"stream_url,omitempty"`
+	Referer   string            `json:"referer,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// SyncEvent is a single message of the sync protocol
+type SyncEvent struct {
+	Type     SyncEventType `json:"type"`
+	Position float64       `json:"position,omitempty"` // seconds
+	Sender   string        `json:"sender,omitempty"`
+	Media    *SyncMedia    `json:"media,omitempty"`
+}
+
+// SyncHost runs the WebSocket hub of a native watch-together session.
+// Every event received from one peer is relayed to all other peers in the room.
+type SyncHost struct {
+	mu       sync.Mutex
+	room     string
+	listener net.Listener
+	server   *http.Server
+	peers    map[*websocket.Conn]struct{}
+	media    *SyncMedia
+	state    *SyncEvent // Last play/pause event, replayed to late joiners
+}
+
+// NewSyncHost creates a sync hub listening on addr (e.g. ":7878")
+func NewSyncHost(addr string) (*SyncHost, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	h := &SyncHost{
+		room:     NewRoomCode(),
+		listener: listener,
+		peers:    make(map[*websocket.Conn]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(syncPath, websocket.Handler(h.handlePeer))
+	h.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() { _ = h.server.Serve(listener) }()
+
+	return h, nil
+}
+
+// Room returns the room code peers need to join
+func (h *SyncHost) Room() string {
+	return h.room
+}
+
+// Addr returns the address the hub is listening on
+func (h *SyncHost) Addr() string {
+	return h.listener.Addr().String()
+}
+
+// SetMedia sets the media announced to current and future peers
+func (h *SyncHost) SetMedia(media SyncMedia) {
+	h.mu.Lock()
+	h.media = &media
+	h.state = nil
+	h.mu.Unlock()
+
+	h.broadcast(SyncEvent{Type: SyncEventLoad, Media: &media}, nil)
+}
+
+// Close disconnects all peers and stops the hub
+func (h *SyncHost) Close() error {
+	h.mu.Lock()
+	for conn := range h.peers {
+		_ = conn.Close()
+	}
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return h.server.Shutdown(ctx)
+}
+
+// handlePeer serves a single WebSocket connection
+func (h *SyncHost) handlePeer(conn *websocket.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	room := strings.TrimPrefix(conn.Request().URL.Path, syncPath)
+	if !strings.EqualFold(room, h.room) {
+		return
+	}
+
+	h.mu.Lock()
+	h.peers[conn] = struct{}{}
+	media, state := h.media, h.state
+	h.mu.Unlock()
+
+	defer h.drop(conn)
+
+	// Bring the new peer up to date
+	if media != nil {
+		if err := sendEvent(conn, SyncEvent{Type: SyncEventLoad, Media: media}); err != nil {
+			return
+		}
+	}
+	if state != nil {
+		if err := sendEvent(conn, *state); err != nil {
+			return
+		}
+	}
+
+	for {
+		var event SyncEvent
+		if err := websocket.JSON.Receive(conn, &event); err != nil {
+			return
+		}
+
+		switch event.Type {
+		case SyncEventPlay, SyncEventPause, SyncEventSeek:
+			h.mu.Lock()
+			if event.Type != SyncEventSeek {
+				stored := event
+				h.state = &stored
+			}
+			h.mu.Unlock()
+			h.broadcast(event, conn)
+		}
+	}
+}
+
+// broadcast sends event to every peer except skip. The sends happen outside
+// h.mu and in parallel, so a slow peer doesn't hold up the others or block
+// joins and leaves; peers whose send fails are dropped.
+func (h *SyncHost) broadcast(event SyncEvent, skip *websocket.Conn) {
+	h.mu.Lock()
+	peers := make([]*websocket.Conn, 0, len(h.peers))
+	for conn := range h.peers {
+		if conn != skip {
+			peers = append(peers, conn)
+		}
+	}
+	h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, conn := range peers {
+		wg.Go(func() {
+			if err := sendEvent(conn, event); err != nil {
+				h.drop(conn)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// drop removes a peer from the room and closes its connection
+func (h *SyncHost) drop(conn *websocket.Conn) {
+	h.mu.Lock()
+	delete(h.peers, conn)
+	h.mu.Unlock()
+	_ = conn.Close()
+}
+
+// sendEvent writes event to conn, giving up after syncWriteTimeout
+func sendEvent(conn *websocket.Conn, event SyncEvent) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(syncWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(conn, event)
+}
+
+// SyncController is the subset of player.Player a peer needs to mirror commands
+type SyncController interface {
+	GetProgress(ctx context.Context) (*player.PlaybackProgress, error)
+	Seek(ctx context.Context, position time.Duration) error
+	SetPaused(ctx context.Context, paused bool) error
+}
+
+// SyncPeer is a participant connected to a sync hub
+type SyncPeer struct {
+	conn   *websocket.Conn
+	name   string
+	events chan SyncEvent
+	done   chan struct{}
+
+	mu           sync.Mutex
+	lastPaused   bool
+	lastPosition float64
+	lastSample   time.Time
+}
+
+// JoinSync connects to the hub at address (host:port or ws:// URL) and joins room
+func JoinSync(ctx context.Context, address, room, name string) (*SyncPeer, error) {
+	endpoint := address
+	if !strings.HasPrefix(endpoint, "ws://") && !strings.HasPrefix(endpoint, "wss://") {
+		endpoint = "ws://" + endpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/") + syncPath + strings.ToUpper(room)
+
+	cfg, err := websocket.NewConfig(endpoint, "http://localhost/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync address: %w", err)
+	}
+
+	conn, err := cfg.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join room %s: %w", room, err)
+	}
+
+	p := &SyncPeer{
+		conn:   conn,
+		name:   name,
+		events: make(chan SyncEvent, 16),
+		done:   make(chan struct{}),
+	}
+	go p.readLoop()
+
+	return p, nil
+}
+
+// readLoop receives events from the hub until the connection closes
+func (p *SyncPeer) readLoop() {
+	defer close(p.done)
+	defer close(p.events)
+
+	for {
+		var event SyncEvent
+		if err := websocket.JSON.Receive(p.conn, &event); err != nil {
+			return
+		}
+		p.events <- event
+	}
+}
+
+// WaitForMedia blocks until the host announces what to play
+func (p *SyncPeer) WaitForMedia(ctx context.Context) (*SyncMedia, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case event, ok := <-p.events:
+			if !ok {
+				return nil, errors.New("sync session closed before media was announced")
+			}
+			if event.Type == SyncEventLoad && event.Media != nil {
+				return event.Media, nil
+			}
+		}
+	}
+}
+
+// Send publishes an event to the other peers
+func (p *SyncPeer) Send(event SyncEvent) error {
+	event.Sender = p.name
+	return websocket.JSON.Send(p.conn, event)
+}
+
+// Close leaves the session
+func (p *SyncPeer) Close() error {
+	return p.conn.Close()
+}
+
+// Mirror keeps ctrl in sync with the room until ctx is cancelled or the
+// session closes. Remote events are applied to the local player and local
+// pause/resume/seek actions are published to the other peers.
+func (p *SyncPeer) Mirror(ctx context.Context, ctrl SyncController) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.done:
+			return errors.New("sync session closed")
+		case event, ok := <-p.events:
+			if !ok {
+				return errors.New("sync session closed")
+			}
+			p.apply(ctx, ctrl, event)
+		case <-ticker.C:
+			p.publishLocalChanges(ctx, ctrl)
+		}
+	}
+}
+
+// apply executes a remote event on the local player and records the
+// resulting state so the poll loop does not echo it back
+func (p *SyncPeer) apply(ctx context.Context, ctrl SyncController, event SyncEvent) {
+	position := time.Duration(event.Position * float64(time.Second))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch event.Type {
+	case SyncEventPause:
+		_ = ctrl.SetPaused(ctx, true)
+		_ = ctrl.Seek(ctx, position)
+		p.lastPaused = true
+	case SyncEventPlay:
+		_ = ctrl.Seek(ctx, position)
+		_ = ctrl.SetPaused(ctx, false)
+		p.lastPaused = false
+	case SyncEventSeek:
+		_ = ctrl.Seek(ctx, position)
+	default:
+		return
+	}
+
+	p.lastPosition = event.Position
+	p.lastSample = time.Now()
+}
+
+// publishLocalChanges compares the player state with the last known state
+// and sends pause/play/seek events for changes made by the local user
+func (p *SyncPeer) publishLocalChanges(ctx context.Context, ctrl SyncController) {
+	progress, err := ctrl.GetProgress(ctx)
+	if err != nil {
+		return
+	}
+
+	position := progress.CurrentTime.Seconds()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lastSample.IsZero() {
+		p.lastPaused, p.lastPosition, p.lastSample = progress.Paused, position, time.Now()
+		return
+	}
+
+	expected := p.lastPosition
+	if !p.lastPaused {
+		speed := progress.Speed
+		if speed <= 0 {
+			speed = 1
+		}
+		expected += time.Since(p.lastSample).Seconds() * speed
+	}
+
+	switch {
+	case progress.Paused != p.lastPaused:
+		eventType := SyncEventPlay
+		if progress.Paused {
+			eventType = SyncEventPause
+		}
+		_ = p.Send(SyncEvent{Type: eventType, Position: position})
+	case math.Abs(position-expected) > seekThreshold.Seconds():
+		_ = p.Send(SyncEvent{Type: SyncEventSeek, Position: position})
+	}
+
+	p.lastPaused, p.lastPosition, p.lastSample = progress.Paused, position, time.Now()
+}
+
+// NewRoomCode generates a short, human-friendly room code
+func NewRoomCode() string {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No 0/O or 1/I
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "GREG00"
+	}
+	for i := range buf {
+		buf[i] = alphabet[int(buf[i])%len(alphabet)]
+	}
+	return string(buf)
+}
@@ -0,0 +1,166 @@
+package watchparty
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/justchokingaround/greg/internal/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeController is an in-memory SyncController
+type fakeController struct {
+	mu       sync.Mutex
+	paused   bool
+	position time.Duration
+}
+
+func (f *fakeController) GetProgress(ctx context.Context) (*player.PlaybackProgress, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &player.PlaybackProgress{CurrentTime: f.position, Paused: f.paused, Speed: 1}, nil
+}
+
+func (f *fakeController) Seek(ctx context.Context, position time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.position = position
+	return nil
+}
+
+func (f *fakeController) SetPaused(ctx context.Context, paused bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paused = paused
+	return nil
+}
+
+func (f *fakeController) state() (bool, time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.paused, f.position
+}
+
+func TestSyncMediaAnnouncedToLateJoiner(t *testing.T) {
+	host, err := NewSyncHost("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = host.Close() }()
+
+	host.SetMedia(SyncMedia{Provider: "hianime", EpisodeID: "ep-1", Title: "Frieren", Episode: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	peer, err := JoinSync(ctx, host.Addr(), host.Room(), "guest")
+	require.NoError(t, err)
+	defer func() { _ = peer.Close() }()
+
+	media, err := peer.WaitForMedia(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ep-1", media.EpisodeID)
+	assert.Equal(t, "Frieren", media.Title)
+}
+
+func TestSyncPauseIsMirrored(t *testing.T) {
+	host, err := NewSyncHost("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = host.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	alice, err := JoinSync(ctx, host.Addr(), host.Room(), "alice")
+	require.NoError(t, err)
+	defer func() { _ = alice.Close() }()
+
+	bob, err := JoinSync(ctx, host.Addr(), host.Room(), "bob")
+	require.NoError(t, err)
+	defer func() { _ = bob.Close() }()
+
+	bobPlayer := &fakeController{position: 10 * time.Second}
+	go func() { _ = bob.Mirror(ctx, bobPlayer) }()
+
+	require.NoError(t, alice.Send(SyncEvent{Type: SyncEventPause, Position: 42}))
+
+	assert.Eventually(t, func() bool {
+		paused, position := bobPlayer.state()
+		return paused && position == 42*time.Second
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestJoinSyncWrongRoom(t *testing.T) {
+	host, err := NewSyncHost("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = host.Close() }()
+
+	host.SetMedia(SyncMedia{Title: "Frieren"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	peer, err := JoinSync(ctx, host.Addr(), "NOPE00", "guest")
+	require.NoError(t, err)
+	defer func() { _ = peer.Close() }()
+
+	_, err = peer.WaitForMedia(ctx)
+	assert.Error(t, err)
+}
+
+func TestSyncStalledPeerIsDropped(t *testing.T) {
+	timeout := syncWriteTimeout
+	syncWriteTimeout = 500 * time.Millisecond
+	t.Cleanup(func() { syncWriteTimeout = timeout })
+
+	host, err := NewSyncHost("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = host.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// Nobody reads stalled's events, so its connection backs up
+	stalled, err := JoinSync(ctx, host.Addr(), host.Room(), "stalled")
+	require.NoError(t, err)
+	defer func() { _ = stalled.Close() }()
+
+	alice, err := JoinSync(ctx, host.Addr(), host.Room(), "alice")
+	require.NoError(t, err)
+	defer func() { _ = alice.Close() }()
+
+	var received int
+	var mu sync.Mutex
+	go func() {
+		for range alice.events {
+			mu.Lock()
+			received++
+			mu.Unlock()
+		}
+	}()
+
+	require.Eventually(t, func() bool {
+		host.mu.Lock()
+		defer host.mu.Unlock()
+		return len(host.peers) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	title := strings.Repeat("x", 64<<10)
+	const announcements = 512
+	for range announcements {
+		host.SetMedia(SyncMedia{Title: title})
+	}
+
+	host.mu.Lock()
+	peers := len(host.peers)
+	host.mu.Unlock()
+	assert.Equal(t, 1, peers)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return received == announcements
+	}, 5*time.Second, 50*time.Millisecond)
+}
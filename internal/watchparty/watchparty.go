@@ -18,6 +18,9 @@ type Config struct {
 	DefaultProxy    string `json:"default_proxy" yaml:"default_proxy"`
 	AutoOpenBrowser bool   `json:"auto_open_browser" yaml:"auto_open_browser"`
 	DefaultOrigin   string `json:"default_origin" yaml:"default_origin"`
+	SubtitleLang    string `json:"subtitle_lang" yaml:"subtitle_lang"`
+	// StateDir holds greg's proxy token, to recognise URLs of greg's own proxy
+	StateDir string `json:"-" yaml:"-"`
 }
 
 // ProxyConfig holds the m3u8 proxy configuration
@@ -25,6 +28,9 @@ type ProxyConfig struct {
 	ProxyURL string
 	Origin   string
 	Referer  string
+	// Hosted is set when ProxyURL is greg's own proxy, which serves
+	// subtitles as WebVTT
+	Hosted bool
 }
 
 // Manager handles WatchParty operations
@@ -49,19 +55,25 @@ func (m *Manager) CreateWatchParty(ctx context.Context, provider providers.Provi
 
 	// Apply proxy if needed
 	videoURL := stream.URL
+	var subtitles []string
 	if proxyConfig.ProxyURL != "" {
-		videoURL, err = GenerateProxiedURL(stream.URL, ProxyConfig{
+		streamProxy := ProxyConfig{
 			ProxyURL: proxyConfig.ProxyURL,
 			Origin:   proxyConfig.Origin,
 			Referer:  stream.Referer, // Use the stream's referer as additional header if needed
-		})
+			Hosted:   proxyConfig.Hosted || IsOwnProxy(proxyConfig.ProxyURL, m.config.StateDir),
+		}
+		videoURL, err = GenerateProxiedURL(stream.URL, streamProxy)
 		if err != nil {
 			return "", fmt.Errorf("failed to generate proxied URL: %w", err)
 		}
+
+		// Attach the selected subtitles through the proxy so everyone in the room gets them
+		subtitles = RoomSubtitleURLs(stream.Subtitles, m.config.SubtitleLang, streamProxy)
 	}
 
 	// Generate WatchParty URL
-	watchPartyURL := GenerateWatchPartyURL(videoURL)
+	watchPartyURL := GenerateWatchPartyURL(videoURL, subtitles...)
 	return watchPartyURL, nil
 }
 
@@ -107,10 +119,14 @@ func GenerateProxiedURL(streamURL string, config ProxyConfig) (string, error) {
 }
 
 // GenerateWatchPartyURL creates the WatchParty URL with the proxied stream
-func GenerateWatchPartyURL(proxiedStreamURL string) string {
+// and any hosted subtitle tracks
+func GenerateWatchPartyURL(proxiedStreamURL string, subtitleURLs ...string) string {
 	baseURL := "https://www.watchparty.me/create"
 	params := url.Values{}
 	params.Add("video", proxiedStreamURL)
+	for _, subtitleURL := range subtitleURLs {
+		params.Add("subtitle", subtitleURL)
+	}
 
 	watchPartyURL, _ := url.Parse(baseURL) // Error is unlikely here
 	watchPartyURL.RawQuery = params.Encode()
@@ -97,9 +97,11 @@ type MovieInfo struct {
 }
 
 type MangaChapter struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Number string `json:"number"`
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Number    string `json:"number"`
+	Volume    string `json:"volume,omitempty"`
+	ScanGroup string `json:"scan_group,omitempty"`
 }
 
 type MangaInfo struct {